@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	systemservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/server/rest"
+	"github.com/koderover/zadig/v2/pkg/tool/apispec"
+)
+
+// apispec generates the OpenAPI spec of aslan and the client SDKs from it, run it by `make sdk` after `make swag`.
+func main() {
+	output := flag.String("output", "sdk", "the directory of the generated spec and SDKs")
+	version := flag.String("version", "dev", "the version of the spec")
+	flag.Parse()
+
+	files, err := generate(*version)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for file, content := range files {
+		path := filepath.Join(*output, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			log.Fatalf("Failed to create directory of %s, error: %s", path, err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			log.Fatalf("Failed to write %s, error: %s", path, err)
+		}
+	}
+}
+
+// generate returns the content of the generated files keyed by their paths relative to the output directory.
+func generate(version string) (map[string][]byte, error) {
+	spec, err := systemservice.GenerateOpenAPISpec(rest.Routes(), version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate openapi spec, error: %s", err)
+	}
+
+	specJSON, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openapi spec, error: %s", err)
+	}
+	goClient, err := apispec.GenerateGoClient(spec, "zadig")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate go client, error: %s", err)
+	}
+	pythonClient, err := apispec.GeneratePythonClient(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate python client, error: %s", err)
+	}
+
+	return map[string][]byte{
+		"openapi.json":                    append(specJSON, '\n'),
+		"go/zadig/zz_generated.client.go": goClient,
+		"python/zadig_sdk/client.py":      pythonClient,
+	}, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSDKUpToDate checks that the committed spec and SDKs are the same as the output of the generator,
+// run `make sdk` if it fails.
+func TestSDKUpToDate(t *testing.T) {
+	sdkDir := filepath.Join("..", "..", "sdk")
+	committedSpec, err := os.ReadFile(filepath.Join(sdkDir, "openapi.json"))
+	require.NoError(t, err)
+	spec := &struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}{}
+	require.NoError(t, json.Unmarshal(committedSpec, spec))
+
+	files, err := generate(spec.Info.Version)
+	require.NoError(t, err)
+	for file, content := range files {
+		committed, err := os.ReadFile(filepath.Join(sdkDir, file))
+		require.NoError(t, err)
+		assert.Equal(t, string(committed), string(content), "%s is out of date, run `make sdk`", file)
+	}
+}
@@ -183,6 +183,40 @@ func createOrUpdateMongodbIndex(ctx context.Context) {
 		commonrepo.NewReleasePlanColl(),
 		commonrepo.NewReleasePlanLogColl(),
 		commonrepo.NewEnvServiceVersionColl(),
+		commonrepo.NewCertExpiryMonitorColl(),
+		commonrepo.NewEnvDriftMonitorColl(),
+		commonrepo.NewEnvDriftRecordColl(),
+		commonrepo.NewEnvResourceUsageColl(),
+		commonrepo.NewProjectResourceUsageColl(),
+		commonrepo.NewProjectServiceAccountColl(),
+		commonrepo.NewWorkflowTaskColdColl(),
+		commonrepo.NewHelmValuesSchemaColl(),
+		commonrepo.NewNotificationDeliveryColl(),
+		commonrepo.NewSecretProviderColl(),
+		commonrepo.NewSopsKeyColl(),
+		commonrepo.NewImageRetentionPolicyColl(),
+		commonrepo.NewDockerfileTemplateVersionColl(),
+		commonrepo.NewArtifactRepositoryColl(),
+		commonrepo.NewBuildArtifactColl(),
+		commonrepo.NewArgoCDColl(),
+		commonrepo.NewContractRegistryColl(),
+		commonrepo.NewJobOutputColl(),
+		commonrepo.NewHostnamePolicyColl(),
+		commonrepo.NewSavedDashboardColl(),
+		commonrepo.NewEnvSnapshotColl(),
+		commonrepo.NewRecentItemColl(),
+		commonrepo.NewEnvRollbackRecordColl(),
+		commonrepo.NewBuildCacheColl(),
+		commonrepo.NewFirstDeployHookRecordColl(),
+		commonrepo.NewOpenAPISpecSnapshotColl(),
+		commonrepo.NewImageScanResultColl(),
+		commonrepo.NewStaleOwnerResourceColl(),
+		commonrepo.NewUserOffboardingRecordColl(),
+		commonrepo.NewScannerIntegrationColl(),
+		commonrepo.NewApprovalRecordColl(),
+		commonrepo.NewApprovalDelegationColl(),
+		commonrepo.NewScanningMetricsColl(),
+		commonrepo.NewScheduleDecisionColl(),
 
 		// msg queue
 		commonrepo.NewMsgQueueCommonColl(),
@@ -192,6 +226,7 @@ func createOrUpdateMongodbIndex(ctx context.Context) {
 		systemrepo.NewOperationLogColl(),
 		labelMongodb.NewLabelColl(),
 		labelMongodb.NewLabelBindingColl(),
+		labelMongodb.NewLabelRuleColl(),
 		modeMongodb.NewCollaborationModeColl(),
 		modeMongodb.NewCollaborationInstanceColl(),
 
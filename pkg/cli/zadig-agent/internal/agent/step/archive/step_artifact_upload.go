@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/helper/log"
+	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/internal/agent/step/helper"
+	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/internal/common/types"
+	"github.com/koderover/zadig/v2/pkg/tool/artifactrepo"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+)
+
+type ArtifactUploadStep struct {
+	spec       *step.StepArtifactUploadSpec
+	envs       []string
+	secretEnvs []string
+	workspace  string
+	logger     *log.JobLogger
+	dirs       *types.AgentWorkDirs
+}
+
+func NewArtifactUploadStep(spec interface{}, dirs *types.AgentWorkDirs, envs, secretEnvs []string, logger *log.JobLogger) (*ArtifactUploadStep, error) {
+	artifactUploadStep := &ArtifactUploadStep{dirs: dirs, workspace: dirs.Workspace, envs: envs, secretEnvs: secretEnvs, logger: logger}
+	yamlBytes, err := yaml.Marshal(spec)
+	if err != nil {
+		return artifactUploadStep, fmt.Errorf("marshal spec %+v failed", spec)
+	}
+	if err := yaml.Unmarshal(yamlBytes, &artifactUploadStep.spec); err != nil {
+		return artifactUploadStep, fmt.Errorf("unmarshal spec %s to artifact upload spec failed", yamlBytes)
+	}
+	return artifactUploadStep, nil
+}
+
+func (s *ArtifactUploadStep) Run(ctx context.Context) error {
+	start := time.Now()
+	defer func() {
+		s.logger.Infof(fmt.Sprintf("Artifact upload ended. Duration: %.2f seconds", time.Since(start).Seconds()))
+	}()
+
+	client, err := artifactrepo.NewClient(s.spec.Type, s.spec.Address, s.spec.Repository, s.spec.Username, s.spec.Password, s.spec.Token)
+	if err != nil {
+		return fmt.Errorf("failed to create artifact repository client, err: %s", err)
+	}
+
+	envmaps := helper.MakeEnvMap(s.envs, s.secretEnvs)
+	localPath := filepath.Join(s.workspace, filepath.FromSlash(helper.ReplaceEnvWithValue(s.spec.FilePath, envmaps)))
+	destPath := path.Join(s.spec.DestDir, s.spec.FileName)
+	properties := make(map[string]string)
+	for key, value := range s.spec.Properties {
+		properties[key] = helper.ReplaceEnvWithValue(value, envmaps)
+	}
+
+	s.logger.Infof(fmt.Sprintf("Start uploading %s to %s.", localPath, client.FileURL(destPath)))
+	return client.Upload(localPath, destPath, properties)
+}
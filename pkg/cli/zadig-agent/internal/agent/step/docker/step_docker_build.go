@@ -32,6 +32,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/internal/agent/step/helper"
 	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/internal/common/types"
 	"github.com/koderover/zadig/v2/pkg/setting"
+	zadigtypes "github.com/koderover/zadig/v2/pkg/types"
 	"github.com/koderover/zadig/v2/pkg/types/step"
 	"github.com/koderover/zadig/v2/pkg/util/fs"
 )
@@ -70,6 +71,13 @@ func (s *DockerBuildStep) Run(ctx context.Context) error {
 	s.spec.DockerFile = helper.ReplaceEnvWithValue(s.spec.DockerFile, envMap)
 	s.spec.BuildArgs = helper.ReplaceEnvWithValue(s.spec.BuildArgs, envMap)
 
+	if s.spec.GetBackend() != zadigtypes.DockerBuildBackendDocker {
+		return fmt.Errorf("docker build backend %s is not supported on vm, please use docker instead", s.spec.GetBackend())
+	}
+	if s.spec.IsMultiArch() {
+		return fmt.Errorf("multi-arch image build is not supported on vm")
+	}
+
 	if err := s.dockerLogin(); err != nil {
 		return err
 	}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scanning
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/helper/log"
+	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/internal/agent/step/helper"
+	"github.com/koderover/zadig/v2/pkg/cli/zadig-agent/internal/common/types"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/s3"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+)
+
+type ScannerReportStep struct {
+	spec       *step.StepScannerReportSpec
+	envs       []string
+	secretEnvs []string
+	workspace  string
+	dirs       *types.AgentWorkDirs
+	Logger     *log.JobLogger
+}
+
+func NewScannerReportStep(spec interface{}, dirs *types.AgentWorkDirs, envs, secretEnvs []string, logger *log.JobLogger) (*ScannerReportStep, error) {
+	scannerReportStep := &ScannerReportStep{dirs: dirs, workspace: dirs.Workspace, envs: envs, secretEnvs: secretEnvs}
+	yamlBytes, err := yaml.Marshal(spec)
+	if err != nil {
+		return scannerReportStep, fmt.Errorf("marshal spec %+v failed", spec)
+	}
+	if err := yaml.Unmarshal(yamlBytes, &scannerReportStep.spec); err != nil {
+		return scannerReportStep, fmt.Errorf("unmarshal spec %s to scanner report spec failed", yamlBytes)
+	}
+	scannerReportStep.Logger = logger
+	return scannerReportStep, nil
+}
+
+func (s *ScannerReportStep) Run(ctx context.Context) error {
+	if s.spec.S3DestDir == "" || s.spec.FileName == "" || s.spec.S3Storage == nil {
+		return nil
+	}
+	s.Logger.Infof("Start archive %s report.", s.spec.ScannerType)
+
+	envMap := helper.MakeEnvMap(s.envs, s.secretEnvs)
+	reportFile := filepath.Join(s.workspace, helper.ReplaceEnvWithValue(s.spec.ReportFile, envMap))
+	if _, err := os.Stat(reportFile); err != nil {
+		return fmt.Errorf("failed to find %s report file [%s], the error is: %s", s.spec.ScannerType, reportFile, err)
+	}
+
+	forcedPathStyle := true
+	if s.spec.S3Storage.Provider == setting.ProviderSourceAli {
+		forcedPathStyle = false
+	}
+	client, err := s3.NewClient(s.spec.S3Storage.Endpoint, s.spec.S3Storage.Ak, s.spec.S3Storage.Sk, s.spec.S3Storage.Region, s.spec.S3Storage.Insecure, forcedPathStyle)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 client to upload file, err: %s", err)
+	}
+
+	if len(s.spec.S3Storage.Subfolder) > 0 {
+		s.spec.S3DestDir = strings.TrimLeft(path.Join(s.spec.S3Storage.Subfolder, s.spec.S3DestDir), "/")
+	}
+	if err := client.Upload(s.spec.S3Storage.Bucket, reportFile, path.Join(s.spec.S3DestDir, s.spec.FileName)); err != nil {
+		return err
+	}
+	s.Logger.Infof("Finish archive %s report.", s.spec.ScannerType)
+	return nil
+}
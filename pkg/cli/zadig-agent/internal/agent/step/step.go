@@ -83,6 +83,11 @@ func RunStep(ctx context.Context, jobCtx *jobctl.JobContext, step *commonmodels.
 		if err != nil {
 			return err
 		}
+	case "artifact_upload":
+		stepInstance, err = archive.NewArtifactUploadStep(step.Spec, dirs, envs, secretEnvs, logger)
+		if err != nil {
+			return err
+		}
 	case "download_archive":
 		stepInstance, err = archive.NewDownloadArchiveStep(step.Spec, dirs, envs, secretEnvs, logger)
 		if err != nil {
@@ -103,6 +108,11 @@ func RunStep(ctx context.Context, jobCtx *jobctl.JobContext, step *commonmodels.
 		if err != nil {
 			return err
 		}
+	case "scanner_report":
+		stepInstance, err = scanning.NewScannerReportStep(step.Spec, dirs, envs, secretEnvs, logger)
+		if err != nil {
+			return err
+		}
 	case "tools":
 		return nil
 	case "debug_before":
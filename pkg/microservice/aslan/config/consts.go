@@ -122,6 +122,8 @@ const (
 	StatusDebugAfter     Status = "debug_after"
 	StatusUnstable       Status = "unstable"
 	StatusManualApproval Status = "wait_for_manual_error_handling"
+	// StatusVerifying means the job is done and verifying its result during the bake time
+	StatusVerifying Status = "verifying"
 )
 
 func FailedStatus() []Status {
@@ -129,7 +131,7 @@ func FailedStatus() []Status {
 }
 
 func InCompletedStatus() []Status {
-	return []Status{StatusCreated, StatusRunning, StatusWaiting, StatusQueued, StatusBlocked, QueueItemPending, StatusPrepare, StatusWaitingApprove, ""}
+	return []Status{StatusCreated, StatusRunning, StatusWaiting, StatusQueued, StatusBlocked, QueueItemPending, StatusPrepare, StatusWaitingApprove, StatusVerifying, ""}
 }
 
 func CompletedStatus() []Status {
@@ -197,11 +199,13 @@ const (
 	StepDownloadArchive   StepType = "download_archive"
 	StepArchive           StepType = "archive"
 	StepArchiveDistribute StepType = "archive_distribute"
+	StepArtifactUpload    StepType = "artifact_upload"
 	StepJunitReport       StepType = "junit_report"
 	StepHtmlReport        StepType = "html_report"
 	StepTarArchive        StepType = "tar_archive"
 	StepSonarCheck        StepType = "sonar_check"
 	StepSonarGetMetrics   StepType = "sonar_get_metrics"
+	StepScannerReport     StepType = "scanner_report"
 	StepDistributeImage   StepType = "distribute_image"
 	StepDebugBefore       StepType = "debug_before"
 	StepDebugAfter        StepType = "debug_after"
@@ -216,6 +220,7 @@ const (
 	JobZadigDistributeImage JobType = "zadig-distribute-image"
 	JobZadigTesting         JobType = "zadig-test"
 	JobZadigScanning        JobType = "zadig-scanning"
+	JobZadigImageScan       JobType = "zadig-image-scan"
 	JobCustomDeploy         JobType = "custom-deploy"
 	JobZadigDeploy          JobType = "zadig-deploy"
 	JobZadigVMDeploy        JobType = "zadig-vm-deploy"
@@ -247,6 +252,11 @@ const (
 	JobGrafana              JobType = "grafana"
 	JobBlueKing             JobType = "blueking"
 	JobApproval             JobType = "approval"
+	JobDragonflyPreheat     JobType = "dragonfly-preheat"
+	JobArgoRollout          JobType = "argo-rollout"
+	JobZadigRollback        JobType = "zadig-rollback"
+	JobArgoCDSync           JobType = "argocd-sync"
+	JobFluxImageUpdate      JobType = "flux-image-update"
 )
 
 const (
@@ -285,6 +295,20 @@ const (
 	Reject  ApproveOrReject = "reject"
 )
 
+type ApprovalRecordSource string
+
+const (
+	ApprovalRecordSourceWorkflow    ApprovalRecordSource = "workflow"
+	ApprovalRecordSourceReleasePlan ApprovalRecordSource = "release_plan"
+)
+
+type ApprovalDelegateType string
+
+const (
+	ApprovalDelegateTypeUser  ApprovalDelegateType = "user"
+	ApprovalDelegateTypeGroup ApprovalDelegateType = "group"
+)
+
 type DeploySourceType string
 
 const (
@@ -320,6 +344,59 @@ const (
 	ServiceScanningType ScanningModuleType = "service_scanning"
 )
 
+type ImageScanner string
+
+const (
+	ImageScannerTrivy ImageScanner = "trivy"
+	ImageScannerGrype ImageScanner = "grype"
+)
+
+// ImageScanSeverities are the vulnerability severities counted by the image scan job, from the most severe one.
+var ImageScanSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}
+
+type OwnerType string
+
+const (
+	OwnerTypeUser  OwnerType = "user"
+	OwnerTypeGroup OwnerType = "group"
+)
+
+type EnvTTLStatus string
+
+const (
+	EnvTTLStatusActive         EnvTTLStatus = "active"
+	EnvTTLStatusTearingDown    EnvTTLStatus = "tearing_down"
+	EnvTTLStatusTeardownFailed EnvTTLStatus = "teardown_failed"
+)
+
+type EnvTTLExtensionStatus string
+
+const (
+	EnvTTLExtensionPending  EnvTTLExtensionStatus = "pending"
+	EnvTTLExtensionApproved EnvTTLExtensionStatus = "approved"
+	EnvTTLExtensionRejected EnvTTLExtensionStatus = "rejected"
+)
+
+type OwnedResourceType string
+
+const (
+	OwnedResourceService  OwnedResourceType = "service"
+	OwnedResourceWorkflow OwnedResourceType = "workflow"
+	OwnedResourceEnv      OwnedResourceType = "env"
+)
+
+type OffboardingItemType string
+
+const (
+	OffboardingItemService             OffboardingItemType = "service"
+	OffboardingItemWorkflow            OffboardingItemType = "workflow"
+	OffboardingItemEnv                 OffboardingItemType = "env"
+	OffboardingItemReleasePlan         OffboardingItemType = "release_plan"
+	OffboardingItemWorkflowApproval    OffboardingItemType = "workflow_approval"
+	OffboardingItemReleasePlanApproval OffboardingItemType = "release_plan_approval"
+	OffboardingItemProjectAdmin        OffboardingItemType = "project_admin"
+)
+
 type DeployContent string
 
 const (
@@ -328,6 +405,49 @@ const (
 	DeployConfig DeployContent = "config"
 )
 
+type ArgoRolloutAction string
+
+const (
+	ArgoRolloutActionSetImage ArgoRolloutAction = "set_image"
+	ArgoRolloutActionPromote  ArgoRolloutAction = "promote"
+	ArgoRolloutActionAbort    ArgoRolloutAction = "abort"
+)
+
+type FluxImageUpdateType string
+
+const (
+	// FluxImageUpdateTypeKustomize sets the newTag of the image in the images of a kustomization.yaml
+	FluxImageUpdateTypeKustomize FluxImageUpdateType = "kustomize"
+	// FluxImageUpdateTypeValue sets the value of a yaml path, e.g. spec.values.image.tag of a HelmRelease
+	FluxImageUpdateTypeValue FluxImageUpdateType = "value"
+)
+
+type RollbackSource string
+
+const (
+	// RollbackSourceWorkflowTask rolls back to the service versions deployed when the workflow task finished
+	RollbackSourceWorkflowTask RollbackSource = "workflow_task"
+	RollbackSourceEnvSnapshot  RollbackSource = "env_snapshot"
+	// RollbackSourceBakeVerification rolls back to the revision before the deployment which failed the post-deploy verification
+	RollbackSourceBakeVerification RollbackSource = "bake_verification"
+)
+
+type RollbackTrigger string
+
+const (
+	RollbackTriggerWorkflow RollbackTrigger = "workflow"
+	RollbackTriggerAPI      RollbackTrigger = "api"
+)
+
+type FirstDeployHookType string
+
+const (
+	// FirstDeployHookTypeScript runs the script in a container of the given image
+	FirstDeployHookTypeScript FirstDeployHookType = "script"
+	// FirstDeployHookTypeManifest creates the k8s Job defined by the manifest
+	FirstDeployHookTypeManifest FirstDeployHookType = "manifest"
+)
+
 type StageType string
 
 const (
@@ -494,6 +614,8 @@ const (
 	TestJobHTMLReportStepName    = "html-report-step"
 	TestJobArchiveResultStepName = "archive-result-step"
 	TestJobObjectStorageStepName = "object-storage-step"
+	// JobHTMLReportsStepName uploads the html reports of build and testing jobs
+	JobHTMLReportsStepName = "html-reports-step"
 )
 
 type JobRunPolicy string
@@ -514,6 +636,21 @@ const (
 	JobErrorPolicyRetry       JobErrorPolicy = "retry"
 )
 
+type JobRetryBackoff string
+
+const (
+	JobRetryBackoffFixed       JobRetryBackoff = "fixed"
+	JobRetryBackoffExponential JobRetryBackoff = "exponential"
+)
+
+type RegistryHookProvider string
+
+const (
+	RegistryHookProviderHarbor RegistryHookProvider = "harbor"
+	RegistryHookProviderACR    RegistryHookProvider = "acr"
+	RegistryHookProviderECR    RegistryHookProvider = "ecr"
+)
+
 const DefaultDeleteDeploymentTimeout = 10 * time.Minute
 
 // Service creation source for openAPI
@@ -583,8 +720,16 @@ var ReleasePlanStatusMap = map[ReleasePlanStatus][]ReleasePlanStatus{
 type ReleasePlanJobType string
 
 const (
-	JobText     ReleasePlanJobType = "text"
-	JobWorkflow ReleasePlanJobType = "workflow"
+	JobText         ReleasePlanJobType = "text"
+	JobWorkflow     ReleasePlanJobType = "workflow"
+	JobChangeTicket ReleasePlanJobType = "change_ticket"
+)
+
+type ChangeTicketSource string
+
+const (
+	ChangeTicketSourceServiceNow ChangeTicketSource = "servicenow"
+	ChangeTicketSourceJira       ChangeTicketSource = "jira"
 )
 
 type ReleasePlanJobStatus string
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	buildservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/build/service"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type listBuildArtifactsQuery struct {
+	ProjectName   string `form:"projectName"`
+	ServiceName   string `form:"serviceName"`
+	ServiceModule string `form:"serviceModule"`
+	Version       string `form:"version"`
+	RepositoryID  string `form:"repositoryId"`
+	PageNum       int64  `form:"page,default=1"`
+	PageSize      int64  `form:"perPage,default=20"`
+}
+
+func canViewBuildArtifacts(ctx *internalhandler.Context, projectKey string) bool {
+	if ctx.Resources.IsSystemAdmin {
+		return true
+	}
+	projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+	if !ok {
+		return false
+	}
+	return projectAuthInfo.IsProjectAdmin || projectAuthInfo.Build.View
+}
+
+// @Summary List Build Artifacts
+// @Description List the packages pushed to the artifact repositories by build jobs
+// @Tags 	build
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Param 	serviceName		query		string		false	"service name"
+// @Param 	serviceModule	query		string		false	"service module"
+// @Param 	version			query		string		false	"version"
+// @Param 	page			query		int			false	"page"
+// @Param 	perPage			query		int			false	"per page"
+// @Success 200 	{array} 	models.BuildArtifact
+// @Router /api/aslan/build/artifacts [get]
+func ListBuildArtifacts(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(listBuildArtifactsQuery)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if args.ProjectName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be empty")
+		return
+	}
+
+	// authorization checks
+	if !canViewBuildArtifacts(ctx, args.ProjectName) {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	resp, total, err := buildservice.ListBuildArtifacts(&commonrepo.BuildArtifactListOption{
+		ProjectName:   args.ProjectName,
+		ServiceName:   args.ServiceName,
+		ServiceModule: args.ServiceModule,
+		Version:       args.Version,
+		RepositoryID:  args.RepositoryID,
+		PageNum:       args.PageNum,
+		PageSize:      args.PageSize,
+	}, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	c.Writer.Header().Set("X-Total", strconv.FormatInt(total, 10))
+	ctx.Resp = resp
+}
+
+// @Summary List Build Artifact Versions
+// @Description List the versions of the packages pushed by the build of the service module
+// @Tags 	build
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Param 	serviceName		query		string		true	"service name"
+// @Param 	serviceModule	query		string		true	"service module"
+// @Success 200 	{array} 	string
+// @Router /api/aslan/build/artifacts/versions [get]
+func ListBuildArtifactVersions(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be empty")
+		return
+	}
+
+	// authorization checks
+	if !canViewBuildArtifacts(ctx, projectKey) {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = buildservice.ListBuildArtifactVersions(projectKey, c.Query("serviceName"), c.Query("serviceModule"), ctx.Logger)
+}
+
+// @Summary Download Build Artifact
+// @Description Download the package from the artifact repository
+// @Tags 	build
+// @Produce octet-stream
+// @Param 	id				path		string		true	"build artifact id"
+// @Param 	projectName		query		string		true	"project name"
+// @Success 200
+// @Router /api/aslan/build/artifacts/{id}/download [get]
+func DownloadBuildArtifact(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+
+	// authorization checks
+	if !canViewBuildArtifacts(ctx, projectKey) {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	reader, size, fileName, err := buildservice.DownloadBuildArtifact(projectKey, c.Param("id"), ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, size, "application/octet-stream", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, fileName),
+	})
+}
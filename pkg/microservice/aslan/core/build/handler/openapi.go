@@ -27,6 +27,15 @@ import (
 	"github.com/koderover/zadig/v2/pkg/types"
 )
 
+// @Summary OpenAPI Create Build Module
+// @Description OpenAPI Create Build Module
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	source	query	string	false	"source"
+// @Param 	body	body	buildservice.OpenAPIBuildCreationFromTemplateReq	true	"body"
+// @Success 200
+// @Router /openapi/build [post]
 func OpenAPICreateBuildModule(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -105,6 +114,15 @@ func OpenAPICreateBuildModule(c *gin.Context) {
 	ctx.Err = buildservice.OpenAPICreateBuildModule(ctx.UserName, args, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Build Module
+// @Description OpenAPI Delete Build Module
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	query	string	false	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/build [delete]
 func OpenAPIDeleteBuildModule(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -141,6 +159,16 @@ func OpenAPIDeleteBuildModule(c *gin.Context) {
 	ctx.Err = buildservice.DeleteBuild(buildName, projectKey, ctx.Logger)
 }
 
+// @Summary OpenAPI List Build Modules
+// @Description OpenAPI List Build Modules
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	pageNum	query	int	false	"page num"
+// @Param 	pageSize	query	int	false	"page size"
+// @Success 200 	{object} 	buildservice.OpenAPIBuildListResp
+// @Router /openapi/build [get]
 func OpenAPIListBuildModules(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -196,6 +224,15 @@ func OpenAPIListBuildModules(c *gin.Context) {
 	ctx.Resp, ctx.Err = buildservice.OpenAPIListBuildModules(projectKey, args.PageNum, args.PageSize, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Build Module
+// @Description OpenAPI Get Build Module
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	buildservice.OpenAPIBuildDetailResp
+// @Router /openapi/build/{name}/detail [get]
 func OpenAPIGetBuildModule(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -32,6 +32,9 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		build.PUT("", UpdateBuildModule)
 		build.DELETE("", DeleteBuildModule)
 		build.POST("/targets", UpdateBuildTargets)
+		build.GET("/artifacts", ListBuildArtifacts)
+		build.GET("/artifacts/versions", ListBuildArtifactVersions)
+		build.GET("/artifacts/:id/download", DownloadBuildArtifact)
 	}
 
 	target := router.Group("targets")
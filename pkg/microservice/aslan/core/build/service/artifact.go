@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"io"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/artifactrepo"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func ListBuildArtifacts(opt *commonrepo.BuildArtifactListOption, log *zap.SugaredLogger) ([]*commonmodels.BuildArtifact, int64, error) {
+	resp, count, err := commonrepo.NewBuildArtifactColl().List(context.Background(), opt)
+	if err != nil {
+		log.Errorf("failed to list build artifacts, err: %s", err)
+		return nil, 0, e.ErrListBuildArtifact.AddErr(err)
+	}
+	return resp, count, nil
+}
+
+func ListBuildArtifactVersions(projectName, serviceName, serviceModule string, log *zap.SugaredLogger) ([]string, error) {
+	resp, err := commonrepo.NewBuildArtifactColl().ListVersions(context.Background(), projectName, serviceName, serviceModule)
+	if err != nil {
+		log.Errorf("failed to list build artifact versions, err: %s", err)
+		return nil, e.ErrListBuildArtifact.AddErr(err)
+	}
+	return resp, nil
+}
+
+// DownloadBuildArtifact returns the content of the artifact from the artifact repository, the caller should close it
+func DownloadBuildArtifact(projectName, id string, log *zap.SugaredLogger) (io.ReadCloser, int64, string, error) {
+	artifact, err := commonrepo.NewBuildArtifactColl().GetByID(context.Background(), id)
+	if err != nil {
+		return nil, 0, "", e.ErrDownloadBuildArtifact.AddErr(err)
+	}
+	if artifact.ProjectName != projectName {
+		return nil, 0, "", e.ErrDownloadBuildArtifact.AddDesc("构建制品不属于该项目")
+	}
+
+	repo, err := commonrepo.NewArtifactRepositoryColl().GetByID(context.Background(), artifact.RepositoryID)
+	if err != nil {
+		log.Errorf("failed to find artifact repository %s, err: %s", artifact.RepositoryID, err)
+		return nil, 0, "", e.ErrDownloadBuildArtifact.AddErr(err)
+	}
+	client, err := artifactrepo.NewClient(repo.Type, repo.Address, repo.Repository, repo.Username, repo.Password, repo.Token)
+	if err != nil {
+		return nil, 0, "", e.ErrDownloadBuildArtifact.AddErr(err)
+	}
+	reader, size, err := client.Download(artifact.Path)
+	if err != nil {
+		log.Errorf("failed to download build artifact %s, err: %s", artifact.Path, err)
+		return nil, 0, "", e.ErrDownloadBuildArtifact.AddErr(err)
+	}
+	return reader, size, artifact.FileName, nil
+}
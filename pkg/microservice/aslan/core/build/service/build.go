@@ -19,6 +19,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -31,6 +32,8 @@ import (
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/buildtemplate"
+	templ "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/template"
 	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/shared/client/systemconfig"
@@ -284,12 +287,18 @@ func fillBuildTargetData(build *commonmodels.Build) error {
 	}
 	build.Targets = make([]*commonmodels.ServiceModuleTarget, 0, len(build.TargetRepos))
 	for _, target := range build.TargetRepos {
+		// make sure the required parameters of the template are provided and the values are valid
+		params, err := buildtemplate.RenderParameters(buildTemplate.Parameters, target.Params)
+		if err != nil {
+			return fmt.Errorf("invalid parameters of service module %s/%s: %s", target.Service.ServiceName, target.Service.ServiceModule, err)
+		}
 		build.Targets = append(build.Targets, &commonmodels.ServiceModuleTarget{
 			ProductName:   target.Service.ProductName,
 			ServiceName:   target.Service.ServiceName,
 			ServiceModule: target.Service.ServiceModule,
 			Repos:         target.Repos,
 			Envs:          commonservice.MergeBuildEnvs(buildTemplate.PreBuild.Envs, target.Envs),
+			Params:        params,
 		})
 	}
 	return nil
@@ -596,6 +605,40 @@ func UpdateBuildTargets(name, productName string, targets []*commonmodels.Servic
 	return nil
 }
 
+var dockerBuildPlatformRegex = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9]+(/[a-z0-9]+)?$`)
+
+// validateDockerBuildPlatforms checks the platforms of the multi-arch image, duplicated platforms are removed
+func validateDockerBuildPlatforms(infrastructure string, dockerBuild *commonmodels.DockerBuild) error {
+	if !dockerBuild.MultiArchStrategy.Valid() {
+		return fmt.Errorf("invalid multi-arch strategy: %s", dockerBuild.MultiArchStrategy)
+	}
+	if len(dockerBuild.Platforms) == 0 {
+		return nil
+	}
+	if infrastructure == setting.JobVMInfrastructure {
+		return fmt.Errorf("multi-arch image build is not supported on vm")
+	}
+	if dockerBuild.Backend == types.DockerBuildBackendKaniko {
+		return fmt.Errorf("multi-arch image build is not supported by kaniko, please use docker or buildkit instead")
+	}
+
+	platforms := make([]string, 0, len(dockerBuild.Platforms))
+	platformSet := sets.NewString()
+	for _, platform := range dockerBuild.Platforms {
+		platform = strings.TrimSpace(platform)
+		if !dockerBuildPlatformRegex.MatchString(platform) {
+			return fmt.Errorf("invalid platform %s, it should be like linux/amd64", platform)
+		}
+		if platformSet.Has(platform) {
+			continue
+		}
+		platformSet.Insert(platform)
+		platforms = append(platforms, platform)
+	}
+	dockerBuild.Platforms = platforms
+	return nil
+}
+
 func correctFields(build *commonmodels.Build) error {
 	err := fillBuildTargetData(build)
 	if err != nil {
@@ -611,10 +654,25 @@ func correctFields(build *commonmodels.Build) error {
 	}
 	build.Caches = caches
 
+	if err := commonutil.CheckHTMLReports(build.HTMLReports); err != nil {
+		return err
+	}
+
 	// trim the docker file and context
 	if build.PostBuild != nil && build.PostBuild.DockerBuild != nil {
 		build.PostBuild.DockerBuild.DockerFile = strings.Trim(build.PostBuild.DockerBuild.DockerFile, " ")
 		build.PostBuild.DockerBuild.WorkDir = strings.Trim(build.PostBuild.DockerBuild.WorkDir, " ")
+		if !build.PostBuild.DockerBuild.Backend.Valid() {
+			return fmt.Errorf("invalid docker build backend: %s", build.PostBuild.DockerBuild.Backend)
+		}
+		if err := validateDockerBuildPlatforms(build.Infrastructure, build.PostBuild.DockerBuild); err != nil {
+			return err
+		}
+		if build.PostBuild.DockerBuild.Source == setting.DockerfileSourceTemplate && build.PostBuild.DockerBuild.TemplateID != "" {
+			if err := templ.ValidateDockerfileBuildArgs(build.PostBuild.DockerBuild.TemplateID, build.PostBuild.DockerBuild.TemplateVersion, build.PostBuild.DockerBuild.BuildArgs); err != nil {
+				return fmt.Errorf("invalid docker build args: %s", err)
+			}
+		}
 	}
 	if build.TemplateID == "" {
 		for _, repo := range build.Repos {
@@ -17,7 +17,11 @@ limitations under the License.
 package handler
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
 )
 
 func Health(c *gin.Context) {
@@ -34,3 +38,23 @@ func Health(c *gin.Context) {
 
 	c.JSON(200, resp)
 }
+
+// Healthz reports the status and latency of every dependency of aslan, it fails only if a required dependency is down.
+func Healthz(c *gin.Context) {
+	resp := commonservice.CheckHealth(false)
+	if resp.Status == commonservice.HealthStatusUnhealthy {
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Readyz checks the required dependencies only, it is used by the load balancers to decide whether to route traffic.
+func Readyz(c *gin.Context) {
+	resp := commonservice.CheckHealth(true)
+	if resp.Status != commonservice.HealthStatusHealthy {
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// ApprovalDelegation delegates the native approvals of a user to another user or a user group in the time range,
+// e.g. when the user is on vacation.
+type ApprovalDelegation struct {
+	ID           primitive.ObjectID          `bson:"_id,omitempty" json:"id"`
+	UserID       string                      `bson:"user_id"       json:"user_id"`
+	UserName     string                      `bson:"user_name"     json:"user_name"`
+	DelegateType config.ApprovalDelegateType `bson:"delegate_type" json:"delegate_type"`
+	DelegateID   string                      `bson:"delegate_id"   json:"delegate_id"`
+	DelegateName string                      `bson:"delegate_name" json:"delegate_name"`
+	StartTime    int64                       `bson:"start_time"    json:"start_time"`
+	EndTime      int64                       `bson:"end_time"      json:"end_time"`
+	Reason       string                      `bson:"reason"        json:"reason"`
+	CreatedBy    string                      `bson:"created_by"    json:"created_by"`
+	CreateTime   int64                       `bson:"create_time"   json:"create_time"`
+}
+
+func (ApprovalDelegation) TableName() string {
+	return "approval_delegation"
+}
+
+// IsActive returns true if the time is in the time range of the delegation
+func (d *ApprovalDelegation) IsActive(now int64) bool {
+	return d.StartTime <= now && now < d.EndTime
+}
+
+// ApprovalDelegate is the delegate a pending approval is rerouted to, it's kept in the approval as the audit trail
+type ApprovalDelegate struct {
+	DelegationID string                      `bson:"delegation_id" json:"delegation_id"`
+	Type         config.ApprovalDelegateType `bson:"type"          json:"type"`
+	ID           string                      `bson:"id"            json:"id"`
+	Name         string                      `bson:"name"          json:"name"`
+	// MemberIDs are the users who can approve on behalf of the original approver
+	MemberIDs   []string `bson:"member_ids"   json:"member_ids"`
+	RerouteTime int64    `bson:"reroute_time" json:"reroute_time"`
+	// OperatorID and OperatorName are the user who made the decision on behalf of the original approver
+	OperatorID   string `bson:"operator_id,omitempty"   json:"operator_id,omitempty"`
+	OperatorName string `bson:"operator_name,omitempty" json:"operator_name,omitempty"`
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// ApprovalRecord is an append-only log of native approval decisions, every record carries the
+// hash of the previous one so that any modification or deletion breaks the chain.
+type ApprovalRecord struct {
+	ID              primitive.ObjectID          `bson:"_id,omitempty"     json:"id"`
+	Seq             int64                       `bson:"seq"               json:"seq"`
+	Source          config.ApprovalRecordSource `bson:"source"            json:"source"`
+	ProjectName     string                      `bson:"project_name"      json:"project_name"`
+	WorkflowName    string                      `bson:"workflow_name"     json:"workflow_name,omitempty"`
+	JobName         string                      `bson:"job_name"          json:"job_name,omitempty"`
+	TaskID          int64                       `bson:"task_id"           json:"task_id,omitempty"`
+	ReleasePlanID   string                      `bson:"release_plan_id"   json:"release_plan_id,omitempty"`
+	ReleasePlanName string                      `bson:"release_plan_name" json:"release_plan_name,omitempty"`
+	UserID          string                      `bson:"user_id"           json:"user_id"`
+	UserName        string                      `bson:"user_name"         json:"user_name"`
+	Decision        config.ApproveOrReject      `bson:"decision"          json:"decision"`
+	Comment         string                      `bson:"comment"           json:"comment"`
+	CreateTime      int64                       `bson:"create_time"       json:"create_time"`
+	PrevHash        string                      `bson:"prev_hash"         json:"prev_hash"`
+	Hash            string                      `bson:"hash"              json:"hash"`
+	// OnBehalfOfID and OnBehalfOfName are the approver the decision is made for if the user is a delegate, they're
+	// omitted if empty so that the hashes of the records before delegation is supported don't change
+	OnBehalfOfID   string `bson:"on_behalf_of_id,omitempty"   json:"on_behalf_of_id,omitempty"`
+	OnBehalfOfName string `bson:"on_behalf_of_name,omitempty" json:"on_behalf_of_name,omitempty"`
+}
+
+func (ApprovalRecord) TableName() string {
+	return "approval_record"
+}
+
+// ComputeHash returns the sha256 of every field except ID and Hash itself
+func (r *ApprovalRecord) ComputeHash() (string, error) {
+	content := *r
+	content.ID = primitive.NilObjectID
+	content.Hash = ""
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// ArtifactRepository is a nexus or artifactory repository the file archive of builds can be pushed to.
+// Token takes precedence over Username and Password if it is set.
+type ArtifactRepository struct {
+	ID         primitive.ObjectID `json:"id"          bson:"_id,omitempty"`
+	Type       string             `json:"type"        bson:"type"`
+	Name       string             `json:"name"        bson:"name"`
+	Address    string             `json:"address"     bson:"address"`
+	Repository string             `json:"repository"  bson:"repository"`
+	Username   string             `json:"username"    bson:"username"`
+	Password   string             `json:"password"    bson:"password"`
+	Token      string             `json:"token"       bson:"token"`
+	UpdateBy   string             `json:"update_by"   bson:"update_by"`
+	UpdateTime int64              `json:"update_time" bson:"update_time"`
+}
+
+func (ArtifactRepository) TableName() string {
+	return "artifact_repository"
+}
+
+// BuildArtifact is a file archive pushed to an artifact repository by a build job
+type BuildArtifact struct {
+	ID            primitive.ObjectID  `json:"id"             bson:"_id,omitempty"`
+	RepositoryID  string              `json:"repository_id"  bson:"repository_id"`
+	ProjectName   string              `json:"project_name"   bson:"project_name"`
+	ServiceName   string              `json:"service_name"   bson:"service_name"`
+	ServiceModule string              `json:"service_module" bson:"service_module"`
+	Version       string              `json:"version"        bson:"version"`
+	FileName      string              `json:"file_name"      bson:"file_name"`
+	Path          string              `json:"path"           bson:"path"`
+	URL           string              `json:"url"            bson:"url"`
+	WorkflowName  string              `json:"workflow_name"  bson:"workflow_name"`
+	TaskID        int64               `json:"task_id"        bson:"task_id"`
+	JobName       string              `json:"job_name"       bson:"job_name"`
+	Repos         []*types.Repository `json:"repos"          bson:"repos"`
+	CreatedBy     string              `json:"created_by"     bson:"created_by"`
+	CreateTime    int64               `json:"create_time"    bson:"create_time"`
+}
+
+func (BuildArtifact) TableName() string {
+	return "build_artifact"
+}
@@ -65,6 +65,8 @@ type Build struct {
 	// New since V1.10.0. Only to tell the webpage should the advanced settings be displayed
 	AdvancedSettingsModified bool      `bson:"advanced_setting_modified" json:"advanced_setting_modified"`
 	Outputs                  []*Output `bson:"outputs"                   json:"outputs"`
+	// HTMLReports are uploaded to the object storage and can be viewed inline
+	HTMLReports []*HTMLReport `bson:"html_reports,omitempty" json:"html_reports"`
 }
 
 // PreBuild prepares an environment for a job
@@ -124,6 +126,8 @@ type PostBuild struct {
 
 type FileArchive struct {
 	FileLocation string `bson:"file_location" json:"file_location"`
+	// ArtifactRepositoryID is the nexus/artifactory repository the package is pushed to, besides the object storage
+	ArtifactRepositoryID string `bson:"artifact_repository_id,omitempty" json:"artifact_repository_id"`
 }
 
 type ObjectStorageUpload struct {
@@ -145,6 +149,15 @@ type DockerBuild struct {
 	TemplateID string `bson:"template_id"            json:"template_id"`
 	// TemplateName is the name of the template dockerfile
 	TemplateName string `bson:"template_name"        json:"template_name"`
+	// TemplateVersion pins the dockerfile template to a specific version, 0 means always using the latest one
+	TemplateVersion int64 `bson:"template_version,omitempty" json:"template_version"`
+	// Backend is the tool to build the image, docker is used if not set
+	Backend types.DockerBuildBackend `bson:"backend,omitempty" json:"backend"`
+	// Platforms are the platforms of the multi-arch image like linux/amd64 and linux/arm64,
+	// the image is built for the platform of the node if not set
+	Platforms []string `bson:"platforms,omitempty" json:"platforms"`
+	// MultiArchStrategy is used by the docker backend when platforms are set, buildx is used if not set
+	MultiArchStrategy types.MultiArchStrategy `bson:"multi_arch_strategy,omitempty" json:"multi_arch_strategy"`
 }
 
 type JenkinsBuild struct {
@@ -172,6 +185,8 @@ type ServiceModuleTarget struct {
 	BuildName     string              `bson:"build_name"                    json:"build_name"`
 	Repos         []*types.Repository `bson:"repos,omitempty"               json:"repos,omitempty"`
 	Envs          []*KeyVal           `bson:"envs,omitempty"                json:"envs"`
+	// Params are the values of the parameters declared by the build template
+	Params []*KeyVal `bson:"params,omitempty"              json:"params"`
 }
 
 type ServiceModuleTargetBase struct {
@@ -184,6 +199,7 @@ type TargetRepo struct {
 	Service *ServiceModuleTargetBase `json:"service"`
 	Repos   []*types.Repository      `json:"repos"`
 	Envs    []*KeyVal                `json:"envs"`
+	Params  []*KeyVal                `json:"params"`
 }
 
 type KeyVal struct {
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// BuildCache records the object storage cache of a build job, one record for each cache object.
+type BuildCache struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"       json:"id"`
+	WorkflowName  string             `bson:"workflow_name"       json:"workflow_name"`
+	ServiceName   string             `bson:"service_name"        json:"service_name"`
+	ServiceModule string             `bson:"service_module"      json:"service_module"`
+	S3StorageID   string             `bson:"s3_storage_id"       json:"s3_storage_id"`
+	ObjectKey     string             `bson:"object_key"          json:"object_key"`
+	// Size is the size of the cache object in bytes
+	Size           int64 `bson:"size"                json:"size"`
+	HitCount       int64 `bson:"hit_count"           json:"hit_count"`
+	MissCount      int64 `bson:"miss_count"          json:"miss_count"`
+	LastHitTime    int64 `bson:"last_hit_time"       json:"last_hit_time"`
+	LastUpdateTime int64 `bson:"last_update_time"    json:"last_update_time"`
+	CreateTime     int64 `bson:"create_time"         json:"create_time"`
+}
+
+func (BuildCache) TableName() string {
+	return "build_cache"
+}
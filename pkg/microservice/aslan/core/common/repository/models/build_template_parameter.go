@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+type BuildTemplateParameterType string
+
+const (
+	BuildTemplateParameterTypeString BuildTemplateParameterType = "string"
+	BuildTemplateParameterTypeNumber BuildTemplateParameterType = "number"
+	BuildTemplateParameterTypeBool   BuildTemplateParameterType = "bool"
+	BuildTemplateParameterTypeChoice BuildTemplateParameterType = "choice"
+)
+
+// BuildTemplateParameter is a typed input declared by the build template, the value is provided by
+// each service module when a build is created from the template and exposed to the scripts as an env.
+type BuildTemplateParameter struct {
+	Name         string                     `bson:"name"                    json:"name"`
+	Type         BuildTemplateParameterType `bson:"type"                    json:"type"`
+	Description  string                     `bson:"description"             json:"description"`
+	Required     bool                       `bson:"required"                json:"required"`
+	Default      string                     `bson:"default"                 json:"default"`
+	ChoiceOption []string                   `bson:"choice_option,omitempty" json:"choice_option,omitempty"`
+	// Validation is an optional regular expression the value must match
+	Validation string `bson:"validation,omitempty"    json:"validation,omitempty"`
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// CertExpiryMonitor is the project level setting of the certificate expiry monitoring
+type CertExpiryMonitor struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"        json:"id,omitempty"`
+	ProjectName string             `bson:"project_name"         json:"project_name"`
+	Enabled     bool               `bson:"enabled"              json:"enabled"`
+	// ThresholdDays is the number of days before expiry from which notifications are sent
+	ThresholdDays   int                  `bson:"threshold_days"       json:"threshold_days"`
+	NotifyCtls      []*NotifyCtl         `bson:"notify_ctls"          json:"notify_ctls"`
+	RenewalWorkflow *CertRenewalWorkflow `bson:"renewal_workflow"     json:"renewal_workflow"`
+	RenewalRecords  []*CertRenewalRecord `bson:"renewal_records"      json:"renewal_records"`
+	NotifyRecords   []*CertNotifyRecord  `bson:"notify_records"       json:"notify_records"`
+	UpdatedBy       string               `bson:"updated_by"           json:"updated_by"`
+	UpdateTime      int64                `bson:"update_time"          json:"update_time"`
+}
+
+// CertRenewalWorkflow is the custom workflow triggered when a certificate is about to expire,
+// the cert info is passed by the workflow params with the same names if they are defined.
+type CertRenewalWorkflow struct {
+	Enabled      bool   `bson:"enabled"        json:"enabled"`
+	WorkflowName string `bson:"workflow_name"  json:"workflow_name"`
+}
+
+// CertRenewalRecord is used to make sure the renewal workflow is triggered only once for a certificate
+type CertRenewalRecord struct {
+	EnvName    string `bson:"env_name"     json:"env_name"`
+	Namespace  string `bson:"namespace"    json:"namespace"`
+	SecretName string `bson:"secret_name"  json:"secret_name"`
+	NotAfter   int64  `bson:"not_after"    json:"not_after"`
+	TaskID     int64  `bson:"task_id"      json:"task_id"`
+	CreateTime int64  `bson:"create_time"  json:"create_time"`
+}
+
+// CertNotifyRecord keeps the last threshold a certificate was notified at, so the daily check only
+// notifies again when the certificate crosses the next threshold.
+type CertNotifyRecord struct {
+	Namespace     string `bson:"namespace"       json:"namespace"`
+	SecretName    string `bson:"secret_name"     json:"secret_name"`
+	NotAfter      int64  `bson:"not_after"       json:"not_after"`
+	ThresholdDays int    `bson:"threshold_days"  json:"threshold_days"`
+	NotifyTime    int64  `bson:"notify_time"     json:"notify_time"`
+}
+
+func (CertExpiryMonitor) TableName() string {
+	return "cert_expiry_monitor"
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ContractRegistry is a schema/contract registry which deploy jobs ask for the compatibility of the
+// services to be deployed with the consumers deployed in the target env
+type ContractRegistry struct {
+	ID      primitive.ObjectID `json:"id"      bson:"_id,omitempty"`
+	Name    string             `json:"name"    bson:"name"`
+	Address string             `json:"address" bson:"address"`
+	// Token is sent as the bearer token, it's optional
+	Token              string `json:"token"                bson:"token"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" bson:"insecure_skip_verify"`
+	UpdateBy           string `json:"update_by"            bson:"update_by"`
+	UpdateTime         int64  `json:"update_time"          bson:"update_time"`
+}
+
+func (ContractRegistry) TableName() string {
+	return "contract_registry"
+}
@@ -39,6 +39,12 @@ type Cronjob struct {
 	ReleasePlanArgs *ReleasePlanArgs   `bson:"release_plan_args,omitempty"         json:"release_plan_args,omitempty"`
 	JobType         string             `bson:"job_type"                            json:"job_type"`
 	Enabled         bool               `bson:"enabled"                             json:"enabled"`
+	// Timezone is the IANA timezone the cron is evaluated in, the timezone of the cron service is used if it is empty.
+	Timezone string `bson:"timezone,omitempty"                  json:"timezone,omitempty"`
+	// Jitter delays every run by a random duration up to the given seconds to spread the load of the crons.
+	Jitter int64 `bson:"jitter,omitempty"                    json:"jitter,omitempty"`
+	// BulkPaused marks the cron paused by the bulk pause of the workflow or the project, only these crons are enabled by the bulk resume.
+	BulkPaused bool `bson:"bulk_paused"                         json:"bulk_paused"`
 }
 
 type EnvArgs struct {
@@ -19,11 +19,51 @@ package models
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
 type DockerfileTemplate struct {
-	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name    string             `bson:"name"          json:"name"`
-	Content string             `bson:"content"       json:"content"`
+	ID         primitive.ObjectID     `bson:"_id,omitempty"         json:"id,omitempty"`
+	Name       string                 `bson:"name"                  json:"name"`
+	Content    string                 `bson:"content"               json:"content"`
+	ArgSchemas []*DockerfileArgSchema `bson:"arg_schemas,omitempty" json:"arg_schemas"`
+	// Version is increased every time the content or arg schemas of the template changes
+	Version    int64  `bson:"version"     json:"version"`
+	UpdateBy   string `bson:"update_by"   json:"update_by"`
+	UpdateTime int64  `bson:"update_time" json:"update_time"`
+}
+
+type DockerfileArgType string
+
+const (
+	DockerfileArgTypeString DockerfileArgType = "string"
+	DockerfileArgTypeNumber DockerfileArgType = "number"
+	DockerfileArgTypeBool   DockerfileArgType = "bool"
+	DockerfileArgTypeEnum   DockerfileArgType = "enum"
+)
+
+// DockerfileArgSchema describes the build arg a dockerfile template accepts
+type DockerfileArgSchema struct {
+	Key         string            `bson:"key"         json:"key"`
+	Type        DockerfileArgType `bson:"type"        json:"type"`
+	Required    bool              `bson:"required"    json:"required"`
+	Default     string            `bson:"default"     json:"default"`
+	Options     []string          `bson:"options"     json:"options"`
+	Pattern     string            `bson:"pattern"     json:"pattern"`
+	Description string            `bson:"description" json:"description"`
 }
 
 func (DockerfileTemplate) TableName() string {
 	return "dockerfile_template"
 }
+
+type DockerfileTemplateVersion struct {
+	ID         primitive.ObjectID     `bson:"_id,omitempty"         json:"id,omitempty"`
+	TemplateID string                 `bson:"template_id"           json:"template_id"`
+	Version    int64                  `bson:"version"               json:"version"`
+	Name       string                 `bson:"name"                  json:"name"`
+	Content    string                 `bson:"content"               json:"content"`
+	ArgSchemas []*DockerfileArgSchema `bson:"arg_schemas,omitempty" json:"arg_schemas"`
+	CreateBy   string                 `bson:"create_by"             json:"create_by"`
+	CreateTime int64                  `bson:"create_time"           json:"create_time"`
+}
+
+func (DockerfileTemplateVersion) TableName() string {
+	return "dockerfile_template_version"
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// EnvResourceUsage is an hourly sample of the resources requested and used by the pods in the namespace of an env,
+// the cpu is in cores and the memory is in GiB.
+type EnvResourceUsage struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"    json:"id,omitempty"`
+	ProductName     string             `bson:"product_name"     json:"product_name"`
+	EnvName         string             `bson:"env_name"         json:"env_name"`
+	Production      bool               `bson:"production"       json:"production"`
+	ClusterID       string             `bson:"cluster_id"       json:"cluster_id"`
+	Namespace       string             `bson:"namespace"        json:"namespace"`
+	RequestedCPU    float64            `bson:"requested_cpu"    json:"requested_cpu"`
+	RequestedMemory float64            `bson:"requested_memory" json:"requested_memory"`
+	UsedCPU         float64            `bson:"used_cpu"         json:"used_cpu"`
+	UsedMemory      float64            `bson:"used_memory"      json:"used_memory"`
+	CreateTime      int64              `bson:"create_time"      json:"create_time"`
+}
+
+func (EnvResourceUsage) TableName() string {
+	return "env_resource_usage"
+}
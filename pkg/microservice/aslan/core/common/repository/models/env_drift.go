@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type EnvDriftField string
+
+const (
+	EnvDriftFieldReplicas  EnvDriftField = "replicas"
+	EnvDriftFieldImage     EnvDriftField = "image"
+	EnvDriftFieldEnv       EnvDriftField = "env"
+	EnvDriftFieldContainer EnvDriftField = "container"
+	EnvDriftFieldResource  EnvDriftField = "resource"
+)
+
+// EnvDriftMonitor is the project level setting of the drift detection of the environments
+type EnvDriftMonitor struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"        json:"id,omitempty"`
+	ProjectName string             `bson:"project_name"         json:"project_name"`
+	Enabled     bool               `bson:"enabled"              json:"enabled"`
+	// NotifyCtls are optional, notifications are only sent when the drift of an env changes
+	NotifyCtls []*NotifyCtl `bson:"notify_ctls"          json:"notify_ctls"`
+	UpdatedBy  string       `bson:"updated_by"           json:"updated_by"`
+	UpdateTime int64        `bson:"update_time"          json:"update_time"`
+}
+
+// EnvDriftRecord records the differences between the live state of the cluster and the rendered manifests
+// of the services in an env, only the detections with drift are recorded.
+type EnvDriftRecord struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"             json:"id,omitempty"`
+	ProductName string              `bson:"product_name"              json:"product_name"`
+	EnvName     string              `bson:"env_name"                  json:"env_name"`
+	Production  bool                `bson:"production"                json:"production"`
+	ClusterID   string              `bson:"cluster_id"                json:"cluster_id"`
+	Namespace   string              `bson:"namespace"                 json:"namespace"`
+	Drifts      []*EnvResourceDrift `bson:"drifts"                    json:"drifts"`
+	CreateTime  int64               `bson:"create_time"               json:"create_time"`
+}
+
+type EnvResourceDrift struct {
+	ServiceName string          `bson:"service_name"     json:"service_name"`
+	Kind        string          `bson:"kind"             json:"kind"`
+	Name        string          `bson:"name"             json:"name"`
+	Items       []*EnvDriftItem `bson:"items"            json:"items"`
+}
+
+type EnvDriftItem struct {
+	Field     EnvDriftField `bson:"field"                json:"field"`
+	Container string        `bson:"container,omitempty"  json:"container,omitempty"`
+	Key       string        `bson:"key,omitempty"        json:"key,omitempty"`
+	Desired   string        `bson:"desired"              json:"desired"`
+	Live      string        `bson:"live"                 json:"live"`
+}
+
+func (EnvDriftMonitor) TableName() string {
+	return "env_drift_monitor"
+}
+
+func (EnvDriftRecord) TableName() string {
+	return "env_drift_record"
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// EnvRollbackRecord is a rollback of a service in an env, triggered by a workflow job or the api
+type EnvRollbackRecord struct {
+	ID          primitive.ObjectID    `bson:"_id,omitempty"  json:"id,omitempty"`
+	ProductName string                `bson:"product_name"   json:"product_name"`
+	EnvName     string                `bson:"env_name"       json:"env_name"`
+	Production  bool                  `bson:"production"     json:"production"`
+	ServiceName string                `bson:"service_name"   json:"service_name"`
+	Source      config.RollbackSource `bson:"source"         json:"source"`
+	// WorkflowName and TaskID are the workflow task rolled back to when the source is workflow_task
+	WorkflowName string `bson:"workflow_name,omitempty" json:"workflow_name,omitempty"`
+	TaskID       int64  `bson:"task_id,omitempty"       json:"task_id,omitempty"`
+	SnapshotID   string `bson:"snapshot_id,omitempty"   json:"snapshot_id,omitempty"`
+	// Revision is the env service revision rolled back to
+	Revision int64                  `bson:"revision"       json:"revision"`
+	Images   []string               `bson:"images"         json:"images"`
+	Trigger  config.RollbackTrigger `bson:"trigger"        json:"trigger"`
+	// TriggerWorkflowName and TriggerTaskID are the workflow task running the rollback job
+	TriggerWorkflowName string        `bson:"trigger_workflow_name,omitempty" json:"trigger_workflow_name,omitempty"`
+	TriggerTaskID       int64         `bson:"trigger_task_id,omitempty"       json:"trigger_task_id,omitempty"`
+	Status              config.Status `bson:"status"         json:"status"`
+	Error               string        `bson:"error"          json:"error"`
+	CreatedBy           string        `bson:"created_by"     json:"created_by"`
+	CreateTime          int64         `bson:"create_time"    json:"create_time"`
+}
+
+func (EnvRollbackRecord) TableName() string {
+	return "env_rollback_record"
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// FirstDeployHookRecord records the run of the first deploy hook of a service in an env,
+// the hook is run only once for each service in each env.
+type FirstDeployHookRecord struct {
+	ID          primitive.ObjectID         `bson:"_id,omitempty"        json:"id"`
+	ProductName string                     `bson:"product_name"         json:"product_name"`
+	EnvName     string                     `bson:"env_name"             json:"env_name"`
+	Production  bool                       `bson:"production"           json:"production"`
+	ServiceName string                     `bson:"service_name"         json:"service_name"`
+	Revision    int64                      `bson:"revision"             json:"revision"`
+	Type        config.FirstDeployHookType `bson:"type"                 json:"type"`
+	JobName     string                     `bson:"job_name"             json:"job_name"`
+	Status      config.Status              `bson:"status"               json:"status"`
+	Error       string                     `bson:"error"                json:"error"`
+	StartTime   int64                      `bson:"start_time"           json:"start_time"`
+	EndTime     int64                      `bson:"end_time"             json:"end_time"`
+}
+
+func (FirstDeployHookRecord) TableName() string {
+	return "first_deploy_hook_record"
+}
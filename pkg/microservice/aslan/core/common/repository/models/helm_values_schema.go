@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	// HelmValuesSchemaSourceChart validates the values by the values.schema.json in the chart of the service
+	HelmValuesSchemaSourceChart = "chart"
+	// HelmValuesSchemaSourceCustom validates the values by the schema attached to the service
+	HelmValuesSchemaSourceCustom = "custom"
+	// HelmValuesSchemaSourceDisabled skips the validation of the values
+	HelmValuesSchemaSourceDisabled = "disabled"
+)
+
+// HelmValuesSchema is the json schema used to validate the values of a helm service before it is deployed
+type HelmValuesSchema struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"     json:"id,omitempty"`
+	ProjectName string             `bson:"project_name"      json:"project_name"`
+	ServiceName string             `bson:"service_name"      json:"service_name"`
+	Production  bool               `bson:"production"        json:"production"`
+	Source      string             `bson:"source"            json:"source"`
+	Schema      string             `bson:"schema"            json:"schema"`
+	UpdateBy    string             `bson:"update_by"         json:"update_by"`
+	UpdateTime  int64              `bson:"update_time"       json:"update_time"`
+}
+
+func (HelmValuesSchema) TableName() string {
+	return "helm_values_schema"
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// placeholders supported in the hostname pattern
+const (
+	HostnamePlaceholderEnv       = "{env}"
+	HostnamePlaceholderProject   = "{project}"
+	HostnamePlaceholderNamespace = "{namespace}"
+	HostnamePlaceholderService   = "{service}"
+)
+
+// HostnamePolicy is the project level hostname allocation rule, e.g. {env}.{project}.example.com,
+// the allocated hostname can be referenced by $Hostname$ in the service yaml.
+type HostnamePolicy struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"        json:"id,omitempty"`
+	ProjectName string             `bson:"project_name"         json:"project_name"`
+	Enabled     bool               `bson:"enabled"              json:"enabled"`
+	Pattern     string             `bson:"pattern"              json:"pattern"`
+	// ProductionPattern is used by production envs, Pattern is used if it is empty
+	ProductionPattern string `bson:"production_pattern"   json:"production_pattern"`
+	UpdatedBy         string `bson:"updated_by"           json:"updated_by"`
+	UpdateTime        int64  `bson:"update_time"          json:"update_time"`
+}
+
+func (HostnamePolicy) TableName() string {
+	return "hostname_policy"
+}
+
+func (p *HostnamePolicy) GetPattern(production bool) string {
+	if production && p.ProductionPattern != "" {
+		return p.ProductionPattern
+	}
+	return p.Pattern
+}
+
+// Render returns the hostname allocated to the given env/service, it's empty if the policy is disabled
+func (p *HostnamePolicy) Render(production bool, projectName, envName, namespace, serviceName string) string {
+	if p == nil || !p.Enabled {
+		return ""
+	}
+	return RenderHostnamePattern(p.GetPattern(production), projectName, envName, namespace, serviceName)
+}
+
+func RenderHostnamePattern(pattern, projectName, envName, namespace, serviceName string) string {
+	hostname := strings.NewReplacer(
+		HostnamePlaceholderEnv, envName,
+		HostnamePlaceholderProject, projectName,
+		HostnamePlaceholderNamespace, namespace,
+		HostnamePlaceholderService, serviceName,
+	).Replace(pattern)
+	return strings.ToLower(hostname)
+}
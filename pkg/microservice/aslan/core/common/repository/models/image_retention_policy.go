@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ImageRetentionPolicy cleans up the stale tags of the repos in the registry periodically,
+// a tag is deleted only if it is not retained by any of KeepLastN, KeepTagRegex and MinAgeDays.
+type ImageRetentionPolicy struct {
+	ID           primitive.ObjectID `json:"id"                  bson:"_id,omitempty"`
+	Name         string             `json:"name"                bson:"name"`
+	RegistryID   string             `json:"registry_id"         bson:"registry_id"`
+	Repos        []string           `json:"repos"               bson:"repos"`
+	KeepLastN    int                `json:"keep_last_n"         bson:"keep_last_n"`
+	KeepTagRegex string             `json:"keep_tag_regex"      bson:"keep_tag_regex"`
+	MinAgeDays   int                `json:"min_age_days"        bson:"min_age_days"`
+	Enabled      bool               `json:"enabled"             bson:"enabled"`
+	LastRunTime  int64              `json:"last_run_time"       bson:"last_run_time"`
+	LastDeleted  int                `json:"last_deleted"        bson:"last_deleted"`
+	UpdateBy     string             `json:"update_by"           bson:"update_by"`
+	UpdateTime   int64              `json:"update_time"         bson:"update_time"`
+}
+
+func (ImageRetentionPolicy) TableName() string {
+	return "image_retention_policy"
+}
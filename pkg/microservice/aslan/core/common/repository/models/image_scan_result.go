@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ImageScanResult is the vulnerability counts of an image scanned by the image scan job.
+type ImageScanResult struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"   json:"id"`
+	ProjectName   string             `bson:"project_name"    json:"project_name"`
+	WorkflowName  string             `bson:"workflow_name"   json:"workflow_name"`
+	TaskID        int64              `bson:"task_id"         json:"task_id"`
+	JobName       string             `bson:"job_name"        json:"job_name"`
+	ServiceName   string             `bson:"service_name"    json:"service_name"`
+	ServiceModule string             `bson:"service_module"  json:"service_module"`
+	Image         string             `bson:"image"           json:"image"`
+	Scanner       string             `bson:"scanner"         json:"scanner"`
+	// Vulnerabilities is the count of vulnerabilities by severity, the severities are CRITICAL, HIGH, MEDIUM, LOW and UNKNOWN.
+	Vulnerabilities map[string]int `bson:"vulnerabilities" json:"vulnerabilities"`
+	// Passed is false if the count of any severity exceeds its threshold
+	Passed     bool  `bson:"passed"          json:"passed"`
+	CreateTime int64 `bson:"create_time"     json:"create_time"`
+}
+
+func (ImageScanResult) TableName() string {
+	return "image_scan_result"
+}
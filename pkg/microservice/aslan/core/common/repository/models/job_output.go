@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// JobOutput is an output variable of a job task, e.g. IMAGE of a build job, it is saved when the workflow task
+// finishes so the outputs can be queried without loading the tasks.
+type JobOutput struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty"         json:"id"`
+	ProjectName         string             `bson:"project_name"          json:"project_name"`
+	WorkflowName        string             `bson:"workflow_name"         json:"workflow_name"`
+	WorkflowDisplayName string             `bson:"workflow_display_name" json:"workflow_display_name"`
+	TaskID              int64              `bson:"task_id"               json:"task_id"`
+	TaskStatus          string             `bson:"task_status"           json:"task_status"`
+	JobName             string             `bson:"job_name"              json:"job_name"`
+	JobKey              string             `bson:"job_key"               json:"job_key"`
+	JobType             string             `bson:"job_type"              json:"job_type"`
+	ServiceName         string             `bson:"service_name"          json:"service_name"`
+	ServiceModule       string             `bson:"service_module"        json:"service_module"`
+	Name                string             `bson:"name"                  json:"name"`
+	Value               string             `bson:"value"                 json:"value"`
+	CreateTime          int64              `bson:"create_time"           json:"create_time"`
+}
+
+func (JobOutput) TableName() string {
+	return "job_output"
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package models
 
 import (
+	"fmt"
+	"strings"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/dragonfly"
 	"github.com/koderover/zadig/v2/pkg/types"
 )
 
@@ -46,6 +50,8 @@ type K8SCluster struct {
 	LastConnectionTime     int64                    `json:"last_connection_time"      bson:"last_connection_time"`
 	UpdateHubagentErrorMsg string                   `json:"update_hubagent_error_msg" bson:"update_hubagent_error_msg"`
 	DindCfg                *DindCfg                 `json:"dind_cfg"                  bson:"dind_cfg"`
+	DragonflyConfig        *DragonflyConfig         `json:"dragonfly_config"          bson:"dragonfly_config"`
+	WarmPool               *WarmPoolConfig          `json:"warm_pool"                 bson:"warm_pool"`
 
 	// new field in 1.14, intended to enable kubeconfig for cluster management
 	Type       string `json:"type"           bson:"type"` // either agent or kubeconfig supported
@@ -121,6 +127,110 @@ type DindStorage struct {
 	StorageSizeInGiB int64           `json:"storage_size_in_gib" bson:"storage_size_in_gib"`
 }
 
+// DragonflyConfig configures the dragonfly P2P image distribution of the cluster
+type DragonflyConfig struct {
+	Enabled bool `json:"enabled"           bson:"enabled"`
+	// Mirrors defines which dfdaemon proxy is used to pull the images of a registry,
+	// images from the registries not listed here will be pulled as is.
+	Mirrors []*DragonflyMirror `json:"mirrors"           bson:"mirrors"`
+	// ManagerAddress and ManagerToken are used to call the dragonfly manager open api to preheat images
+	ManagerAddress string `json:"manager_address"   bson:"manager_address"`
+	ManagerToken   string `json:"manager_token"     bson:"manager_token"`
+}
+
+type DragonflyMirror struct {
+	Registry string `json:"registry" bson:"registry"`
+	Mirror   string `json:"mirror"   bson:"mirror"`
+}
+
+// MirrorImage returns the image to be pulled through the dragonfly mirror, it is safe to call on a nil config.
+func (c *DragonflyConfig) MirrorImage(image string) string {
+	if c == nil || !c.Enabled {
+		return image
+	}
+	return dragonfly.MirrorImage(image, c.mirrorMap())
+}
+
+// UnmirrorImage restores the image pulled through the dragonfly mirror to the original one,
+// it is used to compare the images in the cluster with the ones saved in zadig.
+func (c *DragonflyConfig) UnmirrorImage(image string) string {
+	if c == nil || !c.Enabled {
+		return image
+	}
+	return dragonfly.UnmirrorImage(image, c.mirrorMap())
+}
+
+func (c *DragonflyConfig) mirrorMap() map[string]string {
+	mirrors := make(map[string]string)
+	for _, mirror := range c.Mirrors {
+		mirrors[strings.TrimSuffix(mirror.Registry, "/")] = mirror.Mirror
+	}
+	return mirrors
+}
+
+func (c *DragonflyConfig) Validate() error {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+	for _, mirror := range c.Mirrors {
+		if mirror.Registry == "" || mirror.Mirror == "" {
+			return fmt.Errorf("registry and mirror address of dragonfly mirror can't be empty")
+		}
+		if strings.Contains(mirror.Registry, "://") || strings.Contains(mirror.Mirror, "://") {
+			return fmt.Errorf("registry and mirror address of dragonfly mirror should not contain scheme")
+		}
+	}
+	return nil
+}
+
+// WarmPoolConfig configures the pre-warmed job executor pods of the cluster, the freestyle jobs running with one of
+// the images and the same resource request claim an idle pod instead of waiting for a new one to be scheduled.
+type WarmPoolConfig struct {
+	Enabled bool `json:"enabled"          bson:"enabled"`
+	// Size is the number of idle pods kept for each image
+	Size            int                 `json:"size"             bson:"size"`
+	Images          []string            `json:"images"           bson:"images"`
+	ResourceRequest setting.Request     `json:"resource_request" bson:"resource_request"`
+	ResReqSpec      setting.RequestSpec `json:"res_req_spec"     bson:"res_req_spec"`
+}
+
+func (c *WarmPoolConfig) Validate() error {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+	if c.Size <= 0 || c.Size > 20 {
+		return fmt.Errorf("size of the warm pool should be between 1 and 20")
+	}
+	if len(c.Images) == 0 {
+		return fmt.Errorf("images of the warm pool can't be empty")
+	}
+	for _, image := range c.Images {
+		if image == "" {
+			return fmt.Errorf("image of the warm pool can't be empty")
+		}
+	}
+	if c.ResourceRequest == "" {
+		c.ResourceRequest = setting.MinRequest
+	}
+	return nil
+}
+
+// Matches returns whether a job with the image and the resource request can be run in the warm pool
+func (c *WarmPoolConfig) Matches(image string, resReq setting.Request, resReqSpec setting.RequestSpec) bool {
+	if c == nil || !c.Enabled || c.ResourceRequest != resReq {
+		return false
+	}
+	if resReq == setting.DefineRequest && !c.ResReqSpec.Equal(resReqSpec) {
+		return false
+	}
+	for _, i := range c.Images {
+		if i == image {
+			return true
+		}
+	}
+	return false
+}
+
 func (K8SCluster) TableName() string {
 	return "k8s_cluster"
 }
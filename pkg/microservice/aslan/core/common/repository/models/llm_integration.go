@@ -31,6 +31,10 @@ type LLMIntegration struct {
 	IsDefault    bool               `bson:"is_default"     json:"is_default"`
 	UpdatedBy    string             `bson:"updated_by"     json:"updated_by"`
 	UpdateTime   int64              `bson:"update_time"    json:"update_time"`
+	// EnableWorkflowAnalysis allows the logs of failed workflow tasks to be sent to the llm for troubleshooting
+	EnableWorkflowAnalysis bool `bson:"enable_workflow_analysis" json:"enable_workflow_analysis"`
+	// RedactionRules are regular expressions whose matches are masked before any workflow data is sent to the llm
+	RedactionRules []string `bson:"redaction_rules" json:"redaction_rules"`
 }
 
 func (llm LLMIntegration) TableName() string {
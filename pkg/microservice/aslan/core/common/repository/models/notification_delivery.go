@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryStatusQueued  NotificationDeliveryStatus = "queued"
+	NotificationDeliveryStatusSuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryStatusFailed  NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery records the delivery of a workflow task notification to one channel.
+// The webhook address is not saved since it contains the token of the robot.
+type NotificationDelivery struct {
+	ID           primitive.ObjectID         `bson:"_id,omitempty"   json:"id"`
+	ProjectName  string                     `bson:"project_name"    json:"project_name"`
+	WorkflowName string                     `bson:"workflow_name"   json:"workflow_name"`
+	TaskID       int64                      `bson:"task_id"         json:"task_id"`
+	JobName      string                     `bson:"job_name"        json:"job_name"`
+	WebHookType  setting.NotifyWebHookType  `bson:"webhook_type"    json:"webhook_type"`
+	Title        string                     `bson:"title"           json:"title"`
+	Status       NotificationDeliveryStatus `bson:"status"          json:"status"`
+	Attempts     int                        `bson:"attempts"        json:"attempts"`
+	// BatchSize is the count of the notifications merged into the message that carried this one
+	BatchSize  int    `bson:"batch_size"      json:"batch_size"`
+	Error      string `bson:"error"           json:"error"`
+	CreateTime int64  `bson:"create_time"     json:"create_time"`
+	UpdateTime int64  `bson:"update_time"     json:"update_time"`
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_delivery"
+}
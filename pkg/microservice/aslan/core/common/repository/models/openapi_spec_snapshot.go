@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// OpenAPISpecSnapshot is the OpenAPI spec of the /openapi routes in a zadig version.
+type OpenAPISpecSnapshot struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Version string             `bson:"version"       json:"version"`
+	// Digest is the sha256 of the spec, it changes if the routes or the annotations change in the same version
+	Digest string `bson:"digest"        json:"digest"`
+	// Spec is stored as the raw JSON since the keys like $ref are not allowed in mongo documents
+	Spec       string `bson:"spec"          json:"-"`
+	CreateTime int64  `bson:"create_time"   json:"create_time"`
+	UpdateTime int64  `bson:"update_time"   json:"update_time"`
+}
+
+func (OpenAPISpecSnapshot) TableName() string {
+	return "openapi_spec_snapshot"
+}
@@ -76,6 +76,9 @@ type VMAgent struct {
 	AgentVersion      string `bson:"agent_version"        json:"agent_version"`
 	ZadigVersion      string `bson:"zadig_version"        json:"zadig_version"`
 	LastHeartbeatTime int64  `bson:"last_heartbeat_time"  json:"last_heartbeat_time"`
+	// CurrentVersion is the version of the running agent reported by the heartbeats, AgentVersion is the version the
+	// agent is upgraded to when NeedUpdate is set.
+	CurrentVersion string `bson:"current_version" json:"current_version"`
 }
 
 func (PrivateKey) TableName() string {
@@ -73,6 +73,8 @@ type Product struct {
 
 	// New Since v1.19.0, env sleep configs
 	PreSleepStatus map[string]int `bson:"pre_sleep_status" json:"pre_sleep_status"`
+	// PreSleepState keeps the cronjobs and autoscalers paused when the env sleeps, they are restored on wake
+	PreSleepState *EnvPreSleepState `bson:"pre_sleep_state,omitempty" json:"pre_sleep_state,omitempty"`
 
 	// New Since v1.19.0, for env global variables
 	// GlobalValues for helm projects
@@ -87,6 +89,68 @@ type Product struct {
 	// For production environment
 	Production bool   `json:"production" bson:"production"`
 	Alias      string `json:"alias" bson:"alias"`
+
+	// ServiceRevisionPins pins the service template revisions used when the services are updated in the env
+	ServiceRevisionPins []*ServiceRevisionPin `bson:"service_revision_pins" json:"service_revision_pins"`
+
+	// Owner is set when the env is created, it's changed by the owner api only.
+	Owner *ResourceOwner `bson:"owner,omitempty" json:"owner,omitempty"`
+
+	// TTL is set for one-off envs, the env is torn down by the teardown workflow when it expires.
+	TTL *EnvTTL `bson:"ttl,omitempty" json:"ttl,omitempty"`
+
+	// ClusterRemap is set when the env is copied from an env in another cluster, the rendered service yamls are
+	// remapped with it so that the cluster specific resources are available in the new cluster.
+	ClusterRemap *ClusterRemapRules `bson:"cluster_remap,omitempty" json:"cluster_remap,omitempty"`
+
+	// FluxResources are the fluxcd HelmRelease and Kustomization objects backing the env
+	FluxResources []*FluxResource `bson:"flux_resources,omitempty" json:"flux_resources,omitempty"`
+}
+
+// FluxResource references a fluxcd HelmRelease or Kustomization object in the cluster of the env
+type FluxResource struct {
+	Kind string `bson:"kind"      json:"kind"`
+	Name string `bson:"name"      json:"name"`
+	// Namespace of the object, the namespace of the env is used if it is empty
+	Namespace string `bson:"namespace" json:"namespace"`
+}
+
+// ClusterRemapRules maps the storage classes and ingress classes used in the source cluster to the ones in the target cluster.
+type ClusterRemapRules struct {
+	StorageClasses map[string]string `bson:"storage_classes" json:"storage_classes"`
+	IngressClasses map[string]string `bson:"ingress_classes" json:"ingress_classes"`
+}
+
+func (r *ClusterRemapRules) IsEmpty() bool {
+	return r == nil || (len(r.StorageClasses) == 0 && len(r.IngressClasses) == 0)
+}
+
+type EnvTTL struct {
+	ExpireTime int64 `bson:"expire_time"                 json:"expire_time"`
+	// TeardownWorkflow is run before the env is deleted, e.g. to export data, the env is deleted directly if it's empty.
+	TeardownWorkflow string              `bson:"teardown_workflow"           json:"teardown_workflow"`
+	TeardownTaskID   int64               `bson:"teardown_task_id,omitempty"  json:"teardown_task_id,omitempty"`
+	Status           config.EnvTTLStatus `bson:"status"                      json:"status"`
+	Extension        *EnvTTLExtension    `bson:"extension,omitempty"         json:"extension,omitempty"`
+}
+
+// EnvTTLExtension is a request to postpone the expiry of the env, it takes effect after being approved by the project admin.
+type EnvTTLExtension struct {
+	RequestedBy     string                       `bson:"requested_by"     json:"requested_by"`
+	RequestedByName string                       `bson:"requested_by_name" json:"requested_by_name"`
+	Reason          string                       `bson:"reason"           json:"reason"`
+	ExpireTime      int64                        `bson:"expire_time"      json:"expire_time"`
+	RequestTime     int64                        `bson:"request_time"     json:"request_time"`
+	Status          config.EnvTTLExtensionStatus `bson:"status"           json:"status"`
+	ReviewedBy      string                       `bson:"reviewed_by"      json:"reviewed_by"`
+	ReviewTime      int64                        `bson:"review_time"      json:"review_time"`
+}
+
+type ServiceRevisionPin struct {
+	ServiceName string `bson:"service_name" json:"service_name"`
+	Revision    int64  `bson:"revision"     json:"revision"`
+	PinnedBy    string `bson:"pinned_by"    json:"pinned_by"`
+	PinTime     int64  `bson:"pin_time"     json:"pin_time"`
 }
 
 type NotificationEvent string
@@ -270,6 +334,21 @@ func (Product) TableName() string {
 }
 
 // GetNamespace returns the default name of namespace created by zadig
+type EnvPreSleepState struct {
+	// SuspendedCronJobs are the cronjobs already suspended before the env sleeps, they are kept suspended on wake
+	SuspendedCronJobs []string              `bson:"suspended_cronjobs" json:"suspended_cronjobs"`
+	Autoscalers       []*PreSleepAutoscaler `bson:"autoscalers"        json:"autoscalers"`
+}
+
+// PreSleepAutoscaler is an autoscaler paused when the env sleeps, HPAs are deleted and recreated from the manifest on wake,
+// KEDA ScaledObjects are paused by the paused-replicas annotation whose original value is kept in PausedReplicas.
+type PreSleepAutoscaler struct {
+	Kind           string `bson:"kind"            json:"kind"`
+	Name           string `bson:"name"            json:"name"`
+	Manifest       string `bson:"manifest"        json:"manifest"`
+	PausedReplicas string `bson:"paused_replicas" json:"paused_replicas"`
+}
+
 func (p *Product) GetDefaultNamespace() string {
 	return p.ProductName + "-env-" + p.EnvName
 }
@@ -318,6 +397,16 @@ func (p *Product) GetSvcList() []*ProductService {
 	return ret
 }
 
+// GetPinnedServiceRevision returns the pinned template revision of the service, 0 means not pinned
+func (p *Product) GetPinnedServiceRevision(serviceName string) int64 {
+	for _, pin := range p.ServiceRevisionPins {
+		if pin.ServiceName == serviceName {
+			return pin.Revision
+		}
+	}
+	return 0
+}
+
 func (p *Product) GetServiceMap() map[string]*ProductService {
 	ret := make(map[string]*ProductService)
 	for _, group := range p.Services {
@@ -38,6 +38,10 @@ type ProjectManagement struct {
 	MeegoPluginSecret       string `bson:"meego_plugin_secret" json:"meego_plugin_secret"`
 	MeegoUserKey            string `bson:"meego_user_key"      json:"meego_user_key"`
 	UpdatedAt               int64  `bson:"updated_at"          json:"updated_at"`
+	// ServiceNowPassword is the password of the ServiceNow user, it's used for basic auth
+	ServiceNowHost     string `bson:"servicenow_host"     json:"servicenow_host"`
+	ServiceNowUser     string `bson:"servicenow_user"     json:"servicenow_user"`
+	ServiceNowPassword string `bson:"servicenow_password" json:"servicenow_password"`
 }
 
 func (ProjectManagement) TableName() string {
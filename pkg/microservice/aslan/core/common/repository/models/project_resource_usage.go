@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ProjectResourceUsage is the monthly rollup of the zadig resources consumed by a project, used for chargeback
+type ProjectResourceUsage struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"          json:"id,omitempty"`
+	ProjectName string             `bson:"project_name"           json:"project_name"`
+	// Month is in the form of 2006-01
+	Month string `bson:"month"                  json:"month"`
+	// the job usages are calculated from the pod jobs of the workflow tasks created in the month,
+	// the cpu and memory are the limits of the job pods
+	JobCount            int64   `bson:"job_count"              json:"job_count"`
+	JobMinutes          float64 `bson:"job_minutes"            json:"job_minutes"`
+	JobCPUMinutes       float64 `bson:"job_cpu_minutes"        json:"job_cpu_minutes"`
+	JobMemoryGiBMinutes float64 `bson:"job_memory_gib_minutes" json:"job_memory_gib_minutes"`
+	// the storage usages are the sizes in the default object storage when they are measured
+	BuildCacheBytes   int64 `bson:"build_cache_bytes"      json:"build_cache_bytes"`
+	ArtifactBytes     int64 `bson:"artifact_bytes"         json:"artifact_bytes"`
+	LogBytes          int64 `bson:"log_bytes"              json:"log_bytes"`
+	StorageUpdateTime int64 `bson:"storage_update_time"    json:"storage_update_time"`
+	UpdateTime        int64 `bson:"update_time"            json:"update_time"`
+}
+
+func (ProjectResourceUsage) TableName() string {
+	return "project_resource_usage"
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectServiceAccount is a non-human identity of a project, automated triggers like crons and webhooks
+// create the tasks of a workflow as the service account set in the workflow's run_as field.
+type ProjectServiceAccount struct {
+	ID          primitive.ObjectID        `bson:"_id,omitempty" json:"id,omitempty"`
+	ProjectName string                    `bson:"project_name"  json:"project_name"`
+	Name        string                    `bson:"name"          json:"name"`
+	Description string                    `bson:"description"   json:"description"`
+	Disabled    bool                      `bson:"disabled"      json:"disabled"`
+	Permission  *ServiceAccountPermission `bson:"permission"    json:"permission"`
+	CreatedBy   string                    `bson:"created_by"    json:"created_by"`
+	CreateTime  int64                     `bson:"create_time"   json:"create_time"`
+	UpdatedBy   string                    `bson:"updated_by"    json:"updated_by"`
+	UpdateTime  int64                     `bson:"update_time"   json:"update_time"`
+}
+
+// ServiceAccountPermission lists what the tasks run as the service account are allowed to do,
+// "*" stands for all the workflows or envs of the project.
+type ServiceAccountPermission struct {
+	Workflows      []string `bson:"workflows"       json:"workflows"`
+	Envs           []string `bson:"envs"            json:"envs"`
+	ProductionEnvs []string `bson:"production_envs" json:"production_envs"`
+}
+
+func (ProjectServiceAccount) TableName() string {
+	return "project_service_account"
+}
+
+func (sa *ProjectServiceAccount) CanRunWorkflow(workflowName string) bool {
+	if sa.Permission == nil {
+		return false
+	}
+	return permissionContains(sa.Permission.Workflows, workflowName)
+}
+
+func (sa *ProjectServiceAccount) CanDeployEnv(envName string, production bool) bool {
+	if sa.Permission == nil {
+		return false
+	}
+	if production {
+		return permissionContains(sa.Permission.ProductionEnvs, envName)
+	}
+	return permissionContains(sa.Permission.Envs, envName)
+}
+
+func permissionContains(items []string, name string) bool {
+	for _, item := range items {
+		if item == "*" || item == name {
+			return true
+		}
+	}
+	return false
+}
@@ -45,7 +45,46 @@ type RegistryNamespace struct {
 	UpdateTime int64  `bson:"update_time"                 json:"update_time"`
 	UpdateBy   string `bson:"update_by"                   json:"update_by"`
 
-	AdvancedSetting *RegistryAdvancedSetting `bson:"advanced_setting" json:"advanced_setting"`
+	AdvancedSetting   *RegistryAdvancedSetting   `bson:"advanced_setting" json:"advanced_setting"`
+	CredentialSetting *RegistryCredentialSetting `bson:"credential_setting,omitempty" json:"credential_setting,omitempty"`
+	// CredentialStatus is maintained by the credential check cron job, it's never updated by the users
+	CredentialStatus *RegistryCredentialStatus `bson:"credential_status,omitempty" json:"credential_status,omitempty"`
+}
+
+// RegistryCredentialSetting manages the lifecycle of the registry credential
+type RegistryCredentialSetting struct {
+	// Temporary uses the access key pair to request temporary login tokens, it's supported by ACR Enterprise.
+	// AWS ECR always uses temporary tokens.
+	Temporary bool `bson:"temporary"   json:"temporary"`
+	// InstanceID of the ACR Enterprise instance, required by the temporary tokens of ACR Enterprise
+	InstanceID string `bson:"instance_id" json:"instance_id"`
+	// AutoRotate refreshes the image pull secrets in the env namespaces before the temporary tokens expire
+	AutoRotate bool `bson:"auto_rotate" json:"auto_rotate"`
+	// ExpireTime is the unix timestamp when the static credential, e.g. a robot account, expires, 0 means never
+	ExpireTime int64 `bson:"expire_time" json:"expire_time"`
+	// AlertDays is the number of days before expiry from which notifications are sent, 7 by default
+	AlertDays  int          `bson:"alert_days"  json:"alert_days"`
+	NotifyCtls []*NotifyCtl `bson:"notify_ctls" json:"notify_ctls"`
+}
+
+type RegistryCredentialStatus struct {
+	Valid     bool   `bson:"valid"      json:"valid"`
+	Message   string `bson:"message"    json:"message"`
+	CheckTime int64  `bson:"check_time" json:"check_time"`
+	// TokenExpireTime is the expiry time of the current temporary token
+	TokenExpireTime int64 `bson:"token_expire_time" json:"token_expire_time"`
+	RotateTime      int64 `bson:"rotate_time"       json:"rotate_time"`
+	// AlertTime is the last time the notifications are sent, used to send at most one notification a day
+	AlertTime int64 `bson:"alert_time"        json:"alert_time"`
+}
+
+// UseTemporaryCredential returns whether the access key pair is used to request temporary login tokens
+func (ns *RegistryNamespace) UseTemporaryCredential() bool {
+	if ns.RegProvider == config.RegistryTypeAWS {
+		return true
+	}
+	return ns.RegProvider == config.RegistryProviderACREnterprise && ns.CredentialSetting != nil &&
+		ns.CredentialSetting.Temporary && ns.CredentialSetting.InstanceID != ""
 }
 
 type RegistryAdvancedSetting struct {
@@ -72,6 +111,20 @@ func (ns *RegistryNamespace) Validate() error {
 		return errors.New("empty namespace")
 	}
 
+	if setting := ns.CredentialSetting; setting != nil {
+		if setting.Temporary {
+			if ns.RegProvider != config.RegistryProviderACREnterprise {
+				return fmt.Errorf("temporary credential is not supported by registry provider %s", ns.RegProvider)
+			}
+			if setting.InstanceID == "" {
+				return errors.New("instance id is required by the temporary credential")
+			}
+		}
+		if setting.AlertDays < 0 {
+			return errors.New("alert days can not be negative")
+		}
+	}
+
 	return nil
 }
 
@@ -73,6 +73,11 @@ type ReleaseJob struct {
 	Name string                    `bson:"name"       yaml:"name"                   json:"name"`
 	Type config.ReleasePlanJobType `bson:"type"       yaml:"type"                   json:"type"`
 	Spec interface{}               `bson:"spec"       yaml:"spec"                   json:"spec"`
+	// DependsOn are the ids of the jobs in the same plan which should be finished before this job starts
+	DependsOn []string `bson:"depends_on,omitempty" yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	// PlannedStartTime and PlannedEndTime are the planned time window of the job, 0 if not planned
+	PlannedStartTime int64 `bson:"planned_start_time,omitempty" yaml:"planned_start_time,omitempty" json:"planned_start_time,omitempty"`
+	PlannedEndTime   int64 `bson:"planned_end_time,omitempty"   yaml:"planned_end_time,omitempty"   json:"planned_end_time,omitempty"`
 
 	ReleaseJobRuntime `bson:",inline" yaml:",inline" json:",inline"`
 }
@@ -99,6 +104,33 @@ type WorkflowReleaseJobSpec struct {
 	TaskID   int64         `bson:"task_id"       yaml:"task_id"                   json:"task_id"`
 }
 
+// ChangeTicketReleaseJobSpec opens a ServiceNow change request or a Jira issue when the plan is approved, the other
+// jobs of the plan can't be executed until the ticket is approved.
+type ChangeTicketReleaseJobSpec struct {
+	Source config.ChangeTicketSource `bson:"source"             yaml:"source"             json:"source"`
+	// ProjectManagementID is the id of the ServiceNow or Jira integration
+	ProjectManagementID string `bson:"project_management_id" yaml:"project_management_id" json:"project_management_id"`
+	Summary             string `bson:"summary"               yaml:"summary"               json:"summary"`
+	Description         string `bson:"description"           yaml:"description"           json:"description"`
+	// AssignmentGroup and ChangeType are only used by ServiceNow
+	AssignmentGroup string `bson:"assignment_group"      yaml:"assignment_group"      json:"assignment_group"`
+	ChangeType      string `bson:"change_type"           yaml:"change_type"           json:"change_type"`
+	// JiraProject, JiraIssueType, ApprovedStatus and RejectedStatus are only used by Jira, the issue is approved
+	// when its status is one of ApprovedStatus and rejected when its status is one of RejectedStatus.
+	JiraProject    string   `bson:"jira_project"          yaml:"jira_project"          json:"jira_project"`
+	JiraIssueType  string   `bson:"jira_issue_type"       yaml:"jira_issue_type"       json:"jira_issue_type"`
+	ApprovedStatus []string `bson:"approved_status"       yaml:"approved_status"       json:"approved_status"`
+	RejectedStatus []string `bson:"rejected_status"       yaml:"rejected_status"       json:"rejected_status"`
+
+	// runtime info of the ticket
+	TicketID      string `bson:"ticket_id"             yaml:"ticket_id"             json:"ticket_id"`
+	TicketNumber  string `bson:"ticket_number"         yaml:"ticket_number"         json:"ticket_number"`
+	TicketURL     string `bson:"ticket_url"            yaml:"ticket_url"            json:"ticket_url"`
+	TicketStatus  string `bson:"ticket_status"         yaml:"ticket_status"         json:"ticket_status"`
+	LastCheckTime int64  `bson:"last_check_time"       yaml:"last_check_time"       json:"last_check_time"`
+	Error         string `bson:"error"                 yaml:"error"                 json:"error"`
+}
+
 type ReleasePlanLog struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty"               json:"id"`
 	PlanID     string             `bson:"plan_id"                     json:"plan_id"`
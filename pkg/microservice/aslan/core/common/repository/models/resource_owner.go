@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// ResourceOwner is the user or the user group responsible for a service, workflow or env.
+type ResourceOwner struct {
+	Type config.OwnerType `bson:"type"          json:"type"          yaml:"type"`
+	// ID is the uid of the user or the id of the user group
+	ID   string `bson:"id"            json:"id"            yaml:"id"`
+	Name string `bson:"name"          json:"name"          yaml:"name"`
+}
+
+// StaleOwnerResource is a resource whose owner has left, e.g. the owner user is deleted, it's removed once the owner
+// is reassigned.
+type StaleOwnerResource struct {
+	ID           primitive.ObjectID       `bson:"_id,omitempty"   json:"id"`
+	ProjectName  string                   `bson:"project_name"    json:"project_name"`
+	ResourceType config.OwnedResourceType `bson:"resource_type"   json:"resource_type"`
+	ResourceName string                   `bson:"resource_name"   json:"resource_name"`
+	Production   bool                     `bson:"production"      json:"production"`
+	Owner        *ResourceOwner           `bson:"owner"           json:"owner"`
+	DetectTime   int64                    `bson:"detect_time"     json:"detect_time"`
+}
+
+func (StaleOwnerResource) TableName() string {
+	return "stale_owner_resource"
+}
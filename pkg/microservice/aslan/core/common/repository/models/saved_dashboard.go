@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type DashboardVisibility string
+
+const (
+	// DashboardVisibilityPrivate means the dashboard can only be viewed by its creator
+	DashboardVisibilityPrivate DashboardVisibility = "private"
+	// DashboardVisibilityProject means the dashboard can be viewed by the members of its project
+	DashboardVisibilityProject DashboardVisibility = "project"
+	// DashboardVisibilityPublic means the dashboard can be viewed by all the users
+	DashboardVisibilityPublic DashboardVisibility = "public"
+)
+
+type DashboardWidgetType string
+
+const (
+	DashboardWidgetTypeWorkflowTask DashboardWidgetType = "workflow_task"
+	DashboardWidgetTypeEnvironment  DashboardWidgetType = "environment"
+	DashboardWidgetTypeService      DashboardWidgetType = "service"
+)
+
+// SavedDashboard is a user defined view made of widgets filtering over workflow tasks, envs and services
+type SavedDashboard struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"         json:"id,omitempty"`
+	Name        string              `bson:"name"                  json:"name"`
+	Description string              `bson:"description"           json:"description"`
+	ProjectName string              `bson:"project_name"          json:"project_name"`
+	Visibility  DashboardVisibility `bson:"visibility"            json:"visibility"`
+	Widgets     []*DashboardWidget  `bson:"widgets"               json:"widgets"`
+	ShareToken  string              `bson:"share_token,omitempty" json:"share_token,omitempty"`
+	CreatorID   string              `bson:"creator_id"            json:"creator_id"`
+	CreatedBy   string              `bson:"created_by"            json:"created_by"`
+	CreateTime  int64               `bson:"create_time"           json:"create_time"`
+	UpdateTime  int64               `bson:"update_time"           json:"update_time"`
+}
+
+type DashboardWidget struct {
+	ID     string              `bson:"id"     json:"id"`
+	Name   string              `bson:"name"   json:"name"`
+	Type   DashboardWidgetType `bson:"type"   json:"type"`
+	Filter *DashboardFilter    `bson:"filter" json:"filter"`
+}
+
+// DashboardFilter filters the data of a widget, empty fields match everything
+type DashboardFilter struct {
+	Projects   []string `bson:"projects"   json:"projects"`
+	Workflows  []string `bson:"workflows"  json:"workflows"`
+	Envs       []string `bson:"envs"       json:"envs"`
+	Services   []string `bson:"services"   json:"services"`
+	Statuses   []string `bson:"statuses"   json:"statuses"`
+	Production *bool    `bson:"production" json:"production"`
+	// Days is the time range of the workflow tasks
+	Days int `bson:"days"       json:"days"`
+	// Limit is the max number of the items returned
+	Limit int `bson:"limit"      json:"limit"`
+}
+
+func (SavedDashboard) TableName() string {
+	return "saved_dashboard"
+}
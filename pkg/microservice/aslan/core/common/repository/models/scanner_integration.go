@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ScannerIntegration holds the code scanners other than sonar, distinguished by Type
+type ScannerIntegration struct {
+	ID            primitive.ObjectID `json:"id"             bson:"_id,omitempty"`
+	Type          string             `json:"type"           bson:"type"`
+	Name          string             `json:"name"           bson:"name"`
+	ServerAddress string             `json:"server_address" bson:"server_address"`
+	Token         string             `json:"token"          bson:"token"`
+	// AuthAddress and Tenant are used for checkmarx only
+	AuthAddress string `json:"auth_address" bson:"auth_address"`
+	Tenant      string `json:"tenant"       bson:"tenant"`
+	UpdateBy    string `json:"update_by"    bson:"update_by"`
+	UpdateTime  int64  `json:"update_time"  bson:"update_time"`
+}
+
+func (ScannerIntegration) TableName() string {
+	return "scanner_integration"
+}
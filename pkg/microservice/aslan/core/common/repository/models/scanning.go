@@ -41,6 +41,8 @@ type Scanning struct {
 	VMLabels       []string            `bson:"vm_labels"                json:"vm_labels"`
 	// Parameter is for sonarQube type only
 	Parameter string `bson:"parameter" json:"parameter"`
+	// ScannerIntegrationID is for semgrep and checkmarx type only
+	ScannerIntegrationID string `bson:"scanner_integration_id" json:"scanner_integration_id"`
 	// Envs is the user defined key/values
 	Envs []*KeyVal `bson:"envs" json:"envs"`
 	// Script is for other type only
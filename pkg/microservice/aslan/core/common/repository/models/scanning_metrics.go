@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ScanningMetrics is the sonar metrics of one scanning in one workflow task, used to chart code quality trends
+type ScanningMetrics struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"       json:"id"`
+	ProjectName       string             `bson:"project_name"        json:"project_name"`
+	ScanningName      string             `bson:"scanning_name"       json:"scanning_name"`
+	WorkflowName      string             `bson:"workflow_name"       json:"workflow_name"`
+	JobName           string             `bson:"job_name"            json:"job_name"`
+	TaskID            int64              `bson:"task_id"             json:"task_id"`
+	Bugs              int64              `bson:"bugs"                json:"bugs"`
+	Vulnerabilities   int64              `bson:"vulnerabilities"     json:"vulnerabilities"`
+	CodeSmells        int64              `bson:"code_smells"         json:"code_smells"`
+	Coverage          float64            `bson:"coverage"            json:"coverage"`
+	Ncloc             int64              `bson:"ncloc"               json:"ncloc"`
+	QualityGateStatus string             `bson:"quality_gate_status" json:"quality_gate_status"`
+	// Date is the day of CreateTime in config.Date format, the trend is aggregated by it
+	Date       string `bson:"date"        json:"date"`
+	CreateTime int64  `bson:"create_time" json:"create_time"`
+}
+
+func (ScanningMetrics) TableName() string {
+	return "scanning_metrics"
+}
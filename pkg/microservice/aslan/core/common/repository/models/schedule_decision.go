@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type ScheduleDecisionKind string
+
+const (
+	ScheduleDecisionKindWorkflowTask ScheduleDecisionKind = "workflow_task"
+	ScheduleDecisionKindJobPod       ScheduleDecisionKind = "job_pod"
+)
+
+// ScheduleDecision records why a waiting workflow task or job pod is picked by the fair scheduler
+type ScheduleDecision struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty"    json:"id"`
+	Kind         ScheduleDecisionKind `bson:"kind"             json:"kind"`
+	ProjectName  string               `bson:"project_name"     json:"project_name"`
+	WorkflowName string               `bson:"workflow_name"    json:"workflow_name"`
+	TaskID       int64                `bson:"task_id"          json:"task_id"`
+	JobName      string               `bson:"job_name"         json:"job_name,omitempty"`
+	Weight       int                  `bson:"weight"           json:"weight"`
+	// Running is the number of the running tasks or job pods of the project when the task or job pod is picked
+	Running int `bson:"running"          json:"running"`
+	// WaitingProjects is the number of the projects with waiting tasks or job pods when the task or job pod is picked
+	WaitingProjects int   `bson:"waiting_projects" json:"waiting_projects"`
+	CreateTime      int64 `bson:"create_time"      json:"create_time"`
+}
+
+func (ScheduleDecision) TableName() string {
+	return "schedule_decision"
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// SecretProvider is an external secret store that the credential variables can reference by its name,
+// Address, Token, Namespace and MountPath are used for vault, Region, AccessKey and SecretKey are used for aws secrets manager.
+type SecretProvider struct {
+	ID         primitive.ObjectID `json:"id"          bson:"_id,omitempty"`
+	Type       string             `json:"type"        bson:"type"`
+	Name       string             `json:"name"        bson:"name"`
+	Address    string             `json:"address"     bson:"address"`
+	Token      string             `json:"token"       bson:"token"`
+	Namespace  string             `json:"namespace"   bson:"namespace"`
+	MountPath  string             `json:"mount_path"  bson:"mount_path"`
+	Region     string             `json:"region"      bson:"region"`
+	AccessKey  string             `json:"access_key"  bson:"access_key"`
+	SecretKey  string             `json:"secret_key"  bson:"secret_key"`
+	UpdateBy   string             `json:"update_by"   bson:"update_by"`
+	UpdateTime int64              `json:"update_time" bson:"update_time"`
+}
+
+func (SecretProvider) TableName() string {
+	return "secret_provider"
+}
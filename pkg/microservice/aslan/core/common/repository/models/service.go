@@ -17,8 +17,11 @@ limitations under the License.
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
 	commontypes "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/types"
 	"github.com/koderover/zadig/v2/pkg/setting"
@@ -82,9 +85,65 @@ type Service struct {
 	DeployTime         int64                            `bson:"deploy_time,omitempty"          json:"deploy_time,omitempty"`
 	TemplateID         string                           `bson:"template_id,omitempty"          json:"template_id,omitempty"`
 	AutoSync           bool                             `bson:"auto_sync"                      json:"auto_sync"`
+	FirstDeployHook    *FirstDeployHook                 `bson:"first_deploy_hook,omitempty"    json:"first_deploy_hook,omitempty"`
+	Owner              *ResourceOwner                   `bson:"owner,omitempty"                json:"owner,omitempty"`
+	DeployWindow       *DeployWindow                    `bson:"deploy_window,omitempty"        json:"deploy_window,omitempty"`
+	Lockout            *ServiceLockout                  `bson:"lockout,omitempty"              json:"lockout,omitempty"`
 	Production         bool                             `bson:"-"                              json:"-"` // check current service data is production service
 }
 
+// DeployWindow limits when the service can be deployed without the approval of its owner. The window of a production
+// service applies to the production envs and the window of a testing service applies to the testing envs.
+type DeployWindow struct {
+	Enabled bool `bson:"enabled"          json:"enabled"`
+	// Timezone is an IANA time zone name like Asia/Shanghai, the timezone of aslan is used if it is empty
+	Timezone string                `bson:"timezone"         json:"timezone"`
+	Periods  []*DeployWindowPeriod `bson:"periods"          json:"periods"`
+	// ApprovalTimeout is the minutes to wait for the owner approval of out-of-window deployments, 60 by default
+	ApprovalTimeout int64 `bson:"approval_timeout" json:"approval_timeout"`
+}
+
+type DeployWindowPeriod struct {
+	// Weekdays of the period, 0 is Sunday, every day is allowed if it is empty
+	Weekdays []int `bson:"weekdays"   json:"weekdays"`
+	// StartTime and EndTime are in the form of HH:MM, a period whose end is earlier than its start ends on the next day
+	StartTime string `bson:"start_time" json:"start_time"`
+	EndTime   string `bson:"end_time"   json:"end_time"`
+}
+
+// ServiceLockout blocks new build and deploy jobs of the service, e.g. when it is mid-migration or quarantined.
+// The lockout of a production service applies to the production envs and the lockout of a testing service applies
+// to the testing envs, builds are blocked by either of them.
+type ServiceLockout struct {
+	Enabled bool   `bson:"enabled"     json:"enabled"`
+	Reason  string `bson:"reason"      json:"reason"`
+	// ExpireTime is the unix timestamp when the lockout is lifted automatically, 0 means it never expires
+	ExpireTime int64  `bson:"expire_time" json:"expire_time"`
+	LockedBy   string `bson:"locked_by"   json:"locked_by"`
+	LockTime   int64  `bson:"lock_time"   json:"lock_time"`
+}
+
+// Active returns whether the lockout blocks the jobs at the given time.
+func (l *ServiceLockout) Active(t time.Time) bool {
+	if l == nil || !l.Enabled {
+		return false
+	}
+	return l.ExpireTime == 0 || t.Unix() < l.ExpireTime
+}
+
+// FirstDeployHook runs once when the service is deployed into an env for the first time, e.g. to create the schemas and seed data.
+type FirstDeployHook struct {
+	Enabled bool                       `bson:"enabled"            json:"enabled"`
+	Type    config.FirstDeployHookType `bson:"type"               json:"type"`
+	// Image and Script are used by the script hook
+	Image  string `bson:"image"              json:"image"`
+	Script string `bson:"script"             json:"script"`
+	// Manifest is the k8s Job used by the manifest hook
+	Manifest string `bson:"manifest"           json:"manifest"`
+	// Timeout in seconds
+	Timeout int64 `bson:"timeout"            json:"timeout"`
+}
+
 type CreateFromRepo struct {
 	GitRepoConfig *templatemodels.GitRepoConfig `bson:"git_repo_config,omitempty"      json:"git_repo_config,omitempty"`
 	LoadPath      string                        `bson:"load_path,omitempty"            json:"load_path,omitempty"`
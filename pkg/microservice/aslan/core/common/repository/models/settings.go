@@ -27,6 +27,12 @@ type SystemSetting struct {
 	Security            *SecuritySettings  `bson:"security" json:"security"`
 	Privacy             *PrivacySettings   `bson:"privacy"  json:"privacy"`
 	UpdateTime          int64              `bson:"update_time" json:"update_time"`
+	// FairScheduling shares the workflow concurrency among the projects by weight
+	FairScheduling *FairSchedulingSettings `bson:"fair_scheduling" json:"fair_scheduling"`
+	// CostPrice is the unit price used to estimate the cost of the environments
+	CostPrice *EnvCostPrice `bson:"cost_price" json:"cost_price"`
+	// ManifestLint configures the checks of k8s yaml when saving service templates and k8s patch jobs
+	ManifestLint *ManifestLintSettings `bson:"manifest_lint" json:"manifest_lint"`
 }
 
 type Theme struct {
@@ -61,6 +67,69 @@ type CustomTheme struct {
 	LinkColor                string `bson:"link_color" json:"link_color"`
 }
 
+// FairSchedulingSettings configures the weighted fair scheduling of the workflow task queue, when it is enabled
+// the waiting task of the project with the lowest running tasks per weight is scheduled first. The job pods are
+// scheduled the same way when JobPodConcurrency is set.
+type FairSchedulingSettings struct {
+	Enabled       bool                       `bson:"enabled" json:"enabled"`
+	DefaultWeight int                        `bson:"default_weight" json:"default_weight"`
+	Weights       []*ProjectSchedulingWeight `bson:"weights" json:"weights"`
+	// JobPodConcurrency is the max number of the job pods running at the same time across the projects, 0 means no limit
+	JobPodConcurrency int `bson:"job_pod_concurrency" json:"job_pod_concurrency"`
+}
+
+type ProjectSchedulingWeight struct {
+	ProjectName string `bson:"project_name" json:"project_name"`
+	Weight      int    `bson:"weight" json:"weight"`
+}
+
+// GetWeight returns the weight of the project, the default weight is 1
+func (s *FairSchedulingSettings) GetWeight(projectName string) int {
+	for _, weight := range s.Weights {
+		if weight.ProjectName == projectName && weight.Weight > 0 {
+			return weight.Weight
+		}
+	}
+	if s.DefaultWeight > 0 {
+		return s.DefaultWeight
+	}
+	return 1
+}
+
+// ManifestLintSettings configures the checks of k8s yaml, the schema of the target clusters is checked if SchemaValidation is set,
+// kube-score and polaris are optional. Findings of error level are rejected only if BlockOnError is set.
+type ManifestLintSettings struct {
+	SchemaValidation bool `bson:"schema_validation" json:"schema_validation"`
+	KubeScore        bool `bson:"kube_score" json:"kube_score"`
+	Polaris          bool `bson:"polaris" json:"polaris"`
+	BlockOnError     bool `bson:"block_on_error" json:"block_on_error"`
+}
+
+// EnvCostPrice is the unit price of the requested resources, prices of the cluster in ClusterPrices
+// override the default ones.
+type EnvCostPrice struct {
+	Currency           string              `bson:"currency" json:"currency"`
+	CPUCoreHourPrice   float64             `bson:"cpu_core_hour_price" json:"cpu_core_hour_price"`
+	MemoryGiBHourPrice float64             `bson:"memory_gib_hour_price" json:"memory_gib_hour_price"`
+	ClusterPrices      []*ClusterCostPrice `bson:"cluster_prices" json:"cluster_prices"`
+}
+
+type ClusterCostPrice struct {
+	ClusterID          string  `bson:"cluster_id" json:"cluster_id"`
+	CPUCoreHourPrice   float64 `bson:"cpu_core_hour_price" json:"cpu_core_hour_price"`
+	MemoryGiBHourPrice float64 `bson:"memory_gib_hour_price" json:"memory_gib_hour_price"`
+}
+
+// GetPrice returns the cpu core hour price and the memory GiB hour price of the cluster
+func (p *EnvCostPrice) GetPrice(clusterID string) (float64, float64) {
+	for _, price := range p.ClusterPrices {
+		if price.ClusterID == clusterID {
+			return price.CPUCoreHourPrice, price.MemoryGiBHourPrice
+		}
+	}
+	return p.CPUCoreHourPrice, p.MemoryGiBHourPrice
+}
+
 type SecuritySettings struct {
 	TokenExpirationTime int64 `json:"token_expiration_time" bson:"token_expiration_time"`
 }
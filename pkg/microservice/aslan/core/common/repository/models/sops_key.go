@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	SopsKeyTypeAge    = "age"
+	SopsKeyTypeAWSKMS = "aws_kms"
+)
+
+// SopsKey is the key used to decrypt the helm values encrypted by sops,
+// AgeKey is used for age, AccessKey and SecretKey are used for aws kms and the credentials of aslan are used if they are empty.
+type SopsKey struct {
+	ID         primitive.ObjectID `json:"id"          bson:"_id,omitempty"`
+	Type       string             `json:"type"        bson:"type"`
+	Name       string             `json:"name"        bson:"name"`
+	AgeKey     string             `json:"age_key"     bson:"age_key"`
+	AccessKey  string             `json:"access_key"  bson:"access_key"`
+	SecretKey  string             `json:"secret_key"  bson:"secret_key"`
+	UpdateBy   string             `json:"update_by"   bson:"update_by"`
+	UpdateTime int64              `json:"update_time" bson:"update_time"`
+}
+
+func (SopsKey) TableName() string {
+	return "sops_key"
+}
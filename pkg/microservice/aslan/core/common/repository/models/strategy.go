@@ -25,6 +25,10 @@ const (
 	// 工作流任务的留存
 	WorkflowTaskRetention     CapacityTarget = "WorkflowTaskRetention"
 	DefaultWorkflowRemainDays int            = 365
+	// 构建缓存的留存
+	BuildCacheRetention CapacityTarget = "BuildCacheRetention"
+	// 工作流任务的冷存储，超过 MaxDays 天的任务会被压缩后移出主集合
+	WorkflowTaskColdStorage CapacityTarget = "WorkflowTaskColdStorage"
 )
 
 var DefaultWorkflowTaskRetention = &CapacityStrategy{
@@ -34,10 +38,24 @@ var DefaultWorkflowTaskRetention = &CapacityStrategy{
 	},
 }
 
+// DefaultBuildCacheRetention doesn't evict any build cache until the policy is configured
+var DefaultBuildCacheRetention = &CapacityStrategy{
+	Target:    BuildCacheRetention,
+	Retention: &RetentionConfig{},
+}
+
+// DefaultWorkflowTaskColdStorage doesn't move any workflow task into the cold storage until the policy is configured
+var DefaultWorkflowTaskColdStorage = &CapacityStrategy{
+	Target:    WorkflowTaskColdStorage,
+	Retention: &RetentionConfig{},
+}
+
 // RetentionConfig 资源留存相关的配置
 type RetentionConfig struct {
 	MaxDays  int `bson:"max_days"      json:"max_days"`  // 最多几天
 	MaxItems int `bson:"max_items"     json:"max_items"` // 最多几条
+	// MaxSize 最多占用多少空间，单位 MB，目前仅用于构建缓存
+	MaxSize int64 `bson:"max_size"      json:"max_size"`
 }
 
 // CapacityStrategy 系统配额策略
@@ -18,6 +18,7 @@ package template
 
 import (
 	"strings"
+	"time"
 
 	commontypes "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/types"
 	"github.com/koderover/zadig/v2/pkg/setting"
@@ -77,10 +78,27 @@ type Product struct {
 	GlobalVariables            []*commontypes.ServiceVariableKV `bson:"global_variables,omitempty"          json:"global_variables,omitempty"`                       // New since 1.18.0 used to store global variables for test services
 	ProductionGlobalVariables  []*commontypes.ServiceVariableKV `bson:"production_global_variables,omitempty"          json:"production_global_variables,omitempty"` // New since 1.18.0 used to store global variables for production services
 	Public                     bool                             `bson:"public,omitempty"                    json:"public"`
+	// Sandbox is set for self-service sandbox projects, nil for regular projects
+	Sandbox *SandboxPolicy `bson:"sandbox,omitempty" json:"sandbox,omitempty"`
+	// EnvValidationHook is updated by its own api only, it's not changed when the project is updated
+	EnvValidationHook *EnvValidationHook `bson:"env_validation_hook,omitempty" json:"env_validation_hook,omitempty"`
 	// created after 1.8.0, used to create default project admins
 	Admins []string `bson:"-" json:"admins"`
 }
 
+// SandboxPolicy describes the quotas and lifetime of a self-service sandbox project
+type SandboxPolicy struct {
+	Owner          string `bson:"owner"            json:"owner"`
+	OwnerName      string `bson:"owner_name"       json:"owner_name"`
+	ExpireTime     int64  `bson:"expire_time"      json:"expire_time"`
+	MaxEnvs        int    `bson:"max_envs"         json:"max_envs"`
+	MaxTaskMinutes int64  `bson:"max_task_minutes" json:"max_task_minutes"`
+}
+
+func (s *SandboxPolicy) IsExpired() bool {
+	return s.ExpireTime > 0 && time.Now().Unix() >= s.ExpireTime
+}
+
 type ServiceInfo struct {
 	Name  string `bson:"name"  json:"name"`
 	Owner string `bson:"owner" json:"owner"`
@@ -216,6 +234,19 @@ type DeliveryVersionHook struct {
 	Path     string `bson:"path"       json:"path"`
 }
 
+// EnvValidationHook receives the proposed default values or global variables before they are applied to an env,
+// the update is rejected if the hook does not allow it.
+type EnvValidationHook struct {
+	Enable bool   `bson:"enable" json:"enable"`
+	URL    string `bson:"url"    json:"url"`
+	// Token is sent in the X-Zadig-Token header
+	Token string `bson:"token"  json:"token"`
+	// TimeoutSeconds defaults to 10 seconds
+	TimeoutSeconds int `bson:"timeout_seconds" json:"timeout_seconds"`
+	// FailOpen lets the update go on when the hook can not be reached or responds with an error
+	FailOpen bool `bson:"fail_open" json:"fail_open"`
+}
+
 type AutoDeployPolicy struct {
 	Enable bool `bson:"enable" json:"enable"`
 }
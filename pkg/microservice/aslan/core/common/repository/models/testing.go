@@ -70,6 +70,18 @@ type Testing struct {
 	// New since V1.10.0. Only to tell the webpage should the advanced settings be displayed
 	AdvancedSettingsModified bool      `bson:"advanced_setting_modified" json:"advanced_setting_modified"`
 	Outputs                  []*Output `bson:"outputs"                   json:"outputs"`
+	// HTMLReports are uploaded to the object storage and can be viewed inline, unlike TestReportPath they can be
+	// directories with assets like allure or coverage reports
+	HTMLReports []*HTMLReport `bson:"html_reports,omitempty" json:"html_reports"`
+}
+
+// HTMLReport is a html report generated by the job
+type HTMLReport struct {
+	Name string `bson:"name"  json:"name"`
+	// Path is the html file or the report directory relative to the workspace
+	Path string `bson:"path"  json:"path"`
+	// Index is the entry page of the report directory, index.html is used if not set
+	Index string `bson:"index" json:"index"`
 }
 
 type TestingHookCtrl struct {
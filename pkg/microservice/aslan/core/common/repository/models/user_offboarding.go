@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// UserOffboardingItem is something a departing user owns, manages or has to approve.
+type UserOffboardingItem struct {
+	Type        config.OffboardingItemType `bson:"type"                 json:"type"`
+	ProjectName string                     `bson:"project_name"         json:"project_name"`
+	Name        string                     `bson:"name"                 json:"name"`
+	Production  bool                       `bson:"production,omitempty" json:"production,omitempty"`
+	// ID is the id of the release plan, or the task id of the workflow approval
+	ID string `bson:"id,omitempty"         json:"id,omitempty"`
+}
+
+// UserOffboardingRecord is the audit record of transferring the resources of a departing user to another user.
+type UserOffboardingRecord struct {
+	ID           primitive.ObjectID     `bson:"_id,omitempty"  json:"id"`
+	FromUID      string                 `bson:"from_uid"       json:"from_uid"`
+	FromUserName string                 `bson:"from_user_name" json:"from_user_name"`
+	ToUID        string                 `bson:"to_uid"         json:"to_uid"`
+	ToUserName   string                 `bson:"to_user_name"   json:"to_user_name"`
+	Operator     string                 `bson:"operator"       json:"operator"`
+	Items        []*UserOffboardingItem `bson:"items"          json:"items"`
+	CreateTime   int64                  `bson:"create_time"    json:"create_time"`
+}
+
+func (UserOffboardingRecord) TableName() string {
+	return "user_offboarding_record"
+}
@@ -41,6 +41,7 @@ type WorkflowTask struct {
 	ClusterIDMap        map[string]bool               `bson:"cluster_id_map"            json:"cluster_id_map"`
 	Status              config.Status                 `bson:"status"                    json:"status,omitempty"`
 	Remark              string                        `bson:"remark"                    json:"remark"`
+	TaskName            string                        `bson:"task_name,omitempty"       json:"task_name,omitempty"`
 	TaskCreator         string                        `bson:"task_creator"              json:"task_creator,omitempty"`
 	TaskCreatorID       string                        `bson:"task_creator_id"           json:"task_creator_id,omitempty"`
 	TaskCreatorPhone    string                        `bson:"task_creator_phone"        json:"task_creator_phone"`
@@ -59,6 +60,12 @@ type WorkflowTask struct {
 	IsDebug             bool                          `bson:"is_debug"                  json:"is_debug"`
 	ShareStorages       []*ShareStorage               `bson:"share_storages"            json:"share_storages"`
 	Type                config.CustomWorkflowTaskType `bson:"type"                      json:"type"`
+	// RetryJobKeys are the keys of the jobs being retried when only some jobs of the task are retried
+	RetryJobKeys []string `bson:"retry_job_keys,omitempty" json:"retry_job_keys,omitempty"`
+	// RunAs is the project service account the task runs as when it's created by an automated trigger
+	RunAs string `bson:"run_as,omitempty" json:"run_as,omitempty"`
+	// Risk is the change risk of the task computed when it's created
+	Risk *TaskRisk `bson:"risk,omitempty" json:"risk,omitempty"`
 }
 
 func (WorkflowTask) TableName() string {
@@ -79,6 +86,7 @@ type StageTask struct {
 	ManualExec *ManualExec   `bson:"manual_exec"     json:"manual_exec,omitempty"`
 	Jobs       []*JobTask    `bson:"jobs"            json:"jobs,omitempty"`
 	Error      string        `bson:"error"           json:"error"`
+	PauseAfter *StagePause   `bson:"pause_after,omitempty" json:"pause_after,omitempty"`
 }
 
 type JobTask struct {
@@ -110,6 +118,11 @@ type JobTask struct {
 	ErrorHandlerUserName string `bson:"error_handler_username"  yaml:"error_handler_username" json:"error_handler_username"`
 
 	RetryCount int `bson:"retry_count" json:"retry_count" yaml:"retry_count"`
+	// RetryPolicy and JobTimeout are copied from the job of the workflow.
+	RetryPolicy *JobRetryPolicy `bson:"retry_policy,omitempty" json:"retry_policy,omitempty" yaml:"retry_policy,omitempty"`
+	JobTimeout  int64           `bson:"job_timeout,omitempty"  json:"job_timeout,omitempty"  yaml:"job_timeout,omitempty"`
+
+	NotifyCtls []*NotifyCtl `bson:"notify_ctls,omitempty" json:"notify_ctls,omitempty" yaml:"notify_ctls,omitempty"`
 }
 
 type TaskJobInfo struct {
@@ -128,6 +141,7 @@ type WorkflowTaskPreview struct {
 	WorkflowName        string          `bson:"workflow_name"         json:"workflow_name"`
 	WorkflowDisplayName string          `bson:"workflow_display_name" json:"workflow_display_name"`
 	Remark              string          `bson:"remark"                json:"remark"`
+	TaskName            string          `bson:"task_name"             json:"task_name,omitempty"`
 	Status              config.Status   `bson:"status"                json:"status"`
 	CreateTime          int64           `bson:"create_time"           json:"create_time,omitempty"`
 	StartTime           int64           `bson:"start_time"            json:"start_time,omitempty"`
@@ -145,6 +159,7 @@ type StagePreview struct {
 	ManualExec *ManualExec   `bson:"manual_exec"   json:"manual_exec,omitempty"`
 	Jobs       []*JobPreview `bson:"jobs"          json:"jobs,omitempty"`
 	Error      string        `bson:"error"         json:"error"`
+	PauseAfter *StagePause   `bson:"pause_after,omitempty" json:"pause_after,omitempty"`
 }
 
 type JobPreview struct {
@@ -205,6 +220,19 @@ type JobTaskDeploySpec struct {
 	Timeout            int                             `bson:"timeout"                          json:"timeout"                             yaml:"timeout"`
 	ReplaceResources   []Resource                      `bson:"replace_resources"                json:"replace_resources"                   yaml:"replace_resources"`
 	RelatedPodLabels   []map[string]string             `bson:"-"                                json:"-"                                   yaml:"-"`
+	// BakeTime is the minutes to verify the services after they are deployed
+	BakeTime int64 `bson:"bake_time"                        json:"bake_time"                           yaml:"bake_time"`
+	// PreviousRevision is the env service revision before the deployment, the service is rolled back to it if the verification fails
+	PreviousRevision   int64                      `bson:"previous_revision"                json:"previous_revision"                   yaml:"previous_revision"`
+	RolledBack         bool                       `bson:"rolled_back"                      json:"rolled_back"                         yaml:"rolled_back"`
+	StatefulSetRollout *StatefulSetRolloutSetting `bson:"statefulset_rollout,omitempty"    json:"statefulset_rollout,omitempty"       yaml:"statefulset_rollout,omitempty"`
+	GitOpsExport       *GitOpsExportConfig        `bson:"gitops_export,omitempty"          json:"gitops_export,omitempty"             yaml:"gitops_export,omitempty"`
+	GitOpsExportResult *GitOpsExportResult        `bson:"gitops_export_result,omitempty"   json:"gitops_export_result,omitempty"      yaml:"gitops_export_result,omitempty"`
+	// DeployWindowApproval is set when the service is deployed out of its deploy window
+	DeployWindowApproval *DeployWindowApproval `bson:"deploy_window_approval,omitempty" json:"deploy_window_approval,omitempty" yaml:"deploy_window_approval,omitempty"`
+	// ContractCheckResult is set once the service is checked against the contract registry of ContractCheck
+	ContractCheck       *ContractCheckConfig `bson:"contract_check,omitempty"        json:"contract_check,omitempty"        yaml:"contract_check,omitempty"`
+	ContractCheckResult *ContractCheckResult `bson:"contract_check_result,omitempty" json:"contract_check_result,omitempty" yaml:"contract_check_result,omitempty"`
 	// for compatibility
 	ServiceModule string `bson:"service_module"                   json:"service_module"                      yaml:"-"`
 	Image         string `bson:"image"                            json:"image"                               yaml:"-"`
@@ -244,6 +272,43 @@ type JobTaskHelmDeploySpec struct {
 	ReleaseName        string                   `bson:"release_name"                     json:"release_name"                        yaml:"release_name"`
 	Timeout            int                      `bson:"timeout"                          json:"timeout"                             yaml:"timeout"`
 	ReplaceResources   []Resource               `bson:"replace_resources"                json:"replace_resources"                   yaml:"replace_resources"`
+	GitOpsExport       *GitOpsExportConfig      `bson:"gitops_export,omitempty"          json:"gitops_export,omitempty"             yaml:"gitops_export,omitempty"`
+	GitOpsExportResult *GitOpsExportResult      `bson:"gitops_export_result,omitempty"   json:"gitops_export_result,omitempty"      yaml:"gitops_export_result,omitempty"`
+	// DeployWindowApproval is set when the service is deployed out of its deploy window
+	DeployWindowApproval *DeployWindowApproval `bson:"deploy_window_approval,omitempty" json:"deploy_window_approval,omitempty" yaml:"deploy_window_approval,omitempty"`
+	// ContractCheckResult is set once the service is checked against the contract registry of ContractCheck
+	ContractCheck       *ContractCheckConfig `bson:"contract_check,omitempty"        json:"contract_check,omitempty"        yaml:"contract_check,omitempty"`
+	ContractCheckResult *ContractCheckResult `bson:"contract_check_result,omitempty" json:"contract_check_result,omitempty" yaml:"contract_check_result,omitempty"`
+}
+
+// DeployWindowApproval is the approval of the service owner required by an out-of-window deployment
+type DeployWindowApproval struct {
+	Reason         string          `bson:"reason"          json:"reason"          yaml:"reason"`
+	Timeout        int64           `bson:"timeout"         json:"timeout"         yaml:"timeout"`
+	NativeApproval *NativeApproval `bson:"native_approval" json:"native_approval" yaml:"native_approval"`
+}
+
+// ContractCheckResult is the compatibility of the service to be deployed with its consumers in the env
+type ContractCheckResult struct {
+	Compatible        bool                       `bson:"compatible"        json:"compatible"        yaml:"compatible"`
+	Incompatibilities []*ContractIncompatibility `bson:"incompatibilities" json:"incompatibilities" yaml:"incompatibilities"`
+	// Error is set when the registry can not be reached and the check is skipped
+	Error     string `bson:"error,omitempty" json:"error,omitempty" yaml:"error,omitempty"`
+	CheckTime int64  `bson:"check_time"      json:"check_time"      yaml:"check_time"`
+}
+
+type ContractIncompatibility struct {
+	Consumer string `bson:"consumer" json:"consumer" yaml:"consumer"`
+	Contract string `bson:"contract" json:"contract" yaml:"contract"`
+	Message  string `bson:"message"  json:"message"  yaml:"message"`
+}
+
+// GitOpsExportResult is where the rendered manifests of the service are committed to
+type GitOpsExportResult struct {
+	Branch   string `bson:"branch"    json:"branch"    yaml:"branch"`
+	FilePath string `bson:"file_path" json:"file_path" yaml:"file_path"`
+	CommitID string `bson:"commit_id" json:"commit_id" yaml:"commit_id"`
+	PRURL    string `bson:"pr_url"    json:"pr_url"    yaml:"pr_url"`
 }
 
 type JobTaskHelmChartDeploySpec struct {
@@ -269,6 +334,19 @@ type JobTaskPluginSpec struct {
 	Plugin     *PluginTemplate `bson:"plugin"              json:"plugin"            yaml:"plugin"`
 }
 
+type JobTaskImageScanSpec struct {
+	Properties    JobProperties         `bson:"properties"          json:"properties"        yaml:"properties"`
+	ServiceName   string                `bson:"service_name"        json:"service_name"      yaml:"service_name"`
+	ServiceModule string                `bson:"service_module"      json:"service_module"    yaml:"service_module"`
+	Image         string                `bson:"image"               json:"image"             yaml:"image"`
+	Scanner       config.ImageScanner   `bson:"scanner"             json:"scanner"           yaml:"scanner"`
+	ScannerImage  string                `bson:"scanner_image"       json:"scanner_image"     yaml:"scanner_image"`
+	IgnoreUnfixed bool                  `bson:"ignore_unfixed"      json:"ignore_unfixed"    yaml:"ignore_unfixed"`
+	Thresholds    []*ImageScanThreshold `bson:"thresholds"          json:"thresholds"        yaml:"thresholds"`
+	// Vulnerabilities is the count of vulnerabilities by severity, it's set after the scan finished.
+	Vulnerabilities map[string]int `bson:"vulnerabilities"     json:"vulnerabilities"   yaml:"vulnerabilities"`
+}
+
 type JobTaskBlueGreenDeploySpec struct {
 	ClusterID        string `bson:"cluster_id"             json:"cluster_id"            yaml:"cluster_id"`
 	Namespace        string `bson:"namespace"              json:"namespace"             yaml:"namespace"`
@@ -569,6 +647,110 @@ type JobTaskGuanceyunCheckSpec struct {
 	Monitors  []*GuanceyunMonitor `bson:"monitors" json:"monitors" yaml:"monitors"`
 }
 
+type JobTaskDragonflyPreheatSpec struct {
+	ClusterID   string                   `bson:"cluster_id" json:"cluster_id" yaml:"cluster_id"`
+	ClusterName string                   `bson:"cluster_name" json:"cluster_name" yaml:"cluster_name"`
+	Scope       string                   `bson:"scope" json:"scope" yaml:"scope"`
+	Timeout     int64                    `bson:"timeout" json:"timeout" yaml:"timeout"`
+	Images      []*DragonflyPreheatImage `bson:"images" json:"images" yaml:"images"`
+}
+
+type DragonflyPreheatImage struct {
+	Image string `bson:"image" json:"image" yaml:"image"`
+	// JobID is the id of the preheat job in dragonfly manager
+	JobID  int64         `bson:"job_id" json:"job_id" yaml:"job_id"`
+	Status config.Status `bson:"status" json:"status" yaml:"status"`
+	Error  string        `bson:"error" json:"error" yaml:"error"`
+}
+
+type JobTaskArgoRolloutSpec struct {
+	ClusterID   string                   `bson:"cluster_id"   json:"cluster_id"   yaml:"cluster_id"`
+	ClusterName string                   `bson:"cluster_name" json:"cluster_name" yaml:"cluster_name"`
+	Namespace   string                   `bson:"namespace"    json:"namespace"    yaml:"namespace"`
+	Action      config.ArgoRolloutAction `bson:"action"       json:"action"       yaml:"action"`
+	Full        bool                     `bson:"full"         json:"full"         yaml:"full"`
+	Timeout     int64                    `bson:"timeout"      json:"timeout"      yaml:"timeout"`
+	Rollouts    []*ArgoRolloutTaskTarget `bson:"rollouts"     json:"rollouts"     yaml:"rollouts"`
+}
+
+type JobTaskArgoCDSyncSpec struct {
+	ArgoCDID           string                  `bson:"argocd_id"            json:"argocd_id"            yaml:"argocd_id"`
+	ArgoCDName         string                  `bson:"argocd_name"          json:"argocd_name"          yaml:"argocd_name"`
+	Prune              bool                    `bson:"prune"                json:"prune"                yaml:"prune"`
+	Force              bool                    `bson:"force"                json:"force"                yaml:"force"`
+	SyncOptions        []string                `bson:"sync_options"         json:"sync_options"         yaml:"sync_options"`
+	RollbackOnDegraded bool                    `bson:"rollback_on_degraded" json:"rollback_on_degraded" yaml:"rollback_on_degraded"`
+	Timeout            int64                   `bson:"timeout"              json:"timeout"              yaml:"timeout"`
+	Applications       []*ArgoCDSyncTaskTarget `bson:"applications"         json:"applications"         yaml:"applications"`
+}
+
+type ArgoCDSyncTaskTarget struct {
+	ApplicationName string        `bson:"application_name" json:"application_name" yaml:"application_name"`
+	Revision        string        `bson:"revision"         json:"revision"         yaml:"revision"`
+	Status          config.Status `bson:"status"           json:"status"           yaml:"status"`
+	// SyncStatus, HealthStatus and Message are the latest status reported by argo cd
+	SyncStatus   string `bson:"sync_status"   json:"sync_status"   yaml:"sync_status"`
+	HealthStatus string `bson:"health_status" json:"health_status" yaml:"health_status"`
+	Message      string `bson:"message"       json:"message"       yaml:"message"`
+	// PrevHistoryID is the deployment before the sync which the application is rolled back to when it becomes degraded
+	PrevHistoryID int64  `bson:"prev_history_id" json:"prev_history_id" yaml:"prev_history_id"`
+	RolledBack    bool   `bson:"rolled_back"     json:"rolled_back"     yaml:"rolled_back"`
+	Error         string `bson:"error"           json:"error"           yaml:"error"`
+}
+
+type JobTaskFluxImageUpdateSpec struct {
+	CodehostID    int                      `bson:"codehost_id"    json:"codehost_id"    yaml:"codehost_id"`
+	RepoOwner     string                   `bson:"repo_owner"     json:"repo_owner"     yaml:"repo_owner"`
+	RepoNamespace string                   `bson:"repo_namespace" json:"repo_namespace" yaml:"repo_namespace"`
+	RepoName      string                   `bson:"repo_name"      json:"repo_name"      yaml:"repo_name"`
+	Branch        string                   `bson:"branch"         json:"branch"         yaml:"branch"`
+	CreatePR      bool                     `bson:"create_pr"      json:"create_pr"      yaml:"create_pr"`
+	Env           string                   `bson:"env"            json:"env"            yaml:"env"`
+	Targets       []*FluxImageUpdateTarget `bson:"targets"        json:"targets"        yaml:"targets"`
+	// CommitBranch, CommitID and PRURL are set after the change is committed, they are empty if nothing is changed
+	CommitBranch string `bson:"commit_branch" json:"commit_branch" yaml:"commit_branch"`
+	CommitID     string `bson:"commit_id"     json:"commit_id"     yaml:"commit_id"`
+	PRURL        string `bson:"pr_url"        json:"pr_url"        yaml:"pr_url"`
+}
+
+func (s *JobTaskFluxImageUpdateSpec) GetRepoNamespace() string {
+	if s.RepoNamespace != "" {
+		return s.RepoNamespace
+	}
+	return s.RepoOwner
+}
+
+type ArgoRolloutTaskTarget struct {
+	RolloutName string                  `bson:"rollout_name" json:"rollout_name" yaml:"rollout_name"`
+	Containers  []*ArgoRolloutContainer `bson:"containers"   json:"containers"   yaml:"containers"`
+	Status      config.Status           `bson:"status"       json:"status"       yaml:"status"`
+	// Phase and Message are the latest status reported by argo rollouts
+	Phase            string `bson:"phase"              json:"phase"              yaml:"phase"`
+	Message          string `bson:"message"            json:"message"            yaml:"message"`
+	CurrentStepIndex int64  `bson:"current_step_index" json:"current_step_index" yaml:"current_step_index"`
+	StepCount        int64  `bson:"step_count"         json:"step_count"         yaml:"step_count"`
+	Error            string `bson:"error"              json:"error"              yaml:"error"`
+}
+
+type JobTaskZadigRollbackSpec struct {
+	Env          string                   `bson:"env"           json:"env"           yaml:"env"`
+	Namespace    string                   `bson:"namespace"     json:"namespace"     yaml:"namespace"`
+	Production   bool                     `bson:"production"    json:"production"    yaml:"production"`
+	Source       config.RollbackSource    `bson:"source"        json:"source"        yaml:"source"`
+	WorkflowName string                   `bson:"workflow_name" json:"workflow_name" yaml:"workflow_name"`
+	TaskID       int64                    `bson:"task_id"       json:"task_id"       yaml:"task_id"`
+	SnapshotID   string                   `bson:"snapshot_id"   json:"snapshot_id"   yaml:"snapshot_id"`
+	Services     []*RollbackServiceTarget `bson:"services"      json:"services"      yaml:"services"`
+}
+type RollbackServiceTarget struct {
+	ServiceName string `bson:"service_name" json:"service_name" yaml:"service_name"`
+	// Revision is the env service revision rolled back to, it is 0 when the source is env_snapshot
+	Revision int64         `bson:"revision" json:"revision" yaml:"revision"`
+	Images   []string      `bson:"images"   json:"images"   yaml:"images"`
+	Status   config.Status `bson:"status"   json:"status"   yaml:"status"`
+	Error    string        `bson:"error"    json:"error"    yaml:"error"`
+}
+
 type JobTaskMseGrayReleaseSpec struct {
 	Production         bool                  `bson:"production" json:"production" yaml:"production"`
 	GrayTag            string                `bson:"gray_tag" json:"gray_tag" yaml:"gray_tag"`
@@ -665,4 +847,18 @@ type WorkflowTaskCtx struct {
 	GlobalContextEach           func(f func(k, v string) bool)
 	ClusterIDAdd                func(clusterID string)
 	StartTime                   time.Time
+	RetryJobKeys                []string
+}
+
+// ShouldRunJob checks whether the job should run, only the retried jobs run when some jobs of the task are retried.
+func (c *WorkflowTaskCtx) ShouldRunJob(key string) bool {
+	if len(c.RetryJobKeys) == 0 {
+		return true
+	}
+	for _, retryKey := range c.RetryJobKeys {
+		if retryKey == key {
+			return true
+		}
+	}
+	return false
 }
@@ -94,6 +94,8 @@ type MainHookRepo struct {
 	Label         string                 `bson:"label"                     json:"label"`
 	Revision      string                 `bson:"revision"                  json:"revision"`
 	IsRegular     bool                   `bson:"is_regular"                json:"is_regular"`
+	// ChangedFiles is the files changed by the event which matches the hook, it's only set when handling the event
+	ChangedFiles []string `bson:"-" json:"-"`
 }
 
 func (m *MainHookRepo) GetRepoNamespace() string {
@@ -133,7 +135,9 @@ type Schedule struct {
 	Cron            string              `bson:"cron"                          json:"cron"`
 	IsModified      bool                `bson:"-"                             json:"-"`
 	// 自由编排工作流的开关是放在schedule里面的
-	Enabled bool `bson:"enabled"                       json:"enabled"`
+	Enabled  bool   `bson:"enabled"                       json:"enabled"`
+	Timezone string `bson:"timezone,omitempty"            json:"timezone,omitempty"`
+	Jitter   int64  `bson:"jitter,omitempty"              json:"jitter,omitempty"`
 }
 
 // TaskArgs 单服务工作流任务参数
@@ -326,18 +330,20 @@ type ProductDistribute struct {
 }
 
 type NotifyCtl struct {
-	Enabled         bool                      `bson:"enabled"                       yaml:"enabled"                       json:"enabled"`
-	WebHookType     setting.NotifyWebHookType `bson:"webhook_type"                  yaml:"webhook_type"                  json:"webhook_type"`
-	WeChatWebHook   string                    `bson:"weChat_webHook,omitempty"      yaml:"weChat_webHook,omitempty"      json:"weChat_webHook,omitempty"`
-	DingDingWebHook string                    `bson:"dingding_webhook,omitempty"    yaml:"dingding_webhook,omitempty"    json:"dingding_webhook,omitempty"`
-	FeiShuWebHook   string                    `bson:"feishu_webhook,omitempty"      yaml:"feishu_webhook,omitempty"      json:"feishu_webhook,omitempty"`
-	MailUsers       []*User                   `bson:"mail_users,omitempty"          yaml:"mail_users,omitempty"          json:"mail_users,omitempty"`
-	WebHookNotify   WebhookNotify             `bson:"webhook_notify,omitempty"      yaml:"webhook_notify,omitempty"      json:"webhook_notify,omitempty"`
-	AtMobiles       []string                  `bson:"at_mobiles,omitempty"          yaml:"at_mobiles,omitempty"          json:"at_mobiles,omitempty"`
-	WechatUserIDs   []string                  `bson:"wechat_user_ids,omitempty"     yaml:"wechat_user_ids,omitempty"     json:"wechat_user_ids,omitempty"`
-	LarkUserIDs     []string                  `bson:"lark_user_ids,omitempty"       yaml:"lark_user_ids,omitempty"       json:"lark_user_ids,omitempty"`
-	IsAtAll         bool                      `bson:"is_at_all,omitempty"           yaml:"is_at_all,omitempty"           json:"is_at_all,omitempty"`
-	NotifyTypes     []string                  `bson:"notify_type"                   yaml:"notify_type"                   json:"notify_type"`
+	Enabled          bool                      `bson:"enabled"                       yaml:"enabled"                       json:"enabled"`
+	WebHookType      setting.NotifyWebHookType `bson:"webhook_type"                  yaml:"webhook_type"                  json:"webhook_type"`
+	WeChatWebHook    string                    `bson:"weChat_webHook,omitempty"      yaml:"weChat_webHook,omitempty"      json:"weChat_webHook,omitempty"`
+	DingDingWebHook  string                    `bson:"dingding_webhook,omitempty"    yaml:"dingding_webhook,omitempty"    json:"dingding_webhook,omitempty"`
+	FeiShuWebHook    string                    `bson:"feishu_webhook,omitempty"      yaml:"feishu_webhook,omitempty"      json:"feishu_webhook,omitempty"`
+	MailUsers        []*User                   `bson:"mail_users,omitempty"          yaml:"mail_users,omitempty"          json:"mail_users,omitempty"`
+	WebHookNotify    WebhookNotify             `bson:"webhook_notify,omitempty"      yaml:"webhook_notify,omitempty"      json:"webhook_notify,omitempty"`
+	TelegramBotToken string                    `bson:"telegram_bot_token,omitempty"  yaml:"telegram_bot_token,omitempty"  json:"telegram_bot_token,omitempty"`
+	TelegramChatID   string                    `bson:"telegram_chat_id,omitempty"    yaml:"telegram_chat_id,omitempty"    json:"telegram_chat_id,omitempty"`
+	AtMobiles        []string                  `bson:"at_mobiles,omitempty"          yaml:"at_mobiles,omitempty"          json:"at_mobiles,omitempty"`
+	WechatUserIDs    []string                  `bson:"wechat_user_ids,omitempty"     yaml:"wechat_user_ids,omitempty"     json:"wechat_user_ids,omitempty"`
+	LarkUserIDs      []string                  `bson:"lark_user_ids,omitempty"       yaml:"lark_user_ids,omitempty"       json:"lark_user_ids,omitempty"`
+	IsAtAll          bool                      `bson:"is_at_all,omitempty"           yaml:"is_at_all,omitempty"           json:"is_at_all,omitempty"`
+	NotifyTypes      []string                  `bson:"notify_type"                   yaml:"notify_type"                   json:"notify_type"`
 }
 
 type WebhookNotify struct {
@@ -385,6 +391,8 @@ type HookPayload struct {
 	DeliveryID     string `bson:"delivery_id"      json:"delivery_id,omitempty"`
 	CodehostID     int    `bson:"codehost_id"      json:"codehost_id"`
 	EventType      string `bson:"event_type"       json:"event_type"`
+	// ChangedFiles is the number of files changed by the event, 0 if unknown
+	ChangedFiles int `bson:"changed_files,omitempty" json:"changed_files,omitempty"`
 }
 
 type TargetArgs struct {
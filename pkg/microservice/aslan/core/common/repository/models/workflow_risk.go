@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+type TaskRiskLevel string
+
+const (
+	TaskRiskLevelLow    TaskRiskLevel = "low"
+	TaskRiskLevelMedium TaskRiskLevel = "medium"
+	TaskRiskLevelHigh   TaskRiskLevel = "high"
+)
+
+// TaskRisk is the change risk of a workflow task, the score ranges from 0 to 100
+type TaskRisk struct {
+	Score   int           `bson:"score"   json:"score"`
+	Level   TaskRiskLevel `bson:"level"   json:"level"`
+	Factors []*RiskFactor `bson:"factors" json:"factors"`
+	// Escalated is true if the task is routed to the high risk approval of the workflow
+	Escalated bool `bson:"escalated" json:"escalated"`
+}
+
+type RiskFactor struct {
+	Name     string `bson:"name"      json:"name"`
+	Score    int    `bson:"score"     json:"score"`
+	MaxScore int    `bson:"max_score" json:"max_score"`
+	Detail   string `bson:"detail"    json:"detail"`
+}
+
+type WorkflowRiskPolicy struct {
+	Enabled bool `bson:"enabled"            yaml:"enabled"            json:"enabled"`
+	// HighRiskScore is the score from which a task is high risk, 60 is used if it's not set
+	HighRiskScore int `bson:"high_risk_score"    yaml:"high_risk_score"    json:"high_risk_score"`
+	// HighRiskApproval is added as the first stage of the high risk tasks, it's usually stricter than the approvals in
+	// the workflow, e.g. more approvers or an approver from the security group is needed.
+	HighRiskApproval *NativeApproval `bson:"high_risk_approval" yaml:"high_risk_approval" json:"high_risk_approval"`
+	// WorkStartHour and WorkEndHour are the working hours in local time, tasks out of them are riskier. 9 to 19 is
+	// used if both are not set.
+	WorkStartHour int `bson:"work_start_hour"    yaml:"work_start_hour"    json:"work_start_hour"`
+	WorkEndHour   int `bson:"work_end_hour"      yaml:"work_end_hour"      json:"work_end_hour"`
+}
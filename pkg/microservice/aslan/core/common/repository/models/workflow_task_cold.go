@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// WorkflowTaskCold is a workflow task moved into the cold storage, the original document is kept gzip compressed
+// in Data and the id is the same as the one in the primary collection so that it can be rehydrated as is
+type WorkflowTaskCold struct {
+	ID           primitive.ObjectID `bson:"_id"               json:"id"`
+	WorkflowName string             `bson:"workflow_name"     json:"workflow_name"`
+	TaskID       int64              `bson:"task_id"           json:"task_id"`
+	ProjectName  string             `bson:"project_name"      json:"project_name"`
+	Status       config.Status      `bson:"status"            json:"status"`
+	TaskCreator  string             `bson:"task_creator"      json:"task_creator"`
+	CreateTime   int64              `bson:"create_time"       json:"create_time"`
+	ArchiveTime  int64              `bson:"archive_time"      json:"archive_time"`
+	RawSize      int64              `bson:"raw_size"          json:"raw_size"`
+	Data         []byte             `bson:"data"              json:"-"`
+}
+
+func (WorkflowTaskCold) TableName() string {
+	return "workflow_task_cold"
+}
+
+// Document decompresses the original document of the task
+func (t *WorkflowTaskCold) Document() (bson.Raw, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(t.Data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	doc, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := bson.Raw(doc).Validate(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
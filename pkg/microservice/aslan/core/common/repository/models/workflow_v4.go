@@ -63,12 +63,21 @@ type WorkflowV4 struct {
 	HookPayload     *HookPayload             `bson:"hook_payload"        yaml:"-"                   json:"hook_payload,omitempty"`
 	BaseName        string                   `bson:"base_name"           yaml:"-"                   json:"base_name"`
 	Remark          string                   `bson:"remark"              yaml:"-"                   json:"remark"`
+	TaskName        string                   `bson:"task_name"           yaml:"-"                   json:"task_name"`
 	ShareStorages   []*ShareStorage          `bson:"share_storages"      yaml:"share_storages"      json:"share_storages"`
 	Hash            string                   `bson:"hash"                yaml:"hash"                json:"hash"`
 	// ConcurrencyLimit is the max number of concurrent runs of this workflow
 	// -1 means no limit
 	ConcurrencyLimit int          `bson:"concurrency_limit"   yaml:"concurrency_limit"   json:"concurrency_limit"`
 	CustomField      *CustomField `bson:"custom_field"        yaml:"-"                   json:"custom_field"`
+	// Owner is set when the workflow is created, it's changed by the owner api only.
+	Owner *ResourceOwner `bson:"owner,omitempty"     yaml:"owner,omitempty"     json:"owner,omitempty"`
+	// RegistryHookCtls trigger the workflow when a new image tag is pushed to a registry
+	RegistryHookCtls []*RegistryHook `bson:"registry_hook_ctls"  yaml:"-"                   json:"registry_hook_ctls"`
+	// RunAs is the project service account that tasks created by crons and webhooks run as
+	RunAs string `bson:"run_as,omitempty"    yaml:"run_as,omitempty"    json:"run_as,omitempty"`
+	// RiskPolicy routes the tasks with a high change risk to an extra approval
+	RiskPolicy *WorkflowRiskPolicy `bson:"risk_policy,omitempty" yaml:"risk_policy,omitempty" json:"risk_policy,omitempty"`
 }
 
 func (w *WorkflowV4) UpdateHash() {
@@ -77,7 +86,7 @@ func (w *WorkflowV4) UpdateHash() {
 
 func (w *WorkflowV4) CalculateHash() [md5.Size]byte {
 	fieldList := make(map[string]interface{})
-	ignoringFieldList := []string{"CreatedBy", "CreateTime", "UpdatedBy", "UpdateTime", "Description", "Hash"}
+	ignoringFieldList := []string{"CreatedBy", "CreateTime", "UpdatedBy", "UpdateTime", "Description", "Hash", "Owner"}
 	ignoringFields := sets.NewString(ignoringFieldList...)
 
 	val := reflect.ValueOf(*w)
@@ -100,6 +109,20 @@ type WorkflowStage struct {
 	Approval   *Approval   `bson:"approval"           yaml:"approval"          json:"approval"`
 	ManualExec *ManualExec `bson:"manual_exec"        yaml:"manual_exec"       json:"manual_exec"`
 	Jobs       []*Job      `bson:"jobs"               yaml:"jobs"              json:"jobs"`
+	// PauseAfter halts the task after the stage passes until it's resumed manually
+	PauseAfter *StagePause `bson:"pause_after,omitempty" yaml:"pause_after,omitempty" json:"pause_after,omitempty"`
+}
+
+// StagePause is different from approvals, there is no approver list, anyone who can run the workflow can resume it.
+type StagePause struct {
+	Enabled bool `bson:"enabled"                  yaml:"enabled"                  json:"enabled"`
+	// Timeout is in minutes, the task times out if it's not resumed in time, 0 means the default 60 minutes
+	Timeout       int64  `bson:"timeout"                  yaml:"timeout"                  json:"timeout"`
+	Waiting       bool   `bson:"waiting,omitempty"        yaml:"waiting,omitempty"        json:"waiting,omitempty"`
+	Resumed       bool   `bson:"resumed,omitempty"        yaml:"resumed,omitempty"        json:"resumed,omitempty"`
+	ResumedByID   string `bson:"resumed_by_id,omitempty"   yaml:"resumed_by_id,omitempty"   json:"resumed_by_id,omitempty"`
+	ResumedByName string `bson:"resumed_by_name,omitempty" yaml:"resumed_by_name,omitempty" json:"resumed_by_name,omitempty"`
+	ResumeTime    int64  `bson:"resume_time,omitempty"     yaml:"resume_time,omitempty"     json:"resume_time,omitempty"`
 }
 
 type ManualExec struct {
@@ -132,6 +155,32 @@ type NativeApproval struct {
 	RejectOrApprove   config.ApproveOrReject `bson:"reject_or_approve"           yaml:"-"                          json:"reject_or_approve"`
 	// InstanceCode: native approval instance code, save for working after restart aslan
 	InstanceCode string `bson:"instance_code"               yaml:"instance_code"              json:"instance_code"`
+	// QuorumRules must all be met besides NeededApprovers to pass the approval, e.g. at least one approver from the security group
+	QuorumRules []*ApprovalQuorumRule `bson:"quorum_rules,omitempty" yaml:"quorum_rules,omitempty" json:"quorum_rules,omitempty"`
+	// Steps is an ordered approval chain, a step is open for approval only after all the previous steps are passed.
+	// ApproveUsers, NeededApprovers and QuorumRules above are ignored if steps are set.
+	Steps []*NativeApprovalStep `bson:"steps,omitempty" yaml:"steps,omitempty" json:"steps,omitempty"`
+	// CurrentStep is the index of the step waiting for approval, it equals len(Steps) after all the steps are passed
+	CurrentStep int `bson:"current_step" yaml:"-" json:"current_step"`
+}
+
+type NativeApprovalStep struct {
+	Name            string                 `bson:"name"                        yaml:"name"                       json:"name"`
+	ApproveUsers    []*User                `bson:"approve_users"               yaml:"approve_users"              json:"approve_users"`
+	NeededApprovers int                    `bson:"needed_approvers"            yaml:"needed_approvers"           json:"needed_approvers"`
+	QuorumRules     []*ApprovalQuorumRule  `bson:"quorum_rules,omitempty"      yaml:"quorum_rules,omitempty"     json:"quorum_rules,omitempty"`
+	RejectOrApprove config.ApproveOrReject `bson:"reject_or_approve"           yaml:"-"                          json:"reject_or_approve"`
+}
+
+// ApprovalQuorumRule requires at least MinApprovers approvals from the members of a user group
+type ApprovalQuorumRule struct {
+	GroupID      string `bson:"group_id"                    yaml:"group_id"                   json:"group_id"`
+	GroupName    string `bson:"group_name"                  yaml:"group_name"                 json:"group_name"`
+	MinApprovers int    `bson:"min_approvers"               yaml:"min_approvers"              json:"min_approvers"`
+	// MemberIDs are resolved from the group when the approval starts
+	MemberIDs []string `bson:"member_ids,omitempty"        yaml:"-"                          json:"member_ids,omitempty"`
+	// Approved is the number of the members who have approved so far
+	Approved int `bson:"approved"                    yaml:"-"                          json:"approved"`
 }
 
 type DingTalkApproval struct {
@@ -231,6 +280,8 @@ type User struct {
 	RejectOrApprove config.ApproveOrReject `bson:"reject_or_approve,omitempty" yaml:"-"                          json:"reject_or_approve,omitempty"`
 	Comment         string                 `bson:"comment,omitempty"           yaml:"-"                          json:"comment,omitempty"`
 	OperationTime   int64                  `bson:"operation_time,omitempty"    yaml:"-"                          json:"operation_time,omitempty"`
+	// DelegatedTo is set when the pending approval of the user is rerouted to the delegate of the user
+	DelegatedTo *ApprovalDelegate `bson:"delegated_to,omitempty" yaml:"-" json:"delegated_to,omitempty"`
 }
 
 type Job struct {
@@ -242,6 +293,12 @@ type Job struct {
 	RunPolicy      config.JobRunPolicy      `bson:"run_policy"           yaml:"run_policy"           json:"run_policy"`
 	ErrorPolicy    *JobErrorPolicy          `bson:"error_policy"         yaml:"error_policy"         json:"error_policy"`
 	ServiceModules []*WorkflowServiceModule `bson:"service_modules"                                  json:"service_modules"`
+	// NotifyCtls are sent when the job finishes, in addition to the workflow notifications, only build, deploy and scanning jobs support it.
+	NotifyCtls []*NotifyCtl `bson:"notify_ctls,omitempty" yaml:"notify_ctls,omitempty" json:"notify_ctls,omitempty"`
+	// RetryPolicy retries the failed job automatically before the error policy is applied.
+	RetryPolicy *JobRetryPolicy `bson:"retry_policy,omitempty" yaml:"retry_policy,omitempty" json:"retry_policy,omitempty"`
+	// Timeout is the maximum minutes of every run of the job, 0 means the job is only limited by its own spec.
+	Timeout int64 `bson:"timeout,omitempty" yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 type JobErrorPolicy struct {
@@ -250,6 +307,28 @@ type JobErrorPolicy struct {
 	ApprovalUsers []*User               `bson:"approval_users" yaml:"approval_users" json:"approval_users"`
 }
 
+type JobRetryPolicy struct {
+	// MaxAttempts is the maximum number of retries after the first run.
+	MaxAttempts int                    `bson:"max_attempts" yaml:"max_attempts" json:"max_attempts"`
+	Backoff     config.JobRetryBackoff `bson:"backoff"      yaml:"backoff"      json:"backoff"`
+	// Interval is the seconds to wait before the first retry, it is doubled for every retry with exponential backoff.
+	Interval int64 `bson:"interval" yaml:"interval" json:"interval"`
+	// RetryOn is the job statuses to retry on, failed and timeout are retried if it is empty.
+	RetryOn []config.Status `bson:"retry_on" yaml:"retry_on" json:"retry_on"`
+}
+
+func (p *JobRetryPolicy) ShouldRetry(status config.Status) bool {
+	if len(p.RetryOn) == 0 {
+		return status == config.StatusFailed || status == config.StatusTimeout
+	}
+	for _, s := range p.RetryOn {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
 type WorkflowServiceModule struct {
 	ServiceModule string              `bson:"service_module" json:"service_module"`
 	ServiceName   string              `bson:"service_name"   json:"service_name"`
@@ -291,6 +370,23 @@ type ZadigBuildJobSpec struct {
 	DockerRegistryID        string             `bson:"docker_registry_id"     yaml:"docker_registry_id"         json:"docker_registry_id"`
 	ServiceAndBuilds        []*ServiceAndBuild `bson:"service_and_builds"     yaml:"service_and_builds"         json:"service_and_builds"`
 	ServiceAndBuildsOptions []*ServiceAndBuild `bson:"-"                      yaml:"service_and_builds_options" json:"service_and_builds_options"`
+	Matrix                  *BuildMatrix       `bson:"matrix,omitempty"       yaml:"matrix,omitempty"           json:"matrix,omitempty"`
+	// SkipUnchangedServices skips the services whose source paths are not changed by the webhook event,
+	// it only works for the tasks triggered by webhook.
+	SkipUnchangedServices bool `bson:"skip_unchanged_services" yaml:"skip_unchanged_services" json:"skip_unchanged_services"`
+	// SkippedServiceAndBuilds are removed from ServiceAndBuilds since they are not changed, so the referring jobs ignore them.
+	SkippedServiceAndBuilds []*ServiceAndBuild `bson:"skipped_service_and_builds,omitempty" yaml:"-" json:"skipped_service_and_builds,omitempty"`
+}
+
+// BuildMatrix builds every service once for each combination of the axis values.
+type BuildMatrix struct {
+	Axes []*BuildMatrixAxis `bson:"axes" yaml:"axes" json:"axes"`
+}
+
+// BuildMatrixAxis is injected into the build as an env named Key, one job task is generated for each value.
+type BuildMatrixAxis struct {
+	Key    string   `bson:"key"    yaml:"key"    json:"key"`
+	Values []string `bson:"values" yaml:"values" json:"values"`
 }
 
 type ServiceAndBuild struct {
@@ -303,6 +399,9 @@ type ServiceAndBuild struct {
 	KeyVals          []*KeyVal           `bson:"key_vals"            yaml:"key_vals"             json:"key_vals"`
 	Repos            []*types.Repository `bson:"repos"               yaml:"repos"                json:"repos"`
 	ShareStorageInfo *ShareStorageInfo   `bson:"share_storage_info"  yaml:"share_storage_info"   json:"share_storage_info"`
+	// SourcePaths are the paths of the repos used by the service, in the same format as the match folders of the webhook.
+	// The service is always built if it's empty.
+	SourcePaths []string `bson:"source_paths,omitempty" yaml:"source_paths,omitempty" json:"source_paths,omitempty"`
 }
 
 type ZadigDeployJobSpec struct {
@@ -321,6 +420,79 @@ type ZadigDeployJobSpec struct {
 	Services      []*DeployServiceInfo `bson:"services"             yaml:"services"             json:"services"`
 	// TODO: Deprecated in 2.3.0, this field is now used for saving the default service module info for deployment.
 	ServiceAndImages []*ServiceAndImage `bson:"service_and_images" yaml:"service_and_images" json:"service_and_images"`
+	// BakeTime is the minutes to verify the services after they are deployed, the services are rolled back to the
+	// previous revision if the verification fails or the task is cancelled. 0 means no verification, only k8s yaml projects are supported.
+	BakeTime int64 `bson:"bake_time" yaml:"bake_time" json:"bake_time"`
+	// StatefulSetRollout controls how the statefulSets of the services are rolled out, nil keeps the settings in the yaml.
+	StatefulSetRollout *StatefulSetRolloutSetting `bson:"statefulset_rollout,omitempty" yaml:"statefulset_rollout,omitempty" json:"statefulset_rollout,omitempty"`
+	// GitOpsExport commits the rendered manifests to a git repository instead of applying them to the cluster, nil means applying.
+	GitOpsExport *GitOpsExportConfig `bson:"gitops_export,omitempty" yaml:"gitops_export,omitempty" json:"gitops_export,omitempty"`
+	// FanOutEnvs are the envs the services are deployed to along with Env at the same time, e.g. prod-us and prod-eu,
+	// they may be in different clusters. One job task is created for each service in each env, only the images are
+	// deployed to the fan-out envs.
+	FanOutEnvs []string `bson:"fan_out_envs,omitempty" yaml:"fan_out_envs,omitempty" json:"fan_out_envs,omitempty"`
+	// ContractCheck asks a contract registry whether the services are compatible with their consumers in the target env
+	// before they are deployed, nil means no check.
+	ContractCheck *ContractCheckConfig `bson:"contract_check,omitempty" yaml:"contract_check,omitempty" json:"contract_check,omitempty"`
+}
+
+// TargetEnvs returns Env followed by the fan-out envs without duplicates.
+func (s *ZadigDeployJobSpec) TargetEnvs() []string {
+	resp := make([]string, 0, len(s.FanOutEnvs)+1)
+	seen := make(map[string]bool)
+	for _, env := range append([]string{s.Env}, s.FanOutEnvs...) {
+		env = strings.ReplaceAll(env, setting.FixedValueMark, "")
+		if env == "" || seen[env] {
+			continue
+		}
+		seen[env] = true
+		resp = append(resp, env)
+	}
+	return resp
+}
+
+// GitOpsExportConfig is the git repository the rendered manifests are committed to, for Argo CD or Flux to sync.
+// The manifest of each service is saved as <Path>/<service name>.yaml on the branch. A pull request is created instead
+// of committing to the branch directly if CreatePR is set or the env is a production env.
+type GitOpsExportConfig struct {
+	CodehostID    int    `bson:"codehost_id"    yaml:"codehost_id"    json:"codehost_id"`
+	RepoOwner     string `bson:"repo_owner"     yaml:"repo_owner"     json:"repo_owner"`
+	RepoNamespace string `bson:"repo_namespace" yaml:"repo_namespace" json:"repo_namespace"`
+	RepoName      string `bson:"repo_name"      yaml:"repo_name"      json:"repo_name"`
+	Branch        string `bson:"branch"         yaml:"branch"         json:"branch"`
+	Path          string `bson:"path"           yaml:"path"           json:"path"`
+	CreatePR      bool   `bson:"create_pr"      yaml:"create_pr"      json:"create_pr"`
+}
+
+// ContractCheckConfig is the contract registry the deploy job checks the compatibility against. The job fails with the
+// incompatibility report if the registry finds the new version incompatible with any consumer deployed in the env.
+type ContractCheckConfig struct {
+	RegistryID string `bson:"registry_id" yaml:"registry_id" json:"registry_id"`
+	// FailOpen lets the deployment go on when the registry can not be reached
+	FailOpen bool `bson:"fail_open"   yaml:"fail_open"   json:"fail_open"`
+}
+
+func (c *GitOpsExportConfig) GetRepoNamespace() string {
+	if c.RepoNamespace != "" {
+		return c.RepoNamespace
+	}
+	return c.RepoOwner
+}
+
+type StatefulSetRolloutSetting struct {
+	// Partition only updates the pods whose ordinal is greater than or equal to it, nil keeps the current partition.
+	Partition *int32 `bson:"partition,omitempty"             yaml:"partition,omitempty"             json:"partition,omitempty"`
+	// PodManagementPolicy is OrderedReady or Parallel, the statefulSet is recreated without its pods to change it.
+	PodManagementPolicy string                         `bson:"pod_management_policy,omitempty" yaml:"pod_management_policy,omitempty" json:"pod_management_policy,omitempty"`
+	PVCRetentionPolicy  *StatefulSetPVCRetentionPolicy `bson:"pvc_retention_policy,omitempty"  yaml:"pvc_retention_policy,omitempty"  json:"pvc_retention_policy,omitempty"`
+}
+
+type StatefulSetPVCRetentionPolicy struct {
+	// WhenDeleted and WhenScaled are Retain or Delete
+	WhenDeleted string `bson:"when_deleted" yaml:"when_deleted" json:"when_deleted"`
+	WhenScaled  string `bson:"when_scaled"  yaml:"when_scaled"  json:"when_scaled"`
+	// ConfirmDelete must be set to use the Delete policy, which is never allowed in production environments.
+	ConfirmDelete bool `bson:"confirm_delete" yaml:"confirm_delete" json:"confirm_delete"`
 }
 
 type ServiceAndVMDeploy struct {
@@ -446,6 +618,11 @@ type ZadigDistributeImageJobSpec struct {
 	StrategyID               string `bson:"strategy_id"                    json:"strategy_id"                   yaml:"strategy_id"`
 	EnableTargetImageTagRule bool   `bson:"enable_target_image_tag_rule" json:"enable_target_image_tag_rule" yaml:"enable_target_image_tag_rule"`
 	TargetImageTagRule       string `bson:"target_image_tag_rule"        json:"target_image_tag_rule"        yaml:"target_image_tag_rule"`
+	// DistributeMethod is image_push or harbor_replication, image_push is used if it is empty.
+	// harbor_replication requires the source registry to be harbor and the target tags to be the same as the source tags.
+	DistributeMethod string `bson:"distribute_method,omitempty"  json:"distribute_method,omitempty"  yaml:"distribute_method,omitempty"`
+	// ReplicationRegistryName is the name of the registry endpoint of the target registry configured in the source harbor.
+	ReplicationRegistryName string `bson:"replication_registry_name,omitempty" json:"replication_registry_name,omitempty" yaml:"replication_registry_name,omitempty"`
 }
 
 type DistributeTarget struct {
@@ -505,6 +682,35 @@ type ZadigScanningJobSpec struct {
 	TargetServices []*ServiceTestTarget `bson:"target_services"   yaml:"target_services"   json:"target_services"`
 }
 
+type ZadigImageScanJobSpec struct {
+	// fromjob/runtime, `fromjob` scans the images built or distributed by the referred job, `runtime` scans the images input by user
+	Source        config.DeploySourceType `bson:"source"           yaml:"source"           json:"source"`
+	JobName       string                  `bson:"job_name"         yaml:"job_name"         json:"job_name"`
+	OriginJobName string                  `bson:"origin_job_name"  yaml:"origin_job_name"  json:"origin_job_name"`
+	Targets       []*ImageScanTarget      `bson:"targets"          yaml:"targets"          json:"targets"`
+	Scanner       config.ImageScanner     `bson:"scanner"          yaml:"scanner"          json:"scanner"`
+	// ScannerImage overrides the default image of the scanner, it's useful in the air-gapped environment.
+	ScannerImage  string `bson:"scanner_image"    yaml:"scanner_image"    json:"scanner_image"`
+	IgnoreUnfixed bool   `bson:"ignore_unfixed"   yaml:"ignore_unfixed"   json:"ignore_unfixed"`
+	// the task fails if the count of vulnerabilities of any severity exceeds its threshold
+	Thresholds []*ImageScanThreshold `bson:"thresholds"       yaml:"thresholds"       json:"thresholds"`
+	// unit is minute.
+	Timeout    int64  `bson:"timeout"          yaml:"timeout"          json:"timeout"`
+	ClusterID  string `bson:"cluster_id"       yaml:"cluster_id"       json:"cluster_id"`
+	StrategyID string `bson:"strategy_id"      yaml:"strategy_id"      json:"strategy_id"`
+}
+
+type ImageScanTarget struct {
+	ServiceName   string `bson:"service_name"        yaml:"service_name"     json:"service_name"`
+	ServiceModule string `bson:"service_module"      yaml:"service_module"   json:"service_module"`
+	Image         string `bson:"image"               yaml:"image"            json:"image"`
+}
+
+type ImageScanThreshold struct {
+	Severity string `bson:"severity"            yaml:"severity"         json:"severity"`
+	Max      int    `bson:"max"                 yaml:"max"              json:"max"`
+}
+
 type ServiceAndScannings struct {
 	ServiceName    string `bson:"service_name"        yaml:"service_name"     json:"service_name"`
 	ServiceModule  string `bson:"service_module"      yaml:"service_module"   json:"service_module"`
@@ -814,6 +1020,105 @@ type GrafanaAlert struct {
 	Url    string `bson:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
 }
 
+type DragonflyPreheatJobSpec struct {
+	ClusterID string `bson:"cluster_id" json:"cluster_id" yaml:"cluster_id"`
+	// Images to be preheated, variables like {{.job.build.svc.IMAGE}} are supported
+	Images []string `bson:"images" json:"images" yaml:"images"`
+	// Scope is the dragonfly preheat scope, single_seed_peer will be used if it is empty
+	Scope string `bson:"scope" json:"scope" yaml:"scope"`
+	// Timeout minute
+	Timeout int64 `bson:"timeout" json:"timeout" yaml:"timeout"`
+}
+
+type ArgoRolloutJobSpec struct {
+	ClusterID string                   `bson:"cluster_id" json:"cluster_id" yaml:"cluster_id"`
+	Namespace string                   `bson:"namespace"  json:"namespace"  yaml:"namespace"`
+	Action    config.ArgoRolloutAction `bson:"action"     json:"action"     yaml:"action"`
+	// Full skips all the remaining steps of the rollouts when the action is promote
+	Full bool `bson:"full" json:"full" yaml:"full"`
+	// Timeout minute
+	Timeout int64                `bson:"timeout" json:"timeout" yaml:"timeout"`
+	Targets []*ArgoRolloutTarget `bson:"targets" json:"targets" yaml:"targets"`
+}
+
+type ArgoCDSyncJobSpec struct {
+	ArgoCDID     string              `bson:"argocd_id"    json:"argocd_id"    yaml:"argocd_id"`
+	Applications []*ArgoCDSyncTarget `bson:"applications" json:"applications" yaml:"applications"`
+	// Prune deletes the resources which are no longer defined in git
+	Prune bool `bson:"prune" json:"prune" yaml:"prune"`
+	// Force replaces the resources which can not be patched
+	Force bool `bson:"force" json:"force" yaml:"force"`
+	// SyncOptions are the argo cd sync options like CreateNamespace=true and ServerSideApply=true
+	SyncOptions []string `bson:"sync_options" json:"sync_options" yaml:"sync_options"`
+	// RollbackOnDegraded rolls the application back to the deployment before the sync if it becomes degraded
+	RollbackOnDegraded bool `bson:"rollback_on_degraded" json:"rollback_on_degraded" yaml:"rollback_on_degraded"`
+	// Timeout minute
+	Timeout int64 `bson:"timeout" json:"timeout" yaml:"timeout"`
+}
+
+type ArgoCDSyncTarget struct {
+	ApplicationName string `bson:"application_name" json:"application_name" yaml:"application_name"`
+	// Revision to be synced, the target revision of the application will be used if it is empty.
+	// Variables like {{.workflow.params.tag}} are supported
+	Revision string `bson:"revision" json:"revision" yaml:"revision"`
+}
+
+type FluxImageUpdateJobSpec struct {
+	// CodehostID, RepoOwner, RepoNamespace, RepoName and Branch locate the flux source repository
+	CodehostID    int    `bson:"codehost_id"    json:"codehost_id"    yaml:"codehost_id"`
+	RepoOwner     string `bson:"repo_owner"     json:"repo_owner"     yaml:"repo_owner"`
+	RepoNamespace string `bson:"repo_namespace" json:"repo_namespace" yaml:"repo_namespace"`
+	RepoName      string `bson:"repo_name"      json:"repo_name"      yaml:"repo_name"`
+	Branch        string `bson:"branch"         json:"branch"         yaml:"branch"`
+	// CreatePR commits the change to a new branch and opens a pull request instead of pushing to the branch directly
+	CreatePR bool                     `bson:"create_pr" json:"create_pr" yaml:"create_pr"`
+	Targets  []*FluxImageUpdateTarget `bson:"targets"   json:"targets"   yaml:"targets"`
+	// Env is the env whose flux resources are reconciled right after the change is pushed, it's ignored when CreatePR is true
+	Env string `bson:"env" json:"env" yaml:"env"`
+}
+
+func (s *FluxImageUpdateJobSpec) GetRepoNamespace() string {
+	if s.RepoNamespace != "" {
+		return s.RepoNamespace
+	}
+	return s.RepoOwner
+}
+
+type FluxImageUpdateTarget struct {
+	Type     config.FluxImageUpdateType `bson:"type"      json:"type"      yaml:"type"`
+	FilePath string                     `bson:"file_path" json:"file_path" yaml:"file_path"`
+	// ImageName is the name of the image in the kustomization.yaml, used by the kustomize type
+	ImageName string `bson:"image_name" json:"image_name" yaml:"image_name"`
+	// ValuePath is the dot separated yaml path of the tag, used by the value type
+	ValuePath string `bson:"value_path" json:"value_path" yaml:"value_path"`
+	// Tag to be set, variables like {{.job.build.svc.IMAGETAG}} are supported
+	Tag string `bson:"tag" json:"tag" yaml:"tag"`
+}
+
+type ZadigRollbackJobSpec struct {
+	Env        string                `bson:"env"         json:"env"         yaml:"env"`
+	Production bool                  `bson:"production"  json:"production"  yaml:"production"`
+	Source     config.RollbackSource `bson:"source"      json:"source"      yaml:"source"`
+	// WorkflowName and TaskID locate the workflow task to roll back to when the source is workflow_task
+	WorkflowName string `bson:"workflow_name" json:"workflow_name" yaml:"workflow_name"`
+	TaskID       int64  `bson:"task_id"       json:"task_id"       yaml:"task_id"`
+	// SnapshotID is the env snapshot to roll back to when the source is env_snapshot
+	SnapshotID string `bson:"snapshot_id" json:"snapshot_id" yaml:"snapshot_id"`
+	// Services to be rolled back, helm chart services are identified by the release name
+	Services []string `bson:"services" json:"services" yaml:"services"`
+}
+
+type ArgoRolloutTarget struct {
+	RolloutName string `bson:"rollout_name" json:"rollout_name" yaml:"rollout_name"`
+	// Containers are the images to be set when the action is set_image, variables like {{.job.build.svc.IMAGE}} are supported
+	Containers []*ArgoRolloutContainer `bson:"containers" json:"containers" yaml:"containers"`
+}
+
+type ArgoRolloutContainer struct {
+	Name  string `bson:"name"  json:"name"  yaml:"name"`
+	Image string `bson:"image" json:"image" yaml:"image"`
+}
+
 type GuanceyunCheckJobSpec struct {
 	ID   string `bson:"id" json:"id" yaml:"id"`
 	Name string `bson:"name" json:"name" yaml:"name"`
@@ -1025,6 +1330,30 @@ type GeneralHook struct {
 	Enabled     bool        `bson:"enabled" json:"enabled"`
 	Description string      `bson:"description" json:"description"`
 	WorkflowArg *WorkflowV4 `bson:"workflow_arg" json:"workflow_arg"`
+	// ParamMappings extract values from the json payload of the webhook request into the workflow params
+	ParamMappings []*GeneralHookParamMapping `bson:"param_mappings" json:"param_mappings"`
+}
+
+type GeneralHookParamMapping struct {
+	// Param is the name of the workflow param to be set
+	Param string `bson:"param" json:"param"`
+	// JSONPath is evaluated against the payload, e.g. $.repository.name or {.commits[0].id}
+	JSONPath string `bson:"json_path" json:"json_path"`
+	// Required fails the trigger if nothing is extracted, otherwise the param keeps the value in the hook
+	Required bool `bson:"required" json:"required"`
+}
+
+type RegistryHook struct {
+	Name        string                      `bson:"name"        json:"name"`
+	Enabled     bool                        `bson:"enabled"     json:"enabled"`
+	Description string                      `bson:"description" json:"description"`
+	Provider    config.RegistryHookProvider `bson:"provider"    json:"provider"`
+	// Secret is compared with the Authorization header or the token query of the webhook request if it is not empty
+	Secret string `bson:"secret"      json:"secret"`
+	// RepoFilter and TagFilter are regular expressions matched against the pushed repository and tag, empty means all
+	RepoFilter  string      `bson:"repo_filter" json:"repo_filter"`
+	TagFilter   string      `bson:"tag_filter"  json:"tag_filter"`
+	WorkflowArg *WorkflowV4 `bson:"workflow_arg" json:"workflow_arg"`
 }
 
 type Param struct {
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ApprovalDelegationColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewApprovalDelegationColl() *ApprovalDelegationColl {
+	name := models.ApprovalDelegation{}.TableName()
+	return &ApprovalDelegationColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ApprovalDelegationColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ApprovalDelegationColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "user_id", Value: 1},
+				bson.E{Key: "end_time", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+func (c *ApprovalDelegationColl) Create(args *models.ApprovalDelegation) error {
+	_, err := c.InsertOne(context.TODO(), args)
+	return err
+}
+
+func (c *ApprovalDelegationColl) GetByID(idHex string) (*models.ApprovalDelegation, error) {
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(models.ApprovalDelegation)
+	if err := c.FindOne(context.TODO(), bson.M{"_id": id}).Decode(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ApprovalDelegationColl) DeleteByID(idHex string) error {
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return err
+	}
+	_, err = c.DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
+
+// List returns the delegations of the user sorted by start time desc, all the delegations are included if the uid
+// is empty.
+func (c *ApprovalDelegationColl) List(uid string) ([]*models.ApprovalDelegation, error) {
+	query := bson.M{}
+	if uid != "" {
+		query["user_id"] = uid
+	}
+
+	resp := make([]*models.ApprovalDelegation, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"start_time", -1}}))
+	if err != nil {
+		return nil, err
+	}
+	return resp, cursor.All(context.TODO(), &resp)
+}
+
+// ListActive returns the delegations of the users which are active at the time
+func (c *ApprovalDelegationColl) ListActive(uids []string, now int64) ([]*models.ApprovalDelegation, error) {
+	resp := make([]*models.ApprovalDelegation, 0)
+	if len(uids) == 0 {
+		return resp, nil
+	}
+
+	query := bson.M{
+		"user_id":    bson.M{"$in": uids},
+		"start_time": bson.M{"$lte": now},
+		"end_time":   bson.M{"$gt": now},
+	}
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"start_time", -1}}))
+	if err != nil {
+		return nil, err
+	}
+	return resp, cursor.All(context.TODO(), &resp)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+// appendApprovalRecordRetry is the number of retries when another record takes the same seq concurrently
+const appendApprovalRecordRetry = 5
+
+type ApprovalRecordColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type ListApprovalRecordOption struct {
+	Source       config.ApprovalRecordSource
+	ProjectName  string
+	WorkflowName string
+	TaskID       int64
+	StartTime    int64
+	EndTime      int64
+	PageNum      int64
+	PageSize     int64
+}
+
+func NewApprovalRecordColl() *ApprovalRecordColl {
+	name := models.ApprovalRecord{}.TableName()
+	return &ApprovalRecordColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ApprovalRecordColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ApprovalRecordColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys:    bson.M{"seq": 1},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "project_name", Value: 1},
+				bson.E{Key: "create_time", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+// Append chains the record to the last one and inserts it, records are never updated or deleted
+func (c *ApprovalRecordColl) Append(ctx context.Context, record *models.ApprovalRecord) error {
+	if record == nil {
+		return errors.New("nil approval record")
+	}
+	record.CreateTime = time.Now().Unix()
+
+	for i := 0; i < appendApprovalRecordRetry; i++ {
+		record.Seq = 1
+		record.PrevHash = ""
+		last, err := c.getLast(ctx)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			record.Seq = last.Seq + 1
+			record.PrevHash = last.Hash
+		}
+		record.Hash, err = record.ComputeHash()
+		if err != nil {
+			return err
+		}
+
+		_, err = c.InsertOne(ctx, record)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to append approval record after %d retries", appendApprovalRecordRetry)
+}
+
+func (c *ApprovalRecordColl) getLast(ctx context.Context) (*models.ApprovalRecord, error) {
+	resp := new(models.ApprovalRecord)
+	opts := options.FindOne().SetSort(bson.D{{"seq", -1}})
+	err := c.FindOne(ctx, bson.M{}, opts).Decode(resp)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ApprovalRecordColl) List(ctx context.Context, opt *ListApprovalRecordOption) ([]*models.ApprovalRecord, int64, error) {
+	query := bson.M{}
+	if opt.Source != "" {
+		query["source"] = opt.Source
+	}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	}
+	if opt.WorkflowName != "" {
+		query["workflow_name"] = opt.WorkflowName
+	}
+	if opt.TaskID > 0 {
+		query["task_id"] = opt.TaskID
+	}
+	timeQuery := bson.M{}
+	if opt.StartTime > 0 {
+		timeQuery["$gte"] = opt.StartTime
+	}
+	if opt.EndTime > 0 {
+		timeQuery["$lte"] = opt.EndTime
+	}
+	if len(timeQuery) > 0 {
+		query["create_time"] = timeQuery
+	}
+
+	findOption := options.Find().SetSort(bson.D{{"seq", 1}})
+	if opt.PageNum > 0 && opt.PageSize > 0 {
+		findOption.SetSkip((opt.PageNum - 1) * opt.PageSize).SetLimit(opt.PageSize)
+	}
+
+	resp := make([]*models.ApprovalRecord, 0)
+	cursor, err := c.Collection.Find(ctx, query, findOption)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := cursor.All(ctx, &resp); err != nil {
+		return nil, 0, err
+	}
+
+	count, err := c.Collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp, count, nil
+}
+
+// Walk iterates over the whole chain in seq order and stops at the first error returned by fn
+func (c *ApprovalRecordColl) Walk(ctx context.Context, fn func(record *models.ApprovalRecord) error) error {
+	cursor, err := c.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{"seq", 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		record := new(models.ApprovalRecord)
+		if err := cursor.Decode(record); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
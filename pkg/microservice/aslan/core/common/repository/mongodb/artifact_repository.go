@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ArtifactRepositoryColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewArtifactRepositoryColl() *ArtifactRepositoryColl {
+	name := models.ArtifactRepository{}.TableName()
+	return &ArtifactRepositoryColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ArtifactRepositoryColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ArtifactRepositoryColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys:    bson.M{"name": 1},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *ArtifactRepositoryColl) Create(ctx context.Context, args *models.ArtifactRepository) error {
+	if args == nil {
+		return errors.New("artifact repository is nil")
+	}
+	args.UpdateTime = time.Now().Unix()
+
+	_, err := c.InsertOne(ctx, args)
+	return err
+}
+
+func (c *ArtifactRepositoryColl) Update(ctx context.Context, idString string, args *models.ArtifactRepository) error {
+	if args == nil {
+		return errors.New("artifact repository is nil")
+	}
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return fmt.Errorf("invalid id")
+	}
+	args.UpdateTime = time.Now().Unix()
+
+	query := bson.M{"_id": id}
+	change := bson.M{"$set": args}
+	_, err = c.UpdateOne(ctx, query, change)
+	return err
+}
+
+func (c *ArtifactRepositoryColl) List(ctx context.Context) ([]*models.ArtifactRepository, error) {
+	resp := make([]*models.ArtifactRepository, 0)
+	cursor, err := c.Collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, cursor.All(ctx, &resp)
+}
+
+func (c *ArtifactRepositoryColl) GetByID(ctx context.Context, idString string) (*models.ArtifactRepository, error) {
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return nil, err
+	}
+
+	query := bson.M{"_id": id}
+	resp := new(models.ArtifactRepository)
+	return resp, c.FindOne(ctx, query).Decode(resp)
+}
+
+func (c *ArtifactRepositoryColl) DeleteByID(ctx context.Context, idString string) error {
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return err
+	}
+
+	query := bson.M{"_id": id}
+	_, err = c.DeleteOne(ctx, query)
+	return err
+}
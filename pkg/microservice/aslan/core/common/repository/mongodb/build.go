@@ -352,6 +352,21 @@ func (c *BuildColl) GetDockerfileTemplateReference(templateID string) ([]*models
 	return ret, nil
 }
 
+func (c *BuildColl) GetArtifactRepositoryReference(repositoryID string) ([]*models.Build, error) {
+	ret := make([]*models.Build, 0)
+	query := bson.M{"post_build.file_archive.artifact_repository_id": repositoryID}
+
+	cursor, err := c.Collection.Find(context.TODO(), query)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
 func (c *BuildColl) GetBuildTemplateReference(templateID string) ([]*models.Build, error) {
 	query := bson.M{
 		"template_id": templateID,
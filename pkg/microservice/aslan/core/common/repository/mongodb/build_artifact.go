@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type BuildArtifactColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type BuildArtifactListOption struct {
+	ProjectName   string
+	ServiceName   string
+	ServiceModule string
+	Version       string
+	RepositoryID  string
+	PageNum       int64
+	PageSize      int64
+}
+
+func NewBuildArtifactColl() *BuildArtifactColl {
+	name := models.BuildArtifact{}.TableName()
+	return &BuildArtifactColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *BuildArtifactColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *BuildArtifactColl) EnsureIndex(ctx context.Context) error {
+	mods := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "project_name", Value: 1},
+				{Key: "service_name", Value: 1},
+				{Key: "service_module", Value: 1},
+				{Key: "version", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys:    bson.M{"repository_id": 1},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mods)
+	return err
+}
+
+func (c *BuildArtifactColl) Create(ctx context.Context, args *models.BuildArtifact) error {
+	if args == nil {
+		return errors.New("build artifact is nil")
+	}
+
+	_, err := c.InsertOne(ctx, args)
+	return err
+}
+
+// List returns the artifacts matching the option, latest first
+func (c *BuildArtifactColl) List(ctx context.Context, opt *BuildArtifactListOption) ([]*models.BuildArtifact, int64, error) {
+	query := bson.M{}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	}
+	if opt.ServiceName != "" {
+		query["service_name"] = opt.ServiceName
+	}
+	if opt.ServiceModule != "" {
+		query["service_module"] = opt.ServiceModule
+	}
+	if opt.Version != "" {
+		query["version"] = opt.Version
+	}
+	if opt.RepositoryID != "" {
+		query["repository_id"] = opt.RepositoryID
+	}
+
+	count, err := c.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOpt := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}})
+	if opt.PageNum > 0 && opt.PageSize > 0 {
+		findOpt.SetSkip((opt.PageNum - 1) * opt.PageSize).SetLimit(opt.PageSize)
+	}
+
+	resp := make([]*models.BuildArtifact, 0)
+	cursor, err := c.Collection.Find(ctx, query, findOpt)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp, count, cursor.All(ctx, &resp)
+}
+
+// ListVersions returns the distinct versions of the artifacts of a service module
+func (c *BuildArtifactColl) ListVersions(ctx context.Context, projectName, serviceName, serviceModule string) ([]string, error) {
+	query := bson.M{
+		"project_name":   projectName,
+		"service_name":   serviceName,
+		"service_module": serviceModule,
+	}
+	values, err := c.Distinct(ctx, "version", query)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]string, 0, len(values))
+	for _, value := range values {
+		if version, ok := value.(string); ok {
+			resp = append(resp, version)
+		}
+	}
+	return resp, nil
+}
+
+func (c *BuildArtifactColl) GetByID(ctx context.Context, idString string) (*models.BuildArtifact, error) {
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(models.BuildArtifact)
+	return resp, c.FindOne(ctx, bson.M{"_id": id}).Decode(resp)
+}
+
+func (c *BuildArtifactColl) CountByRepositoryID(ctx context.Context, repositoryID string) (int64, error) {
+	return c.CountDocuments(ctx, bson.M{"repository_id": repositoryID})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type BuildCacheColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type BuildCacheListOption struct {
+	WorkflowName string
+	ServiceName  string
+	IDs          []string
+}
+
+func NewBuildCacheColl() *BuildCacheColl {
+	name := models.BuildCache{}.TableName()
+	return &BuildCacheColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *BuildCacheColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *BuildCacheColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "s3_storage_id", Value: 1},
+				bson.E{Key: "object_key", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "workflow_name", Value: 1},
+				bson.E{Key: "service_name", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+// RecordLookup counts a lookup of the cache object, the record is created if it doesn't exist yet.
+func (c *BuildCacheColl) RecordLookup(args *models.BuildCache, hit bool) error {
+	now := time.Now().Unix()
+	query := bson.M{"s3_storage_id": args.S3StorageID, "object_key": args.ObjectKey}
+	set := bson.M{
+		"workflow_name":  args.WorkflowName,
+		"service_name":   args.ServiceName,
+		"service_module": args.ServiceModule,
+	}
+	inc := bson.M{}
+	if hit {
+		set["last_hit_time"] = now
+		inc["hit_count"] = 1
+	} else {
+		inc["miss_count"] = 1
+	}
+	change := bson.M{
+		"$set":         set,
+		"$inc":         inc,
+		"$setOnInsert": bson.M{"create_time": now},
+	}
+	_, err := c.UpdateOne(context.TODO(), query, change, options.Update().SetUpsert(true))
+	return err
+}
+
+// UpdateObjectInfo updates the size and the modification time of the cache object after it is uploaded.
+func (c *BuildCacheColl) UpdateObjectInfo(s3StorageID, objectKey string, size, lastUpdateTime int64) error {
+	query := bson.M{"s3_storage_id": s3StorageID, "object_key": objectKey}
+	change := bson.M{"$set": bson.M{
+		"size":             size,
+		"last_update_time": lastUpdateTime,
+	}}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
+func (c *BuildCacheColl) List(opt *BuildCacheListOption) ([]*models.BuildCache, error) {
+	query := bson.M{}
+	if opt.WorkflowName != "" {
+		query["workflow_name"] = opt.WorkflowName
+	}
+	if opt.ServiceName != "" {
+		query["service_name"] = opt.ServiceName
+	}
+	if len(opt.IDs) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(opt.IDs))
+		for _, id := range opt.IDs {
+			oid, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, oid)
+		}
+		query["_id"] = bson.M{"$in": ids}
+	}
+
+	opts := options.Find().SetSort(bson.D{{"workflow_name", 1}, {"service_name", 1}, {"service_module", 1}})
+	resp := make([]*models.BuildCache, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *BuildCacheColl) DeleteByID(id primitive.ObjectID) error {
+	_, err := c.DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+// maxCertRenewalRecords is the number of the latest renewal records kept for a project
+const maxCertRenewalRecords = 200
+
+type CertExpiryMonitorColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewCertExpiryMonitorColl() *CertExpiryMonitorColl {
+	name := models.CertExpiryMonitor{}.TableName()
+	return &CertExpiryMonitorColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *CertExpiryMonitorColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *CertExpiryMonitorColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+
+	return err
+}
+
+func (c *CertExpiryMonitorColl) Find(projectName string) (*models.CertExpiryMonitor, error) {
+	query := bson.M{"project_name": projectName}
+	resp := new(models.CertExpiryMonitor)
+	err := c.FindOne(context.TODO(), query).Decode(resp)
+
+	return resp, err
+}
+
+func (c *CertExpiryMonitorColl) ListEnabled() ([]*models.CertExpiryMonitor, error) {
+	resp := make([]*models.CertExpiryMonitor, 0)
+	cursor, err := c.Collection.Find(context.TODO(), bson.M{"enabled": true})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+func (c *CertExpiryMonitorColl) CreateOrUpdate(args *models.CertExpiryMonitor) error {
+	if args == nil {
+		return errors.New("nil cert expiry monitor")
+	}
+
+	args.UpdateTime = time.Now().Unix()
+
+	query := bson.M{"project_name": args.ProjectName}
+	change := bson.M{"$set": bson.M{
+		"project_name":     args.ProjectName,
+		"enabled":          args.Enabled,
+		"threshold_days":   args.ThresholdDays,
+		"notify_ctls":      args.NotifyCtls,
+		"renewal_workflow": args.RenewalWorkflow,
+		"updated_by":       args.UpdatedBy,
+		"update_time":      args.UpdateTime,
+	}}
+	_, err := c.UpdateOne(context.TODO(), query, change, options.Update().SetUpsert(true))
+
+	return err
+}
+
+func (c *CertExpiryMonitorColl) AddRenewalRecord(projectName string, record *models.CertRenewalRecord) error {
+	query := bson.M{"project_name": projectName}
+	change := bson.M{"$push": bson.M{"renewal_records": bson.M{
+		"$each":  []*models.CertRenewalRecord{record},
+		"$slice": -maxCertRenewalRecords,
+	}}}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+
+	return err
+}
+
+func (c *CertExpiryMonitorColl) UpdateNotifyRecords(projectName string, records []*models.CertNotifyRecord) error {
+	query := bson.M{"project_name": projectName}
+	change := bson.M{"$set": bson.M{"notify_records": records}}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+
+	return err
+}
+
+func (c *CertExpiryMonitorColl) Delete(projectName string) error {
+	_, err := c.DeleteOne(context.TODO(), bson.M{"project_name": projectName})
+
+	return err
+}
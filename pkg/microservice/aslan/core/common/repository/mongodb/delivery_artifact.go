@@ -158,6 +158,9 @@ func (c *DeliveryArtifactColl) Get(args *DeliveryArtifactArgs) (*models.Delivery
 	if args.Image != "" {
 		query["image"] = args.Image
 	}
+	if args.ImageDigest != "" {
+		query["image_digest"] = args.ImageDigest
+	}
 
 	err := c.FindOne(context.TODO(), query).Decode(&resp)
 	if err != nil {
@@ -64,8 +64,14 @@ func (c *DockerfileTemplateColl) Create(obj *models.DockerfileTemplate) error {
 		return fmt.Errorf("nil object")
 	}
 
-	_, err := c.InsertOne(context.TODO(), obj)
-	return err
+	res, err := c.InsertOne(context.TODO(), obj)
+	if err != nil {
+		return err
+	}
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		obj.ID = id
+	}
+	return nil
 }
 
 func (c *DockerfileTemplateColl) Update(idString string, obj *models.DockerfileTemplate) error {
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type EnvResourceUsageColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type EnvResourceUsageListOption struct {
+	ProductName string
+	EnvName     string
+	Projects    []string
+	StartTime   int64
+	EndTime     int64
+}
+
+func NewEnvResourceUsageColl() *EnvResourceUsageColl {
+	name := models.EnvResourceUsage{}.TableName()
+	return &EnvResourceUsageColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *EnvResourceUsageColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *EnvResourceUsageColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "product_name", Value: 1},
+				bson.E{Key: "env_name", Value: 1},
+				bson.E{Key: "create_time", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "create_time", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+func (c *EnvResourceUsageColl) Create(args *models.EnvResourceUsage) error {
+	if args.CreateTime == 0 {
+		args.CreateTime = time.Now().Unix()
+	}
+	res, err := c.InsertOne(context.TODO(), args)
+	if err != nil {
+		return err
+	}
+	args.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// List lists the usage samples in [StartTime, EndTime) in chronological order
+func (c *EnvResourceUsageColl) List(opt *EnvResourceUsageListOption) ([]*models.EnvResourceUsage, error) {
+	query := bson.M{}
+	if opt.ProductName != "" {
+		query["product_name"] = opt.ProductName
+	} else if opt.Projects != nil {
+		query["product_name"] = bson.M{"$in": opt.Projects}
+	}
+	if opt.EnvName != "" {
+		query["env_name"] = opt.EnvName
+	}
+	timeQuery := bson.M{}
+	if opt.StartTime > 0 {
+		timeQuery["$gte"] = opt.StartTime
+	}
+	if opt.EndTime > 0 {
+		timeQuery["$lt"] = opt.EndTime
+	}
+	if len(timeQuery) > 0 {
+		query["create_time"] = timeQuery
+	}
+
+	resp := make([]*models.EnvResourceUsage, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"create_time", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+// DeleteBefore deletes the samples created before the given time
+func (c *EnvResourceUsageColl) DeleteBefore(timestamp int64) error {
+	_, err := c.DeleteMany(context.TODO(), bson.M{"create_time": bson.M{"$lt": timestamp}})
+	return err
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type EnvDriftMonitorColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewEnvDriftMonitorColl() *EnvDriftMonitorColl {
+	name := models.EnvDriftMonitor{}.TableName()
+	return &EnvDriftMonitorColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *EnvDriftMonitorColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *EnvDriftMonitorColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+
+	return err
+}
+
+func (c *EnvDriftMonitorColl) Find(projectName string) (*models.EnvDriftMonitor, error) {
+	query := bson.M{"project_name": projectName}
+	resp := new(models.EnvDriftMonitor)
+	err := c.FindOne(context.TODO(), query).Decode(resp)
+
+	return resp, err
+}
+
+func (c *EnvDriftMonitorColl) ListEnabled() ([]*models.EnvDriftMonitor, error) {
+	resp := make([]*models.EnvDriftMonitor, 0)
+	cursor, err := c.Collection.Find(context.TODO(), bson.M{"enabled": true})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+func (c *EnvDriftMonitorColl) CreateOrUpdate(args *models.EnvDriftMonitor) error {
+	if args == nil {
+		return errors.New("nil env drift monitor")
+	}
+
+	args.UpdateTime = time.Now().Unix()
+
+	query := bson.M{"project_name": args.ProjectName}
+	change := bson.M{"$set": bson.M{
+		"project_name": args.ProjectName,
+		"enabled":      args.Enabled,
+		"notify_ctls":  args.NotifyCtls,
+		"updated_by":   args.UpdatedBy,
+		"update_time":  args.UpdateTime,
+	}}
+	_, err := c.UpdateOne(context.TODO(), query, change, options.Update().SetUpsert(true))
+
+	return err
+}
+
+type EnvDriftRecordColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type EnvDriftRecordListOption struct {
+	ProductName string
+	EnvName     string
+	Production  bool
+	Page        int64
+	PageSize    int64
+}
+
+func NewEnvDriftRecordColl() *EnvDriftRecordColl {
+	name := models.EnvDriftRecord{}.TableName()
+	return &EnvDriftRecordColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *EnvDriftRecordColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *EnvDriftRecordColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "product_name", Value: 1},
+			bson.E{Key: "env_name", Value: 1},
+			bson.E{Key: "production", Value: 1},
+			bson.E{Key: "create_time", Value: -1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *EnvDriftRecordColl) Create(args *models.EnvDriftRecord) error {
+	args.CreateTime = time.Now().Unix()
+	res, err := c.InsertOne(context.TODO(), args)
+	if err != nil {
+		return err
+	}
+	args.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// List lists the drift records of the env in reverse chronological order
+func (c *EnvDriftRecordColl) List(opt *EnvDriftRecordListOption) ([]*models.EnvDriftRecord, int64, error) {
+	query := bson.M{
+		"product_name": opt.ProductName,
+		"env_name":     opt.EnvName,
+		"production":   opt.Production,
+	}
+
+	opts := options.Find().SetSort(bson.D{{"create_time", -1}})
+	if opt.Page > 0 && opt.PageSize > 0 {
+		opts.SetSkip((opt.Page - 1) * opt.PageSize).SetLimit(opt.PageSize)
+	}
+
+	count, err := c.CountDocuments(context.TODO(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*models.EnvDriftRecord, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, count, err
+}
+
+// FindLatest returns the latest drift record of the env, mongo.ErrNoDocuments is returned if there is none
+func (c *EnvDriftRecordColl) FindLatest(productName, envName string, production bool) (*models.EnvDriftRecord, error) {
+	query := bson.M{
+		"product_name": productName,
+		"env_name":     envName,
+		"production":   production,
+	}
+	opts := options.FindOne().SetSort(bson.D{{"create_time", -1}})
+
+	resp := new(models.EnvDriftRecord)
+	err := c.FindOne(context.TODO(), query, opts).Decode(resp)
+	return resp, err
+}
+
+// DeleteOutdated keeps the latest keep records of the env and deletes the others
+func (c *EnvDriftRecordColl) DeleteOutdated(productName, envName string, production bool, keep int) error {
+	records, _, err := c.List(&EnvDriftRecordListOption{
+		ProductName: productName,
+		EnvName:     envName,
+		Production:  production,
+	})
+	if err != nil {
+		return err
+	}
+	if len(records) <= keep {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(records)-keep)
+	for _, record := range records[keep:] {
+		ids = append(ids, record.ID)
+	}
+	_, err = c.DeleteMany(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
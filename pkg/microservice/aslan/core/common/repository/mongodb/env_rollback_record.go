@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type EnvRollbackRecordColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type EnvRollbackRecordListOption struct {
+	ProductName string
+	EnvName     string
+	Production  bool
+	ServiceName string
+	PageNum     int64
+	PageSize    int64
+}
+
+func NewEnvRollbackRecordColl() *EnvRollbackRecordColl {
+	name := models.EnvRollbackRecord{}.TableName()
+	return &EnvRollbackRecordColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *EnvRollbackRecordColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *EnvRollbackRecordColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "product_name", Value: 1},
+			bson.E{Key: "env_name", Value: 1},
+			bson.E{Key: "production", Value: 1},
+			bson.E{Key: "create_time", Value: -1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *EnvRollbackRecordColl) Create(args *models.EnvRollbackRecord) error {
+	args.CreateTime = time.Now().Unix()
+	res, err := c.InsertOne(context.TODO(), args)
+	if err != nil {
+		return err
+	}
+	args.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// List lists the rollback records of the env in reverse chronological order
+func (c *EnvRollbackRecordColl) List(opt *EnvRollbackRecordListOption) ([]*models.EnvRollbackRecord, int64, error) {
+	query := bson.M{
+		"product_name": opt.ProductName,
+		"env_name":     opt.EnvName,
+		"production":   opt.Production,
+	}
+	if opt.ServiceName != "" {
+		query["service_name"] = opt.ServiceName
+	}
+
+	opts := options.Find().SetSort(bson.D{{"create_time", -1}})
+	if opt.PageNum > 0 && opt.PageSize > 0 {
+		opts.SetSkip((opt.PageNum - 1) * opt.PageSize).SetLimit(opt.PageSize)
+	}
+
+	resp := make([]*models.EnvRollbackRecord, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, 0, err
+	}
+
+	count, err := c.CountDocuments(context.TODO(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp, count, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type EnvSnapshotColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type EnvSnapshotListOption struct {
+	ProductName string
+	EnvName     string
+	Production  bool
+	Type        models.EnvSnapshotType
+}
+
+func NewEnvSnapshotColl() *EnvSnapshotColl {
+	name := models.EnvSnapshot{}.TableName()
+	return &EnvSnapshotColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *EnvSnapshotColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *EnvSnapshotColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "product_name", Value: 1},
+			bson.E{Key: "env_name", Value: 1},
+			bson.E{Key: "production", Value: 1},
+			bson.E{Key: "create_time", Value: -1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *EnvSnapshotColl) Create(args *models.EnvSnapshot) error {
+	args.CreateTime = time.Now().Unix()
+	res, err := c.InsertOne(context.TODO(), args)
+	if err != nil {
+		return err
+	}
+	args.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (c *EnvSnapshotColl) Find(productName, envName string, production bool, idStr string) (*models.EnvSnapshot, error) {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	query := bson.M{
+		"_id":          id,
+		"product_name": productName,
+		"env_name":     envName,
+		"production":   production,
+	}
+	resp := new(models.EnvSnapshot)
+	err = c.FindOne(context.TODO(), query).Decode(resp)
+	return resp, err
+}
+
+// List lists the snapshots of the env in reverse chronological order, the snapshot content is not returned
+func (c *EnvSnapshotColl) List(opt *EnvSnapshotListOption) ([]*models.EnvSnapshot, error) {
+	query := bson.M{
+		"product_name": opt.ProductName,
+		"env_name":     opt.EnvName,
+		"production":   opt.Production,
+	}
+	if opt.Type != "" {
+		query["type"] = opt.Type
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{"create_time", -1}}).
+		SetProjection(bson.M{"services": 0, "default_values": 0, "yaml_data": 0, "global_variables": 0})
+
+	resp := make([]*models.EnvSnapshot, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+func (c *EnvSnapshotColl) Delete(productName, envName string, production bool, idStr string) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return err
+	}
+
+	query := bson.M{
+		"_id":          id,
+		"product_name": productName,
+		"env_name":     envName,
+		"production":   production,
+	}
+	_, err = c.DeleteOne(context.TODO(), query)
+	return err
+}
+
+// DeleteOutdated keeps the latest keep snapshots of the given type and deletes the others
+func (c *EnvSnapshotColl) DeleteOutdated(opt *EnvSnapshotListOption, keep int) error {
+	snapshots, err := c.List(opt)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) <= keep {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(snapshots)-keep)
+	for _, snapshot := range snapshots[keep:] {
+		ids = append(ids, snapshot.ID)
+	}
+	_, err = c.DeleteMany(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
@@ -105,6 +105,27 @@ func (c *EnvVersionColl) Find(productName, envName, serviceName string, isHelmCh
 	return res, err
 }
 
+// FindLatestBefore finds the latest version of the service created no later than the given time
+func (c *EnvVersionColl) FindLatestBefore(productName, envName, serviceName string, isHelmChart, production bool, before int64) (*models.EnvServiceVersion, error) {
+	res := &models.EnvServiceVersion{}
+	query := bson.M{}
+	query["env_name"] = envName
+	query["product_name"] = productName
+	query["production"] = production
+	query["create_time"] = bson.M{"$lte": before}
+
+	if isHelmChart {
+		query["service.release_name"] = serviceName
+		query["service.type"] = setting.HelmChartDeployType
+	} else {
+		query["service.service_name"] = serviceName
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{"revision", -1}})
+	err := c.FindOne(context.TODO(), query, opts).Decode(res)
+	return res, err
+}
+
 func (c *EnvVersionColl) GetCountAndMaxRevision(productName, envName, serviceName string, isHelmChart, production bool) (int64, int64, error) {
 	match := bson.M{
 		"product_name":         productName,
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type FirstDeployHookRecordColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewFirstDeployHookRecordColl() *FirstDeployHookRecordColl {
+	name := models.FirstDeployHookRecord{}.TableName()
+	return &FirstDeployHookRecordColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *FirstDeployHookRecordColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *FirstDeployHookRecordColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "product_name", Value: 1},
+			bson.E{Key: "env_name", Value: 1},
+			bson.E{Key: "service_name", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+// Create inserts the record, a duplicate key error is returned if the hook is already run for the service in the env.
+func (c *FirstDeployHookRecordColl) Create(args *models.FirstDeployHookRecord) error {
+	res, err := c.InsertOne(context.TODO(), args)
+	if err != nil {
+		return err
+	}
+	args.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (c *FirstDeployHookRecordColl) UpdateStatus(id primitive.ObjectID, status config.Status, errMsg string) error {
+	change := bson.M{"$set": bson.M{
+		"status":   status,
+		"error":    errMsg,
+		"end_time": time.Now().Unix(),
+	}}
+	_, err := c.UpdateOne(context.TODO(), bson.M{"_id": id}, change)
+	return err
+}
+
+func (c *FirstDeployHookRecordColl) List(productName, envName string) ([]*models.FirstDeployHookRecord, error) {
+	query := bson.M{"product_name": productName, "env_name": envName}
+	opts := options.Find().SetSort(bson.D{{"start_time", -1}})
+
+	resp := make([]*models.FirstDeployHookRecord, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteByEnv removes the records of the env so that the hooks run again if an env with the same name is created.
+func (c *FirstDeployHookRecordColl) DeleteByEnv(productName, envName string) error {
+	_, err := c.DeleteMany(context.TODO(), bson.M{"product_name": productName, "env_name": envName})
+	return err
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type HelmValuesSchemaColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewHelmValuesSchemaColl() *HelmValuesSchemaColl {
+	name := models.HelmValuesSchema{}.TableName()
+	return &HelmValuesSchemaColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *HelmValuesSchemaColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *HelmValuesSchemaColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+			bson.E{Key: "service_name", Value: 1},
+			bson.E{Key: "production", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *HelmValuesSchemaColl) Find(projectName, serviceName string, production bool) (*models.HelmValuesSchema, error) {
+	resp := new(models.HelmValuesSchema)
+	query := bson.M{"project_name": projectName, "service_name": serviceName, "production": production}
+	if err := c.FindOne(context.TODO(), query).Decode(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *HelmValuesSchemaColl) Upsert(obj *models.HelmValuesSchema) error {
+	obj.UpdateTime = time.Now().Unix()
+	query := bson.M{"project_name": obj.ProjectName, "service_name": obj.ServiceName, "production": obj.Production}
+	change := bson.M{"$set": bson.M{
+		"source":      obj.Source,
+		"schema":      obj.Schema,
+		"update_by":   obj.UpdateBy,
+		"update_time": obj.UpdateTime,
+	}}
+	_, err := c.UpdateOne(context.TODO(), query, change, options.Update().SetUpsert(true))
+	return err
+}
+
+func (c *HelmValuesSchemaColl) Delete(projectName, serviceName string, production bool) error {
+	query := bson.M{"project_name": projectName, "service_name": serviceName, "production": production}
+	_, err := c.DeleteOne(context.TODO(), query)
+	return err
+}
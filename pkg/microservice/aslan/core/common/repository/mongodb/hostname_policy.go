@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type HostnamePolicyColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewHostnamePolicyColl() *HostnamePolicyColl {
+	name := models.HostnamePolicy{}.TableName()
+	return &HostnamePolicyColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *HostnamePolicyColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *HostnamePolicyColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+
+	return err
+}
+
+func (c *HostnamePolicyColl) Find(projectName string) (*models.HostnamePolicy, error) {
+	query := bson.M{"project_name": projectName}
+	resp := new(models.HostnamePolicy)
+	err := c.FindOne(context.TODO(), query).Decode(resp)
+
+	return resp, err
+}
+
+func (c *HostnamePolicyColl) ListEnabled() ([]*models.HostnamePolicy, error) {
+	resp := make([]*models.HostnamePolicy, 0)
+	cursor, err := c.Collection.Find(context.TODO(), bson.M{"enabled": true})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+func (c *HostnamePolicyColl) CreateOrUpdate(args *models.HostnamePolicy) error {
+	if args == nil {
+		return errors.New("nil hostname policy")
+	}
+
+	args.UpdateTime = time.Now().Unix()
+
+	query := bson.M{"project_name": args.ProjectName}
+	opts := options.Replace().SetUpsert(true)
+	_, err := c.ReplaceOne(context.TODO(), query, args, opts)
+
+	return err
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ImageRetentionPolicyColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewImageRetentionPolicyColl() *ImageRetentionPolicyColl {
+	name := models.ImageRetentionPolicy{}.TableName()
+	return &ImageRetentionPolicyColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ImageRetentionPolicyColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ImageRetentionPolicyColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys:    bson.M{"name": 1},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *ImageRetentionPolicyColl) Create(args *models.ImageRetentionPolicy) error {
+	if args == nil {
+		return errors.New("image retention policy is nil")
+	}
+	args.UpdateTime = time.Now().Unix()
+
+	_, err := c.InsertOne(context.TODO(), args)
+	return err
+}
+
+func (c *ImageRetentionPolicyColl) Update(idString string, args *models.ImageRetentionPolicy) error {
+	if args == nil {
+		return errors.New("image retention policy is nil")
+	}
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return fmt.Errorf("invalid id")
+	}
+	args.UpdateTime = time.Now().Unix()
+
+	query := bson.M{"_id": id}
+	change := bson.M{"$set": bson.M{
+		"name":           args.Name,
+		"registry_id":    args.RegistryID,
+		"repos":          args.Repos,
+		"keep_last_n":    args.KeepLastN,
+		"keep_tag_regex": args.KeepTagRegex,
+		"min_age_days":   args.MinAgeDays,
+		"enabled":        args.Enabled,
+		"update_by":      args.UpdateBy,
+		"update_time":    args.UpdateTime,
+	}}
+	_, err = c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
+// UpdateRunResult records the time and the number of deleted tags of the last run.
+func (c *ImageRetentionPolicyColl) UpdateRunResult(id primitive.ObjectID, runTime int64, deleted int) error {
+	query := bson.M{"_id": id}
+	change := bson.M{"$set": bson.M{
+		"last_run_time": runTime,
+		"last_deleted":  deleted,
+	}}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
+func (c *ImageRetentionPolicyColl) List(onlyEnabled bool) ([]*models.ImageRetentionPolicy, error) {
+	query := bson.M{}
+	if onlyEnabled {
+		query["enabled"] = true
+	}
+
+	resp := make([]*models.ImageRetentionPolicy, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"update_time", -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, cursor.All(context.TODO(), &resp)
+}
+
+func (c *ImageRetentionPolicyColl) GetByID(idString string) (*models.ImageRetentionPolicy, error) {
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return nil, err
+	}
+
+	query := bson.M{"_id": id}
+	resp := new(models.ImageRetentionPolicy)
+	return resp, c.FindOne(context.TODO(), query).Decode(resp)
+}
+
+func (c *ImageRetentionPolicyColl) DeleteByID(idString string) error {
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return err
+	}
+
+	query := bson.M{"_id": id}
+	_, err = c.DeleteOne(context.TODO(), query)
+	return err
+}
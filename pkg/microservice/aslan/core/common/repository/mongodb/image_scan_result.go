@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ImageScanResultColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type ImageScanResultListOption struct {
+	ProjectName  string
+	WorkflowName string
+	TaskID       int64
+	Image        string
+}
+
+func NewImageScanResultColl() *ImageScanResultColl {
+	name := models.ImageScanResult{}.TableName()
+	return &ImageScanResultColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ImageScanResultColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ImageScanResultColl) EnsureIndex(ctx context.Context) error {
+	mods := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "workflow_name", Value: 1},
+				bson.E{Key: "task_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys:    bson.M{"image": 1},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mods)
+	return err
+}
+
+func (c *ImageScanResultColl) Create(args *models.ImageScanResult) error {
+	args.CreateTime = time.Now().Unix()
+	_, err := c.InsertOne(context.TODO(), args)
+	return err
+}
+
+// List returns the scan results matching the option, the latest one comes first.
+func (c *ImageScanResultColl) List(opt *ImageScanResultListOption) ([]*models.ImageScanResult, error) {
+	query := bson.M{}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	}
+	if opt.WorkflowName != "" {
+		query["workflow_name"] = opt.WorkflowName
+	}
+	if opt.TaskID > 0 {
+		query["task_id"] = opt.TaskID
+	}
+	if opt.Image != "" {
+		query["image"] = opt.Image
+	}
+
+	resp := make([]*models.ImageScanResult, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"create_time", -1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
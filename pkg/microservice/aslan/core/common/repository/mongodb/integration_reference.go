@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+// findByAnyField finds the documents whose value of any of the given fields equals to the value, the fields can be
+// nested paths such as "stages.jobs.spec.cluster_id" which also match the elements of the arrays on the path.
+func findByAnyField(coll *mongo.Collection, fields []string, value interface{}, result interface{}) error {
+	or := bson.A{}
+	for _, field := range fields {
+		or = append(or, bson.M{field: value})
+	}
+
+	ctx := context.Background()
+	cursor, err := coll.Find(ctx, bson.M{"$or": or})
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, result)
+}
+
+func (c *BuildColl) ListByReference(fields []string, value interface{}) ([]*models.Build, error) {
+	resp := make([]*models.Build, 0)
+	return resp, findByAnyField(c.Collection, fields, value, &resp)
+}
+
+func (c *TestingColl) ListByReference(fields []string, value interface{}) ([]*models.Testing, error) {
+	resp := make([]*models.Testing, 0)
+	return resp, findByAnyField(c.Collection, fields, value, &resp)
+}
+
+func (c *ScanningColl) ListByReference(fields []string, value interface{}) ([]*models.Scanning, error) {
+	resp := make([]*models.Scanning, 0)
+	return resp, findByAnyField(c.Collection, fields, value, &resp)
+}
+
+func (c *WorkflowV4Coll) ListByReference(fields []string, value interface{}) ([]*models.WorkflowV4, error) {
+	resp := make([]*models.WorkflowV4, 0)
+	return resp, findByAnyField(c.Collection, fields, value, &resp)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type JobOutputColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type JobOutputListOption struct {
+	ProjectName   string
+	WorkflowName  string
+	TaskID        int64
+	JobName       string
+	ServiceName   string
+	ServiceModule string
+	Name          string
+	Value         string
+	// StartTime and EndTime are the range of the create time of the outputs, 0 means no limit
+	StartTime int64
+	EndTime   int64
+	PageNum   int64
+	PageSize  int64
+}
+
+func NewJobOutputColl() *JobOutputColl {
+	name := models.JobOutput{}.TableName()
+	return &JobOutputColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *JobOutputColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *JobOutputColl) EnsureIndex(ctx context.Context) error {
+	mods := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workflow_name", Value: 1},
+				{Key: "task_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_name", Value: 1},
+				{Key: "service_name", Value: 1},
+				{Key: "create_time", Value: -1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mods)
+	return err
+}
+
+// ReplaceByTask replaces the outputs of the workflow task, the outputs are saved again when the task is retried.
+func (c *JobOutputColl) ReplaceByTask(ctx context.Context, workflowName string, taskID int64, outputs []*models.JobOutput) error {
+	if _, err := c.DeleteMany(ctx, bson.M{"workflow_name": workflowName, "task_id": taskID}); err != nil {
+		return err
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(outputs))
+	for _, output := range outputs {
+		docs = append(docs, output)
+	}
+	_, err := c.InsertMany(ctx, docs)
+	return err
+}
+
+// List returns the outputs matching the option, latest first
+func (c *JobOutputColl) List(ctx context.Context, opt *JobOutputListOption) ([]*models.JobOutput, int64, error) {
+	query := bson.M{}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	}
+	if opt.WorkflowName != "" {
+		query["workflow_name"] = opt.WorkflowName
+	}
+	if opt.TaskID > 0 {
+		query["task_id"] = opt.TaskID
+	}
+	if opt.JobName != "" {
+		query["job_name"] = opt.JobName
+	}
+	if opt.ServiceName != "" {
+		query["service_name"] = opt.ServiceName
+	}
+	if opt.ServiceModule != "" {
+		query["service_module"] = opt.ServiceModule
+	}
+	if opt.Name != "" {
+		query["name"] = opt.Name
+	}
+	if opt.Value != "" {
+		query["value"] = opt.Value
+	}
+	timeRange := bson.M{}
+	if opt.StartTime > 0 {
+		timeRange["$gte"] = opt.StartTime
+	}
+	if opt.EndTime > 0 {
+		timeRange["$lte"] = opt.EndTime
+	}
+	if len(timeRange) > 0 {
+		query["create_time"] = timeRange
+	}
+
+	count, err := c.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOpt := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}, {Key: "task_id", Value: -1}})
+	if opt.PageNum > 0 && opt.PageSize > 0 {
+		findOpt.SetSkip((opt.PageNum - 1) * opt.PageSize).SetLimit(opt.PageSize)
+	}
+
+	resp := make([]*models.JobOutput, 0)
+	cursor, err := c.Collection.Find(ctx, query, findOpt)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp, count, cursor.All(ctx, &resp)
+}
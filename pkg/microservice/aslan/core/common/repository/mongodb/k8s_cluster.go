@@ -222,18 +222,20 @@ func (c *K8SClusterColl) UpdateMutableFields(cluster *models.K8SCluster, id stri
 	}
 	_, err = c.UpdateOne(context.TODO(),
 		bson.M{"_id": cluster.ID}, bson.M{"$set": bson.M{
-			"name":            cluster.Name,
-			"description":     cluster.Description,
-			"tags":            cluster.Tags,
-			"namespace":       cluster.Namespace,
-			"production":      cluster.Production,
-			"advanced_config": cluster.AdvancedConfig,
-			"cache":           cluster.Cache,
-			"dind_cfg":        cluster.DindCfg,
-			"kube_config":     cluster.KubeConfig,
-			"type":            cluster.Type,
-			"share_storage":   cluster.ShareStorage,
-			"provider":        cluster.Provider,
+			"name":             cluster.Name,
+			"description":      cluster.Description,
+			"tags":             cluster.Tags,
+			"namespace":        cluster.Namespace,
+			"production":       cluster.Production,
+			"advanced_config":  cluster.AdvancedConfig,
+			"cache":            cluster.Cache,
+			"dind_cfg":         cluster.DindCfg,
+			"dragonfly_config": cluster.DragonflyConfig,
+			"warm_pool":        cluster.WarmPool,
+			"kube_config":      cluster.KubeConfig,
+			"type":             cluster.Type,
+			"share_storage":    cluster.ShareStorage,
+			"provider":         cluster.Provider,
 		}},
 	)
 
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type NotificationDeliveryColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type NotificationDeliveryListOption struct {
+	WorkflowName string
+	TaskID       int64
+}
+
+func NewNotificationDeliveryColl() *NotificationDeliveryColl {
+	name := models.NotificationDelivery{}.TableName()
+	return &NotificationDeliveryColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *NotificationDeliveryColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *NotificationDeliveryColl) EnsureIndex(ctx context.Context) error {
+	mods := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "workflow_name", Value: 1},
+				bson.E{Key: "task_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys:    bson.M{"create_time": 1},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mods)
+	return err
+}
+
+func (c *NotificationDeliveryColl) Create(args *models.NotificationDelivery) error {
+	args.CreateTime = time.Now().Unix()
+	args.UpdateTime = args.CreateTime
+	res, err := c.InsertOne(context.TODO(), args)
+	if err != nil {
+		return err
+	}
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		args.ID = id
+	}
+	return nil
+}
+
+func (c *NotificationDeliveryColl) UpdateResult(id primitive.ObjectID, status models.NotificationDeliveryStatus, attempts, batchSize int, errMsg string) error {
+	change := bson.M{
+		"status":      status,
+		"attempts":    attempts,
+		"batch_size":  batchSize,
+		"error":       errMsg,
+		"update_time": time.Now().Unix(),
+	}
+	_, err := c.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": change})
+	return err
+}
+
+func (c *NotificationDeliveryColl) DeleteBefore(timestamp int64) error {
+	_, err := c.DeleteMany(context.TODO(), bson.M{"create_time": bson.M{"$lt": timestamp}})
+	return err
+}
+
+// List returns the deliveries matching the option in the order they were queued.
+func (c *NotificationDeliveryColl) List(opt *NotificationDeliveryListOption) ([]*models.NotificationDelivery, error) {
+	query := bson.M{}
+	if opt.WorkflowName != "" {
+		query["workflow_name"] = opt.WorkflowName
+	}
+	if opt.TaskID > 0 {
+		query["task_id"] = opt.TaskID
+	}
+
+	resp := make([]*models.NotificationDelivery, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{Key: "create_time", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type OpenAPISpecSnapshotColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewOpenAPISpecSnapshotColl() *OpenAPISpecSnapshotColl {
+	name := models.OpenAPISpecSnapshot{}.TableName()
+	return &OpenAPISpecSnapshotColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *OpenAPISpecSnapshotColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *OpenAPISpecSnapshotColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys:    bson.M{"version": 1},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+// Upsert saves the spec of the version, the existing spec of the same version is overwritten.
+func (c *OpenAPISpecSnapshotColl) Upsert(args *models.OpenAPISpecSnapshot) error {
+	now := time.Now().Unix()
+	change := bson.M{
+		"$set": bson.M{
+			"digest":      args.Digest,
+			"spec":        args.Spec,
+			"update_time": now,
+		},
+		"$setOnInsert": bson.M{
+			"create_time": now,
+		},
+	}
+	_, err := c.UpdateOne(context.TODO(), bson.M{"version": args.Version}, change, options.Update().SetUpsert(true))
+	return err
+}
+
+func (c *OpenAPISpecSnapshotColl) Find(version string) (*models.OpenAPISpecSnapshot, error) {
+	resp := new(models.OpenAPISpecSnapshot)
+	err := c.FindOne(context.TODO(), bson.M{"version": version}).Decode(resp)
+	return resp, err
+}
+
+// List returns the snapshots without the spec, the latest one comes first.
+func (c *OpenAPISpecSnapshotColl) List() ([]*models.OpenAPISpecSnapshot, error) {
+	opts := options.Find().SetSort(bson.D{{"create_time", -1}}).SetProjection(bson.M{"spec": 0})
+
+	resp := make([]*models.OpenAPISpecSnapshot, 0)
+	cursor, err := c.Collection.Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -107,8 +107,9 @@ func (c *ProductColl) GetCollectionName() string {
 	return c.coll
 }
 
-func (c *ProductColl) EnsureIndex(ctx context.Context) error {
-	mod := []mongo.IndexModel{
+// ManagedIndexes returns the indexes of the collection, they are ensured on startup and checked by the index audit
+func (c *ProductColl) ManagedIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		{
 			Keys: bson.D{
 				bson.E{Key: "env_name", Value: 1},
@@ -124,9 +125,19 @@ func (c *ProductColl) EnsureIndex(ctx context.Context) error {
 			},
 			Options: options.Index().SetUnique(false),
 		},
+		// used to find the envs deployed in a namespace of a cluster
+		{
+			Keys: bson.D{
+				bson.E{Key: "namespace", Value: 1},
+				bson.E{Key: "cluster_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
 	}
+}
 
-	_, err := c.Indexes().CreateMany(ctx, mod)
+func (c *ProductColl) EnsureIndex(ctx context.Context) error {
+	_, err := c.Indexes().CreateMany(ctx, c.ManagedIndexes())
 
 	return err
 }
@@ -423,6 +434,9 @@ func (c *ProductColl) Update(args *models.Product) error {
 	if args.PreSleepStatus != nil {
 		changePayload["pre_sleep_status"] = args.PreSleepStatus
 	}
+	if args.PreSleepState != nil {
+		changePayload["pre_sleep_state"] = args.PreSleepState
+	}
 	change := bson.M{"$set": changePayload}
 	_, err := c.UpdateOne(mongotool.SessionContext(context.TODO(), c.Session), query, change)
 	return err
@@ -476,6 +490,36 @@ func (c *ProductColl) UpdateDeployStrategyAndGlobalVariable(envName, productName
 	return err
 }
 
+func (c *ProductColl) UpdateServiceRevisionPins(envName, productName string, pins []*models.ServiceRevisionPin) error {
+	query := bson.M{
+		"env_name":     envName,
+		"product_name": productName,
+	}
+	change := bson.M{
+		"update_time":           time.Now().Unix(),
+		"service_revision_pins": pins,
+	}
+
+	_, err := c.UpdateOne(context.TODO(), query, bson.M{"$set": change})
+
+	return err
+}
+
+func (c *ProductColl) UpdateFluxResources(envName, productName string, resources []*models.FluxResource) error {
+	query := bson.M{
+		"env_name":     envName,
+		"product_name": productName,
+	}
+	change := bson.M{
+		"update_time":    time.Now().Unix(),
+		"flux_resources": resources,
+	}
+
+	_, err := c.UpdateOne(context.TODO(), query, bson.M{"$set": change})
+
+	return err
+}
+
 func (c *ProductColl) UpdateDeployStrategy(envName, productName string, deployStrategy map[string]string) error {
 	query := bson.M{
 		"env_name":     envName,
@@ -525,6 +569,62 @@ func (c *ProductColl) UpdateProductAlias(envName, productName, alias string) err
 	return err
 }
 
+func (c *ProductColl) UpdateOwner(envName, productName string, owner *models.ResourceOwner) error {
+	query := bson.M{"env_name": envName, "product_name": productName}
+
+	change := bson.M{"$set": bson.M{
+		"owner": owner,
+	}}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+
+	return err
+}
+
+func (c *ProductColl) UpdateTTL(envName, productName string, ttl *models.EnvTTL) error {
+	query := bson.M{"env_name": envName, "product_name": productName}
+
+	var change bson.M
+	if ttl == nil {
+		change = bson.M{"$unset": bson.M{"ttl": ""}}
+	} else {
+		change = bson.M{"$set": bson.M{"ttl": ttl}}
+	}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+
+	return err
+}
+
+// ListWithTTL lists the envs which are not being deleted and have a ttl set.
+func (c *ProductColl) ListWithTTL() ([]*models.Product, error) {
+	var ret []*models.Product
+	query := bson.M{"ttl": bson.M{"$exists": true, "$ne": nil}, "status": bson.M{"$ne": setting.ProductStatusDeleting}}
+	cursor, err := c.Collection.Find(context.TODO(), query)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &ret)
+	return ret, err
+}
+
+func (c *ProductColl) ListByOwner(uid string) ([]*models.Product, error) {
+	var ret []*models.Product
+	query := bson.M{"owner.type": config.OwnerTypeUser, "owner.id": uid, "status": bson.M{"$ne": setting.ProductStatusDeleting}}
+	cursor, err := c.Collection.Find(context.TODO(), query)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &ret)
+	return ret, err
+}
+
+// TransferOwner sets the owner of all the envs owned by the user, the ctx may carry a session.
+func (c *ProductColl) TransferOwner(ctx context.Context, fromUID string, owner *models.ResourceOwner) error {
+	query := bson.M{"owner.type": config.OwnerTypeUser, "owner.id": fromUID}
+	change := bson.M{"$set": bson.M{"owner": owner}}
+	_, err := c.UpdateMany(ctx, query, change)
+	return err
+}
+
 func (c *ProductColl) UpdateIsPublic(envName, productName string, isPublic bool) error {
 	query := bson.M{"env_name": envName, "product_name": productName}
 	change := bson.M{"$set": bson.M{
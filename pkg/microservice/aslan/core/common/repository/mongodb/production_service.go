@@ -348,6 +348,61 @@ func (c *ProductionServiceColl) UpdateServiceVariables(args *models.Service) err
 	return err
 }
 
+func (c *ProductionServiceColl) UpdateServiceFirstDeployHook(args *models.Service) error {
+	if args == nil {
+		return errors.New("nil ServiceTmplObject")
+	}
+	args.ProductName = strings.TrimSpace(args.ProductName)
+	args.ServiceName = strings.TrimSpace(args.ServiceName)
+
+	query := bson.M{"product_name": args.ProductName, "service_name": args.ServiceName, "revision": args.Revision}
+	change := bson.M{"$set": bson.M{"first_deploy_hook": args.FirstDeployHook}}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
+// UpdateDeployWindow sets the deploy window of all the revisions of the service.
+func (c *ProductionServiceColl) UpdateDeployWindow(productName, serviceName string, window *models.DeployWindow) error {
+	query := bson.M{"product_name": productName, "service_name": serviceName}
+	change := bson.M{"$set": bson.M{"deploy_window": window}}
+	_, err := c.UpdateMany(context.TODO(), query, change)
+	return err
+}
+
+// UpdateLockout sets the lockout of all the revisions of the service.
+func (c *ProductionServiceColl) UpdateLockout(productName, serviceName string, lockout *models.ServiceLockout) error {
+	query := bson.M{"product_name": productName, "service_name": serviceName}
+	change := bson.M{"$set": bson.M{"lockout": lockout}}
+	_, err := c.UpdateMany(context.TODO(), query, change)
+	return err
+}
+
+// UpdateOwner sets the owner of all the revisions of the service.
+func (c *ProductionServiceColl) UpdateOwner(productName, serviceName string, owner *models.ResourceOwner) error {
+	query := bson.M{"product_name": productName, "service_name": serviceName}
+	change := bson.M{"$set": bson.M{"owner": owner}}
+	_, err := c.UpdateMany(context.TODO(), query, change)
+	return err
+}
+
+// ListByOwner returns the latest revision of the services owned by the user.
+func (c *ProductionServiceColl) ListByOwner(uid string) ([]*models.Service, error) {
+	m := bson.M{
+		"owner.type": config.OwnerTypeUser,
+		"owner.id":   uid,
+		"status":     bson.M{"$ne": setting.ProductStatusDeleting},
+	}
+	return c.listMaxRevisions(m, nil)
+}
+
+// TransferOwner sets the owner of all the services owned by the user, the ctx may carry a session.
+func (c *ProductionServiceColl) TransferOwner(ctx context.Context, fromUID string, owner *models.ResourceOwner) error {
+	query := bson.M{"owner.type": config.OwnerTypeUser, "owner.id": fromUID}
+	change := bson.M{"$set": bson.M{"owner": owner}}
+	_, err := c.UpdateMany(ctx, query, change)
+	return err
+}
+
 func (c *ProductionServiceColl) UpdateServiceContainers(args *models.Service) error {
 	if args == nil {
 		return errors.New("nil ServiceTmplObject")
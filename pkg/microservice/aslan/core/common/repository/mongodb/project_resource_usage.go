@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ProjectResourceUsageColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type ProjectResourceUsageListOption struct {
+	ProjectName string
+	Projects    []string
+	// StartMonth and EndMonth are in the form of 2006-01, both of them are inclusive
+	StartMonth string
+	EndMonth   string
+}
+
+func NewProjectResourceUsageColl() *ProjectResourceUsageColl {
+	name := models.ProjectResourceUsage{}.TableName()
+	return &ProjectResourceUsageColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ProjectResourceUsageColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ProjectResourceUsageColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+			bson.E{Key: "month", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+// UpsertJobUsage sets the job usages of the project in the month, the storage usages are kept
+func (c *ProjectResourceUsageColl) UpsertJobUsage(args *models.ProjectResourceUsage) error {
+	return c.upsert(args.ProjectName, args.Month, bson.M{
+		"job_count":              args.JobCount,
+		"job_minutes":            args.JobMinutes,
+		"job_cpu_minutes":        args.JobCPUMinutes,
+		"job_memory_gib_minutes": args.JobMemoryGiBMinutes,
+	})
+}
+
+// UpsertStorageUsage sets the storage usages of the project in the month, the job usages are kept
+func (c *ProjectResourceUsageColl) UpsertStorageUsage(args *models.ProjectResourceUsage) error {
+	return c.upsert(args.ProjectName, args.Month, bson.M{
+		"build_cache_bytes":   args.BuildCacheBytes,
+		"artifact_bytes":      args.ArtifactBytes,
+		"log_bytes":           args.LogBytes,
+		"storage_update_time": time.Now().Unix(),
+	})
+}
+
+func (c *ProjectResourceUsageColl) upsert(projectName, month string, fields bson.M) error {
+	fields["update_time"] = time.Now().Unix()
+	query := bson.M{"project_name": projectName, "month": month}
+	_, err := c.UpdateOne(context.TODO(), query, bson.M{"$set": fields}, options.Update().SetUpsert(true))
+	return err
+}
+
+// List lists the monthly usages sorted by month and project
+func (c *ProjectResourceUsageColl) List(opt *ProjectResourceUsageListOption) ([]*models.ProjectResourceUsage, error) {
+	query := bson.M{}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	} else if opt.Projects != nil {
+		query["project_name"] = bson.M{"$in": opt.Projects}
+	}
+	monthQuery := bson.M{}
+	if opt.StartMonth != "" {
+		monthQuery["$gte"] = opt.StartMonth
+	}
+	if opt.EndMonth != "" {
+		monthQuery["$lte"] = opt.EndMonth
+	}
+	if len(monthQuery) > 0 {
+		query["month"] = monthQuery
+	}
+
+	resp := make([]*models.ProjectResourceUsage, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"month", 1}, {"project_name", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ProjectServiceAccountColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewProjectServiceAccountColl() *ProjectServiceAccountColl {
+	name := models.ProjectServiceAccount{}.TableName()
+	return &ProjectServiceAccountColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ProjectServiceAccountColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ProjectServiceAccountColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+			bson.E{Key: "name", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *ProjectServiceAccountColl) Create(args *models.ProjectServiceAccount) error {
+	now := time.Now().Unix()
+	args.CreateTime = now
+	args.UpdateTime = now
+	_, err := c.InsertOne(context.TODO(), args)
+	return err
+}
+
+func (c *ProjectServiceAccountColl) Update(args *models.ProjectServiceAccount) error {
+	query := bson.M{"project_name": args.ProjectName, "name": args.Name}
+	change := bson.M{"$set": bson.M{
+		"description": args.Description,
+		"disabled":    args.Disabled,
+		"permission":  args.Permission,
+		"updated_by":  args.UpdatedBy,
+		"update_time": time.Now().Unix(),
+	}}
+	res, err := c.UpdateOne(context.TODO(), query, change)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (c *ProjectServiceAccountColl) Find(projectName, name string) (*models.ProjectServiceAccount, error) {
+	resp := new(models.ProjectServiceAccount)
+	query := bson.M{"project_name": projectName, "name": name}
+	err := c.FindOne(context.TODO(), query).Decode(resp)
+	return resp, err
+}
+
+func (c *ProjectServiceAccountColl) List(projectName string) ([]*models.ProjectServiceAccount, error) {
+	resp := make([]*models.ProjectServiceAccount, 0)
+	cursor, err := c.Collection.Find(context.TODO(), bson.M{"project_name": projectName}, options.Find().SetSort(bson.D{{"name", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+func (c *ProjectServiceAccountColl) Delete(projectName, name string) error {
+	_, err := c.DeleteOne(context.TODO(), bson.M{"project_name": projectName, "name": name})
+	return err
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type RecentItemColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewRecentItemColl() *RecentItemColl {
+	name := models.RecentItem{}.TableName()
+	return &RecentItemColl{Collection: mongotool.Database(config.MongoDatabase()).Collection(name), coll: name}
+}
+
+func (c *RecentItemColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *RecentItemColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "user_id", Value: 1},
+				bson.E{Key: "type", Value: 1},
+				bson.E{Key: "product_name", Value: 1},
+				bson.E{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "user_id", Value: 1},
+				bson.E{Key: "visit_time", Value: -1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+// Upsert refreshes the visit time of the resource
+func (c *RecentItemColl) Upsert(args *models.RecentItem) error {
+	args.VisitTime = time.Now().Unix()
+	query := bson.M{"user_id": args.UserID, "type": args.Type, "product_name": args.ProductName, "name": args.Name}
+	change := bson.M{"$set": bson.M{"visit_time": args.VisitTime}}
+
+	_, err := c.UpdateOne(context.TODO(), query, change, options.Update().SetUpsert(true))
+	return err
+}
+
+// List lists the recent items of the user in reverse chronological order
+func (c *RecentItemColl) List(args *FavoriteArgs, limit int64) ([]*models.RecentItem, error) {
+	query := bson.M{"user_id": args.UserID}
+	if args.ProductName != "" {
+		query["product_name"] = args.ProductName
+	}
+	if args.Type != "" {
+		query["type"] = args.Type
+	}
+
+	opts := options.Find().SetSort(bson.D{{"visit_time", -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	resp := make([]*models.RecentItem, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+// DeleteOutdated keeps the latest keep items of the user and deletes the others
+func (c *RecentItemColl) DeleteOutdated(userID string, keep int64) error {
+	opts := options.Find().SetSort(bson.D{{"visit_time", -1}}).SetSkip(keep).SetProjection(bson.M{"_id": 1})
+	cursor, err := c.Collection.Find(context.TODO(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return err
+	}
+
+	items := make([]*models.RecentItem, 0)
+	if err := cursor.All(context.TODO(), &items); err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	_, err = c.DeleteMany(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+func (c *RecentItemColl) DeleteByUser(userID string) error {
+	_, err := c.DeleteMany(context.TODO(), bson.M{"user_id": userID})
+	return err
+}
@@ -189,6 +189,17 @@ func (r *RegistryNamespaceColl) Update(id string, args *models.RegistryNamespace
 	return err
 }
 
+// UpdateCredentialStatus only sets the credential status so that the concurrent updates of the registry are kept
+func (r *RegistryNamespaceColl) UpdateCredentialStatus(id string, status *models.RegistryCredentialStatus) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.UpdateOne(context.TODO(), bson.M{"_id": oid}, bson.M{"$set": bson.M{"credential_status": status}})
+	return err
+}
+
 func (r *RegistryNamespaceColl) Delete(id string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -116,11 +116,23 @@ func (c *ReleasePlanColl) DeleteByID(ctx context.Context, idString string) error
 	return err
 }
 
+// TransferManager sets the manager of the unfinished release plans managed by the user, the ctx may carry a session.
+func (c *ReleasePlanColl) TransferManager(ctx context.Context, fromUID, toUID, toName string) error {
+	query := bson.M{
+		"manager_id": fromUID,
+		"status":     bson.M{"$nin": []config.ReleasePlanStatus{config.StatusSuccess, config.StatusCancel}},
+	}
+	change := bson.M{"$set": bson.M{"manager_id": toUID, "manager": toName}}
+	_, err := c.UpdateMany(ctx, query, change)
+	return err
+}
+
 type ListReleasePlanOption struct {
 	PageNum          int64
 	PageSize         int64
 	Name             string
 	Manager          string
+	ManagerID        string
 	SuccessTimeStart int64
 	SuccessTimeEnd   int64
 	IsSort           bool
@@ -151,6 +163,9 @@ func (c *ReleasePlanColl) ListByOptions(opt *ListReleasePlanOption) ([]*models.R
 	if opt.Manager != "" {
 		query["manager"] = bson.M{"$regex": fmt.Sprintf(".*%s.*", opt.Manager), "$options": "i"}
 	}
+	if opt.ManagerID != "" {
+		query["manager_id"] = opt.ManagerID
+	}
 	if opt.SuccessTimeStart > 0 && opt.SuccessTimeEnd > 0 {
 		query["success_time"] = bson.M{"$gte": opt.SuccessTimeStart, "$lte": opt.SuccessTimeEnd}
 	}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type SavedDashboardColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type ListSavedDashboardOption struct {
+	// UserID lists the dashboards created by the user
+	UserID string
+	// ProjectNames lists the project visible dashboards of these projects
+	ProjectNames []string
+	// ProjectName lists the dashboards of the project only
+	ProjectName string
+}
+
+func NewSavedDashboardColl() *SavedDashboardColl {
+	name := models.SavedDashboard{}.TableName()
+	return &SavedDashboardColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *SavedDashboardColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *SavedDashboardColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "creator_id", Value: 1}},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys:    bson.D{bson.E{Key: "project_name", Value: 1}, bson.E{Key: "visibility", Value: 1}},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys:    bson.D{bson.E{Key: "share_token", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+func (c *SavedDashboardColl) Create(args *models.SavedDashboard) error {
+	args.CreateTime = time.Now().Unix()
+	args.UpdateTime = time.Now().Unix()
+	res, err := c.InsertOne(context.TODO(), args)
+	if err != nil {
+		return err
+	}
+	args.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (c *SavedDashboardColl) Update(idStr string, args *models.SavedDashboard) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return err
+	}
+	args.ID = id
+	args.UpdateTime = time.Now().Unix()
+	_, err = c.ReplaceOne(context.TODO(), bson.M{"_id": id}, args)
+	return err
+}
+
+func (c *SavedDashboardColl) UpdateShareToken(idStr, token string) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return err
+	}
+
+	change := bson.M{"$set": bson.M{"share_token": token}}
+	if token == "" {
+		change = bson.M{"$unset": bson.M{"share_token": ""}}
+	}
+	_, err = c.UpdateOne(context.TODO(), bson.M{"_id": id}, change)
+	return err
+}
+
+func (c *SavedDashboardColl) Delete(idStr string) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return err
+	}
+	_, err = c.DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
+
+func (c *SavedDashboardColl) GetByID(idStr string) (*models.SavedDashboard, error) {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(models.SavedDashboard)
+	err = c.FindOne(context.TODO(), bson.M{"_id": id}).Decode(resp)
+	return resp, err
+}
+
+func (c *SavedDashboardColl) GetByShareToken(token string) (*models.SavedDashboard, error) {
+	resp := new(models.SavedDashboard)
+	err := c.FindOne(context.TODO(), bson.M{"share_token": token}).Decode(resp)
+	return resp, err
+}
+
+// List lists the dashboards visible to the user: the ones created by the user, the public ones
+// and the project visible ones of the given projects.
+func (c *SavedDashboardColl) List(opt *ListSavedDashboardOption) ([]*models.SavedDashboard, error) {
+	query := bson.M{
+		"$or": bson.A{
+			bson.M{"creator_id": opt.UserID},
+			bson.M{"visibility": models.DashboardVisibilityPublic},
+			bson.M{"visibility": models.DashboardVisibilityProject, "project_name": bson.M{"$in": opt.ProjectNames}},
+		},
+	}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	}
+
+	resp := make([]*models.SavedDashboard, 0)
+	cursor, err := c.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"update_time", -1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
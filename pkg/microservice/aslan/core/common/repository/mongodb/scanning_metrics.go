@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ScanningMetricsColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type ScanningMetricsTrendOption struct {
+	StartTime    int64
+	EndTime      int64
+	Projects     []string
+	ScanningName string
+}
+
+func NewScanningMetricsColl() *ScanningMetricsColl {
+	name := models.ScanningMetrics{}.TableName()
+	return &ScanningMetricsColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ScanningMetricsColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ScanningMetricsColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "project_name", Value: 1},
+				bson.E{Key: "create_time", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "workflow_name", Value: 1},
+				bson.E{Key: "task_id", Value: 1},
+				bson.E{Key: "job_name", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+func (c *ScanningMetricsColl) Create(metrics *models.ScanningMetrics) error {
+	if metrics == nil {
+		return errors.New("nil scanning metrics")
+	}
+	if metrics.CreateTime == 0 {
+		metrics.CreateTime = time.Now().Unix()
+	}
+	metrics.Date = time.Unix(metrics.CreateTime, 0).Format(config.Date)
+
+	_, err := c.InsertOne(context.TODO(), metrics)
+	return err
+}
+
+// GetDailyTrend returns the last metrics of every scanning in every day, sorted by project, scanning and date
+func (c *ScanningMetricsColl) GetDailyTrend(opt *ScanningMetricsTrendOption) ([]*models.ScanningMetrics, error) {
+	query := bson.M{}
+	timeQuery := bson.M{}
+	if opt.StartTime > 0 {
+		timeQuery["$gte"] = opt.StartTime
+	}
+	if opt.EndTime > 0 {
+		timeQuery["$lt"] = opt.EndTime
+	}
+	if len(timeQuery) > 0 {
+		query["create_time"] = timeQuery
+	}
+	if len(opt.Projects) > 0 {
+		query["project_name"] = bson.M{"$in": opt.Projects}
+	}
+	if opt.ScanningName != "" {
+		query["scanning_name"] = opt.ScanningName
+	}
+
+	pipeline := []bson.M{
+		{"$match": query},
+		{"$sort": bson.M{"create_time": 1}},
+		{
+			"$group": bson.M{
+				"_id": bson.M{
+					"project_name":  "$project_name",
+					"scanning_name": "$scanning_name",
+					"date":          "$date",
+				},
+				"doc": bson.M{"$last": "$$ROOT"},
+			},
+		},
+		{"$replaceRoot": bson.M{"newRoot": "$doc"}},
+		{"$sort": bson.D{{"project_name", 1}, {"scanning_name", 1}, {"date", 1}}},
+	}
+
+	cursor, err := c.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*models.ScanningMetrics, 0)
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type ScheduleDecisionColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewScheduleDecisionColl() *ScheduleDecisionColl {
+	name := models.ScheduleDecision{}.TableName()
+	return &ScheduleDecisionColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *ScheduleDecisionColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *ScheduleDecisionColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys:    bson.M{"create_time": 1},
+		Options: options.Index().SetUnique(false),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *ScheduleDecisionColl) Create(args *models.ScheduleDecision) error {
+	if args.CreateTime == 0 {
+		args.CreateTime = time.Now().Unix()
+	}
+	_, err := c.InsertOne(context.TODO(), args)
+	return err
+}
+
+// ListLatest returns the latest decisions in reverse chronological order
+func (c *ScheduleDecisionColl) ListLatest(limit int64) ([]*models.ScheduleDecision, error) {
+	resp := make([]*models.ScheduleDecision, 0)
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := c.Collection.Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+// DeleteBefore deletes the decisions made before the given time
+func (c *ScheduleDecisionColl) DeleteBefore(timestamp int64) error {
+	_, err := c.DeleteMany(context.TODO(), bson.M{"create_time": bson.M{"$lt": timestamp}})
+	return err
+}
@@ -80,6 +80,36 @@ func (c *SystemSettingColl) UpdateConcurrencySetting(workflowConcurrency, buildC
 	return err
 }
 
+func (c *SystemSettingColl) UpdateFairSchedulingSetting(fairScheduling *models.FairSchedulingSettings) error {
+	id, _ := primitive.ObjectIDFromHex(setting.LocalClusterID)
+	change := bson.M{"$set": bson.M{
+		"fair_scheduling": fairScheduling,
+	}}
+	query := bson.M{"_id": id}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
+func (c *SystemSettingColl) UpdateCostPriceSetting(costPrice *models.EnvCostPrice) error {
+	id, _ := primitive.ObjectIDFromHex(setting.LocalClusterID)
+	change := bson.M{"$set": bson.M{
+		"cost_price": costPrice,
+	}}
+	query := bson.M{"_id": id}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
+func (c *SystemSettingColl) UpdateManifestLintSetting(manifestLint *models.ManifestLintSettings) error {
+	id, _ := primitive.ObjectIDFromHex(setting.LocalClusterID)
+	change := bson.M{"$set": bson.M{
+		"manifest_lint": manifestLint,
+	}}
+	query := bson.M{"_id": id}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
 func (c *SystemSettingColl) UpdateSecuritySetting(tokenExpirationTime int64) error {
 	id, _ := primitive.ObjectIDFromHex(setting.LocalClusterID)
 	change := bson.M{"$set": bson.M{
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type StaleOwnerResourceColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewStaleOwnerResourceColl() *StaleOwnerResourceColl {
+	name := models.StaleOwnerResource{}.TableName()
+	return &StaleOwnerResourceColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *StaleOwnerResourceColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *StaleOwnerResourceColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+			bson.E{Key: "resource_type", Value: 1},
+			bson.E{Key: "resource_name", Value: 1},
+			bson.E{Key: "production", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func staleOwnerResourceQuery(projectName string, resourceType config.OwnedResourceType, resourceName string, production bool) bson.M {
+	return bson.M{
+		"project_name":  projectName,
+		"resource_type": resourceType,
+		"resource_name": resourceName,
+		"production":    production,
+	}
+}
+
+func (c *StaleOwnerResourceColl) Create(args *models.StaleOwnerResource) error {
+	_, err := c.InsertOne(context.TODO(), args)
+	return err
+}
+
+func (c *StaleOwnerResourceColl) Delete(projectName string, resourceType config.OwnedResourceType, resourceName string, production bool) error {
+	_, err := c.DeleteOne(context.TODO(), staleOwnerResourceQuery(projectName, resourceType, resourceName, production))
+	return err
+}
+
+// List returns the stale owner resources of the project, all the projects are included if the project is empty.
+func (c *StaleOwnerResourceColl) List(projectName string) ([]*models.StaleOwnerResource, error) {
+	query := bson.M{}
+	if projectName != "" {
+		query["project_name"] = projectName
+	}
+
+	resp := make([]*models.StaleOwnerResource, 0)
+	opts := options.Find().SetSort(bson.D{{"project_name", 1}, {"resource_type", 1}, {"resource_name", 1}})
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -349,6 +349,27 @@ func (c *ProductColl) ListWithOption(opt *ProductListOpt) ([]*template.Product,
 	return resp, nil
 }
 
+// ListSandboxes lists sandbox projects, filtered by owner if it is not empty
+func (c *ProductColl) ListSandboxes(owner string) ([]*template.Product, error) {
+	var resp []*template.Product
+
+	query := bson.M{"sandbox": bson.M{"$exists": true, "$ne": nil}}
+	if owner != "" {
+		query["sandbox.owner"] = owner
+	}
+
+	cursor, err := c.Collection.Find(context.TODO(), query)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // TODO: make it common
 func stringToBool(source string) bool {
 	return source == "true"
@@ -560,6 +581,16 @@ func (c *ProductColl) UpdateGlobalVars(productName string, serviceVars []*types.
 	return err
 }
 
+func (c *ProductColl) UpdateEnvValidationHook(productName string, hook *template.EnvValidationHook) error {
+	query := bson.M{"product_name": productName}
+	change := bson.M{"$set": bson.M{
+		"env_validation_hook": hook,
+	}}
+
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
 func (c *ProductColl) Delete(productName string) error {
 	query := bson.M{"product_name": productName}
 
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type UserOffboardingRecordColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewUserOffboardingRecordColl() *UserOffboardingRecordColl {
+	name := models.UserOffboardingRecord{}.TableName()
+	return &UserOffboardingRecordColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *UserOffboardingRecordColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *UserOffboardingRecordColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys:    bson.M{"from_uid": 1},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys:    bson.M{"create_time": -1},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+// Create inserts the record, the ctx may carry a session so that the record is written in the transfer transaction.
+func (c *UserOffboardingRecordColl) Create(ctx context.Context, args *models.UserOffboardingRecord) error {
+	_, err := c.InsertOne(ctx, args)
+	return err
+}
+
+// List returns the records of the user sorted by create time desc, all the records are included if the uid is empty.
+func (c *UserOffboardingRecordColl) List(uid string, pageNum, pageSize int64) ([]*models.UserOffboardingRecord, int64, error) {
+	query := bson.M{}
+	if uid != "" {
+		query["from_uid"] = uid
+	}
+
+	count, err := c.CountDocuments(context.TODO(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{"create_time", -1}})
+	if pageNum > 0 && pageSize > 0 {
+		opts.SetSkip((pageNum - 1) * pageSize).SetLimit(pageSize)
+	}
+
+	resp := make([]*models.UserOffboardingRecord, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, 0, err
+	}
+	return resp, count, nil
+}
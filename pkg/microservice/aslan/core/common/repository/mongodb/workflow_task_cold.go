@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type WorkflowTaskColdColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+type WorkflowTaskColdListOption struct {
+	WorkflowName string
+	ProjectName  string
+	PageNum      int64
+	PageSize     int64
+}
+
+func NewWorkflowTaskColdColl() *WorkflowTaskColdColl {
+	name := models.WorkflowTaskCold{}.TableName()
+	return &WorkflowTaskColdColl{
+		Collection: mongotool.Database(config.MongoDatabase()).Collection(name),
+		coll:       name,
+	}
+}
+
+func (c *WorkflowTaskColdColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *WorkflowTaskColdColl) EnsureIndex(ctx context.Context) error {
+	mod := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "workflow_name", Value: 1},
+				bson.E{Key: "task_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "project_name", Value: 1},
+				bson.E{Key: "create_time", Value: -1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+	}
+
+	_, err := c.Indexes().CreateMany(ctx, mod)
+	return err
+}
+
+// Upsert saves the cold task by the id of the original task, so moving the same task twice is harmless
+func (c *WorkflowTaskColdColl) Upsert(obj *models.WorkflowTaskCold) error {
+	_, err := c.ReplaceOne(context.TODO(), bson.M{"_id": obj.ID}, obj, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (c *WorkflowTaskColdColl) Find(workflowName string, taskID int64) (*models.WorkflowTaskCold, error) {
+	resp := new(models.WorkflowTaskCold)
+	err := c.FindOne(context.TODO(), bson.M{"workflow_name": workflowName, "task_id": taskID}).Decode(resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// List returns the cold tasks without the compressed data
+func (c *WorkflowTaskColdColl) List(opt *WorkflowTaskColdListOption) ([]*models.WorkflowTaskCold, int64, error) {
+	query := bson.M{}
+	if opt.WorkflowName != "" {
+		query["workflow_name"] = opt.WorkflowName
+	}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	}
+
+	count, err := c.CountDocuments(context.TODO(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{"create_time", -1}}).SetProjection(bson.M{"data": 0})
+	if opt.PageNum > 0 && opt.PageSize > 0 {
+		opts.SetSkip((opt.PageNum - 1) * opt.PageSize).SetLimit(opt.PageSize)
+	}
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]*models.WorkflowTaskCold, 0)
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, 0, err
+	}
+	return resp, count, nil
+}
+
+// ListTasks decompresses the cold tasks of the project created in [startTime, endTime], 0 means no limit, in the order
+// of the create time.
+func (c *WorkflowTaskColdColl) ListTasks(projectName string, startTime, endTime int64) ([]*models.WorkflowTask, error) {
+	query := bson.M{"project_name": projectName}
+	timeQuery := bson.M{}
+	if startTime > 0 {
+		timeQuery["$gte"] = startTime
+	}
+	if endTime > 0 {
+		timeQuery["$lte"] = endTime
+	}
+	if len(timeQuery) > 0 {
+		query["create_time"] = timeQuery
+	}
+
+	cursor, err := c.Collection.Find(context.TODO(), query, options.Find().SetSort(bson.D{{"create_time", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	resp := make([]*models.WorkflowTask, 0)
+	for cursor.Next(context.TODO()) {
+		cold := new(models.WorkflowTaskCold)
+		if err := cursor.Decode(cold); err != nil {
+			return nil, err
+		}
+		doc, err := cold.Document()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress task %s-%d, error: %s", cold.WorkflowName, cold.TaskID, err)
+		}
+		task := new(models.WorkflowTask)
+		if err := bson.Unmarshal(doc, task); err != nil {
+			return nil, fmt.Errorf("failed to decode task %s-%d, error: %s", cold.WorkflowName, cold.TaskID, err)
+		}
+		resp = append(resp, task)
+	}
+	return resp, cursor.Err()
+}
+
+func (c *WorkflowTaskColdColl) Delete(id primitive.ObjectID) error {
+	_, err := c.DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
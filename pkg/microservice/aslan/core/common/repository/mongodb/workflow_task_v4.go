@@ -20,6 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -59,8 +62,9 @@ func (c *WorkflowTaskv4Coll) GetCollectionName() string {
 	return c.coll
 }
 
-func (c *WorkflowTaskv4Coll) EnsureIndex(ctx context.Context) error {
-	mod := []mongo.IndexModel{
+// ManagedIndexes returns the indexes of the collection, they are ensured on startup and checked by the index audit
+func (c *WorkflowTaskv4Coll) ManagedIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		{
 			Keys: bson.D{
 				bson.E{Key: "task_id", Value: 1},
@@ -83,9 +87,20 @@ func (c *WorkflowTaskv4Coll) EnsureIndex(ctx context.Context) error {
 			},
 			Options: options.Index().SetUnique(false),
 		},
+		// used to find a task of a workflow, optionally in the given status
+		{
+			Keys: bson.D{
+				bson.E{Key: "workflow_name", Value: 1},
+				bson.E{Key: "task_id", Value: 1},
+				bson.E{Key: "status", Value: 1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
 	}
+}
 
-	_, err := c.Indexes().CreateMany(ctx, mod)
+func (c *WorkflowTaskv4Coll) EnsureIndex(ctx context.Context) error {
+	_, err := c.Indexes().CreateMany(ctx, c.ManagedIndexes())
 
 	return err
 }
@@ -150,6 +165,36 @@ func (c *WorkflowTaskv4Coll) List(opt *ListWorkflowTaskV4Option) ([]*models.Work
 	return resp, count, nil
 }
 
+// ListRecentFinishedStatuses returns the statuses of the latest finished tasks of the workflow, newest first
+func (c *WorkflowTaskv4Coll) ListRecentFinishedStatuses(workflowName string, limit int64) ([]config.Status, error) {
+	query := bson.M{
+		"workflow_name": workflowName,
+		"is_deleted":    false,
+		"status": bson.M{"$in": []config.Status{
+			config.StatusPassed, config.StatusFailed, config.StatusTimeout, config.StatusCancelled,
+		}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{"task_id", -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"status": 1})
+
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*models.WorkflowTask, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+
+	resp := make([]config.Status, 0, len(tasks))
+	for _, task := range tasks {
+		resp = append(resp, task.Status)
+	}
+	return resp, nil
+}
+
 func (c *WorkflowTaskv4Coll) GetLatest(workflowName string) (*models.WorkflowTask, error) {
 	resp := new(models.WorkflowTask)
 	query := bson.M{}
@@ -288,6 +333,51 @@ func (c *WorkflowTaskv4Coll) ArchiveHistoryWorkflowTask(workflowName string, tas
 	return err
 }
 
+// ListRawFinishedBefore returns the raw documents of the finished tasks created before the given time, the tasks
+// rehydrated from the cold storage after rehydrateAfter are skipped
+func (c *WorkflowTaskv4Coll) ListRawFinishedBefore(createTime, rehydrateAfter, limit int64) ([]bson.Raw, error) {
+	query := bson.M{
+		"create_time": bson.M{"$lt": createTime},
+		"status": bson.M{"$in": []config.Status{
+			config.StatusPassed, config.StatusFailed, config.StatusTimeout, config.StatusCancelled, config.StatusReject, config.StatusUnstable,
+		}},
+		"$or": bson.A{
+			bson.M{"rehydrate_time": bson.M{"$exists": false}},
+			bson.M{"rehydrate_time": bson.M{"$lt": rehydrateAfter}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{"create_time", 1}}).SetLimit(limit)
+
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	resp := make([]bson.Raw, 0)
+	for cursor.Next(context.TODO()) {
+		doc := make(bson.Raw, len(cursor.Current))
+		copy(doc, cursor.Current)
+		resp = append(resp, doc)
+	}
+	return resp, cursor.Err()
+}
+
+// InsertRaw inserts the raw document of a task rehydrated from the cold storage and marks its rehydrate time
+func (c *WorkflowTaskv4Coll) InsertRaw(doc bson.Raw) error {
+	res, err := c.InsertOne(context.TODO(), doc)
+	if err != nil {
+		return err
+	}
+	_, err = c.UpdateOne(context.TODO(), bson.M{"_id": res.InsertedID}, bson.M{"$set": bson.M{"rehydrate_time": time.Now().Unix()}})
+	return err
+}
+
+func (c *WorkflowTaskv4Coll) DeleteByObjectID(id primitive.ObjectID) error {
+	_, err := c.DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
+
 func (c *WorkflowTaskv4Coll) ListByCursor(opt *ListWorkflowTaskV4Option) (*mongo.Cursor, error) {
 	query := bson.M{}
 	if opt.WorkflowName != "" {
@@ -339,6 +429,43 @@ func (c *WorkflowTaskv4Coll) ListCreator(projectName, name string) ([]string, er
 	return creators, err
 }
 
+// SumTaskDurationByProject returns the total running seconds of the finished workflow tasks in the given project
+func (c *WorkflowTaskv4Coll) SumTaskDurationByProject(projectName string) (int64, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"project_name": projectName,
+				"start_time":   bson.M{"$gt": 0},
+				"end_time":     bson.M{"$gt": 0},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id":      nil,
+				"duration": bson.M{"$sum": bson.M{"$subtract": bson.A{"$end_time", "$start_time"}}},
+			},
+		},
+	}
+
+	cursor, err := c.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if !cursor.Next(context.TODO()) {
+		return 0, cursor.Err()
+	}
+
+	result := struct {
+		Duration int64 `bson:"duration"`
+	}{}
+	if err := cursor.Decode(&result); err != nil {
+		return 0, err
+	}
+	return result.Duration, nil
+}
+
 type WorkFlowTaskFilter struct {
 	WorkflowName string   `json:"workflow_name"`
 	ProjectName  string   `json:"project_name"`
@@ -349,6 +476,11 @@ type WorkFlowTaskFilter struct {
 	Service      []string `json:"service"`
 	Env          []string `json:"env"`
 	Status       []string `json:"status"`
+	// Keyword matches the task id, the task name or the remark of the tasks
+	Keyword string `json:"keyword"`
+	// TaskIDs and ExcludedTaskIDs are ignored if they are nil
+	TaskIDs         []int64 `json:"task_ids"`
+	ExcludedTaskIDs []int64 `json:"excluded_task_ids"`
 }
 
 func (c *WorkflowTaskv4Coll) ListByFilter(filter *WorkFlowTaskFilter, pageNum, pageSize int64) ([]*models.WorkflowTask, int64, error) {
@@ -369,6 +501,22 @@ func (c *WorkflowTaskv4Coll) ListByFilter(filter *WorkFlowTaskFilter, pageNum, p
 	if len(filter.Status) > 0 {
 		query["status"] = bson.M{"$in": filter.Status}
 	}
+	if filter.TaskIDs != nil {
+		query["task_id"] = bson.M{"$in": filter.TaskIDs}
+	} else if len(filter.ExcludedTaskIDs) > 0 {
+		query["task_id"] = bson.M{"$nin": filter.ExcludedTaskIDs}
+	}
+	if filter.Keyword != "" {
+		keyword := regexp.QuoteMeta(filter.Keyword)
+		keywordQuery := bson.A{
+			bson.M{"task_name": bson.M{"$regex": keyword, "$options": "i"}},
+			bson.M{"remark": bson.M{"$regex": keyword, "$options": "i"}},
+		}
+		if taskID, err := strconv.ParseInt(strings.TrimPrefix(filter.Keyword, "#"), 10, 64); err == nil {
+			keywordQuery = append(keywordQuery, bson.M{"task_id": taskID})
+		}
+		query["$or"] = keywordQuery
+	}
 
 	if len(filter.Service) > 0 {
 		query["workflow_args.stages.jobs"] = bson.M{
@@ -415,3 +563,37 @@ func (c *WorkflowTaskv4Coll) ListByFilter(filter *WorkFlowTaskFilter, pageNum, p
 	}
 	return tasks, count, nil
 }
+
+// ListDeployTasks lists the tasks of the project containing deploy jobs created in the time range,
+// the workflow args are not returned since they are not needed to know what has been deployed.
+func (c *WorkflowTaskv4Coll) ListDeployTasks(projectName string, startTime, endTime int64) ([]*models.WorkflowTask, error) {
+	query := bson.M{
+		"project_name":     projectName,
+		"is_deleted":       false,
+		"stages.jobs.type": bson.M{"$in": []config.JobType{config.JobZadigDeploy, config.JobZadigHelmDeploy}},
+	}
+	timeQuery := bson.M{}
+	if startTime > 0 {
+		timeQuery["$gte"] = startTime
+	}
+	if endTime > 0 {
+		timeQuery["$lte"] = endTime
+	}
+	if len(timeQuery) > 0 {
+		query["create_time"] = timeQuery
+	}
+
+	opt := options.Find().
+		SetSort(bson.D{{"create_time", 1}}).
+		SetProjection(bson.M{"workflow_args": 0, "origin_workflow_args": 0})
+
+	resp := make([]*models.WorkflowTask, 0)
+	cursor, err := c.Collection.Find(context.TODO(), query, opt)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -291,6 +291,32 @@ func (c *WorkflowV4Coll) GetByID(idstring string) (*models.WorkflowV4, error) {
 	return resp, nil
 }
 
+func (c *WorkflowV4Coll) UpdateOwner(name string, owner *models.ResourceOwner) error {
+	query := bson.M{"name": name}
+	change := bson.M{"$set": bson.M{"owner": owner}}
+	_, err := c.UpdateOne(context.TODO(), query, change)
+	return err
+}
+
+func (c *WorkflowV4Coll) ListByOwner(uid string) ([]*models.WorkflowV4, error) {
+	resp := make([]*models.WorkflowV4, 0)
+	query := bson.M{"owner.type": config.OwnerTypeUser, "owner.id": uid}
+	cursor, err := c.Collection.Find(context.TODO(), query)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &resp)
+	return resp, err
+}
+
+// TransferOwner sets the owner of all the workflows owned by the user, the ctx may carry a session.
+func (c *WorkflowV4Coll) TransferOwner(ctx context.Context, fromUID string, owner *models.ResourceOwner) error {
+	query := bson.M{"owner.type": config.OwnerTypeUser, "owner.id": fromUID}
+	change := bson.M{"$set": bson.M{"owner": owner}}
+	_, err := c.UpdateMany(ctx, query, change)
+	return err
+}
+
 func (c *WorkflowV4Coll) Update(idString string, obj *models.WorkflowV4) error {
 	if obj == nil {
 		return fmt.Errorf("nil object")
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
 
 	config2 "github.com/koderover/zadig/v2/pkg/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
@@ -83,32 +84,70 @@ func (c *GlobalApproveManager) DoApproval(key, userName, userID, comment string,
 		return nil, fmt.Errorf("not found approval")
 	}
 
-	meetUser := false
-	for _, user := range approvalData.ApproveUsers {
-		if user.UserID != userID {
-			continue
-		}
-		if user.RejectOrApprove != "" {
+	// the delegations created after the approval started are applied here
+	ApplyApprovalDelegations(approvalData)
+
+	target, err := approvalTarget(activeApproveUsers(approvalData), userID, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	target.Comment = comment
+	target.OperationTime = time.Now().Unix()
+	if approve {
+		target.RejectOrApprove = config.Approve
+	} else {
+		target.RejectOrApprove = config.Reject
+	}
+
+	c.SetApproval(key, approvalData)
+	return approvalData, nil
+}
+
+// approvalTarget returns the approver whose decision the user makes. The user makes at most one decision among the
+// approvers, either his own or on behalf of an approver who delegates to him, so that an approver who is also a
+// delegate can't fill two slots.
+func approvalTarget(users []*commonmodels.User, userID, userName string) (*commonmodels.User, error) {
+	for _, user := range users {
+		if user.RejectOrApprove != "" && decisionOperator(user) == userID {
 			return nil, fmt.Errorf("%s have %s already", userName, user.RejectOrApprove)
 		}
-		user.Comment = comment
-		user.OperationTime = time.Now().Unix()
-		if approve {
-			user.RejectOrApprove = config.Approve
-			meetUser = true
-			break
-		} else {
-			user.RejectOrApprove = config.Reject
-			meetUser = true
+	}
+
+	var target *commonmodels.User
+	for _, user := range users {
+		if user.UserID == userID {
+			target = user
 			break
 		}
 	}
-	if !meetUser {
+	// the user approves on behalf of the approver who delegates to him, if he is not an approver himself or his
+	// decision has been made by his delegate
+	if target == nil || target.RejectOrApprove != "" {
+		for _, user := range users {
+			if user.RejectOrApprove == "" && user.DelegatedTo != nil && lo.Contains(user.DelegatedTo.MemberIDs, userID) {
+				user.DelegatedTo.OperatorID = userID
+				user.DelegatedTo.OperatorName = userName
+				target = user
+				break
+			}
+		}
+	}
+	if target == nil {
 		return nil, fmt.Errorf("user %s has no authority to Approve", userName)
 	}
+	if target.RejectOrApprove != "" {
+		return nil, fmt.Errorf("%s have %s already", userName, target.RejectOrApprove)
+	}
+	return target, nil
+}
 
-	c.SetApproval(key, approvalData)
-	return approvalData, nil
+// decisionOperator returns the id of the user who made the decision of the approver
+func decisionOperator(user *commonmodels.User) string {
+	if user.DelegatedTo != nil && user.DelegatedTo.OperatorID != "" {
+		return user.DelegatedTo.OperatorID
+	}
+	return user.UserID
 }
 
 func (c *GlobalApproveManager) IsApproval(key string) (bool, int, *commonmodels.NativeApproval, error) {
@@ -117,19 +156,6 @@ func (c *GlobalApproveManager) IsApproval(key string) (bool, int, *commonmodels.
 		return false, 0, nil, fmt.Errorf("not found approval")
 	}
 
-	ApproveCount := 0
-	for _, user := range approval.ApproveUsers {
-		if user.RejectOrApprove == config.Reject {
-			approval.RejectOrApprove = config.Reject
-			return false, ApproveCount, approval, fmt.Errorf("%s reject this task", user.UserName)
-		}
-		if user.RejectOrApprove == config.Approve {
-			ApproveCount++
-		}
-	}
-	if ApproveCount >= approval.NeededApprovers {
-		approval.RejectOrApprove = config.Approve
-		return true, ApproveCount, approval, nil
-	}
-	return false, ApproveCount, approval, nil
+	approved, approveCount, err := EvaluateNativeApproval(approval)
+	return approved, approveCount, approval, err
 }
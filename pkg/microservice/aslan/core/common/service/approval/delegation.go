@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/cache"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+// RerouteApproval reroutes the pending approvers of the approval in the cache to their delegates, it returns true if
+// any approver is rerouted.
+func (c *GlobalApproveManager) RerouteApproval(key string) (bool, error) {
+	redisMutex := cache.NewRedisLock(approveLockKey(key))
+	redisMutex.Lock()
+	defer redisMutex.Unlock()
+
+	approvalData, ok := c.GetApproval(key)
+	if !ok {
+		return false, fmt.Errorf("not found approval")
+	}
+	if !ApplyApprovalDelegations(approvalData) {
+		return false, nil
+	}
+	c.SetApproval(key, approvalData)
+	return true, nil
+}
+
+// ApplyApprovalDelegations reroutes the pending approvers of the approval to the delegates in their active
+// delegations, it returns true if any approver is rerouted.
+func ApplyApprovalDelegations(approval *commonmodels.NativeApproval) bool {
+	uids := make([]string, 0)
+	for _, user := range allApproveUsers(approval) {
+		if user.RejectOrApprove == "" && user.DelegatedTo == nil && user.UserID != "" {
+			uids = append(uids, user.UserID)
+		}
+	}
+	if len(uids) == 0 {
+		return false
+	}
+
+	delegations, err := commonrepo.NewApprovalDelegationColl().ListActive(uids, time.Now().Unix())
+	if err != nil {
+		log.Errorf("failed to list active approval delegations, error: %s", err)
+		return false
+	}
+	return RerouteNativeApproval(approval, delegations, delegationMembers, time.Now().Unix())
+}
+
+// RerouteNativeApproval reroutes the pending approvers to the delegates in their active delegations, members resolves
+// the users who can approve on behalf of the approver. The original approvers are kept so that the approval is still
+// counted for them, e.g. in the quorum rules.
+func RerouteNativeApproval(approval *commonmodels.NativeApproval, delegations []*commonmodels.ApprovalDelegation, members func(*commonmodels.ApprovalDelegation) []string, now int64) bool {
+	delegationMap := make(map[string]*commonmodels.ApprovalDelegation)
+	for _, delegation := range delegations {
+		if !delegation.IsActive(now) {
+			continue
+		}
+		// the latest delegation of the user takes effect
+		if old, ok := delegationMap[delegation.UserID]; ok && old.StartTime >= delegation.StartTime {
+			continue
+		}
+		delegationMap[delegation.UserID] = delegation
+	}
+
+	rerouted := false
+	for _, user := range allApproveUsers(approval) {
+		if user.RejectOrApprove != "" || user.DelegatedTo != nil {
+			continue
+		}
+		delegation, ok := delegationMap[user.UserID]
+		if !ok {
+			continue
+		}
+		memberIDs := sets.NewString(members(delegation)...)
+		// a delegate can't approve on behalf of himself
+		memberIDs.Delete(user.UserID)
+		if memberIDs.Len() == 0 {
+			continue
+		}
+		user.DelegatedTo = &commonmodels.ApprovalDelegate{
+			DelegationID: delegation.ID.Hex(),
+			Type:         delegation.DelegateType,
+			ID:           delegation.DelegateID,
+			Name:         delegation.DelegateName,
+			MemberIDs:    memberIDs.List(),
+			RerouteTime:  now,
+		}
+		rerouted = true
+	}
+	return rerouted
+}
+
+// OnBehalfOf returns the approver the user made the latest decision for as a delegate, nil if the user made the
+// decision for himself
+func OnBehalfOf(approval *commonmodels.NativeApproval, userID string) *commonmodels.User {
+	var resp *commonmodels.User
+	for _, user := range allApproveUsers(approval) {
+		if user.DelegatedTo == nil || user.DelegatedTo.OperatorID != userID {
+			continue
+		}
+		if resp == nil || user.OperationTime > resp.OperationTime {
+			resp = user
+		}
+	}
+	return resp
+}
+
+func allApproveUsers(approval *commonmodels.NativeApproval) []*commonmodels.User {
+	users := append([]*commonmodels.User{}, approval.ApproveUsers...)
+	for _, step := range approval.Steps {
+		users = append(users, step.ApproveUsers...)
+	}
+	return users
+}
+
+func delegationMembers(delegation *commonmodels.ApprovalDelegation) []string {
+	if delegation.DelegateType != config.ApprovalDelegateTypeGroup {
+		return []string{delegation.DelegateID}
+	}
+	users, _ := util.GeneFlatUsers([]*commonmodels.User{{
+		Type:    setting.UserTypeGroup,
+		GroupID: delegation.DelegateID,
+	}})
+	resp := make([]string, 0, len(users))
+	for _, user := range users {
+		resp = append(resp, user.UserID)
+	}
+	return resp
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+func TestRerouteNativeApproval(t *testing.T) {
+	approval := &commonmodels.NativeApproval{
+		ApproveUsers: []*commonmodels.User{
+			{UserID: "a", UserName: "a"},
+			{UserID: "b", UserName: "b", RejectOrApprove: config.Approve},
+			{UserID: "c", UserName: "c"},
+		},
+		Steps: []*commonmodels.NativeApprovalStep{
+			{Name: "ops", ApproveUsers: []*commonmodels.User{{UserID: "d", UserName: "d"}}},
+		},
+	}
+	delegations := []*commonmodels.ApprovalDelegation{
+		{ID: primitive.NewObjectID(), UserID: "a", DelegateType: config.ApprovalDelegateTypeUser, DelegateID: "x", StartTime: 100, EndTime: 200},
+		// a newer delegation of a overrides the older one
+		{ID: primitive.NewObjectID(), UserID: "a", DelegateType: config.ApprovalDelegateTypeUser, DelegateID: "y", StartTime: 120, EndTime: 200},
+		// b has approved already
+		{ID: primitive.NewObjectID(), UserID: "b", DelegateType: config.ApprovalDelegateTypeUser, DelegateID: "x", StartTime: 100, EndTime: 200},
+		// the delegation of c has expired
+		{ID: primitive.NewObjectID(), UserID: "c", DelegateType: config.ApprovalDelegateTypeUser, DelegateID: "x", StartTime: 100, EndTime: 150},
+		{ID: primitive.NewObjectID(), UserID: "d", DelegateType: config.ApprovalDelegateTypeGroup, DelegateID: "ops", StartTime: 100, EndTime: 200},
+	}
+	members := func(d *commonmodels.ApprovalDelegation) []string {
+		if d.DelegateType == config.ApprovalDelegateTypeGroup {
+			return []string{"d", "e", "f"}
+		}
+		return []string{d.DelegateID}
+	}
+
+	assert.True(t, RerouteNativeApproval(approval, delegations, members, 160))
+	assert.Equal(t, "y", approval.ApproveUsers[0].DelegatedTo.ID)
+	assert.Equal(t, []string{"y"}, approval.ApproveUsers[0].DelegatedTo.MemberIDs)
+	assert.Nil(t, approval.ApproveUsers[1].DelegatedTo)
+	assert.Nil(t, approval.ApproveUsers[2].DelegatedTo)
+	// the approver himself is excluded from the members of the group
+	assert.Equal(t, []string{"e", "f"}, approval.Steps[0].ApproveUsers[0].DelegatedTo.MemberIDs)
+
+	// the rerouted approvers are not rerouted again
+	assert.False(t, RerouteNativeApproval(approval, delegations, members, 170))
+}
+
+func TestOnBehalfOf(t *testing.T) {
+	approval := &commonmodels.NativeApproval{
+		ApproveUsers: []*commonmodels.User{
+			{UserID: "a", UserName: "a", OperationTime: 10, DelegatedTo: &commonmodels.ApprovalDelegate{OperatorID: "x"}},
+			{UserID: "b", UserName: "b", OperationTime: 20, DelegatedTo: &commonmodels.ApprovalDelegate{OperatorID: "x"}},
+			{UserID: "x", UserName: "x", OperationTime: 30},
+		},
+	}
+
+	assert.Equal(t, "b", OnBehalfOf(approval, "x").UserID)
+	assert.Nil(t, OnBehalfOf(approval, "a"))
+}
+
+func TestApprovalTarget(t *testing.T) {
+	newUsers := func() []*commonmodels.User {
+		return []*commonmodels.User{
+			{UserID: "a", UserName: "a", DelegatedTo: &commonmodels.ApprovalDelegate{MemberIDs: []string{"x", "y"}}},
+			{UserID: "x", UserName: "x"},
+		}
+	}
+	decide := func(users []*commonmodels.User, userID string) error {
+		target, err := approvalTarget(users, userID, userID)
+		if err != nil {
+			return err
+		}
+		target.RejectOrApprove = config.Approve
+		return nil
+	}
+
+	// x approves himself and can't approve on behalf of a any more
+	users := newUsers()
+	assert.NoError(t, decide(users, "x"))
+	assert.Error(t, decide(users, "x"))
+	assert.Equal(t, config.ApproveOrReject(""), users[0].RejectOrApprove)
+	// the other delegate of a is still able to approve on behalf of a
+	assert.NoError(t, decide(users, "y"))
+	assert.Equal(t, "y", users[0].DelegatedTo.OperatorID)
+
+	// x is an approver himself so his own decision is made first
+	users = newUsers()
+	assert.NoError(t, decide(users, "x"))
+	assert.Equal(t, "", users[0].DelegatedTo.OperatorID)
+
+	// x approves on behalf of a after a has delegated the decision, then he can't approve himself
+	users = newUsers()
+	users[1].DelegatedTo = &commonmodels.ApprovalDelegate{MemberIDs: []string{"z"}, OperatorID: "z"}
+	users[1].RejectOrApprove = config.Approve
+	assert.NoError(t, decide(users, "x"))
+	assert.Equal(t, "x", users[0].DelegatedTo.OperatorID)
+	assert.Error(t, decide(users, "x"))
+
+	assert.Error(t, decide(newUsers(), "z"))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+// EvaluateNativeApproval checks the decisions made so far against the needed approvers, the quorum rules and the
+// approval steps. The progress of the quorum rules and steps is written back to the approval so that it can be shown
+// in the task. It returns the number of approvals of the current step and an error if the approval is rejected.
+func EvaluateNativeApproval(approval *commonmodels.NativeApproval) (bool, int, error) {
+	if len(approval.Steps) == 0 {
+		approved, count, rejecter := evaluateApprovers(approval.ApproveUsers, approval.NeededApprovers, approval.QuorumRules)
+		if rejecter != "" {
+			approval.RejectOrApprove = config.Reject
+			return false, count, fmt.Errorf("%s reject this task", rejecter)
+		}
+		if approved {
+			approval.RejectOrApprove = config.Approve
+		}
+		return approved, count, nil
+	}
+
+	for i, step := range approval.Steps {
+		approval.CurrentStep = i
+		approved, count, rejecter := evaluateApprovers(step.ApproveUsers, step.NeededApprovers, step.QuorumRules)
+		if rejecter != "" {
+			step.RejectOrApprove = config.Reject
+			approval.RejectOrApprove = config.Reject
+			return false, count, fmt.Errorf("%s reject this task at step %s", rejecter, step.Name)
+		}
+		if !approved {
+			return false, count, nil
+		}
+		step.RejectOrApprove = config.Approve
+	}
+	approval.CurrentStep = len(approval.Steps)
+	approval.RejectOrApprove = config.Approve
+	return true, 0, nil
+}
+
+// activeApproveUsers returns the users who can make a decision now, which are the users of the current step if the
+// approval is a chain of steps.
+func activeApproveUsers(approval *commonmodels.NativeApproval) []*commonmodels.User {
+	if len(approval.Steps) == 0 {
+		return approval.ApproveUsers
+	}
+	_, _, _ = EvaluateNativeApproval(approval)
+	if approval.CurrentStep >= len(approval.Steps) {
+		return nil
+	}
+	return approval.Steps[approval.CurrentStep].ApproveUsers
+}
+
+// evaluateApprovers returns whether the approvers are enough and the name of the first user who rejected, if any
+func evaluateApprovers(users []*commonmodels.User, neededApprovers int, rules []*commonmodels.ApprovalQuorumRule) (bool, int, string) {
+	approvedUsers := sets.NewString()
+	for _, user := range users {
+		if user.RejectOrApprove == config.Reject {
+			return false, approvedUsers.Len(), user.UserName
+		}
+		if user.RejectOrApprove == config.Approve {
+			approvedUsers.Insert(user.UserID)
+		}
+	}
+
+	approved := approvedUsers.Len() >= neededApprovers
+	for _, rule := range rules {
+		rule.Approved = 0
+		for _, member := range rule.MemberIDs {
+			if approvedUsers.Has(member) {
+				rule.Approved++
+			}
+		}
+		if rule.Approved < rule.MinApprovers {
+			approved = false
+		}
+	}
+	return approved, approvedUsers.Len(), ""
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+func TestEvaluateNativeApprovalQuorum(t *testing.T) {
+	approval := &commonmodels.NativeApproval{
+		ApproveUsers: []*commonmodels.User{
+			{UserID: "a", UserName: "a", RejectOrApprove: config.Approve},
+			{UserID: "b", UserName: "b", RejectOrApprove: config.Approve},
+			{UserID: "sec", UserName: "sec"},
+		},
+		NeededApprovers: 2,
+		QuorumRules: []*commonmodels.ApprovalQuorumRule{
+			{GroupID: "security", MinApprovers: 1, MemberIDs: []string{"sec"}},
+		},
+	}
+
+	approved, count, err := EvaluateNativeApproval(approval)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 0, approval.QuorumRules[0].Approved)
+
+	approval.ApproveUsers[2].RejectOrApprove = config.Approve
+	approved, _, err = EvaluateNativeApproval(approval)
+	assert.NoError(t, err)
+	assert.True(t, approved)
+	assert.Equal(t, 1, approval.QuorumRules[0].Approved)
+	assert.Equal(t, config.Approve, approval.RejectOrApprove)
+}
+
+func TestEvaluateNativeApprovalSteps(t *testing.T) {
+	approval := &commonmodels.NativeApproval{
+		Steps: []*commonmodels.NativeApprovalStep{
+			{Name: "dev", NeededApprovers: 1, ApproveUsers: []*commonmodels.User{{UserID: "a", UserName: "a"}}},
+			{Name: "ops", NeededApprovers: 1, ApproveUsers: []*commonmodels.User{{UserID: "b", UserName: "b"}}},
+		},
+	}
+
+	approved, _, err := EvaluateNativeApproval(approval)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.Equal(t, 0, approval.CurrentStep)
+	assert.Equal(t, "a", activeApproveUsers(approval)[0].UserID)
+
+	approval.Steps[0].ApproveUsers[0].RejectOrApprove = config.Approve
+	approved, _, err = EvaluateNativeApproval(approval)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.Equal(t, 1, approval.CurrentStep)
+	assert.Equal(t, "b", activeApproveUsers(approval)[0].UserID)
+
+	approval.Steps[1].ApproveUsers[0].RejectOrApprove = config.Reject
+	approved, _, err = EvaluateNativeApproval(approval)
+	assert.Error(t, err)
+	assert.False(t, approved)
+	assert.Equal(t, config.Reject, approval.RejectOrApprove)
+
+	approval.Steps[1].ApproveUsers[0].RejectOrApprove = config.Approve
+	approved, _, err = EvaluateNativeApproval(approval)
+	assert.NoError(t, err)
+	assert.True(t, approved)
+	assert.Equal(t, 2, approval.CurrentStep)
+	assert.Empty(t, activeApproveUsers(approval))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+// RecordWorkflowApproval appends a native workflow approval decision to the approval record chain.
+// The decision has already taken effect, so failures are logged instead of returned.
+// onBehalfOf is the approver the decision is made for if the user is a delegate, nil otherwise.
+func RecordWorkflowApproval(workflowName, jobName string, taskID int64, userID, userName, comment string, approve bool, onBehalfOf *commonmodels.User) {
+	projectName := ""
+	task, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+	if err != nil {
+		log.Warnf("failed to find workflow task %s-%d for approval record, err: %s", workflowName, taskID, err)
+	} else {
+		projectName = task.ProjectName
+	}
+
+	appendApprovalRecord(&commonmodels.ApprovalRecord{
+		Source:       config.ApprovalRecordSourceWorkflow,
+		ProjectName:  projectName,
+		WorkflowName: workflowName,
+		JobName:      jobName,
+		TaskID:       taskID,
+		UserID:       userID,
+		UserName:     userName,
+		Decision:     approvalDecision(approve),
+		Comment:      comment,
+	}, onBehalfOf)
+}
+
+// RecordReleasePlanApproval appends a native release plan approval decision to the approval record chain.
+func RecordReleasePlanApproval(planID, planName, userID, userName, comment string, approve bool, onBehalfOf *commonmodels.User) {
+	appendApprovalRecord(&commonmodels.ApprovalRecord{
+		Source:          config.ApprovalRecordSourceReleasePlan,
+		ReleasePlanID:   planID,
+		ReleasePlanName: planName,
+		UserID:          userID,
+		UserName:        userName,
+		Decision:        approvalDecision(approve),
+		Comment:         comment,
+	}, onBehalfOf)
+}
+
+func appendApprovalRecord(record *commonmodels.ApprovalRecord, onBehalfOf *commonmodels.User) {
+	if onBehalfOf != nil {
+		record.OnBehalfOfID = onBehalfOf.UserID
+		record.OnBehalfOfName = onBehalfOf.UserName
+	}
+	if err := commonrepo.NewApprovalRecordColl().Append(context.Background(), record); err != nil {
+		log.Errorf("failed to append approval record of %s by %s, err: %s", record.Source, record.UserName, err)
+	}
+}
+
+func approvalDecision(approve bool) config.ApproveOrReject {
+	if approve {
+		return config.Approve
+	}
+	return config.Reject
+}
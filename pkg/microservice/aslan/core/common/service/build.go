@@ -167,8 +167,9 @@ func EnsureResp(build *commonmodels.Build) {
 					ServiceName:   target.ServiceName,
 					ServiceModule: target.ServiceModule,
 				},
-				Repos: target.Repos,
-				Envs:  envs,
+				Repos:  target.Repos,
+				Envs:   envs,
+				Params: target.Params,
 			}
 			build.TargetRepos = append(build.TargetRepos, targetRepo)
 		}
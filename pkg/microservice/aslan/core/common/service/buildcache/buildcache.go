@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildcache
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/s3"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	s3tool "github.com/koderover/zadig/v2/pkg/tool/s3"
+)
+
+func newClient(s3StorageID string) (*s3.S3, *s3tool.Client, error) {
+	storage, err := s3.FindS3ById(s3StorageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find s3 storage %s: %v", s3StorageID, err)
+	}
+	forcedPathStyle := true
+	if storage.Provider == setting.ProviderSourceAli {
+		forcedPathStyle = false
+	}
+	client, err := s3tool.NewClient(storage.Endpoint, storage.Ak, storage.Sk, storage.Region, storage.Insecure, forcedPathStyle)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create s3 client: %v", err)
+	}
+	return storage, client, nil
+}
+
+// RecordLookup checks whether the cache object exists before the build job runs and counts it as a hit or a miss,
+// the modification time of the object is returned so that the upload of the job can be detected afterwards.
+func RecordLookup(record *commonmodels.BuildCache) (int64, error) {
+	storage, client, err := newClient(record.S3StorageID)
+	if err != nil {
+		return 0, err
+	}
+	object, found, err := client.StatObject(storage.Bucket, record.ObjectKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat build cache %s: %v", record.ObjectKey, err)
+	}
+
+	if err := commonrepo.NewBuildCacheColl().RecordLookup(record, found); err != nil {
+		return 0, fmt.Errorf("failed to record build cache %s: %v", record.ObjectKey, err)
+	}
+	if !found || object.LastModified == nil {
+		return 0, nil
+	}
+	return object.LastModified.Unix(), nil
+}
+
+// RecordUpload updates the size of the cache object if it's uploaded after lastModified.
+func RecordUpload(record *commonmodels.BuildCache, lastModified int64) error {
+	storage, client, err := newClient(record.S3StorageID)
+	if err != nil {
+		return err
+	}
+	object, found, err := client.StatObject(storage.Bucket, record.ObjectKey)
+	if err != nil {
+		return fmt.Errorf("failed to stat build cache %s: %v", record.ObjectKey, err)
+	}
+	if !found || object.LastModified == nil || object.LastModified.Unix() <= lastModified {
+		return nil
+	}
+
+	size := int64(0)
+	if object.ContentLength != nil {
+		size = *object.ContentLength
+	}
+	return commonrepo.NewBuildCacheColl().UpdateObjectInfo(record.S3StorageID, record.ObjectKey, size, object.LastModified.Unix())
+}
+
+// Prune removes the cache objects and their records, the total size of the removed objects is returned.
+func Prune(records []*commonmodels.BuildCache, dryRun bool) (int64, error) {
+	var freed int64
+	keys := make(map[string][]string)
+	for _, record := range records {
+		freed += record.Size
+		keys[record.S3StorageID] = append(keys[record.S3StorageID], record.ObjectKey)
+	}
+	if dryRun {
+		return freed, nil
+	}
+
+	failedStorages := make(map[string]struct{})
+	for storageID, objectKeys := range keys {
+		storage, client, err := newClient(storageID)
+		if err == nil {
+			err = client.DeleteObjects(storage.Bucket, objectKeys)
+		}
+		if err != nil {
+			log.Errorf("failed to delete build caches in s3 storage %s: %v", storageID, err)
+			failedStorages[storageID] = struct{}{}
+		}
+	}
+
+	freed = 0
+	for _, record := range records {
+		if _, ok := failedStorages[record.S3StorageID]; ok {
+			continue
+		}
+		if err := commonrepo.NewBuildCacheColl().DeleteByID(record.ID); err != nil {
+			return freed, fmt.Errorf("failed to delete build cache record %s: %v", record.ID.Hex(), err)
+		}
+		freed += record.Size
+	}
+	if len(failedStorages) > 0 {
+		return freed, fmt.Errorf("failed to delete build caches in %d s3 storages", len(failedStorages))
+	}
+	return freed, nil
+}
+
+// LastUsedTime is the last time the cache is hit or uploaded.
+func LastUsedTime(record *commonmodels.BuildCache) int64 {
+	lastUsed := record.CreateTime
+	if record.LastHitTime > lastUsed {
+		lastUsed = record.LastHitTime
+	}
+	if record.LastUpdateTime > lastUsed {
+		lastUsed = record.LastUpdateTime
+	}
+	return lastUsed
+}
+
+// SelectStale returns the caches to be evicted by the retention, the caches not used for MaxDays are evicted first,
+// then the least recently used ones are evicted until the total size is within MaxSize.
+func SelectStale(records []*commonmodels.BuildCache, retention *commonmodels.RetentionConfig, now time.Time) []*commonmodels.BuildCache {
+	if retention == nil {
+		return nil
+	}
+
+	sorted := make([]*commonmodels.BuildCache, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return LastUsedTime(sorted[i]) < LastUsedTime(sorted[j])
+	})
+
+	resp := make([]*commonmodels.BuildCache, 0)
+	remain := make([]*commonmodels.BuildCache, 0, len(sorted))
+	var totalSize int64
+	for _, record := range sorted {
+		if retention.MaxDays > 0 && LastUsedTime(record) < now.AddDate(0, 0, -retention.MaxDays).Unix() {
+			resp = append(resp, record)
+			continue
+		}
+		remain = append(remain, record)
+		totalSize += record.Size
+	}
+
+	if retention.MaxSize > 0 {
+		maxSize := retention.MaxSize * 1024 * 1024
+		for _, record := range remain {
+			if totalSize <= maxSize {
+				break
+			}
+			resp = append(resp, record)
+			totalSize -= record.Size
+		}
+	}
+	return resp
+}
+
+// Evict removes the stale caches according to the build cache retention strategy.
+func Evict(strategy *commonmodels.CapacityStrategy, dryRun bool) ([]*commonmodels.BuildCache, int64, error) {
+	records, err := commonrepo.NewBuildCacheColl().List(&commonrepo.BuildCacheListOption{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list build caches: %v", err)
+	}
+
+	stale := SelectStale(records, strategy.Retention, time.Now())
+	if len(stale) == 0 {
+		return stale, 0, nil
+	}
+	freed, err := Prune(stale, dryRun)
+	log.Infof("%d stale build caches evicted, %d bytes freed, dry run: %v", len(stale), freed, dryRun)
+	return stale, freed, err
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildtemplate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+var parameterNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidateParameters checks the parameter declarations of a build template
+func ValidateParameters(params []*commonmodels.BuildTemplateParameter, envs []*commonmodels.KeyVal) error {
+	names := make(map[string]bool)
+	for _, env := range envs {
+		names[env.Key] = true
+	}
+
+	declared := make(map[string]bool)
+	for _, param := range params {
+		if !parameterNameRegex.MatchString(param.Name) {
+			return fmt.Errorf("invalid parameter name %s, only letters, digits and underscores are allowed", param.Name)
+		}
+		if declared[param.Name] {
+			return fmt.Errorf("duplicate parameter %s", param.Name)
+		}
+		if names[param.Name] {
+			return fmt.Errorf("parameter %s conflicts with the env of the same name", param.Name)
+		}
+		declared[param.Name] = true
+
+		switch param.Type {
+		case commonmodels.BuildTemplateParameterTypeString, commonmodels.BuildTemplateParameterTypeNumber, commonmodels.BuildTemplateParameterTypeBool:
+		case commonmodels.BuildTemplateParameterTypeChoice:
+			if len(param.ChoiceOption) == 0 {
+				return fmt.Errorf("choice options of parameter %s can't be empty", param.Name)
+			}
+		default:
+			return fmt.Errorf("unsupported type %s of parameter %s", param.Type, param.Name)
+		}
+		if param.Validation != "" {
+			if _, err := regexp.Compile(param.Validation); err != nil {
+				return fmt.Errorf("invalid validation of parameter %s: %s", param.Name, err)
+			}
+		}
+		if param.Default != "" {
+			if err := validateParameterValue(param, param.Default); err != nil {
+				return fmt.Errorf("invalid default value: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+// RenderParameters resolves the values of the template parameters provided by a service module,
+// defaults are used for the missing values and the result is returned as envs consumed by the build scripts.
+func RenderParameters(params []*commonmodels.BuildTemplateParameter, values []*commonmodels.KeyVal) ([]*commonmodels.KeyVal, error) {
+	valueMap := make(map[string]string)
+	for _, kv := range values {
+		valueMap[kv.Key] = kv.Value
+	}
+
+	resp := make([]*commonmodels.KeyVal, 0, len(params))
+	for _, param := range params {
+		value, ok := valueMap[param.Name]
+		if !ok || value == "" {
+			value = param.Default
+		}
+		if value == "" {
+			if param.Required {
+				return nil, fmt.Errorf("parameter %s is required", param.Name)
+			}
+		} else if err := validateParameterValue(param, value); err != nil {
+			return nil, err
+		}
+
+		kv := &commonmodels.KeyVal{
+			Key:   param.Name,
+			Value: value,
+			Type:  commonmodels.StringType,
+		}
+		if param.Type == commonmodels.BuildTemplateParameterTypeChoice {
+			kv.Type = commonmodels.ChoiceType
+			kv.ChoiceOption = param.ChoiceOption
+		}
+		resp = append(resp, kv)
+	}
+	return resp, nil
+}
+
+func validateParameterValue(param *commonmodels.BuildTemplateParameter, value string) error {
+	switch param.Type {
+	case commonmodels.BuildTemplateParameterTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("value %s of parameter %s is not a number", value, param.Name)
+		}
+	case commonmodels.BuildTemplateParameterTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("value %s of parameter %s is not a bool", value, param.Name)
+		}
+	case commonmodels.BuildTemplateParameterTypeChoice:
+		found := false
+		for _, option := range param.ChoiceOption {
+			if option == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("value %s of parameter %s is not one of the options %v", value, param.Name, param.ChoiceOption)
+		}
+	}
+
+	if param.Validation != "" {
+		matched, err := regexp.MatchString(param.Validation, value)
+		if err != nil {
+			return fmt.Errorf("invalid validation of parameter %s: %s", param.Name, err)
+		}
+		if !matched {
+			return fmt.Errorf("value %s of parameter %s doesn't match %s", value, param.Name, param.Validation)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+func TestValidateParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []*commonmodels.BuildTemplateParameter
+		envs    []*commonmodels.KeyVal
+		wantErr bool
+	}{
+		{
+			name: "valid parameters",
+			params: []*commonmodels.BuildTemplateParameter{
+				{Name: "GO_VERSION", Type: commonmodels.BuildTemplateParameterTypeString, Default: "1.21", Validation: `^1\.\d+$`},
+				{Name: "replicas", Type: commonmodels.BuildTemplateParameterTypeNumber, Default: "3"},
+				{Name: "SKIP_TEST", Type: commonmodels.BuildTemplateParameterTypeBool, Default: "false"},
+				{Name: "ARCH", Type: commonmodels.BuildTemplateParameterTypeChoice, ChoiceOption: []string{"amd64", "arm64"}, Default: "amd64"},
+			},
+			envs: []*commonmodels.KeyVal{{Key: "IMAGE"}},
+		},
+		{
+			name:    "invalid name",
+			params:  []*commonmodels.BuildTemplateParameter{{Name: "1-version", Type: commonmodels.BuildTemplateParameterTypeString}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name",
+			params: []*commonmodels.BuildTemplateParameter{
+				{Name: "VERSION", Type: commonmodels.BuildTemplateParameterTypeString},
+				{Name: "VERSION", Type: commonmodels.BuildTemplateParameterTypeNumber},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "conflict with env",
+			params:  []*commonmodels.BuildTemplateParameter{{Name: "IMAGE", Type: commonmodels.BuildTemplateParameterTypeString}},
+			envs:    []*commonmodels.KeyVal{{Key: "IMAGE"}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			params:  []*commonmodels.BuildTemplateParameter{{Name: "VERSION", Type: "date"}},
+			wantErr: true,
+		},
+		{
+			name:    "choice without options",
+			params:  []*commonmodels.BuildTemplateParameter{{Name: "ARCH", Type: commonmodels.BuildTemplateParameterTypeChoice}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid validation",
+			params:  []*commonmodels.BuildTemplateParameter{{Name: "VERSION", Type: commonmodels.BuildTemplateParameterTypeString, Validation: "(["}},
+			wantErr: true,
+		},
+		{
+			name:    "default not a number",
+			params:  []*commonmodels.BuildTemplateParameter{{Name: "REPLICAS", Type: commonmodels.BuildTemplateParameterTypeNumber, Default: "three"}},
+			wantErr: true,
+		},
+		{
+			name:    "default not in options",
+			params:  []*commonmodels.BuildTemplateParameter{{Name: "ARCH", Type: commonmodels.BuildTemplateParameterTypeChoice, ChoiceOption: []string{"amd64"}, Default: "arm64"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParameters(tt.params, tt.envs)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRenderParameters(t *testing.T) {
+	params := []*commonmodels.BuildTemplateParameter{
+		{Name: "VERSION", Type: commonmodels.BuildTemplateParameterTypeString, Required: true, Validation: `^v\d+$`},
+		{Name: "REPLICAS", Type: commonmodels.BuildTemplateParameterTypeNumber, Default: "1"},
+		{Name: "ARCH", Type: commonmodels.BuildTemplateParameterTypeChoice, ChoiceOption: []string{"amd64", "arm64"}, Default: "amd64"},
+		{Name: "SKIP_TEST", Type: commonmodels.BuildTemplateParameterTypeBool},
+	}
+
+	tests := []struct {
+		name    string
+		values  []*commonmodels.KeyVal
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "defaults are used for missing values",
+			values: []*commonmodels.KeyVal{{Key: "VERSION", Value: "v1"}},
+			want:   map[string]string{"VERSION": "v1", "REPLICAS": "1", "ARCH": "amd64", "SKIP_TEST": ""},
+		},
+		{
+			name: "provided values override defaults",
+			values: []*commonmodels.KeyVal{
+				{Key: "VERSION", Value: "v2"},
+				{Key: "REPLICAS", Value: "3"},
+				{Key: "ARCH", Value: "arm64"},
+				{Key: "SKIP_TEST", Value: "true"},
+			},
+			want: map[string]string{"VERSION": "v2", "REPLICAS": "3", "ARCH": "arm64", "SKIP_TEST": "true"},
+		},
+		{
+			name:    "missing required value",
+			wantErr: true,
+		},
+		{
+			name:    "value doesn't match validation",
+			values:  []*commonmodels.KeyVal{{Key: "VERSION", Value: "1.0"}},
+			wantErr: true,
+		},
+		{
+			name:    "value not a number",
+			values:  []*commonmodels.KeyVal{{Key: "VERSION", Value: "v1"}, {Key: "REPLICAS", Value: "many"}},
+			wantErr: true,
+		},
+		{
+			name:    "value not a bool",
+			values:  []*commonmodels.KeyVal{{Key: "VERSION", Value: "v1"}, {Key: "SKIP_TEST", Value: "maybe"}},
+			wantErr: true,
+		},
+		{
+			name:    "value not in options",
+			values:  []*commonmodels.KeyVal{{Key: "VERSION", Value: "v1"}, {Key: "ARCH", Value: "386"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kvs, err := RenderParameters(params, tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			got := make(map[string]string)
+			for _, kv := range kvs {
+				got[kv.Key] = kv.Value
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, commonmodels.ChoiceType, kvs[2].Type)
+			assert.Equal(t, []string{"amd64", "arm64"}, kvs[2].ChoiceOption)
+		})
+	}
+}
@@ -1002,7 +1002,28 @@ type ZadigServiceStatusResp struct {
 	Workloads   []*Workload
 }
 
+// QueryPodsStatus returns the status of the service in the env, the result is served from the service status cache
+// unless the watched resources in the namespace have changed since it was computed.
 func QueryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodels.Service, serviceName string, clientset *kubernetes.Clientset, informer informers.SharedInformerFactory, log *zap.SugaredLogger) *ZadigServiceStatusResp {
+	return GetCachedServiceStatus(productInfo, ServiceStatusKindPods+"/"+serviceName, serviceTmpl, func() (interface{}, bool) {
+		return queryPodsStatus(productInfo, serviceTmpl, serviceName, clientset, informer, log)
+	}).(*ZadigServiceStatusResp)
+}
+
+// unmirrorImages restores the images pulled through the dragonfly mirror so they can be compared with the service images
+func unmirrorImages(images []string, clusterID string) []string {
+	dragonflyConfig := kube.GetDragonflyConfig(clusterID)
+	if dragonflyConfig == nil {
+		return images
+	}
+	imageSet := sets.NewString()
+	for _, image := range images {
+		imageSet.Insert(dragonflyConfig.UnmirrorImage(image))
+	}
+	return imageSet.List()
+}
+
+func queryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodels.Service, serviceName string, clientset *kubernetes.Clientset, informer informers.SharedInformerFactory, log *zap.SugaredLogger) (*ZadigServiceStatusResp, bool) {
 	resp := &ZadigServiceStatusResp{
 		ServiceName: serviceName,
 		PodStatus:   setting.PodError,
@@ -1014,7 +1035,7 @@ func QueryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodel
 	svcResp, err := GetServiceImpl(serviceTmpl.ServiceName, serviceTmpl, "", productInfo, clientset, informer, log)
 	if err != nil {
 		log.Errorf("failed to get %s service impl, error: %v", serviceTmpl.ServiceName, err)
-		return resp
+		return resp, false
 	}
 
 	resp.Ingress = svcResp.Ingress
@@ -1022,7 +1043,7 @@ func QueryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodel
 	if len(serviceTmpl.Containers) == 0 {
 		resp.PodStatus = setting.PodSucceeded
 		resp.Ready = setting.PodReady
-		return resp
+		return resp, true
 	}
 
 	suspendCronJobCount := 0
@@ -1043,9 +1064,9 @@ func QueryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodel
 			imageSet.Insert(workload.Images...)
 		}
 
-		resp.Images = imageSet.List()
+		resp.Images = unmirrorImages(imageSet.List(), productInfo.ClusterID)
 		resp.PodStatus, resp.Ready = setting.PodNonStarted, setting.PodNotReady
-		return resp
+		return resp, true
 	}
 
 	imageSet := sets.String{}
@@ -1061,7 +1082,7 @@ func QueryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodel
 		}
 	}
 
-	resp.Images = imageSet.List()
+	resp.Images = unmirrorImages(imageSet.List(), productInfo.ClusterID)
 
 	ready := setting.PodReady
 
@@ -1073,7 +1094,7 @@ func QueryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodel
 		} else {
 			resp.PodStatus = setting.ServiceStatusPartSuspended
 		}
-		return resp
+		return resp, true
 	}
 
 	succeededPods := 0
@@ -1084,15 +1105,15 @@ func QueryPodsStatus(productInfo *commonmodels.Product, serviceTmpl *commonmodel
 		}
 		if !pod.Ready {
 			resp.PodStatus, resp.Ready = setting.PodUnstable, setting.PodNotReady
-			return resp
+			return resp, true
 		}
 	}
 
 	if len(pods) == succeededPods {
 		resp.PodStatus, resp.Ready = string(corev1.PodSucceeded), setting.JobReady
-		return resp
+		return resp, true
 	}
 
 	resp.PodStatus, resp.Ready = setting.PodRunning, ready
-	return resp
+	return resp, true
 }
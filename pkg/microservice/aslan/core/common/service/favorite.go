@@ -17,12 +17,15 @@
 package service
 
 import (
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 )
 
 const (
-	FavoriteTypeEnv = "environment"
+	FavoriteTypeEnv      = "environment"
+	FavoriteTypeService  = "service"
+	FavoriteTypeWorkflow = string(config.WorkflowTypeV4)
 )
 
 func CreateFavorite(favorite *models.Favorite) error {
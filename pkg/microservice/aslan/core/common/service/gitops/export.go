@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v35/github"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	githubservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/github"
+	gitlabservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/gitlab"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/client/systemconfig"
+)
+
+type ExportParam struct {
+	Config       *commonmodels.GitOpsExportConfig
+	Production   bool
+	ProjectName  string
+	EnvName      string
+	ServiceName  string
+	WorkflowName string
+	TaskID       int64
+	Manifest     string
+}
+
+// ValidateExportConfig checks the required fields of the export config, only github and gitlab are supported
+func ValidateExportConfig(cfg *commonmodels.GitOpsExportConfig) error {
+	return ValidateRepo(cfg.CodehostID, cfg.GetRepoNamespace(), cfg.RepoName, cfg.Branch)
+}
+
+// ValidateRepo checks the repository which gitops commits to, only github and gitlab are supported
+func ValidateRepo(codehostID int, owner, repo, branch string) error {
+	if codehostID == 0 || owner == "" || repo == "" || branch == "" {
+		return fmt.Errorf("codehost, repository and branch must be provided for gitops")
+	}
+	ch, err := systemconfig.New().GetCodeHost(codehostID)
+	if err != nil {
+		return fmt.Errorf("failed to find codehost %d: %s", codehostID, err)
+	}
+	if ch.Type != setting.SourceFromGithub && ch.Type != setting.SourceFromGitlab {
+		return fmt.Errorf("gitops does not support %s repositories", ch.Type)
+	}
+	return nil
+}
+
+// Export commits the rendered manifest of the service to the git repository. In pull request mode the manifest is
+// committed to a new branch created from the configured branch, and a pull request is opened against it.
+func Export(param *ExportParam) (*commonmodels.GitOpsExportResult, error) {
+	cfg := param.Config
+	ch, err := systemconfig.New().GetCodeHost(cfg.CodehostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find codehost %d: %s", cfg.CodehostID, err)
+	}
+
+	createPR := cfg.CreatePR || param.Production
+	result := &commonmodels.GitOpsExportResult{
+		Branch:   cfg.Branch,
+		FilePath: strings.TrimPrefix(path.Join(cfg.Path, param.ServiceName+".yaml"), "/"),
+	}
+	if createPR {
+		result.Branch = fmt.Sprintf("zadig/%s-%d-%s", param.WorkflowName, param.TaskID, param.ServiceName)
+	}
+	message := fmt.Sprintf("Update %s of env %s/%s by workflow %s #%d", param.ServiceName, param.ProjectName, param.EnvName, param.WorkflowName, param.TaskID)
+	files := map[string]string{result.FilePath: param.Manifest}
+	owner := cfg.GetRepoNamespace()
+
+	result.CommitID, result.PRURL, err = commitFiles(ch, owner, cfg.RepoName, result.Branch, cfg.Branch, message, files, createPR)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// commitFiles commits the files to the branch which is created from the base branch if it does not exist, a pull
+// request from the branch to the base branch is opened if createPR is true.
+func commitFiles(ch *systemconfig.CodeHost, owner, repo, branch, baseBranch, message string, files map[string]string, createPR bool) (commitID, prURL string, err error) {
+	switch ch.Type {
+	case setting.SourceFromGithub:
+		client := githubservice.NewClient(ch.AccessToken, config.ProxyHTTPSAddr(), ch.EnableProxy)
+		commitID, err = client.CommitFiles(context.Background(), owner, repo, branch, baseBranch, message, files)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to commit to %s/%s: %s", owner, repo, err)
+		}
+		if createPR {
+			pr, err := client.CreatePullRequest(context.Background(), owner, repo, message, message, branch, baseBranch)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to create pull request for %s/%s: %s", owner, repo, err)
+			}
+			prURL = pr.GetHTMLURL()
+		}
+	case setting.SourceFromGitlab:
+		client, err := gitlabservice.NewClient(ch.ID, ch.Address, ch.AccessToken, config.ProxyHTTPSAddr(), ch.EnableProxy)
+		if err != nil {
+			return "", "", err
+		}
+		commit, err := client.CommitFiles(owner, repo, branch, baseBranch, message, files)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to commit to %s/%s: %s", owner, repo, err)
+		}
+		commitID = commit.ID
+		if createPR {
+			mr, err := client.CreateMergeRequest(owner, repo, branch, baseBranch, message, message)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to create merge request for %s/%s: %s", owner, repo, err)
+			}
+			prURL = mr.WebURL
+		}
+	default:
+		return "", "", fmt.Errorf("gitops does not support %s repositories", ch.Type)
+	}
+	return commitID, prURL, nil
+}
+
+// readFile reads the content of the file in the branch of the repository
+func readFile(ch *systemconfig.CodeHost, owner, repo, branch, filePath string) ([]byte, error) {
+	switch ch.Type {
+	case setting.SourceFromGithub:
+		client := githubservice.NewClient(ch.AccessToken, config.ProxyHTTPSAddr(), ch.EnableProxy)
+		file, _, err := client.GetContents(context.Background(), owner, repo, filePath, &github.RepositoryContentGetOptions{Ref: branch})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s from %s/%s: %s", filePath, owner, repo, err)
+		}
+		if file == nil {
+			return nil, fmt.Errorf("%s of %s/%s is not a file", filePath, owner, repo)
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, err
+		}
+		return []byte(content), nil
+	case setting.SourceFromGitlab:
+		client, err := gitlabservice.NewClient(ch.ID, ch.Address, ch.AccessToken, config.ProxyHTTPSAddr(), ch.EnableProxy)
+		if err != nil {
+			return nil, err
+		}
+		content, err := client.GetFileContent(owner, repo, filePath, branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s from %s/%s: %s", filePath, owner, repo, err)
+		}
+		return content, nil
+	default:
+		return nil, fmt.Errorf("gitops does not support %s repositories", ch.Type)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/shared/client/systemconfig"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/flux"
+)
+
+type FluxImageUpdateParam struct {
+	CodehostID   int
+	Owner        string
+	RepoName     string
+	Branch       string
+	CreatePR     bool
+	WorkflowName string
+	TaskID       int64
+	Targets      []*commonmodels.FluxImageUpdateTarget
+}
+
+type FluxImageUpdateResult struct {
+	Branch       string
+	CommitID     string
+	PRURL        string
+	ChangedFiles []string
+}
+
+// UpdateFluxImages sets the image tags in the manifests of the flux source repository and commits the changed files
+// in one commit, nothing is committed if all the tags are up to date.
+func UpdateFluxImages(param *FluxImageUpdateParam) (*FluxImageUpdateResult, error) {
+	ch, err := systemconfig.New().GetCodeHost(param.CodehostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find codehost %d: %s", param.CodehostID, err)
+	}
+
+	// the targets in the same file are applied in order on the same content
+	contents := make(map[string][]byte)
+	changedFiles := make([]string, 0)
+	files := make(map[string]string)
+	for _, target := range param.Targets {
+		filePath := strings.TrimPrefix(target.FilePath, "/")
+		content, ok := contents[filePath]
+		if !ok {
+			content, err = readFile(ch, param.Owner, param.RepoName, param.Branch, filePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var changed bool
+		switch target.Type {
+		case config.FluxImageUpdateTypeKustomize:
+			content, changed, err = flux.SetKustomizationImage(content, target.ImageName, target.Tag)
+		case config.FluxImageUpdateTypeValue:
+			content, changed, err = flux.SetValue(content, target.ValuePath, target.Tag)
+		default:
+			err = fmt.Errorf("unsupported type %s", target.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update %s: %s", filePath, err)
+		}
+		contents[filePath] = content
+
+		if changed {
+			if _, ok := files[filePath]; !ok {
+				changedFiles = append(changedFiles, filePath)
+			}
+			files[filePath] = string(content)
+		}
+	}
+
+	result := &FluxImageUpdateResult{
+		Branch:       param.Branch,
+		ChangedFiles: changedFiles,
+	}
+	if len(files) == 0 {
+		return result, nil
+	}
+
+	if param.CreatePR {
+		result.Branch = fmt.Sprintf("zadig/%s-%d-flux", param.WorkflowName, param.TaskID)
+	}
+	message := fmt.Sprintf("Update images by workflow %s #%d", param.WorkflowName, param.TaskID)
+	result.CommitID, result.PRURL, err = commitFiles(ch, param.Owner, param.RepoName, result.Branch, param.Branch, message, files, param.CreatePR)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	config2 "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/cache"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type HealthStatusType string
+
+const (
+	HealthStatusHealthy   HealthStatusType = "healthy"
+	HealthStatusDegraded  HealthStatusType = "degraded"
+	HealthStatusUnhealthy HealthStatusType = "unhealthy"
+
+	dependencyCheckTimeout = 5 * time.Second
+)
+
+type HealthStatus struct {
+	Status       HealthStatusType    `json:"status"`
+	Dependencies []*DependencyStatus `json:"dependencies"`
+}
+
+// DependencyStatus is returned by the public health APIs, the errors of the checks are only logged since they may
+// expose the addresses of the dependencies.
+type DependencyStatus struct {
+	Name string `json:"name"`
+	// Required dependencies make aslan unavailable when they are down, the others only degrade some features.
+	Required bool  `json:"required"`
+	Healthy  bool  `json:"healthy"`
+	Latency  int64 `json:"latency_ms"`
+}
+
+type dependencyChecker struct {
+	name     string
+	required bool
+	check    func(ctx context.Context) error
+}
+
+var dependencyCheckers = []*dependencyChecker{
+	{
+		name:     "mongodb",
+		required: true,
+		check:    mongotool.Ping,
+	},
+	{
+		name:     "redis",
+		required: true,
+		check: func(ctx context.Context) error {
+			return cache.NewRedisCache(config2.RedisCommonCacheTokenDB()).Ping(ctx)
+		},
+	},
+	{
+		// the user service checks its mysql databases in its healthz API, it's not required for the readiness of aslan
+		// since only the user related features are affected when it is down
+		name:     "mysql",
+		required: false,
+		check: func(ctx context.Context) error {
+			return user.New().Healthz()
+		},
+	},
+	{
+		name:     "local_cluster",
+		required: false,
+		check: func(ctx context.Context) error {
+			clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), setting.LocalClusterID)
+			if err != nil {
+				return err
+			}
+			_, err = clientset.Discovery().ServerVersion()
+			return err
+		},
+	},
+}
+
+// CheckHealth checks the dependencies of aslan concurrently, only the required ones are checked if requiredOnly is set.
+func CheckHealth(requiredOnly bool) *HealthStatus {
+	resp := &HealthStatus{
+		Status:       HealthStatusHealthy,
+		Dependencies: make([]*DependencyStatus, 0),
+	}
+
+	checkers := make([]*dependencyChecker, 0)
+	for _, checker := range dependencyCheckers {
+		if requiredOnly && !checker.required {
+			continue
+		}
+		checkers = append(checkers, checker)
+	}
+
+	statuses := make([]*DependencyStatus, len(checkers))
+	wg := sync.WaitGroup{}
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker *dependencyChecker) {
+			defer wg.Done()
+			statuses[i] = checkDependency(checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	for _, status := range statuses {
+		if !status.Healthy {
+			if status.Required {
+				resp.Status = HealthStatusUnhealthy
+			} else if resp.Status == HealthStatusHealthy {
+				resp.Status = HealthStatusDegraded
+			}
+		}
+		resp.Dependencies = append(resp.Dependencies, status)
+	}
+	return resp
+}
+
+func checkDependency(checker *dependencyChecker) *DependencyStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), dependencyCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- checker.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		log.Errorf("health check of %s failed: %s", checker.name, err)
+	}
+	return &DependencyStatus{
+		Name:     checker.name,
+		Required: checker.required,
+		Healthy:  err == nil,
+		Latency:  time.Since(start).Milliseconds(),
+	}
+}
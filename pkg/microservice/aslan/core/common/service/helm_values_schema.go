@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	helmtool "github.com/koderover/zadig/v2/pkg/tool/helmclient"
+)
+
+const chartValuesSchemaFile = "values.schema.json"
+
+// chartValuesSchemaCache caches the values.schema.json of the charts by service revision, the chart of a revision never changes
+var chartValuesSchemaCache sync.Map
+
+type HelmValuesViolation struct {
+	ServiceName string `json:"service_name"`
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// GetHelmValuesSchema returns the json schema used to validate the values of the service, an empty schema means
+// the values are not validated
+func GetHelmValuesSchema(serviceObj *commonmodels.Service, production bool) (string, error) {
+	schemaSetting, err := commonrepo.NewHelmValuesSchemaColl().Find(serviceObj.ProductName, serviceObj.ServiceName, production)
+	if err != nil && !commonrepo.IsErrNoDocuments(err) {
+		return "", err
+	}
+	if schemaSetting != nil {
+		switch schemaSetting.Source {
+		case commonmodels.HelmValuesSchemaSourceDisabled:
+			return "", nil
+		case commonmodels.HelmValuesSchemaSourceCustom:
+			return schemaSetting.Schema, nil
+		}
+	}
+	return LoadChartValuesSchema(serviceObj, production)
+}
+
+// LoadChartValuesSchema returns the values.schema.json in the chart of the service, it is empty if the chart has no schema
+func LoadChartValuesSchema(serviceObj *commonmodels.Service, production bool) (string, error) {
+	key := fmt.Sprintf("%s/%s/%d/%v", serviceObj.ProductName, serviceObj.ServiceName, serviceObj.Revision, production)
+	if schema, ok := chartValuesSchemaCache.Load(key); ok {
+		return schema.(string), nil
+	}
+
+	base := config.LocalServicePathWithRevision(serviceObj.ProductName, serviceObj.ServiceName, fmt.Sprint(serviceObj.Revision), production)
+	if err := commonutil.PreloadServiceManifestsByRevision(base, serviceObj, production); err != nil {
+		return "", fmt.Errorf("failed to load chart of service %s, err: %s", serviceObj.ServiceName, err)
+	}
+	content, err := os.ReadFile(filepath.Join(base, serviceObj.ServiceName, chartValuesSchemaFile))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	chartValuesSchemaCache.Store(key, string(content))
+	return string(content), nil
+}
+
+// CheckHelmValuesSchema checks if the schema is a valid json schema
+func CheckHelmValuesSchema(schema string) error {
+	if _, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema)); err != nil {
+		return fmt.Errorf("invalid json schema: %s", err)
+	}
+	return nil
+}
+
+// ValidateHelmValues validates the values of the service against its values schema, mergedValues are the values
+// supplied by the user which are coalesced with the values.yaml of the chart as helm does, the layers encrypted by sops
+// must be decrypted before they are merged
+func ValidateHelmValues(serviceObj *commonmodels.Service, mergedValues string, production bool) ([]*HelmValuesViolation, error) {
+	if serviceObj == nil || serviceObj.HelmChart == nil {
+		return nil, nil
+	}
+
+	schema, err := GetHelmValuesSchema(serviceObj, production)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(schema) == "" {
+		return nil, nil
+	}
+
+	fullValues, err := helmtool.MergeOverrideValues(serviceObj.HelmChart.ValuesYaml, mergedValues, "", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge values of service %s, err: %s", serviceObj.ServiceName, err)
+	}
+	valuesJSON := []byte("{}")
+	if strings.TrimSpace(fullValues) != "" {
+		valuesJSON, err = yaml.YAMLToJSON([]byte(fullValues))
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert values of service %s, err: %s", serviceObj.ServiceName, err)
+		}
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(schema), gojsonschema.NewBytesLoader(valuesJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate values of service %s, err: %s", serviceObj.ServiceName, err)
+	}
+
+	violations := make([]*HelmValuesViolation, 0)
+	for _, resultErr := range result.Errors() {
+		violations = append(violations, &HelmValuesViolation{
+			ServiceName: serviceObj.ServiceName,
+			Field:       resultErr.Field(),
+			Description: resultErr.Description(),
+		})
+	}
+	return violations, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instantmessage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+func (w *Service) SendCertExpiryNotifications(certExpiry *webhooknotify.CertExpiryNotify, notifies []*models.NotifyCtl) error {
+	if certExpiry == nil || len(certExpiry.Certs) == 0 {
+		return nil
+	}
+
+	title := fmt.Sprintf("项目 %s 有 %d 个证书即将过期", certExpiry.ProjectName, len(certExpiry.Certs))
+	lines := make([]string, 0, len(certExpiry.Certs))
+	for _, cert := range certExpiry.Certs {
+		status := fmt.Sprintf("剩余 %d 天", cert.DaysLeft)
+		if cert.DaysLeft < 0 {
+			status = "已过期"
+		}
+		lines = append(lines, fmt.Sprintf("- 环境 %s / Secret %s（%s）：%s 过期，%s",
+			cert.EnvName, cert.SecretName, strings.Join(cert.DNSNames, ","), time.Unix(cert.NotAfter, 0).Format("2006-01-02 15:04:05"), status))
+	}
+	content := strings.Join(lines, "\n")
+
+	errs := make([]string, 0)
+	for _, notify := range notifies {
+		if !notify.Enabled {
+			continue
+		}
+
+		var err error
+		switch notify.WebHookType {
+		case setting.NotifyWebHookTypeDingDing:
+			err = w.sendDingDingMessage(notify.DingDingWebHook, title, fmt.Sprintf("### %s\n%s", title, content), notify.AtMobiles, notify.IsAtAll)
+		case setting.NotifyWebHookTypeFeishu:
+			card := NewLarkCard()
+			card.SetConfig(true)
+			card.SetHeader(feishuHeaderTemplateRed, title, feiShuTagText)
+			card.AddI18NElementsZhcnFeild(content, true)
+			if certExpiry.DetailURL != "" {
+				card.AddI18NElementsZhcnAction("点击查看更多信息", certExpiry.DetailURL)
+			}
+			err = w.sendFeishuMessage(notify.FeiShuWebHook, card)
+			if err == nil {
+				err = w.sendFeishuMessageOfSingleType("", notify.FeiShuWebHook, getNotifyAtContent(notify))
+			}
+		case setting.NotifyWebHookTypeMail:
+			err = w.sendMailMessage(title, strings.ReplaceAll(content, "\n", "<br>"), notify.MailUsers)
+		case setting.NotifyWebHookTypeWebook:
+			err = webhooknotify.NewClient(notify.WebHookNotify.Address, notify.WebHookNotify.Token).SendCertExpiryWebhook(certExpiry)
+		default:
+			err = w.SendWeChatWorkMessage(weChatTextTypeMarkdown, notify.WeChatWebHook, fmt.Sprintf("### %s\n%s", title, content))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", notify.WebHookType, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send cert expiry notifications: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
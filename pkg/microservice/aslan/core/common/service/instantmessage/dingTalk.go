@@ -45,6 +45,5 @@ func (w *Service) sendDingDingMessage(uri, title, content string, atMobiles []st
 		IsAtAll:   isAtAll,
 	}
 
-	_, err := w.SendMessageRequest(uri, message)
-	return err
+	return w.sendRobotMessage(uri, message)
 }
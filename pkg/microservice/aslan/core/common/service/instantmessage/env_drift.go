@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instantmessage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+func (w *Service) SendEnvDriftNotifications(envDrift *webhooknotify.EnvDriftNotify, notifies []*models.NotifyCtl) error {
+	if envDrift == nil || len(envDrift.Resources) == 0 {
+		return nil
+	}
+
+	title := fmt.Sprintf("项目 %s 环境 %s 检测到 %d 个资源配置漂移", envDrift.ProjectName, envDrift.EnvName, len(envDrift.Resources))
+	lines := make([]string, 0, len(envDrift.Resources))
+	for _, resource := range envDrift.Resources {
+		lines = append(lines, fmt.Sprintf("- 服务 %s / %s %s：%s", resource.ServiceName, resource.Kind, resource.Name, strings.Join(resource.Fields, ",")))
+	}
+	content := strings.Join(lines, "\n")
+
+	errs := make([]string, 0)
+	for _, notify := range notifies {
+		if !notify.Enabled {
+			continue
+		}
+
+		var err error
+		if notify.WebHookType == setting.NotifyWebHookTypeWebook {
+			err = webhooknotify.NewClient(notify.WebHookNotify.Address, notify.WebHookNotify.Token).SendEnvDriftWebhook(envDrift)
+		} else {
+			err = w.SendTextNotification(title, content, envDrift.DetailURL, notify)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", notify.WebHookType, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send env drift notifications: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
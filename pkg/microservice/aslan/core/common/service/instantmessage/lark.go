@@ -179,8 +179,7 @@ func (w *Service) sendFeishuMessage(uri string, lcMsg *LarkCard) error {
 		MsgType: feishuCardType,
 		Card:    lcMsg,
 	}
-	_, err := w.SendMessageRequest(uri, message)
-	return err
+	return w.sendRobotMessage(uri, message)
 }
 
 func (w *Service) sendFeishuMessageOfSingleType(title, uri, content string) error {
@@ -200,8 +199,7 @@ func (w *Service) sendFeishuMessageOfSingleType(title, uri, content string) erro
 			},
 		}
 	}
-	_, err := w.SendMessageRequest(uri, message)
-	return err
+	return w.sendRobotMessage(uri, message)
 }
 
 func getColorTemplateWithStatus(status config.Status) string {
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instantmessage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/juju/ratelimit"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const (
+	notificationQueueSize    = 1000
+	notificationMaxBatchSize = 10
+	notificationMaxRetries   = 3
+	notificationBatchDivider = "\n\n---\n\n"
+	// notificationDeliveryRetentionDays is the number of days the delivery records are kept
+	notificationDeliveryRetentionDays = 30
+)
+
+// channelLimit is the rate limit of one channel, the limits of the IM robots are
+// dingtalk: 20/min, wechat work: 20/min, feishu: 100/min (2 requests for each notification), telegram: 20/min in a group.
+type channelLimit struct {
+	perMinute int
+	burst     int64
+	// maxBatchContent is the max length of the merged content, 0 means the notifications can not be merged
+	maxBatchContent int
+}
+
+var channelLimits = map[setting.NotifyWebHookType]channelLimit{
+	setting.NotifyWebHookTypeDingDing:   {perMinute: 18, burst: 5, maxBatchContent: 18000},
+	setting.NotifyWebHookTypeWechatWork: {perMinute: 18, burst: 5, maxBatchContent: 4000},
+	setting.NotifyWebHookTypeTelegram:   {perMinute: 18, burst: 5, maxBatchContent: telegramMaxTextRunes},
+	setting.NotifyWebHookTypeFeishu:     {perMinute: 45, burst: 5},
+	setting.NotifyWebHookTypeMail:       {perMinute: 60, burst: 10},
+	setting.NotifyWebHookTypeWebook:     {perMinute: 120, burst: 20},
+}
+
+// NotificationSource is the workflow task the notification belongs to, it is saved in the delivery record.
+type NotificationSource struct {
+	ProjectName  string
+	WorkflowName string
+	TaskID       int64
+	JobName      string
+}
+
+type queuedNotification struct {
+	deliveryID    primitive.ObjectID
+	title         string
+	content       string
+	notify        *models.NotifyCtl
+	card          *LarkCard
+	webhookNotify *webhooknotify.WorkflowNotify
+}
+
+// notificationChannel is the queue of one webhook, the notifications are sent one by one under the rate limit.
+// The notifications piled up while waiting for the limit are merged into one message if the channel supports it.
+type notificationChannel struct {
+	limit  channelLimit
+	bucket *ratelimit.Bucket
+	queue  chan *queuedNotification
+}
+
+var notificationChannels sync.Map
+
+func notificationChannelKey(notify *models.NotifyCtl) string {
+	switch notify.WebHookType {
+	case setting.NotifyWebHookTypeDingDing:
+		return fmt.Sprintf("%s/%s", notify.WebHookType, notify.DingDingWebHook)
+	case setting.NotifyWebHookTypeFeishu:
+		return fmt.Sprintf("%s/%s", notify.WebHookType, notify.FeiShuWebHook)
+	case setting.NotifyWebHookTypeTelegram:
+		return fmt.Sprintf("%s/%s/%s", notify.WebHookType, notify.TelegramBotToken, notify.TelegramChatID)
+	case setting.NotifyWebHookTypeWebook:
+		return fmt.Sprintf("%s/%s", notify.WebHookType, notify.WebHookNotify.Address)
+	case setting.NotifyWebHookTypeMail:
+		return string(notify.WebHookType)
+	default:
+		return fmt.Sprintf("%s/%s", setting.NotifyWebHookTypeWechatWork, notify.WeChatWebHook)
+	}
+}
+
+func (w *Service) getNotificationChannel(notify *models.NotifyCtl) *notificationChannel {
+	key := notificationChannelKey(notify)
+	if ch, ok := notificationChannels.Load(key); ok {
+		return ch.(*notificationChannel)
+	}
+
+	limit, ok := channelLimits[notify.WebHookType]
+	if !ok {
+		limit = channelLimits[setting.NotifyWebHookTypeWechatWork]
+	}
+	ch := &notificationChannel{
+		limit:  limit,
+		bucket: ratelimit.NewBucketWithRate(float64(limit.perMinute)/60, limit.burst),
+		queue:  make(chan *queuedNotification, notificationQueueSize),
+	}
+	actual, loaded := notificationChannels.LoadOrStore(key, ch)
+	if !loaded {
+		// the robots' responses are checked for the queued notifications so the rejected ones are retried
+		sender := *w
+		sender.checkRobotResponse = true
+		go sender.runNotificationChannel(ch)
+	}
+	return actual.(*notificationChannel)
+}
+
+// enqueueNotification records the notification and puts it into the queue of its channel, it never blocks the caller.
+func (w *Service) enqueueNotification(source *NotificationSource, title, content string, notify *models.NotifyCtl, card *LarkCard, webhookNotify *webhooknotify.WorkflowNotify) {
+	delivery := &models.NotificationDelivery{
+		ProjectName:  source.ProjectName,
+		WorkflowName: source.WorkflowName,
+		TaskID:       source.TaskID,
+		JobName:      source.JobName,
+		WebHookType:  notify.WebHookType,
+		Title:        title,
+		Status:       models.NotificationDeliveryStatusQueued,
+	}
+	if err := w.notificationDeliveryColl.Create(delivery); err != nil {
+		log.Warnf("failed to create notification delivery of workflow %s task %d, err: %s", source.WorkflowName, source.TaskID, err)
+	}
+
+	msg := &queuedNotification{
+		deliveryID:    delivery.ID,
+		title:         title,
+		content:       content,
+		notify:        notify,
+		card:          card,
+		webhookNotify: webhookNotify,
+	}
+	select {
+	case w.getNotificationChannel(notify).queue <- msg:
+	default:
+		log.Errorf("notification queue of %s is full, drop the notification of workflow %s task %d", notify.WebHookType, source.WorkflowName, source.TaskID)
+		w.updateDeliveries([]*queuedNotification{msg}, models.NotificationDeliveryStatusFailed, 0, "notification queue is full")
+	}
+}
+
+func (w *Service) runNotificationChannel(ch *notificationChannel) {
+	var pending *queuedNotification
+	for {
+		msg := pending
+		pending = nil
+		if msg == nil {
+			msg = <-ch.queue
+		}
+		ch.bucket.Wait(1)
+
+		batch := []*queuedNotification{msg}
+		if ch.limit.maxBatchContent > 0 {
+			batch, pending = ch.drain(batch)
+		}
+		w.deliverNotifications(ch, batch)
+	}
+}
+
+// drain takes the notifications piled up in the queue until the batch is full,
+// the one that overflows the content limit is returned to start the next batch.
+func (ch *notificationChannel) drain(batch []*queuedNotification) ([]*queuedNotification, *queuedNotification) {
+	length := len(batch[0].content)
+	for len(batch) < notificationMaxBatchSize {
+		select {
+		case msg := <-ch.queue:
+			if length+len(notificationBatchDivider)+len(msg.content) > ch.limit.maxBatchContent {
+				return batch, msg
+			}
+			length += len(notificationBatchDivider) + len(msg.content)
+			batch = append(batch, msg)
+		default:
+			return batch, nil
+		}
+	}
+	return batch, nil
+}
+
+func (w *Service) deliverNotifications(ch *notificationChannel, batch []*queuedNotification) {
+	title, content, notify := mergeNotifications(batch)
+	first := batch[0]
+
+	attempts := 0
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = 2 * time.Second
+	bo.MaxElapsedTime = 2 * time.Minute
+	err := backoff.Retry(func() error {
+		attempts++
+		if attempts > 1 {
+			ch.bucket.Wait(1)
+		}
+		return w.sendNotification(title, content, notify, first.card, first.webhookNotify)
+	}, backoff.WithMaxRetries(bo, notificationMaxRetries))
+
+	if err != nil {
+		log.Errorf("failed to send %d %s notification(s) after %d attempts, err: %s", len(batch), notify.WebHookType, attempts, err)
+		w.updateDeliveries(batch, models.NotificationDeliveryStatusFailed, attempts, err.Error())
+		return
+	}
+	w.updateDeliveries(batch, models.NotificationDeliveryStatusSuccess, attempts, "")
+}
+
+// mergeNotifications merges the contents of the batch into one message, the mentioned users are merged as well.
+func mergeNotifications(batch []*queuedNotification) (string, string, *models.NotifyCtl) {
+	first := batch[0]
+	if len(batch) == 1 {
+		return first.title, first.content, first.notify
+	}
+
+	notify := *first.notify
+	contents := make([]string, 0, len(batch))
+	atMobiles := make([]string, 0)
+	mobileSet := make(map[string]struct{})
+	for _, msg := range batch {
+		contents = append(contents, msg.content)
+		notify.IsAtAll = notify.IsAtAll || msg.notify.IsAtAll
+		for _, mobile := range msg.notify.AtMobiles {
+			if _, ok := mobileSet[mobile]; ok {
+				continue
+			}
+			mobileSet[mobile] = struct{}{}
+			atMobiles = append(atMobiles, mobile)
+		}
+	}
+	notify.AtMobiles = atMobiles
+
+	return fmt.Sprintf("%s 等 %d 条通知", first.title, len(batch)), strings.Join(contents, notificationBatchDivider), &notify
+}
+
+func (w *Service) updateDeliveries(batch []*queuedNotification, status models.NotificationDeliveryStatus, attempts int, errMsg string) {
+	for _, msg := range batch {
+		if msg.deliveryID.IsZero() {
+			continue
+		}
+		if err := w.notificationDeliveryColl.UpdateResult(msg.deliveryID, status, attempts, len(batch), errMsg); err != nil {
+			log.Warnf("failed to update notification delivery %s, err: %s", msg.deliveryID.Hex(), err)
+		}
+	}
+}
+
+// ListNotificationDeliveries returns the delivery records of the notifications of the workflow task.
+func ListNotificationDeliveries(workflowName string, taskID int64) ([]*models.NotificationDelivery, error) {
+	return mongodb.NewNotificationDeliveryColl().List(&mongodb.NotificationDeliveryListOption{
+		WorkflowName: workflowName,
+		TaskID:       taskID,
+	})
+}
+
+// CleanNotificationDeliveries deletes the outdated delivery records, it is expected to be called daily.
+func CleanNotificationDeliveries() {
+	err := mongodb.NewNotificationDeliveryColl().DeleteBefore(time.Now().AddDate(0, 0, -notificationDeliveryRetentionDays).Unix())
+	if err != nil {
+		log.Errorf("failed to delete outdated notification deliveries: %s", err)
+	}
+}
+
+func notificationSourceOfTask(task *models.WorkflowTask, jobName string) *NotificationSource {
+	return &NotificationSource{
+		ProjectName:  task.ProjectName,
+		WorkflowName: task.WorkflowName,
+		TaskID:       task.TaskID,
+		JobName:      jobName,
+	}
+}
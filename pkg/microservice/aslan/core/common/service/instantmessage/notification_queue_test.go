@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instantmessage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+func newTestNotificationChannel(maxBatchContent int, contents ...string) *notificationChannel {
+	ch := &notificationChannel{
+		limit: channelLimit{maxBatchContent: maxBatchContent},
+		queue: make(chan *queuedNotification, notificationQueueSize),
+	}
+	for _, content := range contents {
+		ch.queue <- &queuedNotification{content: content, notify: &models.NotifyCtl{}}
+	}
+	return ch
+}
+
+func batchContents(batch []*queuedNotification) []string {
+	resp := make([]string, 0, len(batch))
+	for _, msg := range batch {
+		resp = append(resp, msg.content)
+	}
+	return resp
+}
+
+func TestNotificationChannelDrain(t *testing.T) {
+	first := &queuedNotification{content: "first", notify: &models.NotifyCtl{}}
+
+	t.Run("empty queue", func(t *testing.T) {
+		ch := newTestNotificationChannel(1000)
+		batch, pending := ch.drain([]*queuedNotification{first})
+		assert.Equal(t, []string{"first"}, batchContents(batch))
+		assert.Nil(t, pending)
+	})
+
+	t.Run("piled up notifications are batched", func(t *testing.T) {
+		ch := newTestNotificationChannel(1000, "second", "third")
+		batch, pending := ch.drain([]*queuedNotification{first})
+		assert.Equal(t, []string{"first", "second", "third"}, batchContents(batch))
+		assert.Nil(t, pending)
+		assert.Empty(t, ch.queue)
+	})
+
+	t.Run("batch size is limited", func(t *testing.T) {
+		contents := make([]string, 0)
+		for i := 0; i < notificationMaxBatchSize+2; i++ {
+			contents = append(contents, "n")
+		}
+		ch := newTestNotificationChannel(1000, contents...)
+		batch, pending := ch.drain([]*queuedNotification{first})
+		assert.Len(t, batch, notificationMaxBatchSize)
+		assert.Nil(t, pending)
+		// the rest are kept in the queue for the next batch
+		assert.Len(t, ch.queue, 3)
+	})
+
+	t.Run("overflowing notification is carried over", func(t *testing.T) {
+		limit := len("first") + len(notificationBatchDivider) + len("second")
+		ch := newTestNotificationChannel(limit, "second", "third", "fourth")
+		batch, pending := ch.drain([]*queuedNotification{first})
+		assert.Equal(t, []string{"first", "second"}, batchContents(batch))
+		if assert.NotNil(t, pending) {
+			assert.Equal(t, "third", pending.content)
+		}
+		assert.Len(t, ch.queue, 1)
+
+		// the carried over notification starts the next batch
+		batch, pending = ch.drain([]*queuedNotification{pending})
+		assert.Equal(t, []string{"third", "fourth"}, batchContents(batch))
+		assert.Nil(t, pending)
+	})
+}
+
+func TestMergeNotifications(t *testing.T) {
+	t.Run("single notification is kept as is", func(t *testing.T) {
+		notify := &models.NotifyCtl{AtMobiles: []string{"1"}}
+		title, content, merged := mergeNotifications([]*queuedNotification{{title: "title", content: "content", notify: notify}})
+		assert.Equal(t, "title", title)
+		assert.Equal(t, "content", content)
+		assert.Same(t, notify, merged)
+	})
+
+	t.Run("contents and mentions are merged", func(t *testing.T) {
+		firstNotify := &models.NotifyCtl{AtMobiles: []string{"1", "2"}}
+		batch := []*queuedNotification{
+			{title: "task 1", content: "a", notify: firstNotify},
+			{title: "task 2", content: "b", notify: &models.NotifyCtl{AtMobiles: []string{"2", "3"}}},
+			{title: "task 3", content: "c", notify: &models.NotifyCtl{IsAtAll: true}},
+		}
+
+		title, content, merged := mergeNotifications(batch)
+		assert.Equal(t, "task 1 等 3 条通知", title)
+		assert.Equal(t, strings.Join([]string{"a", "b", "c"}, notificationBatchDivider), content)
+		assert.Equal(t, []string{"1", "2", "3"}, merged.AtMobiles)
+		assert.True(t, merged.IsAtAll)
+		// the notify of the first notification is not modified
+		assert.Equal(t, []string{"1", "2"}, firstNotify.AtMobiles)
+		assert.False(t, firstNotify.IsAtAll)
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instantmessage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+func (w *Service) SendRegistryCredentialNotifications(registryCredential *webhooknotify.RegistryCredentialNotify, notifies []*models.NotifyCtl) error {
+	if registryCredential == nil {
+		return nil
+	}
+
+	registry := registryCredential.RegAddr
+	if registryCredential.Namespace != "" {
+		registry = fmt.Sprintf("%s/%s", registryCredential.RegAddr, registryCredential.Namespace)
+	}
+	var title, content string
+	if !registryCredential.Valid {
+		title = fmt.Sprintf("镜像仓库 %s 的凭证已失效", registry)
+		content = fmt.Sprintf("- 镜像仓库：%s\n- 错误信息：%s", registry, registryCredential.Message)
+	} else {
+		title = fmt.Sprintf("镜像仓库 %s 的凭证即将过期", registry)
+		content = fmt.Sprintf("- 镜像仓库：%s\n- 过期时间：%s，剩余 %d 天", registry,
+			time.Unix(registryCredential.ExpireTime, 0).Format("2006-01-02 15:04:05"), registryCredential.DaysLeft)
+	}
+
+	errs := make([]string, 0)
+	for _, notify := range notifies {
+		if !notify.Enabled {
+			continue
+		}
+
+		var err error
+		if notify.WebHookType == setting.NotifyWebHookTypeWebook {
+			err = webhooknotify.NewClient(notify.WebHookNotify.Address, notify.WebHookNotify.Token).SendRegistryCredentialWebhook(registryCredential)
+		} else {
+			err = w.SendTextNotification(title, content, registryCredential.DetailURL, notify)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", notify.WebHookType, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send registry credential notifications: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package instantmessage
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"sort"
@@ -64,6 +65,10 @@ type Service struct {
 	workflowV4Coll     *mongodb.WorkflowV4Coll
 	workflowTaskV4Coll *mongodb.WorkflowTaskv4Coll
 	scanningColl       *mongodb.ScanningColl
+
+	notificationDeliveryColl *mongodb.NotificationDeliveryColl
+	// checkRobotResponse makes the robot messages fail on the error codes in the response
+	checkRobotResponse bool
 }
 
 func NewWeChatClient() *Service {
@@ -76,6 +81,8 @@ func NewWeChatClient() *Service {
 		workflowV4Coll:     mongodb.NewWorkflowV4Coll(),
 		workflowTaskV4Coll: mongodb.NewworkflowTaskv4Coll(),
 		scanningColl:       mongodb.NewScanningColl(),
+
+		notificationDeliveryColl: mongodb.NewNotificationDeliveryColl(),
 	}
 }
 
@@ -108,6 +115,42 @@ func (w *Service) SendMessageRequest(uri string, message interface{}) ([]byte, e
 	return res.Body(), nil
 }
 
+// imResponse is the common response of the dingtalk, wechat work and feishu robots,
+// they respond 200 even if the message is rejected, e.g. for hitting the rate limit.
+type imResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+}
+
+// sendRobotMessage sends the message to the dingtalk, wechat work or feishu robot, the response is only
+// checked if checkRobotResponse is set, the direct senders keep ignoring it as before.
+func (w *Service) sendRobotMessage(uri string, message interface{}) error {
+	body, err := w.SendMessageRequest(uri, message)
+	if err != nil {
+		return err
+	}
+	if !w.checkRobotResponse {
+		return nil
+	}
+	return checkIMResponse(body)
+}
+
+func checkIMResponse(body []byte) error {
+	resp := &imResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("errcode: %d, errmsg: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("code: %d, msg: %s", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
 // @note pipeline notification, deprecated
 func (w *Service) SendInstantMessage(task *task.Task, testTaskStatusChanged, scanningTaskStatusChanged bool) error {
 	var notifyCtls []*models.NotifyCtl
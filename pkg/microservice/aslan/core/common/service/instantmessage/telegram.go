@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instantmessage
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	telegramAPIAddress   = "https://api.telegram.org"
+	telegramParseModeMD  = "Markdown"
+	telegramMaxTextRunes = 4096
+)
+
+var (
+	telegramHeadingRegexp = regexp.MustCompile(`(?m)^\s*#{1,6}\s*`)
+	telegramRuleRegexp    = regexp.MustCompile(`(?m)^\s*---\s*$`)
+	telegramLinkRegexp    = regexp.MustCompile(`\[[^\]]*\]\([^)]*\)`)
+)
+
+type TelegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (w *Service) sendTelegramMessage(botToken, chatID, content string) error {
+	if botToken == "" || chatID == "" {
+		return fmt.Errorf("telegram bot token and chat id are required")
+	}
+
+	message := &TelegramMessage{
+		ChatID:                chatID,
+		Text:                  toTelegramMarkdown(content),
+		ParseMode:             telegramParseModeMD,
+		DisableWebPagePreview: true,
+	}
+
+	uri := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIAddress, botToken)
+	body, err := w.SendMessageRequest(uri, message)
+	if err != nil {
+		return fmt.Errorf("failed to send telegram message to chat %s, err: %s", chatID, err)
+	}
+
+	resp := &telegramResponse{}
+	if err := json.Unmarshal(body, resp); err == nil && !resp.OK {
+		return fmt.Errorf("failed to send telegram message to chat %s, err: %s", chatID, resp.Description)
+	}
+	return nil
+}
+
+// toTelegramMarkdown converts the markdown built for dingding/wechat into the legacy markdown telegram accepts:
+// headings and rules are dropped, **bold** becomes *bold* and underscores outside links are escaped.
+func toTelegramMarkdown(content string) string {
+	content = telegramHeadingRegexp.ReplaceAllString(content, "")
+	content = telegramRuleRegexp.ReplaceAllString(content, "")
+	content = strings.ReplaceAll(content, "**", "*")
+
+	var builder strings.Builder
+	last := 0
+	for _, loc := range telegramLinkRegexp.FindAllStringIndex(content, -1) {
+		builder.WriteString(escapeTelegramText(content[last:loc[0]]))
+		builder.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	builder.WriteString(escapeTelegramText(content[last:]))
+
+	text := strings.TrimSpace(builder.String())
+	if runes := []rune(text); len(runes) > telegramMaxTextRunes {
+		text = string(runes[:telegramMaxTextRunes])
+	}
+	return text
+}
+
+func escapeTelegramText(text string) string {
+	return strings.NewReplacer("_", "\\_", "`", "\\`").Replace(text)
+}
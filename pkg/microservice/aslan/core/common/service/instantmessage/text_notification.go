@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instantmessage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+// SendTextNotification sends a plain markdown message to the IM/mail configured in the notify ctl,
+// webhook notifications carry structured payloads and are not supported here.
+func (w *Service) SendTextNotification(title, content, detailURL string, notify *models.NotifyCtl) error {
+	if notify == nil || !notify.Enabled {
+		return nil
+	}
+
+	switch notify.WebHookType {
+	case setting.NotifyWebHookTypeDingDing:
+		return w.sendDingDingMessage(notify.DingDingWebHook, title, fmt.Sprintf("### %s\n%s", title, content), notify.AtMobiles, notify.IsAtAll)
+	case setting.NotifyWebHookTypeFeishu:
+		card := NewLarkCard()
+		card.SetConfig(true)
+		card.SetHeader(feishuHeaderTemplateRed, title, feiShuTagText)
+		card.AddI18NElementsZhcnFeild(content, true)
+		if detailURL != "" {
+			card.AddI18NElementsZhcnAction("点击查看更多信息", detailURL)
+		}
+		if err := w.sendFeishuMessage(notify.FeiShuWebHook, card); err != nil {
+			return err
+		}
+		return w.sendFeishuMessageOfSingleType("", notify.FeiShuWebHook, getNotifyAtContent(notify))
+	case setting.NotifyWebHookTypeMail:
+		return w.sendMailMessage(title, strings.ReplaceAll(content, "\n", "<br>"), notify.MailUsers)
+	case setting.NotifyWebHookTypeTelegram:
+		return w.sendTelegramMessage(notify.TelegramBotToken, notify.TelegramChatID, fmt.Sprintf("### %s\n%s", title, content))
+	case setting.NotifyWebHookTypeWebook:
+		return fmt.Errorf("webhook notification is not supported for message: %s", title)
+	default:
+		return w.SendWeChatWorkMessage(weChatTextTypeMarkdown, notify.WeChatWebHook, fmt.Sprintf("### %s\n%s", title, content))
+	}
+}
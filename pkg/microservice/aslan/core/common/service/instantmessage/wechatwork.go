@@ -67,6 +67,5 @@ func (w *Service) SendWeChatWorkMessage(textType TextType, uri, content string)
 		return fmt.Errorf("SendWeChatWorkMessage err:%s", "WeChatWork textType is invalid")
 	}
 
-	_, err := w.SendMessageRequest(uri, message)
-	return err
+	return w.sendRobotMessage(uri, message)
 }
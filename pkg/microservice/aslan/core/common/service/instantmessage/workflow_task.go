@@ -92,9 +92,7 @@ func (w *Service) SendWorkflowTaskApproveNotifications(workflowName string, task
 			}
 		}
 
-		if err := w.sendNotification(title, content, notify, larkCard, webhookNotify); err != nil {
-			log.Errorf("failed to send notification, err: %s", err)
-		}
+		w.enqueueNotification(notificationSourceOfTask(task, ""), title, content, notify, larkCard, webhookNotify)
 	}
 	return nil
 }
@@ -133,30 +131,79 @@ func (w *Service) SendWorkflowTaskNotifications(task *models.WorkflowTask) error
 			}
 
 			if notify.WebHookType == setting.NotifyWebHookTypeMail {
-				if task.TaskCreatorID != "" {
-					for _, user := range notify.MailUsers {
-						if user.Type == setting.UserTypeTaskCreator {
-							userInfo, err := userclient.New().GetUserByID(task.TaskCreatorID)
-							if err != nil {
-								log.Errorf("failed to find user %s, error: %s", task.TaskCreatorID, err)
-								break
-							}
-							user.Type = setting.UserTypeUser
-							user.UserID = userInfo.Uid
-							user.UserName = userInfo.Name
-							break
-						}
-					}
-				}
+				setTaskCreatorMailUser(notify, task)
 			}
 
-			if err := w.sendNotification(title, content, notify, larkCard, webhookNotify); err != nil {
-				log.Errorf("failed to send notification, err: %s", err)
+			w.enqueueNotification(notificationSourceOfTask(task, ""), title, content, notify, larkCard, webhookNotify)
+		}
+	}
+	return nil
+}
+
+// SendWorkflowJobNotifications sends the notifications configured on the jobs of the task,
+// the content only contains the job itself so that each channel gets the jobs it cares about.
+func (w *Service) SendWorkflowJobNotifications(task *models.WorkflowTask) error {
+	if task.TaskID <= 0 {
+		return nil
+	}
+	for _, stage := range task.Stages {
+		for _, job := range stage.Jobs {
+			for _, notify := range job.NotifyCtls {
+				if !notify.Enabled {
+					continue
+				}
+				if !sets.NewString(notify.NotifyTypes...).Has(string(job.Status)) {
+					continue
+				}
+
+				jobTask := *task
+				jobTask.Status = job.Status
+				jobTask.StartTime = job.StartTime
+				jobTask.EndTime = job.EndTime
+				jobTask.Error = job.Error
+				jobTask.Stages = []*models.StageTask{{
+					Name:      stage.Name,
+					Status:    job.Status,
+					StartTime: job.StartTime,
+					EndTime:   job.EndTime,
+					Error:     job.Error,
+					Jobs:      []*models.JobTask{job},
+				}}
+				title, content, larkCard, webhookNotify, err := w.getNotificationContent(notify, &jobTask)
+				if err != nil {
+					log.Errorf("failed to get notification content of job %s, err: %s", job.Name, err)
+					continue
+				}
+
+				if notify.WebHookType == setting.NotifyWebHookTypeMail {
+					setTaskCreatorMailUser(notify, task)
+				}
+
+				w.enqueueNotification(notificationSourceOfTask(task, job.Name), title, content, notify, larkCard, webhookNotify)
 			}
 		}
 	}
 	return nil
 }
+
+func setTaskCreatorMailUser(notify *models.NotifyCtl, task *models.WorkflowTask) {
+	if task.TaskCreatorID == "" {
+		return
+	}
+	for _, user := range notify.MailUsers {
+		if user.Type == setting.UserTypeTaskCreator {
+			userInfo, err := userclient.New().GetUserByID(task.TaskCreatorID)
+			if err != nil {
+				log.Errorf("failed to find user %s, error: %s", task.TaskCreatorID, err)
+				break
+			}
+			user.Type = setting.UserTypeUser
+			user.UserID = userInfo.Uid
+			user.UserName = userInfo.Name
+			break
+		}
+	}
+}
 func (w *Service) getApproveNotificationContent(notify *models.NotifyCtl, task *models.WorkflowTask) (string, string, *LarkCard, *webhooknotify.WorkflowNotify, error) {
 	workflowNotification := &workflowTaskNotification{
 		Task:               task,
@@ -171,6 +218,7 @@ func (w *Service) getApproveNotificationContent(notify *models.NotifyCtl, task *
 		WorkflowDisplayName: task.WorkflowDisplayName,
 		ProjectName:         task.ProjectName,
 		Status:              task.Status,
+		TaskName:            task.TaskName,
 		Remark:              task.Remark,
 		Error:               task.Error,
 		CreateTime:          task.CreateTime,
@@ -180,19 +228,26 @@ func (w *Service) getApproveNotificationContent(notify *models.NotifyCtl, task *
 		TaskCreatorID:       task.TaskCreatorID,
 		TaskCreatorPhone:    task.TaskCreatorPhone,
 		TaskCreatorEmail:    task.TaskCreatorEmail,
+		RunAs:               task.RunAs,
+	}
+	if task.Risk != nil {
+		webhookNotify.RiskLevel = string(task.Risk.Level)
+		webhookNotify.RiskScore = task.Risk.Score
 	}
 
-	tplTitle := "{{if ne .WebHookType \"feishu\"}}#### {{end}}{{getIcon .Task.Status }}{{if eq .WebHookType \"wechat\"}}<font color=\"markdownColorInfo\">工作流{{.Task.WorkflowDisplayName}} #{{.Task.TaskID}} 等待审批</font>{{else}}工作流 {{.Task.WorkflowDisplayName}} #{{.Task.TaskID}} 等待审批{{end}} \n"
-	mailTplTitle := "{{getIcon .Task.Status }}工作流 {{.Task.WorkflowDisplayName}} #{{.Task.TaskID}} 等待审批\n"
+	tplTitle := "{{if ne .WebHookType \"feishu\"}}#### {{end}}{{getIcon .Task.Status }}{{if eq .WebHookType \"wechat\"}}<font color=\"markdownColorInfo\">工作流{{.Task.WorkflowDisplayName}} #{{.Task.TaskID}}{{if .Task.TaskName}} {{.Task.TaskName}}{{end}} 等待审批</font>{{else}}工作流 {{.Task.WorkflowDisplayName}} #{{.Task.TaskID}}{{if .Task.TaskName}} {{.Task.TaskName}}{{end}} 等待审批{{end}} \n"
+	mailTplTitle := "{{getIcon .Task.Status }}工作流 {{.Task.WorkflowDisplayName}} #{{.Task.TaskID}}{{if .Task.TaskName}} {{.Task.TaskName}}{{end}} 等待审批\n"
 
-	tplBaseInfo := []string{"{{if eq .WebHookType \"dingding\"}}##### {{end}}**执行用户**：{{.Task.TaskCreator}} \n",
+	tplBaseInfo := []string{"{{if eq .WebHookType \"dingding\"}}##### {{end}}**执行用户**：{{.Task.TaskCreator}}{{if .Task.RunAs}}（服务账号：{{.Task.RunAs}}）{{end}} \n",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**项目名称**：{{.Task.ProjectName}} \n",
+		"{{if .Task.Risk}}{{if eq .WebHookType \"dingding\"}}##### {{end}}**变更风险**：{{.Task.Risk.Level}}（{{.Task.Risk.Score}}分） \n{{end}}",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**开始时间**：{{ getStartTime .Task.StartTime}} \n",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**持续时间**：{{ getDuration .TotalTime}} \n",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**备注**：{{.Task.Remark}} \n",
 	}
-	mailTplBaseInfo := []string{"执行用户：{{.Task.TaskCreator}} \n",
+	mailTplBaseInfo := []string{"执行用户：{{.Task.TaskCreator}}{{if .Task.RunAs}}（服务账号：{{.Task.RunAs}}）{{end}} \n",
 		"项目名称：{{.Task.ProjectName}} \n",
+		"{{if .Task.Risk}}变更风险：{{.Task.Risk.Level}}（{{.Task.Risk.Score}}分） \n{{end}}",
 		"开始时间：{{ getStartTime .Task.StartTime}} \n",
 		"持续时间：{{ getDuration .TotalTime}} \n",
 		"备注：{{ .Task.Remark}} \n\n",
@@ -291,6 +346,7 @@ func (w *Service) getNotificationContent(notify *models.NotifyCtl, task *models.
 		WorkflowDisplayName: task.WorkflowDisplayName,
 		ProjectName:         task.ProjectName,
 		Status:              task.Status,
+		TaskName:            task.TaskName,
 		Remark:              task.Remark,
 		Error:               task.Error,
 		CreateTime:          task.CreateTime,
@@ -300,19 +356,26 @@ func (w *Service) getNotificationContent(notify *models.NotifyCtl, task *models.
 		TaskCreatorID:       task.TaskCreatorID,
 		TaskCreatorPhone:    task.TaskCreatorPhone,
 		TaskCreatorEmail:    task.TaskCreatorEmail,
+		RunAs:               task.RunAs,
+	}
+	if task.Risk != nil {
+		webhookNotify.RiskLevel = string(task.Risk.Level)
+		webhookNotify.RiskScore = task.Risk.Score
 	}
 
-	tplTitle := "{{if ne .WebHookType \"feishu\"}}#### {{end}}{{getIcon .Task.Status }}{{if eq .WebHookType \"wechat\"}}<font color=\"{{ getColor .Task.Status }}\">工作流{{.Task.WorkflowDisplayName}} #{{.Task.TaskID}} {{ taskStatus .Task.Status }}</font>{{else}}工作流 {{.Task.WorkflowDisplayName}} #{{.Task.TaskID}} {{ taskStatus .Task.Status }}{{end}} \n"
-	mailTplTitle := "{{getIcon .Task.Status }} 工作流 {{.Task.WorkflowDisplayName}}#{{.Task.TaskID}} {{ taskStatus .Task.Status }}"
+	tplTitle := "{{if ne .WebHookType \"feishu\"}}#### {{end}}{{getIcon .Task.Status }}{{if eq .WebHookType \"wechat\"}}<font color=\"{{ getColor .Task.Status }}\">工作流{{.Task.WorkflowDisplayName}} #{{.Task.TaskID}}{{if .Task.TaskName}} {{.Task.TaskName}}{{end}} {{ taskStatus .Task.Status }}</font>{{else}}工作流 {{.Task.WorkflowDisplayName}} #{{.Task.TaskID}}{{if .Task.TaskName}} {{.Task.TaskName}}{{end}} {{ taskStatus .Task.Status }}{{end}} \n"
+	mailTplTitle := "{{getIcon .Task.Status }} 工作流 {{.Task.WorkflowDisplayName}}#{{.Task.TaskID}}{{if .Task.TaskName}} {{.Task.TaskName}}{{end}} {{ taskStatus .Task.Status }}"
 
-	tplBaseInfo := []string{"{{if eq .WebHookType \"dingding\"}}##### {{end}}**执行用户**：{{.Task.TaskCreator}} \n",
+	tplBaseInfo := []string{"{{if eq .WebHookType \"dingding\"}}##### {{end}}**执行用户**：{{.Task.TaskCreator}}{{if .Task.RunAs}}（服务账号：{{.Task.RunAs}}）{{end}} \n",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**项目名称**：{{.Task.ProjectName}} \n",
+		"{{if .Task.Risk}}{{if eq .WebHookType \"dingding\"}}##### {{end}}**变更风险**：{{.Task.Risk.Level}}（{{.Task.Risk.Score}}分） \n{{end}}",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**开始时间**：{{ getStartTime .Task.StartTime}} \n",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**持续时间**：{{ getDuration .TotalTime}} \n",
 		"{{if eq .WebHookType \"dingding\"}}##### {{end}}**备注**：{{.Task.Remark}} \n",
 	}
-	mailTplBaseInfo := []string{"执行用户：{{.Task.TaskCreator}} \n",
+	mailTplBaseInfo := []string{"执行用户：{{.Task.TaskCreator}}{{if .Task.RunAs}}（服务账号：{{.Task.RunAs}}）{{end}} \n",
 		"项目名称：{{.Task.ProjectName}} \n",
+		"{{if .Task.Risk}}变更风险：{{.Task.Risk.Level}}（{{.Task.Risk.Score}}分） \n{{end}}",
 		"开始时间：{{ getStartTime .Task.StartTime}} \n",
 		"持续时间：{{ getDuration .TotalTime}} \n",
 		"备注：{{ .Task.Remark}} \n",
@@ -771,6 +834,10 @@ func (w *Service) sendNotification(title, content string, notify *models.NotifyC
 		if err := w.sendMailMessage(title, content, notify.MailUsers); err != nil {
 			return err
 		}
+	case setting.NotifyWebHookTypeTelegram:
+		if err := w.sendTelegramMessage(notify.TelegramBotToken, notify.TelegramChatID, content); err != nil {
+			return err
+		}
 	case setting.NotifyWebHookTypeWebook:
 		webhookclient := webhooknotify.NewClient(notify.WebHookNotify.Address, notify.WebHookNotify.Token)
 		err := webhookclient.SendWorkflowWebhook(webhookNotify)
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// References is the impact report of deleting an integration. The integration can't be deleted when it is
+// used by environments, the other references only fail later so they are warnings which can be forcibly ignored.
+type References struct {
+	Deletable bool         `json:"deletable"`
+	Envs      []*Reference `json:"envs"`
+	Builds    []*Reference `json:"builds"`
+	Testings  []*Reference `json:"testings"`
+	Scannings []*Reference `json:"scannings"`
+	Workflows []*Reference `json:"workflows"`
+}
+
+type Reference struct {
+	ProjectName string `json:"project_name"`
+	Name        string `json:"name"`
+	Production  bool   `json:"production,omitempty"`
+}
+
+func (r *References) HasWarnings() bool {
+	return len(r.Builds) > 0 || len(r.Testings) > 0 || len(r.Scannings) > 0 || len(r.Workflows) > 0
+}
+
+// CheckDeletion returns an error describing the references if the integration should not be deleted.
+func (r *References) CheckDeletion(force bool) error {
+	if !r.Deletable {
+		return e.ErrIntegrationInUse.AddDesc(fmt.Sprintf("used by environments: %s", joinReferences(r.Envs)))
+	}
+	if force || !r.HasWarnings() {
+		return nil
+	}
+
+	desc := make([]string, 0)
+	appendDesc := func(kind string, refs []*Reference) {
+		if len(refs) > 0 {
+			desc = append(desc, fmt.Sprintf("%s: %s", kind, joinReferences(refs)))
+		}
+	}
+	appendDesc("builds", r.Builds)
+	appendDesc("testings", r.Testings)
+	appendDesc("scannings", r.Scannings)
+	appendDesc("workflows", r.Workflows)
+	return e.ErrIntegrationInUse.AddDesc(fmt.Sprintf("used by %s, delete it forcibly to ignore them", strings.Join(desc, "; ")))
+}
+
+func joinReferences(refs []*Reference) string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, fmt.Sprintf("%s/%s", ref.ProjectName, ref.Name))
+	}
+	return strings.Join(names, ", ")
+}
+
+func GetRegistryReferences(registryID string) (*References, error) {
+	resp := &References{}
+
+	envs, err := mongodb.NewProductColl().List(&mongodb.ProductListOptions{
+		ExcludeStatus: []string{setting.ProductStatusDeleting},
+	})
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, env := range envs {
+		if env.RegistryID == registryID {
+			resp.Envs = append(resp.Envs, &Reference{ProjectName: env.ProductName, Name: env.EnvName, Production: env.Production})
+		}
+	}
+
+	if resp.Workflows, err = listWorkflowReferences([]string{
+		"stages.jobs.spec.docker_registry_id",
+		"stages.jobs.spec.source_registry_id",
+		"stages.jobs.spec.target_registry_id",
+	}, registryID); err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+
+	resp.Deletable = len(resp.Envs) == 0
+	return resp, nil
+}
+
+func GetCodehostReferences(codehostID int) (*References, error) {
+	resp := &References{Deletable: true}
+
+	builds, err := mongodb.NewBuildColl().ListByReference([]string{"repos.codehost_id"}, codehostID)
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, build := range builds {
+		resp.Builds = append(resp.Builds, &Reference{ProjectName: build.ProductName, Name: build.Name})
+	}
+
+	testings, err := mongodb.NewTestingColl().ListByReference([]string{"repos.codehost_id"}, codehostID)
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, testing := range testings {
+		resp.Testings = append(resp.Testings, &Reference{ProjectName: testing.ProductName, Name: testing.Name})
+	}
+
+	scannings, err := mongodb.NewScanningColl().ListByReference([]string{"repos.codehost_id"}, codehostID)
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, scanning := range scannings {
+		resp.Scannings = append(resp.Scannings, &Reference{ProjectName: scanning.ProjectName, Name: scanning.Name})
+	}
+
+	if resp.Workflows, err = listWorkflowReferences([]string{
+		"hook_ctl.main_repo.codehost_id",
+		"stages.jobs.spec.repos.codehost_id",
+		"stages.jobs.spec.service_and_builds.repos.codehost_id",
+	}, codehostID); err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+
+	return resp, nil
+}
+
+func GetSonarReferences(sonarID string) (*References, error) {
+	resp := &References{Deletable: true}
+
+	scannings, err := mongodb.NewScanningColl().ListByReference([]string{"sonar_id"}, sonarID)
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, scanning := range scannings {
+		resp.Scannings = append(resp.Scannings, &Reference{ProjectName: scanning.ProjectName, Name: scanning.Name})
+	}
+
+	return resp, nil
+}
+
+func GetClusterReferences(clusterID string) (*References, error) {
+	resp := &References{}
+
+	envs, err := mongodb.NewProductColl().List(&mongodb.ProductListOptions{
+		ClusterID: clusterID,
+	})
+	if err != nil && !mongodb.IsErrNoDocuments(err) {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, env := range envs {
+		resp.Envs = append(resp.Envs, &Reference{ProjectName: env.ProductName, Name: env.EnvName, Production: env.Production})
+	}
+
+	builds, err := mongodb.NewBuildColl().ListByReference([]string{"pre_build.cluster_id"}, clusterID)
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, build := range builds {
+		resp.Builds = append(resp.Builds, &Reference{ProjectName: build.ProductName, Name: build.Name})
+	}
+
+	testings, err := mongodb.NewTestingColl().ListByReference([]string{"pre_test.cluster_id"}, clusterID)
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, testing := range testings {
+		resp.Testings = append(resp.Testings, &Reference{ProjectName: testing.ProductName, Name: testing.Name})
+	}
+
+	scannings, err := mongodb.NewScanningColl().ListByReference([]string{"advanced_setting.cluster_id"}, clusterID)
+	if err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+	for _, scanning := range scannings {
+		resp.Scannings = append(resp.Scannings, &Reference{ProjectName: scanning.ProjectName, Name: scanning.Name})
+	}
+
+	if resp.Workflows, err = listWorkflowReferences([]string{
+		"stages.jobs.spec.cluster_id",
+		"stages.jobs.spec.properties.cluster_id",
+	}, clusterID); err != nil {
+		return nil, e.ErrGetIntegrationReferences.AddErr(err)
+	}
+
+	resp.Deletable = len(resp.Envs) == 0
+	return resp, nil
+}
+
+func listWorkflowReferences(fields []string, value interface{}) ([]*Reference, error) {
+	workflows, err := mongodb.NewWorkflowV4Coll().ListByReference(fields, value)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*Reference, 0, len(workflows))
+	for _, workflow := range workflows {
+		resp = append(resp, &Reference{ProjectName: workflow.Project, Name: workflow.Name})
+	}
+	return resp, nil
+}
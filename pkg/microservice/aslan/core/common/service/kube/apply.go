@@ -150,6 +150,35 @@ func ApplySystemImagePullSecrets(podSpec *corev1.PodSpec) {
 		})
 }
 
+// GetDragonflyConfig returns the dragonfly config of the cluster, nil will be returned if it is not enabled
+func GetDragonflyConfig(clusterID string) *commonmodels.DragonflyConfig {
+	if clusterID == "" {
+		clusterID = setting.LocalClusterID
+	}
+	cluster, err := commonrepo.NewK8SClusterColl().Get(clusterID)
+	if err != nil {
+		log.Warnf("failed to find cluster %s to get dragonfly config, error: %s", clusterID, err)
+		return nil
+	}
+	if cluster.DragonflyConfig == nil || !cluster.DragonflyConfig.Enabled {
+		return nil
+	}
+	return cluster.DragonflyConfig
+}
+
+// ApplyDragonflyImageMirror rewrites the images in the pod spec to be pulled through the dragonfly mirror
+func ApplyDragonflyImageMirror(podSpec *corev1.PodSpec, dragonflyConfig *commonmodels.DragonflyConfig) {
+	if dragonflyConfig == nil {
+		return
+	}
+	for i := range podSpec.InitContainers {
+		podSpec.InitContainers[i].Image = dragonflyConfig.MirrorImage(podSpec.InitContainers[i].Image)
+	}
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Image = dragonflyConfig.MirrorImage(podSpec.Containers[i].Image)
+	}
+}
+
 func SetFieldValueIsNotExist(obj map[string]interface{}, value interface{}, fields ...string) map[string]interface{} {
 	m := obj
 	for _, field := range fields[:len(fields)-1] {
@@ -482,6 +511,7 @@ func CreateOrPatchResource(applyParam *ResourceApplyParam, log *zap.SugaredLogge
 		labels = map[string]string{}
 		clusterLabels = map[string]string{}
 	}
+	dragonflyConfig := GetDragonflyConfig(productInfo.ClusterID)
 
 	var res []*unstructured.Unstructured
 	errList := &multierror.Error{}
@@ -600,6 +630,7 @@ func CreateOrPatchResource(applyParam *ResourceApplyParam, log *zap.SugaredLogge
 				if applyParam.InjectSecrets {
 					ApplySystemImagePullSecrets(&res.Spec.Template.Spec)
 				}
+				ApplyDragonflyImageMirror(&res.Spec.Template.Spec, dragonflyConfig)
 
 				err = updater.CreateOrPatchDeployment(res, kubeClient)
 				if err != nil {
@@ -612,6 +643,7 @@ func CreateOrPatchResource(applyParam *ResourceApplyParam, log *zap.SugaredLogge
 				if applyParam.InjectSecrets {
 					ApplySystemImagePullSecrets(&res.Spec.Template.Spec)
 				}
+				ApplyDragonflyImageMirror(&res.Spec.Template.Spec, dragonflyConfig)
 
 				err = updater.CreateOrPatchStatefulSet(res, kubeClient)
 				if err != nil {
@@ -646,6 +678,7 @@ func CreateOrPatchResource(applyParam *ResourceApplyParam, log *zap.SugaredLogge
 			if applyParam.InjectSecrets {
 				ApplySystemImagePullSecrets(&obj.Spec.Template.Spec)
 			}
+			ApplyDragonflyImageMirror(&obj.Spec.Template.Spec, dragonflyConfig)
 
 			if err := updater.DeleteJobAndWait(namespace, obj.Name, kubeClient); err != nil {
 				log.Errorf("Failed to delete Job, error: %v", err)
@@ -683,6 +716,7 @@ func CreateOrPatchResource(applyParam *ResourceApplyParam, log *zap.SugaredLogge
 				if applyParam.InjectSecrets {
 					ApplySystemImagePullSecrets(&obj.Spec.JobTemplate.Spec.Template.Spec)
 				}
+				ApplyDragonflyImageMirror(&obj.Spec.JobTemplate.Spec.Template.Spec, dragonflyConfig)
 
 				err = updater.CreateOrPatchCronJob(obj, kubeClient)
 				if err != nil {
@@ -707,6 +741,7 @@ func CreateOrPatchResource(applyParam *ResourceApplyParam, log *zap.SugaredLogge
 				if applyParam.InjectSecrets {
 					ApplySystemImagePullSecrets(&obj.Spec.JobTemplate.Spec.Template.Spec)
 				}
+				ApplyDragonflyImageMirror(&obj.Spec.JobTemplate.Spec.Template.Spec, dragonflyConfig)
 
 				err = updater.CreateOrPatchCronJob(obj, kubeClient)
 				if err != nil {
@@ -744,6 +779,11 @@ func CreateOrPatchResource(applyParam *ResourceApplyParam, log *zap.SugaredLogge
 		return res, errList.ErrorOrNil()
 	}
 
+	// nothing is deployed for the service before, so it's the first deploy of the service in the env
+	if applyParam.CurrentResourceYaml == "" && applyParam.ServiceName != "" {
+		TriggerFirstDeployHook(productInfo, applyParam.ServiceName, kubeClient, log)
+	}
+
 	return res, nil
 }
 
@@ -771,9 +811,9 @@ func PrepareHelmServiceData(applyParam *ResourceApplyParam) (*commonmodels.Produ
 		ServiceName: applyParam.ServiceName,
 		Revision:    productService.Revision,
 	}
-	// use latest svc template if option 'UpdateServiceRevision' is true
+	// use latest svc template if option 'UpdateServiceRevision' is true, unless the service revision is pinned in the env
 	if applyParam.UpdateServiceRevision {
-		svcFindOption.Revision = 0
+		svcFindOption.Revision = productInfo.GetPinnedServiceRevision(applyParam.ServiceName)
 	}
 
 	svcTemplate, err := repository.QueryTemplateService(svcFindOption, productInfo.Production)
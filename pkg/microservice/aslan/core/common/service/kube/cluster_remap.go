@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/serializer"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// keys of the helm values which are commonly used to set the storage class or ingress class in charts
+var (
+	helmStorageClassKeys = map[string]bool{"storageClass": true, "storageClassName": true}
+	helmIngressClassKeys = map[string]bool{"ingressClass": true, "ingressClassName": true, "className": true}
+)
+
+// RemapClusterResources replaces the storage classes and ingress classes in the manifests according to the rules,
+// the manifests which are not changed are kept as they are.
+func RemapClusterResources(manifests string, rules *commonmodels.ClusterRemapRules) (string, error) {
+	if rules.IsEmpty() || manifests == "" {
+		return manifests, nil
+	}
+
+	yamls := util.SplitYaml(manifests)
+	for i, item := range yamls {
+		u, err := serializer.NewDecoder().YamlToUnstructured([]byte(item))
+		if err != nil {
+			return "", fmt.Errorf("failed to decode yaml: %s", err)
+		}
+
+		changed := false
+		switch u.GetKind() {
+		case setting.PersistentVolumeClaim:
+			changed = remapNestedString(u.Object, rules.StorageClasses, "spec", "storageClassName")
+		case setting.StatefulSet:
+			spec, _ := u.Object["spec"].(map[string]interface{})
+			templates, _ := spec["volumeClaimTemplates"].([]interface{})
+			for _, template := range templates {
+				if tm, ok := template.(map[string]interface{}); ok && remapNestedString(tm, rules.StorageClasses, "spec", "storageClassName") {
+					changed = true
+				}
+			}
+		case setting.Ingress:
+			changed = remapNestedString(u.Object, rules.IngressClasses, "spec", "ingressClassName")
+			annotations := u.GetAnnotations()
+			if target, ok := rules.IngressClasses[annotations[ingressClassAnnotation]]; ok && annotations[ingressClassAnnotation] != "" {
+				annotations[ingressClassAnnotation] = target
+				u.SetAnnotations(annotations)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		bs, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal %s/%s: %s", u.GetKind(), u.GetName(), err)
+		}
+		yamls[i] = string(bs)
+	}
+	return util.JoinYamls(yamls), nil
+}
+
+// RemapHelmValues finds the storage classes and ingress classes set in the helm values and returns the values yaml
+// which overrides them according to the rules, an empty string is returned if nothing needs to be overridden.
+func RemapHelmValues(valuesYaml string, rules *commonmodels.ClusterRemapRules) (string, error) {
+	if rules.IsEmpty() || valuesYaml == "" {
+		return "", nil
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(valuesYaml), &values); err != nil {
+		return "", fmt.Errorf("failed to unmarshal values yaml: %s", err)
+	}
+	overrides := remapHelmValues(values, rules)
+	if len(overrides) == 0 {
+		return "", nil
+	}
+	bs, err := yaml.Marshal(overrides)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal values yaml: %s", err)
+	}
+	return string(bs), nil
+}
+
+func remapHelmValues(values map[string]interface{}, rules *commonmodels.ClusterRemapRules) map[string]interface{} {
+	overrides := make(map[string]interface{})
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			if helmStorageClassKeys[key] {
+				if target, ok := rules.StorageClasses[v]; ok {
+					overrides[key] = target
+				}
+			}
+			if helmIngressClassKeys[key] {
+				if target, ok := rules.IngressClasses[v]; ok {
+					overrides[key] = target
+				}
+			}
+		case map[string]interface{}:
+			if sub := remapHelmValues(v, rules); len(sub) > 0 {
+				overrides[key] = sub
+			}
+		}
+	}
+	return overrides
+}
+
+func remapNestedString(obj map[string]interface{}, mapping map[string]string, fields ...string) bool {
+	m := obj
+	for _, field := range fields[:len(fields)-1] {
+		next, ok := m[field].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		m = next
+	}
+	last := fields[len(fields)-1]
+	current, ok := m[last].(string)
+	if !ok || current == "" {
+		return false
+	}
+	target, ok := mapping[current]
+	if !ok {
+		return false
+	}
+	m[last] = target
+	return true
+}
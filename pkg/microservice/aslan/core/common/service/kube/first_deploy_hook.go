@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	"github.com/koderover/zadig/v2/pkg/shared/kube/wrapper"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/serializer"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/updater"
+)
+
+const defaultFirstDeployHookTimeout = 600
+
+// ValidateFirstDeployHook checks the first deploy hook of the service template.
+func ValidateFirstDeployHook(hook *commonmodels.FirstDeployHook) error {
+	if hook == nil || !hook.Enabled {
+		return nil
+	}
+	if hook.Timeout < 0 {
+		return fmt.Errorf("first deploy hook timeout can not be negative")
+	}
+	switch hook.Type {
+	case config.FirstDeployHookTypeScript:
+		if hook.Image == "" || hook.Script == "" {
+			return fmt.Errorf("image and script of the first deploy hook can not be empty")
+		}
+	case config.FirstDeployHookTypeManifest:
+		if _, err := serializer.NewDecoder().YamlToJob([]byte(hook.Manifest)); err != nil {
+			return fmt.Errorf("first deploy hook manifest is not a valid k8s Job: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid first deploy hook type: %s", hook.Type)
+	}
+	return nil
+}
+
+// TriggerFirstDeployHook runs the first deploy hook of the service in the background if the hook has never run
+// for the service in the env, the result is tracked by FirstDeployHookRecord.
+func TriggerFirstDeployHook(productInfo *commonmodels.Product, serviceName string, kubeClient client.Client, log *zap.SugaredLogger) {
+	option := &commonrepo.ServiceFindOption{ServiceName: serviceName, ProductName: productInfo.ProductName}
+	if svc, ok := productInfo.GetServiceMap()[serviceName]; ok {
+		option.Revision = svc.Revision
+	}
+	svcTemplate, err := repository.QueryTemplateService(option, productInfo.Production)
+	if err != nil {
+		log.Warnf("failed to find service template %s/%s for first deploy hook: %v", productInfo.ProductName, serviceName, err)
+		return
+	}
+	hook := svcTemplate.FirstDeployHook
+	if hook == nil || !hook.Enabled {
+		return
+	}
+
+	record := &commonmodels.FirstDeployHookRecord{
+		ProductName: productInfo.ProductName,
+		EnvName:     productInfo.EnvName,
+		Production:  productInfo.Production,
+		ServiceName: serviceName,
+		Revision:    svcTemplate.Revision,
+		Type:        hook.Type,
+		Status:      config.StatusRunning,
+		StartTime:   time.Now().Unix(),
+	}
+	job, buildErr := buildFirstDeployHookJob(productInfo, serviceName, hook)
+	if buildErr == nil {
+		record.JobName = job.Name
+	} else {
+		record.Status = config.StatusFailed
+		record.Error = buildErr.Error()
+		record.EndTime = record.StartTime
+	}
+	if err := commonrepo.NewFirstDeployHookRecordColl().Create(record); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			log.Errorf("failed to create first deploy hook record of %s/%s/%s: %v", productInfo.ProductName, productInfo.EnvName, serviceName, err)
+		}
+		return
+	}
+	if buildErr != nil {
+		log.Errorf("failed to build first deploy hook of %s/%s/%s: %v", productInfo.ProductName, productInfo.EnvName, serviceName, buildErr)
+		return
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultFirstDeployHookTimeout
+	}
+	go func() {
+		status, err := runFirstDeployHookJob(job, time.Duration(timeout)*time.Second, kubeClient)
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
+			log.Errorf("first deploy hook of %s/%s/%s finished with status %s: %v", productInfo.ProductName, productInfo.EnvName, serviceName, status, err)
+		}
+		if err := commonrepo.NewFirstDeployHookRecordColl().UpdateStatus(record.ID, status, errMsg); err != nil {
+			log.Errorf("failed to update first deploy hook record of %s/%s/%s: %v", productInfo.ProductName, productInfo.EnvName, serviceName, err)
+		}
+	}()
+}
+
+func firstDeployHookJobName(serviceName string) string {
+	name := fmt.Sprintf("%s-first-deploy-hook", serviceName)
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+func buildFirstDeployHookJob(productInfo *commonmodels.Product, serviceName string, hook *commonmodels.FirstDeployHook) (*batchv1.Job, error) {
+	labels := GetPredefinedLabels(productInfo.ProductName, serviceName)
+
+	var job *batchv1.Job
+	switch hook.Type {
+	case config.FirstDeployHookTypeManifest:
+		var err error
+		job, err = serializer.NewDecoder().YamlToJob([]byte(hook.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid k8s Job manifest: %v", err)
+		}
+		if job.Name == "" {
+			job.Name = firstDeployHookJobName(serviceName)
+		}
+	case config.FirstDeployHookTypeScript:
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: firstDeployHookJobName(serviceName),
+			},
+			Spec: batchv1.JobSpec{
+				BackoffLimit: pointer.Int32(0),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers: []corev1.Container{
+							{
+								Name:    "hook",
+								Image:   hook.Image,
+								Command: []string{"/bin/sh", "-c", hook.Script},
+								Env: []corev1.EnvVar{
+									{Name: "PROJECT", Value: productInfo.ProductName},
+									{Name: "ENV_NAME", Value: productInfo.EnvName},
+									{Name: "NAMESPACE", Value: productInfo.Namespace},
+									{Name: "SERVICE_NAME", Value: serviceName},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("invalid first deploy hook type: %s", hook.Type)
+	}
+
+	job.Namespace = productInfo.Namespace
+	job.Labels = MergeLabels(labels, job.Labels)
+	job.Spec.Template.Labels = MergeLabels(labels, job.Spec.Template.Labels)
+	ApplySystemImagePullSecrets(&job.Spec.Template.Spec)
+	return job, nil
+}
+
+// runFirstDeployHookJob creates the job and waits for it to finish, the final status of the hook is returned.
+func runFirstDeployHookJob(job *batchv1.Job, timeout time.Duration, kubeClient client.Client) (config.Status, error) {
+	if err := updater.DeleteJobAndWait(job.Namespace, job.Name, kubeClient); err != nil {
+		return config.StatusFailed, fmt.Errorf("failed to delete the existing job %s: %v", job.Name, err)
+	}
+	if err := updater.CreateJob(job, kubeClient); err != nil {
+		return config.StatusFailed, fmt.Errorf("failed to create job %s: %v", job.Name, err)
+	}
+
+	status := config.StatusTimeout
+	err := wait.PollImmediate(3*time.Second, timeout, func() (bool, error) {
+		current, found, err := getter.GetJob(job.Namespace, job.Name, kubeClient)
+		if err != nil || !found {
+			return false, nil
+		}
+		if wrapper.Job(current).Complete() {
+			status = config.StatusPassed
+			return true, nil
+		}
+		if wrapper.Job(current).Failed() {
+			status = config.StatusFailed
+			return true, nil
+		}
+		return false, nil
+	})
+	switch {
+	case err != nil:
+		return status, fmt.Errorf("job %s is not finished in %s", job.Name, timeout)
+	case status == config.StatusFailed:
+		return status, fmt.Errorf("job %s failed", job.Name)
+	}
+	return status, nil
+}
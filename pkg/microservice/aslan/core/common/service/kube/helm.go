@@ -73,6 +73,46 @@ type ReleaseInstallParam struct {
 }
 
 func InstallOrUpgradeHelmChartWithValues(param *ReleaseInstallParam, isRetry bool, helmClient *helmtool.HelmClient) error {
+	namespace, serviceObj := param.Namespace, param.ServiceObj
+	chartSpec, err := buildReleaseChartSpec(param)
+	if err != nil {
+		return err
+	}
+	if isRetry {
+		chartSpec.Replace = true
+	}
+
+	// If the target environment is a shared environment and a sub env, we need to clear the deployed K8s Service.
+	ctx := context.TODO()
+	err = EnsureDeletePreCreatedServices(ctx, param.ProductName, param.Namespace, chartSpec, helmClient)
+	if err != nil {
+		return fmt.Errorf("failed to ensure deleting pre-created K8s Services for product %q in namespace %q: %s", param.ProductName, param.Namespace, err)
+	}
+
+	helmClient, err = helmClient.Clone()
+	if err != nil {
+		return fmt.Errorf("failed to clone helm client: %s", err)
+	}
+
+	var release *release.Release
+	release, err = helmClient.InstallOrUpgradeChart(ctx, chartSpec, nil)
+	if err != nil {
+		err = errors.WithMessagef(
+			err,
+			"failed to install or upgrade helm chart %s/%s",
+			namespace, serviceObj.ServiceName)
+	} else {
+		err = EnsureZadigServiceByManifest(ctx, param.ProductName, param.Namespace, release.Manifest)
+		if err != nil {
+			err = errors.WithMessagef(err, "failed to ensure Zadig Service, err: %s", err)
+		}
+	}
+
+	return err
+}
+
+// buildReleaseChartSpec loads the chart of the service to local and generates the chart spec to install it
+func buildReleaseChartSpec(param *ReleaseInstallParam) (*helmclient.ChartSpec, error) {
 	namespace, valuesYaml, renderChart, serviceObj := param.Namespace, param.MergedValues, param.RenderChart, param.ServiceObj
 	base := config.LocalServicePathWithRevision(serviceObj.ProductName, serviceObj.ServiceName, fmt.Sprint(serviceObj.Revision), param.Production)
 	if param.IsChartInstall {
@@ -85,7 +125,7 @@ func InstallOrUpgradeHelmChartWithValues(param *ReleaseInstallParam, isRetry boo
 		base = config.LocalServicePath(serviceObj.ProductName, serviceObj.ServiceName, param.Production)
 		if err = commonutil.PreLoadServiceManifests(base, serviceObj, param.Production); err != nil {
 			log.Errorf("failed to load chart info for service %v, production: %v", serviceObj.ServiceName, param.Production)
-			return fmt.Errorf("failed to load chart info for service %s", serviceObj.ServiceName)
+			return nil, fmt.Errorf("failed to load chart info for service %s", serviceObj.ServiceName)
 		}
 	}
 
@@ -96,7 +136,7 @@ func InstallOrUpgradeHelmChartWithValues(param *ReleaseInstallParam, isRetry boo
 	chartPath, err := fs.RelativeToCurrentPath(chartFullPath)
 	if err != nil {
 		log.Errorf("Failed to get relative path %s, err: %s", chartFullPath, err)
-		return err
+		return nil, err
 	}
 
 	chartSpec := &helmclient.ChartSpec{
@@ -109,40 +149,10 @@ func InstallOrUpgradeHelmChartWithValues(param *ReleaseInstallParam, isRetry boo
 		CleanupOnFail: true,
 		MaxHistory:    10,
 	}
-	if isRetry {
-		chartSpec.Replace = true
-	}
 	if param.Timeout > 0 {
 		chartSpec.Timeout = time.Second * time.Duration(param.Timeout)
 	}
-
-	// If the target environment is a shared environment and a sub env, we need to clear the deployed K8s Service.
-	ctx := context.TODO()
-	err = EnsureDeletePreCreatedServices(ctx, param.ProductName, param.Namespace, chartSpec, helmClient)
-	if err != nil {
-		return fmt.Errorf("failed to ensure deleting pre-created K8s Services for product %q in namespace %q: %s", param.ProductName, param.Namespace, err)
-	}
-
-	helmClient, err = helmClient.Clone()
-	if err != nil {
-		return fmt.Errorf("failed to clone helm client: %s", err)
-	}
-
-	var release *release.Release
-	release, err = helmClient.InstallOrUpgradeChart(ctx, chartSpec, nil)
-	if err != nil {
-		err = errors.WithMessagef(
-			err,
-			"failed to install or upgrade helm chart %s/%s",
-			namespace, serviceObj.ServiceName)
-	} else {
-		err = EnsureZadigServiceByManifest(ctx, param.ProductName, param.Namespace, release.Manifest)
-		if err != nil {
-			err = errors.WithMessagef(err, "failed to ensure Zadig Service, err: %s", err)
-		}
-	}
-
-	return err
+	return chartSpec, nil
 }
 
 // GeneMergedValues generate values.yaml used to install or upgrade helm chart, like param in after option -f
@@ -216,86 +226,16 @@ func GeneMergedValues(productSvc *commonmodels.ProductService, svcRender *templa
 // UpgradeHelmRelease upgrades helm release with some specific images
 func UpgradeHelmRelease(product *commonmodels.Product, productSvc *commonmodels.ProductService,
 	svcTemp *commonmodels.Service, images []string, timeout int, user string) error {
-	chartInfo := productSvc.GetServiceRender()
-
-	var (
-		err                      error
-		releaseName              string
-		replacedMergedValuesYaml string
-	)
-
-	releaseName = productSvc.ReleaseName
-	if productSvc.FromZadig() {
-		releaseName = util.GeneReleaseName(svcTemp.GetReleaseNaming(), svcTemp.ProductName, product.Namespace, product.EnvName, svcTemp.ServiceName)
-	}
-
-	err = CheckReleaseInstalledByOtherEnv(sets.NewString(releaseName), product)
+	param, err := prepareReleaseInstallParam(product, productSvc, svcTemp, images, timeout)
 	if err != nil {
 		return err
 	}
 
-	if productSvc.FromZadig() {
-		releaseName = util.GeneReleaseName(svcTemp.GetReleaseNaming(), svcTemp.ProductName, product.Namespace, product.EnvName, svcTemp.ServiceName)
-		replacedMergedValuesYaml, err = GeneMergedValues(productSvc, chartInfo, product.DefaultValues, images, false)
-		if err != nil {
-			return fmt.Errorf("failed to gene merged values, err: %s", err)
-		}
-	} else {
-		releaseName = productSvc.ReleaseName
-		svcTemp = &commonmodels.Service{
-			ServiceName: releaseName,
-			ProductName: product.ProductName,
-			HelmChart: &commonmodels.HelmChart{
-				Name:    chartInfo.ChartName,
-				Repo:    chartInfo.ChartRepo,
-				Version: chartInfo.ChartVersion,
-			},
-		}
-
-		replacedMergedValuesYaml, err = helmtool.MergeOverrideValues("", product.DefaultValues, chartInfo.GetOverrideYaml(), chartInfo.OverrideValues, nil)
-		if err != nil {
-			return fmt.Errorf("failed to merge override values, err: %s", err)
-		}
-
-		chartRepo, err := commonrepo.NewHelmRepoColl().Find(&commonrepo.HelmRepoFindOption{RepoName: chartInfo.ChartRepo})
-		if err != nil {
-			return fmt.Errorf("failed to query chart-repo info, productName: %s, repoName: %s", product.ProductName, chartInfo.ChartRepo)
-		}
-
-		chartRef := fmt.Sprintf("%s/%s", chartInfo.ChartRepo, chartInfo.ChartName)
-		localPath := config.LocalServicePathWithRevision(product.ProductName, releaseName, chartInfo.ChartVersion, product.Production)
-		// remove local file to untar
-		_ = os.RemoveAll(localPath)
-
-		hClient, err := helmtool.NewClient()
-		if err != nil {
-			return err
-		}
-		err = hClient.DownloadChart(commonutil.GeneHelmRepo(chartRepo), chartRef, chartInfo.ChartVersion, localPath, true)
-		if err != nil {
-			return fmt.Errorf("failed to download chart, chartName: %s, chartRepo: %+v, err: %s", chartInfo.ChartName, chartRepo.RepoName, err)
-		}
-	}
-
 	helmClient, err := helmtool.NewClientFromNamespace(product.ClusterID, product.Namespace)
 	if err != nil {
 		return err
 	}
 
-	param := &ReleaseInstallParam{
-		ProductName:  svcTemp.ProductName,
-		Namespace:    product.Namespace,
-		ReleaseName:  releaseName,
-		MergedValues: replacedMergedValuesYaml,
-		RenderChart:  chartInfo,
-		ServiceObj:   svcTemp,
-		Timeout:      timeout,
-		Production:   product.Production,
-	}
-	if !productSvc.FromZadig() {
-		param.IsChartInstall = true
-	}
-
 	ensureUpgrade := func() error {
 		hrs, errHistory := helmClient.ListReleaseHistory(param.ReleaseName, 10)
 		if errHistory != nil {
@@ -417,6 +357,122 @@ func UpgradeHelmRelease(product *commonmodels.Product, productSvc *commonmodels.
 	return mongo.CommitTransaction(session)
 }
 
+// TemplateHelmRelease renders the manifests of the helm service with the images without installing it.
+// Services with sops encrypted values are rejected, since the rendered manifests would contain the decrypted secrets.
+func TemplateHelmRelease(product *commonmodels.Product, productSvc *commonmodels.ProductService, svcTemp *commonmodels.Service, images []string) (string, error) {
+	if HasSopsValues(product.DefaultValues, productSvc.GetServiceRender()) {
+		return "", fmt.Errorf("the values of service %s are encrypted by sops, rendering them to plain manifests is not allowed", productSvc.ServiceName)
+	}
+	param, err := prepareReleaseInstallParam(product, productSvc, svcTemp, images, 0)
+	if err != nil {
+		return "", err
+	}
+	chartSpec, err := buildReleaseChartSpec(param)
+	if err != nil {
+		return "", err
+	}
+	helmClient, err := helmtool.NewClientFromNamespace(product.ClusterID, product.Namespace)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := helmClient.TemplateChart(chartSpec)
+	if err != nil {
+		return "", fmt.Errorf("failed to template chart %s for release %s: %s", chartSpec.ChartName, chartSpec.ReleaseName, err)
+	}
+	return string(manifest), nil
+}
+
+func prepareReleaseInstallParam(product *commonmodels.Product, productSvc *commonmodels.ProductService,
+	svcTemp *commonmodels.Service, images []string, timeout int) (*ReleaseInstallParam, error) {
+	chartInfo := productSvc.GetServiceRender()
+
+	var (
+		err                      error
+		releaseName              string
+		replacedMergedValuesYaml string
+	)
+
+	releaseName = productSvc.ReleaseName
+	if productSvc.FromZadig() {
+		releaseName = util.GeneReleaseName(svcTemp.GetReleaseNaming(), svcTemp.ProductName, product.Namespace, product.EnvName, svcTemp.ServiceName)
+	}
+
+	err = CheckReleaseInstalledByOtherEnv(sets.NewString(releaseName), product)
+	if err != nil {
+		return nil, err
+	}
+
+	// the layers encrypted by sops are decrypted before being merged, the merged values are only kept in memory
+	defaultValues, err := DecryptSopsValues(product.DefaultValues)
+	if err != nil {
+		return nil, err
+	}
+	decryptedChartInfo, err := DecryptSopsRender(chartInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if productSvc.FromZadig() {
+		releaseName = util.GeneReleaseName(svcTemp.GetReleaseNaming(), svcTemp.ProductName, product.Namespace, product.EnvName, svcTemp.ServiceName)
+		replacedMergedValuesYaml, err = GeneMergedValues(productSvc, decryptedChartInfo, defaultValues, images, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to gene merged values, err: %s", err)
+		}
+		// the values.yaml with the images replaced is saved back to the env
+		chartInfo.ValuesYaml = decryptedChartInfo.ValuesYaml
+	} else {
+		releaseName = productSvc.ReleaseName
+		svcTemp = &commonmodels.Service{
+			ServiceName: releaseName,
+			ProductName: product.ProductName,
+			HelmChart: &commonmodels.HelmChart{
+				Name:    chartInfo.ChartName,
+				Repo:    chartInfo.ChartRepo,
+				Version: chartInfo.ChartVersion,
+			},
+		}
+
+		replacedMergedValuesYaml, err = helmtool.MergeOverrideValues("", defaultValues, decryptedChartInfo.GetOverrideYaml(), chartInfo.OverrideValues, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge override values, err: %s", err)
+		}
+
+		chartRepo, err := commonrepo.NewHelmRepoColl().Find(&commonrepo.HelmRepoFindOption{RepoName: chartInfo.ChartRepo})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query chart-repo info, productName: %s, repoName: %s", product.ProductName, chartInfo.ChartRepo)
+		}
+
+		chartRef := fmt.Sprintf("%s/%s", chartInfo.ChartRepo, chartInfo.ChartName)
+		localPath := config.LocalServicePathWithRevision(product.ProductName, releaseName, chartInfo.ChartVersion, product.Production)
+		// remove local file to untar
+		_ = os.RemoveAll(localPath)
+
+		hClient, err := helmtool.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		err = hClient.DownloadChart(commonutil.GeneHelmRepo(chartRepo), chartRef, chartInfo.ChartVersion, localPath, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download chart, chartName: %s, chartRepo: %+v, err: %s", chartInfo.ChartName, chartRepo.RepoName, err)
+		}
+	}
+
+	param := &ReleaseInstallParam{
+		ProductName:  svcTemp.ProductName,
+		Namespace:    product.Namespace,
+		ReleaseName:  releaseName,
+		MergedValues: replacedMergedValuesYaml,
+		RenderChart:  chartInfo,
+		ServiceObj:   svcTemp,
+		Timeout:      timeout,
+		Production:   product.Production,
+	}
+	if !productSvc.FromZadig() {
+		param.IsChartInstall = true
+	}
+	return param, nil
+}
+
 func UninstallServiceByName(helmClient helmclient.Client, serviceName string, env *commonmodels.Product, revision int64, force bool) error {
 	revisionSvc, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
 		ServiceName: serviceName,
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/lint"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const schemaValidatorTTL = 10 * time.Minute
+
+// system variables like $Namespace$ are rendered at deploy time, they are replaced with a valid name before checking
+var systemVariableRegex = regexp.MustCompile(`\$\w+\$`)
+
+type cachedSchemaValidator struct {
+	validator *lint.SchemaValidator
+	expireAt  time.Time
+}
+
+// schemaValidators caches the schema validator of each cluster, fetching the openapi schema is expensive
+var schemaValidators sync.Map
+
+func GetManifestLintSettings() (*commonmodels.ManifestLintSettings, error) {
+	sysSetting, err := commonrepo.NewSystemSettingColl().Get()
+	if err != nil {
+		return nil, err
+	}
+	if sysSetting.ManifestLint == nil {
+		return &commonmodels.ManifestLintSettings{SchemaValidation: true}, nil
+	}
+	return sysSetting.ManifestLint, nil
+}
+
+func getSchemaValidator(clusterID string) (*lint.SchemaValidator, error) {
+	if v, ok := schemaValidators.Load(clusterID); ok {
+		cached := v.(*cachedSchemaValidator)
+		if time.Now().Before(cached.expireAt) {
+			return cached.validator, nil
+		}
+	}
+
+	cls, err := GetClientset(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset of cluster %s, err: %s", clusterID, err)
+	}
+	validator, err := lint.NewSchemaValidator(cls.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	schemaValidators.Store(clusterID, &cachedSchemaValidator{
+		validator: validator,
+		expireAt:  time.Now().Add(schemaValidatorTTL),
+	})
+	return validator, nil
+}
+
+// LintManifests checks the manifests with the enabled checks. The schema is checked against each of the clusters,
+// clusters that can not be reached are skipped with an info finding. When partial is true the manifests are treated
+// as patches, only the schema is checked and missing required fields are allowed.
+func LintManifests(clusterIDs []string, manifests []string, partial bool) ([]*lint.Finding, error) {
+	settings, err := GetManifestLintSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest lint settings, err: %s", err)
+	}
+
+	docs := make([]string, 0, len(manifests))
+	for _, manifest := range manifests {
+		if strings.TrimSpace(manifest) == "" {
+			continue
+		}
+		docs = append(docs, systemVariableRegex.ReplaceAllString(manifest, "placeholder"))
+	}
+	findings := make([]*lint.Finding, 0)
+	if len(docs) == 0 {
+		return findings, nil
+	}
+
+	if settings.SchemaValidation {
+		for _, clusterID := range sets.NewString(clusterIDs...).List() {
+			validator, err := getSchemaValidator(clusterID)
+			if err != nil {
+				log.Warnf("failed to get schema validator of cluster %s, err: %s", clusterID, err)
+				findings = append(findings, &lint.Finding{
+					Source:    lint.SourceSchema,
+					Level:     lint.LevelInfo,
+					Message:   fmt.Sprintf("schema validation skipped: %s", err),
+					ClusterID: clusterID,
+				})
+				continue
+			}
+			for _, finding := range validator.Validate(docs, partial) {
+				finding.ClusterID = clusterID
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	if partial {
+		return findings, nil
+	}
+
+	if settings.KubeScore {
+		kubeScoreFindings, err := lint.KubeScore(docs)
+		if err != nil {
+			log.Warnf("failed to run kube-score, err: %s", err)
+			kubeScoreFindings = []*lint.Finding{{Source: lint.SourceKubeScore, Level: lint.LevelInfo, Message: fmt.Sprintf("kube-score skipped: %s", err)}}
+		}
+		findings = append(findings, kubeScoreFindings...)
+	}
+	if settings.Polaris {
+		polarisFindings, err := lint.Polaris(docs)
+		if err != nil {
+			log.Warnf("failed to run polaris, err: %s", err)
+			polarisFindings = []*lint.Finding{{Source: lint.SourcePolaris, Level: lint.LevelInfo, Message: fmt.Sprintf("polaris skipped: %s", err)}}
+		}
+		findings = append(findings, polarisFindings...)
+	}
+	return findings, nil
+}
+
+// ShouldBlockManifests returns an error if the findings contain errors and the settings require to reject them
+func ShouldBlockManifests(findings []*lint.Finding) error {
+	if !lint.HasError(findings) {
+		return nil
+	}
+	settings, err := GetManifestLintSettings()
+	if err != nil || !settings.BlockOnError {
+		return nil
+	}
+	msgs := make([]string, 0)
+	for _, finding := range findings {
+		if finding.Level != lint.LevelError {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("[%s] %s/%s: %s", finding.Source, finding.Kind, finding.Name, finding.Message))
+	}
+	return fmt.Errorf("manifest check failed:\n%s", strings.Join(msgs, "\n"))
+}
@@ -19,6 +19,9 @@ package kube
 import (
 	"regexp"
 	"strings"
+
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
 )
 
 const (
@@ -30,6 +33,7 @@ const (
 	envRegexString       = `\$EnvName\$`
 	productRegexString   = `\$Product\$`
 	serviceRegexString   = `\$Service\$`
+	hostnameRegexString  = `\$Hostname\$`
 )
 
 var (
@@ -41,6 +45,7 @@ var (
 	productRegex   = regexp.MustCompile(productRegexString)
 	envNameRegex   = regexp.MustCompile(envRegexString)
 	serviceRegex   = regexp.MustCompile(serviceRegexString)
+	hostnameRegex  = regexp.MustCompile(hostnameRegexString)
 )
 
 // ParseSysKeys 渲染系统变量键值
@@ -49,5 +54,26 @@ func ParseSysKeys(namespace, envName, productName, serviceName, ori string) stri
 	ori = namespaceRegex.ReplaceAllLiteralString(ori, strings.ToLower(namespace))
 	ori = productRegex.ReplaceAllLiteralString(ori, strings.ToLower(productName))
 	ori = serviceRegex.ReplaceAllLiteralString(ori, strings.ToLower(serviceName))
+	if hostnameRegex.MatchString(ori) {
+		ori = hostnameRegex.ReplaceAllLiteralString(ori, GetAllocatedHostname(productName, envName, namespace, serviceName))
+	}
 	return ori
 }
+
+// GetAllocatedHostname returns the hostname allocated by the hostname policy of the project,
+// it's empty if there is no enabled policy.
+func GetAllocatedHostname(productName, envName, namespace, serviceName string) string {
+	policy, err := commonrepo.NewHostnamePolicyColl().Find(productName)
+	if err != nil || !policy.Enabled {
+		return ""
+	}
+
+	production := false
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: productName, EnvName: envName})
+	if err != nil {
+		log.Warnf("failed to find env %s/%s to render hostname: %s", productName, envName, err)
+	} else {
+		production = env.Production
+	}
+	return policy.Render(production, productName, envName, namespace, serviceName)
+}
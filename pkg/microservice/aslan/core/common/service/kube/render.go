@@ -465,11 +465,12 @@ func GenerateRenderedYaml(option *GeneSvcYamlOption) (string, int, []*WorkloadRe
 
 	var prodSvcTemplate, latestSvcTemplate *commonmodels.Service
 
+	// the pinned revision is used as the latest one if the service is pinned in the env
 	latestSvcTemplate, err = repository.QueryTemplateService(&commonrepo.ServiceFindOption{
 		ProductName:         option.ProductName,
 		ServiceName:         option.ServiceName,
 		ExcludeStatus:       setting.ProductStatusDeleting,
-		Revision:            0,
+		Revision:            productInfo.GetPinnedServiceRevision(option.ServiceName),
 		IgnoreNoDocumentErr: true,
 	}, productInfo.Production)
 	if err != nil {
@@ -533,6 +534,10 @@ func GenerateRenderedYaml(option *GeneSvcYamlOption) (string, int, []*WorkloadRe
 		return "", 0, nil, err
 	}
 	fullRenderedYaml = ParseSysKeys(productInfo.Namespace, productInfo.EnvName, option.ProductName, option.ServiceName, fullRenderedYaml)
+	fullRenderedYaml, err = RemapClusterResources(fullRenderedYaml, productInfo.ClusterRemap)
+	if err != nil {
+		return "", 0, nil, errors.Wrapf(err, "failed to remap cluster resources")
+	}
 
 	// service may not be deployed in environment, we need to extract containers again, since image related variables may be changed
 	latestSvcTemplate.KubeYamls = util.SplitYaml(fullRenderedYaml)
@@ -577,6 +582,10 @@ func RenderEnvServiceWithTempl(prod *commonmodels.Product, serviceRender *templa
 		return "", err
 	}
 	parsedYaml = ParseSysKeys(prod.Namespace, prod.EnvName, prod.ProductName, service.ServiceName, parsedYaml)
+	parsedYaml, err = RemapClusterResources(parsedYaml, prod.ClusterRemap)
+	if err != nil {
+		return "", err
+	}
 	parsedYaml, _, err = ReplaceWorkloadImages(parsedYaml, service.Containers)
 	return parsedYaml, err
 }
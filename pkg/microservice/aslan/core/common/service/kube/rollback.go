@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+)
+
+type EnvRollbackArgs struct {
+	ProjectName  string                `json:"project_name"`
+	EnvName      string                `json:"env_name"`
+	Production   bool                  `json:"production"`
+	Source       config.RollbackSource `json:"source"`
+	WorkflowName string                `json:"workflow_name"`
+	TaskID       int64                 `json:"task_id"`
+	SnapshotID   string                `json:"snapshot_id"`
+	Services     []string              `json:"services"`
+}
+
+// FindRollbackServiceVersions finds the versions the services should be rolled back to.
+// For a workflow task, it is the latest version recorded before the task finished, for an env snapshot, it is the service in the snapshot.
+// The versions are returned in the same order as args.Services.
+func FindRollbackServiceVersions(args *EnvRollbackArgs) ([]*commonmodels.EnvServiceVersion, error) {
+	if len(args.Services) == 0 {
+		return nil, fmt.Errorf("no service is selected")
+	}
+
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       args.ProjectName,
+		EnvName:    args.EnvName,
+		Production: &args.Production,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find env %s/%s, error: %v", args.ProjectName, args.EnvName, err)
+	}
+	currentSvcMap, currentChartMap := env.GetServiceMap(), env.GetChartServiceMap()
+
+	resp := make([]*commonmodels.EnvServiceVersion, 0, len(args.Services))
+	switch args.Source {
+	case config.RollbackSourceWorkflowTask:
+		task, err := commonrepo.NewworkflowTaskv4Coll().Find(args.WorkflowName, args.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find workflow task %s#%d, error: %v", args.WorkflowName, args.TaskID, err)
+		}
+		if task.ProjectName != args.ProjectName {
+			return nil, fmt.Errorf("workflow task %s#%d does not belong to project %s", args.WorkflowName, args.TaskID, args.ProjectName)
+		}
+		if task.EndTime == 0 {
+			return nil, fmt.Errorf("workflow task %s#%d is not finished", args.WorkflowName, args.TaskID)
+		}
+
+		for _, serviceName := range args.Services {
+			isHelmChart := false
+			if _, ok := currentSvcMap[serviceName]; !ok {
+				if _, ok := currentChartMap[serviceName]; !ok {
+					return nil, fmt.Errorf("service %s is not in env %s", serviceName, args.EnvName)
+				}
+				isHelmChart = true
+			}
+			version, err := commonrepo.NewEnvServiceVersionColl().FindLatestBefore(args.ProjectName, args.EnvName, serviceName, isHelmChart, args.Production, task.EndTime)
+			if err != nil {
+				return nil, fmt.Errorf("no version of service %s was recorded before workflow task %s#%d finished, error: %v", serviceName, args.WorkflowName, args.TaskID, err)
+			}
+			resp = append(resp, version)
+		}
+	case config.RollbackSourceEnvSnapshot:
+		snapshot, err := commonrepo.NewEnvSnapshotColl().Find(args.ProjectName, args.EnvName, args.Production, args.SnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find snapshot %s of env %s, error: %v", args.SnapshotID, args.EnvName, err)
+		}
+
+		snapshotSvcMap := make(map[string]*commonmodels.ProductService)
+		for _, group := range snapshot.Services {
+			for _, svc := range group {
+				if svc.FromZadig() {
+					snapshotSvcMap[svc.ServiceName] = svc
+				} else {
+					snapshotSvcMap[svc.ReleaseName] = svc
+				}
+			}
+		}
+		for _, serviceName := range args.Services {
+			_, inEnv := currentSvcMap[serviceName]
+			if _, ok := currentChartMap[serviceName]; !ok && !inEnv {
+				return nil, fmt.Errorf("service %s is not in env %s", serviceName, args.EnvName)
+			}
+			svc, ok := snapshotSvcMap[serviceName]
+			if !ok {
+				return nil, fmt.Errorf("service %s is not in snapshot %s", serviceName, args.SnapshotID)
+			}
+			resp = append(resp, &commonmodels.EnvServiceVersion{
+				ProductName:     snapshot.ProductName,
+				EnvName:         snapshot.EnvName,
+				Namespace:       env.Namespace,
+				Production:      snapshot.Production,
+				Service:         svc,
+				DefaultValues:   snapshot.DefaultValues,
+				YamlData:        snapshot.YamlData,
+				GlobalVariables: snapshot.GlobalVariables,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("invalid rollback source: %s", args.Source)
+	}
+	return resp, nil
+}
+
+// NewEnvRollbackRecord creates the record of rolling back a service to the version, the status is not set
+func NewEnvRollbackRecord(args *EnvRollbackArgs, version *commonmodels.EnvServiceVersion, trigger config.RollbackTrigger, createdBy string) *commonmodels.EnvRollbackRecord {
+	record := &commonmodels.EnvRollbackRecord{
+		ProductName: args.ProjectName,
+		EnvName:     args.EnvName,
+		Production:  args.Production,
+		ServiceName: GetRollbackServiceName(version),
+		Source:      args.Source,
+		Revision:    version.Revision,
+		Images:      GetRollbackServiceImages(version),
+		Trigger:     trigger,
+		CreatedBy:   createdBy,
+	}
+	switch args.Source {
+	case config.RollbackSourceWorkflowTask:
+		record.WorkflowName, record.TaskID = args.WorkflowName, args.TaskID
+	case config.RollbackSourceEnvSnapshot:
+		record.SnapshotID = args.SnapshotID
+	}
+	return record
+}
+
+// RollbackEnvService rolls back the service to the version and saves the record with the result
+func RollbackEnvService(version *commonmodels.EnvServiceVersion, record *commonmodels.EnvRollbackRecord, sharedEnvHandler SharedEnvHandler, log *zap.SugaredLogger) error {
+	err := RollbackEnvServiceVersion(version, record.CreatedBy, sharedEnvHandler, log)
+	record.Status = config.StatusPassed
+	if err != nil {
+		record.Status = config.StatusFailed
+		record.Error = err.Error()
+	}
+
+	if createErr := commonrepo.NewEnvRollbackRecordColl().Create(record); createErr != nil {
+		log.Errorf("failed to create rollback record of service %s in env %s/%s, error: %v", record.ServiceName, record.ProductName, record.EnvName, createErr)
+	}
+	return err
+}
+
+func GetRollbackServiceName(version *commonmodels.EnvServiceVersion) string {
+	if version.Service.FromZadig() {
+		return version.Service.ServiceName
+	}
+	return version.Service.ReleaseName
+}
+
+func GetRollbackServiceImages(version *commonmodels.EnvServiceVersion) []string {
+	images := make([]string, 0, len(version.Service.Containers))
+	for _, container := range version.Service.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/sops"
+)
+
+// DecryptSopsValues decrypts the values encrypted by sops with the configured keys, other values are returned as they are.
+// The decrypted values must only be kept in memory, the encrypted ones are saved.
+// Every layer of the values, e.g. the default values of the env and the chart values of the service, is encrypted with its own
+// data key and mac, so the layers must be decrypted one by one before they are merged.
+func DecryptSopsValues(values string) (string, error) {
+	if !sops.IsEncrypted(values) {
+		return values, nil
+	}
+
+	sopsKeys, err := commonrepo.NewSopsKeyColl().List(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("failed to list sops keys, err: %s", err)
+	}
+	keys := &sops.Keys{}
+	for _, key := range sopsKeys {
+		switch key.Type {
+		case commonmodels.SopsKeyTypeAge:
+			keys.AgeIdentities = append(keys.AgeIdentities, key.AgeKey)
+		case commonmodels.SopsKeyTypeAWSKMS:
+			keys.KMS = append(keys.KMS, &sops.KMSCredential{
+				AccessKey: key.AccessKey,
+				SecretKey: key.SecretKey,
+			})
+		}
+	}
+
+	decrypted, err := sops.Decrypt(values, keys)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the values encrypted by sops, err: %s", err)
+	}
+	return decrypted, nil
+}
+
+// DecryptSopsRender returns a copy of the render with the chart values decrypted, the render itself is returned if
+// the chart values are not encrypted by sops, so the encrypted ones are never written back to the env.
+func DecryptSopsRender(render *templatemodels.ServiceRender) (*templatemodels.ServiceRender, error) {
+	if render == nil || !sops.IsEncrypted(render.GetOverrideYaml()) {
+		return render, nil
+	}
+
+	decrypted, err := DecryptSopsValues(render.GetOverrideYaml())
+	if err != nil {
+		return nil, err
+	}
+	ret := *render
+	overrideYaml := *render.OverrideYaml
+	overrideYaml.YamlContent = decrypted
+	ret.OverrideYaml = &overrideYaml
+	return &ret, nil
+}
+
+// HasSopsValues tells if any layer of the values of the service is encrypted by sops.
+func HasSopsValues(defaultValues string, render *templatemodels.ServiceRender) bool {
+	return sops.IsEncrypted(defaultValues) || (render != nil && sops.IsEncrypted(render.GetOverrideYaml()))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/updater"
+)
+
+// ValidateStatefulSetRollout checks the rollout setting, deleting pvcs must be confirmed explicitly and is
+// refused in production environments since the data can not be recovered.
+func ValidateStatefulSetRollout(rollout *commonmodels.StatefulSetRolloutSetting, production bool) error {
+	if rollout == nil {
+		return nil
+	}
+	if rollout.Partition != nil && *rollout.Partition < 0 {
+		return fmt.Errorf("statefulSet partition can not be negative")
+	}
+	switch appsv1.PodManagementPolicyType(rollout.PodManagementPolicy) {
+	case "", appsv1.OrderedReadyPodManagement, appsv1.ParallelPodManagement:
+	default:
+		return fmt.Errorf("invalid statefulSet pod management policy: %s", rollout.PodManagementPolicy)
+	}
+
+	policy := rollout.PVCRetentionPolicy
+	if policy == nil {
+		return nil
+	}
+	deletePVC := false
+	for _, p := range []string{policy.WhenDeleted, policy.WhenScaled} {
+		switch appsv1.PersistentVolumeClaimRetentionPolicyType(p) {
+		case appsv1.RetainPersistentVolumeClaimRetentionPolicyType:
+		case appsv1.DeletePersistentVolumeClaimRetentionPolicyType:
+			deletePVC = true
+		default:
+			return fmt.Errorf("invalid statefulSet pvc retention policy: %s", p)
+		}
+	}
+	if deletePVC && production {
+		return fmt.Errorf("pvc retention policy Delete is not allowed in production environments")
+	}
+	if deletePVC && !policy.ConfirmDelete {
+		return fmt.Errorf("pvc retention policy Delete removes the data of the statefulSet and must be confirmed")
+	}
+	return nil
+}
+
+// ApplyStatefulSetRollout applies the rollout setting to an existing statefulSet, it should be called before the
+// statefulSet is updated so that the partition takes effect on the update. Nothing is done if the statefulSet doesn't exist.
+func ApplyStatefulSetRollout(namespace, name string, rollout *commonmodels.StatefulSetRolloutSetting, kubeClient client.Client) error {
+	if rollout == nil {
+		return nil
+	}
+	sts, found, err := getter.GetStatefulSet(namespace, name, kubeClient)
+	if err != nil {
+		return fmt.Errorf("failed to get statefulSet %s/%s: %v", namespace, name, err)
+	}
+	if !found {
+		return nil
+	}
+
+	policy := appsv1.PodManagementPolicyType(rollout.PodManagementPolicy)
+	if policy != "" && policy != sts.Spec.PodManagementPolicy {
+		sts.Spec.PodManagementPolicy = policy
+		if err := updater.RecreateStatefulSetOrphan(sts, kubeClient); err != nil {
+			return fmt.Errorf("failed to change pod management policy of statefulSet %s/%s: %v", namespace, name, err)
+		}
+	}
+
+	if rollout.Partition != nil {
+		if err := updater.UpdateStatefulSetPartition(namespace, name, *rollout.Partition, kubeClient); err != nil {
+			return fmt.Errorf("failed to update partition of statefulSet %s/%s: %v", namespace, name, err)
+		}
+	}
+
+	if rollout.PVCRetentionPolicy != nil {
+		err := updater.UpdateStatefulSetPVCRetentionPolicy(namespace, name, &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+			WhenDeleted: appsv1.PersistentVolumeClaimRetentionPolicyType(rollout.PVCRetentionPolicy.WhenDeleted),
+			WhenScaled:  appsv1.PersistentVolumeClaimRetentionPolicyType(rollout.PVCRetentionPolicy.WhenScaled),
+		}, kubeClient)
+		if err != nil {
+			return fmt.Errorf("failed to update pvc retention policy of statefulSet %s/%s: %v", namespace, name, err)
+		}
+	}
+	return nil
+}
+
+// StatefulSetRolledOut reports whether the pods selected by the partition of the statefulSet are updated and ready,
+// the pods below the partition keep the current revision on purpose.
+func StatefulSetRolledOut(sts *appsv1.StatefulSet) bool {
+	if sts.Status.ObservedGeneration < sts.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	partition := int32(0)
+	if sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		partition = *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	expectedUpdated := replicas - partition
+	if expectedUpdated < 0 {
+		expectedUpdated = 0
+	}
+	return sts.Status.UpdatedReplicas >= expectedUpdated && sts.Status.ReadyReplicas == replicas
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	versionedclient "istio.io/client-go/pkg/clientset/versioned"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	helmtool "github.com/koderover/zadig/v2/pkg/tool/helmclient"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/informer"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+// RollbackEnvServiceVersion redeploys the service recorded in envSvcVersion into its env,
+// sharedEnvHandler is called for the workloads of a share env or an istio grayscale env.
+func RollbackEnvServiceVersion(envSvcVersion *commonmodels.EnvServiceVersion, userName string, sharedEnvHandler SharedEnvHandler, log *zap.SugaredLogger) error {
+	projectName, envName, serviceName, isProduction := envSvcVersion.ProductName, envSvcVersion.EnvName, envSvcVersion.Service.ServiceName, envSvcVersion.Production
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &isProduction,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find %s/%s env, isProduction %v, error: %v", projectName, envName, isProduction, err)
+	}
+
+	if envSvcVersion.Service.Type == setting.K8SDeployType {
+		kubeClient, err := kubeclient.GetKubeClient(config.HubServerAddress(), env.ClusterID)
+		if err != nil {
+			return err
+		}
+
+		restConfig, err := kubeclient.GetRESTConfig(config.HubServerAddress(), env.ClusterID)
+		if err != nil {
+			return err
+		}
+
+		istioClient, err := versionedclient.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+
+		cls, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), env.ClusterID)
+		if err != nil {
+			log.Errorf("[%s][%s] error: %v", envName, env.Namespace, err)
+			return err
+
+		}
+		informer, err := informer.NewInformer(env.ClusterID, env.Namespace, cls)
+		if err != nil {
+			log.Errorf("[%s][%s] error: %v", envName, env.Namespace, err)
+			return err
+		}
+
+		fakeEnv := &commonmodels.Product{
+			ProductName: envSvcVersion.ProductName,
+			EnvName:     envSvcVersion.EnvName,
+			Namespace:   envSvcVersion.Namespace,
+			Production:  envSvcVersion.Production,
+		}
+		parsedYaml, err := RenderEnvService(fakeEnv, envSvcVersion.Service.GetServiceRender(), envSvcVersion.Service)
+		if err != nil {
+			err = fmt.Errorf("Failed to render env %s, service %s, revision %d, error: %v", envSvcVersion.EnvName, envSvcVersion.Service.ServiceName, envSvcVersion.Service.Revision, err)
+			return err
+		}
+
+		preProdSvc := env.GetServiceMap()[envSvcVersion.Service.ServiceName]
+		if preProdSvc == nil {
+			return fmt.Errorf("failed to find service %s in env %s", envSvcVersion.Service.ServiceName, envSvcVersion.EnvName)
+		}
+		preResourceYaml, err := RenderEnvService(env, preProdSvc.GetServiceRender(), preProdSvc)
+		if err != nil {
+			err = fmt.Errorf("Failed to render env %s, service %s, revision %d, error: %v", envSvcVersion.EnvName, envSvcVersion.Service.ServiceName, envSvcVersion.Service.Revision, err)
+			return err
+		}
+
+		err = CheckResourceAppliedByOtherEnv(parsedYaml, env, envSvcVersion.Service.ServiceName)
+		if err != nil {
+			return err
+		}
+
+		resourceApplyParam := &ResourceApplyParam{
+			ProductInfo:         env,
+			ServiceName:         envSvcVersion.Service.ServiceName,
+			CurrentResourceYaml: preResourceYaml,
+			UpdateResourceYaml:  parsedYaml,
+			Informer:            informer,
+			KubeClient:          kubeClient,
+			IstioClient:         istioClient,
+			InjectSecrets:       true,
+			Uninstall:           false,
+			AddZadigLabel:       !isProduction,
+			SharedEnvHandler:    sharedEnvHandler,
+		}
+
+		unstructuredList, err := CreateOrPatchResource(resourceApplyParam, log)
+		if err != nil {
+			return fmt.Errorf("failed to create or patch resource for env %s, service %s, revision %d, error: %v", envSvcVersion.EnvName, envSvcVersion.Service.ServiceName, envSvcVersion.Service.Revision, err)
+		}
+
+		session := mongotool.Session()
+		defer session.EndSession(context.Background())
+
+		err = mongotool.StartTransaction(session)
+		if err != nil {
+			return err
+		}
+
+		err = commonutil.CreateEnvServiceVersion(env, envSvcVersion.Service, userName, session, log)
+		if err != nil {
+			log.Errorf("failed to create env service version for service %s/%s, error: %v", envSvcVersion.EnvName, envSvcVersion.Service.ServiceName, err)
+		}
+
+		groupIndex := -1
+		svcIndex := -1
+		for i, group := range env.Services {
+			for j, svc := range group {
+				if svc.ServiceName == envSvcVersion.Service.ServiceName {
+					svcIndex = j
+					groupIndex = i
+					svc.Resources = UnstructuredToResources(unstructuredList)
+					for _, kv := range envSvcVersion.Service.GetServiceRender().OverrideYaml.RenderVariableKVs {
+						kv.UseGlobalVariable = false
+					}
+					break
+				}
+			}
+		}
+		if groupIndex < 0 || svcIndex < 0 {
+			mongotool.AbortTransaction(session)
+			return fmt.Errorf("failed to find service %s in env %s/%s, isProudction %v", envSvcVersion.Service.ServiceName, envSvcVersion.ProductName, envSvcVersion.EnvName, envSvcVersion.Production)
+		}
+
+		env.Services[groupIndex][svcIndex] = envSvcVersion.Service
+		err = commonrepo.NewProductCollWithSession(session).UpdateGroup(envName, projectName, groupIndex, env.Services[groupIndex])
+		if err != nil {
+			mongotool.AbortTransaction(session)
+			return fmt.Errorf("failed to update service %s in env %s/%s, isProudction %v", envSvcVersion.Service.ServiceName, envSvcVersion.ProductName, envSvcVersion.EnvName, envSvcVersion.Production)
+		}
+
+		for _, globalKV := range env.GlobalVariables {
+			relatedServiceSet := sets.NewString(globalKV.RelatedServices...)
+			if relatedServiceSet.Has(serviceName) {
+				relatedServiceSet.Delete(serviceName)
+			}
+			globalKV.RelatedServices = relatedServiceSet.List()
+		}
+		err = commonrepo.NewProductCollWithSession(session).UpdateGlobalVariable(env)
+		if err != nil {
+			mongotool.AbortTransaction(session)
+			return fmt.Errorf("failed to update global variables in env %s/%s, isProudction %v", envSvcVersion.ProductName, envSvcVersion.EnvName, envSvcVersion.Production)
+		}
+		err = mongotool.CommitTransaction(session)
+		if err != nil {
+			return err
+		}
+	} else if envSvcVersion.Service.Type == setting.HelmDeployType || envSvcVersion.Service.Type == setting.HelmChartDeployType {
+		var svcTmpl *commonmodels.Service
+		if envSvcVersion.Service.Type == setting.HelmDeployType {
+			svcTmpl, err = commonrepo.NewServiceColl().Find(&commonrepo.ServiceFindOption{
+				ProductName: envSvcVersion.ProductName,
+				ServiceName: envSvcVersion.Service.ServiceName,
+				Type:        envSvcVersion.Service.Type,
+				Revision:    envSvcVersion.Service.Revision,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to find service temlate %s/%s/%d, error: %v", envSvcVersion.EnvName, envSvcVersion.Service.ServiceName, envSvcVersion.Service.Revision, err)
+			}
+		}
+
+		// if env.DefaultValues != "" {
+		// 	mergedValues, err := helmtool.MergeOverrideValues("", envSvcVersion.DefaultValues, envSvcVersion.Service.GetServiceRender().GetOverrideYaml(), envSvcVersion.Service.GetServiceRender().OverrideValues, nil)
+		// 	if err != nil {
+		// 		return fmt.Errorf("failed to merge service %s's override yaml %s and values %s, err: %s", envSvcVersion.Service.ServiceName, envSvcVersion.Service.GetServiceRender().GetOverrideYaml(), envSvcVersion.Service.GetServiceRender().OverrideValues, err)
+		// 	}
+
+		// 	mergedValuesFlatMap, err := converter.YamlToFlatMap([]byte(mergedValues))
+		// 	if err != nil {
+		// 		return fmt.Errorf("failed to convert mergedSvcValues to flatMap, err: %s", err)
+		// 	}
+		// 	defaultValuesFlatMap, err := converter.YamlToFlatMap([]byte(env.DefaultValues))
+		// 	if err != nil {
+		// 		return fmt.Errorf("failed to convert defaultValues to flatMap, err: %s", err)
+		// 	}
+
+		// 	// in current env's defaultValues, but not in service's mergedValues, add it to needToAddValues
+		// 	needToAddValuesFlatMap := make(map[string]interface{})
+		// 	for defaultKey, defaultValue := range defaultValuesFlatMap {
+		// 		if _, ok := mergedValuesFlatMap[defaultKey]; !ok {
+		// 			needToAddValuesFlatMap[defaultKey] = defaultValue
+		// 		}
+		// 	}
+
+		// 	if envSvcVersion.Service.Type == setting.HelmDeployType {
+		// 		svcTmplValuesFlatMap, err := converter.YamlToFlatMap([]byte(svcTmpl.HelmChart.ValuesYaml))
+		// 		if err != nil {
+		// 			return fmt.Errorf("failed to convert template service %s's values yaml to flatMap, err: %v", svcTmpl.ServiceName, err)
+		// 		}
+		// 		for key, value := range needToAddValuesFlatMap {
+		// 			if v, ok := svcTmplValuesFlatMap[key]; !ok {
+		// 				// in needToAddValues, but not in service's chart values, add it to mergedValues and set value from needToAddValues
+		// 				mergedValuesFlatMap[key] = value
+		// 			} else {
+		// 				// in needToAddValues, and in service's chart values, add it to mergedValues and set value from template serivce values
+		// 				mergedValuesFlatMap[key] = v
+		// 			}
+		// 		}
+		// 	} else if envSvcVersion.Service.Type == setting.HelmChartDeployType {
+		// 		chartRepoName := envSvcVersion.Service.GetServiceRender().ChartRepo
+		// 		chartName := envSvcVersion.Service.GetServiceRender().ChartName
+		// 		chartVersion := envSvcVersion.Service.GetServiceRender().ChartVersion
+		// 		chartRepo, err := commonrepo.NewHelmRepoColl().Find(&commonrepo.HelmRepoFindOption{RepoName: chartRepoName})
+		// 		if err != nil {
+		// 			return fmt.Errorf("failed to query chart-repo info, productName: %s, repoName: %s", env.ProductName, chartRepoName)
+		// 		}
+
+		// 		hClient, err := helmclient.NewClient()
+		// 		if err != nil {
+		// 			return err
+		// 		}
+
+		// 		valuesYaml, err := hClient.GetChartValues(commonutil.GeneHelmRepo(chartRepo), env.ProductName, serviceName, chartRepoName, chartName, chartVersion)
+		// 		if err != nil {
+		// 			return fmt.Errorf("failed to get chart values, chartRepo: %s, chartName: %s, chartVersion: %s, err %s", chartRepoName, chartName, chartVersion, err)
+		// 		}
+		// 		valuesYamlFlatMap, err := converter.YamlToFlatMap([]byte(valuesYaml))
+		// 		if err != nil {
+		// 			return fmt.Errorf("failed to convert mergedSvcValues to flatMap, err: %s", err)
+		// 		}
+
+		// 		for key, value := range needToAddValuesFlatMap {
+		// 			if v, ok := valuesYamlFlatMap[key]; !ok {
+		// 				// in needToAddValues, but not in service's chart values, add it to mergedValues and set value from needToAddValues
+		// 				mergedValuesFlatMap[key] = value
+		// 			} else {
+		// 				// in needToAddValues, and in service's chart values, add it to mergedValues and set value from chart values
+		// 				mergedValuesFlatMap[key] = v
+		// 			}
+		// 		}
+		// 	}
+
+		// 	mergedValuesByte, err := yaml.Marshal(mergedValuesFlatMap)
+		// 	if err != nil {
+		// 		return fmt.Errorf("failed to mashal mergedValuesFlatMap, err: %s", err)
+		// 	}
+
+		// 	envSvcVersion.Service.GetServiceRender().OverrideYaml.YamlContent = string(mergedValuesByte)
+		// 	envSvcVersion.Service.GetServiceRender().OverrideValues = ""
+		// } else {
+		mergedValues, err := helmtool.MergeOverrideValues("", envSvcVersion.DefaultValues, envSvcVersion.Service.GetServiceRender().GetOverrideYaml(), envSvcVersion.Service.GetServiceRender().OverrideValues, nil)
+		if err != nil {
+			return fmt.Errorf("failed to merge service %s's override yaml %s and values %s, err: %s", envSvcVersion.Service.ServiceName, envSvcVersion.Service.GetServiceRender().GetOverrideYaml(), envSvcVersion.Service.GetServiceRender().OverrideValues, err)
+		}
+
+		envSvcVersion.Service.GetServiceRender().OverrideYaml.YamlContent = mergedValues
+		envSvcVersion.Service.GetServiceRender().OverrideValues = ""
+		env.DefaultValues = ""
+		// }
+
+		err = UpgradeHelmRelease(env, envSvcVersion.Service, svcTmpl, nil, 0, userName)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade helm release for env %s, service %s, revision %d, error: %v", envSvcVersion.EnvName, envSvcVersion.Service.ServiceName, envSvcVersion.Service.Revision, err)
+		}
+	}
+
+	return nil
+}
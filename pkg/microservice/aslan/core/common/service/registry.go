@@ -22,14 +22,12 @@ import (
 	"go.uber.org/zap"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
 	"github.com/koderover/zadig/v2/pkg/tool/crypto"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
-	"github.com/koderover/zadig/v2/pkg/util"
 )
 
 func FindRegistryById(registryId string, getRealCredential bool, log *zap.SugaredLogger) (reg *models.RegistryNamespace, err error) {
@@ -98,18 +96,8 @@ func ListRegistryNamespaces(encryptedKey string, getRealCredential bool, log *za
 	}
 
 	for _, reg := range resp {
-		switch reg.RegProvider {
-		case config.RegistryTypeSWR:
-			reg.SecretKey = util.ComputeHmacSha256(reg.AccessKey, reg.SecretKey)
-			reg.AccessKey = fmt.Sprintf("%s@%s", reg.Region, reg.AccessKey)
-		case config.RegistryTypeAWS:
-			realAK, realSK, err := commonutil.GetAWSRegistryCredential(reg.ID.Hex(), reg.AccessKey, reg.SecretKey, reg.Region)
-			if err != nil {
-				log.Errorf("Failed to get keypair from aws, the error is: %s", err)
-				return nil, err
-			}
-			reg.AccessKey = realAK
-			reg.SecretKey = realSK
+		if _, err := commonutil.DecodeRegistry(reg); err != nil {
+			return nil, err
 		}
 		if len(encryptedKey) == 0 {
 			continue
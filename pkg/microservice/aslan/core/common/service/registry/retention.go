@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// RetentionRule describes which tags of a repository are retained, the tags not retained by any rule are expired.
+type RetentionRule struct {
+	// KeepLastN keeps the newest N tags of the repository
+	KeepLastN int
+	// KeepTagRegex keeps the tags matching the regular expression
+	KeepTagRegex string
+	// MinAgeDays keeps the tags created in the last N days
+	MinAgeDays int
+}
+
+type TagInfo struct {
+	Tag    string
+	Digest string
+	// Created is zero if the creation time of the tag is unknown
+	Created time.Time
+}
+
+var imageCreationTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02T15:04:05",
+}
+
+// ParseImageCreationTime parses the creation time returned by the registries, a zero time is returned if it can't be parsed.
+func ParseImageCreationTime(created string) time.Time {
+	for _, layout := range imageCreationTimeLayouts {
+		if t, err := time.Parse(layout, created); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// SelectExpiredTags returns the tags which are not retained by the rule.
+// The tags with unknown creation time are always retained, and so are the tags sharing the digest with a retained one,
+// since deleting the manifest removes every tag of it.
+func SelectExpiredTags(tags []*TagInfo, rule *RetentionRule, now time.Time) ([]*TagInfo, error) {
+	var keepRegex *regexp.Regexp
+	if rule.KeepTagRegex != "" {
+		var err error
+		keepRegex, err = regexp.Compile(rule.KeepTagRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keep tag regex %s: %s", rule.KeepTagRegex, err)
+		}
+	}
+
+	sorted := make([]*TagInfo, 0, len(tags))
+	keptDigests := make(map[string]bool)
+	for _, tag := range tags {
+		if tag.Created.IsZero() {
+			keptDigests[tag.Digest] = true
+			continue
+		}
+		sorted = append(sorted, tag)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Created.After(sorted[j].Created)
+	})
+
+	minAgeTime := now.AddDate(0, 0, -rule.MinAgeDays)
+	candidates := make([]*TagInfo, 0)
+	for i, tag := range sorted {
+		if i < rule.KeepLastN ||
+			(keepRegex != nil && keepRegex.MatchString(tag.Tag)) ||
+			(rule.MinAgeDays > 0 && tag.Created.After(minAgeTime)) {
+			keptDigests[tag.Digest] = true
+			continue
+		}
+		candidates = append(candidates, tag)
+	}
+
+	expired := make([]*TagInfo, 0, len(candidates))
+	for _, tag := range candidates {
+		if tag.Digest != "" && keptDigests[tag.Digest] {
+			continue
+		}
+		expired = append(expired, tag)
+	}
+	return expired, nil
+}
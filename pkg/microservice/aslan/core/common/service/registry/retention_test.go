@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectExpiredTags(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.AddDate(0, 0, -days)
+	}
+	tags := []*TagInfo{
+		{Tag: "20240601000000-1-main", Digest: "sha256:a", Created: daysAgo(29)},
+		{Tag: "20240629000000-5-main", Digest: "sha256:e", Created: daysAgo(1)},
+		{Tag: "v1.0.0", Digest: "sha256:b", Created: daysAgo(40)},
+		{Tag: "20240610000000-3-main", Digest: "sha256:c", Created: daysAgo(20)},
+		{Tag: "20240605000000-2-main", Digest: "sha256:d", Created: daysAgo(25)},
+		{Tag: "latest", Digest: "sha256:e", Created: daysAgo(1)},
+		{Tag: "unknown", Digest: "sha256:f"},
+		{Tag: "20240501000000-0-main", Digest: "sha256:f", Created: daysAgo(60)},
+	}
+
+	tagNames := func(tags []*TagInfo) []string {
+		names := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			names = append(names, tag.Tag)
+		}
+		return names
+	}
+
+	expired, err := SelectExpiredTags(tags, &RetentionRule{KeepLastN: 2}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"20240610000000-3-main", "20240605000000-2-main", "20240601000000-1-main", "v1.0.0"}, tagNames(expired))
+
+	expired, err = SelectExpiredTags(tags, &RetentionRule{KeepLastN: 1, KeepTagRegex: `^v\d+`, MinAgeDays: 21}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"20240605000000-2-main", "20240601000000-1-main"}, tagNames(expired))
+
+	_, err = SelectExpiredTags(tags, &RetentionRule{KeepTagRegex: "("}, now)
+	assert.Error(t, err)
+}
+
+func TestParseImageCreationTime(t *testing.T) {
+	expected := time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC)
+	assert.True(t, expected.Equal(ParseImageCreationTime("2024-06-01T08:30:00.000000000Z")))
+	assert.True(t, expected.Equal(ParseImageCreationTime("2024-06-01 08:30:00 +0000 UTC")))
+	assert.True(t, ParseImageCreationTime("").IsZero())
+}
@@ -76,9 +76,16 @@ type GetRepoImageDetailOption struct {
 	Tag   string
 }
 
+type DeleteImageTagOption struct {
+	Endpoint
+	Image string
+	Tag   string
+}
+
 type Service interface {
 	ListRepoImages(option ListRepoImagesOption, log *zap.SugaredLogger) (*ReposResp, error)
 	GetImageInfo(option GetRepoImageDetailOption, log *zap.SugaredLogger) (*commonmodels.DeliveryImage, error)
+	DeleteImageTag(option DeleteImageTagOption, log *zap.SugaredLogger) error
 }
 
 func NewV2Service(provider string, tlsEnabled bool, tlsCert string) Service {
@@ -174,7 +181,46 @@ func (s *v2RegistryService) createClient(ep Endpoint, logger *zap.SugaredLogger)
 	return
 }
 
-func (c *authClient) getRepository(repoName string) (repo distribution.Repository, err error) {
+// CheckLogin verifies the credential against the /v2/ endpoint of the registry like docker login does,
+// the endpoint should carry the decoded credential.
+func CheckLogin(ep Endpoint, tlsEnabled bool, tlsCert string, logger *zap.SugaredLogger) error {
+	s := &v2RegistryService{
+		EnableHTTPS: tlsEnabled,
+		CustomCert:  tlsCert,
+	}
+	cli, err := s.createClient(ep, logger)
+	if err != nil {
+		return fmt.Errorf("failed to ping registry %s: %s", ep.Addr, err)
+	}
+
+	creds := registry.NewStaticCredentialStore(&types.AuthConfig{
+		Username:      ep.Ak,
+		Password:      ep.Sk,
+		ServerAddress: ep.Addr,
+	})
+	tokenHandler := auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+		Transport:   cli.tr,
+		Credentials: creds,
+		ClientID:    registry.AuthClientID,
+	})
+	tr := transport.NewTransport(cli.tr, auth.NewAuthorizer(cli.cm, tokenHandler, auth.NewBasicHandler(creds)))
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(cli.endpointURL.String(), "/")+"/v2/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Transport: tr, Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to login registry %s: %s", ep.Addr, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to login registry %s, status code: %d", ep.Addr, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *authClient) getRepository(repoName string, actions ...string) (repo distribution.Repository, err error) {
 	repoNameRef, err := reference.WithName(repoName)
 	if err != nil {
 		return
@@ -189,7 +235,7 @@ func (c *authClient) getRepository(repoName string) (repo distribution.Repositor
 	basicHandler := auth.NewBasicHandler(creds)
 	scope := auth.RepositoryScope{
 		Repository: repoName,
-		Actions:    []string{"pull"},
+		Actions:    actions,
 		Class:      "",
 	}
 
@@ -213,7 +259,7 @@ func (c *authClient) getRepository(repoName string) (repo distribution.Repositor
 }
 
 func (c *authClient) listTags(repoName string) (tags []string, err error) {
-	repo, err := c.getRepository(repoName)
+	repo, err := c.getRepository(repoName, "pull")
 	if err != nil {
 		return
 	}
@@ -236,7 +282,7 @@ type containerInfo struct {
 }
 
 func (c *authClient) getImageInfo(repoName, tag string) (ci *containerInfo, err error) {
-	repo, err := c.getRepository(repoName)
+	repo, err := c.getRepository(repoName, "pull")
 	if err != nil {
 		return
 	}
@@ -286,6 +332,26 @@ func (c *authClient) getImageInfo(repoName, tag string) (ci *containerInfo, err
 	return
 }
 
+// deleteTag deletes the manifest referenced by the tag, which also removes the other tags of the same digest.
+func (c *authClient) deleteTag(repoName, tag string) error {
+	repo, err := c.getRepository(repoName, "pull", "delete")
+	if err != nil {
+		return err
+	}
+
+	desc, err := repo.Tags(c.ctx).Get(c.ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	manifestService, err := repo.Manifests(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	return manifestService.Delete(c.ctx, desc.Digest)
+}
+
 func (s *v2RegistryService) GetImageInfo(option GetRepoImageDetailOption, log *zap.SugaredLogger) (di *commonmodels.DeliveryImage, err error) {
 	cli, err := s.createClient(option.Endpoint, log)
 	if err != nil {
@@ -311,6 +377,19 @@ func (s *v2RegistryService) GetImageInfo(option GetRepoImageDetailOption, log *z
 	}, nil
 }
 
+func (s *v2RegistryService) DeleteImageTag(option DeleteImageTagOption, log *zap.SugaredLogger) error {
+	cli, err := s.createClient(option.Endpoint, log)
+	if err != nil {
+		return err
+	}
+
+	img := strings.Join([]string{option.Namespace, option.Image}, "/")
+	if err := cli.deleteTag(img, option.Tag); err != nil {
+		return errors.Wrapf(err, "failed to delete image %s:%s", img, option.Tag)
+	}
+	return nil
+}
+
 type ReverseStringSlice []string
 
 // Len is the number of elements in the collection.
@@ -476,6 +555,21 @@ func (s *swrService) GetImageInfo(option GetRepoImageDetailOption, log *zap.Suga
 	return &commonmodels.DeliveryImage{}, nil
 }
 
+func (s *swrService) DeleteImageTag(option DeleteImageTagOption, log *zap.SugaredLogger) error {
+	swrCli := s.createClient(option.Endpoint)
+
+	request := &model.DeleteRepoTagRequest{
+		ContentType: model.GetDeleteRepoTagRequestContentTypeEnum().APPLICATION_JSONCHARSETUTF_8,
+		Namespace:   option.Namespace,
+		Repository:  option.Image,
+		Tag:         option.Tag,
+	}
+	if _, err := swrCli.DeleteRepoTag(request); err != nil {
+		return errors.Wrapf(err, "failed to delete image %s:%s", option.Image, option.Tag)
+	}
+	return nil
+}
+
 type ecrService struct {
 }
 
@@ -576,3 +670,26 @@ func (s *ecrService) GetImageInfo(option GetRepoImageDetailOption, log *zap.Suga
 	}
 	return &commonmodels.DeliveryImage{}, nil
 }
+
+func (s *ecrService) DeleteImageTag(option DeleteImageTagOption, log *zap.SugaredLogger) error {
+	svc, err := s.getECRService(option.Endpoint, log)
+	if err != nil {
+		return err
+	}
+	input := &ecr.BatchDeleteImageInput{
+		ImageIds: []*ecr.ImageIdentifier{
+			{
+				ImageTag: aws.String(option.Tag),
+			},
+		},
+		RepositoryName: aws.String(option.Image),
+	}
+	result, err := svc.BatchDeleteImage(input)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete image %s:%s", option.Image, option.Tag)
+	}
+	for _, failure := range result.Failures {
+		return fmt.Errorf("failed to delete image %s:%s: %s", option.Image, option.Tag, aws.StringValue(failure.FailureReason))
+	}
+	return nil
+}
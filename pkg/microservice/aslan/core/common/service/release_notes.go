@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openapi "github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/llm"
+	"github.com/koderover/zadig/v2/pkg/types"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+// releaseNotesPrompt asks the llm to draft markdown release notes from the changes of the tasks
+const releaseNotesPrompt = `你是一个资深的研发效能专家，我会提供一份用三重引号分割的发布数据，其中包含本次发布涉及的代码提交、合并请求以及服务变更。
+请根据这些数据为最终用户撰写一份易读的发布说明草稿，要求：
+1. 使用 markdown 格式输出，不要包含 markdown 之外的任何内容，也不要使用代码块包裹整个回答；
+2. 按照"新功能"、"问题修复"、"优化改进"、"服务变更"等章节归类，没有内容的章节不要输出；
+3. 合并同一改动的多个提交，忽略无实际意义的提交（如 merge、格式调整）；
+4. 不要编造数据中不存在的改动。
+`
+
+type ReleaseNotesSource struct {
+	Title       string
+	Description string
+	Tasks       []*models.WorkflowTask
+	// Notes are the free text contents of the release, e.g. the text jobs of a release plan
+	Notes []string
+}
+
+type ReleaseNotesDraft struct {
+	Markdown string                 `json:"markdown"`
+	Commits  []*ReleaseNotesCommit  `json:"commits"`
+	Services []*ReleaseNotesService `json:"services"`
+}
+
+type ReleaseNotesCommit struct {
+	Source   string `json:"source"`
+	RepoName string `json:"repo_name"`
+	Branch   string `json:"branch,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	PRs      []int  `json:"prs,omitempty"`
+	CommitID string `json:"commit_id"`
+	Message  string `json:"message"`
+	Author   string `json:"author,omitempty"`
+}
+
+type ReleaseNotesService struct {
+	ServiceName   string `json:"service_name"`
+	ServiceModule string `json:"service_module"`
+	Env           string `json:"env,omitempty"`
+	Image         string `json:"image,omitempty"`
+}
+
+// DraftReleaseNotes collects the commits, pull requests and service changes of the tasks
+// and asks the default llm for a markdown release notes draft. The draft is not saved,
+// users are expected to edit it before publishing.
+func DraftReleaseNotes(source *ReleaseNotesSource, logger *zap.SugaredLogger) (*ReleaseNotesDraft, error) {
+	resp := &ReleaseNotesDraft{
+		Commits:  make([]*ReleaseNotesCommit, 0),
+		Services: make([]*ReleaseNotesService, 0),
+	}
+	commitSet := make(map[string]bool)
+	serviceSet := make(map[string]bool)
+	for _, task := range source.Tasks {
+		for _, stage := range task.Stages {
+			for _, job := range stage.Jobs {
+				commits, services := getReleaseNotesChanges(job)
+				for _, commit := range commits {
+					key := fmt.Sprintf("%s/%s/%s", commit.Source, commit.RepoName, commit.CommitID)
+					if commitSet[key] {
+						continue
+					}
+					commitSet[key] = true
+					resp.Commits = append(resp.Commits, commit)
+				}
+				for _, svc := range services {
+					key := fmt.Sprintf("%s/%s/%s/%s", svc.Env, svc.ServiceName, svc.ServiceModule, svc.Image)
+					if serviceSet[key] {
+						continue
+					}
+					serviceSet[key] = true
+					resp.Services = append(resp.Services, svc)
+				}
+			}
+		}
+	}
+	if len(resp.Commits) == 0 && len(resp.Services) == 0 && len(source.Notes) == 0 {
+		return nil, e.ErrDraftReleaseNotes.AddDesc("no commit or service change is found")
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"title":       source.Title,
+		"description": source.Description,
+		"commits":     resp.Commits,
+		"services":    resp.Services,
+		"notes":       source.Notes,
+	})
+	if err != nil {
+		return nil, e.ErrDraftReleaseNotes.AddErr(err)
+	}
+
+	ctx := context.Background()
+	client, err := GetDefaultLLMClient(ctx)
+	if err != nil {
+		logger.Errorf("failed to get llm client, the error is: %+v", err)
+		return nil, e.ErrDraftReleaseNotes.AddErr(err)
+	}
+	prompt := fmt.Sprintf("%s; 发布数据: \"\"\"%s\"\"\"", releaseNotesPrompt, util.RemoveExtraSpaces(string(data)))
+	answer, err := client.GetCompletion(ctx, prompt, llm.WithModel(openapi.GPT4o))
+	if err != nil {
+		logger.Errorf("failed to get answer from ai: %v, the error is: %+v", client.GetName(), err)
+		return nil, e.ErrDraftReleaseNotes.AddErr(err)
+	}
+
+	answer = strings.TrimSpace(answer)
+	answer = strings.TrimPrefix(answer, "```markdown")
+	answer = strings.TrimSuffix(answer, "```")
+	resp.Markdown = strings.TrimSpace(answer)
+	return resp, nil
+}
+
+func getReleaseNotesChanges(job *models.JobTask) ([]*ReleaseNotesCommit, []*ReleaseNotesService) {
+	commits := make([]*ReleaseNotesCommit, 0)
+	services := make([]*ReleaseNotesService, 0)
+	switch job.JobType {
+	case string(config.JobZadigBuild), string(config.JobFreestyle):
+		jobSpec := &models.JobTaskFreestyleSpec{}
+		if err := models.IToi(job.Spec, jobSpec); err != nil {
+			return commits, services
+		}
+		for _, stepTask := range jobSpec.Steps {
+			if stepTask.StepType != config.StepGit {
+				continue
+			}
+			stepSpec := &step.StepGitSpec{}
+			if err := models.IToi(stepTask.Spec, stepSpec); err != nil {
+				continue
+			}
+			for _, repo := range stepSpec.Repos {
+				if repo.CommitID == "" {
+					continue
+				}
+				commits = append(commits, newReleaseNotesCommit(repo))
+			}
+		}
+	case string(config.JobZadigDeploy):
+		jobSpec := &models.JobTaskDeploySpec{}
+		if err := models.IToi(job.Spec, jobSpec); err != nil {
+			return commits, services
+		}
+		for _, svc := range jobSpec.ServiceAndImages {
+			services = append(services, &ReleaseNotesService{
+				ServiceName:   jobSpec.ServiceName,
+				ServiceModule: svc.ServiceModule,
+				Env:           jobSpec.Env,
+				Image:         svc.Image,
+			})
+		}
+	case string(config.JobZadigHelmDeploy):
+		jobSpec := &models.JobTaskHelmDeploySpec{}
+		if err := models.IToi(job.Spec, jobSpec); err != nil {
+			return commits, services
+		}
+		for _, svc := range jobSpec.ImageAndModules {
+			services = append(services, &ReleaseNotesService{
+				ServiceName:   jobSpec.ServiceName,
+				ServiceModule: svc.ServiceModule,
+				Env:           jobSpec.Env,
+				Image:         svc.Image,
+			})
+		}
+	}
+	return commits, services
+}
+
+func newReleaseNotesCommit(repo *types.Repository) *ReleaseNotesCommit {
+	prs := repo.PRs
+	if len(prs) == 0 && repo.PR > 0 {
+		prs = []int{repo.PR}
+	}
+	return &ReleaseNotesCommit{
+		Source:   repo.Source,
+		RepoName: fmt.Sprintf("%s/%s", repo.GetRepoNamespace(), repo.RepoName),
+		Branch:   repo.Branch,
+		Tag:      repo.Tag,
+		PRs:      prs,
+		CommitID: repo.CommitID,
+		Message:  repo.CommitMessage,
+		Author:   repo.AuthorName,
+	}
+}
@@ -105,6 +105,30 @@ func UpdateServiceVariables(args *models.Service, production bool) error {
 	}
 }
 
+func UpdateServiceFirstDeployHook(args *models.Service, production bool) error {
+	if !production {
+		return mongodb.NewServiceColl().UpdateServiceFirstDeployHook(args)
+	} else {
+		return mongodb.NewProductionServiceColl().UpdateServiceFirstDeployHook(args)
+	}
+}
+
+func UpdateServiceDeployWindow(productName, serviceName string, window *models.DeployWindow, production bool) error {
+	if !production {
+		return mongodb.NewServiceColl().UpdateDeployWindow(productName, serviceName, window)
+	} else {
+		return mongodb.NewProductionServiceColl().UpdateDeployWindow(productName, serviceName, window)
+	}
+}
+
+func UpdateServiceLockout(productName, serviceName string, lockout *models.ServiceLockout, production bool) error {
+	if !production {
+		return mongodb.NewServiceColl().UpdateLockout(productName, serviceName, lockout)
+	} else {
+		return mongodb.NewProductionServiceColl().UpdateLockout(productName, serviceName, lockout)
+	}
+}
+
 func UpdateServiceContainers(args *models.Service, production bool) error {
 	if !production {
 		return mongodb.NewServiceColl().UpdateServiceContainers(args)
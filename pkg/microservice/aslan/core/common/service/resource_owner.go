@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// EnsureResourceOwner validates the given owner and fills in its display name.
+// When no owner is given, the creator becomes the owner.
+func EnsureResourceOwner(owner *models.ResourceOwner, creatorUID string) (*models.ResourceOwner, error) {
+	if owner == nil || owner.ID == "" {
+		if creatorUID == "" {
+			return nil, e.ErrInvalidOwner.AddDesc("owner is required")
+		}
+		owner = &models.ResourceOwner{
+			Type: config.OwnerTypeUser,
+			ID:   creatorUID,
+		}
+	}
+
+	switch owner.Type {
+	case config.OwnerTypeUser, "":
+		userInfo, err := user.New().GetUserByID(owner.ID)
+		if err != nil || userInfo == nil {
+			return nil, e.ErrInvalidOwner.AddDesc(fmt.Sprintf("user %s not found", owner.ID))
+		}
+		return &models.ResourceOwner{
+			Type: config.OwnerTypeUser,
+			ID:   owner.ID,
+			Name: userInfo.Name,
+		}, nil
+	case config.OwnerTypeGroup:
+		group, err := user.New().GetGroupDetailedInfo(owner.ID)
+		if err != nil || group == nil {
+			return nil, e.ErrInvalidOwner.AddDesc(fmt.Sprintf("user group %s not found", owner.ID))
+		}
+		return &models.ResourceOwner{
+			Type: config.OwnerTypeGroup,
+			ID:   owner.ID,
+			Name: group.Name,
+		}, nil
+	default:
+		return nil, e.ErrInvalidOwner.AddDesc(fmt.Sprintf("unsupported owner type %s", owner.Type))
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// CheckSandboxEnvQuota returns an error if the project is an expired sandbox
+// or if creating another env would exceed its env quota.
+// Regular projects are never limited.
+func CheckSandboxEnvQuota(projectName string) error {
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return fmt.Errorf("failed to find project %s, error: %s", projectName, err)
+	}
+	if project.Sandbox == nil {
+		return nil
+	}
+	if project.Sandbox.IsExpired() {
+		return e.ErrSandboxExpired.AddDesc(projectName)
+	}
+	if project.Sandbox.MaxEnvs <= 0 {
+		return nil
+	}
+
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{Name: projectName})
+	if err != nil {
+		return fmt.Errorf("failed to list envs of project %s, error: %s", projectName, err)
+	}
+	if len(envs) >= project.Sandbox.MaxEnvs {
+		return e.ErrSandboxQuotaExceeded.AddDesc(fmt.Sprintf("sandbox project %s can have at most %d envs", projectName, project.Sandbox.MaxEnvs))
+	}
+	return nil
+}
+
+// CheckSandboxTaskQuota returns an error if the project is an expired sandbox
+// or if its workflow tasks have used up the task minutes quota.
+// Regular projects are never limited.
+func CheckSandboxTaskQuota(projectName string) error {
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return fmt.Errorf("failed to find project %s, error: %s", projectName, err)
+	}
+	if project.Sandbox == nil {
+		return nil
+	}
+	if project.Sandbox.IsExpired() {
+		return e.ErrSandboxExpired.AddDesc(projectName)
+	}
+	if project.Sandbox.MaxTaskMinutes <= 0 {
+		return nil
+	}
+
+	used, err := commonrepo.NewworkflowTaskv4Coll().SumTaskDurationByProject(projectName)
+	if err != nil {
+		return fmt.Errorf("failed to count task minutes of project %s, error: %s", projectName, err)
+	}
+	if used/60 >= project.Sandbox.MaxTaskMinutes {
+		return e.ErrSandboxQuotaExceeded.AddDesc(fmt.Sprintf("sandbox project %s has used up its %d task minutes", projectName, project.Sandbox.MaxTaskMinutes))
+	}
+	return nil
+}
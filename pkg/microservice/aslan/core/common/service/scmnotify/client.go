@@ -31,6 +31,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/gitee"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/github"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/shared/client/systemconfig"
 	"github.com/koderover/zadig/v2/pkg/tool/gerrit"
@@ -193,3 +194,42 @@ func (c *Client) Comment(notify *models.Notification) error {
 
 	return nil
 }
+
+// CommentPullRequest creates a new comment on the given pull request, the repo namespace is used as the repo owner.
+func (c *Client) CommentPullRequest(codehostID int, repoNamespace, repoName string, prID int, body string) error {
+	codeHostDetail, err := systemconfig.New().GetCodeHost(codehostID)
+	if err != nil {
+		return errors.Wrapf(err, "codehost %d not found to comment", codehostID)
+	}
+
+	switch strings.ToLower(codeHostDetail.Type) {
+	case setting.SourceFromGitlab:
+		cli, err := gitlabtool.NewClient(codeHostDetail.ID, codeHostDetail.Address, codeHostDetail.AccessToken, config.ProxyHTTPSAddr(), codeHostDetail.EnableProxy)
+		if err != nil {
+			return fmt.Errorf("create gitlab client failed err: %v", err)
+		}
+		_, _, err = cli.Notes.CreateMergeRequestNote(fmt.Sprintf("%s/%s", repoNamespace, repoName), prID, &gitlab.CreateMergeRequestNoteOptions{
+			Body: &body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to comment gitlab due to %s/%s/%d %v", repoNamespace, repoName, prID, err)
+		}
+	case setting.SourceFromGithub:
+		cli := github.NewClient(codeHostDetail.AccessToken, config.ProxyHTTPSAddr(), codeHostDetail.EnableProxy)
+		if _, err := cli.CreateIssueComment(context.Background(), repoNamespace, repoName, prID, body); err != nil {
+			return fmt.Errorf("failed to comment github due to %s/%s/%d %v", repoNamespace, repoName, prID, err)
+		}
+	case setting.SourceFromGitee, setting.SourceFromGiteeEE:
+		cli := gitee.NewClient(codeHostDetail.ID, codeHostDetail.AccessToken, config.ProxyHTTPSAddr(), codeHostDetail.EnableProxy, codeHostDetail.Address)
+		_, err := cli.CreateMergeRequestComment(context.Background(), repoNamespace, repoName, int32(prID), giteeClient.PullRequestCommentPostParam{
+			Body: body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to comment gitee due to %s/%s/%d %v", repoNamespace, repoName, prID, err)
+		}
+	default:
+		return fmt.Errorf("codehost type %s not supported to comment", codeHostDetail.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/secrets"
+)
+
+// NewSecretProvider returns the client of the external secret store.
+func NewSecretProvider(provider *commonmodels.SecretProvider) (secrets.Provider, error) {
+	switch provider.Type {
+	case secrets.ProviderTypeVault:
+		return secrets.NewVaultProvider(provider.Address, provider.Token, provider.Namespace, provider.MountPath), nil
+	case secrets.ProviderTypeAWSSecretsManager:
+		return secrets.NewAWSSecretsManagerProvider(provider.Region, provider.AccessKey, provider.SecretKey), nil
+	default:
+		return nil, fmt.Errorf("invalid secret provider type: %s", provider.Type)
+	}
+}
+
+// ResolveSecretKeyVals replaces the credential variables referencing the external secret stores with the sealed secrets,
+// the values are unsealed only when they are passed to the job executor.
+func ResolveSecretKeyVals(kvs []*commonmodels.KeyVal) error {
+	providers := make(map[string]secrets.Provider)
+	for _, kv := range kvs {
+		if kv == nil || !kv.IsCredential || !secrets.IsReference(kv.Value) {
+			continue
+		}
+		value, err := resolveSecretReference(kv.Value, providers)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the secret of variable %s: %s", kv.Key, err)
+		}
+		kv.Value, err = secrets.Seal(value)
+		if err != nil {
+			return fmt.Errorf("failed to seal the secret of variable %s: %s", kv.Key, err)
+		}
+	}
+	return nil
+}
+
+func resolveSecretReference(value string, providers map[string]secrets.Provider) (string, error) {
+	ref, err := secrets.ParseReference(value)
+	if err != nil {
+		return "", err
+	}
+
+	provider, ok := providers[ref.Provider]
+	if !ok {
+		setting, err := mongodb.NewSecretProviderColl().GetByName(context.Background(), ref.Provider)
+		if err != nil {
+			return "", fmt.Errorf("failed to find secret provider %s: %s", ref.Provider, err)
+		}
+		provider, err = NewSecretProvider(setting)
+		if err != nil {
+			return "", err
+		}
+		providers[ref.Provider] = provider
+	}
+
+	return provider.GetSecret(ref.Path, ref.Key)
+}
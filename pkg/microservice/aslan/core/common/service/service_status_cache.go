@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/informer"
+)
+
+const (
+	ServiceStatusKindPods     = "pods"
+	ServiceStatusKindWorkload = "workload"
+
+	// serviceStatusCacheTTL is a fallback in case some events of the watch are missed
+	serviceStatusCacheTTL = 5 * time.Minute
+)
+
+// serviceStatusCache caches the computed status of the services in the environments. An entry is only valid
+// while the generation of its namespace, which is increased by the informer on any change of the watched
+// resources, stays the same, so the status is recomputed right after the workloads change.
+var serviceStatusCache = &statusCache{
+	entries: make(map[string]*statusCacheEntry),
+}
+
+type statusCache struct {
+	mutex     sync.RWMutex
+	entries   map[string]*statusCacheEntry
+	cleanTime time.Time
+}
+
+type statusCacheEntry struct {
+	generation uint64
+	expireTime time.Time
+	value      interface{}
+}
+
+// serviceStatusCacheKey builds the key of a service in the env, the update time of the env is included
+// so that changes of the env, such as the variables of the services, result in a different key.
+func serviceStatusCacheKey(productInfo *commonmodels.Product, kind string, serviceTmpl *commonmodels.Service) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s/%d/%d", productInfo.ClusterID, productInfo.Namespace, productInfo.ProductName, productInfo.EnvName,
+		kind, serviceTmpl.ServiceName, serviceTmpl.Revision, productInfo.UpdateTime)
+}
+
+// GetCachedServiceStatus returns the cached status of the service in the env if the namespace has not changed since it was
+// computed, otherwise it calls compute and caches the result if compute reports it as cacheable.
+func GetCachedServiceStatus(productInfo *commonmodels.Product, kind string, serviceTmpl *commonmodels.Service, compute func() (interface{}, bool)) interface{} {
+	key := serviceStatusCacheKey(productInfo, kind, serviceTmpl)
+	// the generation must be read before the computation, so a change during the computation invalidates the result
+	generation := informer.Generation(productInfo.ClusterID, productInfo.Namespace)
+	now := time.Now()
+
+	serviceStatusCache.mutex.RLock()
+	entry, ok := serviceStatusCache.entries[key]
+	serviceStatusCache.mutex.RUnlock()
+	if ok && entry.generation == generation && now.Before(entry.expireTime) {
+		return entry.value
+	}
+
+	value, cacheable := compute()
+	if !cacheable {
+		return value
+	}
+
+	serviceStatusCache.mutex.Lock()
+	defer serviceStatusCache.mutex.Unlock()
+	serviceStatusCache.entries[key] = &statusCacheEntry{
+		generation: generation,
+		expireTime: now.Add(serviceStatusCacheTTL),
+		value:      value,
+	}
+	if now.Sub(serviceStatusCache.cleanTime) > serviceStatusCacheTTL {
+		for k, v := range serviceStatusCache.entries {
+			if now.After(v.expireTime) {
+				delete(serviceStatusCache.entries, k)
+			}
+		}
+		serviceStatusCache.cleanTime = now
+	}
+	return value
+}
@@ -19,9 +19,12 @@ package template
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
@@ -44,10 +47,153 @@ func GetDockerfileTemplateDetail(id string, logger *zap.SugaredLogger) (*Dockerf
 	resp.ID = dockerfileTemplate.ID.Hex()
 	resp.Name = dockerfileTemplate.Name
 	resp.Content = dockerfileTemplate.Content
+	resp.Version = dockerfileTemplate.Version
 	resp.Variables = variables
+	resp.ArgSchemas = dockerfileTemplate.ArgSchemas
 	return resp, nil
 }
 
+// GetDockerfileTemplateContent returns the content of the given template version, the latest content is returned if version is 0
+func GetDockerfileTemplateContent(id string, version int64, logger *zap.SugaredLogger) (string, error) {
+	if version == 0 {
+		dockerfileTemplate, err := commonrepo.NewDockerfileTemplateColl().GetById(id)
+		if err != nil {
+			logger.Errorf("Failed to get dockerfile template from id: %s, the error is: %s", id, err)
+			return "", err
+		}
+		return dockerfileTemplate.Content, nil
+	}
+
+	templateVersion, err := commonrepo.NewDockerfileTemplateVersionColl().Get(id, version)
+	if err != nil {
+		logger.Errorf("Failed to get dockerfile template %s of version %d, the error is: %s", id, version, err)
+		return "", err
+	}
+	return templateVersion.Content, nil
+}
+
+// ValidateDockerfileBuildArgs checks the docker build args of a build against the arg schemas of the referenced template version
+func ValidateDockerfileBuildArgs(id string, version int64, buildArgs string) error {
+	var schemas []*commonmodels.DockerfileArgSchema
+	if version == 0 {
+		dockerfileTemplate, err := commonrepo.NewDockerfileTemplateColl().GetById(id)
+		if err != nil {
+			return fmt.Errorf("failed to find dockerfile template %s, err: %s", id, err)
+		}
+		schemas = dockerfileTemplate.ArgSchemas
+	} else {
+		templateVersion, err := commonrepo.NewDockerfileTemplateVersionColl().Get(id, version)
+		if err != nil {
+			return fmt.Errorf("failed to find version %d of dockerfile template %s, err: %s", version, id, err)
+		}
+		schemas = templateVersion.ArgSchemas
+	}
+
+	args := parseDockerBuildArgs(buildArgs)
+	for _, schema := range schemas {
+		value, ok := args[schema.Key]
+		if !ok || value == "" {
+			if schema.Required && schema.Default == "" {
+				return fmt.Errorf("build arg %s is required", schema.Key)
+			}
+			continue
+		}
+		// values referencing variables are rendered at runtime, skip them
+		if strings.Contains(value, "$") {
+			continue
+		}
+		if err := validateDockerfileArg(schema, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDockerfileArg(schema *commonmodels.DockerfileArgSchema, value string) error {
+	switch schema.Type {
+	case commonmodels.DockerfileArgTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("build arg %s should be a number, got: %s", schema.Key, value)
+		}
+	case commonmodels.DockerfileArgTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("build arg %s should be a bool, got: %s", schema.Key, value)
+		}
+	case commonmodels.DockerfileArgTypeEnum:
+		if !sets.NewString(schema.Options...).Has(value) {
+			return fmt.Errorf("build arg %s should be one of %v, got: %s", schema.Key, schema.Options, value)
+		}
+	}
+	if schema.Pattern != "" {
+		matched, err := regexp.MatchString(schema.Pattern, value)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %s of build arg %s: %s", schema.Pattern, schema.Key, err)
+		}
+		if !matched {
+			return fmt.Errorf("build arg %s does not match pattern %s, got: %s", schema.Key, schema.Pattern, value)
+		}
+	}
+	return nil
+}
+
+// parseDockerBuildArgs parses args like `--build-arg a=b --build-arg=c=d` into a map
+func parseDockerBuildArgs(buildArgs string) map[string]string {
+	ret := make(map[string]string)
+	fields := strings.Fields(buildArgs)
+	for i := 0; i < len(fields); i++ {
+		kv := ""
+		switch {
+		case fields[i] == "--build-arg" && i+1 < len(fields):
+			i++
+			kv = fields[i]
+		case strings.HasPrefix(fields[i], "--build-arg="):
+			kv = strings.TrimPrefix(fields[i], "--build-arg=")
+		default:
+			continue
+		}
+		key, value, _ := strings.Cut(kv, "=")
+		ret[key] = strings.Trim(value, "\"'")
+	}
+	return ret
+}
+
+// ValidateDockerfileArgSchemas makes sure the arg schemas of a template are well defined
+func ValidateDockerfileArgSchemas(schemas []*commonmodels.DockerfileArgSchema) error {
+	keys := sets.NewString()
+	for _, schema := range schemas {
+		if schema.Key == "" {
+			return fmt.Errorf("build arg key can not be empty")
+		}
+		if keys.Has(schema.Key) {
+			return fmt.Errorf("duplicated build arg: %s", schema.Key)
+		}
+		keys.Insert(schema.Key)
+
+		switch schema.Type {
+		case "":
+			schema.Type = commonmodels.DockerfileArgTypeString
+		case commonmodels.DockerfileArgTypeString, commonmodels.DockerfileArgTypeNumber, commonmodels.DockerfileArgTypeBool:
+		case commonmodels.DockerfileArgTypeEnum:
+			if len(schema.Options) == 0 {
+				return fmt.Errorf("options of enum build arg %s can not be empty", schema.Key)
+			}
+		default:
+			return fmt.Errorf("unsupported type %s of build arg %s", schema.Type, schema.Key)
+		}
+		if schema.Pattern != "" {
+			if _, err := regexp.Compile(schema.Pattern); err != nil {
+				return fmt.Errorf("invalid pattern %s of build arg %s: %s", schema.Pattern, schema.Key, err)
+			}
+		}
+		if schema.Default != "" {
+			if err := validateDockerfileArg(schema, schema.Default); err != nil {
+				return fmt.Errorf("invalid default value: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
 func getVariables(s string, logger *zap.SugaredLogger) ([]*commonmodels.ChartVariable, error) {
 	ret := make([]*commonmodels.ChartVariable, 0)
 	reader := strings.NewReader(s)
@@ -36,20 +36,26 @@ type Chart struct {
 }
 
 type DockerfileTemplate struct {
-	Name    string `json:"name"`
-	Content string `json:"content"`
+	Name       string                        `json:"name"`
+	Content    string                        `json:"content"`
+	ArgSchemas []*models.DockerfileArgSchema `json:"arg_schemas"`
 }
 
 type DockerfileListObject struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Version    int64  `json:"version"`
+	UpdateBy   string `json:"update_by"`
+	UpdateTime int64  `json:"update_time"`
 }
 
 type DockerfileDetail struct {
-	ID        string                  `json:"id"`
-	Name      string                  `json:"name"`
-	Content   string                  `json:"content"`
-	Variables []*models.ChartVariable `json:"variable"`
+	ID         string                        `json:"id"`
+	Name       string                        `json:"name"`
+	Content    string                        `json:"content"`
+	Version    int64                         `json:"version"`
+	Variables  []*models.ChartVariable       `json:"variable"`
+	ArgSchemas []*models.DockerfileArgSchema `json:"arg_schemas"`
 }
 
 type BuildReference struct {
@@ -45,6 +45,33 @@ func (c *webhookNotifyclient) SendWorkflowWebhook(webhookNotify *WorkflowNotify)
 	return c.sendWebhook(notify)
 }
 
+func (c *webhookNotifyclient) SendCertExpiryWebhook(certExpiryNotify *CertExpiryNotify) error {
+	notify := &WebHookNotify{
+		ObjectKind: WebHookNotifyObjectKindCertificate,
+		Event:      WebHookNotifyEventCertExpiry,
+		CertExpiry: certExpiryNotify,
+	}
+	return c.sendWebhook(notify)
+}
+
+func (c *webhookNotifyclient) SendEnvDriftWebhook(envDriftNotify *EnvDriftNotify) error {
+	notify := &WebHookNotify{
+		ObjectKind: WebHookNotifyObjectKindEnvironment,
+		Event:      WebHookNotifyEventEnvDrift,
+		EnvDrift:   envDriftNotify,
+	}
+	return c.sendWebhook(notify)
+}
+
+func (c *webhookNotifyclient) SendRegistryCredentialWebhook(registryCredentialNotify *RegistryCredentialNotify) error {
+	notify := &WebHookNotify{
+		ObjectKind:         WebHookNotifyObjectKindRegistry,
+		Event:              WebHookNotifyEventRegistryCredential,
+		RegistryCredential: registryCredentialNotify,
+	}
+	return c.sendWebhook(notify)
+}
+
 func (c *webhookNotifyclient) sendWebhook(notify *WebHookNotify) error {
 	resp, err := httpclient.Post(
 		c.Address,
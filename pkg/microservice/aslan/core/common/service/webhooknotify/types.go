@@ -33,19 +33,77 @@ const (
 type WebHookNotifyEvent string
 
 const (
-	WebHookNotifyEventWorkflow WebHookNotifyEvent = "workflow"
+	WebHookNotifyEventWorkflow   WebHookNotifyEvent = "workflow"
+	WebHookNotifyEventCertExpiry WebHookNotifyEvent = "cert_expiry"
+	WebHookNotifyEventEnvDrift   WebHookNotifyEvent = "env_drift"
+
+	WebHookNotifyEventRegistryCredential WebHookNotifyEvent = "registry_credential"
 )
 
 type WebHookNotifyObjectKind string
 
 const (
-	WebHookNotifyObjectKindWorkflow WebHookNotifyObjectKind = "workflow"
+	WebHookNotifyObjectKindWorkflow    WebHookNotifyObjectKind = "workflow"
+	WebHookNotifyObjectKindCertificate WebHookNotifyObjectKind = "certificate"
+	WebHookNotifyObjectKindEnvironment WebHookNotifyObjectKind = "environment"
+	WebHookNotifyObjectKindRegistry    WebHookNotifyObjectKind = "registry"
 )
 
 type WebHookNotify struct {
 	ObjectKind WebHookNotifyObjectKind `json:"object_kind"`
 	Event      WebHookNotifyEvent      `json:"event"`
 	Workflow   *WorkflowNotify         `json:"workflow"`
+	CertExpiry *CertExpiryNotify       `json:"cert_expiry,omitempty"`
+	EnvDrift   *EnvDriftNotify         `json:"env_drift,omitempty"`
+
+	RegistryCredential *RegistryCredentialNotify `json:"registry_credential,omitempty"`
+}
+
+type RegistryCredentialNotify struct {
+	RegistryID string `json:"registry_id"`
+	RegAddr    string `json:"reg_addr"`
+	Namespace  string `json:"namespace"`
+	Provider   string `json:"provider"`
+	Valid      bool   `json:"valid"`
+	Message    string `json:"message"`
+	// ExpireTime and DaysLeft are set when the static credential is about to expire
+	ExpireTime int64  `json:"expire_time"`
+	DaysLeft   int    `json:"days_left"`
+	DetailURL  string `json:"detail_url"`
+}
+
+type CertExpiryNotify struct {
+	ProjectName   string                  `json:"project_name"`
+	ThresholdDays int                     `json:"threshold_days"`
+	DetailURL     string                  `json:"detail_url"`
+	Certs         []*CertExpiryNotifyCert `json:"certs"`
+}
+
+type CertExpiryNotifyCert struct {
+	EnvName    string   `json:"env_name"`
+	Production bool     `json:"production"`
+	Namespace  string   `json:"namespace"`
+	SecretName string   `json:"secret_name"`
+	CommonName string   `json:"common_name"`
+	DNSNames   []string `json:"dns_names"`
+	NotAfter   int64    `json:"not_after"`
+	DaysLeft   int      `json:"days_left"`
+}
+
+type EnvDriftNotify struct {
+	ProjectName string                    `json:"project_name"`
+	EnvName     string                    `json:"env_name"`
+	Production  bool                      `json:"production"`
+	Namespace   string                    `json:"namespace"`
+	DetailURL   string                    `json:"detail_url"`
+	Resources   []*EnvDriftNotifyResource `json:"resources"`
+}
+
+type EnvDriftNotifyResource struct {
+	ServiceName string   `json:"service_name"`
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Fields      []string `json:"fields"`
 }
 
 type WorkflowNotify struct {
@@ -54,6 +112,7 @@ type WorkflowNotify struct {
 	WorkflowName        string                 `json:"workflow_name"`
 	WorkflowDisplayName string                 `json:"workflow_display_name"`
 	Status              config.Status          `json:"status"`
+	TaskName            string                 `json:"task_name,omitempty"`
 	Remark              string                 `json:"remark"`
 	DetailURL           string                 `json:"detail_url"`
 	Error               string                 `json:"error"`
@@ -65,6 +124,10 @@ type WorkflowNotify struct {
 	TaskCreatorID       string                 `json:"task_creator_id"`
 	TaskCreatorPhone    string                 `json:"task_creator_phone"`
 	TaskCreatorEmail    string                 `json:"task_creator_email"`
+	RunAs               string                 `json:"run_as,omitempty"`
+	// RiskLevel and RiskScore are the change risk of the task
+	RiskLevel string `json:"risk_level,omitempty"`
+	RiskScore int    `json:"risk_score,omitempty"`
 }
 
 type WorkflowNotifyStage struct {
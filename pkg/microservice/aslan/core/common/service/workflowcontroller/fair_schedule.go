@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflowcontroller
+
+import (
+	"sort"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/workflowcontroller/jobcontroller"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const (
+	// the number of the latest scheduling decisions returned by the queue insights
+	scheduleDecisionListLimit = 100
+	// scheduleDecisionRetentionDays is how long the scheduling decisions are kept
+	scheduleDecisionRetentionDays = 7
+)
+
+type ProjectQueueInsight struct {
+	ProjectName  string `json:"project_name"`
+	Weight       int    `json:"weight"`
+	RunningTasks int    `json:"running_tasks"`
+	WaitingTasks int    `json:"waiting_tasks"`
+	// RunningJobPods and WaitingJobPods are the job pods scheduled by the instance serving the request
+	RunningJobPods int `json:"running_job_pods"`
+	WaitingJobPods int `json:"waiting_job_pods"`
+	// Share is the expected share of the workflow concurrency of the project among the busy projects
+	Share float64 `json:"share"`
+}
+
+type QueueInsights struct {
+	FairSchedulingEnabled bool                             `json:"fair_scheduling_enabled"`
+	WorkflowConcurrency   int64                            `json:"workflow_concurrency"`
+	JobPodConcurrency     int                              `json:"job_pod_concurrency"`
+	Projects              []*ProjectQueueInsight           `json:"projects"`
+	Decisions             []*commonmodels.ScheduleDecision `json:"decisions"`
+}
+
+// countRunningTasksByProject counts the tasks which are taking the workflow concurrency
+func countRunningTasksByProject() map[string]int {
+	resp := make(map[string]int)
+	for _, t := range RunningAndQueuedTasks() {
+		resp[t.ProjectName]++
+	}
+	return resp
+}
+
+// sortWaitingTasksByFairShare sorts the waiting tasks so that the tasks of the project with the lowest
+// running tasks per weight come first, the tasks of the same project keep the FIFO order.
+func sortWaitingTasksByFairShare(tasks []*commonmodels.WorkflowQueue, settings *commonmodels.FairSchedulingSettings, running map[string]int) []*commonmodels.WorkflowQueue {
+	if settings == nil || !settings.Enabled {
+		return tasks
+	}
+
+	resp := make([]*commonmodels.WorkflowQueue, len(tasks))
+	copy(resp, tasks)
+	sort.SliceStable(resp, func(i, j int) bool {
+		// compare running_i / weight_i with running_j / weight_j without division
+		left := running[resp[i].ProjectName] * settings.GetWeight(resp[j].ProjectName)
+		right := running[resp[j].ProjectName] * settings.GetWeight(resp[i].ProjectName)
+		if left != right {
+			return left < right
+		}
+		return resp[i].CreateTime < resp[j].CreateTime
+	})
+	return resp
+}
+
+func recordScheduleDecision(t *commonmodels.WorkflowQueue, waitingTasks []*commonmodels.WorkflowQueue, settings *commonmodels.FairSchedulingSettings, running map[string]int) {
+	if settings == nil || !settings.Enabled {
+		return
+	}
+
+	waitingProjects := make(map[string]bool)
+	for _, task := range waitingTasks {
+		waitingProjects[task.ProjectName] = true
+	}
+	err := commonrepo.NewScheduleDecisionColl().Create(&commonmodels.ScheduleDecision{
+		Kind:            commonmodels.ScheduleDecisionKindWorkflowTask,
+		ProjectName:     t.ProjectName,
+		WorkflowName:    t.WorkflowName,
+		TaskID:          t.TaskID,
+		Weight:          settings.GetWeight(t.ProjectName),
+		Running:         running[t.ProjectName],
+		WaitingProjects: len(waitingProjects),
+	})
+	if err != nil {
+		log.Errorf("failed to record the scheduling decision of task %s/%d: %s", t.WorkflowName, t.TaskID, err)
+	}
+}
+
+// CleanScheduleDecisions deletes the outdated scheduling decisions, it is expected to be called daily.
+func CleanScheduleDecisions() {
+	err := commonrepo.NewScheduleDecisionColl().DeleteBefore(time.Now().AddDate(0, 0, -scheduleDecisionRetentionDays).Unix())
+	if err != nil {
+		log.Errorf("failed to delete outdated scheduling decisions: %s", err)
+	}
+}
+
+// GetQueueInsights returns the running and waiting tasks and job pods of each project with the weights, and the
+// latest scheduling decisions.
+func GetQueueInsights() (*QueueInsights, error) {
+	sysSetting, err := commonrepo.NewSystemSettingColl().Get()
+	if err != nil {
+		return nil, err
+	}
+	settings := sysSetting.FairScheduling
+	if settings == nil {
+		settings = &commonmodels.FairSchedulingSettings{}
+	}
+
+	projects := make(map[string]*ProjectQueueInsight)
+	getProject := func(projectName string) *ProjectQueueInsight {
+		if _, ok := projects[projectName]; !ok {
+			projects[projectName] = &ProjectQueueInsight{
+				ProjectName: projectName,
+				Weight:      settings.GetWeight(projectName),
+			}
+		}
+		return projects[projectName]
+	}
+	for _, t := range ListTasks() {
+		switch t.Status {
+		case config.StatusRunning, config.StatusQueued:
+			getProject(t.ProjectName).RunningTasks++
+		case config.StatusWaiting:
+			getProject(t.ProjectName).WaitingTasks++
+		}
+	}
+
+	for projectName, jobPods := range jobcontroller.ListJobPodQueueInsights() {
+		project := getProject(projectName)
+		project.RunningJobPods = jobPods.Running
+		project.WaitingJobPods = jobPods.Waiting
+	}
+
+	decisions, err := commonrepo.NewScheduleDecisionColl().ListLatest(scheduleDecisionListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	totalWeight := 0
+	for _, project := range projects {
+		totalWeight += project.Weight
+	}
+	resp := &QueueInsights{
+		FairSchedulingEnabled: settings.Enabled,
+		WorkflowConcurrency:   sysSetting.WorkflowConcurrency,
+		JobPodConcurrency:     settings.JobPodConcurrency,
+		Projects:              make([]*ProjectQueueInsight, 0, len(projects)),
+		Decisions:             decisions,
+	}
+	for _, project := range projects {
+		if totalWeight > 0 {
+			project.Share = float64(project.Weight) / float64(totalWeight)
+		}
+		resp.Projects = append(resp.Projects, project)
+	}
+	sort.Slice(resp.Projects, func(i, j int) bool {
+		return resp.Projects[i].ProjectName < resp.Projects[j].ProjectName
+	})
+	return resp, nil
+}
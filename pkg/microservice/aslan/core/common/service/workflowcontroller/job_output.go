@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflowcontroller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+)
+
+// saveJobOutputs saves the outputs of the jobs written to the global context, the key of an output is like
+// {{.job.jobKey.output.outputName}}.
+func (c *workflowCtl) saveJobOutputs() {
+	globalContext := c.getGlobalContextAll()
+	now := time.Now().Unix()
+	outputs := make([]*commonmodels.JobOutput, 0)
+	for _, stage := range c.workflowTask.Stages {
+		for _, job := range stage.Jobs {
+			if job.Status == config.StatusSkipped || job.Status == "" {
+				continue
+			}
+			jobInfo := &commonmodels.TaskJobInfo{}
+			if err := commonmodels.IToi(job.JobInfo, jobInfo); err != nil {
+				c.logger.Warnf("failed to parse the info of job %s, error: %v", job.Name, err)
+			}
+			jobName := jobInfo.JobName
+			if jobName == "" {
+				jobName = job.Name
+			}
+
+			prefix := "{{.job." + job.Key + ".output."
+			for key, value := range globalContext {
+				if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, "}}") {
+					continue
+				}
+				name := strings.TrimSuffix(strings.TrimPrefix(key, prefix), "}}")
+				if name == "" || strings.Contains(name, ".") {
+					continue
+				}
+				outputs = append(outputs, &commonmodels.JobOutput{
+					ProjectName:         c.workflowTask.ProjectName,
+					WorkflowName:        c.workflowTask.WorkflowName,
+					WorkflowDisplayName: c.workflowTask.WorkflowDisplayName,
+					TaskID:              c.workflowTask.TaskID,
+					TaskStatus:          string(c.workflowTask.Status),
+					JobName:             jobName,
+					JobKey:              job.Key,
+					JobType:             job.JobType,
+					ServiceName:         jobInfo.ServiceName,
+					ServiceModule:       jobInfo.ServiceModule,
+					Name:                name,
+					Value:               value,
+					CreateTime:          now,
+				})
+			}
+		}
+	}
+
+	if err := commonrepo.NewJobOutputColl().ReplaceByTask(context.TODO(), c.workflowTask.WorkflowName, c.workflowTask.TaskID, outputs); err != nil {
+		c.logger.Errorf("failed to save the job outputs of workflow %s task %d, error: %v", c.workflowTask.WorkflowName, c.workflowTask.TaskID, err)
+	}
+}
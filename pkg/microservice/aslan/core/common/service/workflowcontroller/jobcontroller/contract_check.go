@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/tool/contractregistry"
+)
+
+// contractCheckTarget is the service to be deployed by a deploy or helm deploy job
+type contractCheckTarget struct {
+	projectName string
+	envName     string
+	production  bool
+	serviceName string
+	images      []string
+}
+
+// checkContractCompatibility asks the contract registry whether the service to be deployed is compatible with the other
+// services currently deployed in the env. The job is failed with the incompatibility report and false is returned if it's
+// not, or if the registry can not be reached unless the check is set to fail open.
+func checkContractCompatibility(job *commonmodels.JobTask, cfg *commonmodels.ContractCheckConfig, target *contractCheckTarget, logger *zap.SugaredLogger) (*commonmodels.ContractCheckResult, bool) {
+	result := &commonmodels.ContractCheckResult{CheckTime: time.Now().Unix()}
+	failOrSkip := func(msg string) (*commonmodels.ContractCheckResult, bool) {
+		if cfg.FailOpen {
+			logger.Warnf("contract check of service %s skipped: %s", target.serviceName, msg)
+			result.Compatible = true
+			result.Error = msg
+			return result, true
+		}
+		logError(job, fmt.Sprintf("contract check of service %s failed: %s", target.serviceName, msg), logger)
+		return result, false
+	}
+
+	registry, err := commonrepo.NewContractRegistryColl().GetByID(context.TODO(), cfg.RegistryID)
+	if err != nil {
+		return failOrSkip(fmt.Sprintf("failed to find contract registry %s: %v", cfg.RegistryID, err))
+	}
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       target.projectName,
+		EnvName:    target.envName,
+		Production: &target.production,
+	})
+	if err != nil {
+		logError(job, fmt.Sprintf("failed to find env %s: %v", target.envName, err), logger)
+		return result, false
+	}
+
+	consumers := make([]*contractregistry.Participant, 0)
+	for _, svc := range env.GetSvcList() {
+		if svc.ServiceName == target.serviceName {
+			continue
+		}
+		images := make([]string, 0, len(svc.Containers))
+		for _, container := range svc.Containers {
+			images = append(images, container.Image)
+		}
+		consumers = append(consumers, newContractParticipant(svc.ServiceName, images))
+	}
+
+	checkResult, err := contractregistry.NewClient(registry.Address, registry.Token, registry.InsecureSkipVerify).CheckCompatibility(&contractregistry.CompatibilityRequest{
+		Project:    target.projectName,
+		Env:        target.envName,
+		Production: target.production,
+		Provider:   newContractParticipant(target.serviceName, target.images),
+		Consumers:  consumers,
+	})
+	if err != nil {
+		return failOrSkip(fmt.Sprintf("failed to query contract registry %s: %v", registry.Name, err))
+	}
+
+	result.Compatible = checkResult.Compatible
+	for _, item := range checkResult.Incompatibilities {
+		result.Incompatibilities = append(result.Incompatibilities, &commonmodels.ContractIncompatibility{
+			Consumer: item.Consumer,
+			Contract: item.Contract,
+			Message:  item.Message,
+		})
+	}
+	if !result.Compatible {
+		logError(job, fmt.Sprintf("service %s is incompatible with the consumers deployed in env %s:\n%s", target.serviceName, target.envName, checkResult.Report()), logger)
+		return result, false
+	}
+	return result, true
+}
+
+// newContractParticipant uses the tag of the first image as the version of the service
+func newContractParticipant(serviceName string, images []string) *contractregistry.Participant {
+	participant := &contractregistry.Participant{
+		Service: serviceName,
+		Images:  images,
+	}
+	if len(images) > 0 {
+		participant.Version = util.ExtractImageTag(images[0])
+	}
+	return participant
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+const defaultDeployWindowApprovalTimeout = 60
+
+// newDeployWindowApproval returns the owner approval required to deploy the service now, nil means the service is
+// in its deploy window or it has no deploy window.
+func newDeployWindowApproval(projectName, serviceName string, production bool) (*commonmodels.DeployWindowApproval, error) {
+	svc, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+		ProductName: projectName,
+		ServiceName: serviceName,
+	}, production)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find service %s, error: %v", serviceName, err)
+	}
+	inWindow, err := commonutil.InDeployWindow(svc.DeployWindow, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the deploy window of service %s, error: %v", serviceName, err)
+	}
+	if inWindow {
+		return nil, nil
+	}
+
+	if svc.Owner == nil || svc.Owner.ID == "" {
+		return nil, fmt.Errorf("service %s is out of its deploy window and it has no owner to approve the deployment", serviceName)
+	}
+	owner := &commonmodels.User{Type: setting.UserTypeUser, UserID: svc.Owner.ID, UserName: svc.Owner.Name}
+	if svc.Owner.Type == config.OwnerTypeGroup {
+		owner = &commonmodels.User{Type: setting.UserTypeGroup, GroupID: svc.Owner.ID, GroupName: svc.Owner.Name}
+	}
+	approveUsers, _ := commonutil.GeneFlatUsers([]*commonmodels.User{owner})
+	if len(approveUsers) == 0 {
+		return nil, fmt.Errorf("service %s is out of its deploy window and no user of its owner %s can approve the deployment", serviceName, svc.Owner.Name)
+	}
+
+	timeout := svc.DeployWindow.ApprovalTimeout
+	if timeout <= 0 {
+		timeout = defaultDeployWindowApprovalTimeout
+	}
+	return &commonmodels.DeployWindowApproval{
+		Reason:  fmt.Sprintf("service %s is deployed out of its deploy window", serviceName),
+		Timeout: timeout,
+		NativeApproval: &commonmodels.NativeApproval{
+			Timeout:         int(timeout),
+			ApproveUsers:    approveUsers,
+			NeededApprovers: 1,
+		},
+	}, nil
+}
+
+// waitForDeployWindowApproval waits for the owner to approve the out-of-window deployment, the job status is set
+// and false is returned if it is not approved.
+func waitForDeployWindowApproval(ctx context.Context, job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, approval *commonmodels.DeployWindowApproval, ack func()) bool {
+	job.Status = config.StatusWaitingApprove
+	ack()
+
+	status, err := waitForNativeApprove(ctx, &commonmodels.JobTaskApprovalSpec{
+		Timeout:        approval.Timeout,
+		Type:           config.NativeApproval,
+		Description:    approval.Reason,
+		NativeApproval: approval.NativeApproval,
+	}, workflowCtx.WorkflowName, job.Name, workflowCtx.TaskID, ack)
+	if status != config.StatusPassed {
+		job.Status = status
+		if err != nil {
+			job.Error = fmt.Sprintf("%s, the deployment is not approved: %v", approval.Reason, err)
+		}
+		return false
+	}
+
+	job.Status = config.StatusRunning
+	ack()
+	return true
+}
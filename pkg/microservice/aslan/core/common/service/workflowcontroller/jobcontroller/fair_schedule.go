@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+// JobPodQueueInsight is the running and waiting job pods of a project in the job pod scheduler
+type JobPodQueueInsight struct {
+	Running int
+	Waiting int
+}
+
+var jobPods = &jobPodScheduler{
+	running: make(map[string]int),
+}
+
+// jobPodScheduler shares the job pod slots among the projects by weight, the waiting job of the project with the
+// lowest running job pods per weight gets the next free slot, the jobs of the same project keep the FIFO order.
+type jobPodScheduler struct {
+	sync.Mutex
+	running  map[string]int
+	waiting  []*jobPodRequest
+	settings *commonmodels.FairSchedulingSettings
+}
+
+type jobPodRequest struct {
+	projectName  string
+	workflowName string
+	taskID       int64
+	jobName      string
+	ready        chan struct{}
+}
+
+// runsJobPod checks whether the job runs its steps in a job pod of the build cluster
+func runsJobPod(job *commonmodels.JobTask, jobCtl JobCtl) bool {
+	if job.Infrastructure == setting.JobVMInfrastructure {
+		return false
+	}
+	switch jobCtl.(type) {
+	case *FreestyleJobCtl, *PluginJobCtl, *ImageScanJobCtl:
+		return true
+	}
+	return false
+}
+
+// acquireJobPodSlot blocks until the job gets a job pod slot, the returned function releases the slot and can be
+// called more than once. No slot is needed if fair scheduling is disabled or the job pod concurrency is not limited.
+func acquireJobPodSlot(ctx context.Context, job *commonmodels.JobTask, jobCtl JobCtl, workflowCtx *commonmodels.WorkflowTaskCtx, logger *zap.SugaredLogger) (func(), error) {
+	if !runsJobPod(job, jobCtl) {
+		return func() {}, nil
+	}
+	sysSetting, err := commonrepo.NewSystemSettingColl().Get()
+	if err != nil {
+		logger.Errorf("failed to get fair scheduling settings, the job pod is scheduled without limit: %s", err)
+		return func() {}, nil
+	}
+	settings := sysSetting.FairScheduling
+	if settings == nil || !settings.Enabled || settings.JobPodConcurrency <= 0 {
+		return func() {}, nil
+	}
+
+	req := &jobPodRequest{
+		projectName:  workflowCtx.ProjectName,
+		workflowName: workflowCtx.WorkflowName,
+		taskID:       workflowCtx.TaskID,
+		jobName:      job.Name,
+		ready:        make(chan struct{}),
+	}
+	jobPods.Lock()
+	jobPods.settings = settings
+	jobPods.waiting = append(jobPods.waiting, req)
+	decisions := jobPods.dispatch()
+	jobPods.Unlock()
+	recordJobPodScheduleDecisions(decisions)
+
+	select {
+	case <-req.ready:
+	case <-ctx.Done():
+		jobPods.Lock()
+		select {
+		case <-req.ready:
+			// the slot was granted right before the job is cancelled
+			decisions = jobPods.release(req.projectName)
+		default:
+			decisions = nil
+			jobPods.remove(req)
+		}
+		jobPods.Unlock()
+		recordJobPodScheduleDecisions(decisions)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			jobPods.Lock()
+			decisions := jobPods.release(req.projectName)
+			jobPods.Unlock()
+			recordJobPodScheduleDecisions(decisions)
+		})
+	}, nil
+}
+
+// dispatch grants the free slots to the waiting jobs, it must be called with the lock held. The decisions are
+// returned to be recorded after the lock is released.
+func (s *jobPodScheduler) dispatch() []*commonmodels.ScheduleDecision {
+	decisions := make([]*commonmodels.ScheduleDecision, 0)
+	for len(s.waiting) > 0 && s.runningTotal() < s.settings.JobPodConcurrency {
+		picked := 0
+		for i, req := range s.waiting {
+			// compare running_i / weight_i with running_picked / weight_picked without division
+			left := s.running[req.projectName] * s.settings.GetWeight(s.waiting[picked].projectName)
+			right := s.running[s.waiting[picked].projectName] * s.settings.GetWeight(req.projectName)
+			if left < right {
+				picked = i
+			}
+		}
+		req := s.waiting[picked]
+
+		waitingProjects := make(map[string]bool)
+		for _, waiting := range s.waiting {
+			waitingProjects[waiting.projectName] = true
+		}
+		decisions = append(decisions, &commonmodels.ScheduleDecision{
+			Kind:            commonmodels.ScheduleDecisionKindJobPod,
+			ProjectName:     req.projectName,
+			WorkflowName:    req.workflowName,
+			TaskID:          req.taskID,
+			JobName:         req.jobName,
+			Weight:          s.settings.GetWeight(req.projectName),
+			Running:         s.running[req.projectName],
+			WaitingProjects: len(waitingProjects),
+		})
+
+		s.waiting = append(s.waiting[:picked], s.waiting[picked+1:]...)
+		s.running[req.projectName]++
+		close(req.ready)
+	}
+	return decisions
+}
+
+// release frees the slot of the project and grants it to the waiting jobs, it must be called with the lock held
+func (s *jobPodScheduler) release(projectName string) []*commonmodels.ScheduleDecision {
+	s.running[projectName]--
+	if s.running[projectName] <= 0 {
+		delete(s.running, projectName)
+	}
+	if s.settings == nil {
+		return nil
+	}
+	return s.dispatch()
+}
+
+func (s *jobPodScheduler) remove(req *jobPodRequest) {
+	for i, waiting := range s.waiting {
+		if waiting == req {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *jobPodScheduler) runningTotal() int {
+	total := 0
+	for _, count := range s.running {
+		total += count
+	}
+	return total
+}
+
+// ListJobPodQueueInsights returns the running and waiting job pods of each project scheduled by this instance
+func ListJobPodQueueInsights() map[string]*JobPodQueueInsight {
+	jobPods.Lock()
+	defer jobPods.Unlock()
+
+	resp := make(map[string]*JobPodQueueInsight)
+	getProject := func(projectName string) *JobPodQueueInsight {
+		if _, ok := resp[projectName]; !ok {
+			resp[projectName] = &JobPodQueueInsight{}
+		}
+		return resp[projectName]
+	}
+	for projectName, count := range jobPods.running {
+		getProject(projectName).Running = count
+	}
+	for _, req := range jobPods.waiting {
+		getProject(req.projectName).Waiting++
+	}
+	return resp
+}
+
+// recordJobPodScheduleDecisions saves the decisions made by dispatch, it must be called without the lock held
+func recordJobPodScheduleDecisions(decisions []*commonmodels.ScheduleDecision) {
+	for _, decision := range decisions {
+		if err := commonrepo.NewScheduleDecisionColl().Create(decision); err != nil {
+			log.Errorf("failed to record the scheduling decision of job %s in %s/%d: %s", decision.JobName, decision.WorkflowName, decision.TaskID, err)
+		}
+	}
+}
@@ -57,6 +57,8 @@ func initJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTas
 		jobCtl = NewCustomDeployJobCtl(job, workflowCtx, ack, logger)
 	case string(config.JobPlugin):
 		jobCtl = NewPluginsJobCtl(job, workflowCtx, ack, logger)
+	case string(config.JobZadigImageScan):
+		jobCtl = NewImageScanJobCtl(job, workflowCtx, ack, logger)
 	case string(config.JobK8sCanaryDeploy):
 		jobCtl = NewCanaryDeployJobCtl(job, workflowCtx, ack, logger)
 	case string(config.JobK8sCanaryRelease):
@@ -105,17 +107,39 @@ func initJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTas
 		jobCtl = NewBlueKingJobCtl(job, workflowCtx, ack, logger)
 	case string(config.JobApproval):
 		jobCtl = NewApprovalJobCtl(job, workflowCtx, ack, logger)
+	case string(config.JobDragonflyPreheat):
+		jobCtl = NewDragonflyPreheatJobCtl(job, workflowCtx, ack, logger)
+	case string(config.JobArgoRollout):
+		jobCtl = NewArgoRolloutJobCtl(job, workflowCtx, ack, logger)
+	case string(config.JobArgoCDSync):
+		jobCtl = NewArgoCDSyncJobCtl(job, workflowCtx, ack, logger)
+	case string(config.JobFluxImageUpdate):
+		jobCtl = NewFluxImageUpdateJobCtl(job, workflowCtx, ack, logger)
+	case string(config.JobZadigRollback):
+		jobCtl = NewZadigRollbackJobCtl(job, workflowCtx, ack, logger)
+	case string(config.JobZadigDistributeImage):
+		if isHarborReplicationJob(job) {
+			jobCtl = NewHarborReplicationJobCtl(job, workflowCtx, ack, logger)
+		} else {
+			jobCtl = NewFreestyleJobCtl(job, workflowCtx, ack, logger)
+		}
 	default:
 		jobCtl = NewFreestyleJobCtl(job, workflowCtx, ack, logger)
 	}
 	return jobCtl
 }
 
+const maxJobRetryInterval = 10 * time.Minute
+
 func runJob(ctx context.Context, job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, logger *zap.SugaredLogger, ack func()) {
 	// should skip passed job when workflow task be restarted
 	if job.Status == config.StatusPassed || job.Status == config.StatusSkipped {
 		return
 	}
+	// the other jobs keep their status when only some jobs of the task are retried
+	if !workflowCtx.ShouldRunJob(job.Key) {
+		return
+	}
 	// render global variables for every job.
 	workflowCtx.GlobalContextEach(func(k, v string) bool {
 		b, _ := json.Marshal(job)
@@ -158,7 +182,21 @@ func runJob(ctx context.Context, job *commonmodels.JobTask, workflowCtx *commonm
 		}
 	}(&jobCtl)
 
-	jobCtl.Run(ctx)
+	// the job pods of the projects are scheduled by weight when the build cluster is shared
+	releaseJobPodSlot, err := acquireJobPodSlot(ctx, job, jobCtl, workflowCtx, logger)
+	if err != nil {
+		job.Status = config.StatusCancelled
+		job.Error = fmt.Sprintf("job is cancelled while waiting for a job pod slot: %s", err)
+		return
+	}
+	defer releaseJobPodSlot()
+
+	runJobCtl(ctx, job, jobCtl)
+
+	// retry the job with its retry policy first, the error policy is applied if it still fails
+	if job.RetryPolicy != nil && job.RetryPolicy.ShouldRetry(job.Status) {
+		retryJobWithPolicy(ctx, workflowCtx.WorkflowName, workflowCtx.TaskID, job, jobCtl, ack, logger)
+	}
 
 	// if the job is in a failed state, do the error handling policy
 	if (job.Status == config.StatusFailed || job.Status == config.StatusTimeout) && job.ErrorPolicy != nil {
@@ -170,6 +208,8 @@ func runJob(ctx context.Context, job *commonmodels.JobTask, workflowCtx *commonm
 		case config.JobErrorPolicyRetry:
 			retryJob(ctx, workflowCtx.WorkflowName, workflowCtx.TaskID, job, jobCtl, ack, job.ErrorPolicy.MaximumRetry)
 		case config.JobErrorPolicyManualCheck:
+			// the job pod has exited, the slot is not held while waiting for the manual check
+			releaseJobPodSlot()
 			waitForManualErrorHandling(ctx, workflowCtx.WorkflowName, workflowCtx.TaskID, job, ack, logger)
 		}
 	}
@@ -192,7 +232,7 @@ func retryJob(ctx context.Context, workflowName string, taskID int64, job *commo
 			job.K8sJobName = getJobName(workflowName, taskID)
 			ack()
 
-			jobCtl.Run(ctx)
+			runJobCtl(ctx, job, jobCtl)
 
 			if job.Status == config.StatusPassed {
 				break
@@ -203,6 +243,61 @@ func retryJob(ctx context.Context, workflowName string, taskID int64, job *commo
 	}
 }
 
+// runJobCtl runs the job and marks it as timeout if it runs longer than the job timeout.
+func runJobCtl(ctx context.Context, job *commonmodels.JobTask, jobCtl JobCtl) {
+	if job.JobTimeout <= 0 {
+		jobCtl.Run(ctx)
+		return
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(job.JobTimeout)*time.Minute)
+	defer cancel()
+	jobCtl.Run(timeoutCtx)
+
+	if ctx.Err() == nil && timeoutCtx.Err() == context.DeadlineExceeded && job.Status != config.StatusPassed {
+		job.Status = config.StatusTimeout
+		job.Error = fmt.Sprintf("job timeout after %d minutes", job.JobTimeout)
+	}
+}
+
+func retryJobWithPolicy(ctx context.Context, workflowName string, taskID int64, job *commonmodels.JobTask, jobCtl JobCtl, ack func(), logger *zap.SugaredLogger) {
+	policy := job.RetryPolicy
+	interval := time.Duration(policy.Interval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		logger.Infof("retry job %s in %s, attempt: %d/%d, last status: %s", job.Name, interval, attempt, policy.MaxAttempts, job.Status)
+		select {
+		case <-ctx.Done():
+			job.Status = config.StatusCancelled
+			job.Error = fmt.Sprintf("controller shutdown, marking job as cancelled.")
+			return
+		case <-time.After(interval):
+		}
+
+		job.RetryCount++
+		job.Status = config.StatusPrepare
+		job.Error = ""
+		job.StartTime = time.Now().Unix()
+		job.K8sJobName = getJobName(workflowName, taskID)
+		ack()
+
+		runJobCtl(ctx, job, jobCtl)
+
+		if !policy.ShouldRetry(job.Status) {
+			return
+		}
+		if policy.Backoff == config.JobRetryBackoffExponential && interval < maxJobRetryInterval {
+			interval *= 2
+			if interval > maxJobRetryInterval {
+				interval = maxJobRetryInterval
+			}
+		}
+	}
+}
+
 func waitForManualErrorHandling(ctx context.Context, workflowName string, taskID int64, job *commonmodels.JobTask, ack func(), logger *zap.SugaredLogger) {
 	originalStatus := job.Status
 	job.Status = config.StatusManualApproval
@@ -109,6 +109,7 @@ func waitForNativeApprove(ctx context.Context, spec *commonmodels.JobTaskApprova
 	}
 
 	approveKey := fmt.Sprintf("%s-%s-%d", workflowName, jobName, taskID)
+	approvalservice.ApplyApprovalDelegations(approval)
 	approvalservice.GlobalApproveMap.SetApproval(approveKey, approval)
 	defer func() {
 		approvalservice.GlobalApproveMap.DeleteApproval(approveKey)
@@ -135,9 +136,14 @@ func waitForNativeApprove(ctx context.Context, spec *commonmodels.JobTaskApprova
 							user.RejectOrApprove = nativeUser.RejectOrApprove
 							user.Comment = nativeUser.Comment
 							user.OperationTime = nativeUser.OperationTime
+							user.DelegatedTo = nativeUser.DelegatedTo
 						}
 					}
 				}
+				// the progress of the quorum rules and steps is shown in the task
+				approval.QuorumRules = navtiveApproval.QuorumRules
+				approval.Steps = navtiveApproval.Steps
+				approval.CurrentStep = navtiveApproval.CurrentStep
 			}
 
 			// update the approval user information
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	crClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/argorollouts"
+)
+
+const defaultArgoRolloutTimeout = 30
+
+type ArgoRolloutJobCtl struct {
+	job         *commonmodels.JobTask
+	workflowCtx *commonmodels.WorkflowTaskCtx
+	logger      *zap.SugaredLogger
+	kubeClient  crClient.Client
+	jobTaskSpec *commonmodels.JobTaskArgoRolloutSpec
+	ack         func()
+}
+
+func NewArgoRolloutJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *ArgoRolloutJobCtl {
+	jobTaskSpec := &commonmodels.JobTaskArgoRolloutSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		logger.Error(err)
+	}
+	job.Spec = jobTaskSpec
+	return &ArgoRolloutJobCtl{
+		job:         job,
+		workflowCtx: workflowCtx,
+		logger:      logger,
+		ack:         ack,
+		jobTaskSpec: jobTaskSpec,
+	}
+}
+
+func (c *ArgoRolloutJobCtl) Clean(ctx context.Context) {}
+
+func (c *ArgoRolloutJobCtl) Run(ctx context.Context) {
+	c.job.Status = config.StatusRunning
+	c.ack()
+
+	var err error
+	c.kubeClient, err = kubeclient.GetKubeClient(config.HubServerAddress(), c.jobTaskSpec.ClusterID)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("can't init k8s client: %v", err), c.logger)
+		return
+	}
+
+	// the step index before the action, a paused rollout is regarded as promoted only if its step moves on
+	initialStepIndex := make(map[string]int64)
+	for _, rollout := range c.jobTaskSpec.Rollouts {
+		if err := c.runAction(rollout, initialStepIndex); err != nil {
+			rollout.Status = config.StatusFailed
+			rollout.Error = err.Error()
+			logError(c.job, fmt.Sprintf("failed to %s rollout %s, error: %v", c.jobTaskSpec.Action, rollout.RolloutName, err), c.logger)
+			return
+		}
+		rollout.Status = config.StatusRunning
+	}
+	c.ack()
+
+	timeout := time.After(time.Duration(c.jobTaskSpec.Timeout) * time.Minute)
+	if c.jobTaskSpec.Timeout <= 0 {
+		timeout = time.After(defaultArgoRolloutTimeout * time.Minute)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			c.job.Status = config.StatusCancelled
+			return
+		case <-timeout:
+			for _, rollout := range c.jobTaskSpec.Rollouts {
+				if rollout.Status == config.StatusRunning {
+					rollout.Status = config.StatusTimeout
+				}
+			}
+			c.job.Status = config.StatusTimeout
+			c.job.Error = "wait for argo rollouts timeout"
+			return
+		default:
+			time.Sleep(3 * time.Second)
+		}
+
+		finished, failed := true, false
+		for _, rollout := range c.jobTaskSpec.Rollouts {
+			if rollout.Status != config.StatusRunning {
+				failed = failed || rollout.Status == config.StatusFailed
+				continue
+			}
+			obj, found, err := argorollouts.GetRollout(c.jobTaskSpec.Namespace, rollout.RolloutName, c.kubeClient)
+			if err != nil || !found {
+				c.logger.Warnf("failed to get rollout %s/%s, found: %v, error: %v", c.jobTaskSpec.Namespace, rollout.RolloutName, found, err)
+				finished = false
+				continue
+			}
+
+			status := argorollouts.GetRolloutStatus(obj)
+			rollout.Phase = status.Phase
+			rollout.Message = status.Message
+			rollout.CurrentStepIndex = status.CurrentStepIndex
+			rollout.StepCount = status.StepCount
+
+			switch c.checkRolloutStatus(status, initialStepIndex[rollout.RolloutName]) {
+			case config.StatusPassed:
+				rollout.Status = config.StatusPassed
+			case config.StatusFailed:
+				rollout.Status = config.StatusFailed
+				rollout.Error = fmt.Sprintf("rollout is %s: %s", status.Phase, status.Message)
+				failed = true
+			default:
+				finished = false
+			}
+		}
+		c.ack()
+
+		if finished {
+			if failed {
+				c.job.Status = config.StatusFailed
+				c.job.Error = "some argo rollouts failed"
+			} else {
+				c.job.Status = config.StatusPassed
+			}
+			return
+		}
+	}
+}
+
+func (c *ArgoRolloutJobCtl) runAction(rollout *commonmodels.ArgoRolloutTaskTarget, initialStepIndex map[string]int64) error {
+	switch c.jobTaskSpec.Action {
+	case config.ArgoRolloutActionSetImage:
+		images := make(map[string]string)
+		for _, container := range rollout.Containers {
+			images[container.Name] = container.Image
+		}
+		return argorollouts.SetImage(c.jobTaskSpec.Namespace, rollout.RolloutName, images, c.kubeClient)
+	case config.ArgoRolloutActionPromote:
+		obj, found, err := argorollouts.GetRollout(c.jobTaskSpec.Namespace, rollout.RolloutName, c.kubeClient)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("rollout %s not found", rollout.RolloutName)
+		}
+		initialStepIndex[rollout.RolloutName] = argorollouts.GetRolloutStatus(obj).CurrentStepIndex
+		return argorollouts.Promote(c.jobTaskSpec.Namespace, rollout.RolloutName, c.jobTaskSpec.Full, c.kubeClient)
+	case config.ArgoRolloutActionAbort:
+		return argorollouts.Abort(c.jobTaskSpec.Namespace, rollout.RolloutName, c.kubeClient)
+	default:
+		return fmt.Errorf("invalid action %s", c.jobTaskSpec.Action)
+	}
+}
+
+// checkRolloutStatus returns passed or failed if the rollout reaches the expected state of the action, otherwise running.
+// A rollout paused by a canary step is regarded as passed since it waits for the next promotion.
+func (c *ArgoRolloutJobCtl) checkRolloutStatus(status *argorollouts.RolloutStatus, initialStepIndex int64) config.Status {
+	if c.jobTaskSpec.Action == config.ArgoRolloutActionAbort {
+		if status.Aborted && status.Phase == argorollouts.PhaseDegraded {
+			return config.StatusPassed
+		}
+		return config.StatusRunning
+	}
+
+	if !status.Observed {
+		return config.StatusRunning
+	}
+	switch status.Phase {
+	case argorollouts.PhaseHealthy:
+		return config.StatusPassed
+	case argorollouts.PhaseDegraded:
+		return config.StatusFailed
+	case argorollouts.PhasePaused:
+		if c.jobTaskSpec.Action == config.ArgoRolloutActionPromote && status.CurrentStepIndex <= initialStepIndex {
+			return config.StatusRunning
+		}
+		return config.StatusPassed
+	}
+	return config.StatusRunning
+}
+
+func (c *ArgoRolloutJobCtl) SaveInfo(ctx context.Context) error {
+	return mongodb.NewJobInfoColl().Create(context.TODO(), &commonmodels.JobInfo{
+		Type:                c.job.JobType,
+		WorkflowName:        c.workflowCtx.WorkflowName,
+		WorkflowDisplayName: c.workflowCtx.WorkflowDisplayName,
+		TaskID:              c.workflowCtx.TaskID,
+		ProductName:         c.workflowCtx.ProjectName,
+		StartTime:           c.job.StartTime,
+		EndTime:             c.job.EndTime,
+		Duration:            c.job.EndTime - c.job.StartTime,
+		Status:              string(c.job.Status),
+	})
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/argocd"
+)
+
+const defaultArgoCDSyncTimeout = 30
+
+type ArgoCDSyncJobCtl struct {
+	job         *commonmodels.JobTask
+	workflowCtx *commonmodels.WorkflowTaskCtx
+	logger      *zap.SugaredLogger
+	client      *argocd.Client
+	jobTaskSpec *commonmodels.JobTaskArgoCDSyncSpec
+	ack         func()
+}
+
+func NewArgoCDSyncJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *ArgoCDSyncJobCtl {
+	jobTaskSpec := &commonmodels.JobTaskArgoCDSyncSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		logger.Error(err)
+	}
+	job.Spec = jobTaskSpec
+	return &ArgoCDSyncJobCtl{
+		job:         job,
+		workflowCtx: workflowCtx,
+		logger:      logger,
+		ack:         ack,
+		jobTaskSpec: jobTaskSpec,
+	}
+}
+
+func (c *ArgoCDSyncJobCtl) Clean(ctx context.Context) {}
+
+func (c *ArgoCDSyncJobCtl) Run(ctx context.Context) {
+	c.job.Status = config.StatusRunning
+	c.ack()
+
+	info, err := mongodb.NewArgoCDColl().GetByID(context.Background(), c.jobTaskSpec.ArgoCDID)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to find argo cd %s, error: %v", c.jobTaskSpec.ArgoCDName, err), c.logger)
+		return
+	}
+	c.client = argocd.NewClient(info.Address, info.Token, info.InsecureSkipVerify)
+
+	// the start time of the last operation before the sync, the sync is regarded as started only if a newer operation shows up
+	prevOperationStart := make(map[string]time.Time)
+	for _, app := range c.jobTaskSpec.Applications {
+		startedAt, err := c.sync(app)
+		if err != nil {
+			app.Status = config.StatusFailed
+			app.Error = err.Error()
+			logError(c.job, fmt.Sprintf("failed to sync argo cd application %s, error: %v", app.ApplicationName, err), c.logger)
+			return
+		}
+		prevOperationStart[app.ApplicationName] = startedAt
+		app.Status = config.StatusRunning
+	}
+	c.ack()
+
+	timeout := time.After(time.Duration(c.jobTaskSpec.Timeout) * time.Minute)
+	if c.jobTaskSpec.Timeout <= 0 {
+		timeout = time.After(defaultArgoCDSyncTimeout * time.Minute)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			c.job.Status = config.StatusCancelled
+			return
+		case <-timeout:
+			for _, app := range c.jobTaskSpec.Applications {
+				if app.Status == config.StatusRunning {
+					app.Status = config.StatusTimeout
+				}
+			}
+			c.job.Status = config.StatusTimeout
+			c.job.Error = "wait for argo cd applications timeout"
+			return
+		default:
+			time.Sleep(3 * time.Second)
+		}
+
+		finished, failed := true, false
+		for _, app := range c.jobTaskSpec.Applications {
+			if app.Status != config.StatusRunning {
+				failed = failed || app.Status == config.StatusFailed
+				continue
+			}
+			application, err := c.client.GetApplication(app.ApplicationName)
+			if err != nil {
+				c.logger.Warnf("failed to get argo cd application %s, error: %v", app.ApplicationName, err)
+				finished = false
+				continue
+			}
+			app.SyncStatus = application.Status.Sync.Status
+			app.HealthStatus = application.Status.Health.Status
+			app.Message = application.Status.Health.Message
+
+			switch c.checkApplicationStatus(application, prevOperationStart[app.ApplicationName]) {
+			case config.StatusPassed:
+				app.Status = config.StatusPassed
+			case config.StatusFailed:
+				app.Status = config.StatusFailed
+				app.Error = c.failureMessage(application)
+				if application.Status.Health.Status == argocd.HealthStatusDegraded && c.jobTaskSpec.RollbackOnDegraded {
+					c.rollback(app)
+				}
+				failed = true
+			default:
+				finished = false
+			}
+		}
+		c.ack()
+
+		if finished {
+			if failed {
+				c.job.Status = config.StatusFailed
+				c.job.Error = "some argo cd applications failed"
+			} else {
+				c.job.Status = config.StatusPassed
+			}
+			return
+		}
+	}
+}
+
+// sync triggers the sync of the application and returns the start time of the last operation before it
+func (c *ArgoCDSyncJobCtl) sync(app *commonmodels.ArgoCDSyncTaskTarget) (time.Time, error) {
+	application, err := c.client.GetApplication(app.ApplicationName)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if application.Operation != nil {
+		return time.Time{}, fmt.Errorf("another operation is in progress")
+	}
+	app.PrevHistoryID = application.LatestHistoryID()
+
+	var startedAt time.Time
+	if application.Status.OperationState != nil {
+		startedAt = application.Status.OperationState.StartedAt
+	}
+
+	args := &argocd.SyncRequest{
+		Revision: app.Revision,
+		Prune:    c.jobTaskSpec.Prune,
+	}
+	if c.jobTaskSpec.Force {
+		args.Strategy = &argocd.SyncStrategy{Hook: &argocd.SyncStrategyHook{Force: true}}
+	}
+	if len(c.jobTaskSpec.SyncOptions) > 0 {
+		args.SyncOptions = &argocd.SyncOptions{Items: c.jobTaskSpec.SyncOptions}
+	}
+	_, err = c.client.SyncApplication(app.ApplicationName, args)
+	return startedAt, err
+}
+
+// checkApplicationStatus returns passed or failed if the sync operation finished and the application health is settled, otherwise running.
+// A suspended application is regarded as passed since it waits for a manual action like the promotion of a rollout.
+func (c *ArgoCDSyncJobCtl) checkApplicationStatus(application *argocd.Application, prevOperationStart time.Time) config.Status {
+	state := application.Status.OperationState
+	if application.Operation != nil || state == nil || !state.StartedAt.After(prevOperationStart) {
+		return config.StatusRunning
+	}
+	switch state.Phase {
+	case argocd.OperationPhaseFailed, argocd.OperationPhaseError:
+		return config.StatusFailed
+	case argocd.OperationPhaseSucceeded:
+	default:
+		return config.StatusRunning
+	}
+
+	switch application.Status.Health.Status {
+	case argocd.HealthStatusHealthy, argocd.HealthStatusSuspended:
+		return config.StatusPassed
+	case argocd.HealthStatusDegraded:
+		return config.StatusFailed
+	}
+	return config.StatusRunning
+}
+
+func (c *ArgoCDSyncJobCtl) failureMessage(application *argocd.Application) string {
+	state := application.Status.OperationState
+	if state != nil && state.Phase != argocd.OperationPhaseSucceeded {
+		return fmt.Sprintf("sync is %s: %s", state.Phase, state.Message)
+	}
+	return fmt.Sprintf("application is %s: %s", application.Status.Health.Status, application.Status.Health.Message)
+}
+
+// rollback rolls the degraded application back to the deployment before the sync, failures are only recorded
+// since the job fails anyway.
+func (c *ArgoCDSyncJobCtl) rollback(app *commonmodels.ArgoCDSyncTaskTarget) {
+	if app.PrevHistoryID == 0 {
+		app.Error = fmt.Sprintf("%s, no previous deployment to roll back to", app.Error)
+		return
+	}
+	_, err := c.client.RollbackApplication(app.ApplicationName, &argocd.RollbackRequest{
+		ID:    app.PrevHistoryID,
+		Prune: c.jobTaskSpec.Prune,
+	})
+	if err != nil {
+		c.logger.Errorf("failed to roll back argo cd application %s to %d, error: %v", app.ApplicationName, app.PrevHistoryID, err)
+		app.Error = fmt.Sprintf("%s, failed to roll back: %v", app.Error, err)
+		return
+	}
+	app.RolledBack = true
+	app.Error = fmt.Sprintf("%s, rolled back to deployment %d", app.Error, app.PrevHistoryID)
+}
+
+func (c *ArgoCDSyncJobCtl) SaveInfo(ctx context.Context) error {
+	return mongodb.NewJobInfoColl().Create(context.TODO(), &commonmodels.JobInfo{
+		Type:                c.job.JobType,
+		WorkflowName:        c.workflowCtx.WorkflowName,
+		WorkflowDisplayName: c.workflowCtx.WorkflowDisplayName,
+		TaskID:              c.workflowCtx.TaskID,
+		ProductName:         c.workflowCtx.ProjectName,
+		StartTime:           c.job.StartTime,
+		EndTime:             c.job.EndTime,
+		Duration:            c.job.EndTime - c.job.StartTime,
+		Status:              string(c.job.Status),
+	})
+}
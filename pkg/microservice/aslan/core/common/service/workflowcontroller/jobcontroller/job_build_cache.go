@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"path"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/buildcache"
+	"github.com/koderover/zadig/v2/pkg/types"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+)
+
+// buildCacheTracker tracks the hit and the upload of the object storage cache of a zadig build job.
+type buildCacheTracker struct {
+	record       *commonmodels.BuildCache
+	lastModified int64
+}
+
+// lookupBuildCache records whether the cache of the build job exists before the job runs,
+// nil is returned if the job doesn't use object storage cache. Failures are logged only
+// since the statistics shouldn't block the build.
+func (c *FreestyleJobCtl) lookupBuildCache() *buildCacheTracker {
+	if c.job.JobType != string(config.JobZadigBuild) || !c.jobTaskSpec.Properties.CacheEnable ||
+		c.jobTaskSpec.Properties.Cache.MediumType != types.ObjectMedium {
+		return nil
+	}
+
+	for _, stepTask := range c.jobTaskSpec.Steps {
+		if stepTask.StepType != config.StepDownloadArchive {
+			continue
+		}
+		spec := &step.StepDownloadArchiveSpec{}
+		if err := commonmodels.IToi(stepTask.Spec, spec); err != nil {
+			c.logger.Errorf("failed to decode download archive step: %v", err)
+			return nil
+		}
+		subfolder := ""
+		if spec.S3 != nil {
+			subfolder = spec.S3.Subfolder
+		}
+		jobInfo := make(map[string]string)
+		if err := commonmodels.IToi(c.job.JobInfo, &jobInfo); err != nil {
+			c.logger.Errorf("failed to decode job info: %v", err)
+			return nil
+		}
+
+		tracker := &buildCacheTracker{
+			record: &commonmodels.BuildCache{
+				WorkflowName:  c.workflowCtx.WorkflowName,
+				ServiceName:   jobInfo["service_name"],
+				ServiceModule: path.Base(spec.ObjectPath),
+				S3StorageID:   c.jobTaskSpec.Properties.Cache.ObjectProperties.ID,
+				ObjectKey:     strings.TrimLeft(path.Join(subfolder, spec.ObjectPath, spec.FileName), "/"),
+			},
+		}
+		lastModified, err := buildcache.RecordLookup(tracker.record)
+		if err != nil {
+			c.logger.Warnf("failed to record build cache lookup: %v", err)
+			return nil
+		}
+		tracker.lastModified = lastModified
+		return tracker
+	}
+	return nil
+}
+
+// recordBuildCacheUpload records the size of the cache uploaded by the build job.
+func (c *FreestyleJobCtl) recordBuildCacheUpload(tracker *buildCacheTracker) {
+	if tracker == nil {
+		return
+	}
+	if err := buildcache.RecordUpload(tracker.record, tracker.lastModified); err != nil {
+		c.logger.Warnf("failed to record build cache upload: %v", err)
+	}
+}
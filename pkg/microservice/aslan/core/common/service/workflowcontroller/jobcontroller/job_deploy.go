@@ -44,6 +44,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/gitops"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	commontypes "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/types"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
@@ -69,6 +70,11 @@ type DeployJobCtl struct {
 	istioClient *versionedclient.Clientset
 	jobTaskSpec *commonmodels.JobTaskDeploySpec
 	ack         func()
+	// dragonflyConfig is used to pull images through the dragonfly mirror of the cluster, nil if it is disabled
+	dragonflyConfig *commonmodels.DragonflyConfig
+	// suspendedCronJobs are the cronjobs suspended by this job during the release, they are resumed when it is done
+	suspendedCronJobs  []string
+	versionLessThan121 bool
 }
 
 func NewDeployJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *DeployJobCtl {
@@ -91,15 +97,53 @@ func (c *DeployJobCtl) Clean(ctx context.Context) {}
 func (c *DeployJobCtl) Run(ctx context.Context) {
 	c.job.Status = config.StatusRunning
 	c.ack()
+	approval, err := newDeployWindowApproval(c.workflowCtx.ProjectName, c.jobTaskSpec.ServiceName, c.jobTaskSpec.Production)
+	if err != nil {
+		logError(c.job, err.Error(), c.logger)
+		return
+	}
+	if approval != nil {
+		c.jobTaskSpec.DeployWindowApproval = approval
+		if !waitForDeployWindowApproval(ctx, c.job, c.workflowCtx, approval, c.ack) {
+			return
+		}
+	}
+
+	if c.jobTaskSpec.ContractCheck != nil {
+		images := make([]string, 0, len(c.jobTaskSpec.ServiceAndImages))
+		for _, svc := range c.jobTaskSpec.ServiceAndImages {
+			images = append(images, svc.Image)
+		}
+		result, ok := checkContractCompatibility(c.job, c.jobTaskSpec.ContractCheck, &contractCheckTarget{
+			projectName: c.workflowCtx.ProjectName,
+			envName:     c.jobTaskSpec.Env,
+			production:  c.jobTaskSpec.Production,
+			serviceName: c.jobTaskSpec.ServiceName,
+			images:      images,
+		}, c.logger)
+		c.jobTaskSpec.ContractCheckResult = result
+		if !ok {
+			return
+		}
+	}
+
 	c.preRun()
+	if c.jobTaskSpec.GitOpsExport != nil {
+		c.exportToGit()
+		return
+	}
+	c.recordPreviousRevision()
 	if err := c.run(ctx); err != nil {
 		return
 	}
 	if c.jobTaskSpec.SkipCheckRunStatus {
 		c.job.Status = config.StatusPassed
-		return
+	} else {
+		c.wait(ctx)
+	}
+	if c.job.Status == config.StatusPassed && c.jobTaskSpec.BakeTime > 0 {
+		c.bake(ctx)
 	}
-	c.wait(ctx)
 }
 
 func (c *DeployJobCtl) preRun() {
@@ -132,6 +176,7 @@ func (c *DeployJobCtl) run(ctx context.Context) error {
 
 	c.namespace = env.Namespace
 	c.jobTaskSpec.ClusterID = env.ClusterID
+	c.dragonflyConfig = kube.GetDragonflyConfig(env.ClusterID)
 
 	c.restConfig, err = kubeclient.GetRESTConfig(config.HubServerAddress(), c.jobTaskSpec.ClusterID)
 	if err != nil {
@@ -169,6 +214,61 @@ func (c *DeployJobCtl) run(ctx context.Context) error {
 
 	// k8s projects
 	//if c.jobTaskSpec.CreateEnvType == "system" {
+	option, err := c.geneSvcYamlOption(env)
+	if err != nil {
+		logError(c.job, err.Error(), c.logger)
+		return err
+	}
+	updateRevision, containers := option.UpdateServiceRevision, option.Containers
+	updatedYaml, revision, resources, err := kube.GenerateRenderedYaml(option)
+	if err != nil {
+		msg := fmt.Sprintf("generate service yaml error: %v", err)
+		logError(c.job, msg, c.logger)
+		return errors.New(msg)
+	}
+
+	// suspend the cronjobs of the service so that no schedule fires with a half released spec
+	if err := c.suspendCronJobs(resources); err != nil {
+		msg := fmt.Sprintf("suspend cronjobs error: %v", err)
+		logError(c.job, msg, c.logger)
+		return errors.New(msg)
+	}
+	defer c.resumeCronJobs()
+
+	// the rollout settings are applied before the update so that the partition takes effect on it
+	if err := c.applyStatefulSetRollout(resources); err != nil {
+		logError(c.job, err.Error(), c.logger)
+		return err
+	}
+	c.jobTaskSpec.YamlContent = updatedYaml
+	c.ack()
+
+	currentYaml, _, err := kube.FetchCurrentAppliedYaml(option)
+	if err != nil {
+		msg := fmt.Sprintf("get current service yaml error: %v", err)
+		logError(c.job, msg, c.logger)
+		return errors.New(msg)
+	}
+
+	// if not only deploy image, we will redeploy service
+	if !onlyDeployImage(c.jobTaskSpec.DeployContents) {
+		if err := c.updateSystemService(env, currentYaml, updatedYaml, c.jobTaskSpec.VariableKVs, revision, containers, updateRevision, c.jobTaskSpec.ServiceName); err != nil {
+			logError(c.job, err.Error(), c.logger)
+			return err
+		}
+
+		return nil
+	}
+	// if only deploy image, we only patch image.
+	if err := c.updateServiceModuleImages(ctx, resources, env); err != nil {
+		logError(c.job, err.Error(), c.logger)
+		return err
+	}
+
+	return nil
+}
+
+func (c *DeployJobCtl) geneSvcYamlOption(env *commonmodels.Product) (*kube.GeneSvcYamlOption, error) {
 	var updateRevision bool
 	if slices.Contains(c.jobTaskSpec.DeployContents, config.DeployConfig) && c.jobTaskSpec.UpdateConfig {
 		updateRevision = true
@@ -177,11 +277,10 @@ func (c *DeployJobCtl) run(ctx context.Context) error {
 	varsYaml := ""
 	varKVs := []*commontypes.RenderVariableKV{}
 	if slices.Contains(c.jobTaskSpec.DeployContents, config.DeployVars) {
+		var err error
 		varsYaml, err = commontypes.RenderVariableKVToYaml(c.jobTaskSpec.VariableKVs)
 		if err != nil {
-			msg := fmt.Sprintf("generate vars yaml error: %v", err)
-			logError(c.job, msg, c.logger)
-			return errors.New(msg)
+			return nil, fmt.Errorf("generate vars yaml error: %v", err)
 		}
 		varKVs = c.jobTaskSpec.VariableKVs
 	}
@@ -196,7 +295,7 @@ func (c *DeployJobCtl) run(ctx context.Context) error {
 		}
 	}
 
-	option := &kube.GeneSvcYamlOption{
+	return &kube.GeneSvcYamlOption{
 		ProductName:           env.ProductName,
 		EnvName:               c.jobTaskSpec.Env,
 		ServiceName:           c.jobTaskSpec.ServiceName,
@@ -204,39 +303,51 @@ func (c *DeployJobCtl) run(ctx context.Context) error {
 		VariableYaml:          varsYaml,
 		VariableKVs:           varKVs,
 		Containers:            containers,
+	}, nil
+}
+
+// exportToGit commits the rendered yaml of the service to the gitops repository instead of applying it,
+// the env is left untouched since the cluster is synced from the repository by Argo CD or Flux.
+func (c *DeployJobCtl) exportToGit() {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:    c.workflowCtx.ProjectName,
+		EnvName: c.jobTaskSpec.Env,
+	})
+	if err != nil {
+		logError(c.job, fmt.Sprintf("find project error: %v", err), c.logger)
+		return
 	}
-	updatedYaml, revision, resources, err := kube.GenerateRenderedYaml(option)
+	c.jobTaskSpec.ClusterID = env.ClusterID
+
+	option, err := c.geneSvcYamlOption(env)
 	if err != nil {
-		msg := fmt.Sprintf("generate service yaml error: %v", err)
-		logError(c.job, msg, c.logger)
-		return errors.New(msg)
+		logError(c.job, err.Error(), c.logger)
+		return
+	}
+	updatedYaml, _, _, err := kube.GenerateRenderedYaml(option)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("generate service yaml error: %v", err), c.logger)
+		return
 	}
 	c.jobTaskSpec.YamlContent = updatedYaml
 	c.ack()
 
-	currentYaml, _, err := kube.FetchCurrentAppliedYaml(option)
+	result, err := gitops.Export(&gitops.ExportParam{
+		Config:       c.jobTaskSpec.GitOpsExport,
+		Production:   env.Production,
+		ProjectName:  env.ProductName,
+		EnvName:      env.EnvName,
+		ServiceName:  c.jobTaskSpec.ServiceName,
+		WorkflowName: c.workflowCtx.WorkflowName,
+		TaskID:       c.workflowCtx.TaskID,
+		Manifest:     updatedYaml,
+	})
 	if err != nil {
-		msg := fmt.Sprintf("get current service yaml error: %v", err)
-		logError(c.job, msg, c.logger)
-		return errors.New(msg)
-	}
-
-	// if not only deploy image, we will redeploy service
-	if !onlyDeployImage(c.jobTaskSpec.DeployContents) {
-		if err := c.updateSystemService(env, currentYaml, updatedYaml, c.jobTaskSpec.VariableKVs, revision, containers, updateRevision, c.jobTaskSpec.ServiceName); err != nil {
-			logError(c.job, err.Error(), c.logger)
-			return err
-		}
-
-		return nil
-	}
-	// if only deploy image, we only patch image.
-	if err := c.updateServiceModuleImages(ctx, resources, env); err != nil {
-		logError(c.job, err.Error(), c.logger)
-		return err
+		logError(c.job, fmt.Sprintf("export service yaml error: %v", err), c.logger)
+		return
 	}
-
-	return nil
+	c.jobTaskSpec.GitOpsExportResult = result
+	c.job.Status = config.StatusPassed
 }
 
 func onlyDeployImage(deployContents []config.DeployContent) bool {
@@ -304,7 +415,17 @@ func (c *DeployJobCtl) updateSystemService(env *commonmodels.Product, currentYam
 				c.jobTaskSpec.RelatedPodLabels = append(c.jobTaskSpec.RelatedPodLabels, podLabels)
 			}
 			c.jobTaskSpec.ReplaceResources = append(c.jobTaskSpec.ReplaceResources, commonmodels.Resource{Name: us.GetName(), Kind: us.GetKind()})
-		case setting.CronJob, setting.Job:
+		case setting.CronJob:
+			// the cronjob is suspended in the new yaml, keep it as it is after the release
+			if suspend, found, err := unstructured.NestedBool(us.Object, "spec", "suspend"); err == nil && found && suspend {
+				c.suspendedCronJobs = slices.DeleteFunc(c.suspendedCronJobs, func(name string) bool { return name == us.GetName() })
+			}
+			c.jobTaskSpec.ReplaceResources = append(c.jobTaskSpec.ReplaceResources, commonmodels.Resource{Name: us.GetName(), Kind: us.GetKind()})
+		case setting.Job:
+			podLabels, _, err := unstructured.NestedStringMap(us.Object, "spec", "template", "metadata", "labels")
+			if err == nil && len(podLabels) > 0 {
+				c.jobTaskSpec.RelatedPodLabels = append(c.jobTaskSpec.RelatedPodLabels, podLabels)
+			}
 			c.jobTaskSpec.ReplaceResources = append(c.jobTaskSpec.ReplaceResources, commonmodels.Resource{Name: us.GetName(), Kind: us.GetKind()})
 		}
 	}
@@ -324,7 +445,7 @@ L:
 	for _, deploy := range deployments {
 		for _, container := range deploy.Spec.Template.Spec.Containers {
 			if container.Name == serviceModule.ServiceModule {
-				err = updater.UpdateDeploymentImage(deploy.Namespace, deploy.Name, serviceModule.ServiceModule, serviceModule.Image, c.kubeClient)
+				err = updater.UpdateDeploymentImage(deploy.Namespace, deploy.Name, serviceModule.ServiceModule, c.dragonflyConfig.MirrorImage(serviceModule.Image), c.kubeClient)
 				if err != nil {
 					return fmt.Errorf("failed to update container image in %s/deployments/%s/%s: %v", env.Namespace, deploy.Name, container.Name, err)
 				}
@@ -344,7 +465,7 @@ Loop:
 	for _, sts := range statefulSets {
 		for _, container := range sts.Spec.Template.Spec.Containers {
 			if container.Name == serviceModule.ServiceModule {
-				err = updater.UpdateStatefulSetImage(sts.Namespace, sts.Name, serviceModule.ServiceModule, serviceModule.Image, c.kubeClient)
+				err = updater.UpdateStatefulSetImage(sts.Namespace, sts.Name, serviceModule.ServiceModule, c.dragonflyConfig.MirrorImage(serviceModule.Image), c.kubeClient)
 				if err != nil {
 					return fmt.Errorf("failed to update container image in %s/statefulsets/%s/%s: %v", env.Namespace, sts.Name, container.Name, err)
 				}
@@ -364,7 +485,7 @@ CronLoop:
 	for _, cron := range cronJobs {
 		for _, container := range cron.Spec.JobTemplate.Spec.Template.Spec.Containers {
 			if container.Name == serviceModule.ServiceModule {
-				err = updater.UpdateCronJobImage(cron.Namespace, cron.Name, serviceModule.ServiceModule, serviceModule.Image, c.kubeClient, false)
+				err = updater.UpdateCronJobImage(cron.Namespace, cron.Name, serviceModule.ServiceModule, c.dragonflyConfig.MirrorImage(serviceModule.Image), c.kubeClient, false)
 				if err != nil {
 					return fmt.Errorf("failed to update container image in %s/cronJob/%s/%s: %v", env.Namespace, cron.Name, container.Name, err)
 				}
@@ -384,7 +505,7 @@ BetaCronLoop:
 	for _, cron := range betaCronJobs {
 		for _, container := range cron.Spec.JobTemplate.Spec.Template.Spec.Containers {
 			if container.Name == serviceModule.ServiceModule {
-				err = updater.UpdateCronJobImage(cron.Namespace, cron.Name, serviceModule.ServiceModule, serviceModule.Image, c.kubeClient, true)
+				err = updater.UpdateCronJobImage(cron.Namespace, cron.Name, serviceModule.ServiceModule, c.dragonflyConfig.MirrorImage(serviceModule.Image), c.kubeClient, true)
 				if err != nil {
 					return fmt.Errorf("failed to update container image in %s/cronJobBeta/%s/%s: %v", env.Namespace, cron.Name, container.Name, err)
 				}
@@ -404,19 +525,20 @@ Job:
 	for _, job := range jobs {
 		for _, container := range job.Spec.Template.Spec.Containers {
 			if container.Name == serviceModule.ServiceModule {
-				return fmt.Errorf("job %s/%s/%s is not supported to update image", env.Namespace, job.Name, container.Name)
-				// err = updater.UpdateJobImage(job.Namespace, job.Name, serviceModule.ServiceModule, serviceModule.Image, c.kubeClient)
-				// if err != nil {
-				// 	return fmt.Errorf("failed to update container image in %s/job/%s/%s: %v", env.Namespace, job.Name, container.Name, err)
-				// }
-				// c.jobTaskSpec.ReplaceResources = append(c.jobTaskSpec.ReplaceResources, commonmodels.Resource{
-				// 	Kind:      setting.Job,
-				// 	Container: container.Name,
-				// 	Origin:    container.Image,
-				// 	Name:      job.Name,
-				// })
-				// replaced = true
-				// c.jobTaskSpec.RelatedPodLabels = append(c.jobTaskSpec.RelatedPodLabels, job.Spec.Template.Labels)
+				// the pod template of a job is immutable, so the job is recreated with the new image
+				if err = c.recreateJobWithImage(job, serviceModule.ServiceModule, c.dragonflyConfig.MirrorImage(serviceModule.Image)); err != nil {
+					return fmt.Errorf("failed to update container image in %s/job/%s/%s: %v", env.Namespace, job.Name, container.Name, err)
+				}
+				c.jobTaskSpec.ReplaceResources = append(c.jobTaskSpec.ReplaceResources, commonmodels.Resource{
+					Kind:      setting.Job,
+					Container: container.Name,
+					Origin:    container.Image,
+					Name:      job.Name,
+				})
+				replaced = true
+				if podLabels := jobPodLabels(job); len(podLabels) > 0 {
+					c.jobTaskSpec.RelatedPodLabels = append(c.jobTaskSpec.RelatedPodLabels, podLabels)
+				}
 				break Job
 			}
 		}
@@ -485,7 +607,7 @@ func (c *DeployJobCtl) getResourcesPodOwnerUIDImpl(strict bool) ([]commonmodels.
 		for _, serviceImage := range c.jobTaskSpec.ServiceAndImages {
 			containerMap[serviceImage.ServiceModule] = &commonmodels.Container{
 				Name:      serviceImage.ServiceModule,
-				Image:     serviceImage.Image,
+				Image:     c.dragonflyConfig.MirrorImage(serviceImage.Image),
 				ImageName: util.ExtractImageName(serviceImage.Image),
 			}
 		}
@@ -499,6 +621,14 @@ func (c *DeployJobCtl) getResourcesPodOwnerUIDImpl(strict bool) ([]commonmodels.
 				return newResources, err
 			}
 			resource.PodOwnerUID = string(sts.ObjectMeta.UID)
+		case setting.Job:
+			job, found, err := getter.GetJob(c.namespace, resource.Name, c.kubeClient)
+			if err != nil {
+				return newResources, err
+			}
+			if found {
+				resource.PodOwnerUID = string(job.ObjectMeta.UID)
+			}
 		case setting.Deployment:
 			deployment, _, err := getter.GetDeployment(c.namespace, resource.Name, c.kubeClient)
 			if err != nil {
@@ -661,10 +791,33 @@ func (c *DeployJobCtl) wait(ctx context.Context) {
 							e,
 						)
 						ready = false
+					} else if c.jobTaskSpec.StatefulSetRollout != nil && c.jobTaskSpec.StatefulSetRollout.Partition != nil {
+						ready = kube.StatefulSetRolledOut(st)
 					} else {
 						ready = wrapper.StatefulSet(st).Ready()
 					}
 
+					if !ready {
+						break L
+					}
+				case setting.Job:
+					j, found, e := getter.GetJob(c.namespace, resource.Name, c.kubeClient)
+					if e != nil || !found {
+						c.logger.Errorf(
+							"failed to check job status %s/%s/%s - %v",
+							c.namespace,
+							resource.Kind,
+							resource.Name,
+							e,
+						)
+						ready = false
+					} else if wrapper.Job(j).Failed() {
+						logError(c.job, fmt.Sprintf("job %s/%s failed", c.namespace, resource.Name), c.logger)
+						return
+					} else {
+						ready = wrapper.Job(j).Complete()
+					}
+
 					if !ready {
 						break L
 					}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/kube/wrapper"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+)
+
+const (
+	bakeCheckInterval = 10 * time.Second
+	// bakeMaxUnreadyChecks is the number of continuous checks a workload is allowed to be unready, to tolerate scaling and node draining
+	bakeMaxUnreadyChecks = 3
+)
+
+// recordPreviousRevision saves the env service revision before the deployment, which is the rollback target of the bake verification
+func (c *DeployJobCtl) recordPreviousRevision() {
+	if c.jobTaskSpec.BakeTime <= 0 {
+		return
+	}
+	_, revision, err := commonrepo.NewEnvServiceVersionColl().GetCountAndMaxRevision(c.workflowCtx.ProjectName, c.jobTaskSpec.Env, c.jobTaskSpec.ServiceName, false, c.jobTaskSpec.Production)
+	if err != nil {
+		c.logger.Warnf("failed to get the revision of service %s in env %s, error: %v", c.jobTaskSpec.ServiceName, c.jobTaskSpec.Env, err)
+		return
+	}
+	c.jobTaskSpec.PreviousRevision = revision
+}
+
+// bake keeps the job verifying for the bake time, the service is rolled back to the previous revision
+// if the pods are crashing or restarting, the workloads stay unready, or the task is cancelled.
+func (c *DeployJobCtl) bake(ctx context.Context) {
+	c.job.Status = config.StatusVerifying
+	c.ack()
+
+	restartCounts, err := c.getRestartCounts()
+	if err != nil {
+		c.logger.Warnf("failed to get the restart counts of service %s, error: %v", c.jobTaskSpec.ServiceName, err)
+	}
+
+	unreadyChecks := 0
+	deadline := time.After(time.Duration(c.jobTaskSpec.BakeTime) * time.Minute)
+	for {
+		select {
+		case <-ctx.Done():
+			if err := c.rollbackAfterBake("the task is cancelled during the verification"); err != nil {
+				c.logger.Errorf("failed to roll back service %s after the task is cancelled, error: %v", c.jobTaskSpec.ServiceName, err)
+			}
+			c.job.Status = config.StatusCancelled
+			return
+		case <-deadline:
+			c.job.Status = config.StatusPassed
+			return
+		case <-time.After(bakeCheckInterval):
+		}
+
+		if err := c.checkPodsHealth(restartCounts); err != nil {
+			c.failBake(err.Error())
+			return
+		}
+
+		ready, err := c.workloadsReady()
+		if err != nil {
+			c.logger.Warnf("failed to check the workloads of service %s, error: %v", c.jobTaskSpec.ServiceName, err)
+			continue
+		}
+		if ready {
+			unreadyChecks = 0
+			continue
+		}
+		unreadyChecks++
+		if unreadyChecks >= bakeMaxUnreadyChecks {
+			c.failBake(fmt.Sprintf("workloads of service %s are not ready for %s", c.jobTaskSpec.ServiceName, bakeCheckInterval*bakeMaxUnreadyChecks))
+			return
+		}
+	}
+}
+
+func (c *DeployJobCtl) failBake(reason string) {
+	if err := c.rollbackAfterBake(reason); err != nil {
+		logError(c.job, fmt.Sprintf("verification failed: %s, and failed to roll back: %v", reason, err), c.logger)
+		return
+	}
+	logError(c.job, fmt.Sprintf("verification failed: %s, rolled back to revision %d", reason, c.jobTaskSpec.PreviousRevision), c.logger)
+}
+
+func (c *DeployJobCtl) rollbackAfterBake(reason string) error {
+	if c.jobTaskSpec.PreviousRevision <= 0 {
+		return fmt.Errorf("no previous revision of service %s is recorded", c.jobTaskSpec.ServiceName)
+	}
+	c.logger.Infof("rolling back service %s in env %s to revision %d: %s", c.jobTaskSpec.ServiceName, c.jobTaskSpec.Env, c.jobTaskSpec.PreviousRevision, reason)
+
+	version, err := commonrepo.NewEnvServiceVersionColl().Find(c.workflowCtx.ProjectName, c.jobTaskSpec.Env, c.jobTaskSpec.ServiceName, false, c.jobTaskSpec.Production, c.jobTaskSpec.PreviousRevision)
+	if err != nil {
+		return fmt.Errorf("failed to find revision %d of service %s, error: %v", c.jobTaskSpec.PreviousRevision, c.jobTaskSpec.ServiceName, err)
+	}
+
+	args := &kube.EnvRollbackArgs{
+		ProjectName: c.workflowCtx.ProjectName,
+		EnvName:     c.jobTaskSpec.Env,
+		Production:  c.jobTaskSpec.Production,
+		Source:      config.RollbackSourceBakeVerification,
+		Services:    []string{c.jobTaskSpec.ServiceName},
+	}
+	record := kube.NewEnvRollbackRecord(args, version, config.RollbackTriggerWorkflow, c.workflowCtx.WorkflowTaskCreatorUsername)
+	record.TriggerWorkflowName = c.workflowCtx.WorkflowName
+	record.TriggerTaskID = c.workflowCtx.TaskID
+	if err := kube.RollbackEnvService(version, record, kube.EnsureUpdateZadigService, c.logger); err != nil {
+		return err
+	}
+	c.jobTaskSpec.RolledBack = true
+	return nil
+}
+
+// getRestartCounts returns the restart count of every container of the service, keyed by pod/container
+func (c *DeployJobCtl) getRestartCounts() (map[string]int32, error) {
+	resp := make(map[string]int32)
+	for _, label := range c.jobTaskSpec.RelatedPodLabels {
+		pods, err := getter.ListPods(c.namespace, labels.Set(label).AsSelector(), c.kubeClient)
+		if err != nil {
+			return resp, err
+		}
+		for _, pod := range pods {
+			for _, cs := range pod.Status.ContainerStatuses {
+				resp[pod.Name+"/"+cs.Name] = cs.RestartCount
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (c *DeployJobCtl) checkPodsHealth(restartCounts map[string]int32) error {
+	for _, label := range c.jobTaskSpec.RelatedPodLabels {
+		pods, err := getter.ListPods(c.namespace, labels.Set(label).AsSelector(), c.kubeClient)
+		if err != nil {
+			c.logger.Warnf("failed to list pods of service %s, error: %v", c.jobTaskSpec.ServiceName, err)
+			return nil
+		}
+		for _, pod := range pods {
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.State.Waiting != nil {
+					switch cs.State.Waiting.Reason {
+					case "ImagePullBackOff", "ErrImagePull", "CrashLoopBackOff", "ErrImageNeverPull":
+						return fmt.Errorf("pod: %s, %s: %s", pod.Name, cs.State.Waiting.Reason, cs.State.Waiting.Message)
+					}
+				}
+				if cs.RestartCount > restartCounts[pod.Name+"/"+cs.Name] {
+					return fmt.Errorf("container %s of pod %s restarted %d times", cs.Name, pod.Name, cs.RestartCount-restartCounts[pod.Name+"/"+cs.Name])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *DeployJobCtl) workloadsReady() (bool, error) {
+	for _, resource := range c.jobTaskSpec.ReplaceResources {
+		switch resource.Kind {
+		case setting.Deployment:
+			d, found, err := getter.GetDeployment(c.namespace, resource.Name, c.kubeClient)
+			if err != nil {
+				return false, err
+			}
+			if !found || !wrapper.Deployment(d).Ready() {
+				return false, nil
+			}
+		case setting.StatefulSet:
+			st, found, err := getter.GetStatefulSet(c.namespace, resource.Name, c.kubeClient)
+			if err != nil {
+				return false, err
+			}
+			if !found || !wrapper.StatefulSet(st).Ready() {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
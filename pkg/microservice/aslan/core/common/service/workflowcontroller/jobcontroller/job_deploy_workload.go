@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/updater"
+)
+
+// suspendCronJobs suspends the running cronjobs among the resources of the service,
+// cronjobs which are already suspended are left untouched and won't be resumed later.
+func (c *DeployJobCtl) suspendCronJobs(resources []*kube.WorkloadResource) error {
+	hasCronJob := false
+	for _, resource := range resources {
+		if resource.Type == setting.CronJob {
+			hasCronJob = true
+			break
+		}
+	}
+	if !hasCronJob {
+		return nil
+	}
+
+	k8sServerVersion, err := c.clientSet.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get k8s server version: %v", err)
+	}
+	c.versionLessThan121 = kubeclient.VersionLessThan121(k8sServerVersion)
+
+	for _, resource := range resources {
+		if resource.Type != setting.CronJob {
+			continue
+		}
+		cronJob, cronJobBeta, found, err := getter.GetCronJob(c.namespace, resource.Name, c.kubeClient, c.versionLessThan121)
+		if err != nil {
+			return fmt.Errorf("failed to get cronjob %s: %v", resource.Name, err)
+		}
+		if !found {
+			continue
+		}
+
+		suspended := false
+		if cronJob != nil && cronJob.Spec.Suspend != nil {
+			suspended = *cronJob.Spec.Suspend
+		}
+		if cronJobBeta != nil && cronJobBeta.Spec.Suspend != nil {
+			suspended = *cronJobBeta.Spec.Suspend
+		}
+		if suspended {
+			continue
+		}
+
+		if err := updater.SuspendCronJob(c.namespace, resource.Name, c.kubeClient, c.versionLessThan121); err != nil {
+			return fmt.Errorf("failed to suspend cronjob %s: %v", resource.Name, err)
+		}
+		c.suspendedCronJobs = append(c.suspendedCronJobs, resource.Name)
+	}
+	return nil
+}
+
+// resumeCronJobs resumes the cronjobs suspended by suspendCronJobs, failures are logged only
+// since the release itself is already done.
+func (c *DeployJobCtl) resumeCronJobs() {
+	for _, name := range c.suspendedCronJobs {
+		if err := updater.ResumeCronJob(c.namespace, name, c.kubeClient, c.versionLessThan121); err != nil {
+			c.logger.Errorf("failed to resume cronjob %s/%s: %v", c.namespace, name, err)
+		}
+	}
+	c.suspendedCronJobs = nil
+}
+
+func (c *DeployJobCtl) applyStatefulSetRollout(resources []*kube.WorkloadResource) error {
+	if c.jobTaskSpec.StatefulSetRollout == nil {
+		return nil
+	}
+	for _, resource := range resources {
+		if resource.Type != setting.StatefulSet {
+			continue
+		}
+		if err := kube.ApplyStatefulSetRollout(c.namespace, resource.Name, c.jobTaskSpec.StatefulSetRollout, c.kubeClient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *DeployJobCtl) recreateJobWithImage(job *batchv1.Job, container, image string) error {
+	job = job.DeepCopy()
+	for i := range job.Spec.Template.Spec.Containers {
+		if job.Spec.Template.Spec.Containers[i].Name == container {
+			job.Spec.Template.Spec.Containers[i].Image = image
+		}
+	}
+	return updater.RecreateJob(job, c.kubeClient)
+}
+
+// jobPodLabels returns the pod labels of the job without the ones generated by the job controller,
+// which change every time the job is recreated.
+func jobPodLabels(job *batchv1.Job) map[string]string {
+	podLabels := make(map[string]string)
+	for key, value := range job.Spec.Template.Labels {
+		switch key {
+		case "controller-uid", "job-name", "batch.kubernetes.io/controller-uid", "batch.kubernetes.io/job-name":
+			continue
+		}
+		podLabels[key] = value
+	}
+	return podLabels
+}
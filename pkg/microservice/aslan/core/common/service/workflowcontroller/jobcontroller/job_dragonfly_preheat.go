@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/dragonfly"
+)
+
+type DragonflyPreheatJobCtl struct {
+	job         *commonmodels.JobTask
+	workflowCtx *commonmodels.WorkflowTaskCtx
+	logger      *zap.SugaredLogger
+	jobTaskSpec *commonmodels.JobTaskDragonflyPreheatSpec
+	ack         func()
+}
+
+func NewDragonflyPreheatJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *DragonflyPreheatJobCtl {
+	jobTaskSpec := &commonmodels.JobTaskDragonflyPreheatSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		logger.Error(err)
+	}
+	job.Spec = jobTaskSpec
+	return &DragonflyPreheatJobCtl{
+		job:         job,
+		workflowCtx: workflowCtx,
+		logger:      logger,
+		ack:         ack,
+		jobTaskSpec: jobTaskSpec,
+	}
+}
+
+func (c *DragonflyPreheatJobCtl) Clean(ctx context.Context) {}
+
+func (c *DragonflyPreheatJobCtl) Run(ctx context.Context) {
+	c.job.Status = config.StatusRunning
+	c.ack()
+
+	cluster, err := mongodb.NewK8SClusterColl().Get(c.jobTaskSpec.ClusterID)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to find cluster %s, error: %v", c.jobTaskSpec.ClusterID, err), c.logger)
+		return
+	}
+	if cluster.DragonflyConfig == nil || !cluster.DragonflyConfig.Enabled || cluster.DragonflyConfig.ManagerAddress == "" {
+		logError(c.job, fmt.Sprintf("dragonfly is not enabled in cluster %s", cluster.Name), c.logger)
+		return
+	}
+	client := dragonfly.NewClient(cluster.DragonflyConfig.ManagerAddress, cluster.DragonflyConfig.ManagerToken)
+
+	registries, err := mongodb.NewRegistryNamespaceColl().FindAll(&mongodb.FindRegOps{})
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to list registries, error: %v", err), c.logger)
+		return
+	}
+
+	for _, image := range c.jobTaskSpec.Images {
+		args := &dragonfly.PreheatArgs{
+			Type:  dragonfly.PreheatTypeImage,
+			Scope: c.jobTaskSpec.Scope,
+		}
+		scheme := ""
+		// use the credential of the zadig registry to pull the manifest of private images
+		if matched := getMatchedRegistries(image.Image, registries); len(matched) > 0 {
+			args.Username = matched[0].AccessKey
+			args.Password = matched[0].SecretKey
+			if u, err := url.Parse(matched[0].RegAddr); err == nil {
+				scheme = u.Scheme
+			}
+		}
+		args.URL, err = dragonfly.ManifestURL(image.Image, scheme)
+		if err != nil {
+			image.Status = config.StatusFailed
+			image.Error = err.Error()
+			logError(c.job, err.Error(), c.logger)
+			return
+		}
+
+		job, err := client.CreatePreheatJob(args)
+		if err != nil {
+			image.Status = config.StatusFailed
+			image.Error = err.Error()
+			logError(c.job, fmt.Sprintf("failed to create preheat job for image %s, error: %v", image.Image, err), c.logger)
+			return
+		}
+		image.JobID = job.ID
+		image.Status = config.StatusRunning
+	}
+	c.ack()
+
+	timeout := time.After(time.Duration(c.jobTaskSpec.Timeout) * time.Minute)
+	if c.jobTaskSpec.Timeout <= 0 {
+		timeout = time.After(60 * time.Minute)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			c.job.Status = config.StatusCancelled
+			return
+		case <-timeout:
+			for _, image := range c.jobTaskSpec.Images {
+				if image.Status == config.StatusRunning {
+					image.Status = config.StatusTimeout
+				}
+			}
+			c.job.Status = config.StatusTimeout
+			c.job.Error = "preheat timeout"
+			return
+		default:
+			time.Sleep(3 * time.Second)
+		}
+
+		finished, failed := true, false
+		for _, image := range c.jobTaskSpec.Images {
+			if image.Status != config.StatusRunning {
+				failed = failed || image.Status == config.StatusFailed
+				continue
+			}
+			job, err := client.GetJob(image.JobID)
+			if err != nil {
+				c.logger.Warnf("failed to get dragonfly preheat job %d, error: %v", image.JobID, err)
+				finished = false
+				continue
+			}
+			switch job.State {
+			case dragonfly.JobStateSuccess:
+				image.Status = config.StatusPassed
+			case dragonfly.JobStateFailure:
+				image.Status = config.StatusFailed
+				image.Error = fmt.Sprintf("dragonfly preheat job %d failed", image.JobID)
+				failed = true
+			default:
+				finished = false
+			}
+		}
+		c.ack()
+
+		if finished {
+			if failed {
+				c.job.Status = config.StatusFailed
+				c.job.Error = "some images failed to be preheated"
+			} else {
+				c.job.Status = config.StatusPassed
+			}
+			return
+		}
+	}
+}
+
+func (c *DragonflyPreheatJobCtl) SaveInfo(ctx context.Context) error {
+	return mongodb.NewJobInfoColl().Create(context.TODO(), &commonmodels.JobInfo{
+		Type:                c.job.JobType,
+		WorkflowName:        c.workflowCtx.WorkflowName,
+		WorkflowDisplayName: c.workflowCtx.WorkflowDisplayName,
+		TaskID:              c.workflowCtx.TaskID,
+		ProductName:         c.workflowCtx.ProjectName,
+		StartTime:           c.job.StartTime,
+		EndTime:             c.job.EndTime,
+		Duration:            c.job.EndTime - c.job.StartTime,
+		Status:              string(c.job.Status),
+	})
+}
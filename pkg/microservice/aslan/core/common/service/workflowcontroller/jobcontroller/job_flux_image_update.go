@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/gitops"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/flux"
+)
+
+type FluxImageUpdateJobCtl struct {
+	job         *commonmodels.JobTask
+	workflowCtx *commonmodels.WorkflowTaskCtx
+	logger      *zap.SugaredLogger
+	jobTaskSpec *commonmodels.JobTaskFluxImageUpdateSpec
+	ack         func()
+}
+
+func NewFluxImageUpdateJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *FluxImageUpdateJobCtl {
+	jobTaskSpec := &commonmodels.JobTaskFluxImageUpdateSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		logger.Error(err)
+	}
+	job.Spec = jobTaskSpec
+	return &FluxImageUpdateJobCtl{
+		job:         job,
+		workflowCtx: workflowCtx,
+		logger:      logger,
+		ack:         ack,
+		jobTaskSpec: jobTaskSpec,
+	}
+}
+
+func (c *FluxImageUpdateJobCtl) Clean(ctx context.Context) {}
+
+func (c *FluxImageUpdateJobCtl) Run(ctx context.Context) {
+	c.job.Status = config.StatusRunning
+	c.ack()
+
+	result, err := gitops.UpdateFluxImages(&gitops.FluxImageUpdateParam{
+		CodehostID:   c.jobTaskSpec.CodehostID,
+		Owner:        c.jobTaskSpec.GetRepoNamespace(),
+		RepoName:     c.jobTaskSpec.RepoName,
+		Branch:       c.jobTaskSpec.Branch,
+		CreatePR:     c.jobTaskSpec.CreatePR,
+		WorkflowName: c.workflowCtx.WorkflowName,
+		TaskID:       c.workflowCtx.TaskID,
+		Targets:      c.jobTaskSpec.Targets,
+	})
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to update images in %s/%s: %v", c.jobTaskSpec.GetRepoNamespace(), c.jobTaskSpec.RepoName, err), c.logger)
+		return
+	}
+	if result.CommitID == "" {
+		c.logger.Infof("images in %s/%s are up to date, nothing is committed", c.jobTaskSpec.GetRepoNamespace(), c.jobTaskSpec.RepoName)
+		c.job.Status = config.StatusPassed
+		return
+	}
+
+	c.jobTaskSpec.CommitBranch = result.Branch
+	c.jobTaskSpec.CommitID = result.CommitID
+	c.jobTaskSpec.PRURL = result.PRURL
+	c.ack()
+
+	// the change takes effect after the pull request is merged, flux picks it up in its interval then
+	if !c.jobTaskSpec.CreatePR && c.jobTaskSpec.Env != "" {
+		c.requestReconcile()
+	}
+	c.job.Status = config.StatusPassed
+}
+
+// requestReconcile asks flux to reconcile the resources of the env right away, failures are only logged since flux
+// reconciles them in its interval anyway.
+func (c *FluxImageUpdateJobCtl) requestReconcile() {
+	env, err := mongodb.NewProductColl().Find(&mongodb.ProductFindOptions{
+		Name:    c.workflowCtx.ProjectName,
+		EnvName: c.jobTaskSpec.Env,
+	})
+	if err != nil {
+		c.logger.Warnf("failed to find env %s to reconcile its flux resources, error: %v", c.jobTaskSpec.Env, err)
+		return
+	}
+	if len(env.FluxResources) == 0 {
+		return
+	}
+	kubeClient, err := kubeclient.GetKubeClient(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		c.logger.Warnf("failed to get kube client of cluster %s, error: %v", env.ClusterID, err)
+		return
+	}
+	for _, resource := range env.FluxResources {
+		namespace := resource.Namespace
+		if namespace == "" {
+			namespace = env.Namespace
+		}
+		if err := flux.RequestReconcile(resource.Kind, namespace, resource.Name, kubeClient); err != nil {
+			c.logger.Warnf("failed to request reconcile of %s %s/%s, error: %v", resource.Kind, namespace, resource.Name, err)
+		}
+	}
+}
+
+func (c *FluxImageUpdateJobCtl) SaveInfo(ctx context.Context) error {
+	return mongodb.NewJobInfoColl().Create(context.TODO(), &commonmodels.JobInfo{
+		Type:                c.job.JobType,
+		WorkflowName:        c.workflowCtx.WorkflowName,
+		WorkflowDisplayName: c.workflowCtx.WorkflowDisplayName,
+		TaskID:              c.workflowCtx.TaskID,
+		ProductName:         c.workflowCtx.ProjectName,
+		StartTime:           c.job.StartTime,
+		EndTime:             c.job.EndTime,
+		Duration:            c.job.EndTime - c.job.StartTime,
+		Status:              string(c.job.Status),
+	})
+}
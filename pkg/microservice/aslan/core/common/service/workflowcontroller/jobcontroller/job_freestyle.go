@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -41,10 +42,13 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/workflowcontroller/stepcontroller"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/artifactrepo"
 	"github.com/koderover/zadig/v2/pkg/tool/dockerhost"
 	krkubeclient "github.com/koderover/zadig/v2/pkg/tool/kube/client"
 	"github.com/koderover/zadig/v2/pkg/tool/kube/informer"
 	"github.com/koderover/zadig/v2/pkg/tool/kube/updater"
+	"github.com/koderover/zadig/v2/pkg/tool/secrets"
+	commontypes "github.com/koderover/zadig/v2/pkg/types"
 	"github.com/koderover/zadig/v2/pkg/types/step"
 )
 
@@ -65,6 +69,8 @@ type FreestyleJobCtl struct {
 	paths       *string
 	jobTaskSpec *commonmodels.JobTaskFreestyleSpec
 	ack         func()
+	// warmPoolJob is the k8s job of the warm pool pod claimed by the job, empty if the job runs in a new pod
+	warmPoolJob string
 }
 
 func NewFreestyleJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *FreestyleJobCtl {
@@ -101,11 +107,13 @@ func (c *FreestyleJobCtl) Run(ctx context.Context) {
 		c.vmJobWait(ctx, vmJobID)
 		c.vmComplete(ctx, vmJobID)
 	} else {
+		cacheTracker := c.lookupBuildCache()
 		if err := c.run(ctx); err != nil {
 			return
 		}
 		c.wait(ctx)
 		c.complete(ctx)
+		c.recordBuildCacheUpload(cacheTracker)
 	}
 }
 
@@ -216,6 +224,11 @@ func (c *FreestyleJobCtl) run(ctx context.Context) error {
 	jobImage := getBaseImage(c.jobTaskSpec.Properties.BuildOS, c.jobTaskSpec.Properties.ImageFrom)
 
 	c.jobTaskSpec.Properties.Registries = getMatchedRegistries(jobImage, c.jobTaskSpec.Properties.Registries)
+
+	if c.claimWarmPoolPod(jobImage, jobLabel, string(jobCtxBytes)) {
+		return c.initInformer()
+	}
+
 	//Resource request default value is LOW
 	job, err := buildJob(c.job.JobType, jobImage, c.job.K8sJobName, c.jobTaskSpec.Properties.ClusterID, c.jobTaskSpec.Properties.Namespace, c.jobTaskSpec.Properties.ResourceRequest, c.jobTaskSpec.Properties.ResReqSpec, c.job, c.jobTaskSpec, c.workflowCtx, nil)
 	if err != nil {
@@ -244,7 +257,12 @@ func (c *FreestyleJobCtl) run(ctx context.Context) error {
 		return errors.New(msg)
 	}
 
-	// set informer when job and cm have been created
+	c.logger.Infof("succeed to create job %s", c.job.K8sJobName)
+	return c.initInformer()
+}
+
+// initInformer sets informer when job and cm have been created
+func (c *FreestyleJobCtl) initInformer() error {
 	clientSet, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), c.jobTaskSpec.Properties.ClusterID)
 	if err != nil {
 		return errors.Wrap(err, "get kube client set")
@@ -254,10 +272,41 @@ func (c *FreestyleJobCtl) run(ctx context.Context) error {
 		return errors.Wrap(err, "get informer")
 	}
 	c.informer = informer
-	c.logger.Infof("succeed to create job %s", c.job.K8sJobName)
 	return nil
 }
 
+// claimWarmPoolPod runs the job in an idle pod of the warm pool of the cluster if the job is able to,
+// false is returned if the job should be run in a new pod.
+func (c *FreestyleJobCtl) claimWarmPoolPod(jobImage string, jobLabel *JobLabel, jobCtx string) bool {
+	properties := c.jobTaskSpec.Properties
+	// the warm pool pods are scheduled with the default strategy and mount no extra volumes
+	if properties.StrategyID != "" || properties.UseHostDockerDaemon || len(properties.ShareStorageDetails) > 0 ||
+		(properties.CacheEnable && properties.Cache.MediumType == commontypes.NFSMedium) {
+		return false
+	}
+	cluster, err := mongodb.NewK8SClusterColl().FindByID(properties.ClusterID)
+	if err != nil {
+		c.logger.Warnf("failed to find cluster %s: %s", properties.ClusterID, err)
+		return false
+	}
+	if !cluster.WarmPool.Matches(jobImage, properties.ResourceRequest, properties.ResReqSpec) {
+		return false
+	}
+
+	c.warmPoolJob = claimWarmPoolPod(&warmPoolClaimArgs{
+		ClusterID:        properties.ClusterID,
+		Namespace:        properties.Namespace,
+		Image:            jobImage,
+		JobLabel:         jobLabel,
+		JobCtx:           jobCtx,
+		DockerHost:       properties.DockerHost,
+		BreakpointBefore: c.job.BreakpointBefore,
+		BreakpointAfter:  c.job.BreakpointAfter,
+		Timeout:          properties.Timeout,
+	}, c.kubeclient, c.clientset, c.restConfig, c.logger)
+	return c.warmPoolJob != ""
+}
+
 func (c *FreestyleJobCtl) runVMJob(ctx context.Context) (string, error) {
 	jobCtxBytes, err := yaml.Marshal(BuildJobExcutorContext(c.jobTaskSpec, c.job, c.workflowCtx, c.logger))
 	if err != nil {
@@ -296,16 +345,23 @@ func (c *FreestyleJobCtl) runVMJob(ctx context.Context) (string, error) {
 func (c *FreestyleJobCtl) wait(ctx context.Context) {
 	var err error
 	taskTimeout := time.After(time.Duration(c.jobTaskSpec.Properties.Timeout) * time.Minute)
-	c.job.Status, err = waitJobStart(ctx, c.jobTaskSpec.Properties.Namespace, c.job.K8sJobName, c.kubeclient, c.apiServer, taskTimeout, c.logger)
-	if err != nil {
-		c.job.Error = err.Error()
+	k8sJobName := c.job.K8sJobName
+	if c.warmPoolJob != "" {
+		// the claimed pod is running already
+		k8sJobName = c.warmPoolJob
+		c.job.Status = config.StatusRunning
+	} else {
+		c.job.Status, err = waitJobStart(ctx, c.jobTaskSpec.Properties.Namespace, c.job.K8sJobName, c.kubeclient, c.apiServer, taskTimeout, c.logger)
+		if err != nil {
+			c.job.Error = err.Error()
+		}
 	}
 	if c.job.Status == config.StatusRunning {
 		c.ack()
 	} else {
 		return
 	}
-	c.job.Status, c.job.Error = waitJobEndByCheckingConfigMap(ctx, taskTimeout, c.jobTaskSpec.Properties.Namespace, c.job.K8sJobName, true, c.kubeclient, c.clientset, c.restConfig, c.informer, c.job, c.ack, c.logger)
+	c.job.Status, c.job.Error = waitJobEndByCheckingConfigMap(ctx, taskTimeout, c.jobTaskSpec.Properties.Namespace, k8sJobName, true, c.kubeclient, c.clientset, c.restConfig, c.informer, c.job, c.ack, c.logger)
 }
 
 func (c *FreestyleJobCtl) vmJobWait(ctx context.Context, jobID string) {
@@ -420,7 +476,11 @@ func BuildJobExcutorContext(jobTaskSpec *commonmodels.JobTaskFreestyleSpec, job
 	var envVars, secretEnvVars []string
 	for _, env := range jobTaskSpec.Properties.Envs {
 		if env.IsCredential {
-			secretEnvVars = append(secretEnvVars, strings.Join([]string{env.Key, env.Value}, "="))
+			value, err := secrets.Unseal(env.Value)
+			if err != nil {
+				logger.Errorf("failed to unseal the secret of variable %s: %s", env.Key, err)
+			}
+			secretEnvVars = append(secretEnvVars, strings.Join([]string{env.Key, value}, "="))
 			continue
 		}
 		envVars = append(envVars, strings.Join([]string{env.Key, env.Value}, "="))
@@ -527,6 +587,41 @@ func (c *FreestyleJobCtl) SaveInfo(ctx context.Context) error {
 				break
 			}
 		}
+
+		// record the packages pushed to the artifact repositories
+		for _, stepTask := range c.jobTaskSpec.Steps {
+			if stepTask.StepType != config.StepArtifactUpload {
+				continue
+			}
+			uploadSpec := &step.StepArtifactUploadSpec{}
+			if err := commonmodels.IToiYaml(stepTask.Spec, uploadSpec); err != nil {
+				return fmt.Errorf("unmarshal artifact upload spec error: %v", err)
+			}
+			artifactPath := path.Join(uploadSpec.DestDir, uploadSpec.FileName)
+			artifactURL := ""
+			if client, err := artifactrepo.NewClient(uploadSpec.Type, uploadSpec.Address, uploadSpec.Repository, "", "", ""); err == nil {
+				artifactURL = client.FileURL(artifactPath)
+			}
+			err := mongodb.NewBuildArtifactColl().Create(context.TODO(), &commonmodels.BuildArtifact{
+				RepositoryID:  uploadSpec.RepositoryID,
+				ProjectName:   c.workflowCtx.ProjectName,
+				ServiceName:   uploadSpec.ServiceName,
+				ServiceModule: uploadSpec.ServiceModule,
+				Version:       uploadSpec.Version,
+				FileName:      uploadSpec.FileName,
+				Path:          artifactPath,
+				URL:           artifactURL,
+				WorkflowName:  c.workflowCtx.WorkflowName,
+				TaskID:        c.workflowCtx.TaskID,
+				JobName:       c.job.Name,
+				Repos:         uploadSpec.Repos,
+				CreatedBy:     c.workflowCtx.WorkflowTaskCreatorUsername,
+				CreateTime:    time.Now().Unix(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save build artifact %s, err: %v", artifactPath, err)
+			}
+		}
 	}
 
 	jobInfo := &commonmodels.JobInfo{
@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/harbor"
+	"github.com/koderover/zadig/v2/pkg/types/job"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+)
+
+var invalidPolicyNameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// HarborReplicationJobCtl distributes the images by the replication of the source harbor instead of pulling and
+// pushing them in the job pod, which is much faster for the large images.
+type HarborReplicationJobCtl struct {
+	job         *commonmodels.JobTask
+	workflowCtx *commonmodels.WorkflowTaskCtx
+	logger      *zap.SugaredLogger
+	jobTaskSpec *commonmodels.JobTaskFreestyleSpec
+	stepSpec    *step.StepImageDistributeSpec
+	ack         func()
+}
+
+// isHarborReplicationJob checks whether the distribute image job uses the harbor replication.
+func isHarborReplicationJob(job *commonmodels.JobTask) bool {
+	jobTaskSpec := &commonmodels.JobTaskFreestyleSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		return false
+	}
+	for _, stepTask := range jobTaskSpec.Steps {
+		if stepTask.StepType != config.StepDistributeImage {
+			continue
+		}
+		stepSpec := &step.StepImageDistributeSpec{}
+		if err := commonmodels.IToi(stepTask.Spec, stepSpec); err != nil {
+			return false
+		}
+		return stepSpec.Method == step.DistributeMethodHarborReplication
+	}
+	return false
+}
+
+func NewHarborReplicationJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *HarborReplicationJobCtl {
+	jobTaskSpec := &commonmodels.JobTaskFreestyleSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		logger.Error(err)
+	}
+	stepSpec := &step.StepImageDistributeSpec{}
+	for _, stepTask := range jobTaskSpec.Steps {
+		if stepTask.StepType != config.StepDistributeImage {
+			continue
+		}
+		if err := commonmodels.IToi(stepTask.Spec, stepSpec); err != nil {
+			logger.Error(err)
+		}
+		stepTask.Spec = stepSpec
+	}
+	job.Spec = jobTaskSpec
+	return &HarborReplicationJobCtl{
+		job:         job,
+		workflowCtx: workflowCtx,
+		logger:      logger,
+		ack:         ack,
+		jobTaskSpec: jobTaskSpec,
+		stepSpec:    stepSpec,
+	}
+}
+
+func (c *HarborReplicationJobCtl) Clean(ctx context.Context) {}
+
+func (c *HarborReplicationJobCtl) Run(ctx context.Context) {
+	c.job.Status = config.StatusRunning
+	c.ack()
+
+	if c.stepSpec.SourceRegistry == nil || c.stepSpec.TargetRegistry == nil {
+		logError(c.job, "image registry infos are missing", c.logger)
+		return
+	}
+	source := c.stepSpec.SourceRegistry
+	client := harbor.NewClient(source.RegAddr, source.AccessKey, source.SecretKey, source.TLSEnabled, source.TLSCert)
+
+	destRegistry, err := client.GetRegistryByName(c.stepSpec.ReplicationRegistryName)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to get the replication registry %s, error: %v", c.stepSpec.ReplicationRegistryName, err), c.logger)
+		return
+	}
+
+	for _, target := range c.stepSpec.DistributeTarget {
+		if target.SourceImage == "" {
+			logError(c.job, fmt.Sprintf("source image of %s/%s is empty", target.ServiceName, target.ServiceModule), c.logger)
+			return
+		}
+		target.SetTargetImage(c.stepSpec.TargetRegistry)
+
+		if err := c.startReplication(client, destRegistry, target); err != nil {
+			target.ReplicationStatus = harbor.ExecutionStatusFailed
+			target.ReplicationStatusText = err.Error()
+			logError(c.job, fmt.Sprintf("failed to replicate image %s, error: %v", target.SourceImage, err), c.logger)
+			return
+		}
+	}
+	c.ack()
+
+	timeout := time.After(time.Duration(c.job.Timeout) * time.Minute)
+	if c.job.Timeout <= 0 {
+		timeout = time.After(60 * time.Minute)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			c.job.Status = config.StatusCancelled
+			return
+		case <-timeout:
+			c.job.Status = config.StatusTimeout
+			c.job.Error = "harbor replication timeout"
+			return
+		default:
+			time.Sleep(3 * time.Second)
+		}
+
+		finished, failed := true, false
+		for _, target := range c.stepSpec.DistributeTarget {
+			if target.ReplicationStatus != harbor.ExecutionStatusInProgress {
+				failed = failed || target.ReplicationStatus != harbor.ExecutionStatusSucceed
+				continue
+			}
+			execution, err := client.GetReplicationExecution(target.ReplicationExecutionID)
+			if err != nil {
+				c.logger.Warnf("failed to get harbor replication execution %d, error: %v", target.ReplicationExecutionID, err)
+				finished = false
+				continue
+			}
+			target.ReplicationStatus = execution.Status
+			target.ReplicationStatusText = execution.StatusText
+			switch execution.Status {
+			case harbor.ExecutionStatusSucceed:
+				// the execution succeeds without any task if no artifact matches the filters
+				if execution.Total == 0 {
+					target.ReplicationStatus = harbor.ExecutionStatusFailed
+					target.ReplicationStatusText = fmt.Sprintf("image %s is not found in harbor", target.SourceImage)
+					failed = true
+				}
+			case harbor.ExecutionStatusFailed, harbor.ExecutionStatusStopped:
+				failed = true
+			default:
+				finished = false
+			}
+		}
+		c.ack()
+
+		if finished {
+			if failed {
+				c.job.Status = config.StatusFailed
+				c.job.Error = "some images failed to be replicated"
+				return
+			}
+			for _, target := range c.stepSpec.DistributeTarget {
+				targetKey := strings.Join([]string{c.job.Name, target.ServiceName, target.ServiceModule}, ".")
+				c.workflowCtx.GlobalContextSet(job.GetJobOutputKey(targetKey, "IMAGE"), target.TargetImage)
+			}
+			c.job.Status = config.StatusPassed
+			return
+		}
+	}
+}
+
+// startReplication creates or updates the replication policy of the target and triggers it,
+// the images are pushed by the source harbor to the target registry keeping their tags.
+func (c *HarborReplicationJobCtl) startReplication(client *harbor.Client, destRegistry *harbor.Registry, target *step.DistributeTaskTarget) error {
+	repository, tag, err := harbor.RepositoryAndTag(target.SourceImage)
+	if err != nil {
+		return err
+	}
+	_, targetTag, err := harbor.RepositoryAndTag(target.TargetImage)
+	if err != nil {
+		return err
+	}
+	if targetTag != tag {
+		return fmt.Errorf("harbor replication can't change the image tag from %s to %s", tag, targetTag)
+	}
+
+	name := invalidPolicyNameChars.ReplaceAllString(strings.ToLower(
+		fmt.Sprintf("zadig-%s-%s-%s-%s", c.workflowCtx.WorkflowName, c.job.Name, target.ServiceName, target.ServiceModule)), "-")
+	policy := &harbor.ReplicationPolicy{
+		Name:         name,
+		Description:  fmt.Sprintf("created by zadig workflow %s job %s", c.workflowCtx.WorkflowName, c.job.Name),
+		DestRegistry: destRegistry,
+		// the image is replicated to <target namespace>/<image name> the same as the image distributed by the job pod
+		DestNamespace:             c.stepSpec.TargetRegistry.Namespace,
+		DestNamespaceReplaceCount: harbor.FlattenAll,
+		Filters: []*harbor.ReplicationFilter{
+			{Type: harbor.FilterTypeName, Value: repository},
+			{Type: harbor.FilterTypeTag, Value: tag},
+		},
+		Trigger:  &harbor.ReplicationTrigger{Type: harbor.TriggerTypeManual},
+		Override: true,
+		Enabled:  true,
+	}
+
+	existed, err := client.GetReplicationPolicyByName(name)
+	if err != nil {
+		return fmt.Errorf("failed to get replication policy %s: %s", name, err)
+	}
+	if existed == nil {
+		policy.ID, err = client.CreateReplicationPolicy(policy)
+		if err != nil {
+			return fmt.Errorf("failed to create replication policy %s: %s", name, err)
+		}
+	} else {
+		policy.ID = existed.ID
+		if err := client.UpdateReplicationPolicy(policy.ID, policy); err != nil {
+			return fmt.Errorf("failed to update replication policy %s: %s", name, err)
+		}
+	}
+	target.ReplicationPolicyID = policy.ID
+
+	target.ReplicationExecutionID, err = client.StartReplication(policy.ID)
+	if err != nil {
+		return fmt.Errorf("failed to start replication policy %s: %s", name, err)
+	}
+	target.ReplicationStatus = harbor.ExecutionStatusInProgress
+	c.logger.Infof("harbor replication execution %d of policy %s started for image %s", target.ReplicationExecutionID, name, target.SourceImage)
+	return nil
+}
+
+func (c *HarborReplicationJobCtl) SaveInfo(ctx context.Context) error {
+	return mongodb.NewJobInfoColl().Create(context.TODO(), &commonmodels.JobInfo{
+		Type:                c.job.JobType,
+		WorkflowName:        c.workflowCtx.WorkflowName,
+		WorkflowDisplayName: c.workflowCtx.WorkflowDisplayName,
+		TaskID:              c.workflowCtx.TaskID,
+		ProductName:         c.workflowCtx.ProjectName,
+		StartTime:           c.job.StartTime,
+		EndTime:             c.job.EndTime,
+		Duration:            c.job.EndTime - c.job.StartTime,
+		Status:              string(c.job.Status),
+	})
+}
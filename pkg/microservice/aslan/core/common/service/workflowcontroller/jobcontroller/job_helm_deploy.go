@@ -31,6 +31,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/gitops"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
 	"github.com/koderover/zadig/v2/pkg/setting"
@@ -70,6 +71,36 @@ func (c *HelmDeployJobCtl) Run(ctx context.Context) {
 	c.job.Status = config.StatusRunning
 	c.ack()
 
+	approval, err := newDeployWindowApproval(c.workflowCtx.ProjectName, c.jobTaskSpec.ServiceName, c.jobTaskSpec.IsProduction)
+	if err != nil {
+		logError(c.job, err.Error(), c.logger)
+		return
+	}
+	if approval != nil {
+		c.jobTaskSpec.DeployWindowApproval = approval
+		if !waitForDeployWindowApproval(ctx, c.job, c.workflowCtx, approval, c.ack) {
+			return
+		}
+	}
+
+	if c.jobTaskSpec.ContractCheck != nil {
+		images := make([]string, 0, len(c.jobTaskSpec.ImageAndModules))
+		for _, svc := range c.jobTaskSpec.ImageAndModules {
+			images = append(images, svc.Image)
+		}
+		result, ok := checkContractCompatibility(c.job, c.jobTaskSpec.ContractCheck, &contractCheckTarget{
+			projectName: c.workflowCtx.ProjectName,
+			envName:     c.jobTaskSpec.Env,
+			production:  c.jobTaskSpec.IsProduction,
+			serviceName: c.jobTaskSpec.ServiceName,
+			images:      images,
+		}, c.logger)
+		c.jobTaskSpec.ContractCheckResult = result
+		if !ok {
+			return
+		}
+	}
+
 	// set IMAGE job output
 	for _, svc := range c.jobTaskSpec.ImageAndModules {
 		// helm deploy job key is jobName.serviceName
@@ -196,6 +227,11 @@ func (c *HelmDeployJobCtl) Run(ctx context.Context) {
 	c.logger.Infof("start helm deploy, productName %s serviceName %s namespace %s, images %v variableYaml %s overrideValues: %s updateServiceRevision %v",
 		c.workflowCtx.ProjectName, c.jobTaskSpec.ServiceName, c.namespace, images, variableYaml, chartInfo.OverrideValues, updateServiceRevision)
 
+	if c.jobTaskSpec.GitOpsExport != nil {
+		c.exportToGit(productInfo, productService, svcTemplate, param.Images)
+		return
+	}
+
 	timeOut := c.timeout()
 
 	done := make(chan bool)
@@ -230,6 +266,32 @@ func (c *HelmDeployJobCtl) Run(ctx context.Context) {
 	c.job.Status = config.StatusPassed
 }
 
+// exportToGit commits the rendered manifests of the release to the gitops repository instead of upgrading it
+func (c *HelmDeployJobCtl) exportToGit(productInfo *commonmodels.Product, productService *commonmodels.ProductService, svcTemplate *commonmodels.Service, images []string) {
+	manifest, err := kube.TemplateHelmRelease(productInfo, productService, svcTemplate, images)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to render helm chart %s/%s: %v", c.namespace, c.jobTaskSpec.ServiceName, err), c.logger)
+		return
+	}
+
+	result, err := gitops.Export(&gitops.ExportParam{
+		Config:       c.jobTaskSpec.GitOpsExport,
+		Production:   productInfo.Production,
+		ProjectName:  productInfo.ProductName,
+		EnvName:      productInfo.EnvName,
+		ServiceName:  c.jobTaskSpec.ServiceName,
+		WorkflowName: c.workflowCtx.WorkflowName,
+		TaskID:       c.workflowCtx.TaskID,
+		Manifest:     manifest,
+	})
+	if err != nil {
+		logError(c.job, fmt.Sprintf("export helm manifests error: %v", err), c.logger)
+		return
+	}
+	c.jobTaskSpec.GitOpsExportResult = result
+	c.job.Status = config.StatusPassed
+}
+
 func (c *HelmDeployJobCtl) timeout() int {
 	if c.jobTaskSpec.Timeout == 0 {
 		c.jobTaskSpec.Timeout = setting.DeployTimeout
@@ -0,0 +1,357 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	crClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	zadigconfig "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	krkubeclient "github.com/koderover/zadig/v2/pkg/tool/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/updater"
+	"github.com/koderover/zadig/v2/pkg/types/job"
+)
+
+const (
+	defaultTrivyImage = "aquasec/trivy:latest"
+	// the debug image of grype contains busybox, the script can not run in the default distroless image.
+	defaultGrypeImage = "anchore/grype:debug"
+
+	imageScanSeverityFile = "/tmp/zadig-image-scan-severities"
+)
+
+type ImageScanJobCtl struct {
+	job         *commonmodels.JobTask
+	workflowCtx *commonmodels.WorkflowTaskCtx
+	logger      *zap.SugaredLogger
+	kubeclient  crClient.Client
+	clientset   kubernetes.Interface
+	restConfig  *rest.Config
+	apiServer   crClient.Reader
+	jobTaskSpec *commonmodels.JobTaskImageScanSpec
+	pluginSpec  *commonmodels.JobTaskPluginSpec
+	ack         func()
+}
+
+func NewImageScanJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *ImageScanJobCtl {
+	jobTaskSpec := &commonmodels.JobTaskImageScanSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		logger.Error(err)
+	}
+	job.Spec = jobTaskSpec
+	return &ImageScanJobCtl{
+		job:         job,
+		workflowCtx: workflowCtx,
+		logger:      logger,
+		ack:         ack,
+		jobTaskSpec: jobTaskSpec,
+	}
+}
+
+func (c *ImageScanJobCtl) prepare(ctx context.Context) {
+	// set default timeout
+	if c.jobTaskSpec.Properties.Timeout <= 0 {
+		c.jobTaskSpec.Properties.Timeout = 30
+	}
+	// set default resource
+	if c.jobTaskSpec.Properties.ResourceRequest == setting.Request("") {
+		c.jobTaskSpec.Properties.ResourceRequest = setting.MinRequest
+	}
+	// set default resource
+	if c.jobTaskSpec.Properties.ClusterID == "" {
+		c.jobTaskSpec.Properties.ClusterID = setting.LocalClusterID
+	}
+}
+
+func (c *ImageScanJobCtl) Clean(ctx context.Context) {}
+
+func (c *ImageScanJobCtl) Run(ctx context.Context) {
+	c.prepare(ctx)
+	if err := c.run(ctx); err != nil {
+		return
+	}
+	c.wait(ctx)
+	c.complete(ctx)
+}
+
+func (c *ImageScanJobCtl) run(ctx context.Context) error {
+	// the image referred from other jobs is rendered before the job runs, it's still a variable if the referred job
+	// didn't output it, e.g. the service was skipped by the build job.
+	if c.jobTaskSpec.Image == "" || strings.Contains(c.jobTaskSpec.Image, "{{.") {
+		msg := fmt.Sprintf("image of %s/%s is not available", c.jobTaskSpec.ServiceName, c.jobTaskSpec.ServiceModule)
+		logError(c.job, msg, c.logger)
+		return errors.New(msg)
+	}
+
+	// get kube client
+	hubServerAddr := config.HubServerAddress()
+	switch c.jobTaskSpec.Properties.ClusterID {
+	case setting.LocalClusterID:
+		c.jobTaskSpec.Properties.Namespace = zadigconfig.Namespace()
+		c.kubeclient = krkubeclient.Client()
+		c.clientset = krkubeclient.Clientset()
+		c.restConfig = krkubeclient.RESTConfig()
+		c.apiServer = krkubeclient.APIReader()
+	default:
+		c.jobTaskSpec.Properties.Namespace = setting.AttachedClusterNamespace
+
+		crClient, clientset, restConfig, apiServer, err := GetK8sClients(hubServerAddr, c.jobTaskSpec.Properties.ClusterID)
+		if err != nil {
+			logError(c.job, err.Error(), c.logger)
+			return err
+		}
+		c.kubeclient = crClient
+		c.clientset = clientset
+		c.restConfig = restConfig
+		c.apiServer = apiServer
+	}
+
+	jobLabel := &JobLabel{
+		JobType: string(c.job.JobType),
+		JobName: c.job.K8sJobName,
+	}
+
+	plugin, err := buildImageScanPlugin(c.jobTaskSpec)
+	if err != nil {
+		logError(c.job, err.Error(), c.logger)
+		return err
+	}
+	c.pluginSpec = &commonmodels.JobTaskPluginSpec{
+		Properties: c.jobTaskSpec.Properties,
+		Plugin:     plugin,
+	}
+	c.pluginSpec.Properties.Registries = getMatchedRegistries(plugin.Image, c.jobTaskSpec.Properties.Registries)
+	job, err := buildPlainJob(c.job.K8sJobName, c.pluginSpec.Properties.ResourceRequest, c.pluginSpec.Properties.ResReqSpec, c.job, c.pluginSpec, c.workflowCtx)
+	if err != nil {
+		msg := fmt.Sprintf("create job context error: %v", err)
+		logError(c.job, msg, c.logger)
+		return err
+	}
+
+	job.Namespace = c.jobTaskSpec.Properties.Namespace
+
+	if err := ensureDeleteJob(c.jobTaskSpec.Properties.Namespace, jobLabel, c.kubeclient); err != nil {
+		msg := fmt.Sprintf("delete job error: %v", err)
+		logError(c.job, msg, c.logger)
+		return err
+	}
+
+	if err := createOrUpdateRegistrySecrets(c.jobTaskSpec.Properties.Namespace, c.pluginSpec.Properties.Registries, c.kubeclient); err != nil {
+		msg := fmt.Sprintf("create secret error: %v", err)
+		logError(c.job, msg, c.logger)
+		return errors.New(msg)
+	}
+
+	if err := updater.CreateJob(job, c.kubeclient); err != nil {
+		msg := fmt.Sprintf("create job error: %v", err)
+		logError(c.job, msg, c.logger)
+		return err
+	}
+	c.logger.Infof("succeed to create job %s", c.job.K8sJobName)
+	return nil
+}
+
+func (c *ImageScanJobCtl) wait(ctx context.Context) {
+	var err error
+	timeout := time.After(time.Duration(c.jobTaskSpec.Properties.Timeout) * time.Minute)
+	c.job.Status, err = waitJobStart(ctx, c.jobTaskSpec.Properties.Namespace, c.job.K8sJobName, c.kubeclient, c.apiServer, timeout, c.logger)
+	if err != nil {
+		c.logger.Errorf("wait job start error: %v", err)
+	}
+	if c.job.Status == config.StatusRunning {
+		c.ack()
+	} else {
+		return
+	}
+	status := waitPlainJobEnd(ctx, int(c.jobTaskSpec.Properties.Timeout), timeout, c.jobTaskSpec.Properties.Namespace, c.job.K8sJobName, c.kubeclient, c.logger)
+	c.job.Status = status
+}
+
+func (c *ImageScanJobCtl) complete(ctx context.Context) {
+	jobLabel := &JobLabel{
+		JobType: string(c.job.JobType),
+		JobName: c.job.K8sJobName,
+	}
+
+	// 清理用户取消和超时的任务
+	defer func() {
+		go func() {
+			if err := ensureDeleteJob(c.jobTaskSpec.Properties.Namespace, jobLabel, c.kubeclient); err != nil {
+				c.logger.Error(err)
+			}
+		}()
+	}()
+
+	if err := saveContainerLog(c.jobTaskSpec.Properties.Namespace, c.jobTaskSpec.Properties.ClusterID, c.workflowCtx.WorkflowName, c.job.Name, c.workflowCtx.TaskID, jobLabel, c.kubeclient); err != nil {
+		c.logger.Error(err)
+		c.job.Error = err.Error()
+	}
+
+	if c.job.Status != config.StatusPassed {
+		return
+	}
+
+	outputs, err := listJobOutputsFromTerminalMsg(c.jobTaskSpec.Properties.Namespace, GetJobContainerName(c.job.Name), c.job, c.kubeclient)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to get the scan result: %s", err), c.logger)
+		return
+	}
+	writeOutputs(outputs, c.job.Key, c.workflowCtx)
+
+	c.jobTaskSpec.Vulnerabilities = make(map[string]int)
+	for _, output := range outputs {
+		count, err := strconv.Atoi(output.Value)
+		if err != nil {
+			logError(c.job, fmt.Sprintf("invalid count %q of severity %s", output.Value, output.Name), c.logger)
+			return
+		}
+		c.jobTaskSpec.Vulnerabilities[output.Name] = count
+	}
+
+	exceeded := make([]string, 0)
+	for _, threshold := range c.jobTaskSpec.Thresholds {
+		if count := c.jobTaskSpec.Vulnerabilities[threshold.Severity]; count > threshold.Max {
+			exceeded = append(exceeded, fmt.Sprintf("%s: %d > %d", threshold.Severity, count, threshold.Max))
+		}
+	}
+
+	if err := commonrepo.NewImageScanResultColl().Create(&commonmodels.ImageScanResult{
+		ProjectName:     c.workflowCtx.ProjectName,
+		WorkflowName:    c.workflowCtx.WorkflowName,
+		TaskID:          c.workflowCtx.TaskID,
+		JobName:         c.job.Name,
+		ServiceName:     c.jobTaskSpec.ServiceName,
+		ServiceModule:   c.jobTaskSpec.ServiceModule,
+		Image:           c.jobTaskSpec.Image,
+		Scanner:         string(c.jobTaskSpec.Scanner),
+		Vulnerabilities: c.jobTaskSpec.Vulnerabilities,
+		Passed:          len(exceeded) == 0,
+	}); err != nil {
+		c.logger.Errorf("failed to save the scan result of image %s: %s", c.jobTaskSpec.Image, err)
+	}
+
+	if len(exceeded) > 0 {
+		logError(c.job, fmt.Sprintf("vulnerabilities of image %s exceed the thresholds, %s", c.jobTaskSpec.Image, strings.Join(exceeded, ", ")), c.logger)
+	}
+}
+
+func (c *ImageScanJobCtl) SaveInfo(ctx context.Context) error {
+	return commonrepo.NewJobInfoColl().Create(context.TODO(), &commonmodels.JobInfo{
+		Type:                c.job.JobType,
+		WorkflowName:        c.workflowCtx.WorkflowName,
+		WorkflowDisplayName: c.workflowCtx.WorkflowDisplayName,
+		TaskID:              c.workflowCtx.TaskID,
+		ProductName:         c.workflowCtx.ProjectName,
+		StartTime:           c.job.StartTime,
+		EndTime:             c.job.EndTime,
+		Duration:            c.job.EndTime - c.job.StartTime,
+		Status:              string(c.job.Status),
+	})
+}
+
+// buildImageScanPlugin generates the container running the scanner, the scanner prints a severity per line for each
+// vulnerability, then the count of each severity is written to the termination message as the job outputs.
+func buildImageScanPlugin(spec *commonmodels.JobTaskImageScanSpec) (*commonmodels.PluginTemplate, error) {
+	plugin := &commonmodels.PluginTemplate{
+		Image: spec.ScannerImage,
+		Envs:  make([]*commonmodels.Env, 0),
+	}
+
+	var registry *commonmodels.RegistryNamespace
+	if registries := getMatchedRegistries(spec.Image, spec.Properties.Registries); len(registries) > 0 {
+		registry = registries[0]
+	}
+	insecure := registry != nil && (registry.AdvancedSetting == nil || !registry.AdvancedSetting.TLSEnabled)
+
+	var shell, scanCommand string
+	switch spec.Scanner {
+	case config.ImageScannerTrivy:
+		if plugin.Image == "" {
+			plugin.Image = defaultTrivyImage
+		}
+		shell = "/bin/sh"
+		args := []string{"trivy", "image", "--quiet", "--format", "template",
+			"--template", `'{{range .}}{{range .Vulnerabilities}}{{.Severity}}{{"\n"}}{{end}}{{end}}'`,
+			"--output", imageScanSeverityFile}
+		if spec.IgnoreUnfixed {
+			args = append(args, "--ignore-unfixed")
+		}
+		if insecure {
+			args = append(args, "--insecure")
+		}
+		scanCommand = strings.Join(append(args, fmt.Sprintf("'%s'", spec.Image)), " ")
+		if registry != nil {
+			plugin.Envs = append(plugin.Envs,
+				&commonmodels.Env{Name: "TRIVY_USERNAME", Value: registry.AccessKey},
+				&commonmodels.Env{Name: "TRIVY_PASSWORD", Value: registry.SecretKey},
+			)
+		}
+	case config.ImageScannerGrype:
+		if plugin.Image == "" {
+			plugin.Image = defaultGrypeImage
+		}
+		shell = "/busybox/sh"
+		args := []string{"grype", fmt.Sprintf("'registry:%s'", spec.Image), "--quiet", "--output", "template", "--template", imageScanSeverityFile + ".tpl"}
+		if spec.IgnoreUnfixed {
+			args = append(args, "--only-fixed")
+		}
+		// grype reports the severities in title case and has an extra severity Negligible which is treated as LOW.
+		scanCommand = fmt.Sprintf(`echo '{{range .Matches}}{{.Vulnerability.Severity}}{{"\n"}}{{end}}' > %s.tpl && %s | tr 'a-z' 'A-Z' | sed 's/^NEGLIGIBLE$/LOW/' > %s`,
+			imageScanSeverityFile, strings.Join(args, " "), imageScanSeverityFile)
+		if registry != nil {
+			plugin.Envs = append(plugin.Envs,
+				&commonmodels.Env{Name: "GRYPE_REGISTRY_AUTH_AUTHORITY", Value: strings.TrimPrefix(strings.TrimPrefix(registry.RegAddr, "https://"), "http://")},
+				&commonmodels.Env{Name: "GRYPE_REGISTRY_AUTH_USERNAME", Value: registry.AccessKey},
+				&commonmodels.Env{Name: "GRYPE_REGISTRY_AUTH_PASSWORD", Value: registry.SecretKey},
+			)
+		}
+		if insecure {
+			plugin.Envs = append(plugin.Envs, &commonmodels.Env{Name: "GRYPE_REGISTRY_INSECURE_SKIP_TLS_VERIFY", Value: "true"})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported image scanner: %s", spec.Scanner)
+	}
+
+	outputs := make([]string, 0, len(config.ImageScanSeverities))
+	for _, severity := range config.ImageScanSeverities {
+		outputs = append(outputs, fmt.Sprintf(`{"name":"%s","value":"'$(grep -c '^%s$' %s || true)'"}`, severity, severity, imageScanSeverityFile))
+	}
+	script := strings.Join([]string{
+		"set -eo pipefail",
+		fmt.Sprintf("echo 'scanning image %s'", spec.Image),
+		scanCommand,
+		fmt.Sprintf("sort %s | uniq -c", imageScanSeverityFile),
+		fmt.Sprintf("echo '[%s]' > %s", strings.Join(outputs, ","), job.JobTerminationFile),
+	}, "\n")
+
+	plugin.Cmds = []string{shell, "-c"}
+	plugin.Args = []string{script}
+	return plugin, nil
+}
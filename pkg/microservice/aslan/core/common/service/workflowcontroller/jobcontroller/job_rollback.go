@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+)
+
+type ZadigRollbackJobCtl struct {
+	job         *commonmodels.JobTask
+	workflowCtx *commonmodels.WorkflowTaskCtx
+	logger      *zap.SugaredLogger
+	jobTaskSpec *commonmodels.JobTaskZadigRollbackSpec
+	ack         func()
+}
+
+func NewZadigRollbackJobCtl(job *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, ack func(), logger *zap.SugaredLogger) *ZadigRollbackJobCtl {
+	jobTaskSpec := &commonmodels.JobTaskZadigRollbackSpec{}
+	if err := commonmodels.IToi(job.Spec, jobTaskSpec); err != nil {
+		logger.Error(err)
+	}
+	job.Spec = jobTaskSpec
+	return &ZadigRollbackJobCtl{
+		job:         job,
+		workflowCtx: workflowCtx,
+		logger:      logger,
+		ack:         ack,
+		jobTaskSpec: jobTaskSpec,
+	}
+}
+
+func (c *ZadigRollbackJobCtl) Clean(ctx context.Context) {}
+
+func (c *ZadigRollbackJobCtl) Run(ctx context.Context) {
+	c.job.Status = config.StatusRunning
+	c.ack()
+
+	serviceNames := make([]string, 0, len(c.jobTaskSpec.Services))
+	for _, svc := range c.jobTaskSpec.Services {
+		serviceNames = append(serviceNames, svc.ServiceName)
+	}
+	args := &kube.EnvRollbackArgs{
+		ProjectName:  c.workflowCtx.ProjectName,
+		EnvName:      c.jobTaskSpec.Env,
+		Production:   c.jobTaskSpec.Production,
+		Source:       c.jobTaskSpec.Source,
+		WorkflowName: c.jobTaskSpec.WorkflowName,
+		TaskID:       c.jobTaskSpec.TaskID,
+		SnapshotID:   c.jobTaskSpec.SnapshotID,
+		Services:     serviceNames,
+	}
+	versions, err := kube.FindRollbackServiceVersions(args)
+	if err != nil {
+		logError(c.job, fmt.Sprintf("failed to find the versions to roll back to: %v", err), c.logger)
+		return
+	}
+
+	failed := false
+	for i, version := range versions {
+		if ctx.Err() != nil {
+			c.job.Status = config.StatusCancelled
+			return
+		}
+
+		target := c.jobTaskSpec.Services[i]
+		target.Status = config.StatusRunning
+		c.ack()
+
+		record := kube.NewEnvRollbackRecord(args, version, config.RollbackTriggerWorkflow, c.workflowCtx.WorkflowTaskCreatorUsername)
+		record.TriggerWorkflowName = c.workflowCtx.WorkflowName
+		record.TriggerTaskID = c.workflowCtx.TaskID
+		if err := kube.RollbackEnvService(version, record, kube.EnsureUpdateZadigService, c.logger); err != nil {
+			target.Status = config.StatusFailed
+			target.Error = err.Error()
+			failed = true
+			c.logger.Errorf("failed to roll back service %s in env %s, error: %v", target.ServiceName, c.jobTaskSpec.Env, err)
+		} else {
+			target.Status = config.StatusPassed
+			target.Revision = version.Revision
+			target.Images = kube.GetRollbackServiceImages(version)
+		}
+		c.ack()
+	}
+
+	if failed {
+		c.job.Status = config.StatusFailed
+		c.job.Error = "some services failed to roll back"
+		return
+	}
+	c.job.Status = config.StatusPassed
+}
+
+func (c *ZadigRollbackJobCtl) SaveInfo(ctx context.Context) error {
+	return mongodb.NewJobInfoColl().Create(context.TODO(), &commonmodels.JobInfo{
+		Type:                c.job.JobType,
+		WorkflowName:        c.workflowCtx.WorkflowName,
+		WorkflowDisplayName: c.workflowCtx.WorkflowDisplayName,
+		TaskID:              c.workflowCtx.TaskID,
+		ProductName:         c.workflowCtx.ProjectName,
+		StartTime:           c.job.StartTime,
+		EndTime:             c.job.EndTime,
+		Duration:            c.job.EndTime - c.job.StartTime,
+		Status:              string(c.job.Status),
+		TargetEnv:           c.jobTaskSpec.Env,
+		Production:          c.jobTaskSpec.Production,
+	})
+}
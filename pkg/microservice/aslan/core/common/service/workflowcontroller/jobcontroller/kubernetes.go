@@ -938,10 +938,11 @@ func waitJobEndByCheckingConfigMap(ctx context.Context, taskTimeout <-chan time.
 				xl.Errorf(errMsg)
 				return config.StatusFailed, errMsg
 			}
-			// configMap name is the same as the k8s job name
-			cm, err := cmLister.Get(jobName)
+			// configMap name is the same as the k8s job name of the job task, which differs from jobName
+			// when the job runs in a warm pool pod
+			cm, err := cmLister.Get(jobTask.K8sJobName)
 			if err != nil {
-				errMsg := fmt.Sprintf("failed to get job context configMap job-name=%s %v", jobName, err)
+				errMsg := fmt.Sprintf("failed to get job context configMap job-name=%s %v", jobTask.K8sJobName, err)
 				xl.Errorf(errMsg)
 				return config.StatusFailed, errMsg
 			}
@@ -999,6 +1000,15 @@ func waitJobEndByCheckingConfigMap(ctx context.Context, taskTimeout <-chan time.
 }
 
 func getJobOutputFromTerminalMsg(namespace, containerName string, jobTask *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, kubeClient crClient.Client) error {
+	outputs, err := listJobOutputsFromTerminalMsg(namespace, containerName, jobTask, kubeClient)
+	if err != nil {
+		return err
+	}
+	writeOutputs(outputs, jobTask.Key, workflowCtx)
+	return nil
+}
+
+func listJobOutputsFromTerminalMsg(namespace, containerName string, jobTask *commonmodels.JobTask, kubeClient crClient.Client) ([]*job.JobOutput, error) {
 	jobLabel := &JobLabel{
 		JobType: string(jobTask.JobType),
 		JobName: jobTask.K8sJobName,
@@ -1007,13 +1017,13 @@ func getJobOutputFromTerminalMsg(namespace, containerName string, jobTask *commo
 	ls := getJobLabels(jobLabel)
 	pods, err := getter.ListPods(namespace, labels.Set(ls).AsSelector(), kubeClient)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, pod := range pods {
 		ipod := wrapper.Pod(pod)
 		// only collect succeeed job outputs.
 		if !ipod.Succeeded() {
-			return nil
+			return outputs, nil
 		}
 		for _, containerStatus := range pod.Status.ContainerStatuses {
 			if containerStatus.Name != containerName {
@@ -1021,13 +1031,12 @@ func getJobOutputFromTerminalMsg(namespace, containerName string, jobTask *commo
 			}
 			if containerStatus.State.Terminated != nil && len(containerStatus.State.Terminated.Message) != 0 {
 				if err := json.Unmarshal([]byte(containerStatus.State.Terminated.Message), &outputs); err != nil {
-					return err
+					return nil, err
 				}
 			}
 		}
 	}
-	writeOutputs(outputs, jobTask.Key, workflowCtx)
-	return nil
+	return outputs, nil
 }
 
 func getJobOutputFromConfigMap(namespace, containerName string, jobTask *commonmodels.JobTask, workflowCtx *commonmodels.WorkflowTaskCtx, informer informers.SharedInformerFactory) error {
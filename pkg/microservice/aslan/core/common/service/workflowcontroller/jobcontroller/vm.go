@@ -10,8 +10,13 @@ import (
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
 )
 
+// vmAgentCheckInterval is the interval to check whether the agent running the job is still alive
+const vmAgentCheckInterval = 15 * time.Second
+
 func waitVMJobStart(ctx context.Context, jobID string, taskTimeout <-chan time.Time, jobTask *commonmodels.JobTask, logger *zap.SugaredLogger) (config.Status, error) {
 	logger.Infof("start to wait vm job %s job_id:%s start", jobTask.Name, jobID)
 	for {
@@ -36,6 +41,7 @@ func waitVMJobStart(ctx context.Context, jobID string, taskTimeout <-chan time.T
 
 func waitVMJobEndByCheckStatus(ctx context.Context, jobID string, taskTimeout <-chan time.Time, jobTask *commonmodels.JobTask, ack func(), logger *zap.SugaredLogger) (status config.Status, errMsg string) {
 	logger.Infof("start to wait vm job %s id:%s end", jobTask.Name, jobID)
+	lastAgentCheck := time.Now()
 	for {
 		select {
 		case <-ctx.Done():
@@ -65,6 +71,14 @@ func waitVMJobEndByCheckStatus(ctx context.Context, jobID string, taskTimeout <-
 			case string(config.StatusRunning):
 				jobTask.Status = config.StatusRunning
 				ack()
+
+				if time.Since(lastAgentCheck) > vmAgentCheckInterval {
+					lastAgentCheck = time.Now()
+					if offline, msg := isVMAgentOffline(vmJob.VMID, logger); offline {
+						jobTask.Status = config.StatusFailed
+						return config.StatusFailed, msg
+					}
+				}
 			case string(config.StatusPassed):
 				jobTask.Status = config.StatusPassed
 				return config.StatusPassed, ""
@@ -77,3 +91,21 @@ func waitVMJobEndByCheckStatus(ctx context.Context, jobID string, taskTimeout <-
 		}
 	}
 }
+
+// isVMAgentOffline checks the heartbeat status of the vm running the job, the status is maintained by the cron service,
+// so that the job fails instead of hanging until timeout when the agent is dead.
+func isVMAgentOffline(vmID string, logger *zap.SugaredLogger) (bool, string) {
+	if vmID == "" {
+		return false, ""
+	}
+	vm, err := commonrepo.NewPrivateKeyColl().Find(commonrepo.FindPrivateKeyOption{ID: vmID})
+	if err != nil {
+		// ignore the error here, the job is still checked next time
+		logger.Warnf("failed to find vm %s, error: %s", vmID, err)
+		return false, ""
+	}
+	if vm.Agent == nil || (vm.Status != setting.VMAbnormal && vm.Status != setting.VMOffline) {
+		return false, ""
+	}
+	return true, fmt.Sprintf("vm agent of %s is %s, last heartbeat time: %s", vm.Name, vm.Status, time.Unix(vm.Agent.LastHeartbeatTime, 0).Format("2006-01-02 15:04:05"))
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcontroller
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	crClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	zadigconfig "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	krkubeclient "github.com/koderover/zadig/v2/pkg/tool/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/podexec"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/updater"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/types/job"
+)
+
+const (
+	warmPoolLabelKey      = "zadig-warm-pool"
+	warmPoolImageLabelKey = "zadig-warm-pool-image"
+	warmPoolStatusIdle    = "idle"
+	warmPoolStatusClaimed = "claimed"
+	warmPoolContainerName = "warm-executor"
+	warmPoolJobConfigFile = ZadigContextDir + "job-config.xml"
+	// warmPoolClaimFile is written when the pod is claimed, it is sourced by the pod before the job executor starts
+	warmPoolClaimFile = ZadigContextDir + "claimed"
+	// idle pods are recycled periodically so that the updates of the images are picked up
+	warmPoolMaxIdleTime = 24 * time.Hour
+)
+
+// warmPoolImageLabel returns the label value of the image since an image is not a valid label value
+func warmPoolImageLabel(image string) string {
+	sum := sha1.Sum([]byte(image))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func getWarmPoolNamespace(clusterID string) string {
+	if clusterID == "" || clusterID == setting.LocalClusterID {
+		return zadigconfig.Namespace()
+	}
+	return setting.AttachedClusterNamespace
+}
+
+func getWarmPoolClients(clusterID string) (crClient.Client, kubernetes.Interface, *rest.Config, error) {
+	if clusterID == "" || clusterID == setting.LocalClusterID {
+		return krkubeclient.Client(), krkubeclient.Clientset(), krkubeclient.RESTConfig(), nil
+	}
+	kubeClient, clientset, restConfig, _, err := GetK8sClients(config.HubServerAddress(), clusterID)
+	return kubeClient, clientset, restConfig, err
+}
+
+func buildWarmPoolJob(cluster *commonmodels.K8SCluster, image, namespace string) (*batchv1.Job, error) {
+	imagePullSecrets, err := getImagePullSecrets(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jobLabels := map[string]string{
+		warmPoolLabelKey:      warmPoolStatusIdle,
+		warmPoolImageLabelKey: warmPoolImageLabel(image),
+	}
+	bootingScript := fmt.Sprintf("mkdir -p %sdebug; while [ ! -f %s ]; do sleep 1; done; . %s; %s",
+		ZadigContextDir, warmPoolClaimFile, warmPoolClaimFile, JobExecutorFile)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "zadig-warm-pool-",
+			Namespace:    namespace,
+			Labels:       jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			Completions:             int32Ptr(1),
+			Parallelism:             int32Ptr(1),
+			BackoffLimit:            int32Ptr(0),
+			TTLSecondsAfterFinished: int32Ptr(3600),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ImagePullSecrets:   imagePullSecrets,
+					ServiceAccountName: workflowConfigMapRoleSA,
+					InitContainers: []corev1.Container{
+						{
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Name:            "executor-resource-init",
+							Image:           config.ExecutorImage(),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      ExecutorResourceVolumeName,
+									MountPath: ExecutorVolumePath,
+								},
+							},
+							Command: []string{"/bin/sh", "-c", fmt.Sprintf("cp /app/* %s", ExecutorVolumePath)},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							ImagePullPolicy: corev1.PullAlways,
+							Name:            warmPoolContainerName,
+							Image:           image,
+							Command:         []string{"/bin/sh", "-c"},
+							Args:            []string{bootingScript},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "zadig-context",
+									MountPath: ZadigContextDir,
+								},
+								{
+									Name:      ExecutorResourceVolumeName,
+									MountPath: ExecutorVolumePath,
+								},
+							},
+							Resources: getResourceRequirements(cluster.WarmPool.ResourceRequest, cluster.WarmPool.ResReqSpec),
+
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							TerminationMessagePath:   job.JobTerminationFile,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name:         "zadig-context",
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						},
+						{
+							Name:         ExecutorResourceVolumeName,
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						},
+					},
+					Tolerations: buildTolerations(cluster.AdvancedConfig, ""),
+					Affinity:    addNodeAffinity(cluster.AdvancedConfig, ""),
+				},
+			},
+		},
+	}, nil
+}
+
+// ReconcileWarmPools keeps the configured number of idle pods for each image of the warm pools, and removes the idle pods
+// which are no longer needed or have been idle for too long. It is expected to be called periodically.
+func ReconcileWarmPools() {
+	logger := log.SugaredLogger().With("source", "warm pool")
+	clusters, err := commonrepo.NewK8SClusterColl().FindConnectedClusters()
+	if err != nil {
+		logger.Errorf("failed to list connected clusters: %s", err)
+		return
+	}
+
+	for _, cluster := range clusters {
+		if err := reconcileWarmPool(cluster, logger); err != nil {
+			logger.Errorf("failed to reconcile the warm pool of cluster %s: %s", cluster.Name, err)
+		}
+	}
+}
+
+func reconcileWarmPool(cluster *commonmodels.K8SCluster, logger *zap.SugaredLogger) error {
+	clusterID := cluster.ID.Hex()
+	namespace := getWarmPoolNamespace(clusterID)
+	kubeClient, _, _, err := getWarmPoolClients(clusterID)
+	if err != nil {
+		return err
+	}
+
+	idleJobs, err := getter.ListJobs(namespace, labels.Set{warmPoolLabelKey: warmPoolStatusIdle}.AsSelector(), kubeClient)
+	if err != nil {
+		return fmt.Errorf("failed to list idle warm pool jobs: %s", err)
+	}
+
+	images := make(map[string]string)
+	if cluster.WarmPool != nil && cluster.WarmPool.Enabled {
+		for _, image := range cluster.WarmPool.Images {
+			images[warmPoolImageLabel(image)] = image
+		}
+	}
+
+	idleCount := make(map[string]int)
+	for _, idleJob := range idleJobs {
+		imageLabel := idleJob.Labels[warmPoolImageLabelKey]
+		_, inUse := images[imageLabel]
+		expired := time.Since(idleJob.CreationTimestamp.Time) > warmPoolMaxIdleTime
+		if inUse && !expired && idleJob.Status.Failed == 0 {
+			idleCount[imageLabel]++
+			continue
+		}
+		logger.Infof("remove idle warm pool job %s/%s", namespace, idleJob.Name)
+		if err := updater.DeleteJobAndWait(namespace, idleJob.Name, kubeClient); err != nil {
+			logger.Errorf("failed to remove idle warm pool job %s/%s: %s", namespace, idleJob.Name, err)
+		}
+	}
+
+	for imageLabel, image := range images {
+		for i := idleCount[imageLabel]; i < cluster.WarmPool.Size; i++ {
+			warmJob, err := buildWarmPoolJob(cluster, image, namespace)
+			if err != nil {
+				return err
+			}
+			if err := updater.CreateJob(warmJob, kubeClient); err != nil {
+				return fmt.Errorf("failed to create warm pool job for image %s: %s", image, err)
+			}
+		}
+	}
+	return nil
+}
+
+type warmPoolClaimArgs struct {
+	ClusterID        string
+	Namespace        string
+	Image            string
+	JobLabel         *JobLabel
+	JobCtx           string
+	DockerHost       string
+	BreakpointBefore bool
+	BreakpointAfter  bool
+	// Timeout is the timeout of the job in minutes
+	Timeout int64
+}
+
+// claimWarmPoolPod claims an idle pod of the warm pool to run the job, the name of the k8s job owning the pod is returned,
+// empty name is returned if there is no idle pod available and the job should be run in a new pod.
+func claimWarmPoolPod(args *warmPoolClaimArgs, kubeClient crClient.Client, clientset kubernetes.Interface, restConfig *rest.Config, logger *zap.SugaredLogger) string {
+	pods, err := getter.ListPods(args.Namespace, labels.Set{
+		warmPoolLabelKey:      warmPoolStatusIdle,
+		warmPoolImageLabelKey: warmPoolImageLabel(args.Image),
+	}.AsSelector(), kubeClient)
+	if err != nil {
+		logger.Warnf("failed to list idle warm pool pods: %s", err)
+		return ""
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		warmJobName := pod.Labels["job-name"]
+		if warmJobName == "" {
+			continue
+		}
+
+		// the update fails with a conflict if the pod has been claimed by another job
+		claimed := pod.DeepCopy()
+		claimed.Labels[warmPoolLabelKey] = warmPoolStatusClaimed
+		for k, v := range getJobLabels(args.JobLabel) {
+			claimed.Labels[k] = v
+		}
+		if err := kubeClient.Update(context.TODO(), claimed); err != nil {
+			logger.Infof("failed to claim warm pool pod %s: %s", pod.Name, err)
+			continue
+		}
+
+		if err := startWarmPoolPod(args, claimed, warmJobName, kubeClient, clientset, restConfig); err != nil {
+			logger.Errorf("failed to start the job in warm pool pod %s, fall back to a new pod: %s", pod.Name, err)
+			if err := updater.DeleteJobAndWait(args.Namespace, warmJobName, kubeClient); err != nil {
+				logger.Errorf("failed to remove warm pool job %s: %s", warmJobName, err)
+			}
+			return ""
+		}
+
+		logger.Infof("job %s claimed warm pool pod %s", args.JobLabel.JobName, pod.Name)
+		return warmJobName
+	}
+	return ""
+}
+
+// startWarmPoolPod labels the k8s job of the claimed pod as the job's so that it is cleaned up with the job, then writes the
+// job context into the pod to start the job executor.
+func startWarmPoolPod(args *warmPoolClaimArgs, pod *corev1.Pod, warmJobName string, kubeClient crClient.Client, clientset kubernetes.Interface, restConfig *rest.Config) error {
+	warmJob, found, err := getter.GetJob(args.Namespace, warmJobName, kubeClient)
+	if err != nil || !found {
+		return fmt.Errorf("failed to get warm pool job %s: %v", warmJobName, err)
+	}
+	jobLabels := map[string]string{warmPoolLabelKey: warmPoolStatusClaimed}
+	for k, v := range getJobLabels(args.JobLabel) {
+		jobLabels[k] = v
+	}
+	// the deadline counts from the start of the warm job, so the idle time is added
+	idleSeconds := int64(time.Since(warmJob.CreationTimestamp.Time).Seconds())
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": jobLabels},
+		"spec":     map[string]interface{}{"activeDeadlineSeconds": idleSeconds + args.Timeout*60 + 3600},
+	})
+	if err != nil {
+		return err
+	}
+	if err := kubeClient.Patch(context.TODO(), warmJob, crClient.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed to patch warm pool job: %s", err)
+	}
+
+	if err := writeWarmPoolPodFile(args.Namespace, pod.Name, warmPoolJobConfigFile, args.JobCtx, clientset, restConfig); err != nil {
+		return fmt.Errorf("failed to write job context: %s", err)
+	}
+
+	claimScript := fmt.Sprintf("export %s=%q\n", setting.JobConfigFile, warmPoolJobConfigFile)
+	if args.DockerHost != "" {
+		claimScript += fmt.Sprintf("export %s=%q\n", setting.DockerHost, args.DockerHost)
+	}
+	if args.BreakpointBefore {
+		claimScript += fmt.Sprintf("touch %sdebug/breakpoint_before\n", ZadigContextDir)
+	}
+	if args.BreakpointAfter {
+		claimScript += fmt.Sprintf("touch %sdebug/breakpoint_after\n", ZadigContextDir)
+	}
+	return writeWarmPoolPodFile(args.Namespace, pod.Name, warmPoolClaimFile, claimScript, clientset, restConfig)
+}
+
+// writeWarmPoolPodFile writes the file atomically so that the pod never reads a partial file
+func writeWarmPoolPodFile(namespace, podName, filePath, content string, clientset kubernetes.Interface, restConfig *rest.Config) error {
+	// no retry since the stdin can only be read once
+	_, stderr, _, err := podexec.KubeExec(clientset, restConfig, podexec.ExecOptions{
+		Command:       []string{"/bin/sh", "-c", fmt.Sprintf("cat > %s.tmp && mv %s.tmp %s", filePath, filePath, filePath)},
+		Namespace:     namespace,
+		PodName:       podName,
+		ContainerName: warmPoolContainerName,
+		Stdin:         strings.NewReader(content),
+	})
+	if err != nil {
+		return fmt.Errorf("%s, stderr: %s", err, stderr)
+	}
+	return nil
+}
@@ -134,13 +134,15 @@ func InitQueue() error {
 	return nil
 }
 
+const workflowTaskSenderLockKey = "workflow-task-sender"
+
 // WorfklowTaskSender 监控warpdrive空闲情况, 如果有空闲, 则发现下一个waiting task给warpdrive
 // 并将task状态设置为queued
 func WorfklowTaskSender() {
 	for {
 		time.Sleep(time.Second * 3)
 
-		mutex := cache.NewRedisLock("workflow-task-sender")
+		mutex := cache.NewRedisLock(workflowTaskSenderLockKey)
 		if err := mutex.TryLock(); err != nil {
 			continue
 		}
@@ -159,6 +161,8 @@ func WorfklowTaskSender() {
 			mutex.Unlock()
 			continue
 		}
+		running := countRunningTasksByProject()
+		waitingTasks = sortWaitingTasksByFairShare(waitingTasks, sysSetting.FairScheduling, running)
 		var t *commonmodels.WorkflowQueue
 		for _, task := range waitingTasks {
 			var concurrency int
@@ -242,6 +246,7 @@ func WorfklowTaskSender() {
 			mutex.Unlock()
 			continue
 		}
+		recordScheduleDecision(t, waitingTasks, sysSetting.FairScheduling, running)
 		mutex.Unlock()
 	}
 }
@@ -254,7 +259,7 @@ func RunningAndQueuedTasks() []*commonmodels.WorkflowQueue {
 	tasks := make([]*commonmodels.WorkflowQueue, 0)
 	for _, t := range ListTasks() {
 		// task状态为TaskQueued说明task已经被send到nsq,wd已经开始处理但是没有返回ack
-		if t.Status == config.StatusRunning || t.Status == config.StatusQueued {
+		if (t.Status == config.StatusRunning || t.Status == config.StatusQueued) && !taskPaused(t) {
 			tasks = append(tasks, t)
 		}
 	}
@@ -287,6 +292,7 @@ func WaitingTasks() ([]*commonmodels.WorkflowQueue, error) {
 	return nil, errors.New("no waiting task found")
 }
 
+// RunningWorkflowTasks lists the running tasks of the workflow taking its concurrency, the paused tasks are excluded
 func RunningWorkflowTasks(name string) ([]*commonmodels.WorkflowQueue, error) {
 	opt := &commonrepo.ListWorfklowQueueOption{
 		WorkflowName: name,
@@ -301,7 +307,13 @@ func RunningWorkflowTasks(name string) ([]*commonmodels.WorkflowQueue, error) {
 		return nil, err
 	}
 
-	return tasks, nil
+	resp := make([]*commonmodels.WorkflowQueue, 0, len(tasks))
+	for _, t := range tasks {
+		if !taskPaused(t) {
+			resp = append(resp, t)
+		}
+	}
+	return resp, nil
 }
 
 func WaitForApproveWorkflowTasks(name string) ([]*commonmodels.WorkflowQueue, error) {
@@ -63,23 +63,47 @@ func runStage(ctx context.Context, stage *commonmodels.StageTask, workflowCtx *c
 	stageCtl.AfterRun()
 }
 
-func RunStages(ctx context.Context, stages []*commonmodels.StageTask, workflowCtx *commonmodels.WorkflowTaskCtx, concurrency int, logger *zap.SugaredLogger, ack func()) {
-	for _, stage := range stages {
+// RunStages runs the stages in order, the returned status and error are set if the task is stopped while it's paused
+// after a passed stage, in which case the stage statuses don't reflect it.
+func RunStages(ctx context.Context, stages []*commonmodels.StageTask, workflowCtx *commonmodels.WorkflowTaskCtx, concurrency int, logger *zap.SugaredLogger, ack func()) (config.Status, error) {
+	for i, stage := range stages {
 		// should skip passed stage when workflow task be restarted
 		if stage.Status == config.StatusPassed {
 			continue
 		}
+		// the stages without retried jobs are not run and keep their previous status when only some jobs are retried
+		if !stageHasJobToRun(stage, workflowCtx) {
+			continue
+		}
 		runStage(ctx, stage, workflowCtx, concurrency, logger, ack)
 		if statusStopped(stage.Status) {
-			return
+			return "", nil
+		}
+		// there is nothing to wait for after the last stage
+		if i < len(stages)-1 {
+			if status, err := waitForStageResume(ctx, stage, workflowCtx, logger, ack); status != "" {
+				return status, err
+			}
+		}
+	}
+	return "", nil
+}
+
+func stageHasJobToRun(stage *commonmodels.StageTask, workflowCtx *commonmodels.WorkflowTaskCtx) bool {
+	if len(workflowCtx.RetryJobKeys) == 0 {
+		return true
+	}
+	for _, job := range stage.Jobs {
+		if workflowCtx.ShouldRunJob(job.Key) {
+			return true
 		}
 	}
+	return false
 }
 
-func ApproveStage(workflowName, jobName, userName, userID, comment string, taskID int64, approve bool) error {
+func ApproveStage(workflowName, jobName, userName, userID, comment string, taskID int64, approve bool) (*commonmodels.NativeApproval, error) {
 	approveKey := fmt.Sprintf("%s-%s-%d", workflowName, jobName, taskID)
-	_, err := approvalservice.GlobalApproveMap.DoApproval(approveKey, userName, userID, comment, approve)
-	return err
+	return approvalservice.GlobalApproveMap.DoApproval(approveKey, userName, userID, comment, approve)
 }
 
 func waitForManualExec(ctx context.Context, stage *commonmodels.StageTask, workflowCtx *commonmodels.WorkflowTaskCtx, logger *zap.SugaredLogger, ack func()) (wait bool, err error) {
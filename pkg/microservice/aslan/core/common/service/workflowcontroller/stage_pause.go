@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflowcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	config2 "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/cache"
+)
+
+const defaultStagePauseTimeout = 60
+
+type stageResumeInfo struct {
+	UserID   string `json:"user_id"`
+	UserName string `json:"user_name"`
+}
+
+func stageResumeKey(workflowName, stageName string, taskID int64) string {
+	return fmt.Sprintf("workflow-stage-resume-%s-%s-%d", workflowName, stageName, taskID)
+}
+
+// waitForStageResume halts the task after the stage until it's resumed by ResumeStage. The stage has passed so its
+// status is kept, the status the task should stop with is returned instead if it's cancelled or not resumed in time.
+func waitForStageResume(ctx context.Context, stage *commonmodels.StageTask, workflowCtx *commonmodels.WorkflowTaskCtx, logger *zap.SugaredLogger, ack func()) (config.Status, error) {
+	pause := stage.PauseAfter
+	if pause == nil || !pause.Enabled || pause.Resumed {
+		return "", nil
+	}
+
+	timeout := pause.Timeout
+	if timeout <= 0 {
+		timeout = defaultStagePauseTimeout
+	}
+
+	key := stageResumeKey(workflowCtx.WorkflowName, stage.Name, workflowCtx.TaskID)
+	redisCache := cache.NewRedisCache(config2.RedisCommonCacheTokenDB())
+	defer redisCache.Delete(key)
+
+	logger.Infof("stage %s paused, waiting to be resumed", stage.Name)
+	pause.Waiting = true
+	ack()
+	defer func() {
+		pause.Waiting = false
+		ack()
+	}()
+
+	timeoutChan := time.After(time.Duration(timeout) * time.Minute)
+	for {
+		time.Sleep(1 * time.Second)
+		select {
+		case <-ctx.Done():
+			return config.StatusCancelled, nil
+		case <-timeoutChan:
+			return config.StatusTimeout, fmt.Errorf("stage %s was not resumed in %d minutes", stage.Name, timeout)
+		default:
+			value, err := redisCache.GetString(key)
+			if err != nil || value == "" {
+				continue
+			}
+			info := &stageResumeInfo{}
+			if err := json.Unmarshal([]byte(value), info); err != nil {
+				logger.Errorf("failed to unmarshal resume info of stage %s: %s", stage.Name, err)
+				continue
+			}
+			pause.Resumed = true
+			pause.ResumedByID = info.UserID
+			pause.ResumedByName = info.UserName
+			pause.ResumeTime = time.Now().Unix()
+			logger.Infof("stage %s resumed by %s", stage.Name, info.UserName)
+			return waitForConcurrencySlot(ctx, workflowCtx.WorkflowName, pause, ack), nil
+		}
+	}
+}
+
+// waitForConcurrencySlot blocks until the resumed task takes the workflow concurrency again, other tasks may have
+// taken the slot while it was paused. The check is made with the lock of the task sender held so that the sender
+// doesn't start another task with the same slot.
+func waitForConcurrencySlot(ctx context.Context, workflowName string, pause *commonmodels.StagePause, ack func()) config.Status {
+	for {
+		mutex := cache.NewRedisLock(workflowTaskSenderLockKey)
+		if err := mutex.Lock(); err == nil {
+			if concurrencySlotAvailable(workflowName) {
+				pause.Waiting = false
+				ack()
+				mutex.Unlock()
+				return ""
+			}
+			mutex.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return config.StatusCancelled
+		case <-time.After(3 * time.Second):
+		}
+	}
+}
+
+// concurrencySlotAvailable checks the system and the workflow concurrency the same way the task sender does
+func concurrencySlotAvailable(workflowName string) bool {
+	sysSetting, err := commonrepo.NewSystemSettingColl().Get()
+	if err != nil || !hasAgentAvaiable(int(sysSetting.WorkflowConcurrency)) {
+		return false
+	}
+	workflow, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
+	if err != nil || workflow.ConcurrencyLimit == -1 {
+		return true
+	}
+	running, err := RunningWorkflowTasks(workflowName)
+	if err != nil {
+		return false
+	}
+	approving, err := WaitForApproveWorkflowTasks(workflowName)
+	if err != nil {
+		return false
+	}
+	return len(running)+len(approving) < workflow.ConcurrencyLimit
+}
+
+// taskPaused checks whether the queued task is halted after a stage, the paused tasks don't take the workflow
+// concurrency since nothing is running, they wait for a slot once resumed.
+func taskPaused(t *commonmodels.WorkflowQueue) bool {
+	for _, stage := range t.Stages {
+		if stage.PauseAfter != nil && stage.PauseAfter.Waiting {
+			return true
+		}
+	}
+	return false
+}
+
+// ResumeStage resumes the task paused after the stage, the task may be running on another aslan instance so the
+// signal is passed through redis.
+func ResumeStage(workflowName, stageName string, taskID int64, userID, userName string) error {
+	bytes, err := json.Marshal(&stageResumeInfo{UserID: userID, UserName: userName})
+	if err != nil {
+		return err
+	}
+	return cache.NewRedisCache(config2.RedisCommonCacheTokenDB()).Write(stageResumeKey(workflowName, stageName, taskID), string(bytes), time.Hour)
+}
@@ -85,6 +85,8 @@ func instantiateStepCtl(step *commonmodels.StepTask, workflowCtx *commonmodels.W
 		stepCtl, err = NewToolInstallCtl(step, jobPath, logger)
 	case config.StepArchive:
 		stepCtl, err = NewArchiveCtl(step, workflowCtx, logger)
+	case config.StepArtifactUpload:
+		stepCtl, err = NewArtifactUploadCtl(step, logger)
 	case config.StepDownloadArchive:
 		stepCtl, err = NewDownloadArchiveCtl(step, logger)
 	case config.StepJunitReport:
@@ -95,6 +97,8 @@ func instantiateStepCtl(step *commonmodels.StepTask, workflowCtx *commonmodels.W
 		stepCtl, err = NewSonarCheckCtl(step, workflowCtx, logger)
 	case config.StepSonarGetMetrics:
 		stepCtl, err = NewSonarGetMetricsCtl(step, workflowCtx, logger)
+	case config.StepScannerReport:
+		stepCtl, err = NewScannerReportCtl(step, logger)
 	case config.StepDistributeImage:
 		stepCtl, err = NewDistributeCtl(step, workflowCtx, jobName, logger)
 	case config.StepDebugBefore, config.StepDebugAfter:
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stepcontroller
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+)
+
+type artifactUploadCtl struct {
+	step               *commonmodels.StepTask
+	artifactUploadSpec *step.StepArtifactUploadSpec
+	log                *zap.SugaredLogger
+}
+
+func NewArtifactUploadCtl(stepTask *commonmodels.StepTask, log *zap.SugaredLogger) (*artifactUploadCtl, error) {
+	yamlString, err := yaml.Marshal(stepTask.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal artifact upload spec error: %v", err)
+	}
+	artifactUploadSpec := &step.StepArtifactUploadSpec{}
+	if err := yaml.Unmarshal(yamlString, &artifactUploadSpec); err != nil {
+		return nil, fmt.Errorf("unmarshal artifact upload spec error: %v", err)
+	}
+	stepTask.Spec = artifactUploadSpec
+	return &artifactUploadCtl{artifactUploadSpec: artifactUploadSpec, log: log, step: stepTask}, nil
+}
+
+// PreRun fills the address and credentials of the repository, they are not saved in the build
+func (s *artifactUploadCtl) PreRun(ctx context.Context) error {
+	repo, err := commonrepo.NewArtifactRepositoryColl().GetByID(ctx, s.artifactUploadSpec.RepositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to find artifact repository %s, err: %s", s.artifactUploadSpec.RepositoryID, err)
+	}
+	s.artifactUploadSpec.Type = repo.Type
+	s.artifactUploadSpec.Address = repo.Address
+	s.artifactUploadSpec.Repository = repo.Repository
+	s.artifactUploadSpec.Username = repo.Username
+	s.artifactUploadSpec.Password = repo.Password
+	s.artifactUploadSpec.Token = repo.Token
+	s.step.Spec = s.artifactUploadSpec
+	return nil
+}
+
+func (s *artifactUploadCtl) AfterRun(ctx context.Context) error {
+	return nil
+}
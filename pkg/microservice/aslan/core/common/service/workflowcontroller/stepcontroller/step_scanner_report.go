@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stepcontroller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/checkmarx"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	s3tool "github.com/koderover/zadig/v2/pkg/tool/s3"
+	"github.com/koderover/zadig/v2/pkg/tool/semgrep"
+	"github.com/koderover/zadig/v2/pkg/types"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+// maxScannerFindings limits the findings saved in the task, the counters still cover all of them
+const maxScannerFindings = 500
+
+type scannerReportCtl struct {
+	step              *commonmodels.StepTask
+	scannerReportSpec *step.StepScannerReportSpec
+	log               *zap.SugaredLogger
+}
+
+func NewScannerReportCtl(stepTask *commonmodels.StepTask, log *zap.SugaredLogger) (*scannerReportCtl, error) {
+	yamlString, err := yaml.Marshal(stepTask.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal scanner report spec error: %v", err)
+	}
+	scannerReportSpec := &step.StepScannerReportSpec{}
+	if err := yaml.Unmarshal(yamlString, &scannerReportSpec); err != nil {
+		return nil, fmt.Errorf("unmarshal scanner report spec error: %v", err)
+	}
+	stepTask.Spec = scannerReportSpec
+	return &scannerReportCtl{scannerReportSpec: scannerReportSpec, log: log, step: stepTask}, nil
+}
+
+func (s *scannerReportCtl) PreRun(ctx context.Context) error {
+	if s.scannerReportSpec.S3Storage == nil {
+		modelS3, err := commonrepo.NewS3StorageColl().FindDefault()
+		if err != nil {
+			return err
+		}
+		s.scannerReportSpec.S3Storage = modelS3toS3(modelS3)
+	}
+	s.step.Spec = s.scannerReportSpec
+	return nil
+}
+
+func (s *scannerReportCtl) AfterRun(ctx context.Context) error {
+	storage := s.scannerReportSpec.S3Storage
+	if storage == nil || s.scannerReportSpec.S3DestDir == "" || s.scannerReportSpec.FileName == "" {
+		return nil
+	}
+
+	filename, err := util.GenerateTmpFile()
+	if err != nil {
+		log.Errorf("GenerateTmpFile err:%v", err)
+		return err
+	}
+	defer os.Remove(filename)
+
+	forcedPathStyle := true
+	if storage.Provider == setting.ProviderSourceAli {
+		forcedPathStyle = false
+	}
+	client, err := s3tool.NewClient(storage.Endpoint, storage.Ak, storage.Sk, storage.Region, storage.Insecure, forcedPathStyle)
+	if err != nil {
+		log.Errorf("NewClient err:%v", err)
+		return err
+	}
+	destDir := s.scannerReportSpec.S3DestDir
+	if len(storage.Subfolder) > 0 {
+		destDir = strings.TrimLeft(filepath.Join(storage.Subfolder, destDir), "/")
+	}
+	objectKey := filepath.Join(destDir, s.scannerReportSpec.FileName)
+	if err := client.Download(storage.Bucket, objectKey, filename); err != nil {
+		log.Errorf("Download %s report err:%v", s.scannerReportSpec.ScannerType, err)
+		return err
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		log.Errorf("read %s report error: %v", s.scannerReportSpec.ScannerType, err)
+		return err
+	}
+
+	result, err := parseScannerReport(s.scannerReportSpec.ScannerType, data)
+	if err != nil {
+		log.Errorf("parse %s report error: %v", s.scannerReportSpec.ScannerType, err)
+		return err
+	}
+	s.scannerReportSpec.ScannerResult = result
+	s.step.Spec = s.scannerReportSpec
+	return nil
+}
+
+func parseScannerReport(scannerType string, data []byte) (*step.ScannerResult, error) {
+	result := &step.ScannerResult{Findings: make([]*step.ScannerFinding, 0)}
+	switch scannerType {
+	case types.ScanningTypeSemgrep:
+		report, err := semgrep.ParseReport(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range report.Results {
+			result.AddFinding(&step.ScannerFinding{
+				RuleID:   item.CheckID,
+				Severity: normalizeScannerSeverity(item.Extra.Severity),
+				Message:  item.Extra.Message,
+				File:     item.Path,
+				Line:     item.Start.Line,
+			}, maxScannerFindings)
+		}
+	case types.ScanningTypeCheckmarx:
+		report, err := checkmarx.ParseReport(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range report.Results {
+			file, line := item.Location()
+			ruleID := item.Data.QueryName
+			if ruleID == "" {
+				ruleID = item.ID
+			}
+			result.AddFinding(&step.ScannerFinding{
+				RuleID:   ruleID,
+				Severity: normalizeScannerSeverity(item.Severity),
+				Message:  item.Description,
+				File:     file,
+				Line:     line,
+			}, maxScannerFindings)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported scanner type: %s", scannerType)
+	}
+	return result, nil
+}
+
+// normalizeScannerSeverity maps the severities of different scanners to the same levels,
+// semgrep reports ERROR/WARNING/INFO while checkmarx reports CRITICAL/HIGH/MEDIUM/LOW/INFO
+func normalizeScannerSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return step.ScannerSeverityCritical
+	case "error", "high":
+		return step.ScannerSeverityHigh
+	case "warning", "medium":
+		return step.ScannerSeverityMedium
+	case "low":
+		return step.ScannerSeverityLow
+	default:
+		return step.ScannerSeverityInfo
+	}
+}
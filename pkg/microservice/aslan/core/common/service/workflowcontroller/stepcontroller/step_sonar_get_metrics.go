@@ -19,12 +19,15 @@ package stepcontroller
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
 
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/scmnotify"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
 	"github.com/koderover/zadig/v2/pkg/tool/sonar"
@@ -76,7 +79,11 @@ func (s *sonarGetMetricsCtl) AfterRun(ctx context.Context) error {
 	}
 
 	client := sonar.NewSonarClient(s.sonarGetMetricsSpec.SonarServer, s.sonarGetMetricsSpec.SonarToken)
-	resp, err := client.GetComponentMeasures(s.sonarGetMetricsSpec.ProjectKey)
+	pullRequest := ""
+	if s.sonarGetMetricsSpec.PullRequest != nil {
+		pullRequest = strconv.Itoa(s.sonarGetMetricsSpec.PullRequest.ID)
+	}
+	resp, err := client.GetComponentMeasures(s.sonarGetMetricsSpec.ProjectKey, pullRequest)
 	if err != nil {
 		err = fmt.Errorf("get component measures error: %v", err)
 		log.Error(err)
@@ -100,7 +107,8 @@ func (s *sonarGetMetricsCtl) AfterRun(ctx context.Context) error {
 		}
 	}
 
-	if s.sonarGetMetricsSpec.CheckQualityGate {
+	// the quality gate result is required to decorate the pull request
+	if s.sonarGetMetricsSpec.CheckQualityGate || s.sonarGetMetricsSpec.PullRequest != nil {
 		analysisID, err := client.WaitForCETaskTobeDone(id, time.Minute*10)
 		if err != nil {
 			log.Error(err)
@@ -114,5 +122,62 @@ func (s *sonarGetMetricsCtl) AfterRun(ctx context.Context) error {
 		s.sonarGetMetricsSpec.SonarMetrics.QualityGateStatus = gateInfo.ProjectStatus.Status
 	}
 
+	// failing to save the metrics should not fail the scanning
+	if err := s.saveScanningMetrics(); err != nil {
+		log.Warnf("failed to save scanning metrics, err: %s", err)
+	}
+
+	if s.sonarGetMetricsSpec.PullRequest != nil {
+		// failing to decorate the pull request should not fail the scanning
+		if err := s.decoratePullRequest(); err != nil {
+			log.Warnf("failed to comment sonar result to pull request, err: %s", err)
+		}
+	}
+
 	return nil
 }
+
+func (s *sonarGetMetricsCtl) decoratePullRequest() error {
+	pr := s.sonarGetMetricsSpec.PullRequest
+	metrics := s.sonarGetMetricsSpec.SonarMetrics
+
+	link, err := sonar.GetSonarAddressWithPullRequest(s.sonarGetMetricsSpec.SonarServer, s.sonarGetMetricsSpec.ProjectKey, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	emoji := "✅"
+	if metrics.QualityGateStatus != sonar.QualityGateOK {
+		emoji = "❌"
+	}
+	body := fmt.Sprintf("#### SonarQube Quality Gate %s %s\n\n", emoji, metrics.QualityGateStatus)
+	body += "| Bugs | Vulnerabilities | Code Smells | Coverage | Lines |\n"
+	body += "| --- | --- | --- | --- | --- |\n"
+	body += fmt.Sprintf("| %s | %s | %s | %s | %s |\n\n", metrics.Bugs, metrics.Vulnerabilities, metrics.CodeSmells, metrics.Coverage, metrics.Ncloc)
+	body += fmt.Sprintf("[%s](%s)", s.sonarGetMetricsSpec.ProjectKey, link)
+
+	return scmnotify.NewClient().CommentPullRequest(pr.CodehostID, pr.RepoNamespace, pr.RepoName, pr.ID, body)
+}
+
+func (s *sonarGetMetricsCtl) saveScanningMetrics() error {
+	metrics := s.sonarGetMetricsSpec.SonarMetrics
+	parseInt := func(value string) int64 {
+		i, _ := strconv.ParseInt(value, 10, 64)
+		return i
+	}
+	coverage, _ := strconv.ParseFloat(metrics.Coverage, 64)
+
+	return commonrepo.NewScanningMetricsColl().Create(&commonmodels.ScanningMetrics{
+		ProjectName:       s.workflowCtx.ProjectName,
+		ScanningName:      s.sonarGetMetricsSpec.ScanningName,
+		WorkflowName:      s.workflowCtx.WorkflowName,
+		JobName:           s.step.JobName,
+		TaskID:            s.workflowCtx.TaskID,
+		Bugs:              parseInt(metrics.Bugs),
+		Vulnerabilities:   parseInt(metrics.Vulnerabilities),
+		CodeSmells:        parseInt(metrics.CodeSmells),
+		Coverage:          coverage,
+		Ncloc:             parseInt(metrics.Ncloc),
+		QualityGateStatus: string(metrics.QualityGateStatus),
+	})
+}
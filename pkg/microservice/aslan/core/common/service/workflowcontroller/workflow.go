@@ -192,6 +192,7 @@ func (c *workflowCtl) Run(ctx context.Context, concurrency int) {
 	defer func() {
 		c.workflowTask.EndTime = time.Now().Unix()
 		c.logger.Infof("finish workflow: %s,status: %s", c.workflowTask.WorkflowName, c.workflowTask.Status)
+		c.saveJobOutputs()
 		c.ack()
 		// clean share storage after workflow finished
 		go c.CleanShareStorage()
@@ -246,6 +247,7 @@ func (c *workflowCtl) Run(ctx context.Context, concurrency int) {
 		GlobalContextEach:           c.globalContextEach,
 		ClusterIDAdd:                c.addClusterID,
 		StartTime:                   time.Now(),
+		RetryJobKeys:                c.workflowTask.RetryJobKeys,
 	}
 	defer jobcontroller.CleanWorkflowJobs(ctx, c.workflowTask, workflowCtx, c.logger, c.ack)
 	if err := scmnotify.NewService().UpdateWebhookCommentForWorkflowV4(c.workflowTask, c.logger); err != nil {
@@ -254,8 +256,11 @@ func (c *workflowCtl) Run(ctx context.Context, concurrency int) {
 	if err := scmnotify.NewService().UpdateGitCheckForWorkflowV4(c.workflowTask.WorkflowArgs, c.workflowTask.TaskID, c.logger); err != nil {
 		log.Warnf("Failed to update github check status for custom workflow %s, taskID: %d the error is: %s", c.workflowTask.WorkflowName, c.workflowTask.TaskID, err)
 	}
-	RunStages(ctx, c.workflowTask.Stages, workflowCtx, concurrency, c.logger, c.ack)
-	updateworkflowStatus(c.workflowTask)
+	pauseStatus, pauseErr := RunStages(ctx, c.workflowTask.Stages, workflowCtx, concurrency, c.logger, c.ack)
+	if pauseErr != nil {
+		c.workflowTask.Error = pauseErr.Error()
+	}
+	updateworkflowStatus(c.workflowTask, workflowCtx, pauseStatus)
 }
 
 func (c *workflowCtl) handleWorkflowBreakpoint(jobName, position string, set bool) error {
@@ -500,7 +505,9 @@ FOR:
 	return nil
 }
 
-func updateworkflowStatus(workflow *commonmodels.WorkflowTask) {
+// updateworkflowStatus calculates the status of the task from its stages, the stages not run when only some jobs of the
+// task are retried are excluded. pauseStatus is the status the task is stopped with while paused after a stage.
+func updateworkflowStatus(workflow *commonmodels.WorkflowTask, workflowCtx *commonmodels.WorkflowTaskCtx, pauseStatus config.Status) {
 	statusMap := map[config.Status]int{
 		config.StatusPause:     7,
 		config.StatusReject:    6,
@@ -515,14 +522,20 @@ func updateworkflowStatus(workflow *commonmodels.WorkflowTask) {
 	// 初始化workflowStatus为创建状态
 	workflowStatus := config.StatusRunning
 
-	stageStatus := make([]int, len(workflow.Stages))
+	stageStatus := make([]int, 0, len(workflow.Stages))
 
-	for i, j := range workflow.Stages {
+	for _, j := range workflow.Stages {
+		if !stageHasJobToRun(j, workflowCtx) {
+			continue
+		}
 		statusCode, ok := statusMap[j.Status]
 		if !ok {
 			statusCode = -1
 		}
-		stageStatus[i] = statusCode
+		stageStatus = append(stageStatus, statusCode)
+	}
+	if statusCode, ok := statusMap[pauseStatus]; ok {
+		stageStatus = append(stageStatus, statusCode)
 	}
 	var workflowStatusCode int
 	for i, code := range stageStatus {
@@ -582,6 +595,9 @@ func (c *workflowCtl) updateWorkflowTask() {
 		if err := instantmessage.NewWeChatClient().SendWorkflowTaskNotifications(c.workflowTask); err != nil {
 			c.logger.Errorf("send workflow task notification failed, error: %v", err)
 		}
+		if err := instantmessage.NewWeChatClient().SendWorkflowJobNotifications(c.workflowTask); err != nil {
+			c.logger.Errorf("send workflow job notification failed, error: %v", err)
+		}
 		q := ConvertTaskToQueue(c.workflowTask)
 		if err := Remove(q); err != nil {
 			c.logger.Errorf("remove queue task: %s:%d error: %v", c.workflowTask.WorkflowName, c.workflowTask.TaskID, err)
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+const deployWindowTimeLayout = "15:04"
+
+// ValidateDeployWindow checks the timezone and the periods of the deploy window, a nil window is valid.
+func ValidateDeployWindow(window *models.DeployWindow) error {
+	if window == nil {
+		return nil
+	}
+	if _, err := time.LoadLocation(window.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %s: %s", window.Timezone, err)
+	}
+	if window.ApprovalTimeout < 0 {
+		return fmt.Errorf("approval timeout can not be negative")
+	}
+	if window.Enabled && len(window.Periods) == 0 {
+		return fmt.Errorf("at least one period is required when the deploy window is enabled")
+	}
+	for _, period := range window.Periods {
+		start, err := time.Parse(deployWindowTimeLayout, period.StartTime)
+		if err != nil {
+			return fmt.Errorf("invalid start time %s, it should be in the form of HH:MM", period.StartTime)
+		}
+		end, err := time.Parse(deployWindowTimeLayout, period.EndTime)
+		if err != nil {
+			return fmt.Errorf("invalid end time %s, it should be in the form of HH:MM", period.EndTime)
+		}
+		if start.Equal(end) {
+			return fmt.Errorf("the start time and the end time of a period can not be the same")
+		}
+		for _, weekday := range period.Weekdays {
+			if weekday < int(time.Sunday) || weekday > int(time.Saturday) {
+				return fmt.Errorf("invalid weekday %d, it should be between 0 and 6", weekday)
+			}
+		}
+	}
+	return nil
+}
+
+// InDeployWindow returns whether the time is in one of the periods of the deploy window,
+// it's always true if the window is not enabled.
+func InDeployWindow(window *models.DeployWindow, t time.Time) (bool, error) {
+	if window == nil || !window.Enabled {
+		return true, nil
+	}
+	location, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("invalid timezone %s: %s", window.Timezone, err)
+	}
+	t = t.In(location)
+	minute := t.Hour()*60 + t.Minute()
+
+	for _, period := range window.Periods {
+		start, err := time.Parse(deployWindowTimeLayout, period.StartTime)
+		if err != nil {
+			return false, fmt.Errorf("invalid start time %s", period.StartTime)
+		}
+		end, err := time.Parse(deployWindowTimeLayout, period.EndTime)
+		if err != nil {
+			return false, fmt.Errorf("invalid end time %s", period.EndTime)
+		}
+		startMinute := start.Hour()*60 + start.Minute()
+		endMinute := end.Hour()*60 + end.Minute()
+
+		if startMinute < endMinute {
+			if periodHasWeekday(period, t.Weekday()) && minute >= startMinute && minute < endMinute {
+				return true, nil
+			}
+			continue
+		}
+		// the period crosses midnight, the part after midnight belongs to the weekday before
+		if periodHasWeekday(period, t.Weekday()) && minute >= startMinute {
+			return true, nil
+		}
+		if periodHasWeekday(period, t.AddDate(0, 0, -1).Weekday()) && minute < endMinute {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func periodHasWeekday(period *models.DeployWindowPeriod, weekday time.Weekday) bool {
+	if len(period.Weekdays) == 0 {
+		return true
+	}
+	for _, day := range period.Weekdays {
+		if day == int(weekday) {
+			return true
+		}
+	}
+	return false
+}
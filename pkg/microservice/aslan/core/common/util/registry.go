@@ -30,21 +30,24 @@ import (
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/tool/acr"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
 	"github.com/koderover/zadig/v2/pkg/util"
 )
 
-var awsKeyMap sync.Map
+// temporary tokens are cached in memory and refreshed before they expire
+var registryTokenMap sync.Map
 var expirationTime = 10 * time.Hour
+var registryTokenRefreshAhead = 2 * time.Hour
 
-type awsKeyWithExpiration struct {
-	AccessKey  string
-	SecretKey  string
-	Expiration int64
-}
-
-func (k *awsKeyWithExpiration) IsExpired() bool {
-	return time.Now().Unix() > k.Expiration
+// TemporaryRegistryCredential is the temporary docker login credential requested with the access key pair of the registry
+type TemporaryRegistryCredential struct {
+	Username string
+	Password string
+	// ExpireTime is the unix timestamp when the token expires, 0 if it's unknown
+	ExpireTime int64
+	// refreshTime is the unix timestamp after which a new token is requested
+	refreshTime int64
 }
 
 func DecodeRegistry(resp *models.RegistryNamespace) (*models.RegistryNamespace, error) {
@@ -53,29 +56,64 @@ func DecodeRegistry(resp *models.RegistryNamespace) (*models.RegistryNamespace,
 	case config.RegistryTypeSWR:
 		resp.SecretKey = util.ComputeHmacSha256(resp.AccessKey, resp.SecretKey)
 		resp.AccessKey = fmt.Sprintf("%s@%s", resp.Region, resp.AccessKey)
-	case config.RegistryTypeAWS:
-		realAK, realSK, err := GetAWSRegistryCredential(resp.ID.Hex(), resp.AccessKey, resp.SecretKey, resp.Region)
+	case config.RegistryTypeAWS, config.RegistryProviderACREnterprise:
+		if !resp.UseTemporaryCredential() {
+			break
+		}
+		cred, err := GetTemporaryRegistryCredential(resp, false)
 		if err != nil {
-			log.Errorf("Failed to get keypair from aws, the error is: %s", err)
+			log.Errorf("Failed to get temporary credential of registry %s, the error is: %s", resp.RegAddr, err)
 			return nil, err
 		}
-		resp.AccessKey = realAK
-		resp.SecretKey = realSK
+		resp.AccessKey = cred.Username
+		resp.SecretKey = cred.Password
 	}
 	return resp, nil
 }
 
-func GetAWSRegistryCredential(id, ak, sk, region string) (realAK string, realSK string, err error) {
-	// first we try to get ak/sk from our memory cache
-	obj, ok := awsKeyMap.Load(id)
-	if ok {
-		keypair, ok := obj.(awsKeyWithExpiration)
-		if ok {
-			if !keypair.IsExpired() {
-				return keypair.AccessKey, keypair.SecretKey, nil
+// GetTemporaryRegistryCredential returns the cached temporary credential of the registry, a new one is requested
+// if the cached one is about to expire or forceRefresh is true.
+func GetTemporaryRegistryCredential(reg *models.RegistryNamespace, forceRefresh bool) (*TemporaryRegistryCredential, error) {
+	id := reg.ID.Hex()
+	if !forceRefresh {
+		if obj, ok := registryTokenMap.Load(id); ok {
+			if cred, ok := obj.(*TemporaryRegistryCredential); ok && time.Now().Unix() < cred.refreshTime {
+				return cred, nil
 			}
 		}
 	}
+
+	var cred *TemporaryRegistryCredential
+	var err error
+	switch reg.RegProvider {
+	case config.RegistryTypeAWS:
+		cred, err = getAWSRegistryToken(reg.AccessKey, reg.SecretKey, reg.Region)
+	case config.RegistryProviderACREnterprise:
+		if reg.CredentialSetting == nil || reg.CredentialSetting.InstanceID == "" {
+			return nil, errors.New("instance id is required to get the temporary credential of acr enterprise")
+		}
+		cred, err = getACRRegistryToken(reg.AccessKey, reg.SecretKey, reg.Region, reg.CredentialSetting.InstanceID)
+	default:
+		return nil, fmt.Errorf("registry provider %s doesn't support temporary credentials", reg.RegProvider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cred.refreshTime = time.Now().Add(expirationTime).Unix()
+	if cred.ExpireTime > 0 && cred.ExpireTime-int64(registryTokenRefreshAhead.Seconds()) < cred.refreshTime {
+		cred.refreshTime = cred.ExpireTime - int64(registryTokenRefreshAhead.Seconds())
+	}
+	registryTokenMap.Store(id, cred)
+	return cred, nil
+}
+
+// ClearTemporaryRegistryCredential drops the cached temporary credential, e.g. when the access key pair is changed
+func ClearTemporaryRegistryCredential(id string) {
+	registryTokenMap.Delete(id)
+}
+
+func getAWSRegistryToken(ak, sk, region string) (*TemporaryRegistryCredential, error) {
 	creds := credentials.NewStaticCredentials(ak, sk, "")
 	config := &aws.Config{
 		Region:      aws.String(region),
@@ -83,30 +121,46 @@ func GetAWSRegistryCredential(id, ak, sk, region string) (realAK string, realSK
 	}
 	sess, err := session.NewSession(config)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	svc := ecr.New(sess)
 	input := &ecr.GetAuthorizationTokenInput{}
 
 	result, err := svc.GetAuthorizationToken(input)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+	if len(result.AuthorizationData) == 0 {
+		return nil, errors.New("no authorization data returned")
 	}
 	// since the new AWS ECR will give a token that has access to ALL the repository, we use the first token
 	encodedToken := *result.AuthorizationData[0].AuthorizationToken
 	rawDecodedText, err := base64.StdEncoding.DecodeString(encodedToken)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	keypair := strings.Split(string(rawDecodedText), ":")
 	if len(keypair) != 2 {
-		return "", "", errors.New("format of keypair is invalid")
+		return nil, errors.New("format of keypair is invalid")
+	}
+	cred := &TemporaryRegistryCredential{
+		Username: keypair[0],
+		Password: keypair[1],
+	}
+	if result.AuthorizationData[0].ExpiresAt != nil {
+		cred.ExpireTime = result.AuthorizationData[0].ExpiresAt.Unix()
+	}
+	return cred, nil
+}
+
+func getACRRegistryToken(ak, sk, region, instanceID string) (*TemporaryRegistryCredential, error) {
+	token, err := acr.NewClient(region, ak, sk).GetAuthorizationToken(instanceID)
+	if err != nil {
+		return nil, err
 	}
-	// cache the aws ak/sk
-	awsKeyMap.Store(id, awsKeyWithExpiration{
-		AccessKey:  keypair[0],
-		SecretKey:  keypair[1],
-		Expiration: time.Now().Add(expirationTime).Unix(),
-	})
-	return keypair[0], keypair[1], nil
+	return &TemporaryRegistryCredential{
+		Username:   token.TempUsername,
+		Password:   token.AuthorizationToken,
+		ExpireTime: token.ExpireTime / 1000,
+	}, nil
 }
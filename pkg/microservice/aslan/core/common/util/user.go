@@ -17,6 +17,8 @@ limitations under the License.
 package util
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
@@ -102,3 +104,71 @@ func GeneFlatUsersWithCaller(users []*models.User, userID string) ([]*models.Use
 
 	return flatUsers, userMap
 }
+
+// GeneQuorumRules returns copies of the quorum rules with the current members of their user groups
+func GeneQuorumRules(rules []*models.ApprovalQuorumRule) []*models.ApprovalQuorumRule {
+	resp := make([]*models.ApprovalQuorumRule, 0, len(rules))
+	for _, rule := range rules {
+		copied := *rule
+		copied.MemberIDs = nil
+		groupInfo, err := user.New().GetGroupDetailedInfo(rule.GroupID)
+		if err != nil {
+			log.Warnf("failed to find user group %s of quorum rule, error: %s", rule.GroupID, err)
+		} else {
+			copied.MemberIDs = groupInfo.UIDs
+		}
+		resp = append(resp, &copied)
+	}
+	return resp
+}
+
+// GeneNativeApprovalSteps returns copies of the approval steps with flat approve users and resolved quorum rules
+func GeneNativeApprovalSteps(steps []*models.NativeApprovalStep) []*models.NativeApprovalStep {
+	resp := make([]*models.NativeApprovalStep, 0, len(steps))
+	for _, step := range steps {
+		copied := *step
+		copied.ApproveUsers, _ = GeneFlatUsers(step.ApproveUsers)
+		copied.QuorumRules = GeneQuorumRules(step.QuorumRules)
+		resp = append(resp, &copied)
+	}
+	return resp
+}
+
+// LintNativeApprovalQuorum checks the quorum rules and the approval steps of a native approval
+func LintNativeApprovalQuorum(approval *models.NativeApproval) error {
+	if err := lintQuorumRules(approval.QuorumRules); err != nil {
+		return err
+	}
+	for i, step := range approval.Steps {
+		if step.NeededApprovers <= 0 {
+			return fmt.Errorf("needed approvers of approval step %d should be greater than 0", i+1)
+		}
+		allApproveUsers, _ := GeneFlatUsers(step.ApproveUsers)
+		if len(allApproveUsers) < step.NeededApprovers {
+			return fmt.Errorf("approve users of approval step %d should not less than needed approvers", i+1)
+		}
+		if err := lintQuorumRules(step.QuorumRules); err != nil {
+			return fmt.Errorf("approval step %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+func lintQuorumRules(rules []*models.ApprovalQuorumRule) error {
+	for _, rule := range rules {
+		if rule.GroupID == "" {
+			return fmt.Errorf("user group of quorum rule is required")
+		}
+		if rule.MinApprovers <= 0 {
+			return fmt.Errorf("min approvers of quorum rule %s should be greater than 0", rule.GroupName)
+		}
+		groupInfo, err := user.New().GetGroupDetailedInfo(rule.GroupID)
+		if err != nil {
+			return fmt.Errorf("failed to find user group %s of quorum rule: %s", rule.GroupName, err)
+		}
+		if len(groupInfo.UIDs) < rule.MinApprovers {
+			return fmt.Errorf("user group %s has less members than the min approvers of its quorum rule", rule.GroupName)
+		}
+	}
+	return nil
+}
@@ -18,10 +18,13 @@ package util
 
 import (
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/shared/client/plutusvendor"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
@@ -56,6 +59,31 @@ func CheckDefineResourceParam(req setting.Request, reqSpec setting.RequestSpec)
 	return nil
 }
 
+var htmlReportNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// CheckHTMLReports checks that the names of the html reports are unique and can be used in the url,
+// and the paths are relative to the workspace
+func CheckHTMLReports(reports []*commonmodels.HTMLReport) error {
+	names := make(map[string]struct{})
+	for _, report := range reports {
+		if !htmlReportNameRegex.MatchString(report.Name) {
+			return fmt.Errorf("invalid html report name %s, only letters, digits, - and _ are allowed", report.Name)
+		}
+		if _, ok := names[report.Name]; ok {
+			return fmt.Errorf("duplicated html report name %s", report.Name)
+		}
+		names[report.Name] = struct{}{}
+
+		if report.Path == "" || path.IsAbs(report.Path) || strings.HasPrefix(path.Clean(report.Path), "..") {
+			return fmt.Errorf("invalid path %s of html report %s, it should be relative to the workspace", report.Path, report.Name)
+		}
+		if report.Index != "" && strings.HasPrefix(path.Clean(report.Index), "..") {
+			return fmt.Errorf("invalid index %s of html report %s", report.Index, report.Name)
+		}
+	}
+	return nil
+}
+
 func CheckZadigProfessionalLicense() error {
 	licenseStatus, err := plutusvendor.New().CheckZadigXLicenseStatus()
 	if err != nil {
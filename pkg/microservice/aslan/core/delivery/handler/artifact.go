@@ -90,6 +90,40 @@ func ListDeliveryArtifacts(c *gin.Context) {
 	ctx.Resp, ctx.Err = artifacts, err
 }
 
+// @Summary Trace Image
+// @Description Get the build tasks, the source commits and the envs of the image
+// @Tags 	delivery
+// @Produce json
+// @Param 	image	query		string	true	"registry/repo:tag, registry/repo@digest or digest"
+// @Success 200 	{object} 	deliveryservice.ImageTrace
+// @Router /api/aslan/delivery/artifacts/trace [get]
+func TraceImage(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.DeliveryCenter.ViewArtifact {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	image := c.Query("image")
+	if image == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("image can't be empty!")
+		return
+	}
+
+	ctx.Resp, ctx.Err = deliveryservice.TraceImage(image, ctx.Logger)
+}
+
 func GetDeliveryArtifactIDByImage(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
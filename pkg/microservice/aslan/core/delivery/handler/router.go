@@ -29,6 +29,7 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		deliveryArtifact.GET("", ListDeliveryArtifacts)
 		deliveryArtifact.GET("/:id", GetDeliveryArtifact)
 		deliveryArtifact.GET("/image", GetDeliveryArtifactIDByImage)
+		deliveryArtifact.GET("/trace", TraceImage)
 		deliveryArtifact.POST("/:id/activities", CreateDeliveryActivities)
 	}
 
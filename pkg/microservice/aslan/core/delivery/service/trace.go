@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// imageOutputName is the name of the output of the build jobs holding the built image
+const imageOutputName = "IMAGE"
+
+type ImageTrace struct {
+	Image    string                         `json:"image"`
+	Digest   string                         `json:"digest,omitempty"`
+	Artifact *commonmodels.DeliveryArtifact `json:"artifact,omitempty"`
+	// Builds are the build tasks producing the image, the latest first
+	Builds  []*ImageTraceBuild             `json:"builds"`
+	Commits []*commonmodels.ActivityCommit `json:"commits"`
+	// Deployments are the envs the image is currently deployed to
+	Deployments []*ImageDeployment `json:"deployments"`
+}
+
+type ImageTraceBuild struct {
+	ProjectName         string `json:"project_name"`
+	WorkflowName        string `json:"workflow_name"`
+	WorkflowDisplayName string `json:"workflow_display_name"`
+	TaskID              int64  `json:"task_id"`
+	TaskStatus          string `json:"task_status"`
+	JobName             string `json:"job_name"`
+	ServiceName         string `json:"service_name"`
+	ServiceModule       string `json:"service_module"`
+	CreateTime          int64  `json:"create_time"`
+}
+
+type ImageDeployment struct {
+	ProjectName   string `json:"project_name"`
+	EnvName       string `json:"env_name"`
+	Production    bool   `json:"production"`
+	ClusterID     string `json:"cluster_id"`
+	Namespace     string `json:"namespace"`
+	ServiceName   string `json:"service_name"`
+	ServiceModule string `json:"service_module"`
+}
+
+// TraceImage looks up the build tasks and the source commits producing the image, and the envs it is deployed to. The
+// image is either registry/repo:tag, registry/repo@digest or the digest only, digests are resolved to the images by
+// the delivery artifacts. The builds are taken from the job outputs, so the tasks in the cold storage are included.
+func TraceImage(image string, log *zap.SugaredLogger) (*ImageTrace, error) {
+	image = strings.TrimSpace(image)
+	resp := &ImageTrace{
+		Image:       image,
+		Builds:      make([]*ImageTraceBuild, 0),
+		Commits:     make([]*commonmodels.ActivityCommit, 0),
+		Deployments: make([]*ImageDeployment, 0),
+	}
+
+	if digest := imageDigest(image); digest != "" {
+		resp.Digest = digest
+		artifact, err := commonrepo.NewDeliveryArtifactColl().Get(&commonrepo.DeliveryArtifactArgs{ImageDigest: digest})
+		if err != nil {
+			log.Errorf("failed to find the artifact of image digest %s, error: %v", digest, err)
+			return nil, e.ErrTraceImage.AddDesc("no image is found by the digest")
+		}
+		resp.Image = artifact.Image
+		resp.Artifact = artifact
+	} else if artifact, err := commonrepo.NewDeliveryArtifactColl().Get(&commonrepo.DeliveryArtifactArgs{Image: image}); err == nil {
+		resp.Artifact = artifact
+		resp.Digest = artifact.ImageDigest
+	}
+
+	if resp.Artifact != nil {
+		activities, _, err := commonrepo.NewDeliveryActivityColl().List(&commonrepo.DeliveryActivityArgs{ArtifactID: resp.Artifact.ID.Hex()})
+		if err != nil {
+			log.Errorf("failed to list the activities of artifact %s, error: %v", resp.Artifact.ID.Hex(), err)
+			return nil, e.ErrTraceImage.AddErr(err)
+		}
+		for _, activity := range activities {
+			if activity.Type == setting.BuildType {
+				resp.Commits = append(resp.Commits, activity.Commits...)
+			}
+		}
+	}
+
+	outputs, _, err := commonrepo.NewJobOutputColl().List(context.TODO(), &commonrepo.JobOutputListOption{
+		Name:  imageOutputName,
+		Value: resp.Image,
+	})
+	if err != nil {
+		log.Errorf("failed to list the build jobs of image %s, error: %v", resp.Image, err)
+		return nil, e.ErrTraceImage.AddErr(err)
+	}
+	for _, output := range outputs {
+		resp.Builds = append(resp.Builds, &ImageTraceBuild{
+			ProjectName:         output.ProjectName,
+			WorkflowName:        output.WorkflowName,
+			WorkflowDisplayName: output.WorkflowDisplayName,
+			TaskID:              output.TaskID,
+			TaskStatus:          output.TaskStatus,
+			JobName:             output.JobName,
+			ServiceName:         output.ServiceName,
+			ServiceModule:       output.ServiceModule,
+			CreateTime:          output.CreateTime,
+		})
+	}
+
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{})
+	if err != nil {
+		log.Errorf("failed to list envs, error: %v", err)
+		return nil, e.ErrTraceImage.AddErr(err)
+	}
+	for _, env := range envs {
+		for _, svc := range env.GetServiceMap() {
+			for _, container := range svc.Containers {
+				if container.Image != resp.Image {
+					continue
+				}
+				resp.Deployments = append(resp.Deployments, &ImageDeployment{
+					ProjectName:   env.ProductName,
+					EnvName:       env.EnvName,
+					Production:    env.Production,
+					ClusterID:     env.ClusterID,
+					Namespace:     env.Namespace,
+					ServiceName:   svc.ServiceName,
+					ServiceModule: container.Name,
+				})
+			}
+		}
+	}
+	return resp, nil
+}
+
+// imageDigest returns the digest of the image if the image is referenced by digest
+func imageDigest(image string) string {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	if strings.HasPrefix(image, "sha256:") {
+		return image
+	}
+	return ""
+}
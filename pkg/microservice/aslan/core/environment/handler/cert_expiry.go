@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Certificate Expiry
+// @Description List the TLS certificates in the namespaces of the project's environments sorted by expiry time
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string								true	"project name"
+// @Param 	production		query		bool								false	"is production env"
+// @Param 	days			query		int									false	"only list the certs expiring within the days"
+// @Success 200 			{array} 	service.CertExpiryInfo
+// @Router /api/aslan/environment/certificates/expiry [get]
+func ListCertExpiry(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can't be empty")
+		return
+	}
+	production := c.Query("production") == "true"
+
+	days := 0
+	if c.Query("days") != "" {
+		days, err = strconv.Atoi(c.Query("days"))
+		if err != nil {
+			ctx.Err = e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid days: %s", err))
+			return
+		}
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		projectInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+		if !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if production {
+			if !projectInfo.IsProjectAdmin && !projectInfo.ProductionEnv.View {
+				ctx.UnAuthorized = true
+				return
+			}
+		} else {
+			if !projectInfo.IsProjectAdmin && !projectInfo.Env.View {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.ListCertExpiry(projectKey, &production, days, ctx.Logger)
+}
+
+// @Summary Get Certificate Expiry Monitor
+// @Description Get the certificate expiry monitor setting of the project
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string								true	"project name"
+// @Success 200 			{object} 	commonmodels.CertExpiryMonitor
+// @Router /api/aslan/environment/certificates/monitor [get]
+func GetCertExpiryMonitor(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can't be empty")
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		projectInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+		if !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if !projectInfo.IsProjectAdmin && !projectInfo.Env.View && !projectInfo.ProductionEnv.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.GetCertExpiryMonitor(projectKey)
+}
+
+// @Summary Update Certificate Expiry Monitor
+// @Description Update the certificate expiry monitor setting of the project
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string								true	"project name"
+// @Param 	body 			body 		commonmodels.CertExpiryMonitor 		true 	"body"
+// @Success 200
+// @Router /api/aslan/environment/certificates/monitor [put]
+func UpdateCertExpiryMonitor(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can't be empty")
+		return
+	}
+
+	args := new(commonmodels.CertExpiryMonitor)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "更新", "证书过期监控", projectKey, "", ctx.Logger)
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if projectInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = service.UpdateCertExpiryMonitor(projectKey, ctx.UserName, args)
+}
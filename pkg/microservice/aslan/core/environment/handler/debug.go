@@ -25,6 +25,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 	"github.com/koderover/zadig/v2/pkg/types"
 )
 
@@ -83,3 +84,69 @@ func PatchDebugContainer(c *gin.Context) {
 
 	ctx.Err = service.PatchDebugContainer(c, projectKey, envName, podName, debugImage, production)
 }
+
+// @Summary Run Network Check
+// @Description Run a dns/tcp/http connectivity check from inside the namespace of the environment
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	env				path		string								true	"env name"
+// @Param 	projectName		query		string								true	"project name"
+// @Param 	production		query		bool								false	"is production env"
+// @Param 	body 			body 		service.NetworkCheckArgs 			true 	"body"
+// @Success 200 			{object} 	service.NetworkCheckResult
+// @Router /api/aslan/environment/kube/{env}/network/check [post]
+func RunNetworkCheck(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	envName := c.Param("env")
+	projectKey := c.Query("projectName")
+	production := c.Query("production") == "true"
+
+	args := new(service.NetworkCheckArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if production {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].ProductionEnv.DebugPod {
+				permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, types.ProductionEnvActionDebug)
+				if err != nil || !permitted {
+					ctx.UnAuthorized = true
+					return
+				}
+			}
+
+			err = commonutil.CheckZadigProfessionalLicense()
+			if err != nil {
+				ctx.Err = err
+				return
+			}
+		} else {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].Env.DebugPod {
+				permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, types.EnvActionDebug)
+				if err != nil || !permitted {
+					ctx.UnAuthorized = true
+					return
+				}
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.RunNetworkCheck(c, projectKey, envName, production, args, ctx.Logger)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// @Summary List Environment Deploy Ledger
+// @Description List who deployed what to the environment in the time range
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Param 	startTime		query		int								false	"start time"
+// @Param 	endTime			query		int								false	"end time"
+// @Success 200 			{array}  	service.DeployLedgerEntry
+// @Router /api/aslan/environment/environments/{name}/deployLedger [get]
+func ListDeployLedger(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.DeployLedgerArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	envName := c.Param("name")
+
+	if !checkDeployLedgerPermission(ctx, args.ProjectName, envName, args.Production) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListDeployLedger(envName, args, ctx.Logger)
+}
+
+// @Summary Export Environment Deploy Ledger
+// @Description Export the deploy ledger of the environment in json or csv format
+// @Tags 	environment
+// @Accept 	json
+// @Produce octet-stream
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Param 	startTime		query		int								false	"start time"
+// @Param 	endTime			query		int								false	"end time"
+// @Param 	format			query		string							false	"json or csv, default json"
+// @Success 200
+// @Router /api/aslan/environment/environments/{name}/deployLedger/export [get]
+func ExportDeployLedger(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	args := new(service.DeployLedgerArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+	envName := c.Param("name")
+
+	if !checkDeployLedgerPermission(ctx, args.ProjectName, envName, args.Production) {
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	data, fileName, err := service.ExportDeployLedger(envName, args, c.Query("format"), ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, args.ProjectName, "导出", "环境部署记录", envName, "", ctx.Logger)
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, "application/octet-stream", data)
+}
+
+// checkDeployLedgerPermission requires the view permission of the environment, it sets ctx.UnAuthorized or ctx.Err if not permitted
+func checkDeployLedgerPermission(ctx *internalhandler.Context, projectKey, envName string, production bool) bool {
+	if envName == "" || projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("empty env name or project name")
+		return false
+	}
+	if ctx.Resources.IsSystemAdmin {
+		return true
+	}
+	if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+		ctx.UnAuthorized = true
+		return false
+	}
+
+	if production {
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].ProductionEnv.View {
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, types.ProductionEnvActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return false
+			}
+		}
+
+		if err := commonutil.CheckZadigProfessionalLicense(); err != nil {
+			ctx.Err = err
+			return false
+		}
+		return true
+	}
+
+	if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+		!ctx.Resources.ProjectAuthInfo[projectKey].Env.View {
+		permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, types.EnvActionView)
+		if err != nil || !permitted {
+			ctx.UnAuthorized = true
+			return false
+		}
+	}
+	return true
+}
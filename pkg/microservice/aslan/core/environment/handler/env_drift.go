@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Environment Drift Records
+// @Description List the drift records between the live state and the rendered manifests of the environment
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Param 	page			query		int								false	"page"
+// @Param 	pageSize		query		int								false	"page size"
+// @Success 200 			{object}  	service.ListEnvDriftRecordsResp
+// @Router /api/aslan/environment/environments/{name}/drifts [get]
+func ListEnvDriftRecords(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, false) {
+		return
+	}
+
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid page")
+		return
+	}
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("pageSize", "20"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid pageSize")
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListEnvDriftRecords(projectKey, envName, production, page, pageSize)
+}
+
+// @Summary Detect Environment Drift
+// @Description Compare the live state of the environment with the rendered manifests immediately, empty response means no drift
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Success 200 			{object}  	commonmodels.EnvDriftRecord
+// @Router /api/aslan/environment/environments/{name}/drifts/detect [post]
+func DetectEnvDrift(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, false) {
+		return
+	}
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "检测", "环境-配置漂移", envName, "", ctx.Logger, envName)
+
+	ctx.Resp, ctx.Err = service.DetectEnvDrift(projectKey, envName, production, ctx.Logger)
+}
+
+// @Summary Get Environment Drift Monitor
+// @Description Get the drift detection setting of the project
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string								true	"project name"
+// @Success 200 			{object} 	commonmodels.EnvDriftMonitor
+// @Router /api/aslan/environment/drift/monitor [get]
+func GetEnvDriftMonitor(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can't be empty")
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		projectInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+		if !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if !projectInfo.IsProjectAdmin && !projectInfo.Env.View && !projectInfo.ProductionEnv.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.GetEnvDriftMonitor(projectKey)
+}
+
+// @Summary Update Environment Drift Monitor
+// @Description Update the drift detection setting of the project
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string								true	"project name"
+// @Param 	body 			body 		commonmodels.EnvDriftMonitor 		true 	"body"
+// @Success 200
+// @Router /api/aslan/environment/drift/monitor [put]
+func UpdateEnvDriftMonitor(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can't be empty")
+		return
+	}
+
+	args := new(commonmodels.EnvDriftMonitor)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "更新", "环境配置漂移检测", projectKey, "", ctx.Logger)
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if projectInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = service.UpdateEnvDriftMonitor(projectKey, ctx.UserName, args)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// checkEnvConfigPermission checks the view or edit config permission of the env, the result is set in ctx
+func checkEnvConfigPermission(ctx *internalhandler.Context, projectKey, envName string, production, edit bool) bool {
+	if envName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("empty name")
+		return false
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		authInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+		if !ok {
+			ctx.UnAuthorized = true
+			return false
+		}
+
+		if !authInfo.IsProjectAdmin {
+			var permitted bool
+			var action string
+			switch {
+			case production && edit:
+				permitted, action = authInfo.ProductionEnv.EditConfig, types.ProductionEnvActionEditConfig
+			case production:
+				permitted, action = authInfo.ProductionEnv.View, types.ProductionEnvActionView
+			case edit:
+				permitted, action = authInfo.Env.EditConfig, types.EnvActionEditConfig
+			default:
+				permitted, action = authInfo.Env.View, types.EnvActionView
+			}
+			if !permitted {
+				permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, action)
+				if err != nil || !permitted {
+					ctx.UnAuthorized = true
+					return false
+				}
+			}
+		}
+	}
+
+	if production {
+		if err := commonutil.CheckZadigProfessionalLicense(); err != nil {
+			ctx.Err = err
+			return false
+		}
+	}
+	return true
+}
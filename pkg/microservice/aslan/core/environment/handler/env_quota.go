@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Get Environment Quota
+// @Description Get the ResourceQuota, LimitRange and the cluster capacity of the environment's namespace
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Success 200 			{object}  	service.EnvQuota
+// @Router /api/aslan/environment/environments/{name}/quota [get]
+func GetEnvQuota(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, false) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetEnvQuota(projectKey, envName, production, ctx.Logger)
+}
+
+// @Summary Update Environment Quota
+// @Description Set the ResourceQuota and LimitRange of the environment's namespace, only project admins are allowed
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Param 	body 			body 		service.UpdateEnvQuotaArgs 		true 	"body"
+// @Success 200
+// @Router /api/aslan/environment/environments/{name}/quota [put]
+func UpdateEnvQuota(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if envName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("empty name")
+		return
+	}
+
+	args := new(service.UpdateEnvQuotaArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "更新", "环境-资源配额", envName, "", ctx.Logger, envName)
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if projectInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	if production {
+		if err := commonutil.CheckZadigProfessionalLicense(); err != nil {
+			ctx.Err = err
+			return
+		}
+	}
+
+	ctx.Err = service.UpdateEnvQuota(projectKey, envName, production, args, ctx.Logger)
+}
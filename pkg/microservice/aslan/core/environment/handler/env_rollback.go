@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Rollback Environment Services
+// @Description Roll the services back to the versions deployed when a previous workflow task finished, or to the versions in an env snapshot
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string									true	"env name"
+// @Param 	projectName		query		string									true	"project name"
+// @Param 	production		query		bool									false	"is production env"
+// @Param 	body 			body 		service.RollbackEnvServicesArgs 		true 	"body"
+// @Success 200 			{object}  	service.RollbackEnvServicesResp
+// @Router /api/aslan/environment/environments/{name}/rollback [post]
+func RollbackEnvServices(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, true) {
+		return
+	}
+
+	args := new(service.RollbackEnvServicesArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "回滚", "环境-服务", fmt.Sprintf("环境: %s, 服务: %s", envName, strings.Join(args.Services, ",")), "", ctx.Logger, envName)
+
+	ctx.Resp, ctx.Err = service.RollbackEnvServices(ctx, projectKey, envName, production, args, ctx.Logger)
+}
+
+type listEnvRollbackRecordsQuery struct {
+	ProjectName string `form:"projectName"`
+	Production  bool   `form:"production"`
+	ServiceName string `form:"serviceName"`
+	PageNum     int64  `form:"pageNum"`
+	PageSize    int64  `form:"pageSize"`
+}
+
+// @Summary List Environment Rollback Records
+// @Description List the rollbacks of the environment triggered by workflow jobs and the api
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string									true	"env name"
+// @Param 	projectName		query		string									true	"project name"
+// @Param 	production		query		bool									false	"is production env"
+// @Param 	serviceName		query		string									false	"service name"
+// @Param 	pageNum			query		int										false	"page num"
+// @Param 	pageSize		query		int										false	"page size"
+// @Success 200 			{object}  	service.ListEnvRollbackRecordsResp
+// @Router /api/aslan/environment/environments/{name}/rollbacks [get]
+func ListEnvRollbackRecords(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	query := new(listEnvRollbackRecordsQuery)
+	if err := c.ShouldBindQuery(query); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	envName := c.Param("name")
+	if !checkEnvConfigPermission(ctx, query.ProjectName, envName, query.Production, false) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListEnvRollbackRecords(query.ProjectName, envName, query.Production, query.ServiceName, query.PageNum, query.PageSize, ctx.Logger)
+}
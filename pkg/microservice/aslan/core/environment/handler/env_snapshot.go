@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Environment Snapshots
+// @Description List the snapshots of the environment, the snapshot content is not returned
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Success 200 			{array}  	models.EnvSnapshot
+// @Router /api/aslan/environment/environments/{name}/snapshots [get]
+func ListEnvSnapshots(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, false) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListEnvSnapshots(projectKey, envName, production, ctx.Logger)
+}
+
+// @Summary Get Environment Snapshot
+// @Description Get the environment snapshot with the service renders, values, images and global variables
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	id				path		string							true	"snapshot id"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Success 200 			{object}  	models.EnvSnapshot
+// @Router /api/aslan/environment/environments/{name}/snapshots/{id} [get]
+func GetEnvSnapshot(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, false) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetEnvSnapshot(projectKey, envName, production, c.Param("id"))
+}
+
+// @Summary Create Environment Snapshot
+// @Description Capture the current state of the environment
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Param 	body 			body 		service.CreateEnvSnapshotArgs 	true 	"body"
+// @Success 200 			{object}  	models.EnvSnapshot
+// @Router /api/aslan/environment/environments/{name}/snapshots [post]
+func CreateEnvSnapshot(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, true) {
+		return
+	}
+
+	args := new(service.CreateEnvSnapshotArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "新增", "环境-快照", envName, "", ctx.Logger, envName)
+
+	ctx.Resp, ctx.Err = service.CreateEnvSnapshot(projectKey, envName, production, args, ctx.UserName, ctx.Logger)
+}
+
+// @Summary Delete Environment Snapshot
+// @Description Delete Environment Snapshot
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	id				path		string							true	"snapshot id"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Success 200
+// @Router /api/aslan/environment/environments/{name}/snapshots/{id} [delete]
+func DeleteEnvSnapshot(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, true) {
+		return
+	}
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "删除", "环境-快照", fmt.Sprintf("环境: %s, 快照: %s", envName, c.Param("id")), "", ctx.Logger, envName)
+
+	ctx.Err = service.DeleteEnvSnapshot(projectKey, envName, production, c.Param("id"))
+}
+
+// @Summary Restore Environment Snapshot
+// @Description Restore the environment to the state recorded in the snapshot, the current state is saved as a new snapshot first
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	id				path		string							true	"snapshot id"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Success 200 			{object}  	service.RestoreEnvSnapshotResp
+// @Router /api/aslan/environment/environments/{name}/snapshots/{id}/restore [post]
+func RestoreEnvSnapshot(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, true) {
+		return
+	}
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "恢复", "环境-快照", fmt.Sprintf("环境: %s, 快照: %s", envName, c.Param("id")), "", ctx.Logger, envName)
+
+	ctx.Resp, ctx.Err = service.RestoreEnvSnapshot(ctx, projectKey, envName, production, c.Param("id"), ctx.Logger)
+}
@@ -118,11 +118,16 @@ func ListProducts(c *gin.Context) {
 		return
 	}
 
+	var envs []*service.EnvResp
 	if production {
-		ctx.Resp, ctx.Err = service.ListProductionEnvs(ctx.UserID, projectName, envFilter, ctx.Logger)
+		envs, err = service.ListProductionEnvs(ctx.UserID, projectName, envFilter, ctx.Logger)
 	} else {
-		ctx.Resp, ctx.Err = service.ListProducts(ctx.UserID, projectName, envFilter, false, ctx.Logger)
+		envs, err = service.ListProducts(ctx.UserID, projectName, envFilter, false, ctx.Logger)
 	}
+	if err == nil && c.Query("labelSelector") != "" {
+		envs, err = service.FilterEnvsByLabelSelector(projectName, c.Query("labelSelector"), envs)
+	}
+	ctx.Resp, ctx.Err = envs, err
 }
 
 // @Summary Update Multi products
@@ -329,6 +334,18 @@ func CreateProduct(c *gin.Context) {
 			}
 		}
 
+		for _, arg := range createArgs {
+			arg.Owner, err = commonservice.EnsureResourceOwner(arg.Owner, ctx.UserID)
+			if err != nil {
+				ctx.Err = err
+				return
+			}
+			if err = arg.TTL.Validate(createParam.ProjectName, arg.Production); err != nil {
+				ctx.Err = err
+				return
+			}
+		}
+
 		if createParam.Scene == "copy" {
 			copyProduct(c, createParam, createArgs, string(data), ctx)
 		} else {
@@ -361,6 +378,12 @@ func CreateProduct(c *gin.Context) {
 			return
 		}
 
+		args.Owner, err = commonservice.EnsureResourceOwner(args.Owner, ctx.UserID)
+		if err != nil {
+			ctx.Err = err
+			return
+		}
+
 		ctx.Err = service.CreateProduct(ctx.UserName, ctx.RequestID, &service.ProductCreateArg{Product: args}, ctx.Logger)
 	}
 }
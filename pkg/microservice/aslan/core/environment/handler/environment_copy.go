@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// @Summary Copy Environment To Another Project
+// @Description Copy the services, variables and values of the environment into another project which shares the same service templates
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string							true	"source project name"
+// @Param 	name			path		string							true	"source env name"
+// @Param 	body 			body 		service.CopyEnvToProjectArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/environment/environments/{name}/copy-to-project [post]
+func CopyEnvToProject(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	envName := c.Param("name")
+	projectKey := c.Query("projectName")
+
+	args := new(service.CopyEnvToProjectArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	// authorization checks, the env should be visible in the source project and creatable in the target project
+	if !ctx.Resources.IsSystemAdmin {
+		sourceAuth, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+		if !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if !sourceAuth.IsProjectAdmin && !sourceAuth.Env.View {
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, types.EnvActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+		targetAuth, ok := ctx.Resources.ProjectAuthInfo[args.TargetProject]
+		if !ok || (!targetAuth.IsProjectAdmin && !targetAuth.Env.Create) {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	args.Owner, err = commonservice.EnsureResourceOwner(args.Owner, ctx.UserID)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, args.TargetProject, setting.OperationSceneEnv, "复制", "环境",
+		fmt.Sprintf("%s/%s-->%s/%s", projectKey, envName, args.TargetProject, args.EnvName), "", ctx.Logger, args.EnvName)
+
+	ctx.Err = service.CopyEnvToProject(projectKey, envName, ctx.UserName, ctx.RequestID, args, ctx.Logger)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+)
+
+// @Summary List First Deploy Hook Records
+// @Description List the runs of the first deploy hooks of the services in the environment
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string									true	"env name"
+// @Param 	projectName		query		string									true	"project name"
+// @Param 	production		query		bool									false	"is production env"
+// @Success 200 			{array}  	models.FirstDeployHookRecord
+// @Router /api/aslan/environment/environments/{name}/firstDeployHooks [get]
+func ListFirstDeployHookRecords(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	envName := c.Param("name")
+	production := c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, false) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListFirstDeployHookRecords(projectKey, envName, ctx.Logger)
+}
@@ -285,6 +285,15 @@ type OpenAPIUpdateContainerImageArgs struct {
 	Image         string `json:"image"`
 }
 
+// @Summary OpenAPI Update Deployment Container Image
+// @Description OpenAPI Update Deployment Container Image
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	envName	path	string	true	"env name"
+// @Param 	body	body	OpenAPIUpdateContainerImageArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/image/deployment/{envName} [post]
 func OpenAPIUpdateDeploymentContainerImage(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -361,6 +370,15 @@ func OpenAPIUpdateDeploymentContainerImage(c *gin.Context) {
 	ctx.Err = service.UpdateContainerImage(ctx.RequestID, ctx.UserName, origArgs, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Stateful Set Container Image
+// @Description OpenAPI Update Stateful Set Container Image
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	envName	path	string	true	"env name"
+// @Param 	body	body	OpenAPIUpdateContainerImageArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/image/statefulset/{envName} [post]
 func OpenAPIUpdateStatefulSetContainerImage(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -426,6 +444,15 @@ func OpenAPIUpdateStatefulSetContainerImage(c *gin.Context) {
 	ctx.Err = service.UpdateContainerImage(ctx.RequestID, ctx.UserName, origArgs, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Cron Job Container Image
+// @Description OpenAPI Update Cron Job Container Image
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	envName	path	string	true	"env name"
+// @Param 	body	body	OpenAPIUpdateContainerImageArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/image/cronjob/{envName} [post]
 func OpenAPIUpdateCronJobContainerImage(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -836,6 +836,17 @@ type OpenAPIListKubeEventResponse struct {
 	Type string `json:"type,omitempty"`
 }
 
+// @Summary OpenAPI List Kube Events
+// @Description OpenAPI List Kube Events
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	envName	query	string	false	"env name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	name	query	string	false	"name"
+// @Param 	type	query	string	false	"type"
+// @Success 200 	{array} 	OpenAPIListKubeEventResponse
+// @Router /openapi/environments/kube/events [get]
 func OpenAPIListKubeEvents(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
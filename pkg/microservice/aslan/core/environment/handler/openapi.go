@@ -53,6 +53,14 @@ func generalOpenAPIRequestValidate(c *gin.Context) (string, string, error) {
 	return projectName, envName, nil
 }
 
+// @Summary OpenAPI Scale Workloads
+// @Description OpenAPI Scale Workloads
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	service.OpenAPIScaleServiceReq	true	"body"
+// @Success 200
+// @Router /openapi/environments/scale [post]
 func OpenAPIScaleWorkloads(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -112,6 +120,15 @@ func OpenAPIScaleWorkloads(c *gin.Context) {
 	ctx.Err = service.OpenAPIScale(req, ctx.Logger)
 }
 
+// @Summary OpenAPI Apply Yaml Service
+// @Description OpenAPI Apply Yaml Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIApplyYamlServiceReq	true	"body"
+// @Success 200
+// @Router /openapi/environments/service/yaml [post]
 func OpenAPIApplyYamlService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -180,6 +197,15 @@ func OpenAPIApplyYamlService(c *gin.Context) {
 	ctx.Err = err
 }
 
+// @Summary OpenAPI Delete Yaml Service From Env
+// @Description OpenAPI Delete Yaml Service From Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIDeleteYamlServiceFromEnvReq	true	"body"
+// @Success 200
+// @Router /openapi/environments/service/yaml [delete]
 func OpenAPIDeleteYamlServiceFromEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -248,6 +274,15 @@ func OpenAPIDeleteYamlServiceFromEnv(c *gin.Context) {
 	ctx.Err = service.DeleteProductServices(ctx.UserName, ctx.RequestID, req.EnvName, projectKey, req.ServiceNames, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Production Yaml Service From Env
+// @Description OpenAPI Delete Production Yaml Service From Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIDeleteYamlServiceFromEnvReq	true	"body"
+// @Success 200
+// @Router /openapi/environments/production/service/yaml [delete]
 func OpenAPIDeleteProductionYamlServiceFromEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -303,6 +338,15 @@ func OpenAPIDeleteProductionYamlServiceFromEnv(c *gin.Context) {
 	ctx.Err = service.DeleteProductServices(ctx.UserName, ctx.RequestID, req.EnvName, projectKey, req.ServiceNames, true, ctx.Logger)
 }
 
+// @Summary OpenAPI Apply Production Yaml Service
+// @Description OpenAPI Apply Production Yaml Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIApplyYamlServiceReq	true	"body"
+// @Success 200
+// @Router /openapi/environments/production/service/yaml [post]
 func OpenAPIApplyProductionYamlService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -372,6 +416,15 @@ func OpenAPIApplyProductionYamlService(c *gin.Context) {
 	ctx.Err = err
 }
 
+// @Summary OpenAPI Update Common Env Cfg
+// @Description OpenAPI Update Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIEnvCfgArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/envcfgs [put]
 func OpenAPIUpdateCommonEnvCfg(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -421,6 +474,15 @@ func OpenAPIUpdateCommonEnvCfg(c *gin.Context) {
 	ctx.Err = service.OpenAPIUpdateCommonEnvCfg(projectKey, args, ctx.UserName, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Production Common Env Cfg
+// @Description OpenAPI Update Production Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIEnvCfgArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/production/envcfgs [put]
 func OpenAPIUpdateProductionCommonEnvCfg(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -479,6 +541,17 @@ func OpenAPIUpdateProductionCommonEnvCfg(c *gin.Context) {
 	ctx.Err = service.OpenAPIUpdateCommonEnvCfg(projectKey, args, ctx.UserName, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Common Env Cfg
+// @Description OpenAPI Create Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIEnvCfgArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/production/{name}/envcfgs [post]
+// @Router /openapi/environments/{name}/envcfgs [post]
 func OpenAPICreateCommonEnvCfg(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -540,6 +613,16 @@ func OpenAPICreateCommonEnvCfg(c *gin.Context) {
 	ctx.Err = service.OpenAPICreateCommonEnvCfg(args.ProductName, args, ctx.UserName, ctx.Logger)
 }
 
+// @Summary OpenAPI List Production Common Env Cfg
+// @Description OpenAPI List Production Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	type	query	string	false	"type"
+// @Success 200 	{array} 	service.OpenAPIEnvCfgBrief
+// @Router /openapi/environments/production/{name}/envcfgs [get]
 func OpenAPIListProductionCommonEnvCfg(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -559,6 +642,17 @@ func OpenAPIListProductionCommonEnvCfg(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIListCommonEnvCfg(projectName, envName, c.Query("type"), true, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Production Common Env Cfg
+// @Description OpenAPI Get Production Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	cfgName	path	string	true	"cfg name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	type	query	string	false	"type"
+// @Success 200 	{object} 	service.OpenAPIEnvCfgDetail
+// @Router /openapi/environments/production/{name}/envcfg/{cfgName} [get]
 func OpenAPIGetProductionCommonEnvCfg(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -583,6 +677,16 @@ func OpenAPIGetProductionCommonEnvCfg(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIGetCommonEnvCfg(projectName, envName, c.Query("type"), cfgName, true, ctx.Logger)
 }
 
+// @Summary OpenAPI List Common Env Cfg
+// @Description OpenAPI List Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	type	query	string	false	"type"
+// @Success 200 	{array} 	service.OpenAPIEnvCfgBrief
+// @Router /openapi/environments/{name}/envcfgs [get]
 func OpenAPIListCommonEnvCfg(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -596,6 +700,17 @@ func OpenAPIListCommonEnvCfg(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIListCommonEnvCfg(projectName, envName, c.Query("type"), false, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Common Env Cfg
+// @Description OpenAPI Get Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	cfgName	path	string	true	"cfg name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	type	query	string	false	"type"
+// @Success 200 	{object} 	service.OpenAPIEnvCfgDetail
+// @Router /openapi/environments/{name}/envcfg/{cfgName} [get]
 func OpenAPIGetCommonEnvCfg(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -614,6 +729,17 @@ func OpenAPIGetCommonEnvCfg(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIGetCommonEnvCfg(projectName, envName, c.Query("type"), cfgName, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Common Env Cfg
+// @Description OpenAPI Delete Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	cfgName	path	string	true	"cfg name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	type	query	string	false	"type"
+// @Success 200
+// @Router /openapi/environments/{name}/envcfg/{cfgName} [delete]
 func OpenAPIDeleteCommonEnvCfg(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -657,6 +783,17 @@ func OpenAPIDeleteCommonEnvCfg(c *gin.Context) {
 	ctx.Err = service.OpenAPIDeleteCommonEnvCfg(projectName, envName, cfgType, cfgName, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Production Env Common Env Cfg
+// @Description OpenAPI Delete Production Env Common Env Cfg
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	cfgName	path	string	true	"cfg name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	type	query	string	false	"type"
+// @Success 200
+// @Router /openapi/environments/production/{name}/envcfg/{cfgName} [delete]
 func OpenAPIDeleteProductionEnvCommonEnvCfg(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -706,6 +843,15 @@ func OpenAPIDeleteProductionEnvCommonEnvCfg(c *gin.Context) {
 	ctx.Err = service.OpenAPIDeleteProductionEnvCommonEnvCfg(projectName, envName, cfgType, cfgName, ctx.Logger)
 }
 
+// @Summary OpenAPI Create K8s Env
+// @Description OpenAPI Create K8s Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPICreateEnvArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments [post]
 func OpenAPICreateK8sEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -751,6 +897,15 @@ func OpenAPICreateK8sEnv(c *gin.Context) {
 	ctx.Err = service.OpenAPICreateK8sEnv(args, ctx.UserName, ctx.RequestID, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Production Env
+// @Description OpenAPI Delete Production Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/environments/production/{name} [delete]
 func OpenAPIDeleteProductionEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -790,6 +945,15 @@ func OpenAPIDeleteProductionEnv(c *gin.Context) {
 	ctx.Err = service.DeleteProductionProduct(ctx.UserName, envName, projectName, ctx.RequestID, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Production Env
+// @Description OpenAPI Create Production Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPICreateEnvArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/production [post]
 func OpenAPICreateProductionEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -842,6 +1006,16 @@ func OpenAPICreateProductionEnv(c *gin.Context) {
 	ctx.Err = service.OpenAPICreateProductionEnv(args, ctx.UserName, ctx.RequestID, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Env
+// @Description OpenAPI Delete Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	isDelete	query	string	false	"is delete"
+// @Success 200
+// @Router /openapi/environments/{name} [delete]
 func OpenAPIDeleteEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -880,6 +1054,15 @@ func OpenAPIDeleteEnv(c *gin.Context) {
 	ctx.Err = service.DeleteProduct(ctx.UserName, envName, projectName, ctx.RequestID, isDelete, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Env Detail
+// @Description OpenAPI Get Env Detail
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	service.OpenAPIEnvDetail
+// @Router /openapi/environments/{name} [get]
 func OpenAPIGetEnvDetail(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -893,6 +1076,15 @@ func OpenAPIGetEnvDetail(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.GetEnvDetail(projectName, envName, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Production Env Detail
+// @Description OpenAPI Get Production Env Detail
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	service.OpenAPIEnvDetail
+// @Router /openapi/environments/production/{name} [get]
 func OpenAPIGetProductionEnvDetail(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -912,6 +1104,16 @@ func OpenAPIGetProductionEnvDetail(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.GetEnvDetail(projectName, envName, true, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Env Basic Info
+// @Description OpenAPI Update Env Basic Info
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.EnvBasicInfoArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/{name} [put]
 func OpenAPIUpdateEnvBasicInfo(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -937,6 +1139,16 @@ func OpenAPIUpdateEnvBasicInfo(c *gin.Context) {
 	ctx.Err = service.OpenAPIUpdateEnvBasicInfo(args, ctx.UserName, projectName, envName, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Yaml Services
+// @Description OpenAPI Update Yaml Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIServiceVariablesReq	true	"body"
+// @Success 200
+// @Router /openapi/environments/{name}/services [put]
 func OpenAPIUpdateYamlServices(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -981,6 +1193,15 @@ func OpenAPIUpdateYamlServices(c *gin.Context) {
 	ctx.Err = service.OpenAPIUpdateYamlService(args, ctx.UserName, ctx.RequestID, projectName, envName, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Env Global Variables
+// @Description OpenAPI Get Env Global Variables
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	types.GlobalVariableKV
+// @Router /openapi/environments/{name}/variable [get]
 func OpenAPIGetEnvGlobalVariables(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -994,6 +1215,15 @@ func OpenAPIGetEnvGlobalVariables(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIGetGlobalVariables(projectName, envName, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Production Env Global Variables
+// @Description OpenAPI Get Production Env Global Variables
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	types.GlobalVariableKV
+// @Router /openapi/environments/production/{name}/variable [get]
 func OpenAPIGetProductionEnvGlobalVariables(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1058,6 +1288,16 @@ func OpenAPIUpdateGlobalVariables(c *gin.Context) {
 	ctx.Err = service.UpdateProductGlobalVariables(projectName, envName, ctx.UserName, ctx.RequestID, env.UpdateTime, args.GlobalVariables, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Production Yaml Services
+// @Description OpenAPI Update Production Yaml Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.OpenAPIServiceVariablesReq	true	"body"
+// @Success 200
+// @Router /openapi/environments/production/{name}/services [put]
 func OpenAPIUpdateProductionYamlServices(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1178,6 +1418,16 @@ func OpenAPIUpdateProductionGlobalVariables(c *gin.Context) {
 	ctx.Err = service.UpdateProductGlobalVariables(projectName, envName, ctx.UserName, ctx.RequestID, env.UpdateTime, args.GlobalVariables, true, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Production Env Basic Info
+// @Description OpenAPI Update Production Env Basic Info
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	service.EnvBasicInfoArgs	true	"body"
+// @Success 200
+// @Router /openapi/environments/production/{name} [put]
 func OpenAPIUpdateProductionEnvBasicInfo(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1227,6 +1477,14 @@ func OpenAPIUpdateProductionEnvBasicInfo(c *gin.Context) {
 	ctx.Err = service.OpenAPIUpdateEnvBasicInfo(args, ctx.UserName, projectName, envName, true, ctx.Logger)
 }
 
+// @Summary OpenAPI List Envs
+// @Description OpenAPI List Envs
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	service.OpenAPIListEnvBrief
+// @Router /openapi/environments [get]
 func OpenAPIListEnvs(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1271,6 +1529,14 @@ func OpenAPIListEnvs(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIListEnvs(ctx.UserID, projectKey, envFilter, false, ctx.Logger)
 }
 
+// @Summary OpenAPI List Production Envs
+// @Description OpenAPI List Production Envs
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	service.OpenAPIListEnvBrief
+// @Router /openapi/environments/production [get]
 func OpenAPIListProductionEnvs(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1321,6 +1587,16 @@ func OpenAPIListProductionEnvs(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIListProductionEnvs(ctx.UserID, projectKey, envFilter, ctx.Logger)
 }
 
+// @Summary OpenAPI Restart Service
+// @Description OpenAPI Restart Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	serviceName	path	string	true	"service name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/environments/{name}/service/{serviceName}/restart [post]
 func OpenAPIRestartService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1362,6 +1638,16 @@ func OpenAPIRestartService(c *gin.Context) {
 	ctx.Err = service.OpenAPIRestartService(projectName, envName, serviceName, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Production Restart Service
+// @Description OpenAPI Production Restart Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	serviceName	path	string	true	"service name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/environments/production/{name}/service/{serviceName}/restart [post]
 func OpenAPIProductionRestartService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1409,6 +1695,15 @@ func OpenAPIProductionRestartService(c *gin.Context) {
 	ctx.Err = service.OpenAPIRestartService(projectName, envName, serviceName, true, ctx.Logger)
 }
 
+// @Summary OpenAPI Check Workloads K8s Services
+// @Description OpenAPI Check Workloads K8s Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	string
+// @Router /openapi/environments/{name}/check/workloads/k8services [get]
 func OpenAPICheckWorkloadsK8sServices(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1444,6 +1739,15 @@ func OpenAPICheckWorkloadsK8sServices(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.CheckWorkloadsK8sServices(c, envName, projectKey, false)
 }
 
+// @Summary OpenAPI Enable Base Env
+// @Description OpenAPI Enable Base Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/environments/{name}/share/enable [post]
 func OpenAPIEnableBaseEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1487,6 +1791,15 @@ func OpenAPIEnableBaseEnv(c *gin.Context) {
 	ctx.Err = service.EnableBaseEnv(c, envName, projectKey)
 }
 
+// @Summary OpenAPI Disable Base Env
+// @Description OpenAPI Disable Base Env
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/environments/{name}/share/enable [delete]
 func OpenAPIDsiableBaseEnv(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1545,6 +1858,16 @@ type OpenAPIShareEnvReadyChecks struct {
 	WorkloadsHaveK8sService bool `json:"workloads_have_k8s_service"`
 }
 
+// @Summary OpenAPI Check Share Env Ready
+// @Description OpenAPI Check Share Env Ready
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	op	path	string	true	"op"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	OpenAPIShareEnvReadyResponse
+// @Router /openapi/environments/{name}/check/sharenv/{op}/ready [get]
 func OpenAPICheckShareEnvReady(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1603,6 +1926,16 @@ type OpenAPIGetPortalServiceResponse struct {
 	Servers               []OpenAPISetPortalServiceRequest `json:"servers"`
 }
 
+// @Summary OpenAPI Get Portal Service
+// @Description OpenAPI Get Portal Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	serviceName	path	string	true	"service name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/environments/{name}/share/portal/{serviceName} [get]
 func OpenAPIGetPortalService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -1668,6 +2001,17 @@ type OpenAPISetPortalServiceRequest struct {
 	PortProtocol string `json:"port_protocol"`
 }
 
+// @Summary OpenAPI Set Portal Service
+// @Description OpenAPI Set Portal Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	serviceName	path	string	true	"service name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	[]OpenAPISetPortalServiceRequest	true	"body"
+// @Success 200
+// @Router /openapi/environments/{name}/share/portal/{serviceName} [post]
 func OpenAPISetPortalService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -112,6 +112,7 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		kube.POST("/helm/releaseInstances", GetReleaseInstanceDeployStatus)
 
 		kube.POST("/:env/pods/:podName/debugcontainer", PatchDebugContainer)
+		kube.POST("/:env/network/check", RunNetworkCheck)
 
 		kube.GET("/pods/:podName/file", DownloadFileFromPod)
 		kube.GET("/namespace/cluster/:clusterID", ListNamespace)
@@ -132,6 +133,27 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		operations.GET("", GetOperationLogs)
 	}
 
+	certificates := router.Group("certificates")
+	{
+		certificates.GET("/expiry", ListCertExpiry)
+		certificates.GET("/monitor", GetCertExpiryMonitor)
+		certificates.PUT("/monitor", UpdateCertExpiryMonitor)
+	}
+
+	drift := router.Group("drift")
+	{
+		drift.GET("/monitor", GetEnvDriftMonitor)
+		drift.PUT("/monitor", UpdateEnvDriftMonitor)
+	}
+
+	hostnames := router.Group("hostnames")
+	{
+		hostnames.GET("", ListProjectHostnames)
+		hostnames.GET("/conflicts", CheckHostnameConflict)
+		hostnames.GET("/policy", GetHostnamePolicy)
+		hostnames.PUT("/policy", UpdateHostnamePolicy)
+	}
+
 	// ---------------------------------------------------------------------------------------
 	// 产品管理接口(环境)
 	// ---------------------------------------------------------------------------------------
@@ -146,6 +168,10 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		environments.GET("/:name", GetEnvironment)
 		environments.PUT("/:name/envRecycle", UpdateProductRecycleDay)
 		environments.PUT("/:name/alias", UpdateProductAlias)
+		environments.POST("/:name/copy-to-project", CopyEnvToProject)
+		environments.PUT("/:name/ttl", UpdateEnvTTL)
+		environments.POST("/:name/ttl/extension", RequestEnvTTLExtension)
+		environments.POST("/:name/ttl/extension/review", ReviewEnvTTLExtension)
 		environments.POST("/:name/affectedservices", AffectedServices)
 		environments.POST("/:name/estimated-values", EstimatedValues)
 
@@ -160,10 +186,18 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		environments.PUT("/:name/syncVariables", SyncHelmProductRenderset)
 		environments.DELETE("/:name", DeleteProduct)
 		environments.GET("/:name/groups", ListGroups)
+
+		environments.GET("/:name/servicerevisions", ListServiceRevisionPins)
+		environments.POST("/:name/servicerevisions/pin", PinServiceRevisions)
+		environments.POST("/:name/servicerevisions/unpin", UnpinServiceRevisions)
+		environments.POST("/:name/servicerevisions/advance", AdvanceServiceRevisionPins)
+		environments.PUT("/:name/fluxResources", UpdateFluxResources)
 		environments.GET("/:name/workloads", ListWorkloadsInEnv)
 
 		environments.GET("/:name/helm/releases", ListReleases)
 		environments.DELETE("/:name/helm/releases", DeleteHelmReleases)
+		environments.GET("/:name/helm/releases/:releaseName/history", ListHelmReleaseHistory)
+		environments.POST("/:name/helm/releases/:releaseName/rollback", RollbackHelmRelease)
 		environments.GET("/:name/helm/values", GetChartValues)
 		environments.GET("/:name/helm/charts", GetChartInfos)
 		environments.GET("/:name/helm/images", GetImageInfos)
@@ -178,6 +212,7 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		environments.POST("/:name/services/:serviceName/restart", RestartService)
 		environments.POST("/:name/services/:serviceName/restartNew", RestartWorkload)
 		environments.POST("/:name/services/:serviceName/scaleNew", ScaleNewService)
+		environments.PUT("/:name/services/:serviceName/statefulsets/:workloadName/rollout", UpdateStatefulSetRollout)
 
 		environments.POST("/:name/estimated-renderchart", GetEstimatedRenderCharts)
 
@@ -217,6 +252,26 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		environments.GET("/:name/version/:serviceName/revision/:revision", GetEnvServiceVersionYaml)
 		environments.GET("/:name/version/:serviceName/diff", DiffEnvServiceVersions)
 		environments.POST("/:name/version/:serviceName/rollback", RollbackEnvServiceVersion)
+
+		environments.GET("/:name/snapshots", ListEnvSnapshots)
+		environments.POST("/:name/snapshots", CreateEnvSnapshot)
+		environments.GET("/:name/snapshots/:id", GetEnvSnapshot)
+		environments.DELETE("/:name/snapshots/:id", DeleteEnvSnapshot)
+		environments.POST("/:name/snapshots/:id/restore", RestoreEnvSnapshot)
+
+		environments.GET("/:name/drifts", ListEnvDriftRecords)
+		environments.POST("/:name/drifts/detect", DetectEnvDrift)
+
+		environments.GET("/:name/unmanagedResources", ListUnmanagedResources)
+		environments.POST("/:name/unmanagedResources/adopt", AdoptUnmanagedResources)
+
+		environments.GET("/:name/quota", GetEnvQuota)
+		environments.PUT("/:name/quota", UpdateEnvQuota)
+		environments.POST("/:name/rollback", RollbackEnvServices)
+		environments.GET("/:name/rollbacks", ListEnvRollbackRecords)
+		environments.GET("/:name/firstDeployHooks", ListFirstDeployHookRecords)
+		environments.GET("/:name/deployLedger", ListDeployLedger)
+		environments.GET("/:name/deployLedger/export", ExportDeployLedger)
 	}
 
 	// ---------------------------------------------------------------------------------------
@@ -589,6 +589,17 @@ type OpenAPIGetServiceResponse struct {
 	Workloads   []*commonservice.Workload    `json:"-"`
 }
 
+// @Summary OpenAPI Get Service
+// @Description OpenAPI Get Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	serviceName	path	string	true	"service name"
+// @Param 	projectName	query	string	false	"project name"
+// @Param 	workLoadType	query	string	false	"work load type"
+// @Success 200 	{object} 	OpenAPIGetServiceResponse
+// @Router /openapi/environments/{name}/services/{serviceName} [get]
 func OpenAPIGetService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -641,6 +652,17 @@ func OpenAPIGetService(c *gin.Context) {
 	return
 }
 
+// @Summary OpenAPI Get Production Service
+// @Description OpenAPI Get Production Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	serviceName	path	string	true	"service name"
+// @Param 	projectName	query	string	false	"project name"
+// @Param 	workLoadType	query	string	false	"work load type"
+// @Success 200 	{object} 	OpenAPIGetServiceResponse
+// @Router /openapi/environments/production/{name}/services/{serviceName} [get]
 func OpenAPIGetProductionService(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
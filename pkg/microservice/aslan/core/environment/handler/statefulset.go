@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// @Summary Update StatefulSet Rollout
+// @Description Update the partition, pod management policy or pvc retention policy of a statefulSet in the env
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string									true	"env name"
+// @Param 	serviceName		path		string									true	"service name"
+// @Param 	workloadName	path		string									true	"statefulSet name"
+// @Param 	projectName		query		string									true	"project name"
+// @Param 	production		query		bool									false	"is production env"
+// @Param 	body 			body 		service.UpdateStatefulSetRolloutArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/environment/environments/{name}/services/{serviceName}/statefulsets/{workloadName}/rollout [put]
+func UpdateStatefulSetRollout(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	envName := c.Param("name")
+	serviceName := c.Param("serviceName")
+	workloadName := c.Param("workloadName")
+	production := c.Query("production") == "true"
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if production {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].ProductionEnv.ManagePods {
+				permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, types.ProductionEnvActionManagePod)
+				if err != nil || !permitted {
+					ctx.UnAuthorized = true
+					return
+				}
+			}
+
+			if err := commonutil.CheckZadigProfessionalLicense(); err != nil {
+				ctx.Err = err
+				return
+			}
+		} else {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].Env.ManagePods {
+				permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeEnvironment, envName, types.EnvActionManagePod)
+				if err != nil || !permitted {
+					ctx.UnAuthorized = true
+					return
+				}
+			}
+		}
+	}
+
+	args := new(service.UpdateStatefulSetRolloutArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if args.Rollout == nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("rollout can not be empty")
+		return
+	}
+	args.ProductName = projectKey
+	args.EnvName = envName
+	args.ServiceName = serviceName
+	args.Name = workloadName
+	args.Production = production
+
+	internalhandler.InsertDetailedOperationLog(
+		c, ctx.UserName,
+		projectKey, setting.OperationSceneEnv,
+		"更新",
+		"环境-服务-StatefulSet发布策略",
+		fmt.Sprintf("环境名称:%s,服务名称:%s,StatefulSet:%s", envName, serviceName, workloadName),
+		"", ctx.Logger, envName)
+
+	ctx.Err = service.UpdateStatefulSetRollout(args, ctx.Logger)
+}
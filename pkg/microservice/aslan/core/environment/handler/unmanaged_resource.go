@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Unmanaged Resources
+// @Description List the resources in the namespace of the environment which are not managed by zadig, resources with conflicts are listed first
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string							true	"env name"
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	production		query		bool							false	"is production env"
+// @Success 200 			{array}  	service.UnmanagedResource
+// @Router /api/aslan/environment/environments/{name}/unmanagedResources [get]
+func ListUnmanagedResources(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, false) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListUnmanagedResources(projectKey, envName, production, ctx.Logger)
+}
+
+// @Summary Adopt Unmanaged Resources
+// @Description Adopt the unmanaged resources in the namespace of the environment into a service
+// @Tags 	environment
+// @Accept 	json
+// @Produce json
+// @Param 	name			path		string									true	"env name"
+// @Param 	projectName		query		string									true	"project name"
+// @Param 	production		query		bool									false	"is production env"
+// @Param 	body 			body 		service.AdoptUnmanagedResourcesArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/environment/environments/{name}/unmanagedResources/adopt [post]
+func AdoptUnmanagedResources(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName, production := c.Query("projectName"), c.Param("name"), c.Query("production") == "true"
+	if !checkEnvConfigPermission(ctx, projectKey, envName, production, true) {
+		return
+	}
+
+	args := new(service.AdoptUnmanagedResourcesArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	data, _ := json.Marshal(args)
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "纳管", "环境-外部资源", fmt.Sprintf("%s:%s", envName, args.ServiceName), string(data), ctx.Logger, envName)
+
+	ctx.Err = service.AdoptUnmanagedResources(projectKey, envName, production, args, ctx.Logger)
+}
@@ -88,3 +88,18 @@ func GetBundleResources(logger *zap.SugaredLogger) ([]*resourceSpec, error) {
 
 	return res, nil
 }
+
+// FilterEnvsByLabelSelector filters the environments of the project by the label selector.
+func FilterEnvsByLabelSelector(projectName, selector string, envs []*EnvResp) ([]*EnvResp, error) {
+	filter, err := service.NewResourceFilter(config.ResourceTypeEnvironment, projectName, selector)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*EnvResp, 0)
+	for _, env := range envs {
+		if filter.Matches(env.Name) {
+			resp = append(resp, env)
+		}
+	}
+	return resp, nil
+}
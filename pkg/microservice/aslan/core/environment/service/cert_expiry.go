@@ -0,0 +1,434 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	versionedclient "istio.io/client-go/pkg/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configbase "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/instantmessage"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const defaultCertExpiryThresholdDays = 30
+
+// certNotifyThresholds are the days left at which an expiring certificate is notified again after
+// the first notification, 0 is for the expired ones.
+var certNotifyThresholds = []int{7, 3, 1, 0}
+
+// workflow params filled with the cert info when the renewal workflow is triggered
+const (
+	CertRenewalParamEnvName    = "CERT_ENV_NAME"
+	CertRenewalParamNamespace  = "CERT_NAMESPACE"
+	CertRenewalParamSecretName = "CERT_SECRET_NAME"
+	CertRenewalParamDomains    = "CERT_DOMAINS"
+	CertRenewalParamNotAfter   = "CERT_NOT_AFTER"
+)
+
+type CertExpiryInfo struct {
+	EnvName    string   `json:"env_name"`
+	Production bool     `json:"production"`
+	ClusterID  string   `json:"cluster_id"`
+	Namespace  string   `json:"namespace"`
+	SecretName string   `json:"secret_name"`
+	CommonName string   `json:"common_name"`
+	Issuer     string   `json:"issuer"`
+	DNSNames   []string `json:"dns_names"`
+	NotBefore  int64    `json:"not_before"`
+	NotAfter   int64    `json:"not_after"`
+	DaysLeft   int      `json:"days_left"`
+	Expired    bool     `json:"expired"`
+	Ingresses  []string `json:"ingresses"`
+	Gateways   []string `json:"gateways"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// ListCertExpiry scans the TLS secrets in the namespaces of the project's environments and returns the
+// certificates sorted by expiry time. Only the certs expiring within the given days are returned if withinDays > 0.
+func ListCertExpiry(projectName string, production *bool, withinDays int, log *zap.SugaredLogger) ([]*CertExpiryInfo, error) {
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+		Name:          projectName,
+		Production:    production,
+		ExcludeStatus: []string{setting.ProductStatusDeleting, setting.ProductStatusUnknown},
+	})
+	if err != nil {
+		return nil, e.ErrListCertExpiry.AddErr(err)
+	}
+
+	resp := make([]*CertExpiryInfo, 0)
+	for _, env := range envs {
+		certs, err := scanEnvCerts(env)
+		if err != nil {
+			log.Warnf("failed to scan certificates of env %s/%s: %s", env.ProductName, env.EnvName, err)
+			continue
+		}
+		for _, cert := range certs {
+			if withinDays > 0 && cert.DaysLeft > withinDays {
+				continue
+			}
+			resp = append(resp, cert)
+		}
+	}
+
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].NotAfter < resp[j].NotAfter
+	})
+	return resp, nil
+}
+
+func scanEnvCerts(env *commonmodels.Product) ([]*CertExpiryInfo, error) {
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube clientset: %s", err)
+	}
+
+	ctx := context.TODO()
+	secrets, err := clientset.CoreV1().Secrets(env.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("type=%s", corev1.SecretTypeTLS),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tls secrets in namespace %s: %s", env.Namespace, err)
+	}
+	if len(secrets.Items) == 0 {
+		return nil, nil
+	}
+
+	// the ingresses and gateways are only used to show where the certs are referenced, errors are ignored
+	ingressRefs := make(map[string][]string)
+	ingresses, err := clientset.NetworkingV1().Ingresses(env.Namespace).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, ing := range ingresses.Items {
+			for _, tls := range ing.Spec.TLS {
+				ingressRefs[tls.SecretName] = append(ingressRefs[tls.SecretName], ing.Name)
+			}
+		}
+	} else {
+		log.Warnf("failed to list ingresses in namespace %s: %s", env.Namespace, err)
+	}
+
+	gatewayRefs := make(map[string][]string)
+	if istioInstalled, err := kube.CheckIstiodInstalled(ctx, clientset); err == nil && istioInstalled {
+		restConfig, err := kubeclient.GetRESTConfig(config.HubServerAddress(), env.ClusterID)
+		if err == nil {
+			istioClient, err := versionedclient.NewForConfig(restConfig)
+			if err == nil {
+				gateways, err := istioClient.NetworkingV1alpha3().Gateways(env.Namespace).List(ctx, metav1.ListOptions{})
+				if err == nil {
+					for _, gw := range gateways.Items {
+						for _, server := range gw.Spec.Servers {
+							if server.Tls != nil && server.Tls.CredentialName != "" {
+								gatewayRefs[server.Tls.CredentialName] = append(gatewayRefs[server.Tls.CredentialName], gw.Name)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	now := time.Now()
+	resp := make([]*CertExpiryInfo, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		info := &CertExpiryInfo{
+			EnvName:    env.EnvName,
+			Production: env.Production,
+			ClusterID:  env.ClusterID,
+			Namespace:  env.Namespace,
+			SecretName: secret.Name,
+			Ingresses:  ingressRefs[secret.Name],
+			Gateways:   gatewayRefs[secret.Name],
+		}
+
+		cert, err := parseLeafCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			info.Error = err.Error()
+			resp = append(resp, info)
+			continue
+		}
+
+		info.CommonName = cert.Subject.CommonName
+		info.Issuer = cert.Issuer.CommonName
+		info.DNSNames = cert.DNSNames
+		info.NotBefore = cert.NotBefore.Unix()
+		info.NotAfter = cert.NotAfter.Unix()
+		info.DaysLeft = int(cert.NotAfter.Sub(now).Hours() / 24)
+		info.Expired = now.After(cert.NotAfter)
+		if info.Expired {
+			info.DaysLeft = -1
+		}
+		resp = append(resp, info)
+	}
+	return resp, nil
+}
+
+// parseLeafCertificate returns the first certificate in the PEM encoded chain, which is the leaf certificate
+func parseLeafCertificate(data []byte) (*x509.Certificate, error) {
+	for len(data) > 0 {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %s", err)
+		}
+		return cert, nil
+	}
+	return nil, fmt.Errorf("no certificate found in %s", corev1.TLSCertKey)
+}
+
+func GetCertExpiryMonitor(projectName string) (*commonmodels.CertExpiryMonitor, error) {
+	monitor, err := commonrepo.NewCertExpiryMonitorColl().Find(projectName)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &commonmodels.CertExpiryMonitor{
+				ProjectName:   projectName,
+				ThresholdDays: defaultCertExpiryThresholdDays,
+				NotifyCtls:    make([]*commonmodels.NotifyCtl, 0),
+			}, nil
+		}
+		return nil, e.ErrGetCertExpiryMonitor.AddErr(err)
+	}
+	return monitor, nil
+}
+
+func UpdateCertExpiryMonitor(projectName, username string, args *commonmodels.CertExpiryMonitor) error {
+	if args.ThresholdDays <= 0 {
+		args.ThresholdDays = defaultCertExpiryThresholdDays
+	}
+	if args.RenewalWorkflow != nil && args.RenewalWorkflow.Enabled {
+		wf, err := commonrepo.NewWorkflowV4Coll().Find(args.RenewalWorkflow.WorkflowName)
+		if err != nil {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("failed to find renewal workflow %s: %s", args.RenewalWorkflow.WorkflowName, err))
+		}
+		if wf.Project != projectName {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("renewal workflow %s doesn't belong to project %s", wf.Name, projectName))
+		}
+	}
+
+	args.ProjectName = projectName
+	args.UpdatedBy = username
+	if err := commonrepo.NewCertExpiryMonitorColl().CreateOrUpdate(args); err != nil {
+		return e.ErrUpdateCertExpiryMonitor.AddErr(err)
+	}
+	return nil
+}
+
+// RunCertExpiryMonitors checks the certificates of all the projects with cert expiry monitoring enabled,
+// notifies the expiring ones and triggers the renewal workflow if configured.
+func RunCertExpiryMonitors() {
+	logger := log.SugaredLogger().With("source", "cert expiry monitor")
+	monitors, err := commonrepo.NewCertExpiryMonitorColl().ListEnabled()
+	if err != nil {
+		logger.Errorf("failed to list cert expiry monitors: %s", err)
+		return
+	}
+
+	for _, monitor := range monitors {
+		if err := runCertExpiryMonitor(monitor, logger); err != nil {
+			logger.Errorf("failed to run cert expiry monitor for project %s: %s", monitor.ProjectName, err)
+		}
+	}
+}
+
+func runCertExpiryMonitor(monitor *commonmodels.CertExpiryMonitor, logger *zap.SugaredLogger) error {
+	threshold := monitor.ThresholdDays
+	if threshold <= 0 {
+		threshold = defaultCertExpiryThresholdDays
+	}
+
+	certs, err := ListCertExpiry(monitor.ProjectName, nil, threshold, logger)
+	if err != nil {
+		return err
+	}
+	expiring := make([]*CertExpiryInfo, 0)
+	for _, cert := range certs {
+		if cert.Error == "" {
+			expiring = append(expiring, cert)
+		}
+	}
+
+	now := time.Now().Unix()
+	notifyRecords := make([]*commonmodels.CertNotifyRecord, 0, len(expiring))
+	seen := make(map[*commonmodels.CertNotifyRecord]bool)
+	toNotify := make([]*CertExpiryInfo, 0)
+	for _, cert := range expiring {
+		current := certNotifyThreshold(threshold, cert.DaysLeft)
+		record := findCertNotifyRecord(monitor, cert)
+		if record != nil {
+			seen[record] = true
+		}
+		if record == nil || current < record.ThresholdDays {
+			record = &commonmodels.CertNotifyRecord{
+				Namespace:     cert.Namespace,
+				SecretName:    cert.SecretName,
+				NotAfter:      cert.NotAfter,
+				ThresholdDays: current,
+				NotifyTime:    now,
+			}
+			toNotify = append(toNotify, cert)
+		}
+		notifyRecords = append(notifyRecords, record)
+	}
+	// records of the certs missing from this scan are kept until the certs expire, in case the env
+	// failed to be scanned, so the renewed or removed certs are cleaned up eventually.
+	for _, record := range monitor.NotifyRecords {
+		if !seen[record] && record.NotAfter > now {
+			notifyRecords = append(notifyRecords, record)
+		}
+	}
+	if len(toNotify) > 0 {
+		sendCertExpiryNotifications(monitor, threshold, toNotify, logger)
+	}
+	if err := commonrepo.NewCertExpiryMonitorColl().UpdateNotifyRecords(monitor.ProjectName, notifyRecords); err != nil {
+		logger.Errorf("failed to save cert notify records for project %s: %s", monitor.ProjectName, err)
+	}
+
+	if monitor.RenewalWorkflow == nil || !monitor.RenewalWorkflow.Enabled {
+		return nil
+	}
+	for _, cert := range expiring {
+		if certRenewalTriggered(monitor, cert) {
+			continue
+		}
+		taskID, err := triggerCertRenewalWorkflow(monitor.RenewalWorkflow.WorkflowName, cert, logger)
+		if err != nil {
+			logger.Errorf("failed to trigger cert renewal workflow %s for secret %s/%s: %s", monitor.RenewalWorkflow.WorkflowName, cert.Namespace, cert.SecretName, err)
+			continue
+		}
+		err = commonrepo.NewCertExpiryMonitorColl().AddRenewalRecord(monitor.ProjectName, &commonmodels.CertRenewalRecord{
+			EnvName:    cert.EnvName,
+			Namespace:  cert.Namespace,
+			SecretName: cert.SecretName,
+			NotAfter:   cert.NotAfter,
+			TaskID:     taskID,
+			CreateTime: time.Now().Unix(),
+		})
+		if err != nil {
+			logger.Errorf("failed to save cert renewal record for secret %s/%s: %s", cert.Namespace, cert.SecretName, err)
+		}
+	}
+	return nil
+}
+
+func sendCertExpiryNotifications(monitor *commonmodels.CertExpiryMonitor, threshold int, expiring []*CertExpiryInfo, logger *zap.SugaredLogger) {
+	notify := &webhooknotify.CertExpiryNotify{
+		ProjectName:   monitor.ProjectName,
+		ThresholdDays: threshold,
+		DetailURL:     fmt.Sprintf("%s/v1/projects/detail/%s/envs", configbase.SystemAddress(), monitor.ProjectName),
+		Certs:         make([]*webhooknotify.CertExpiryNotifyCert, 0, len(expiring)),
+	}
+	for _, cert := range expiring {
+		notify.Certs = append(notify.Certs, &webhooknotify.CertExpiryNotifyCert{
+			EnvName:    cert.EnvName,
+			Production: cert.Production,
+			Namespace:  cert.Namespace,
+			SecretName: cert.SecretName,
+			CommonName: cert.CommonName,
+			DNSNames:   cert.DNSNames,
+			NotAfter:   cert.NotAfter,
+			DaysLeft:   cert.DaysLeft,
+		})
+	}
+	if err := instantmessage.NewWeChatClient().SendCertExpiryNotifications(notify, monitor.NotifyCtls); err != nil {
+		logger.Errorf("failed to send cert expiry notifications for project %s: %s", monitor.ProjectName, err)
+	}
+}
+
+// certNotifyThreshold returns the lowest threshold the certificate has reached
+func certNotifyThreshold(threshold, daysLeft int) int {
+	current := threshold
+	for _, t := range certNotifyThresholds {
+		if t < current && daysLeft <= t {
+			current = t
+		}
+	}
+	return current
+}
+
+func findCertNotifyRecord(monitor *commonmodels.CertExpiryMonitor, cert *CertExpiryInfo) *commonmodels.CertNotifyRecord {
+	for _, record := range monitor.NotifyRecords {
+		if record.Namespace == cert.Namespace && record.SecretName == cert.SecretName && record.NotAfter == cert.NotAfter {
+			return record
+		}
+	}
+	return nil
+}
+
+// certRenewalTriggered checks whether the renewal workflow has been triggered for the current certificate,
+// a renewed certificate has a different expiry time so it will be handled again when it is about to expire.
+func certRenewalTriggered(monitor *commonmodels.CertExpiryMonitor, cert *CertExpiryInfo) bool {
+	for _, record := range monitor.RenewalRecords {
+		if record.Namespace == cert.Namespace && record.SecretName == cert.SecretName && record.NotAfter == cert.NotAfter {
+			return true
+		}
+	}
+	return false
+}
+
+func triggerCertRenewalWorkflow(workflowName string, cert *CertExpiryInfo, logger *zap.SugaredLogger) (int64, error) {
+	wf, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find workflow %s: %s", workflowName, err)
+	}
+
+	values := map[string]string{
+		CertRenewalParamEnvName:    cert.EnvName,
+		CertRenewalParamNamespace:  cert.Namespace,
+		CertRenewalParamSecretName: cert.SecretName,
+		CertRenewalParamDomains:    strings.Join(cert.DNSNames, ","),
+		CertRenewalParamNotAfter:   time.Unix(cert.NotAfter, 0).Format(time.RFC3339),
+	}
+	for _, param := range wf.Params {
+		if value, ok := values[param.Name]; ok {
+			param.Value = value
+		}
+	}
+
+	resp, err := workflow.CreateWorkflowTaskV4(&workflow.CreateWorkflowTaskV4Args{
+		Name: setting.SystemUser,
+	}, wf, logger)
+	if err != nil {
+		return 0, err
+	}
+	return resp.TaskID, nil
+}
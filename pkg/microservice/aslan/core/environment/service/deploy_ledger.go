@@ -0,0 +1,322 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const (
+	DeployLedgerExportFormatJSON = "json"
+	DeployLedgerExportFormatCSV  = "csv"
+
+	deployLedgerApprovalDeployWindow = "deploy_window"
+)
+
+type DeployLedgerArgs struct {
+	ProjectName string `form:"projectName"`
+	Production  bool   `form:"production"`
+	StartTime   int64  `form:"startTime"`
+	EndTime     int64  `form:"endTime"`
+}
+
+// DeployLedgerEntry is a deployment of a service module to the environment made by a workflow task
+type DeployLedgerEntry struct {
+	DeployTime          int64                   `json:"deploy_time"`
+	EnvName             string                  `json:"env_name"`
+	Production          bool                    `json:"production"`
+	ServiceName         string                  `json:"service_name"`
+	ServiceModule       string                  `json:"service_module"`
+	Image               string                  `json:"image"`
+	WorkflowName        string                  `json:"workflow_name"`
+	WorkflowDisplayName string                  `json:"workflow_display_name"`
+	TaskID              int64                   `json:"task_id"`
+	TaskName            string                  `json:"task_name,omitempty"`
+	JobName             string                  `json:"job_name"`
+	Status              config.Status           `json:"status"`
+	Operator            string                  `json:"operator"`
+	Approvals           []*DeployLedgerApproval `json:"approvals"`
+}
+
+// DeployLedgerApproval is an approval of the task the deployment belongs to
+type DeployLedgerApproval struct {
+	JobName   string        `json:"job_name"`
+	Type      string        `json:"type"`
+	Status    config.Status `json:"status,omitempty"`
+	Approvers []string      `json:"approvers"`
+}
+
+// ListDeployLedger returns who deployed what to the environment in the time range, in the order of the deploy time.
+// The tasks moved into the cold storage are included.
+func ListDeployLedger(envName string, args *DeployLedgerArgs, log *zap.SugaredLogger) ([]*DeployLedgerEntry, error) {
+	tasks, err := commonrepo.NewworkflowTaskv4Coll().ListDeployTasks(args.ProjectName, args.StartTime, args.EndTime)
+	if err != nil {
+		log.Errorf("failed to list deploy tasks of project %s, error: %s", args.ProjectName, err)
+		return nil, e.ErrListDeployLedger.AddErr(err)
+	}
+	coldTasks, err := commonrepo.NewWorkflowTaskColdColl().ListTasks(args.ProjectName, args.StartTime, args.EndTime)
+	if err != nil {
+		log.Errorf("failed to list cold tasks of project %s, error: %s", args.ProjectName, err)
+		return nil, e.ErrListDeployLedger.AddErr(err)
+	}
+	for _, task := range coldTasks {
+		if !task.IsDeleted {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreateTime < tasks[j].CreateTime
+	})
+
+	resp := make([]*DeployLedgerEntry, 0)
+	for _, task := range tasks {
+		entries := make([]*DeployLedgerEntry, 0)
+		for _, stage := range task.Stages {
+			for _, job := range stage.Jobs {
+				if job.Status == "" || job.Status == config.StatusSkipped || job.Status == config.StatusNotRun {
+					continue
+				}
+				jobEntries, err := deployLedgerEntriesFromJob(task, job, envName, args.Production)
+				if err != nil {
+					log.Errorf("failed to parse job %s of task %s-%d, error: %s", job.Name, task.WorkflowName, task.TaskID, err)
+					return nil, e.ErrListDeployLedger.AddErr(err)
+				}
+				entries = append(entries, jobEntries...)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		approvals, err := getTaskApprovals(task)
+		if err != nil {
+			log.Errorf("failed to get approvals of task %s-%d, error: %s", task.WorkflowName, task.TaskID, err)
+			return nil, e.ErrListDeployLedger.AddErr(err)
+		}
+		for _, entry := range entries {
+			entry.Approvals = append(append(make([]*DeployLedgerApproval, 0, len(approvals)+len(entry.Approvals)), approvals...), entry.Approvals...)
+			resp = append(resp, entry)
+		}
+	}
+	return resp, nil
+}
+
+func deployLedgerEntriesFromJob(task *commonmodels.WorkflowTask, job *commonmodels.JobTask, envName string, production bool) ([]*DeployLedgerEntry, error) {
+	newEntry := func(serviceName, serviceModule, image string) *DeployLedgerEntry {
+		deployTime := job.StartTime
+		if deployTime == 0 {
+			deployTime = task.CreateTime
+		}
+		return &DeployLedgerEntry{
+			DeployTime:          deployTime,
+			EnvName:             envName,
+			Production:          production,
+			ServiceName:         serviceName,
+			ServiceModule:       serviceModule,
+			Image:               image,
+			WorkflowName:        task.WorkflowName,
+			WorkflowDisplayName: task.WorkflowDisplayName,
+			TaskID:              task.TaskID,
+			TaskName:            task.TaskName,
+			JobName:             job.Name,
+			Status:              job.Status,
+			Operator:            task.TaskCreator,
+			Approvals:           make([]*DeployLedgerApproval, 0),
+		}
+	}
+
+	resp := make([]*DeployLedgerEntry, 0)
+	var windowApproval *commonmodels.DeployWindowApproval
+	switch config.JobType(job.JobType) {
+	case config.JobZadigDeploy:
+		spec := new(commonmodels.JobTaskDeploySpec)
+		if err := commonmodels.IToi(job.Spec, spec); err != nil {
+			return nil, err
+		}
+		if spec.Env != envName || spec.Production != production {
+			return nil, nil
+		}
+		for _, module := range spec.ServiceAndImages {
+			resp = append(resp, newEntry(spec.ServiceName, module.ServiceModule, module.Image))
+		}
+		if len(spec.ServiceAndImages) == 0 {
+			resp = append(resp, newEntry(spec.ServiceName, spec.ServiceModule, spec.Image))
+		}
+		windowApproval = spec.DeployWindowApproval
+	case config.JobZadigHelmDeploy:
+		spec := new(commonmodels.JobTaskHelmDeploySpec)
+		if err := commonmodels.IToi(job.Spec, spec); err != nil {
+			return nil, err
+		}
+		if spec.Env != envName || spec.IsProduction != production {
+			return nil, nil
+		}
+		for _, module := range spec.ImageAndModules {
+			resp = append(resp, newEntry(spec.ServiceName, module.ServiceModule, module.Image))
+		}
+		if len(spec.ImageAndModules) == 0 {
+			resp = append(resp, newEntry(spec.ServiceName, "", ""))
+		}
+		windowApproval = spec.DeployWindowApproval
+	default:
+		return nil, nil
+	}
+
+	if windowApproval != nil && windowApproval.NativeApproval != nil {
+		approval := &DeployLedgerApproval{
+			JobName:   job.Name,
+			Type:      deployLedgerApprovalDeployWindow,
+			Approvers: approvedUsers(windowApproval.NativeApproval.ApproveUsers),
+		}
+		for _, entry := range resp {
+			entry.Approvals = append(entry.Approvals, approval)
+		}
+	}
+	return resp, nil
+}
+
+// getTaskApprovals returns the approval jobs of the task, the approvers of native approvals are taken from the
+// approval records since they are tamper-evident.
+func getTaskApprovals(task *commonmodels.WorkflowTask) ([]*DeployLedgerApproval, error) {
+	resp := make([]*DeployLedgerApproval, 0)
+	for _, stage := range task.Stages {
+		for _, job := range stage.Jobs {
+			if config.JobType(job.JobType) != config.JobApproval || job.Status == "" {
+				continue
+			}
+			spec := new(commonmodels.JobTaskApprovalSpec)
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return nil, err
+			}
+			resp = append(resp, &DeployLedgerApproval{
+				JobName:   job.Name,
+				Type:      string(spec.Type),
+				Status:    job.Status,
+				Approvers: make([]string, 0),
+			})
+		}
+	}
+	if len(resp) == 0 {
+		return resp, nil
+	}
+
+	records, _, err := commonrepo.NewApprovalRecordColl().List(context.Background(), &commonrepo.ListApprovalRecordOption{
+		Source:       config.ApprovalRecordSourceWorkflow,
+		ProjectName:  task.ProjectName,
+		WorkflowName: task.WorkflowName,
+		TaskID:       task.TaskID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, approval := range resp {
+		for _, record := range records {
+			if record.JobName == approval.JobName && record.Decision == config.Approve {
+				approval.Approvers = append(approval.Approvers, record.UserName)
+			}
+		}
+	}
+	return resp, nil
+}
+
+func approvedUsers(users []*commonmodels.User) []string {
+	resp := make([]string, 0)
+	for _, user := range users {
+		if user.RejectOrApprove == config.Approve {
+			resp = append(resp, user.UserName)
+		}
+	}
+	return resp
+}
+
+// ExportDeployLedger exports the deploy ledger of the environment in json or csv format
+func ExportDeployLedger(envName string, args *DeployLedgerArgs, format string, log *zap.SugaredLogger) ([]byte, string, error) {
+	entries, err := ListDeployLedger(envName, args, log)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fileName := fmt.Sprintf("deploy-ledger-%s-%s-%s", args.ProjectName, envName, time.Now().Format("20060102150405"))
+	switch format {
+	case DeployLedgerExportFormatCSV:
+		data, err := deployLedgerToCSV(entries)
+		if err != nil {
+			return nil, "", e.ErrExportDeployLedger.AddErr(err)
+		}
+		return data, fileName + ".csv", nil
+	case DeployLedgerExportFormatJSON, "":
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return nil, "", e.ErrExportDeployLedger.AddErr(err)
+		}
+		return data, fileName + ".json", nil
+	default:
+		return nil, "", e.ErrExportDeployLedger.AddDesc(fmt.Sprintf("unsupported format: %s", format))
+	}
+}
+
+func deployLedgerToCSV(entries []*DeployLedgerEntry) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	header := []string{"deploy_time", "env_name", "production", "service_name", "service_module", "image", "workflow_name",
+		"workflow_display_name", "task_id", "task_name", "job_name", "status", "operator", "approvals"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		approvals := make([]string, 0, len(entry.Approvals))
+		for _, approval := range entry.Approvals {
+			approvals = append(approvals, fmt.Sprintf("%s(%s,%s):%s", approval.JobName, approval.Type, approval.Status, strings.Join(approval.Approvers, "|")))
+		}
+		row := []string{
+			time.Unix(entry.DeployTime, 0).Format(time.RFC3339),
+			entry.EnvName,
+			strconv.FormatBool(entry.Production),
+			entry.ServiceName,
+			entry.ServiceModule,
+			entry.Image,
+			entry.WorkflowName,
+			entry.WorkflowDisplayName,
+			strconv.FormatInt(entry.TaskID, 10),
+			entry.TaskName,
+			entry.JobName,
+			string(entry.Status),
+			entry.Operator,
+			strings.Join(approvals, "; "),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
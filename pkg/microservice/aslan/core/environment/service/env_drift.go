@@ -0,0 +1,417 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	configbase "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/instantmessage"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+// the number of drift records kept for each env
+const envDriftRecordRetention = 50
+
+type ListEnvDriftRecordsResp struct {
+	Records []*commonmodels.EnvDriftRecord `json:"records"`
+	Total   int64                          `json:"total"`
+}
+
+func GetEnvDriftMonitor(projectName string) (*commonmodels.EnvDriftMonitor, error) {
+	monitor, err := commonrepo.NewEnvDriftMonitorColl().Find(projectName)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &commonmodels.EnvDriftMonitor{
+				ProjectName: projectName,
+				NotifyCtls:  make([]*commonmodels.NotifyCtl, 0),
+			}, nil
+		}
+		return nil, e.ErrGetEnvDriftMonitor.AddErr(err)
+	}
+	return monitor, nil
+}
+
+func UpdateEnvDriftMonitor(projectName, username string, args *commonmodels.EnvDriftMonitor) error {
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return e.ErrUpdateEnvDriftMonitor.AddErr(err)
+	}
+	if args.Enabled && !project.IsK8sYamlProduct() {
+		return e.ErrUpdateEnvDriftMonitor.AddDesc("drift detection is only supported by k8s yaml projects")
+	}
+
+	args.ProjectName = projectName
+	args.UpdatedBy = username
+	if err := commonrepo.NewEnvDriftMonitorColl().CreateOrUpdate(args); err != nil {
+		return e.ErrUpdateEnvDriftMonitor.AddErr(err)
+	}
+	return nil
+}
+
+func ListEnvDriftRecords(projectName, envName string, production bool, page, pageSize int64) (*ListEnvDriftRecordsResp, error) {
+	records, total, err := commonrepo.NewEnvDriftRecordColl().List(&commonrepo.EnvDriftRecordListOption{
+		ProductName: projectName,
+		EnvName:     envName,
+		Production:  production,
+		Page:        page,
+		PageSize:    pageSize,
+	})
+	if err != nil {
+		return nil, e.ErrListEnvDriftRecords.AddErr(err)
+	}
+	return &ListEnvDriftRecordsResp{
+		Records: records,
+		Total:   total,
+	}, nil
+}
+
+// DetectEnvDrift compares the live state of the services in the env with the rendered manifests immediately,
+// the result is recorded if any drift is found.
+func DetectEnvDrift(projectName, envName string, production bool, log *zap.SugaredLogger) (*commonmodels.EnvDriftRecord, error) {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return nil, e.ErrDetectEnvDrift.AddErr(err)
+	}
+	if env.IsSleeping() {
+		return nil, e.ErrDetectEnvDrift.AddDesc("the environment is sleeping")
+	}
+
+	record, _, err := detectAndRecordEnvDrift(env, log)
+	if err != nil {
+		return nil, e.ErrDetectEnvDrift.AddErr(err)
+	}
+	return record, nil
+}
+
+// RunEnvDriftDetection detects the drift of all the envs in the projects with drift detection enabled,
+// and notifies when the drift of an env is changed.
+func RunEnvDriftDetection() {
+	logger := log.SugaredLogger().With("source", "env drift detection")
+	monitors, err := commonrepo.NewEnvDriftMonitorColl().ListEnabled()
+	if err != nil {
+		logger.Errorf("failed to list env drift monitors: %s", err)
+		return
+	}
+
+	for _, monitor := range monitors {
+		envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+			Name:          monitor.ProjectName,
+			ExcludeStatus: []string{setting.ProductStatusCreating, setting.ProductStatusDeleting, setting.ProductStatusUnknown, setting.ProductStatusSleeping},
+		})
+		if err != nil {
+			logger.Errorf("failed to list envs of project %s: %s", monitor.ProjectName, err)
+			continue
+		}
+
+		for _, env := range envs {
+			record, changed, err := detectAndRecordEnvDrift(env, logger)
+			if err != nil {
+				logger.Errorf("failed to detect drift of env %s/%s: %s", env.ProductName, env.EnvName, err)
+				continue
+			}
+			if !changed || record == nil {
+				continue
+			}
+			if err := instantmessage.NewWeChatClient().SendEnvDriftNotifications(newEnvDriftNotify(record), monitor.NotifyCtls); err != nil {
+				logger.Errorf("failed to send env drift notifications for env %s/%s: %s", env.ProductName, env.EnvName, err)
+			}
+		}
+	}
+}
+
+// detectAndRecordEnvDrift returns the drift record of the env, nil is returned if there is no drift.
+// changed indicates whether the drift is different from the latest record.
+func detectAndRecordEnvDrift(env *commonmodels.Product, log *zap.SugaredLogger) (*commonmodels.EnvDriftRecord, bool, error) {
+	drifts, err := detectEnvDrift(env, log)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(drifts) == 0 {
+		return nil, false, nil
+	}
+
+	coll := commonrepo.NewEnvDriftRecordColl()
+	changed := true
+	latest, err := coll.FindLatest(env.ProductName, env.EnvName, env.Production)
+	if err == nil {
+		changed = !reflect.DeepEqual(latest.Drifts, drifts)
+	} else if err != mongo.ErrNoDocuments {
+		return nil, false, fmt.Errorf("failed to find latest drift record: %s", err)
+	}
+
+	record := &commonmodels.EnvDriftRecord{
+		ProductName: env.ProductName,
+		EnvName:     env.EnvName,
+		Production:  env.Production,
+		ClusterID:   env.ClusterID,
+		Namespace:   env.Namespace,
+		Drifts:      drifts,
+	}
+	if err := coll.Create(record); err != nil {
+		return nil, false, fmt.Errorf("failed to create drift record: %s", err)
+	}
+	if err := coll.DeleteOutdated(env.ProductName, env.EnvName, env.Production, envDriftRecordRetention); err != nil {
+		log.Warnf("failed to delete outdated drift records of env %s/%s: %s", env.ProductName, env.EnvName, err)
+	}
+	return record, changed, nil
+}
+
+func detectEnvDrift(env *commonmodels.Product, log *zap.SugaredLogger) ([]*commonmodels.EnvResourceDrift, error) {
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube clientset: %s", err)
+	}
+
+	ctx := context.TODO()
+	// the replicas of the workloads scaled by hpa are expected to be different from the manifests
+	scaledWorkloads := make(map[string]bool)
+	hpas, err := clientset.AutoscalingV1().HorizontalPodAutoscalers(env.Namespace).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, hpa := range hpas.Items {
+			scaledWorkloads[fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name)] = true
+		}
+	} else {
+		log.Warnf("failed to list hpas in namespace %s: %s", env.Namespace, err)
+	}
+
+	// images in the cluster are pulled through the dragonfly mirror if it is enabled
+	dragonflyConfig := kube.GetDragonflyConfig(env.ClusterID)
+
+	drifts := make([]*commonmodels.EnvResourceDrift, 0)
+	for _, svc := range env.GetSvcList() {
+		if svc.Type != setting.K8SDeployType {
+			continue
+		}
+		yamlContent, _, err := kube.FetchCurrentAppliedYaml(&kube.GeneSvcYamlOption{
+			ProductName: env.ProductName,
+			EnvName:     env.EnvName,
+			ServiceName: svc.ServiceName,
+		})
+		if err != nil {
+			log.Warnf("failed to render yaml of service %s in env %s/%s: %s", svc.ServiceName, env.ProductName, env.EnvName, err)
+			continue
+		}
+		resources, _, err := kube.ManifestToUnstructured(yamlContent)
+		if err != nil {
+			log.Warnf("failed to parse yaml of service %s in env %s/%s: %s", svc.ServiceName, env.ProductName, env.EnvName, err)
+		}
+
+		for _, resource := range resources {
+			var items []*commonmodels.EnvDriftItem
+			switch resource.GetKind() {
+			case setting.Deployment:
+				items, err = detectDeploymentDrift(ctx, clientset, env.Namespace, resource.Object, scaledWorkloads, dragonflyConfig)
+			case setting.StatefulSet:
+				items, err = detectStatefulSetDrift(ctx, clientset, env.Namespace, resource.Object, scaledWorkloads, dragonflyConfig)
+			default:
+				continue
+			}
+			if err != nil {
+				log.Warnf("failed to detect drift of %s/%s in env %s/%s: %s", resource.GetKind(), resource.GetName(), env.ProductName, env.EnvName, err)
+				continue
+			}
+			if len(items) == 0 {
+				continue
+			}
+			drifts = append(drifts, &commonmodels.EnvResourceDrift{
+				ServiceName: svc.ServiceName,
+				Kind:        resource.GetKind(),
+				Name:        resource.GetName(),
+				Items:       items,
+			})
+		}
+	}
+	return drifts, nil
+}
+
+func detectDeploymentDrift(ctx context.Context, clientset *kubernetes.Clientset, namespace string, obj map[string]interface{}, scaledWorkloads map[string]bool, dragonflyConfig *commonmodels.DragonflyConfig) ([]*commonmodels.EnvDriftItem, error) {
+	desired := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, desired); err != nil {
+		return nil, err
+	}
+	live, err := clientset.AppsV1().Deployments(namespace).Get(ctx, desired.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return []*commonmodels.EnvDriftItem{{Field: commonmodels.EnvDriftFieldResource, Desired: desired.Name}}, nil
+		}
+		return nil, err
+	}
+
+	items := make([]*commonmodels.EnvDriftItem, 0)
+	if !scaledWorkloads[fmt.Sprintf("%s/%s", setting.Deployment, desired.Name)] {
+		items = append(items, compareReplicas(desired.Spec.Replicas, live.Spec.Replicas)...)
+	}
+	return append(items, comparePodSpec(&desired.Spec.Template.Spec, &live.Spec.Template.Spec, dragonflyConfig)...), nil
+}
+
+func detectStatefulSetDrift(ctx context.Context, clientset *kubernetes.Clientset, namespace string, obj map[string]interface{}, scaledWorkloads map[string]bool, dragonflyConfig *commonmodels.DragonflyConfig) ([]*commonmodels.EnvDriftItem, error) {
+	desired := &appsv1.StatefulSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, desired); err != nil {
+		return nil, err
+	}
+	live, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, desired.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return []*commonmodels.EnvDriftItem{{Field: commonmodels.EnvDriftFieldResource, Desired: desired.Name}}, nil
+		}
+		return nil, err
+	}
+
+	items := make([]*commonmodels.EnvDriftItem, 0)
+	if !scaledWorkloads[fmt.Sprintf("%s/%s", setting.StatefulSet, desired.Name)] {
+		items = append(items, compareReplicas(desired.Spec.Replicas, live.Spec.Replicas)...)
+	}
+	return append(items, comparePodSpec(&desired.Spec.Template.Spec, &live.Spec.Template.Spec, dragonflyConfig)...), nil
+}
+
+func compareReplicas(desired, live *int32) []*commonmodels.EnvDriftItem {
+	// the replicas default to 1 if not set
+	desiredReplicas, liveReplicas := int32(1), int32(1)
+	if desired != nil {
+		desiredReplicas = *desired
+	}
+	if live != nil {
+		liveReplicas = *live
+	}
+	if desiredReplicas == liveReplicas {
+		return nil
+	}
+	return []*commonmodels.EnvDriftItem{{
+		Field:   commonmodels.EnvDriftFieldReplicas,
+		Desired: fmt.Sprintf("%d", desiredReplicas),
+		Live:    fmt.Sprintf("%d", liveReplicas),
+	}}
+}
+
+// comparePodSpec compares the images and the env vars of the containers, only the env vars with literal values are compared.
+// The desired images are rewritten the same way as they are applied if the dragonfly mirror is enabled.
+func comparePodSpec(desired, live *corev1.PodSpec, dragonflyConfig *commonmodels.DragonflyConfig) []*commonmodels.EnvDriftItem {
+	items := make([]*commonmodels.EnvDriftItem, 0)
+	liveContainers := make(map[string]*corev1.Container)
+	for i := range live.Containers {
+		liveContainers[live.Containers[i].Name] = &live.Containers[i]
+	}
+
+	for _, desiredContainer := range desired.Containers {
+		liveContainer, ok := liveContainers[desiredContainer.Name]
+		if !ok {
+			items = append(items, &commonmodels.EnvDriftItem{
+				Field:     commonmodels.EnvDriftFieldContainer,
+				Container: desiredContainer.Name,
+				Desired:   desiredContainer.Name,
+			})
+			continue
+		}
+
+		if dragonflyConfig.MirrorImage(desiredContainer.Image) != liveContainer.Image {
+			items = append(items, &commonmodels.EnvDriftItem{
+				Field:     commonmodels.EnvDriftFieldImage,
+				Container: desiredContainer.Name,
+				Desired:   desiredContainer.Image,
+				Live:      liveContainer.Image,
+			})
+		}
+
+		liveEnvs := make(map[string]string)
+		for _, env := range liveContainer.Env {
+			if env.ValueFrom == nil {
+				liveEnvs[env.Name] = env.Value
+			}
+		}
+		desiredEnvs := make(map[string]bool)
+		for _, env := range desiredContainer.Env {
+			if env.ValueFrom != nil {
+				continue
+			}
+			desiredEnvs[env.Name] = true
+			if liveValue, ok := liveEnvs[env.Name]; !ok || liveValue != env.Value {
+				items = append(items, &commonmodels.EnvDriftItem{
+					Field:     commonmodels.EnvDriftFieldEnv,
+					Container: desiredContainer.Name,
+					Key:       env.Name,
+					Desired:   env.Value,
+					Live:      liveValue,
+				})
+			}
+		}
+		for _, env := range liveContainer.Env {
+			if env.ValueFrom != nil || desiredEnvs[env.Name] {
+				continue
+			}
+			items = append(items, &commonmodels.EnvDriftItem{
+				Field:     commonmodels.EnvDriftFieldEnv,
+				Container: desiredContainer.Name,
+				Key:       env.Name,
+				Live:      env.Value,
+			})
+		}
+	}
+	return items
+}
+
+func newEnvDriftNotify(record *commonmodels.EnvDriftRecord) *webhooknotify.EnvDriftNotify {
+	notify := &webhooknotify.EnvDriftNotify{
+		ProjectName: record.ProductName,
+		EnvName:     record.EnvName,
+		Production:  record.Production,
+		Namespace:   record.Namespace,
+		DetailURL:   fmt.Sprintf("%s/v1/projects/detail/%s/envs/detail?envName=%s", configbase.SystemAddress(), record.ProductName, record.EnvName),
+		Resources:   make([]*webhooknotify.EnvDriftNotifyResource, 0, len(record.Drifts)),
+	}
+	for _, drift := range record.Drifts {
+		fields := make([]string, 0, len(drift.Items))
+		fieldSet := make(map[commonmodels.EnvDriftField]bool)
+		for _, item := range drift.Items {
+			if !fieldSet[item.Field] {
+				fieldSet[item.Field] = true
+				fields = append(fields, string(item.Field))
+			}
+		}
+		notify.Resources = append(notify.Resources, &webhooknotify.EnvDriftNotifyResource{
+			ServiceName: drift.ServiceName,
+			Kind:        drift.Kind,
+			Name:        drift.Name,
+			Fields:      fields,
+		})
+	}
+	return notify
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// the ResourceQuota and LimitRange managed by zadig in the namespace of the env
+const (
+	envResourceQuotaName = "zadig-env-quota"
+	envLimitRangeName    = "zadig-env-limit-range"
+)
+
+type EnvQuota struct {
+	Namespace string `json:"namespace"`
+	// Hard is the hard limits of the ResourceQuota, e.g. requests.cpu: 4, limits.memory: 8Gi, pods: 20
+	Hard map[string]string `json:"hard"`
+	// Used is the observed usage of the resources limited by the ResourceQuota
+	Used       map[string]string `json:"used"`
+	LimitRange *EnvLimitRange    `json:"limit_range"`
+	// Capacity is the total allocatable cpu and memory of the nodes in the cluster
+	Capacity map[string]string `json:"capacity"`
+}
+
+// EnvLimitRange is the container level LimitRange of the env
+type EnvLimitRange struct {
+	Default        map[string]string `json:"default"`
+	DefaultRequest map[string]string `json:"default_request"`
+	Max            map[string]string `json:"max"`
+}
+
+type UpdateEnvQuotaArgs struct {
+	// the ResourceQuota is removed if Hard is empty
+	Hard map[string]string `json:"hard"`
+	// the LimitRange is removed if LimitRange is nil
+	LimitRange *EnvLimitRange `json:"limit_range"`
+}
+
+func GetEnvQuota(projectName, envName string, production bool, log *zap.SugaredLogger) (*EnvQuota, error) {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return nil, e.ErrGetEnvQuota.AddErr(err)
+	}
+
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		log.Errorf("failed to get kube clientset of cluster %s: %s", env.ClusterID, err)
+		return nil, e.ErrGetEnvQuota.AddErr(err)
+	}
+
+	ctx := context.TODO()
+	resp := &EnvQuota{
+		Namespace: env.Namespace,
+		Hard:      make(map[string]string),
+		Used:      make(map[string]string),
+	}
+	quota, err := clientset.CoreV1().ResourceQuotas(env.Namespace).Get(ctx, envResourceQuotaName, metav1.GetOptions{})
+	if err == nil {
+		resp.Hard = resourceListToMap(quota.Spec.Hard)
+		resp.Used = resourceListToMap(quota.Status.Used)
+	} else if !apierrors.IsNotFound(err) {
+		return nil, e.ErrGetEnvQuota.AddErr(err)
+	}
+
+	limitRange, err := clientset.CoreV1().LimitRanges(env.Namespace).Get(ctx, envLimitRangeName, metav1.GetOptions{})
+	if err == nil {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type == corev1.LimitTypeContainer {
+				resp.LimitRange = &EnvLimitRange{
+					Default:        resourceListToMap(item.Default),
+					DefaultRequest: resourceListToMap(item.DefaultRequest),
+					Max:            resourceListToMap(item.Max),
+				}
+			}
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return nil, e.ErrGetEnvQuota.AddErr(err)
+	}
+
+	capacity, err := getClusterCapacity(ctx, clientset)
+	if err != nil {
+		// the capacity is only used as a reference
+		log.Warnf("failed to get capacity of cluster %s: %s", env.ClusterID, err)
+	} else {
+		resp.Capacity = resourceListToMap(capacity)
+	}
+	return resp, nil
+}
+
+// UpdateEnvQuota creates, updates or removes the ResourceQuota and LimitRange in the namespace of the env.
+// The cpu and memory limits can't exceed the allocatable resources of the cluster.
+func UpdateEnvQuota(projectName, envName string, production bool, args *UpdateEnvQuotaArgs, log *zap.SugaredLogger) error {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return e.ErrUpdateEnvQuota.AddErr(err)
+	}
+
+	hard, err := parseResourceList(args.Hard)
+	if err != nil {
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid quota: %s", err))
+	}
+	var limitRangeItem *corev1.LimitRangeItem
+	if args.LimitRange != nil {
+		limitRangeItem = &corev1.LimitRangeItem{Type: corev1.LimitTypeContainer}
+		if limitRangeItem.Default, err = parseResourceList(args.LimitRange.Default); err != nil {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid default limits: %s", err))
+		}
+		if limitRangeItem.DefaultRequest, err = parseResourceList(args.LimitRange.DefaultRequest); err != nil {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid default requests: %s", err))
+		}
+		if limitRangeItem.Max, err = parseResourceList(args.LimitRange.Max); err != nil {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid max limits: %s", err))
+		}
+	}
+
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		log.Errorf("failed to get kube clientset of cluster %s: %s", env.ClusterID, err)
+		return e.ErrUpdateEnvQuota.AddErr(err)
+	}
+
+	ctx := context.TODO()
+	capacity, err := getClusterCapacity(ctx, clientset)
+	if err != nil {
+		log.Errorf("failed to get capacity of cluster %s: %s", env.ClusterID, err)
+		return e.ErrUpdateEnvQuota.AddErr(err)
+	}
+	if err := validateResourcesWithinCapacity(hard, capacity); err != nil {
+		return e.ErrInvalidParam.AddDesc(err.Error())
+	}
+	if limitRangeItem != nil {
+		if err := validateResourcesWithinCapacity(limitRangeItem.Max, capacity); err != nil {
+			return e.ErrInvalidParam.AddDesc(err.Error())
+		}
+	}
+
+	labels := map[string]string{
+		setting.ProductLabel: env.ProductName,
+		setting.EnvNameLabel: env.EnvName,
+	}
+	if err := applyEnvResourceQuota(ctx, clientset, env.Namespace, labels, hard); err != nil {
+		log.Errorf("failed to apply resource quota in namespace %s: %s", env.Namespace, err)
+		return e.ErrUpdateEnvQuota.AddErr(err)
+	}
+	if err := applyEnvLimitRange(ctx, clientset, env.Namespace, labels, limitRangeItem); err != nil {
+		log.Errorf("failed to apply limit range in namespace %s: %s", env.Namespace, err)
+		return e.ErrUpdateEnvQuota.AddErr(err)
+	}
+	return nil
+}
+
+func applyEnvResourceQuota(ctx context.Context, clientset *kubernetes.Clientset, namespace string, labels map[string]string, hard corev1.ResourceList) error {
+	quotaClient := clientset.CoreV1().ResourceQuotas(namespace)
+	current, err := quotaClient.Get(ctx, envResourceQuotaName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	switch {
+	case len(hard) == 0 && exists:
+		return quotaClient.Delete(ctx, envResourceQuotaName, metav1.DeleteOptions{})
+	case len(hard) == 0:
+		return nil
+	case exists:
+		current.Spec.Hard = hard
+		_, err = quotaClient.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	default:
+		_, err = quotaClient.Create(ctx, &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: envResourceQuotaName, Namespace: namespace, Labels: labels},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		}, metav1.CreateOptions{})
+		return err
+	}
+}
+
+func applyEnvLimitRange(ctx context.Context, clientset *kubernetes.Clientset, namespace string, labels map[string]string, item *corev1.LimitRangeItem) error {
+	limitRangeClient := clientset.CoreV1().LimitRanges(namespace)
+	current, err := limitRangeClient.Get(ctx, envLimitRangeName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	switch {
+	case item == nil && exists:
+		return limitRangeClient.Delete(ctx, envLimitRangeName, metav1.DeleteOptions{})
+	case item == nil:
+		return nil
+	case exists:
+		current.Spec.Limits = []corev1.LimitRangeItem{*item}
+		_, err = limitRangeClient.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	default:
+		_, err = limitRangeClient.Create(ctx, &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: envLimitRangeName, Namespace: namespace, Labels: labels},
+			Spec:       corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{*item}},
+		}, metav1.CreateOptions{})
+		return err
+	}
+}
+
+// getClusterCapacity sums up the allocatable cpu and memory of the schedulable nodes
+func getClusterCapacity(ctx context.Context, clientset *kubernetes.Clientset) (corev1.ResourceList, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	cpu, memory := resource.NewQuantity(0, resource.DecimalSI), resource.NewQuantity(0, resource.BinarySI)
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		cpu.Add(*node.Status.Allocatable.Cpu())
+		memory.Add(*node.Status.Allocatable.Memory())
+	}
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    *cpu,
+		corev1.ResourceMemory: *memory,
+	}, nil
+}
+
+// validateResourcesWithinCapacity checks the cpu and memory resources, e.g. cpu, requests.cpu, limits.cpu, against the capacity
+func validateResourcesWithinCapacity(resources, capacity corev1.ResourceList) error {
+	for name, quantity := range resources {
+		var capacityName corev1.ResourceName
+		switch {
+		case strings.HasSuffix(string(name), string(corev1.ResourceCPU)):
+			capacityName = corev1.ResourceCPU
+		case strings.HasSuffix(string(name), string(corev1.ResourceMemory)):
+			capacityName = corev1.ResourceMemory
+		default:
+			continue
+		}
+		total, ok := capacity[capacityName]
+		if !ok {
+			continue
+		}
+		if quantity.Cmp(total) > 0 {
+			return fmt.Errorf("%s %s exceeds the cluster capacity %s", name, quantity.String(), total.String())
+		}
+	}
+	return nil
+}
+
+func parseResourceList(resources map[string]string) (corev1.ResourceList, error) {
+	resp := make(corev1.ResourceList, len(resources))
+	for name, value := range resources {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		if quantity.Sign() < 0 {
+			return nil, fmt.Errorf("%s can't be negative", name)
+		}
+		resp[corev1.ResourceName(name)] = quantity
+	}
+	return resp, nil
+}
+
+func resourceListToMap(resources corev1.ResourceList) map[string]string {
+	resp := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		resp[string(name)] = quantity.String()
+	}
+	return resp
+}
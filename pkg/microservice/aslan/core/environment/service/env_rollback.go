@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type RollbackEnvServicesArgs struct {
+	Source       config.RollbackSource `json:"source"`
+	WorkflowName string                `json:"workflow_name"`
+	TaskID       int64                 `json:"task_id"`
+	SnapshotID   string                `json:"snapshot_id"`
+	Services     []string              `json:"services"`
+}
+
+type RollbackEnvServicesResp struct {
+	Records []*commonmodels.EnvRollbackRecord `json:"records"`
+}
+
+type ListEnvRollbackRecordsResp struct {
+	Records []*commonmodels.EnvRollbackRecord `json:"records"`
+	Total   int64                             `json:"total"`
+}
+
+// RollbackEnvServices rolls the services back to the versions of a previous workflow task or an env snapshot,
+// the services are rolled back one by one and a failure does not stop the others.
+func RollbackEnvServices(ctx *internalhandler.Context, projectName, envName string, production bool, args *RollbackEnvServicesArgs, log *zap.SugaredLogger) (*RollbackEnvServicesResp, error) {
+	rollbackArgs := &kube.EnvRollbackArgs{
+		ProjectName:  projectName,
+		EnvName:      envName,
+		Production:   production,
+		Source:       args.Source,
+		WorkflowName: args.WorkflowName,
+		TaskID:       args.TaskID,
+		SnapshotID:   args.SnapshotID,
+		Services:     args.Services,
+	}
+	versions, err := kube.FindRollbackServiceVersions(rollbackArgs)
+	if err != nil {
+		return nil, e.ErrRollbackEnvServices.AddErr(err)
+	}
+
+	resp := &RollbackEnvServicesResp{Records: make([]*commonmodels.EnvRollbackRecord, 0, len(versions))}
+	errList := new(multierror.Error)
+	for _, version := range versions {
+		record := kube.NewEnvRollbackRecord(rollbackArgs, version, config.RollbackTriggerAPI, ctx.UserName)
+		if err := kube.RollbackEnvService(version, record, EnsureUpdateZadigService, log); err != nil {
+			errList = multierror.Append(errList, fmt.Errorf("service %s: %v", record.ServiceName, err))
+		}
+		resp.Records = append(resp.Records, record)
+	}
+
+	if err := errList.ErrorOrNil(); err != nil {
+		log.Errorf("failed to roll back services of env %s/%s, error: %v", projectName, envName, err)
+		return resp, e.ErrRollbackEnvServices.AddErr(err)
+	}
+	return resp, nil
+}
+
+func ListEnvRollbackRecords(projectName, envName string, production bool, serviceName string, pageNum, pageSize int64, log *zap.SugaredLogger) (*ListEnvRollbackRecordsResp, error) {
+	records, total, err := commonrepo.NewEnvRollbackRecordColl().List(&commonrepo.EnvRollbackRecordListOption{
+		ProductName: projectName,
+		EnvName:     envName,
+		Production:  production,
+		ServiceName: serviceName,
+		PageNum:     pageNum,
+		PageSize:    pageSize,
+	})
+	if err != nil {
+		log.Errorf("failed to list rollback records of env %s/%s, error: %v", projectName, envName, err)
+		return nil, e.ErrListEnvRollbackRecords.AddErr(err)
+	}
+	return &ListEnvRollbackRecordsResp{Records: records, Total: total}, nil
+}
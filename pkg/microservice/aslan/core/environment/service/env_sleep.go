@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/updater"
+)
+
+const (
+	kindHorizontalPodAutoscaler = "HorizontalPodAutoscaler"
+	kindScaledObject            = "ScaledObject"
+
+	kedaPausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+)
+
+var (
+	// the HPAs are read in the newest version supported by the cluster so that no field is lost when they are recreated
+	hpaGVKs = []schema.GroupVersionKind{
+		{Group: "autoscaling", Version: "v2", Kind: kindHorizontalPodAutoscaler},
+		{Group: "autoscaling", Version: "v2beta2", Kind: kindHorizontalPodAutoscaler},
+		{Group: "autoscaling", Version: "v1", Kind: kindHorizontalPodAutoscaler},
+	}
+	scaledObjectGVK = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: kindScaledObject}
+)
+
+func sleepTargetKey(kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// isCronJobSuspended returns whether the cronjob is suspended, false is returned if the cronjob is not found
+func isCronJobSuspended(namespace, name string, informer informers.SharedInformerFactory, versionLessThan121 bool) bool {
+	cronJob, cronJobBeta, err := getter.GetCronJobByNameWithCache(name, namespace, informer, versionLessThan121)
+	if err != nil {
+		return false
+	}
+	if cronJob != nil {
+		return cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+	}
+	if cronJobBeta != nil {
+		return cronJobBeta.Spec.Suspend != nil && *cronJobBeta.Spec.Suspend
+	}
+	return false
+}
+
+// pauseEnvAutoscalers pauses the HPAs and KEDA ScaledObjects targeting the sleeping workloads, otherwise they would
+// scale the workloads back up. targets are the keys of the workloads built by sleepTargetKey.
+func pauseEnvAutoscalers(namespace string, targets sets.String, kubeClient client.Client, log *zap.SugaredLogger) []*commonmodels.PreSleepAutoscaler {
+	resp := make([]*commonmodels.PreSleepAutoscaler, 0)
+
+	scaledObjects, err := getter.ListUnstructuredResourceInCache(namespace, nil, nil, scaledObjectGVK, kubeClient)
+	if err != nil && !meta.IsNoMatchError(err) {
+		log.Errorf("failed to list keda scaled objects in namespace %s: %s", namespace, err)
+	}
+	for _, scaledObject := range scaledObjects {
+		kind, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "kind")
+		name, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "name")
+		if kind == "" {
+			kind = setting.Deployment
+		}
+		if !targets.Has(sleepTargetKey(kind, name)) {
+			continue
+		}
+
+		autoscaler := &commonmodels.PreSleepAutoscaler{
+			Kind:           kindScaledObject,
+			Name:           scaledObject.GetName(),
+			PausedReplicas: scaledObject.GetAnnotations()[kedaPausedReplicasAnnotation],
+		}
+		log.Infof("pause keda scaled object %s", scaledObject.GetName())
+		if err := patchKedaPausedReplicas(scaledObject, "0", kubeClient); err != nil {
+			log.Errorf("failed to pause %s/scaledobject/%s: %s", namespace, scaledObject.GetName(), err)
+			continue
+		}
+		resp = append(resp, autoscaler)
+	}
+
+	var hpas []*unstructured.Unstructured
+	for _, gvk := range hpaGVKs {
+		hpas, err = getter.ListUnstructuredResourceInCache(namespace, nil, nil, gvk, kubeClient)
+		if err == nil || !meta.IsNoMatchError(err) {
+			break
+		}
+	}
+	if err != nil {
+		log.Errorf("failed to list hpas in namespace %s: %s", namespace, err)
+		return resp
+	}
+	for _, hpa := range hpas {
+		// the HPAs managed by KEDA are paused together with the ScaledObjects
+		ownedByKeda := false
+		for _, owner := range hpa.GetOwnerReferences() {
+			if owner.Kind == kindScaledObject {
+				ownedByKeda = true
+			}
+		}
+		kind, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "kind")
+		name, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "name")
+		if ownedByKeda || !targets.Has(sleepTargetKey(kind, name)) {
+			continue
+		}
+
+		manifest := hpa.DeepCopy()
+		unstructured.RemoveNestedField(manifest.Object, "status")
+		for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "managedFields", "generation", "selfLink"} {
+			unstructured.RemoveNestedField(manifest.Object, "metadata", field)
+		}
+		data, err := json.Marshal(manifest.Object)
+		if err != nil {
+			log.Errorf("failed to marshal %s/hpa/%s: %s", namespace, hpa.GetName(), err)
+			continue
+		}
+
+		log.Infof("remove hpa %s", hpa.GetName())
+		if err := updater.DeleteUnstructured(hpa, kubeClient); err != nil {
+			log.Errorf("failed to delete %s/hpa/%s: %s", namespace, hpa.GetName(), err)
+			continue
+		}
+		resp = append(resp, &commonmodels.PreSleepAutoscaler{
+			Kind:     kindHorizontalPodAutoscaler,
+			Name:     hpa.GetName(),
+			Manifest: string(data),
+		})
+	}
+
+	return resp
+}
+
+// resumeEnvAutoscalers restores the autoscalers paused by pauseEnvAutoscalers, the autoscalers failed to be restored are returned
+func resumeEnvAutoscalers(namespace string, autoscalers []*commonmodels.PreSleepAutoscaler, kubeClient client.Client, log *zap.SugaredLogger) []*commonmodels.PreSleepAutoscaler {
+	failed := make([]*commonmodels.PreSleepAutoscaler, 0)
+	for _, autoscaler := range autoscalers {
+		switch autoscaler.Kind {
+		case kindHorizontalPodAutoscaler:
+			u := &unstructured.Unstructured{}
+			if err := json.Unmarshal([]byte(autoscaler.Manifest), &u.Object); err != nil {
+				log.Errorf("failed to unmarshal the manifest of %s/hpa/%s: %s", namespace, autoscaler.Name, err)
+				continue
+			}
+			log.Infof("restore hpa %s", autoscaler.Name)
+			if err := updater.CreateOrPatchUnstructured(u, kubeClient); err != nil {
+				log.Errorf("failed to restore %s/hpa/%s: %s", namespace, autoscaler.Name, err)
+				failed = append(failed, autoscaler)
+			}
+		case kindScaledObject:
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(scaledObjectGVK)
+			u.SetNamespace(namespace)
+			u.SetName(autoscaler.Name)
+			log.Infof("resume keda scaled object %s", autoscaler.Name)
+			if err := patchKedaPausedReplicas(u, autoscaler.PausedReplicas, kubeClient); err != nil {
+				log.Errorf("failed to resume %s/scaledobject/%s: %s", namespace, autoscaler.Name, err)
+				failed = append(failed, autoscaler)
+			}
+		}
+	}
+	return failed
+}
+
+// patchKedaPausedReplicas sets the paused-replicas annotation of the ScaledObject, the annotation is removed if value is empty
+func patchKedaPausedReplicas(scaledObject *unstructured.Unstructured, value string, kubeClient client.Client) error {
+	var annotation interface{}
+	if value != "" {
+		annotation = value
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				kedaPausedReplicasAnnotation: annotation,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return updater.PatchUnstructured(scaledObject, patch, k8stypes.MergePatchType, kubeClient)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+// the number of scheduled snapshots kept for each env
+const scheduledEnvSnapshotRetention = 7
+
+type CreateEnvSnapshotArgs struct {
+	Description string `json:"description"`
+}
+
+type RestoreEnvSnapshotResp struct {
+	RestoredServices []string `json:"restored_services"`
+	// SkippedServices are the services in the snapshot which have been deleted from the env
+	SkippedServices []string `json:"skipped_services"`
+	// BackupSnapshotID is the snapshot of the env state before the restore
+	BackupSnapshotID string `json:"backup_snapshot_id"`
+}
+
+func newEnvSnapshot(env *commonmodels.Product, snapshotType commonmodels.EnvSnapshotType, description, username string) *commonmodels.EnvSnapshot {
+	return &commonmodels.EnvSnapshot{
+		ProductName:     env.ProductName,
+		EnvName:         env.EnvName,
+		Namespace:       env.Namespace,
+		Production:      env.Production,
+		Type:            snapshotType,
+		Description:     description,
+		Services:        env.Services,
+		DefaultValues:   env.DefaultValues,
+		YamlData:        env.YamlData,
+		GlobalVariables: env.GlobalVariables,
+		CreatedBy:       username,
+	}
+}
+
+func CreateEnvSnapshot(projectName, envName string, production bool, args *CreateEnvSnapshotArgs, username string, log *zap.SugaredLogger) (*commonmodels.EnvSnapshot, error) {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return nil, e.ErrCreateEnvSnapshot.AddErr(fmt.Errorf("failed to find env %s/%s, error: %v", projectName, envName, err))
+	}
+
+	snapshot := newEnvSnapshot(env, commonmodels.EnvSnapshotTypeManual, args.Description, username)
+	if err := commonrepo.NewEnvSnapshotColl().Create(snapshot); err != nil {
+		log.Errorf("failed to create snapshot for env %s/%s, error: %v", projectName, envName, err)
+		return nil, e.ErrCreateEnvSnapshot.AddErr(err)
+	}
+	return snapshot, nil
+}
+
+func ListEnvSnapshots(projectName, envName string, production bool, log *zap.SugaredLogger) ([]*commonmodels.EnvSnapshot, error) {
+	snapshots, err := commonrepo.NewEnvSnapshotColl().List(&commonrepo.EnvSnapshotListOption{
+		ProductName: projectName,
+		EnvName:     envName,
+		Production:  production,
+	})
+	if err != nil {
+		log.Errorf("failed to list snapshots of env %s/%s, error: %v", projectName, envName, err)
+		return nil, e.ErrListEnvSnapshots.AddErr(err)
+	}
+	return snapshots, nil
+}
+
+func GetEnvSnapshot(projectName, envName string, production bool, id string) (*commonmodels.EnvSnapshot, error) {
+	snapshot, err := commonrepo.NewEnvSnapshotColl().Find(projectName, envName, production, id)
+	if err != nil {
+		return nil, e.ErrListEnvSnapshots.AddErr(fmt.Errorf("failed to find snapshot %s of env %s/%s, error: %v", id, projectName, envName, err))
+	}
+	return snapshot, nil
+}
+
+func DeleteEnvSnapshot(projectName, envName string, production bool, id string) error {
+	if err := commonrepo.NewEnvSnapshotColl().Delete(projectName, envName, production, id); err != nil {
+		return e.ErrDeleteEnvSnapshot.AddErr(err)
+	}
+	return nil
+}
+
+// RestoreEnvSnapshot redeploys the services of the env with the service renders, variables and images recorded in the snapshot,
+// and restores the global variables of the env. Services added to the env after the snapshot are not touched.
+// The current state of the env is saved as a new snapshot before the restore so that the restore itself can be reverted.
+func RestoreEnvSnapshot(ctx *internalhandler.Context, projectName, envName string, production bool, id string, log *zap.SugaredLogger) (*RestoreEnvSnapshotResp, error) {
+	snapshot, err := commonrepo.NewEnvSnapshotColl().Find(projectName, envName, production, id)
+	if err != nil {
+		return nil, e.ErrRestoreEnvSnapshot.AddErr(fmt.Errorf("failed to find snapshot %s of env %s/%s, error: %v", id, projectName, envName, err))
+	}
+
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return nil, e.ErrRestoreEnvSnapshot.AddErr(fmt.Errorf("failed to find env %s/%s, error: %v", projectName, envName, err))
+	}
+	if env.Status == setting.ProductStatusCreating || env.Status == setting.ProductStatusDeleting {
+		return nil, e.ErrRestoreEnvSnapshot.AddDesc(fmt.Sprintf("env %s is %s, can not be restored", envName, env.Status))
+	}
+
+	backup := newEnvSnapshot(env, commonmodels.EnvSnapshotTypeManual, fmt.Sprintf("auto created before restoring snapshot %s", id), ctx.UserName)
+	if err := commonrepo.NewEnvSnapshotColl().Create(backup); err != nil {
+		return nil, e.ErrRestoreEnvSnapshot.AddErr(fmt.Errorf("failed to backup env %s/%s before restore, error: %v", projectName, envName, err))
+	}
+
+	resp := &RestoreEnvSnapshotResp{
+		RestoredServices: make([]string, 0),
+		SkippedServices:  make([]string, 0),
+		BackupSnapshotID: backup.ID.Hex(),
+	}
+
+	currentSvcMap, currentChartMap := env.GetServiceMap(), env.GetChartServiceMap()
+	errList := new(multierror.Error)
+	for _, group := range snapshot.Services {
+		for _, svc := range group {
+			name, exists := svc.ServiceName, false
+			if svc.FromZadig() {
+				_, exists = currentSvcMap[svc.ServiceName]
+			} else {
+				name = svc.ReleaseName
+				_, exists = currentChartMap[svc.ReleaseName]
+			}
+			if !exists {
+				resp.SkippedServices = append(resp.SkippedServices, name)
+				continue
+			}
+
+			envSvcVersion := &commonmodels.EnvServiceVersion{
+				ProductName:     snapshot.ProductName,
+				EnvName:         snapshot.EnvName,
+				Namespace:       env.Namespace,
+				Production:      snapshot.Production,
+				Service:         svc,
+				DefaultValues:   snapshot.DefaultValues,
+				YamlData:        snapshot.YamlData,
+				GlobalVariables: snapshot.GlobalVariables,
+			}
+			if err := rollbackEnvServiceToVersion(ctx, envSvcVersion, log); err != nil {
+				errList = multierror.Append(errList, fmt.Errorf("service %s: %v", name, err))
+				continue
+			}
+			resp.RestoredServices = append(resp.RestoredServices, name)
+		}
+	}
+
+	env.DefaultValues = snapshot.DefaultValues
+	env.YamlData = snapshot.YamlData
+	env.GlobalVariables = snapshot.GlobalVariables
+	if err := commonrepo.NewProductColl().UpdateProductVariables(env); err != nil {
+		errList = multierror.Append(errList, fmt.Errorf("failed to restore env variables: %v", err))
+	}
+
+	if err := errList.ErrorOrNil(); err != nil {
+		log.Errorf("failed to restore snapshot %s of env %s/%s, error: %v", id, projectName, envName, err)
+		return resp, e.ErrRestoreEnvSnapshot.AddErr(err)
+	}
+	return resp, nil
+}
+
+// RunScheduledEnvSnapshots captures a snapshot for every env and removes the outdated scheduled snapshots
+func RunScheduledEnvSnapshots() {
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+		ExcludeStatus: []string{setting.ProductStatusCreating, setting.ProductStatusDeleting},
+	})
+	if err != nil {
+		log.Errorf("failed to list envs for scheduled snapshots, error: %v", err)
+		return
+	}
+
+	coll := commonrepo.NewEnvSnapshotColl()
+	for _, env := range envs {
+		snapshot := newEnvSnapshot(env, commonmodels.EnvSnapshotTypeScheduled, "", setting.SystemUser)
+		if err := coll.Create(snapshot); err != nil {
+			log.Errorf("failed to create scheduled snapshot for env %s/%s, error: %v", env.ProductName, env.EnvName, err)
+			continue
+		}
+
+		err := coll.DeleteOutdated(&commonrepo.EnvSnapshotListOption{
+			ProductName: env.ProductName,
+			EnvName:     env.EnvName,
+			Production:  env.Production,
+			Type:        commonmodels.EnvSnapshotTypeScheduled,
+		}, scheduledEnvSnapshotRetention)
+		if err != nil {
+			log.Errorf("failed to delete outdated snapshots of env %s/%s, error: %v", env.ProductName, env.EnvName, err)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/notify"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// workflow params filled with the env info when the teardown workflow is triggered
+const (
+	EnvTeardownParamEnvName    = "TTL_ENV_NAME"
+	EnvTeardownParamNamespace  = "TTL_NAMESPACE"
+	EnvTeardownParamExpireTime = "TTL_EXPIRE_TIME"
+)
+
+type EnvTTLArgs struct {
+	// Hours is the lifetime of the env counted from now, the ttl is removed if it's 0
+	Hours            int    `json:"hours"`
+	TeardownWorkflow string `json:"teardown_workflow"`
+}
+
+type EnvTTLExtensionArgs struct {
+	Hours  int    `json:"hours"`
+	Reason string `json:"reason"`
+}
+
+type EnvTTLExtensionReviewArgs struct {
+	Approve bool `json:"approve"`
+}
+
+// Validate checks the ttl args, a nil args means no ttl.
+func (args *EnvTTLArgs) Validate(projectName string, production bool) error {
+	if args == nil || args.Hours == 0 {
+		return nil
+	}
+	if production {
+		return e.ErrInvalidParam.AddDesc("ttl is not supported for production environments")
+	}
+	if args.Hours < 0 {
+		return e.ErrInvalidParam.AddDesc("ttl hours must be a positive integer")
+	}
+	if args.TeardownWorkflow == "" {
+		return nil
+	}
+	wf, err := commonrepo.NewWorkflowV4Coll().Find(args.TeardownWorkflow)
+	if err != nil {
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("failed to find teardown workflow %s: %s", args.TeardownWorkflow, err))
+	}
+	if wf.Project != projectName {
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("teardown workflow %s doesn't belong to project %s", wf.Name, projectName))
+	}
+	return nil
+}
+
+func (args *EnvTTLArgs) toEnvTTL() *commonmodels.EnvTTL {
+	if args == nil || args.Hours == 0 {
+		return nil
+	}
+	return &commonmodels.EnvTTL{
+		ExpireTime:       time.Now().Add(time.Duration(args.Hours) * time.Hour).Unix(),
+		TeardownWorkflow: args.TeardownWorkflow,
+		Status:           config.EnvTTLStatusActive,
+	}
+}
+
+func UpdateEnvTTL(projectName, envName string, args *EnvTTLArgs) error {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: projectName, EnvName: envName})
+	if err != nil {
+		return e.ErrUpdateEnvTTL.AddErr(err)
+	}
+	if err := args.Validate(projectName, env.Production); err != nil {
+		return err
+	}
+	if env.TTL != nil && env.TTL.Status == config.EnvTTLStatusTearingDown {
+		return e.ErrUpdateEnvTTL.AddDesc("the environment is being torn down")
+	}
+
+	if err := commonrepo.NewProductColl().UpdateTTL(envName, projectName, args.toEnvTTL()); err != nil {
+		return e.ErrUpdateEnvTTL.AddErr(err)
+	}
+	return nil
+}
+
+// RequestEnvTTLExtension requests to postpone the expiry of the env, the project admins are notified to review it.
+func RequestEnvTTLExtension(projectName, envName, uid, username string, args *EnvTTLExtensionArgs, logger *zap.SugaredLogger) error {
+	if args.Hours <= 0 {
+		return e.ErrInvalidParam.AddDesc("extension hours must be a positive integer")
+	}
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: projectName, EnvName: envName})
+	if err != nil {
+		return e.ErrRequestEnvTTLExtension.AddErr(err)
+	}
+	if env.TTL == nil {
+		return e.ErrRequestEnvTTLExtension.AddDesc("the environment has no ttl")
+	}
+	if env.TTL.Status == config.EnvTTLStatusTearingDown {
+		return e.ErrRequestEnvTTLExtension.AddDesc("the environment is being torn down")
+	}
+	if env.TTL.Extension != nil && env.TTL.Extension.Status == config.EnvTTLExtensionPending {
+		return e.ErrRequestEnvTTLExtension.AddDesc("there is already a pending extension request")
+	}
+
+	// the extension is counted from now if the env has expired, e.g. the teardown workflow failed
+	base := env.TTL.ExpireTime
+	if now := time.Now().Unix(); base < now {
+		base = now
+	}
+	env.TTL.Extension = &commonmodels.EnvTTLExtension{
+		RequestedBy:     uid,
+		RequestedByName: username,
+		Reason:          args.Reason,
+		ExpireTime:      base + int64(args.Hours*60*60),
+		RequestTime:     time.Now().Unix(),
+		Status:          config.EnvTTLExtensionPending,
+	}
+	if err := commonrepo.NewProductColl().UpdateTTL(envName, projectName, env.TTL); err != nil {
+		return e.ErrRequestEnvTTLExtension.AddErr(err)
+	}
+
+	title := fmt.Sprintf("环境 %s/%s 申请延期", projectName, envName)
+	content := fmt.Sprintf("%s 申请将环境 %s 的有效期延长至 %s, 原因: %s, 请审批。", username, envName,
+		time.Unix(env.TTL.Extension.ExpireTime, 0).Format(time.RFC3339), args.Reason)
+	for _, receiver := range projectAdminUIDs(projectName, logger) {
+		notify.SendMessage(receiver, title, content, "", logger)
+	}
+	return nil
+}
+
+// ReviewEnvTTLExtension approves or rejects the pending extension request of the env.
+func ReviewEnvTTLExtension(projectName, envName, username string, args *EnvTTLExtensionReviewArgs, logger *zap.SugaredLogger) error {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: projectName, EnvName: envName})
+	if err != nil {
+		return e.ErrReviewEnvTTLExtension.AddErr(err)
+	}
+	if env.TTL == nil || env.TTL.Extension == nil || env.TTL.Extension.Status != config.EnvTTLExtensionPending {
+		return e.ErrReviewEnvTTLExtension.AddDesc("there is no pending extension request")
+	}
+	if env.TTL.Status == config.EnvTTLStatusTearingDown {
+		return e.ErrReviewEnvTTLExtension.AddDesc("the environment is being torn down")
+	}
+
+	extension := env.TTL.Extension
+	extension.ReviewedBy = username
+	extension.ReviewTime = time.Now().Unix()
+	result := "拒绝"
+	if args.Approve {
+		extension.Status = config.EnvTTLExtensionApproved
+		env.TTL.ExpireTime = extension.ExpireTime
+		env.TTL.Status = config.EnvTTLStatusActive
+		env.TTL.TeardownTaskID = 0
+		result = "通过"
+	} else {
+		extension.Status = config.EnvTTLExtensionRejected
+	}
+	if err := commonrepo.NewProductColl().UpdateTTL(envName, projectName, env.TTL); err != nil {
+		return e.ErrReviewEnvTTLExtension.AddErr(err)
+	}
+
+	title := fmt.Sprintf("环境 %s/%s 延期申请已%s", projectName, envName, result)
+	content := fmt.Sprintf("%s 已%s环境 %s 的延期申请, 当前有效期至 %s。", username, result, envName,
+		time.Unix(env.TTL.ExpireTime, 0).Format(time.RFC3339))
+	notify.SendMessage(extension.RequestedBy, title, content, "", logger)
+	return nil
+}
+
+// processExpiredEnvs tears down the expired envs. The teardown workflow is triggered first if configured, and the env
+// is deleted once the workflow passes, otherwise the env is deleted directly.
+func processExpiredEnvs(requestID string, logger *zap.SugaredLogger) {
+	envs, err := commonrepo.NewProductColl().ListWithTTL()
+	if err != nil {
+		logger.Errorf("failed to list envs with ttl: %s", err)
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, env := range envs {
+		switch env.TTL.Status {
+		case config.EnvTTLStatusActive, "":
+			if env.TTL.ExpireTime > now {
+				continue
+			}
+			if env.TTL.TeardownWorkflow == "" {
+				deleteExpiredEnv(env, requestID, logger)
+				continue
+			}
+			taskID, err := triggerEnvTeardownWorkflow(env, logger)
+			if err != nil {
+				logger.Errorf("failed to trigger teardown workflow %s for env %s/%s: %s", env.TTL.TeardownWorkflow, env.ProductName, env.EnvName, err)
+				failEnvTeardown(env, fmt.Sprintf("启动销毁工作流 %s 失败: %s", env.TTL.TeardownWorkflow, err), logger)
+				continue
+			}
+			env.TTL.Status = config.EnvTTLStatusTearingDown
+			env.TTL.TeardownTaskID = taskID
+			if err := commonrepo.NewProductColl().UpdateTTL(env.EnvName, env.ProductName, env.TTL); err != nil {
+				logger.Errorf("failed to update ttl of env %s/%s: %s", env.ProductName, env.EnvName, err)
+			}
+		case config.EnvTTLStatusTearingDown:
+			task, err := commonrepo.NewworkflowTaskv4Coll().Find(env.TTL.TeardownWorkflow, env.TTL.TeardownTaskID)
+			if err != nil {
+				logger.Errorf("failed to find teardown task %s#%d of env %s/%s: %s", env.TTL.TeardownWorkflow, env.TTL.TeardownTaskID, env.ProductName, env.EnvName, err)
+				continue
+			}
+			switch {
+			case task.Status == config.StatusPassed:
+				deleteExpiredEnv(env, requestID, logger)
+			case isTeardownTaskFailed(task.Status):
+				failEnvTeardown(env, fmt.Sprintf("销毁工作流 %s#%d 执行状态: %s", env.TTL.TeardownWorkflow, env.TTL.TeardownTaskID, task.Status), logger)
+			}
+		}
+	}
+}
+
+func triggerEnvTeardownWorkflow(env *commonmodels.Product, logger *zap.SugaredLogger) (int64, error) {
+	wf, err := commonrepo.NewWorkflowV4Coll().Find(env.TTL.TeardownWorkflow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find workflow %s: %s", env.TTL.TeardownWorkflow, err)
+	}
+
+	values := map[string]string{
+		EnvTeardownParamEnvName:    env.EnvName,
+		EnvTeardownParamNamespace:  env.Namespace,
+		EnvTeardownParamExpireTime: time.Unix(env.TTL.ExpireTime, 0).Format(time.RFC3339),
+	}
+	for _, param := range wf.Params {
+		if value, ok := values[param.Name]; ok {
+			param.Value = value
+		}
+	}
+
+	resp, err := workflow.CreateWorkflowTaskV4(&workflow.CreateWorkflowTaskV4Args{
+		Name: setting.SystemUser,
+	}, wf, logger)
+	if err != nil {
+		return 0, err
+	}
+	return resp.TaskID, nil
+}
+
+func deleteExpiredEnv(env *commonmodels.Product, requestID string, logger *zap.SugaredLogger) {
+	if err := DeleteProduct("robot", env.EnvName, env.ProductName, requestID, true, logger); err != nil {
+		logger.Errorf("failed to delete expired env %s/%s: %s", env.ProductName, env.EnvName, err)
+		failEnvTeardown(env, fmt.Sprintf("删除环境失败: %s", err), logger)
+		return
+	}
+	logger.Infof("expired env %s/%s deleted", env.ProductName, env.EnvName)
+
+	title := fmt.Sprintf("环境 %s/%s 已到期销毁", env.ProductName, env.EnvName)
+	content := fmt.Sprintf("环境 %s 已于 %s 到期, 系统已自动销毁该环境。", env.EnvName, time.Unix(env.TTL.ExpireTime, 0).Format(time.RFC3339))
+	notifyEnvOwner(env, title, content, logger)
+}
+
+// failEnvTeardown marks the teardown as failed so it's not retried automatically, the env can be kept by extending its ttl
+// or deleted manually.
+func failEnvTeardown(env *commonmodels.Product, reason string, logger *zap.SugaredLogger) {
+	env.TTL.Status = config.EnvTTLStatusTeardownFailed
+	if err := commonrepo.NewProductColl().UpdateTTL(env.EnvName, env.ProductName, env.TTL); err != nil {
+		logger.Errorf("failed to update ttl of env %s/%s: %s", env.ProductName, env.EnvName, err)
+	}
+
+	title := fmt.Sprintf("环境 %s/%s 到期销毁失败", env.ProductName, env.EnvName)
+	content := fmt.Sprintf("环境 %s 已到期, 但自动销毁失败, 请手动处理。%s", env.EnvName, reason)
+	notifyEnvOwner(env, title, content, logger)
+}
+
+func notifyEnvOwner(env *commonmodels.Product, title, content string, logger *zap.SugaredLogger) {
+	if env.Owner == nil {
+		return
+	}
+	receivers := []string{env.Owner.ID}
+	if env.Owner.Type == config.OwnerTypeGroup {
+		group, err := user.New().GetGroupDetailedInfo(env.Owner.ID)
+		if err != nil {
+			logger.Warnf("failed to get user group %s, err: %s", env.Owner.ID, err)
+			return
+		}
+		receivers = group.UIDs
+	}
+	for _, receiver := range receivers {
+		notify.SendMessage(receiver, title, content, "", logger)
+	}
+}
+
+func projectAdminUIDs(projectName string, logger *zap.SugaredLogger) []string {
+	bindings, err := user.New().ListRoleBindings(projectName)
+	if err != nil {
+		logger.Errorf("failed to list role bindings of project %s, err: %s", projectName, err)
+		return nil
+	}
+
+	receivers := sets.NewString()
+	for _, binding := range bindings {
+		if !sets.NewString(binding.Roles...).Has(string(setting.ProjectAdmin)) {
+			continue
+		}
+		switch {
+		case binding.UserInfo != nil:
+			receivers.Insert(binding.UserInfo.UID)
+		case binding.GroupInfo != nil:
+			group, err := user.New().GetGroupDetailedInfo(binding.GroupInfo.GID)
+			if err != nil {
+				logger.Warnf("failed to get user group %s, err: %s", binding.GroupInfo.GID, err)
+				continue
+			}
+			receivers.Insert(group.UIDs...)
+		}
+	}
+	return receivers.List()
+}
+
+func isTeardownTaskFailed(status config.Status) bool {
+	for _, failed := range config.FailedStatus() {
+		if status == failed {
+			return true
+		}
+	}
+	return false
+}
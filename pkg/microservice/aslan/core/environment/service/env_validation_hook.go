@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	configbase "github.com/koderover/zadig/v2/pkg/config"
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	commontypes "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/types"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/httpclient"
+)
+
+const (
+	EnvValidationTypeDefaultValues   = "default_values"
+	EnvValidationTypeGlobalVariables = "global_variables"
+
+	envValidationHookEvent          = "env_validation"
+	defaultEnvValidationHookTimeout = 10
+)
+
+// EnvValidationHookPayload is sent to the env validation hook of the project with the proposed values
+type EnvValidationHookPayload struct {
+	ProjectName     string                          `json:"project_name"`
+	EnvName         string                          `json:"env_name"`
+	Production      bool                            `json:"production"`
+	Operator        string                          `json:"operator"`
+	Type            string                          `json:"type"`
+	DefaultValues   string                          `json:"default_values,omitempty"`
+	ValuesData      *commonservice.ValuesDataArgs   `json:"values_data,omitempty"`
+	GlobalVariables []*commontypes.GlobalVariableKV `json:"global_variables,omitempty"`
+}
+
+// EnvValidationHookResponse is the response expected from the env validation hook
+type EnvValidationHookResponse struct {
+	Allowed bool   `json:"allowed"`
+	Message string `json:"message"`
+}
+
+// validateEnvByHook sends the proposed values to the env validation hook of the project and returns an error if
+// the hook rejects them. If the hook can not be reached the update is rejected too, unless the hook is set to fail open.
+func validateEnvByHook(project *templatemodels.Product, payload *EnvValidationHookPayload, log *zap.SugaredLogger) error {
+	hook := project.EnvValidationHook
+	if hook == nil || !hook.Enable || hook.URL == "" {
+		return nil
+	}
+
+	timeout := hook.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = defaultEnvValidationHookTimeout
+	}
+	client := httpclient.New()
+	client.SetTimeout(time.Duration(timeout) * time.Second)
+
+	result := new(EnvValidationHookResponse)
+	_, err := client.Post(hook.URL,
+		httpclient.SetBody(payload),
+		httpclient.SetResult(result),
+		httpclient.SetHeader(webhooknotify.TokenHeader, hook.Token),
+		httpclient.SetHeader(webhooknotify.InstanceHeader, configbase.SystemAddress()),
+		httpclient.SetHeader(webhooknotify.EventHeader, envValidationHookEvent),
+		httpclient.SetHeader(webhooknotify.EventUUIDHeader, uuid.New().String()),
+	)
+	if err != nil {
+		log.Errorf("failed to call env validation hook %s of project %s, error: %s", hook.URL, project.ProductName, err)
+		if hook.FailOpen {
+			return nil
+		}
+		return e.ErrUpdateEnv.AddDesc(fmt.Sprintf("failed to call env validation hook: %s", err))
+	}
+
+	if !result.Allowed {
+		msg := result.Message
+		if msg == "" {
+			msg = "no message given"
+		}
+		return e.ErrUpdateEnv.AddDesc(fmt.Sprintf("rejected by env validation hook: %s", msg))
+	}
+	return nil
+}
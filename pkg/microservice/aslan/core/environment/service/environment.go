@@ -188,6 +188,7 @@ func ListProducts(userID, projectName string, envNames []string, production bool
 			IstioGrayscaleIsBase:  env.IstioGrayscale.IsBase,
 			IstioGrayscaleBaseEnv: env.IstioGrayscale.BaseEnv,
 			IsFavorite:            favSet.Has(env.EnvName),
+			FluxStatuses:          ListFluxResourceStatuses(env, log),
 		})
 	}
 
@@ -954,6 +955,9 @@ func UpdateCVMProduct(envName, productName, user, requestID string, log *zap.Sug
 // CreateProduct create a new product with its dependent stacks
 func CreateProduct(user, requestID string, args *ProductCreateArg, log *zap.SugaredLogger) (err error) {
 	log.Infof("[%s][P:%s] CreateProduct", args.EnvName, args.ProductName)
+	if err := commonservice.CheckSandboxEnvQuota(args.ProductName); err != nil {
+		return err
+	}
 	creator := getCreatorBySource(args.Source)
 	args.UpdateBy = user
 	return creator.Create(user, requestID, args, log)
@@ -1630,6 +1634,27 @@ func UpdateProductDefaultValues(productName, envName, userName, requestID string
 		return e.ErrUpdateEnv.AddDesc(fmt.Sprintf("failed to validate args: %s", err))
 	}
 
+	if err = validateEnvHelmValues(product, args.DefaultValues, nil, false); err != nil {
+		return err
+	}
+
+	project, err := templaterepo.NewProductColl().Find(productName)
+	if err != nil {
+		return e.ErrUpdateEnv.AddErr(fmt.Errorf("failed to find project: %s, error: %s", productName, err))
+	}
+	err = validateEnvByHook(project, &EnvValidationHookPayload{
+		ProjectName:   productName,
+		EnvName:       envName,
+		Production:    production,
+		Operator:      userName,
+		Type:          EnvValidationTypeDefaultValues,
+		DefaultValues: args.DefaultValues,
+		ValuesData:    args.ValuesData,
+	}, log)
+	if err != nil {
+		return err
+	}
+
 	err = UpdateProductDefaultValuesWithRender(product, nil, userName, requestID, args, production, log)
 	if err != nil {
 		return e.ErrUpdateEnv.AddErr(err)
@@ -1736,6 +1761,10 @@ func UpdateHelmProductCharts(productName, envName, userName, requestID string, p
 			updatedRcMap[serviceName] = rcValues
 		}
 
+		if err := validateEnvHelmValues(product, product.DefaultValues, updatedRcMap, args.UpdateServiceTmpl); err != nil {
+			return err
+		}
+
 		// update service to latest revision acts like update service templates
 		if args.UpdateServiceTmpl {
 			updateEnvArg := &UpdateMultiHelmProductArg{
@@ -2125,6 +2154,11 @@ func DeleteProduct(username, envName, productName, requestID string, isDelete bo
 		log.Errorf("deleteEnvSleepCron error: %v", err)
 	}
 
+	err = commonrepo.NewFirstDeployHookRecordColl().DeleteByEnv(productInfo.ProductName, productInfo.EnvName)
+	if err != nil {
+		log.Errorf("failed to delete first deploy hook records, error: %v", err)
+	}
+
 	ctx := context.TODO()
 	switch productInfo.Source {
 	case setting.SourceFromHelm:
@@ -2787,7 +2821,16 @@ func buildInstallParam(defaultValues string, productInfo *commonmodels.Product,
 		ret.ReleaseName = renderChart.ReleaseName
 	}
 
-	mergedValues, err := commonutil.GeneHelmMergedValues(productSvc, defaultValues, renderChart)
+	// the layers encrypted by sops are decrypted before being merged, the merged values are only kept in memory
+	defaultValues, err := kube.DecryptSopsValues(defaultValues)
+	if err != nil {
+		return ret, err
+	}
+	decryptedRenderChart, err := kube.DecryptSopsRender(renderChart)
+	if err != nil {
+		return ret, err
+	}
+	mergedValues, err := commonutil.GeneHelmMergedValues(productSvc, defaultValues, decryptedRenderChart)
 	if err != nil {
 		return ret, err
 	}
@@ -3381,6 +3424,18 @@ func UpdateProductGlobalVariables(productName, envName, userName, requestID stri
 		return e.ErrUpdateEnv.AddErr(fmt.Errorf("failed to find project: %s, error: %s", productName, err))
 	}
 
+	err = validateEnvByHook(project, &EnvValidationHookPayload{
+		ProjectName:     productName,
+		EnvName:         envName,
+		Production:      production,
+		Operator:        userName,
+		Type:            EnvValidationTypeGlobalVariables,
+		GlobalVariables: arg,
+	}, log)
+	if err != nil {
+		return err
+	}
+
 	err = UpdateProductGlobalVariablesWithRender(project, product, nil, userName, requestID, arg, log)
 	if err != nil {
 		return e.ErrUpdateEnv.AddErr(err)
@@ -4178,6 +4233,14 @@ func EnvSleep(productName, envName string, isEnable, isProduction bool, log *zap
 
 	oldScaleNumMap := make(map[string]int)
 	newScaleNumMap := make(map[string]int)
+	oldPreSleepState := &commonmodels.EnvPreSleepState{}
+	newPreSleepState := &commonmodels.EnvPreSleepState{
+		SuspendedCronJobs: make([]string, 0),
+		Autoscalers:       make([]*commonmodels.PreSleepAutoscaler, 0),
+	}
+	if prod.PreSleepState != nil {
+		oldPreSleepState = prod.PreSleepState
+	}
 	prod.Status = setting.ProductStatusSleeping
 	if !isEnable {
 		oldScaleNumMap = prod.PreSleepStatus
@@ -4313,6 +4376,17 @@ func EnvSleep(productName, envName string, isEnable, isProduction bool, log *zap
 		})
 	}
 
+	// pause the autoscalers before the workloads are scaled down, the autoscalers failed to be restored on last wake are kept
+	if isEnable {
+		sleepTargets := sets.NewString()
+		for _, workload := range workLoads {
+			if workload.DeployedFromZadig && (workload.Type == setting.Deployment || workload.Type == setting.StatefulSet) {
+				sleepTargets.Insert(sleepTargetKey(workload.Type, workload.Name))
+			}
+		}
+		newPreSleepState.Autoscalers = append(oldPreSleepState.Autoscalers, pauseEnvAutoscalers(prod.Namespace, sleepTargets, kubeClient, log)...)
+	}
+
 	for _, workload := range workLoads {
 		if !workload.DeployedFromZadig {
 			continue
@@ -4339,12 +4413,19 @@ func EnvSleep(productName, envName string, isEnable, isProduction bool, log *zap
 			}
 		case setting.CronJob:
 			if isEnable {
+				if isCronJobSuspended(prod.Namespace, workload.Name, informer, kubeclient.VersionLessThan121(version)) {
+					newPreSleepState.SuspendedCronJobs = append(newPreSleepState.SuspendedCronJobs, workload.Name)
+					continue
+				}
 				log.Infof("suspend cronjob %s", workload.Name)
 				err := updater.SuspendCronJob(prod.Namespace, workload.Name, kubeClient, kubeclient.VersionLessThan121(version))
 				if err != nil {
 					log.Errorf("failed to suspend %s/cronjob/%s", prod.Namespace, workload.Name)
 				}
 			} else {
+				if sets.NewString(oldPreSleepState.SuspendedCronJobs...).Has(workload.Name) {
+					continue
+				}
 				log.Infof("resume cronjob %s", workload.Name)
 				err := updater.ResumeCronJob(prod.Namespace, workload.Name, kubeClient, kubeclient.VersionLessThan121(version))
 				if err != nil {
@@ -4354,7 +4435,13 @@ func EnvSleep(productName, envName string, isEnable, isProduction bool, log *zap
 		}
 	}
 
+	// restore the autoscalers after the workloads are scaled up
+	if !isEnable {
+		newPreSleepState.Autoscalers = resumeEnvAutoscalers(prod.Namespace, oldPreSleepState.Autoscalers, kubeClient, log)
+	}
+
 	prod.PreSleepStatus = newScaleNumMap
+	prod.PreSleepState = newPreSleepState
 	err = commonrepo.NewProductColl().Update(prod)
 	if err != nil {
 		wrapErr := fmt.Errorf("failed to update product, err: %w", err)
@@ -149,6 +149,7 @@ func BulkCopyYamlProduct(projectName, user, requestID string, arg CopyYamlProduc
 			newProduct.BaseName = item.BaseName
 			newProduct.GlobalVariables = item.GlobalVariables
 			newProduct.DefaultValues = item.DefaultValues
+			newProduct.TTL = nil
 
 			svcVariableKVMap := make(map[string][]*commontypes.RenderVariableKV)
 			for _, sv := range item.Services {
@@ -200,6 +201,10 @@ func CopyYamlProduct(user, requestID, projectName string, args []*CreateSinglePr
 			return e.ErrCreateEnv.AddErr(fmt.Errorf("failed to find base environment: %s, err: %s", arg.BaseEnvName, err))
 		}
 
+		if err := resolveClusterRemap(baseProject, arg); err != nil {
+			return e.ErrCreateEnv.AddErr(err)
+		}
+
 		// use service revision defined in base environment
 		servicesInBaseNev := baseProject.GetServiceMap()
 
@@ -209,6 +214,9 @@ func CopyYamlProduct(user, requestID, projectName string, args []*CreateSinglePr
 					svc.Revision = baseSvc.Revision
 					svc.ProductName = baseSvc.ProductName
 				}
+				if err := remapContainerImages(svc.Containers, baseProject.RegistryID, arg.RegistryID); err != nil {
+					return e.ErrCreateEnv.AddErr(err)
+				}
 			}
 		}
 
@@ -246,6 +254,10 @@ func CopyHelmProduct(productName, userName, requestID string, args []*CreateSing
 			errList = multierror.Append(errList, fmt.Errorf("failed to query base product info name :%s,envname:%s", productName, arg.BaseName))
 			continue
 		}
+		if err := resolveClusterRemap(baseProduct, arg); err != nil {
+			errList = multierror.Append(errList, err)
+			continue
+		}
 		templateSvcs, err := commonutil.GetProductUsedTemplateSvcs(baseProduct)
 		templateServiceMap := make(map[string]*commonmodels.Service)
 		for _, svc := range templateSvcs {
@@ -281,6 +293,13 @@ func copySingleHelmProduct(templateProduct *templatemodels.Product, productInfo
 	productInfo.BaseName = arg.BaseName
 	productInfo.Namespace = commonservice.GetProductEnvNamespace(arg.EnvName, arg.ProductName, arg.Namespace)
 	productInfo.EnvConfigs = arg.EnvConfigs
+	productInfo.Owner = arg.Owner
+	productInfo.TTL = arg.TTL.toEnvTTL()
+	productInfo.ClusterRemap = arg.ClusterRemap
+	sourceRegistryID := productInfo.RegistryID
+	if arg.RegistryID != "" {
+		productInfo.RegistryID = arg.RegistryID
+	}
 
 	// merge chart infos, use chart info in product to override charts in template_project
 	sourceChartMap := make(map[string]*templatemodels.ServiceRender)
@@ -301,6 +320,9 @@ func copySingleHelmProduct(templateProduct *templatemodels.Product, productInfo
 	if err != nil {
 		return err
 	}
+	if err := remapHelmProduct(productInfo, sourceRegistryID); err != nil {
+		return e.ErrCreateEnv.AddErr(err)
+	}
 
 	return CreateProduct(userName, requestID, &ProductCreateArg{productInfo, nil}, log)
 }
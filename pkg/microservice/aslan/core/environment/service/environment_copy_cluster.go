@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	helmtool "github.com/koderover/zadig/v2/pkg/tool/helmclient"
+	yamlutil "github.com/koderover/zadig/v2/pkg/util/yaml"
+)
+
+const (
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+)
+
+type clusterClasses struct {
+	storageClasses      sets.String
+	defaultStorageClass string
+	ingressClasses      sets.String
+	defaultIngressClass string
+}
+
+// resolveClusterRemap completes the remap rules of the env copied from baseEnv. When the env is copied into another
+// cluster, the storage classes and ingress classes which don't exist in the target cluster are mapped to its default ones
+// unless they are mapped explicitly.
+func resolveClusterRemap(baseEnv *commonmodels.Product, arg *CreateSingleProductArg) error {
+	if arg.ClusterID == "" || arg.ClusterID == baseEnv.ClusterID {
+		if arg.ClusterRemap.IsEmpty() {
+			arg.ClusterRemap = nil
+		}
+		return nil
+	}
+
+	source, err := listClusterClasses(baseEnv.ClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to list classes in source cluster %s: %s", baseEnv.ClusterID, err)
+	}
+	target, err := listClusterClasses(arg.ClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to list classes in target cluster %s: %s", arg.ClusterID, err)
+	}
+
+	rules := arg.ClusterRemap
+	if rules == nil {
+		rules = &commonmodels.ClusterRemapRules{}
+	}
+	rules.StorageClasses = completeClassMapping(rules.StorageClasses, source.storageClasses, target.storageClasses, target.defaultStorageClass)
+	rules.IngressClasses = completeClassMapping(rules.IngressClasses, source.ingressClasses, target.ingressClasses, target.defaultIngressClass)
+	if rules.IsEmpty() {
+		rules = nil
+	}
+	arg.ClusterRemap = rules
+	return nil
+}
+
+func completeClassMapping(mapping map[string]string, source, target sets.String, targetDefault string) map[string]string {
+	if mapping == nil {
+		mapping = make(map[string]string)
+	}
+	if targetDefault == "" {
+		return mapping
+	}
+	for _, class := range source.List() {
+		if _, ok := mapping[class]; ok || target.Has(class) {
+			continue
+		}
+		mapping[class] = targetDefault
+	}
+	return mapping
+}
+
+func listClusterClasses(clusterID string) (*clusterClasses, error) {
+	kclient, err := kubeclient.GetKubeClient(config.HubServerAddress(), clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube client: %s", err)
+	}
+
+	// cluster-level resources of attached clusters are listed through the namespace of the agent
+	namespace := ""
+	if clusterID != setting.LocalClusterID {
+		namespace = setting.AttachedClusterNamespace
+	}
+
+	ret := &clusterClasses{
+		storageClasses: sets.NewString(),
+		ingressClasses: sets.NewString(),
+	}
+	scList := &storagev1.StorageClassList{}
+	if err := kclient.List(context.TODO(), scList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list storageclasses: %s", err)
+	}
+	for _, sc := range scList.Items {
+		ret.storageClasses.Insert(sc.Name)
+		if sc.Annotations[defaultStorageClassAnnotation] == "true" {
+			ret.defaultStorageClass = sc.Name
+		}
+	}
+
+	icList := &networkingv1.IngressClassList{}
+	if err := kclient.List(context.TODO(), icList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list ingressclasses: %s", err)
+	}
+	for _, ic := range icList.Items {
+		ret.ingressClasses.Insert(ic.Name)
+		if ic.Annotations[defaultIngressClassAnnotation] == "true" {
+			ret.defaultIngressClass = ic.Name
+		}
+	}
+	return ret, nil
+}
+
+// remapContainerImages replaces the registry of the images when the env is copied with another registry.
+func remapContainerImages(containers []*commonmodels.Container, sourceRegistryID, targetRegistryID string) error {
+	if sourceRegistryID == "" || targetRegistryID == "" || sourceRegistryID == targetRegistryID {
+		return nil
+	}
+	sourcePrefix, err := registryImagePrefix(sourceRegistryID)
+	if err != nil {
+		return err
+	}
+	targetPrefix, err := registryImagePrefix(targetRegistryID)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		if strings.HasPrefix(container.Image, sourcePrefix+"/") {
+			container.Image = targetPrefix + strings.TrimPrefix(container.Image, sourcePrefix)
+		}
+	}
+	return nil
+}
+
+func registryImagePrefix(registryID string) (string, error) {
+	registry, err := commonrepo.NewRegistryNamespaceColl().Find(&commonrepo.FindRegOps{ID: registryID})
+	if err != nil {
+		return "", fmt.Errorf("failed to find registry %s: %s", registryID, err)
+	}
+	addr, err := registry.GetRegistryAddress()
+	if err != nil {
+		return "", err
+	}
+	if registry.Namespace == "" {
+		return addr, nil
+	}
+	return fmt.Sprintf("%s/%s", addr, registry.Namespace), nil
+}
+
+// remapHelmProduct remaps the images and the storage classes and ingress classes set in the helm values of the copied env,
+// the remapped values are merged into the override yaml of the services so they are kept in later upgrades.
+func remapHelmProduct(productInfo *commonmodels.Product, sourceRegistryID string) error {
+	for _, svc := range productInfo.GetServiceMap() {
+		if err := remapContainerImages(svc.Containers, sourceRegistryID, productInfo.RegistryID); err != nil {
+			return err
+		}
+
+		render := svc.GetServiceRender()
+		if productInfo.ClusterRemap.IsEmpty() || render == nil {
+			continue
+		}
+		fullValues, err := helmtool.MergeOverrideValues(render.ValuesYaml, productInfo.DefaultValues, render.GetOverrideYaml(), render.OverrideValues, nil)
+		if err != nil {
+			return fmt.Errorf("failed to merge values of service %s: %s", svc.ServiceName, err)
+		}
+		remapped, err := kube.RemapHelmValues(fullValues, productInfo.ClusterRemap)
+		if err != nil {
+			return fmt.Errorf("failed to remap values of service %s: %s", svc.ServiceName, err)
+		}
+		if remapped == "" {
+			continue
+		}
+		merged, err := yamlutil.Merge([][]byte{[]byte(render.GetOverrideYaml()), []byte(remapped)})
+		if err != nil {
+			return fmt.Errorf("failed to merge remapped values of service %s: %s", svc.ServiceName, err)
+		}
+		if render.OverrideYaml == nil {
+			render.OverrideYaml = &templatemodels.CustomYaml{}
+		}
+		render.OverrideYaml.YamlContent = string(merged)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	commontypes "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/types"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+type CopyEnvToProjectArgs struct {
+	TargetProject string `json:"target_project"`
+	EnvName       string `json:"env_name"`
+	ClusterID     string `json:"cluster_id"`
+	Namespace     string `json:"namespace"`
+	RegistryID    string `json:"registry_id"`
+	// ServiceMapping maps the services in the source env to the services in the target project, the services which
+	// are not in the mapping are copied to the services with the same name. A service mapped to "" is not copied.
+	ServiceMapping map[string]string           `json:"service_mapping"`
+	Owner          *commonmodels.ResourceOwner `json:"owner"`
+}
+
+// CopyEnvToProject copies the services, variables and values of an env into another project which shares the same
+// service templates, the env is created in the target project with the latest revisions of the target services.
+func CopyEnvToProject(sourceProject, sourceEnvName, userName, requestID string, args *CopyEnvToProjectArgs, log *zap.SugaredLogger) error {
+	if args.TargetProject == "" || args.EnvName == "" {
+		return e.ErrInvalidParam.AddDesc("target project and env name are required")
+	}
+	if args.TargetProject == sourceProject {
+		return e.ErrInvalidParam.AddDesc("target project should be different from the source project")
+	}
+
+	sourceEnv, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       sourceProject,
+		EnvName:    sourceEnvName,
+		Production: util.GetBoolPointer(false),
+	})
+	if err != nil {
+		return e.ErrCreateEnv.AddDesc(fmt.Sprintf("failed to find env %s/%s: %s", sourceProject, sourceEnvName, err))
+	}
+	sourceTemplate, err := templaterepo.NewProductColl().Find(sourceProject)
+	if err != nil {
+		return e.ErrCreateEnv.AddDesc(fmt.Sprintf("failed to find project %s: %s", sourceProject, err))
+	}
+	targetTemplate, err := templaterepo.NewProductColl().Find(args.TargetProject)
+	if err != nil {
+		return e.ErrCreateEnv.AddDesc(fmt.Sprintf("failed to find project %s: %s", args.TargetProject, err))
+	}
+	if sourceTemplate.IsHelmProduct() != targetTemplate.IsHelmProduct() || sourceTemplate.IsK8sYamlProduct() != targetTemplate.IsK8sYamlProduct() {
+		return e.ErrCreateEnv.AddDesc("the source project and the target project should be of the same type")
+	}
+	if !sourceTemplate.IsHelmProduct() && !sourceTemplate.IsK8sYamlProduct() {
+		return e.ErrCreateEnv.AddDesc("only k8s yaml and helm projects are supported")
+	}
+
+	targetServices, err := commonrepo.NewServiceColl().ListMaxRevisionsByProduct(args.TargetProject)
+	if err != nil {
+		return e.ErrCreateEnv.AddErr(err)
+	}
+	targetServiceMap := make(map[string]*commonmodels.Service)
+	for _, svc := range targetServices {
+		targetServiceMap[svc.ServiceName] = svc
+	}
+
+	// resolve the service mapping first so that all the missing services are reported together
+	serviceMapping := make(map[string]string)
+	missing := make([]string, 0)
+	for _, svc := range sourceEnv.GetSvcList() {
+		if !svc.FromZadig() {
+			continue
+		}
+		target, ok := args.ServiceMapping[svc.ServiceName]
+		if !ok {
+			target = svc.ServiceName
+		}
+		if target == "" {
+			continue
+		}
+		if _, ok := targetServiceMap[target]; !ok {
+			missing = append(missing, svc.ServiceName)
+			continue
+		}
+		serviceMapping[svc.ServiceName] = target
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return e.ErrCreateEnv.AddDesc(fmt.Sprintf("services %s are not found in project %s, please map them to the services in the target project", strings.Join(missing, ","), args.TargetProject))
+	}
+
+	arg := &CreateSingleProductArg{
+		ProductName:   args.TargetProject,
+		EnvName:       args.EnvName,
+		Namespace:     args.Namespace,
+		ClusterID:     args.ClusterID,
+		RegistryID:    args.RegistryID,
+		DefaultValues: sourceEnv.DefaultValues,
+		Owner:         args.Owner,
+	}
+	if arg.ClusterID == "" {
+		arg.ClusterID = sourceEnv.ClusterID
+	}
+	if arg.RegistryID == "" {
+		arg.RegistryID = sourceEnv.RegistryID
+	}
+	if err := resolveClusterRemap(sourceEnv, arg); err != nil {
+		return e.ErrCreateEnv.AddErr(err)
+	}
+
+	if targetTemplate.IsHelmProduct() {
+		for _, svc := range sourceEnv.GetSvcList() {
+			target, ok := serviceMapping[svc.ServiceName]
+			if !ok {
+				continue
+			}
+			renderArg := &commonservice.HelmSvcRenderArg{}
+			renderArg.LoadFromRenderChartModel(svc.GetServiceRender())
+			renderArg.ServiceName = target
+			renderArg.EnvName = args.EnvName
+			renderArg.ReleaseName = ""
+			arg.ChartValues = append(arg.ChartValues, &ProductHelmServiceCreationInfo{
+				HelmSvcRenderArg: renderArg,
+				DeployStrategy:   setting.ServiceDeployStrategyDeploy,
+			})
+		}
+		return CreateHelmProduct(args.TargetProject, userName, requestID, []*CreateSingleProductArg{arg}, log)
+	}
+
+	relatedServices := make(map[string]sets.String)
+	for _, group := range sourceEnv.Services {
+		services := make([]*ProductK8sServiceCreationInfo, 0)
+		for _, svc := range group {
+			target, ok := serviceMapping[svc.ServiceName]
+			if !ok {
+				continue
+			}
+			template := targetServiceMap[target]
+			variableKVs := svc.GetServiceRender().OverrideYaml.RenderVariableKVs
+			for _, kv := range variableKVs {
+				if kv.UseGlobalVariable {
+					if relatedServices[kv.Key] == nil {
+						relatedServices[kv.Key] = sets.NewString()
+					}
+					relatedServices[kv.Key].Insert(target)
+				}
+			}
+
+			services = append(services, &ProductK8sServiceCreationInfo{
+				ProductService: &commonmodels.ProductService{
+					ServiceName: target,
+					ProductName: args.TargetProject,
+					Type:        template.Type,
+					Revision:    template.Revision,
+					Containers:  copyContainers(template.Containers, svc.Containers),
+					VariableKVs: variableKVs,
+				},
+				DeployStrategy: setting.ServiceDeployStrategyDeploy,
+			})
+			if err := remapContainerImages(services[len(services)-1].Containers, sourceEnv.RegistryID, arg.RegistryID); err != nil {
+				return e.ErrCreateEnv.AddErr(err)
+			}
+		}
+		if len(services) > 0 {
+			arg.Services = append(arg.Services, services)
+		}
+	}
+
+	// only the global variables defined in the target project are kept
+	sourceGlobalVariables := make(map[string]*commontypes.GlobalVariableKV)
+	for _, kv := range sourceEnv.GlobalVariables {
+		sourceGlobalVariables[kv.Key] = kv
+	}
+	for _, define := range targetTemplate.GlobalVariables {
+		kv := &commontypes.GlobalVariableKV{ServiceVariableKV: *define}
+		if source, ok := sourceGlobalVariables[define.Key]; ok {
+			kv.Value = source.Value
+		}
+		if related, ok := relatedServices[define.Key]; ok {
+			kv.RelatedServices = related.List()
+		}
+		arg.GlobalVariables = append(arg.GlobalVariables, kv)
+	}
+
+	return CreateYamlProduct(args.TargetProject, userName, requestID, []*CreateSingleProductArg{arg}, log)
+}
+
+// copyContainers uses the containers defined in the target service template with the images used in the source env.
+func copyContainers(templateContainers, sourceContainers []*commonmodels.Container) []*commonmodels.Container {
+	sourceImages := make(map[string]string)
+	for _, container := range sourceContainers {
+		sourceImages[container.Name] = container.Image
+	}
+
+	ret := make([]*commonmodels.Container, 0, len(templateContainers))
+	for _, container := range templateContainers {
+		copied := *container
+		if image, ok := sourceImages[container.Name]; ok {
+			copied.Image = image
+		}
+		ret = append(ret, &copied)
+	}
+	return ret
+}
@@ -154,6 +154,8 @@ func createSingleHostProduct(templateProduct *templatemodels.Product, requestID,
 		IsExisted:       arg.IsExisted,
 		Production:      arg.Production,
 		Alias:           arg.Alias,
+		Owner:           arg.Owner,
+		TTL:             arg.TTL.toEnvTTL(),
 	}
 
 	return CreateProduct(userName, requestID, &ProductCreateArg{productObj, nil}, log)
@@ -179,6 +181,8 @@ func createSingleHelmProduct(templateProduct *templatemodels.Product, requestID,
 		IstioGrayscale:  arg.IstioGrayscale,
 		Production:      arg.Production,
 		Alias:           arg.Alias,
+		Owner:           arg.Owner,
+		TTL:             arg.TTL.toEnvTTL(),
 	}
 
 	// fill services and chart infos of product
@@ -320,6 +324,9 @@ func createSingleYamlProduct(templateProduct *templatemodels.Product, requestID,
 		IstioGrayscale:  arg.IstioGrayscale,
 		Production:      arg.Production,
 		Alias:           arg.Alias,
+		Owner:           arg.Owner,
+		TTL:             arg.TTL.toEnvTTL(),
+		ClusterRemap:    arg.ClusterRemap,
 	}
 	if len(arg.BaseEnvName) > 0 {
 		productObj.BaseEnvName = arg.BaseEnvName
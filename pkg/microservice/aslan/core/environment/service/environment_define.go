@@ -25,6 +25,7 @@ import (
 	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
 	commontypes "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/types"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/flux"
 )
 
 const (
@@ -73,6 +74,9 @@ type EnvResp struct {
 	IstioGrayscaleEnable  bool   `json:"istio_grayscale_enable"`
 	IstioGrayscaleIsBase  bool   `json:"istio_grayscale_is_base"`
 	IstioGrayscaleBaseEnv string `json:"istio_grayscale_base_env"`
+
+	// FluxStatuses are the reconcile status of the flux resources backing the env
+	FluxStatuses []*flux.ResourceStatus `json:"flux_statuses,omitempty"`
 }
 
 type SharedNSEnvs struct {
@@ -114,6 +118,7 @@ type ProductResp struct {
 	IstioGrayscaleIsBase  bool                       `json:"istio_grayscale_is_base"`
 	IstioGrayscaleBaseEnv string                     `json:"istio_grayscale_base_env"`
 	YamlData              *templatemodels.CustomYaml `json:"yaml_data,omitempty"` // used for cron service
+	FluxStatuses          []*flux.ResourceStatus     `json:"flux_statuses,omitempty"`
 }
 
 type ProductParams struct {
@@ -189,6 +194,14 @@ type CreateSingleProductArg struct {
 	EnvConfigs []*commonmodels.CreateUpdateCommonEnvCfgArgs `json:"env_configs"`
 	// New Since v2.1.0
 	IstioGrayscale commonmodels.IstioGrayscale `json:"istio_grayscale"`
+
+	// Owner is the user or user group responsible for the environment, defaults to the creator
+	Owner *commonmodels.ResourceOwner `json:"owner"`
+	// TTL makes the env a one-off env which is torn down when it expires
+	TTL *EnvTTLArgs `json:"ttl"`
+	// ClusterRemap is used when the env is copied into another cluster, the storage classes and ingress classes
+	// which are not mapped and don't exist in the target cluster are mapped to its default ones
+	ClusterRemap *commonmodels.ClusterRemapRules `json:"cluster_remap"`
 }
 
 type UpdateMultiHelmProductArg struct {
@@ -0,0 +1,34 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func ListFirstDeployHookRecords(projectName, envName string, log *zap.SugaredLogger) ([]*commonmodels.FirstDeployHookRecord, error) {
+	records, err := commonrepo.NewFirstDeployHookRecordColl().List(projectName, envName)
+	if err != nil {
+		log.Errorf("failed to list first deploy hook records of env %s/%s, error: %v", projectName, envName, err)
+		return nil, e.ErrListFirstDeployHookRecords.AddErr(err)
+	}
+	return records, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/flux"
+)
+
+type UpdateFluxResourcesArgs struct {
+	Resources []*commonmodels.FluxResource `json:"resources"`
+}
+
+func UpdateFluxResources(projectName, envName string, production bool, args *UpdateFluxResourcesArgs, log *zap.SugaredLogger) error {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return e.ErrGetEnv.AddDesc(fmt.Sprintf("failed to find env %s in project %s: %s", envName, projectName, err))
+	}
+	if env.Source == setting.SourceFromPM {
+		return e.ErrInvalidParam.AddDesc("flux resources are not supported for this kind of env")
+	}
+
+	resourceSet := make(map[string]bool)
+	for _, resource := range args.Resources {
+		if err := flux.ValidateKind(resource.Kind); err != nil {
+			return e.ErrInvalidParam.AddErr(err)
+		}
+		if resource.Name == "" {
+			return e.ErrInvalidParam.AddDesc("name of the flux resource is empty")
+		}
+		key := fmt.Sprintf("%s/%s/%s", resource.Kind, resource.Namespace, resource.Name)
+		if resourceSet[key] {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("%s %s is referenced more than once", resource.Kind, resource.Name))
+		}
+		resourceSet[key] = true
+	}
+
+	if err := commonrepo.NewProductColl().UpdateFluxResources(envName, projectName, args.Resources); err != nil {
+		log.Errorf("failed to update flux resources of env %s/%s, error: %s", projectName, envName, err)
+		return e.ErrUpdateEnv.AddErr(err)
+	}
+	return nil
+}
+
+// ListFluxResourceStatuses returns the reconcile status of the flux resources referenced by the env, failures of
+// the cluster are reported in the status instead of failing the env apis.
+func ListFluxResourceStatuses(env *commonmodels.Product, log *zap.SugaredLogger) []*flux.ResourceStatus {
+	if len(env.FluxResources) == 0 {
+		return nil
+	}
+
+	resp := make([]*flux.ResourceStatus, 0, len(env.FluxResources))
+	kubeClient, clientErr := kubeclient.GetKubeClient(config.HubServerAddress(), env.ClusterID)
+	if clientErr != nil {
+		log.Errorf("failed to get kube client of cluster %s, error: %s", env.ClusterID, clientErr)
+	}
+	for _, resource := range env.FluxResources {
+		namespace := resource.Namespace
+		if namespace == "" {
+			namespace = env.Namespace
+		}
+		if clientErr != nil {
+			resp = append(resp, &flux.ResourceStatus{Kind: resource.Kind, Name: resource.Name, Namespace: namespace, Error: clientErr.Error()})
+			continue
+		}
+
+		status, err := flux.GetResourceStatus(resource.Kind, namespace, resource.Name, kubeClient)
+		if err != nil {
+			log.Warnf("failed to get the status of %s %s/%s, error: %s", resource.Kind, namespace, resource.Name, err)
+			status = &flux.ResourceStatus{Kind: resource.Kind, Name: resource.Name, Namespace: namespace, Error: err.Error()}
+		}
+		resp = append(resp, status)
+	}
+	return resp
+}
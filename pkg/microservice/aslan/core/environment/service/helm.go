@@ -21,8 +21,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
 
@@ -596,3 +598,170 @@ func GetImageInfos(productName, envName, serviceNames string, production bool, l
 	}
 	return ret, nil
 }
+
+type HelmReleaseHistory struct {
+	Revision     int    `json:"revision"`
+	Updated      int64  `json:"updated"`
+	Status       string `json:"status"`
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chartVersion"`
+	AppVersion   string `json:"appVersion"`
+	Description  string `json:"description"`
+	Current      bool   `json:"current"`
+}
+
+const helmReleaseRollbackTimeout = 5 * time.Minute
+
+func getEnvHelmClient(prod *models.Product) (*helmtool.HelmClient, error) {
+	restConfig, err := kube.GetRESTConfig(prod.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get k8s rest config, err: %s", err)
+	}
+	helmClient, err := helmtool.NewClientFromRestConf(restConfig, prod.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init helm client, err: %s", err)
+	}
+	return helmClient, nil
+}
+
+// findProductServiceByRelease returns the service of the env which is deployed as the given release
+func findProductServiceByRelease(prod *models.Product, releaseName string) (*models.ProductService, error) {
+	if svc, ok := prod.GetChartServiceMap()[releaseName]; ok {
+		return svc, nil
+	}
+
+	svcToReleaseNameMap, err := commonutil.GetServiceNameToReleaseNameMap(prod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build release-service map: %s", err)
+	}
+	for _, svc := range prod.GetServiceMap() {
+		if svcToReleaseNameMap[svc.ServiceName] == releaseName {
+			return svc, nil
+		}
+	}
+	return nil, fmt.Errorf("release %s is not managed by env %s", releaseName, prod.EnvName)
+}
+
+func ListHelmReleaseHistory(projectName, envName, releaseName string, production bool, log *zap.SugaredLogger) ([]*HelmReleaseHistory, error) {
+	prod, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: projectName, EnvName: envName, Production: util.GetBoolPointer(production)})
+	if err != nil {
+		return nil, e.ErrListHelmReleaseHistory.AddErr(fmt.Errorf("failed to find env: %s/%s, err: %s", projectName, envName, err))
+	}
+	if _, err := findProductServiceByRelease(prod, releaseName); err != nil {
+		return nil, e.ErrListHelmReleaseHistory.AddErr(err)
+	}
+
+	helmClient, err := getEnvHelmClient(prod)
+	if err != nil {
+		log.Errorf("[%s][%s] failed to get helm client, err: %s", projectName, envName, err)
+		return nil, e.ErrListHelmReleaseHistory.AddErr(err)
+	}
+	releases, err := helmClient.ListReleaseHistory(releaseName, 0)
+	if err != nil {
+		return nil, e.ErrListHelmReleaseHistory.AddErr(fmt.Errorf("failed to list history of release %s, err: %s", releaseName, err))
+	}
+
+	ret := make([]*HelmReleaseHistory, 0, len(releases))
+	for _, re := range releases {
+		history := &HelmReleaseHistory{
+			Revision: re.Version,
+		}
+		if re.Info != nil {
+			history.Updated = re.Info.LastDeployed.Unix()
+			history.Status = re.Info.Status.String()
+			history.Description = re.Info.Description
+			history.Current = re.Info.Status == release.StatusDeployed
+		}
+		if re.Chart != nil && re.Chart.Metadata != nil {
+			history.Chart = re.Chart.Metadata.Name
+			history.ChartVersion = re.Chart.Metadata.Version
+			history.AppVersion = re.Chart.Metadata.AppVersion
+		}
+		ret = append(ret, history)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Revision > ret[j].Revision
+	})
+	return ret, nil
+}
+
+// RollbackHelmRelease rolls back the release in the env to the given revision of its history, the render of the service
+// in the env is updated with the chart version and values of that revision so the next deployment of the service keeps them
+func RollbackHelmRelease(projectName, envName, releaseName string, revision int, production bool, log *zap.SugaredLogger) error {
+	prod, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: projectName, EnvName: envName, Production: util.GetBoolPointer(production)})
+	if err != nil {
+		return e.ErrRollbackHelmRelease.AddErr(fmt.Errorf("failed to find env: %s/%s, err: %s", projectName, envName, err))
+	}
+	if prod.Status == setting.ProductStatusCreating || prod.Status == setting.ProductStatusUpdating || prod.Status == setting.ProductStatusDeleting {
+		return e.ErrRollbackHelmRelease.AddDesc(fmt.Sprintf("env %s is %s, please try later", envName, prod.Status))
+	}
+	if prod.IsSleeping() {
+		return e.ErrRollbackHelmRelease.AddDesc(fmt.Sprintf("env %s is sleeping", envName))
+	}
+
+	prodSvc, err := findProductServiceByRelease(prod, releaseName)
+	if err != nil {
+		return e.ErrRollbackHelmRelease.AddErr(err)
+	}
+
+	helmClient, err := getEnvHelmClient(prod)
+	if err != nil {
+		log.Errorf("[%s][%s] failed to get helm client, err: %s", projectName, envName, err)
+		return e.ErrRollbackHelmRelease.AddErr(err)
+	}
+
+	releases, err := helmClient.ListReleaseHistory(releaseName, 0)
+	if err != nil {
+		return e.ErrRollbackHelmRelease.AddErr(fmt.Errorf("failed to list history of release %s, err: %s", releaseName, err))
+	}
+	found := false
+	for _, re := range releases {
+		if re.Version == revision {
+			found = true
+		}
+		if re.Info != nil && re.Info.Status.IsPending() {
+			return e.ErrRollbackHelmRelease.AddDesc(fmt.Sprintf("another operation is in progress on release %s, please try later", releaseName))
+		}
+	}
+	if !found {
+		return e.ErrRollbackHelmRelease.AddDesc(fmt.Sprintf("revision %d not found in the history of release %s", revision, releaseName))
+	}
+
+	rel, err := helmClient.RollbackReleaseToRevision(releaseName, revision, helmReleaseRollbackTimeout)
+	if err != nil {
+		log.Errorf("[%s][%s] failed to rollback release %s to revision %d, err: %s", projectName, envName, releaseName, revision, err)
+		return e.ErrRollbackHelmRelease.AddErr(err)
+	}
+
+	valuesYaml := ""
+	if len(rel.Config) > 0 {
+		valuesBytes, err := yaml.Marshal(rel.Config)
+		if err != nil {
+			return e.ErrRollbackHelmRelease.AddErr(fmt.Errorf("failed to marshal values of release %s, err: %s", releaseName, err))
+		}
+		valuesYaml = string(valuesBytes)
+	}
+
+	// the values of the rolled back release already contain the override values, so they are replaced by the values yaml
+	render := prodSvc.GetServiceRender()
+	render.OverrideYaml.YamlContent = valuesYaml
+	render.OverrideValues = ""
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		render.ChartVersion = rel.Chart.Metadata.Version
+	}
+	prodSvc.Error = ""
+	prodSvc.UpdateTime = time.Now().Unix()
+
+	for groupIndex, group := range prod.Services {
+		for _, svc := range group {
+			if svc != prodSvc {
+				continue
+			}
+			if err := commonrepo.NewProductColl().UpdateGroup(prod.EnvName, prod.ProductName, groupIndex, group); err != nil {
+				return e.ErrRollbackHelmRelease.AddErr(fmt.Errorf("release %s is rolled back but failed to update service group of env %s, err: %s", releaseName, envName, err))
+			}
+			return nil
+		}
+	}
+	return nil
+}
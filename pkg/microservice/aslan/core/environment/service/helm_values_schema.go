@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// validateEnvHelmValues validates the values of the helm services in the env against their values schemas before the env
+// is updated. renders are the updated renders of the services by service name, the current renders of all the services are
+// validated if it's nil. Services deployed from chart repos are skipped since they have no service template.
+func validateEnvHelmValues(prod *commonmodels.Product, defaultValues string, renders map[string]*templatemodels.ServiceRender, updateServiceTmpl bool) error {
+	// the sops metadata is not a part of the values, the layers are decrypted before being merged
+	defaultValues, err := kube.DecryptSopsValues(defaultValues)
+	if err != nil {
+		return e.ErrUpdateEnv.AddErr(err)
+	}
+
+	violations := make([]*commonservice.HelmValuesViolation, 0)
+	for _, prodSvc := range prod.GetSvcList() {
+		if !prodSvc.FromZadig() {
+			continue
+		}
+		render := prodSvc.GetServiceRender()
+		if renders != nil {
+			var ok bool
+			if render, ok = renders[prodSvc.ServiceName]; !ok {
+				continue
+			}
+		}
+
+		revision := prodSvc.Revision
+		if updateServiceTmpl {
+			revision = prod.GetPinnedServiceRevision(prodSvc.ServiceName)
+		}
+		serviceObj, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+			ServiceName: prodSvc.ServiceName,
+			Revision:    revision,
+			ProductName: prod.ProductName,
+		}, prod.Production)
+		if err != nil {
+			return e.ErrUpdateEnv.AddErr(fmt.Errorf("failed to find service %s, err: %s", prodSvc.ServiceName, err))
+		}
+
+		render, err = kube.DecryptSopsRender(render)
+		if err != nil {
+			return e.ErrUpdateEnv.AddErr(err)
+		}
+		mergedValues, err := commonutil.GeneHelmMergedValues(prodSvc, defaultValues, render)
+		if err != nil {
+			return e.ErrUpdateEnv.AddErr(err)
+		}
+		serviceViolations, err := commonservice.ValidateHelmValues(serviceObj, mergedValues, prod.Production)
+		if err != nil {
+			return e.ErrUpdateEnv.AddErr(err)
+		}
+		violations = append(violations, serviceViolations...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	desc := fmt.Sprintf("%d values don't match the values schema, first: %s: %s: %s", len(violations),
+		violations[0].ServiceName, violations[0].Field, violations[0].Description)
+	return e.NewWithExtras(e.ErrHelmValuesSchemaViolation, desc, map[string]interface{}{"violations": violations})
+}
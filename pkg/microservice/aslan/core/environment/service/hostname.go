@@ -0,0 +1,322 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type HostnameSource string
+
+const (
+	// HostnameSourceAllocated means the hostname is allocated by the hostname policy of the project
+	HostnameSourceAllocated HostnameSource = "allocated"
+	// HostnameSourceIngress means the hostname is used by an ingress in the env namespace
+	HostnameSourceIngress HostnameSource = "ingress"
+)
+
+type HostnameOwner struct {
+	ProjectName string         `json:"project_name"`
+	EnvName     string         `json:"env_name"`
+	Production  bool           `json:"production"`
+	ClusterID   string         `json:"cluster_id"`
+	Namespace   string         `json:"namespace"`
+	ServiceName string         `json:"service_name,omitempty"`
+	Ingress     string         `json:"ingress,omitempty"`
+	Source      HostnameSource `json:"source"`
+}
+
+type HostnameInfo struct {
+	Hostname string `json:"hostname"`
+	*HostnameOwner
+	// Conflicts are the other envs using the same hostname
+	Conflicts []*HostnameOwner `json:"conflicts"`
+}
+
+func GetHostnamePolicy(projectName string) (*commonmodels.HostnamePolicy, error) {
+	policy, err := commonrepo.NewHostnamePolicyColl().Find(projectName)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &commonmodels.HostnamePolicy{ProjectName: projectName}, nil
+		}
+		return nil, e.ErrGetHostnamePolicy.AddErr(err)
+	}
+	return policy, nil
+}
+
+func UpdateHostnamePolicy(projectName, username string, args *commonmodels.HostnamePolicy, log *zap.SugaredLogger) error {
+	args.ProjectName = projectName
+	args.UpdatedBy = username
+	if args.Enabled {
+		for _, pattern := range []string{args.Pattern, args.ProductionPattern} {
+			if pattern == "" {
+				continue
+			}
+			if err := validateHostnamePattern(pattern); err != nil {
+				return e.ErrInvalidParam.AddErr(err)
+			}
+		}
+		if args.Pattern == "" {
+			return e.ErrInvalidParam.AddDesc("hostname pattern can't be empty")
+		}
+
+		// the hostnames allocated by the new policy must not be owned by other projects
+		owners, err := allocatedHostnameOwners(args)
+		if err != nil {
+			return e.ErrUpdateHostnamePolicy.AddErr(err)
+		}
+		index, err := hostnameIndexOfOtherProjects(projectName, log)
+		if err != nil {
+			return e.ErrUpdateHostnamePolicy.AddErr(err)
+		}
+		for hostname, owner := range owners {
+			if others, ok := index[hostname]; ok {
+				return e.ErrUpdateHostnamePolicy.AddDesc(fmt.Sprintf("hostname %s of env %s conflicts with env %s in project %s", hostname, owner.EnvName, others[0].EnvName, others[0].ProjectName))
+			}
+		}
+	}
+
+	if err := commonrepo.NewHostnamePolicyColl().CreateOrUpdate(args); err != nil {
+		return e.ErrUpdateHostnamePolicy.AddErr(err)
+	}
+	return nil
+}
+
+// validateHostnamePattern makes sure every env gets its own hostname and the rendered result is a valid dns name
+func validateHostnamePattern(pattern string) error {
+	if !strings.Contains(pattern, commonmodels.HostnamePlaceholderEnv) && !strings.Contains(pattern, commonmodels.HostnamePlaceholderNamespace) {
+		return fmt.Errorf("hostname pattern %s must contain %s or %s", pattern, commonmodels.HostnamePlaceholderEnv, commonmodels.HostnamePlaceholderNamespace)
+	}
+	sample := commonmodels.RenderHostnamePattern(pattern, "project", "env", "namespace", "service")
+	if errs := validation.IsDNS1123Subdomain(sample); len(errs) > 0 {
+		return fmt.Errorf("invalid hostname pattern %s: %s", pattern, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// ListProjectHostnames lists the hostnames allocated to or used by the ingresses of the project's envs,
+// together with the other envs using the same hostname.
+func ListProjectHostnames(projectName string, log *zap.SugaredLogger) ([]*HostnameInfo, error) {
+	policy, err := GetHostnamePolicy(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	envs, err := listActiveEnvs(projectName)
+	if err != nil {
+		return nil, e.ErrListHostnames.AddErr(err)
+	}
+
+	resp := make([]*HostnameInfo, 0)
+	for _, env := range envs {
+		for _, owner := range envAllocatedHostnames(policy, env) {
+			resp = append(resp, &HostnameInfo{Hostname: owner.hostname, HostnameOwner: owner.HostnameOwner})
+		}
+
+		owners, err := envIngressHostnames(env)
+		if err != nil {
+			log.Warnf("failed to list ingress hostnames of env %s/%s: %s", projectName, env.EnvName, err)
+			continue
+		}
+		for _, owner := range owners {
+			resp = append(resp, &HostnameInfo{Hostname: owner.hostname, HostnameOwner: owner.HostnameOwner})
+		}
+	}
+
+	index, err := hostnameIndexOfOtherProjects(projectName, log)
+	if err != nil {
+		return nil, e.ErrListHostnames.AddErr(err)
+	}
+	for _, info := range resp {
+		info.Conflicts = make([]*HostnameOwner, 0)
+		info.Conflicts = append(info.Conflicts, index[info.Hostname]...)
+		for _, other := range resp {
+			if other.Hostname == info.Hostname && (other.EnvName != info.EnvName || other.ClusterID != info.ClusterID) {
+				info.Conflicts = append(info.Conflicts, other.HostnameOwner)
+			}
+		}
+	}
+
+	sort.SliceStable(resp, func(i, j int) bool {
+		if resp[i].Hostname != resp[j].Hostname {
+			return resp[i].Hostname < resp[j].Hostname
+		}
+		return resp[i].EnvName < resp[j].EnvName
+	})
+	return resp, nil
+}
+
+// CheckHostnameConflict returns the envs other than the given one which own the hostname
+func CheckHostnameConflict(projectName, envName, hostname string, log *zap.SugaredLogger) ([]*HostnameOwner, error) {
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	if hostname == "" {
+		return nil, e.ErrInvalidParam.AddDesc("hostname can't be empty")
+	}
+
+	infos, err := ListProjectHostnames(projectName, log)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*HostnameOwner, 0)
+	for _, info := range infos {
+		if info.Hostname == hostname && info.EnvName != envName {
+			resp = append(resp, info.HostnameOwner)
+		}
+	}
+
+	index, err := hostnameIndexOfOtherProjects(projectName, log)
+	if err != nil {
+		return nil, e.ErrListHostnames.AddErr(err)
+	}
+	return append(resp, index[hostname]...), nil
+}
+
+type ownedHostname struct {
+	hostname string
+	*HostnameOwner
+}
+
+func listActiveEnvs(projectName string) ([]*commonmodels.Product, error) {
+	return commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+		Name:          projectName,
+		ExcludeStatus: []string{setting.ProductStatusDeleting, setting.ProductStatusUnknown},
+	})
+}
+
+func envAllocatedHostnames(policy *commonmodels.HostnamePolicy, env *commonmodels.Product) []*ownedHostname {
+	if policy == nil || !policy.Enabled {
+		return nil
+	}
+
+	newOwner := func(serviceName string) *ownedHostname {
+		return &ownedHostname{
+			hostname: policy.Render(env.Production, env.ProductName, env.EnvName, env.Namespace, serviceName),
+			HostnameOwner: &HostnameOwner{
+				ProjectName: env.ProductName,
+				EnvName:     env.EnvName,
+				Production:  env.Production,
+				ClusterID:   env.ClusterID,
+				Namespace:   env.Namespace,
+				ServiceName: serviceName,
+				Source:      HostnameSourceAllocated,
+			},
+		}
+	}
+
+	if !strings.Contains(policy.GetPattern(env.Production), commonmodels.HostnamePlaceholderService) {
+		return []*ownedHostname{newOwner("")}
+	}
+	resp := make([]*ownedHostname, 0)
+	for serviceName := range env.GetServiceMap() {
+		resp = append(resp, newOwner(serviceName))
+	}
+	return resp
+}
+
+func envIngressHostnames(env *commonmodels.Product) ([]*ownedHostname, error) {
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube clientset: %s", err)
+	}
+	ingresses, err := clientset.NetworkingV1().Ingresses(env.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*ownedHostname, 0)
+	for _, ing := range ingresses.Items {
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host == "" {
+				continue
+			}
+			resp = append(resp, &ownedHostname{
+				hostname: strings.ToLower(rule.Host),
+				HostnameOwner: &HostnameOwner{
+					ProjectName: env.ProductName,
+					EnvName:     env.EnvName,
+					Production:  env.Production,
+					ClusterID:   env.ClusterID,
+					Namespace:   env.Namespace,
+					Ingress:     ing.Name,
+					Source:      HostnameSourceIngress,
+				},
+			})
+		}
+	}
+	return resp, nil
+}
+
+func allocatedHostnameOwners(policy *commonmodels.HostnamePolicy) (map[string]*HostnameOwner, error) {
+	envs, err := listActiveEnvs(policy.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make(map[string]*HostnameOwner)
+	for _, env := range envs {
+		for _, owner := range envAllocatedHostnames(policy, env) {
+			if existed, ok := resp[owner.hostname]; ok && existed.EnvName != owner.EnvName {
+				return nil, fmt.Errorf("hostname %s is allocated to both env %s and env %s", owner.hostname, existed.EnvName, owner.EnvName)
+			}
+			resp[owner.hostname] = owner.HostnameOwner
+		}
+	}
+	return resp, nil
+}
+
+// hostnameIndexOfOtherProjects builds the hostname index from the policies of the other projects,
+// the ingresses of other projects are not scanned to avoid touching all the clusters.
+func hostnameIndexOfOtherProjects(projectName string, log *zap.SugaredLogger) (map[string][]*HostnameOwner, error) {
+	policies, err := commonrepo.NewHostnamePolicyColl().ListEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make(map[string][]*HostnameOwner)
+	for _, policy := range policies {
+		if policy.ProjectName == projectName {
+			continue
+		}
+		envs, err := listActiveEnvs(policy.ProjectName)
+		if err != nil {
+			log.Warnf("failed to list envs of project %s: %s", policy.ProjectName, err)
+			continue
+		}
+		for _, env := range envs {
+			for _, owner := range envAllocatedHostnames(policy, env) {
+				resp[owner.hostname] = append(resp[owner.hostname], owner.HostnameOwner)
+			}
+		}
+	}
+	return resp, nil
+}
@@ -133,19 +133,20 @@ func UpdateContainerImage(requestID, username string, args *UpdateContainerImage
 			return e.ErrUpdateConainterImage.AddErr(err)
 		}
 	} else {
+		image := kube.GetDragonflyConfig(product.ClusterID).MirrorImage(args.Image)
 		switch args.Type {
 		case setting.Deployment:
-			if err := updater.UpdateDeploymentImage(namespace, args.Name, args.ContainerName, args.Image, kubeClient); err != nil {
+			if err := updater.UpdateDeploymentImage(namespace, args.Name, args.ContainerName, image, kubeClient); err != nil {
 				log.Errorf("[%s] UpdateDeploymentImageByName error: %s", namespace, err.Error())
 				return e.ErrUpdateConainterImage.AddDesc("更新 Deployment 容器镜像失败")
 			}
 		case setting.StatefulSet:
-			if err := updater.UpdateStatefulSetImage(namespace, args.Name, args.ContainerName, args.Image, kubeClient); err != nil {
+			if err := updater.UpdateStatefulSetImage(namespace, args.Name, args.ContainerName, image, kubeClient); err != nil {
 				log.Errorf("[%s] UpdateStatefulsetImageByName error: %s", namespace, err.Error())
 				return e.ErrUpdateConainterImage.AddDesc("更新 StatefulSet 容器镜像失败")
 			}
 		case setting.CronJob:
-			if err := updater.UpdateCronJobImage(namespace, args.Name, args.ContainerName, args.Image, kubeClient, VersionLessThan121(version)); err != nil {
+			if err := updater.UpdateCronJobImage(namespace, args.Name, args.ContainerName, image, kubeClient, VersionLessThan121(version)); err != nil {
 				log.Errorf("[%s] UpdateCronJobImageByName error: %s", namespace, err.Error())
 				return e.ErrUpdateConainterImage.AddDesc("更新 CronJob 容器镜像失败")
 			}
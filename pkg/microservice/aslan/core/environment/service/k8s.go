@@ -78,18 +78,19 @@ func (k *K8sService) queryServiceStatus(serviceTmpl *commonmodels.Service, produ
 // only supports Deployment and StatefulSet
 func (k *K8sService) queryWorkloadStatus(serviceTmpl *commonmodels.Service, productInfo *commonmodels.Product, informer informers.SharedInformerFactory) string {
 	if len(serviceTmpl.Containers) > 0 {
-		workloads, err := GetServiceWorkloads(serviceTmpl, productInfo, informer, k.log)
-		if err != nil {
-			k.log.Errorf("failed to get service workloads, err: %s", err)
-			return setting.PodUnstable
-		}
-		for _, workload := range workloads {
-			log.Infof("workload name: %s, ready: %v", workload.Name, workload.Ready)
-			if !workload.Ready {
-				return setting.PodUnstable
+		return commonservice.GetCachedServiceStatus(productInfo, commonservice.ServiceStatusKindWorkload, serviceTmpl, func() (interface{}, bool) {
+			workloads, err := GetServiceWorkloads(serviceTmpl, productInfo, informer, k.log)
+			if err != nil {
+				k.log.Errorf("failed to get service workloads, err: %s", err)
+				return setting.PodUnstable, false
 			}
-		}
-		return setting.PodRunning
+			for _, workload := range workloads {
+				if !workload.Ready {
+					return setting.PodUnstable, true
+				}
+			}
+			return setting.PodRunning, true
+		}).(string)
 	}
 	return setting.PodSucceeded
 }
@@ -124,9 +125,11 @@ func (k *K8sService) updateService(args *SvcOptArgs) error {
 	if !args.UpdateServiceTmpl {
 		newProductSvc.Revision = currentProductSvc.Revision
 	} else {
+		// the pinned revision is used as the latest one if the service is pinned in the env
 		latestSvcRevision, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
 			ServiceName: newProductSvc.ServiceName,
 			ProductName: newProductSvc.ProductName,
+			Revision:    prodinfo.GetPinnedServiceRevision(newProductSvc.ServiceName),
 		}, prodinfo.Production)
 		if err != nil {
 			return e.ErrUpdateService.AddErr(fmt.Errorf("failed to find service, err: %s", err))
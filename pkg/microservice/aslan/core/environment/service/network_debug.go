@@ -0,0 +1,333 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/containerlog"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+type NetworkCheckType string
+
+const (
+	NetworkCheckTypeDNS  NetworkCheckType = "dns"
+	NetworkCheckTypeTCP  NetworkCheckType = "tcp"
+	NetworkCheckTypeHTTP NetworkCheckType = "http"
+)
+
+const (
+	networkCheckContainerName  = "zadig-network-check"
+	defaultNetworkCheckTimeout = 10
+	maxNetworkCheckTimeout     = 60
+
+	networkCheckExitCodeMarker = "ZADIG_EXIT_CODE="
+	networkCheckHTTPCodeMarker = "ZADIG_HTTP_CODE="
+	networkCheckTimeMarker     = "ZADIG_TIME_TOTAL="
+)
+
+// user input is passed to the scripts through env vars so that it is never interpreted by the shell
+var networkCheckScripts = map[NetworkCheckType]string{
+	NetworkCheckTypeDNS:  `nslookup "$TARGET"; echo "` + networkCheckExitCodeMarker + `$?"`,
+	NetworkCheckTypeTCP:  `nc -z -v -w "$TIMEOUT" "$HOST" "$PORT" 2>&1; echo "` + networkCheckExitCodeMarker + `$?"`,
+	NetworkCheckTypeHTTP: `curl -sS -o /dev/null -X "$METHOD" -m "$TIMEOUT" -w '` + networkCheckHTTPCodeMarker + `%{http_code}\n` + networkCheckTimeMarker + `%{time_total}\n' "$TARGET" 2>&1; echo "` + networkCheckExitCodeMarker + `$?"`,
+}
+
+type NetworkCheckArgs struct {
+	Type   NetworkCheckType `json:"type"`
+	Target string           `json:"target"`
+	// Method is only used by http checks, default to GET
+	Method string `json:"method"`
+	// Timeout in seconds for the check itself, default to 10
+	Timeout int64  `json:"timeout"`
+	Image   string `json:"image"`
+}
+
+type NetworkCheckResult struct {
+	Type       NetworkCheckType `json:"type"`
+	Target     string           `json:"target"`
+	Success    bool             `json:"success"`
+	ExitCode   int              `json:"exit_code"`
+	Addresses  []string         `json:"addresses,omitempty"`
+	StatusCode int              `json:"status_code,omitempty"`
+	LatencyMs  int64            `json:"latency_ms"`
+	PodName    string           `json:"pod_name"`
+	Output     string           `json:"output"`
+	Error      string           `json:"error,omitempty"`
+}
+
+func (args *NetworkCheckArgs) Validate() error {
+	if args.Target == "" {
+		return fmt.Errorf("target can't be empty")
+	}
+	if args.Timeout <= 0 {
+		args.Timeout = defaultNetworkCheckTimeout
+	}
+	if args.Timeout > maxNetworkCheckTimeout {
+		return fmt.Errorf("timeout can't be greater than %d seconds", maxNetworkCheckTimeout)
+	}
+
+	switch args.Type {
+	case NetworkCheckTypeDNS:
+	case NetworkCheckTypeTCP:
+		host, port, err := net.SplitHostPort(args.Target)
+		if err != nil {
+			return fmt.Errorf("tcp target must be in host:port format: %s", err)
+		}
+		if host == "" {
+			return fmt.Errorf("tcp target host can't be empty")
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("invalid tcp target port %q", port)
+		}
+	case NetworkCheckTypeHTTP:
+		u, err := url.Parse(args.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("http target must be a valid http or https url")
+		}
+		if args.Method == "" {
+			args.Method = "GET"
+		}
+		args.Method = strings.ToUpper(args.Method)
+	default:
+		return fmt.Errorf("unsupported network check type %q", args.Type)
+	}
+	return nil
+}
+
+func (args *NetworkCheckArgs) envs() []corev1.EnvVar {
+	envs := []corev1.EnvVar{
+		{Name: "TARGET", Value: args.Target},
+		{Name: "TIMEOUT", Value: strconv.FormatInt(args.Timeout, 10)},
+	}
+	switch args.Type {
+	case NetworkCheckTypeTCP:
+		host, port, _ := net.SplitHostPort(args.Target)
+		envs = append(envs, corev1.EnvVar{Name: "HOST", Value: host}, corev1.EnvVar{Name: "PORT", Value: port})
+	case NetworkCheckTypeHTTP:
+		envs = append(envs, corev1.EnvVar{Name: "METHOD", Value: args.Method})
+	}
+	return envs
+}
+
+// RunNetworkCheck runs a one-shot connectivity check from inside the namespace of the given environment
+// with an ephemeral debug pod, the pod is removed once the result is collected.
+func RunNetworkCheck(ctx context.Context, projectName, envName string, production bool, args *NetworkCheckArgs, log *zap.SugaredLogger) (*NetworkCheckResult, error) {
+	if err := args.Validate(); err != nil {
+		return nil, e.ErrInvalidParam.AddErr(err)
+	}
+	if args.Image == "" {
+		args.Image = types.DebugImage
+	}
+
+	prod, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return nil, e.ErrGetEnv.AddDesc(fmt.Sprintf("failed to query env %q in project %q: %s", envName, projectName, err))
+	}
+
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), prod.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube clientset: %s", err)
+	}
+
+	podName := fmt.Sprintf("zadig-network-check-%s", rand.String(6))
+	pod := genNetworkCheckPod(prod.Namespace, podName, args)
+	if _, err := clientset.CoreV1().Pods(prod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create network check pod in ns %q: %s", prod.Namespace, err)
+	}
+	defer func() {
+		err := clientset.CoreV1().Pods(prod.Namespace).Delete(context.Background(), podName, metav1.DeleteOptions{})
+		if err != nil {
+			log.Warnf("failed to delete network check pod %s/%s: %s", prod.Namespace, podName, err)
+		}
+	}()
+
+	start := time.Now()
+	result := &NetworkCheckResult{
+		Type:    args.Type,
+		Target:  args.Target,
+		PodName: podName,
+	}
+
+	// extra time is given for scheduling and image pulling
+	if err := waitNetworkCheckPodDone(ctx, clientset, prod.Namespace, podName, time.Duration(args.Timeout)*time.Second+2*time.Minute); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := containerlog.GetContainerLogs(prod.Namespace, podName, networkCheckContainerName, false, int64(200), buf, clientset); err != nil {
+		return nil, fmt.Errorf("failed to get logs of network check pod %s/%s: %s", prod.Namespace, podName, err)
+	}
+
+	parseNetworkCheckOutput(result, buf.String())
+	if args.Type != NetworkCheckTypeHTTP || result.LatencyMs == 0 {
+		result.LatencyMs = time.Since(start).Milliseconds()
+	}
+	return result, nil
+}
+
+func genNetworkCheckPod(namespace, name string, args *NetworkCheckArgs) *corev1.Pod {
+	deadline := args.Timeout + 30
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "zadig",
+				"app.kubernetes.io/component":  networkCheckContainerName,
+			},
+			// the check runs before the sidecar is ready and the pod never completes with a sidecar injected
+			Annotations: map[string]string{
+				"sidecar.istio.io/inject": "false",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: &deadline,
+			Containers: []corev1.Container{
+				{
+					Name:            networkCheckContainerName,
+					Image:           args.Image,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Command:         []string{"sh", "-c", networkCheckScripts[args.Type]},
+					Env:             args.envs(),
+				},
+			},
+		},
+	}
+}
+
+func waitNetworkCheckPodDone(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string, timeout time.Duration) error {
+	err := wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return true, nil
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil && (status.State.Waiting.Reason == "ErrImagePull" || status.State.Waiting.Reason == "ImagePullBackOff") {
+				return false, fmt.Errorf("failed to pull image %s: %s", status.Image, status.State.Waiting.Message)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("network check pod %s/%s didn't finish: %s", namespace, name, err)
+	}
+	return nil
+}
+
+// parseNetworkCheckOutput fills the result with the markers printed by the check scripts,
+// the remaining lines are kept as the raw output.
+func parseNetworkCheckOutput(result *NetworkCheckResult, output string) {
+	result.ExitCode = -1
+	lines := make([]string, 0)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, networkCheckExitCodeMarker):
+			if code, err := strconv.Atoi(strings.TrimPrefix(line, networkCheckExitCodeMarker)); err == nil {
+				result.ExitCode = code
+			}
+		case strings.HasPrefix(line, networkCheckHTTPCodeMarker):
+			result.StatusCode, _ = strconv.Atoi(strings.TrimPrefix(line, networkCheckHTTPCodeMarker))
+		case strings.HasPrefix(line, networkCheckTimeMarker):
+			if seconds, err := strconv.ParseFloat(strings.TrimPrefix(line, networkCheckTimeMarker), 64); err == nil {
+				result.LatencyMs = int64(seconds * 1000)
+			}
+		default:
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	result.Output = strings.Join(lines, "\n")
+
+	if result.Type == NetworkCheckTypeDNS {
+		result.Addresses = parseNslookupAddresses(lines)
+	}
+
+	result.Success = result.ExitCode == 0
+	if result.Type == NetworkCheckTypeHTTP && result.StatusCode == 0 {
+		result.Success = false
+	}
+	if !result.Success && result.Error == "" {
+		result.Error = fmt.Sprintf("%s check against %s failed with exit code %d", result.Type, result.Target, result.ExitCode)
+	}
+}
+
+// parseNslookupAddresses extracts the resolved addresses from nslookup output, the address
+// of the dns server itself is printed before the "Name:" line and is skipped.
+func parseNslookupAddresses(lines []string) []string {
+	addresses := make([]string, 0)
+	answered := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "Name:") {
+			answered = true
+			continue
+		}
+		if !answered || !strings.HasPrefix(line, "Address") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields := strings.Fields(parts[1])
+		if len(fields) == 0 {
+			continue
+		}
+		addr := fields[0]
+		if idx := strings.LastIndex(addr, "#"); idx > 0 {
+			addr = addr[:idx]
+		}
+		if net.ParseIP(addr) != nil {
+			addresses = append(addresses, addr)
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type networkCheckValidateParams struct {
+	args           *NetworkCheckArgs
+	expectErr      bool
+	expectedMethod string
+}
+
+var busyboxNslookupOutput = `Server:		10.96.0.10
+Address:	10.96.0.10:53
+
+Name:	nginx.default.svc.cluster.local
+Address: 10.244.1.23
+Address: fd00::17
+ZADIG_EXIT_CODE=0
+`
+
+var _ = Describe("Testing network debug", func() {
+
+	DescribeTable("Testing NetworkCheckArgs Validate",
+		func(p networkCheckValidateParams) {
+			err := p.args.Validate()
+			if p.expectErr {
+				Expect(err).Should(HaveOccurred())
+				return
+			}
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(p.args.Timeout).To(BeNumerically(">", 0))
+			Expect(p.args.Method).To(Equal(p.expectedMethod))
+		},
+		Entry("empty target", networkCheckValidateParams{
+			args:      &NetworkCheckArgs{Type: NetworkCheckTypeDNS},
+			expectErr: true,
+		}),
+		Entry("dns check with default timeout", networkCheckValidateParams{
+			args: &NetworkCheckArgs{Type: NetworkCheckTypeDNS, Target: "nginx"},
+		}),
+		Entry("timeout too long", networkCheckValidateParams{
+			args:      &NetworkCheckArgs{Type: NetworkCheckTypeDNS, Target: "nginx", Timeout: maxNetworkCheckTimeout + 1},
+			expectErr: true,
+		}),
+		Entry("tcp check", networkCheckValidateParams{
+			args: &NetworkCheckArgs{Type: NetworkCheckTypeTCP, Target: "redis:6379"},
+		}),
+		Entry("tcp check without port", networkCheckValidateParams{
+			args:      &NetworkCheckArgs{Type: NetworkCheckTypeTCP, Target: "redis"},
+			expectErr: true,
+		}),
+		Entry("tcp check with invalid port", networkCheckValidateParams{
+			args:      &NetworkCheckArgs{Type: NetworkCheckTypeTCP, Target: "redis:65536"},
+			expectErr: true,
+		}),
+		Entry("http check with default method", networkCheckValidateParams{
+			args:           &NetworkCheckArgs{Type: NetworkCheckTypeHTTP, Target: "http://nginx/healthz"},
+			expectedMethod: "GET",
+		}),
+		Entry("http check with lower case method", networkCheckValidateParams{
+			args:           &NetworkCheckArgs{Type: NetworkCheckTypeHTTP, Target: "https://nginx", Method: "head"},
+			expectedMethod: "HEAD",
+		}),
+		Entry("http check without scheme", networkCheckValidateParams{
+			args:      &NetworkCheckArgs{Type: NetworkCheckTypeHTTP, Target: "nginx/healthz"},
+			expectErr: true,
+		}),
+		Entry("unsupported type", networkCheckValidateParams{
+			args:      &NetworkCheckArgs{Type: "icmp", Target: "nginx"},
+			expectErr: true,
+		}),
+	)
+
+	Describe("test parseNetworkCheckOutput", func() {
+
+		Context("dns check succeeded", func() {
+			It("should return the resolved addresses", func() {
+				result := &NetworkCheckResult{Type: NetworkCheckTypeDNS, Target: "nginx"}
+				parseNetworkCheckOutput(result, busyboxNslookupOutput)
+				Expect(result.Success).To(BeTrue())
+				Expect(result.ExitCode).To(Equal(0))
+				Expect(result.Addresses).To(Equal([]string{"10.244.1.23", "fd00::17"}))
+				Expect(result.Output).NotTo(ContainSubstring(networkCheckExitCodeMarker))
+				Expect(result.Error).To(BeEmpty())
+			})
+		})
+
+		Context("http check succeeded", func() {
+			It("should return the status code and latency", func() {
+				result := &NetworkCheckResult{Type: NetworkCheckTypeHTTP, Target: "http://nginx"}
+				parseNetworkCheckOutput(result, "ZADIG_HTTP_CODE=503\nZADIG_TIME_TOTAL=0.125\nZADIG_EXIT_CODE=0\n")
+				Expect(result.Success).To(BeTrue())
+				Expect(result.StatusCode).To(Equal(503))
+				Expect(result.LatencyMs).To(Equal(int64(125)))
+				Expect(result.Output).To(BeEmpty())
+			})
+		})
+
+		Context("http check got no response", func() {
+			It("should fail", func() {
+				result := &NetworkCheckResult{Type: NetworkCheckTypeHTTP, Target: "http://nginx"}
+				parseNetworkCheckOutput(result, "curl: (6) Could not resolve host: nginx\nZADIG_HTTP_CODE=000\nZADIG_TIME_TOTAL=0.001\nZADIG_EXIT_CODE=6\n")
+				Expect(result.Success).To(BeFalse())
+				Expect(result.ExitCode).To(Equal(6))
+				Expect(result.Output).To(Equal("curl: (6) Could not resolve host: nginx"))
+				Expect(result.Error).NotTo(BeEmpty())
+			})
+		})
+
+		Context("exit code is missing", func() {
+			It("should fail", func() {
+				result := &NetworkCheckResult{Type: NetworkCheckTypeTCP, Target: "redis:6379"}
+				parseNetworkCheckOutput(result, "sh: nc: not found\n")
+				Expect(result.Success).To(BeFalse())
+				Expect(result.ExitCode).To(Equal(-1))
+			})
+		})
+	})
+
+	Describe("test parseNslookupAddresses", func() {
+
+		It("should skip the address of the dns server", func() {
+			lines := []string{"Server: 10.96.0.10", "Address: 10.96.0.10#53", "Name: nginx", "Address: 10.244.1.23"}
+			Expect(parseNslookupAddresses(lines)).To(Equal([]string{"10.244.1.23"}))
+		})
+
+		It("should parse the numbered addresses of old busybox", func() {
+			lines := []string{"Server: 10.96.0.10", "Address 1: 10.96.0.10 kube-dns.kube-system.svc.cluster.local",
+				"Name: nginx", "Address 1: 10.244.1.23 10-244-1-23.nginx.default.svc.cluster.local", "Address 2: 10.244.2.5"}
+			Expect(parseNslookupAddresses(lines)).To(Equal([]string{"10.244.1.23", "10.244.2.5"}))
+		})
+
+		It("should return nothing if the name is not resolved", func() {
+			lines := []string{"Server: 10.96.0.10", "Address: 10.96.0.10:53", "** server can't find nginx: NXDOMAIN"}
+			Expect(parseNslookupAddresses(lines)).To(BeEmpty())
+		})
+	})
+})
@@ -66,7 +66,8 @@ func CleanProductCronJob(requestID string, log *zap.SugaredLogger) {
 			continue
 		}
 
-		if product.RecycleDay == 0 {
+		// envs with ttl are torn down by processExpiredEnvs
+		if product.RecycleDay == 0 || product.TTL != nil {
 			continue
 		}
 		if _, ok := envCMMap[collaboration.BuildEnvCMMapKey(product.ProductName, product.EnvName)]; ok {
@@ -89,6 +90,8 @@ func CleanProductCronJob(requestID string, log *zap.SugaredLogger) {
 			log.Warnf("[%s] product %s deleted", product.EnvName, product.ProductName)
 		}
 	}
+
+	processExpiredEnvs(requestID, log)
 }
 
 func GetInitProduct(productTmplName string, envType types.EnvType, isBaseEnv bool, baseEnvName string, production bool, log *zap.SugaredLogger) (*commonmodels.Product, error) {
@@ -243,6 +246,7 @@ func buildProductResp(envName string, prod *commonmodels.Product, log *zap.Sugar
 		IstioGrayscaleIsBase:  prod.IstioGrayscale.IsBase,
 		IstioGrayscaleBaseEnv: prod.IstioGrayscale.BaseEnv,
 		YamlData:              prod.YamlData,
+		FluxStatuses:          ListFluxResourceStatuses(prod, log),
 	}
 
 	serviceMap := prod.GetServiceMap()
@@ -86,6 +86,11 @@ func DeleteProductionProduct(username, envName, productName, requestID string, l
 	log.Infof("[%s] delete product %s", username, productInfo.Namespace)
 	commonservice.LogProductStats(username, setting.DeleteProductEvent, productName, requestID, eventStart, log)
 
+	err = commonrepo.NewFirstDeployHookRecordColl().DeleteByEnv(productName, envName)
+	if err != nil {
+		log.Errorf("failed to delete first deploy hook records, error: %v", err)
+	}
+
 	err = commonrepo.NewProductColl().Delete(envName, productName)
 	if err != nil {
 		log.Errorf("Production product delete error: %v", err)
@@ -86,6 +86,39 @@ func Scale(args *ScaleArgs, logger *zap.SugaredLogger) error {
 	return nil
 }
 
+// UpdateStatefulSetRollout changes the rollout setting of a statefulSet in the env, e.g. promotes a partitioned canary
+// by lowering the partition.
+func UpdateStatefulSetRollout(args *UpdateStatefulSetRolloutArgs, logger *zap.SugaredLogger) error {
+	prod, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       args.ProductName,
+		EnvName:    args.EnvName,
+		Production: &args.Production,
+	})
+	if err != nil {
+		return e.ErrUpdateStatefulSetRollout.AddErr(err)
+	}
+	if prod.IsSleeping() {
+		return e.ErrUpdateStatefulSetRollout.AddErr(fmt.Errorf("environment is sleeping"))
+	}
+	if err := kube.ValidateStatefulSetRollout(args.Rollout, args.Production); err != nil {
+		return e.ErrUpdateStatefulSetRollout.AddErr(err)
+	}
+
+	kubeClient, err := kubeclient.GetKubeClient(config.HubServerAddress(), prod.ClusterID)
+	if err != nil {
+		return e.ErrUpdateStatefulSetRollout.AddErr(err)
+	}
+	if _, found, err := getter.GetStatefulSet(prod.Namespace, args.Name, kubeClient); err != nil || !found {
+		return e.ErrUpdateStatefulSetRollout.AddErr(fmt.Errorf("statefulSet %s not found in env %s", args.Name, args.EnvName))
+	}
+
+	if err := kube.ApplyStatefulSetRollout(prod.Namespace, args.Name, args.Rollout, kubeClient); err != nil {
+		logger.Errorf("failed to update rollout of %s/sts/%s: %v", prod.Namespace, args.Name, err)
+		return e.ErrUpdateStatefulSetRollout.AddErr(err)
+	}
+	return nil
+}
+
 func OpenAPIScale(req *OpenAPIScaleServiceReq, logger *zap.SugaredLogger) error {
 	args := &ScaleArgs{
 		Type:        req.WorkloadType,
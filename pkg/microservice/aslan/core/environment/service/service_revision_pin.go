@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type ServiceRevisionPinInfo struct {
+	ServiceName string `json:"service_name"`
+	// CurrentRevision is the template revision currently deployed in the env
+	CurrentRevision int64  `json:"current_revision"`
+	LatestRevision  int64  `json:"latest_revision"`
+	Pinned          bool   `json:"pinned"`
+	PinnedRevision  int64  `json:"pinned_revision"`
+	PinnedBy        string `json:"pinned_by"`
+	PinTime         int64  `json:"pin_time"`
+	// Outdated means there are newer template revisions than the pinned one
+	Outdated bool `json:"outdated"`
+}
+
+type ServiceRevisionPinArgs struct {
+	ServiceName string `json:"service_name"`
+	// Revision to pin, the current deployed revision is used if it is 0
+	Revision int64 `json:"revision"`
+}
+
+type PinServiceRevisionsArgs struct {
+	Pins []*ServiceRevisionPinArgs `json:"pins"`
+}
+
+type UnpinServiceRevisionsArgs struct {
+	ServiceNames []string `json:"service_names"`
+}
+
+type AdvanceServiceRevisionPinsArgs struct {
+	// ServiceNames to advance, all the pinned services are advanced if it is empty
+	ServiceNames []string `json:"service_names"`
+}
+
+func ListServiceRevisionPins(projectName, envName string, production bool, log *zap.SugaredLogger) ([]*ServiceRevisionPinInfo, error) {
+	env, err := findEnvForRevisionPin(projectName, envName, production)
+	if err != nil {
+		return nil, err
+	}
+
+	pinMap := make(map[string]*commonmodels.ServiceRevisionPin)
+	for _, pin := range env.ServiceRevisionPins {
+		pinMap[pin.ServiceName] = pin
+	}
+
+	resp := make([]*ServiceRevisionPinInfo, 0)
+	for serviceName, svc := range env.GetServiceMap() {
+		info := &ServiceRevisionPinInfo{
+			ServiceName:     serviceName,
+			CurrentRevision: svc.Revision,
+		}
+
+		latest, err := latestServiceRevision(projectName, serviceName, production)
+		if err != nil {
+			log.Warnf("failed to find latest revision of service %s: %s", serviceName, err)
+		} else {
+			info.LatestRevision = latest
+		}
+
+		if pin, ok := pinMap[serviceName]; ok {
+			info.Pinned = true
+			info.PinnedRevision = pin.Revision
+			info.PinnedBy = pin.PinnedBy
+			info.PinTime = pin.PinTime
+			info.Outdated = info.LatestRevision > pin.Revision
+		}
+		resp = append(resp, info)
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].ServiceName < resp[j].ServiceName
+	})
+	return resp, nil
+}
+
+func PinServiceRevisions(projectName, envName string, production bool, username string, args *PinServiceRevisionsArgs) error {
+	env, err := findEnvForRevisionPin(projectName, envName, production)
+	if err != nil {
+		return err
+	}
+
+	serviceMap := env.GetServiceMap()
+	pins := env.ServiceRevisionPins
+	for _, arg := range args.Pins {
+		revision := arg.Revision
+		if revision == 0 {
+			svc, ok := serviceMap[arg.ServiceName]
+			if !ok {
+				return e.ErrInvalidParam.AddDesc(fmt.Sprintf("service %s is not deployed in env %s, revision must be specified", arg.ServiceName, envName))
+			}
+			revision = svc.Revision
+		}
+
+		_, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+			ProductName: projectName,
+			ServiceName: arg.ServiceName,
+			Revision:    revision,
+		}, production)
+		if err != nil {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("failed to find revision %d of service %s: %s", revision, arg.ServiceName, err))
+		}
+
+		pins = setServiceRevisionPin(pins, arg.ServiceName, revision, username)
+	}
+
+	if err := commonrepo.NewProductColl().UpdateServiceRevisionPins(envName, projectName, pins); err != nil {
+		return e.ErrUpdateEnv.AddErr(err)
+	}
+	return nil
+}
+
+func UnpinServiceRevisions(projectName, envName string, production bool, args *UnpinServiceRevisionsArgs) error {
+	env, err := findEnvForRevisionPin(projectName, envName, production)
+	if err != nil {
+		return err
+	}
+
+	unpinSet := make(map[string]bool)
+	for _, serviceName := range args.ServiceNames {
+		unpinSet[serviceName] = true
+	}
+	pins := make([]*commonmodels.ServiceRevisionPin, 0)
+	for _, pin := range env.ServiceRevisionPins {
+		if !unpinSet[pin.ServiceName] {
+			pins = append(pins, pin)
+		}
+	}
+
+	if err := commonrepo.NewProductColl().UpdateServiceRevisionPins(envName, projectName, pins); err != nil {
+		return e.ErrUpdateEnv.AddErr(err)
+	}
+	return nil
+}
+
+// AdvanceServiceRevisionPins moves the pins of the given services to the latest template revisions,
+// the services are not redeployed, the new revisions take effect in the next update of the services.
+func AdvanceServiceRevisionPins(projectName, envName string, production bool, username string, args *AdvanceServiceRevisionPinsArgs) ([]*ServiceRevisionPinInfo, error) {
+	env, err := findEnvForRevisionPin(projectName, envName, production)
+	if err != nil {
+		return nil, err
+	}
+
+	advanceSet := make(map[string]bool)
+	for _, serviceName := range args.ServiceNames {
+		advanceSet[serviceName] = true
+	}
+
+	resp := make([]*ServiceRevisionPinInfo, 0)
+	serviceMap := env.GetServiceMap()
+	pins := env.ServiceRevisionPins
+	for _, pin := range env.ServiceRevisionPins {
+		if len(advanceSet) > 0 && !advanceSet[pin.ServiceName] {
+			continue
+		}
+
+		latest, err := latestServiceRevision(projectName, pin.ServiceName, production)
+		if err != nil {
+			return nil, e.ErrUpdateEnv.AddDesc(fmt.Sprintf("failed to find latest revision of service %s: %s", pin.ServiceName, err))
+		}
+		if latest <= pin.Revision {
+			continue
+		}
+
+		info := &ServiceRevisionPinInfo{
+			ServiceName:    pin.ServiceName,
+			LatestRevision: latest,
+			Pinned:         true,
+			PinnedRevision: latest,
+			PinnedBy:       username,
+			PinTime:        time.Now().Unix(),
+		}
+		if svc, ok := serviceMap[pin.ServiceName]; ok {
+			info.CurrentRevision = svc.Revision
+		}
+		resp = append(resp, info)
+		pins = setServiceRevisionPin(pins, pin.ServiceName, latest, username)
+	}
+
+	if len(resp) == 0 {
+		return resp, nil
+	}
+	if err := commonrepo.NewProductColl().UpdateServiceRevisionPins(envName, projectName, pins); err != nil {
+		return nil, e.ErrUpdateEnv.AddErr(err)
+	}
+	return resp, nil
+}
+
+func findEnvForRevisionPin(projectName, envName string, production bool) (*commonmodels.Product, error) {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return nil, e.ErrGetEnv.AddDesc(fmt.Sprintf("failed to find env %s in project %s: %s", envName, projectName, err))
+	}
+	if env.Source == setting.SourceFromExternal || env.Source == setting.SourceFromPM {
+		return nil, e.ErrInvalidParam.AddDesc("service revision pinning is not supported for this kind of env")
+	}
+	return env, nil
+}
+
+func latestServiceRevision(projectName, serviceName string, production bool) (int64, error) {
+	svc, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+		ProductName:   projectName,
+		ServiceName:   serviceName,
+		ExcludeStatus: setting.ProductStatusDeleting,
+	}, production)
+	if err != nil {
+		return 0, err
+	}
+	return svc.Revision, nil
+}
+
+func setServiceRevisionPin(pins []*commonmodels.ServiceRevisionPin, serviceName string, revision int64, username string) []*commonmodels.ServiceRevisionPin {
+	for _, pin := range pins {
+		if pin.ServiceName == serviceName {
+			pin.Revision = revision
+			pin.PinnedBy = username
+			pin.PinTime = time.Now().Unix()
+			return pins
+		}
+	}
+	return append(pins, &commonmodels.ServiceRevisionPin{
+		ServiceName: serviceName,
+		Revision:    revision,
+		PinnedBy:    username,
+		PinTime:     time.Now().Unix(),
+	})
+}
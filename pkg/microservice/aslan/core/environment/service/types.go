@@ -103,6 +103,15 @@ type ScaleArgs struct {
 	Production  bool   `json:"production"`
 }
 
+type UpdateStatefulSetRolloutArgs struct {
+	ProductName string                                  `json:"-"`
+	EnvName     string                                  `json:"-"`
+	ServiceName string                                  `json:"-"`
+	Name        string                                  `json:"-"`
+	Production  bool                                    `json:"-"`
+	Rollout     *commonmodels.StatefulSetRolloutSetting `json:"rollout"`
+}
+
 func (pr *ProductRevision) GroupsUpdated() bool {
 	if pr.ServiceRevisions == nil || len(pr.ServiceRevisions) == 0 {
 		return false
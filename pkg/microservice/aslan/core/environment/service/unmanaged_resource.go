@@ -0,0 +1,351 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// the kinds of resources scanned when looking for resources not managed by zadig
+var unmanagedResourceGVKs = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: setting.Deployment},
+	{Group: "apps", Version: "v1", Kind: setting.StatefulSet},
+	{Group: "batch", Version: "v1", Kind: setting.CronJob},
+	{Group: "", Version: "v1", Kind: setting.Service},
+	{Group: "networking.k8s.io", Version: "v1", Kind: setting.Ingress},
+	{Group: "", Version: "v1", Kind: setting.ConfigMap},
+	{Group: "", Version: "v1", Kind: setting.Secret},
+	{Group: "", Version: "v1", Kind: setting.PersistentVolumeClaim},
+}
+
+const (
+	// UnmanagedResourceConflictOverwrite means the resource will be overwritten when the service is deployed to the env
+	UnmanagedResourceConflictOverwrite = "overwrite"
+)
+
+type UnmanagedResource struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	CreationTime int64  `json:"creation_time"`
+	// Orphaned indicates the resource is labeled by zadig but the service it belongs to no longer exists in the env,
+	// it won't be cleaned up by any env update
+	Orphaned  bool                         `json:"orphaned"`
+	Conflicts []*UnmanagedResourceConflict `json:"conflicts"`
+}
+
+type UnmanagedResourceConflict struct {
+	ServiceName string `json:"service_name"`
+	Action      string `json:"action"`
+}
+
+type UnmanagedResourceRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type AdoptUnmanagedResourcesArgs struct {
+	ServiceName string                  `json:"service_name"`
+	Resources   []*UnmanagedResourceRef `json:"resources"`
+}
+
+func unmanagedResourceKey(kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// ListUnmanagedResources lists the resources in the namespace of the env which are not owned by any service of the envs
+// sharing the namespace, the resources which will be overwritten by deploying the latest services are flagged as conflicts.
+func ListUnmanagedResources(projectName, envName string, production bool, log *zap.SugaredLogger) ([]*UnmanagedResource, error) {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return nil, e.ErrListUnmanagedResources.AddErr(err)
+	}
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return nil, e.ErrListUnmanagedResources.AddErr(err)
+	}
+
+	kubeClient, err := kubeclient.GetKubeClient(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		return nil, e.ErrListUnmanagedResources.AddErr(err)
+	}
+
+	items, err := listUnmanagedResources(kubeClient, env, log)
+	if err != nil {
+		return nil, e.ErrListUnmanagedResources.AddErr(err)
+	}
+
+	declared := make(map[string][]string)
+	if project.IsK8sYamlProduct() {
+		declared = declaredServiceResources(env, log)
+	}
+
+	resp := make([]*UnmanagedResource, 0, len(items))
+	for _, item := range items {
+		resource := &UnmanagedResource{
+			Kind:         item.GetKind(),
+			Name:         item.GetName(),
+			CreationTime: item.GetCreationTimestamp().Unix(),
+			Orphaned:     item.GetLabels()[setting.ProductLabel] == env.ProductName,
+			Conflicts:    make([]*UnmanagedResourceConflict, 0),
+		}
+		for _, serviceName := range declared[unmanagedResourceKey(item.GetKind(), item.GetName())] {
+			resource.Conflicts = append(resource.Conflicts, &UnmanagedResourceConflict{
+				ServiceName: serviceName,
+				Action:      UnmanagedResourceConflictOverwrite,
+			})
+		}
+		resp = append(resp, resource)
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		if len(resp[i].Conflicts) != len(resp[j].Conflicts) {
+			return len(resp[i].Conflicts) > len(resp[j].Conflicts)
+		}
+		return unmanagedResourceKey(resp[i].Kind, resp[i].Name) < unmanagedResourceKey(resp[j].Kind, resp[j].Name)
+	})
+	return resp, nil
+}
+
+// AdoptUnmanagedResources claims the ownership of the unmanaged resources for a service of the env,
+// the resources are labeled with the service and recorded as the resources of the service.
+func AdoptUnmanagedResources(projectName, envName string, production bool, args *AdoptUnmanagedResourcesArgs, log *zap.SugaredLogger) error {
+	if args.ServiceName == "" || len(args.Resources) == 0 {
+		return e.ErrAdoptUnmanagedResources.AddDesc("service name and resources can't be empty")
+	}
+
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return e.ErrAdoptUnmanagedResources.AddErr(err)
+	}
+	if !project.IsK8sYamlProduct() {
+		return e.ErrAdoptUnmanagedResources.AddDesc("adopting resources is only supported by k8s yaml projects")
+	}
+
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    envName,
+		Production: &production,
+	})
+	if err != nil {
+		return e.ErrAdoptUnmanagedResources.AddErr(err)
+	}
+
+	groupIndex, svc := -1, (*commonmodels.ProductService)(nil)
+	for i, group := range env.Services {
+		for _, s := range group {
+			if s.ServiceName == args.ServiceName {
+				groupIndex, svc = i, s
+			}
+		}
+	}
+	if svc == nil {
+		return e.ErrAdoptUnmanagedResources.AddDesc(fmt.Sprintf("service %s is not found in env %s", args.ServiceName, envName))
+	}
+
+	kubeClient, err := kubeclient.GetKubeClient(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		return e.ErrAdoptUnmanagedResources.AddErr(err)
+	}
+	items, err := listUnmanagedResources(kubeClient, env, log)
+	if err != nil {
+		return e.ErrAdoptUnmanagedResources.AddErr(err)
+	}
+	unmanaged := make(map[string]*unstructured.Unstructured)
+	for _, item := range items {
+		unmanaged[unmanagedResourceKey(item.GetKind(), item.GetName())] = item
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": kube.GetPredefinedLabels(projectName, args.ServiceName),
+		},
+	})
+	if err != nil {
+		return e.ErrAdoptUnmanagedResources.AddErr(err)
+	}
+
+	for _, ref := range args.Resources {
+		item, ok := unmanaged[unmanagedResourceKey(ref.Kind, ref.Name)]
+		if !ok {
+			return e.ErrAdoptUnmanagedResources.AddDesc(fmt.Sprintf("%s/%s is not an unmanaged resource of env %s", ref.Kind, ref.Name, envName))
+		}
+		if err := kubeClient.Patch(context.TODO(), item, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			return e.ErrAdoptUnmanagedResources.AddErr(fmt.Errorf("failed to label %s/%s: %s", ref.Kind, ref.Name, err))
+		}
+		svc.Resources = append(svc.Resources, &commonmodels.ServiceResource{
+			GroupVersionKind: item.GroupVersionKind(),
+			Name:             item.GetName(),
+		})
+	}
+
+	if err := commonrepo.NewProductColl().UpdateGroup(envName, projectName, groupIndex, env.Services[groupIndex]); err != nil {
+		return e.ErrAdoptUnmanagedResources.AddErr(err)
+	}
+	return nil
+}
+
+// listUnmanagedResources returns the resources in the namespace of the env which are not owned by any service
+// or common env config of the envs sharing the namespace.
+func listUnmanagedResources(kubeClient client.Client, env *commonmodels.Product, log *zap.SugaredLogger) ([]*unstructured.Unstructured, error) {
+	envs, err := commonrepo.NewProductColl().ListEnvByNamespace(env.ClusterID, env.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list envs in namespace %s: %s", env.Namespace, err)
+	}
+
+	owned := sets.NewString()
+	releases := sets.NewString()
+	labeledServices := sets.NewString()
+	for _, sharedEnv := range envs {
+		for _, svc := range sharedEnv.GetSvcList() {
+			for _, res := range svc.Resources {
+				owned.Insert(unmanagedResourceKey(res.Kind, res.Name))
+			}
+			if svc.ReleaseName != "" {
+				releases.Insert(svc.ReleaseName)
+			}
+			labeledServices.Insert(fmt.Sprintf("%s/%s", sharedEnv.ProductName, svc.ServiceName))
+		}
+
+		envResources, err := commonrepo.NewEnvResourceColl().List(&commonrepo.QueryEnvResourceOption{
+			ProductName: sharedEnv.ProductName,
+			EnvName:     sharedEnv.EnvName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list common env configs of env %s/%s: %s", sharedEnv.ProductName, sharedEnv.EnvName, err)
+		}
+		for _, res := range envResources {
+			kind := res.Type
+			if kind == string(config.CommonEnvCfgTypePvc) {
+				kind = setting.PersistentVolumeClaim
+			}
+			owned.Insert(unmanagedResourceKey(kind, res.Name))
+		}
+	}
+
+	resp := make([]*unstructured.Unstructured, 0)
+	for _, gvk := range unmanagedResourceGVKs {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := kubeClient.List(context.TODO(), list, client.InNamespace(env.Namespace)); err != nil {
+			if apierrors.IsNotFound(err) || strings.Contains(err.Error(), "no matches for kind") {
+				log.Warnf("%s is not supported by cluster %s: %s", gvk.Kind, env.ClusterID, err)
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s in namespace %s: %s", gvk.Kind, env.Namespace, err)
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			item.SetGroupVersionKind(gvk)
+			if owned.Has(unmanagedResourceKey(item.GetKind(), item.GetName())) || len(item.GetOwnerReferences()) > 0 {
+				continue
+			}
+			if releases.Has(item.GetAnnotations()[setting.HelmReleaseNameAnnotation]) {
+				continue
+			}
+			labels := item.GetLabels()
+			if labels[setting.ServiceLabel] != "" && labeledServices.Has(fmt.Sprintf("%s/%s", labels[setting.ProductLabel], labels[setting.ServiceLabel])) {
+				continue
+			}
+			if isSystemResource(item) {
+				continue
+			}
+			resp = append(resp, item)
+		}
+	}
+	return resp, nil
+}
+
+// isSystemResource returns whether the resource is created by kubernetes, helm or zadig itself
+func isSystemResource(item *unstructured.Unstructured) bool {
+	switch item.GetKind() {
+	case setting.ConfigMap:
+		return item.GetName() == "kube-root-ca.crt"
+	case setting.Secret:
+		secretType, _, _ := unstructured.NestedString(item.Object, "type")
+		switch corev1.SecretType(secretType) {
+		case corev1.SecretTypeServiceAccountToken, "helm.sh/release.v1":
+			return true
+		case corev1.SecretTypeDockerConfigJson:
+			return item.GetName() == setting.DefaultImagePullSecret || strings.HasSuffix(item.GetName(), "-registry-secret")
+		}
+	}
+	return false
+}
+
+// declaredServiceResources returns the services declaring each resource in the latest rendered yaml,
+// these resources will be overwritten once the services are deployed to the env.
+func declaredServiceResources(env *commonmodels.Product, log *zap.SugaredLogger) map[string][]string {
+	resp := make(map[string][]string)
+	services, err := repository.ListMaxRevisionsServices(env.ProductName, env.Production)
+	if err != nil {
+		log.Warnf("failed to list services of project %s: %s", env.ProductName, err)
+		return resp
+	}
+
+	for _, svc := range services {
+		if svc.Type != setting.K8SDeployType {
+			continue
+		}
+		yamlContent, _, _, err := kube.GenerateRenderedYaml(&kube.GeneSvcYamlOption{
+			ProductName:           env.ProductName,
+			EnvName:               env.EnvName,
+			ServiceName:           svc.ServiceName,
+			UpdateServiceRevision: true,
+		})
+		if err != nil {
+			log.Warnf("failed to render yaml of service %s in env %s/%s: %s", svc.ServiceName, env.ProductName, env.EnvName, err)
+			continue
+		}
+		resources, err := kube.ManifestToResource(yamlContent)
+		if err != nil {
+			log.Warnf("failed to parse yaml of service %s in env %s/%s: %s", svc.ServiceName, env.ProductName, env.EnvName, err)
+			continue
+		}
+		for _, res := range resources {
+			key := unmanagedResourceKey(res.Kind, res.Name)
+			resp[key] = append(resp[key], svc.ServiceName)
+		}
+	}
+	return resp
+}
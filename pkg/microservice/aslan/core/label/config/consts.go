@@ -16,7 +16,13 @@ limitations under the License.
 
 package config
 
-import "github.com/koderover/zadig/v2/pkg/setting"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
 
 type ResourceType string
 
@@ -24,8 +30,29 @@ const (
 	ResourceTypeWorkflow       ResourceType = "Workflow"
 	ResourceTypeCommonWorkflow ResourceType = "CommonWorkflow"
 	ResourceTypeEnvironment    ResourceType = "Environment"
+	ResourceTypeService        ResourceType = "Service"
+	// ResourceTypeProductionService is the service of the production environments
+	ResourceTypeProductionService ResourceType = "ProductionService"
+	// ResourceTypeWorkflowTask is the task of the custom workflows, the resource name is built by BuildTaskResourceName
+	ResourceTypeWorkflowTask ResourceType = "WorkflowTask"
 )
 
+func BuildTaskResourceName(workflowName string, taskID int64) string {
+	return fmt.Sprintf("%s/%d", workflowName, taskID)
+}
+
+func ParseTaskResourceName(name string) (string, int64, bool) {
+	idx := strings.LastIndex(name, "/")
+	if idx <= 0 {
+		return "", 0, false
+	}
+	taskID, err := strconv.ParseInt(name[idx+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:idx], taskID, true
+}
+
 func GetWorkflowResourceType(workflowType string) string {
 	if IsCustomWorkflow(workflowType) {
 		return string(ResourceTypeCommonWorkflow)
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// checkLabelRulePermission checks the permission of managing the rules, the global rules can only be managed
+// by the system admins.
+func checkLabelRulePermission(ctx *internalhandler.Context, projectName string) bool {
+	if ctx.Resources.IsSystemAdmin {
+		return true
+	}
+	if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectName]; projectName != "" && ok && projectAuthInfo.IsProjectAdmin {
+		return true
+	}
+	ctx.UnAuthorized = true
+	return false
+}
+
+// @Summary List Label Rules
+// @Description List the label rules of the project and the global label rules
+// @Tags 	label
+// @Accept 	json
+// @Produce json
+// @Param 	projectName	query		string							false	"project name"
+// @Success 200 		{object} 	service.ListLabelRulesResp
+// @Router /api/aslan/label/labels/rules [get]
+func ListLabelRules(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListLabelRules(c.Query("projectName"), ctx.Logger)
+}
+
+// @Summary Upsert Label Rule
+// @Description Create or update the label rule of the key, the rule without project applies to all projects
+// @Tags 	label
+// @Accept 	json
+// @Produce json
+// @Param 	body 		body 		models.LabelRule 				true 	"body"
+// @Success 200
+// @Router /api/aslan/label/labels/rules [put]
+func UpsertLabelRule(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	rule := new(models.LabelRule)
+	if err := c.ShouldBindJSON(rule); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, rule.ProjectName, "更新", "标签规则", rule.Key, "", ctx.Logger)
+
+	if !checkLabelRulePermission(ctx, rule.ProjectName) {
+		return
+	}
+
+	ctx.Err = service.UpsertLabelRule(rule, ctx.UserName, ctx.Logger)
+}
+
+// @Summary Delete Label Rule
+// @Description Delete Label Rule
+// @Tags 	label
+// @Accept 	json
+// @Produce json
+// @Param 	key			path		string							true	"label key"
+// @Param 	projectName	query		string							false	"project name"
+// @Success 200
+// @Router /api/aslan/label/labels/rules/{key} [delete]
+func DeleteLabelRule(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectName := c.Query("projectName")
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectName, "删除", "标签规则", c.Param("key"), "", ctx.Logger)
+
+	if !checkLabelRulePermission(ctx, projectName) {
+		return
+	}
+
+	ctx.Err = service.DeleteLabelRule(projectName, c.Param("key"), ctx.Logger)
+}
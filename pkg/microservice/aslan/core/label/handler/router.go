@@ -30,6 +30,10 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		labels.POST("/bulk-delete", DeleteLabels)
 		labels.POST("/resources-by-labels", ListResourcesByLabels)
 		labels.POST("/labels-by-resources", ListLabelsByResources)
+
+		labels.GET("/rules", ListLabelRules)
+		labels.PUT("/rules", UpsertLabelRule)
+		labels.DELETE("/rules/:key", DeleteLabelRule)
 	}
 	labelBindings := router.Group("labelbindings")
 	{
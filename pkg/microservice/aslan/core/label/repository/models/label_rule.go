@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LabelRule restricts the labels of a key in a project, the rule of the empty project applies to all projects.
+type LabelRule struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"               json:"id,omitempty"`
+	ProjectName string             `bson:"project_name"                json:"project_name"`
+	Key         string             `bson:"key"                         json:"key"`
+	Description string             `bson:"description"                 json:"description"`
+	// Values are the allowed values of the key, any value is allowed if it's empty
+	Values []string `bson:"values"                      json:"values"`
+	// ValuePattern is the regular expression the values must match
+	ValuePattern string `bson:"value_pattern"               json:"value_pattern"`
+	// ResourceTypes are the resource types the key can be bound to, all types are allowed if it's empty
+	ResourceTypes []string `bson:"resource_types"              json:"resource_types"`
+	UpdateBy      string   `bson:"update_by"                   json:"update_by"`
+	UpdateTime    int64    `bson:"update_time"                 json:"update_time"`
+}
+
+func (LabelRule) TableName() string {
+	return "label_rule"
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/repository/models"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+type LabelRuleColl struct {
+	*mongo.Collection
+
+	coll string
+}
+
+func NewLabelRuleColl() *LabelRuleColl {
+	name := models.LabelRule{}.TableName()
+	return &LabelRuleColl{Collection: mongotool.Database(config.MongoDatabase()).Collection(name), coll: name}
+}
+
+func (c *LabelRuleColl) GetCollectionName() string {
+	return c.coll
+}
+
+func (c *LabelRuleColl) EnsureIndex(ctx context.Context) error {
+	mod := mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "project_name", Value: 1},
+			bson.E{Key: "key", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := c.Indexes().CreateOne(ctx, mod)
+	return err
+}
+
+func (c *LabelRuleColl) Upsert(rule *models.LabelRule) error {
+	rule.UpdateTime = time.Now().Unix()
+	query := bson.M{"project_name": rule.ProjectName, "key": rule.Key}
+	change := bson.M{"$set": bson.M{
+		"project_name":   rule.ProjectName,
+		"key":            rule.Key,
+		"description":    rule.Description,
+		"values":         rule.Values,
+		"value_pattern":  rule.ValuePattern,
+		"resource_types": rule.ResourceTypes,
+		"update_by":      rule.UpdateBy,
+		"update_time":    rule.UpdateTime,
+	}}
+	_, err := c.UpdateOne(context.TODO(), query, change, options.Update().SetUpsert(true))
+	return err
+}
+
+func (c *LabelRuleColl) Delete(projectName, key string) error {
+	_, err := c.DeleteOne(context.TODO(), bson.M{"project_name": projectName, "key": key})
+	return err
+}
+
+// List lists the rules of the projects, the global rules are included if the empty project is in the list.
+func (c *LabelRuleColl) List(projectNames []string) ([]*models.LabelRule, error) {
+	res := make([]*models.LabelRule, 0)
+	query := bson.M{"project_name": bson.M{"$in": projectNames}}
+	opts := options.Find().SetSort(bson.D{{"project_name", 1}, {"key", 1}})
+	cursor, err := c.Collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -98,6 +98,7 @@ type LabelBindingCollFindOpt struct {
 	ResourceID   string
 	ResourcesIDs []string
 	ResourceType string
+	ProjectName  string
 }
 
 func (c *LabelBindingColl) FindByOpt(opt *LabelBindingCollFindOpt) (*models.LabelBinding, error) {
@@ -128,6 +129,9 @@ func (c *LabelBindingColl) ListByOpt(opt *LabelBindingCollFindOpt) ([]*models.La
 	if opt.ResourceType != "" {
 		query["resource_type"] = opt.ResourceType
 	}
+	if opt.ProjectName != "" {
+		query["project_name"] = opt.ProjectName
+	}
 	ctx := context.Background()
 
 	cursor, err := c.Collection.Find(ctx, query)
@@ -32,6 +32,9 @@ type CreateLabelsResp struct {
 }
 
 func CreateLabels(arg *CreateLabelsArgs, userName string) (*CreateLabelsResp, error) {
+	if err := validateLabels(arg.Labels); err != nil {
+		return nil, err
+	}
 	filteredLabels := make([]*models.Label, 0)
 	keyValues := sets.NewString()
 	for _, v := range arg.Labels {
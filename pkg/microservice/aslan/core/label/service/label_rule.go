@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const (
+	maxLabelKeyLength   = 63
+	maxLabelValueLength = 255
+	// the chars are used by the label selectors
+	labelReservedChars      = ",=!() \t"
+	labelValueReservedChars = ",()"
+)
+
+var labelResourceTypes = sets.NewString(
+	string(config.ResourceTypeWorkflow),
+	string(config.ResourceTypeCommonWorkflow),
+	string(config.ResourceTypeEnvironment),
+	string(config.ResourceTypeService),
+	string(config.ResourceTypeProductionService),
+	string(config.ResourceTypeWorkflowTask),
+)
+
+// validateLabelFormat checks the format of the label so that it can be used in the label selectors.
+func validateLabelFormat(key, value string) error {
+	switch {
+	case key == "" || value == "":
+		return fmt.Errorf("the key and the value of the label can't be empty")
+	case utf8.RuneCountInString(key) > maxLabelKeyLength:
+		return fmt.Errorf("the key %s is longer than %d", key, maxLabelKeyLength)
+	case utf8.RuneCountInString(value) > maxLabelValueLength:
+		return fmt.Errorf("the value of key %s is longer than %d", key, maxLabelValueLength)
+	case strings.ContainsAny(key, labelReservedChars):
+		return fmt.Errorf("the key %s can't contain any of %q", key, labelReservedChars)
+	case strings.ContainsAny(value, labelValueReservedChars) || strings.TrimSpace(value) != value:
+		return fmt.Errorf("the value %s of key %s can't contain any of %q or start or end with spaces", value, key, labelValueReservedChars)
+	}
+	return nil
+}
+
+// getLabelRules returns the rules of the project by the keys, the rules of the project override the global rules.
+func getLabelRules(projectName string) (map[string]*models.LabelRule, error) {
+	rules, err := mongodb.NewLabelRuleColl().List([]string{"", projectName})
+	if err != nil {
+		return nil, err
+	}
+	resp := make(map[string]*models.LabelRule)
+	for _, rule := range rules {
+		if _, ok := resp[rule.Key]; ok && rule.ProjectName == "" {
+			continue
+		}
+		resp[rule.Key] = rule
+	}
+	return resp, nil
+}
+
+func validateLabelValue(rule *models.LabelRule, value string) error {
+	if len(rule.Values) > 0 && !sets.NewString(rule.Values...).Has(value) {
+		return fmt.Errorf("the value %s of key %s is not in %v", value, rule.Key, rule.Values)
+	}
+	if rule.ValuePattern != "" {
+		re, err := regexp.Compile(rule.ValuePattern)
+		if err != nil {
+			return fmt.Errorf("invalid value pattern of key %s: %v", rule.Key, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("the value %s of key %s doesn't match %s", value, rule.Key, rule.ValuePattern)
+		}
+	}
+	return nil
+}
+
+// validateLabels checks the format of the labels and the rules of their projects.
+func validateLabels(labels []mongodb.Label) error {
+	rulesByProject := make(map[string]map[string]*models.LabelRule)
+	for _, label := range labels {
+		if err := validateLabelFormat(label.Key, label.Value); err != nil {
+			return e.ErrInvalidLabel.AddErr(err)
+		}
+		rules, ok := rulesByProject[label.ProjectName]
+		if !ok {
+			var err error
+			rules, err = getLabelRules(label.ProjectName)
+			if err != nil {
+				return e.ErrListLabelRules.AddErr(err)
+			}
+			rulesByProject[label.ProjectName] = rules
+		}
+		if rule, ok := rules[label.Key]; ok {
+			if err := validateLabelValue(rule, label.Value); err != nil {
+				return e.ErrInvalidLabel.AddErr(err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateLabelBindings checks whether the keys of the labels can be bound to the types of the resources.
+func validateLabelBindings(bindings []*mongodb.LabelBinding, labels []*models.Label) error {
+	labelMap := make(map[string]*models.Label)
+	for _, label := range labels {
+		labelMap[label.ID.Hex()] = label
+	}
+	rulesByProject := make(map[string]map[string]*models.LabelRule)
+	for _, binding := range bindings {
+		if !labelResourceTypes.Has(binding.Resource.Type) {
+			return e.ErrInvalidLabel.AddDesc(fmt.Sprintf("unknown resource type %s", binding.Resource.Type))
+		}
+		label, ok := labelMap[binding.LabelID]
+		if !ok {
+			continue
+		}
+		rules, ok := rulesByProject[binding.Resource.ProjectName]
+		if !ok {
+			var err error
+			rules, err = getLabelRules(binding.Resource.ProjectName)
+			if err != nil {
+				return e.ErrListLabelRules.AddErr(err)
+			}
+			rulesByProject[binding.Resource.ProjectName] = rules
+		}
+		rule, ok := rules[label.Key]
+		if !ok {
+			continue
+		}
+		if len(rule.ResourceTypes) > 0 && !sets.NewString(rule.ResourceTypes...).Has(binding.Resource.Type) {
+			return e.ErrInvalidLabel.AddDesc(fmt.Sprintf("the label %s can't be bound to %s", label.Key, binding.Resource.Type))
+		}
+		// the label may be created before the rule
+		if err := validateLabelValue(rule, label.Value); err != nil {
+			return e.ErrInvalidLabel.AddErr(err)
+		}
+	}
+	return nil
+}
+
+type ListLabelRulesResp struct {
+	Rules []*models.LabelRule `json:"rules"`
+}
+
+// ListLabelRules lists the rules of the project and the global rules.
+func ListLabelRules(projectName string, logger *zap.SugaredLogger) (*ListLabelRulesResp, error) {
+	rules, err := mongodb.NewLabelRuleColl().List([]string{"", projectName})
+	if err != nil {
+		logger.Errorf("failed to list label rules of project %s, error: %s", projectName, err)
+		return nil, e.ErrListLabelRules.AddErr(err)
+	}
+	return &ListLabelRulesResp{Rules: rules}, nil
+}
+
+func UpsertLabelRule(rule *models.LabelRule, userName string, logger *zap.SugaredLogger) error {
+	if rule.Key == "" || utf8.RuneCountInString(rule.Key) > maxLabelKeyLength || strings.ContainsAny(rule.Key, labelReservedChars) {
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid key %s", rule.Key))
+	}
+	if rule.ValuePattern != "" {
+		if _, err := regexp.Compile(rule.ValuePattern); err != nil {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid value pattern: %s", err))
+		}
+	}
+	for _, value := range rule.Values {
+		if err := validateLabelFormat(rule.Key, value); err != nil {
+			return e.ErrInvalidParam.AddErr(err)
+		}
+		if err := validateLabelValue(&models.LabelRule{Key: rule.Key, ValuePattern: rule.ValuePattern}, value); err != nil {
+			return e.ErrInvalidParam.AddErr(err)
+		}
+	}
+	for _, resourceType := range rule.ResourceTypes {
+		if !labelResourceTypes.Has(resourceType) {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("unknown resource type %s", resourceType))
+		}
+	}
+
+	rule.UpdateBy = userName
+	if err := mongodb.NewLabelRuleColl().Upsert(rule); err != nil {
+		logger.Errorf("failed to upsert label rule %s of project %s, error: %s", rule.Key, rule.ProjectName, err)
+		return e.ErrUpdateLabelRule.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteLabelRule(projectName, key string, logger *zap.SugaredLogger) error {
+	if err := mongodb.NewLabelRuleColl().Delete(projectName, key); err != nil {
+		logger.Errorf("failed to delete label rule %s of project %s, error: %s", key, projectName, err)
+		return e.ErrDeleteLabelRule.AddErr(err)
+	}
+	return nil
+}
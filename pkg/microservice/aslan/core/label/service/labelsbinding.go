@@ -50,6 +50,9 @@ func CreateLabelBindings(cr *CreateLabelBindingsArgs, userName string, logger *z
 		logger.Errorf("there're labels not exist")
 		return fmt.Errorf("there're labels not exist")
 	}
+	if err := validateLabelBindings(cr.LabelBindings, labels); err != nil {
+		return err
+	}
 	//check resource exist
 	m := make(map[string][]*mongodb.LabelBinding)
 	for _, binding := range cr.LabelBindings {
@@ -118,6 +121,31 @@ func CreateLabelBindings(cr *CreateLabelBindingsArgs, userName string, logger *z
 				logger.Errorf("there're resources not exist")
 				return e.ErrForbidden.AddDesc("there're resources not exist")
 			}
+		case string(config.ResourceTypeService), string(config.ResourceTypeProductionService):
+			for _, vv := range v {
+				opt := &commondb.ServiceFindOption{ServiceName: vv.Resource.Name, ProductName: vv.Resource.ProjectName}
+				if k == string(config.ResourceTypeService) {
+					_, err = commondb.NewServiceColl().Find(opt)
+				} else {
+					_, err = commondb.NewProductionServiceColl().Find(opt)
+				}
+				if err != nil {
+					logger.Errorf("can not find service %s of project %s, err:%s", vv.Resource.Name, vv.Resource.ProjectName, err)
+					return e.ErrForbidden.AddDesc("there're resources not exist")
+				}
+			}
+		case string(config.ResourceTypeWorkflowTask):
+			for _, vv := range v {
+				workflowName, taskID, ok := config.ParseTaskResourceName(vv.Resource.Name)
+				if !ok {
+					return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid task resource name %s", vv.Resource.Name))
+				}
+				task, err := commondb.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+				if err != nil || task.ProjectName != vv.Resource.ProjectName {
+					logger.Errorf("can not find task %s of project %s, err:%v", vv.Resource.Name, vv.Resource.ProjectName, err)
+					return e.ErrForbidden.AddDesc("there're resources not exist")
+				}
+			}
 		}
 	}
 
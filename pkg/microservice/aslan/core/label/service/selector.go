@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type selectorOperator string
+
+const (
+	selectorOpEquals       selectorOperator = "="
+	selectorOpNotEquals    selectorOperator = "!="
+	selectorOpIn           selectorOperator = "in"
+	selectorOpNotIn        selectorOperator = "notin"
+	selectorOpExists       selectorOperator = "exists"
+	selectorOpDoesNotExist selectorOperator = "!"
+)
+
+var setRequirementRegexp = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+type requirement struct {
+	key      string
+	operator selectorOperator
+	values   sets.String
+}
+
+// Selector selects the resources by their labels, the syntax is the same as the kubernetes label selector:
+// "tier=backend,owner!=alice,env in (dev,test),team notin (a,b),cost-center,!deprecated".
+// A resource can be bound to several values of a key, "key=value" matches if any of the values equals the value
+// and "key!=value" matches if none of the values equals the value.
+type Selector []*requirement
+
+func ParseSelector(selector string) (Selector, error) {
+	resp := make(Selector, 0)
+	for _, part := range splitRequirements(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		req, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, req)
+	}
+	return resp, nil
+}
+
+// splitRequirements splits the selector by the commas which are not in the value sets.
+func splitRequirements(selector string) []string {
+	resp := make([]string, 0)
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				resp = append(resp, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(resp, selector[start:])
+}
+
+func parseRequirement(s string) (*requirement, error) {
+	req := &requirement{values: sets.NewString()}
+	switch {
+	case setRequirementRegexp.MatchString(s):
+		matches := setRequirementRegexp.FindStringSubmatch(s)
+		req.key, req.operator = matches[1], selectorOperator(matches[2])
+		for _, v := range strings.Split(matches[3], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				req.values.Insert(v)
+			}
+		}
+		if req.values.Len() == 0 {
+			return nil, fmt.Errorf("invalid label selector %q: empty value set", s)
+		}
+	case strings.Contains(s, "!="):
+		kv := strings.SplitN(s, "!=", 2)
+		req.key, req.operator = strings.TrimSpace(kv[0]), selectorOpNotEquals
+		req.values.Insert(strings.TrimSpace(kv[1]))
+	case strings.Contains(s, "="):
+		kv := strings.SplitN(strings.Replace(s, "==", "=", 1), "=", 2)
+		req.key, req.operator = strings.TrimSpace(kv[0]), selectorOpEquals
+		req.values.Insert(strings.TrimSpace(kv[1]))
+	case strings.HasPrefix(s, "!"):
+		req.key, req.operator = strings.TrimSpace(s[1:]), selectorOpDoesNotExist
+	default:
+		req.key, req.operator = s, selectorOpExists
+	}
+
+	if req.key == "" || strings.ContainsAny(req.key, labelReservedChars) {
+		return nil, fmt.Errorf("invalid label selector %q: invalid key", s)
+	}
+	return req, nil
+}
+
+// Matches returns true if the labels, which map the keys to the values, match all the requirements.
+func (s Selector) Matches(labels map[string]sets.String) bool {
+	for _, req := range s {
+		values, ok := labels[req.key]
+		switch req.operator {
+		case selectorOpEquals, selectorOpIn:
+			if !ok || !values.HasAny(req.values.UnsortedList()...) {
+				return false
+			}
+		case selectorOpNotEquals, selectorOpNotIn:
+			if ok && values.HasAny(req.values.UnsortedList()...) {
+				return false
+			}
+		case selectorOpExists:
+			if !ok {
+				return false
+			}
+		case selectorOpDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ResourceFilter filters the resources of a type in a project by the label selector.
+type ResourceFilter struct {
+	selector Selector
+	// labels maps the names of the labeled resources to their labels
+	labels map[string]map[string]sets.String
+}
+
+func NewResourceFilter(resourceType config.ResourceType, projectName, selector string) (*ResourceFilter, error) {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil, e.ErrInvalidParam.AddErr(err)
+	}
+	filter := &ResourceFilter{selector: s, labels: make(map[string]map[string]sets.String)}
+	if len(s) == 0 {
+		return filter, nil
+	}
+
+	bindings, err := mongodb.NewLabelBindingColl().ListByOpt(&mongodb.LabelBindingCollFindOpt{
+		ResourceType: string(resourceType),
+		ProjectName:  projectName,
+	})
+	if err != nil {
+		return nil, e.ErrListLabels.AddErr(err)
+	}
+	labelIDs := sets.NewString()
+	for _, binding := range bindings {
+		labelIDs.Insert(binding.LabelID)
+	}
+	if labelIDs.Len() == 0 {
+		return filter, nil
+	}
+	labels, err := mongodb.NewLabelColl().ListByIDs(labelIDs.List())
+	if err != nil {
+		return nil, e.ErrListLabels.AddErr(err)
+	}
+	labelMap := make(map[string]*models.Label)
+	for _, label := range labels {
+		labelMap[label.ID.Hex()] = label
+	}
+
+	for _, binding := range bindings {
+		label, ok := labelMap[binding.LabelID]
+		if !ok {
+			continue
+		}
+		if _, ok := filter.labels[binding.ResourceName]; !ok {
+			filter.labels[binding.ResourceName] = make(map[string]sets.String)
+		}
+		if _, ok := filter.labels[binding.ResourceName][label.Key]; !ok {
+			filter.labels[binding.ResourceName][label.Key] = sets.NewString()
+		}
+		filter.labels[binding.ResourceName][label.Key].Insert(label.Value)
+	}
+	return filter, nil
+}
+
+func (f *ResourceFilter) Matches(name string) bool {
+	return f.selector.Matches(f.labels[name])
+}
+
+// MatchesUnlabeled returns true if the resources without labels match the selector, e.g. "!deprecated".
+func (f *ResourceFilter) MatchesUnlabeled() bool {
+	return f.selector.Matches(nil)
+}
+
+// LabeledNames returns the names of the labeled resources which match or don't match the selector, so that the
+// resources can be filtered in the database queries together with MatchesUnlabeled.
+func (f *ResourceFilter) LabeledNames(matched bool) []string {
+	resp := make([]string, 0)
+	for name := range f.labels {
+		if f.Matches(name) == matched {
+			resp = append(resp, name)
+		}
+	}
+	return resp
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -8,6 +9,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/log/service/ai"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
 )
 
 func AIAnalyzeBuildLog(c *gin.Context) {
@@ -30,3 +32,57 @@ func AIAnalyzeBuildLog(c *gin.Context) {
 	args.Log = string(data)
 	ctx.Resp, ctx.Err = ai.AnalyzeBuildLog(args, c.Query("projectName"), c.Param("workflowName"), c.Param("jobName"), taskID, ctx.Logger)
 }
+
+// @Summary AI Analyze Workflow Task
+// @Description Analyze the failed jobs of a workflow task with the default llm and return the root cause and suggested fixes.
+// @Description Workflow analysis must be enabled in the llm integration, sensitive data is masked before it is sent to the llm.
+// @Tags 	log
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string							true	"project name"
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	taskID			path		string							true	"task id"
+// @Success 200 			{object} 	ai.WorkflowTaskAnalysis
+// @Router /api/aslan/logs/log/ai/workflow/{workflowName}/tasks/{taskID}/troubleshoot [post]
+func AIAnalyzeWorkflowTask(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+	projectName := c.Query("projectName")
+	if projectName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName cannot be empty")
+		return
+	}
+	workflowName := c.Param("workflowName")
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectName]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectName].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectName].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectName, types.ResourceTypeWorkflow, workflowName, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = ai.AnalyzeWorkflowTask(projectName, workflowName, taskID, ctx.Logger)
+}
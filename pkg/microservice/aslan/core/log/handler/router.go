@@ -38,6 +38,7 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		log.GET("/scanning/:id/task/:scan_id", GetScanningContainerLogs)
 		log.GET("/v4/workflow/:workflowName/tasks/:taskID/jobs/:jobName", GetWorkflowV4JobContainerLogs)
 		log.POST("/ai/workflow/:workflowName/tasks/:taskID/jobs/:jobName", AIAnalyzeBuildLog)
+		log.POST("/ai/workflow/:workflowName/tasks/:taskID/troubleshoot", AIAnalyzeWorkflowTask)
 	}
 
 	sse := router.Group("sse")
@@ -488,6 +488,18 @@ func GetJenkinsJobContainerLogsSSE(c *gin.Context) {
 	}, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Container Logs SSE
+// @Description OpenAPI Get Container Logs SSE
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce text/event-stream
+// @Param 	podName	path	string	true	"pod name"
+// @Param 	containerName	path	string	true	"container name"
+// @Param 	tails	query	string	false	"tails"
+// @Param 	envName	query	string	false	"env name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/logs/sse/pods/{podName}/containers/{containerName} [get]
 func OpenAPIGetContainerLogsSSE(c *gin.Context) {
 	logger := ginzap.WithContext(c).Sugar()
 
@@ -4,3 +4,9 @@ package ai
 const BuildLogAnalysisPrompt = `你是一个资深devops开发专家，我会提供一份用三重引号分割的构建过程中产生的日志数据，你需要按照要求生成对该日志的分析报告，在分析报告中，你需要根据输入的日志数据来分析此次构建的整体效率，
 并重点分析日志中出现的异常问题，异常问题需要提供出现异常的位置，异常的原因，并提供高质量的异常解决方案；你的回答需要符合text格式，同时你的回答中不要复述我的问题，直接回答你的分析报告即可。
 `
+
+// WorkflowTaskAnalysisPrompt asks the llm to return a structured root-cause hypothesis for a failed workflow task
+const WorkflowTaskAnalysisPrompt = `你是一个资深devops开发专家，我会提供一份用三重引号分割的工作流任务执行数据，其中包含失败任务的元数据以及失败任务的日志，日志中的敏感信息已经被替换为 ******。
+你需要分析任务失败的根本原因，并给出修复建议。你的回答必须是一个合法的 JSON 对象，不要包含任何其他内容，格式如下：
+{"root_cause": "最可能的失败根因", "failed_job": "导致失败的任务名称", "evidence": ["支撑该结论的关键日志或元数据"], "suggested_fixes": ["具体可执行的修复建议"], "confidence": "high|medium|low"}
+`
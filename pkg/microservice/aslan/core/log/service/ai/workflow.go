@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	openapi "github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	logservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/log/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/llm"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+const (
+	redactedMark = "******"
+	// workflowJobLogRowNum is the number of the last log rows of each failed job sent to the llm
+	workflowJobLogRowNum = 200
+)
+
+// builtinRedactionRules mask the common credentials in logs and metadata, the user defined rules are applied after them
+var builtinRedactionRules = []*redactionRule{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), redactedMark},
+	{regexp.MustCompile(`(?i)(bearer|basic)\s+[a-z0-9\-._~+/]+=*`), "${1} " + redactedMark},
+	{regexp.MustCompile(`(?i)(password|passwd|pwd|secret|token|access[_-]?key|secret[_-]?key|api[_-]?key)(["']?\s*[:=]\s*["']?)[^\s"',;]+`), "${1}${2}" + redactedMark},
+	{regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`), "://" + redactedMark + "@"},
+	{regexp.MustCompile(`AKIA[0-9A-Z]{16}`), redactedMark},
+}
+
+type redactionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+type WorkflowTaskAnalysis struct {
+	WorkflowName   string                   `json:"workflow_name"`
+	TaskID         int64                    `json:"task_id"`
+	FailedJobs     []*WorkflowFailedJobInfo `json:"failed_jobs"`
+	RootCause      string                   `json:"root_cause"`
+	FailedJob      string                   `json:"failed_job"`
+	Evidence       []string                 `json:"evidence"`
+	SuggestedFixes []string                 `json:"suggested_fixes"`
+	Confidence     string                   `json:"confidence"`
+	// RawAnswer is set when the answer of the llm is not a valid json
+	RawAnswer string `json:"raw_answer,omitempty"`
+}
+
+type WorkflowFailedJobInfo struct {
+	Name      string        `json:"name"`
+	JobType   string        `json:"job_type"`
+	Status    config.Status `json:"status"`
+	Error     string        `json:"error"`
+	StartTime int64         `json:"start_time"`
+	EndTime   int64         `json:"end_time"`
+	Log       string        `json:"log,omitempty"`
+}
+
+// AnalyzeWorkflowTask collects the metadata and logs of the failed jobs in the task, masks the sensitive data
+// and asks the default llm for a root-cause hypothesis and suggested fixes.
+// It only works when workflow analysis is enabled in the llm integration.
+func AnalyzeWorkflowTask(projectName, workflowName string, taskID int64, logger *zap.SugaredLogger) (*WorkflowTaskAnalysis, error) {
+	ctx := context.Background()
+	llmIntegration, err := commonrepo.NewLLMIntegrationColl().FindDefault(ctx)
+	if err != nil {
+		return nil, e.ErrAnalyzeWorkflowTask.AddDesc("no llm integration is configured")
+	}
+	if !llmIntegration.EnableWorkflowAnalysis {
+		return nil, e.ErrAnalyzeWorkflowTask.AddDesc("workflow analysis is not enabled in the llm integration")
+	}
+
+	task, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+	if err != nil {
+		logger.Errorf("failed to find workflow task %s-%d, error: %s", workflowName, taskID, err)
+		return nil, e.ErrAnalyzeWorkflowTask.AddErr(err)
+	}
+	if task.ProjectName != projectName {
+		return nil, e.ErrAnalyzeWorkflowTask.AddDesc(fmt.Sprintf("workflow task %s-%d is not in project %s", workflowName, taskID, projectName))
+	}
+	if task.Status != config.StatusFailed && task.Status != config.StatusTimeout {
+		return nil, e.ErrAnalyzeWorkflowTask.AddDesc(fmt.Sprintf("only failed tasks can be analyzed, the task status is %s", task.Status))
+	}
+
+	rules, err := getRedactionRules(llmIntegration, task)
+	if err != nil {
+		return nil, e.ErrAnalyzeWorkflowTask.AddErr(err)
+	}
+
+	resp := &WorkflowTaskAnalysis{
+		WorkflowName: workflowName,
+		TaskID:       taskID,
+		FailedJobs:   make([]*WorkflowFailedJobInfo, 0),
+	}
+	for _, stage := range task.Stages {
+		for _, job := range stage.Jobs {
+			if job.Status != config.StatusFailed && job.Status != config.StatusTimeout {
+				continue
+			}
+			jobInfo := &WorkflowFailedJobInfo{
+				Name:      job.Name,
+				JobType:   job.JobType,
+				Status:    job.Status,
+				Error:     redact(job.Error, rules),
+				StartTime: job.StartTime,
+				EndTime:   job.EndTime,
+			}
+			// not every type of job has container logs
+			if jobLog, err := logservice.GetWorkflowV4JobContainerLogs(strings.ToLower(workflowName), job.Name, taskID, logger); err == nil {
+				jobInfo.Log = redact(splitBuildLogByRowNum(jobLog, workflowJobLogRowNum), rules)
+			}
+			resp.FailedJobs = append(resp.FailedJobs, jobInfo)
+		}
+	}
+	if len(resp.FailedJobs) == 0 {
+		return nil, e.ErrAnalyzeWorkflowTask.AddDesc("no failed job is found in the task")
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"workflow":    workflowName,
+		"task_id":     taskID,
+		"status":      task.Status,
+		"task_error":  redact(task.Error, rules),
+		"failed_jobs": resp.FailedJobs,
+	})
+	if err != nil {
+		return nil, e.ErrAnalyzeWorkflowTask.AddErr(err)
+	}
+
+	client, err := service.GetDefaultLLMClient(ctx)
+	if err != nil {
+		logger.Errorf("failed to get llm client, the error is: %+v", err)
+		return nil, e.ErrAnalyzeWorkflowTask.AddErr(err)
+	}
+	prompt := fmt.Sprintf("%s; 工作流任务数据: \"\"\"%s\"\"\"", WorkflowTaskAnalysisPrompt, util.RemoveExtraSpaces(string(data)))
+	answer, err := client.GetCompletion(ctx, prompt, llm.WithModel(openapi.GPT4o))
+	if err != nil {
+		logger.Errorf("failed to get answer from ai: %v, the error is: %+v", client.GetName(), err)
+		return nil, e.ErrAnalyzeWorkflowTask.AddErr(err)
+	}
+
+	if err := json.Unmarshal([]byte(trimJSONAnswer(answer)), resp); err != nil {
+		logger.Warnf("the answer of ai is not a valid json: %s", err)
+		resp.RawAnswer = answer
+	}
+	// the logs are only used in the prompt
+	for _, job := range resp.FailedJobs {
+		job.Log = ""
+	}
+	return resp, nil
+}
+
+func getRedactionRules(llmIntegration *commonmodels.LLMIntegration, task *commonmodels.WorkflowTask) ([]*redactionRule, error) {
+	rules := make([]*redactionRule, 0, len(builtinRedactionRules)+len(llmIntegration.RedactionRules))
+	rules = append(rules, builtinRedactionRules...)
+
+	// the values of credential params are masked as they are
+	if task.WorkflowArgs != nil {
+		for _, param := range task.WorkflowArgs.Params {
+			if param.IsCredential && param.Value != "" {
+				rules = append(rules, &redactionRule{regexp.MustCompile(regexp.QuoteMeta(param.Value)), redactedMark})
+			}
+		}
+	}
+
+	for _, rule := range llmIntegration.RedactionRules {
+		pattern, err := regexp.Compile(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction rule %s: %s", rule, err)
+		}
+		rules = append(rules, &redactionRule{pattern, redactedMark})
+	}
+	return rules, nil
+}
+
+func redact(data string, rules []*redactionRule) string {
+	for _, rule := range rules {
+		data = rule.pattern.ReplaceAllString(data, rule.replacement)
+	}
+	return data
+}
+
+// trimJSONAnswer removes the markdown code fence around the json answer
+func trimJSONAnswer(answer string) string {
+	answer = strings.TrimSpace(answer)
+	answer = strings.TrimPrefix(answer, "```json")
+	answer = strings.TrimPrefix(answer, "```")
+	answer = strings.TrimSuffix(answer, "```")
+	return strings.TrimSpace(answer)
+}
@@ -205,7 +205,7 @@ func DeleteCluster(c *gin.Context) {
 		}
 	}
 
-	ctx.Err = service.DeleteCluster(ctx.UserName, c.Param("id"), ctx.Logger)
+	ctx.Err = service.DeleteCluster(ctx.UserName, c.Param("id"), c.Query("force") == "true", ctx.Logger)
 }
 
 func GetClusterStrategyReferences(c *gin.Context) {
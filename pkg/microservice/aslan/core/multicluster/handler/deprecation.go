@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/multicluster/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Get Cluster Compatibility Matrix
+// @Description Get the apis removed in the upcoming k8s versions of each cluster
+// @Tags 	cluster
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	service.ClusterCompatibility
+// @Router /api/aslan/cluster/clusters/compatibility [get]
+func GetClusterCompatibilityMatrix(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.ClusterManagement.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.GetClusterCompatibilityMatrix(ctx.Logger)
+}
+
+// @Summary Scan Project API Deprecations
+// @Description Scan the service templates and the envs of the project for the deprecated k8s apis
+// @Tags 	cluster
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Param 	targetVersion	query		string		false	"target k8s version, the next minor version of the cluster by default"
+// @Success 200 	{object} 	service.APIDeprecationReport
+// @Router /api/aslan/cluster/clusters/deprecations [get]
+func ScanProjectAPIDeprecations(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be empty")
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+		if !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if !projectAuthInfo.IsProjectAdmin &&
+			!projectAuthInfo.Env.View &&
+			!projectAuthInfo.ProductionEnv.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.ScanProjectAPIDeprecations(projectKey, c.Query("targetVersion"), ctx.Logger)
+}
@@ -33,6 +33,9 @@ func (*Router) Inject(router *gin.RouterGroup) {
 	Cluster := router.Group("clusters")
 	{
 		Cluster.GET("", ListClusters)
+		Cluster.GET("/compatibility", GetClusterCompatibilityMatrix)
+		Cluster.GET("/deprecations", ScanProjectAPIDeprecations)
+		Cluster.GET("/health", ListClusterHealth)
 		Cluster.GET("/:id", GetCluster)
 
 		Cluster.POST("", CreateCluster)
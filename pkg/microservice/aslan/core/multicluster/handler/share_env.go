@@ -29,6 +29,14 @@ func CheckIstiod(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.CheckIstiod(c, c.Param("id"))
 }
 
+// @Summary OpenAPI Check Istiod
+// @Description OpenAPI Check Istiod
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id	path	string	true	"id"
+// @Success 200 	{boolean} 	bool
+// @Router /openapi/cluster/istio/check/{id} [get]
 func OpenAPICheckIstiod(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
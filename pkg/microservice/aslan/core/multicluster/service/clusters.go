@@ -47,6 +47,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
@@ -62,21 +63,23 @@ import (
 var namePattern = regexp.MustCompile(`^[0-9a-zA-Z-]{1,100}$`)
 
 type K8SCluster struct {
-	ID                     string                   `json:"id,omitempty"`
-	Name                   string                   `json:"name"`
-	Description            string                   `json:"description"`
-	AdvancedConfig         *AdvancedConfig          `json:"advanced_config,omitempty"`
-	Status                 setting.K8SClusterStatus `json:"status"`
-	Production             bool                     `json:"production"`
-	CreatedAt              int64                    `json:"createdAt"`
-	CreatedBy              string                   `json:"createdBy"`
-	Provider               int8                     `json:"provider"`
-	Local                  bool                     `json:"local"`
-	Cache                  types.Cache              `json:"cache"`
-	ShareStorage           types.ShareStorage       `json:"share_storage"`
-	LastConnectionTime     int64                    `json:"last_connection_time"`
-	UpdateHubagentErrorMsg string                   `json:"update_hubagent_error_msg"`
-	DindCfg                *commonmodels.DindCfg    `json:"dind_cfg"`
+	ID                     string                        `json:"id,omitempty"`
+	Name                   string                        `json:"name"`
+	Description            string                        `json:"description"`
+	AdvancedConfig         *AdvancedConfig               `json:"advanced_config,omitempty"`
+	Status                 setting.K8SClusterStatus      `json:"status"`
+	Production             bool                          `json:"production"`
+	CreatedAt              int64                         `json:"createdAt"`
+	CreatedBy              string                        `json:"createdBy"`
+	Provider               int8                          `json:"provider"`
+	Local                  bool                          `json:"local"`
+	Cache                  types.Cache                   `json:"cache"`
+	ShareStorage           types.ShareStorage            `json:"share_storage"`
+	LastConnectionTime     int64                         `json:"last_connection_time"`
+	UpdateHubagentErrorMsg string                        `json:"update_hubagent_error_msg"`
+	DindCfg                *commonmodels.DindCfg         `json:"dind_cfg"`
+	DragonflyConfig        *commonmodels.DragonflyConfig `json:"dragonfly_config"`
+	WarmPool               *commonmodels.WarmPoolConfig  `json:"warm_pool"`
 
 	// new field in 1.14, intended to enable kubeconfig for cluster management
 	Type       string `json:"type"` // either agent or kubeconfig supported
@@ -134,6 +137,13 @@ func (args *K8SCluster) Validate() error {
 		}
 	}
 
+	if err := args.DragonflyConfig.Validate(); err != nil {
+		return e.ErrInvalidParam.AddErr(err)
+	}
+	if err := args.WarmPool.Validate(); err != nil {
+		return e.ErrInvalidParam.AddErr(err)
+	}
+
 	return nil
 }
 
@@ -276,6 +286,8 @@ func ListClusters(ids []string, projectName string, logger *zap.SugaredLogger) (
 			LastConnectionTime:     c.LastConnectionTime,
 			UpdateHubagentErrorMsg: c.UpdateHubagentErrorMsg,
 			DindCfg:                c.DindCfg,
+			DragonflyConfig:        maskDragonflyConfig(c.DragonflyConfig),
+			WarmPool:               c.WarmPool,
 			KubeConfig:             c.KubeConfig,
 			Type:                   c.Type,
 			ShareStorage:           c.ShareStorage,
@@ -292,6 +304,15 @@ func ListClusters(ids []string, projectName string, logger *zap.SugaredLogger) (
 	return res, nil
 }
 
+func maskDragonflyConfig(dragonflyConfig *commonmodels.DragonflyConfig) *commonmodels.DragonflyConfig {
+	if dragonflyConfig == nil || dragonflyConfig.ManagerToken == "" {
+		return dragonflyConfig
+	}
+	masked := *dragonflyConfig
+	masked.ManagerToken = setting.MaskValue
+	return &masked
+}
+
 func GetCluster(id string, logger *zap.SugaredLogger) (*commonmodels.K8SCluster, error) {
 	s, _ := kube.NewService("")
 
@@ -417,19 +438,21 @@ func CreateCluster(args *K8SCluster, logger *zap.SugaredLogger) (*commonmodels.K
 	}
 
 	cluster := &commonmodels.K8SCluster{
-		Name:           args.Name,
-		Description:    args.Description,
-		AdvancedConfig: advancedConfig,
-		Status:         args.Status,
-		Production:     args.Production,
-		Provider:       args.Provider,
-		CreatedAt:      args.CreatedAt,
-		CreatedBy:      args.CreatedBy,
-		Cache:          args.Cache,
-		DindCfg:        args.DindCfg,
-		Type:           args.Type,
-		KubeConfig:     args.KubeConfig,
-		ShareStorage:   args.ShareStorage,
+		Name:            args.Name,
+		Description:     args.Description,
+		AdvancedConfig:  advancedConfig,
+		Status:          args.Status,
+		Production:      args.Production,
+		Provider:        args.Provider,
+		CreatedAt:       args.CreatedAt,
+		CreatedBy:       args.CreatedBy,
+		Cache:           args.Cache,
+		DindCfg:         args.DindCfg,
+		DragonflyConfig: args.DragonflyConfig,
+		WarmPool:        args.WarmPool,
+		Type:            args.Type,
+		KubeConfig:      args.KubeConfig,
+		ShareStorage:    args.ShareStorage,
 	}
 
 	return s.CreateCluster(cluster, args.ID, logger)
@@ -459,6 +482,18 @@ func UpdateCluster(id string, args *K8SCluster, logger *zap.SugaredLogger) (*com
 		return nil, fmt.Errorf("failed to new kube service: %s", err)
 	}
 
+	// the manager token is masked when listing clusters, keep the saved one if it is not changed
+	if args.DragonflyConfig != nil && args.DragonflyConfig.ManagerToken == setting.MaskValue {
+		origin, err := commonrepo.NewK8SClusterColl().Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find cluster %s: %s", id, err)
+		}
+		args.DragonflyConfig.ManagerToken = ""
+		if origin.DragonflyConfig != nil {
+			args.DragonflyConfig.ManagerToken = origin.DragonflyConfig.ManagerToken
+		}
+	}
+
 	advancedConfig := new(commonmodels.AdvancedConfig)
 	if args.AdvancedConfig != nil {
 		advancedConfig.Strategy = args.AdvancedConfig.Strategy
@@ -544,16 +579,18 @@ func UpdateCluster(id string, args *K8SCluster, logger *zap.SugaredLogger) (*com
 	}
 
 	cluster := &commonmodels.K8SCluster{
-		Name:           args.Name,
-		Description:    args.Description,
-		AdvancedConfig: advancedConfig,
-		Production:     args.Production,
-		Cache:          args.Cache,
-		DindCfg:        args.DindCfg,
-		Type:           args.Type,
-		KubeConfig:     args.KubeConfig,
-		ShareStorage:   args.ShareStorage,
-		Provider:       args.Provider,
+		Name:            args.Name,
+		Description:     args.Description,
+		AdvancedConfig:  advancedConfig,
+		Production:      args.Production,
+		Cache:           args.Cache,
+		DindCfg:         args.DindCfg,
+		DragonflyConfig: args.DragonflyConfig,
+		WarmPool:        args.WarmPool,
+		Type:            args.Type,
+		KubeConfig:      args.KubeConfig,
+		ShareStorage:    args.ShareStorage,
+		Provider:        args.Provider,
 	}
 	cluster, err = s.UpdateCluster(id, cluster, logger)
 	if err != nil {
@@ -617,6 +654,8 @@ func GetClusterStatus() map[string]float64 {
 type ClusterDeletionInfo struct {
 	Deletable bool       `json:"deletable"`
 	EnvInUse  []*EnvInfo `json:"env_in_use,omitempty"`
+	// References are the builds, testings, scannings and workflows using the cluster, they fail after it is deleted.
+	References *integration.References `json:"references,omitempty"`
 }
 
 type EnvInfo struct {
@@ -636,10 +675,17 @@ func GetClusterDeletionInfo(clusterID string, logger *zap.SugaredLogger) (*Clust
 		return nil, fmt.Errorf("failed to find cluster using cluster: %s, error: %s", clusterID, err)
 	}
 
+	references, err := integration.GetClusterReferences(clusterID)
+	if err != nil {
+		log.Errorf("failed to get references of cluster: %s, error: %s", clusterID, err)
+		return nil, err
+	}
+
 	if len(envs) == 0 {
 		return &ClusterDeletionInfo{
-			Deletable: true,
-			EnvInUse:  nil,
+			Deletable:  true,
+			EnvInUse:   nil,
+			References: references,
 		}, nil
 	}
 
@@ -659,12 +705,13 @@ func GetClusterDeletionInfo(clusterID string, logger *zap.SugaredLogger) (*Clust
 	}
 
 	return &ClusterDeletionInfo{
-		Deletable: false,
-		EnvInUse:  envList,
+		Deletable:  false,
+		EnvInUse:   envList,
+		References: references,
 	}, nil
 }
 
-func DeleteCluster(username, clusterID string, logger *zap.SugaredLogger) error {
+func DeleteCluster(username, clusterID string, force bool, logger *zap.SugaredLogger) error {
 	products, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
 		ClusterID: clusterID,
 	})
@@ -677,6 +724,14 @@ func DeleteCluster(username, clusterID string, logger *zap.SugaredLogger) error
 		return e.ErrDeleteCluster.AddDesc("请删除在该集群创建的环境后，再尝试删除该集群")
 	}
 
+	references, err := integration.GetClusterReferences(clusterID)
+	if err != nil {
+		return e.ErrDeleteCluster.AddErr(err)
+	}
+	if err := references.CheckDeletion(force); err != nil {
+		return err
+	}
+
 	s, _ := kube.NewService("")
 
 	if err = commonrepo.NewProjectClusterRelationColl().Delete(&commonrepo.ProjectClusterRelationOption{ClusterID: clusterID}); err != nil {
@@ -0,0 +1,282 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/helmclient"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/deprecation"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+// compatibilityUpgradeCount is the count of the upcoming minor versions listed in the compatibility matrix
+const compatibilityUpgradeCount = 3
+
+const (
+	DeprecationSourceServiceTemplate = "service_template"
+	DeprecationSourceEnvironment     = "environment"
+)
+
+type ClusterCompatibility struct {
+	ClusterID   string                         `json:"cluster_id"`
+	ClusterName string                         `json:"cluster_name"`
+	Version     string                         `json:"version"`
+	Error       string                         `json:"error,omitempty"`
+	Upgrades    []*ClusterUpgradeCompatibility `json:"upgrades"`
+}
+
+// ClusterUpgradeCompatibility lists the apis no longer served after upgrading the cluster to the version
+type ClusterUpgradeCompatibility struct {
+	Version     string             `json:"version"`
+	RemovedAPIs []*deprecation.API `json:"removed_apis"`
+}
+
+type APIDeprecationFinding struct {
+	*deprecation.Finding `json:",inline"`
+	Source               string `json:"source"`
+	ServiceName          string `json:"service_name"`
+	Production           bool   `json:"production"`
+	EnvName              string `json:"env_name,omitempty"`
+	ClusterID            string `json:"cluster_id,omitempty"`
+	ClusterVersion       string `json:"cluster_version,omitempty"`
+	TargetVersion        string `json:"target_version"`
+}
+
+type APIDeprecationReport struct {
+	ProjectName string                   `json:"project_name"`
+	Clusters    []*ClusterCompatibility  `json:"clusters"`
+	Findings    []*APIDeprecationFinding `json:"findings"`
+	Errors      []string                 `json:"errors"`
+	ScanTime    int64                    `json:"scan_time"`
+}
+
+func getClusterVersion(clusterID string) (*version.Version, error) {
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	return deprecation.ParseVersion(info.GitVersion)
+}
+
+func buildClusterCompatibility(cluster *commonmodels.K8SCluster) (*ClusterCompatibility, *version.Version) {
+	resp := &ClusterCompatibility{
+		ClusterID:   cluster.ID.Hex(),
+		ClusterName: cluster.Name,
+		Upgrades:    make([]*ClusterUpgradeCompatibility, 0),
+	}
+	if cluster.Status != setting.Normal {
+		resp.Error = fmt.Sprintf("cluster status is %s", cluster.Status)
+		return resp, nil
+	}
+	current, err := getClusterVersion(resp.ClusterID)
+	if err != nil {
+		resp.Error = fmt.Sprintf("failed to get cluster version: %s", err)
+		return resp, nil
+	}
+	resp.Version = current.String()
+	for _, upgrade := range deprecation.NextMinor(current, compatibilityUpgradeCount) {
+		resp.Upgrades = append(resp.Upgrades, &ClusterUpgradeCompatibility{
+			Version:     upgrade.String(),
+			RemovedAPIs: deprecation.RemovedIn(upgrade),
+		})
+	}
+	return resp, current
+}
+
+// GetClusterCompatibilityMatrix lists the apis removed in the upcoming k8s versions of each cluster
+func GetClusterCompatibilityMatrix(logger *zap.SugaredLogger) ([]*ClusterCompatibility, error) {
+	clusters, err := commonrepo.NewK8SClusterColl().List(&commonrepo.ClusterListOpts{})
+	if err != nil {
+		logger.Errorf("failed to list clusters, err: %s", err)
+		return nil, e.ErrListK8SCluster.AddErr(err)
+	}
+
+	resp := make([]*ClusterCompatibility, 0, len(clusters))
+	for _, cluster := range clusters {
+		compatibility, _ := buildClusterCompatibility(cluster)
+		resp = append(resp, compatibility)
+	}
+	return resp, nil
+}
+
+// ScanProjectAPIDeprecations scans the k8s service templates and the manifests deployed in the envs of the project
+// for the apis deprecated or removed in the target version. The cluster of each env is checked against its next minor
+// version if the target version is not specified, and the service templates are checked against the latest target.
+func ScanProjectAPIDeprecations(projectName, targetVersion string, logger *zap.SugaredLogger) (*APIDeprecationReport, error) {
+	var target *version.Version
+	if targetVersion != "" {
+		v, err := deprecation.ParseVersion(targetVersion)
+		if err != nil {
+			return nil, e.ErrInvalidParam.AddErr(err)
+		}
+		target = v
+	}
+
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{Name: projectName})
+	if err != nil {
+		logger.Errorf("failed to list envs of project %s, err: %s", projectName, err)
+		return nil, e.ErrListEnvs.AddErr(err)
+	}
+
+	report := &APIDeprecationReport{
+		ProjectName: projectName,
+		Clusters:    make([]*ClusterCompatibility, 0),
+		Findings:    make([]*APIDeprecationFinding, 0),
+		Errors:      make([]string, 0),
+		ScanTime:    time.Now().Unix(),
+	}
+
+	clusterVersions := make(map[string]*version.Version)
+	var templateTarget *version.Version
+	for _, env := range envs {
+		if _, ok := clusterVersions[env.ClusterID]; !ok {
+			cluster, err := commonrepo.NewK8SClusterColl().Get(env.ClusterID)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to find cluster %s: %s", env.ClusterID, err))
+				clusterVersions[env.ClusterID] = nil
+				continue
+			}
+			compatibility, current := buildClusterCompatibility(cluster)
+			report.Clusters = append(report.Clusters, compatibility)
+			clusterVersions[env.ClusterID] = current
+		}
+		current := clusterVersions[env.ClusterID]
+		if current == nil {
+			continue
+		}
+
+		envTarget := target
+		if envTarget == nil {
+			envTarget = deprecation.NextMinor(current, 1)[0]
+		}
+		if templateTarget == nil || templateTarget.LessThan(envTarget) {
+			templateTarget = envTarget
+		}
+
+		manifests, err := getEnvServiceManifests(env)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to get the manifests of env %s: %s", env.EnvName, err))
+		}
+		for serviceName, manifest := range manifests {
+			for _, finding := range deprecation.Scan(util.SplitManifests(manifest), envTarget) {
+				report.Findings = append(report.Findings, &APIDeprecationFinding{
+					Finding:        finding,
+					Source:         DeprecationSourceEnvironment,
+					ServiceName:    serviceName,
+					Production:     env.Production,
+					EnvName:        env.EnvName,
+					ClusterID:      env.ClusterID,
+					ClusterVersion: current.String(),
+					TargetVersion:  envTarget.String(),
+				})
+			}
+		}
+	}
+
+	if templateTarget == nil {
+		templateTarget = target
+	}
+	if templateTarget == nil {
+		templateTarget = deprecation.LatestRemoval()
+	}
+
+	testServices, err := commonrepo.NewServiceColl().ListMaxRevisionsByProduct(projectName)
+	if err != nil {
+		return nil, e.ErrListTemplate.AddErr(err)
+	}
+	productionServices, err := commonrepo.NewProductionServiceColl().ListMaxRevisionsByProduct(projectName)
+	if err != nil {
+		return nil, e.ErrListTemplate.AddErr(err)
+	}
+	scanTemplates := func(services []*commonmodels.Service, production bool) {
+		for _, svc := range services {
+			if svc.Type != setting.K8SDeployType {
+				continue
+			}
+			for _, finding := range deprecation.Scan(util.SplitManifests(svc.Yaml), templateTarget) {
+				report.Findings = append(report.Findings, &APIDeprecationFinding{
+					Finding:       finding,
+					Source:        DeprecationSourceServiceTemplate,
+					ServiceName:   svc.ServiceName,
+					Production:    production,
+					TargetVersion: templateTarget.String(),
+				})
+			}
+		}
+	}
+	scanTemplates(testServices, false)
+	scanTemplates(productionServices, true)
+
+	return report, nil
+}
+
+// getEnvServiceManifests returns the manifests currently deployed in the env, keyed by the service name
+func getEnvServiceManifests(env *commonmodels.Product) (map[string]string, error) {
+	resp := make(map[string]string)
+	if env.Source != setting.SourceFromHelm {
+		for _, svc := range env.GetServiceMap() {
+			if svc.Type != setting.K8SDeployType {
+				continue
+			}
+			manifest, _, err := kube.FetchCurrentAppliedYaml(&kube.GeneSvcYamlOption{
+				ProductName: env.ProductName,
+				EnvName:     env.EnvName,
+				ServiceName: svc.ServiceName,
+			})
+			if err != nil {
+				return resp, fmt.Errorf("failed to render service %s: %s", svc.ServiceName, err)
+			}
+			resp[svc.ServiceName] = manifest
+		}
+		return resp, nil
+	}
+
+	helmClient, err := helmclient.NewClientFromNamespace(env.ClusterID, env.Namespace)
+	if err != nil {
+		return resp, err
+	}
+	releaseNameMap, err := commonutil.GetReleaseNameToServiceNameMap(env)
+	if err != nil {
+		return resp, err
+	}
+	for releaseName, serviceName := range releaseNameMap {
+		release, err := helmClient.GetRelease(releaseName)
+		if err != nil {
+			// the release may not be installed yet
+			continue
+		}
+		resp[serviceName] = release.Manifest
+	}
+	return resp, nil
+}
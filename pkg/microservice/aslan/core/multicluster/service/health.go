@@ -0,0 +1,283 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const (
+	// clusterHealthTimeout is the max time to collect the health of a cluster
+	clusterHealthTimeout = 20 * time.Second
+	// clusterPressureRatio is the ratio of the requested resources to the allocatable ones above which the resource is
+	// considered under pressure
+	clusterPressureRatio = 0.9
+)
+
+type ResourceUsage struct {
+	// CPU is in millicores, memory is in bytes
+	AllocatableCPU    int64 `json:"allocatable_cpu"`
+	RequestedCPU      int64 `json:"requested_cpu"`
+	AllocatableMemory int64 `json:"allocatable_memory"`
+	RequestedMemory   int64 `json:"requested_memory"`
+	AllocatablePods   int64 `json:"allocatable_pods"`
+	Pods              int64 `json:"pods"`
+}
+
+func (u *ResourceUsage) add(o *ResourceUsage) {
+	u.AllocatableCPU += o.AllocatableCPU
+	u.RequestedCPU += o.RequestedCPU
+	u.AllocatableMemory += o.AllocatableMemory
+	u.RequestedMemory += o.RequestedMemory
+	u.AllocatablePods += o.AllocatablePods
+	u.Pods += o.Pods
+}
+
+type NodeHealth struct {
+	Name          string `json:"name"`
+	Ready         bool   `json:"ready"`
+	Unschedulable bool   `json:"unschedulable"`
+	// Pressures are the pressure conditions reported by the kubelet, e.g. MemoryPressure, DiskPressure
+	Pressures []string       `json:"pressures"`
+	Usage     *ResourceUsage `json:"usage"`
+}
+
+type PodPressure struct {
+	Pending       int `json:"pending"`
+	Unschedulable int `json:"unschedulable"`
+	Failed        int `json:"failed"`
+}
+
+type ClusterHealth struct {
+	ClusterID   string                   `json:"cluster_id"`
+	ClusterName string                   `json:"cluster_name"`
+	Production  bool                     `json:"production"`
+	Status      setting.K8SClusterStatus `json:"status"`
+	// Connected tells whether the api server of the cluster can be reached through the agent or the kubeconfig
+	Connected          bool           `json:"connected"`
+	LastConnectionTime int64          `json:"last_connection_time"`
+	Error              string         `json:"error,omitempty"`
+	Usage              *ResourceUsage `json:"usage"`
+	Nodes              []*NodeHealth  `json:"nodes"`
+	ReadyNodes         int            `json:"ready_nodes"`
+	PodPressure        *PodPressure   `json:"pod_pressure"`
+	// Warnings are the capacity issues which may fail the pods of the workflow tasks to be scheduled
+	Warnings []string `json:"warnings"`
+}
+
+// ListClusterHealth collects the node conditions, the allocatable and requested resources and the pending pods of
+// all the clusters.
+func ListClusterHealth(logger *zap.SugaredLogger) ([]*ClusterHealth, error) {
+	clusters, err := commonrepo.NewK8SClusterColl().List(&commonrepo.ClusterListOpts{})
+	if err != nil {
+		logger.Errorf("failed to list clusters, err: %s", err)
+		return nil, e.ErrListK8SCluster.AddErr(err)
+	}
+
+	resp := make([]*ClusterHealth, len(clusters))
+	wg := sync.WaitGroup{}
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func(i int, cluster *commonmodels.K8SCluster) {
+			defer wg.Done()
+			resp[i] = getClusterHealth(cluster)
+		}(i, cluster)
+	}
+	wg.Wait()
+	return resp, nil
+}
+
+func getClusterHealth(cluster *commonmodels.K8SCluster) *ClusterHealth {
+	resp := &ClusterHealth{
+		ClusterID:          cluster.ID.Hex(),
+		ClusterName:        cluster.Name,
+		Production:         cluster.Production,
+		Status:             cluster.Status,
+		LastConnectionTime: cluster.LastConnectionTime,
+		Usage:              &ResourceUsage{},
+		Nodes:              make([]*NodeHealth, 0),
+		PodPressure:        &PodPressure{},
+		Warnings:           make([]string, 0),
+	}
+	if cluster.Status != setting.Normal {
+		resp.Error = fmt.Sprintf("cluster status is %s", cluster.Status)
+		return resp
+	}
+
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), resp.ClusterID)
+	if err != nil {
+		resp.Error = fmt.Sprintf("failed to get the client of the cluster: %s", err)
+		return resp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterHealthTimeout)
+	defer cancel()
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		resp.Error = fmt.Sprintf("failed to list nodes: %s", err)
+		return resp
+	}
+	resp.Connected = true
+
+	// the pods of all the namespaces which are not finished occupy the resources of the nodes
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+			fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+		).String(),
+	})
+	if err != nil {
+		resp.Error = fmt.Sprintf("failed to list pods: %s", err)
+		return resp
+	}
+
+	nodeMap := make(map[string]*NodeHealth)
+	for _, node := range nodes.Items {
+		nodeHealth := buildNodeHealth(&node)
+		nodeMap[node.Name] = nodeHealth
+		resp.Nodes = append(resp.Nodes, nodeHealth)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodPending {
+			resp.PodPressure.Pending++
+			if isPodUnschedulable(&pod) {
+				resp.PodPressure.Unschedulable++
+			}
+		}
+		nodeHealth, ok := nodeMap[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		cpu, memory := podRequests(&pod)
+		nodeHealth.Usage.RequestedCPU += cpu
+		nodeHealth.Usage.RequestedMemory += memory
+		nodeHealth.Usage.Pods++
+	}
+
+	failedPods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodFailed)).String(),
+	})
+	if err == nil {
+		resp.PodPressure.Failed = len(failedPods.Items)
+	}
+
+	for _, nodeHealth := range resp.Nodes {
+		if nodeHealth.Ready {
+			resp.ReadyNodes++
+		}
+		// the resources of the nodes which can not run new pods are not available to the workflow tasks
+		if nodeHealth.Ready && !nodeHealth.Unschedulable {
+			resp.Usage.add(nodeHealth.Usage)
+		}
+		if len(nodeHealth.Pressures) > 0 {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("node %s is under %v", nodeHealth.Name, nodeHealth.Pressures))
+		}
+	}
+	resp.Warnings = append(resp.Warnings, capacityWarnings(resp)...)
+	return resp
+}
+
+func buildNodeHealth(node *corev1.Node) *NodeHealth {
+	resp := &NodeHealth{
+		Name:          node.Name,
+		Unschedulable: node.Spec.Unschedulable,
+		Pressures:     make([]string, 0),
+		Usage: &ResourceUsage{
+			AllocatableCPU:    node.Status.Allocatable.Cpu().MilliValue(),
+			AllocatableMemory: node.Status.Allocatable.Memory().Value(),
+			AllocatablePods:   node.Status.Allocatable.Pods().Value(),
+		},
+	}
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case corev1.NodeReady:
+			resp.Ready = condition.Status == corev1.ConditionTrue
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+			if condition.Status == corev1.ConditionTrue {
+				resp.Pressures = append(resp.Pressures, string(condition.Type))
+			}
+		}
+	}
+	return resp
+}
+
+// podRequests returns the cpu in millicores and the memory in bytes requested by the pod, init containers run one by
+// one so the max of their requests counts.
+func podRequests(pod *corev1.Pod) (int64, int64) {
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	for _, container := range pod.Spec.Containers {
+		cpu.Add(*container.Resources.Requests.Cpu())
+		memory.Add(*container.Resources.Requests.Memory())
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.Resources.Requests.Cpu().Cmp(cpu) > 0 {
+			cpu = container.Resources.Requests.Cpu().DeepCopy()
+		}
+		if container.Resources.Requests.Memory().Cmp(memory) > 0 {
+			memory = container.Resources.Requests.Memory().DeepCopy()
+		}
+	}
+	return cpu.MilliValue(), memory.Value()
+}
+
+func isPodUnschedulable(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+func capacityWarnings(health *ClusterHealth) []string {
+	resp := make([]string, 0)
+	if health.ReadyNodes < len(health.Nodes) {
+		resp = append(resp, fmt.Sprintf("%d of %d nodes are not ready", len(health.Nodes)-health.ReadyNodes, len(health.Nodes)))
+	}
+	usage := health.Usage
+	check := func(name string, requested, allocatable int64) {
+		if allocatable == 0 {
+			return
+		}
+		if ratio := float64(requested) / float64(allocatable); ratio >= clusterPressureRatio {
+			resp = append(resp, fmt.Sprintf("%.0f%% of the allocatable %s is requested", ratio*100, name))
+		}
+	}
+	check("cpu", usage.RequestedCPU, usage.AllocatableCPU)
+	check("memory", usage.RequestedMemory, usage.AllocatableMemory)
+	check("pods", usage.Pods, usage.AllocatablePods)
+	if health.PodPressure.Unschedulable > 0 {
+		resp = append(resp, fmt.Sprintf("%d pods can not be scheduled", health.PodPressure.Unschedulable))
+	}
+	return resp
+}
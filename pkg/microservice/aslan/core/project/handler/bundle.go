@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	projectservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/project/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Export project bundle
+// @Description Export the services, builds, testings, scannings and workflows of the project into a gzip compressed archive
+// @Tags 	project
+// @Accept 	json
+// @Produce octet-stream
+// @Param 	name	path		string		true	"project name"
+// @Success 200
+// @Router /api/aslan/project/products/{name}/bundle [get]
+func ExportProjectBundle(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	projectKey := c.Param("name")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be null!")
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			internalhandler.JSONResponse(c, ctx)
+			return
+		}
+	}
+
+	data, err := projectservice.ExportProjectBundle(projectKey, ctx.UserName, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "导出", "项目管理-项目模板", projectKey, "", ctx.Logger)
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-bundle.json.gz"`, projectKey))
+	c.Data(http.StatusOK, "application/octet-stream", data)
+}
+
+// @Summary Import project bundle
+// @Description Create a new project from the archive exported by the export project bundle api
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		projectservice.ImportProjectBundleArgs 	true 	"body"
+// @Success 200 	{object} 	projectservice.ImportProjectBundleResp
+// @Router /api/aslan/project/products/bundle/import [post]
+func ImportProjectBundle(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(projectservice.ImportProjectBundleArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, args.ProjectKey, "导入", "项目管理-项目模板", args.ProjectKey, "", ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.Project.Create {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = projectservice.ImportProjectBundle(ctx.UserID, ctx.UserName, args, ctx.Logger)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	projectservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/project/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Get env validation hook
+// @Description Get the hook which validates the default values and global variables before envs are updated
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string								true	"project name"
+// @Success 200 	{object} 	templatemodels.EnvValidationHook
+// @Router /api/aslan/project/products/{name}/envValidationHook [get]
+func GetEnvValidationHook(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("name")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be null!")
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = projectservice.GetEnvValidationHook(projectKey, ctx.Logger)
+}
+
+// @Summary Update env validation hook
+// @Description Update the hook which validates the default values and global variables before envs are updated
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string								true	"project name"
+// @Param 	body 	body 		templatemodels.EnvValidationHook 	true 	"body"
+// @Success 200
+// @Router /api/aslan/project/products/{name}/envValidationHook [put]
+func UpdateEnvValidationHook(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("name")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be null!")
+		return
+	}
+
+	args := new(templatemodels.EnvValidationHook)
+	data, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if err = json.Unmarshal(data, args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(data))
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "更新", "项目管理-环境变量校验", projectKey, "", ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = projectservice.UpdateEnvValidationHook(projectKey, args, ctx.Logger)
+}
@@ -178,7 +178,7 @@ func DeleteCodeHost(c *gin.Context) {
 		ctx.Err = err
 		return
 	}
-	ctx.Err = service.DeleteProjectCodeHost(projectKey, id, ctx.Logger)
+	ctx.Err = service.DeleteProjectCodeHost(projectKey, id, c.Query("force") == "true", ctx.Logger)
 }
 
 // @Summary Update Project CodeHost
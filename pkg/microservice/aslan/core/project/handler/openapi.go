@@ -30,6 +30,14 @@ import (
 	"github.com/koderover/zadig/v2/pkg/tool/log"
 )
 
+// @Summary OpenAPI Create Product Template
+// @Description OpenAPI Create Product Template
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	service.OpenAPICreateProductReq	true	"body"
+// @Success 200
+// @Router /openapi/projects/project [post]
 func OpenAPICreateProductTemplate(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -116,6 +124,14 @@ func OpenAPIInitializeYamlProject(c *gin.Context) {
 	ctx.Err = service.InitializeYAMLProject(ctx.UserID, ctx.UserName, ctx.RequestID, args, ctx.Logger)
 }
 
+// @Summary OpenAPI Initialize Helm Project
+// @Description OpenAPI Initialize Helm Project
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	service.OpenAPIInitializeProjectReq	true	"body"
+// @Success 200
+// @Router /openapi/projects/project/init/helm [post]
 func OpenAPIInitializeHelmProject(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -154,6 +170,15 @@ func OpenAPIInitializeHelmProject(c *gin.Context) {
 	ctx.Err = service.OpenAPIInitializeHelmProject(ctx.UserID, ctx.UserName, ctx.RequestID, args, ctx.Logger)
 }
 
+// @Summary OpenAPI List Project
+// @Description OpenAPI List Project
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	pageSize	query	int	false	"page size"
+// @Param 	pageNum	query	int	false	"page num"
+// @Success 200 	{object} 	projectResp
+// @Router /openapi/projects/project [get]
 func OpenAPIListProject(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -194,6 +219,14 @@ func OpenAPIListProject(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.ListProjectOpenAPI(authorizedProjectList, args.PageSize, args.PageNum, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Project Detail
+// @Description OpenAPI Get Project Detail
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	service.OpenAPIProjectDetailResp
+// @Router /openapi/projects/project/detail [get]
 func OpenAPIGetProjectDetail(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -237,6 +270,15 @@ func OpenAPIGetProjectDetail(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.GetProjectDetailOpenAPI(projectKey, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Project
+// @Description OpenAPI Delete Project
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	isDelete	query	string	false	"is delete"
+// @Success 200
+// @Router /openapi/projects/project [delete]
 func OpenAPIDeleteProject(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -270,6 +312,14 @@ func OpenAPIDeleteProject(c *gin.Context) {
 	ctx.Err = service.DeleteProjectOpenAPI(ctx.UserName, ctx.RequestID, projectKey, isDelete, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Global Variables
+// @Description OpenAPI Get Global Variables
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	types.GlobalVariables
+// @Router /openapi/projects/project/globalVariable [get]
 func OpenAPIGetGlobalVariables(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
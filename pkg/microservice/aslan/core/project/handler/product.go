@@ -107,6 +107,8 @@ func CreateProductTemplate(c *gin.Context) {
 		return
 	}
 
+	// sandbox projects can only be created by the sandbox api
+	args.Sandbox = nil
 	args.UpdateBy = ctx.UserName
 	ctx.Err = projectservice.CreateProductTemplate(args, ctx.Logger)
 }
@@ -34,6 +34,8 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		product.GET("/:name/searching-rules", GetCustomMatchRules)
 		product.PUT("/:name/searching-rules", CreateOrUpdateMatchRules)
 		product.POST("", CreateProductTemplate)
+		product.POST("/bundle/import", ImportProjectBundle)
+		product.GET("/:name/bundle", ExportProjectBundle)
 		product.PUT("/:name", UpdateProductTemplate)
 		product.PUT("/:name/:status", UpdateProductTmplStatus)
 		product.PATCH("/:name", UpdateServiceOrchestration)
@@ -48,6 +50,24 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		product.GET("/:name/productionGlobalVariables", GetProductionGlobalVariables)
 		product.PUT("/:name/productionGlobalVariables", UpdateProductionGlobalVariables)
 		product.GET("/:name/productionGlobalVariableCandidates", GetProductionGlobalVariableCandidates)
+
+		product.GET("/:name/owners/stale", ListStaleOwnerResources)
+		product.PUT("/:name/owners", UpdateResourceOwner)
+
+		product.GET("/:name/serviceaccounts", ListServiceAccounts)
+		product.POST("/:name/serviceaccounts", CreateServiceAccount)
+		product.PUT("/:name/serviceaccounts/:saName", UpdateServiceAccount)
+		product.DELETE("/:name/serviceaccounts/:saName", DeleteServiceAccount)
+
+		product.GET("/:name/envValidationHook", GetEnvValidationHook)
+		product.PUT("/:name/envValidationHook", UpdateEnvValidationHook)
+	}
+
+	sandbox := router.Group("sandbox")
+	{
+		sandbox.POST("", CreateSandboxProject)
+		sandbox.GET("", ListSandboxProjects)
+		sandbox.GET("/cron/clean", CleanExpiredSandboxProjects)
 	}
 
 	group := router.Group("group")
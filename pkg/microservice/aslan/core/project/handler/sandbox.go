@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	projectservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/project/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Create Sandbox Project
+// @Description Create a limited project with quotas on envs and task minutes which expires automatically, any logged in user can create it
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	body 			body 		projectservice.CreateSandboxProjectReq 	true 	"body"
+// @Success 200 			{object} 	projectservice.SandboxProject
+// @Router /api/aslan/project/sandbox [post]
+func CreateSandboxProject(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(projectservice.CreateSandboxProjectReq)
+	data, err := c.GetRawData()
+	if err != nil {
+		ctx.Logger.Errorf("CreateSandboxProject c.GetRawData() err : %v", err)
+	}
+	if err = json.Unmarshal(data, args); err != nil {
+		ctx.Logger.Errorf("CreateSandboxProject json.Unmarshal err : %v", err)
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, args.ProjectKey, "新增", "项目管理-沙箱项目", args.ProjectKey, string(data), ctx.Logger)
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(data))
+
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if err := args.Validate(); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = projectservice.CreateSandboxProject(ctx.UserID, ctx.UserName, args, ctx.Logger)
+}
+
+// @Summary List Sandbox Projects
+// @Description List the sandbox projects owned by current user, system admins can list all of them with all=true
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	all			query		bool					false	"list sandbox projects of all users"
+// @Success 200 		{array} 	projectservice.SandboxProject
+// @Router /api/aslan/project/sandbox [get]
+func ListSandboxProjects(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	owner := ctx.UserID
+	if c.Query("all") == "true" {
+		if !ctx.Resources.IsSystemAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+		owner = ""
+	}
+
+	ctx.Resp, ctx.Err = projectservice.ListSandboxProjects(owner, ctx.Logger)
+}
+
+func CleanExpiredSandboxProjects(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Err = projectservice.CleanExpiredSandboxProjects(ctx.RequestID, ctx.Logger)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	projectservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/project/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List service accounts
+// @Description List the service accounts that the automated tasks of the project can run as
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string								true	"project name"
+// @Success 200 	{array} 	models.ProjectServiceAccount
+// @Router /api/aslan/project/products/{name}/serviceaccounts [get]
+func ListServiceAccounts(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("name")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be null!")
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = projectservice.ListServiceAccounts(projectKey, ctx.Logger)
+}
+
+// @Summary Create service account
+// @Description Create a service account of the project
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string								true	"project name"
+// @Param 	body 	body 		projectservice.ServiceAccountArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/project/products/{name}/serviceaccounts [post]
+func CreateServiceAccount(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("name")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be null!")
+		return
+	}
+
+	args := new(projectservice.ServiceAccountArgs)
+	data, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if err = json.Unmarshal(data, args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(data))
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "新增", "项目管理-服务账号", args.Name, string(data), ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = projectservice.CreateServiceAccount(projectKey, ctx.UserName, args, ctx.Logger)
+}
+
+// @Summary Update service account
+// @Description Update the description, status and permission of a service account
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string								true	"project name"
+// @Param 	saName	path		string								true	"service account name"
+// @Param 	body 	body 		projectservice.ServiceAccountArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/project/products/{name}/serviceaccounts/{saName} [put]
+func UpdateServiceAccount(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("name")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be null!")
+		return
+	}
+	saName := c.Param("saName")
+
+	args := new(projectservice.ServiceAccountArgs)
+	data, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if err = json.Unmarshal(data, args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(data))
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "更新", "项目管理-服务账号", saName, string(data), ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = projectservice.UpdateServiceAccount(projectKey, saName, ctx.UserName, args, ctx.Logger)
+}
+
+// @Summary Delete service account
+// @Description Delete a service account which is not used by any workflow
+// @Tags 	project
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string								true	"project name"
+// @Param 	saName	path		string								true	"service account name"
+// @Success 200
+// @Router /api/aslan/project/products/{name}/serviceaccounts/{saName} [delete]
+func DeleteServiceAccount(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("name")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be null!")
+		return
+	}
+	saName := c.Param("saName")
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "删除", "项目管理-服务账号", saName, "", ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = projectservice.DeleteServiceAccount(projectKey, saName, ctx.Logger)
+}
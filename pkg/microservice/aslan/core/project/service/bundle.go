@@ -0,0 +1,365 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	svcService "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/service/service"
+	workflowservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	testingservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/testing/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// projectBundleVersion is increased whenever the bundle format is changed incompatibly
+const projectBundleVersion = 1
+
+// ProjectBundle is the archive of a project which is used to bootstrap new projects. Only the definitions are
+// exported, envs, tasks and the triggers of the builds, testings and scannings are not included.
+type ProjectBundle struct {
+	Version    int                        `json:"version"`
+	ExportBy   string                     `json:"export_by"`
+	ExportTime int64                      `json:"export_time"`
+	Project    *template.Product          `json:"project"`
+	Services   []*commonmodels.Service    `json:"services"`
+	Builds     []*commonmodels.Build      `json:"builds"`
+	Testings   []*commonmodels.Testing    `json:"testings"`
+	Scannings  []*commonmodels.Scanning   `json:"scannings"`
+	Workflows  []*commonmodels.WorkflowV4 `json:"workflows"`
+}
+
+type ImportProjectBundleArgs struct {
+	ProjectKey  string `json:"project_key"`
+	ProjectName string `json:"project_name"`
+	// WorkflowNameMapping maps the workflow names in the bundle to the names in the new project since workflow names
+	// are unique across projects. Workflows not in the mapping are named by replacing the source project key in their
+	// names with the new project key, or by appending the new project key if the source key is not part of the name.
+	WorkflowNameMapping map[string]string `json:"workflow_name_mapping"`
+	// Bundle is the archive exported by ExportProjectBundle, base64 encoded in json
+	Bundle []byte `json:"bundle"`
+}
+
+type ImportProjectBundleResp struct {
+	ProjectKey string `json:"project_key"`
+	Services   int    `json:"services"`
+	Builds     int    `json:"builds"`
+	Testings   int    `json:"testings"`
+	Scannings  int    `json:"scannings"`
+	Workflows  int    `json:"workflows"`
+	// Warnings lists the definitions which are not imported and the reasons
+	Warnings []string `json:"warnings"`
+}
+
+// ExportProjectBundle exports the service definitions, builds, testings, scannings and workflows of a project into a
+// gzip compressed json archive.
+func ExportProjectBundle(projectName, username string, log *zap.SugaredLogger) ([]byte, error) {
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return nil, e.ErrExportProjectBundle.AddDesc(fmt.Sprintf("failed to find project %s: %s", projectName, err))
+	}
+	if project.IsHelmProduct() {
+		return nil, e.ErrExportProjectBundle.AddDesc("helm projects are not supported since the charts are not stored in the database")
+	}
+
+	bundle := &ProjectBundle{
+		Version:    projectBundleVersion,
+		ExportBy:   username,
+		ExportTime: time.Now().Unix(),
+		Project:    project,
+	}
+	if bundle.Services, err = commonrepo.NewServiceColl().ListMaxRevisionsByProduct(projectName); err != nil {
+		log.Errorf("failed to list services of project %s, error: %s", projectName, err)
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+	if bundle.Builds, err = commonrepo.NewBuildColl().List(&commonrepo.BuildListOption{ProductName: projectName}); err != nil {
+		log.Errorf("failed to list builds of project %s, error: %s", projectName, err)
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+	if bundle.Testings, err = commonrepo.NewTestingColl().List(&commonrepo.ListTestOption{ProductName: projectName}); err != nil {
+		log.Errorf("failed to list testings of project %s, error: %s", projectName, err)
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+	if bundle.Scannings, _, err = commonrepo.NewScanningColl().List(&commonrepo.ScanningListOption{ProjectName: projectName}, 0, 0); err != nil {
+		log.Errorf("failed to list scannings of project %s, error: %s", projectName, err)
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+	if bundle.Workflows, _, err = commonrepo.NewWorkflowV4Coll().List(&commonrepo.ListWorkflowV4Option{ProjectName: projectName}, 0, 0); err != nil {
+		log.Errorf("failed to list workflows of project %s, error: %s", projectName, err)
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, e.ErrExportProjectBundle.AddErr(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportProjectBundle creates a new project from an archive exported by ExportProjectBundle. Every reference to the
+// source project key in the definitions is changed to the new project key. The project is created first and the
+// definitions which fail to be imported are reported as warnings instead of failing the whole import.
+func ImportProjectBundle(userID, username string, args *ImportProjectBundleArgs, log *zap.SugaredLogger) (*ImportProjectBundleResp, error) {
+	if args.ProjectKey == "" {
+		return nil, e.ErrInvalidParam.AddDesc("project key is required")
+	}
+	bundle, err := loadProjectBundle(args.Bundle)
+	if err != nil {
+		return nil, e.ErrImportProjectBundle.AddErr(err)
+	}
+	if _, err := templaterepo.NewProductColl().Find(args.ProjectKey); err == nil {
+		return nil, e.ErrImportProjectBundle.AddDesc(fmt.Sprintf("project %s already exists", args.ProjectKey))
+	}
+
+	sourceKey := bundle.Project.ProductName
+	if err := remapProjectReferences(bundle, sourceKey, args.ProjectKey); err != nil {
+		return nil, e.ErrImportProjectBundle.AddErr(err)
+	}
+
+	project := bundle.Project
+	project.ProductName = args.ProjectKey
+	if args.ProjectName != "" {
+		project.ProjectName = args.ProjectName
+	}
+	// the service orchestration is rebuilt when the services are created
+	project.Services = nil
+	project.ProductionServices = nil
+	project.CreateTime = time.Now().Unix()
+	project.UpdateBy = username
+	project.Admins = []string{userID}
+	project.Sandbox = nil
+	project.EnvValidationHook = nil
+	if err := CreateProductTemplate(project, log); err != nil {
+		return nil, err
+	}
+
+	resp := &ImportProjectBundleResp{
+		ProjectKey: args.ProjectKey,
+		Warnings:   make([]string, 0),
+	}
+	warn := func(kind, name string, err error) {
+		log.Warnf("failed to import %s %s into project %s, error: %s", kind, name, args.ProjectKey, err)
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf("%s %s: %s", kind, name, err))
+	}
+
+	for _, svc := range bundle.Services {
+		svc.Revision = 0
+		svc.CreateBy = username
+		svc.EnvStatuses = nil
+		svc.Owner = nil
+		svc.DeployWindow = nil
+		svc.Lockout = nil
+		if svc.Source == setting.SourceFromGerrit {
+			warn("service", svc.ServiceName, fmt.Errorf("services synchronized from gerrit are not supported"))
+			continue
+		}
+		if _, err := svcService.CreateServiceTemplate(username, svc, false, false, log); err != nil {
+			warn("service", svc.ServiceName, err)
+			continue
+		}
+		resp.Services++
+	}
+
+	for _, build := range bundle.Builds {
+		build.ID = primitive.NilObjectID
+		if err := commonservice.CreateBuild(username, build, log); err != nil {
+			warn("build", build.Name, err)
+			continue
+		}
+		resp.Builds++
+	}
+
+	for _, testing := range bundle.Testings {
+		testing.ID = primitive.NilObjectID
+		testing.HookCtl = &commonmodels.TestingHookCtrl{}
+		testing.Schedules = nil
+		if err := testingservice.CreateTesting(username, testing, log); err != nil {
+			warn("testing", testing.Name, err)
+			continue
+		}
+		resp.Testings++
+	}
+
+	for _, scanning := range bundle.Scannings {
+		scanning.ID = primitive.NilObjectID
+		scanning.UpdatedBy = username
+		if scanning.AdvancedSetting != nil {
+			scanning.AdvancedSetting.HookCtl = &commonmodels.ScanningHookCtl{}
+		}
+		if err := commonrepo.NewScanningColl().Create(scanning); err != nil {
+			warn("scanning", scanning.Name, err)
+			continue
+		}
+		resp.Scannings++
+	}
+
+	bundledWorkflows := make(map[string]bool)
+	for _, workflow := range bundle.Workflows {
+		bundledWorkflows[workflow.Name] = true
+	}
+	for _, workflow := range bundle.Workflows {
+		sourceName := workflow.Name
+		workflow.ID = primitive.NilObjectID
+		workflow.Name = bundleWorkflowName(sourceName, sourceKey, args.ProjectKey, args.WorkflowNameMapping)
+		// service accounts and triggers belong to the source project, the triggers would otherwise
+		// start the imported workflows on every event of the source project's repos
+		workflow.RunAs = ""
+		workflow.HookCtls = nil
+		workflow.JiraHookCtls = nil
+		workflow.MeegoHookCtls = nil
+		workflow.GeneralHookCtls = nil
+		workflow.RegistryHookCtls = nil
+		if err := remapWorkflowTriggerJobs(workflow, bundledWorkflows, sourceKey, args); err != nil {
+			warn("workflow", sourceName, err)
+			continue
+		}
+		if err := workflowservice.CreateWorkflowV4(username, workflow, log); err != nil {
+			warn("workflow", sourceName, err)
+			continue
+		}
+		resp.Workflows++
+	}
+
+	return resp, nil
+}
+
+func loadProjectBundle(archive []byte) (*ProjectBundle, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project bundle: %s", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project bundle: %s", err)
+	}
+	bundle := &ProjectBundle{}
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("invalid project bundle: %s", err)
+	}
+	if bundle.Version != projectBundleVersion {
+		return nil, fmt.Errorf("unsupported project bundle version %d", bundle.Version)
+	}
+	if bundle.Project == nil || bundle.Project.ProductName == "" {
+		return nil, fmt.Errorf("invalid project bundle: project is missing")
+	}
+	return bundle, nil
+}
+
+// remapProjectReferences replaces every string value in the bundle which equals the source project key with the target
+// key. Only whole values are replaced so that names which merely contain the project key are kept unchanged.
+func remapProjectReferences(bundle *ProjectBundle, sourceKey, targetKey string) error {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	data, err = json.Marshal(replaceStringValue(raw, sourceKey, targetKey))
+	if err != nil {
+		return err
+	}
+
+	remapped := &ProjectBundle{}
+	if err := json.Unmarshal(data, remapped); err != nil {
+		return err
+	}
+	*bundle = *remapped
+	return nil
+}
+
+func replaceStringValue(obj interface{}, from, to string) interface{} {
+	switch v := obj.(type) {
+	case string:
+		if v == from {
+			return to
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = replaceStringValue(v[i], from, to)
+		}
+		return v
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = replaceStringValue(v[key], from, to)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// remapWorkflowTriggerJobs renames the workflows triggered by the workflow trigger jobs in the same way as the imported workflows
+func remapWorkflowTriggerJobs(workflow *commonmodels.WorkflowV4, bundledWorkflows map[string]bool, sourceKey string, args *ImportProjectBundleArgs) error {
+	for _, stage := range workflow.Stages {
+		for _, job := range stage.Jobs {
+			if job.JobType != config.JobWorkflowTrigger {
+				continue
+			}
+			spec := &commonmodels.WorkflowTriggerJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return fmt.Errorf("failed to decode spec of job %s: %s", job.Name, err)
+			}
+			for _, infos := range [][]*commonmodels.ServiceTriggerWorkflowInfo{spec.FixedWorkflowList, spec.ServiceTriggerWorkflow} {
+				for _, info := range infos {
+					if info.ProjectName == args.ProjectKey && bundledWorkflows[info.WorkflowName] {
+						info.WorkflowName = bundleWorkflowName(info.WorkflowName, sourceKey, args.ProjectKey, args.WorkflowNameMapping)
+					}
+				}
+			}
+			job.Spec = spec
+		}
+	}
+	return nil
+}
+
+func bundleWorkflowName(name, sourceKey, targetKey string, mapping map[string]string) string {
+	if mapped, ok := mapping[name]; ok && mapped != "" {
+		return mapped
+	}
+	if strings.Contains(name, sourceKey) {
+		return strings.ReplaceAll(name, sourceKey, targetKey)
+	}
+	return fmt.Sprintf("%s-%s", name, targetKey)
+}
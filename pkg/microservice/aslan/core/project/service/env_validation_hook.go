@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"net/url"
+
+	"go.uber.org/zap"
+
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func GetEnvValidationHook(projectName string, log *zap.SugaredLogger) (*templatemodels.EnvValidationHook, error) {
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		log.Errorf("failed to find project %s, error: %s", projectName, err)
+		return nil, e.ErrGetProduct.AddErr(err)
+	}
+	if project.EnvValidationHook == nil {
+		return &templatemodels.EnvValidationHook{}, nil
+	}
+	return project.EnvValidationHook, nil
+}
+
+func UpdateEnvValidationHook(projectName string, hook *templatemodels.EnvValidationHook, log *zap.SugaredLogger) error {
+	if hook.Enable {
+		u, err := url.Parse(hook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid hook url: %s", hook.URL))
+		}
+	}
+	if hook.TimeoutSeconds < 0 || hook.TimeoutSeconds > 60 {
+		return e.ErrInvalidParam.AddDesc("timeout should be between 0 and 60 seconds")
+	}
+
+	if _, err := templaterepo.NewProductColl().Find(projectName); err != nil {
+		return e.ErrUpdateProduct.AddErr(err)
+	}
+	if err := templaterepo.NewProductColl().UpdateEnvValidationHook(projectName, hook); err != nil {
+		log.Errorf("failed to update env validation hook of project %s, error: %s", projectName, err)
+		return e.ErrUpdateProduct.AddErr(err)
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	"github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/repository/mongodb"
 	systemconfig_codehost_service "github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/service"
@@ -70,7 +71,15 @@ func GetProjectCodehostList(projectName, encryptedKey, address, owner, source st
 	return systemconfig_codehost_service.EncypteCodeHost(encryptedKey, codeHosts, log)
 }
 
-func DeleteProjectCodeHost(projectName string, id int, _ *zap.SugaredLogger) error {
+func DeleteProjectCodeHost(projectName string, id int, force bool, log *zap.SugaredLogger) error {
+	references, err := integration.GetCodehostReferences(id)
+	if err != nil {
+		log.Errorf("failed to get references of codehost %d, error: %s", id, err)
+		return err
+	}
+	if err := references.CheckDeletion(force); err != nil {
+		return err
+	}
 	return mongodb.NewCodehostColl().DeleteProjectCodeHostByID(projectName, id)
 }
 
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/notify"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+type UpdateResourceOwnerArgs struct {
+	ResourceType config.OwnedResourceType    `json:"resource_type"`
+	ResourceName string                      `json:"resource_name"`
+	Production   bool                        `json:"production"`
+	Owner        *commonmodels.ResourceOwner `json:"owner"`
+}
+
+func ListStaleOwnerResources(projectName string, log *zap.SugaredLogger) ([]*commonmodels.StaleOwnerResource, error) {
+	resp, err := commonrepo.NewStaleOwnerResourceColl().List(projectName)
+	if err != nil {
+		log.Errorf("failed to list stale owner resources of project %s, err: %s", projectName, err)
+		return nil, e.ErrListStaleOwners.AddErr(err)
+	}
+	return resp, nil
+}
+
+func UpdateResourceOwner(projectName string, args *UpdateResourceOwnerArgs, log *zap.SugaredLogger) error {
+	if args.Owner == nil || args.Owner.ID == "" {
+		return e.ErrInvalidOwner.AddDesc("owner is required")
+	}
+	owner, err := commonservice.EnsureResourceOwner(args.Owner, "")
+	if err != nil {
+		return err
+	}
+
+	switch args.ResourceType {
+	case config.OwnedResourceService:
+		if args.Production {
+			err = commonrepo.NewProductionServiceColl().UpdateOwner(projectName, args.ResourceName, owner)
+		} else {
+			err = commonrepo.NewServiceColl().UpdateOwner(projectName, args.ResourceName, owner)
+		}
+	case config.OwnedResourceWorkflow:
+		workflow, findErr := commonrepo.NewWorkflowV4Coll().Find(args.ResourceName)
+		if findErr != nil || workflow.Project != projectName {
+			return e.ErrUpdateResourceOwner.AddDesc(fmt.Sprintf("workflow %s not found in project %s", args.ResourceName, projectName))
+		}
+		err = commonrepo.NewWorkflowV4Coll().UpdateOwner(args.ResourceName, owner)
+	case config.OwnedResourceEnv:
+		err = commonrepo.NewProductColl().UpdateOwner(args.ResourceName, projectName, owner)
+	default:
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("unsupported resource type %s", args.ResourceType))
+	}
+	if err != nil {
+		log.Errorf("failed to update owner of %s %s in project %s, err: %s", args.ResourceType, args.ResourceName, projectName, err)
+		return e.ErrUpdateResourceOwner.AddErr(err)
+	}
+
+	if err := commonrepo.NewStaleOwnerResourceColl().Delete(projectName, args.ResourceType, args.ResourceName, args.Production); err != nil {
+		log.Warnf("failed to delete stale owner record of %s %s in project %s, err: %s", args.ResourceType, args.ResourceName, projectName, err)
+	}
+	return nil
+}
+
+// RunStaleOwnerCheck flags the resources whose owner user has been deleted or whose owner group no longer exists,
+// and asks the project admins to reassign them.
+func RunStaleOwnerCheck() {
+	logger := log.SugaredLogger()
+
+	projects, err := templaterepo.NewProductColl().List()
+	if err != nil {
+		logger.Errorf("stale owner check: failed to list projects, err: %s", err)
+		return
+	}
+
+	groups, err := user.New().ListUserGroups(1, 10000)
+	if err != nil {
+		logger.Errorf("stale owner check: failed to list user groups, err: %s", err)
+		return
+	}
+	existingGroups := sets.NewString()
+	for _, group := range groups.GroupList {
+		existingGroups.Insert(group.ID)
+	}
+
+	for _, project := range projects {
+		if err := checkProjectStaleOwners(project.ProductName, existingGroups, logger); err != nil {
+			logger.Errorf("stale owner check: failed to check project %s, err: %s", project.ProductName, err)
+		}
+	}
+}
+
+func checkProjectStaleOwners(projectName string, existingGroups sets.String, logger *zap.SugaredLogger) error {
+	owned, err := listOwnedResources(projectName)
+	if err != nil {
+		return err
+	}
+
+	userIDs := sets.NewString()
+	for _, resource := range owned {
+		if resource.Owner.Type != config.OwnerTypeGroup {
+			userIDs.Insert(resource.Owner.ID)
+		}
+	}
+	existingUsers := sets.NewString()
+	if userIDs.Len() > 0 {
+		users, err := user.New().SearchUsersByIDList(userIDs.List())
+		if err != nil {
+			return fmt.Errorf("failed to search users, err: %s", err)
+		}
+		for _, u := range users.Users {
+			existingUsers.Insert(u.Uid)
+		}
+	}
+
+	records, err := commonrepo.NewStaleOwnerResourceColl().List(projectName)
+	if err != nil {
+		return err
+	}
+	recorded := make(map[string]*commonmodels.StaleOwnerResource)
+	for _, record := range records {
+		recorded[staleOwnerResourceKey(record)] = record
+	}
+
+	newlyStale := make([]*commonmodels.StaleOwnerResource, 0)
+	for _, resource := range owned {
+		stale := false
+		if resource.Owner.Type == config.OwnerTypeGroup {
+			stale = !existingGroups.Has(resource.Owner.ID)
+		} else {
+			stale = !existingUsers.Has(resource.Owner.ID)
+		}
+
+		key := staleOwnerResourceKey(resource)
+		if _, ok := recorded[key]; ok {
+			delete(recorded, key)
+			// the record is kept until the owner is reassigned or comes back
+			if stale {
+				continue
+			}
+			if err := commonrepo.NewStaleOwnerResourceColl().Delete(projectName, resource.ResourceType, resource.ResourceName, resource.Production); err != nil {
+				logger.Warnf("failed to delete stale owner record of %s %s, err: %s", resource.ResourceType, resource.ResourceName, err)
+			}
+			continue
+		}
+		if !stale {
+			continue
+		}
+
+		resource.DetectTime = time.Now().Unix()
+		if err := commonrepo.NewStaleOwnerResourceColl().Create(resource); err != nil {
+			logger.Errorf("failed to create stale owner record of %s %s, err: %s", resource.ResourceType, resource.ResourceName, err)
+			continue
+		}
+		newlyStale = append(newlyStale, resource)
+	}
+
+	// the rest of the records point to the resources which are deleted or have no owner any more
+	for _, record := range recorded {
+		if err := commonrepo.NewStaleOwnerResourceColl().Delete(projectName, record.ResourceType, record.ResourceName, record.Production); err != nil {
+			logger.Warnf("failed to delete stale owner record of %s %s, err: %s", record.ResourceType, record.ResourceName, err)
+		}
+	}
+
+	if len(newlyStale) > 0 {
+		notifyProjectAdminsOfStaleOwners(projectName, newlyStale, logger)
+	}
+	return nil
+}
+
+func staleOwnerResourceKey(resource *commonmodels.StaleOwnerResource) string {
+	return fmt.Sprintf("%s/%s/%t", resource.ResourceType, resource.ResourceName, resource.Production)
+}
+
+func listOwnedResources(projectName string) ([]*commonmodels.StaleOwnerResource, error) {
+	resp := make([]*commonmodels.StaleOwnerResource, 0)
+	appendOwned := func(resourceType config.OwnedResourceType, name string, production bool, owner *commonmodels.ResourceOwner) {
+		if owner == nil || owner.ID == "" {
+			return
+		}
+		resp = append(resp, &commonmodels.StaleOwnerResource{
+			ProjectName:  projectName,
+			ResourceType: resourceType,
+			ResourceName: name,
+			Production:   production,
+			Owner:        owner,
+		})
+	}
+
+	services, err := commonrepo.NewServiceColl().ListMaxRevisionsByProduct(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services, err: %s", err)
+	}
+	for _, svc := range services {
+		appendOwned(config.OwnedResourceService, svc.ServiceName, false, svc.Owner)
+	}
+
+	productionServices, err := commonrepo.NewProductionServiceColl().ListMaxRevisionsByProduct(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list production services, err: %s", err)
+	}
+	for _, svc := range productionServices {
+		appendOwned(config.OwnedResourceService, svc.ServiceName, true, svc.Owner)
+	}
+
+	workflows, _, err := commonrepo.NewWorkflowV4Coll().List(&commonrepo.ListWorkflowV4Option{ProjectName: projectName}, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows, err: %s", err)
+	}
+	for _, workflow := range workflows {
+		appendOwned(config.OwnedResourceWorkflow, workflow.Name, false, workflow.Owner)
+	}
+
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{Name: projectName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list envs, err: %s", err)
+	}
+	for _, env := range envs {
+		appendOwned(config.OwnedResourceEnv, env.EnvName, env.Production, env.Owner)
+	}
+
+	return resp, nil
+}
+
+func notifyProjectAdminsOfStaleOwners(projectName string, resources []*commonmodels.StaleOwnerResource, logger *zap.SugaredLogger) {
+	bindings, err := user.New().ListRoleBindings(projectName)
+	if err != nil {
+		logger.Errorf("failed to list role bindings of project %s, err: %s", projectName, err)
+		return
+	}
+
+	receivers := sets.NewString()
+	for _, binding := range bindings {
+		if !sets.NewString(binding.Roles...).Has(string(setting.ProjectAdmin)) {
+			continue
+		}
+		switch {
+		case binding.UserInfo != nil:
+			receivers.Insert(binding.UserInfo.UID)
+		case binding.GroupInfo != nil:
+			group, err := user.New().GetGroupDetailedInfo(binding.GroupInfo.GID)
+			if err != nil {
+				logger.Warnf("failed to get user group %s, err: %s", binding.GroupInfo.GID, err)
+				continue
+			}
+			receivers.Insert(group.UIDs...)
+		}
+	}
+
+	title := fmt.Sprintf("项目 %s 中有资源的负责人已失效", projectName)
+	content := "以下资源的负责人已离开, 请重新指定负责人:\n"
+	for _, resource := range resources {
+		content += fmt.Sprintf("%s: %s, 原负责人: %s\n", resource.ResourceType, resource.ResourceName, resource.Owner.Name)
+	}
+	for _, receiver := range receivers.List() {
+		notify.SendMessage(receiver, title, content, "", logger)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const (
+	sandboxLifetime       = 30 * 24 * time.Hour
+	sandboxMaxEnvs        = 2
+	sandboxMaxTaskMinutes = 600
+	sandboxMaxPerUser     = 2
+)
+
+type SandboxProject struct {
+	ProjectName string                  `json:"project_name"`
+	ProjectKey  string                  `json:"project_key"`
+	Description string                  `json:"description"`
+	CreateTime  int64                   `json:"create_time"`
+	Sandbox     *template.SandboxPolicy `json:"sandbox"`
+}
+
+// CreateSandboxProject creates a limited project owned by the given user without admin involvement.
+// Sandbox projects are private, only deploy to the local cluster and expire after sandboxLifetime.
+func CreateSandboxProject(userID, username string, args *CreateSandboxProjectReq, logger *zap.SugaredLogger) (*SandboxProject, error) {
+	owned, err := templaterepo.NewProductColl().ListSandboxes(userID)
+	if err != nil {
+		logger.Errorf("failed to list sandbox projects of user %s, error: %s", username, err)
+		return nil, e.ErrCreateSandboxProject.AddErr(err)
+	}
+	if len(owned) >= sandboxMaxPerUser {
+		return nil, e.ErrSandboxQuotaExceeded.AddDesc(fmt.Sprintf("each user can own at most %d sandbox projects", sandboxMaxPerUser))
+	}
+
+	feature := &template.ProductFeature{
+		BasicFacility: "kubernetes",
+		CreateEnvType: "system",
+		DeployType:    "k8s",
+	}
+	if args.ProjectType == config.ProjectTypeHelm {
+		feature.DeployType = "helm"
+	}
+
+	now := time.Now()
+	createArgs := &template.Product{
+		ProjectName:    args.ProjectName,
+		ProductName:    args.ProjectKey,
+		CreateTime:     now.Unix(),
+		UpdateBy:       username,
+		Enabled:        true,
+		Description:    args.Description,
+		ClusterIDs:     []string{setting.LocalClusterID},
+		ProductFeature: feature,
+		Public:         false,
+		Admins:         []string{userID},
+		Sandbox: &template.SandboxPolicy{
+			Owner:          userID,
+			OwnerName:      username,
+			ExpireTime:     now.Add(sandboxLifetime).Unix(),
+			MaxEnvs:        sandboxMaxEnvs,
+			MaxTaskMinutes: sandboxMaxTaskMinutes,
+		},
+	}
+
+	if err := CreateProductTemplate(createArgs, logger); err != nil {
+		return nil, err
+	}
+
+	return toSandboxProject(createArgs), nil
+}
+
+// ListSandboxProjects lists the sandbox projects owned by the given user, all of them if userID is empty
+func ListSandboxProjects(userID string, logger *zap.SugaredLogger) ([]*SandboxProject, error) {
+	projects, err := templaterepo.NewProductColl().ListSandboxes(userID)
+	if err != nil {
+		logger.Errorf("failed to list sandbox projects, error: %s", err)
+		return nil, err
+	}
+
+	resp := make([]*SandboxProject, 0, len(projects))
+	for _, project := range projects {
+		resp = append(resp, toSandboxProject(project))
+	}
+	return resp, nil
+}
+
+// CleanExpiredSandboxProjects deletes every sandbox project which has passed its expire time,
+// including all the envs, workflows and services in it.
+func CleanExpiredSandboxProjects(requestID string, logger *zap.SugaredLogger) error {
+	projects, err := templaterepo.NewProductColl().ListSandboxes("")
+	if err != nil {
+		logger.Errorf("failed to list sandbox projects, error: %s", err)
+		return e.ErrCleanSandboxProjects.AddErr(err)
+	}
+
+	for _, project := range projects {
+		if !project.Sandbox.IsExpired() {
+			continue
+		}
+
+		logger.Infof("deleting expired sandbox project %s owned by %s", project.ProductName, project.Sandbox.OwnerName)
+		if err := DeleteProductTemplate(setting.SystemUser, project.ProductName, requestID, true, logger); err != nil {
+			logger.Errorf("failed to delete expired sandbox project %s, error: %s", project.ProductName, err)
+		}
+	}
+	return nil
+}
+
+func toSandboxProject(project *template.Product) *SandboxProject {
+	return &SandboxProject{
+		ProjectName: project.ProjectName,
+		ProjectKey:  project.ProductName,
+		Description: project.Description,
+		CreateTime:  project.CreateTime,
+		Sandbox:     project.Sandbox,
+	}
+}
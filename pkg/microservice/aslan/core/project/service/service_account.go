@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+var serviceAccountNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,30}[a-z0-9])?$`)
+
+type ServiceAccountArgs struct {
+	Name        string                                 `json:"name"`
+	Description string                                 `json:"description"`
+	Disabled    bool                                   `json:"disabled"`
+	Permission  *commonmodels.ServiceAccountPermission `json:"permission"`
+}
+
+func ListServiceAccounts(projectName string, log *zap.SugaredLogger) ([]*commonmodels.ProjectServiceAccount, error) {
+	resp, err := commonrepo.NewProjectServiceAccountColl().List(projectName)
+	if err != nil {
+		log.Errorf("failed to list service accounts of project %s, error: %s", projectName, err)
+		return nil, e.ErrListServiceAccount.AddErr(err)
+	}
+	return resp, nil
+}
+
+func CreateServiceAccount(projectName, username string, args *ServiceAccountArgs, log *zap.SugaredLogger) error {
+	if !serviceAccountNameRegex.MatchString(args.Name) {
+		return e.ErrCreateServiceAccount.AddDesc("name should consist of lower case letters, digits and '-', and be no longer than 32 characters")
+	}
+	if _, err := commonrepo.NewProjectServiceAccountColl().Find(projectName, args.Name); err == nil {
+		return e.ErrCreateServiceAccount.AddDesc(fmt.Sprintf("service account %s already exists", args.Name))
+	}
+
+	err := commonrepo.NewProjectServiceAccountColl().Create(&commonmodels.ProjectServiceAccount{
+		ProjectName: projectName,
+		Name:        args.Name,
+		Description: args.Description,
+		Disabled:    args.Disabled,
+		Permission:  normalizeServiceAccountPermission(args.Permission),
+		CreatedBy:   username,
+		UpdatedBy:   username,
+	})
+	if err != nil {
+		log.Errorf("failed to create service account %s of project %s, error: %s", args.Name, projectName, err)
+		return e.ErrCreateServiceAccount.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateServiceAccount(projectName, name, username string, args *ServiceAccountArgs, log *zap.SugaredLogger) error {
+	err := commonrepo.NewProjectServiceAccountColl().Update(&commonmodels.ProjectServiceAccount{
+		ProjectName: projectName,
+		Name:        name,
+		Description: args.Description,
+		Disabled:    args.Disabled,
+		Permission:  normalizeServiceAccountPermission(args.Permission),
+		UpdatedBy:   username,
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return e.ErrUpdateServiceAccount.AddDesc(fmt.Sprintf("service account %s not found", name))
+		}
+		log.Errorf("failed to update service account %s of project %s, error: %s", name, projectName, err)
+		return e.ErrUpdateServiceAccount.AddErr(err)
+	}
+	return nil
+}
+
+// DeleteServiceAccount deletes the service account if no workflow runs as it.
+func DeleteServiceAccount(projectName, name string, log *zap.SugaredLogger) error {
+	workflows, _, err := commonrepo.NewWorkflowV4Coll().List(&commonrepo.ListWorkflowV4Option{ProjectName: projectName}, 0, 0)
+	if err != nil {
+		log.Errorf("failed to list workflows of project %s, error: %s", projectName, err)
+		return e.ErrDeleteServiceAccount.AddErr(err)
+	}
+	for _, workflow := range workflows {
+		if workflow.RunAs == name {
+			return e.ErrDeleteServiceAccount.AddDesc(fmt.Sprintf("service account %s is used by workflow %s", name, workflow.DisplayName))
+		}
+	}
+
+	if err := commonrepo.NewProjectServiceAccountColl().Delete(projectName, name); err != nil {
+		log.Errorf("failed to delete service account %s of project %s, error: %s", name, projectName, err)
+		return e.ErrDeleteServiceAccount.AddErr(err)
+	}
+	return nil
+}
+
+func normalizeServiceAccountPermission(permission *commonmodels.ServiceAccountPermission) *commonmodels.ServiceAccountPermission {
+	if permission == nil {
+		permission = &commonmodels.ServiceAccountPermission{}
+	}
+	if permission.Workflows == nil {
+		permission.Workflows = make([]string, 0)
+	}
+	if permission.Envs == nil {
+		permission.Envs = make([]string, 0)
+	}
+	if permission.ProductionEnvs == nil {
+		permission.ProductionEnvs = make([]string, 0)
+	}
+	return permission
+}
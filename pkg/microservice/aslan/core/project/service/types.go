@@ -53,6 +53,33 @@ func (req OpenAPICreateProductReq) Validate() error {
 	return nil
 }
 
+type CreateSandboxProjectReq struct {
+	ProjectName string             `json:"project_name"`
+	ProjectKey  string             `json:"project_key"`
+	Description string             `json:"description"`
+	ProjectType config.ProjectType `json:"project_type"`
+}
+
+func (req CreateSandboxProjectReq) Validate() error {
+	if req.ProjectName == "" {
+		return errors.New("project_name cannot be empty")
+	}
+
+	match, err := regexp.MatchString(setting.ProjectKeyRegEx, req.ProjectKey)
+	if err != nil || !match {
+		return errors.New(`project key should match regex: ^[a-z-\\d]+$`)
+	}
+
+	switch req.ProjectType {
+	case config.ProjectTypeYaml, config.ProjectTypeHelm:
+		break
+	default:
+		return errors.New("sandbox project only supports yaml and helm project type")
+	}
+
+	return nil
+}
+
 type OpenAPIInitializeProjectReq struct {
 	ProjectName string               `json:"project_name"`
 	ProjectKey  string               `json:"project_key"`
@@ -32,6 +32,15 @@ type OpenAPIListReleasePlanOption struct {
 	PageSize int64 `form:"pageSize" binding:"required"`
 }
 
+// @Summary OpenAPI List Release Plans
+// @Description OpenAPI List Release Plans
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	pageNum	query	int	true	"page num"
+// @Param 	pageSize	query	int	true	"page size"
+// @Success 200 	{object} 	service.OpenAPIListReleasePlanResp
+// @Router /openapi/release_plan/v1 [get]
 func OpenAPIListReleasePlans(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -63,6 +72,14 @@ func OpenAPIListReleasePlans(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIListReleasePlans(opt.PageNum, opt.PageSize)
 }
 
+// @Summary OpenAPI Get Release Plan
+// @Description OpenAPI Get Release Plan
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id	path	string	true	"id"
+// @Success 200 	{object} 	models.ReleasePlan
+// @Router /openapi/release_plan/v1/{id} [get]
 func OpenAPIGetReleasePlan(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -88,6 +105,14 @@ func OpenAPIGetReleasePlan(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.OpenAPIGetReleasePlan(c.Param("id"))
 }
 
+// @Summary OpenAPI Create Release Plan
+// @Description OpenAPI Create Release Plan
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	service.OpenAPICreateReleasePlanArgs	true	"body"
+// @Success 200
+// @Router /openapi/release_plan/v1 [post]
 func OpenAPICreateReleasePlan(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -53,6 +53,62 @@ func GetReleasePlan(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.GetReleasePlan(c.Param("id"))
 }
 
+// @Summary Get Release Plan Timeline
+// @Description Get the estimated timeline of the release plan computed from the dependencies and planned time windows of its jobs
+// @Tags 	releasePlan
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string							true	"release plan id"
+// @Success 200 	{object} 	service.ReleasePlanTimeline
+// @Router /api/aslan/release_plan/v1/{id}/timeline [get]
+func GetReleasePlanTimeline(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin && !ctx.Resources.SystemActions.ReleasePlan.View {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	err = commonutil.CheckZadigEnterpriseLicense()
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetReleasePlanTimeline(c.Param("id"))
+}
+
+func DraftReleasePlanReleaseNotes(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin && !ctx.Resources.SystemActions.ReleasePlan.View {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	err = commonutil.CheckZadigEnterpriseLicense()
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.DraftReleasePlanReleaseNotes(c.Param("id"), ctx.Logger)
+}
+
 func GetReleasePlanLogs(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -27,6 +27,8 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		v1.POST("", CreateReleasePlan)
 		v1.GET("/:id", GetReleasePlan)
 		v1.GET("/:id/logs", GetReleasePlanLogs)
+		v1.GET("/:id/timeline", GetReleasePlanTimeline)
+		v1.POST("/:id/release_notes", DraftReleasePlanReleaseNotes)
 		v1.PUT("/:id", UpdateReleasePlan)
 		v1.DELETE("/:id", DeleteReleasePlan)
 
@@ -384,14 +384,17 @@ func createNativeApproval(plan *models.ReleasePlan, url string) error {
 		}
 	}()
 
-	originApprovalUser := approval.ApproveUsers
+	originApprovalUser, originQuorumRules, originSteps := approval.ApproveUsers, approval.QuorumRules, approval.Steps
 	approval.ApproveUsers = approvalUsers
+	approval.QuorumRules = util.GeneQuorumRules(approval.QuorumRules)
+	approval.Steps = util.GeneNativeApprovalSteps(approval.Steps)
 
 	approveKey := uuid.New().String()
 	approval.InstanceCode = approveKey
 
+	approvalservice.ApplyApprovalDelegations(approval)
 	approvalservice.GlobalApproveMap.SetApproval(approveKey, approval)
-	approval.ApproveUsers = originApprovalUser
+	approval.ApproveUsers, approval.QuorumRules, approval.Steps = originApprovalUser, originQuorumRules, originSteps
 	return nil
 }
 
@@ -0,0 +1,319 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	configbase "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/jira"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/tool/servicenow"
+)
+
+// change tickets are checked at most once in this interval by the watcher
+const changeTicketCheckInterval = 30
+
+var releasePlanJobStatusText = map[config.ReleasePlanJobStatus]string{
+	config.ReleasePlanJobStatusTodo:    "未执行",
+	config.ReleasePlanJobStatusDone:    "完成",
+	config.ReleasePlanJobStatusSkipped: "跳过",
+	config.ReleasePlanJobStatusFailed:  "失败",
+	config.ReleasePlanJobStatusRunning: "执行中",
+}
+
+func lintChangeTicket(spec *models.ChangeTicketReleaseJobSpec) error {
+	if spec.ProjectManagementID == "" {
+		return errors.New("project management integration is required")
+	}
+	switch spec.Source {
+	case config.ChangeTicketSourceServiceNow:
+	case config.ChangeTicketSourceJira:
+		if spec.JiraProject == "" || spec.JiraIssueType == "" {
+			return errors.New("jira project and issue type are required")
+		}
+		if len(spec.ApprovedStatus) == 0 {
+			return errors.New("approved status of the jira issue is required")
+		}
+	default:
+		return errors.Errorf("invalid change ticket source %s", spec.Source)
+	}
+	return nil
+}
+
+// openChangeTickets creates or updates the tickets of the change ticket jobs when the plan starts executing, the job
+// fails if the ticket can't be opened, and the manager can retry it by executing the job.
+func openChangeTickets(plan *models.ReleasePlan) {
+	for _, job := range plan.Jobs {
+		if job.Type != config.JobChangeTicket || job.Status != config.ReleasePlanJobStatusTodo {
+			continue
+		}
+		if err := openChangeTicket(plan, job); err != nil {
+			log.Errorf("failed to open change ticket of release job %s in plan %s, error: %s", job.Name, plan.Name, err)
+			job.Status = config.ReleasePlanJobStatusFailed
+		}
+	}
+}
+
+func openChangeTicket(plan *models.ReleasePlan, job *models.ReleaseJob) error {
+	spec := new(models.ChangeTicketReleaseJobSpec)
+	if err := models.IToi(job.Spec, spec); err != nil {
+		return errors.Wrap(err, "invalid spec")
+	}
+	// the spec is always written back so that the error is recorded
+	defer func() { job.Spec = spec }()
+
+	summary := spec.Summary
+	if summary == "" {
+		summary = fmt.Sprintf("发布计划 %s", plan.Name)
+	}
+	description := changeTicketDescription(plan, spec.Description)
+
+	var err error
+	switch spec.Source {
+	case config.ChangeTicketSourceServiceNow:
+		err = openServiceNowChangeRequest(spec, summary, description)
+	case config.ChangeTicketSourceJira:
+		err = openJiraIssue(spec, summary, description)
+	default:
+		err = errors.Errorf("invalid change ticket source %s", spec.Source)
+	}
+	if err != nil {
+		spec.Error = err.Error()
+		return err
+	}
+
+	spec.Error = ""
+	spec.LastCheckTime = 0
+	job.Status = config.ReleasePlanJobStatusRunning
+	return nil
+}
+
+func changeTicketDescription(plan *models.ReleasePlan, description string) string {
+	content := fmt.Sprintf("发布计划名称: %s\n发布负责人: %s\n", plan.Name, plan.Manager)
+	if plan.StartTime != 0 && plan.EndTime != 0 {
+		content += fmt.Sprintf("发布窗口期: %s\n", time.Unix(plan.StartTime, 0).Format("2006-01-02 15:04:05")+"-"+time.Unix(plan.EndTime, 0).Format("2006-01-02 15:04:05"))
+	}
+	if description != "" {
+		content += fmt.Sprintf("%s\n", description)
+	}
+	content += "发布内容:\n"
+	for _, job := range plan.Jobs {
+		if job.Type == config.JobChangeTicket {
+			continue
+		}
+		content += fmt.Sprintf("	%s\n", job.Name)
+	}
+	content += fmt.Sprintf("\n更多详见: %s", releasePlanDetailURL(plan))
+	return content
+}
+
+func releasePlanDetailURL(plan *models.ReleasePlan) string {
+	return fmt.Sprintf("%s/v1/releasePlan/detail?id=%s", configbase.SystemAddress(), url.QueryEscape(plan.ID.Hex()))
+}
+
+func openServiceNowChangeRequest(spec *models.ChangeTicketReleaseJobSpec, summary, description string) error {
+	info, err := mongodb.NewProjectManagementColl().GetServiceNowByID(spec.ProjectManagementID)
+	if err != nil {
+		return errors.Wrap(err, "get servicenow integration")
+	}
+	client := servicenow.NewClient(info.ServiceNowHost, info.ServiceNowUser, info.ServiceNowPassword)
+
+	change := &servicenow.ChangeRequest{
+		ShortDescription: summary,
+		Description:      description,
+		Type:             spec.ChangeType,
+		AssignmentGroup:  spec.AssignmentGroup,
+	}
+	if spec.TicketID != "" {
+		change, err = client.UpdateChangeRequest(spec.TicketID, change)
+	} else {
+		change, err = client.CreateChangeRequest(change)
+	}
+	if err != nil {
+		return errors.Wrap(err, "open servicenow change request")
+	}
+
+	spec.TicketID = change.SysID
+	spec.TicketNumber = change.Number
+	spec.TicketURL = fmt.Sprintf("%s/nav_to.do?uri=change_request.do?sys_id=%s", strings.TrimSuffix(info.ServiceNowHost, "/"), change.SysID)
+	spec.TicketStatus = change.Approval
+	return nil
+}
+
+func openJiraIssue(spec *models.ChangeTicketReleaseJobSpec, summary, description string) error {
+	info, err := mongodb.NewProjectManagementColl().GetJiraByID(spec.ProjectManagementID)
+	if err != nil {
+		return errors.Wrap(err, "get jira integration")
+	}
+	client := jira.NewJiraClientWithAuthType(info.JiraHost, info.JiraUser, info.JiraToken, info.JiraPersonalAccessToken, info.JiraAuthType)
+
+	if spec.TicketNumber != "" {
+		if err := client.Issue.UpdateSummary(spec.TicketNumber, summary, description); err != nil {
+			return errors.Wrap(err, "update jira issue")
+		}
+		return nil
+	}
+	issue, err := client.Issue.Create(&jira.CreateIssueArgs{
+		ProjectKey:  spec.JiraProject,
+		IssueType:   spec.JiraIssueType,
+		Summary:     summary,
+		Description: description,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create jira issue")
+	}
+
+	spec.TicketID = issue.ID
+	spec.TicketNumber = issue.Key
+	spec.TicketURL = fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(info.JiraHost, "/"), issue.Key)
+	return nil
+}
+
+// refreshChangeTicket checks the status of the ticket of the running change ticket job, the job is done when the
+// ticket is approved and failed when the ticket is rejected.
+func refreshChangeTicket(job *models.ReleaseJob, log *zap.SugaredLogger) {
+	spec := new(models.ChangeTicketReleaseJobSpec)
+	if err := models.IToi(job.Spec, spec); err != nil {
+		log.Errorf("convert spec error: %v", err)
+		return
+	}
+	if time.Now().Unix()-spec.LastCheckTime < changeTicketCheckInterval {
+		return
+	}
+	spec.LastCheckTime = time.Now().Unix()
+	job.Spec = spec
+
+	var approved, rejected bool
+	switch spec.Source {
+	case config.ChangeTicketSourceServiceNow:
+		info, err := mongodb.NewProjectManagementColl().GetServiceNowByID(spec.ProjectManagementID)
+		if err != nil {
+			log.Errorf("get servicenow integration %s error: %v", spec.ProjectManagementID, err)
+			return
+		}
+		change, err := servicenow.NewClient(info.ServiceNowHost, info.ServiceNowUser, info.ServiceNowPassword).GetChangeRequest(spec.TicketID)
+		if err != nil {
+			log.Errorf("get servicenow change request %s error: %v", spec.TicketNumber, err)
+			return
+		}
+		spec.TicketStatus = change.Approval
+		approved, rejected = change.IsApproved(), change.IsRejected()
+	case config.ChangeTicketSourceJira:
+		info, err := mongodb.NewProjectManagementColl().GetJiraByID(spec.ProjectManagementID)
+		if err != nil {
+			log.Errorf("get jira integration %s error: %v", spec.ProjectManagementID, err)
+			return
+		}
+		issue, err := jira.NewJiraClientWithAuthType(info.JiraHost, info.JiraUser, info.JiraToken, info.JiraPersonalAccessToken, info.JiraAuthType).
+			Issue.GetByKeyOrID(spec.TicketNumber, "status")
+		if err != nil {
+			log.Errorf("get jira issue %s error: %v", spec.TicketNumber, err)
+			return
+		}
+		if issue.Fields == nil || issue.Fields.Status == nil {
+			log.Errorf("status of jira issue %s not found", spec.TicketNumber)
+			return
+		}
+		spec.TicketStatus = issue.Fields.Status.Name
+		approved = lo.Contains(spec.ApprovedStatus, spec.TicketStatus)
+		rejected = lo.Contains(spec.RejectedStatus, spec.TicketStatus)
+	}
+
+	switch {
+	case approved:
+		job.Status = config.ReleasePlanJobStatusDone
+	case rejected:
+		spec.Error = fmt.Sprintf("ticket %s is rejected", spec.TicketNumber)
+		job.Status = config.ReleasePlanJobStatusFailed
+	}
+}
+
+// checkChangeTicketsApproved returns error if the job is not a change ticket job and any change ticket of the plan
+// is not approved yet
+func checkChangeTicketsApproved(plan *models.ReleasePlan, jobID string) error {
+	for _, job := range plan.Jobs {
+		if job.ID == jobID && job.Type == config.JobChangeTicket {
+			return nil
+		}
+	}
+	for _, job := range plan.Jobs {
+		if job.Type != config.JobChangeTicket {
+			continue
+		}
+		if job.Status != config.ReleasePlanJobStatusDone && job.Status != config.ReleasePlanJobStatusSkipped {
+			return errors.Errorf("change ticket %s is not approved", job.Name)
+		}
+	}
+	return nil
+}
+
+// postChangeTicketResults posts the execution results of the plan back to its change tickets when the plan finishes
+func postChangeTicketResults(plan *models.ReleasePlan) {
+	content := fmt.Sprintf("发布计划 %s 执行结束, 状态: %s\n", plan.Name, plan.Status)
+	for _, job := range plan.Jobs {
+		if job.Type == config.JobChangeTicket {
+			continue
+		}
+		content += fmt.Sprintf("	%s: %s", job.Name, releasePlanJobStatusText[job.Status])
+		if job.ExecutedBy != "" {
+			content += fmt.Sprintf(" (%s)", job.ExecutedBy)
+		}
+		content += "\n"
+	}
+	content += fmt.Sprintf("\n更多详见: %s", releasePlanDetailURL(plan))
+
+	for _, job := range plan.Jobs {
+		if job.Type != config.JobChangeTicket {
+			continue
+		}
+		spec := new(models.ChangeTicketReleaseJobSpec)
+		if err := models.IToi(job.Spec, spec); err != nil || spec.TicketID == "" {
+			continue
+		}
+
+		var err error
+		switch spec.Source {
+		case config.ChangeTicketSourceServiceNow:
+			var info *models.ProjectManagement
+			info, err = mongodb.NewProjectManagementColl().GetServiceNowByID(spec.ProjectManagementID)
+			if err == nil {
+				err = servicenow.NewClient(info.ServiceNowHost, info.ServiceNowUser, info.ServiceNowPassword).AddWorkNote(spec.TicketID, content)
+			}
+		case config.ChangeTicketSourceJira:
+			var info *models.ProjectManagement
+			info, err = mongodb.NewProjectManagementColl().GetJiraByID(spec.ProjectManagementID)
+			if err == nil {
+				err = jira.NewJiraClientWithAuthType(info.JiraHost, info.JiraUser, info.JiraToken, info.JiraPersonalAccessToken, info.JiraAuthType).
+					Issue.AddCommentV2(spec.TicketNumber, content)
+			}
+		}
+		if err != nil {
+			log.Errorf("failed to post results of plan %s to change ticket %s, error: %s", plan.Name, spec.TicketNumber, err)
+		}
+	}
+}
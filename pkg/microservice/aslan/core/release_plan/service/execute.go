@@ -48,6 +48,8 @@ func NewReleaseJobExecutor(c *ExecuteReleaseJobContext, args *ExecuteReleaseJobA
 		return NewTextReleaseJobExecutor(c, args)
 	case config.JobWorkflow:
 		return NewWorkflowReleaseJobExecutor(c, args)
+	case config.JobChangeTicket:
+		return NewChangeTicketReleaseJobExecutor(c, args)
 	default:
 		return nil, errors.Errorf("invalid release job type: %s", args.Type)
 	}
@@ -182,3 +184,35 @@ func (e *WorkflowReleaseJobExecutor) Execute(plan *models.ReleasePlan) error {
 	}
 	return errors.Errorf("job %s not found", e.ID)
 }
+
+// ChangeTicketReleaseJobExecutor reopens the ticket of the change ticket job, it's used to retry after the ticket
+// failed to be opened or was rejected
+type ChangeTicketReleaseJobExecutor struct {
+	ID  string
+	Ctx *ExecuteReleaseJobContext
+}
+
+func NewChangeTicketReleaseJobExecutor(c *ExecuteReleaseJobContext, args *ExecuteReleaseJobArgs) (ReleaseJobExecutor, error) {
+	return &ChangeTicketReleaseJobExecutor{
+		ID:  args.ID,
+		Ctx: c,
+	}, nil
+}
+
+func (e *ChangeTicketReleaseJobExecutor) Execute(plan *models.ReleasePlan) error {
+	for _, job := range plan.Jobs {
+		if job.ID != e.ID {
+			continue
+		}
+		if job.Status != config.ReleasePlanJobStatusTodo && job.Status != config.ReleasePlanJobStatusFailed {
+			return errors.Errorf("job %s status %s can't execute", job.Name, job.Status)
+		}
+		if err := openChangeTicket(plan, job); err != nil {
+			return errors.Wrapf(err, "failed to open change ticket")
+		}
+		job.ExecutedBy = e.Ctx.UserName
+		job.ExecutedTime = time.Now().Unix()
+		return nil
+	}
+	return errors.Errorf("job %s not found", e.ID)
+}
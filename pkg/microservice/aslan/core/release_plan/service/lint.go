@@ -42,6 +42,12 @@ func lintReleaseJob(_type config.ReleasePlanJobType, spec interface{}) error {
 			return fmt.Errorf("invalid workflow spec: %v", err)
 		}
 		return lintWorkflow(w.Workflow)
+	case config.JobChangeTicket:
+		t := new(models.ChangeTicketReleaseJobSpec)
+		if err := models.IToi(spec, t); err != nil {
+			return fmt.Errorf("invalid change ticket spec: %v", err)
+		}
+		return lintChangeTicket(t)
 	default:
 		return fmt.Errorf("invalid release job type: %s", _type)
 	}
@@ -94,6 +100,9 @@ func lintApproval(approval *models.Approval) error {
 		if len(allApproveUsers) < approval.NativeApproval.NeededApprovers {
 			return errors.New("all approve users should not less than needed approvers")
 		}
+		if err := util.LintNativeApprovalQuorum(approval.NativeApproval); err != nil {
+			return err
+		}
 	case config.LarkApproval:
 		if approval.LarkApproval == nil {
 			return errors.New("approval not found")
@@ -29,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
@@ -38,6 +39,7 @@ import (
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
 	approvalservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/approval"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow/job"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/shared/client/user"
@@ -290,6 +292,50 @@ func GetReleasePlanLogs(id string) ([]*models.ReleasePlanLog, error) {
 	})
 }
 
+// DraftReleasePlanReleaseNotes drafts the release notes from the executed workflow tasks and the text jobs of the release plan.
+func DraftReleasePlanReleaseNotes(id string, logger *zap.SugaredLogger) (*commonservice.ReleaseNotesDraft, error) {
+	releasePlan, err := mongodb.NewReleasePlanColl().GetByID(context.Background(), id)
+	if err != nil {
+		return nil, errors.Wrap(err, "get release plan")
+	}
+
+	source := &commonservice.ReleaseNotesSource{
+		Title:       releasePlan.Name,
+		Description: releasePlan.Description,
+		Tasks:       make([]*commonmodels.WorkflowTask, 0),
+		Notes:       make([]string, 0),
+	}
+	for _, releasePlanJob := range releasePlan.Jobs {
+		switch releasePlanJob.Type {
+		case config.JobText:
+			spec := new(models.TextReleaseJobSpec)
+			if err := models.IToi(releasePlanJob.Spec, spec); err != nil {
+				return nil, fmt.Errorf("invalid spec for job: %s. decode error: %s", releasePlanJob.Name, err)
+			}
+			if spec.Content != "" {
+				source.Notes = append(source.Notes, spec.Content)
+			}
+		case config.JobWorkflow:
+			spec := new(models.WorkflowReleaseJobSpec)
+			if err := models.IToi(releasePlanJob.Spec, spec); err != nil {
+				return nil, fmt.Errorf("invalid spec for job: %s. decode error: %s", releasePlanJob.Name, err)
+			}
+			// only the executed workflows are taken into account
+			if spec.Workflow == nil || spec.TaskID == 0 {
+				continue
+			}
+			task, err := commonrepo.NewworkflowTaskv4Coll().Find(spec.Workflow.Name, spec.TaskID)
+			if err != nil {
+				logger.Errorf("failed to find workflow task %s-%d, error: %s", spec.Workflow.Name, spec.TaskID, err)
+				return nil, e.ErrDraftReleaseNotes.AddErr(err)
+			}
+			source.Tasks = append(source.Tasks, task)
+		}
+	}
+
+	return commonservice.DraftReleaseNotes(source, logger)
+}
+
 func DeleteReleasePlan(c *gin.Context, username, id string) error {
 	info, err := mongodb.NewReleasePlanColl().GetByID(context.Background(), id)
 	if err != nil {
@@ -392,6 +438,13 @@ func ExecuteReleaseJob(c *handler.Context, planID string, args *ExecuteReleaseJo
 		return errors.Errorf("only manager can execute")
 	}
 
+	if err := checkReleaseJobDependencies(plan, args.ID); err != nil {
+		return err
+	}
+	if err := checkChangeTicketsApproved(plan, args.ID); err != nil {
+		return err
+	}
+
 	executor, err := NewReleaseJobExecutor(&ExecuteReleaseJobContext{
 		AuthResources: c.Resources,
 		UserID:        c.UserID,
@@ -417,6 +470,9 @@ func ExecuteReleaseJob(c *handler.Context, planID string, args *ExecuteReleaseJo
 	if err = mongodb.NewReleasePlanColl().UpdateByID(ctx, planID, plan); err != nil {
 		return errors.Wrap(err, "update plan")
 	}
+	if plan.Status == config.StatusSuccess {
+		go postChangeTicketResults(plan)
+	}
 
 	go func() {
 		if err := mongodb.NewReleasePlanLogColl().Create(&models.ReleasePlanLog{
@@ -476,6 +532,10 @@ func ScheduleExecuteReleasePlan(c *handler.Context, planID string) error {
 			if job.Status == config.ReleasePlanJobStatusDone || job.Status == config.ReleasePlanJobStatusSkipped || job.Status == config.ReleasePlanJobStatusRunning {
 				continue
 			}
+			if err := checkChangeTicketsApproved(plan, job.ID); err != nil {
+				log.Errorf("plan ID is %s, name is %s, index is %d, %s", plan.ID, plan.Name, plan.Index, err)
+				return err
+			}
 
 			args := &ExecuteReleaseJobArgs{
 				ID:   job.ID,
@@ -515,6 +575,9 @@ func ScheduleExecuteReleasePlan(c *handler.Context, planID string) error {
 				log.Error(err)
 				return err
 			}
+			if plan.Status == config.StatusSuccess {
+				go postChangeTicketResults(plan)
+			}
 
 			go func() {
 				if err := mongodb.NewReleasePlanLogColl().Create(&models.ReleasePlanLog{
@@ -594,6 +657,9 @@ func SkipReleaseJob(c *handler.Context, planID string, args *SkipReleaseJobArgs)
 	if err = mongodb.NewReleasePlanColl().UpdateByID(ctx, planID, plan); err != nil {
 		return errors.Wrap(err, "update plan")
 	}
+	if plan.Status == config.StatusSuccess {
+		go postChangeTicketResults(plan)
+	}
 
 	go func() {
 		if err := mongodb.NewReleasePlanLogColl().Create(&models.ReleasePlanLog{
@@ -657,6 +723,7 @@ func UpdateReleasePlanStatus(c *handler.Context, planID, status string) error {
 		}
 
 		setReleaseJobsForExecuting(plan)
+		openChangeTickets(plan)
 	case config.StatusWaitForApprove:
 		if err := clearApprovalData(plan.Approval); err != nil {
 			return errors.Wrap(err, "clear approval data")
@@ -683,6 +750,9 @@ func UpdateReleasePlanStatus(c *handler.Context, planID, status string) error {
 	if err = mongodb.NewReleasePlanColl().UpdateByID(ctx, planID, plan); err != nil {
 		return errors.Wrap(err, "update plan")
 	}
+	if plan.Status == config.StatusCancel {
+		go postChangeTicketResults(plan)
+	}
 
 	go func() {
 		if err := mongodb.NewReleasePlanLogColl().Create(&models.ReleasePlanLog{
@@ -735,16 +805,20 @@ func ApproveReleasePlan(c *handler.Context, planID string, req *ApproveRequest)
 		// restore data after restart aslan
 		log.Infof("updateNativeApproval: approval instance code %s not found, set it", plan.Approval.NativeApproval.InstanceCode)
 		approvalUsers, _ := geneFlatNativeApprovalUsers(plan.Approval.NativeApproval)
-		originApprovalUsers := plan.Approval.NativeApproval.ApproveUsers
-		plan.Approval.NativeApproval.ApproveUsers = approvalUsers
-		approvalservice.GlobalApproveMap.SetApproval(plan.Approval.NativeApproval.InstanceCode, plan.Approval.NativeApproval)
-		plan.Approval.NativeApproval.ApproveUsers = originApprovalUsers
+		nativeApproval := plan.Approval.NativeApproval
+		originApprovalUsers, originQuorumRules, originSteps := nativeApproval.ApproveUsers, nativeApproval.QuorumRules, nativeApproval.Steps
+		nativeApproval.ApproveUsers = approvalUsers
+		nativeApproval.QuorumRules = commonutil.GeneQuorumRules(nativeApproval.QuorumRules)
+		nativeApproval.Steps = commonutil.GeneNativeApprovalSteps(nativeApproval.Steps)
+		approvalservice.GlobalApproveMap.SetApproval(nativeApproval.InstanceCode, nativeApproval)
+		nativeApproval.ApproveUsers, nativeApproval.QuorumRules, nativeApproval.Steps = originApprovalUsers, originQuorumRules, originSteps
 	}
 
 	approval, err = approvalservice.GlobalApproveMap.DoApproval(approvalKey, c.UserName, c.UserID, req.Comment, req.Approve)
 	if err != nil {
 		return errors.Wrap(err, "do approval")
 	}
+	commonservice.RecordReleasePlanApproval(planID, plan.Name, c.UserID, c.UserName, req.Comment, req.Approve, approvalservice.OnBehalfOf(approval, c.UserID))
 
 	plan.Approval.NativeApproval = approval
 	approved, _, _, err := approvalservice.GlobalApproveMap.IsApproval(approvalKey)
@@ -776,6 +850,7 @@ func ApproveReleasePlan(c *handler.Context, planID string, req *ApproveRequest)
 		}
 
 		setReleaseJobsForExecuting(plan)
+		openChangeTickets(plan)
 	case config.StatusReject:
 		planLog = &models.ReleasePlanLog{
 			PlanID:    planID,
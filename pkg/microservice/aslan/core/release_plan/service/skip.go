@@ -43,6 +43,8 @@ func NewReleaseJobSkipper(c *SkipReleaseJobContext, args *SkipReleaseJobArgs) (R
 		return NewTextReleaseJobSkipper(c, args)
 	case config.JobWorkflow:
 		return NewWorkflowReleaseJobSkipper(c, args)
+	case config.JobChangeTicket:
+		return NewChangeTicketReleaseJobSkipper(c, args)
 	default:
 		return nil, errors.Errorf("invalid release job type: %s", args.Type)
 	}
@@ -125,3 +127,32 @@ func (e *TextReleaseJobSkipper) Skip(plan *models.ReleasePlan) error {
 	}
 	return errors.Errorf("job %s not found", e.ID)
 }
+
+type ChangeTicketReleaseJobSkipper struct {
+	ID  string
+	Ctx *SkipReleaseJobContext
+}
+
+func NewChangeTicketReleaseJobSkipper(c *SkipReleaseJobContext, args *SkipReleaseJobArgs) (ReleaseJobSkipper, error) {
+	return &ChangeTicketReleaseJobSkipper{
+		ID:  args.ID,
+		Ctx: c,
+	}, nil
+}
+
+func (e *ChangeTicketReleaseJobSkipper) Skip(plan *models.ReleasePlan) error {
+	for _, job := range plan.Jobs {
+		if job.ID != e.ID {
+			continue
+		}
+		if job.Status != config.ReleasePlanJobStatusTodo && job.Status != config.ReleasePlanJobStatusFailed {
+			return errors.Errorf("job %s status %s can't skip", job.Name, job.Status)
+		}
+
+		job.Status = config.ReleasePlanJobStatusSkipped
+		job.ExecutedBy = e.Ctx.UserName
+		job.ExecutedTime = time.Now().Unix()
+		return nil
+	}
+	return errors.Errorf("job %s not found", e.ID)
+}
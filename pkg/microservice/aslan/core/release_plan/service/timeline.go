@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+)
+
+// defaultReleaseJobDuration is the estimated duration in seconds of the jobs without a planned time window
+const defaultReleaseJobDuration int64 = 30 * 60
+
+type ReleasePlanTimeline struct {
+	PlanID             string `json:"plan_id"`
+	EstimatedStartTime int64  `json:"estimated_start_time"`
+	EstimatedEndTime   int64  `json:"estimated_end_time"`
+	// CriticalPath is the ids of the jobs on the longest dependency chain, in execution order
+	CriticalPath []string              `json:"critical_path"`
+	Jobs         []*ReleaseJobTimeline `json:"jobs"`
+}
+
+type ReleaseJobTimeline struct {
+	ID                 string                      `json:"id"`
+	Name               string                      `json:"name"`
+	Type               config.ReleasePlanJobType   `json:"type"`
+	Status             config.ReleasePlanJobStatus `json:"status"`
+	DependsOn          []string                    `json:"depends_on"`
+	PlannedStartTime   int64                       `json:"planned_start_time"`
+	PlannedEndTime     int64                       `json:"planned_end_time"`
+	EstimatedStartTime int64                       `json:"estimated_start_time"`
+	EstimatedEndTime   int64                       `json:"estimated_end_time"`
+	// Slack is how long in seconds the job can be delayed without delaying the whole plan
+	Slack    int64 `json:"slack"`
+	Critical bool  `json:"critical"`
+}
+
+// GetReleasePlanTimeline computes the estimated start and end time of every job in the plan from their dependencies
+// and planned time windows, the plan starts at its start time or now if the start time is not set.
+func GetReleasePlanTimeline(id string) (*ReleasePlanTimeline, error) {
+	plan, err := mongodb.NewReleasePlanColl().GetByID(context.Background(), id)
+	if err != nil {
+		return nil, errors.Wrap(err, "get plan")
+	}
+
+	start := plan.StartTime
+	if start == 0 {
+		start = time.Now().Unix()
+	}
+	timeline, err := computeReleasePlanTimeline(plan.Jobs, start)
+	if err != nil {
+		return nil, err
+	}
+	timeline.PlanID = id
+	return timeline, nil
+}
+
+// checkReleaseJobDependencies requires all the dependencies of a job to be done or skipped before it's executed
+func checkReleaseJobDependencies(plan *models.ReleasePlan, jobID string) error {
+	jobMap := make(map[string]*models.ReleaseJob, len(plan.Jobs))
+	for _, job := range plan.Jobs {
+		jobMap[job.ID] = job
+	}
+	job, ok := jobMap[jobID]
+	if !ok {
+		return nil
+	}
+	for _, dep := range job.DependsOn {
+		depJob, ok := jobMap[dep]
+		if !ok {
+			continue
+		}
+		if depJob.Status != config.ReleasePlanJobStatusDone && depJob.Status != config.ReleasePlanJobStatusSkipped {
+			return errors.Errorf("job %s depends on job %s which is not finished yet", job.Name, depJob.Name)
+		}
+	}
+	return nil
+}
+
+// sortReleaseJobs sorts the jobs so that every job comes after its dependencies, the original order is kept among
+// the jobs which don't depend on each other.
+func sortReleaseJobs(jobs []*models.ReleaseJob) ([]*models.ReleaseJob, error) {
+	jobMap := make(map[string]*models.ReleaseJob, len(jobs))
+	for _, job := range jobs {
+		jobMap[job.ID] = job
+	}
+	for _, job := range jobs {
+		for _, dep := range job.DependsOn {
+			if _, ok := jobMap[dep]; !ok {
+				return nil, fmt.Errorf("job %s depends on job %s which is not in the plan", job.Name, dep)
+			}
+		}
+	}
+
+	sorted := make([]*models.ReleaseJob, 0, len(jobs))
+	visited := make(map[string]bool, len(jobs))
+	for len(sorted) < len(jobs) {
+		progressed := false
+		for _, job := range jobs {
+			if visited[job.ID] {
+				continue
+			}
+			ready := true
+			for _, dep := range job.DependsOn {
+				if !visited[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				visited[job.ID] = true
+				sorted = append(sorted, job)
+				progressed = true
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("circular dependency found among the release jobs")
+		}
+	}
+	return sorted, nil
+}
+
+func computeReleasePlanTimeline(jobs []*models.ReleaseJob, start int64) (*ReleasePlanTimeline, error) {
+	sorted, err := sortReleaseJobs(jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &ReleasePlanTimeline{
+		EstimatedStartTime: start,
+		EstimatedEndTime:   start,
+		CriticalPath:       make([]string, 0),
+		Jobs:               make([]*ReleaseJobTimeline, 0, len(sorted)),
+	}
+	items := make(map[string]*ReleaseJobTimeline, len(sorted))
+	durations := make(map[string]int64, len(sorted))
+
+	// forward pass: a job starts after all its dependencies and not before its planned start time
+	for _, job := range sorted {
+		duration := defaultReleaseJobDuration
+		if job.PlannedStartTime > 0 && job.PlannedEndTime > job.PlannedStartTime {
+			duration = job.PlannedEndTime - job.PlannedStartTime
+		}
+		item := &ReleaseJobTimeline{
+			ID:               job.ID,
+			Name:             job.Name,
+			Type:             job.Type,
+			Status:           job.Status,
+			DependsOn:        job.DependsOn,
+			PlannedStartTime: job.PlannedStartTime,
+			PlannedEndTime:   job.PlannedEndTime,
+		}
+
+		finished := job.Status == config.ReleasePlanJobStatusDone || job.Status == config.ReleasePlanJobStatusSkipped
+		if finished && job.ExecutedTime > 0 {
+			item.EstimatedEndTime = job.ExecutedTime
+			item.EstimatedStartTime = job.ExecutedTime - duration
+		} else {
+			item.EstimatedStartTime = start
+			if job.PlannedStartTime > item.EstimatedStartTime {
+				item.EstimatedStartTime = job.PlannedStartTime
+			}
+			for _, dep := range job.DependsOn {
+				if items[dep].EstimatedEndTime > item.EstimatedStartTime {
+					item.EstimatedStartTime = items[dep].EstimatedEndTime
+				}
+			}
+			item.EstimatedEndTime = item.EstimatedStartTime + duration
+		}
+
+		if item.EstimatedStartTime < timeline.EstimatedStartTime {
+			timeline.EstimatedStartTime = item.EstimatedStartTime
+		}
+		if item.EstimatedEndTime > timeline.EstimatedEndTime {
+			timeline.EstimatedEndTime = item.EstimatedEndTime
+		}
+		items[job.ID] = item
+		durations[job.ID] = item.EstimatedEndTime - item.EstimatedStartTime
+		timeline.Jobs = append(timeline.Jobs, item)
+	}
+
+	// backward pass: the latest end time of a job is the earliest latest start time of the jobs depending on it
+	latestEnd := make(map[string]int64, len(sorted))
+	for _, job := range sorted {
+		latestEnd[job.ID] = timeline.EstimatedEndTime
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		job := sorted[i]
+		item := items[job.ID]
+		latestStart := latestEnd[job.ID] - durations[job.ID]
+		item.Slack = latestStart - item.EstimatedStartTime
+		item.Critical = item.Slack == 0
+		for _, dep := range job.DependsOn {
+			if latestStart < latestEnd[dep] {
+				latestEnd[dep] = latestStart
+			}
+		}
+	}
+
+	// the critical path is traced back from the critical job which ends last
+	var current *ReleaseJobTimeline
+	for _, item := range timeline.Jobs {
+		if item.Critical && item.EstimatedEndTime == timeline.EstimatedEndTime {
+			current = item
+		}
+	}
+	path := make([]string, 0)
+	for current != nil {
+		path = append([]string{current.ID}, path...)
+		var next *ReleaseJobTimeline
+		for _, dep := range current.DependsOn {
+			if items[dep].Critical && items[dep].EstimatedEndTime == current.EstimatedStartTime {
+				next = items[dep]
+				break
+			}
+		}
+		current = next
+	}
+	timeline.CriticalPath = path
+
+	return timeline, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+func TestComputeReleasePlanTimeline(t *testing.T) {
+	const start = int64(1000000)
+	jobs := []*models.ReleaseJob{
+		{ID: "deploy", Name: "deploy", DependsOn: []string{"build", "db"}},
+		{ID: "build", Name: "build", PlannedStartTime: start, PlannedEndTime: start + 3600},
+		{ID: "db", Name: "db"},
+		{ID: "notify", Name: "notify", DependsOn: []string{"db"}},
+	}
+
+	timeline, err := computeReleasePlanTimeline(jobs, start)
+	assert.NoError(t, err)
+	assert.Equal(t, start+3600+defaultReleaseJobDuration, timeline.EstimatedEndTime)
+	assert.Equal(t, []string{"build", "deploy"}, timeline.CriticalPath)
+
+	items := make(map[string]*ReleaseJobTimeline)
+	for _, item := range timeline.Jobs {
+		items[item.ID] = item
+	}
+	assert.Equal(t, start+3600, items["deploy"].EstimatedStartTime)
+	assert.Equal(t, int64(3600-defaultReleaseJobDuration), items["db"].Slack)
+	assert.False(t, items["notify"].Critical)
+}
+
+func TestSortReleaseJobsCycle(t *testing.T) {
+	jobs := []*models.ReleaseJob{
+		{ID: "a", Name: "a", DependsOn: []string{"b"}},
+		{ID: "b", Name: "b", DependsOn: []string{"a"}},
+	}
+	_, err := sortReleaseJobs(jobs)
+	assert.Error(t, err)
+
+	_, err = sortReleaseJobs([]*models.ReleaseJob{{ID: "a", Name: "a", DependsOn: []string{"c"}}})
+	assert.Error(t, err)
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
@@ -45,6 +46,8 @@ const (
 	VerbUpdateReleaseJob = "update_release_job"
 	VerbDeleteReleaseJob = "delete_release_job"
 
+	VerbUpdateReleaseJobSchedule = "update_release_job_schedule"
+
 	VerbUpdateApproval = "update_approval"
 	VerbDeleteApproval = "delete_approval"
 
@@ -89,6 +92,8 @@ func NewPlanUpdater(args *UpdateReleasePlanArgs) (PlanUpdater, error) {
 		return NewUpdateReleaseJobUpdater(args)
 	case VerbDeleteReleaseJob:
 		return NewDeleteReleaseJobUpdater(args)
+	case VerbUpdateReleaseJobSchedule:
+		return NewReleaseJobScheduleUpdater(args)
 	case VerbUpdateApproval:
 		return NewUpdateApprovalUpdater(args)
 	case VerbDeleteApproval:
@@ -374,6 +379,9 @@ func (u *DeleteReleaseJobUpdater) Update(plan *models.ReleasePlan) (before inter
 		if job.ID == u.ID {
 			u.name = job.Name
 			plan.Jobs = append(plan.Jobs[:i], plan.Jobs[i+1:]...)
+			for _, other := range plan.Jobs {
+				other.DependsOn = lo.Without(other.DependsOn, u.ID)
+			}
 			return
 		}
 	}
@@ -399,6 +407,81 @@ func (u *DeleteReleaseJobUpdater) Verb() string {
 	return VerbDelete
 }
 
+type ReleaseJobScheduleUpdater struct {
+	ID               string   `json:"id"`
+	DependsOn        []string `json:"depends_on"`
+	PlannedStartTime int64    `json:"planned_start_time"`
+	PlannedEndTime   int64    `json:"planned_end_time"`
+	name             string
+}
+
+func NewReleaseJobScheduleUpdater(args *UpdateReleasePlanArgs) (*ReleaseJobScheduleUpdater, error) {
+	var updater ReleaseJobScheduleUpdater
+	if err := models.IToi(args.Spec, &updater); err != nil {
+		return nil, errors.Wrap(err, "invalid spec")
+	}
+	return &updater, nil
+}
+
+func (u *ReleaseJobScheduleUpdater) Update(plan *models.ReleasePlan) (before interface{}, after interface{}, err error) {
+	var target *models.ReleaseJob
+	for _, job := range plan.Jobs {
+		if job.ID == u.ID {
+			target = job
+			break
+		}
+	}
+	if target == nil {
+		return nil, nil, fmt.Errorf("job %s not found", u.ID)
+	}
+
+	u.name = target.Name
+	before = &ReleaseJobScheduleUpdater{
+		ID:               target.ID,
+		DependsOn:        target.DependsOn,
+		PlannedStartTime: target.PlannedStartTime,
+		PlannedEndTime:   target.PlannedEndTime,
+	}
+	after = u
+	target.DependsOn = lo.Uniq(u.DependsOn)
+	target.PlannedStartTime = u.PlannedStartTime
+	target.PlannedEndTime = u.PlannedEndTime
+
+	// the dependencies are checked against the whole plan to reject unknown jobs and cycles
+	if _, err := sortReleaseJobs(plan.Jobs); err != nil {
+		return nil, nil, err
+	}
+	return
+}
+
+func (u *ReleaseJobScheduleUpdater) Lint() error {
+	if u.ID == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+	if lo.Contains(u.DependsOn, u.ID) {
+		return fmt.Errorf("job cannot depend on itself")
+	}
+	if u.PlannedStartTime < 0 || u.PlannedEndTime < 0 {
+		return fmt.Errorf("planned time cannot be negative")
+	}
+	if u.PlannedStartTime != 0 && u.PlannedEndTime != 0 && u.PlannedStartTime > u.PlannedEndTime {
+		return fmt.Errorf("planned start time should not be greater than planned end time")
+	}
+	return nil
+}
+
+func (u *ReleaseJobScheduleUpdater) TargetName() string {
+	return u.name
+}
+
+func (u *ReleaseJobScheduleUpdater) TargetType() string {
+	return TargetTypeReleaseJob
+}
+
+func (u *ReleaseJobScheduleUpdater) Verb() string {
+	return VerbUpdate
+}
+
 type UpdateApprovalUpdater struct {
 	Approval *models.Approval `json:"approval"`
 }
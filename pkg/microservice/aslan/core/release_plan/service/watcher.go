@@ -82,6 +82,14 @@ func updatePlanWorkflowReleaseJob(plan *models.ReleasePlan, log *zap.SugaredLogg
 		return
 	}
 	for _, job := range plan.Jobs {
+		if job.Status == config.ReleasePlanJobStatusRunning && job.Type == config.JobChangeTicket {
+			refreshChangeTicket(job, log)
+			if checkReleasePlanJobsAllDone(plan) {
+				plan.ExecutingTime = time.Now().Unix()
+				plan.SuccessTime = time.Now().Unix()
+				plan.Status = config.StatusSuccess
+			}
+		}
 		if job.Status == config.ReleasePlanJobStatusRunning && job.Type == config.JobWorkflow {
 			spec := new(models.WorkflowReleaseJobSpec)
 			if err := models.IToi(job.Spec, spec); err != nil {
@@ -109,6 +117,10 @@ func updatePlanWorkflowReleaseJob(plan *models.ReleasePlan, log *zap.SugaredLogg
 	}
 	if err := mongodb.NewReleasePlanColl().UpdateByID(ctx, plan.ID.Hex(), plan); err != nil {
 		log.Errorf("update plan %s error: %v", plan.ID.Hex(), err)
+		return
+	}
+	if plan.Status == config.StatusSuccess {
+		go postChangeTicketResults(plan)
 	}
 	return
 }
@@ -206,6 +218,7 @@ func updatePlanApproval(plan *models.ReleasePlan) error {
 		}
 
 		setReleaseJobsForExecuting(plan)
+		openChangeTickets(plan)
 	case config.StatusReject:
 		planLog = &models.ReleasePlanLog{
 			PlanID:    plan.ID.Hex(),
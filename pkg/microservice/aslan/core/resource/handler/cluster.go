@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/resource/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary OpenAPI Create Cluster Resource
+// @Description OpenAPI Create Cluster Resource, the etag of the cluster is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body 		body 		service.Cluster 	true 	"body"
+// @Success 200 		{object} 	service.Cluster
+// @Router /openapi/resources/clusters [post]
+func OpenAPICreateClusterResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Cluster)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", "", "新增", "资源配置-集群", args.Name, "", ctx.Logger)
+
+	resp, etag, err := service.CreateCluster(ctx.UserName, args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Get Cluster Resource
+// @Description OpenAPI Get Cluster Resource, the etag of the cluster is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id 			path 		string 				true 	"cluster id"
+// @Success 200 		{object} 	service.Cluster
+// @Router /openapi/resources/clusters/{id} [get]
+func OpenAPIGetClusterResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	resp, etag, err := service.GetCluster(c.Param("id"), ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Update Cluster Resource
+// @Description OpenAPI Update Cluster Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id 			path 		string 				true 	"cluster id"
+// @Param 	If-Match 	header 		string 				false 	"etag of the cluster"
+// @Param 	body 		body 		service.Cluster 	true 	"body"
+// @Success 200 		{object} 	service.Cluster
+// @Router /openapi/resources/clusters/{id} [put]
+func OpenAPIUpdateClusterResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Cluster)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", "", "更新", "资源配置-集群", c.Param("id"), "", ctx.Logger)
+
+	resp, etag, err := service.UpdateCluster(ctx.UserName, c.Param("id"), c.GetHeader(ifMatchHeader), args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Delete Cluster Resource
+// @Description OpenAPI Delete Cluster Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id 			path 		string 				true 	"cluster id"
+// @Param 	If-Match 	header 		string 				false 	"etag of the cluster"
+// @Success 200
+// @Router /openapi/resources/clusters/{id} [delete]
+func OpenAPIDeleteClusterResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", "", "删除", "资源配置-集群", c.Param("id"), "", ctx.Logger)
+
+	ctx.Err = service.DeleteCluster(ctx.UserName, c.Param("id"), c.GetHeader(ifMatchHeader), ctx.Logger)
+}
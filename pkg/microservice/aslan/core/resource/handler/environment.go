@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/resource/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type envAction int
+
+const (
+	envActionView envAction = iota
+	envActionCreate
+	envActionEdit
+	envActionDelete
+)
+
+// checkEnvPermission checks the permission of the environment action, the production environments are
+// only available in the professional edition.
+func checkEnvPermission(ctx *internalhandler.Context, projectKey string, production bool, action envAction) bool {
+	if production {
+		if err := commonutil.CheckZadigProfessionalLicense(); err != nil {
+			ctx.Err = err
+			return false
+		}
+	}
+	if ctx.Resources.IsSystemAdmin {
+		return true
+	}
+	projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+	if !ok {
+		ctx.UnAuthorized = true
+		return false
+	}
+	if projectAuthInfo.IsProjectAdmin {
+		return true
+	}
+
+	allowed := false
+	switch {
+	case production && action == envActionView:
+		allowed = projectAuthInfo.ProductionEnv.View
+	case production && action == envActionCreate:
+		allowed = projectAuthInfo.ProductionEnv.Create
+	case production && action == envActionEdit:
+		allowed = projectAuthInfo.ProductionEnv.EditConfig
+	case production && action == envActionDelete:
+		allowed = projectAuthInfo.ProductionEnv.Delete
+	case action == envActionView:
+		allowed = projectAuthInfo.Env.View
+	case action == envActionCreate:
+		allowed = projectAuthInfo.Env.Create
+	case action == envActionEdit:
+		allowed = projectAuthInfo.Env.EditConfig
+	case action == envActionDelete:
+		allowed = projectAuthInfo.Env.Delete
+	}
+	if !allowed {
+		ctx.UnAuthorized = true
+	}
+	return allowed
+}
+
+// @Summary OpenAPI Create Environment Resource
+// @Description OpenAPI Create Environment Resource, the etag of the environment is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	key 		path 		string 					true 	"project key"
+// @Param 	body 		body 		service.Environment 	true 	"body"
+// @Success 200 		{object} 	service.Environment
+// @Router /openapi/resources/projects/{key}/environments [post]
+func OpenAPICreateEnvironmentResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Environment)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	projectKey := c.Param("key")
+	if args.ProjectKey == "" {
+		args.ProjectKey = projectKey
+	}
+	if args.ProjectKey != projectKey {
+		ctx.Err = e.ErrInvalidParam.AddDesc("project_key doesn't match the project in the path")
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", projectKey, "新增", "环境", args.EnvName, "", ctx.Logger)
+
+	if !checkEnvPermission(ctx, projectKey, args.Production, envActionCreate) {
+		return
+	}
+
+	resp, etag, err := service.CreateEnvironment(ctx.UserName, ctx.RequestID, args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Get Environment Resource
+// @Description OpenAPI Get Environment Resource, the etag of the environment is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	key 		path 		string 					true 	"project key"
+// @Param 	name 		path 		string 					true 	"env name"
+// @Success 200 		{object} 	service.Environment
+// @Router /openapi/resources/projects/{key}/environments/{name} [get]
+func OpenAPIGetEnvironmentResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("key")
+	resp, etag, err := service.GetEnvironment(projectKey, c.Param("name"), ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	if !checkEnvPermission(ctx, projectKey, resp.Production, envActionView) {
+		return
+	}
+	setResourceResponse(c, ctx, resp, etag, nil)
+}
+
+// @Summary OpenAPI Update Environment Resource
+// @Description OpenAPI Update Environment Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	key 		path 		string 					true 	"project key"
+// @Param 	name 		path 		string 					true 	"env name"
+// @Param 	If-Match 	header 		string 					false 	"etag of the environment"
+// @Param 	body 		body 		service.Environment 	true 	"body"
+// @Success 200 		{object} 	service.Environment
+// @Router /openapi/resources/projects/{key}/environments/{name} [put]
+func OpenAPIUpdateEnvironmentResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Environment)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	projectKey, envName := c.Param("key"), c.Param("name")
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", projectKey, "更新", "环境", envName, "", ctx.Logger)
+
+	env, _, err := service.GetEnvironment(projectKey, envName, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	if !checkEnvPermission(ctx, projectKey, env.Production, envActionEdit) {
+		return
+	}
+
+	resp, etag, err := service.UpdateEnvironment(ctx.UserName, projectKey, envName, c.GetHeader(ifMatchHeader), args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Delete Environment Resource
+// @Description OpenAPI Delete Environment Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	key 		path 		string 					true 	"project key"
+// @Param 	name 		path 		string 					true 	"env name"
+// @Param 	If-Match 	header 		string 					false 	"etag of the environment"
+// @Param 	isDelete 	query 		bool 					false 	"whether to delete the namespace of the environment"
+// @Success 200
+// @Router /openapi/resources/projects/{key}/environments/{name} [delete]
+func OpenAPIDeleteEnvironmentResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, envName := c.Param("key"), c.Param("name")
+	isDelete, err := isDeleteQuery(c)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid param isDelete")
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", projectKey, "删除", "环境", envName, "", ctx.Logger)
+
+	env, _, err := service.GetEnvironment(projectKey, envName, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	if !checkEnvPermission(ctx, projectKey, env.Production, envActionDelete) {
+		return
+	}
+
+	ctx.Err = service.DeleteEnvironment(ctx.UserName, ctx.RequestID, projectKey, envName, c.GetHeader(ifMatchHeader), isDelete, ctx.Logger)
+}
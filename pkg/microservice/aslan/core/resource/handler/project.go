@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/resource/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary OpenAPI Create Project Resource
+// @Description OpenAPI Create Project Resource, the etag of the project is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body 		body 		service.Project 	true 	"body"
+// @Success 200 		{object} 	service.Project
+// @Router /openapi/resources/projects [post]
+func OpenAPICreateProjectResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Project)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", args.ProjectKey, "新增", "项目管理-项目", args.ProjectKey, "", ctx.Logger)
+
+	resp, etag, err := service.CreateProject(ctx.UserID, ctx.UserName, args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Get Project Resource
+// @Description OpenAPI Get Project Resource, the etag of the project is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	key 		path 		string 				true 	"project key"
+// @Success 200 		{object} 	service.Project
+// @Router /openapi/resources/projects/{key} [get]
+func OpenAPIGetProjectResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("key")
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	resp, etag, err := service.GetProject(projectKey, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Update Project Resource
+// @Description OpenAPI Update Project Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	key 		path 		string 				true 	"project key"
+// @Param 	If-Match 	header 		string 				false 	"etag of the project"
+// @Param 	body 		body 		service.Project 	true 	"body"
+// @Success 200 		{object} 	service.Project
+// @Router /openapi/resources/projects/{key} [put]
+func OpenAPIUpdateProjectResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("key")
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	args := new(service.Project)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", projectKey, "更新", "项目管理-项目", projectKey, "", ctx.Logger)
+
+	resp, etag, err := service.UpdateProject(ctx.UserName, projectKey, c.GetHeader(ifMatchHeader), args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Delete Project Resource
+// @Description OpenAPI Delete Project Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	key 		path 		string 				true 	"project key"
+// @Param 	If-Match 	header 		string 				false 	"etag of the project"
+// @Param 	isDelete 	query 		bool 				false 	"whether to delete the resources of the environments"
+// @Success 200
+// @Router /openapi/resources/projects/{key} [delete]
+func OpenAPIDeleteProjectResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("key")
+	if !ctx.Resources.IsSystemAdmin {
+		if projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok || !projectAuthInfo.IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	isDelete, err := isDeleteQuery(c)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid param isDelete")
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", projectKey, "删除", "项目管理-项目", projectKey, "", ctx.Logger)
+
+	ctx.Err = service.DeleteProject(ctx.UserName, ctx.RequestID, projectKey, c.GetHeader(ifMatchHeader), isDelete, ctx.Logger)
+}
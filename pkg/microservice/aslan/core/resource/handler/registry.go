@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/resource/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary OpenAPI Create Registry Resource
+// @Description OpenAPI Create Registry Resource, the etag of the registry is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body 		body 		service.Registry 	true 	"body"
+// @Success 200 		{object} 	service.Registry
+// @Router /openapi/resources/registries [post]
+func OpenAPICreateRegistryResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Registry)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", "", "新增", "系统设置-Registry", fmt.Sprintf("提供商:%s,Namespace:%s", args.Provider, args.Namespace), "", ctx.Logger)
+
+	resp, etag, err := service.CreateRegistry(ctx.UserName, args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Get Registry Resource
+// @Description OpenAPI Get Registry Resource, the etag of the registry is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id 			path 		string 				true 	"registry id"
+// @Success 200 		{object} 	service.Registry
+// @Router /openapi/resources/registries/{id} [get]
+func OpenAPIGetRegistryResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	resp, etag, err := service.GetRegistry(c.Param("id"), ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Update Registry Resource
+// @Description OpenAPI Update Registry Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id 			path 		string 				true 	"registry id"
+// @Param 	If-Match 	header 		string 				false 	"etag of the registry"
+// @Param 	body 		body 		service.Registry 	true 	"body"
+// @Success 200 		{object} 	service.Registry
+// @Router /openapi/resources/registries/{id} [put]
+func OpenAPIUpdateRegistryResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Registry)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", "", "更新", "系统设置-Registry", c.Param("id"), "", ctx.Logger)
+
+	resp, etag, err := service.UpdateRegistry(ctx.UserName, c.Param("id"), c.GetHeader(ifMatchHeader), args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Delete Registry Resource
+// @Description OpenAPI Delete Registry Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id 			path 		string 				true 	"registry id"
+// @Param 	If-Match 	header 		string 				false 	"etag of the registry"
+// @Success 200
+// @Router /openapi/resources/registries/{id} [delete]
+func OpenAPIDeleteRegistryResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", "", "删除", "系统设置-Registry", c.Param("id"), "", ctx.Logger)
+
+	ctx.Err = service.DeleteRegistry(c.Param("id"), c.GetHeader(ifMatchHeader), ctx.Logger)
+}
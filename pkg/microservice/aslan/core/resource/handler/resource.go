@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+)
+
+const ifMatchHeader = "If-Match"
+
+// setResourceResponse returns the resource with its etag in the ETag header, the clients send the etag back
+// in the If-Match header to make sure the resource is not modified by others since it's read.
+func setResourceResponse(c *gin.Context, ctx *internalhandler.Context, resource interface{}, etag string, err error) {
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	c.Header("ETag", etag)
+	ctx.Resp = resource
+}
+
+// isDeleteQuery parses the optional isDelete query, the related resources are kept if it's not set.
+func isDeleteQuery(c *gin.Context) (bool, error) {
+	if c.Query("isDelete") == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(c.Query("isDelete"))
+}
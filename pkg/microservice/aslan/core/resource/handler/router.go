@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type OpenAPIRouter struct{}
+
+func (*OpenAPIRouter) Inject(router *gin.RouterGroup) {
+	project := router.Group("projects")
+	{
+		project.POST("", OpenAPICreateProjectResource)
+		project.GET("/:key", OpenAPIGetProjectResource)
+		project.PUT("/:key", OpenAPIUpdateProjectResource)
+		project.DELETE("/:key", OpenAPIDeleteProjectResource)
+
+		project.POST("/:key/environments", OpenAPICreateEnvironmentResource)
+		project.GET("/:key/environments/:name", OpenAPIGetEnvironmentResource)
+		project.PUT("/:key/environments/:name", OpenAPIUpdateEnvironmentResource)
+		project.DELETE("/:key/environments/:name", OpenAPIDeleteEnvironmentResource)
+	}
+
+	workflow := router.Group("workflows")
+	{
+		workflow.POST("", OpenAPICreateWorkflowResource)
+		workflow.GET("/:name", OpenAPIGetWorkflowResource)
+		workflow.PUT("/:name", OpenAPIUpdateWorkflowResource)
+		workflow.DELETE("/:name", OpenAPIDeleteWorkflowResource)
+	}
+
+	registry := router.Group("registries")
+	{
+		registry.POST("", OpenAPICreateRegistryResource)
+		registry.GET("/:id", OpenAPIGetRegistryResource)
+		registry.PUT("/:id", OpenAPIUpdateRegistryResource)
+		registry.DELETE("/:id", OpenAPIDeleteRegistryResource)
+	}
+
+	cluster := router.Group("clusters")
+	{
+		cluster.POST("", OpenAPICreateClusterResource)
+		cluster.GET("/:id", OpenAPIGetClusterResource)
+		cluster.PUT("/:id", OpenAPIUpdateClusterResource)
+		cluster.DELETE("/:id", OpenAPIDeleteClusterResource)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/resource/service"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func checkWorkflowPermission(ctx *internalhandler.Context, projectKey string, allowed func(*user.WorkflowActions) bool) bool {
+	if ctx.Resources.IsSystemAdmin {
+		return true
+	}
+	projectAuthInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+	if !ok || (!projectAuthInfo.IsProjectAdmin && !allowed(projectAuthInfo.Workflow)) {
+		ctx.UnAuthorized = true
+		return false
+	}
+	return true
+}
+
+// @Summary OpenAPI Create Workflow Resource
+// @Description OpenAPI Create Workflow Resource, the etag of the workflow is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body 		body 		service.Workflow 	true 	"body"
+// @Success 200 		{object} 	service.Workflow
+// @Router /openapi/resources/workflows [post]
+func OpenAPICreateWorkflowResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Workflow)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", args.ProjectKey, "新增", "自定义工作流", args.Name, "", ctx.Logger)
+
+	if !checkWorkflowPermission(ctx, args.ProjectKey, func(actions *user.WorkflowActions) bool {
+		return actions.Create
+	}) {
+		return
+	}
+
+	resp, etag, err := service.CreateWorkflow(ctx.Resources, ctx.UserName, args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Get Workflow Resource
+// @Description OpenAPI Get Workflow Resource, the etag of the workflow is returned in the ETag header
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name 		path 		string 				true 	"workflow name"
+// @Success 200 		{object} 	service.Workflow
+// @Router /openapi/resources/workflows/{name} [get]
+func OpenAPIGetWorkflowResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	resp, etag, err := service.GetWorkflow(c.Param("name"), ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	if !checkWorkflowPermission(ctx, resp.ProjectKey, func(actions *user.WorkflowActions) bool {
+		return actions.View
+	}) {
+		return
+	}
+	setResourceResponse(c, ctx, resp, etag, nil)
+}
+
+// @Summary OpenAPI Update Workflow Resource
+// @Description OpenAPI Update Workflow Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name 		path 		string 				true 	"workflow name"
+// @Param 	If-Match 	header 		string 				false 	"etag of the workflow"
+// @Param 	body 		body 		service.Workflow 	true 	"body"
+// @Success 200 		{object} 	service.Workflow
+// @Router /openapi/resources/workflows/{name} [put]
+func OpenAPIUpdateWorkflowResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.Workflow)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	name := c.Param("name")
+	workflow, _, err := service.GetWorkflow(name, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", workflow.ProjectKey, "更新", "自定义工作流", name, "", ctx.Logger)
+
+	if !checkWorkflowPermission(ctx, workflow.ProjectKey, func(actions *user.WorkflowActions) bool {
+		return actions.Edit
+	}) {
+		return
+	}
+
+	resp, etag, err := service.UpdateWorkflow(ctx.Resources, ctx.UserName, name, c.GetHeader(ifMatchHeader), args, ctx.Logger)
+	setResourceResponse(c, ctx, resp, etag, err)
+}
+
+// @Summary OpenAPI Delete Workflow Resource
+// @Description OpenAPI Delete Workflow Resource, 412 is returned if the If-Match header doesn't match the current etag
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name 		path 		string 				true 	"workflow name"
+// @Param 	If-Match 	header 		string 				false 	"etag of the workflow"
+// @Success 200
+// @Router /openapi/resources/workflows/{name} [delete]
+func OpenAPIDeleteWorkflowResource(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	name := c.Param("name")
+	workflow, _, err := service.GetWorkflow(name, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", workflow.ProjectKey, "删除", "自定义工作流", name, "", ctx.Logger)
+
+	if !checkWorkflowPermission(ctx, workflow.ProjectKey, func(actions *user.WorkflowActions) bool {
+		return actions.Delete
+	}) {
+		return
+	}
+
+	ctx.Err = service.DeleteWorkflow(name, c.GetHeader(ifMatchHeader), ctx.Logger)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	clusterservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/multicluster/service"
+	systemservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const resourceKindCluster = "cluster"
+
+func getCluster(id string, logger *zap.SugaredLogger) (*Cluster, string, error) {
+	cluster, err := commonrepo.NewK8SClusterColl().Get(id)
+	if err != nil {
+		return nil, "", notFoundOr(err, resourceKindCluster, id)
+	}
+	projectNames := clusterservice.GetProjectNames(id, logger)
+	if projectNames == nil {
+		projectNames = []string{}
+	}
+	sort.Strings(projectNames)
+
+	resp := &Cluster{
+		ID:           cluster.ID.Hex(),
+		Name:         cluster.Name,
+		Description:  cluster.Description,
+		Production:   cluster.Production,
+		Provider:     cluster.Provider,
+		Type:         cluster.Type,
+		ProjectNames: projectNames,
+	}
+	etag, err := computeETag(resp, cluster.KubeConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, etag, nil
+}
+
+func GetCluster(id string, logger *zap.SugaredLogger) (*Cluster, string, error) {
+	return getCluster(id, logger)
+}
+
+func CreateCluster(userName string, args *Cluster, logger *zap.SugaredLogger) (*Cluster, string, error) {
+	resp, err := systemservice.OpenAPICreateCluster(userName, &systemservice.OpenAPICreateClusterRequest{
+		Name:         args.Name,
+		Production:   args.Production,
+		Description:  args.Description,
+		Provider:     args.Provider,
+		Type:         args.Type,
+		KubeConfig:   args.KubeConfig,
+		ProjectNames: args.ProjectNames,
+	}, logger)
+	if err != nil {
+		return nil, "", err
+	}
+	return getCluster(resp.Cluster.ID, logger)
+}
+
+// UpdateCluster updates the name, the description and the projects of the cluster, the other fields can't be
+// changed once the cluster is created.
+func UpdateCluster(userName, id, ifMatch string, args *Cluster, logger *zap.SugaredLogger) (*Cluster, string, error) {
+	unlock, err := lockResource(resourceKindCluster, id)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	cluster, etag, err := getCluster(id, logger)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return nil, "", err
+	}
+	switch {
+	case args.Production != cluster.Production:
+		return nil, "", e.ErrInvalidParam.AddDesc(fmt.Sprintf("production of cluster %s can't be changed", id))
+	case args.Type != "" && args.Type != cluster.Type:
+		return nil, "", e.ErrInvalidParam.AddDesc(fmt.Sprintf("type of cluster %s can't be changed", id))
+	case args.Provider != cluster.Provider:
+		return nil, "", e.ErrInvalidParam.AddDesc(fmt.Sprintf("provider of cluster %s can't be changed", id))
+	case args.KubeConfig != "":
+		return nil, "", e.ErrInvalidParam.AddDesc(fmt.Sprintf("kube_config of cluster %s can't be changed", id))
+	}
+
+	err = systemservice.OpenAPIUpdateCluster(userName, id, &systemservice.OpenAPICluster{
+		Name:         args.Name,
+		Description:  args.Description,
+		ProjectNames: args.ProjectNames,
+	}, logger)
+	if err != nil {
+		return nil, "", err
+	}
+	return getCluster(id, logger)
+}
+
+func DeleteCluster(userName, id, ifMatch string, logger *zap.SugaredLogger) error {
+	unlock, err := lockResource(resourceKindCluster, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, etag, err := getCluster(id, logger)
+	if err != nil {
+		return err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return err
+	}
+	return systemservice.OpenAPIDeleteCluster(userName, id, logger)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	environmentservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const resourceKindEnvironment = "environment"
+
+func getEnvironment(projectKey, envName string) (*Environment, string, error) {
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: projectKey, EnvName: envName})
+	if err != nil {
+		return nil, "", notFoundOr(err, resourceKindEnvironment, fmt.Sprintf("%s/%s", projectKey, envName))
+	}
+	resp := &Environment{
+		ProjectKey: env.ProductName,
+		EnvName:    env.EnvName,
+		Alias:      env.Alias,
+		Production: env.Production,
+		ClusterID:  env.ClusterID,
+		Namespace:  env.Namespace,
+		RegistryID: env.RegistryID,
+	}
+	etag, err := computeETag(resp)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, etag, nil
+}
+
+func GetEnvironment(projectKey, envName string, logger *zap.SugaredLogger) (*Environment, string, error) {
+	return getEnvironment(projectKey, envName)
+}
+
+// CreateEnvironment creates an empty environment of the k8s yaml project, the services are deployed by the workflows.
+func CreateEnvironment(userName, requestID string, args *Environment, logger *zap.SugaredLogger) (*Environment, string, error) {
+	if args.ProjectKey == "" || args.EnvName == "" {
+		return nil, "", e.ErrInvalidParam.AddDesc("project_key and env_name cannot be empty")
+	}
+	if !args.Production && args.Alias != "" {
+		return nil, "", e.ErrInvalidParam.AddDesc("alias can only be set for production environments")
+	}
+
+	createArgs := &environmentservice.OpenAPICreateEnvArgs{
+		Production:  args.Production,
+		ProjectName: args.ProjectKey,
+		EnvName:     args.EnvName,
+		ClusterID:   args.ClusterID,
+		Namespace:   args.Namespace,
+		RegistryID:  args.RegistryID,
+		Alias:       args.Alias,
+	}
+	var err error
+	if args.Production {
+		err = environmentservice.OpenAPICreateProductionEnv(createArgs, userName, requestID, logger)
+	} else {
+		err = environmentservice.OpenAPICreateK8sEnv(createArgs, userName, requestID, logger)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return getEnvironment(args.ProjectKey, args.EnvName)
+}
+
+// UpdateEnvironment updates the registry and the alias of the environment, the other fields can't be changed.
+func UpdateEnvironment(userName, projectKey, envName, ifMatch string, args *Environment, logger *zap.SugaredLogger) (*Environment, string, error) {
+	unlock, err := lockResource(resourceKindEnvironment, fmt.Sprintf("%s/%s", projectKey, envName))
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	current, etag, err := getEnvironment(projectKey, envName)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return nil, "", err
+	}
+	if args.Production != current.Production || args.ClusterID != current.ClusterID || args.Namespace != current.Namespace {
+		return nil, "", e.ErrInvalidParam.AddDesc("production, cluster_id and namespace can't be changed")
+	}
+	if !current.Production && args.Alias != current.Alias {
+		return nil, "", e.ErrInvalidParam.AddDesc("alias can only be set for production environments")
+	}
+
+	err = environmentservice.OpenAPIUpdateEnvBasicInfo(&environmentservice.EnvBasicInfoArgs{
+		RegistryID: args.RegistryID,
+		Alias:      args.Alias,
+	}, userName, projectKey, envName, current.Production, logger)
+	if err != nil {
+		return nil, "", err
+	}
+	return getEnvironment(projectKey, envName)
+}
+
+func DeleteEnvironment(userName, requestID, projectKey, envName, ifMatch string, isDelete bool, logger *zap.SugaredLogger) error {
+	unlock, err := lockResource(resourceKindEnvironment, fmt.Sprintf("%s/%s", projectKey, envName))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, etag, err := getEnvironment(projectKey, envName)
+	if err != nil {
+		return err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return err
+	}
+	if current.Production {
+		return environmentservice.DeleteProductionProduct(userName, envName, projectKey, requestID, logger)
+	}
+	return environmentservice.DeleteProduct(userName, envName, projectKey, requestID, isDelete, logger)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/koderover/zadig/v2/pkg/tool/cache"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const resourceLockExpiry = time.Minute
+
+// computeETag returns the strong etag of the resource, the states which are not exposed but affect the resource,
+// like the secrets, are passed in as hidden.
+func computeETag(resource interface{}, hidden ...string) (string, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", e.ErrGetResource.AddErr(err)
+	}
+	h := sha256.New()
+	h.Write(data)
+	for _, s := range hidden {
+		h.Write([]byte{0})
+		h.Write([]byte(s))
+	}
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(h.Sum(nil))[:32]), nil
+}
+
+// checkETag checks the If-Match header against the current etag of the resource, the check is skipped if
+// the header is empty or "*".
+func checkETag(ifMatch, etag string) error {
+	if ifMatch == "" {
+		return nil
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		// weak etags are never generated, but a weak comparison is fine for the clients who add the prefix
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return nil
+		}
+	}
+	return e.ErrPreconditionFailed.AddDesc(fmt.Sprintf("the resource has been modified, the current etag is %s", etag))
+}
+
+// lockResource locks the resource so that it can't be modified between the etag check and the update, a conflict
+// error is returned if the resource is being modified by another request.
+func lockResource(kind, id string) (func(), error) {
+	lock := cache.NewRedisLockWithExpiry(fmt.Sprintf("resource:%s:%s", kind, id), resourceLockExpiry)
+	if err := lock.Lock(); err != nil {
+		return nil, e.ErrConflict.AddDesc(fmt.Sprintf("%s %s is being modified by another request, please retry later: %v", kind, id, err))
+	}
+	return func() { lock.Unlock() }, nil
+}
+
+func notFoundOr(err error, kind, id string) error {
+	if err == mongo.ErrNoDocuments {
+		return e.ErrNotFound.AddDesc(fmt.Sprintf("%s %s not found", kind, id))
+	}
+	return e.ErrGetResource.AddErr(err)
+}
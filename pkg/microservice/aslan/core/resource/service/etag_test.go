@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func TestCheckETag(t *testing.T) {
+	const etag = `"0123456789abcdef0123456789abcdef"`
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		wantErr bool
+	}{
+		{name: "no header", ifMatch: ""},
+		{name: "wildcard", ifMatch: "*"},
+		{name: "matched", ifMatch: etag},
+		{name: "weak etag matched", ifMatch: "W/" + etag},
+		{name: "one of the list matched", ifMatch: `"other", ` + etag},
+		{name: "wildcard in the list", ifMatch: `"other", *`},
+		{name: "not matched", ifMatch: `"other"`, wantErr: true},
+		{name: "unquoted etag", ifMatch: "0123456789abcdef0123456789abcdef", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkETag(tt.ifMatch, etag)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			httpErr := &e.HTTPError{}
+			if assert.True(t, errors.As(err, &httpErr)) {
+				assert.Equal(t, http.StatusPreconditionFailed, httpErr.Code())
+			}
+		})
+	}
+}
+
+func TestComputeETag(t *testing.T) {
+	resource := map[string]string{"name": "foo"}
+
+	etag, err := computeETag(resource)
+	assert.NoError(t, err)
+	again, err := computeETag(map[string]string{"name": "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, etag, again)
+
+	// the hidden states change the etag
+	hidden, err := computeETag(resource, "secret")
+	assert.NoError(t, err)
+	assert.NotEqual(t, etag, hidden)
+	assert.NoError(t, checkETag(hidden, hidden))
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	projectservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/project/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const resourceKindProject = "project"
+
+func toProject(product *template.Product) *Project {
+	resp := &Project{
+		ProjectKey:  product.ProductName,
+		ProjectName: product.ProjectName,
+		Description: product.Description,
+		IsPublic:    product.Public,
+	}
+	switch {
+	case product.IsHelmProduct():
+		resp.ProjectType = config.ProjectTypeHelm
+	case product.IsK8sYamlProduct():
+		resp.ProjectType = config.ProjectTypeYaml
+	case product.IsHostProduct():
+		resp.ProjectType = config.ProjectTypeLoaded
+	case product.IsCVMProduct():
+		resp.ProjectType = config.ProjectTypeVM
+	}
+	return resp
+}
+
+func getProject(projectKey string) (*template.Product, *Project, string, error) {
+	product, err := templaterepo.NewProductColl().Find(projectKey)
+	if err != nil {
+		return nil, nil, "", notFoundOr(err, resourceKindProject, projectKey)
+	}
+	resp := toProject(product)
+	etag, err := computeETag(resp)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return product, resp, etag, nil
+}
+
+func GetProject(projectKey string, logger *zap.SugaredLogger) (*Project, string, error) {
+	_, resp, etag, err := getProject(projectKey)
+	return resp, etag, err
+}
+
+func CreateProject(userID, userName string, args *Project, logger *zap.SugaredLogger) (*Project, string, error) {
+	req := &projectservice.OpenAPICreateProductReq{
+		ProjectName: args.ProjectName,
+		ProjectKey:  args.ProjectKey,
+		IsPublic:    args.IsPublic,
+		Description: args.Description,
+		ProjectType: args.ProjectType,
+	}
+	if err := req.Validate(); err != nil {
+		return nil, "", e.ErrInvalidParam.AddErr(err)
+	}
+	if err := projectservice.CreateProjectOpenAPI(userID, userName, req, logger); err != nil {
+		return nil, "", err
+	}
+	return GetProject(args.ProjectKey, logger)
+}
+
+// UpdateProject updates the name, description and visibility of the project, the type of the project can't be changed.
+func UpdateProject(userName, projectKey, ifMatch string, args *Project, logger *zap.SugaredLogger) (*Project, string, error) {
+	unlock, err := lockResource(resourceKindProject, projectKey)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	product, current, etag, err := getProject(projectKey)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return nil, "", err
+	}
+	if args.ProjectType != "" && args.ProjectType != current.ProjectType {
+		return nil, "", e.ErrInvalidParam.AddDesc("project_type can't be changed")
+	}
+	if args.ProjectName == "" {
+		return nil, "", e.ErrInvalidParam.AddDesc("project_name cannot be empty")
+	}
+
+	product.ProjectName = args.ProjectName
+	product.Description = args.Description
+	product.Public = args.IsPublic
+	product.UpdateBy = userName
+	if err := projectservice.UpdateProductTemplate(projectKey, product, logger); err != nil {
+		return nil, "", err
+	}
+	return GetProject(projectKey, logger)
+}
+
+func DeleteProject(userName, requestID, projectKey, ifMatch string, isDelete bool, logger *zap.SugaredLogger) error {
+	unlock, err := lockResource(resourceKindProject, projectKey)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, _, etag, err := getProject(projectKey)
+	if err != nil {
+		return err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return err
+	}
+	return projectservice.DeleteProjectOpenAPI(userName, requestID, projectKey, isDelete, logger)
+}
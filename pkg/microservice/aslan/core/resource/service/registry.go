@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	systemservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const resourceKindRegistry = "registry"
+
+func getRegistry(id string) (*Registry, string, error) {
+	registry, err := commonrepo.NewRegistryNamespaceColl().Find(&commonrepo.FindRegOps{ID: id})
+	if err != nil {
+		return nil, "", notFoundOr(err, resourceKindRegistry, id)
+	}
+	resp := &Registry{
+		ID:        registry.ID.Hex(),
+		Address:   registry.RegAddr,
+		Provider:  config.RegistryProvider(registry.RegProvider),
+		Namespace: registry.Namespace,
+		Region:    registry.Region,
+		IsDefault: registry.IsDefault,
+		AccessKey: registry.AccessKey,
+	}
+	if registry.AdvancedSetting != nil {
+		resp.EnableTLS = registry.AdvancedSetting.TLSEnabled
+		resp.TLSCert = registry.AdvancedSetting.TLSCert
+	}
+	etag, err := computeETag(resp, registry.SecretKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, etag, nil
+}
+
+func GetRegistry(id string, logger *zap.SugaredLogger) (*Registry, string, error) {
+	return getRegistry(id)
+}
+
+func CreateRegistry(userName string, args *Registry, logger *zap.SugaredLogger) (*Registry, string, error) {
+	req := &systemservice.OpenAPICreateRegistryReq{
+		Address:   args.Address,
+		Provider:  args.Provider,
+		Namespace: args.Namespace,
+		IsDefault: args.IsDefault,
+		AccessKey: args.AccessKey,
+		SecretKey: args.SecretKey,
+		EnableTLS: args.EnableTLS,
+		Region:    args.Region,
+		TLSCert:   args.TLSCert,
+	}
+	if err := req.Validate(); err != nil {
+		return nil, "", e.ErrInvalidParam.AddErr(err)
+	}
+	id, err := systemservice.OpenAPICreateRegistry(userName, req, logger)
+	if err != nil {
+		return nil, "", err
+	}
+	return GetRegistry(id, logger)
+}
+
+// UpdateRegistry updates the registry, the secret key is kept if it's empty.
+func UpdateRegistry(userName, id, ifMatch string, args *Registry, logger *zap.SugaredLogger) (*Registry, string, error) {
+	unlock, err := lockResource(resourceKindRegistry, id)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	_, etag, err := getRegistry(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return nil, "", err
+	}
+
+	registry, err := commonservice.FindRegistryById(id, true, logger)
+	if err != nil {
+		return nil, "", e.ErrGetResource.AddErr(err)
+	}
+	registry.RegAddr = args.Address
+	registry.RegProvider = string(args.Provider)
+	registry.Namespace = args.Namespace
+	registry.Region = args.Region
+	registry.IsDefault = args.IsDefault
+	registry.AccessKey = args.AccessKey
+	if args.SecretKey != "" {
+		registry.SecretKey = args.SecretKey
+	}
+	if registry.AdvancedSetting == nil {
+		registry.AdvancedSetting = &commonmodels.RegistryAdvancedSetting{}
+	}
+	registry.AdvancedSetting.Modified = true
+	registry.AdvancedSetting.TLSEnabled = args.EnableTLS
+	registry.AdvancedSetting.TLSCert = args.TLSCert
+
+	if err := registry.Validate(); err != nil {
+		return nil, "", e.ErrInvalidParam.AddErr(err)
+	}
+	if err := registry.LicenseValidate(); err != nil {
+		return nil, "", err
+	}
+	if err := systemservice.UpdateRegistryNamespace(userName, id, registry, logger); err != nil {
+		return nil, "", err
+	}
+	return GetRegistry(id, logger)
+}
+
+func DeleteRegistry(id, ifMatch string, logger *zap.SugaredLogger) error {
+	unlock, err := lockResource(resourceKindRegistry, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, etag, err := getRegistry(id)
+	if err != nil {
+		return err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return err
+	}
+	return systemservice.DeleteRegistryNamespace(id, true, logger)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+)
+
+// The resources are the configurations managed by the declarative tools like terraform, only the configurable
+// fields are included so that the etag of a resource only changes when its configuration changes.
+
+type Project struct {
+	ProjectKey  string             `json:"project_key"`
+	ProjectName string             `json:"project_name"`
+	Description string             `json:"description"`
+	IsPublic    bool               `json:"is_public"`
+	ProjectType config.ProjectType `json:"project_type"`
+}
+
+type Workflow struct {
+	Name        string `json:"name"`
+	ProjectKey  string `json:"project_key"`
+	DisplayName string `json:"display_name"`
+	// Yaml is the definition of the custom workflow, the same as the one used in the workflow editor
+	Yaml string `json:"yaml"`
+}
+
+type Environment struct {
+	ProjectKey string `json:"project_key"`
+	EnvName    string `json:"env_name"`
+	// Alias can only be set for production environments
+	Alias      string `json:"alias"`
+	Production bool   `json:"production"`
+	ClusterID  string `json:"cluster_id"`
+	Namespace  string `json:"namespace"`
+	RegistryID string `json:"registry_id"`
+}
+
+type Registry struct {
+	ID        string                  `json:"registry_id"`
+	Address   string                  `json:"address"`
+	Provider  config.RegistryProvider `json:"provider"`
+	Namespace string                  `json:"namespace"`
+	Region    string                  `json:"region"`
+	IsDefault bool                    `json:"is_default"`
+	AccessKey string                  `json:"access_key"`
+	// SecretKey is write only, it's never returned
+	SecretKey string `json:"secret_key,omitempty"`
+	EnableTLS bool   `json:"enable_tls"`
+	TLSCert   string `json:"tls_cert"`
+}
+
+type Cluster struct {
+	ID           string   `json:"cluster_id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Production   bool     `json:"production"`
+	Provider     int8     `json:"provider"`
+	Type         string   `json:"type"`
+	ProjectNames []string `json:"project_names"`
+	// KubeConfig is write only, it's never returned
+	KubeConfig string `json:"kube_config,omitempty"`
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	workflowservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const resourceKindWorkflow = "workflow"
+
+func getWorkflow(name string) (*Workflow, string, error) {
+	workflow, err := commonrepo.NewWorkflowV4Coll().Find(name)
+	if err != nil {
+		return nil, "", notFoundOr(err, resourceKindWorkflow, name)
+	}
+	data, err := yaml.Marshal(workflow)
+	if err != nil {
+		return nil, "", e.ErrGetResource.AddErr(err)
+	}
+	resp := &Workflow{
+		Name:        workflow.Name,
+		ProjectKey:  workflow.Project,
+		DisplayName: workflow.DisplayName,
+		Yaml:        string(data),
+	}
+	etag, err := computeETag(resp)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, etag, nil
+}
+
+func GetWorkflow(name string, logger *zap.SugaredLogger) (*Workflow, string, error) {
+	return getWorkflow(name)
+}
+
+// parseWorkflow parses the yaml of the workflow, the name and the project in the yaml are filled by the resource
+// if they are not set, otherwise they must be the same.
+func parseWorkflow(args *Workflow) (*commonmodels.WorkflowV4, error) {
+	workflow := new(commonmodels.WorkflowV4)
+	if err := yaml.Unmarshal([]byte(args.Yaml), workflow); err != nil {
+		return nil, e.ErrInvalidParam.AddErr(err)
+	}
+	if workflow.Name == "" {
+		workflow.Name = args.Name
+	}
+	if workflow.Project == "" {
+		workflow.Project = args.ProjectKey
+	}
+	if args.DisplayName != "" {
+		workflow.DisplayName = args.DisplayName
+	}
+	if workflow.Name != args.Name || workflow.Project != args.ProjectKey {
+		return nil, e.ErrInvalidParam.AddDesc("the name and the project in yaml don't match the resource")
+	}
+	return workflow, nil
+}
+
+func CreateWorkflow(resources *user.AuthorizedResources, userName string, args *Workflow, logger *zap.SugaredLogger) (*Workflow, string, error) {
+	workflow, err := parseWorkflow(args)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := workflowservice.CheckWorkflowRunAsPermission(resources, workflow); err != nil {
+		return nil, "", err
+	}
+	if err := workflowservice.CreateWorkflowV4(userName, workflow, logger); err != nil {
+		return nil, "", err
+	}
+	return getWorkflow(workflow.Name)
+}
+
+// UpdateWorkflow updates the definition of the workflow, the name and the project can't be changed.
+func UpdateWorkflow(resources *user.AuthorizedResources, userName, name, ifMatch string, args *Workflow, logger *zap.SugaredLogger) (*Workflow, string, error) {
+	unlock, err := lockResource(resourceKindWorkflow, name)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	current, etag, err := getWorkflow(name)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return nil, "", err
+	}
+	if args.Name != name || args.ProjectKey != current.ProjectKey {
+		return nil, "", e.ErrInvalidParam.AddDesc("name and project_key can't be changed")
+	}
+	workflow, err := parseWorkflow(args)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := workflowservice.CheckWorkflowRunAsPermission(resources, workflow); err != nil {
+		return nil, "", err
+	}
+	if err := workflowservice.UpdateWorkflowV4(name, userName, workflow, logger); err != nil {
+		return nil, "", err
+	}
+	return getWorkflow(name)
+}
+
+func DeleteWorkflow(name, ifMatch string, logger *zap.SugaredLogger) error {
+	unlock, err := lockResource(resourceKindWorkflow, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, etag, err := getWorkflow(name)
+	if err != nil {
+		return err
+	}
+	if err := checkETag(ifMatch, etag); err != nil {
+		return err
+	}
+	return workflowservice.DeleteWorkflowV4(name, logger)
+}
@@ -35,12 +35,16 @@ import (
 	commonconfig "github.com/koderover/zadig/v2/pkg/config"
 	configbase "github.com/koderover/zadig/v2/pkg/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/instantmessage"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhook"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/workflowcontroller"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/workflowcontroller/jobcontroller"
 	environmentservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
 	multiclusterservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/multicluster/service"
+	projectservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/project/service"
 	releaseplanservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/release_plan/service"
+	statservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/stat/service"
 	systemservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
 	workflowservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
 	hubserverconfig "github.com/koderover/zadig/v2/pkg/microservice/hubserver/config"
@@ -48,6 +52,7 @@ import (
 	mongodb2 "github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	"github.com/koderover/zadig/v2/pkg/tool/cache"
 	"github.com/koderover/zadig/v2/pkg/tool/git/gitlab"
 	gormtool "github.com/koderover/zadig/v2/pkg/tool/gorm"
 	"github.com/koderover/zadig/v2/pkg/tool/klock"
@@ -141,6 +146,8 @@ func Start(ctx context.Context) {
 
 	go multiclusterservice.ClusterApplyUpgrade()
 
+	go systemservice.EnsureManagedIndexes()
+
 	initRsaKey()
 
 	initCron()
@@ -175,9 +182,98 @@ func initCron() {
 		log.Infof("[CRONJOB] gitlab token updated....")
 	})
 
+	Scheduler.Every(30).Minutes().Do(exclusiveCronJob("env-drift-detection", 15*time.Minute, func() {
+		log.Infof("[CRONJOB] detecting environment drift....")
+		environmentservice.RunEnvDriftDetection()
+		log.Infof("[CRONJOB] environment drift detected....")
+	}))
+
+	Scheduler.Every(1).Minutes().Do(exclusiveCronJob("warm-pool-reconcile", 30*time.Second, func() {
+		jobcontroller.ReconcileWarmPools()
+	}))
+
+	Scheduler.Every(1).Hour().Do(exclusiveCronJob("registry-credential-check", 30*time.Minute, func() {
+		log.Infof("[CRONJOB] checking registry credentials....")
+		systemservice.RunRegistryCredentialChecks()
+		log.Infof("[CRONJOB] registry credentials checked....")
+	}))
+
+	Scheduler.Every(1).Hour().Do(exclusiveCronJob("env-resource-usage-sample", 30*time.Minute, func() {
+		log.Infof("[CRONJOB] sampling environment resource usage....")
+		statservice.SampleEnvResourceUsage()
+		log.Infof("[CRONJOB] environment resource usage sampled....")
+	}))
+
+	Scheduler.Every(1).Day().At("10:00").Do(exclusiveCronJob("cert-expiry-monitor", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] checking certificate expiry....")
+		environmentservice.RunCertExpiryMonitors()
+		log.Infof("[CRONJOB] certificate expiry checked....")
+	}))
+
+	Scheduler.Every(1).Day().At("01:00").Do(exclusiveCronJob("project-resource-usage-rollup", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] rolling up project resource usage....")
+		statservice.RollupProjectResourceUsage()
+		log.Infof("[CRONJOB] project resource usage rolled up....")
+	}))
+
+	Scheduler.Every(1).Day().At("02:00").Do(exclusiveCronJob("env-snapshot", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] capturing environment snapshots....")
+		environmentservice.RunScheduledEnvSnapshots()
+		log.Infof("[CRONJOB] environment snapshots captured....")
+	}))
+
+	Scheduler.Every(1).Day().At("03:00").Do(exclusiveCronJob("build-cache-retention", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] evicting stale build caches....")
+		systemservice.HandleBuildCacheRetention()
+		log.Infof("[CRONJOB] stale build caches evicted....")
+	}))
+
+	Scheduler.Every(1).Day().At("03:30").Do(exclusiveCronJob("workflow-task-cold-storage", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] moving old workflow tasks into cold storage....")
+		systemservice.HandleWorkflowTaskColdStorage()
+		log.Infof("[CRONJOB] old workflow tasks moved into cold storage....")
+	}))
+
+	Scheduler.Every(1).Day().At("04:00").Do(exclusiveCronJob("stale-owner-check", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] checking stale resource owners....")
+		projectservice.RunStaleOwnerCheck()
+		log.Infof("[CRONJOB] stale resource owners checked....")
+	}))
+
+	Scheduler.Every(1).Day().At("04:30").Do(exclusiveCronJob("schedule-decision-clean", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] cleaning outdated scheduling decisions....")
+		workflowcontroller.CleanScheduleDecisions()
+		log.Infof("[CRONJOB] outdated scheduling decisions cleaned....")
+	}))
+
+	Scheduler.Every(1).Day().At("04:45").Do(exclusiveCronJob("notification-delivery-clean", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] cleaning outdated notification deliveries....")
+		instantmessage.CleanNotificationDeliveries()
+		log.Infof("[CRONJOB] outdated notification deliveries cleaned....")
+	}))
+
+	Scheduler.Every(1).Day().At("05:00").Do(exclusiveCronJob("image-retention", 12*time.Hour, func() {
+		log.Infof("[CRONJOB] deleting stale image tags by retention policies....")
+		systemservice.RunImageRetentionPolicies()
+		log.Infof("[CRONJOB] stale image tags deleted....")
+	}))
+
 	Scheduler.StartAsync()
 }
 
+// exclusiveCronJob wraps the cron job so that only one of the aslan replicas runs it each time it's triggered.
+// The lock isn't released when the job is done but expires after the given duration, which should be shorter
+// than the interval of the job, so the replicas triggered a bit later skip the run as well.
+func exclusiveCronJob(name string, expiry time.Duration, job func()) func() {
+	return func() {
+		lock := cache.NewRedisLockWithExpiry(fmt.Sprintf("cronjob:%s", name), expiry)
+		if err := lock.TryLock(); err != nil {
+			return
+		}
+		job()
+	}
+}
+
 func initService() {
 	errors := new(multierror.Error)
 
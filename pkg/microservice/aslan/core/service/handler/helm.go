@@ -485,3 +485,125 @@ func CreateOrUpdateBulkHelmServices(c *gin.Context) {
 
 	ctx.Resp, ctx.Err = svcservice.CreateOrUpdateBulkHelmService(projectKey, args, false, ctx.Logger)
 }
+
+// @Summary Get Helm Values Schema
+// @Description Get the json schema used to validate the values of the helm service
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	productName		path		string							true	"project name"
+// @Param 	serviceName		path		string							true	"service name"
+// @Param 	production		query		bool							false	"is production service"
+// @Success 200 			{object} 	svcservice.HelmValuesSchemaResp
+// @Router /api/aslan/service/helm/{productName}/{serviceName}/valuesSchema [get]
+func GetHelmValuesSchema(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Param("productName")
+	production := c.Query("production") == "true"
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if production {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].ProductionService.View {
+				ctx.UnAuthorized = true
+				return
+			}
+		} else {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].Service.View {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	if production {
+		err = commonutil.CheckZadigProfessionalLicense()
+		if err != nil {
+			ctx.Err = err
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = svcservice.GetHelmValuesSchema(projectKey, c.Param("serviceName"), production, ctx.Logger)
+}
+
+// @Summary Update Helm Values Schema
+// @Description Use the values.schema.json in the chart, a custom json schema or nothing to validate the values of the helm service
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	serviceName		path		string								true	"service name"
+// @Param 	projectName		query		string								true	"project name"
+// @Param 	production		query		bool								false	"is production service"
+// @Param 	body 			body 		svcservice.UpdateHelmValuesSchemaArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/service/helm/{serviceName}/valuesSchema [put]
+func UpdateHelmValuesSchema(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	production := c.Query("production") == "true"
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if production {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].ProductionService.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		} else {
+			if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectKey].Service.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	if production {
+		err = commonutil.CheckZadigProfessionalLicense()
+		if err != nil {
+			ctx.Err = err
+			return
+		}
+	}
+
+	args := new(svcservice.UpdateHelmValuesSchemaArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	serviceName := c.Param("serviceName")
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "更新", "项目管理-服务values schema", fmt.Sprintf("服务名称:%s", serviceName), "", ctx.Logger)
+
+	ctx.Err = svcservice.UpdateHelmValuesSchema(projectKey, serviceName, production, args, ctx.UserName, ctx.Logger)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	svcservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/service/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// checkHelmReleaseImportPermission checks whether the user is allowed to create envs in the project,
+// since importing helm releases may create envs and services
+func checkHelmReleaseImportPermission(ctx *internalhandler.Context, projectKey string, production bool) bool {
+	if !ctx.Resources.IsSystemAdmin {
+		projectInfo, ok := ctx.Resources.ProjectAuthInfo[projectKey]
+		if !ok {
+			ctx.UnAuthorized = true
+			return false
+		}
+		if production {
+			if !projectInfo.IsProjectAdmin && !projectInfo.ProductionEnv.Create {
+				ctx.UnAuthorized = true
+				return false
+			}
+		} else {
+			if !projectInfo.IsProjectAdmin && !projectInfo.Env.Create {
+				ctx.UnAuthorized = true
+				return false
+			}
+		}
+	}
+
+	// releases are imported with the import deploy strategy, which requires the professional license
+	if err := commonutil.CheckZadigProfessionalLicense(); err != nil {
+		ctx.Err = err
+		return false
+	}
+	return true
+}
+
+// @Summary List Importable Helm Releases
+// @Description List the deployed helm releases in the namespace and the services they are mapped to
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string								true	"project name"
+// @Param 	clusterId		query		string								true	"cluster id"
+// @Param 	namespace		query		string								true	"namespace"
+// @Param 	production		query		bool								false	"is production"
+// @Success 200 			{array} 	svcservice.ImportableHelmRelease
+// @Router /api/aslan/service/helm/releases/importable [get]
+func ListImportableHelmReleases(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, production := c.Query("projectName"), c.Query("production") == "true"
+	clusterID, namespace := c.Query("clusterId"), c.Query("namespace")
+	if projectKey == "" || clusterID == "" || namespace == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName, clusterId and namespace can't be empty")
+		return
+	}
+
+	if !checkHelmReleaseImportPermission(ctx, projectKey, production) {
+		return
+	}
+
+	ctx.Resp, ctx.Err = svcservice.ListImportableHelmReleases(projectKey, clusterID, namespace, production, ctx.Logger)
+}
+
+// @Summary Import Helm Releases
+// @Description Import the existing helm releases into a new or existing environment without redeploying them
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string								true	"project name"
+// @Param 	production		query		bool								false	"is production"
+// @Param 	body 			body 		svcservice.ImportHelmReleasesArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/service/helm/releases/import [post]
+func ImportHelmReleases(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey, production := c.Query("projectName"), c.Query("production") == "true"
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can't be empty")
+		return
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args := new(svcservice.ImportHelmReleasesArgs)
+	if err = json.Unmarshal(data, args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(data))
+
+	internalhandler.InsertDetailedOperationLog(c, ctx.UserName, projectKey, setting.OperationSceneEnv, "导入", "环境-Helm Release", args.EnvName, string(data), ctx.Logger, args.EnvName)
+
+	if !checkHelmReleaseImportPermission(ctx, projectKey, production) {
+		return
+	}
+
+	ctx.Err = svcservice.ImportHelmReleases(projectKey, ctx.UserName, ctx.RequestID, args, production, ctx.Logger)
+}
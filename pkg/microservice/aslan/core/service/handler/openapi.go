@@ -12,6 +12,14 @@ import (
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 )
 
+// @Summary OpenAPI Load Service From Yaml Template
+// @Description OpenAPI Load Service From Yaml Template
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	svcservice.OpenAPILoadServiceFromYamlTemplateReq	true	"body"
+// @Success 200
+// @Router /openapi/service/template/load/yaml [post]
 func LoadServiceFromYamlTemplateOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -53,6 +61,14 @@ func LoadServiceFromYamlTemplateOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.OpenAPILoadServiceFromYamlTemplate(ctx.UserName, req, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Load Production Service From Yaml Template
+// @Description OpenAPI Load Production Service From Yaml Template
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	svcservice.OpenAPILoadServiceFromYamlTemplateReq	true	"body"
+// @Success 200
+// @Router /openapi/service/template/production/load/yaml [post]
 func LoadProductionServiceFromYamlTemplateOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -81,6 +97,15 @@ func LoadProductionServiceFromYamlTemplateOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.OpenAPILoadServiceFromYamlTemplate(ctx.UserName, req, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Raw Yaml Services
+// @Description OpenAPI Create Raw Yaml Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	svcservice.OpenAPICreateYamlServiceReq	true	"body"
+// @Success 200
+// @Router /openapi/service/yaml/raw [post]
 func CreateRawYamlServicesOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -126,6 +151,15 @@ func CreateRawYamlServicesOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.CreateRawYamlServicesOpenAPI(ctx.UserName, projectKey, req, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Raw Production Yaml Services
+// @Description OpenAPI Create Raw Production Yaml Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	svcservice.OpenAPICreateYamlServiceReq	true	"body"
+// @Success 200
+// @Router /openapi/service/yaml/production/raw [post]
 func CreateRawProductionYamlServicesOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -178,6 +212,16 @@ func CreateRawProductionYamlServicesOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.CreateRawYamlServicesOpenAPI(ctx.UserName, projectKey, req, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Service Config
+// @Description OpenAPI Update Service Config
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	svcservice.OpenAPIUpdateServiceConfigArgs	true	"body"
+// @Success 200
+// @Router /openapi/service/yaml/{name} [put]
 func UpdateServiceConfigOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -219,6 +263,16 @@ func UpdateServiceConfigOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.OpenAPIUpdateServiceConfig(ctx.UserName, args, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Production Service Config
+// @Description OpenAPI Update Production Service Config
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	svcservice.OpenAPIUpdateServiceConfigArgs	true	"body"
+// @Success 200
+// @Router /openapi/service/yaml/production/{name} [put]
 func UpdateProductionServiceConfigOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -266,6 +320,16 @@ func UpdateProductionServiceConfigOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.OpenAPIProductionUpdateServiceConfig(ctx.UserName, args, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Service Variable
+// @Description OpenAPI Update Service Variable
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	svcservice.OpenAPIUpdateServiceVariableRequest	true	"body"
+// @Success 200
+// @Router /openapi/service/yaml/{name}/variable [put]
 func UpdateServiceVariableOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -311,6 +375,16 @@ func UpdateServiceVariableOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.OpenAPIUpdateServiceVariable(ctx.UserName, projectKey, serviceName, req, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Production Service Variable
+// @Description OpenAPI Update Production Service Variable
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	svcservice.OpenAPIUpdateServiceVariableRequest	true	"body"
+// @Success 200
+// @Router /openapi/service/yaml/production/{name}/variable [put]
 func UpdateProductionServiceVariableOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -362,6 +436,15 @@ func UpdateProductionServiceVariableOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.OpenAPIUpdateProductionServiceVariable(ctx.UserName, projectKey, serviceName, req, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Yaml Services
+// @Description OpenAPI Delete Yaml Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/service/yaml/{name} [delete]
 func DeleteYamlServicesOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -401,6 +484,15 @@ func DeleteYamlServicesOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.DeleteServiceTemplate(serviceName, "k8s", projectKey, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Production Services
+// @Description OpenAPI Delete Production Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/service/yaml/production/{name} [delete]
 func DeleteProductionServicesOpenAPI(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -445,6 +537,15 @@ func DeleteProductionServicesOpenAPI(c *gin.Context) {
 	ctx.Err = svcservice.DeleteServiceTemplate(serviceName, "k8s", projectKey, false, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Yaml Service
+// @Description OpenAPI Get Yaml Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	svcservice.OpenAPIGetYamlServiceResp
+// @Router /openapi/service/yaml/{name} [get]
 func GetYamlServiceOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -464,6 +565,15 @@ func GetYamlServiceOpenAPI(c *gin.Context) {
 	ctx.Resp, ctx.Err = svcservice.OpenAPIGetYamlService(projectKey, serviceName, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Production Yaml Service
+// @Description OpenAPI Get Production Yaml Service
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	svcservice.OpenAPIGetYamlServiceResp
+// @Router /openapi/service/yaml/production/{name} [get]
 func GetProductionYamlServiceOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -489,6 +599,14 @@ func GetProductionYamlServiceOpenAPI(c *gin.Context) {
 	ctx.Resp, ctx.Err = svcservice.GetProductionYamlServiceOpenAPI(projectKey, serviceName, ctx.Logger)
 }
 
+// @Summary OpenAPI List Yaml Services
+// @Description OpenAPI List Yaml Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	svcservice.OpenAPIServiceBrief
+// @Router /openapi/service/yaml/services [get]
 func ListYamlServicesOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -507,6 +625,14 @@ func ListYamlServicesOpenAPI(c *gin.Context) {
 	ctx.Resp = resp
 }
 
+// @Summary OpenAPI List Production Yaml Services
+// @Description OpenAPI List Production Yaml Services
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	svcservice.OpenAPIServiceBrief
+// @Router /openapi/service/yaml/production/services [get]
 func ListProductionYamlServicesOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -37,11 +37,15 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		helm.GET("/:productName/:serviceName/serviceModule", GetHelmServiceModule)
 		helm.GET("/:productName/:serviceName/filePath", GetFilePath)
 		helm.GET("/:productName/:serviceName/fileContent", GetFileContent)
+		helm.GET("/:productName/:serviceName/valuesSchema", GetHelmValuesSchema)
 		helm.PUT("/:serviceName/file", UpdateFileContent)
+		helm.PUT("/:serviceName/valuesSchema", UpdateHelmValuesSchema)
 		helm.POST("/services", CreateOrUpdateHelmService)
 		helm.PUT("/services", UpdateHelmService)
 		helm.POST("/services/bulk", CreateOrUpdateBulkHelmServices)
 		helm.PUT("/services/releaseNaming", HelmReleaseNaming)
+		helm.GET("/releases/importable", ListImportableHelmReleases)
+		helm.POST("/releases/import", ImportHelmReleases)
 	}
 
 	k8s := router.Group("services")
@@ -51,8 +55,12 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		k8s.GET("/:name", GetServiceTemplateOption)
 		k8s.POST("", GetServiceTemplateProductName, CreateServiceTemplate)
 		k8s.PUT("/:name/variable", UpdateServiceVariable)
+		k8s.PUT("/:name/firstDeployHook", UpdateServiceFirstDeployHook)
+		k8s.PUT("/:name/deployWindow", UpdateServiceDeployWindow)
+		k8s.PUT("/:name/lockout", UpdateServiceLockout)
 		k8s.PUT("", UpdateServiceTemplate)
 		k8s.PUT("/yaml/validator", YamlValidator)
+		k8s.PUT("/yaml/lint", LintYaml)
 		k8s.DELETE("/:name/:type", DeleteServiceTemplate)
 		k8s.GET("/:name/environments/deployable", GetDeployableEnvs)
 		k8s.POST("/variable/convert", ConvertVaraibleKVAndYaml)
@@ -82,7 +82,11 @@ func ListServiceTemplate(c *gin.Context) {
 		}
 	}
 
-	ctx.Resp, ctx.Err = commonservice.ListServiceTemplate(projectName, production, ctx.Logger)
+	services, err := commonservice.ListServiceTemplate(projectName, production, ctx.Logger)
+	if err == nil && c.Query("labelSelector") != "" {
+		services, err = svcservice.FilterServiceTemplatesByLabelSelector(projectName, c.Query("labelSelector"), production, services)
+	}
+	ctx.Resp, ctx.Err = services, err
 }
 
 func ListWorkloadTemplate(c *gin.Context) {
@@ -277,6 +281,7 @@ type createServiceTemplateRequest struct {
 	Yaml               string                           `json:"yaml" binding:"required"`
 	VariableYaml       string                           `json:"variable_yaml"`
 	ServiceVariableKVs []*commontypes.ServiceVariableKV `json:"service_variable_kvs"`
+	Owner              *commonmodels.ResourceOwner      `json:"owner"`
 }
 
 // @Summary Create service template
@@ -371,6 +376,11 @@ func CreateServiceTemplate(c *gin.Context) {
 	svc.VariableYaml = args.VariableYaml
 	svc.ServiceVariableKVs = args.ServiceVariableKVs
 	svc.Yaml = args.Yaml
+	svc.Owner, err = commonservice.EnsureResourceOwner(args.Owner, ctx.UserID)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
 
 	ctx.Resp, ctx.Err = svcservice.CreateServiceTemplate(ctx.UserName, svc, force, production, ctx.Logger)
 }
@@ -493,6 +503,214 @@ func UpdateServiceVariable(c *gin.Context) {
 	ctx.Err = svcservice.UpdateServiceVariables(servceTmplObjectargs, production)
 }
 
+// @Summary Update Service First Deploy Hook
+// @Description Update the hook which runs once when the service is deployed into an env for the first time
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	name		path		string							true	"service name"
+// @Param 	projectName	query		string							true	"project name"
+// @Param 	production	query		bool							true	"is production"
+// @Param 	body  		body 		commonmodels.FirstDeployHook 	true 	"body"
+// @Success 200
+// @Router /api/aslan/service/services/{name}/firstDeployHook [put]
+func UpdateServiceFirstDeployHook(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	hook := new(commonmodels.FirstDeployHook)
+	if err := c.ShouldBindJSON(hook); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	production := c.Query("production") == "true"
+	detail := "项目管理-服务首次部署钩子"
+	if production {
+		detail = "项目管理-生产服务首次部署钩子"
+	}
+
+	// authorization
+	projectName := c.Query("projectName")
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectName]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if production {
+			if !ctx.Resources.ProjectAuthInfo[projectName].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectName].ProductionService.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		} else {
+			if !ctx.Resources.ProjectAuthInfo[projectName].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectName].Service.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	if production {
+		err = commonutil.CheckZadigProfessionalLicense()
+		if err != nil {
+			ctx.Err = err
+			return
+		}
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectName, "更新", detail, fmt.Sprintf("服务名称:%s", c.Param("name")), "", ctx.Logger)
+
+	ctx.Err = svcservice.UpdateServiceFirstDeployHook(projectName, c.Param("name"), hook, production)
+}
+
+// @Summary Update Service Deploy Window
+// @Description Update the deploy window of the service, deployments out of the window require the approval of the service owner
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	name		path		string							true	"service name"
+// @Param 	projectName	query		string							true	"project name"
+// @Param 	production	query		bool							true	"is production"
+// @Param 	body  		body 		commonmodels.DeployWindow 		true 	"body"
+// @Success 200
+// @Router /api/aslan/service/services/{name}/deployWindow [put]
+func UpdateServiceDeployWindow(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	window := new(commonmodels.DeployWindow)
+	if err := c.ShouldBindJSON(window); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	production := c.Query("production") == "true"
+	detail := "项目管理-服务部署窗口"
+	if production {
+		detail = "项目管理-生产服务部署窗口"
+	}
+
+	// authorization
+	projectName := c.Query("projectName")
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectName]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if production {
+			if !ctx.Resources.ProjectAuthInfo[projectName].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectName].ProductionService.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		} else {
+			if !ctx.Resources.ProjectAuthInfo[projectName].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectName].Service.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	if production {
+		err = commonutil.CheckZadigProfessionalLicense()
+		if err != nil {
+			ctx.Err = err
+			return
+		}
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectName, "更新", detail, fmt.Sprintf("服务名称:%s", c.Param("name")), "", ctx.Logger)
+
+	ctx.Err = svcservice.UpdateServiceDeployWindow(projectName, c.Param("name"), window, production)
+}
+
+// @Summary Update Service Lockout
+// @Description Lock out or unlock the service, new build and deploy jobs of a locked out service are rejected
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	name		path		string							true	"service name"
+// @Param 	projectName	query		string							true	"project name"
+// @Param 	production	query		bool							true	"is production"
+// @Param 	body  		body 		commonmodels.ServiceLockout 	true 	"body"
+// @Success 200
+// @Router /api/aslan/service/services/{name}/lockout [put]
+func UpdateServiceLockout(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	lockout := new(commonmodels.ServiceLockout)
+	if err := c.ShouldBindJSON(lockout); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	production := c.Query("production") == "true"
+	detail := "项目管理-服务锁定"
+	if production {
+		detail = "项目管理-生产服务锁定"
+	}
+
+	// authorization
+	projectName := c.Query("projectName")
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectName]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+		if production {
+			if !ctx.Resources.ProjectAuthInfo[projectName].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectName].ProductionService.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		} else {
+			if !ctx.Resources.ProjectAuthInfo[projectName].IsProjectAdmin &&
+				!ctx.Resources.ProjectAuthInfo[projectName].Service.Edit {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	if production {
+		err = commonutil.CheckZadigProfessionalLicense()
+		if err != nil {
+			ctx.Err = err
+			return
+		}
+	}
+
+	action := "解除锁定"
+	if lockout.Enabled {
+		action = "锁定"
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectName, action, detail, fmt.Sprintf("服务名称:%s", c.Param("name")), "", ctx.Logger)
+
+	ctx.Err = svcservice.UpdateServiceLockout(projectName, c.Param("name"), ctx.UserName, lockout, production)
+}
+
 func UpdateServiceHealthCheckStatus(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -552,6 +770,26 @@ func YamlValidator(c *gin.Context) {
 	ctx.Resp = resp
 }
 
+// @Summary Lint K8s Yaml
+// @Description Check the yaml against the schema of the target clusters and the optional kube-score/polaris checks
+// @Tags 	service
+// @Accept 	json
+// @Produce json
+// @Param 	body 		body 		svcservice.LintYamlReq 	true 	"body"
+// @Success 200 		{array} 	lint.Finding
+// @Router /api/aslan/service/services/yaml/lint [put]
+func LintYaml(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	args := new(svcservice.LintYamlReq)
+	if err := c.BindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid yaml args")
+		return
+	}
+	ctx.Resp, ctx.Err = svcservice.LintYaml(args, ctx.Logger)
+}
+
 func HelmReleaseNaming(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -0,0 +1,443 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/otiai10/copy"
+	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	fsservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/fs"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/environment/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/helmclient"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+type ImportableHelmRelease struct {
+	ReleaseName  string `json:"release_name"`
+	ChartName    string `json:"chart_name"`
+	ChartVersion string `json:"chart_version"`
+	AppVersion   string `json:"app_version"`
+	Revision     int    `json:"revision"`
+	UpdateTime   int64  `json:"update_time"`
+	// ServiceName is the service the release is mapped to by default,
+	// a new service will be created from the chart of the release if ServiceExists is false
+	ServiceName   string `json:"service_name"`
+	ServiceExists bool   `json:"service_exists"`
+	// ImportedBy is the env which has already registered the release, such releases can't be imported again
+	ImportedBy string `json:"imported_by,omitempty"`
+}
+
+type HelmReleaseImportItem struct {
+	ReleaseName string `json:"release_name"`
+	ServiceName string `json:"service_name"`
+}
+
+type ImportHelmReleasesArgs struct {
+	EnvName    string                   `json:"env_name"`
+	ClusterID  string                   `json:"cluster_id"`
+	Namespace  string                   `json:"namespace"`
+	RegistryID string                   `json:"registry_id"`
+	Releases   []*HelmReleaseImportItem `json:"releases"`
+}
+
+// ListImportableHelmReleases scans the namespace for the deployed helm releases and maps them to the services of the project
+func ListImportableHelmReleases(projectName, clusterID, namespace string, production bool, log *zap.SugaredLogger) ([]*ImportableHelmRelease, error) {
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return nil, e.ErrListImportableHelmReleases.AddErr(err)
+	}
+	if !project.IsHelmProduct() {
+		return nil, e.ErrListImportableHelmReleases.AddDesc("importing helm releases is only supported by helm projects")
+	}
+
+	helmClient, err := helmclient.NewClientFromNamespace(clusterID, namespace)
+	if err != nil {
+		return nil, e.ErrListImportableHelmReleases.AddErr(err)
+	}
+	releases, err := helmClient.ListDeployedReleases()
+	if err != nil {
+		return nil, e.ErrListImportableHelmReleases.AddErr(fmt.Errorf("failed to list helm releases in namespace %s: %s", namespace, err))
+	}
+
+	templateSvcs, err := repository.ListMaxRevisionsServices(projectName, production)
+	if err != nil {
+		return nil, e.ErrListImportableHelmReleases.AddErr(err)
+	}
+	importedBy, err := importedHelmReleases(clusterID, namespace)
+	if err != nil {
+		return nil, e.ErrListImportableHelmReleases.AddErr(err)
+	}
+
+	resp := make([]*ImportableHelmRelease, 0, len(releases))
+	for _, rel := range releases {
+		if rel.Chart == nil || rel.Chart.Metadata == nil {
+			log.Warnf("chart of release %s in namespace %s is missing", rel.Name, namespace)
+			continue
+		}
+		item := &ImportableHelmRelease{
+			ReleaseName:  rel.Name,
+			ChartName:    rel.Chart.Metadata.Name,
+			ChartVersion: rel.Chart.Metadata.Version,
+			AppVersion:   rel.Chart.Metadata.AppVersion,
+			Revision:     rel.Version,
+			ServiceName:  rel.Name,
+			ImportedBy:   importedBy[rel.Name],
+		}
+		if rel.Info != nil {
+			item.UpdateTime = rel.Info.LastDeployed.Unix()
+		}
+		if svc := matchHelmReleaseService(rel, templateSvcs); svc != nil {
+			item.ServiceName = svc.ServiceName
+			item.ServiceExists = true
+		}
+		resp = append(resp, item)
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].ReleaseName < resp[j].ReleaseName
+	})
+	return resp, nil
+}
+
+// ImportHelmReleases registers the existing helm releases into a new or existing env without redeploying them,
+// services are created from the charts of the releases if they don't exist in the project.
+func ImportHelmReleases(projectName, username, requestID string, args *ImportHelmReleasesArgs, production bool, log *zap.SugaredLogger) error {
+	if args.EnvName == "" || len(args.Releases) == 0 {
+		return e.ErrImportHelmReleases.AddDesc("env name and releases can't be empty")
+	}
+
+	project, err := templaterepo.NewProductColl().Find(projectName)
+	if err != nil {
+		return e.ErrImportHelmReleases.AddErr(err)
+	}
+	if !project.IsHelmProduct() {
+		return e.ErrImportHelmReleases.AddDesc("importing helm releases is only supported by helm projects")
+	}
+
+	env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{
+		Name:       projectName,
+		EnvName:    args.EnvName,
+		Production: util.GetBoolPointer(production),
+	})
+	if err == nil {
+		if env.ClusterID != args.ClusterID || env.Namespace != args.Namespace {
+			return e.ErrImportHelmReleases.AddDesc(fmt.Sprintf("env %s is not deployed in namespace %s of the cluster", args.EnvName, args.Namespace))
+		}
+	} else {
+		env = nil
+	}
+
+	importedBy, err := importedHelmReleases(args.ClusterID, args.Namespace)
+	if err != nil {
+		return e.ErrImportHelmReleases.AddErr(err)
+	}
+
+	helmClient, err := helmclient.NewClientFromNamespace(args.ClusterID, args.Namespace)
+	if err != nil {
+		return e.ErrImportHelmReleases.AddErr(err)
+	}
+
+	releases := make(map[string]*release.Release)
+	serviceNames := sets.NewString()
+	for _, item := range args.Releases {
+		if item.ServiceName == "" {
+			item.ServiceName = item.ReleaseName
+		}
+		if serviceNames.Has(item.ServiceName) {
+			return e.ErrImportHelmReleases.AddDesc(fmt.Sprintf("service %s is mapped to multiple releases", item.ServiceName))
+		}
+		serviceNames.Insert(item.ServiceName)
+		if envName, ok := importedBy[item.ReleaseName]; ok {
+			return e.ErrImportHelmReleases.AddDesc(fmt.Sprintf("release %s is already registered in env %s", item.ReleaseName, envName))
+		}
+		if env != nil {
+			if _, ok := env.GetServiceMap()[item.ServiceName]; ok {
+				return e.ErrImportHelmReleases.AddDesc(fmt.Sprintf("service %s already exists in env %s", item.ServiceName, args.EnvName))
+			}
+		}
+
+		rel, err := helmClient.GetRelease(item.ReleaseName)
+		if err != nil {
+			return e.ErrImportHelmReleases.AddErr(fmt.Errorf("failed to get release %s: %s", item.ReleaseName, err))
+		}
+		if rel.Info == nil || rel.Info.Status != release.StatusDeployed || rel.Chart == nil || rel.Chart.Metadata == nil {
+			return e.ErrImportHelmReleases.AddDesc(fmt.Sprintf("release %s is not deployed", item.ReleaseName))
+		}
+		releases[item.ReleaseName] = rel
+	}
+
+	deployStrategy := make(map[string]string)
+	if env != nil && env.ServiceDeployStrategy != nil {
+		deployStrategy = env.ServiceDeployStrategy
+	}
+	productServices := make([]*commonmodels.ProductService, 0, len(args.Releases))
+	for _, item := range args.Releases {
+		rel := releases[item.ReleaseName]
+		svcTmpl, err := ensureHelmReleaseService(projectName, item.ServiceName, username, requestID, args, rel, production, log)
+		if err != nil {
+			return e.ErrImportHelmReleases.AddErr(err)
+		}
+
+		prodSvc, err := buildImportedHelmProductService(projectName, svcTmpl, rel)
+		if err != nil {
+			return e.ErrImportHelmReleases.AddErr(err)
+		}
+		productServices = append(productServices, prodSvc)
+		// the release is imported as it is, it won't be upgraded until the service is deployed by zadig
+		deployStrategy = commonutil.SetServiceDeployStrategyImport(deployStrategy, item.ServiceName)
+	}
+
+	if env == nil {
+		err = service.CreateProduct(username, requestID, &service.ProductCreateArg{Product: &commonmodels.Product{
+			ProductName:           projectName,
+			Source:                setting.SourceFromHelm,
+			ClusterID:             args.ClusterID,
+			RegistryID:            args.RegistryID,
+			EnvName:               args.EnvName,
+			Namespace:             args.Namespace,
+			UpdateBy:              username,
+			IsExisted:             true,
+			Production:            production,
+			Services:              [][]*commonmodels.ProductService{productServices},
+			ServiceDeployStrategy: deployStrategy,
+		}}, log)
+		if err != nil {
+			return e.ErrImportHelmReleases.AddErr(err)
+		}
+		return nil
+	}
+
+	if len(env.Services) == 0 {
+		env.Services = [][]*commonmodels.ProductService{{}}
+	}
+	groupIndex := len(env.Services) - 1
+	env.Services[groupIndex] = append(env.Services[groupIndex], productServices...)
+	if err := commonrepo.NewProductColl().UpdateGroup(args.EnvName, projectName, groupIndex, env.Services[groupIndex]); err != nil {
+		return e.ErrImportHelmReleases.AddErr(err)
+	}
+	if err := commonrepo.NewProductColl().UpdateDeployStrategy(args.EnvName, projectName, deployStrategy); err != nil {
+		return e.ErrImportHelmReleases.AddErr(err)
+	}
+	return nil
+}
+
+// importedHelmReleases returns the releases registered by the envs in the namespace, release name => env name
+func importedHelmReleases(clusterID, namespace string) (map[string]string, error) {
+	envs, err := commonrepo.NewProductColl().ListEnvByNamespace(clusterID, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list envs in namespace %s: %s", namespace, err)
+	}
+	resp := make(map[string]string)
+	for _, env := range envs {
+		for _, svc := range env.GetSvcList() {
+			if svc.ReleaseName != "" {
+				resp[svc.ReleaseName] = fmt.Sprintf("%s/%s", env.ProductName, env.EnvName)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// matchHelmReleaseService returns the helm service named after the release, or the only service using the same chart
+func matchHelmReleaseService(rel *release.Release, templateSvcs []*commonmodels.Service) *commonmodels.Service {
+	var matched []*commonmodels.Service
+	for _, svc := range templateSvcs {
+		if svc.Type != setting.HelmDeployType || svc.HelmChart == nil {
+			continue
+		}
+		if svc.ServiceName == rel.Name {
+			return svc
+		}
+		if svc.HelmChart.Name == rel.Chart.Metadata.Name {
+			matched = append(matched, svc)
+		}
+	}
+	if len(matched) == 1 {
+		return matched[0]
+	}
+	return nil
+}
+
+// ensureHelmReleaseService returns the service the release is mapped to, the service is created from the chart of the release if not exists
+func ensureHelmReleaseService(projectName, serviceName, username, requestID string, args *ImportHelmReleasesArgs, rel *release.Release, production bool, log *zap.SugaredLogger) (*commonmodels.Service, error) {
+	svcTmpl, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+		ProductName:         projectName,
+		ServiceName:         serviceName,
+		ExcludeStatus:       setting.ProductStatusDeleting,
+		IgnoreNoDocumentErr: true,
+	}, production)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseNaming := setting.DefaultReleaseNaming
+	if svcTmpl != nil {
+		if svcTmpl.Type != setting.HelmDeployType || svcTmpl.HelmChart == nil {
+			return nil, fmt.Errorf("service %s is not a helm service", serviceName)
+		}
+		if svcTmpl.HelmChart.Name != rel.Chart.Metadata.Name {
+			return nil, fmt.Errorf("release %s uses chart %s while service %s uses chart %s", rel.Name, rel.Chart.Metadata.Name, serviceName, svcTmpl.HelmChart.Name)
+		}
+		releaseNaming = svcTmpl.GetReleaseNaming()
+	}
+	// the release name generated by zadig must be the same as the existing one, otherwise a new release will be installed on the next deployment
+	if releaseName := util.GeneReleaseName(releaseNaming, projectName, args.Namespace, args.EnvName, serviceName); releaseName != rel.Name {
+		return nil, fmt.Errorf("release name of service %s in env %s is %s rather than %s", serviceName, args.EnvName, releaseName, rel.Name)
+	}
+	if svcTmpl != nil {
+		return svcTmpl, nil
+	}
+
+	return createHelmServiceFromRelease(projectName, serviceName, username, requestID, rel, production, log)
+}
+
+// createHelmServiceFromRelease creates a helm service with the chart stored in the release,
+// the default values of the chart are used as the values of the service.
+func createHelmServiceFromRelease(projectName, serviceName, username, requestID string, rel *release.Release, production bool, logger *zap.SugaredLogger) (*commonmodels.Service, error) {
+	tmpDir, err := os.MkdirTemp("", "helm-release-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := chartutil.SaveDir(rel.Chart, tmpDir); err != nil {
+		return nil, fmt.Errorf("failed to save chart of release %s: %s", rel.Name, err)
+	}
+	// the raw files are not kept in the release, so values.yaml is restored from the parsed values
+	valuesYAML, err := yaml.Marshal(rel.Chart.Values)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, rel.Chart.Name(), setting.ValuesYaml), valuesYAML, 0644); err != nil {
+		return nil, err
+	}
+
+	to := filepath.Join(config.LocalServicePath(projectName, serviceName, production), serviceName)
+	if err := os.RemoveAll(to); err != nil {
+		logger.Errorf("Failed to remove dir %s, err: %s", to, err)
+		return nil, err
+	}
+	if err := copy.Copy(filepath.Join(tmpDir, rel.Chart.Name()), to); err != nil {
+		logger.Errorf("Failed to copy chart of release %s to %s, err: %s", rel.Name, to, err)
+		return nil, err
+	}
+
+	rev, err := getNextServiceRevision(projectName, serviceName, production)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service next revision, service %s: %s", serviceName, err)
+	}
+	if err = copyChartRevision(projectName, serviceName, rev, production); err != nil {
+		return nil, fmt.Errorf("failed to copy chart info, service %s: %s", serviceName, err)
+	}
+
+	// clear files from both s3 and local when error occurred in next stages
+	defer func() {
+		if err != nil {
+			clearChartFiles(projectName, serviceName, rev, production, logger)
+		}
+	}()
+
+	fsTree := os.DirFS(config.LocalServicePath(projectName, serviceName, production))
+	serviceS3Base := config.ObjectStorageServicePath(projectName, serviceName, production)
+	if err = fsservice.ArchiveAndUploadFilesToS3(fsTree, []string{serviceName, fmt.Sprintf("%s-%d", serviceName, rev)}, serviceS3Base, logger); err != nil {
+		return nil, fmt.Errorf("failed to upload files for service %s in project %s: %s", serviceName, projectName, err)
+	}
+
+	// the service is not deployed to other envs automatically, since it is imported from an existing release
+	svc, errCreate := createOrUpdateHelmService(
+		fsTree,
+		&helmServiceCreationArgs{
+			ChartName:       rel.Chart.Metadata.Name,
+			ChartVersion:    rel.Chart.Metadata.Version,
+			ServiceRevision: rev,
+			MergedValues:    string(valuesYAML),
+			ServiceName:     serviceName,
+			FilePath:        to,
+			ProductName:     projectName,
+			CreateBy:        username,
+			RequestID:       requestID,
+			Source:          setting.SourceFromCustomEdit,
+			ValuesSource:    &commonservice.ValuesDataArgs{},
+			Production:      production,
+		}, false,
+		logger,
+	)
+	if errCreate != nil {
+		err = errCreate
+		return nil, fmt.Errorf("failed to create service %s from release %s: %s", serviceName, rel.Name, err)
+	}
+	return svc, nil
+}
+
+// buildImportedHelmProductService builds the env service of the release, the user supplied values of the release
+// are kept as the override values of the env.
+func buildImportedHelmProductService(projectName string, svcTmpl *commonmodels.Service, rel *release.Release) (*commonmodels.ProductService, error) {
+	overrideYaml := ""
+	if len(rel.Config) > 0 {
+		bs, err := yaml.Marshal(rel.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal values of release %s: %s", rel.Name, err)
+		}
+		overrideYaml = string(bs)
+	}
+
+	values, err := chartutil.CoalesceValues(rel.Chart, rel.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge values of release %s: %s", rel.Name, err)
+	}
+	containers, err := commonutil.ParseImagesForProductService(values, svcTmpl.ServiceName, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse images of release %s: %s", rel.Name, err)
+	}
+
+	return &commonmodels.ProductService{
+		ServiceName: svcTmpl.ServiceName,
+		ReleaseName: rel.Name,
+		ProductName: projectName,
+		Type:        setting.HelmDeployType,
+		Revision:    svcTmpl.Revision,
+		Containers:  containers,
+		Render: &templatemodels.ServiceRender{
+			ServiceName:  svcTmpl.ServiceName,
+			ReleaseName:  rel.Name,
+			ChartName:    rel.Chart.Metadata.Name,
+			ChartVersion: rel.Chart.Metadata.Version,
+			ValuesYaml:   svcTmpl.HelmChart.ValuesYaml,
+			OverrideYaml: &templatemodels.CustomYaml{
+				YamlContent: overrideYaml,
+			},
+		},
+	}, nil
+}
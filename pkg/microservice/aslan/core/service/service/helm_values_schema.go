@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type HelmValuesSchemaResp struct {
+	Source string `json:"source"`
+	Schema string `json:"schema"`
+	// ChartSchema is the values.schema.json in the chart of the latest revision of the service
+	ChartSchema string `json:"chart_schema"`
+}
+
+type UpdateHelmValuesSchemaArgs struct {
+	Source string `json:"source"`
+	Schema string `json:"schema"`
+}
+
+func getHelmServiceTemplate(projectName, serviceName string, production bool) (*commonmodels.Service, error) {
+	svc, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+		ServiceName: serviceName,
+		ProductName: projectName,
+		Type:        setting.HelmDeployType,
+	}, production)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find helm service %s, err: %s", serviceName, err)
+	}
+	return svc, nil
+}
+
+func GetHelmValuesSchema(projectName, serviceName string, production bool, logger *zap.SugaredLogger) (*HelmValuesSchemaResp, error) {
+	svc, err := getHelmServiceTemplate(projectName, serviceName, production)
+	if err != nil {
+		return nil, e.ErrGetHelmValuesSchema.AddErr(err)
+	}
+
+	resp := &HelmValuesSchemaResp{
+		Source: commonmodels.HelmValuesSchemaSourceChart,
+	}
+	schemaSetting, err := commonrepo.NewHelmValuesSchemaColl().Find(projectName, serviceName, production)
+	if err == nil {
+		resp.Source = schemaSetting.Source
+		resp.Schema = schemaSetting.Schema
+	} else if !commonrepo.IsErrNoDocuments(err) {
+		return nil, e.ErrGetHelmValuesSchema.AddErr(err)
+	}
+
+	// the schema setting is returned even if the chart fails to be loaded
+	if resp.ChartSchema, err = commonservice.LoadChartValuesSchema(svc, production); err != nil {
+		logger.Warnf("failed to load values schema in chart of service %s, err: %s", serviceName, err)
+	}
+	return resp, nil
+}
+
+func UpdateHelmValuesSchema(projectName, serviceName string, production bool, args *UpdateHelmValuesSchemaArgs, userName string, logger *zap.SugaredLogger) error {
+	if _, err := getHelmServiceTemplate(projectName, serviceName, production); err != nil {
+		return e.ErrUpdateHelmValuesSchema.AddErr(err)
+	}
+
+	switch args.Source {
+	case commonmodels.HelmValuesSchemaSourceChart, commonmodels.HelmValuesSchemaSourceDisabled:
+		args.Schema = ""
+	case commonmodels.HelmValuesSchemaSourceCustom:
+		if err := commonservice.CheckHelmValuesSchema(args.Schema); err != nil {
+			return e.ErrUpdateHelmValuesSchema.AddErr(err)
+		}
+	default:
+		return e.ErrUpdateHelmValuesSchema.AddDesc(fmt.Sprintf("invalid source: %s", args.Source))
+	}
+
+	err := commonrepo.NewHelmValuesSchemaColl().Upsert(&commonmodels.HelmValuesSchema{
+		ProjectName: projectName,
+		ServiceName: serviceName,
+		Production:  production,
+		Source:      args.Source,
+		Schema:      args.Schema,
+		UpdateBy:    userName,
+	})
+	if err != nil {
+		logger.Errorf("failed to update values schema of service %s/%s, err: %s", projectName, serviceName, err)
+		return e.ErrUpdateHelmValuesSchema.AddErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	labelconfig "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/config"
+	labelservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/label/service"
+)
+
+// FilterServiceTemplatesByLabelSelector filters the services of the project by the label selector, the labels
+// of the production services are bound to the ProductionService resources.
+func FilterServiceTemplatesByLabelSelector(projectName, selector string, production bool, services *commonservice.ServiceTmplResp) (*commonservice.ServiceTmplResp, error) {
+	resourceType := labelconfig.ResourceTypeService
+	if production {
+		resourceType = labelconfig.ResourceTypeProductionService
+	}
+	filter, err := labelservice.NewResourceFilter(resourceType, projectName, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &commonservice.ServiceTmplResp{Data: make([]*commonservice.ServiceProductMap, 0)}
+	for _, svc := range services.Data {
+		if filter.Matches(svc.Service) {
+			resp.Data = append(resp.Data, svc)
+		}
+	}
+	resp.Total = len(resp.Data)
+	return resp, nil
+}
@@ -57,6 +57,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/tool/gerrit"
 	"github.com/koderover/zadig/v2/pkg/tool/httpclient"
 	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+	"github.com/koderover/zadig/v2/pkg/tool/kube/lint"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
 	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
 	"github.com/koderover/zadig/v2/pkg/types"
@@ -71,6 +72,7 @@ type ServiceOption struct {
 	ServiceVariableKVs []*commontypes.ServiceVariableKV `json:"service_variable_kvs"`
 	Yaml               string                           `json:"yaml"`
 	Service            *commonmodels.Service            `json:"service,omitempty"`
+	LintFindings       []*lint.Finding                  `json:"lint_findings,omitempty"`
 }
 
 type ServiceModule struct {
@@ -164,6 +166,9 @@ func GetServiceOption(args *commonmodels.Service, log *zap.SugaredLogger) (*Serv
 		{
 			Key:   "$EnvName$",
 			Value: ""},
+		{
+			Key:   "$Hostname$",
+			Value: ""},
 	}
 
 	serviceOption.VariableYaml = args.VariableYaml
@@ -593,6 +598,21 @@ func CreateServiceTemplate(userName string, args *commonmodels.Service, force bo
 		return nil, fmt.Errorf("failed to fill service variable, err: %w", err)
 	}
 
+	// the first deploy hook is configured separately, keep it for the new revision
+	if args.FirstDeployHook == nil && serviceTmpl != nil {
+		args.FirstDeployHook = serviceTmpl.FirstDeployHook
+	}
+
+	// the owner is reassigned through the owner api only, keep it for the new revision
+	if serviceTmpl != nil && serviceTmpl.Owner != nil {
+		args.Owner = serviceTmpl.Owner
+	}
+
+	// the deploy window is configured separately, keep it for the new revision
+	if serviceTmpl != nil {
+		args.DeployWindow = serviceTmpl.DeployWindow
+	}
+
 	// 校验args
 	args.Production = production
 	if err := ensureServiceTmpl(userName, args, log); err != nil {
@@ -600,6 +620,14 @@ func CreateServiceTemplate(userName string, args *commonmodels.Service, force bo
 		return nil, e.ErrValidateTemplate.AddDesc(err.Error())
 	}
 
+	var lintFindings []*lint.Finding
+	if args.Type == setting.K8SDeployType {
+		lintFindings = LintServiceManifests(args.ProductName, args.KubeYamls, production, log)
+		if err := kube.ShouldBlockManifests(lintFindings); err != nil {
+			return nil, e.ErrValidateTemplate.AddDesc(err.Error())
+		}
+	}
+
 	if err := repository.Delete(args.ServiceName, args.Type, args.ProductName, setting.ProductStatusDeleting, args.Revision, production); err != nil {
 		log.Errorf("ServiceTmpl.delete %s error: %v", args.ServiceName, err)
 	}
@@ -641,7 +669,38 @@ func CreateServiceTemplate(userName string, args *commonmodels.Service, force bo
 		return nil, e.ErrCreateTemplate.AddErr(err)
 	}
 
-	return GetServiceOption(args, log)
+	serviceOption, err := GetServiceOption(args, log)
+	if err != nil {
+		return nil, err
+	}
+	serviceOption.LintFindings = lintFindings
+	return serviceOption, nil
+}
+
+// LintServiceManifests checks the rendered k8s yaml against the clusters of the project envs, the local cluster is used
+// if there is no env yet. Failures of the checks are logged only since they should never block saving the service.
+func LintServiceManifests(projectName string, manifests []string, production bool, log *zap.SugaredLogger) []*lint.Finding {
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+		Name:       projectName,
+		Production: util.GetBoolPointer(production),
+	})
+	if err != nil {
+		log.Warnf("failed to list envs of project %s for manifest lint, err: %s", projectName, err)
+	}
+	clusterIDs := make([]string, 0)
+	for _, env := range envs {
+		clusterIDs = append(clusterIDs, env.ClusterID)
+	}
+	if len(clusterIDs) == 0 {
+		clusterIDs = append(clusterIDs, setting.LocalClusterID)
+	}
+
+	findings, err := kube.LintManifests(clusterIDs, manifests, false)
+	if err != nil {
+		log.Warnf("failed to lint manifests of project %s, err: %s", projectName, err)
+		return nil
+	}
+	return findings
 }
 
 func UpdateServiceEnvStatus(args *commonservice.ServiceTmplObject) error {
@@ -779,6 +838,76 @@ func UpdateServiceVariables(args *commonservice.ServiceTmplObject, production bo
 	return nil
 }
 
+func UpdateServiceFirstDeployHook(projectName, serviceName string, hook *commonmodels.FirstDeployHook, production bool) error {
+	if err := kube.ValidateFirstDeployHook(hook); err != nil {
+		return e.ErrInvalidParam.AddErr(err)
+	}
+
+	currentService, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+		ProductName: projectName,
+		ServiceName: serviceName,
+	}, production)
+	if err != nil {
+		return e.ErrUpdateService.AddErr(fmt.Errorf("failed to get service info, err: %s", err))
+	}
+	if currentService.Type != setting.K8SDeployType {
+		return e.ErrUpdateService.AddErr(fmt.Errorf("first deploy hook is not supported by service type: %v", currentService.Type))
+	}
+
+	currentService.FirstDeployHook = hook
+	if err := repository.UpdateServiceFirstDeployHook(currentService, production); err != nil {
+		return e.ErrUpdateService.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateServiceDeployWindow(projectName, serviceName string, window *commonmodels.DeployWindow, production bool) error {
+	if err := commonutil.ValidateDeployWindow(window); err != nil {
+		return e.ErrInvalidParam.AddErr(err)
+	}
+
+	if _, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+		ProductName: projectName,
+		ServiceName: serviceName,
+	}, production); err != nil {
+		return e.ErrUpdateService.AddErr(fmt.Errorf("failed to get service info, err: %s", err))
+	}
+
+	if err := repository.UpdateServiceDeployWindow(projectName, serviceName, window, production); err != nil {
+		return e.ErrUpdateService.AddErr(err)
+	}
+	return nil
+}
+
+// UpdateServiceLockout locks out or unlocks the service, the jobs of a locked out service can not be created.
+func UpdateServiceLockout(projectName, serviceName, username string, lockout *commonmodels.ServiceLockout, production bool) error {
+	if lockout.Enabled {
+		if strings.TrimSpace(lockout.Reason) == "" {
+			return e.ErrInvalidParam.AddDesc("reason is required to lock out the service")
+		}
+		if lockout.ExpireTime != 0 && lockout.ExpireTime <= time.Now().Unix() {
+			return e.ErrInvalidParam.AddDesc("expire time should be in the future")
+		}
+		lockout.LockedBy = username
+		lockout.LockTime = time.Now().Unix()
+	}
+
+	if _, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+		ProductName: projectName,
+		ServiceName: serviceName,
+	}, production); err != nil {
+		return e.ErrUpdateService.AddErr(fmt.Errorf("failed to get service info, err: %s", err))
+	}
+
+	if !lockout.Enabled {
+		lockout = nil
+	}
+	if err := repository.UpdateServiceLockout(projectName, serviceName, lockout, production); err != nil {
+		return e.ErrUpdateService.AddErr(err)
+	}
+	return nil
+}
+
 func UpdateServiceHealthCheckStatus(args *commonservice.ServiceTmplObject) error {
 	currentService, err := commonrepo.NewServiceColl().Find(&commonrepo.ServiceFindOption{
 		ProductName: args.ProductName,
@@ -928,6 +1057,32 @@ func YamlValidator(args *YamlValidatorReq) []string {
 	return errorDetails
 }
 
+type LintYamlReq struct {
+	YamlValidatorReq
+	ProjectName string `json:"project_name"`
+	Production  bool   `json:"production"`
+	// ClusterID is the cluster to check the schema against, the clusters of the project envs are used if it is empty
+	ClusterID string `json:"cluster_id"`
+	// Partial means the yaml is a patch, such as the patch content of k8s patch jobs
+	Partial bool `json:"partial"`
+}
+
+func LintYaml(args *LintYamlReq, log *zap.SugaredLogger) ([]*lint.Finding, error) {
+	if args.Yaml == "" {
+		return make([]*lint.Finding, 0), nil
+	}
+	manifests := util.SplitYaml(getRenderedYaml(&args.YamlValidatorReq))
+	if args.ClusterID == "" && !args.Partial {
+		return LintServiceManifests(args.ProjectName, manifests, args.Production, log), nil
+	}
+
+	clusterID := args.ClusterID
+	if clusterID == "" {
+		clusterID = setting.LocalClusterID
+	}
+	return kube.LintManifests([]string{clusterID}, manifests, args.Partial)
+}
+
 func UpdateReleaseNamingRule(userName, requestID, projectName string, args *ReleaseNamingRule, production bool, log *zap.SugaredLogger) error {
 	serviceTemplate, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
 		ServiceName:   args.ServiceName,
@@ -1074,6 +1229,9 @@ func DeleteServiceTemplate(serviceName, serviceType, productName string, product
 				log.Warnf("Failed to delete file %s, err: %s", serviceName, err)
 			}
 		}
+		if err = commonrepo.NewHelmValuesSchemaColl().Delete(productName, serviceName, production); err != nil {
+			log.Warnf("Failed to delete values schema of service %s, err: %s", serviceName, err)
+		}
 	}
 
 	//删除环境模板
@@ -52,6 +52,16 @@ type OpenAPIGetBuildStatArgs struct {
 	Project   string `json:"project"        form:"projectKey"`
 }
 
+// @Summary OpenAPI Get Build Stat
+// @Description OpenAPI Get Build Stat
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	startDate	query	int	false	"start date"
+// @Param 	endDate	query	int	false	"end date"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	service.dashboardBuild
+// @Router /openapi/statistics/build [get]
 func GetBuildStatForOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
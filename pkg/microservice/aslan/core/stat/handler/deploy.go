@@ -48,6 +48,16 @@ type OpenAPIGetDeployStatArgs struct {
 	Project   string `json:"project"        form:"projectKey"`
 }
 
+// @Summary OpenAPI Get Deploy Stats
+// @Description OpenAPI Get Deploy Stats
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	startDate	query	int	false	"start date"
+// @Param 	endDate	query	int	false	"end date"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	service.DeployDashboard
+// @Router /openapi/statistics/deploy [get]
 func GetDeployStatsOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/stat/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Get Environment Cost Report
+// @Description Aggregate the requested and used resources of the environments into daily or weekly costs
+// @Tags 	stat
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string							false	"project name"
+// @Param 	envName			query		string							false	"env name"
+// @Param 	period			query		string							false	"daily or weekly, default is daily"
+// @Param 	start_time		query		int								false	"start time"
+// @Param 	end_time		query		int								false	"end time"
+// @Success 200 			{object}  	service.EnvCostReport
+// @Router /api/aslan/stat/v2/cost/report [get]
+func GetEnvCostReport(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.EnvCostReportArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	scope := &service.EnvCostScope{
+		IsSystemAdmin:         ctx.Resources.IsSystemAdmin,
+		EnvProjects:           make([]string, 0),
+		ProductionEnvProjects: make([]string, 0),
+	}
+	for project, authInfo := range ctx.Resources.ProjectAuthInfo {
+		if authInfo.IsProjectAdmin || authInfo.Env.View {
+			scope.EnvProjects = append(scope.EnvProjects, project)
+		}
+		if authInfo.IsProjectAdmin || authInfo.ProductionEnv.View {
+			scope.ProductionEnvProjects = append(scope.ProductionEnvProjects, project)
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.GetEnvCostReport(args, scope, ctx.Logger)
+}
+
+// @Summary Get Environment Cost Price
+// @Description Get the unit price used to estimate the cost of the environments
+// @Tags 	stat
+// @Accept 	json
+// @Produce json
+// @Success 200 			{object}  	commonmodels.EnvCostPrice
+// @Router /api/aslan/stat/v2/cost/price [get]
+func GetEnvCostPrice(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetEnvCostPrice()
+}
+
+// @Summary Update Environment Cost Price
+// @Description Update the unit price used to estimate the cost of the environments, prices of the clusters override the default ones
+// @Tags 	stat
+// @Accept 	json
+// @Produce json
+// @Param 	body 			body 		commonmodels.EnvCostPrice		true 	"body"
+// @Success 200
+// @Router /api/aslan/stat/v2/cost/price [put]
+func UpdateEnvCostPrice(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(commonmodels.EnvCostPrice)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Err = service.UpdateEnvCostPrice(args, ctx.Logger)
+}
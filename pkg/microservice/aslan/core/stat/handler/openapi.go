@@ -49,6 +49,16 @@ func (req *getStatReqV2) Validate() error {
 	return nil
 }
 
+// @Summary OpenAPI Get Release Stat
+// @Description OpenAPI Get Release Stat
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	startTime	query	int	false	"start time"
+// @Param 	endTime	query	int	false	"end time"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	service.OpenAPIStatV2
+// @Router /openapi/statistics/v2/release [get]
 func GetReleaseStatOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -23,6 +23,13 @@ import (
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
 )
 
+// @Summary OpenAPI Get Overview Stat
+// @Description OpenAPI Get Overview Stat
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Success 200 	{object} 	service.Overview
+// @Router /openapi/statistics/overview [get]
 func GetOverviewStat(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/stat/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Get Project Resource Usage Report
+// @Description Get the monthly job pod usages and storage usages of the projects for chargeback
+// @Tags 	stat
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string							false	"project name"
+// @Param 	startMonth		query		string							false	"start month in the form of YYYY-MM, default is 5 months ago"
+// @Param 	endMonth		query		string							false	"end month in the form of YYYY-MM, default is the current month"
+// @Success 200 			{object}  	service.ProjectResourceUsageReport
+// @Router /api/aslan/stat/v2/usage/report [get]
+func GetProjectResourceUsageReport(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.ProjectResourceUsageArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	scope, ok := getProjectResourceUsageScope(ctx, args.ProjectName)
+	if !ok {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetProjectResourceUsageReport(args, scope, ctx.Logger)
+}
+
+// @Summary Export Project Resource Usage Report
+// @Description Export the monthly resource usages of the projects in json or csv format
+// @Tags 	stat
+// @Accept 	json
+// @Produce octet-stream
+// @Param 	format			query		string							false	"json or csv, default json"
+// @Param 	projectName		query		string							false	"project name"
+// @Param 	startMonth		query		string							false	"start month in the form of YYYY-MM"
+// @Param 	endMonth		query		string							false	"end month in the form of YYYY-MM"
+// @Success 200
+// @Router /api/aslan/stat/v2/usage/report/export [get]
+func ExportProjectResourceUsageReport(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	args := new(service.ProjectResourceUsageArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	scope, ok := getProjectResourceUsageScope(ctx, args.ProjectName)
+	if !ok {
+		ctx.UnAuthorized = true
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	data, fileName, err := service.ExportProjectResourceUsageReport(args, scope, c.Query("format"), ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, args.ProjectName, "导出", "项目资源用量", fileName, "", ctx.Logger)
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, "application/octet-stream", data)
+}
+
+// getProjectResourceUsageScope returns the projects administered by the user, false is returned if the user
+// is not the admin of the given project.
+func getProjectResourceUsageScope(ctx *internalhandler.Context, projectName string) (*service.ProjectResourceUsageScope, bool) {
+	scope := &service.ProjectResourceUsageScope{
+		IsSystemAdmin: ctx.Resources.IsSystemAdmin,
+		Projects:      make([]string, 0),
+	}
+	if scope.IsSystemAdmin {
+		return scope, true
+	}
+	for project, authInfo := range ctx.Resources.ProjectAuthInfo {
+		if authInfo.IsProjectAdmin {
+			scope.Projects = append(scope.Projects, project)
+		}
+	}
+	if projectName != "" {
+		authInfo, ok := ctx.Resources.ProjectAuthInfo[projectName]
+		return scope, ok && authInfo.IsProjectAdmin
+	}
+	return scope, true
+}
@@ -80,6 +80,7 @@ func (*Router) Inject(router *gin.RouterGroup) {
 	{
 		aiV2.POST("/analysis", GetAIStatsAnalysis)
 		aiV2.GET("/analysis/prompt", GetAIStatsAnalysisPrompts)
+		aiV2.POST("/query", AIQuery)
 		aiV2.GET("/overview", GetProjectsOverview)
 		aiV2.GET("/build/trend", GetCurrently30DayBuildTrend)
 		aiV2.GET("/radar", GetEfficiencyRadar)
@@ -105,6 +106,24 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		deployV2.GET("/service/failure", GetTopDeployFailuresByService)
 	}
 
+	scanningV2 := qualityV2.Group("scanning")
+	{
+		scanningV2.GET("/trend", GetScanningTrend)
+	}
+
+	costV2 := v2.Group("cost")
+	{
+		costV2.GET("/report", GetEnvCostReport)
+		costV2.GET("/price", GetEnvCostPrice)
+		costV2.PUT("/price", UpdateEnvCostPrice)
+	}
+
+	usageV2 := v2.Group("usage")
+	{
+		usageV2.GET("/report", GetProjectResourceUsageReport)
+		usageV2.GET("/report/export", ExportProjectResourceUsageReport)
+	}
+
 }
 
 type OpenAPIRouter struct{}
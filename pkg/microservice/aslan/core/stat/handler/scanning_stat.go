@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/stat/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type getScanningTrendReq struct {
+	getStatGeneralReq `form:",inline"`
+
+	ScanningName string `json:"scanningName" form:"scanningName"`
+}
+
+// @Summary Get Scanning Trend
+// @Description Get the daily sonar metrics of scannings in the given projects
+// @Tags 	stat
+// @Accept 	json
+// @Produce json
+// @Param 	startDate		query		int			false	"start time"
+// @Param 	endDate			query		int			false	"end time"
+// @Param 	projects		query		[]string	false	"projects"
+// @Param 	scanningName	query		string		false	"scanning name"
+// @Success 200 			{array} 	service.ScanningTrend
+// @Router /api/aslan/stat/v2/quality/scanning/trend [get]
+func GetScanningTrend(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	args := new(getScanningTrendReq)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetScanningTrend(args.StartTime, args.EndTime, args.Projects, args.ScanningName, ctx.Logger)
+}
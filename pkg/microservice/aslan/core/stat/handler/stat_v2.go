@@ -18,6 +18,7 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -165,6 +166,51 @@ func GetAIStatsAnalysis(c *gin.Context) {
 	ctx.Resp, ctx.Err = ai.AnalyzeProjectStats(args, ctx.Logger)
 }
 
+// @Summary AI Query
+// @Description Answer the natural language question about the environments and workflow jobs, only the data the user has permission to view is used
+// @Tags 	stat
+// @Accept 	json
+// @Produce json
+// @Param 	body 			body 		ai.QueryReq 		true 	"body"
+// @Success 200 			{object} 	ai.QueryResp
+// @Router /api/stat/v2/ai/query [post]
+func AIQuery(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(ai.QueryReq)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	scope := &ai.QueryScope{
+		IsSystemAdmin:         ctx.Resources.IsSystemAdmin,
+		EnvProjects:           make([]string, 0),
+		ProductionEnvProjects: make([]string, 0),
+		WorkflowProjects:      make([]string, 0),
+	}
+	for project, authInfo := range ctx.Resources.ProjectAuthInfo {
+		if authInfo.IsProjectAdmin || authInfo.Env.View {
+			scope.EnvProjects = append(scope.EnvProjects, project)
+		}
+		if authInfo.IsProjectAdmin || authInfo.ProductionEnv.View {
+			scope.ProductionEnvProjects = append(scope.ProductionEnvProjects, project)
+		}
+		if authInfo.IsProjectAdmin || authInfo.Workflow.View {
+			scope.WorkflowProjects = append(scope.WorkflowProjects, project)
+		}
+	}
+
+	ctx.Resp, ctx.Err = ai.AnswerQuestion(args, scope, ctx.Logger)
+}
+
 func GetAIStatsAnalysisPrompts(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -54,6 +54,16 @@ type OpenAPIGetTestStatArgs struct {
 	Project   string `json:"project"        form:"projectKey"`
 }
 
+// @Summary OpenAPI Get Test Stat
+// @Description OpenAPI Get Test Stat
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	startDate	query	int	false	"start date"
+// @Param 	endDate	query	int	false	"end date"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	OpenAPITestStatResp
+// @Router /openapi/statistics/test [get]
 func GetTestStatOpenAPI(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
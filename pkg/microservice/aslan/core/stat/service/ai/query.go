@@ -0,0 +1,311 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/llm"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+const (
+	QueryTargetEnv = "env"
+	QueryTargetJob = "job"
+
+	// queryResultLimit is the max number of rows sent to the llm
+	queryResultLimit = 200
+	// jobs of the last day are queried if the time range is not given
+	defaultQueryDuration = 24 * time.Hour
+)
+
+var queryJobTypes = map[string][]string{
+	"build":  {string(config.JobZadigBuild)},
+	"test":   {string(config.JobZadigTesting)},
+	"deploy": {string(config.JobZadigDeploy), string(config.JobZadigHelmDeploy), string(config.JobZadigHelmChartDeploy)},
+}
+
+type QueryReq struct {
+	Question string `json:"question"`
+}
+
+// QueryScope is the permission of the user, only the data in the projects of the scope can be queried
+type QueryScope struct {
+	IsSystemAdmin bool
+	// EnvProjects are the projects in which the user can view the testing envs
+	EnvProjects []string
+	// ProductionEnvProjects are the projects in which the user can view the production envs
+	ProductionEnvProjects []string
+	// WorkflowProjects are the projects in which the user can view the workflows
+	WorkflowProjects []string
+}
+
+// Query is the internal query translated from the question by the llm
+type Query struct {
+	Target      string   `json:"target"`
+	Projects    []string `json:"projects"`
+	EnvName     string   `json:"env_name"`
+	Production  *bool    `json:"production"`
+	ServiceName string   `json:"service_name"`
+	JobType     string   `json:"job_type"`
+	Status      string   `json:"status"`
+	StartTime   int64    `json:"start_time"`
+	EndTime     int64    `json:"end_time"`
+}
+
+type QueryResp struct {
+	Answer string      `json:"answer"`
+	Query  *Query      `json:"query"`
+	Data   interface{} `json:"data"`
+	// Truncated is true if only part of the query result is used to answer the question
+	Truncated bool `json:"truncated"`
+}
+
+type QueryEnvServiceRow struct {
+	ProjectName string   `json:"project_name"`
+	EnvName     string   `json:"env_name"`
+	Production  bool     `json:"production"`
+	ServiceName string   `json:"service_name"`
+	Images      []string `json:"images"`
+	UpdateTime  int64    `json:"update_time"`
+}
+
+type QueryJobRow struct {
+	ProjectName   string `json:"project_name"`
+	WorkflowName  string `json:"workflow_name"`
+	TaskID        int64  `json:"task_id"`
+	JobType       string `json:"job_type"`
+	ServiceName   string `json:"service_name,omitempty"`
+	ServiceModule string `json:"service_module,omitempty"`
+	TargetEnv     string `json:"target_env,omitempty"`
+	Production    bool   `json:"production"`
+	Status        string `json:"status"`
+	StartTime     int64  `json:"start_time"`
+	Duration      int64  `json:"duration"`
+}
+
+// AnswerQuestion translates the natural language question into an internal query, runs the query
+// within the permission scope of the user and asks the llm to answer the question with the result.
+func AnswerQuestion(args *QueryReq, scope *QueryScope, logger *zap.SugaredLogger) (*QueryResp, error) {
+	if strings.TrimSpace(args.Question) == "" {
+		return nil, e.ErrInvalidParam.AddDesc("question can't be empty")
+	}
+
+	if scope.IsSystemAdmin {
+		projects, err := templaterepo.NewProductColl().ListAllName()
+		if err != nil {
+			return nil, e.ErrAIQuery.AddErr(err)
+		}
+		scope.EnvProjects, scope.ProductionEnvProjects, scope.WorkflowProjects = projects, projects, projects
+	}
+	visibleProjects := lo.Uniq(lo.Flatten([][]string{scope.EnvProjects, scope.ProductionEnvProjects, scope.WorkflowProjects}))
+
+	ctx := context.Background()
+	client, err := service.GetDefaultLLMClient(ctx)
+	if err != nil {
+		logger.Errorf("failed to get llm client, the error is: %+v", err)
+		return nil, e.ErrAIQuery.AddErr(err)
+	}
+
+	projectList, _ := json.Marshal(visibleProjects)
+	prompt := fmt.Sprintf(QueryParsePrompt, time.Now().Unix(), string(projectList), args.Question)
+	answer, err := client.GetCompletion(ctx, util.RemoveExtraSpaces(prompt), llm.WithTemperature(float32(0.1)), llm.WithModel(AnalysisModel))
+	if err != nil {
+		logger.Errorf("failed to get answer from ai: %v, the error is: %+v", client.GetName(), err)
+		return nil, e.ErrAIQuery.AddErr(err)
+	}
+	query := new(Query)
+	answer = strings.TrimSpace(answer)
+	answer = strings.TrimPrefix(answer, "```json")
+	answer = strings.TrimSuffix(answer, "```")
+	if err := json.Unmarshal([]byte(strings.TrimSpace(answer)), query); err != nil {
+		logger.Errorf("failed to parse the query %s, error: %s", answer, err)
+		return nil, e.ErrAIQuery.AddErr(ReturnAnswerWrongFormat)
+	}
+
+	resp := &QueryResp{Query: query}
+	switch query.Target {
+	case QueryTargetEnv:
+		rows, err := queryEnvServices(query, scope)
+		if err != nil {
+			return nil, e.ErrAIQuery.AddErr(err)
+		}
+		if len(rows) > queryResultLimit {
+			rows, resp.Truncated = rows[:queryResultLimit], true
+		}
+		resp.Data = rows
+	case QueryTargetJob:
+		rows, err := queryJobs(query, scope)
+		if err != nil {
+			return nil, e.ErrAIQuery.AddErr(err)
+		}
+		if len(rows) > queryResultLimit {
+			rows, resp.Truncated = rows[:queryResultLimit], true
+		}
+		resp.Data = rows
+	default:
+		return nil, e.ErrAIQuery.AddDesc("only the questions about environments and workflow jobs are supported")
+	}
+
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, e.ErrAIQuery.AddErr(err)
+	}
+	prompt = fmt.Sprintf(QueryAnswerPrompt, args.Question, string(data))
+	resp.Answer, err = client.GetCompletion(ctx, util.RemoveExtraSpaces(prompt), llm.WithTemperature(float32(0.2)), llm.WithModel(AnalysisModel))
+	if err != nil {
+		logger.Errorf("failed to get answer from ai: %v, the error is: %+v", client.GetName(), err)
+		return nil, e.ErrAIQuery.AddErr(err)
+	}
+	return resp, nil
+}
+
+func queryEnvServices(query *Query, scope *QueryScope) ([]*QueryEnvServiceRow, error) {
+	rows := make([]*QueryEnvServiceRow, 0)
+	for _, production := range []bool{false, true} {
+		if query.Production != nil && *query.Production != production {
+			continue
+		}
+		allowed := scope.EnvProjects
+		if production {
+			allowed = scope.ProductionEnvProjects
+		}
+		projects := filterQueryProjects(query.Projects, allowed)
+		if len(projects) == 0 {
+			continue
+		}
+
+		production := production
+		envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+			EnvName:       query.EnvName,
+			InProjects:    projects,
+			Production:    &production,
+			ExcludeStatus: []string{setting.ProductStatusDeleting},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, env := range envs {
+			for _, svc := range env.GetSvcList() {
+				if query.ServiceName != "" && !strings.Contains(svc.ServiceName, query.ServiceName) {
+					continue
+				}
+				images := make([]string, 0, len(svc.Containers))
+				for _, container := range svc.Containers {
+					images = append(images, container.Image)
+				}
+				rows = append(rows, &QueryEnvServiceRow{
+					ProjectName: env.ProductName,
+					EnvName:     env.EnvName,
+					Production:  env.Production,
+					ServiceName: svc.ServiceName,
+					Images:      images,
+					UpdateTime:  svc.UpdateTime,
+				})
+			}
+		}
+	}
+	return rows, nil
+}
+
+func queryJobs(query *Query, scope *QueryScope) ([]*QueryJobRow, error) {
+	rows := make([]*QueryJobRow, 0)
+	projects := filterQueryProjects(query.Projects, scope.WorkflowProjects)
+	// an empty project list means all the projects in the job info collection, so it must be checked
+	if len(projects) == 0 {
+		return rows, nil
+	}
+
+	endTime := query.EndTime
+	if endTime == 0 {
+		endTime = time.Now().Unix()
+	}
+	startTime := query.StartTime
+	if startTime == 0 {
+		startTime = time.Unix(endTime, 0).Add(-defaultQueryDuration).Unix()
+	}
+
+	jobs, err := commonrepo.NewJobInfoColl().GetJobInfos(startTime, endTime, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	jobTypes := queryJobTypes[query.JobType]
+	for _, job := range jobs {
+		if len(jobTypes) > 0 && !lo.Contains(jobTypes, job.Type) {
+			continue
+		}
+		if query.Status != "" && job.Status != query.Status {
+			continue
+		}
+		if query.ServiceName != "" && !strings.Contains(job.ServiceName, query.ServiceName) {
+			continue
+		}
+		if query.EnvName != "" && job.TargetEnv != query.EnvName {
+			continue
+		}
+		if query.Production != nil && job.Production != *query.Production {
+			continue
+		}
+		rows = append(rows, &QueryJobRow{
+			ProjectName:   job.ProductName,
+			WorkflowName:  job.WorkflowName,
+			TaskID:        job.TaskID,
+			JobType:       job.Type,
+			ServiceName:   job.ServiceName,
+			ServiceModule: job.ServiceModule,
+			TargetEnv:     job.TargetEnv,
+			Production:    job.Production,
+			Status:        job.Status,
+			StartTime:     job.StartTime,
+			Duration:      job.Duration,
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].StartTime > rows[j].StartTime
+	})
+	return rows, nil
+}
+
+// filterQueryProjects returns the projects in the query which are allowed, all the allowed projects are returned if none is given
+func filterQueryProjects(projects, allowed []string) []string {
+	if len(projects) == 0 {
+		return allowed
+	}
+	resp := make([]string, 0, len(projects))
+	for _, project := range projects {
+		if lo.Contains(allowed, project) {
+			resp = append(resp, project)
+		}
+	}
+	return resp
+}
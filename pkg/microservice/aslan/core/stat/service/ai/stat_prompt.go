@@ -96,3 +96,23 @@ const AttentionPrompt = `你需要从两个月数据中做对比，分析出两
 你的输出必须是JSON格式，返回前必须对你的回答进行检查，并根据两个月差异的从大到小进行排序再返回，保证差异最大的内容在最上面，有错误则重新进行计算并分析，如果项目数大于5，则返回变化最大的5条内容，如果项目数量小于5条，则返回变化最大的3条内容。
 注意不需要返回分析步骤，如果没有数据，则返回如下JSON内容{\n    \"answer\":[]\n}。
 `
+
+// QueryParsePrompt translates the question of the user into the internal query, the placeholders are
+// the current time, the project list and the question
+const QueryParsePrompt = `你是一个查询语句解析器，需要把三重引号分割的用户问题解析为 JSON 格式的内部查询条件，当前时间的 unix 时间戳为 %d，可选的项目列表为 %s。
+查询条件格式为：{"target": "env|job", "projects": [], "env_name": "", "production": null, "service_name": "", "job_type": "", "status": "", "start_time": 0, "end_time": 0}
+规则如下：
+1. target：询问环境中部署了什么服务、镜像或版本时为 env；询问构建、测试、部署等任务的执行情况时为 job；
+2. projects：问题中提到的项目，必须是可选项目列表的子集，没有提到时返回空数组；
+3. production：问题中提到生产环境时为 true，提到测试环境时为 false，没有提到时为 null；
+4. job_type：只能是 build、test、deploy 之一，没有提到时返回空字符串；
+5. status：只能是 passed、failed、timeout、cancelled 之一，没有提到时返回空字符串；
+6. start_time、end_time：问题中提到的时间范围对应的 unix 时间戳，没有提到时为 0；
+7. 无法识别的字段返回空值，不要编造。
+仅返回 JSON 数据，不要有任何多余的解释。用户问题："""%s"""
+`
+
+// QueryAnswerPrompt answers the question of the user with the query result, the placeholders are the question and the data
+const QueryAnswerPrompt = `你是一个资深的 devops 助手，请只根据三重引号分割的查询结果回答用户的问题，用简洁的中文回答，可以使用 markdown 列表。
+如果查询结果为空，请直接说明没有找到相关数据，不要编造数据。用户问题：%s；查询结果："""%s"""
+`
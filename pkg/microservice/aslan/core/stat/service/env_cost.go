@@ -0,0 +1,343 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const (
+	EnvCostPeriodDaily  = "daily"
+	EnvCostPeriodWeekly = "weekly"
+
+	// envResourceUsageRetentionDays is how long the hourly usage samples are kept
+	envResourceUsageRetentionDays = 180
+	gib                           = 1 << 30
+)
+
+type EnvCostReportArgs struct {
+	ProjectName string `json:"projectName" form:"projectName"`
+	EnvName     string `json:"envName"     form:"envName"`
+	Period      string `json:"period"      form:"period"`
+	StartTime   int64  `json:"start_time"  form:"start_time"`
+	EndTime     int64  `json:"end_time"    form:"end_time"`
+}
+
+// EnvCostScope is the envs the user is able to view, all of them are visible to the system admin
+type EnvCostScope struct {
+	IsSystemAdmin         bool
+	EnvProjects           []string
+	ProductionEnvProjects []string
+}
+
+type EnvCostSummary struct {
+	RequestedCPUCoreHours   float64 `json:"requested_cpu_core_hours"`
+	RequestedMemoryGiBHours float64 `json:"requested_memory_gib_hours"`
+	UsedCPUCoreHours        float64 `json:"used_cpu_core_hours"`
+	UsedMemoryGiBHours      float64 `json:"used_memory_gib_hours"`
+	RequestedCost           float64 `json:"requested_cost"`
+	UsedCost                float64 `json:"used_cost"`
+}
+
+type EnvCostReportItem struct {
+	StartTime   int64  `json:"start_time"`
+	ProjectName string `json:"project_name"`
+	EnvName     string `json:"env_name"`
+	Production  bool   `json:"production"`
+	*EnvCostSummary
+}
+
+type EnvCostReport struct {
+	Currency  string               `json:"currency"`
+	Period    string               `json:"period"`
+	StartTime int64                `json:"start_time"`
+	EndTime   int64                `json:"end_time"`
+	Items     []*EnvCostReportItem `json:"items"`
+	Total     *EnvCostSummary      `json:"total"`
+}
+
+func (s *EnvCostSummary) add(usage *commonmodels.EnvResourceUsage, cpuPrice, memoryPrice float64) {
+	// every sample stands for the usage of an hour
+	s.RequestedCPUCoreHours += usage.RequestedCPU
+	s.RequestedMemoryGiBHours += usage.RequestedMemory
+	s.UsedCPUCoreHours += usage.UsedCPU
+	s.UsedMemoryGiBHours += usage.UsedMemory
+	s.RequestedCost += usage.RequestedCPU*cpuPrice + usage.RequestedMemory*memoryPrice
+	s.UsedCost += usage.UsedCPU*cpuPrice + usage.UsedMemory*memoryPrice
+}
+
+func GetEnvCostPrice() (*commonmodels.EnvCostPrice, error) {
+	sysSetting, err := commonrepo.NewSystemSettingColl().Get()
+	if err != nil {
+		return nil, e.ErrGetEnvCostPrice.AddErr(err)
+	}
+	if sysSetting.CostPrice == nil {
+		return &commonmodels.EnvCostPrice{ClusterPrices: make([]*commonmodels.ClusterCostPrice, 0)}, nil
+	}
+	return sysSetting.CostPrice, nil
+}
+
+func UpdateEnvCostPrice(args *commonmodels.EnvCostPrice, log *zap.SugaredLogger) error {
+	if args.CPUCoreHourPrice < 0 || args.MemoryGiBHourPrice < 0 {
+		return e.ErrInvalidParam.AddDesc("price can not be negative")
+	}
+	clusters := make(map[string]bool)
+	for _, price := range args.ClusterPrices {
+		if price.ClusterID == "" {
+			return e.ErrInvalidParam.AddDesc("cluster id can not be empty")
+		}
+		if price.CPUCoreHourPrice < 0 || price.MemoryGiBHourPrice < 0 {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("price of cluster %s can not be negative", price.ClusterID))
+		}
+		if clusters[price.ClusterID] {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("duplicated price of cluster %s", price.ClusterID))
+		}
+		clusters[price.ClusterID] = true
+	}
+
+	if err := commonrepo.NewSystemSettingColl().UpdateCostPriceSetting(args); err != nil {
+		log.Errorf("failed to update env cost price, err: %s", err)
+		return e.ErrUpdateEnvCostPrice.AddErr(err)
+	}
+	return nil
+}
+
+// GetEnvCostReport aggregates the hourly usage samples of the envs into daily or weekly costs with the current unit price
+func GetEnvCostReport(args *EnvCostReportArgs, scope *EnvCostScope, log *zap.SugaredLogger) (*EnvCostReport, error) {
+	if args.Period == "" {
+		args.Period = EnvCostPeriodDaily
+	}
+	if args.Period != EnvCostPeriodDaily && args.Period != EnvCostPeriodWeekly {
+		return nil, e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid period: %s", args.Period))
+	}
+	if args.EndTime == 0 {
+		args.EndTime = time.Now().Unix()
+	}
+	if args.StartTime == 0 {
+		if args.Period == EnvCostPeriodDaily {
+			args.StartTime = time.Unix(args.EndTime, 0).AddDate(0, 0, -7).Unix()
+		} else {
+			args.StartTime = time.Unix(args.EndTime, 0).AddDate(0, 0, -28).Unix()
+		}
+	}
+	if args.StartTime >= args.EndTime {
+		return nil, e.ErrInvalidParam.AddDesc("start_time must be earlier than end_time")
+	}
+
+	opt := &commonrepo.EnvResourceUsageListOption{
+		ProductName: args.ProjectName,
+		EnvName:     args.EnvName,
+		StartTime:   args.StartTime,
+		EndTime:     args.EndTime,
+	}
+	if !scope.IsSystemAdmin {
+		opt.Projects = append(append([]string{}, scope.EnvProjects...), scope.ProductionEnvProjects...)
+	}
+	usages, err := commonrepo.NewEnvResourceUsageColl().List(opt)
+	if err != nil {
+		log.Errorf("failed to list env resource usages, err: %s", err)
+		return nil, e.ErrGetEnvCostReport.AddErr(err)
+	}
+
+	price, err := GetEnvCostPrice()
+	if err != nil {
+		return nil, err
+	}
+
+	envProjects, productionEnvProjects := make(map[string]bool), make(map[string]bool)
+	for _, project := range scope.EnvProjects {
+		envProjects[project] = true
+	}
+	for _, project := range scope.ProductionEnvProjects {
+		productionEnvProjects[project] = true
+	}
+
+	resp := &EnvCostReport{
+		Currency:  price.Currency,
+		Period:    args.Period,
+		StartTime: args.StartTime,
+		EndTime:   args.EndTime,
+		Items:     make([]*EnvCostReportItem, 0),
+		Total:     &EnvCostSummary{},
+	}
+	itemMap := make(map[string]*EnvCostReportItem)
+	for _, usage := range usages {
+		if !scope.IsSystemAdmin {
+			if usage.Production && !productionEnvProjects[usage.ProductName] {
+				continue
+			}
+			if !usage.Production && !envProjects[usage.ProductName] {
+				continue
+			}
+		}
+
+		startTime := getEnvCostPeriodStart(usage.CreateTime, args.Period)
+		key := fmt.Sprintf("%d/%s/%s/%v", startTime, usage.ProductName, usage.EnvName, usage.Production)
+		item, ok := itemMap[key]
+		if !ok {
+			item = &EnvCostReportItem{
+				StartTime:      startTime,
+				ProjectName:    usage.ProductName,
+				EnvName:        usage.EnvName,
+				Production:     usage.Production,
+				EnvCostSummary: &EnvCostSummary{},
+			}
+			itemMap[key] = item
+			resp.Items = append(resp.Items, item)
+		}
+
+		cpuPrice, memoryPrice := price.GetPrice(usage.ClusterID)
+		item.add(usage, cpuPrice, memoryPrice)
+		resp.Total.add(usage, cpuPrice, memoryPrice)
+	}
+
+	return resp, nil
+}
+
+// getEnvCostPeriodStart returns the start of the day, or the start of the monday of the week the timestamp belongs to
+func getEnvCostPeriodStart(timestamp int64, period string) int64 {
+	t := time.Unix(timestamp, 0)
+	dayStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if period == EnvCostPeriodWeekly {
+		offset := (int(dayStart.Weekday()) + 6) % 7
+		dayStart = dayStart.AddDate(0, 0, -offset)
+	}
+	return dayStart.Unix()
+}
+
+// SampleEnvResourceUsage records the resources requested and used by the running envs, it is expected to be called hourly.
+// Envs sharing the same namespace are sampled separately, so the namespace is counted once for each of them.
+func SampleEnvResourceUsage() {
+	logger := log.SugaredLogger().With("source", "env resource usage sampling")
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+		ExcludeStatus: []string{setting.ProductStatusCreating, setting.ProductStatusDeleting, setting.ProductStatusUnknown, setting.ProductStatusSleeping},
+	})
+	if err != nil {
+		logger.Errorf("failed to list envs: %s", err)
+		return
+	}
+
+	now := time.Now().Unix()
+	coll := commonrepo.NewEnvResourceUsageColl()
+	vmProjects := make(map[string]bool)
+	for _, env := range envs {
+		isVMProject, ok := vmProjects[env.ProductName]
+		if !ok {
+			project, err := templaterepo.NewProductColl().Find(env.ProductName)
+			if err != nil {
+				logger.Errorf("failed to find project %s: %s", env.ProductName, err)
+				continue
+			}
+			isVMProject = project.IsCVMProduct()
+			vmProjects[env.ProductName] = isVMProject
+		}
+		if isVMProject || env.Namespace == "" {
+			continue
+		}
+
+		usage, err := sampleEnvResourceUsage(env, logger)
+		if err != nil {
+			logger.Errorf("failed to sample resource usage of env %s/%s: %s", env.ProductName, env.EnvName, err)
+			continue
+		}
+		usage.CreateTime = now
+		if err := coll.Create(usage); err != nil {
+			logger.Errorf("failed to create resource usage of env %s/%s: %s", env.ProductName, env.EnvName, err)
+		}
+	}
+
+	if err := coll.DeleteBefore(time.Now().AddDate(0, 0, -envResourceUsageRetentionDays).Unix()); err != nil {
+		logger.Errorf("failed to delete outdated env resource usages: %s", err)
+	}
+}
+
+func sampleEnvResourceUsage(env *commonmodels.Product, log *zap.SugaredLogger) (*commonmodels.EnvResourceUsage, error) {
+	clientset, err := kubeclient.GetKubeClientSet(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube clientset: %s", err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(env.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %s", err)
+	}
+
+	requestedCPU, requestedMemory := resource.NewQuantity(0, resource.DecimalSI), resource.NewQuantity(0, resource.BinarySI)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+				requestedCPU.Add(cpu)
+			}
+			if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+				requestedMemory.Add(memory)
+			}
+		}
+	}
+
+	usage := &commonmodels.EnvResourceUsage{
+		ProductName:     env.ProductName,
+		EnvName:         env.EnvName,
+		Production:      env.Production,
+		ClusterID:       env.ClusterID,
+		Namespace:       env.Namespace,
+		RequestedCPU:    float64(requestedCPU.MilliValue()) / 1000,
+		RequestedMemory: float64(requestedMemory.Value()) / gib,
+	}
+
+	// the usage is left empty if the metrics-server is not available in the cluster
+	metricsClient, err := kubeclient.GetKubeMetricsClient(config.HubServerAddress(), env.ClusterID)
+	if err != nil {
+		log.Warnf("failed to get metrics client of cluster %s: %s", env.ClusterID, err)
+		return usage, nil
+	}
+	podMetrics, err := metricsClient.PodMetricses(env.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		log.Warnf("failed to list pod metrics of env %s/%s: %s", env.ProductName, env.EnvName, err)
+		return usage, nil
+	}
+
+	usedCPU, usedMemory := resource.NewQuantity(0, resource.DecimalSI), resource.NewQuantity(0, resource.BinarySI)
+	for _, podMetric := range podMetrics.Items {
+		for _, container := range podMetric.Containers {
+			usedCPU.Add(*container.Usage.Cpu())
+			usedMemory.Add(*container.Usage.Memory())
+		}
+	}
+	usage.UsedCPU = float64(usedCPU.MilliValue()) / 1000
+	usage.UsedMemory = float64(usedMemory.Value()) / gib
+
+	return usage, nil
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	s3service "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/s3"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	s3tool "github.com/koderover/zadig/v2/pkg/tool/s3"
+)
+
+const (
+	ProjectResourceUsageExportFormatCSV  = "csv"
+	ProjectResourceUsageExportFormatJSON = "json"
+
+	resourceUsageMonthLayout = "2006-01"
+)
+
+type ProjectResourceUsageArgs struct {
+	ProjectName string `json:"projectName" form:"projectName"`
+	StartMonth  string `json:"startMonth"  form:"startMonth"`
+	EndMonth    string `json:"endMonth"    form:"endMonth"`
+}
+
+// ProjectResourceUsageScope is the projects the user is able to view, all of them are visible to the system admin
+type ProjectResourceUsageScope struct {
+	IsSystemAdmin bool
+	Projects      []string
+}
+
+type ProjectResourceUsageReport struct {
+	StartMonth string                               `json:"start_month"`
+	EndMonth   string                               `json:"end_month"`
+	Items      []*commonmodels.ProjectResourceUsage `json:"items"`
+}
+
+func GetProjectResourceUsageReport(args *ProjectResourceUsageArgs, scope *ProjectResourceUsageScope, log *zap.SugaredLogger) (*ProjectResourceUsageReport, error) {
+	now := time.Now()
+	if args.EndMonth == "" {
+		args.EndMonth = now.Format(resourceUsageMonthLayout)
+	}
+	if args.StartMonth == "" {
+		args.StartMonth = now.AddDate(0, -5, 0).Format(resourceUsageMonthLayout)
+	}
+	for _, month := range []string{args.StartMonth, args.EndMonth} {
+		if _, err := time.Parse(resourceUsageMonthLayout, month); err != nil {
+			return nil, e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid month %s, it should be in the form of YYYY-MM", month))
+		}
+	}
+	if args.StartMonth > args.EndMonth {
+		return nil, e.ErrInvalidParam.AddDesc("startMonth must not be later than endMonth")
+	}
+
+	opt := &commonrepo.ProjectResourceUsageListOption{
+		ProjectName: args.ProjectName,
+		StartMonth:  args.StartMonth,
+		EndMonth:    args.EndMonth,
+	}
+	if !scope.IsSystemAdmin {
+		opt.Projects = scope.Projects
+	}
+	usages, err := commonrepo.NewProjectResourceUsageColl().List(opt)
+	if err != nil {
+		log.Errorf("failed to list project resource usages, err: %s", err)
+		return nil, e.ErrGetProjectResourceUsage.AddErr(err)
+	}
+
+	return &ProjectResourceUsageReport{
+		StartMonth: args.StartMonth,
+		EndMonth:   args.EndMonth,
+		Items:      usages,
+	}, nil
+}
+
+func ExportProjectResourceUsageReport(args *ProjectResourceUsageArgs, scope *ProjectResourceUsageScope, format string, log *zap.SugaredLogger) ([]byte, string, error) {
+	report, err := GetProjectResourceUsageReport(args, scope, log)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fileName := fmt.Sprintf("project-resource-usage-%s-%s", report.StartMonth, report.EndMonth)
+	switch format {
+	case ProjectResourceUsageExportFormatCSV:
+		data, err := projectResourceUsagesToCSV(report.Items)
+		if err != nil {
+			return nil, "", e.ErrExportProjectResourceUsage.AddErr(err)
+		}
+		return data, fileName + ".csv", nil
+	case ProjectResourceUsageExportFormatJSON, "":
+		data, err := json.MarshalIndent(report.Items, "", "  ")
+		if err != nil {
+			return nil, "", e.ErrExportProjectResourceUsage.AddErr(err)
+		}
+		return data, fileName + ".json", nil
+	default:
+		return nil, "", e.ErrExportProjectResourceUsage.AddDesc(fmt.Sprintf("unsupported format: %s", format))
+	}
+}
+
+func projectResourceUsagesToCSV(usages []*commonmodels.ProjectResourceUsage) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	header := []string{"month", "project_name", "job_count", "job_minutes", "job_cpu_minutes", "job_memory_gib_minutes",
+		"build_cache_bytes", "artifact_bytes", "log_bytes", "storage_update_time"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, u := range usages {
+		row := []string{
+			u.Month,
+			u.ProjectName,
+			strconv.FormatInt(u.JobCount, 10),
+			strconv.FormatFloat(u.JobMinutes, 'f', 2, 64),
+			strconv.FormatFloat(u.JobCPUMinutes, 'f', 2, 64),
+			strconv.FormatFloat(u.JobMemoryGiBMinutes, 'f', 2, 64),
+			strconv.FormatInt(u.BuildCacheBytes, 10),
+			strconv.FormatInt(u.ArtifactBytes, 10),
+			strconv.FormatInt(u.LogBytes, 10),
+			strconv.FormatInt(u.StorageUpdateTime, 10),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// RollupProjectResourceUsage calculates the job usages of the current month and measures the storage usages of all the
+// projects, it is expected to be called daily. The job usages of the last month are recalculated on the first day of
+// a month to include the tasks finished after midnight.
+func RollupProjectResourceUsage() {
+	logger := log.SugaredLogger().With("source", "project resource usage rollup")
+	projects, err := templaterepo.NewProductColl().List()
+	if err != nil {
+		logger.Errorf("failed to list projects: %s", err)
+		return
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	months := []time.Time{monthStart}
+	if now.Day() == 1 {
+		months = append(months, monthStart.AddDate(0, -1, 0))
+	}
+
+	storage, err := s3service.FindDefaultS3()
+	if err != nil {
+		logger.Errorf("failed to find default object storage: %s", err)
+		return
+	}
+	forcedPathStyle := true
+	if storage.Provider == setting.ProviderSourceAli {
+		forcedPathStyle = false
+	}
+	client, err := s3tool.NewClient(storage.Endpoint, storage.Ak, storage.Sk, storage.Region, storage.Insecure, forcedPathStyle)
+	if err != nil {
+		logger.Errorf("failed to create s3 client: %s", err)
+		client = nil
+	}
+
+	coll := commonrepo.NewProjectResourceUsageColl()
+	for _, project := range projects {
+		for _, month := range months {
+			usage, err := calculateProjectJobUsage(project.ProductName, month, month.AddDate(0, 1, 0))
+			if err != nil {
+				logger.Errorf("failed to calculate job usage of project %s in %s: %s", project.ProductName, month.Format(resourceUsageMonthLayout), err)
+				continue
+			}
+			if err := coll.UpsertJobUsage(usage); err != nil {
+				logger.Errorf("failed to save job usage of project %s: %s", project.ProductName, err)
+			}
+		}
+
+		if client == nil {
+			continue
+		}
+		usage, err := measureProjectStorageUsage(project.ProductName, storage, client)
+		if err != nil {
+			logger.Errorf("failed to measure storage usage of project %s: %s", project.ProductName, err)
+			continue
+		}
+		usage.Month = monthStart.Format(resourceUsageMonthLayout)
+		if err := coll.UpsertStorageUsage(usage); err != nil {
+			logger.Errorf("failed to save storage usage of project %s: %s", project.ProductName, err)
+		}
+	}
+}
+
+// calculateProjectJobUsage sums up the durations and the resource limits of the pod jobs in the tasks created in [start, end),
+// including the tasks moved into the cold storage
+func calculateProjectJobUsage(projectName string, start, end time.Time) (*commonmodels.ProjectResourceUsage, error) {
+	usage := &commonmodels.ProjectResourceUsage{
+		ProjectName: projectName,
+		Month:       start.Format(resourceUsageMonthLayout),
+	}
+
+	cursor, err := commonrepo.NewworkflowTaskv4Coll().ListByCursor(&commonrepo.ListWorkflowTaskV4Option{
+		ProjectName: projectName,
+		CreateTime:  start.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		task := new(commonmodels.WorkflowTask)
+		if err := cursor.Decode(task); err != nil {
+			return nil, err
+		}
+		if task.CreateTime >= end.Unix() {
+			continue
+		}
+		addTaskJobUsage(usage, task)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	coldTasks, err := commonrepo.NewWorkflowTaskColdColl().ListTasks(projectName, start.Unix(), end.Unix()-1)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range coldTasks {
+		addTaskJobUsage(usage, task)
+	}
+	return usage, nil
+}
+
+func addTaskJobUsage(usage *commonmodels.ProjectResourceUsage, task *commonmodels.WorkflowTask) {
+	for _, stage := range task.Stages {
+		for _, job := range stage.Jobs {
+			if job.StartTime <= 0 || job.EndTime <= job.StartTime {
+				continue
+			}
+			spec := &struct {
+				Properties commonmodels.JobProperties `json:"properties"`
+			}{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Properties.ResourceRequest == "" {
+				continue
+			}
+			if spec.Properties.Infrastructure == setting.JobVMInfrastructure {
+				continue
+			}
+
+			minutes := float64(job.EndTime-job.StartTime) / 60
+			reqSpec := jobResourceRequestSpec(spec.Properties.ResourceRequest, spec.Properties.ResReqSpec)
+			usage.JobCount++
+			usage.JobMinutes += minutes
+			usage.JobCPUMinutes += minutes * float64(reqSpec.CpuLimit) / 1000
+			usage.JobMemoryGiBMinutes += minutes * float64(reqSpec.MemoryLimit) / 1024
+		}
+	}
+}
+
+// jobResourceRequestSpec returns the spec of the resource request the same way the job pods are built
+func jobResourceRequestSpec(req setting.Request, spec setting.RequestSpec) setting.RequestSpec {
+	switch req {
+	case setting.HighRequest:
+		return setting.HighRequestSpec
+	case setting.MediumRequest:
+		return setting.MediumRequestSpec
+	case setting.LowRequest:
+		return setting.LowRequestSpec
+	case setting.MinRequest:
+		return setting.MinRequestSpec
+	case setting.DefineRequest:
+		return spec
+	default:
+		return setting.DefaultRequestSpec
+	}
+}
+
+// measureProjectStorageUsage sums up the sizes of the objects of the project's workflows in the default object storage,
+// the objects are stored under <workflow>/<task id>/ and the logs are in the log directories. The build caches are
+// counted by their records since they can be stored in any object storage.
+func measureProjectStorageUsage(projectName string, storage *s3service.S3, client *s3tool.Client) (*commonmodels.ProjectResourceUsage, error) {
+	usage := &commonmodels.ProjectResourceUsage{ProjectName: projectName}
+	workflows, err := commonrepo.NewWorkflowV4Coll().ListByProjectNames([]string{projectName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %s", err)
+	}
+
+	for _, workflow := range workflows {
+		prefix := storage.GetObjectPath(workflow.Name) + "/"
+		objects, err := client.ListFilesWithSize(storage.Bucket, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects of workflow %s: %s", workflow.Name, err)
+		}
+		for key, size := range objects {
+			switch {
+			case strings.HasPrefix(key, prefix+"cache/"):
+			case strings.Contains(strings.TrimPrefix(key, prefix), "/log/"):
+				usage.LogBytes += size
+			default:
+				usage.ArtifactBytes += size
+			}
+		}
+
+		caches, err := commonrepo.NewBuildCacheColl().List(&commonrepo.BuildCacheListOption{WorkflowName: workflow.Name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list build caches of workflow %s: %s", workflow.Name, err)
+		}
+		for _, cache := range caches {
+			usage.BuildCacheBytes += cache.Size
+		}
+	}
+	return usage, nil
+}
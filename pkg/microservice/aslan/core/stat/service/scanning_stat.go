@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+)
+
+type ScanningTrend struct {
+	ProjectName  string                `json:"project_name"`
+	ScanningName string                `json:"scanning_name"`
+	Points       []*ScanningTrendPoint `json:"points"`
+}
+
+type ScanningTrendPoint struct {
+	Date              string  `json:"date"`
+	WorkflowName      string  `json:"workflow_name"`
+	TaskID            int64   `json:"task_id"`
+	Bugs              int64   `json:"bugs"`
+	Vulnerabilities   int64   `json:"vulnerabilities"`
+	CodeSmells        int64   `json:"code_smells"`
+	Coverage          float64 `json:"coverage"`
+	Ncloc             int64   `json:"ncloc"`
+	QualityGateStatus string  `json:"quality_gate_status"`
+}
+
+// GetScanningTrend returns the daily sonar metrics of every scanning in the given projects,
+// the metrics of the last task is used if a scanning runs more than once in a day.
+func GetScanningTrend(startTime, endTime int64, projects []string, scanningName string, log *zap.SugaredLogger) ([]*ScanningTrend, error) {
+	metricsList, err := commonrepo.NewScanningMetricsColl().GetDailyTrend(&commonrepo.ScanningMetricsTrendOption{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Projects:     projects,
+		ScanningName: scanningName,
+	})
+	if err != nil {
+		log.Errorf("failed to get scanning metrics trend, error: %s", err)
+		return nil, fmt.Errorf("failed to get scanning metrics trend, error: %s", err)
+	}
+
+	resp := make([]*ScanningTrend, 0)
+	var current *ScanningTrend
+	// the metrics are sorted by project, scanning and date
+	for _, metrics := range metricsList {
+		if current == nil || current.ProjectName != metrics.ProjectName || current.ScanningName != metrics.ScanningName {
+			current = &ScanningTrend{
+				ProjectName:  metrics.ProjectName,
+				ScanningName: metrics.ScanningName,
+				Points:       make([]*ScanningTrendPoint, 0),
+			}
+			resp = append(resp, current)
+		}
+		current.Points = append(current.Points, &ScanningTrendPoint{
+			Date:              metrics.Date,
+			WorkflowName:      metrics.WorkflowName,
+			TaskID:            metrics.TaskID,
+			Bugs:              metrics.Bugs,
+			Vulnerabilities:   metrics.Vulnerabilities,
+			CodeSmells:        metrics.CodeSmells,
+			Coverage:          metrics.Coverage,
+			Ncloc:             metrics.Ncloc,
+			QualityGateStatus: metrics.QualityGateStatus,
+		})
+	}
+	return resp, nil
+}
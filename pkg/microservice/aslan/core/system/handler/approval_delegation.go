@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Approval Delegations
+// @Description List the approval delegations of the user, system admins can list the delegations of all the users
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	uid		query		string							false	"user id, only system admins can list other users' delegations"
+// @Success 200 	{array} 	models.ApprovalDelegation
+// @Router /api/aslan/system/approval/delegations [get]
+func ListApprovalDelegations(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	uid := c.Query("uid")
+	if !ctx.Resources.IsSystemAdmin {
+		if uid != "" && uid != ctx.UserID {
+			ctx.UnAuthorized = true
+			return
+		}
+		uid = ctx.UserID
+	}
+
+	ctx.Resp, ctx.Err = service.ListApprovalDelegations(uid, ctx.Logger)
+}
+
+// @Summary Create Approval Delegation
+// @Description Delegate the native approvals of the user to another user or a user group in the time range
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		service.CreateApprovalDelegationArgs 	true 	"body"
+// @Success 200 	{object} 	models.ApprovalDelegation
+// @Router /api/aslan/system/approval/delegations [post]
+func CreateApprovalDelegation(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.CreateApprovalDelegationArgs)
+	data, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if err = json.Unmarshal(data, args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(data))
+
+	if args.UserID == "" {
+		args.UserID = ctx.UserID
+	}
+	if args.UserID != ctx.UserID && !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新建", "审批委托", fmt.Sprintf("%s->%s", args.UserID, args.DelegateID), string(data), ctx.Logger)
+
+	ctx.Resp, ctx.Err = service.CreateApprovalDelegation(args, ctx.UserName, ctx.Logger)
+}
+
+// @Summary Delete Approval Delegation
+// @Description Delete the approval delegation, the approvals already rerouted to the delegate are not affected
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string							true	"delegation id"
+// @Success 200
+// @Router /api/aslan/system/approval/delegations/{id} [delete]
+func DeleteApprovalDelegation(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "审批委托", c.Param("id"), "", ctx.Logger)
+
+	ctx.Err = service.DeleteApprovalDelegation(c.Param("id"), ctx.UserID, ctx.Resources.IsSystemAdmin, ctx.Logger)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Approval Records
+// @Description List the native approval decisions of workflows and release plans in the order they are made
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	source		query		string							false	"workflow or release_plan"
+// @Param 	projectName	query		string							false	"project name"
+// @Param 	startTime	query		int								false	"start time"
+// @Param 	endTime		query		int								false	"end time"
+// @Param 	pageNum		query		int								false	"page num"
+// @Param 	pageSize	query		int								false	"page size"
+// @Success 200 	{object} 	service.ListApprovalRecordsResp
+// @Router /api/aslan/system/approval/records [get]
+func ListApprovalRecords(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	args := new(service.ListApprovalRecordsArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListApprovalRecords(args, ctx.Logger)
+}
+
+// @Summary Export Approval Records
+// @Description Export the approval records with their hashes in json or csv format
+// @Tags 	system
+// @Accept 	json
+// @Produce octet-stream
+// @Param 	format		query		string							false	"json or csv, default json"
+// @Param 	source		query		string							false	"workflow or release_plan"
+// @Param 	projectName	query		string							false	"project name"
+// @Param 	startTime	query		int								false	"start time"
+// @Param 	endTime		query		int								false	"end time"
+// @Success 200
+// @Router /api/aslan/system/approval/records/export [get]
+func ExportApprovalRecords(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+
+	args := new(service.ListApprovalRecordsArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	data, fileName, err := service.ExportApprovalRecords(args, c.Query("format"), ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, args.ProjectName, "导出", "审批记录", fileName, "", ctx.Logger)
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, "application/octet-stream", data)
+}
+
+// @Summary Verify Approval Records
+// @Description Verify the hash chain of the approval records to find out whether any record was modified or deleted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{object} 	service.ApprovalRecordVerification
+// @Router /api/aslan/system/approval/records/verify [get]
+func VerifyApprovalRecords(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.VerifyApprovalRecords(ctx.Logger)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Argo CD
+// @Description List Argo CD, the token is omitted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.ArgoCD
+// @Router /api/aslan/system/argocd [get]
+func ListArgoCD(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListArgoCD(false)
+}
+
+// @Summary List Argo CD Detail
+// @Description List Argo CD with the token
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.ArgoCD
+// @Router /api/aslan/system/argocd/detail [get]
+func ListArgoCDDetail(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListArgoCD(true)
+}
+
+// @Summary Create Argo CD
+// @Description Create Argo CD
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ArgoCD 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/argocd [post]
+func CreateArgoCD(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ArgoCD
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新增", "系统配置-Argo CD", args.Name, "", ctx.Logger)
+	ctx.Err = service.CreateArgoCD(&args)
+}
+
+// @Summary Update Argo CD
+// @Description Update Argo CD
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"argo cd id"
+// @Param 	body 	body 		commonmodels.ArgoCD 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/argocd/{id} [put]
+func UpdateArgoCD(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ArgoCD
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "更新", "系统配置-Argo CD", args.Name, "", ctx.Logger)
+	ctx.Err = service.UpdateArgoCD(c.Param("id"), &args)
+}
+
+// @Summary Delete Argo CD
+// @Description Delete Argo CD
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"argo cd id"
+// @Success 200
+// @Router /api/aslan/system/argocd/{id} [delete]
+func DeleteArgoCD(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统配置-Argo CD", c.Param("id"), "", ctx.Logger)
+	ctx.Err = service.DeleteArgoCD(c.Param("id"))
+}
+
+// @Summary Validate Argo CD
+// @Description Validate Argo CD
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ArgoCD 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/argocd/validate [post]
+func ValidateArgoCD(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ArgoCD
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	ctx.Err = service.ValidateArgoCD(&args)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Artifact Repository
+// @Description List Artifact Repository, the credentials are omitted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.ArtifactRepository
+// @Router /api/aslan/system/artifactRepository [get]
+func ListArtifactRepository(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListArtifactRepository(false)
+}
+
+// @Summary List Artifact Repository Detail
+// @Description List Artifact Repository with the credentials
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.ArtifactRepository
+// @Router /api/aslan/system/artifactRepository/detail [get]
+func ListArtifactRepositoryDetail(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListArtifactRepository(true)
+}
+
+// @Summary Create Artifact Repository
+// @Description Create Artifact Repository
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ArtifactRepository 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/artifactRepository [post]
+func CreateArtifactRepository(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ArtifactRepository
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新增", "系统配置-制品仓库", args.Name, "", ctx.Logger)
+	ctx.Err = service.CreateArtifactRepository(&args)
+}
+
+// @Summary Update Artifact Repository
+// @Description Update Artifact Repository
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"artifact repository id"
+// @Param 	body 	body 		commonmodels.ArtifactRepository 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/artifactRepository/{id} [put]
+func UpdateArtifactRepository(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ArtifactRepository
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "更新", "系统配置-制品仓库", args.Name, "", ctx.Logger)
+	ctx.Err = service.UpdateArtifactRepository(c.Param("id"), &args)
+}
+
+// @Summary Delete Artifact Repository
+// @Description Delete Artifact Repository
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"artifact repository id"
+// @Success 200
+// @Router /api/aslan/system/artifactRepository/{id} [delete]
+func DeleteArtifactRepository(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统配置-制品仓库", c.Param("id"), "", ctx.Logger)
+	ctx.Err = service.DeleteArtifactRepository(c.Param("id"))
+}
+
+// @Summary Validate Artifact Repository
+// @Description Validate Artifact Repository
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ArtifactRepository 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/artifactRepository/validate [post]
+func ValidateArtifactRepository(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ArtifactRepository
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	ctx.Err = service.ValidateArtifactRepository(&args)
+}
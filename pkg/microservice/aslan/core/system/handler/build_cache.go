@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Build Caches
+// @Description List the object storage caches of build jobs with the hit statistics
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	query		string								false	"workflow name"
+// @Param 	serviceName		query		string								false	"service name"
+// @Success 200 			{object} 	service.ListBuildCachesResp
+// @Router /api/aslan/system/buildCache [get]
+func ListBuildCaches(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListBuildCaches(c.Query("workflowName"), c.Query("serviceName"), ctx.Logger)
+}
+
+// @Summary Prune Build Caches
+// @Description Prune the specified build caches, or the ones not used for unused_days
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 			body 		service.PruneBuildCachesArgs 		true 	"body"
+// @Success 200 			{object} 	service.PruneBuildCachesResp
+// @Router /api/aslan/system/buildCache/prune [post]
+func PruneBuildCaches(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.PruneBuildCachesArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	if !args.DryRun {
+		internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "构建缓存",
+			fmt.Sprintf("ids:%s, unused days:%d", strings.Join(args.IDs, ","), args.UnusedDays), "", ctx.Logger)
+	}
+
+	ctx.Resp, ctx.Err = service.PruneBuildCaches(args, ctx.Logger)
+}
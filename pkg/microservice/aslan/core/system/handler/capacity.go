@@ -18,12 +18,14 @@ package handler
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 )
 
 const (
@@ -138,3 +140,73 @@ func GetStrategy(c *gin.Context) {
 	}
 	ctx.Resp = resp
 }
+
+// @Summary List Cold Workflow Tasks
+// @Description List the workflow tasks moved into the cold storage
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	query		string							false	"workflow name"
+// @Param 	projectName		query		string							false	"project name"
+// @Param 	pageNum			query		int								false	"page num"
+// @Param 	pageSize		query		int								false	"page size"
+// @Success 200 	{object} 	service.ListColdWorkflowTasksResp
+// @Router /api/aslan/system/capacity/coldstorage/tasks [get]
+func ListColdWorkflowTasks(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.ListColdWorkflowTasksArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListColdWorkflowTasks(args, ctx.Logger)
+}
+
+// @Summary Rehydrate Workflow Task
+// @Description Move a workflow task back from the cold storage into the primary collection
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	taskID			path		int								true	"task id"
+// @Success 200
+// @Router /api/aslan/system/capacity/coldstorage/tasks/{workflowName}/{taskID}/rehydrate [post]
+func RehydrateWorkflowTask(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+
+	ctx.Err = service.RehydrateWorkflowTask(c.Param("workflowName"), taskID, ctx.Logger)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Contract Registry
+// @Description List Contract Registry, the token is omitted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.ContractRegistry
+// @Router /api/aslan/system/contractRegistry [get]
+func ListContractRegistry(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListContractRegistry(false)
+}
+
+// @Summary List Contract Registry Detail
+// @Description List Contract Registry with the token
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.ContractRegistry
+// @Router /api/aslan/system/contractRegistry/detail [get]
+func ListContractRegistryDetail(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListContractRegistry(true)
+}
+
+// @Summary Create Contract Registry
+// @Description Create Contract Registry
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ContractRegistry 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/contractRegistry [post]
+func CreateContractRegistry(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ContractRegistry
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新增", "系统配置-契约注册中心", args.Name, "", ctx.Logger)
+	ctx.Err = service.CreateContractRegistry(&args)
+}
+
+// @Summary Update Contract Registry
+// @Description Update Contract Registry
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"contract registry id"
+// @Param 	body 	body 		commonmodels.ContractRegistry 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/contractRegistry/{id} [put]
+func UpdateContractRegistry(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ContractRegistry
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "更新", "系统配置-契约注册中心", args.Name, "", ctx.Logger)
+	ctx.Err = service.UpdateContractRegistry(c.Param("id"), &args)
+}
+
+// @Summary Delete Contract Registry
+// @Description Delete Contract Registry
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"contract registry id"
+// @Success 200
+// @Router /api/aslan/system/contractRegistry/{id} [delete]
+func DeleteContractRegistry(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统配置-契约注册中心", c.Param("id"), "", ctx.Logger)
+	ctx.Err = service.DeleteContractRegistry(c.Param("id"))
+}
+
+// @Summary Validate Contract Registry
+// @Description Validate Contract Registry
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ContractRegistry 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/contractRegistry/validate [post]
+func ValidateContractRegistry(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ContractRegistry
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	ctx.Err = service.ValidateContractRegistry(&args)
+}
@@ -17,11 +17,16 @@
 package handler
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	systemservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 )
@@ -37,7 +42,7 @@ func CreateFavorite(c *gin.Context) {
 		Type:        c.Param("type"),
 	}
 	switch f.Type {
-	case service.FavoriteTypeEnv:
+	case service.FavoriteTypeEnv, service.FavoriteTypeService, service.FavoriteTypeWorkflow:
 		if f.ProductName == "" {
 			ctx.Err = e.ErrInvalidParam.AddDesc("empty projectName")
 			return
@@ -62,7 +67,7 @@ func DeleteFavorite(c *gin.Context) {
 		Type:        c.Param("type"),
 	}
 	switch f.Type {
-	case service.FavoriteTypeEnv:
+	case service.FavoriteTypeEnv, service.FavoriteTypeService, service.FavoriteTypeWorkflow:
 		if f.ProductName == "" {
 			ctx.Err = e.ErrInvalidParam.AddDesc("empty projectName")
 			return
@@ -75,3 +80,120 @@ func DeleteFavorite(c *gin.Context) {
 	ctx.Err = service.DeleteFavorite(f)
 	return
 }
+
+// authorizedProjects returns the projects the user can access, nil means all the projects
+func authorizedProjects(ctx *internalhandler.Context) sets.String {
+	if ctx.Resources.IsSystemAdmin {
+		return nil
+	}
+	projects := sets.NewString()
+	for projectKey := range ctx.Resources.ProjectAuthInfo {
+		projects.Insert(projectKey)
+	}
+	return projects
+}
+
+// @Summary List Favorites
+// @Description List the favorite workflows, environments and services of the current user with their status
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	type		query		string								false	"resource type, environment, service or workflow_v4"
+// @Success 200 		{array} 	systemservice.UserResourceItem
+// @Router /api/aslan/system/favorite [get]
+func ListFavorites(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	resourceType := c.Query("type")
+	if resourceType != "" && !systemservice.IsValidUserResourceType(resourceType) {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid type")
+		return
+	}
+
+	ctx.Resp, ctx.Err = systemservice.ListFavoriteResources(ctx.UserID, resourceType, authorizedProjects(ctx), ctx.Logger)
+}
+
+// @Summary Record Recent Item
+// @Description Record the visit of a workflow, environment or service
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	type		path		string								true	"resource type, environment, service or workflow_v4"
+// @Param 	name		path		string								true	"resource name"
+// @Param 	projectName	query		string								true	"project name"
+// @Success 200
+// @Router /api/aslan/system/recent/{type}/{name} [post]
+func RecordRecentItem(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	resourceType, projectName := c.Param("type"), c.Query("projectName")
+	if !systemservice.IsValidUserResourceType(resourceType) {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid type")
+		return
+	}
+	if projectName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("empty projectName")
+		return
+	}
+
+	ctx.Err = systemservice.RecordRecentResource(ctx.UserID, projectName, c.Param("name"), resourceType)
+}
+
+// @Summary List Recent Items
+// @Description List the resources recently visited by the current user with their status
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	type		query		string								false	"resource type, environment, service or workflow_v4"
+// @Param 	limit		query		int									false	"max number of items, default is 20"
+// @Success 200 		{array} 	systemservice.UserResourceItem
+// @Router /api/aslan/system/recent [get]
+func ListRecentItems(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	resourceType := c.Query("type")
+	if resourceType != "" && !systemservice.IsValidUserResourceType(resourceType) {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid type")
+		return
+	}
+
+	var limit int64
+	if c.Query("limit") != "" {
+		limit, err = strconv.ParseInt(c.Query("limit"), 10, 64)
+		if err != nil {
+			ctx.Err = e.ErrInvalidParam.AddDesc("invalid limit")
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = systemservice.ListRecentResources(ctx.UserID, resourceType, limit, authorizedProjects(ctx), ctx.Logger)
+}
+
+// @Summary Clear Recent Items
+// @Description Clear the recent items of the current user
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200
+// @Router /api/aslan/system/recent [delete]
+func ClearRecentItems(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Err = systemservice.ClearRecentResources(ctx.UserID)
+}
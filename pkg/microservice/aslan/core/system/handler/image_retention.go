@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Image Retention Policies
+// @Description List the retention policies which clean up the stale image tags in the registries
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 			{array} 	commonmodels.ImageRetentionPolicy
+// @Router /api/aslan/system/registry/retentionPolicies [get]
+func ListImageRetentionPolicies(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.ListImageRetentionPolicies(ctx.Logger)
+}
+
+// @Summary Get Image Retention Policy
+// @Description Get Image Retention Policy
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 				path		string								true	"policy id"
+// @Success 200 			{object} 	commonmodels.ImageRetentionPolicy
+// @Router /api/aslan/system/registry/retentionPolicies/{id} [get]
+func GetImageRetentionPolicy(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.GetImageRetentionPolicy(c.Param("id"), ctx.Logger)
+}
+
+// @Summary Create Image Retention Policy
+// @Description Create Image Retention Policy
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 			body 		commonmodels.ImageRetentionPolicy 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/registry/retentionPolicies [post]
+func CreateImageRetentionPolicy(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.Create {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	args := new(commonmodels.ImageRetentionPolicy)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新增", "系统设置-镜像清理策略", args.Name, "", ctx.Logger)
+
+	ctx.Err = service.CreateImageRetentionPolicy(ctx.UserName, args, ctx.Logger)
+}
+
+// @Summary Update Image Retention Policy
+// @Description Update Image Retention Policy
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 				path		string								true	"policy id"
+// @Param 	body 			body 		commonmodels.ImageRetentionPolicy 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/registry/retentionPolicies/{id} [put]
+func UpdateImageRetentionPolicy(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.Edit {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	args := new(commonmodels.ImageRetentionPolicy)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "更新", "系统设置-镜像清理策略", args.Name, "", ctx.Logger)
+
+	ctx.Err = service.UpdateImageRetentionPolicy(ctx.UserName, c.Param("id"), args, ctx.Logger)
+}
+
+// @Summary Delete Image Retention Policy
+// @Description Delete Image Retention Policy
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 				path		string								true	"policy id"
+// @Success 200
+// @Router /api/aslan/system/registry/retentionPolicies/{id} [delete]
+func DeleteImageRetentionPolicy(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.Delete {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统设置-镜像清理策略", c.Param("id"), "", ctx.Logger)
+
+	ctx.Err = service.DeleteImageRetentionPolicy(c.Param("id"), ctx.Logger)
+}
+
+// @Summary Preview Image Retention
+// @Description Dry run the image retention policy and return the tags which would be deleted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 			body 		commonmodels.ImageRetentionPolicy 	true 	"body"
+// @Success 200 			{object} 	service.ImageRetentionResult
+// @Router /api/aslan/system/registry/retentionPolicies/preview [post]
+func PreviewImageRetention(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	args := new(commonmodels.ImageRetentionPolicy)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.PreviewImageRetention(args, ctx.Logger)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Audit Mongo Indexes
+// @Description Report the missing managed indexes, the duplicate or redundant indexes and the slow query samples of the database
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	slowThreshold	query		int								false	"slow query threshold in milliseconds, default 100"
+// @Param 	slowLimit		query		int								false	"max number of slow query samples, default 20"
+// @Success 200 	{object} 	service.MongoIndexAuditResp
+// @Router /api/aslan/system/mongo/indexes/audit [get]
+func AuditMongoIndexes(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	args := new(service.MongoIndexAuditArgs)
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.AuditMongoIndexes(args, ctx.Logger)
+}
@@ -21,23 +21,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"time"
 
 	"github.com/gin-gonic/gin"
 
-	configbase "github.com/koderover/zadig/v2/pkg/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
-	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
-	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
-	clusterservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/multicluster/service"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
-	"github.com/koderover/zadig/v2/pkg/types"
 )
 
+// @Summary OpenAPI Create Registry
+// @Description OpenAPI Create Registry
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	service.OpenAPICreateRegistryReq	true	"body"
+// @Success 200
+// @Router /openapi/system/registry [post]
 func OpenAPICreateRegistry(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -76,9 +78,16 @@ func OpenAPICreateRegistry(c *gin.Context) {
 		return
 	}
 
-	ctx.Err = service.OpenAPICreateRegistry(ctx.UserName, args, ctx.Logger)
+	_, ctx.Err = service.OpenAPICreateRegistry(ctx.UserName, args, ctx.Logger)
 }
 
+// @Summary OpenAPI List Registry
+// @Description OpenAPI List Registry
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	service.OpenAPIRegistry
+// @Router /openapi/system/registry [get]
 func OpenAPIListRegistry(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -103,6 +112,14 @@ func OpenAPIListRegistry(c *gin.Context) {
 	ctx.Resp = resp
 }
 
+// @Summary OpenAPI Get Registry
+// @Description OpenAPI Get Registry
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id	path	string	true	"id"
+// @Success 200 	{object} 	service.OpenAPIRegistry
+// @Router /openapi/system/registry/{id} [get]
 func OpenAPIGetRegistry(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -136,6 +153,15 @@ func OpenAPIGetRegistry(c *gin.Context) {
 	ctx.Resp = ret
 }
 
+// @Summary OpenAPI Update Registry
+// @Description OpenAPI Update Registry
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id	path	string	true	"id"
+// @Param 	body	body	service.OpenAPIRegistry	true	"body"
+// @Success 200
+// @Router /openapi/system/registry/{id} [put]
 func OpenAPIUpdateRegistry(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -184,6 +210,14 @@ func OpenAPIUpdateRegistry(c *gin.Context) {
 	ctx.Err = service.UpdateRegistryNamespace(ctx.UserName, c.Param("id"), registryInfo, ctx.Logger)
 }
 
+// @Summary OpenAPI List Cluster
+// @Description OpenAPI List Cluster
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectName	query	string	false	"project name"
+// @Success 200 	{array} 	service.OpenAPICluster
+// @Router /openapi/system/cluster [get]
 func OpenAPIListCluster(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -223,122 +257,18 @@ func OpenAPICreateCluster(c *gin.Context) {
 	}
 	internalhandler.InsertOperationLog(c, ctx.UserName+"(openAPI)", "", "创建", "资源配置-集群", req.Name, "", ctx.Logger)
 
-	clusterAccessYaml := `apiVersion: rbac.authorization.k8s.io/v1
-kind: ClusterRole
-metadata:
-  name: koderover-agent-admin
-rules:
-- apiGroups:
-  - '*'
-  resources:
-  - '*'
-  verbs:
-  - '*'
-- nonResourceURLs:
-  - '*'
-  verbs:
-  - '*'
-`
-
-	AdvancedConfig := &clusterservice.AdvancedConfig{
-		ClusterAccessYaml: clusterAccessYaml,
-		ProjectNames:      req.ProjectNames,
-		ScheduleWorkflow:  true,
-		ScheduleStrategy: []*clusterservice.ScheduleStrategy{
-			{
-				NodeLabels:   []string{},
-				StrategyName: "normal",
-				Strategy:     "normal",
-				Default:      true,
-			},
-		},
-	}
-	dindCfg := &models.DindCfg{
-		Replicas: 1,
-		Resources: &models.Resources{
-			Limits: &models.Limits{
-				CPU:    4000,
-				Memory: 8192,
-			},
-		},
-		Storage: &models.DindStorage{
-			StorageSizeInGiB: 10,
-			Type:             models.DindStorageRootfs,
-		},
-	}
-	shareStorage := types.ShareStorage{
-		MediumType: types.NFSMedium,
-		NFSProperties: types.NFSProperties{
-			PVC:              "cache-cfs-10",
-			StorageClass:     "cfs",
-			StorageSizeInGiB: 10,
-		},
-	}
-
-	cluster := &clusterservice.K8SCluster{
-		Name:           req.Name,
-		Type:           req.Type,
-		Provider:       req.Provider,
-		KubeConfig:     req.KubeConfig,
-		Description:    req.Description,
-		Production:     req.Production,
-		AdvancedConfig: AdvancedConfig,
-		DindCfg:        dindCfg,
-		ShareStorage:   shareStorage,
-		CreatedAt:      time.Now().Unix(),
-		CreatedBy:      ctx.UserName + "(openAPI)",
-	}
-
-	if err := cluster.Clean(); err != nil {
-		ctx.Err = e.ErrInvalidParam.AddErr(err)
-		return
-	}
-
-	err = cluster.Validate()
-	if err != nil {
-		ctx.Err = fmt.Errorf("failed to validate cluster: %v", err)
-		return
-	}
-
-	clusterResp, err := clusterservice.CreateCluster(cluster, ctx.Logger)
-	if err != nil {
-		ctx.Err = fmt.Errorf("Failed to create cluster: %v", err)
-		return
-	}
-
-	for _, projectName := range req.ProjectNames {
-		err = commonrepo.NewProjectClusterRelationColl().Create(&models.ProjectClusterRelation{
-			ProjectName: projectName,
-			ClusterID:   clusterResp.ID.Hex(),
-			CreatedBy:   ctx.UserName + "(openAPI)",
-		})
-		if err != nil {
-			log.Errorf("Failed to create projectClusterRelation err:%s", err)
-		}
-	}
-
-	agentCmd := fmt.Sprintf(`kubectl apply -f "%s/api/aslan/cluster/agent/%s/agent.yaml?type=deploy"`, configbase.SystemAddress(), clusterResp.ID.Hex())
-
-	resp := service.OpenAPICreateClusterResponse{
-		Cluster: &service.OpenAPICluster{
-			ID:           clusterResp.ID.Hex(),
-			Name:         clusterResp.Name,
-			Type:         clusterResp.Type,
-			ProviderName: service.ClusterProviderValueNames[clusterResp.Provider],
-			Production:   clusterResp.Production,
-			Description:  clusterResp.Description,
-			ProjectNames: clusterservice.GetProjectNames(clusterResp.ID.Hex(), log.SugaredLogger()),
-			Local:        clusterResp.Local,
-			Status:       string(clusterResp.Status),
-			CreatedBy:    clusterResp.CreatedBy,
-			CreatedTime:  clusterResp.CreatedAt,
-		},
-		AgentCmd: agentCmd,
-	}
-
-	ctx.Resp = resp
+	ctx.Resp, ctx.Err = service.OpenAPICreateCluster(ctx.UserName, req, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Cluster
+// @Description OpenAPI Update Cluster
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id	path	string	true	"id"
+// @Param 	body	body	service.OpenAPICluster	true	"body"
+// @Success 200
+// @Router /openapi/system/cluster/{id} [put]
 func OpenAPIUpdateCluster(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -366,6 +296,14 @@ func OpenAPIUpdateCluster(c *gin.Context) {
 	ctx.Err = service.OpenAPIUpdateCluster(ctx.UserName, c.Param("id"), args, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Cluster
+// @Description OpenAPI Delete Cluster
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	id	path	string	true	"id"
+// @Success 200
+// @Router /openapi/system/cluster/{id} [delete]
 func OpenAPIDeleteCluster(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+)
+
+// @Summary Get OpenAPI Spec
+// @Description Get the OpenAPI 3.0 spec of the OpenAPI routes
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	version	query		string			false	"zadig version, the running version is used if not specified"
+// @Success 200 	{object} 	apispec.Document
+// @Router /openapi/system/apispec [get]
+func OpenAPIGetSpec(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetOpenAPISpec(c.Query("version"), ctx.Logger)
+}
+
+// @Summary List OpenAPI Spec Versions
+// @Description List the zadig versions whose OpenAPI spec is recorded
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	models.OpenAPISpecSnapshot
+// @Router /openapi/system/apispec/versions [get]
+func OpenAPIListSpecVersions(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListOpenAPISpecVersions(ctx.Logger)
+}
+
+// @Summary Diff OpenAPI Spec
+// @Description Get the operations added, removed or changed between the OpenAPI specs of two zadig versions
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	base	query		string			true	"base version"
+// @Param 	target	query		string			false	"target version, the running version is used if not specified"
+// @Success 200 	{object} 	apispec.Diff
+// @Router /openapi/system/apispec/diff [get]
+func OpenAPIDiffSpec(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.DiffOpenAPISpec(c.Query("base"), c.Query("target"), ctx.Logger)
+}
@@ -79,6 +79,8 @@ func ListProjectManagementForProject(c *gin.Context) {
 		pm.MeegoPluginID = ""
 		pm.MeegoPluginSecret = ""
 		pm.MeegoUserKey = ""
+		pm.ServiceNowUser = ""
+		pm.ServiceNowPassword = ""
 	}
 	ctx.Err = err
 	ctx.Resp = pms
@@ -193,6 +195,8 @@ func Validate(c *gin.Context) {
 		ctx.Err = service.ValidateJira(req)
 	case setting.PMMeego:
 		ctx.Err = service.ValidateMeego(req)
+	case setting.PMServiceNow:
+		ctx.Err = service.ValidateServiceNow(req)
 	default:
 		ctx.Err = e.ErrValidateProjectManagement.AddDesc("invalid type")
 	}
@@ -28,6 +28,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/shared/client/plutusvendor"
@@ -291,7 +292,65 @@ func DeleteRegistryNamespace(c *gin.Context) {
 		}
 	}
 
-	ctx.Err = service.DeleteRegistryNamespace(c.Param("id"), ctx.Logger)
+	ctx.Err = service.DeleteRegistryNamespace(c.Param("id"), c.Query("force") == "true", ctx.Logger)
+}
+
+// @Summary Get Registry Namespace References
+// @Description Get the environments, builds and workflows using the registry before it is deleted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string									true	"registry id"
+// @Success 200 	{object} 	integration.References
+// @Router /api/aslan/system/registry/namespaces/{id}/references [get]
+func GetRegistryNamespaceReferences(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.Delete {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = integration.GetRegistryReferences(c.Param("id"))
+}
+
+// @Summary Check Registry Credential
+// @Description Log in the registry with its credential and save the result as the credential status
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string									true	"registry id"
+// @Success 200 	{object} 	commonmodels.RegistryCredentialStatus
+// @Router /api/aslan/system/registry/namespaces/{id}/credential/check [post]
+func CheckRegistryCredential(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.RegistryManagement.Edit {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = service.CheckRegistryCredential(c.Param("id"), ctx.Logger)
 }
 
 func ListAllRepos(c *gin.Context) {
@@ -65,9 +65,18 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		registry.PUT("/namespaces/:id", UpdateRegistryNamespace)
 
 		registry.DELETE("/namespaces/:id", DeleteRegistryNamespace)
+		registry.GET("/namespaces/:id/references", GetRegistryNamespaceReferences)
+		registry.POST("/namespaces/:id/credential/check", CheckRegistryCredential)
 		registry.GET("/release/repos", ListAllRepos)
 		registry.POST("/images", ListImages)
 		registry.GET("/images/repos/:name", ListRepoImages)
+
+		registry.GET("/retentionPolicies", ListImageRetentionPolicies)
+		registry.POST("/retentionPolicies", CreateImageRetentionPolicy)
+		registry.POST("/retentionPolicies/preview", PreviewImageRetention)
+		registry.GET("/retentionPolicies/:id", GetImageRetentionPolicy)
+		registry.PUT("/retentionPolicies/:id", UpdateImageRetentionPolicy)
+		registry.DELETE("/retentionPolicies/:id", DeleteImageRetentionPolicy)
 	}
 
 	s3storage := router.Group("s3storage")
@@ -97,6 +106,13 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		security.GET("", GetSecuritySettings)
 	}
 
+	// k8s yaml lint settings
+	manifestLint := router.Group("manifestLint")
+	{
+		manifestLint.GET("", GetManifestLintSettings)
+		manifestLint.PUT("", UpdateManifestLintSettings)
+	}
+
 	// ---------------------------------------------------------------------------------------
 	// jenkins集成接口以及jobs和buildWithParameters接口
 	// ---------------------------------------------------------------------------------------
@@ -134,6 +150,15 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		capacity.POST("/gc", GarbageCollection)
 		// 清理已被删除的工作流的所有缓存，暂时用于手动调用
 		capacity.POST("/clean", CleanCache)
+		capacity.GET("/coldstorage/tasks", ListColdWorkflowTasks)
+		capacity.POST("/coldstorage/tasks/:workflowName/:taskID/rehydrate", RehydrateWorkflowTask)
+	}
+
+	// object storage cache of build jobs
+	buildCache := router.Group("buildCache")
+	{
+		buildCache.GET("", ListBuildCaches)
+		buildCache.POST("/prune", PruneBuildCaches)
 	}
 
 	// workflow concurrency settings
@@ -141,6 +166,9 @@ func (*Router) Inject(router *gin.RouterGroup) {
 	{
 		concurrency.GET("/workflow", GetWorkflowConcurrency)
 		concurrency.POST("/workflow", UpdateWorkflowConcurrency)
+		concurrency.GET("/workflow/fairness", GetFairSchedulingSettings)
+		concurrency.PUT("/workflow/fairness", UpdateFairSchedulingSettings)
+		concurrency.GET("/workflow/insights", GetWorkflowQueueInsights)
 	}
 
 	// default login default login home page settings
@@ -266,9 +294,91 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		sonar.GET("/integration", ListSonarIntegration)
 		sonar.GET("/integration/:id", GetSonarIntegration)
 		sonar.DELETE("/integration/:id", DeleteSonarIntegration)
+		sonar.GET("/integration/:id/references", GetSonarIntegrationReferences)
 		sonar.POST("/validate", ValidateSonarInformation)
 	}
 
+	// ---------------------------------------------------------------------------------------
+	// semgrep/checkmarx scanner integration API
+	// ---------------------------------------------------------------------------------------
+	scanner := router.Group("scanner")
+	{
+		scanner.GET("", ListScannerIntegration)
+		scanner = scanner.Group("", isSystemAdmin)
+		scanner.GET("/detail", ListScannerIntegrationDetail)
+		scanner.POST("", CreateScannerIntegration)
+		scanner.PUT("/:id", UpdateScannerIntegration)
+		scanner.DELETE("/:id", DeleteScannerIntegration)
+		scanner.POST("/validate", ValidateScannerIntegration)
+	}
+
+	// ---------------------------------------------------------------------------------------
+	// external secret store integration API
+	// ---------------------------------------------------------------------------------------
+	secretProvider := router.Group("secretProvider")
+	{
+		secretProvider.GET("", ListSecretProvider)
+		secretProvider = secretProvider.Group("", isSystemAdmin)
+		secretProvider.GET("/detail", ListSecretProviderDetail)
+		secretProvider.POST("", CreateSecretProvider)
+		secretProvider.PUT("/:id", UpdateSecretProvider)
+		secretProvider.DELETE("/:id", DeleteSecretProvider)
+		secretProvider.POST("/validate", ValidateSecretProvider)
+	}
+
+	// ---------------------------------------------------------------------------------------
+	// artifact repository integration API
+	// ---------------------------------------------------------------------------------------
+	artifactRepository := router.Group("artifactRepository")
+	{
+		artifactRepository.GET("", ListArtifactRepository)
+		artifactRepository = artifactRepository.Group("", isSystemAdmin)
+		artifactRepository.GET("/detail", ListArtifactRepositoryDetail)
+		artifactRepository.POST("", CreateArtifactRepository)
+		artifactRepository.PUT("/:id", UpdateArtifactRepository)
+		artifactRepository.DELETE("/:id", DeleteArtifactRepository)
+		artifactRepository.POST("/validate", ValidateArtifactRepository)
+	}
+
+	// ---------------------------------------------------------------------------------------
+	// argo cd integration API
+	// ---------------------------------------------------------------------------------------
+	argoCD := router.Group("argocd")
+	{
+		argoCD.GET("", ListArgoCD)
+		argoCD = argoCD.Group("", isSystemAdmin)
+		argoCD.GET("/detail", ListArgoCDDetail)
+		argoCD.POST("", CreateArgoCD)
+		argoCD.PUT("/:id", UpdateArgoCD)
+		argoCD.DELETE("/:id", DeleteArgoCD)
+		argoCD.POST("/validate", ValidateArgoCD)
+	}
+
+	// ---------------------------------------------------------------------------------------
+	// contract registry integration API
+	// ---------------------------------------------------------------------------------------
+	contractRegistry := router.Group("contractRegistry")
+	{
+		contractRegistry.GET("", ListContractRegistry)
+		contractRegistry = contractRegistry.Group("", isSystemAdmin)
+		contractRegistry.GET("/detail", ListContractRegistryDetail)
+		contractRegistry.POST("", CreateContractRegistry)
+		contractRegistry.PUT("/:id", UpdateContractRegistry)
+		contractRegistry.DELETE("/:id", DeleteContractRegistry)
+		contractRegistry.POST("/validate", ValidateContractRegistry)
+	}
+
+	// ---------------------------------------------------------------------------------------
+	// sops key management API
+	// ---------------------------------------------------------------------------------------
+	sops := router.Group("sops", isSystemAdmin)
+	{
+		sops.GET("/key", ListSopsKey)
+		sops.POST("/key", CreateSopsKey)
+		sops.PUT("/key/:id", UpdateSopsKey)
+		sops.DELETE("/key/:id", DeleteSopsKey)
+	}
+
 	// ---------------------------------------------------------------------------------------
 	// configuration management integration API
 	// ---------------------------------------------------------------------------------------
@@ -363,6 +473,16 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		dashboard.GET("/workflow/running", GetRunningWorkflow)
 		dashboard.GET("/workflow/mine", GetMyWorkflow)
 		dashboard.GET("/environment/:name", GetMyEnvironment)
+
+		// saved dashboard views
+		dashboard.GET("/views", ListSavedDashboards)
+		dashboard.POST("/views", CreateSavedDashboard)
+		dashboard.PUT("/views/:id", UpdateSavedDashboard)
+		dashboard.DELETE("/views/:id", DeleteSavedDashboard)
+		dashboard.GET("/views/:id/data", GetSavedDashboardData)
+		dashboard.POST("/views/:id/share", ShareSavedDashboard)
+		dashboard.DELETE("/views/:id/share", RevokeSavedDashboardShare)
+		dashboard.GET("/shared/:token", GetSharedDashboardData)
 	}
 
 	// initialization apis
@@ -403,10 +523,43 @@ func (*Router) Inject(router *gin.RouterGroup) {
 	// personal favorite API
 	favorite := router.Group("favorite")
 	{
+		favorite.GET("", ListFavorites)
 		favorite.POST("/:type/:name", CreateFavorite)
 		favorite.DELETE("/:type/:name", DeleteFavorite)
 	}
 
+	// personal recent item API
+	recent := router.Group("recent")
+	{
+		recent.GET("", ListRecentItems)
+		recent.POST("/:type/:name", RecordRecentItem)
+		recent.DELETE("", ClearRecentItems)
+	}
+
+	// user offboarding API
+	offboarding := router.Group("offboarding")
+	{
+		offboarding.GET("/users/:uid", GetUserOffboardingReport)
+		offboarding.POST("/users/:uid/transfer", TransferUserResources)
+		offboarding.GET("/records", ListUserOffboardingRecords)
+	}
+
+	// approval record API, the records are append-only and hash-chained for compliance
+	approvalRecord := router.Group("approval/records", isSystemAdmin)
+	{
+		approvalRecord.GET("", ListApprovalRecords)
+		approvalRecord.GET("/export", ExportApprovalRecords)
+		approvalRecord.GET("/verify", VerifyApprovalRecords)
+	}
+
+	// approval delegation API, users manage their own delegations and system admins manage everyone's
+	approvalDelegation := router.Group("approval/delegations")
+	{
+		approvalDelegation.GET("", ListApprovalDelegations)
+		approvalDelegation.POST("", CreateApprovalDelegation)
+		approvalDelegation.DELETE("/:id", DeleteApprovalDelegation)
+	}
+
 	// ---------------------------------------------------------------------------------------
 	// external system API
 	// ---------------------------------------------------------------------------------------
@@ -428,6 +581,12 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		webhook.GET("/config", GetWebhookConfig)
 	}
 
+	// mongo index audit API
+	mongoIndex := router.Group("mongo/indexes", isSystemAdmin)
+	{
+		mongoIndex.GET("/audit", AuditMongoIndexes)
+	}
+
 	// ---------------------------------------------------------------------------------------
 	// database instance
 	// ---------------------------------------------------------------------------------------
@@ -462,4 +621,11 @@ func (*OpenAPIRouter) Inject(router *gin.RouterGroup) {
 		cluster.PUT("/:id", OpenAPIUpdateCluster)
 		cluster.DELETE("/:id", OpenAPIDeleteCluster)
 	}
+
+	apiSpec := router.Group("apispec")
+	{
+		apiSpec.GET("", OpenAPIGetSpec)
+		apiSpec.GET("/versions", OpenAPIListSpecVersions)
+		apiSpec.GET("/diff", OpenAPIDiffSpec)
+	}
 }
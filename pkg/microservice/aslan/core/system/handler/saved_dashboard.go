@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type shareSavedDashboardResp struct {
+	ShareToken string `json:"share_token"`
+}
+
+func newDashboardViewer(ctx *internalhandler.Context) *service.DashboardViewer {
+	viewer := &service.DashboardViewer{
+		UserID:        ctx.UserID,
+		UserName:      ctx.UserName,
+		IsAdmin:       ctx.Resources.IsSystemAdmin,
+		ProjectAdmins: sets.NewString(),
+	}
+	for projectKey, authInfo := range ctx.Resources.ProjectAuthInfo {
+		if authInfo.IsProjectAdmin {
+			viewer.ProjectAdmins.Insert(projectKey)
+		}
+	}
+	return viewer
+}
+
+// @Summary List Saved Dashboards
+// @Description List the dashboards visible to the current user
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	projectName	query		string									false	"project name"
+// @Success 200 		{array} 	commonmodels.SavedDashboard
+// @Router /api/aslan/system/dashboard/views [get]
+func ListSavedDashboards(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.ListSavedDashboards(newDashboardViewer(ctx), c.Query("projectName"), ctx.Logger)
+}
+
+// @Summary Create Saved Dashboard
+// @Description Create a saved dashboard
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 		body 		commonmodels.SavedDashboard 			true 	"body"
+// @Success 200 		{object} 	commonmodels.SavedDashboard
+// @Router /api/aslan/system/dashboard/views [post]
+func CreateSavedDashboard(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(commonmodels.SavedDashboard)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.CreateSavedDashboard(newDashboardViewer(ctx), args)
+}
+
+// @Summary Update Saved Dashboard
+// @Description Update a saved dashboard, only the creator or the admin can update it
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id			path		string									true	"dashboard id"
+// @Param 	body 		body 		commonmodels.SavedDashboard 			true 	"body"
+// @Success 200
+// @Router /api/aslan/system/dashboard/views/{id} [put]
+func UpdateSavedDashboard(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(commonmodels.SavedDashboard)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	ctx.Err = service.UpdateSavedDashboard(newDashboardViewer(ctx), c.Param("id"), args)
+}
+
+// @Summary Delete Saved Dashboard
+// @Description Delete a saved dashboard, only the creator or the admin can delete it
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id			path		string									true	"dashboard id"
+// @Success 200
+// @Router /api/aslan/system/dashboard/views/{id} [delete]
+func DeleteSavedDashboard(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Err = service.DeleteSavedDashboard(newDashboardViewer(ctx), c.Param("id"))
+}
+
+// @Summary Get Saved Dashboard Data
+// @Description Get the definition and the aggregated widget data of a saved dashboard
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id			path		string									true	"dashboard id"
+// @Success 200 		{object} 	service.SavedDashboardData
+// @Router /api/aslan/system/dashboard/views/{id}/data [get]
+func GetSavedDashboardData(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetSavedDashboardData(newDashboardViewer(ctx), c.Param("id"), ctx.Logger)
+}
+
+// @Summary Share Saved Dashboard
+// @Description Generate a share link token of the saved dashboard, the previous link is revoked
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id			path		string									true	"dashboard id"
+// @Success 200 		{object} 	shareSavedDashboardResp
+// @Router /api/aslan/system/dashboard/views/{id}/share [post]
+func ShareSavedDashboard(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	token, err := service.ShareSavedDashboard(newDashboardViewer(ctx), c.Param("id"))
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	ctx.Resp = &shareSavedDashboardResp{ShareToken: token}
+}
+
+// @Summary Revoke Saved Dashboard Share
+// @Description Revoke the share link of the saved dashboard
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id			path		string									true	"dashboard id"
+// @Success 200
+// @Router /api/aslan/system/dashboard/views/{id}/share [delete]
+func RevokeSavedDashboardShare(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Err = service.RevokeSavedDashboardShare(newDashboardViewer(ctx), c.Param("id"))
+}
+
+// @Summary Get Shared Dashboard Data
+// @Description Get the dashboard of the share link, the data is limited to the projects the current user can access
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	token		path		string									true	"share token"
+// @Success 200 		{object} 	service.SavedDashboardData
+// @Router /api/aslan/system/dashboard/shared/{token} [get]
+func GetSharedDashboardData(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetSharedDashboardData(newDashboardViewer(ctx), c.Param("token"), ctx.Logger)
+}
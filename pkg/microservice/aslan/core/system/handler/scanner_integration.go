@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Scanner Integration
+// @Description List Scanner Integration, tokens are omitted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	type	query		string								false	"scanner type, semgrep or checkmarx"
+// @Success 200 	{array} 	commonmodels.ScannerIntegration
+// @Router /api/aslan/system/scanner [get]
+func ListScannerIntegration(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListScannerIntegration(c.Query("type"), false)
+}
+
+// @Summary List Scanner Integration Detail
+// @Description List Scanner Integration with tokens
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	type	query		string								false	"scanner type, semgrep or checkmarx"
+// @Success 200 	{array} 	commonmodels.ScannerIntegration
+// @Router /api/aslan/system/scanner/detail [get]
+func ListScannerIntegrationDetail(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListScannerIntegration(c.Query("type"), true)
+}
+
+// @Summary Create Scanner Integration
+// @Description Create Scanner Integration
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ScannerIntegration 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/scanner [post]
+func CreateScannerIntegration(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ScannerIntegration
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新增", "系统配置-代码扫描集成", args.Name, "", ctx.Logger)
+	ctx.Err = service.CreateScannerIntegration(&args)
+}
+
+// @Summary Update Scanner Integration
+// @Description Update Scanner Integration
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"scanner integration id"
+// @Param 	body 	body 		commonmodels.ScannerIntegration 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/scanner/{id} [put]
+func UpdateScannerIntegration(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ScannerIntegration
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "更新", "系统配置-代码扫描集成", args.Name, "", ctx.Logger)
+	ctx.Err = service.UpdateScannerIntegration(c.Param("id"), &args)
+}
+
+// @Summary Delete Scanner Integration
+// @Description Delete Scanner Integration
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"scanner integration id"
+// @Success 200
+// @Router /api/aslan/system/scanner/{id} [delete]
+func DeleteScannerIntegration(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统配置-代码扫描集成", c.Param("id"), "", ctx.Logger)
+	ctx.Err = service.DeleteScannerIntegration(c.Param("id"))
+}
+
+// @Summary Validate Scanner Integration
+// @Description Validate Scanner Integration
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.ScannerIntegration 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/scanner/validate [post]
+func ValidateScannerIntegration(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.ScannerIntegration
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	ctx.Err = service.ValidateScannerIntegration(&args)
+}
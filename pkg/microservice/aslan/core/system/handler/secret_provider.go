@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Secret Provider
+// @Description List Secret Provider, the credentials are omitted
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.SecretProvider
+// @Router /api/aslan/system/secretProvider [get]
+func ListSecretProvider(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListSecretProvider(false)
+}
+
+// @Summary List Secret Provider Detail
+// @Description List Secret Provider with the credentials
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.SecretProvider
+// @Router /api/aslan/system/secretProvider/detail [get]
+func ListSecretProviderDetail(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListSecretProvider(true)
+}
+
+// @Summary Create Secret Provider
+// @Description Create Secret Provider
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.SecretProvider 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/secretProvider [post]
+func CreateSecretProvider(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.SecretProvider
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新增", "系统配置-密钥管理集成", args.Name, "", ctx.Logger)
+	ctx.Err = service.CreateSecretProvider(&args)
+}
+
+// @Summary Update Secret Provider
+// @Description Update Secret Provider
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"secret provider id"
+// @Param 	body 	body 		commonmodels.SecretProvider 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/secretProvider/{id} [put]
+func UpdateSecretProvider(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.SecretProvider
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "更新", "系统配置-密钥管理集成", args.Name, "", ctx.Logger)
+	ctx.Err = service.UpdateSecretProvider(c.Param("id"), &args)
+}
+
+// @Summary Delete Secret Provider
+// @Description Delete Secret Provider
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string								true	"secret provider id"
+// @Success 200
+// @Router /api/aslan/system/secretProvider/{id} [delete]
+func DeleteSecretProvider(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统配置-密钥管理集成", c.Param("id"), "", ctx.Logger)
+	ctx.Err = service.DeleteSecretProvider(c.Param("id"))
+}
+
+// @Summary Validate Secret Provider
+// @Description Validate Secret Provider
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.SecretProvider 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/secretProvider/validate [post]
+func ValidateSecretProvider(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.SecretProvider
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	ctx.Err = service.ValidateSecretProvider(&args)
+}
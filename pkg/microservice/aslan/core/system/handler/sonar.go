@@ -25,6 +25,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/tool/crypto"
 
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
@@ -220,7 +221,26 @@ func DeleteSonarIntegration(c *gin.Context) {
 	}
 
 	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统配置-Sonar集成", fmt.Sprintf("id:%s", c.Param("id")), "", ctx.Logger)
-	ctx.Err = service.DeleteSonarIntegration(c.Param("id"), ctx.Logger)
+	ctx.Err = service.DeleteSonarIntegration(c.Param("id"), c.Query("force") == "true", ctx.Logger)
+}
+
+func GetSonarIntegrationReferences(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = integration.GetSonarReferences(c.Param("id"))
 }
 
 func ValidateSonarInformation(c *gin.Context) {
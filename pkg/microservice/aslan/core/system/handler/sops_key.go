@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary List Sops Key
+// @Description List the keys used to decrypt the helm values encrypted by sops
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Success 200 	{array} 	commonmodels.SopsKey
+// @Router /api/aslan/system/sops/key [get]
+func ListSopsKey(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = service.ListSopsKey()
+}
+
+// @Summary Create Sops Key
+// @Description Create Sops Key, the type is age or aws_kms
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.SopsKey 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/sops/key [post]
+func CreateSopsKey(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.SopsKey
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "新增", "系统配置-sops密钥", args.Name, "", ctx.Logger)
+	ctx.Err = service.CreateSopsKey(&args)
+}
+
+// @Summary Update Sops Key
+// @Description Update Sops Key
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string					true	"sops key id"
+// @Param 	body 	body 		commonmodels.SopsKey 	true 	"body"
+// @Success 200
+// @Router /api/aslan/system/sops/key/{id} [put]
+func UpdateSopsKey(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	var args commonmodels.SopsKey
+	if err := c.ShouldBindJSON(&args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	args.UpdateBy = ctx.UserName
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "更新", "系统配置-sops密钥", args.Name, "", ctx.Logger)
+	ctx.Err = service.UpdateSopsKey(c.Param("id"), &args)
+}
+
+// @Summary Delete Sops Key
+// @Description Delete Sops Key
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	id 		path		string					true	"sops key id"
+// @Success 200
+// @Router /api/aslan/system/sops/key/{id} [delete]
+func DeleteSopsKey(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "删除", "系统配置-sops密钥", c.Param("id"), "", ctx.Logger)
+	ctx.Err = service.DeleteSopsKey(c.Param("id"))
+}
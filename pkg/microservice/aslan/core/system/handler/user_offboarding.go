@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Get User Offboarding Report
+// @Description List the services, workflows, envs and release plans owned by the user, the approvals pending on the user and the projects administered by the user only
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	uid		path		string							true	"user id"
+// @Success 200 	{object} 	service.UserOffboardingReport
+// @Router /api/aslan/system/offboarding/users/{uid} [get]
+func GetUserOffboardingReport(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.GetUserOffboardingReport(c.Param("uid"), ctx.Logger)
+}
+
+// @Summary Transfer User Resources
+// @Description Transfer everything in the offboarding report of the user to the target user
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	uid		path		string								true	"user id"
+// @Param 	body 	body 		service.TransferUserResourcesArgs 	true 	"body"
+// @Success 200 	{object} 	models.UserOffboardingRecord
+// @Router /api/aslan/system/offboarding/users/{uid}/transfer [post]
+func TransferUserResources(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	uid := c.Param("uid")
+	args := new(service.TransferUserResourcesArgs)
+	data, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if err = json.Unmarshal(data, args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(data))
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "交接", "系统设置-用户资源", fmt.Sprintf("%s->%s", uid, args.TargetUID), string(data), ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.TransferUserResources(uid, args, ctx.UserName, ctx.Logger)
+}
+
+// @Summary List User Offboarding Records
+// @Description List the audit records of the user resource transfers
+// @Tags 	system
+// @Accept 	json
+// @Produce json
+// @Param 	uid			query		string								false	"the departing user id"
+// @Param 	page_num	query		int									false	"page num"
+// @Param 	page_size	query		int									false	"page size"
+// @Success 200 	{object} 	service.ListUserOffboardingRecordsResp
+// @Router /api/aslan/system/offboarding/records [get]
+func ListUserOffboardingRecords(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	pageNum, _ := strconv.ParseInt(c.DefaultQuery("page_num", "1"), 10, 64)
+	pageSize, _ := strconv.ParseInt(c.DefaultQuery("page_size", "20"), 10, 64)
+
+	ctx.Resp, ctx.Err = service.ListUserOffboardingRecords(c.Query("uid"), pageNum, pageSize, ctx.Logger)
+}
@@ -61,15 +61,28 @@ func (c *OperationLogColl) GetCollectionName() string {
 	return c.coll
 }
 
-func (c *OperationLogColl) EnsureIndex(ctx context.Context) error {
-	mod := mongo.IndexModel{
-		Keys: bson.D{
-			bson.E{Key: "created_at", Value: -1},
+// ManagedIndexes returns the indexes of the collection, they are ensured on startup and checked by the index audit
+func (c *OperationLogColl) ManagedIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetUnique(false),
+		},
+		// used to list the operation logs of a project by time
+		{
+			Keys: bson.D{
+				bson.E{Key: "product_name", Value: 1},
+				bson.E{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetUnique(false),
 		},
-		Options: options.Index().SetUnique(false),
 	}
+}
 
-	_, err := c.Indexes().CreateOne(ctx, mod)
+func (c *OperationLogColl) EnsureIndex(ctx context.Context) error {
+	_, err := c.Indexes().CreateMany(ctx, c.ManagedIndexes())
 
 	return err
 }
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	approvalservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/approval"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type CreateApprovalDelegationArgs struct {
+	// UserID is the user whose approvals are delegated, only system admins can delegate for other users
+	UserID       string                      `json:"user_id"`
+	DelegateType config.ApprovalDelegateType `json:"delegate_type"`
+	DelegateID   string                      `json:"delegate_id"`
+	StartTime    int64                       `json:"start_time"`
+	EndTime      int64                       `json:"end_time"`
+	Reason       string                      `json:"reason"`
+}
+
+// CreateApprovalDelegation creates the delegation, the pending approvals of the user are rerouted to the delegate
+// right away if the delegation is active.
+func CreateApprovalDelegation(args *CreateApprovalDelegationArgs, operator string, log *zap.SugaredLogger) (*commonmodels.ApprovalDelegation, error) {
+	if args.UserID == "" || args.DelegateID == "" {
+		return nil, e.ErrInvalidParam.AddDesc("user and delegate are required")
+	}
+	if args.StartTime >= args.EndTime || args.EndTime <= time.Now().Unix() {
+		return nil, e.ErrInvalidParam.AddDesc("invalid time range")
+	}
+
+	delegator, err := user.New().GetUserByID(args.UserID)
+	if err != nil || delegator == nil {
+		return nil, e.ErrInvalidParam.AddDesc(fmt.Sprintf("user %s not found", args.UserID))
+	}
+
+	delegation := &commonmodels.ApprovalDelegation{
+		UserID:       delegator.Uid,
+		UserName:     delegator.Name,
+		DelegateType: args.DelegateType,
+		DelegateID:   args.DelegateID,
+		StartTime:    args.StartTime,
+		EndTime:      args.EndTime,
+		Reason:       args.Reason,
+		CreatedBy:    operator,
+		CreateTime:   time.Now().Unix(),
+	}
+	switch args.DelegateType {
+	case config.ApprovalDelegateTypeUser:
+		if args.DelegateID == args.UserID {
+			return nil, e.ErrInvalidParam.AddDesc("can't delegate to the user himself")
+		}
+		delegate, err := user.New().GetUserByID(args.DelegateID)
+		if err != nil || delegate == nil {
+			return nil, e.ErrInvalidParam.AddDesc(fmt.Sprintf("delegate user %s not found", args.DelegateID))
+		}
+		delegation.DelegateName = delegate.Name
+	case config.ApprovalDelegateTypeGroup:
+		group, err := user.New().GetGroupDetailedInfo(args.DelegateID)
+		if err != nil || group == nil {
+			return nil, e.ErrInvalidParam.AddDesc(fmt.Sprintf("delegate group %s not found", args.DelegateID))
+		}
+		delegation.DelegateName = group.Name
+	default:
+		return nil, e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid delegate type %s", args.DelegateType))
+	}
+
+	if err := commonrepo.NewApprovalDelegationColl().Create(delegation); err != nil {
+		log.Errorf("failed to create approval delegation of %s, err: %s", delegation.UserName, err)
+		return nil, e.ErrCreateApprovalDelegation.AddErr(err)
+	}
+
+	if delegation.IsActive(time.Now().Unix()) {
+		go reroutePendingApprovals(log)
+	}
+	return delegation, nil
+}
+
+func ListApprovalDelegations(uid string, log *zap.SugaredLogger) ([]*commonmodels.ApprovalDelegation, error) {
+	delegations, err := commonrepo.NewApprovalDelegationColl().List(uid)
+	if err != nil {
+		log.Errorf("failed to list approval delegations, err: %s", err)
+		return nil, e.ErrListApprovalDelegations.AddErr(err)
+	}
+	return delegations, nil
+}
+
+// DeleteApprovalDelegation deletes the delegation, the approvals already rerouted to the delegate are not affected.
+// Users can only delete their own delegations unless they're system admins.
+func DeleteApprovalDelegation(id, operatorID string, isSystemAdmin bool, log *zap.SugaredLogger) error {
+	delegation, err := commonrepo.NewApprovalDelegationColl().GetByID(id)
+	if err != nil {
+		return e.ErrDeleteApprovalDelegation.AddErr(err)
+	}
+	if !isSystemAdmin && delegation.UserID != operatorID {
+		return e.ErrDeleteApprovalDelegation.AddDesc("only the delegations of the user himself can be deleted")
+	}
+	if err := commonrepo.NewApprovalDelegationColl().DeleteByID(id); err != nil {
+		log.Errorf("failed to delete approval delegation %s, err: %s", id, err)
+		return e.ErrDeleteApprovalDelegation.AddErr(err)
+	}
+	return nil
+}
+
+// reroutePendingApprovals applies the active delegations to the native approvals of the workflow tasks and the
+// release plans waiting for approval
+func reroutePendingApprovals(log *zap.SugaredLogger) {
+	keys := make([]string, 0)
+	tasks, err := commonrepo.NewworkflowTaskv4Coll().InCompletedTasks()
+	if err != nil {
+		log.Errorf("failed to list incompleted workflow tasks, err: %s", err)
+	}
+	for _, task := range tasks {
+		for _, stage := range task.Stages {
+			for _, job := range stage.Jobs {
+				if job.JobType == string(config.JobApproval) && job.Status == config.StatusWaitingApprove {
+					keys = append(keys, fmt.Sprintf("%s-%s-%d", task.WorkflowName, job.Name, task.TaskID))
+				}
+			}
+		}
+	}
+
+	plans, _, err := commonrepo.NewReleasePlanColl().ListByOptions(&commonrepo.ListReleasePlanOption{Status: config.StatusWaitForApprove})
+	if err != nil {
+		log.Errorf("failed to list release plans waiting for approval, err: %s", err)
+	}
+	for _, plan := range plans {
+		if plan.Approval != nil && plan.Approval.Type == config.NativeApproval && plan.Approval.NativeApproval != nil {
+			keys = append(keys, plan.Approval.NativeApproval.InstanceCode)
+		}
+	}
+
+	for _, key := range keys {
+		if _, ok := approvalservice.GlobalApproveMap.GetApproval(key); !ok {
+			continue
+		}
+		rerouted, err := approvalservice.GlobalApproveMap.RerouteApproval(key)
+		if err != nil {
+			log.Warnf("failed to reroute approval %s, err: %s", key, err)
+			continue
+		}
+		if rerouted {
+			log.Infof("approval %s is rerouted to the delegates", key)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const (
+	ApprovalRecordExportFormatJSON = "json"
+	ApprovalRecordExportFormatCSV  = "csv"
+)
+
+type ListApprovalRecordsArgs struct {
+	Source      config.ApprovalRecordSource `form:"source"`
+	ProjectName string                      `form:"projectName"`
+	StartTime   int64                       `form:"startTime"`
+	EndTime     int64                       `form:"endTime"`
+	PageNum     int64                       `form:"pageNum"`
+	PageSize    int64                       `form:"pageSize"`
+}
+
+type ListApprovalRecordsResp struct {
+	Records []*commonmodels.ApprovalRecord `json:"records"`
+	Total   int64                          `json:"total"`
+}
+
+type ApprovalRecordVerification struct {
+	Valid bool  `json:"valid"`
+	Total int64 `json:"total"`
+	// BrokenSeq is the seq of the first record failing the verification
+	BrokenSeq int64  `json:"broken_seq,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func ListApprovalRecords(args *ListApprovalRecordsArgs, log *zap.SugaredLogger) (*ListApprovalRecordsResp, error) {
+	records, total, err := commonrepo.NewApprovalRecordColl().List(context.Background(), &commonrepo.ListApprovalRecordOption{
+		Source:      args.Source,
+		ProjectName: args.ProjectName,
+		StartTime:   args.StartTime,
+		EndTime:     args.EndTime,
+		PageNum:     args.PageNum,
+		PageSize:    args.PageSize,
+	})
+	if err != nil {
+		log.Errorf("failed to list approval records, err: %s", err)
+		return nil, e.ErrListApprovalRecords.AddErr(err)
+	}
+	return &ListApprovalRecordsResp{Records: records, Total: total}, nil
+}
+
+// ExportApprovalRecords exports the records matching the args with their hashes, so that the chain can be
+// verified outside of zadig. It returns the file content and the file name.
+func ExportApprovalRecords(args *ListApprovalRecordsArgs, format string, log *zap.SugaredLogger) ([]byte, string, error) {
+	records, _, err := commonrepo.NewApprovalRecordColl().List(context.Background(), &commonrepo.ListApprovalRecordOption{
+		Source:      args.Source,
+		ProjectName: args.ProjectName,
+		StartTime:   args.StartTime,
+		EndTime:     args.EndTime,
+	})
+	if err != nil {
+		log.Errorf("failed to list approval records to export, err: %s", err)
+		return nil, "", e.ErrExportApprovalRecords.AddErr(err)
+	}
+
+	fileName := fmt.Sprintf("approval-records-%s", time.Now().Format("20060102150405"))
+	switch format {
+	case ApprovalRecordExportFormatCSV:
+		data, err := approvalRecordsToCSV(records)
+		if err != nil {
+			return nil, "", e.ErrExportApprovalRecords.AddErr(err)
+		}
+		return data, fileName + ".csv", nil
+	case ApprovalRecordExportFormatJSON, "":
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return nil, "", e.ErrExportApprovalRecords.AddErr(err)
+		}
+		return data, fileName + ".json", nil
+	default:
+		return nil, "", e.ErrExportApprovalRecords.AddDesc(fmt.Sprintf("unsupported format: %s", format))
+	}
+}
+
+func approvalRecordsToCSV(records []*commonmodels.ApprovalRecord) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	header := []string{"seq", "source", "project_name", "workflow_name", "job_name", "task_id", "release_plan_id", "release_plan_name",
+		"user_id", "user_name", "decision", "comment", "create_time", "prev_hash", "hash", "on_behalf_of_id", "on_behalf_of_name"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		row := []string{
+			strconv.FormatInt(r.Seq, 10),
+			string(r.Source),
+			r.ProjectName,
+			r.WorkflowName,
+			r.JobName,
+			strconv.FormatInt(r.TaskID, 10),
+			r.ReleasePlanID,
+			r.ReleasePlanName,
+			r.UserID,
+			r.UserName,
+			string(r.Decision),
+			r.Comment,
+			strconv.FormatInt(r.CreateTime, 10),
+			r.PrevHash,
+			r.Hash,
+			r.OnBehalfOfID,
+			r.OnBehalfOfName,
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// VerifyApprovalRecords walks through the whole chain and checks that the seq is continuous,
+// every record links to the hash of the previous one and the hash matches the content.
+func VerifyApprovalRecords(log *zap.SugaredLogger) (*ApprovalRecordVerification, error) {
+	resp := &ApprovalRecordVerification{Valid: true}
+	var prev *commonmodels.ApprovalRecord
+
+	err := commonrepo.NewApprovalRecordColl().Walk(context.Background(), func(record *commonmodels.ApprovalRecord) error {
+		resp.Total++
+		if !resp.Valid {
+			return nil
+		}
+
+		reason := ""
+		expectedSeq, expectedPrevHash := int64(1), ""
+		if prev != nil {
+			expectedSeq, expectedPrevHash = prev.Seq+1, prev.Hash
+		}
+		hash, err := record.ComputeHash()
+		if err != nil {
+			return err
+		}
+		switch {
+		case record.Seq != expectedSeq:
+			reason = fmt.Sprintf("expect seq %d, got %d, records may have been deleted", expectedSeq, record.Seq)
+		case record.PrevHash != expectedPrevHash:
+			reason = "previous hash mismatch, the previous record may have been modified"
+		case record.Hash != hash:
+			reason = "hash mismatch, the record has been modified"
+		}
+		if reason != "" {
+			resp.Valid = false
+			resp.BrokenSeq = record.Seq
+			resp.Reason = reason
+		}
+		prev = record
+		return nil
+	})
+	if err != nil {
+		log.Errorf("failed to verify approval records, err: %s", err)
+		return nil, e.ErrVerifyApprovalRecords.AddErr(err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/argocd"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func ListArgoCD(isAdmin bool) ([]*models.ArgoCD, error) {
+	resp, err := mongodb.NewArgoCDColl().List(context.Background())
+	if err != nil {
+		return nil, e.ErrListArgoCD.AddErr(err)
+	}
+	if !isAdmin {
+		for _, v := range resp {
+			v.Token = ""
+		}
+	}
+	return resp, nil
+}
+
+func CreateArgoCD(args *models.ArgoCD) error {
+	if err := checkArgoCD(args); err != nil {
+		return e.ErrCreateArgoCD.AddErr(err)
+	}
+	args.UpdateTime = time.Now().Unix()
+	if err := mongodb.NewArgoCDColl().Create(context.Background(), args); err != nil {
+		return e.ErrCreateArgoCD.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateArgoCD(id string, args *models.ArgoCD) error {
+	if err := checkArgoCD(args); err != nil {
+		return e.ErrUpdateArgoCD.AddErr(err)
+	}
+	args.UpdateTime = time.Now().Unix()
+	if err := mongodb.NewArgoCDColl().Update(context.Background(), id, args); err != nil {
+		return e.ErrUpdateArgoCD.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteArgoCD(id string) error {
+	if err := mongodb.NewArgoCDColl().DeleteByID(context.Background(), id); err != nil {
+		return e.ErrDeleteArgoCD.AddErr(err)
+	}
+	return nil
+}
+
+func ValidateArgoCD(args *models.ArgoCD) error {
+	if err := checkArgoCD(args); err != nil {
+		return e.ErrValidateArgoCD.AddErr(err)
+	}
+	if err := argocd.NewClient(args.Address, args.Token, args.InsecureSkipVerify).Validate(); err != nil {
+		return e.ErrValidateArgoCD.AddErr(err)
+	}
+	return nil
+}
+
+func checkArgoCD(args *models.ArgoCD) error {
+	if args.Name == "" {
+		return fmt.Errorf("name must be provided")
+	}
+	if args.Address == "" || args.Token == "" {
+		return fmt.Errorf("address and token must be provided")
+	}
+	return nil
+}
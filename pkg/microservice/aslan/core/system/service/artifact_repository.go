@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/artifactrepo"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func ListArtifactRepository(isAdmin bool) ([]*models.ArtifactRepository, error) {
+	resp, err := mongodb.NewArtifactRepositoryColl().List(context.Background())
+	if err != nil {
+		return nil, e.ErrListArtifactRepository.AddErr(err)
+	}
+	if !isAdmin {
+		for _, v := range resp {
+			v.Username = ""
+			v.Password = ""
+			v.Token = ""
+		}
+	}
+	return resp, nil
+}
+
+func CreateArtifactRepository(args *models.ArtifactRepository) error {
+	if err := checkArtifactRepository(args); err != nil {
+		return e.ErrCreateArtifactRepository.AddErr(err)
+	}
+	args.UpdateTime = time.Now().Unix()
+	if err := mongodb.NewArtifactRepositoryColl().Create(context.Background(), args); err != nil {
+		return e.ErrCreateArtifactRepository.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateArtifactRepository(id string, args *models.ArtifactRepository) error {
+	if err := checkArtifactRepository(args); err != nil {
+		return e.ErrUpdateArtifactRepository.AddErr(err)
+	}
+	args.UpdateTime = time.Now().Unix()
+	if err := mongodb.NewArtifactRepositoryColl().Update(context.Background(), id, args); err != nil {
+		return e.ErrUpdateArtifactRepository.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteArtifactRepository(id string) error {
+	builds, err := mongodb.NewBuildColl().GetArtifactRepositoryReference(id)
+	if err != nil {
+		return e.ErrDeleteArtifactRepository.AddErr(err)
+	}
+	if len(builds) > 0 {
+		names := make([]string, 0, len(builds))
+		for _, build := range builds {
+			names = append(names, fmt.Sprintf("%s/%s", build.ProductName, build.Name))
+		}
+		return e.ErrDeleteArtifactRepository.AddDesc(fmt.Sprintf("制品仓库被构建 %s 使用，无法删除", strings.Join(names, ", ")))
+	}
+
+	// the recorded artifacts are downloaded with the credentials of the repository
+	count, err := mongodb.NewBuildArtifactColl().CountByRepositoryID(context.Background(), id)
+	if err != nil {
+		return e.ErrDeleteArtifactRepository.AddErr(err)
+	}
+	if count > 0 {
+		return e.ErrDeleteArtifactRepository.AddDesc(fmt.Sprintf("制品仓库中存在 %d 个构建制品记录，无法删除", count))
+	}
+
+	if err := mongodb.NewArtifactRepositoryColl().DeleteByID(context.Background(), id); err != nil {
+		return e.ErrDeleteArtifactRepository.AddErr(err)
+	}
+	return nil
+}
+
+func ValidateArtifactRepository(args *models.ArtifactRepository) error {
+	if err := checkArtifactRepository(args); err != nil {
+		return e.ErrValidateArtifactRepository.AddErr(err)
+	}
+
+	client, err := artifactrepo.NewClient(args.Type, args.Address, args.Repository, args.Username, args.Password, args.Token)
+	if err != nil {
+		return e.ErrValidateArtifactRepository.AddErr(err)
+	}
+	if err := client.Validate(); err != nil {
+		return e.ErrValidateArtifactRepository.AddErr(err)
+	}
+	return nil
+}
+
+func checkArtifactRepository(args *models.ArtifactRepository) error {
+	if args.Name == "" {
+		return fmt.Errorf("name must be provided")
+	}
+	if args.Token == "" && (args.Username == "" || args.Password == "") {
+		return fmt.Errorf("either token or username and password must be provided")
+	}
+	_, err := artifactrepo.NewClient(args.Type, args.Address, args.Repository, args.Username, args.Password, args.Token)
+	return err
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/buildcache"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+type BuildCacheItem struct {
+	*commonmodels.BuildCache
+	HitRate      float64 `json:"hit_rate"`
+	LastUsedTime int64   `json:"last_used_time"`
+}
+
+type BuildCacheStats struct {
+	TotalSize int64   `json:"total_size"`
+	HitCount  int64   `json:"hit_count"`
+	MissCount int64   `json:"miss_count"`
+	HitRate   float64 `json:"hit_rate"`
+}
+
+type ListBuildCachesResp struct {
+	Caches []*BuildCacheItem `json:"caches"`
+	Stats  *BuildCacheStats  `json:"stats"`
+}
+
+type PruneBuildCachesArgs struct {
+	IDs []string `json:"ids"`
+	// UnusedDays prunes the caches which are not hit or uploaded in the days
+	UnusedDays int  `json:"unused_days"`
+	DryRun     bool `json:"dryrun"`
+}
+
+type PruneBuildCachesResp struct {
+	Caches    []*commonmodels.BuildCache `json:"caches"`
+	FreedSize int64                      `json:"freed_size"`
+}
+
+func hitRate(hit, miss int64) float64 {
+	if hit+miss == 0 {
+		return 0
+	}
+	return float64(hit) / float64(hit+miss)
+}
+
+func ListBuildCaches(workflowName, serviceName string, logger *zap.SugaredLogger) (*ListBuildCachesResp, error) {
+	caches, err := commonrepo.NewBuildCacheColl().List(&commonrepo.BuildCacheListOption{
+		WorkflowName: workflowName,
+		ServiceName:  serviceName,
+	})
+	if err != nil {
+		logger.Errorf("failed to list build caches, error: %s", err)
+		return nil, e.ErrListBuildCache.AddErr(err)
+	}
+
+	resp := &ListBuildCachesResp{
+		Caches: make([]*BuildCacheItem, 0, len(caches)),
+		Stats:  &BuildCacheStats{},
+	}
+	for _, cache := range caches {
+		resp.Caches = append(resp.Caches, &BuildCacheItem{
+			BuildCache:   cache,
+			HitRate:      hitRate(cache.HitCount, cache.MissCount),
+			LastUsedTime: buildcache.LastUsedTime(cache),
+		})
+		resp.Stats.TotalSize += cache.Size
+		resp.Stats.HitCount += cache.HitCount
+		resp.Stats.MissCount += cache.MissCount
+	}
+	resp.Stats.HitRate = hitRate(resp.Stats.HitCount, resp.Stats.MissCount)
+	return resp, nil
+}
+
+func PruneBuildCaches(args *PruneBuildCachesArgs, logger *zap.SugaredLogger) (*PruneBuildCachesResp, error) {
+	if len(args.IDs) == 0 && args.UnusedDays <= 0 {
+		return nil, e.ErrInvalidParam.AddDesc("ids or unused_days must be specified")
+	}
+
+	caches, err := commonrepo.NewBuildCacheColl().List(&commonrepo.BuildCacheListOption{IDs: args.IDs})
+	if err != nil {
+		logger.Errorf("failed to list build caches, error: %s", err)
+		return nil, e.ErrPruneBuildCache.AddErr(err)
+	}
+	if args.UnusedDays > 0 {
+		caches = buildcache.SelectStale(caches, &commonmodels.RetentionConfig{MaxDays: args.UnusedDays}, time.Now())
+	}
+
+	freed, err := buildcache.Prune(caches, args.DryRun)
+	if err != nil {
+		logger.Errorf("failed to prune build caches, error: %s", err)
+		return nil, e.ErrPruneBuildCache.AddErr(err)
+	}
+	return &PruneBuildCachesResp{
+		Caches:    caches,
+		FreedSize: freed,
+	}, nil
+}
+
+// HandleBuildCacheRetention evicts the stale build caches by the BuildCacheRetention strategy.
+func HandleBuildCacheRetention() {
+	strategy, err := GetCapacityStrategy(commonmodels.BuildCacheRetention)
+	if err != nil {
+		log.Errorf("failed to get build cache retention strategy, error: %s", err)
+		return
+	}
+	if _, _, err := buildcache.Evict(strategy, false); err != nil {
+		log.Errorf("failed to evict build caches, error: %s", err)
+	}
+}
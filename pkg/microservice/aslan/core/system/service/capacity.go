@@ -44,7 +44,13 @@ func UpdateSysCapStrategy(strategy *commonmodels.CapacityStrategy) error {
 	}
 
 	// 更新成功后，立即按照新的配置清理数据
-	go handleWorkflowTaskRetentionCenter(strategy, false)
+	if strategy.Target == commonmodels.BuildCacheRetention {
+		go HandleBuildCacheRetention()
+	} else if strategy.Target == commonmodels.WorkflowTaskColdStorage {
+		go HandleWorkflowTaskColdStorage()
+	} else {
+		go handleWorkflowTaskRetentionCenter(strategy, false)
+	}
 
 	return nil
 }
@@ -54,6 +60,12 @@ func GetCapacityStrategy(target commonmodels.CapacityTarget) (*commonmodels.Capa
 	if err != nil && target == commonmodels.WorkflowTaskRetention {
 		return commonmodels.DefaultWorkflowTaskRetention, nil // Return default setup
 	}
+	if err != nil && target == commonmodels.BuildCacheRetention {
+		return commonmodels.DefaultBuildCacheRetention, nil
+	}
+	if err != nil && target == commonmodels.WorkflowTaskColdStorage {
+		return commonmodels.DefaultWorkflowTaskColdStorage, nil
+	}
 	return result, err
 }
 
@@ -471,6 +483,25 @@ func validateStrategy(strategy *commonmodels.CapacityStrategy) error {
 				"can only set one positive value at a time. days: %v, items: %v",
 				retention.MaxDays, retention.MaxItems)
 		}
+	} else if strategy.Target == commonmodels.BuildCacheRetention {
+		retention := strategy.Retention
+		if retention == nil {
+			return errors.New("SysCap strategy: nil retention config for BuildCacheRetention")
+		}
+		if retention.MaxDays < 0 || retention.MaxSize < 0 || retention.MaxItems != 0 {
+			return fmt.Errorf("SysCap strategy: max days or size value invalid, "+
+				"only non-negative days and size are supported. days: %v, size: %v",
+				retention.MaxDays, retention.MaxSize)
+		}
+	} else if strategy.Target == commonmodels.WorkflowTaskColdStorage {
+		retention := strategy.Retention
+		if retention == nil {
+			return errors.New("SysCap strategy: nil retention config for WorkflowTaskColdStorage")
+		}
+		if retention.MaxDays < 0 || retention.MaxItems != 0 || retention.MaxSize != 0 {
+			return fmt.Errorf("SysCap strategy: max days value invalid, "+
+				"only non-negative days are supported, 0 disables the cold storage. days: %v", retention.MaxDays)
+		}
 	} else {
 		// Note: currently doesn't support other strategies yet.
 		return fmt.Errorf("SysCap strategy target is invalid - passed in value: %v", strategy.Target)
@@ -18,10 +18,13 @@ package service
 
 import (
 	"errors"
+	"fmt"
 
 	"go.uber.org/zap"
 
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/workflowcontroller"
 	workflowservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
 )
 
@@ -51,3 +54,50 @@ func UpdateWorkflowConcurrency(workflowConcurrency, buildConcurrency int64, log
 
 	return nil
 }
+
+func GetFairSchedulingSettings() (*commonmodels.FairSchedulingSettings, error) {
+	configuration, err := commonrepo.NewSystemSettingColl().Get()
+	if err != nil {
+		return nil, err
+	}
+	if configuration.FairScheduling == nil {
+		return &commonmodels.FairSchedulingSettings{
+			DefaultWeight: 1,
+			Weights:       make([]*commonmodels.ProjectSchedulingWeight, 0),
+		}, nil
+	}
+	return configuration.FairScheduling, nil
+}
+
+func UpdateFairSchedulingSettings(args *commonmodels.FairSchedulingSettings, log *zap.SugaredLogger) error {
+	if args.DefaultWeight < 0 {
+		return errors.New("default weight cannot be negative")
+	}
+	if args.JobPodConcurrency < 0 {
+		return errors.New("job pod concurrency cannot be negative")
+	}
+	projects := make(map[string]bool)
+	for _, weight := range args.Weights {
+		if weight.ProjectName == "" {
+			return errors.New("project name cannot be empty")
+		}
+		if weight.Weight <= 0 {
+			return fmt.Errorf("weight of project %s must be greater than 0", weight.ProjectName)
+		}
+		if projects[weight.ProjectName] {
+			return fmt.Errorf("duplicated weight of project %s", weight.ProjectName)
+		}
+		projects[weight.ProjectName] = true
+	}
+
+	err := commonrepo.NewSystemSettingColl().UpdateFairSchedulingSetting(args)
+	if err != nil {
+		log.Errorf("Failed to update fair scheduling settings, the error is: %s", err)
+		return err
+	}
+	return nil
+}
+
+func GetWorkflowQueueInsights() (*workflowcontroller.QueueInsights, error) {
+	return workflowcontroller.GetQueueInsights()
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/contractregistry"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+func ListContractRegistry(isAdmin bool) ([]*models.ContractRegistry, error) {
+	resp, err := mongodb.NewContractRegistryColl().List(context.Background())
+	if err != nil {
+		return nil, e.ErrListContractRegistry.AddErr(err)
+	}
+	if !isAdmin {
+		for _, v := range resp {
+			v.Token = ""
+		}
+	}
+	return resp, nil
+}
+
+func CreateContractRegistry(args *models.ContractRegistry) error {
+	if err := checkContractRegistry(args); err != nil {
+		return e.ErrCreateContractRegistry.AddErr(err)
+	}
+	args.UpdateTime = time.Now().Unix()
+	if err := mongodb.NewContractRegistryColl().Create(context.Background(), args); err != nil {
+		return e.ErrCreateContractRegistry.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateContractRegistry(id string, args *models.ContractRegistry) error {
+	if err := checkContractRegistry(args); err != nil {
+		return e.ErrUpdateContractRegistry.AddErr(err)
+	}
+	args.UpdateTime = time.Now().Unix()
+	if err := mongodb.NewContractRegistryColl().Update(context.Background(), id, args); err != nil {
+		return e.ErrUpdateContractRegistry.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteContractRegistry(id string) error {
+	if err := mongodb.NewContractRegistryColl().DeleteByID(context.Background(), id); err != nil {
+		return e.ErrDeleteContractRegistry.AddErr(err)
+	}
+	return nil
+}
+
+func ValidateContractRegistry(args *models.ContractRegistry) error {
+	if err := checkContractRegistry(args); err != nil {
+		return e.ErrValidateContractRegistry.AddErr(err)
+	}
+	if err := contractregistry.NewClient(args.Address, args.Token, args.InsecureSkipVerify).Validate(); err != nil {
+		return e.ErrValidateContractRegistry.AddErr(err)
+	}
+	return nil
+}
+
+func checkContractRegistry(args *models.ContractRegistry) error {
+	if args.Name == "" {
+		return fmt.Errorf("name must be provided")
+	}
+	if args.Address == "" {
+		return fmt.Errorf("address must be provided")
+	}
+	return nil
+}
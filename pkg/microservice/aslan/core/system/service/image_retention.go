@@ -0,0 +1,292 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/registry"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+// imageInfoConcurrency limits the concurrent requests to the registry when getting the creation time of the tags
+const imageInfoConcurrency = 10
+
+type ImageRetentionTag struct {
+	Tag     string `json:"tag"`
+	Digest  string `json:"digest"`
+	Created int64  `json:"created"`
+	Error   string `json:"error,omitempty"`
+}
+
+type ImageRetentionRepoResult struct {
+	Repo    string               `json:"repo"`
+	Total   int                  `json:"total"`
+	Expired []*ImageRetentionTag `json:"expired"`
+}
+
+type ImageRetentionResult struct {
+	PolicyName string                      `json:"policy_name"`
+	DryRun     bool                        `json:"dry_run"`
+	Deleted    int                         `json:"deleted"`
+	Repos      []*ImageRetentionRepoResult `json:"repos"`
+}
+
+func validateImageRetentionPolicy(args *commonmodels.ImageRetentionPolicy) error {
+	if args.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if args.RegistryID == "" {
+		return fmt.Errorf("registry_id is required")
+	}
+	if len(args.Repos) == 0 {
+		return fmt.Errorf("repos are required")
+	}
+	if args.KeepLastN < 0 || args.MinAgeDays < 0 {
+		return fmt.Errorf("keep_last_n and min_age_days can't be negative")
+	}
+	// a policy without keep_last_n and min_age_days may delete every tag of the repos
+	if args.KeepLastN == 0 && args.MinAgeDays == 0 {
+		return fmt.Errorf("at least one of keep_last_n and min_age_days must be specified")
+	}
+	if _, err := registry.SelectExpiredTags(nil, retentionRule(args), time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func retentionRule(policy *commonmodels.ImageRetentionPolicy) *registry.RetentionRule {
+	return &registry.RetentionRule{
+		KeepLastN:    policy.KeepLastN,
+		KeepTagRegex: policy.KeepTagRegex,
+		MinAgeDays:   policy.MinAgeDays,
+	}
+}
+
+func ListImageRetentionPolicies(logger *zap.SugaredLogger) ([]*commonmodels.ImageRetentionPolicy, error) {
+	policies, err := commonrepo.NewImageRetentionPolicyColl().List(false)
+	if err != nil {
+		logger.Errorf("failed to list image retention policies, error: %s", err)
+		return nil, e.ErrListImageRetentionPolicy.AddErr(err)
+	}
+	return policies, nil
+}
+
+func GetImageRetentionPolicy(id string, logger *zap.SugaredLogger) (*commonmodels.ImageRetentionPolicy, error) {
+	policy, err := commonrepo.NewImageRetentionPolicyColl().GetByID(id)
+	if err != nil {
+		logger.Errorf("failed to get image retention policy %s, error: %s", id, err)
+		return nil, e.ErrGetImageRetentionPolicy.AddErr(err)
+	}
+	return policy, nil
+}
+
+func CreateImageRetentionPolicy(username string, args *commonmodels.ImageRetentionPolicy, logger *zap.SugaredLogger) error {
+	if err := validateImageRetentionPolicy(args); err != nil {
+		return e.ErrInvalidParam.AddErr(err)
+	}
+	if _, err := commonservice.FindRegistryById(args.RegistryID, false, logger); err != nil {
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("registry %s not found", args.RegistryID))
+	}
+
+	args.UpdateBy = username
+	if err := commonrepo.NewImageRetentionPolicyColl().Create(args); err != nil {
+		logger.Errorf("failed to create image retention policy %s, error: %s", args.Name, err)
+		return e.ErrCreateImageRetentionPolicy.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateImageRetentionPolicy(username, id string, args *commonmodels.ImageRetentionPolicy, logger *zap.SugaredLogger) error {
+	if err := validateImageRetentionPolicy(args); err != nil {
+		return e.ErrInvalidParam.AddErr(err)
+	}
+	if _, err := commonservice.FindRegistryById(args.RegistryID, false, logger); err != nil {
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("registry %s not found", args.RegistryID))
+	}
+
+	args.UpdateBy = username
+	if err := commonrepo.NewImageRetentionPolicyColl().Update(id, args); err != nil {
+		logger.Errorf("failed to update image retention policy %s, error: %s", id, err)
+		return e.ErrUpdateImageRetentionPolicy.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteImageRetentionPolicy(id string, logger *zap.SugaredLogger) error {
+	if err := commonrepo.NewImageRetentionPolicyColl().DeleteByID(id); err != nil {
+		logger.Errorf("failed to delete image retention policy %s, error: %s", id, err)
+		return e.ErrDeleteImageRetentionPolicy.AddErr(err)
+	}
+	return nil
+}
+
+// PreviewImageRetention returns the tags which would be deleted by the policy without deleting them.
+func PreviewImageRetention(args *commonmodels.ImageRetentionPolicy, logger *zap.SugaredLogger) (*ImageRetentionResult, error) {
+	if err := validateImageRetentionPolicy(args); err != nil {
+		return nil, e.ErrInvalidParam.AddErr(err)
+	}
+
+	result, err := applyImageRetentionPolicy(args, true, logger)
+	if err != nil {
+		logger.Errorf("failed to preview image retention policy %s, error: %s", args.Name, err)
+		return nil, e.ErrPreviewImageRetention.AddErr(err)
+	}
+	return result, nil
+}
+
+// RunImageRetentionPolicies deletes the stale tags by the enabled image retention policies.
+func RunImageRetentionPolicies() {
+	policies, err := commonrepo.NewImageRetentionPolicyColl().List(true)
+	if err != nil {
+		log.Errorf("failed to list image retention policies, error: %s", err)
+		return
+	}
+
+	logger := log.SugaredLogger()
+	for _, policy := range policies {
+		result, err := applyImageRetentionPolicy(policy, false, logger)
+		if err != nil {
+			log.Errorf("failed to apply image retention policy %s, error: %s", policy.Name, err)
+			continue
+		}
+		log.Infof("image retention policy %s deleted %d tags", policy.Name, result.Deleted)
+
+		if err := commonrepo.NewImageRetentionPolicyColl().UpdateRunResult(policy.ID, time.Now().Unix(), result.Deleted); err != nil {
+			log.Errorf("failed to update the run result of image retention policy %s, error: %s", policy.Name, err)
+		}
+	}
+}
+
+func applyImageRetentionPolicy(policy *commonmodels.ImageRetentionPolicy, dryRun bool, logger *zap.SugaredLogger) (*ImageRetentionResult, error) {
+	registryInfo, err := commonservice.FindRegistryById(policy.RegistryID, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find registry %s: %s", policy.RegistryID, err)
+	}
+
+	var regService registry.Service
+	if registryInfo.AdvancedSetting != nil {
+		regService = registry.NewV2Service(registryInfo.RegProvider, registryInfo.AdvancedSetting.TLSEnabled, registryInfo.AdvancedSetting.TLSCert)
+	} else {
+		regService = registry.NewV2Service(registryInfo.RegProvider, true, "")
+	}
+	endPoint := registry.Endpoint{
+		Addr:      registryInfo.RegAddr,
+		Ak:        registryInfo.AccessKey,
+		Sk:        registryInfo.SecretKey,
+		Namespace: registryInfo.Namespace,
+		Region:    registryInfo.Region,
+	}
+
+	repos, err := regService.ListRepoImages(registry.ListRepoImagesOption{
+		Endpoint: endPoint,
+		Repos:    policy.Repos,
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %s", err)
+	}
+	sort.Slice(repos.Repos, func(i, j int) bool {
+		return repos.Repos[i].Name < repos.Repos[j].Name
+	})
+
+	result := &ImageRetentionResult{
+		PolicyName: policy.Name,
+		DryRun:     dryRun,
+		Repos:      make([]*ImageRetentionRepoResult, 0, len(repos.Repos)),
+	}
+	for _, repo := range repos.Repos {
+		tags := getRepoTagInfos(regService, endPoint, repo, logger)
+		expired, err := registry.SelectExpiredTags(tags, retentionRule(policy), time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		repoResult := &ImageRetentionRepoResult{
+			Repo:    repo.Name,
+			Total:   len(repo.Tags),
+			Expired: make([]*ImageRetentionTag, 0, len(expired)),
+		}
+		for _, tag := range expired {
+			retentionTag := &ImageRetentionTag{
+				Tag:     tag.Tag,
+				Digest:  tag.Digest,
+				Created: tag.Created.Unix(),
+			}
+			if !dryRun {
+				err := regService.DeleteImageTag(registry.DeleteImageTagOption{
+					Endpoint: endPoint,
+					Image:    repo.Name,
+					Tag:      tag.Tag,
+				}, logger)
+				if err != nil {
+					logger.Errorf("image retention policy %s failed to delete %s:%s, error: %s", policy.Name, repo.Name, tag.Tag, err)
+					retentionTag.Error = err.Error()
+				} else {
+					result.Deleted++
+				}
+			}
+			repoResult.Expired = append(repoResult.Expired, retentionTag)
+		}
+		result.Repos = append(result.Repos, repoResult)
+	}
+
+	return result, nil
+}
+
+// getRepoTagInfos gets the digest and the creation time of the tags, the creation time is left zero if the image info
+// can't be got so that the tag is always retained.
+func getRepoTagInfos(regService registry.Service, endPoint registry.Endpoint, repo *registry.Repo, logger *zap.SugaredLogger) []*registry.TagInfo {
+	tags := make([]*registry.TagInfo, len(repo.Tags))
+	sem := make(chan struct{}, imageInfoConcurrency)
+	wg := sync.WaitGroup{}
+	for i, tag := range repo.Tags {
+		tags[i] = &registry.TagInfo{Tag: tag}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(info *registry.TagInfo) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			image, err := regService.GetImageInfo(registry.GetRepoImageDetailOption{
+				Endpoint: endPoint,
+				Image:    repo.Name,
+				Tag:      info.Tag,
+			}, logger)
+			if err != nil {
+				logger.Warnf("failed to get image info of %s:%s, error: %s", repo.Name, info.Tag, err)
+				return
+			}
+			info.Digest = image.ImageDigest
+			info.Created = registry.ParseImageCreationTime(image.CreationTime)
+		}(tags[i])
+	}
+	wg.Wait()
+
+	return tags
+}
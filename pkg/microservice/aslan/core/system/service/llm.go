@@ -19,6 +19,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
@@ -73,6 +74,9 @@ func CreateLLMIntegration(ctx context.Context, args *commonmodels.LLMIntegration
 	if count > 0 {
 		return e.ErrCreateLLMIntegration.AddDesc("llm integration already exists")
 	}
+	if err := validateRedactionRules(args.RedactionRules); err != nil {
+		return e.ErrCreateLLMIntegration.AddErr(err)
+	}
 
 	if err := commonrepo.NewLLMIntegrationColl().Create(ctx, args); err != nil {
 		fmtErr := fmt.Errorf("CreateLLMIntegration err: %w", err)
@@ -83,6 +87,9 @@ func CreateLLMIntegration(ctx context.Context, args *commonmodels.LLMIntegration
 }
 
 func UpdateLLMIntegration(ctx context.Context, ID string, args *commonmodels.LLMIntegration) error {
+	if err := validateRedactionRules(args.RedactionRules); err != nil {
+		return e.ErrUpdateLLMIntegration.AddErr(err)
+	}
 	if err := commonrepo.NewLLMIntegrationColl().Update(ctx, ID, args); err != nil {
 		fmtErr := fmt.Errorf("UpdateLLMIntegration err: %w", err)
 		log.Error(fmtErr)
@@ -99,3 +106,12 @@ func DeleteLLMIntegration(ctx context.Context, ID string) error {
 	}
 	return nil
 }
+
+func validateRedactionRules(rules []string) error {
+	for _, rule := range rules {
+		if _, err := regexp.Compile(rule); err != nil {
+			return fmt.Errorf("invalid redaction rule %s: %s", rule, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+)
+
+func GetManifestLintSettings(logger *zap.SugaredLogger) (*commonmodels.ManifestLintSettings, error) {
+	settings, err := kube.GetManifestLintSettings()
+	if err != nil {
+		logger.Errorf("failed to get manifest lint settings, error: %s", err)
+		return nil, err
+	}
+	return settings, nil
+}
+
+func UpdateManifestLintSettings(args *commonmodels.ManifestLintSettings, logger *zap.SugaredLogger) error {
+	err := commonrepo.NewSystemSettingColl().UpdateManifestLintSetting(args)
+	if err != nil {
+		logger.Errorf("failed to update manifest lint settings, error: %s", err)
+	}
+	return err
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	systemrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+)
+
+// managedIndexCollection is a hot collection whose indexes are managed by aslan
+type managedIndexCollection interface {
+	GetCollectionName() string
+	ManagedIndexes() []mongo.IndexModel
+	Indexes() mongo.IndexView
+}
+
+func managedIndexCollections() []managedIndexCollection {
+	return []managedIndexCollection{
+		commonrepo.NewworkflowTaskv4Coll(),
+		commonrepo.NewProductColl(),
+		systemrepo.NewOperationLogColl(),
+	}
+}
+
+type MongoIndexAuditArgs struct {
+	SlowThreshold int64 `json:"slow_threshold" form:"slowThreshold"`
+	SlowLimit     int64 `json:"slow_limit"     form:"slowLimit"`
+}
+
+type MongoIndexAuditResp struct {
+	Collections   []*MongoCollectionIndexAudit `json:"collections"`
+	SlowQueries   []*MongoSlowQuery            `json:"slow_queries"`
+	ProfilerError string                       `json:"profiler_error,omitempty"`
+}
+
+type MongoCollectionIndexAudit struct {
+	Collection string `json:"collection"`
+	Managed    bool   `json:"managed"`
+	// Indexes are the key patterns of the existing indexes, such as "workflow_name_1_task_id_1"
+	Indexes []string `json:"indexes"`
+	// Missing are the managed indexes which don't exist
+	Missing []string `json:"missing"`
+	// Duplicates are the indexes with the same key pattern as another index
+	Duplicates []string `json:"duplicates"`
+	// Redundant are the non-unique indexes whose key pattern is a prefix of another index
+	Redundant []string `json:"redundant"`
+}
+
+type MongoSlowQuery struct {
+	Namespace    string `json:"namespace"`
+	Operation    string `json:"operation"`
+	Millis       int64  `json:"millis"`
+	PlanSummary  string `json:"plan_summary"`
+	DocsExamined int64  `json:"docs_examined"`
+	KeysExamined int64  `json:"keys_examined"`
+	Command      string `json:"command"`
+	Time         int64  `json:"time"`
+}
+
+type existingIndex struct {
+	Name   string `bson:"name"`
+	Key    bson.D `bson:"key"`
+	Unique bool   `bson:"unique"`
+}
+
+// EnsureManagedIndexes creates the managed indexes of the hot collections which don't exist yet
+func EnsureManagedIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	for _, coll := range managedIndexCollections() {
+		if _, err := coll.Indexes().CreateMany(ctx, coll.ManagedIndexes()); err != nil {
+			log.Warnf("failed to ensure managed indexes of collection %s, err: %s", coll.GetCollectionName(), err)
+		}
+	}
+}
+
+// AuditMongoIndexes reports the missing managed indexes, the duplicate indexes of all the collections and the slow queries
+// sampled by the mongo profiler
+func AuditMongoIndexes(args *MongoIndexAuditArgs, logger *zap.SugaredLogger) (*MongoIndexAuditResp, error) {
+	if args.SlowThreshold <= 0 {
+		args.SlowThreshold = 100
+	}
+	if args.SlowLimit <= 0 || args.SlowLimit > 200 {
+		args.SlowLimit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	db := mongotool.Database(config.MongoDatabase())
+	collNames, err := db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		logger.Errorf("failed to list collections, err: %s", err)
+		return nil, e.ErrAuditMongoIndex.AddErr(err)
+	}
+	sort.Strings(collNames)
+
+	managed := make(map[string]managedIndexCollection)
+	for _, coll := range managedIndexCollections() {
+		managed[coll.GetCollectionName()] = coll
+	}
+
+	resp := &MongoIndexAuditResp{
+		Collections: make([]*MongoCollectionIndexAudit, 0),
+		SlowQueries: make([]*MongoSlowQuery, 0),
+	}
+	for _, collName := range collNames {
+		if strings.HasPrefix(collName, "system.") {
+			continue
+		}
+
+		indexes, err := listExistingIndexes(ctx, db.Collection(collName))
+		if err != nil {
+			logger.Errorf("failed to list indexes of collection %s, err: %s", collName, err)
+			return nil, e.ErrAuditMongoIndex.AddErr(err)
+		}
+
+		audit := auditCollectionIndexes(collName, indexes)
+		if coll, ok := managed[collName]; ok {
+			audit.Managed = true
+			existing := make(map[string]bool)
+			for _, index := range indexes {
+				existing[indexKeyPattern(index.Key)] = true
+			}
+			for _, model := range coll.ManagedIndexes() {
+				pattern := indexKeyPattern(model.Keys)
+				if !existing[pattern] {
+					audit.Missing = append(audit.Missing, pattern)
+				}
+			}
+		}
+		resp.Collections = append(resp.Collections, audit)
+	}
+
+	resp.SlowQueries, err = listSlowQueries(ctx, db, args.SlowThreshold, args.SlowLimit)
+	if err != nil {
+		// the profiler might be disabled or the user might not be permitted to read it, which should not fail the audit
+		resp.ProfilerError = err.Error()
+	}
+	return resp, nil
+}
+
+func listExistingIndexes(ctx context.Context, coll *mongo.Collection) ([]*existingIndex, error) {
+	cursor, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]*existingIndex, 0)
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+func auditCollectionIndexes(collName string, indexes []*existingIndex) *MongoCollectionIndexAudit {
+	audit := &MongoCollectionIndexAudit{
+		Collection: collName,
+		Indexes:    make([]string, 0),
+		Missing:    make([]string, 0),
+		Duplicates: make([]string, 0),
+		Redundant:  make([]string, 0),
+	}
+
+	seen := make(map[string]string)
+	for _, index := range indexes {
+		pattern := indexKeyPattern(index.Key)
+		audit.Indexes = append(audit.Indexes, pattern)
+		if name, ok := seen[pattern]; ok {
+			audit.Duplicates = append(audit.Duplicates, fmt.Sprintf("%s (same as %s)", index.Name, name))
+			continue
+		}
+		seen[pattern] = index.Name
+	}
+
+	for _, index := range indexes {
+		if index.Unique || index.Name == "_id_" {
+			continue
+		}
+		for _, other := range indexes {
+			if other == index || len(other.Key) <= len(index.Key) {
+				continue
+			}
+			if isIndexKeyPrefix(index.Key, other.Key) {
+				audit.Redundant = append(audit.Redundant, fmt.Sprintf("%s (prefix of %s)", index.Name, other.Name))
+				break
+			}
+		}
+	}
+	return audit
+}
+
+func isIndexKeyPrefix(prefix, keys bson.D) bool {
+	for i, elem := range prefix {
+		if keys[i].Key != elem.Key || fmt.Sprint(keys[i].Value) != fmt.Sprint(elem.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexKeyPattern formats the keys of an index in the way mongo names the index by default
+func indexKeyPattern(keys interface{}) string {
+	parts := make([]string, 0)
+	switch k := keys.(type) {
+	case bson.D:
+		for _, elem := range k {
+			parts = append(parts, fmt.Sprintf("%s_%v", elem.Key, elem.Value))
+		}
+	case bson.M:
+		for key, value := range k {
+			parts = append(parts, fmt.Sprintf("%s_%v", key, value))
+		}
+		sort.Strings(parts)
+	default:
+		return fmt.Sprint(keys)
+	}
+	return strings.Join(parts, "_")
+}
+
+func listSlowQueries(ctx context.Context, db *mongo.Database, threshold, limit int64) ([]*MongoSlowQuery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "ts", Value: -1}}).SetLimit(limit)
+	cursor, err := db.Collection("system.profile").Find(ctx, bson.M{"millis": bson.M{"$gte": threshold}}, opts)
+	if err != nil {
+		return make([]*MongoSlowQuery, 0), err
+	}
+
+	profiles := make([]bson.M, 0)
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return make([]*MongoSlowQuery, 0), err
+	}
+
+	ret := make([]*MongoSlowQuery, 0, len(profiles))
+	for _, profile := range profiles {
+		query := &MongoSlowQuery{
+			Namespace:    fmt.Sprint(profile["ns"]),
+			Operation:    fmt.Sprint(profile["op"]),
+			Millis:       profileInt(profile["millis"]),
+			DocsExamined: profileInt(profile["docsExamined"]),
+			KeysExamined: profileInt(profile["keysExamined"]),
+		}
+		if planSummary, ok := profile["planSummary"].(string); ok {
+			query.PlanSummary = planSummary
+		}
+		if ts, ok := profile["ts"].(primitive.DateTime); ok {
+			query.Time = ts.Time().Unix()
+		}
+		if command, ok := profile["command"]; ok {
+			if data, err := bson.MarshalExtJSON(command, false, false); err == nil {
+				query.Command = string(data)
+			}
+		}
+		ret = append(ret, query)
+	}
+	return ret, nil
+}
+
+func profileInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -17,19 +17,26 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 
+	configbase "github.com/koderover/zadig/v2/pkg/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	cluster "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/multicluster/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/types"
 )
 
-func OpenAPICreateRegistry(username string, req *OpenAPICreateRegistryReq, logger *zap.SugaredLogger) error {
+// OpenAPICreateRegistry creates the registry and returns its id.
+func OpenAPICreateRegistry(username string, req *OpenAPICreateRegistryReq, logger *zap.SugaredLogger) (string, error) {
 	reg := &commonmodels.RegistryNamespace{
+		ID:          primitive.NewObjectID(),
 		RegAddr:     req.Address,
 		RegProvider: string(req.Provider),
 		IsDefault:   req.IsDefault,
@@ -46,7 +53,10 @@ func OpenAPICreateRegistry(username string, req *OpenAPICreateRegistryReq, logge
 		},
 	}
 
-	return CreateRegistryNamespace(username, reg, logger)
+	if err := CreateRegistryNamespace(username, reg, logger); err != nil {
+		return "", err
+	}
+	return reg.ID.Hex(), nil
 }
 
 func getProjectNames(clusterID string, logger *zap.SugaredLogger) (projectNames []string) {
@@ -61,28 +71,116 @@ func getProjectNames(clusterID string, logger *zap.SugaredLogger) (projectNames
 	return projectNames
 }
 
-func OpenAPICreateCluster(projectName string, logger *zap.SugaredLogger) ([]*OpenAPICluster, error) {
-	clusters, err := cluster.ListClusters([]string{}, projectName, logger)
+// OpenAPICreateCluster creates an agent type cluster with the default settings, the agent is installed by the returned command.
+func OpenAPICreateCluster(userName string, req *OpenAPICreateClusterRequest, logger *zap.SugaredLogger) (*OpenAPICreateClusterResponse, error) {
+	clusterAccessYaml := `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: koderover-agent-admin
+rules:
+- apiGroups:
+  - '*'
+  resources:
+  - '*'
+  verbs:
+  - '*'
+- nonResourceURLs:
+  - '*'
+  verbs:
+  - '*'
+`
+
+	AdvancedConfig := &cluster.AdvancedConfig{
+		ClusterAccessYaml: clusterAccessYaml,
+		ProjectNames:      req.ProjectNames,
+		ScheduleWorkflow:  true,
+		ScheduleStrategy: []*cluster.ScheduleStrategy{
+			{
+				NodeLabels:   []string{},
+				StrategyName: "normal",
+				Strategy:     "normal",
+				Default:      true,
+			},
+		},
+	}
+	dindCfg := &commonmodels.DindCfg{
+		Replicas: 1,
+		Resources: &commonmodels.Resources{
+			Limits: &commonmodels.Limits{
+				CPU:    4000,
+				Memory: 8192,
+			},
+		},
+		Storage: &commonmodels.DindStorage{
+			StorageSizeInGiB: 10,
+			Type:             commonmodels.DindStorageRootfs,
+		},
+	}
+	shareStorage := types.ShareStorage{
+		MediumType: types.NFSMedium,
+		NFSProperties: types.NFSProperties{
+			PVC:              "cache-cfs-10",
+			StorageClass:     "cfs",
+			StorageSizeInGiB: 10,
+		},
+	}
+
+	clusterArgs := &cluster.K8SCluster{
+		Name:           req.Name,
+		Type:           req.Type,
+		Provider:       req.Provider,
+		KubeConfig:     req.KubeConfig,
+		Description:    req.Description,
+		Production:     req.Production,
+		AdvancedConfig: AdvancedConfig,
+		DindCfg:        dindCfg,
+		ShareStorage:   shareStorage,
+		CreatedAt:      time.Now().Unix(),
+		CreatedBy:      userName + "(openAPI)",
+	}
+
+	if err := clusterArgs.Clean(); err != nil {
+		return nil, e.ErrInvalidParam.AddErr(err)
+	}
+
+	err := clusterArgs.Validate()
 	if err != nil {
-		logger.Errorf("OpenAPI:ListClusters err : %v", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to validate cluster: %v", err)
 	}
 
-	resp := make([]*OpenAPICluster, 0)
-	for _, cl := range clusters {
-		resp = append(resp, &OpenAPICluster{
-			ID:           cl.ID,
-			Name:         cl.Name,
-			Production:   cl.Production,
-			Description:  cl.Description,
-			ProviderName: ClusterProviderValueNames[cl.Provider],
-			CreatedBy:    cl.CreatedBy,
-			CreatedTime:  cl.CreatedAt,
-			Local:        cl.Local,
-			Status:       string(cl.Status),
-			Type:         cl.Type,
-			ProjectNames: getProjectNames(cl.ID, logger),
+	clusterResp, err := cluster.CreateCluster(clusterArgs, logger)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create cluster: %v", err)
+	}
+
+	for _, projectName := range req.ProjectNames {
+		err = commonrepo.NewProjectClusterRelationColl().Create(&commonmodels.ProjectClusterRelation{
+			ProjectName: projectName,
+			ClusterID:   clusterResp.ID.Hex(),
+			CreatedBy:   userName + "(openAPI)",
 		})
+		if err != nil {
+			logger.Errorf("Failed to create projectClusterRelation err:%s", err)
+		}
+	}
+
+	agentCmd := fmt.Sprintf(`kubectl apply -f "%s/api/aslan/cluster/agent/%s/agent.yaml?type=deploy"`, configbase.SystemAddress(), clusterResp.ID.Hex())
+
+	resp := &OpenAPICreateClusterResponse{
+		Cluster: &OpenAPICluster{
+			ID:           clusterResp.ID.Hex(),
+			Name:         clusterResp.Name,
+			Type:         clusterResp.Type,
+			ProviderName: ClusterProviderValueNames[clusterResp.Provider],
+			Production:   clusterResp.Production,
+			Description:  clusterResp.Description,
+			ProjectNames: cluster.GetProjectNames(clusterResp.ID.Hex(), logger),
+			Local:        clusterResp.Local,
+			Status:       string(clusterResp.Status),
+			CreatedBy:    clusterResp.CreatedBy,
+			CreatedTime:  clusterResp.CreatedAt,
+		},
+		AgentCmd: agentCmd,
 	}
 
 	return resp, nil
@@ -117,7 +215,7 @@ func OpenAPIListCluster(projectName string, logger *zap.SugaredLogger) ([]*OpenA
 }
 
 func OpenAPIDeleteCluster(userName, clusterID string, logger *zap.SugaredLogger) error {
-	return cluster.DeleteCluster(userName, clusterID, logger)
+	return cluster.DeleteCluster(userName, clusterID, true, logger)
 }
 
 func OpenAPIUpdateCluster(userName, clusterID string, clusterInfo *OpenAPICluster, logger *zap.SugaredLogger) error {
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/swaggo/swag"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/apispec"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const (
+	openAPIRoutePrefix = "/openapi/"
+	// devSpecVersion is used if the chart version is not set, e.g. running aslan locally
+	devSpecVersion = "dev"
+)
+
+var (
+	openAPISpec     *apispec.Document
+	openAPISpecLock sync.RWMutex
+)
+
+func currentSpecVersion() string {
+	if version := config.ChartVersion(); version != "" {
+		return version
+	}
+	return devSpecVersion
+}
+
+// GenerateOpenAPISpec generates the OpenAPI spec of the /openapi routes from the swag annotations and the registered routes.
+func GenerateOpenAPISpec(routes []apispec.Route, version string) (*apispec.Document, error) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read swagger doc: %v", err)
+	}
+	return apispec.Generate(doc, routes, openAPIRoutePrefix, version)
+}
+
+// InitOpenAPISpec generates the OpenAPI spec of the running version, then saves it as the snapshot of the version
+// so that the specs of different versions can be compared.
+func InitOpenAPISpec(routes []apispec.Route) {
+	spec, err := GenerateOpenAPISpec(routes, currentSpecVersion())
+	if err != nil {
+		log.Errorf("failed to generate openapi spec, error: %s", err)
+		return
+	}
+
+	openAPISpecLock.Lock()
+	openAPISpec = spec
+	openAPISpecLock.Unlock()
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		log.Errorf("failed to marshal openapi spec, error: %s", err)
+		return
+	}
+	digest := sha256.Sum256(raw)
+	err = commonrepo.NewOpenAPISpecSnapshotColl().Upsert(&commonmodels.OpenAPISpecSnapshot{
+		Version: currentSpecVersion(),
+		Digest:  hex.EncodeToString(digest[:]),
+		Spec:    string(raw),
+	})
+	if err != nil {
+		log.Errorf("failed to save openapi spec snapshot of version %s, error: %s", currentSpecVersion(), err)
+	}
+}
+
+func getOpenAPISpec(version string) (*apispec.Document, error) {
+	if version == "" || version == currentSpecVersion() {
+		openAPISpecLock.RLock()
+		defer openAPISpecLock.RUnlock()
+		if openAPISpec == nil {
+			return nil, fmt.Errorf("openapi spec is not generated")
+		}
+		return openAPISpec, nil
+	}
+
+	snapshot, err := commonrepo.NewOpenAPISpecSnapshotColl().Find(version)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("openapi spec of version %s not found", version)
+		}
+		return nil, err
+	}
+	spec := new(apispec.Document)
+	if err := json.Unmarshal([]byte(snapshot.Spec), spec); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi spec of version %s: %v", version, err)
+	}
+	return spec, nil
+}
+
+// GetOpenAPISpec returns the OpenAPI spec of the version, the spec of the running version is returned if version is empty.
+func GetOpenAPISpec(version string, logger *zap.SugaredLogger) (*apispec.Document, error) {
+	spec, err := getOpenAPISpec(version)
+	if err != nil {
+		logger.Errorf("failed to get openapi spec of version %s, error: %s", version, err)
+		return nil, e.ErrGetOpenAPISpec.AddErr(err)
+	}
+	return spec, nil
+}
+
+func ListOpenAPISpecVersions(logger *zap.SugaredLogger) ([]*commonmodels.OpenAPISpecSnapshot, error) {
+	snapshots, err := commonrepo.NewOpenAPISpecSnapshotColl().List()
+	if err != nil {
+		logger.Errorf("failed to list openapi spec snapshots, error: %s", err)
+		return nil, e.ErrGetOpenAPISpec.AddErr(err)
+	}
+	return snapshots, nil
+}
+
+// DiffOpenAPISpec compares the OpenAPI specs of two versions, target defaults to the running version.
+func DiffOpenAPISpec(base, target string, logger *zap.SugaredLogger) (*apispec.Diff, error) {
+	if base == "" {
+		return nil, e.ErrInvalidParam.AddDesc("base version must be specified")
+	}
+	baseSpec, err := getOpenAPISpec(base)
+	if err != nil {
+		logger.Errorf("failed to get openapi spec of version %s, error: %s", base, err)
+		return nil, e.ErrDiffOpenAPISpec.AddErr(err)
+	}
+	targetSpec, err := getOpenAPISpec(target)
+	if err != nil {
+		logger.Errorf("failed to get openapi spec of version %s, error: %s", target, err)
+		return nil, e.ErrDiffOpenAPISpec.AddErr(err)
+	}
+
+	diff, err := apispec.Compare(baseSpec, targetSpec)
+	if err != nil {
+		logger.Errorf("failed to compare openapi spec of version %s and %s, error: %s", base, target, err)
+		return nil, e.ErrDiffOpenAPISpec.AddErr(err)
+	}
+	return diff, nil
+}
@@ -39,6 +39,7 @@ import (
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 	"github.com/koderover/zadig/v2/pkg/tool/jira"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/tool/servicenow"
 )
 
 func ListProjectManagement(log *zap.SugaredLogger) ([]*models.ProjectManagement, error) {
@@ -123,6 +124,16 @@ func ValidateMeego(info *models.ProjectManagement) error {
 	return nil
 }
 
+func ValidateServiceNow(info *models.ProjectManagement) error {
+	_, err := servicenow.NewClient(info.ServiceNowHost, info.ServiceNowUser, info.ServiceNowPassword).R().
+		SetQueryParam("sysparm_limit", "1").Get("/api/now/table/change_request")
+	if err != nil {
+		log.Errorf("Validate servicenow error: %v", err)
+		return e.ErrValidateProjectManagement.AddDesc("failed to validate servicenow")
+	}
+	return nil
+}
+
 type JiraProjectResp struct {
 	Name string `json:"name"`
 	Key  string `json:"key"`
@@ -543,7 +554,7 @@ func HandleMeegoHookEvent(workflowName, hookName string, event *meego.GeneralWeb
 
 func checkType(_type string) error {
 	switch _type {
-	case setting.PMJira, setting.PMMeego, setting.PMLark:
+	case setting.PMJira, setting.PMMeego, setting.PMLark, setting.PMServiceNow:
 	default:
 		return errors.New("invalid pm type")
 	}
@@ -17,7 +17,6 @@ limitations under the License.
 package service
 
 import (
-	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -32,7 +31,9 @@ import (
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/registry"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
@@ -123,6 +124,7 @@ func CreateRegistryNamespace(username string, args *commonmodels.RegistryNamespa
 
 	args.UpdateBy = username
 	args.Namespace = strings.TrimSpace(args.Namespace)
+	args.CredentialStatus = nil
 
 	if err := commonrepo.NewRegistryNamespaceColl().Create(args); err != nil {
 		log.Errorf("RegistryNamespace.Create error: %v", err)
@@ -155,15 +157,28 @@ func UpdateRegistryNamespace(username, id string, args *commonmodels.RegistryNam
 
 	args.UpdateBy = username
 	args.Namespace = strings.TrimSpace(args.Namespace)
+	// the status is maintained by the credential check
+	args.CredentialStatus = nil
 
 	if err := commonrepo.NewRegistryNamespaceColl().Update(id, args); err != nil {
 		log.Errorf("RegistryNamespace.Update error: %v", err)
 		return fmt.Errorf("RegistryNamespace.Update error: %v", err)
 	}
+	// the access key pair may be changed
+	commonutil.ClearTemporaryRegistryCredential(id)
 	return SyncDinDForRegistries()
 }
 
-func DeleteRegistryNamespace(id string, log *zap.SugaredLogger) error {
+func DeleteRegistryNamespace(id string, force bool, log *zap.SugaredLogger) error {
+	references, err := integration.GetRegistryReferences(id)
+	if err != nil {
+		log.Errorf("failed to get references of registry %s, error: %s", id, err)
+		return err
+	}
+	if err := references.CheckDeletion(force); err != nil {
+		return err
+	}
+
 	registries, err := commonrepo.NewRegistryNamespaceColl().FindAll(&commonrepo.FindRegOps{})
 	if err != nil {
 		log.Errorf("RegistryNamespace.FindAll error: %s", err)
@@ -173,15 +188,6 @@ func DeleteRegistryNamespace(id string, log *zap.SugaredLogger) error {
 		isDefault          = false
 		registryNamespaces []*commonmodels.RegistryNamespace
 	)
-	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
-		ExcludeStatus: []string{setting.ProductStatusDeleting},
-	})
-
-	for _, env := range envs {
-		if env.RegistryID == id {
-			return errors.New("The registry cannot be deleted, it's being used by environment")
-		}
-	}
 
 	// whether it is the default registry
 	for _, registry := range registries {
@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configbase "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/instantmessage"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/registry"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/webhooknotify"
+	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	kubeclient "github.com/koderover/zadig/v2/pkg/shared/kube/client"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const (
+	defaultRegistryCredentialAlertDays = 7
+	registryCredentialAlertInterval    = 24 * time.Hour
+)
+
+// CheckRegistryCredential verifies the credential of the registry and saves the result, no notification is sent.
+func CheckRegistryCredential(id string, logger *zap.SugaredLogger) (*commonmodels.RegistryCredentialStatus, error) {
+	reg, err := commonrepo.NewRegistryNamespaceColl().Find(&commonrepo.FindRegOps{ID: id})
+	if err != nil {
+		return nil, e.ErrCheckRegistryCredential.AddErr(err)
+	}
+
+	status, _ := checkRegistryCredential(reg, logger)
+	if err := commonrepo.NewRegistryNamespaceColl().UpdateCredentialStatus(id, status); err != nil {
+		return nil, e.ErrCheckRegistryCredential.AddErr(err)
+	}
+	return status, nil
+}
+
+// RunRegistryCredentialChecks verifies the credentials of all the registries, rotates the image pull secrets of the
+// temporary credentials before they expire and notifies the invalid or expiring credentials.
+func RunRegistryCredentialChecks() {
+	logger := log.SugaredLogger().With("source", "registry credential check")
+	registries, err := commonrepo.NewRegistryNamespaceColl().FindAll(&commonrepo.FindRegOps{})
+	if err != nil {
+		logger.Errorf("failed to list registries: %s", err)
+		return
+	}
+
+	for _, reg := range registries {
+		status, decoded := checkRegistryCredential(reg, logger)
+
+		// a new temporary token is requested before the current one expires, the image pull secrets are rotated then
+		if status.Valid && reg.UseTemporaryCredential() && reg.CredentialSetting != nil && reg.CredentialSetting.AutoRotate &&
+			(reg.CredentialStatus == nil || reg.CredentialStatus.TokenExpireTime != status.TokenExpireTime) {
+			if err := rotateRegistrySecrets(decoded, logger); err != nil {
+				logger.Errorf("failed to rotate image pull secrets of registry %s: %s", reg.RegAddr, err)
+			} else {
+				status.RotateTime = time.Now().Unix()
+			}
+		}
+
+		if notify := registryCredentialAlert(reg, status); notify != nil {
+			if err := instantmessage.NewWeChatClient().SendRegistryCredentialNotifications(notify, reg.CredentialSetting.NotifyCtls); err != nil {
+				logger.Errorf("failed to send credential notifications of registry %s: %s", reg.RegAddr, err)
+			}
+			status.AlertTime = time.Now().Unix()
+		}
+
+		if err := commonrepo.NewRegistryNamespaceColl().UpdateCredentialStatus(reg.ID.Hex(), status); err != nil {
+			logger.Errorf("failed to save credential status of registry %s: %s", reg.RegAddr, err)
+		}
+	}
+}
+
+// checkRegistryCredential logs in the registry with its credential, the registry with the decoded credential is returned as well.
+func checkRegistryCredential(reg *commonmodels.RegistryNamespace, logger *zap.SugaredLogger) (*commonmodels.RegistryCredentialStatus, *commonmodels.RegistryNamespace) {
+	status := &commonmodels.RegistryCredentialStatus{
+		CheckTime: time.Now().Unix(),
+	}
+	if reg.CredentialStatus != nil {
+		status.TokenExpireTime = reg.CredentialStatus.TokenExpireTime
+		status.RotateTime = reg.CredentialStatus.RotateTime
+		status.AlertTime = reg.CredentialStatus.AlertTime
+	}
+
+	decoded := *reg
+	if reg.UseTemporaryCredential() {
+		cred, err := commonutil.GetTemporaryRegistryCredential(reg, false)
+		if err != nil {
+			status.Message = fmt.Sprintf("failed to get temporary credential: %s", err)
+			return status, &decoded
+		}
+		decoded.AccessKey = cred.Username
+		decoded.SecretKey = cred.Password
+		status.TokenExpireTime = cred.ExpireTime
+	} else if _, err := commonutil.DecodeRegistry(&decoded); err != nil {
+		status.Message = err.Error()
+		return status, &decoded
+	}
+
+	tlsEnabled, tlsCert := false, ""
+	if reg.AdvancedSetting != nil {
+		tlsEnabled, tlsCert = reg.AdvancedSetting.TLSEnabled, reg.AdvancedSetting.TLSCert
+	}
+	err := registry.CheckLogin(registry.Endpoint{
+		Addr:      reg.RegAddr,
+		Ak:        decoded.AccessKey,
+		Sk:        decoded.SecretKey,
+		Region:    reg.Region,
+		Namespace: reg.Namespace,
+	}, tlsEnabled, tlsCert, logger)
+	if err != nil {
+		status.Message = err.Error()
+		return status, &decoded
+	}
+
+	status.Valid = true
+	return status, &decoded
+}
+
+// registryCredentialAlert returns the notification if the credential is invalid or about to expire,
+// at most one notification is sent a day.
+func registryCredentialAlert(reg *commonmodels.RegistryNamespace, status *commonmodels.RegistryCredentialStatus) *webhooknotify.RegistryCredentialNotify {
+	credSetting := reg.CredentialSetting
+	if credSetting == nil || len(credSetting.NotifyCtls) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if now.Sub(time.Unix(status.AlertTime, 0)) < registryCredentialAlertInterval {
+		return nil
+	}
+
+	notify := &webhooknotify.RegistryCredentialNotify{
+		RegistryID: reg.ID.Hex(),
+		RegAddr:    reg.RegAddr,
+		Namespace:  reg.Namespace,
+		Provider:   reg.RegProvider,
+		Valid:      status.Valid,
+		Message:    status.Message,
+		DetailURL:  fmt.Sprintf("%s/v1/system/registry", configbase.SystemAddress()),
+	}
+	if !status.Valid {
+		return notify
+	}
+
+	if credSetting.ExpireTime == 0 {
+		return nil
+	}
+	alertDays := credSetting.AlertDays
+	if alertDays == 0 {
+		alertDays = defaultRegistryCredentialAlertDays
+	}
+	daysLeft := int(time.Unix(credSetting.ExpireTime, 0).Sub(now).Hours() / 24)
+	if daysLeft > alertDays {
+		return nil
+	}
+	notify.ExpireTime = credSetting.ExpireTime
+	notify.DaysLeft = daysLeft
+	return notify
+}
+
+// rotateRegistrySecrets updates the image pull secrets of the registry in the namespaces of all the environments
+func rotateRegistrySecrets(reg *commonmodels.RegistryNamespace, logger *zap.SugaredLogger) error {
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+		ExcludeStatus: []string{setting.ProductStatusDeleting, setting.ProductStatusUnknown},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %s", err)
+	}
+	secretName, err := kube.GenRegistrySecretName(reg)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]string, 0)
+	for _, env := range envs {
+		if env.Namespace == "" || env.ClusterID == "" {
+			continue
+		}
+		kubeClient, err := kubeclient.GetKubeClient(config.HubServerAddress(), env.ClusterID)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %s", env.ProductName, env.EnvName, err))
+			continue
+		}
+
+		if env.RegistryID == reg.ID.Hex() || (env.RegistryID == "" && reg.IsDefault) {
+			if err := kube.CreateOrUpdateDefaultRegistrySecret(env.Namespace, reg, kubeClient); err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %s", env.ProductName, env.EnvName, err))
+				continue
+			}
+		}
+		if secretName == setting.DefaultImagePullSecret {
+			continue
+		}
+
+		// the secret named after the registry is created when it is used by the services, only the existing ones are updated
+		err = kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: env.Namespace, Name: secretName}, &corev1.Secret{})
+		if err != nil {
+			continue
+		}
+		if err := kube.CreateOrUpdateRegistrySecret(env.Namespace, reg, false, kubeClient); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %s", env.ProductName, env.EnvName, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to update secrets in environments: %s", strings.Join(errs, "; "))
+	}
+	logger.Infof("image pull secrets of registry %s are rotated", reg.RegAddr)
+	return nil
+}
@@ -0,0 +1,561 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+const (
+	defaultDashboardTaskDays  = 7
+	defaultDashboardItemLimit = 50
+	maxDashboardItemLimit     = 500
+)
+
+// DashboardViewer is the user viewing a dashboard, the data of the dashboard is limited to the projects the viewer can access
+type DashboardViewer struct {
+	UserID   string
+	UserName string
+	IsAdmin  bool
+	// ProjectAdmins are the projects the viewer is admin of
+	ProjectAdmins sets.String
+}
+
+type SavedDashboardData struct {
+	Dashboard *commonmodels.SavedDashboard `json:"dashboard"`
+	Widgets   []*DashboardWidgetData       `json:"widgets"`
+}
+
+type DashboardWidgetData struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Type         string                  `json:"type"`
+	Error        string                  `json:"error,omitempty"`
+	StatusCount  map[string]int          `json:"status_count,omitempty"`
+	Tasks        []*DashboardTaskItem    `json:"tasks,omitempty"`
+	Environments []*DashboardEnvItem     `json:"environments,omitempty"`
+	Services     []*DashboardServiceItem `json:"services,omitempty"`
+}
+
+type DashboardTaskItem struct {
+	ProjectName         string `json:"project_name"`
+	WorkflowName        string `json:"workflow_name"`
+	WorkflowDisplayName string `json:"workflow_display_name"`
+	TaskID              int64  `json:"task_id"`
+	Status              string `json:"status"`
+	Creator             string `json:"creator"`
+	CreateTime          int64  `json:"create_time"`
+	StartTime           int64  `json:"start_time"`
+	EndTime             int64  `json:"end_time"`
+}
+
+type DashboardEnvItem struct {
+	ProjectName string `json:"project_name"`
+	EnvName     string `json:"env_name"`
+	Production  bool   `json:"production"`
+	ClusterID   string `json:"cluster_id"`
+	Namespace   string `json:"namespace"`
+	Status      string `json:"status"`
+	UpdateBy    string `json:"update_by"`
+	UpdateTime  int64  `json:"update_time"`
+}
+
+type DashboardServiceItem struct {
+	ProjectName string   `json:"project_name"`
+	EnvName     string   `json:"env_name"`
+	Production  bool     `json:"production"`
+	ServiceName string   `json:"service_name"`
+	Revision    int64    `json:"revision"`
+	Images      []string `json:"images"`
+	EnvStatus   string   `json:"env_status"`
+	UpdateTime  int64    `json:"update_time"`
+}
+
+func CreateSavedDashboard(viewer *DashboardViewer, args *commonmodels.SavedDashboard) (*commonmodels.SavedDashboard, error) {
+	if err := validateSavedDashboard(viewer, args); err != nil {
+		return nil, err
+	}
+	args.CreatorID = viewer.UserID
+	args.CreatedBy = viewer.UserName
+	args.ShareToken = ""
+	if err := commonrepo.NewSavedDashboardColl().Create(args); err != nil {
+		return nil, e.ErrCreateDashboard.AddErr(err)
+	}
+	return args, nil
+}
+
+func UpdateSavedDashboard(viewer *DashboardViewer, id string, args *commonmodels.SavedDashboard) error {
+	dashboard, err := getEditableDashboard(viewer, id)
+	if err != nil {
+		return err
+	}
+	if err := validateSavedDashboard(viewer, args); err != nil {
+		return err
+	}
+
+	args.CreatorID = dashboard.CreatorID
+	args.CreatedBy = dashboard.CreatedBy
+	args.CreateTime = dashboard.CreateTime
+	args.ShareToken = dashboard.ShareToken
+	if err := commonrepo.NewSavedDashboardColl().Update(id, args); err != nil {
+		return e.ErrUpdateDashboard.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteSavedDashboard(viewer *DashboardViewer, id string) error {
+	if _, err := getEditableDashboard(viewer, id); err != nil {
+		return err
+	}
+	if err := commonrepo.NewSavedDashboardColl().Delete(id); err != nil {
+		return e.ErrDeleteDashboard.AddErr(err)
+	}
+	return nil
+}
+
+func ListSavedDashboards(viewer *DashboardViewer, projectName string, log *zap.SugaredLogger) ([]*commonmodels.SavedDashboard, error) {
+	projects, err := viewerProjects(viewer, "workflow", types.WorkflowActionView)
+	if err != nil {
+		log.Errorf("failed to list authorized projects of user %s: %s", viewer.UserName, err)
+		return nil, e.ErrListDashboards.AddErr(err)
+	}
+
+	opt := &commonrepo.ListSavedDashboardOption{
+		UserID:       viewer.UserID,
+		ProjectNames: projects,
+		ProjectName:  projectName,
+	}
+	if viewer.IsAdmin {
+		opt.ProjectNames, err = templaterepo.NewProductColl().ListAllName()
+		if err != nil {
+			return nil, e.ErrListDashboards.AddErr(err)
+		}
+	}
+
+	resp, err := commonrepo.NewSavedDashboardColl().List(opt)
+	if err != nil {
+		return nil, e.ErrListDashboards.AddErr(err)
+	}
+	for _, dashboard := range resp {
+		// only the creator can see the share token
+		if dashboard.CreatorID != viewer.UserID {
+			dashboard.ShareToken = ""
+		}
+	}
+	return resp, nil
+}
+
+func GetSavedDashboardData(viewer *DashboardViewer, id string, log *zap.SugaredLogger) (*SavedDashboardData, error) {
+	dashboard, err := commonrepo.NewSavedDashboardColl().GetByID(id)
+	if err != nil {
+		return nil, e.ErrGetDashboard.AddErr(err)
+	}
+	visible, err := isDashboardVisible(viewer, dashboard)
+	if err != nil {
+		return nil, e.ErrGetDashboard.AddErr(err)
+	}
+	if !visible {
+		return nil, e.ErrGetDashboard.AddDesc("no permission to view the dashboard")
+	}
+	if dashboard.CreatorID != viewer.UserID {
+		dashboard.ShareToken = ""
+	}
+	return aggregateDashboard(viewer, dashboard, log)
+}
+
+// GetSharedDashboardData returns the dashboard of the share link, the data is still limited to the projects the viewer can access
+func GetSharedDashboardData(viewer *DashboardViewer, token string, log *zap.SugaredLogger) (*SavedDashboardData, error) {
+	if token == "" {
+		return nil, e.ErrInvalidParam.AddDesc("empty share token")
+	}
+	dashboard, err := commonrepo.NewSavedDashboardColl().GetByShareToken(token)
+	if err != nil {
+		return nil, e.ErrGetDashboard.AddDesc("the share link is invalid or revoked")
+	}
+	if dashboard.CreatorID != viewer.UserID {
+		dashboard.ShareToken = ""
+	}
+	return aggregateDashboard(viewer, dashboard, log)
+}
+
+// ShareSavedDashboard generates a new share token of the dashboard, the previous share link is revoked
+func ShareSavedDashboard(viewer *DashboardViewer, id string) (string, error) {
+	if _, err := getEditableDashboard(viewer, id); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", e.ErrShareDashboard.AddErr(err)
+	}
+	token := hex.EncodeToString(buf)
+	if err := commonrepo.NewSavedDashboardColl().UpdateShareToken(id, token); err != nil {
+		return "", e.ErrShareDashboard.AddErr(err)
+	}
+	return token, nil
+}
+
+func RevokeSavedDashboardShare(viewer *DashboardViewer, id string) error {
+	if _, err := getEditableDashboard(viewer, id); err != nil {
+		return err
+	}
+	if err := commonrepo.NewSavedDashboardColl().UpdateShareToken(id, ""); err != nil {
+		return e.ErrShareDashboard.AddErr(err)
+	}
+	return nil
+}
+
+func validateSavedDashboard(viewer *DashboardViewer, args *commonmodels.SavedDashboard) error {
+	if args.Name == "" {
+		return e.ErrInvalidParam.AddDesc("dashboard name can't be empty")
+	}
+	switch args.Visibility {
+	case "":
+		args.Visibility = commonmodels.DashboardVisibilityPrivate
+	case commonmodels.DashboardVisibilityPrivate:
+	case commonmodels.DashboardVisibilityProject:
+		if args.ProjectName == "" {
+			return e.ErrInvalidParam.AddDesc("project must be specified for project visible dashboard")
+		}
+	case commonmodels.DashboardVisibilityPublic:
+		if !viewer.IsAdmin {
+			return e.ErrInvalidParam.AddDesc("only system admin can create public dashboard")
+		}
+	default:
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid visibility: %s", args.Visibility))
+	}
+
+	ids := sets.NewString()
+	for _, widget := range args.Widgets {
+		if widget.ID == "" || ids.Has(widget.ID) {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("widget id of %s is empty or duplicated", widget.Name))
+		}
+		ids.Insert(widget.ID)
+
+		switch widget.Type {
+		case commonmodels.DashboardWidgetTypeWorkflowTask, commonmodels.DashboardWidgetTypeEnvironment, commonmodels.DashboardWidgetTypeService:
+		default:
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("invalid type %s of widget %s", widget.Type, widget.Name))
+		}
+		if widget.Filter == nil {
+			widget.Filter = &commonmodels.DashboardFilter{}
+		}
+		// widgets of the project dashboard only show the data of the project
+		if args.ProjectName != "" {
+			widget.Filter.Projects = []string{args.ProjectName}
+		}
+	}
+	return nil
+}
+
+func getEditableDashboard(viewer *DashboardViewer, id string) (*commonmodels.SavedDashboard, error) {
+	dashboard, err := commonrepo.NewSavedDashboardColl().GetByID(id)
+	if err != nil {
+		return nil, e.ErrGetDashboard.AddErr(err)
+	}
+	if dashboard.CreatorID == viewer.UserID || viewer.IsAdmin {
+		return dashboard, nil
+	}
+	if dashboard.Visibility == commonmodels.DashboardVisibilityProject && viewer.ProjectAdmins.Has(dashboard.ProjectName) {
+		return dashboard, nil
+	}
+	return nil, e.ErrUpdateDashboard.AddDesc("only the creator or the admin can modify the dashboard")
+}
+
+func isDashboardVisible(viewer *DashboardViewer, dashboard *commonmodels.SavedDashboard) (bool, error) {
+	if viewer.IsAdmin || dashboard.CreatorID == viewer.UserID {
+		return true, nil
+	}
+	switch dashboard.Visibility {
+	case commonmodels.DashboardVisibilityPublic:
+		return true, nil
+	case commonmodels.DashboardVisibilityProject:
+		projects, err := viewerProjects(viewer, "workflow", types.WorkflowActionView)
+		if err != nil {
+			return false, err
+		}
+		return sets.NewString(projects...).Has(dashboard.ProjectName), nil
+	}
+	return false, nil
+}
+
+// viewerProjects returns the projects the viewer has the permission of the verb, nil means all the projects
+func viewerProjects(viewer *DashboardViewer, resource, verb string) ([]string, error) {
+	if viewer.IsAdmin {
+		return nil, nil
+	}
+	projects, _, err := user.New().ListAuthorizedProjectsByResourceAndVerb(viewer.UserID, resource, verb)
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// filterProjects returns the projects matching both the filter and the authorized ones, nil means no restriction
+func filterProjects(filter []string, authorized []string, isAdmin bool) []string {
+	if isAdmin {
+		if len(filter) == 0 {
+			return nil
+		}
+		return filter
+	}
+	if len(filter) == 0 {
+		return authorized
+	}
+	return sets.NewString(filter...).Intersection(sets.NewString(authorized...)).List()
+}
+
+func aggregateDashboard(viewer *DashboardViewer, dashboard *commonmodels.SavedDashboard, log *zap.SugaredLogger) (*SavedDashboardData, error) {
+	workflowProjects, err := viewerProjects(viewer, "workflow", types.WorkflowActionView)
+	if err != nil {
+		return nil, e.ErrGetDashboardData.AddErr(err)
+	}
+	envProjects, err := viewerProjects(viewer, "environment", types.EnvActionView)
+	if err != nil {
+		return nil, e.ErrGetDashboardData.AddErr(err)
+	}
+	productionEnvProjects, err := viewerProjects(viewer, "environment", types.ProductionEnvActionView)
+	if err != nil {
+		return nil, e.ErrGetDashboardData.AddErr(err)
+	}
+
+	resp := &SavedDashboardData{
+		Dashboard: dashboard,
+		Widgets:   make([]*DashboardWidgetData, 0),
+	}
+	for _, widget := range dashboard.Widgets {
+		filter := widget.Filter
+		if filter == nil {
+			filter = &commonmodels.DashboardFilter{}
+		}
+		data := &DashboardWidgetData{
+			ID:   widget.ID,
+			Name: widget.Name,
+			Type: string(widget.Type),
+		}
+
+		switch widget.Type {
+		case commonmodels.DashboardWidgetTypeWorkflowTask:
+			err = aggregateDashboardTasks(filter, filterProjects(filter.Projects, workflowProjects, viewer.IsAdmin), viewer.IsAdmin, data)
+		case commonmodels.DashboardWidgetTypeEnvironment, commonmodels.DashboardWidgetTypeService:
+			var envs []*commonmodels.Product
+			envs, err = listDashboardEnvs(filter,
+				filterProjects(filter.Projects, envProjects, viewer.IsAdmin),
+				filterProjects(filter.Projects, productionEnvProjects, viewer.IsAdmin),
+				viewer.IsAdmin)
+			if err == nil {
+				if widget.Type == commonmodels.DashboardWidgetTypeEnvironment {
+					aggregateDashboardEnvs(filter, envs, data)
+				} else {
+					aggregateDashboardServices(filter, envs, data)
+				}
+			}
+		}
+		if err != nil {
+			log.Warnf("failed to aggregate widget %s of dashboard %s: %s", widget.Name, dashboard.Name, err)
+			data.Error = err.Error()
+		}
+		resp.Widgets = append(resp.Widgets, data)
+	}
+	return resp, nil
+}
+
+func dashboardItemLimit(filter *commonmodels.DashboardFilter) int {
+	if filter.Limit <= 0 {
+		return defaultDashboardItemLimit
+	}
+	if filter.Limit > maxDashboardItemLimit {
+		return maxDashboardItemLimit
+	}
+	return filter.Limit
+}
+
+func aggregateDashboardTasks(filter *commonmodels.DashboardFilter, projects []string, isAdmin bool, data *DashboardWidgetData) error {
+	data.StatusCount = make(map[string]int)
+	data.Tasks = make([]*DashboardTaskItem, 0)
+	if !isAdmin && len(projects) == 0 {
+		return nil
+	}
+
+	days := filter.Days
+	if days <= 0 {
+		days = defaultDashboardTaskDays
+	}
+	opt := &commonrepo.ListWorkflowTaskV4Option{
+		ProjectNames: projects,
+		CreateTime:   time.Now().AddDate(0, 0, -days).Unix(),
+		IsSort:       true,
+	}
+	if len(filter.Workflows) > 0 {
+		opt.WorkflowNames = filter.Workflows
+	}
+	cursor, err := commonrepo.NewworkflowTaskv4Coll().ListByCursor(opt)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	statuses := sets.NewString(filter.Statuses...)
+	limit := dashboardItemLimit(filter)
+	for cursor.Next(context.TODO()) {
+		task := new(commonmodels.WorkflowTask)
+		if err := cursor.Decode(task); err != nil {
+			return err
+		}
+		if statuses.Len() > 0 && !statuses.Has(string(task.Status)) {
+			continue
+		}
+		data.StatusCount[string(task.Status)]++
+		if len(data.Tasks) < limit {
+			data.Tasks = append(data.Tasks, &DashboardTaskItem{
+				ProjectName:         task.ProjectName,
+				WorkflowName:        task.WorkflowName,
+				WorkflowDisplayName: task.WorkflowDisplayName,
+				TaskID:              task.TaskID,
+				Status:              string(task.Status),
+				Creator:             task.TaskCreator,
+				CreateTime:          task.CreateTime,
+				StartTime:           task.StartTime,
+				EndTime:             task.EndTime,
+			})
+		}
+	}
+	return cursor.Err()
+}
+
+func listDashboardEnvs(filter *commonmodels.DashboardFilter, testProjects, productionProjects []string, isAdmin bool) ([]*commonmodels.Product, error) {
+	resp := make([]*commonmodels.Product, 0)
+	for _, production := range []bool{false, true} {
+		if filter.Production != nil && *filter.Production != production {
+			continue
+		}
+		projects := testProjects
+		if production {
+			projects = productionProjects
+		}
+		if !isAdmin && len(projects) == 0 {
+			continue
+		}
+
+		production := production
+		envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+			InProjects:    projects,
+			InEnvs:        filter.Envs,
+			Production:    &production,
+			ExcludeStatus: []string{setting.ProductStatusDeleting},
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, envs...)
+	}
+
+	sort.SliceStable(resp, func(i, j int) bool {
+		if resp[i].ProductName != resp[j].ProductName {
+			return resp[i].ProductName < resp[j].ProductName
+		}
+		return resp[i].EnvName < resp[j].EnvName
+	})
+	return resp, nil
+}
+
+func aggregateDashboardEnvs(filter *commonmodels.DashboardFilter, envs []*commonmodels.Product, data *DashboardWidgetData) {
+	data.StatusCount = make(map[string]int)
+	data.Environments = make([]*DashboardEnvItem, 0)
+
+	statuses := sets.NewString(filter.Statuses...)
+	limit := dashboardItemLimit(filter)
+	for _, env := range envs {
+		if statuses.Len() > 0 && !statuses.Has(env.Status) {
+			continue
+		}
+		data.StatusCount[env.Status]++
+		if len(data.Environments) < limit {
+			data.Environments = append(data.Environments, &DashboardEnvItem{
+				ProjectName: env.ProductName,
+				EnvName:     env.EnvName,
+				Production:  env.Production,
+				ClusterID:   env.ClusterID,
+				Namespace:   env.Namespace,
+				Status:      env.Status,
+				UpdateBy:    env.UpdateBy,
+				UpdateTime:  env.UpdateTime,
+			})
+		}
+	}
+}
+
+func aggregateDashboardServices(filter *commonmodels.DashboardFilter, envs []*commonmodels.Product, data *DashboardWidgetData) {
+	data.StatusCount = make(map[string]int)
+	data.Services = make([]*DashboardServiceItem, 0)
+
+	services := sets.NewString(filter.Services...)
+	statuses := sets.NewString(filter.Statuses...)
+	limit := dashboardItemLimit(filter)
+	for _, env := range envs {
+		if statuses.Len() > 0 && !statuses.Has(env.Status) {
+			continue
+		}
+		serviceMap := env.GetServiceMap()
+		serviceNames := make([]string, 0, len(serviceMap))
+		for serviceName := range serviceMap {
+			serviceNames = append(serviceNames, serviceName)
+		}
+		sort.Strings(serviceNames)
+
+		for _, serviceName := range serviceNames {
+			if services.Len() > 0 && !services.Has(serviceName) {
+				continue
+			}
+			svc := serviceMap[serviceName]
+			data.StatusCount[env.Status]++
+			if len(data.Services) >= limit {
+				continue
+			}
+			images := make([]string, 0)
+			for _, container := range svc.Containers {
+				images = append(images, container.Image)
+			}
+			data.Services = append(data.Services, &DashboardServiceItem{
+				ProjectName: env.ProductName,
+				EnvName:     env.EnvName,
+				Production:  env.Production,
+				ServiceName: serviceName,
+				Revision:    svc.Revision,
+				Images:      images,
+				EnvStatus:   env.Status,
+				UpdateTime:  svc.UpdateTime,
+			})
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/checkmarx"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/semgrep"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+func ListScannerIntegration(_type string, isAdmin bool) ([]*models.ScannerIntegration, error) {
+	resp, err := mongodb.NewScannerIntegrationColl().List(context.Background(), _type)
+	if err != nil {
+		return nil, e.ErrListScannerIntegration.AddErr(err)
+	}
+	if !isAdmin {
+		for _, v := range resp {
+			v.Token = ""
+		}
+	}
+	return resp, nil
+}
+
+func CreateScannerIntegration(args *models.ScannerIntegration) error {
+	if err := checkScannerIntegration(args); err != nil {
+		return e.ErrCreateScannerIntegration.AddErr(err)
+	}
+	if err := mongodb.NewScannerIntegrationColl().Create(context.Background(), args); err != nil {
+		return e.ErrCreateScannerIntegration.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateScannerIntegration(id string, args *models.ScannerIntegration) error {
+	if err := checkScannerIntegration(args); err != nil {
+		return e.ErrUpdateScannerIntegration.AddErr(err)
+	}
+	if err := mongodb.NewScannerIntegrationColl().Update(context.Background(), id, args); err != nil {
+		return e.ErrUpdateScannerIntegration.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteScannerIntegration(id string) error {
+	if err := mongodb.NewScannerIntegrationColl().DeleteByID(context.Background(), id); err != nil {
+		return e.ErrDeleteScannerIntegration.AddErr(err)
+	}
+	return nil
+}
+
+func ValidateScannerIntegration(args *models.ScannerIntegration) error {
+	if err := checkScannerIntegration(args); err != nil {
+		return e.ErrValidateScannerIntegration.AddErr(err)
+	}
+
+	var err error
+	switch args.Type {
+	case types.ScanningTypeSemgrep:
+		_, err = semgrep.NewClient(args.ServerAddress, args.Token).ListDeployments()
+	case types.ScanningTypeCheckmarx:
+		_, err = checkmarx.NewClient(args.ServerAddress, args.AuthAddress, args.Tenant, args.Token).ListProjects(1)
+	}
+	if err != nil {
+		return e.ErrValidateScannerIntegration.AddErr(err)
+	}
+	return nil
+}
+
+func checkScannerIntegration(args *models.ScannerIntegration) error {
+	if args.Name == "" {
+		return fmt.Errorf("name must be provided")
+	}
+	switch args.Type {
+	case types.ScanningTypeSemgrep:
+		// semgrep runs the open source rules without a token, the token is only needed for semgrep app
+		return nil
+	case types.ScanningTypeCheckmarx:
+		if args.ServerAddress == "" || args.Tenant == "" || args.Token == "" {
+			return fmt.Errorf("server address, tenant and api key must be provided for checkmarx")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid scanner type: %s", args.Type)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/secrets"
+)
+
+func ListSecretProvider(isAdmin bool) ([]*models.SecretProvider, error) {
+	resp, err := mongodb.NewSecretProviderColl().List(context.Background())
+	if err != nil {
+		return nil, e.ErrListSecretProvider.AddErr(err)
+	}
+	if !isAdmin {
+		for _, v := range resp {
+			v.Token = ""
+			v.AccessKey = ""
+			v.SecretKey = ""
+		}
+	}
+	return resp, nil
+}
+
+func CreateSecretProvider(args *models.SecretProvider) error {
+	if err := checkSecretProvider(args); err != nil {
+		return e.ErrCreateSecretProvider.AddErr(err)
+	}
+	if err := mongodb.NewSecretProviderColl().Create(context.Background(), args); err != nil {
+		return e.ErrCreateSecretProvider.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateSecretProvider(id string, args *models.SecretProvider) error {
+	if err := checkSecretProvider(args); err != nil {
+		return e.ErrUpdateSecretProvider.AddErr(err)
+	}
+	if err := mongodb.NewSecretProviderColl().Update(context.Background(), id, args); err != nil {
+		return e.ErrUpdateSecretProvider.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteSecretProvider(id string) error {
+	if err := mongodb.NewSecretProviderColl().DeleteByID(context.Background(), id); err != nil {
+		return e.ErrDeleteSecretProvider.AddErr(err)
+	}
+	return nil
+}
+
+func ValidateSecretProvider(args *models.SecretProvider) error {
+	if err := checkSecretProvider(args); err != nil {
+		return e.ErrValidateSecretProvider.AddErr(err)
+	}
+
+	var err error
+	switch args.Type {
+	case secrets.ProviderTypeVault:
+		err = secrets.NewVaultProvider(args.Address, args.Token, args.Namespace, args.MountPath).Validate()
+	case secrets.ProviderTypeAWSSecretsManager:
+		err = secrets.NewAWSSecretsManagerProvider(args.Region, args.AccessKey, args.SecretKey).Validate()
+	}
+	if err != nil {
+		return e.ErrValidateSecretProvider.AddErr(err)
+	}
+	return nil
+}
+
+func checkSecretProvider(args *models.SecretProvider) error {
+	if args.Name == "" {
+		return fmt.Errorf("name must be provided")
+	}
+	// the name is a part of the secret reference
+	if strings.ContainsAny(args.Name, "/#") {
+		return fmt.Errorf("name can not contain / or #")
+	}
+	if _, err := commonservice.NewSecretProvider(args); err != nil {
+		return err
+	}
+	switch args.Type {
+	case secrets.ProviderTypeVault:
+		if args.Address == "" || args.Token == "" {
+			return fmt.Errorf("address and token must be provided for vault")
+		}
+	case secrets.ProviderTypeAWSSecretsManager:
+		if args.Region == "" {
+			return fmt.Errorf("region must be provided for aws secrets manager")
+		}
+	}
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 )
 
@@ -108,8 +109,17 @@ func GetSonarIntegration(id string, log *zap.SugaredLogger) (*SonarIntegration,
 	return resp, nil
 }
 
-func DeleteSonarIntegration(id string, log *zap.SugaredLogger) error {
-	err := commonrepo.NewSonarIntegrationColl().DeleteByID(context.TODO(), id)
+func DeleteSonarIntegration(id string, force bool, log *zap.SugaredLogger) error {
+	references, err := integration.GetSonarReferences(id)
+	if err != nil {
+		log.Errorf("failed to get references of sonar integration %s, error: %s", id, err)
+		return err
+	}
+	if err := references.CheckDeletion(force); err != nil {
+		return err
+	}
+
+	err = commonrepo.NewSonarIntegrationColl().DeleteByID(context.TODO(), id)
 	if err != nil {
 		log.Errorf("Failed to delete sonar integration of id: %s, the error is: %s", id, err)
 	}
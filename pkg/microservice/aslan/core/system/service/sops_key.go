@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/sops"
+)
+
+func ListSopsKey() ([]*models.SopsKey, error) {
+	resp, err := mongodb.NewSopsKeyColl().List(context.Background())
+	if err != nil {
+		return nil, e.ErrListSopsKey.AddErr(err)
+	}
+	return resp, nil
+}
+
+func CreateSopsKey(args *models.SopsKey) error {
+	if err := checkSopsKey(args); err != nil {
+		return e.ErrCreateSopsKey.AddErr(err)
+	}
+	if err := mongodb.NewSopsKeyColl().Create(context.Background(), args); err != nil {
+		return e.ErrCreateSopsKey.AddErr(err)
+	}
+	return nil
+}
+
+func UpdateSopsKey(id string, args *models.SopsKey) error {
+	if err := checkSopsKey(args); err != nil {
+		return e.ErrUpdateSopsKey.AddErr(err)
+	}
+	if err := mongodb.NewSopsKeyColl().Update(context.Background(), id, args); err != nil {
+		return e.ErrUpdateSopsKey.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteSopsKey(id string) error {
+	if err := mongodb.NewSopsKeyColl().DeleteByID(context.Background(), id); err != nil {
+		return e.ErrDeleteSopsKey.AddErr(err)
+	}
+	return nil
+}
+
+func checkSopsKey(args *models.SopsKey) error {
+	if args.Name == "" {
+		return fmt.Errorf("name must be provided")
+	}
+	switch args.Type {
+	case models.SopsKeyTypeAge:
+		return sops.CheckAgeIdentity(args.AgeKey)
+	case models.SopsKeyTypeAWSKMS:
+		if args.AccessKey != "" && args.SecretKey == "" {
+			return fmt.Errorf("secret key must be provided with the access key")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid sops key type: %s", args.Type)
+	}
+}
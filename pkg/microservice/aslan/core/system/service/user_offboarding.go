@@ -0,0 +1,381 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	approvalservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/approval"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	mongotool "github.com/koderover/zadig/v2/pkg/tool/mongo"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+type UserOffboardingReport struct {
+	UID      string                              `json:"uid"`
+	UserName string                              `json:"user_name"`
+	Items    []*commonmodels.UserOffboardingItem `json:"items"`
+}
+
+type TransferUserResourcesArgs struct {
+	TargetUID string `json:"target_uid"`
+}
+
+type ListUserOffboardingRecordsResp struct {
+	Records []*commonmodels.UserOffboardingRecord `json:"records"`
+	Total   int64                                 `json:"total"`
+}
+
+// GetUserOffboardingReport lists everything the user owns or solely administers, including the release plans managed
+// by the user and the approvals pending on the user.
+func GetUserOffboardingReport(uid string, log *zap.SugaredLogger) (*UserOffboardingReport, error) {
+	report := &UserOffboardingReport{
+		UID:   uid,
+		Items: make([]*commonmodels.UserOffboardingItem, 0),
+	}
+	// the user may be deleted already
+	if userInfo, err := user.New().GetUserByID(uid); err == nil && userInfo != nil {
+		report.UserName = userInfo.Name
+	}
+
+	services, err := commonrepo.NewServiceColl().ListByOwner(uid)
+	if err != nil {
+		log.Errorf("failed to list services owned by %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, svc := range services {
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{Type: config.OffboardingItemService, ProjectName: svc.ProductName, Name: svc.ServiceName})
+	}
+
+	productionServices, err := commonrepo.NewProductionServiceColl().ListByOwner(uid)
+	if err != nil {
+		log.Errorf("failed to list production services owned by %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, svc := range productionServices {
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{Type: config.OffboardingItemService, ProjectName: svc.ProductName, Name: svc.ServiceName, Production: true})
+	}
+
+	workflows, err := commonrepo.NewWorkflowV4Coll().ListByOwner(uid)
+	if err != nil {
+		log.Errorf("failed to list workflows owned by %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, workflow := range workflows {
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{Type: config.OffboardingItemWorkflow, ProjectName: workflow.Project, Name: workflow.Name})
+	}
+
+	envs, err := commonrepo.NewProductColl().ListByOwner(uid)
+	if err != nil {
+		log.Errorf("failed to list envs owned by %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, env := range envs {
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{Type: config.OffboardingItemEnv, ProjectName: env.ProductName, Name: env.EnvName, Production: env.Production})
+	}
+
+	plans, _, err := commonrepo.NewReleasePlanColl().ListByOptions(&commonrepo.ListReleasePlanOption{ManagerID: uid})
+	if err != nil {
+		log.Errorf("failed to list release plans managed by %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, plan := range plans {
+		if plan.Status == config.StatusSuccess || plan.Status == config.StatusCancel {
+			continue
+		}
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{Type: config.OffboardingItemReleasePlan, Name: plan.Name, ID: plan.ID.Hex()})
+	}
+
+	approvingPlans, err := listReleasePlansPendingOnUser(uid)
+	if err != nil {
+		log.Errorf("failed to list release plan approvals of %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, plan := range approvingPlans {
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{Type: config.OffboardingItemReleasePlanApproval, Name: plan.Name, ID: plan.ID.Hex()})
+	}
+
+	approvals, err := listWorkflowApprovalsPendingOnUser(uid)
+	if err != nil {
+		log.Errorf("failed to list workflow approvals of %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, approval := range approvals {
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{
+			Type:        config.OffboardingItemWorkflowApproval,
+			ProjectName: approval.task.ProjectName,
+			Name:        approval.task.WorkflowName,
+			ID:          strconv.FormatInt(approval.task.TaskID, 10),
+		})
+	}
+
+	projects, err := listProjectsSolelyAdministeredByUser(uid)
+	if err != nil {
+		log.Errorf("failed to list projects administered by %s, err: %s", uid, err)
+		return nil, e.ErrGetOffboardingReport.AddErr(err)
+	}
+	for _, project := range projects {
+		report.Items = append(report.Items, &commonmodels.UserOffboardingItem{Type: config.OffboardingItemProjectAdmin, ProjectName: project, Name: project})
+	}
+
+	return report, nil
+}
+
+// TransferUserResources transfers everything in the offboarding report of the user to the target user.
+// The owners, release plan managers and release plan approvals are updated in one transaction, the pending workflow
+// approvals kept in the cache and the project admin role bindings are updated after the transaction is committed.
+func TransferUserResources(uid string, args *TransferUserResourcesArgs, operator string, log *zap.SugaredLogger) (*commonmodels.UserOffboardingRecord, error) {
+	if args.TargetUID == "" || args.TargetUID == uid {
+		return nil, e.ErrInvalidParam.AddDesc("target user must be another user")
+	}
+	target, err := user.New().GetUserByID(args.TargetUID)
+	if err != nil || target == nil {
+		return nil, e.ErrInvalidParam.AddDesc(fmt.Sprintf("target user %s not found", args.TargetUID))
+	}
+
+	report, err := GetUserOffboardingReport(uid, log)
+	if err != nil {
+		return nil, err
+	}
+	approvingPlans, err := listReleasePlansPendingOnUser(uid)
+	if err != nil {
+		return nil, e.ErrTransferUserResources.AddErr(err)
+	}
+	approvals, err := listWorkflowApprovalsPendingOnUser(uid)
+	if err != nil {
+		return nil, e.ErrTransferUserResources.AddErr(err)
+	}
+
+	record := &commonmodels.UserOffboardingRecord{
+		FromUID:      uid,
+		FromUserName: report.UserName,
+		ToUID:        target.Uid,
+		ToUserName:   target.Name,
+		Operator:     operator,
+		Items:        report.Items,
+		CreateTime:   time.Now().Unix(),
+	}
+	owner := &commonmodels.ResourceOwner{
+		Type: config.OwnerTypeUser,
+		ID:   target.Uid,
+		Name: target.Name,
+	}
+
+	session := mongotool.Session()
+	defer session.EndSession(context.TODO())
+
+	if err := mongotool.StartTransaction(session); err != nil {
+		return nil, e.ErrTransferUserResources.AddErr(err)
+	}
+	ctx := mongotool.SessionContext(context.TODO(), session)
+
+	err = func() error {
+		if err := commonrepo.NewServiceColl().TransferOwner(ctx, uid, owner); err != nil {
+			return fmt.Errorf("failed to transfer services, err: %s", err)
+		}
+		if err := commonrepo.NewProductionServiceColl().TransferOwner(ctx, uid, owner); err != nil {
+			return fmt.Errorf("failed to transfer production services, err: %s", err)
+		}
+		if err := commonrepo.NewWorkflowV4Coll().TransferOwner(ctx, uid, owner); err != nil {
+			return fmt.Errorf("failed to transfer workflows, err: %s", err)
+		}
+		if err := commonrepo.NewProductColl().TransferOwner(ctx, uid, owner); err != nil {
+			return fmt.Errorf("failed to transfer envs, err: %s", err)
+		}
+		if err := commonrepo.NewReleasePlanColl().TransferManager(ctx, uid, target.Uid, target.Name); err != nil {
+			return fmt.Errorf("failed to transfer release plans, err: %s", err)
+		}
+		for _, plan := range approvingPlans {
+			transferApproveUser(plan.Approval.NativeApproval, uid, target)
+			if err := commonrepo.NewReleasePlanColl().UpdateByID(ctx, plan.ID.Hex(), plan); err != nil {
+				return fmt.Errorf("failed to transfer approval of release plan %s, err: %s", plan.Name, err)
+			}
+		}
+		if err := commonrepo.NewUserOffboardingRecordColl().Create(ctx, record); err != nil {
+			return fmt.Errorf("failed to create offboarding record, err: %s", err)
+		}
+		return nil
+	}()
+	if err != nil {
+		log.Errorf("failed to transfer the resources of %s to %s, %s", uid, target.Uid, err)
+		mongotool.AbortTransaction(session)
+		return nil, e.ErrTransferUserResources.AddErr(err)
+	}
+	if err := mongotool.CommitTransaction(session); err != nil {
+		return nil, e.ErrTransferUserResources.AddErr(err)
+	}
+
+	for _, plan := range approvingPlans {
+		key := plan.Approval.NativeApproval.InstanceCode
+		if cached, ok := approvalservice.GlobalApproveMap.GetApproval(key); ok {
+			transferApproveUser(cached, uid, target)
+			approvalservice.GlobalApproveMap.SetApproval(key, cached)
+		}
+	}
+	for _, approval := range approvals {
+		transferApproveUser(approval.approval, uid, target)
+		approvalservice.GlobalApproveMap.SetApproval(approval.key, approval.approval)
+	}
+	for _, item := range report.Items {
+		if item.Type != config.OffboardingItemProjectAdmin {
+			continue
+		}
+		if err := user.New().CreateUserRoleBinding(target.Uid, item.ProjectName, string(setting.ProjectAdmin)); err != nil {
+			log.Warnf("failed to grant project admin of %s to %s, err: %s", item.ProjectName, target.Uid, err)
+		}
+	}
+
+	return record, nil
+}
+
+func ListUserOffboardingRecords(uid string, pageNum, pageSize int64, log *zap.SugaredLogger) (*ListUserOffboardingRecordsResp, error) {
+	records, total, err := commonrepo.NewUserOffboardingRecordColl().List(uid, pageNum, pageSize)
+	if err != nil {
+		log.Errorf("failed to list user offboarding records, err: %s", err)
+		return nil, e.ErrListUserOffboardingRecords.AddErr(err)
+	}
+	return &ListUserOffboardingRecordsResp{Records: records, Total: total}, nil
+}
+
+func listReleasePlansPendingOnUser(uid string) ([]*commonmodels.ReleasePlan, error) {
+	plans, _, err := commonrepo.NewReleasePlanColl().ListByOptions(&commonrepo.ListReleasePlanOption{Status: config.StatusWaitForApprove})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*commonmodels.ReleasePlan, 0)
+	for _, plan := range plans {
+		if plan.Approval == nil || plan.Approval.Type != config.NativeApproval || plan.Approval.NativeApproval == nil {
+			continue
+		}
+		if hasPendingApproveUser(plan.Approval.NativeApproval, uid) {
+			resp = append(resp, plan)
+		}
+	}
+	return resp, nil
+}
+
+type pendingWorkflowApproval struct {
+	task     *commonmodels.WorkflowTask
+	key      string
+	approval *commonmodels.NativeApproval
+}
+
+func listWorkflowApprovalsPendingOnUser(uid string) ([]*pendingWorkflowApproval, error) {
+	tasks, err := commonrepo.NewworkflowTaskv4Coll().InCompletedTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*pendingWorkflowApproval, 0)
+	for _, task := range tasks {
+		for _, stage := range task.Stages {
+			for _, job := range stage.Jobs {
+				if job.JobType != string(config.JobApproval) || job.Status != config.StatusWaitingApprove {
+					continue
+				}
+				// the approval in the cache is the one being processed
+				key := fmt.Sprintf("%s-%s-%d", task.WorkflowName, job.Name, task.TaskID)
+				approval, ok := approvalservice.GlobalApproveMap.GetApproval(key)
+				if !ok || !hasPendingApproveUser(approval, uid) {
+					continue
+				}
+				resp = append(resp, &pendingWorkflowApproval{task: task, key: key, approval: approval})
+			}
+		}
+	}
+	return resp, nil
+}
+
+func listProjectsSolelyAdministeredByUser(uid string) ([]string, error) {
+	projects, err := templaterepo.NewProductColl().List()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]string, 0)
+	for _, project := range projects {
+		bindings, err := user.New().ListRoleBindings(project.ProductName)
+		if err != nil {
+			return nil, err
+		}
+		admins := sets.NewString()
+		for _, binding := range bindings {
+			if !sets.NewString(binding.Roles...).Has(string(setting.ProjectAdmin)) {
+				continue
+			}
+			switch {
+			case binding.UserInfo != nil:
+				admins.Insert(binding.UserInfo.UID)
+			case binding.GroupInfo != nil:
+				admins.Insert("group:" + binding.GroupInfo.GID)
+			}
+		}
+		if admins.Len() == 1 && admins.Has(uid) {
+			resp = append(resp, project.ProductName)
+		}
+	}
+	return resp, nil
+}
+
+func hasPendingApproveUser(approval *commonmodels.NativeApproval, uid string) bool {
+	for _, approveUser := range approval.ApproveUsers {
+		if approveUser.UserID == uid && approveUser.RejectOrApprove == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// transferApproveUser replaces the pending approver with the target user, the approver is removed instead if the
+// target user is an approver already.
+func transferApproveUser(approval *commonmodels.NativeApproval, uid string, target *types.UserInfo) {
+	targetIncluded := false
+	for _, approveUser := range approval.ApproveUsers {
+		if approveUser.UserID == target.Uid {
+			targetIncluded = true
+		}
+	}
+
+	approveUsers := make([]*commonmodels.User, 0, len(approval.ApproveUsers))
+	for _, approveUser := range approval.ApproveUsers {
+		if approveUser.UserID == uid && approveUser.RejectOrApprove == "" {
+			if targetIncluded {
+				continue
+			}
+			approveUser.UserID = target.Uid
+			approveUser.UserName = target.Name
+		}
+		approveUsers = append(approveUsers, approveUser)
+	}
+	approval.ApproveUsers = approveUsers
+	if approval.NeededApprovers > len(approveUsers) {
+		approval.NeededApprovers = len(approveUsers)
+	}
+}
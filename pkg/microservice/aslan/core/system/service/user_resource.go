@@ -0,0 +1,299 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const (
+	// the number of recent items kept for each user
+	recentItemRetention = 50
+	defaultRecentLimit  = 20
+)
+
+// UserResourceItem is a favorite or recently visited resource with its latest status
+type UserResourceItem struct {
+	Type        string `json:"type"`
+	ProjectName string `json:"project_name"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	// Deleted is true if the resource no longer exists
+	Deleted    bool  `json:"deleted"`
+	UpdateTime int64 `json:"update_time"`
+	VisitTime  int64 `json:"visit_time,omitempty"`
+
+	// workflow status
+	LastTaskID     int64  `json:"last_task_id,omitempty"`
+	LastTaskStatus string `json:"last_task_status,omitempty"`
+	LastTaskTime   int64  `json:"last_task_time,omitempty"`
+	// env status
+	EnvStatus  string `json:"env_status,omitempty"`
+	Production bool   `json:"production,omitempty"`
+	// service status
+	Revision int64    `json:"revision,omitempty"`
+	Envs     []string `json:"envs,omitempty"`
+}
+
+func IsValidUserResourceType(resourceType string) bool {
+	switch resourceType {
+	case commonservice.FavoriteTypeEnv, commonservice.FavoriteTypeService, commonservice.FavoriteTypeWorkflow:
+		return true
+	}
+	return false
+}
+
+// ListFavoriteResources lists the favorite workflows, environments and services of the user with their status.
+// authorizedProjects limits the projects of the result, nil means no restriction.
+func ListFavoriteResources(userID, resourceType string, authorizedProjects sets.String, log *zap.SugaredLogger) ([]*UserResourceItem, error) {
+	favorites, err := commonrepo.NewFavoriteColl().List(&commonrepo.FavoriteArgs{UserID: userID, Type: resourceType})
+	if err != nil {
+		log.Errorf("failed to list favorites of user %s, error: %v", userID, err)
+		return nil, e.ErrListFavorites.AddErr(err)
+	}
+
+	items := make([]*UserResourceItem, 0, len(favorites))
+	for _, favorite := range favorites {
+		if !IsValidUserResourceType(favorite.Type) || !projectAuthorized(authorizedProjects, favorite.ProductName) {
+			continue
+		}
+		items = append(items, &UserResourceItem{
+			Type:        favorite.Type,
+			ProjectName: favorite.ProductName,
+			Name:        favorite.Name,
+		})
+	}
+
+	if err := fillUserResourceStatus(items); err != nil {
+		log.Errorf("failed to get the status of favorites, error: %v", err)
+		return nil, e.ErrListFavorites.AddErr(err)
+	}
+	return items, nil
+}
+
+func RecordRecentResource(userID, projectName, name, resourceType string) error {
+	coll := commonrepo.NewRecentItemColl()
+	err := coll.Upsert(&commonmodels.RecentItem{
+		UserID:      userID,
+		ProductName: projectName,
+		Name:        name,
+		Type:        resourceType,
+	})
+	if err != nil {
+		return e.ErrRecordRecentItem.AddErr(err)
+	}
+
+	if err := coll.DeleteOutdated(userID, recentItemRetention); err != nil {
+		return e.ErrRecordRecentItem.AddErr(err)
+	}
+	return nil
+}
+
+// ListRecentResources lists the resources recently visited by the user with their status
+func ListRecentResources(userID, resourceType string, limit int64, authorizedProjects sets.String, log *zap.SugaredLogger) ([]*UserResourceItem, error) {
+	if limit <= 0 {
+		limit = defaultRecentLimit
+	}
+
+	recents, err := commonrepo.NewRecentItemColl().List(&commonrepo.FavoriteArgs{UserID: userID, Type: resourceType}, limit)
+	if err != nil {
+		log.Errorf("failed to list recent items of user %s, error: %v", userID, err)
+		return nil, e.ErrListRecentItems.AddErr(err)
+	}
+
+	items := make([]*UserResourceItem, 0, len(recents))
+	for _, recent := range recents {
+		if !projectAuthorized(authorizedProjects, recent.ProductName) {
+			continue
+		}
+		items = append(items, &UserResourceItem{
+			Type:        recent.Type,
+			ProjectName: recent.ProductName,
+			Name:        recent.Name,
+			VisitTime:   recent.VisitTime,
+		})
+	}
+
+	if err := fillUserResourceStatus(items); err != nil {
+		log.Errorf("failed to get the status of recent items, error: %v", err)
+		return nil, e.ErrListRecentItems.AddErr(err)
+	}
+	return items, nil
+}
+
+func ClearRecentResources(userID string) error {
+	if err := commonrepo.NewRecentItemColl().DeleteByUser(userID); err != nil {
+		return e.ErrRecordRecentItem.AddErr(err)
+	}
+	return nil
+}
+
+func projectAuthorized(authorizedProjects sets.String, projectName string) bool {
+	return authorizedProjects == nil || authorizedProjects.Has(projectName)
+}
+
+func resourceKey(projectName, name string) string {
+	return fmt.Sprintf("%s/%s", projectName, name)
+}
+
+// fillUserResourceStatus fills the status of the items with one query per resource type,
+// except the latest task of the workflows which is indexed by the workflow name.
+func fillUserResourceStatus(items []*UserResourceItem) error {
+	workflowItems, envItems, serviceItems := make([]*UserResourceItem, 0), make([]*UserResourceItem, 0), make([]*UserResourceItem, 0)
+	for _, item := range items {
+		switch item.Type {
+		case commonservice.FavoriteTypeWorkflow:
+			workflowItems = append(workflowItems, item)
+		case commonservice.FavoriteTypeEnv:
+			envItems = append(envItems, item)
+		case commonservice.FavoriteTypeService:
+			serviceItems = append(serviceItems, item)
+		}
+	}
+
+	if err := fillWorkflowStatus(workflowItems); err != nil {
+		return err
+	}
+
+	// the envs of the projects are used by both the env items and the service items
+	projects := sets.NewString()
+	for _, item := range append(envItems, serviceItems...) {
+		projects.Insert(item.ProjectName)
+	}
+	envs := make([]*commonmodels.Product, 0)
+	if projects.Len() > 0 {
+		var err error
+		envs, err = commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{InProjects: projects.List()})
+		if err != nil {
+			return fmt.Errorf("failed to list envs: %v", err)
+		}
+	}
+
+	fillEnvStatus(envItems, envs)
+	return fillServiceStatus(serviceItems, envs)
+}
+
+func fillWorkflowStatus(items []*UserResourceItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	names := sets.NewString()
+	for _, item := range items {
+		names.Insert(item.Name)
+	}
+	workflows, _, err := commonrepo.NewWorkflowV4Coll().List(&commonrepo.ListWorkflowV4Option{Names: names.List()}, 0, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list workflows: %v", err)
+	}
+	workflowMap := make(map[string]*commonmodels.WorkflowV4)
+	for _, workflow := range workflows {
+		workflowMap[resourceKey(workflow.Project, workflow.Name)] = workflow
+	}
+
+	for _, item := range items {
+		workflow, ok := workflowMap[resourceKey(item.ProjectName, item.Name)]
+		if !ok {
+			item.Deleted = true
+			continue
+		}
+		item.DisplayName = workflow.DisplayName
+		item.UpdateTime = workflow.UpdateTime
+
+		task, err := commonrepo.NewworkflowTaskv4Coll().GetLatest(workflow.Name)
+		if err != nil {
+			// the workflow has never been run
+			continue
+		}
+		item.LastTaskID = task.TaskID
+		item.LastTaskStatus = string(task.Status)
+		item.LastTaskTime = task.CreateTime
+	}
+	return nil
+}
+
+func fillEnvStatus(items []*UserResourceItem, envs []*commonmodels.Product) {
+	envMap := make(map[string]*commonmodels.Product)
+	for _, env := range envs {
+		envMap[resourceKey(env.ProductName, env.EnvName)] = env
+	}
+
+	for _, item := range items {
+		env, ok := envMap[resourceKey(item.ProjectName, item.Name)]
+		if !ok {
+			item.Deleted = true
+			continue
+		}
+		item.DisplayName = env.Alias
+		item.UpdateTime = env.UpdateTime
+		item.EnvStatus = env.Status
+		item.Production = env.Production
+	}
+}
+
+func fillServiceStatus(items []*UserResourceItem, envs []*commonmodels.Product) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	serviceInfos := make([]*templatemodels.ServiceInfo, 0, len(items))
+	for _, item := range items {
+		serviceInfos = append(serviceInfos, &templatemodels.ServiceInfo{Name: item.Name, Owner: item.ProjectName})
+	}
+	services, err := commonrepo.NewServiceColl().ListMaxRevisions(&commonrepo.ServiceListOption{InServices: serviceInfos})
+	if err != nil {
+		return fmt.Errorf("failed to list services: %v", err)
+	}
+	serviceMap := make(map[string]*commonmodels.Service)
+	for _, service := range services {
+		serviceMap[resourceKey(service.ProductName, service.ServiceName)] = service
+	}
+
+	// the non-production envs the services are deployed in
+	serviceEnvs := make(map[string][]string)
+	for _, env := range envs {
+		if env.Production {
+			continue
+		}
+		for _, svc := range env.GetServiceMap() {
+			key := resourceKey(env.ProductName, svc.ServiceName)
+			serviceEnvs[key] = append(serviceEnvs[key], env.EnvName)
+		}
+	}
+
+	for _, item := range items {
+		key := resourceKey(item.ProjectName, item.Name)
+		service, ok := serviceMap[key]
+		if !ok {
+			item.Deleted = true
+			continue
+		}
+		item.Revision = service.Revision
+		item.UpdateTime = service.CreateTime
+		item.Envs = serviceEnvs[key]
+	}
+	return nil
+}
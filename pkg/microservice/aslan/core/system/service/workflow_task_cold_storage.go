@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/cache"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+const (
+	coldStorageBatchSize = 100
+	// the cold storage is triggered by the daily cron of every aslan replica and by the strategy updates
+	coldStorageLockKey    = "workflow_task_cold_storage"
+	coldStorageLockExpiry = time.Hour
+	// rehydrated tasks are kept in the primary collection for a while before they are moved into the cold storage again
+	coldStorageRehydrateKeepDays = 7
+)
+
+// HandleWorkflowTaskColdStorage moves the finished workflow tasks older than the WorkflowTaskColdStorage strategy
+// into the cold storage, the tasks are compressed and removed from the primary collection to keep it small.
+// The readers of the primary collection see the cold tasks as follows:
+//   - the deploy ledger and the project resource usage rollup read the cold tasks in their time ranges as well
+//   - the job outputs and the image traceability are read from the job outputs saved when the tasks finished
+//   - the build stats are calculated daily from the tasks of the previous day, which are never cold
+//   - the task details, logs and reruns need the task to be rehydrated first
+func HandleWorkflowTaskColdStorage() {
+	lock := cache.NewRedisLockWithExpiry(coldStorageLockKey, coldStorageLockExpiry)
+	if err := lock.TryLock(); err != nil {
+		log.Infof("workflow task cold storage is running, skip")
+		return
+	}
+	defer lock.Unlock()
+
+	strategy, err := GetCapacityStrategy(commonmodels.WorkflowTaskColdStorage)
+	if err != nil {
+		log.Errorf("failed to get workflow task cold storage strategy, error: %s", err)
+		return
+	}
+	if strategy.Retention == nil || strategy.Retention.MaxDays <= 0 {
+		return
+	}
+
+	now := time.Now()
+	before := now.AddDate(0, 0, -strategy.Retention.MaxDays).Unix()
+	rehydrateAfter := now.AddDate(0, 0, -coldStorageRehydrateKeepDays).Unix()
+
+	taskColl := commonrepo.NewworkflowTaskv4Coll()
+	coldColl := commonrepo.NewWorkflowTaskColdColl()
+	moved := 0
+	for {
+		docs, err := taskColl.ListRawFinishedBefore(before, rehydrateAfter, coldStorageBatchSize)
+		if err != nil {
+			log.Errorf("failed to list workflow tasks for cold storage, error: %s", err)
+			break
+		}
+
+		for _, doc := range docs {
+			cold, err := buildColdWorkflowTask(doc, now.Unix())
+			if err != nil {
+				log.Errorf("failed to compress workflow task, error: %s", err)
+				return
+			}
+			// the task is only removed after it is saved in the cold storage
+			if err := coldColl.Upsert(cold); err != nil {
+				log.Errorf("failed to save workflow task %s-%d into cold storage, error: %s", cold.WorkflowName, cold.TaskID, err)
+				return
+			}
+			if err := taskColl.DeleteByObjectID(cold.ID); err != nil {
+				log.Errorf("failed to delete workflow task %s-%d moved into cold storage, error: %s", cold.WorkflowName, cold.TaskID, err)
+				return
+			}
+			moved++
+		}
+
+		if len(docs) < coldStorageBatchSize {
+			break
+		}
+	}
+
+	if moved > 0 {
+		log.Infof("%d workflow tasks created before %s are moved into cold storage", moved, time.Unix(before, 0).Format(time.RFC3339))
+	}
+}
+
+func buildColdWorkflowTask(doc bson.Raw, archiveTime int64) (*commonmodels.WorkflowTaskCold, error) {
+	meta := &struct {
+		ID           primitive.ObjectID `bson:"_id"`
+		WorkflowName string             `bson:"workflow_name"`
+		TaskID       int64              `bson:"task_id"`
+		ProjectName  string             `bson:"project_name"`
+		Status       config.Status      `bson:"status"`
+		TaskCreator  string             `bson:"task_creator"`
+		CreateTime   int64              `bson:"create_time"`
+	}{}
+	if err := bson.Unmarshal(doc, meta); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	if _, err := writer.Write(doc); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return &commonmodels.WorkflowTaskCold{
+		ID:           meta.ID,
+		WorkflowName: meta.WorkflowName,
+		TaskID:       meta.TaskID,
+		ProjectName:  meta.ProjectName,
+		Status:       meta.Status,
+		TaskCreator:  meta.TaskCreator,
+		CreateTime:   meta.CreateTime,
+		ArchiveTime:  archiveTime,
+		RawSize:      int64(len(doc)),
+		Data:         buf.Bytes(),
+	}, nil
+}
+
+type ListColdWorkflowTasksArgs struct {
+	WorkflowName string `form:"workflowName"`
+	ProjectName  string `form:"projectName"`
+	PageNum      int64  `form:"pageNum"`
+	PageSize     int64  `form:"pageSize"`
+}
+
+type ListColdWorkflowTasksResp struct {
+	Tasks []*commonmodels.WorkflowTaskCold `json:"tasks"`
+	Total int64                            `json:"total"`
+}
+
+func ListColdWorkflowTasks(args *ListColdWorkflowTasksArgs, logger *zap.SugaredLogger) (*ListColdWorkflowTasksResp, error) {
+	if args.PageNum <= 0 {
+		args.PageNum = 1
+	}
+	if args.PageSize <= 0 {
+		args.PageSize = 20
+	}
+
+	tasks, total, err := commonrepo.NewWorkflowTaskColdColl().List(&commonrepo.WorkflowTaskColdListOption{
+		WorkflowName: args.WorkflowName,
+		ProjectName:  args.ProjectName,
+		PageNum:      args.PageNum,
+		PageSize:     args.PageSize,
+	})
+	if err != nil {
+		logger.Errorf("failed to list cold workflow tasks, error: %s", err)
+		return nil, e.ErrListColdWorkflowTask.AddErr(err)
+	}
+	return &ListColdWorkflowTasksResp{
+		Tasks: tasks,
+		Total: total,
+	}, nil
+}
+
+// RehydrateWorkflowTask moves the task back from the cold storage into the primary collection
+func RehydrateWorkflowTask(workflowName string, taskID int64, logger *zap.SugaredLogger) error {
+	coldColl := commonrepo.NewWorkflowTaskColdColl()
+	cold, err := coldColl.Find(workflowName, taskID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return e.ErrRehydrateWorkflowTask.AddDesc(fmt.Sprintf("task %s-%d is not in the cold storage", workflowName, taskID))
+		}
+		return e.ErrRehydrateWorkflowTask.AddErr(err)
+	}
+
+	doc, err := cold.Document()
+	if err != nil {
+		return e.ErrRehydrateWorkflowTask.AddErr(fmt.Errorf("failed to decompress task %s-%d, error: %s", workflowName, taskID, err))
+	}
+
+	// the task might be rehydrated before but failed to be removed from the cold storage
+	if err := commonrepo.NewworkflowTaskv4Coll().InsertRaw(doc); err != nil && !mongo.IsDuplicateKeyError(err) {
+		logger.Errorf("failed to rehydrate workflow task %s-%d, error: %s", workflowName, taskID, err)
+		return e.ErrRehydrateWorkflowTask.AddErr(err)
+	}
+	if err := coldColl.Delete(cold.ID); err != nil {
+		logger.Errorf("failed to delete rehydrated workflow task %s-%d from cold storage, error: %s", workflowName, taskID, err)
+		return e.ErrRehydrateWorkflowTask.AddErr(err)
+	}
+	return nil
+}
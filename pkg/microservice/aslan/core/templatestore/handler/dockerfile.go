@@ -26,6 +26,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/template"
 	templateservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/templatestore/service"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 )
 
 func CreateDockerfileTemplate(c *gin.Context) {
@@ -64,7 +65,7 @@ func CreateDockerfileTemplate(c *gin.Context) {
 		}
 	}
 
-	ctx.Err = templateservice.CreateDockerfileTemplate(req, ctx.Logger)
+	ctx.Err = templateservice.CreateDockerfileTemplate(req, ctx.UserName, ctx.Logger)
 }
 
 func UpdateDockerfileTemplate(c *gin.Context) {
@@ -103,7 +104,7 @@ func UpdateDockerfileTemplate(c *gin.Context) {
 		}
 	}
 
-	ctx.Err = templateservice.UpdateDockerfileTemplate(c.Param("id"), req, ctx.Logger)
+	ctx.Err = templateservice.UpdateDockerfileTemplate(c.Param("id"), req, ctx.UserName, ctx.Logger)
 }
 
 type listDockerfileQuery struct {
@@ -229,3 +230,80 @@ func ValidateDockerfileTemplate(c *gin.Context) {
 		ctx.Resp = &validateDockerfileTemplateResp{Error: err.Error()}
 	}
 }
+
+func ListDockerfileTemplateVersions(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.Template.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = templateservice.ListDockerfileTemplateVersions(c.Param("id"), ctx.Logger)
+}
+
+type diffDockerfileTemplateVersionsQuery struct {
+	VersionA int64 `form:"versionA" binding:"required"`
+	VersionB int64 `form:"versionB" binding:"required"`
+}
+
+func DiffDockerfileTemplateVersions(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.Template.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	args := &diffDockerfileTemplateVersionsQuery{}
+	if err := c.ShouldBindQuery(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = templateservice.DiffDockerfileTemplateVersions(c.Param("id"), args.VersionA, args.VersionB, ctx.Logger)
+}
+
+func GetDockerfileTemplateUsage(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = templateservice.GetDockerfileTemplateUsage(c.Param("id"), ctx.Logger)
+}
+
+type lintDockerfileTemplateReq struct {
+	Content string `json:"content"`
+}
+
+func LintDockerfileTemplate(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	req := &lintDockerfileTemplateReq{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = templateservice.LintDockerfileTemplate(req.Content, ctx.Logger)
+}
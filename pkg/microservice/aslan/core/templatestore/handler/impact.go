@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	templateservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/templatestore/service"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// @Summary Get Yaml Template Impact
+// @Description List the services, environments and workflows affected by the change of the yaml template
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string								true	"template id"
+// @Success 200 	{object} 	templateservice.TemplateImpact
+// @Router /api/aslan/template/yaml/{id}/impact [get]
+func GetYamlTemplateImpact(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = templateservice.GetYamlTemplateImpact(c.Param("id"), ctx.Logger)
+}
+
+// @Summary Notify Yaml Template Impact
+// @Description Send the impact of the yaml template change to the given IM groups
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string										true	"template id"
+// @Param 	body 	body 		templateservice.NotifyTemplateImpactArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/template/yaml/{id}/impact/notify [post]
+func NotifyYamlTemplateImpact(c *gin.Context) {
+	notifyTemplateImpact(c, templateservice.TemplateTypeYaml, "模板-YAML", "id")
+}
+
+// @Summary Get Chart Template Impact
+// @Description List the services, environments and workflows affected by the change of the chart template
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string								true	"template name"
+// @Success 200 	{object} 	templateservice.TemplateImpact
+// @Router /api/aslan/template/charts/{name}/impact [get]
+func GetChartTemplateImpact(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = templateservice.GetChartTemplateImpact(c.Param("name"), ctx.Logger)
+}
+
+// @Summary Notify Chart Template Impact
+// @Description Send the impact of the chart template change to the given IM groups
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string										true	"template name"
+// @Param 	body 	body 		templateservice.NotifyTemplateImpactArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/template/charts/{name}/impact/notify [post]
+func NotifyChartTemplateImpact(c *gin.Context) {
+	notifyTemplateImpact(c, templateservice.TemplateTypeChart, "模板-Helm Chart", "name")
+}
+
+// @Summary Get Build Template Impact
+// @Description List the builds and workflows affected by the change of the build template
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string								true	"template id"
+// @Success 200 	{object} 	templateservice.TemplateImpact
+// @Router /api/aslan/template/build/{id}/impact [get]
+func GetBuildTemplateImpact(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = templateservice.GetBuildTemplateImpact(c.Param("id"), ctx.Logger)
+}
+
+// @Summary Notify Build Template Impact
+// @Description Send the impact of the build template change to the given IM groups
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string										true	"template id"
+// @Param 	body 	body 		templateservice.NotifyTemplateImpactArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/template/build/{id}/impact/notify [post]
+func NotifyBuildTemplateImpact(c *gin.Context) {
+	notifyTemplateImpact(c, templateservice.TemplateTypeBuild, "模板-构建", "id")
+}
+
+// @Summary Get Dockerfile Template Impact
+// @Description List the builds and workflows affected by the change of the dockerfile template
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string								true	"template id"
+// @Success 200 	{object} 	templateservice.TemplateImpact
+// @Router /api/aslan/template/dockerfile/{id}/impact [get]
+func GetDockerfileTemplateImpact(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	ctx.Resp, ctx.Err = templateservice.GetDockerfileTemplateImpact(c.Param("id"), ctx.Logger)
+}
+
+// @Summary Notify Dockerfile Template Impact
+// @Description Send the impact of the dockerfile template change to the given IM groups
+// @Tags 	template
+// @Accept 	json
+// @Produce json
+// @Param 	id		path		string										true	"template id"
+// @Param 	body 	body 		templateservice.NotifyTemplateImpactArgs 	true 	"body"
+// @Success 200
+// @Router /api/aslan/template/dockerfile/{id}/impact/notify [post]
+func NotifyDockerfileTemplateImpact(c *gin.Context) {
+	notifyTemplateImpact(c, templateservice.TemplateTypeDockerfile, "模板-Dockerfile", "id")
+}
+
+func notifyTemplateImpact(c *gin.Context, templateType templateservice.TemplateType, function, param string) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if !ctx.Resources.SystemActions.Template.Edit {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	args := new(templateservice.NotifyTemplateImpactArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+	if len(args.NotifyCtls) == 0 {
+		ctx.Err = e.ErrInvalidParam.AddDesc("notify_ctls can't be empty")
+		return
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, "", "通知变更影响", function, c.Param(param), "", ctx.Logger)
+
+	ctx.Err = templateservice.NotifyTemplateImpact(templateType, c.Param(param), args, ctx.Logger)
+}
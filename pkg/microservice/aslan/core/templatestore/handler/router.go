@@ -36,6 +36,8 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		chart.PUT("/:name", UpdateChartTemplate)
 		chart.GET("/:name/reference", GetChartTemplateReference)
 		chart.POST("/:name/reference", SyncChartTemplateReference)
+		chart.GET("/:name/impact", GetChartTemplateImpact)
+		chart.POST("/:name/impact/notify", NotifyChartTemplateImpact)
 		chart.PUT("/:name/variables", UpdateChartTemplateVariables)
 		chart.DELETE("/:name", RemoveChartTemplate)
 	}
@@ -48,7 +50,13 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		dockerfile.GET("/:id", GetDockerfileTemplateDetail)
 		dockerfile.DELETE("/:id", DeleteDockerfileTemplate)
 		dockerfile.GET("/:id/reference", GetDockerfileTemplateReference)
+		dockerfile.GET("/:id/impact", GetDockerfileTemplateImpact)
+		dockerfile.POST("/:id/impact/notify", NotifyDockerfileTemplateImpact)
 		dockerfile.POST("/validation", ValidateDockerfileTemplate)
+		dockerfile.POST("/lint", LintDockerfileTemplate)
+		dockerfile.GET("/:id/versions", ListDockerfileTemplateVersions)
+		dockerfile.GET("/:id/versions/diff", DiffDockerfileTemplateVersions)
+		dockerfile.GET("/:id/usage", GetDockerfileTemplateUsage)
 	}
 
 	yaml := router.Group("yaml")
@@ -61,6 +69,8 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		yaml.DELETE("/:id", DeleteYamlTemplate)
 		yaml.GET("/:id/reference", GetYamlTemplateReference)
 		yaml.POST("/:id/reference", SyncYamlTemplateReference)
+		yaml.GET("/:id/impact", GetYamlTemplateImpact)
+		yaml.POST("/:id/impact/notify", NotifyYamlTemplateImpact)
 		yaml.POST("/validateVariable", ValidateTemplateVariables)
 		yaml.POST("/extractVariable", ExtractTemplateVariables)
 		yaml.POST("/flatkvs", GetFlatKvs)
@@ -74,6 +84,8 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		build.GET("/:id", GetBuildTemplate)
 		build.DELETE("/:id", RemoveBuildTemplate)
 		build.GET("/:id/reference", GetBuildTemplateReference)
+		build.GET("/:id/impact", GetBuildTemplateImpact)
+		build.POST("/:id/impact/notify", NotifyBuildTemplateImpact)
 	}
 
 	workflow := router.Group("workflow")
@@ -23,6 +23,7 @@ import (
 
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/buildtemplate"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/template"
 	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
@@ -46,6 +47,9 @@ func AddBuildTemplate(userName string, build *commonmodels.BuildTemplate, logger
 	if err := commonutil.CheckDefineResourceParam(build.PreBuild.ResReq, build.PreBuild.ResReqSpec); err != nil {
 		return e.ErrCreateBuildModule.AddDesc(err.Error())
 	}
+	if err := buildtemplate.ValidateParameters(build.Parameters, build.PreBuild.Envs); err != nil {
+		return e.ErrCreateBuildModule.AddDesc(err.Error())
+	}
 	build.UpdateBy = userName
 	if err := commonrepo.NewBuildTemplateColl().Create(build); err != nil {
 		log.Errorf("[Build.Upsert] %s error: %s", build.Name, err)
@@ -114,6 +118,9 @@ func UpdateBuildTemplate(id string, buildTemplate *commonmodels.BuildTemplate, l
 	if err := commonutil.CheckDefineResourceParam(buildTemplate.PreBuild.ResReq, buildTemplate.PreBuild.ResReqSpec); err != nil {
 		return e.ErrCreateBuildModule.AddDesc(err.Error())
 	}
+	if err := buildtemplate.ValidateParameters(buildTemplate.Parameters, buildTemplate.PreBuild.Envs); err != nil {
+		return e.ErrUpdateBuildModule.AddDesc(err.Error())
+	}
 	return commonrepo.NewBuildTemplateColl().Update(id, buildTemplate)
 }
 
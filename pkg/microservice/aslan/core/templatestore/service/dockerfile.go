@@ -18,40 +18,100 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	dockerfileinstructions "github.com/moby/buildkit/frontend/dockerfile/instructions"
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/pmezard/go-difflib/difflib"
 	"go.uber.org/zap"
 
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/template"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/hadolint"
 )
 
-func CreateDockerfileTemplate(template *template.DockerfileTemplate, logger *zap.SugaredLogger) error {
-	err := commonrepo.NewDockerfileTemplateColl().Create(&commonmodels.DockerfileTemplate{
-		Name:    template.Name,
-		Content: template.Content,
-	})
+func CreateDockerfileTemplate(tmpl *template.DockerfileTemplate, username string, logger *zap.SugaredLogger) error {
+	if err := template.ValidateDockerfileArgSchemas(tmpl.ArgSchemas); err != nil {
+		return e.ErrCreateDockerfileTemplate.AddErr(err)
+	}
+
+	obj := &commonmodels.DockerfileTemplate{
+		Name:       tmpl.Name,
+		Content:    tmpl.Content,
+		ArgSchemas: tmpl.ArgSchemas,
+		Version:    1,
+		UpdateBy:   username,
+		UpdateTime: time.Now().Unix(),
+	}
+	err := commonrepo.NewDockerfileTemplateColl().Create(obj)
 	if err != nil {
 		logger.Errorf("create dockerfile template error: %s", err)
+		return err
 	}
-	return err
+	return createDockerfileTemplateVersion(obj)
 }
 
-func UpdateDockerfileTemplate(id string, template *template.DockerfileTemplate, logger *zap.SugaredLogger) error {
-	err := commonrepo.NewDockerfileTemplateColl().Update(
-		id,
-		&commonmodels.DockerfileTemplate{
-			Name:    template.Name,
-			Content: template.Content,
-		},
-	)
+func UpdateDockerfileTemplate(id string, tmpl *template.DockerfileTemplate, username string, logger *zap.SugaredLogger) error {
+	if err := template.ValidateDockerfileArgSchemas(tmpl.ArgSchemas); err != nil {
+		return e.ErrUpdateDockerfileTemplate.AddErr(err)
+	}
+
+	origin, err := commonrepo.NewDockerfileTemplateColl().GetById(id)
+	if err != nil {
+		logger.Errorf("failed to find dockerfile template %s, error: %s", id, err)
+		return e.ErrUpdateDockerfileTemplate.AddErr(err)
+	}
+
+	obj := &commonmodels.DockerfileTemplate{
+		Name:       tmpl.Name,
+		Content:    tmpl.Content,
+		ArgSchemas: tmpl.ArgSchemas,
+		Version:    origin.Version,
+		UpdateBy:   username,
+		UpdateTime: time.Now().Unix(),
+	}
+	// renaming the template does not generate a new version
+	changed := origin.Content != obj.Content
+	if len(origin.ArgSchemas) > 0 || len(obj.ArgSchemas) > 0 {
+		changed = changed || !reflect.DeepEqual(origin.ArgSchemas, obj.ArgSchemas)
+	}
+	if changed {
+		obj.Version++
+	}
+
+	err = commonrepo.NewDockerfileTemplateColl().Update(id, obj)
 	if err != nil {
 		logger.Errorf("update dockerfile template error: %s", err)
+		return err
 	}
-	return err
+
+	if !changed {
+		return nil
+	}
+	obj.ID = origin.ID
+	return createDockerfileTemplateVersion(obj)
+}
+
+func createDockerfileTemplateVersion(obj *commonmodels.DockerfileTemplate) error {
+	err := commonrepo.NewDockerfileTemplateVersionColl().Create(&commonmodels.DockerfileTemplateVersion{
+		TemplateID: obj.ID.Hex(),
+		Version:    obj.Version,
+		Name:       obj.Name,
+		Content:    obj.Content,
+		ArgSchemas: obj.ArgSchemas,
+		CreateBy:   obj.UpdateBy,
+		CreateTime: obj.UpdateTime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save version %d of dockerfile template %s, error: %s", obj.Version, obj.Name, err)
+	}
+	return nil
 }
 
 func ListDockerfileTemplate(pageNum, pageSize int, logger *zap.SugaredLogger) ([]*template.DockerfileListObject, int, error) {
@@ -63,8 +123,11 @@ func ListDockerfileTemplate(pageNum, pageSize int, logger *zap.SugaredLogger) ([
 	}
 	for _, obj := range templateList {
 		resp = append(resp, &template.DockerfileListObject{
-			ID:   obj.ID.Hex(),
-			Name: obj.Name,
+			ID:         obj.ID.Hex(),
+			Name:       obj.Name,
+			Version:    obj.Version,
+			UpdateBy:   obj.UpdateBy,
+			UpdateTime: obj.UpdateTime,
 		})
 	}
 	return resp, total, err
@@ -82,6 +145,11 @@ func DeleteDockerfileTemplate(id string, logger *zap.SugaredLogger) error {
 	err = commonrepo.NewDockerfileTemplateColl().DeleteByID(id)
 	if err != nil {
 		logger.Errorf("Failed to delete dockerfile template of id: %s, the error is: %s", id, err)
+		return err
+	}
+	err = commonrepo.NewDockerfileTemplateVersionColl().DeleteByTemplateID(id)
+	if err != nil {
+		logger.Errorf("Failed to delete versions of dockerfile template of id: %s, the error is: %s", id, err)
 	}
 	return err
 }
@@ -115,3 +183,102 @@ func ValidateDockerfileTemplate(template string, _ *zap.SugaredLogger) error {
 	}
 	return nil
 }
+
+func ListDockerfileTemplateVersions(id string, logger *zap.SugaredLogger) ([]*commonmodels.DockerfileTemplateVersion, error) {
+	versions, err := commonrepo.NewDockerfileTemplateVersionColl().List(id)
+	if err != nil {
+		logger.Errorf("Failed to list versions of dockerfile template %s, the error is: %s", id, err)
+		return nil, e.ErrListDockerfileTemplateVersion.AddErr(err)
+	}
+	return versions, nil
+}
+
+type DiffDockerfileTemplateVersionsResponse struct {
+	ContentA    string                              `json:"content_a"`
+	ContentB    string                              `json:"content_b"`
+	ArgSchemasA []*commonmodels.DockerfileArgSchema `json:"arg_schemas_a"`
+	ArgSchemasB []*commonmodels.DockerfileArgSchema `json:"arg_schemas_b"`
+	Diff        string                              `json:"diff"`
+}
+
+func DiffDockerfileTemplateVersions(id string, versionA, versionB int64, logger *zap.SugaredLogger) (*DiffDockerfileTemplateVersionsResponse, error) {
+	templateA, err := commonrepo.NewDockerfileTemplateVersionColl().Get(id, versionA)
+	if err != nil {
+		return nil, e.ErrDiffDockerfileTemplateVersions.AddErr(fmt.Errorf("failed to find version %d of dockerfile template %s, error: %s", versionA, id, err))
+	}
+	templateB, err := commonrepo.NewDockerfileTemplateVersionColl().Get(id, versionB)
+	if err != nil {
+		return nil, e.ErrDiffDockerfileTemplateVersions.AddErr(fmt.Errorf("failed to find version %d of dockerfile template %s, error: %s", versionB, id, err))
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(templateA.Content),
+		B:        difflib.SplitLines(templateB.Content),
+		FromFile: fmt.Sprintf("%s-v%d", templateA.Name, versionA),
+		ToFile:   fmt.Sprintf("%s-v%d", templateB.Name, versionB),
+		Context:  3,
+	})
+	if err != nil {
+		logger.Errorf("Failed to diff versions %d and %d of dockerfile template %s, the error is: %s", versionA, versionB, id, err)
+		return nil, e.ErrDiffDockerfileTemplateVersions.AddErr(err)
+	}
+
+	return &DiffDockerfileTemplateVersionsResponse{
+		ContentA:    templateA.Content,
+		ContentB:    templateB.Content,
+		ArgSchemasA: templateA.ArgSchemas,
+		ArgSchemasB: templateB.ArgSchemas,
+		Diff:        diff,
+	}, nil
+}
+
+type DockerfileTemplateVersionUsage struct {
+	// Version 0 means the builds always use the latest version of the template
+	Version int64                      `json:"version"`
+	Builds  []*template.BuildReference `json:"builds"`
+}
+
+// GetDockerfileTemplateUsage groups the builds referencing the template by the template version they use
+func GetDockerfileTemplateUsage(id string, logger *zap.SugaredLogger) ([]*DockerfileTemplateVersionUsage, error) {
+	referenceList, err := commonrepo.NewBuildColl().GetDockerfileTemplateReference(id)
+	if err != nil {
+		logger.Errorf("Failed to get build reference for dockerfile template id: %s, the error is: %s", id, err)
+		return nil, err
+	}
+
+	usageMap := make(map[int64]*DockerfileTemplateVersionUsage)
+	for _, reference := range referenceList {
+		version := int64(0)
+		if reference.PostBuild != nil && reference.PostBuild.DockerBuild != nil {
+			version = reference.PostBuild.DockerBuild.TemplateVersion
+		}
+		if _, ok := usageMap[version]; !ok {
+			usageMap[version] = &DockerfileTemplateVersionUsage{
+				Version: version,
+				Builds:  make([]*template.BuildReference, 0),
+			}
+		}
+		usageMap[version].Builds = append(usageMap[version].Builds, &template.BuildReference{
+			BuildName:   reference.Name,
+			ProjectName: reference.ProductName,
+		})
+	}
+
+	ret := make([]*DockerfileTemplateVersionUsage, 0, len(usageMap))
+	for _, usage := range usageMap {
+		ret = append(ret, usage)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Version > ret[j].Version
+	})
+	return ret, nil
+}
+
+func LintDockerfileTemplate(content string, logger *zap.SugaredLogger) ([]*hadolint.Issue, error) {
+	issues, err := hadolint.Lint(content)
+	if err != nil {
+		logger.Errorf("Failed to lint dockerfile, the error is: %s", err)
+		return nil, e.ErrLintDockerfileTemplate.AddErr(err)
+	}
+	return issues, nil
+}
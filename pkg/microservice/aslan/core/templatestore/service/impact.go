@@ -0,0 +1,496 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	configbase "github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/instantmessage"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type TemplateType string
+
+const (
+	TemplateTypeYaml       TemplateType = "yaml"
+	TemplateTypeChart      TemplateType = "chart"
+	TemplateTypeBuild      TemplateType = "build"
+	TemplateTypeDockerfile TemplateType = "dockerfile"
+)
+
+// UpdatePolicy describes how a referencing object picks up the template change
+type UpdatePolicy string
+
+const (
+	// UpdatePolicyFollowLatest means the change takes effect automatically in the next run/update
+	UpdatePolicyFollowLatest UpdatePolicy = "follow_latest"
+	// UpdatePolicyPinned means the object is pinned to a specific revision and won't be affected
+	UpdatePolicyPinned UpdatePolicy = "pinned"
+	// UpdatePolicyManual means the change takes effect only after a manual sync/update
+	UpdatePolicyManual UpdatePolicy = "manual"
+)
+
+type TemplateImpact struct {
+	TemplateID   string                 `json:"template_id"`
+	TemplateName string                 `json:"template_name"`
+	TemplateType TemplateType           `json:"template_type"`
+	Services     []*ImpactedService     `json:"services"`
+	Builds       []*ImpactedBuild       `json:"builds"`
+	Environments []*ImpactedEnvironment `json:"environments"`
+	Workflows    []*ImpactedWorkflow    `json:"workflows"`
+}
+
+type ImpactedService struct {
+	ProjectName string       `json:"project_name"`
+	ServiceName string       `json:"service_name"`
+	Production  bool         `json:"production"`
+	Revision    int64        `json:"revision"`
+	Policy      UpdatePolicy `json:"policy"`
+}
+
+type ImpactedBuild struct {
+	ProjectName    string       `json:"project_name"`
+	BuildName      string       `json:"build_name"`
+	ServiceModules []string     `json:"service_modules"`
+	Policy         UpdatePolicy `json:"policy"`
+}
+
+type ImpactedEnvironment struct {
+	ProjectName      string       `json:"project_name"`
+	EnvName          string       `json:"env_name"`
+	Production       bool         `json:"production"`
+	ServiceName      string       `json:"service_name"`
+	DeployedRevision int64        `json:"deployed_revision"`
+	PinnedRevision   int64        `json:"pinned_revision,omitempty"`
+	Policy           UpdatePolicy `json:"policy"`
+}
+
+type ImpactedWorkflow struct {
+	ProjectName  string         `json:"project_name"`
+	WorkflowName string         `json:"workflow_name"`
+	DisplayName  string         `json:"display_name"`
+	JobName      string         `json:"job_name"`
+	JobType      config.JobType `json:"job_type"`
+	// Targets are the services or builds in the job referencing the template
+	Targets []string     `json:"targets"`
+	Policy  UpdatePolicy `json:"policy"`
+}
+
+type NotifyTemplateImpactArgs struct {
+	NotifyCtls []*models.NotifyCtl `json:"notify_ctls"`
+	// Message is an optional description of the upcoming change
+	Message string `json:"message"`
+}
+
+// GetYamlTemplateImpact lists the services created from the yaml template, the environments running
+// these services and the workflows deploying them.
+func GetYamlTemplateImpact(id string, logger *zap.SugaredLogger) (*TemplateImpact, error) {
+	yamlTemplate, err := commonrepo.NewYamlTemplateColl().GetById(id)
+	if err != nil {
+		return nil, e.ErrGetTemplateImpact.AddDesc(fmt.Sprintf("failed to find yaml template %s: %s", id, err))
+	}
+
+	resp := newTemplateImpact(id, yamlTemplate.Name, TemplateTypeYaml)
+	for _, production := range []bool{false, true} {
+		var services []*models.Service
+		if production {
+			services, err = commonrepo.NewProductionServiceColl().GetYamlTemplateLatestReference(id)
+		} else {
+			services, err = commonrepo.NewServiceColl().GetYamlTemplateLatestReference(id)
+		}
+		if err != nil {
+			return nil, e.ErrGetTemplateImpact.AddErr(err)
+		}
+		appendImpactedServices(resp, services, production, logger)
+	}
+
+	sortTemplateImpact(resp)
+	return resp, nil
+}
+
+// GetChartTemplateImpact lists the services created from the chart template, the environments running
+// these services and the workflows deploying them.
+func GetChartTemplateImpact(name string, logger *zap.SugaredLogger) (*TemplateImpact, error) {
+	if _, err := commonrepo.NewChartColl().Get(name); err != nil {
+		return nil, e.ErrGetTemplateImpact.AddDesc(fmt.Sprintf("failed to find chart template %s: %s", name, err))
+	}
+
+	resp := newTemplateImpact(name, name, TemplateTypeChart)
+	for _, production := range []bool{false, true} {
+		var services []*models.Service
+		var err error
+		if production {
+			services, err = commonrepo.NewProductionServiceColl().GetChartTemplateReference(name)
+		} else {
+			services, err = commonrepo.NewServiceColl().GetChartTemplateReference(name)
+		}
+		if err != nil {
+			return nil, e.ErrGetTemplateImpact.AddErr(err)
+		}
+		appendImpactedServices(resp, services, production, logger)
+	}
+
+	sortTemplateImpact(resp)
+	return resp, nil
+}
+
+// GetBuildTemplateImpact lists the builds created from the build template and the workflows running these builds,
+// builds always use the latest template content.
+func GetBuildTemplateImpact(id string, logger *zap.SugaredLogger) (*TemplateImpact, error) {
+	buildTemplate, err := commonrepo.NewBuildTemplateColl().Find(&commonrepo.BuildTemplateQueryOption{ID: id})
+	if err != nil {
+		return nil, e.ErrGetTemplateImpact.AddDesc(fmt.Sprintf("failed to find build template %s: %s", id, err))
+	}
+
+	builds, err := commonrepo.NewBuildColl().GetBuildTemplateReference(id)
+	if err != nil {
+		return nil, e.ErrGetTemplateImpact.AddErr(err)
+	}
+
+	resp := newTemplateImpact(id, buildTemplate.Name, TemplateTypeBuild)
+	appendImpactedBuilds(resp, builds, func(*models.Build) UpdatePolicy { return UpdatePolicyFollowLatest }, logger)
+
+	sortTemplateImpact(resp)
+	return resp, nil
+}
+
+// GetDockerfileTemplateImpact lists the builds using the dockerfile template and the workflows running these builds,
+// builds pinned to a template version are not affected.
+func GetDockerfileTemplateImpact(id string, logger *zap.SugaredLogger) (*TemplateImpact, error) {
+	dockerfileTemplate, err := commonrepo.NewDockerfileTemplateColl().GetById(id)
+	if err != nil {
+		return nil, e.ErrGetTemplateImpact.AddDesc(fmt.Sprintf("failed to find dockerfile template %s: %s", id, err))
+	}
+
+	builds, err := commonrepo.NewBuildColl().GetDockerfileTemplateReference(id)
+	if err != nil {
+		return nil, e.ErrGetTemplateImpact.AddErr(err)
+	}
+
+	resp := newTemplateImpact(id, dockerfileTemplate.Name, TemplateTypeDockerfile)
+	appendImpactedBuilds(resp, builds, func(build *models.Build) UpdatePolicy {
+		if build.PostBuild != nil && build.PostBuild.DockerBuild != nil && build.PostBuild.DockerBuild.TemplateVersion > 0 {
+			return UpdatePolicyPinned
+		}
+		return UpdatePolicyFollowLatest
+	}, logger)
+
+	sortTemplateImpact(resp)
+	return resp, nil
+}
+
+// NotifyTemplateImpact sends the impact summary of the template to the given IM groups/users
+func NotifyTemplateImpact(templateType TemplateType, id string, args *NotifyTemplateImpactArgs, logger *zap.SugaredLogger) error {
+	var impact *TemplateImpact
+	var err error
+	switch templateType {
+	case TemplateTypeYaml:
+		impact, err = GetYamlTemplateImpact(id, logger)
+	case TemplateTypeChart:
+		impact, err = GetChartTemplateImpact(id, logger)
+	case TemplateTypeBuild:
+		impact, err = GetBuildTemplateImpact(id, logger)
+	case TemplateTypeDockerfile:
+		impact, err = GetDockerfileTemplateImpact(id, logger)
+	default:
+		return e.ErrInvalidParam.AddDesc(fmt.Sprintf("unsupported template type %s", templateType))
+	}
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("模板 %s 变更影响通知", impact.TemplateName)
+	lines := make([]string, 0)
+	if args.Message != "" {
+		lines = append(lines, args.Message)
+	}
+	for _, svc := range impact.Services {
+		lines = append(lines, fmt.Sprintf("- 服务 %s/%s（%s）", svc.ProjectName, svc.ServiceName, svc.Policy))
+	}
+	for _, build := range impact.Builds {
+		lines = append(lines, fmt.Sprintf("- 构建 %s/%s（%s）", build.ProjectName, build.BuildName, build.Policy))
+	}
+	for _, env := range impact.Environments {
+		lines = append(lines, fmt.Sprintf("- 环境 %s/%s 服务 %s（%s）", env.ProjectName, env.EnvName, env.ServiceName, env.Policy))
+	}
+	for _, wf := range impact.Workflows {
+		lines = append(lines, fmt.Sprintf("- 工作流 %s/%s 任务 %s（%s）", wf.ProjectName, wf.DisplayName, wf.JobName, wf.Policy))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "没有引用该模板的服务、构建、环境或工作流")
+	}
+	content := strings.Join(lines, "\n")
+	detailURL := fmt.Sprintf("%s/v1/template", configbase.SystemAddress())
+
+	errs := make([]string, 0)
+	for _, notify := range args.NotifyCtls {
+		if err := instantmessage.NewWeChatClient().SendTextNotification(title, content, detailURL, notify); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return e.ErrNotifyTemplateImpact.AddDesc(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func newTemplateImpact(id, name string, templateType TemplateType) *TemplateImpact {
+	return &TemplateImpact{
+		TemplateID:   id,
+		TemplateName: name,
+		TemplateType: templateType,
+		Services:     make([]*ImpactedService, 0),
+		Builds:       make([]*ImpactedBuild, 0),
+		Environments: make([]*ImpactedEnvironment, 0),
+		Workflows:    make([]*ImpactedWorkflow, 0),
+	}
+}
+
+// appendImpactedServices adds the services along with the environments and deploy workflows using them,
+// services are synced from the template manually unless auto sync is enabled.
+func appendImpactedServices(resp *TemplateImpact, services []*models.Service, production bool, logger *zap.SugaredLogger) {
+	projectServices := make(map[string]map[string]bool)
+	for _, svc := range services {
+		policy := UpdatePolicyManual
+		if svc.AutoSync {
+			policy = UpdatePolicyFollowLatest
+		}
+		resp.Services = append(resp.Services, &ImpactedService{
+			ProjectName: svc.ProductName,
+			ServiceName: svc.ServiceName,
+			Production:  production,
+			Revision:    svc.Revision,
+			Policy:      policy,
+		})
+		if projectServices[svc.ProductName] == nil {
+			projectServices[svc.ProductName] = make(map[string]bool)
+		}
+		projectServices[svc.ProductName][svc.ServiceName] = true
+	}
+
+	for projectName, serviceSet := range projectServices {
+		envs, err := getImpactedEnvironments(projectName, production, serviceSet)
+		if err != nil {
+			logger.Warnf("failed to list impacted envs of project %s: %s", projectName, err)
+		} else {
+			resp.Environments = append(resp.Environments, envs...)
+		}
+
+		workflows, err := getImpactedDeployWorkflows(projectName, production, serviceSet)
+		if err != nil {
+			logger.Warnf("failed to list impacted workflows of project %s: %s", projectName, err)
+		} else {
+			resp.Workflows = append(resp.Workflows, workflows...)
+		}
+	}
+}
+
+// appendImpactedBuilds adds the builds along with the build workflows running them, workflows of the
+// pinned builds are left out since they won't pick up the change.
+func appendImpactedBuilds(resp *TemplateImpact, builds []*models.Build, policyFunc func(*models.Build) UpdatePolicy, logger *zap.SugaredLogger) {
+	projectBuilds := make(map[string]map[string]bool)
+	for _, build := range builds {
+		serviceModules := make([]string, 0)
+		for _, target := range build.Targets {
+			serviceModules = append(serviceModules, target.ServiceModule)
+		}
+		policy := policyFunc(build)
+		resp.Builds = append(resp.Builds, &ImpactedBuild{
+			ProjectName:    build.ProductName,
+			BuildName:      build.Name,
+			ServiceModules: serviceModules,
+			Policy:         policy,
+		})
+		if policy == UpdatePolicyPinned {
+			continue
+		}
+		if projectBuilds[build.ProductName] == nil {
+			projectBuilds[build.ProductName] = make(map[string]bool)
+		}
+		projectBuilds[build.ProductName][build.Name] = true
+	}
+
+	for projectName, buildSet := range projectBuilds {
+		workflows, err := getImpactedBuildWorkflows(projectName, buildSet)
+		if err != nil {
+			logger.Warnf("failed to list impacted workflows of project %s: %s", projectName, err)
+			continue
+		}
+		resp.Workflows = append(resp.Workflows, workflows...)
+	}
+}
+
+func getImpactedEnvironments(projectName string, production bool, serviceSet map[string]bool) ([]*ImpactedEnvironment, error) {
+	envs, err := commonrepo.NewProductColl().List(&commonrepo.ProductListOptions{
+		Name:          projectName,
+		Production:    &production,
+		ExcludeStatus: []string{setting.ProductStatusDeleting},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*ImpactedEnvironment, 0)
+	for _, env := range envs {
+		for serviceName, svc := range env.GetServiceMap() {
+			if !serviceSet[serviceName] {
+				continue
+			}
+			impacted := &ImpactedEnvironment{
+				ProjectName:      projectName,
+				EnvName:          env.EnvName,
+				Production:       production,
+				ServiceName:      serviceName,
+				DeployedRevision: svc.Revision,
+				Policy:           UpdatePolicyFollowLatest,
+			}
+			if pinned := env.GetPinnedServiceRevision(serviceName); pinned > 0 {
+				impacted.PinnedRevision = pinned
+				impacted.Policy = UpdatePolicyPinned
+			}
+			resp = append(resp, impacted)
+		}
+	}
+	return resp, nil
+}
+
+// getImpactedDeployWorkflows finds the deploy jobs deploying the services, only the jobs updating
+// the service config pick up the new template revisions.
+func getImpactedDeployWorkflows(projectName string, production bool, serviceSet map[string]bool) ([]*ImpactedWorkflow, error) {
+	workflows, _, err := commonrepo.NewWorkflowV4Coll().List(&commonrepo.ListWorkflowV4Option{
+		ProjectName: projectName,
+		JobTypes:    []config.JobType{config.JobZadigDeploy},
+	}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*ImpactedWorkflow, 0)
+	for _, workflow := range workflows {
+		for _, stage := range workflow.Stages {
+			for _, job := range stage.Jobs {
+				if job.JobType != config.JobZadigDeploy {
+					continue
+				}
+				spec := new(models.ZadigDeployJobSpec)
+				if err := models.IToi(job.Spec, spec); err != nil {
+					return nil, fmt.Errorf("failed to decode deploy job %s of workflow %s: %s", job.Name, workflow.Name, err)
+				}
+				if spec.Production != production {
+					continue
+				}
+
+				targets := make([]string, 0)
+				for _, svc := range spec.Services {
+					if serviceSet[svc.ServiceName] {
+						targets = append(targets, svc.ServiceName)
+					}
+				}
+				// services of the runtime deploy jobs are selected when the workflow runs
+				if len(targets) == 0 && spec.Source != config.SourceRuntime {
+					continue
+				}
+
+				policy := UpdatePolicyManual
+				for _, content := range spec.DeployContents {
+					if content == config.DeployConfig {
+						policy = UpdatePolicyFollowLatest
+					}
+				}
+				resp = append(resp, &ImpactedWorkflow{
+					ProjectName:  projectName,
+					WorkflowName: workflow.Name,
+					DisplayName:  workflow.DisplayName,
+					JobName:      job.Name,
+					JobType:      job.JobType,
+					Targets:      targets,
+					Policy:       policy,
+				})
+			}
+		}
+	}
+	return resp, nil
+}
+
+func getImpactedBuildWorkflows(projectName string, buildSet map[string]bool) ([]*ImpactedWorkflow, error) {
+	workflows, _, err := commonrepo.NewWorkflowV4Coll().List(&commonrepo.ListWorkflowV4Option{
+		ProjectName: projectName,
+		JobTypes:    []config.JobType{config.JobZadigBuild},
+	}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*ImpactedWorkflow, 0)
+	for _, workflow := range workflows {
+		for _, stage := range workflow.Stages {
+			for _, job := range stage.Jobs {
+				if job.JobType != config.JobZadigBuild {
+					continue
+				}
+				spec := new(models.ZadigBuildJobSpec)
+				if err := models.IToi(job.Spec, spec); err != nil {
+					return nil, fmt.Errorf("failed to decode build job %s of workflow %s: %s", job.Name, workflow.Name, err)
+				}
+
+				targets := make([]string, 0)
+				for _, build := range spec.ServiceAndBuilds {
+					if buildSet[build.BuildName] {
+						targets = append(targets, fmt.Sprintf("%s/%s", build.ServiceName, build.ServiceModule))
+					}
+				}
+				if len(targets) == 0 {
+					continue
+				}
+				resp = append(resp, &ImpactedWorkflow{
+					ProjectName:  projectName,
+					WorkflowName: workflow.Name,
+					DisplayName:  workflow.DisplayName,
+					JobName:      job.Name,
+					JobType:      job.JobType,
+					Targets:      targets,
+					Policy:       UpdatePolicyFollowLatest,
+				})
+			}
+		}
+	}
+	return resp, nil
+}
+
+func sortTemplateImpact(impact *TemplateImpact) {
+	sort.SliceStable(impact.Services, func(i, j int) bool {
+		return impact.Services[i].ProjectName+impact.Services[i].ServiceName < impact.Services[j].ProjectName+impact.Services[j].ServiceName
+	})
+	sort.SliceStable(impact.Builds, func(i, j int) bool {
+		return impact.Builds[i].ProjectName+impact.Builds[i].BuildName < impact.Builds[j].ProjectName+impact.Builds[j].BuildName
+	})
+	sort.SliceStable(impact.Environments, func(i, j int) bool {
+		return impact.Environments[i].ProjectName+impact.Environments[i].EnvName < impact.Environments[j].ProjectName+impact.Environments[j].EnvName
+	})
+	sort.SliceStable(impact.Workflows, func(i, j int) bool {
+		return impact.Workflows[i].ProjectName+impact.Workflows[i].WorkflowName < impact.Workflows[j].ProjectName+impact.Workflows[j].WorkflowName
+	})
+}
@@ -27,6 +27,7 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		vm.PUT("/:vmid/agent/offline", OfflineVM)
 		vm.PUT("/:vmid/agent/recovery", RecoveryVM)
 		vm.PUT("/:vmid/agent/upgrade", UpgradeAgent)
+		vm.POST("/agent/upgrade", RolloutAgentUpgrade)
 		vm.GET("/vms", ListVMs)
 		vm.GET("/labels", ListVMLabels)
 	}
@@ -110,6 +110,32 @@ func UpgradeAgent(c *gin.Context) {
 	ctx.Resp, ctx.Err = service.UpgradeAgent(c.Param("vmid"), ctx.UserName, ctx.Logger)
 }
 
+func RolloutAgentUpgrade(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Logger.Errorf("failed to generate authorization info for user: %s, error: %s", ctx.UserID, err)
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	// authorization checks
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(service.AgentUpgradeRolloutArgs)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = fmt.Errorf("invalid request: %s", err)
+		return
+	}
+
+	ctx.Resp, ctx.Err = service.RolloutAgentUpgrade(args, ctx.UserName, ctx.Logger)
+}
+
 func ListVMs(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
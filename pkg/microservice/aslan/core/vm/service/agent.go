@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type AgentUpgradeRolloutArgs struct {
+	// VMIDs and Label select the vms to upgrade, all the agents are upgraded if both are empty
+	VMIDs []string `json:"vm_ids"`
+	Label string   `json:"label"`
+}
+
+type AgentUpgradeRolloutResp struct {
+	Version string   `json:"version"`
+	VMs     []string `json:"vms"`
+}
+
+// RolloutAgentUpgrade marks the selected agents running an outdated version to be upgraded to the version of zadig,
+// the agents upgrade themselves when they are told so by the response of the next heartbeat.
+func RolloutAgentUpgrade(args *AgentUpgradeRolloutArgs, user string, logger *zap.SugaredLogger) (*AgentUpgradeRolloutResp, error) {
+	version, err := getZadigAgentVersion()
+	if err != nil {
+		return nil, e.ErrUpgradeZadigVMAgent.AddErr(fmt.Errorf("failed to get zadig-agent version, error: %s", err))
+	}
+
+	vms, err := commonrepo.NewPrivateKeyColl().List(&commonrepo.PrivateKeyArgs{})
+	if err != nil {
+		logger.Errorf("failed to list VMs, error: %s", err)
+		return nil, e.ErrUpgradeZadigVMAgent.AddErr(err)
+	}
+
+	ids := sets.NewString(args.VMIDs...)
+	resp := &AgentUpgradeRolloutResp{
+		Version: version,
+		VMs:     make([]string, 0),
+	}
+	for _, vm := range vms {
+		if vm.Agent == nil || vm.Type != setting.NewVMType {
+			continue
+		}
+		if ids.Len() > 0 && !ids.Has(vm.ID.Hex()) {
+			continue
+		}
+		if args.Label != "" && vm.Label != args.Label {
+			continue
+		}
+		if vm.Agent.CurrentVersion == version || (vm.Agent.NeedUpdate && vm.Agent.AgentVersion == version) {
+			continue
+		}
+
+		vm.Agent.AgentVersion = version
+		vm.Agent.NeedUpdate = true
+		vm.UpdateBy = user
+		vm.UpdateTime = time.Now().Unix()
+		if err := commonrepo.NewPrivateKeyColl().Update(vm.ID.Hex(), vm); err != nil {
+			logger.Errorf("failed to mark vm %s to upgrade, error: %s", vm.Name, err)
+			return nil, e.ErrUpgradeZadigVMAgent.AddErr(fmt.Errorf("failed to mark vm %s to upgrade, error: %s", vm.Name, err))
+		}
+		resp.VMs = append(resp.VMs, vm.Name)
+	}
+	return resp, nil
+}
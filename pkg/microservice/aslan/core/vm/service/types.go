@@ -46,13 +46,18 @@ func (args *CreateVMRequest) Validate() error {
 }
 
 type AgentBriefListResp struct {
-	Name         string `json:"name"`
-	Label        string `json:"label"`
-	Status       string `json:"status"`
-	Error        string `json:"error"`
-	Platform     string `json:"platform"`
-	Architecture string `json:"architecture"`
-	IP           string `json:"ip"`
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Label             string `json:"label"`
+	Status            string `json:"status"`
+	Error             string `json:"error"`
+	Platform          string `json:"platform"`
+	Architecture      string `json:"architecture"`
+	IP                string `json:"ip"`
+	CurrentVersion    string `json:"current_version"`
+	TargetVersion     string `json:"target_version"`
+	NeedUpdate        bool   `json:"need_update"`
+	LastHeartbeatTime int64  `json:"last_heartbeat_time"`
 }
 
 type RegisterAgentParameters struct {
@@ -114,6 +119,7 @@ type HeartbeatParameters struct {
 	DiskSpace     uint64 `json:"disk_space"`
 	FreeDiskSpace uint64 `json:"free_disk_space"`
 	VMname        string `json:"vm_name"`
+	AgentVersion  string `json:"agent_version"`
 }
 
 type HeartbeatRequest struct {
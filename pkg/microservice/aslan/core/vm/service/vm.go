@@ -334,10 +334,15 @@ func ListVMs(logger *zap.SugaredLogger) ([]*AgentBriefListResp, error) {
 		}
 
 		a := &AgentBriefListResp{
-			Name:   vm.Name,
-			Label:  vm.Label,
-			Status: string(vm.Status),
-			Error:  vm.Error,
+			ID:                vm.ID.Hex(),
+			Name:              vm.Name,
+			Label:             vm.Label,
+			Status:            string(vm.Status),
+			Error:             vm.Error,
+			CurrentVersion:    vm.Agent.CurrentVersion,
+			TargetVersion:     vm.Agent.AgentVersion,
+			NeedUpdate:        vm.Agent.NeedUpdate,
+			LastHeartbeatTime: vm.Agent.LastHeartbeatTime,
 		}
 		if vm.VMInfo != nil {
 			a.IP = vm.VMInfo.IP
@@ -405,6 +410,8 @@ func RegisterAgent(args *RegisterAgentRequest, logger *zap.SugaredLogger) (*Regi
 			return nil, fmt.Errorf("zadig server vm %s agent is nil in db", args.Token)
 		}
 		vm.Agent.AgentVersion = args.Parameters.AgentVersion
+		vm.Agent.CurrentVersion = args.Parameters.AgentVersion
+		vm.Agent.NeedUpdate = false
 	}
 	err = commonrepo.NewPrivateKeyColl().Update(vm.ID.Hex(), vm)
 	if err != nil {
@@ -480,6 +487,13 @@ func Heartbeat(args *HeartbeatRequest, logger *zap.SugaredLogger) (*HeartbeatRes
 		return nil, fmt.Errorf("zadig server vm %s agent is nil in db", args.Token)
 	}
 	vm.Agent.LastHeartbeatTime = time.Now().Unix()
+	if args.Parameters != nil && args.Parameters.AgentVersion != "" {
+		vm.Agent.CurrentVersion = args.Parameters.AgentVersion
+		// the upgrade is done once the agent reports the target version
+		if vm.Agent.NeedUpdate && vm.Agent.CurrentVersion == vm.Agent.AgentVersion {
+			vm.Agent.NeedUpdate = false
+		}
+	}
 
 	err = commonrepo.NewPrivateKeyColl().Update(vm.ID.Hex(), vm)
 	if err != nil {
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// htmlReportCSP sandboxes the reports into an opaque origin, so that the scripts in the reports can't
+// access the cookies or call the apis of zadig
+const htmlReportCSP = "sandbox allow-scripts allow-popups allow-forms allow-modals allow-downloads"
+
+// @Summary List Workflow Task HTML Reports
+// @Description List the html reports uploaded by the build or testing job
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	taskID			path		int								true	"task id"
+// @Param 	jobName			path		string							true	"job name"
+// @Success 200 			{array} 	workflow.HTMLReport
+// @Router /api/aslan/workflow/v4/workflowtask/workflow/{workflowName}/task/{taskID}/job/{jobName}/htmlreports [get]
+func ListWorkflowTaskHTMLReports(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !checkHTMLReportPermission(ctx, c.Param("workflowName")) {
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+
+	ctx.Resp, ctx.Err = workflow.ListWorkflowTaskHTMLReports(c.Param("workflowName"), c.Param("jobName"), taskID, ctx.Logger)
+}
+
+// @Summary Get Workflow Task HTML Report File
+// @Description Serve the file of the html report inline, the index file is returned if the file path is empty
+// @Tags 	workflow
+// @Produce octet-stream
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	taskID			path		int								true	"task id"
+// @Param 	jobName			path		string							true	"job name"
+// @Param 	reportName		path		string							true	"report name"
+// @Param 	filePath		path		string							true	"file path in the report"
+// @Success 200
+// @Router /api/aslan/workflow/v4/workflowtask/workflow/{workflowName}/task/{taskID}/job/{jobName}/htmlreports/{reportName}/{filePath} [get]
+func GetWorkflowTaskHTMLReportFile(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	if !checkHTMLReportPermission(ctx, c.Param("workflowName")) {
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+
+	file, err := workflow.GetWorkflowTaskHTMLReportFile(c.Param("workflowName"), c.Param("jobName"), c.Param("reportName"), c.Param("filePath"), taskID, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		internalhandler.JSONResponse(c, ctx)
+		return
+	}
+	defer file.Body.Close()
+
+	c.DataFromReader(http.StatusOK, file.ContentLength, file.ContentType, file.Body, map[string]string{
+		"Content-Disposition":     "inline",
+		"Content-Security-Policy": htmlReportCSP,
+		"X-Content-Type-Options":  "nosniff",
+		"Cache-Control":           "private, max-age=3600",
+	})
+}
+
+// checkHTMLReportPermission requires the view permission of the workflow, it sets ctx.UnAuthorized or ctx.Err if not permitted
+func checkHTMLReportPermission(ctx *internalhandler.Context, workflowName string) bool {
+	w, err := workflow.FindWorkflowV4Raw(workflowName, ctx.Logger)
+	if err != nil {
+		ctx.Logger.Errorf("failed to find workflow %s, error: %s", workflowName, err)
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return false
+	}
+
+	if ctx.Resources.IsSystemAdmin {
+		return true
+	}
+	if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+		ctx.UnAuthorized = true
+		return false
+	}
+	if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+		!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.View {
+		// check if the permission is given by collaboration mode
+		permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, workflowName, types.WorkflowActionView)
+		if err != nil || !permitted {
+			ctx.UnAuthorized = true
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// @Summary Check Workflow Job References
+// @Description Check the job references (source from job, from_job) of an unsaved workflow and suggest the jobs to refer to
+// @Tags 	workflow
+// @Accept 	plain
+// @Produce json
+// @Param 	body 	body 		commonmodels.WorkflowV4 				true 	"workflow yaml"
+// @Success 200 	{object} 	workflow.JobReferenceCheckResp
+// @Router /api/aslan/workflow/v4/references/check [post]
+func CheckWorkflowV4JobReferences(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	args := new(commonmodels.WorkflowV4)
+	if err := yaml.Unmarshal([]byte(getBody(c)), args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	ctx.Resp, ctx.Err = workflow.CheckWorkflowV4JobReferences(args, ctx.Logger)
+}
+
+// @Summary Get Workflow Job Reference Issues
+// @Description Check the job references of a saved workflow and suggest the jobs to refer to
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string									true	"workflow name"
+// @Success 200 	{object} 	workflow.JobReferenceCheckResp
+// @Router /api/aslan/workflow/v4/name/{name}/references [get]
+func GetWorkflowV4JobReferences(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	w, err := workflow.FindWorkflowV4Raw(c.Param("name"), ctx.Logger)
+	if err != nil {
+		ctx.Err = e.ErrFindWorkflow.AddErr(err)
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.CheckWorkflowV4JobReferences(w, ctx.Logger)
+}
+
+// @Summary Repair Workflow Job References
+// @Description Point the broken job references of a saved workflow to the only available job, the ambiguous ones are returned as unresolved
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	name	path		string									true	"workflow name"
+// @Success 200 	{object} 	workflow.JobReferenceRepairResp
+// @Router /api/aslan/workflow/v4/name/{name}/references/repair [post]
+func RepairWorkflowV4JobReferences(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	w, err := workflow.FindWorkflowV4Raw(c.Param("name"), ctx.Logger)
+	if err != nil {
+		ctx.Err = e.ErrFindWorkflow.AddErr(err)
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.Edit {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionEdit)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, w.Project, "修复", "自定义工作流-任务引用", w.Name, "", ctx.Logger)
+
+	ctx.Resp, ctx.Err = workflow.RepairWorkflowV4JobReferences(w.Name, ctx.UserName, ctx.Logger)
+}
+
+// @Summary Explain Workflow Variables
+// @Description Simulate the variable rendering of a task with the given inputs, and explain how every variable in the jobs will be resolved
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	body 	body 		commonmodels.WorkflowV4 				true 	"workflow task args"
+// @Success 200 	{object} 	workflow.WorkflowVariableExplainResp
+// @Router /api/aslan/workflow/v4/variables/explain [post]
+func ExplainWorkflowV4Variables(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(commonmodels.WorkflowV4)
+	if err := c.ShouldBindJSON(args); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[args.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[args.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[args.Project].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, args.Project, types.ResourceTypeWorkflow, args.Name, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.ExplainWorkflowV4Variables(args, ctx.UserName, ctx.Account, ctx.Logger)
+}
@@ -18,6 +18,14 @@ import (
 	"github.com/koderover/zadig/v2/pkg/tool/log"
 )
 
+// @Summary OpenAPI Create Custom Workflow Task
+// @Description OpenAPI Create Custom Workflow Task
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	workflowservice.OpenAPICreateCustomWorkflowTaskArgs	true	"body"
+// @Success 200 	{object} 	workflowservice.CreateTaskV4Resp
+// @Router /openapi/workflows/custom/task [post]
 func CreateCustomWorkflowTask(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -59,6 +67,14 @@ func CreateCustomWorkflowTask(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.CreateCustomWorkflowTask(ctx.UserName, args, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Workflow View
+// @Description OpenAPI Create Workflow View
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	workflowservice.OpenAPICreateWorkflowViewReq	true	"body"
+// @Success 200
+// @Router /openapi/workflows/view [post]
 func OpenAPICreateWorkflowView(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -98,6 +114,14 @@ func OpenAPICreateWorkflowView(c *gin.Context) {
 	ctx.Err = workflowservice.CreateWorkflowViewOpenAPI(args.Name, args.ProjectName, args.WorkflowList, ctx.UserName, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Workflow Views
+// @Description OpenAPI Get Workflow Views
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{array} 	workflowservice.OpenAPIWorkflowViewBrief
+// @Router /openapi/workflows/view [get]
 func OpenAPIGetWorkflowViews(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -111,6 +135,16 @@ func OpenAPIGetWorkflowViews(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.OpenAPIGetWorkflowViews(projectKey, ctx.Logger)
 }
 
+// @Summary OpenAPI Update Workflow View
+// @Description OpenAPI Update Workflow View
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	workflowservice.OpenAPICreateWorkflowViewReq	true	"body"
+// @Success 200
+// @Router /openapi/workflows/view/{name} [put]
 func OpenAPIUpdateWorkflowView(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -167,6 +201,15 @@ func OpenAPIUpdateWorkflowView(c *gin.Context) {
 	ctx.Err = workflowservice.UpdateWorkflowViewOpenAPI(viewName, args.ProjectName, list, ctx.UserName, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Workflow View
+// @Description OpenAPI Delete Workflow View
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/workflows/view/{name} [delete]
 func OpenAPIDeleteWorkflowView(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -212,6 +255,15 @@ type getworkflowTaskReq struct {
 	WorkflowName string `json:"workflow_key" form:"workflowKey"`
 }
 
+// @Summary OpenAPI Get Workflow Task V4
+// @Description OpenAPI Get Workflow Task V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	taskId	query	int	false	"task id"
+// @Param 	workflowKey	query	string	false	"workflow key"
+// @Success 200 	{object} 	workflowservice.WorkflowTaskPreview
+// @Router /openapi/workflows/custom/task [get]
 func OpenAPIGetWorkflowTaskV4(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -225,6 +277,14 @@ func OpenAPIGetWorkflowTaskV4(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.GetWorkflowTaskV4(args.WorkflowName, args.TaskID, ctx.Logger)
 }
 
+// @Summary OpenAPI Cancel Workflow Task V4
+// @Description OpenAPI Cancel Workflow Task V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	getworkflowTaskReq	true	"body"
+// @Success 200
+// @Router /openapi/workflows/custom/task [delete]
 func OpenAPICancelWorkflowTaskV4(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -238,6 +298,14 @@ func OpenAPICancelWorkflowTaskV4(c *gin.Context) {
 	ctx.Err = workflowservice.CancelWorkflowTaskV4(ctx.UserName, args.WorkflowName, args.TaskID, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Product Workflow Task
+// @Description OpenAPI Create Product Workflow Task
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	workflowservice.OpenAPICreateProductWorkflowTaskArgs	true	"body"
+// @Success 200 	{object} 	workflowservice.CreateTaskResp
+// @Router /openapi/workflows/product/task [post]
 func OpenAPICreateProductWorkflowTask(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -285,6 +353,15 @@ func OpenAPICreateProductWorkflowTask(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.OpenAPICreateProductWorkflowTask(ctx.UserName, args, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Custom Workflow V4
+// @Description OpenAPI Delete Custom Workflow V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	workflowKey	query	string	false	"workflow key"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/workflows/custom [delete]
 func OpenAPIDeleteCustomWorkflowV4(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -320,6 +397,15 @@ func OpenAPIDeleteCustomWorkflowV4(c *gin.Context) {
 	ctx.Err = workflowservice.OpenAPIDeleteCustomWorkflowV4(workflowKey, projectKey, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Custom Workflow V4
+// @Description OpenAPI Get Custom Workflow V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	workflowservice.OpenAPIWorkflowV4Detail
+// @Router /openapi/workflows/custom/{name}/detail [get]
 func OpenAPIGetCustomWorkflowV4(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -338,6 +424,15 @@ func OpenAPIGetCustomWorkflowV4(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.OpenAPIGetCustomWorkflowV4(workflowName, projectName, ctx.Logger)
 }
 
+// @Summary OpenAPI Delete Product Workflow V4
+// @Description OpenAPI Delete Product Workflow V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	workflowKey	query	string	false	"workflow key"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/workflows/product [delete]
 func OpenAPIDeleteProductWorkflowV4(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -373,6 +468,17 @@ func OpenAPIDeleteProductWorkflowV4(c *gin.Context) {
 	ctx.Err = workflowservice.OpenAPIDeleteProductWorkflowV4(workflowKey, ctx.RequestID, ctx.RequestID, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Product Workflow Tasks V4
+// @Description OpenAPI Get Product Workflow Tasks V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	pageNum	query	int	false	"page num"
+// @Param 	pageSize	query	int	false	"page size"
+// @Success 200 	{array} 	workflowservice.OpenAPIProductWorkflowTaskBrief
+// @Router /openapi/workflows/product/{name}/tasks [get]
 func OpenAPIGetProductWorkflowTasksV4(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -398,6 +504,16 @@ func OpenAPIGetProductWorkflowTasksV4(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.OpenAPIGetProductWorkflowTasksV4(projectKey, workflowKey, args.PageNum, args.PageSize, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Product Workflow Task V4
+// @Description OpenAPI Get Product Workflow Task V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	taskID	path	string	true	"task id"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	workflowservice.OpenAPIProductWorkflowTaskDetail
+// @Router /openapi/workflows/product/{name}/task/{taskID} [get]
 func OpenAPIGetProductWorkflowTaskV4(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -421,6 +537,15 @@ func OpenAPIGetProductWorkflowTaskV4(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.OpenAPIGetProductWorkflowTaskV4(projectKey, workflowName, taskID, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Workflow V4 List
+// @Description OpenAPI Get Workflow V4 List
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	viewName	query	string	false	"view name"
+// @Success 200 	{object} 	workflowservice.OpenAPIWorkflowListResp
+// @Router /openapi/workflows [get]
 func OpenAPIGetWorkflowV4List(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -439,6 +564,16 @@ func OpenAPIGetWorkflowV4List(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.OpenAPIGetCustomWorkflowV4List(args, ctx.Logger)
 }
 
+// @Summary OpenAPI Retry Custom Workflow Task V4
+// @Description OpenAPI Retry Custom Workflow Task V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	taskID	path	string	true	"task id"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/workflows/custom/{name}/task/{taskID} [post]
 func OpenAPIRetryCustomWorkflowTaskV4(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -453,6 +588,17 @@ func OpenAPIRetryCustomWorkflowTaskV4(c *gin.Context) {
 	ctx.Err = workflowservice.OpenAPIRetryCustomWorkflowTaskV4(name, c.Query("projectKey"), taskID, ctx.Logger)
 }
 
+// @Summary OpenAPI Get Custom Workflow Task V4
+// @Description OpenAPI Get Custom Workflow Task V4
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	name	path	string	true	"name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	pageNum	query	int	false	"page num"
+// @Param 	pageSize	query	int	false	"page size"
+// @Success 200 	{object} 	workflowservice.OpenAPIWorkflowV4TaskListResp
+// @Router /openapi/workflows/custom/{name}/tasks [get]
 func OpenAPIGetCustomWorkflowTaskV4(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -473,6 +619,14 @@ func OpenAPIGetCustomWorkflowTaskV4(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflowservice.OpenAPIGetCustomWorkflowTaskV4(workflowKey, args.ProjectKey, args.PageNum, args.PageSize, ctx.Logger)
 }
 
+// @Summary OpenAPI Approve Stage
+// @Description OpenAPI Approve Stage
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	workflowservice.OpenAPIApproveRequest	true	"body"
+// @Success 200
+// @Router /openapi/workflows/custom/task/approve [post]
 func OpenAPIApproveStage(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -178,6 +178,10 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		workflowV4.POST("/output/:jobName", GetWorkflowGlobalVars)
 		workflowV4.POST("/repo/:jobName", GetWorkflowRepoIndex)
 		workflowV4.GET("/name/:name", FindWorkflowV4)
+		workflowV4.POST("/references/check", CheckWorkflowV4JobReferences)
+		workflowV4.GET("/name/:name/references", GetWorkflowV4JobReferences)
+		workflowV4.POST("/name/:name/references/repair", RepairWorkflowV4JobReferences)
+		workflowV4.POST("/variables/explain", ExplainWorkflowV4Variables)
 		workflowV4.PUT("/:name", UpdateWorkflowV4)
 		workflowV4.DELETE("/:name", DeleteWorkflowV4)
 		workflowV4.GET("/preset/:name", GetWorkflowV4Preset)
@@ -202,11 +206,24 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		workflowV4.PUT("/generalhook/:workflowName", UpdateGeneralHookForWorkflowV4)
 		workflowV4.DELETE("/generalhook/:workflowName/:hookName", DeleteGeneralHookForWorkflowV4)
 		workflowV4.POST("/generalhook/:workflowName/:hookName/webhook", GeneralHookEventHandler)
+		workflowV4.GET("/registryhook/:workflowName", ListRegistryHookForWorkflowV4)
+		workflowV4.POST("/registryhook/:workflowName", CreateRegistryHookForWorkflowV4)
+		workflowV4.PUT("/registryhook/:workflowName", UpdateRegistryHookForWorkflowV4)
+		workflowV4.DELETE("/registryhook/:workflowName/:hookName", DeleteRegistryHookForWorkflowV4)
+		workflowV4.POST("/registryhook/:workflowName/:hookName/webhook", RegistryHookEventHandler)
 		workflowV4.GET("/cron/preset", GetCronForWorkflowV4Preset)
 		workflowV4.GET("/cron", ListCronForWorkflowV4)
 		workflowV4.POST("/cron/:workflowName", CreateCronForWorkflowV4)
 		workflowV4.PUT("/cron", UpdateCronForWorkflowV4)
 		workflowV4.DELETE("/cron/:workflowName/trigger/:cronID", DeleteCronForWorkflowV4)
+		workflowV4.GET("/cron/:workflowName/fire_times", ListCronFireTimesForWorkflowV4)
+		workflowV4.PUT("/cron/:workflowName/trigger/:cronID/pause", PauseCronForWorkflowV4)
+		workflowV4.PUT("/cron/:workflowName/trigger/:cronID/resume", ResumeCronForWorkflowV4)
+		workflowV4.PUT("/cron/:workflowName/pause", PauseAllCronsForWorkflowV4)
+		workflowV4.PUT("/cron/:workflowName/resume", ResumeAllCronsForWorkflowV4)
+		workflowV4.PUT("/crons/pause", PauseAllCronsForProject)
+		workflowV4.PUT("/crons/resume", ResumeAllCronsForProject)
+		workflowV4.GET("/crons/upcoming", ListUpcomingCronRunsForProject)
 		workflowV4.POST("/patch", GetPatchParams)
 		workflowV4.GET("/sharestorage", CheckShareStorageEnabled)
 		workflowV4.GET("/all", ListAllAvailableWorkflows)
@@ -228,12 +245,19 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		taskV4.POST("", CreateWorkflowTaskV4)
 		taskV4.GET("/filter/workflow/:name", GetWorkflowTaskFilters)
 		taskV4.GET("", ListWorkflowTaskV4ByFilter)
+		taskV4.GET("/outputs", ListJobOutputs)
 		taskV4.GET("/workflow/:workflowName/task/:taskID", GetWorkflowTaskV4)
+		taskV4.GET("/workflow/:workflowName/task/:taskID/report", ExportWorkflowTaskReport)
+		taskV4.GET("/workflow/:workflowName/task/:taskID/imagescan", ListWorkflowTaskImageScanResults)
+		taskV4.GET("/workflow/:workflowName/task/:taskID/notifications", ListWorkflowTaskNotifications)
+		taskV4.POST("/workflow/:workflowName/task/:taskID/release_notes", DraftWorkflowTaskReleaseNotes)
 		taskV4.DELETE("/workflow/:workflowName/task/:taskID", CancelWorkflowTaskV4)
 		taskV4.GET("/clone/workflow/:workflowName/task/:taskID", CloneWorkflowTaskV4)
 		taskV4.POST("/retry/workflow/:workflowName/task/:taskID", RetryWorkflowTaskV4)
+		taskV4.POST("/:workflowName/:taskID/jobs/:jobName/retry", RetryWorkflowTaskV4Job)
 		taskV4.POST("/manualexec/workflow/:workflowName/task/:taskID", ManualExecWorkflowTaskV4)
 		taskV4.GET("/manualexec/workflow/:workflowName/task/:taskID", GetManualExecWorkflowTaskV4Info)
+		taskV4.POST("/resume/workflow/:workflowName/task/:taskID", ResumeWorkflowTaskV4Stage)
 		taskV4.POST("/breakpoint/:workflowName/:jobName/task/:taskID/:position", SetWorkflowTaskV4Breakpoint)
 		taskV4.POST("/debug/:workflowName/task/:taskID", EnableDebugWorkflowTaskV4)
 		taskV4.DELETE("/debug/:workflowName/:jobName/task/:taskID/:position", StopDebugWorkflowTaskJobV4)
@@ -241,6 +265,8 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		taskV4.POST("/handle/error", HandleJobError)
 		taskV4.GET("/workflow/:workflowName/taskId/:taskId/job/:jobName", GetWorkflowV4ArtifactFileContent)
 		taskV4.GET("/workflow/:workflowName/taskId/:taskId/job/:jobName/build", GetWorkflowV4BuildJobArtifactFile)
+		taskV4.GET("/workflow/:workflowName/task/:taskID/job/:jobName/htmlreports", ListWorkflowTaskHTMLReports)
+		taskV4.GET("/workflow/:workflowName/task/:taskID/job/:jobName/htmlreports/:reportName/*filePath", GetWorkflowTaskHTMLReportFile)
 		taskV4.POST("/trigger", CreateWorkflowTaskV4ByBuildInTrigger)
 	}
 
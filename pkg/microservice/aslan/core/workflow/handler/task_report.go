@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// @Summary Export Workflow Task Report
+// @Description Render the execution report of a workflow task including stages, jobs, approvals, artifacts and test summaries
+// @Tags 	workflow
+// @Accept 	json
+// @Produce html
+// @Param 	workflowName	path		string		true	"workflow name"
+// @Param 	taskID			path		string		true	"task id"
+// @Param 	format			query		string		false	"report format, html or pdf, default is html"
+// @Param 	download		query		bool		false	"download the report as an attachment"
+// @Success 200
+// @Router /api/aslan/workflow/v4/workflowtask/workflow/{workflowName}/task/{taskID}/report [get]
+func ExportWorkflowTaskReport(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+
+	workflowName := c.Param("workflowName")
+	w, err := workflow.FindWorkflowV4Raw(workflowName, ctx.Logger)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	format := workflow.TaskReportFormat(c.DefaultQuery("format", string(workflow.TaskReportFormatHTML)))
+	data, contentType, err := workflow.RenderWorkflowTaskReport(workflowName, taskID, format, ctx.Logger)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+
+	if c.Query("download") == "true" || format == workflow.TaskReportFormatPDF {
+		c.Writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%d.%s"`, workflowName, taskID, format))
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -215,6 +215,227 @@ func GetWorkflowTaskV4(c *gin.Context) {
 	ctx.Resp, ctx.Err = workflow.GetWorkflowTaskV4(workflowName, taskID, ctx.Logger)
 }
 
+// @Summary List Image Scan Results of Workflow Task
+// @Description List the vulnerability counts of the images scanned by the image scan jobs of the workflow task
+// @Tags 	workflow
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	taskID			path		int								true	"workflow task id"
+// @Success 200 			{array} 	commonmodels.ImageScanResult
+// @Router /api/aslan/workflow/v4/workflowtask/workflow/{workflowName}/task/{taskID}/imagescan [get]
+func ListWorkflowTaskImageScanResults(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+
+	workflowName := c.Param("workflowName")
+
+	w, err := workflow.FindWorkflowV4Raw(workflowName, ctx.Logger)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.ListWorkflowTaskImageScanResults(workflowName, taskID, ctx.Logger)
+}
+
+// @Summary List Job Outputs
+// @Description List the historical outputs of the jobs, e.g. IMAGE and PKG_FILE, by workflow, service and time range
+// @Tags 	workflow
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Param 	workflowName	query		string		false	"workflow name"
+// @Param 	taskID			query		int			false	"workflow task id"
+// @Param 	jobName			query		string		false	"job name"
+// @Param 	serviceName		query		string		false	"service name"
+// @Param 	serviceModule	query		string		false	"service module"
+// @Param 	outputName		query		string		false	"output name, e.g. IMAGE"
+// @Param 	startTime		query		int			false	"start of the time range, unix timestamp"
+// @Param 	endTime			query		int			false	"end of the time range, unix timestamp"
+// @Param 	pageNum			query		int			false	"page num"
+// @Param 	pageSize		query		int			false	"page size"
+// @Success 200 			{object} 	workflow.JobOutputListResp
+// @Router /api/aslan/workflow/v4/workflowtask/outputs [get]
+func ListJobOutputs(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	filter := &workflow.JobOutputFilter{}
+	if err := c.ShouldBindQuery(filter); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	if filter.ProjectName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName can not be empty")
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[filter.ProjectName]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[filter.ProjectName].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[filter.ProjectName].Workflow.View {
+			if filter.WorkflowName == "" {
+				ctx.UnAuthorized = true
+				return
+			}
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, filter.ProjectName, types.ResourceTypeWorkflow, filter.WorkflowName, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.ListJobOutputs(filter, ctx.Logger)
+}
+
+// @Summary List Notification Deliveries of Workflow Task
+// @Description List the delivery status of the notifications sent for the workflow task
+// @Tags 	workflow
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	taskID			path		int								true	"workflow task id"
+// @Success 200 			{array} 	commonmodels.NotificationDelivery
+// @Router /api/aslan/workflow/v4/workflowtask/workflow/{workflowName}/task/{taskID}/notifications [get]
+func ListWorkflowTaskNotifications(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+
+	workflowName := c.Param("workflowName")
+
+	w, err := workflow.FindWorkflowV4Raw(workflowName, ctx.Logger)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.ListWorkflowTaskNotifications(workflowName, taskID, ctx.Logger)
+}
+
+// @Summary Draft Release Notes of Workflow Task
+// @Description Draft markdown release notes from the commits, pull requests and service changes of the workflow task with the default llm
+// @Tags 	workflow
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	taskID			path		int								true	"workflow task id"
+// @Success 200 			{object} 	service.ReleaseNotesDraft
+// @Router /api/aslan/workflow/v4/workflowtask/workflow/{workflowName}/task/{taskID}/release_notes [post]
+func DraftWorkflowTaskReleaseNotes(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+
+	workflowName := c.Param("workflowName")
+
+	w, err := workflow.FindWorkflowV4Raw(workflowName, ctx.Logger)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.DraftWorkflowTaskReleaseNotes(workflowName, taskID, ctx.Logger)
+}
+
 func CancelWorkflowTaskV4(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -385,6 +606,61 @@ func RetryWorkflowTaskV4(c *gin.Context) {
 	ctx.Err = workflow.RetryWorkflowTaskV4(workflowName, taskID, ctx.Logger)
 }
 
+// @Summary Retry Workflow Task V4 Job
+// @Description Retry a failed job of the workflow task, the passed jobs are not run again. The other failed jobs in the same stage are retried as well.
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Param 	workflowName	path		string		true	"workflow name"
+// @Param 	taskID			path		string		true	"workflow task ID"
+// @Param 	jobName			path		string		true	"job name, or the key of a single job task"
+// @Param 	downstream		query		bool		false	"whether to run the jobs in the following stages"
+// @Success 200
+// @Router /api/aslan/workflow/v4/workflowtask/{workflowName}/{taskID}/jobs/{jobName}/retry [post]
+func RetryWorkflowTaskV4Job(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	workflowName := c.Param("workflowName")
+	jobName := c.Param("jobName")
+
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+	downstream := c.Query("downstream") == "true"
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "重试", "自定义工作流任务", fmt.Sprintf("%s-%d-%s", workflowName, taskID, jobName), "", ctx.Logger)
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].Workflow.Execute {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeWorkflow, workflowName, types.WorkflowActionRun)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Err = workflow.RetryWorkflowTaskJobV4(workflowName, taskID, jobName, downstream, ctx.Logger)
+}
+
 // @Summary Manually Execute Workflow Task V4
 // @Description Manually Execute Workflow Task V4
 // @Tags 	workflow
@@ -447,6 +723,59 @@ func ManualExecWorkflowTaskV4(c *gin.Context) {
 	ctx.Err = workflow.ManualExecWorkflowTaskV4(workflowName, taskID, stageName, args.Jobs, ctx.UserID, ctx.UserName, ctx.Logger)
 }
 
+// @Summary Resume Paused Workflow Task V4 Stage
+// @Description Resume the workflow task which is paused after the stage
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Param 	workflowName	path		string		true	"workflow name"
+// @Param 	taskID			path		string		true	"workflow task ID"
+// @Param 	stageName		query		string		true	"workflow stage name"
+// @Success 200
+// @Router /api/aslan/workflow/v4/workflowtask/resume/workflow/{workflowName}/task/{taskID} [post]
+func ResumeWorkflowTaskV4Stage(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	workflowName := c.Param("workflowName")
+	stageName := c.Query("stageName")
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid task id")
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].Workflow.Execute {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, projectKey, types.ResourceTypeWorkflow, workflowName, types.WorkflowActionRun)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "继续执行", "自定义工作流任务", fmt.Sprintf("%s-%d-%s", workflowName, taskID, stageName), "", ctx.Logger)
+
+	ctx.Err = workflow.ResumeWorkflowTaskV4Stage(workflowName, taskID, stageName, ctx.UserID, ctx.UserName, ctx.Logger)
+}
+
 func SetWorkflowTaskV4Breakpoint(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
@@ -31,6 +32,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/types"
 
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
 	"github.com/koderover/zadig/v2/pkg/tool/errors"
@@ -43,6 +45,8 @@ type listWorkflowV4Query struct {
 	PageNum  int64  `json:"page_num"     form:"page_num,default=1"`
 	Project  string `json:"project"      form:"project"`
 	ViewName string `json:"view_name"    form:"view_name"`
+	// LabelSelector filters the workflows by labels, e.g. "tier=backend,owner in (alice,bob)"
+	LabelSelector string `json:"label_selector" form:"labelSelector"`
 }
 
 type filterDeployServiceVarsQuery struct {
@@ -105,6 +109,17 @@ func CreateWorkflowV4(c *gin.Context) {
 			return
 		}
 	}
+	if err := workflow.CheckWorkflowRunAsPermission(ctx.Resources, args); err != nil {
+		ctx.Err = err
+		return
+	}
+
+	owner, err := commonservice.EnsureResourceOwner(args.Owner, ctx.UserID)
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	args.Owner = owner
 
 	if err := workflow.CreateWorkflowV4(ctx.UserName, args, ctx.Logger); err != nil {
 		ctx.Err = err
@@ -252,6 +267,9 @@ func ListWorkflowV4(c *gin.Context) {
 	}
 
 	workflowList, err := workflow.ListWorkflowV4(args.Project, args.ViewName, ctx.UserID, authorizedWorkflow, authorizedWorkflowV4, enableFilter, ctx.Logger)
+	if err == nil && args.LabelSelector != "" {
+		workflowList, err = workflow.FilterWorkflowsByLabelSelector(args.Project, args.LabelSelector, workflowList)
+	}
 	resp := listWorkflowV4Resp{
 		WorkflowList: workflowList,
 		Total:        int64(len(workflowList)),
@@ -312,6 +330,10 @@ func UpdateWorkflowV4(c *gin.Context) {
 			}
 		}
 	}
+	if err := workflow.CheckWorkflowRunAsPermission(ctx.Resources, args); err != nil {
+		ctx.Err = err
+		return
+	}
 
 	ctx.Err = workflow.UpdateWorkflowV4(c.Param("name"), ctx.UserName, args, ctx.Logger)
 }
@@ -1001,7 +1023,12 @@ func DeleteGeneralHookForWorkflowV4(c *gin.Context) {
 func GeneralHookEventHandler(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
-	ctx.Err = workflow.GeneralHookEventHandler(c.Param("workflowName"), c.Param("hookName"), ctx.Logger)
+	payload, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+	ctx.Err = workflow.GeneralHookEventHandler(c.Param("workflowName"), c.Param("hookName"), payload, ctx.Logger)
 }
 
 func GetCronForWorkflowV4Preset(c *gin.Context) {
@@ -1142,6 +1169,280 @@ func DeleteCronForWorkflowV4(c *gin.Context) {
 	ctx.Err = workflow.DeleteCronForWorkflowV4(c.Param("workflowName"), c.Param("cronID"), ctx.Logger)
 }
 
+// @Summary List Cron Fire Times For Workflow V4
+// @Description List the next fire times of every cron of the workflow
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	count			query		int								false	"fire times count of every cron"
+// @Success 200 			{array} 	workflow.CronFireTimes
+// @Router /api/aslan/workflow/v4/cron/{workflowName}/fire_times [get]
+func ListCronFireTimesForWorkflowV4(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	count := 0
+	if c.Query("count") != "" {
+		var err error
+		count, err = strconv.Atoi(c.Query("count"))
+		if err != nil {
+			ctx.Err = e.ErrInvalidParam.AddDesc("invalid count")
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.ListCronFireTimesForWorkflowV4(c.Param("workflowName"), count, ctx.Logger)
+}
+
+// @Summary Pause Cron For Workflow V4
+// @Description Pause a single cron of the workflow
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string		true	"workflow name"
+// @Param 	cronID			path		string		true	"cron id"
+// @Success 200
+// @Router /api/aslan/workflow/v4/cron/{workflowName}/trigger/{cronID}/pause [put]
+func PauseCronForWorkflowV4(c *gin.Context) {
+	setCronEnabledForWorkflowV4(c, false)
+}
+
+// @Summary Resume Cron For Workflow V4
+// @Description Resume a paused cron of the workflow
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string		true	"workflow name"
+// @Param 	cronID			path		string		true	"cron id"
+// @Success 200
+// @Router /api/aslan/workflow/v4/cron/{workflowName}/trigger/{cronID}/resume [put]
+func ResumeCronForWorkflowV4(c *gin.Context) {
+	setCronEnabledForWorkflowV4(c, true)
+}
+
+func setCronEnabledForWorkflowV4(c *gin.Context, enabled bool) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	w, err := workflow.FindWorkflowV4Raw(c.Param("workflowName"), ctx.Logger)
+	if err != nil {
+		ctx.Logger.Errorf("setCronEnabledForWorkflowV4 error: %v", err)
+		ctx.Err = e.ErrUpsertCronjob.AddErr(err)
+		return
+	}
+	action := "暂停"
+	if enabled {
+		action = "恢复"
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, w.Project, action, "自定义工作流-cron", fmt.Sprintf("%s-%s", w.Name, c.Param("cronID")), "", ctx.Logger)
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.Edit {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionEdit)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Err = workflow.SetCronEnabledForWorkflowV4(c.Param("workflowName"), c.Param("cronID"), enabled, ctx.Logger)
+}
+
+// @Summary Pause All Crons For Workflow V4
+// @Description Pause all the enabled crons of the workflow
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string		true	"workflow name"
+// @Success 200 			{object} 	workflow.BulkSetCronsResp
+// @Router /api/aslan/workflow/v4/cron/{workflowName}/pause [put]
+func PauseAllCronsForWorkflowV4(c *gin.Context) {
+	setAllCronsEnabledForWorkflowV4(c, false)
+}
+
+// @Summary Resume All Crons For Workflow V4
+// @Description Resume the crons of the workflow paused by the bulk pause
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string		true	"workflow name"
+// @Success 200 			{object} 	workflow.BulkSetCronsResp
+// @Router /api/aslan/workflow/v4/cron/{workflowName}/resume [put]
+func ResumeAllCronsForWorkflowV4(c *gin.Context) {
+	setAllCronsEnabledForWorkflowV4(c, true)
+}
+
+func setAllCronsEnabledForWorkflowV4(c *gin.Context, enabled bool) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	w, err := workflow.FindWorkflowV4Raw(c.Param("workflowName"), ctx.Logger)
+	if err != nil {
+		ctx.Logger.Errorf("setAllCronsEnabledForWorkflowV4 error: %v", err)
+		ctx.Err = e.ErrUpsertCronjob.AddErr(err)
+		return
+	}
+	action := "暂停"
+	if enabled {
+		action = "恢复"
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, w.Project, action, "自定义工作流-cron", w.Name, "", ctx.Logger)
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.Edit {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionEdit)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.SetAllCronsEnabledForWorkflowV4(w.Name, enabled, ctx.Logger)
+}
+
+// @Summary Pause All Crons For Project
+// @Description Pause all the enabled crons of the workflows in the project, e.g. during the incident freeze
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Success 200 			{object} 	workflow.BulkSetCronsResp
+// @Router /api/aslan/workflow/v4/crons/pause [put]
+func PauseAllCronsForProject(c *gin.Context) {
+	setAllCronsEnabledForProject(c, false)
+}
+
+// @Summary Resume All Crons For Project
+// @Description Resume the crons of the workflows in the project paused by the bulk pause
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Success 200 			{object} 	workflow.BulkSetCronsResp
+// @Router /api/aslan/workflow/v4/crons/resume [put]
+func ResumeAllCronsForProject(c *gin.Context) {
+	setAllCronsEnabledForProject(c, true)
+}
+
+func setAllCronsEnabledForProject(c *gin.Context, enabled bool) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName is required")
+		return
+	}
+	action := "暂停"
+	if enabled {
+		action = "恢复"
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, action, "自定义工作流-cron", "全部", "", ctx.Logger)
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.SetAllCronsEnabledForProject(projectKey, enabled, ctx.Logger)
+}
+
+// @Summary List Upcoming Cron Runs For Project
+// @Description List the next scheduled runs of the workflows in the project ordered by time
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	projectName		query		string		true	"project name"
+// @Param 	count			query		int			false	"max count of the runs"
+// @Success 200 			{array} 	workflow.UpcomingCronRun
+// @Router /api/aslan/workflow/v4/crons/upcoming [get]
+func ListUpcomingCronRunsForProject(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectName")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectName is required")
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].Workflow.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	count := 0
+	if c.Query("count") != "" {
+		count, err = strconv.Atoi(c.Query("count"))
+		if err != nil {
+			ctx.Err = e.ErrInvalidParam.AddDesc("invalid count")
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.ListUpcomingCronRunsForProject(projectKey, count, ctx.Logger)
+}
+
 func GetPatchParams(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow"
+	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// @Summary Create Registry Hook For Workflow V4
+// @Description Create a trigger which runs the workflow when a new image tag is pushed to the registry
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	body 			body 		commonmodels.RegistryHook 		true 	"body"
+// @Success 200
+// @Router /api/aslan/workflow/v4/registryhook/{workflowName} [post]
+func CreateRegistryHookForWorkflowV4(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	hook := new(commonmodels.RegistryHook)
+	if err := c.ShouldBindJSON(hook); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+	w, err := workflow.FindWorkflowV4Raw(c.Param("workflowName"), ctx.Logger)
+	if err != nil {
+		ctx.Logger.Errorf("CreateRegistryHookForWorkflowV4 error: %v", err)
+		ctx.Err = e.ErrCreateRegistryHook.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, w.Project, "新建", "自定义工作流-registryhook", w.Name, hook.Name, ctx.Logger)
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.Edit {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionEdit)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Err = workflow.CreateRegistryHookForWorkflowV4(c.Param("workflowName"), hook, ctx.Logger)
+}
+
+// @Summary List Registry Hooks For Workflow V4
+// @Description List Registry Hooks For Workflow V4
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Success 200 			{array} 	commonmodels.RegistryHook
+// @Router /api/aslan/workflow/v4/registryhook/{workflowName} [get]
+func ListRegistryHookForWorkflowV4(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	w, err := workflow.FindWorkflowV4Raw(c.Param("workflowName"), ctx.Logger)
+	if err != nil {
+		ctx.Logger.Errorf("ListRegistryHookForWorkflowV4 error: %v", err)
+		ctx.Err = e.ErrListRegistryHook.AddErr(err)
+		return
+	}
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.View {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionView)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Resp, ctx.Err = workflow.ListRegistryHookForWorkflowV4(c.Param("workflowName"), ctx.Logger)
+}
+
+// @Summary Update Registry Hook For Workflow V4
+// @Description Update Registry Hook For Workflow V4
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	body 			body 		commonmodels.RegistryHook 		true 	"body"
+// @Success 200
+// @Router /api/aslan/workflow/v4/registryhook/{workflowName} [put]
+func UpdateRegistryHookForWorkflowV4(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	hook := new(commonmodels.RegistryHook)
+	if err := c.ShouldBindJSON(hook); err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+	w, err := workflow.FindWorkflowV4Raw(c.Param("workflowName"), ctx.Logger)
+	if err != nil {
+		ctx.Logger.Errorf("UpdateRegistryHookForWorkflowV4 error: %v", err)
+		ctx.Err = e.ErrUpdateRegistryHook.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, w.Project, "更新", "自定义工作流-registryhook", w.Name, hook.Name, ctx.Logger)
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.Edit {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionEdit)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Err = workflow.UpdateRegistryHookForWorkflowV4(c.Param("workflowName"), hook, ctx.Logger)
+}
+
+// @Summary Delete Registry Hook For Workflow V4
+// @Description Delete Registry Hook For Workflow V4
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	hookName		path		string							true	"hook name"
+// @Success 200
+// @Router /api/aslan/workflow/v4/registryhook/{workflowName}/{hookName} [delete]
+func DeleteRegistryHookForWorkflowV4(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	w, err := workflow.FindWorkflowV4Raw(c.Param("workflowName"), ctx.Logger)
+	if err != nil {
+		ctx.Logger.Errorf("DeleteRegistryHookForWorkflowV4 error: %v", err)
+		ctx.Err = e.ErrDeleteRegistryHook.AddErr(err)
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, w.Project, "删除", "自定义工作流-registryhook", w.Name, c.Param("hookName"), ctx.Logger)
+
+	// authorization check
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[w.Project]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[w.Project].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[w.Project].Workflow.Edit {
+			// check if the permission is given by collaboration mode
+			permitted, err := internalhandler.GetCollaborationModePermission(ctx.UserID, w.Project, types.ResourceTypeWorkflow, w.Name, types.WorkflowActionEdit)
+			if err != nil || !permitted {
+				ctx.UnAuthorized = true
+				return
+			}
+		}
+	}
+
+	ctx.Err = workflow.DeleteRegistryHookForWorkflowV4(c.Param("workflowName"), c.Param("hookName"), ctx.Logger)
+}
+
+// @Summary Registry Hook Event Handler
+// @Description Receive the push event of Harbor, ACR or ECR and run the workflow with the pushed images as deploy targets.
+// @Description The hook secret is read from the token query or the Authorization header.
+// @Tags 	workflow
+// @Accept 	json
+// @Produce json
+// @Param 	workflowName	path		string							true	"workflow name"
+// @Param 	hookName		path		string							true	"hook name"
+// @Param 	token			query		string							false	"hook secret"
+// @Success 200 			{object} 	workflow.CreateTaskV4Resp
+// @Router /api/aslan/workflow/v4/registryhook/{workflowName}/{hookName}/webhook [post]
+func RegistryHookEventHandler(c *gin.Context) {
+	ctx := internalhandler.NewContext(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	body, err := c.GetRawData()
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddErr(err)
+		return
+	}
+
+	token := c.Query("token")
+	if token == "" {
+		token = c.GetHeader("Authorization")
+	}
+
+	ctx.Resp, ctx.Err = workflow.RegistryHookEventHandler(c.Param("workflowName"), c.Param("hookName"), token, body, ctx.Logger)
+}
@@ -72,6 +72,7 @@ func (gpem *giteePushEventMatcherForWorkflowV4) Match(hookRepo *commonmodels.Mai
 			changedFiles = append(changedFiles, commit.Removed...)
 			changedFiles = append(changedFiles, commit.Modified...)
 		}
+		hookRepo.ChangedFiles = changedFiles
 		return MatchChanges(hookRepo, changedFiles), nil
 	}
 
@@ -124,6 +125,7 @@ func (gmem *giteeMergeEventMatcherForWorkflowV4) Match(hookRepo *commonmodels.Ma
 			}
 			gmem.log.Debugf("succeed to get %d changes in merge event", len(changedFiles))
 
+			hookRepo.ChangedFiles = changedFiles
 			return MatchChanges(hookRepo, changedFiles), nil
 		}
 	}
@@ -315,7 +317,7 @@ func TriggerWorkflowV4ByGiteeEvent(event interface{}, baseURI, requestID string,
 				mErr = multierror.Append(mErr, fmt.Errorf(errMsg))
 				continue
 			}
-			if err := job.MergeWebhookRepo(workflow, eventRepo); err != nil {
+			if err := job.MergeWebhookRepo(workflow, eventRepo, item.MainRepo.ChangedFiles); err != nil {
 				errMsg := fmt.Sprintf("merge webhook repo info to workflowargs error: %v", err)
 				log.Error(errMsg)
 				mErr = multierror.Append(mErr, fmt.Errorf(errMsg))
@@ -324,6 +326,9 @@ func TriggerWorkflowV4ByGiteeEvent(event interface{}, baseURI, requestID string,
 			if notification != nil {
 				workflow.NotificationID = notification.ID.Hex()
 			}
+			if hookPayload != nil {
+				hookPayload.ChangedFiles = len(item.MainRepo.ChangedFiles)
+			}
 			workflow.HookPayload = hookPayload
 			if resp, err := workflowservice.CreateWorkflowTaskV4(&workflowservice.CreateWorkflowTaskV4Args{
 				Name: setting.WebhookTaskCreator,
@@ -75,6 +75,7 @@ func (gpem *githubPushEventMatcheForWorkflowV4) Match(hookRepo *commonmodels.Mai
 		changedFiles = append(changedFiles, commit.Removed...)
 		changedFiles = append(changedFiles, commit.Modified...)
 	}
+	hookRepo.ChangedFiles = changedFiles
 	return MatchChanges(hookRepo, changedFiles), nil
 }
 
@@ -129,6 +130,7 @@ func (gmem *githubMergeEventMatcherForWorkflowV4) Match(hookRepo *commonmodels.M
 		}
 		gmem.log.Debugf("succeed to get %d changes in merge event", len(changedFiles))
 
+		hookRepo.ChangedFiles = changedFiles
 		return MatchChanges(hookRepo, changedFiles), nil
 	}
 
@@ -319,12 +321,15 @@ func TriggerWorkflowV4ByGithubEvent(event interface{}, baseURI, deliveryID, requ
 				mErr = multierror.Append(mErr, fmt.Errorf(errMsg))
 				continue
 			}
-			if err := job.MergeWebhookRepo(workflow, eventRepo); err != nil {
+			if err := job.MergeWebhookRepo(workflow, eventRepo, item.MainRepo.ChangedFiles); err != nil {
 				errMsg := fmt.Sprintf("merge webhook repo info to workflowargs error: %v", err)
 				log.Error(errMsg)
 				mErr = multierror.Append(mErr, fmt.Errorf(errMsg))
 				continue
 			}
+			if hookPayload != nil {
+				hookPayload.ChangedFiles = len(item.MainRepo.ChangedFiles)
+			}
 			workflow.HookPayload = hookPayload
 			if resp, err := workflowservice.CreateWorkflowTaskV4(&workflowservice.CreateWorkflowTaskV4Args{
 				Name: setting.WebhookTaskCreator,
@@ -94,6 +94,7 @@ func (gmem *gitlabMergeEventMatcherForWorkflowV4) Match(hookRepo *commonmodels.M
 			gmem.yamlServiceChanged = serviceChangeds
 			return len(serviceChangeds) != 0, nil
 		}
+		hookRepo.ChangedFiles = changedFiles
 		return MatchChanges(hookRepo, changedFiles), nil
 	}
 	return false, nil
@@ -219,6 +220,7 @@ func (gpem *gitlabPushEventMatcherForWorkflowV4) Match(hookRepo *commonmodels.Ma
 		gpem.yamlServiceChanged = serviceChangeds
 		return len(serviceChangeds) != 0, nil
 	}
+	hookRepo.ChangedFiles = changedFiles
 	return MatchChanges(hookRepo, changedFiles), nil
 }
 
@@ -405,7 +407,7 @@ func TriggerWorkflowV4ByGitlabEvent(event interface{}, baseURI, requestID string
 				mErr = multierror.Append(mErr, fmt.Errorf(errMsg))
 				continue
 			}
-			if err := job.MergeWebhookRepo(workflow, eventRepo); err != nil {
+			if err := job.MergeWebhookRepo(workflow, eventRepo, item.MainRepo.ChangedFiles); err != nil {
 				errMsg := fmt.Sprintf("merge webhook repo info to workflowargs error: %v", err)
 				log.Error(errMsg)
 				mErr = multierror.Append(mErr, fmt.Errorf(errMsg))
@@ -414,6 +416,9 @@ func TriggerWorkflowV4ByGitlabEvent(event interface{}, baseURI, requestID string
 			if notification != nil {
 				workflow.NotificationID = notification.ID.Hex()
 			}
+			if hookPayload != nil {
+				hookPayload.ChangedFiles = len(item.MainRepo.ChangedFiles)
+			}
 			workflow.HookPayload = hookPayload
 			if resp, err := workflowservice.CreateWorkflowTaskV4(&workflowservice.CreateWorkflowTaskV4Args{
 				Name: setting.WebhookTaskCreator,
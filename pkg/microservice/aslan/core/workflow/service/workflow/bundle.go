@@ -98,3 +98,52 @@ func GetBundleResources(logger *zap.SugaredLogger) ([]*resourceSpec, error) {
 	}
 	return res, nil
 }
+
+// FilterWorkflowsByLabelSelector filters the workflows of the project by the label selector.
+func FilterWorkflowsByLabelSelector(projectName, selector string, workflows []*Workflow) ([]*Workflow, error) {
+	workflowFilter, err := service.NewResourceFilter(config.ResourceTypeWorkflow, projectName, selector)
+	if err != nil {
+		return nil, err
+	}
+	customWorkflowFilter, err := service.NewResourceFilter(config.ResourceTypeCommonWorkflow, projectName, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*Workflow, 0)
+	for _, workflow := range workflows {
+		filter := workflowFilter
+		if config.IsCustomWorkflow(workflow.WorkflowType) {
+			filter = customWorkflowFilter
+		}
+		if filter.Matches(workflow.Name) {
+			resp = append(resp, workflow)
+		}
+	}
+	return resp, nil
+}
+
+// setTaskLabelFilter sets the ids of the tasks to be listed by the label selector, only the labeled tasks are
+// checked so that the tasks can still be paged in the database.
+func setTaskLabelFilter(opt *mongodb.WorkFlowTaskFilter, selector string) error {
+	filter, err := service.NewResourceFilter(config.ResourceTypeWorkflowTask, opt.ProjectName, selector)
+	if err != nil {
+		return err
+	}
+
+	matchesUnlabeled := filter.MatchesUnlabeled()
+	taskIDs := make([]int64, 0)
+	// the unmatched labeled tasks are excluded if the unlabeled tasks match, otherwise only the matched ones are included
+	for _, name := range filter.LabeledNames(!matchesUnlabeled) {
+		workflowName, taskID, ok := config.ParseTaskResourceName(name)
+		if ok && workflowName == opt.WorkflowName {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	if matchesUnlabeled {
+		opt.ExcludedTaskIDs = taskIDs
+	} else {
+		opt.TaskIDs = taskIDs
+	}
+	return nil
+}
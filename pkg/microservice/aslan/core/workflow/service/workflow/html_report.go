@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/s3"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	s3tool "github.com/koderover/zadig/v2/pkg/tool/s3"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+)
+
+type HTMLReport struct {
+	Name      string `json:"name"`
+	IndexFile string `json:"index_file"`
+}
+
+type HTMLReportFile struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64
+}
+
+// ListWorkflowTaskHTMLReports lists the html reports uploaded by the job, the reports are viewed by
+// GetWorkflowTaskHTMLReportFile with their index files.
+func ListWorkflowTaskHTMLReports(workflowName, jobName string, taskID int64, log *zap.SugaredLogger) ([]*HTMLReport, error) {
+	spec, err := getHTMLReportsStepSpec(workflowName, jobName, taskID)
+	if err != nil {
+		log.Errorf("failed to get html reports of job %s, error: %s", jobName, err)
+		return nil, e.ErrListHTMLReports.AddErr(err)
+	}
+
+	resp := make([]*HTMLReport, 0)
+	if spec == nil {
+		return resp, nil
+	}
+	for _, upload := range spec.UploadDetail {
+		resp = append(resp, &HTMLReport{Name: upload.Name, IndexFile: upload.IndexFile})
+	}
+	return resp, nil
+}
+
+// GetWorkflowTaskHTMLReportFile reads the file of the html report from the object storage it is uploaded to,
+// filePath is relative to the report so that the relative assets in the report are resolved by the browser.
+func GetWorkflowTaskHTMLReportFile(workflowName, jobName, reportName, filePath string, taskID int64, log *zap.SugaredLogger) (*HTMLReportFile, error) {
+	spec, err := getHTMLReportsStepSpec(workflowName, jobName, taskID)
+	if err != nil {
+		log.Errorf("failed to get html reports of job %s, error: %s", jobName, err)
+		return nil, e.ErrGetHTMLReportFile.AddErr(err)
+	}
+	if spec == nil {
+		return nil, e.ErrGetHTMLReportFile.AddDesc(fmt.Sprintf("job %s has no html report", jobName))
+	}
+
+	var report *step.Upload
+	for _, upload := range spec.UploadDetail {
+		if upload.Name == reportName {
+			report = upload
+			break
+		}
+	}
+	if report == nil {
+		return nil, e.ErrGetHTMLReportFile.AddDesc(fmt.Sprintf("html report %s not found", reportName))
+	}
+
+	// cleaning the path with a leading slash makes sure the file is inside the report
+	cleanPath := strings.TrimPrefix(path.Clean("/"+filePath), "/")
+	switch {
+	case cleanPath == "":
+		cleanPath = report.IndexFile
+	case strings.HasSuffix(filePath, "/"):
+		cleanPath = path.Join(cleanPath, "index.html")
+	}
+
+	store, err := getHTMLReportStorage(spec.ObjectStorageID)
+	if err != nil {
+		log.Errorf("failed to find the object storage of html report %s, error: %s", reportName, err)
+		return nil, e.ErrGetHTMLReportFile.AddErr(err)
+	}
+	forcedPathStyle := true
+	if store.Provider == setting.ProviderSourceAli {
+		forcedPathStyle = false
+	}
+	client, err := s3tool.NewClient(store.Endpoint, store.Ak, store.Sk, store.Region, store.Insecure, forcedPathStyle)
+	if err != nil {
+		log.Errorf("failed to create s3 client, error: %s", err)
+		return nil, e.ErrGetHTMLReportFile.AddErr(err)
+	}
+
+	object, err := client.GetFile(store.Bucket, store.GetObjectPath(path.Join(report.DestinationPath, cleanPath)), &s3tool.DownloadOption{RetryNum: 2, IgnoreNotExistError: true})
+	if err != nil {
+		log.Errorf("failed to get file %s of html report %s, error: %s", cleanPath, reportName, err)
+		return nil, e.ErrGetHTMLReportFile.AddErr(err)
+	}
+	if object == nil {
+		return nil, e.ErrGetHTMLReportFile.AddDesc(fmt.Sprintf("file %s not found in html report %s", cleanPath, reportName))
+	}
+
+	resp := &HTMLReportFile{
+		Body:          object.Body,
+		ContentType:   mime.TypeByExtension(path.Ext(cleanPath)),
+		ContentLength: -1,
+	}
+	if object.ContentLength != nil {
+		resp.ContentLength = *object.ContentLength
+	}
+	if resp.ContentType == "" && object.ContentType != nil {
+		resp.ContentType = *object.ContentType
+	}
+	if resp.ContentType == "" {
+		resp.ContentType = "application/octet-stream"
+	}
+	return resp, nil
+}
+
+func getHTMLReportsStepSpec(workflowName, jobName string, taskID int64) (*step.StepArchiveSpec, error) {
+	workflowTask, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find workflow task, workflow name: %s, task id: %d", workflowName, taskID)
+	}
+
+	var jobTask *commonmodels.JobTask
+	for _, stage := range workflowTask.Stages {
+		for _, job := range stage.Jobs {
+			if job.Name == jobName {
+				jobTask = job
+			}
+		}
+	}
+	if jobTask == nil {
+		return nil, fmt.Errorf("cannot find job task, workflow name: %s, task id: %d, job name: %s", workflowName, taskID, jobName)
+	}
+	if jobTask.JobType != string(config.JobZadigBuild) && jobTask.JobType != string(config.JobZadigTesting) {
+		return nil, nil
+	}
+
+	jobSpec := &commonmodels.JobTaskFreestyleSpec{}
+	if err := commonmodels.IToi(jobTask.Spec, jobSpec); err != nil {
+		return nil, fmt.Errorf("unmashal job spec error: %v", err)
+	}
+	for _, stepTask := range jobSpec.Steps {
+		if stepTask.Name != config.JobHTMLReportsStepName || stepTask.StepType != config.StepArchive {
+			continue
+		}
+		stepSpec := &step.StepArchiveSpec{}
+		if err := commonmodels.IToi(stepTask.Spec, stepSpec); err != nil {
+			return nil, fmt.Errorf("unmashal step spec error: %v", err)
+		}
+		return stepSpec, nil
+	}
+	return nil, nil
+}
+
+// getHTMLReportStorage returns the storage the reports are uploaded to, the default one is used for compatibility
+func getHTMLReportStorage(storageID string) (*s3.S3, error) {
+	if storageID == "" {
+		return s3.FindDefaultS3()
+	}
+	return s3.FindS3ById(storageID)
+}
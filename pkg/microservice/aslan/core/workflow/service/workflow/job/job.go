@@ -32,9 +32,11 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/types"
 	"github.com/koderover/zadig/v2/pkg/types/job"
+	"github.com/koderover/zadig/v2/pkg/types/step"
 )
 
 const (
@@ -98,6 +100,8 @@ func InitJobCtl(job *commonmodels.Job, workflow *commonmodels.WorkflowV4) (JobCt
 		resp = &K8sPacthJob{job: job, workflow: workflow}
 	case config.JobZadigScanning:
 		resp = &ScanningJob{job: job, workflow: workflow}
+	case config.JobZadigImageScan:
+		resp = &ImageScanJob{job: job, workflow: workflow}
 	case config.JobZadigDistributeImage:
 		resp = &ImageDistributeJob{job: job, workflow: workflow}
 	case config.JobIstioRelease:
@@ -134,6 +138,16 @@ func InitJobCtl(job *commonmodels.Job, workflow *commonmodels.WorkflowV4) (JobCt
 		resp = &BlueKingJob{job: job, workflow: workflow}
 	case config.JobApproval:
 		resp = &ApprovalJob{job: job, workflow: workflow}
+	case config.JobDragonflyPreheat:
+		resp = &DragonflyPreheatJob{job: job, workflow: workflow}
+	case config.JobArgoRollout:
+		resp = &ArgoRolloutJob{job: job, workflow: workflow}
+	case config.JobArgoCDSync:
+		resp = &ArgoCDSyncJob{job: job, workflow: workflow}
+	case config.JobFluxImageUpdate:
+		resp = &FluxImageUpdateJob{job: job, workflow: workflow}
+	case config.JobZadigRollback:
+		resp = &ZadigRollbackJob{job: job, workflow: workflow}
 	default:
 		return resp, fmt.Errorf("job type not found %s", job.JobType)
 	}
@@ -209,7 +223,23 @@ func ToJobs(job *commonmodels.Job, workflow *commonmodels.WorkflowV4, taskID int
 	if err != nil {
 		return []*commonmodels.JobTask{}, warpJobError(job.Name, err)
 	}
-	return jobCtl.ToJobs(taskID)
+	jobTasks, err := jobCtl.ToJobs(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, jobTask := range jobTasks {
+		jobTask.RetryPolicy = job.RetryPolicy
+		jobTask.JobTimeout = job.Timeout
+		if spec, ok := jobTask.Spec.(*commonmodels.JobTaskFreestyleSpec); ok {
+			if err := commonservice.ResolveSecretKeyVals(spec.Properties.Envs); err != nil {
+				return nil, warpJobError(job.Name, err)
+			}
+			if err := commonservice.ResolveSecretKeyVals(spec.Properties.CustomEnvs); err != nil {
+				return nil, warpJobError(job.Name, err)
+			}
+		}
+	}
+	return jobTasks, nil
 }
 
 func LintJob(job *commonmodels.Job, workflow *commonmodels.WorkflowV4) error {
@@ -217,15 +247,27 @@ func LintJob(job *commonmodels.Job, workflow *commonmodels.WorkflowV4) error {
 	if err != nil {
 		return warpJobError(job.Name, err)
 	}
+	if len(job.NotifyCtls) > 0 {
+		switch job.JobType {
+		case config.JobZadigBuild, config.JobZadigDeploy, config.JobZadigScanning:
+		default:
+			return warpJobError(job.Name, fmt.Errorf("job type %s does not support notification", job.JobType))
+		}
+	}
+	if err := lintJobRetryPolicy(job); err != nil {
+		return warpJobError(job.Name, err)
+	}
 	return jobCtl.LintJob()
 }
 
-func MergeWebhookRepo(workflow *commonmodels.WorkflowV4, repo *types.Repository) error {
+// MergeWebhookRepo merges the repo of the webhook event into the jobs, changedFiles is the files changed by the event,
+// it's nil if the changes are unknown.
+func MergeWebhookRepo(workflow *commonmodels.WorkflowV4, repo *types.Repository, changedFiles []string) error {
 	for _, stage := range workflow.Stages {
 		for _, job := range stage.Jobs {
 			if job.JobType == config.JobZadigBuild {
 				jobCtl := &BuildJob{job: job, workflow: workflow}
-				if err := jobCtl.MergeWebhookRepo(repo); err != nil {
+				if err := jobCtl.MergeWebhookRepo(repo, changedFiles); err != nil {
 					return warpJobError(job.Name, err)
 				}
 			}
@@ -274,6 +316,9 @@ func GetWorkflowOutputs(workflow *commonmodels.WorkflowV4, currentJobName string
 			case config.JobZadigScanning:
 				jobCtl := &ScanningJob{job: job, workflow: workflow}
 				resp = append(resp, jobCtl.GetOutPuts(log)...)
+			case config.JobZadigImageScan:
+				jobCtl := &ImageScanJob{job: job, workflow: workflow}
+				resp = append(resp, jobCtl.GetOutPuts(log)...)
 			case config.JobZadigDistributeImage:
 				jobCtl := &ImageDistributeJob{job: job, workflow: workflow}
 				resp = append(resp, jobCtl.GetOutPuts(log)...)
@@ -673,6 +718,43 @@ func outputScript(outputs []*commonmodels.Output, infrastructure string) []strin
 	return resp
 }
 
+// htmlReportsStep uploads the html reports to <workflow>/<task>/<job>/html-reports/<report name>, nil is returned
+// if there is no report.
+func htmlReportsStep(reports []*commonmodels.HTMLReport, workflowName string, taskID int64, jobTaskName string, storage *commonmodels.S3Storage) *commonmodels.StepTask {
+	if len(reports) == 0 {
+		return nil
+	}
+
+	uploads := make([]*step.Upload, 0, len(reports))
+	for _, report := range reports {
+		index := report.Index
+		if strings.HasSuffix(strings.ToLower(report.Path), ".html") || strings.HasSuffix(strings.ToLower(report.Path), ".htm") {
+			// a single html file is uploaded to the destination directory with its base name
+			index = path.Base(report.Path)
+		}
+		if index == "" {
+			index = "index.html"
+		}
+		uploads = append(uploads, &step.Upload{
+			Name:            report.Name,
+			FilePath:        report.Path,
+			DestinationPath: path.Join(workflowName, fmt.Sprint(taskID), jobTaskName, "html-reports", report.Name),
+			IndexFile:       path.Clean(index),
+		})
+	}
+	return &commonmodels.StepTask{
+		Name:      config.JobHTMLReportsStepName,
+		JobName:   jobTaskName,
+		StepType:  config.StepArchive,
+		Onfailure: true,
+		Spec: step.StepArchiveSpec{
+			UploadDetail:    uploads,
+			ObjectStorageID: storage.ID.Hex(),
+			S3:              modelS3toS3(storage),
+		},
+	}
+}
+
 func checkOutputNames(outputs []*commonmodels.Output) error {
 	for _, output := range outputs {
 		if match := OutputNameRegex.MatchString(output.Name); !match {
@@ -794,3 +876,27 @@ func findMatchedRepoFromParams(params []*commonmodels.Param, paramName string) (
 	}
 	return nil, fmt.Errorf("not found repo from params")
 }
+
+func lintJobRetryPolicy(job *commonmodels.Job) error {
+	if job.Timeout < 0 {
+		return fmt.Errorf("invalid job timeout: %d", job.Timeout)
+	}
+	policy := job.RetryPolicy
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAttempts < 0 || policy.Interval < 0 {
+		return fmt.Errorf("invalid retry policy, max attempts and interval can't be negative")
+	}
+	switch policy.Backoff {
+	case "", config.JobRetryBackoffFixed, config.JobRetryBackoffExponential:
+	default:
+		return fmt.Errorf("invalid retry backoff: %s", policy.Backoff)
+	}
+	for _, status := range policy.RetryOn {
+		if status != config.StatusFailed && status != config.StatusTimeout {
+			return fmt.Errorf("retry on status %s is not supported, only failed and timeout are supported", status)
+		}
+	}
+	return nil
+}
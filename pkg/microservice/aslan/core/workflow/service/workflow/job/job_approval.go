@@ -120,6 +120,8 @@ func (j *ApprovalJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 	if nativeApproval != nil {
 		approvalUser, _ := util.GeneFlatUsers(nativeApproval.ApproveUsers)
 		nativeApproval.ApproveUsers = approvalUser
+		nativeApproval.QuorumRules = util.GeneQuorumRules(nativeApproval.QuorumRules)
+		nativeApproval.Steps = util.GeneNativeApprovalSteps(nativeApproval.Steps)
 	}
 
 	resp := make([]*commonmodels.JobTask, 0)
@@ -167,6 +169,9 @@ func (j *ApprovalJob) LintJob() error {
 		if len(allApproveUsers) < j.spec.NativeApproval.NeededApprovers {
 			return fmt.Errorf("all approve users should not less than needed approvers")
 		}
+		if err := util.LintNativeApprovalQuorum(j.spec.NativeApproval); err != nil {
+			return err
+		}
 	case config.LarkApproval:
 		if j.spec.LarkApproval == nil {
 			return fmt.Errorf("approval not found")
@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+type ArgoRolloutJob struct {
+	job      *commonmodels.Job
+	workflow *commonmodels.WorkflowV4
+	spec     *commonmodels.ArgoRolloutJobSpec
+}
+
+func (j *ArgoRolloutJob) Instantiate() error {
+	j.spec = &commonmodels.ArgoRolloutJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ArgoRolloutJob) SetPreset() error {
+	j.spec = &commonmodels.ArgoRolloutJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ArgoRolloutJob) SetOptions() error {
+	return nil
+}
+
+func (j *ArgoRolloutJob) ClearSelectionField() error {
+	return nil
+}
+
+func (j *ArgoRolloutJob) UpdateWithLatestSetting() error {
+	j.spec = &commonmodels.ArgoRolloutJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	latestWorkflow, err := mongodb.NewWorkflowV4Coll().Find(j.workflow.Name)
+	if err != nil {
+		log.Errorf("Failed to find original workflow to set options, error: %s", err)
+		return err
+	}
+
+	latestSpec := new(commonmodels.ArgoRolloutJobSpec)
+	found := false
+	for _, stage := range latestWorkflow.Stages {
+		if !found {
+			for _, job := range stage.Jobs {
+				if job.Name == j.job.Name && job.JobType == j.job.JobType {
+					if err := commonmodels.IToi(job.Spec, latestSpec); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+		} else {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("failed to find the original workflow: %s", j.workflow.Name)
+	}
+
+	// images of the targets are allowed to be changed by user, others use the latest config
+	userImages := make(map[string]string)
+	for _, target := range j.spec.Targets {
+		for _, container := range target.Containers {
+			userImages[target.RolloutName+"/"+container.Name] = container.Image
+		}
+	}
+	for _, target := range latestSpec.Targets {
+		for _, container := range target.Containers {
+			if image, ok := userImages[target.RolloutName+"/"+container.Name]; ok {
+				container.Image = image
+			}
+		}
+	}
+
+	j.spec = latestSpec
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ArgoRolloutJob) MergeArgs(args *commonmodels.Job) error {
+	if j.job.Name == args.Name && j.job.JobType == args.JobType {
+		j.spec = &commonmodels.ArgoRolloutJobSpec{}
+		if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+			return err
+		}
+		argsSpec := &commonmodels.ArgoRolloutJobSpec{}
+		if err := commonmodels.IToi(args.Spec, argsSpec); err != nil {
+			return err
+		}
+		j.spec.Targets = argsSpec.Targets
+		j.job.Spec = j.spec
+	}
+	return nil
+}
+
+func (j *ArgoRolloutJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
+	j.spec = &commonmodels.ArgoRolloutJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return nil, err
+	}
+	j.job.Spec = j.spec
+
+	cluster, err := mongodb.NewK8SClusterColl().Get(j.spec.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cluster %s, error: %s", j.spec.ClusterID, err)
+	}
+
+	rollouts := make([]*commonmodels.ArgoRolloutTaskTarget, 0)
+	for _, target := range j.spec.Targets {
+		containers := make([]*commonmodels.ArgoRolloutContainer, 0)
+		if j.spec.Action == config.ArgoRolloutActionSetImage {
+			for _, container := range target.Containers {
+				containers = append(containers, &commonmodels.ArgoRolloutContainer{
+					Name:  container.Name,
+					Image: strings.TrimSpace(container.Image),
+				})
+			}
+		}
+		rollouts = append(rollouts, &commonmodels.ArgoRolloutTaskTarget{
+			RolloutName: target.RolloutName,
+			Containers:  containers,
+			Status:      config.StatusPrepare,
+		})
+	}
+
+	jobTask := &commonmodels.JobTask{
+		Name: j.job.Name,
+		JobInfo: map[string]string{
+			JobNameKey: j.job.Name,
+		},
+		Key:     j.job.Name,
+		JobType: string(config.JobArgoRollout),
+		Spec: &commonmodels.JobTaskArgoRolloutSpec{
+			ClusterID:   j.spec.ClusterID,
+			ClusterName: cluster.Name,
+			Namespace:   j.spec.Namespace,
+			Action:      j.spec.Action,
+			Full:        j.spec.Full,
+			Timeout:     j.spec.Timeout,
+			Rollouts:    rollouts,
+		},
+		Timeout:     j.spec.Timeout,
+		ErrorPolicy: j.job.ErrorPolicy,
+	}
+	return []*commonmodels.JobTask{jobTask}, nil
+}
+
+func (j *ArgoRolloutJob) LintJob() error {
+	j.spec = &commonmodels.ArgoRolloutJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	if j.spec.ClusterID == "" {
+		return fmt.Errorf("cluster is not set")
+	}
+	if j.spec.Namespace == "" {
+		return fmt.Errorf("namespace is not set")
+	}
+
+	switch j.spec.Action {
+	case config.ArgoRolloutActionSetImage, config.ArgoRolloutActionPromote, config.ArgoRolloutActionAbort:
+	default:
+		return fmt.Errorf("invalid argo rollout action: %s", j.spec.Action)
+	}
+
+	if len(j.spec.Targets) == 0 {
+		return fmt.Errorf("no rollout is selected")
+	}
+	for _, target := range j.spec.Targets {
+		if target.RolloutName == "" {
+			return fmt.Errorf("rollout name is empty")
+		}
+		if j.spec.Action != config.ArgoRolloutActionSetImage {
+			continue
+		}
+		if len(target.Containers) == 0 {
+			return fmt.Errorf("no container is selected for rollout %s", target.RolloutName)
+		}
+		for _, container := range target.Containers {
+			if container.Name == "" {
+				return fmt.Errorf("container name of rollout %s is empty", target.RolloutName)
+			}
+		}
+	}
+	return nil
+}
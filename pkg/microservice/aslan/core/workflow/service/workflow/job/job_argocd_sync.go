@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+type ArgoCDSyncJob struct {
+	job      *commonmodels.Job
+	workflow *commonmodels.WorkflowV4
+	spec     *commonmodels.ArgoCDSyncJobSpec
+}
+
+func (j *ArgoCDSyncJob) Instantiate() error {
+	j.spec = &commonmodels.ArgoCDSyncJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ArgoCDSyncJob) SetPreset() error {
+	j.spec = &commonmodels.ArgoCDSyncJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ArgoCDSyncJob) SetOptions() error {
+	return nil
+}
+
+func (j *ArgoCDSyncJob) ClearSelectionField() error {
+	return nil
+}
+
+func (j *ArgoCDSyncJob) UpdateWithLatestSetting() error {
+	j.spec = &commonmodels.ArgoCDSyncJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	latestWorkflow, err := mongodb.NewWorkflowV4Coll().Find(j.workflow.Name)
+	if err != nil {
+		log.Errorf("Failed to find original workflow to set options, error: %s", err)
+		return err
+	}
+
+	latestSpec := new(commonmodels.ArgoCDSyncJobSpec)
+	found := false
+	for _, stage := range latestWorkflow.Stages {
+		if !found {
+			for _, job := range stage.Jobs {
+				if job.Name == j.job.Name && job.JobType == j.job.JobType {
+					if err := commonmodels.IToi(job.Spec, latestSpec); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+		} else {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("failed to find the original workflow: %s", j.workflow.Name)
+	}
+
+	// revisions of the applications are allowed to be changed by user, others use the latest config
+	userRevisions := make(map[string]string)
+	for _, app := range j.spec.Applications {
+		userRevisions[app.ApplicationName] = app.Revision
+	}
+	for _, app := range latestSpec.Applications {
+		if revision, ok := userRevisions[app.ApplicationName]; ok {
+			app.Revision = revision
+		}
+	}
+
+	j.spec = latestSpec
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ArgoCDSyncJob) MergeArgs(args *commonmodels.Job) error {
+	if j.job.Name == args.Name && j.job.JobType == args.JobType {
+		j.spec = &commonmodels.ArgoCDSyncJobSpec{}
+		if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+			return err
+		}
+		argsSpec := &commonmodels.ArgoCDSyncJobSpec{}
+		if err := commonmodels.IToi(args.Spec, argsSpec); err != nil {
+			return err
+		}
+		j.spec.Applications = argsSpec.Applications
+		j.job.Spec = j.spec
+	}
+	return nil
+}
+
+func (j *ArgoCDSyncJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
+	j.spec = &commonmodels.ArgoCDSyncJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return nil, err
+	}
+	j.job.Spec = j.spec
+
+	argoCD, err := mongodb.NewArgoCDColl().GetByID(context.Background(), j.spec.ArgoCDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find argo cd %s, error: %s", j.spec.ArgoCDID, err)
+	}
+
+	applications := make([]*commonmodels.ArgoCDSyncTaskTarget, 0)
+	for _, app := range j.spec.Applications {
+		applications = append(applications, &commonmodels.ArgoCDSyncTaskTarget{
+			ApplicationName: app.ApplicationName,
+			Revision:        strings.TrimSpace(app.Revision),
+			Status:          config.StatusPrepare,
+		})
+	}
+
+	jobTask := &commonmodels.JobTask{
+		Name: j.job.Name,
+		JobInfo: map[string]string{
+			JobNameKey: j.job.Name,
+		},
+		Key:     j.job.Name,
+		JobType: string(config.JobArgoCDSync),
+		Spec: &commonmodels.JobTaskArgoCDSyncSpec{
+			ArgoCDID:           j.spec.ArgoCDID,
+			ArgoCDName:         argoCD.Name,
+			Prune:              j.spec.Prune,
+			Force:              j.spec.Force,
+			SyncOptions:        j.spec.SyncOptions,
+			RollbackOnDegraded: j.spec.RollbackOnDegraded,
+			Timeout:            j.spec.Timeout,
+			Applications:       applications,
+		},
+		Timeout:     j.spec.Timeout,
+		ErrorPolicy: j.job.ErrorPolicy,
+	}
+	return []*commonmodels.JobTask{jobTask}, nil
+}
+
+func (j *ArgoCDSyncJob) LintJob() error {
+	j.spec = &commonmodels.ArgoCDSyncJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	if j.spec.ArgoCDID == "" {
+		return fmt.Errorf("argo cd is not set")
+	}
+	if _, err := mongodb.NewArgoCDColl().GetByID(context.Background(), j.spec.ArgoCDID); err != nil {
+		return fmt.Errorf("failed to find argo cd %s, error: %s", j.spec.ArgoCDID, err)
+	}
+
+	if len(j.spec.Applications) == 0 {
+		return fmt.Errorf("no application is selected")
+	}
+	appSet := make(map[string]bool)
+	for _, app := range j.spec.Applications {
+		if app.ApplicationName == "" {
+			return fmt.Errorf("application name is empty")
+		}
+		if appSet[app.ApplicationName] {
+			return fmt.Errorf("application %s is selected more than once", app.ApplicationName)
+		}
+		appSet[app.ApplicationName] = true
+	}
+	for _, option := range j.spec.SyncOptions {
+		if !strings.Contains(option, "=") {
+			return fmt.Errorf("invalid sync option %s, it should be in the form of key=value", option)
+		}
+	}
+	return nil
+}
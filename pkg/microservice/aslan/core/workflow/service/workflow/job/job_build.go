@@ -31,6 +31,7 @@ import (
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/buildtemplate"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
 	templ "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/template"
 	"github.com/koderover/zadig/v2/pkg/setting"
@@ -44,6 +45,8 @@ const (
 	IMAGEKEY    = "IMAGE"
 	IMAGETAGKEY = "imageTag"
 	PKGFILEKEY  = "PKG_FILE"
+	// IMAGEPLATFORMSKEY is only written when the image is built for multiple platforms
+	IMAGEPLATFORMSKEY = setting.BuildJobOutputKeyImagePlatforms
 )
 
 type BuildJob struct {
@@ -388,6 +391,7 @@ func (j *BuildJob) UpdateWithLatestSetting() error {
 				KeyVals:          renderKeyVals(userDefinedArgs.KeyVals, buildInfo.KeyVals),
 				Repos:            mergeRepos(buildInfo.Repos, userDefinedArgs.Repos),
 				ShareStorageInfo: buildInfo.ShareStorageInfo,
+				SourcePaths:      buildInfo.SourcePaths,
 			}
 
 			mergedServiceAndBuilds = append(mergedServiceAndBuilds, newBuildInfo)
@@ -397,12 +401,14 @@ func (j *BuildJob) UpdateWithLatestSetting() error {
 	}
 
 	j.spec.DockerRegistryID = latestSpec.DockerRegistryID
+	j.spec.SkipUnchangedServices = latestSpec.SkipUnchangedServices
 	j.spec.ServiceAndBuilds = mergedServiceAndBuilds
+	j.spec.Matrix = latestSpec.Matrix
 	j.job.Spec = j.spec
 	return nil
 }
 
-func (j *BuildJob) MergeWebhookRepo(webhookRepo *types.Repository) error {
+func (j *BuildJob) MergeWebhookRepo(webhookRepo *types.Repository, changedFiles []string) error {
 	j.spec = &commonmodels.ZadigBuildJobSpec{}
 	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
 		return err
@@ -410,6 +416,9 @@ func (j *BuildJob) MergeWebhookRepo(webhookRepo *types.Repository) error {
 	for _, build := range j.spec.ServiceAndBuilds {
 		build.Repos = mergeRepos(build.Repos, []*types.Repository{webhookRepo})
 	}
+	if j.spec.SkipUnchangedServices {
+		j.skipUnchangedServices(webhookRepo, changedFiles)
+	}
 	j.job.Spec = j.spec
 	return nil
 }
@@ -437,8 +446,10 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 		buildMap         sync.Map
 		buildTemplateMap sync.Map
 	)
-	for _, build := range j.spec.ServiceAndBuilds {
-		imageTag := commonservice.ReleaseCandidate(build.Repos, taskID, j.workflow.Project, build.ServiceModule, "", build.ImageName, "image")
+	for _, target := range getBuildTargets(j.spec.ServiceAndBuilds, j.spec.Matrix) {
+		build, variant := target.build, target.variant
+		// each matrix variant pushes its own image tag
+		imageTag := commonservice.ReleaseCandidate(build.Repos, taskID, j.workflow.Project, build.ServiceModule, "", build.ImageName, "image") + variant.suffix("-")
 
 		image := fmt.Sprintf("%s/%s", registry.RegAddr, imageTag)
 		if len(registry.Namespace) > 0 {
@@ -448,7 +459,7 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 		image = strings.TrimPrefix(image, "http://")
 		image = strings.TrimPrefix(image, "https://")
 
-		pkgFile := fmt.Sprintf("%s.tar.gz", commonservice.ReleaseCandidate(build.Repos, taskID, j.workflow.Project, build.ServiceModule, "", build.ImageName, "tar"))
+		pkgFile := fmt.Sprintf("%s%s.tar.gz", commonservice.ReleaseCandidate(build.Repos, taskID, j.workflow.Project, build.ServiceModule, "", build.ImageName, "tar"), variant.suffix("-"))
 
 		var buildInfo *commonmodels.Build
 		buildMapValue, ok := buildMap.Load(build.BuildName)
@@ -475,14 +486,18 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 		}
 		outputs := ensureBuildInOutputs(buildInfo.Outputs)
 		jobTaskSpec := &commonmodels.JobTaskFreestyleSpec{}
+		jobInfo := map[string]string{
+			"service_name":   build.ServiceName,
+			"service_module": build.ServiceModule,
+			JobNameKey:       j.job.Name,
+		}
+		if variant != nil {
+			jobInfo["matrix_variant"] = variant.Name
+		}
 		jobTask := &commonmodels.JobTask{
-			Name: jobNameFormat(build.ServiceName + "-" + build.ServiceModule + "-" + j.job.Name),
-			JobInfo: map[string]string{
-				"service_name":   build.ServiceName,
-				"service_module": build.ServiceModule,
-				JobNameKey:       j.job.Name,
-			},
-			Key:            strings.Join([]string{j.job.Name, build.ServiceName, build.ServiceModule}, "."),
+			Name:           jobNameFormat(build.ServiceName + "-" + build.ServiceModule + variant.suffix("-") + "-" + j.job.Name),
+			JobInfo:        jobInfo,
+			Key:            strings.Join([]string{j.job.Name, build.ServiceName, build.ServiceModule}, ".") + variant.suffix("."),
 			JobType:        string(config.JobZadigBuild),
 			Spec:           jobTaskSpec,
 			Timeout:        int64(buildInfo.Timeout),
@@ -490,12 +505,13 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 			Infrastructure: buildInfo.Infrastructure,
 			VMLabels:       buildInfo.VMLabels,
 			ErrorPolicy:    j.job.ErrorPolicy,
+			NotifyCtls:     j.job.NotifyCtls,
 		}
 		jobTaskSpec.Properties = commonmodels.JobProperties{
 			Timeout:             int64(buildInfo.Timeout),
 			ResourceRequest:     buildInfo.PreBuild.ResReq,
 			ResReqSpec:          buildInfo.PreBuild.ResReqSpec,
-			CustomEnvs:          renderKeyVals(build.KeyVals, buildInfo.PreBuild.Envs),
+			CustomEnvs:          applyBuildMatrixVariant(renderKeyVals(build.KeyVals, buildInfo.PreBuild.Envs), variant),
 			ClusterID:           buildInfo.PreBuild.ClusterID,
 			StrategyID:          buildInfo.PreBuild.StrategyID,
 			BuildOS:             basicImage.Value,
@@ -542,9 +558,11 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 			}
 		}
 
-		// for other job refer current latest image.
-		build.Image = job.GetJobOutputKey(jobTask.Key, "IMAGE")
-		build.Package = job.GetJobOutputKey(jobTask.Key, "PKG_FILE")
+		// for other job refer current latest image, the first matrix variant is referred when matrix is configured.
+		if target.primary {
+			build.Image = job.GetJobOutputKey(jobTask.Key, "IMAGE")
+			build.Package = job.GetJobOutputKey(jobTask.Key, "PKG_FILE")
+		}
 		log.Infof("BuildJob ToJobs %d: workflow %s service %s, module %s, image %s, package %s",
 			taskID, j.workflow.Name, build.ServiceName, build.ServiceModule, build.Image, build.Package)
 
@@ -578,7 +596,7 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 					UnTar:      true,
 					IgnoreErr:  true,
 					FileName:   setting.BuildOSSCacheFileName,
-					ObjectPath: getBuildJobCacheObjectPath(j.workflow.Name, build.ServiceName, build.ServiceModule),
+					ObjectPath: getBuildJobCacheObjectPath(j.workflow.Name, build.ServiceName, build.ServiceModule+variant.suffix("-")),
 					DestDir:    cacheDir,
 					S3:         modelS3toS3(cacheS3),
 				},
@@ -606,10 +624,18 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 		// init shell step
 		scripts := []string{}
 		dockerLoginCmd := `docker login -u "$DOCKER_REGISTRY_AK" -p "$DOCKER_REGISTRY_SK" "$DOCKER_REGISTRY_HOST" &> /dev/null`
+		dockerBuildBackend := types.DockerBuildBackendDocker
+		if buildInfo.PostBuild != nil && buildInfo.PostBuild.DockerBuild != nil && buildInfo.PostBuild.DockerBuild.Backend != "" {
+			dockerBuildBackend = buildInfo.PostBuild.DockerBuild.Backend
+		}
 		if jobTask.Infrastructure == setting.JobVMInfrastructure {
 			scripts = append(scripts, strings.Split(replaceWrapLine(buildInfo.Scripts), "\n")...)
 		} else {
-			scripts = append([]string{dockerLoginCmd}, strings.Split(replaceWrapLine(buildInfo.Scripts), "\n")...)
+			// there is no docker daemon to login if the image is built by kaniko or buildkit
+			if dockerBuildBackend == types.DockerBuildBackendDocker {
+				scripts = append(scripts, dockerLoginCmd)
+			}
+			scripts = append(scripts, strings.Split(replaceWrapLine(buildInfo.Scripts), "\n")...)
 			scripts = append(scripts, outputScript(outputs, jobTask.Infrastructure)...)
 		}
 		scriptStep := &commonmodels.StepTask{
@@ -646,8 +672,8 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 		if buildInfo.PostBuild != nil && buildInfo.PostBuild.DockerBuild != nil {
 			dockefileContent := ""
 			if buildInfo.PostBuild.DockerBuild.TemplateID != "" {
-				if dockerfileDetail, err := templ.GetDockerfileTemplateDetail(buildInfo.PostBuild.DockerBuild.TemplateID, logger); err == nil {
-					dockefileContent = dockerfileDetail.Content
+				if content, err := templ.GetDockerfileTemplateContent(buildInfo.PostBuild.DockerBuild.TemplateID, buildInfo.PostBuild.DockerBuild.TemplateVersion, logger); err == nil {
+					dockefileContent = content
 				}
 			}
 
@@ -663,6 +689,9 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 					ImageReleaseTag:       imageTag,
 					BuildArgs:             buildInfo.PostBuild.DockerBuild.BuildArgs,
 					DockerTemplateContent: dockefileContent,
+					Backend:               dockerBuildBackend,
+					Platforms:             buildInfo.PostBuild.DockerBuild.Platforms,
+					MultiArchStrategy:     buildInfo.PostBuild.DockerBuild.MultiArchStrategy,
 					DockerRegistry: &step.DockerRegistry{
 						DockerRegistryID: j.spec.DockerRegistryID,
 						Host:             registry.RegAddr,
@@ -682,6 +711,8 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 			if jobTaskSpec.Properties.CacheDirType == types.UserDefinedCacheDir {
 				cacheDir = jobTaskSpec.Properties.CacheUserDir
 			}
+			// the cache is downloaded from the subfolder of the storage, so it must be uploaded there as well
+			cacheObjectPath := strings.TrimLeft(path.Join(cacheS3.Subfolder, getBuildJobCacheObjectPath(j.workflow.Name, build.ServiceName, build.ServiceModule+variant.suffix("-"))), "/")
 			tarArchiveStep := &commonmodels.StepTask{
 				Name:     fmt.Sprintf("%s-%s", build.ServiceName, "tar-archive"),
 				JobName:  jobTask.Name,
@@ -692,7 +723,7 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 					AbsResultDir: true,
 					TarDir:       cacheDir,
 					ChangeTarDir: true,
-					S3DestDir:    getBuildJobCacheObjectPath(j.workflow.Name, build.ServiceName, build.ServiceModule),
+					S3DestDir:    cacheObjectPath,
 					IgnoreErr:    true,
 					S3Storage:    modelS3toS3(cacheS3),
 				},
@@ -725,6 +756,40 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 				},
 			}
 			jobTaskSpec.Steps = append(jobTaskSpec.Steps, archiveStep)
+
+			// push the package to the artifact repository with the build metadata
+			if buildInfo.PostBuild.FileArchive.ArtifactRepositoryID != "" {
+				version := fmt.Sprintf("%s-%d", j.workflow.Name, taskID)
+				properties := map[string]string{
+					"build.name":     j.workflow.Name,
+					"build.number":   fmt.Sprint(taskID),
+					"project":        j.workflow.Project,
+					"service":        build.ServiceName,
+					"service.module": build.ServiceModule,
+				}
+				for _, repo := range repos {
+					if repo.CommitID != "" {
+						properties[fmt.Sprintf("vcs.revision.%s", repo.RepoName)] = repo.CommitID
+					}
+				}
+				artifactUploadStep := &commonmodels.StepTask{
+					Name:     build.ServiceName + "-artifact-upload",
+					JobName:  jobTask.Name,
+					StepType: config.StepArtifactUpload,
+					Spec: step.StepArtifactUploadSpec{
+						RepositoryID:  buildInfo.PostBuild.FileArchive.ArtifactRepositoryID,
+						FilePath:      path.Join(buildInfo.PostBuild.FileArchive.FileLocation, pkgFile),
+						FileName:      pkgFile,
+						DestDir:       path.Join(j.workflow.Project, build.ServiceName, build.ServiceModule, version),
+						ServiceName:   build.ServiceName,
+						ServiceModule: build.ServiceModule,
+						Version:       version,
+						Properties:    properties,
+						Repos:         repos,
+					},
+				}
+				jobTaskSpec.Steps = append(jobTaskSpec.Steps, artifactUploadStep)
+			}
 		}
 
 		// init object storage step
@@ -768,8 +833,14 @@ func (j *BuildJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 			}
 			jobTaskSpec.Steps = append(jobTaskSpec.Steps, shellStep)
 		}
+
+		// init html reports step after the post build scripts which may generate reports
+		if reportsStep := htmlReportsStep(buildInfo.HTMLReports, j.workflow.Name, taskID, jobTask.Name, defaultS3); reportsStep != nil {
+			jobTaskSpec.Steps = append(jobTaskSpec.Steps, reportsStep)
+		}
 		resp = append(resp, jobTask)
 	}
+	resp = append(resp, j.skippedJobTasks()...)
 	j.job.Spec = j.spec
 	return resp, nil
 }
@@ -905,6 +976,12 @@ func fillBuildDetail(moduleBuild *commonmodels.Build, serviceName, serviceModule
 				moduleBuild.PreBuild = &commonmodels.PreBuild{}
 			}
 			moduleBuild.PreBuild.Envs = commonservice.MergeBuildEnvs(moduleBuild.PreBuild.Envs, serviceConfig.Envs)
+			// template parameters are consumed by the scripts as envs
+			params, err := buildtemplate.RenderParameters(buildTemplate.Parameters, serviceConfig.Params)
+			if err != nil {
+				return fmt.Errorf("invalid parameters of build %s for %s/%s: %s", moduleBuild.Name, serviceName, serviceModule, err)
+			}
+			moduleBuild.PreBuild.Envs = append(moduleBuild.PreBuild.Envs, params...)
 			break
 		}
 	}
@@ -958,7 +1035,7 @@ func (j *BuildJob) LintJob() error {
 		return err
 	}
 
-	return nil
+	return lintBuildMatrix(j.spec.Matrix)
 }
 
 func (j *BuildJob) GetOutPuts(log *zap.SugaredLogger) []string {
@@ -967,6 +1044,7 @@ func (j *BuildJob) GetOutPuts(log *zap.SugaredLogger) []string {
 	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
 		return resp
 	}
+	variants := getBuildMatrixVariants(j.spec.Matrix)
 	for _, build := range j.spec.ServiceAndBuilds {
 		jobKey := strings.Join([]string{j.job.Name, build.ServiceName, build.ServiceModule}, ".")
 		buildInfo, err := commonrepo.NewBuildColl().Find(&commonrepo.BuildFindOption{Name: build.BuildName})
@@ -974,16 +1052,18 @@ func (j *BuildJob) GetOutPuts(log *zap.SugaredLogger) []string {
 			log.Errorf("found build %s failed, err: %s", build.BuildName, err)
 			continue
 		}
-		if buildInfo.TemplateID == "" {
-			resp = append(resp, getOutputKey(jobKey, ensureBuildInOutputs(buildInfo.Outputs))...)
-			continue
+		outputs := buildInfo.Outputs
+		if buildInfo.TemplateID != "" {
+			buildTemplate, err := commonrepo.NewBuildTemplateColl().Find(&commonrepo.BuildTemplateQueryOption{ID: buildInfo.TemplateID})
+			if err != nil {
+				log.Errorf("found build template %s failed, err: %s", buildInfo.TemplateID, err)
+				continue
+			}
+			outputs = buildTemplate.Outputs
 		}
-		buildTemplate, err := commonrepo.NewBuildTemplateColl().Find(&commonrepo.BuildTemplateQueryOption{ID: buildInfo.TemplateID})
-		if err != nil {
-			log.Errorf("found build template %s failed, err: %s", buildInfo.TemplateID, err)
-			continue
+		for _, variant := range variants {
+			resp = append(resp, getOutputKey(jobKey+variant.suffix("."), ensureBuildInOutputs(outputs))...)
 		}
-		resp = append(resp, getOutputKey(jobKey, ensureBuildInOutputs(buildTemplate.Outputs))...)
 	}
 	return resp
 }
@@ -1008,6 +1088,11 @@ func ensureBuildInOutputs(outputs []*commonmodels.Output) []*commonmodels.Output
 			Name: PKGFILEKEY,
 		})
 	}
+	if _, ok := keyMap[IMAGEPLATFORMSKEY]; !ok {
+		outputs = append(outputs, &commonmodels.Output{
+			Name: IMAGEPLATFORMSKEY,
+		})
+	}
 	return outputs
 }
 
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"path"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/types"
+)
+
+// skipUnchangedServices moves the services whose source paths are not changed by the webhook event to
+// SkippedServiceAndBuilds, nothing is skipped if the changes are unknown.
+func (j *BuildJob) skipUnchangedServices(webhookRepo *types.Repository, changedFiles []string) {
+	if len(changedFiles) == 0 {
+		return
+	}
+	builds := make([]*commonmodels.ServiceAndBuild, 0, len(j.spec.ServiceAndBuilds))
+	for _, build := range j.spec.ServiceAndBuilds {
+		if sourceChanged(build, webhookRepo, changedFiles) {
+			builds = append(builds, build)
+			continue
+		}
+		log.Infof("BuildJob %s of workflow %s: service %s, module %s is skipped since its source paths are not changed",
+			j.job.Name, j.workflow.Name, build.ServiceName, build.ServiceModule)
+		j.spec.SkippedServiceAndBuilds = append(j.spec.SkippedServiceAndBuilds, build)
+	}
+	j.spec.ServiceAndBuilds = builds
+}
+
+// sourceChanged returns true if the service has no source paths configured, or the webhook repo is used by
+// the service and one of the changed files is in the source paths.
+func sourceChanged(build *commonmodels.ServiceAndBuild, webhookRepo *types.Repository, changedFiles []string) bool {
+	if len(build.SourcePaths) == 0 {
+		return true
+	}
+	repoUsed := false
+	for _, repo := range build.Repos {
+		if repo.Source == webhookRepo.Source && repo.GetRepoNamespace() == webhookRepo.GetRepoNamespace() && repo.RepoName == webhookRepo.RepoName {
+			repoUsed = true
+			break
+		}
+	}
+	if !repoUsed {
+		return false
+	}
+	for _, file := range changedFiles {
+		if sourcePathsContainFile(build.SourcePaths, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourcePathsContainFile follows the rules of the match folders of the webhook: the paths starting with "!"
+// exclude the files by prefix, suffix or extension.
+func sourcePathsContainFile(sourcePaths []string, file string) bool {
+	var includes, excludes []string
+	for _, p := range sourcePaths {
+		if strings.HasPrefix(p, "!") {
+			excludes = append(excludes, strings.TrimPrefix(p, "!"))
+		} else {
+			includes = append(includes, p)
+		}
+	}
+
+	for _, include := range includes {
+		if include != "/" && !strings.HasPrefix(file, include) {
+			continue
+		}
+		for _, exclude := range excludes {
+			if exclude == "" || exclude == "/" || path.Ext(file) == exclude || strings.HasPrefix(file, exclude) || strings.HasSuffix(file, exclude) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// skippedJobTasks returns the job tasks of the skipped services, they are marked as skipped so that they are
+// displayed in the task but never run.
+func (j *BuildJob) skippedJobTasks() []*commonmodels.JobTask {
+	resp := make([]*commonmodels.JobTask, 0)
+	for _, target := range getBuildTargets(j.spec.SkippedServiceAndBuilds, j.spec.Matrix) {
+		build, variant := target.build, target.variant
+		jobInfo := map[string]string{
+			"service_name":   build.ServiceName,
+			"service_module": build.ServiceModule,
+			JobNameKey:       j.job.Name,
+		}
+		if variant != nil {
+			jobInfo["matrix_variant"] = variant.Name
+		}
+		resp = append(resp, &commonmodels.JobTask{
+			Name:        jobNameFormat(build.ServiceName + "-" + build.ServiceModule + variant.suffix("-") + "-" + j.job.Name),
+			JobInfo:     jobInfo,
+			Key:         strings.Join([]string{j.job.Name, build.ServiceName, build.ServiceModule}, ".") + variant.suffix("."),
+			JobType:     string(config.JobZadigBuild),
+			Status:      config.StatusSkipped,
+			Spec:        &commonmodels.JobTaskFreestyleSpec{},
+			ErrorPolicy: j.job.ErrorPolicy,
+		})
+	}
+	return resp
+}
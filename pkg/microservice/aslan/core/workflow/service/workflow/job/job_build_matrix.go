@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+const maxBuildMatrixVariants = 32
+
+var buildMatrixVariantNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// buildMatrixVariant is one combination of the matrix values of a build job.
+type buildMatrixVariant struct {
+	// Name is used in job task names, keys and image tags, so only letters, digits and "-" are kept.
+	Name string
+	Envs []*commonmodels.KeyVal
+}
+
+type buildTarget struct {
+	build   *commonmodels.ServiceAndBuild
+	variant *buildMatrixVariant
+	// primary is the first variant of the service, its outputs are referred by the other jobs.
+	primary bool
+}
+
+func getBuildTargets(builds []*commonmodels.ServiceAndBuild, matrix *commonmodels.BuildMatrix) []*buildTarget {
+	variants := getBuildMatrixVariants(matrix)
+	resp := make([]*buildTarget, 0, len(builds)*len(variants))
+	for _, build := range builds {
+		for idx, variant := range variants {
+			resp = append(resp, &buildTarget{
+				build:   build,
+				variant: variant,
+				primary: idx == 0,
+			})
+		}
+	}
+	return resp
+}
+
+// getBuildMatrixVariants returns the cartesian product of the matrix axes,
+// a single nil variant is returned if no matrix is configured.
+func getBuildMatrixVariants(matrix *commonmodels.BuildMatrix) []*buildMatrixVariant {
+	if matrix == nil || len(matrix.Axes) == 0 {
+		return []*buildMatrixVariant{nil}
+	}
+
+	combinations := [][]*commonmodels.KeyVal{{}}
+	for _, axis := range matrix.Axes {
+		next := make([][]*commonmodels.KeyVal, 0, len(combinations)*len(axis.Values))
+		for _, combination := range combinations {
+			for _, value := range axis.Values {
+				kvs := append(append([]*commonmodels.KeyVal{}, combination...), &commonmodels.KeyVal{
+					Key:   axis.Key,
+					Value: value,
+					Type:  commonmodels.StringType,
+				})
+				next = append(next, kvs)
+			}
+		}
+		combinations = next
+	}
+
+	resp := make([]*buildMatrixVariant, 0, len(combinations))
+	for _, kvs := range combinations {
+		values := make([]string, 0, len(kvs))
+		for _, kv := range kvs {
+			values = append(values, kv.Value)
+		}
+		resp = append(resp, &buildMatrixVariant{
+			Name: strings.ToLower(strings.Trim(buildMatrixVariantNameRegexp.ReplaceAllString(strings.Join(values, "-"), "-"), "-")),
+			Envs: kvs,
+		})
+	}
+	return resp
+}
+
+func lintBuildMatrix(matrix *commonmodels.BuildMatrix) error {
+	if matrix == nil {
+		return nil
+	}
+
+	keys := make(map[string]struct{})
+	total := 1
+	for _, axis := range matrix.Axes {
+		if axis.Key == "" {
+			return fmt.Errorf("build matrix key can not be empty")
+		}
+		if _, ok := keys[axis.Key]; ok {
+			return fmt.Errorf("duplicated build matrix key: %s", axis.Key)
+		}
+		keys[axis.Key] = struct{}{}
+		if len(axis.Values) == 0 {
+			return fmt.Errorf("build matrix key %s has no value", axis.Key)
+		}
+		total *= len(axis.Values)
+		if total > maxBuildMatrixVariants {
+			return fmt.Errorf("build matrix can not have more than %d combinations", maxBuildMatrixVariants)
+		}
+	}
+
+	names := make(map[string]struct{})
+	for _, variant := range getBuildMatrixVariants(matrix) {
+		if variant == nil {
+			continue
+		}
+		if variant.Name == "" {
+			return fmt.Errorf("build matrix values must contain letters or digits")
+		}
+		if _, ok := names[variant.Name]; ok {
+			return fmt.Errorf("build matrix values result in duplicated variant: %s", variant.Name)
+		}
+		names[variant.Name] = struct{}{}
+	}
+	return nil
+}
+
+// applyBuildMatrixVariant overrides the envs of the build with the values of the variant.
+func applyBuildMatrixVariant(envs []*commonmodels.KeyVal, variant *buildMatrixVariant) []*commonmodels.KeyVal {
+	if variant == nil {
+		return envs
+	}
+
+	resp := make([]*commonmodels.KeyVal, 0, len(envs)+len(variant.Envs))
+	overrides := make(map[string]*commonmodels.KeyVal)
+	for _, kv := range variant.Envs {
+		overrides[kv.Key] = kv
+	}
+	for _, kv := range envs {
+		if override, ok := overrides[kv.Key]; ok {
+			resp = append(resp, &commonmodels.KeyVal{Key: kv.Key, Value: override.Value, Type: kv.Type, IsCredential: kv.IsCredential})
+			delete(overrides, kv.Key)
+			continue
+		}
+		resp = append(resp, kv)
+	}
+	for _, kv := range variant.Envs {
+		if _, ok := overrides[kv.Key]; ok {
+			resp = append(resp, kv)
+		}
+	}
+	return resp
+}
+
+func (v *buildMatrixVariant) suffix(sep string) string {
+	if v == nil {
+		return ""
+	}
+	return sep + v.Name
+}
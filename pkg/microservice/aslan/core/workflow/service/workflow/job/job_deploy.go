@@ -17,6 +17,7 @@ limitations under the License.
 package job
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -31,8 +32,10 @@ import (
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	templatemodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/template"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	templaterepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb/template"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/gitops"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
 	commontypes "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/types"
@@ -455,6 +458,10 @@ func (j *DeployJob) UpdateWithLatestSetting() error {
 		j.spec.DeployType = project.ProductFeature.DeployType
 	}
 	j.spec.SkipCheckRunStatus = latestSpec.SkipCheckRunStatus
+	j.spec.BakeTime = latestSpec.BakeTime
+	j.spec.StatefulSetRollout = latestSpec.StatefulSetRollout
+	j.spec.GitOpsExport = latestSpec.GitOpsExport
+	j.spec.ContractCheck = latestSpec.ContractCheck
 	j.spec.DeployContents = latestSpec.DeployContents
 
 	// source is a bit tricky: if the saved args has a source of fromjob, but it has been change to runtime in the config
@@ -754,6 +761,7 @@ func (j *DeployJob) MergeArgs(args *commonmodels.Job) error {
 			return err
 		}
 		j.spec.Env = argsSpec.Env
+		j.spec.FanOutEnvs = argsSpec.FanOutEnvs
 		j.spec.Services = argsSpec.Services
 
 		j.job.Spec = j.spec
@@ -771,19 +779,11 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 	j.setDefaultDeployContent()
 	j.job.Spec = j.spec
 
-	envName := strings.ReplaceAll(j.spec.Env, setting.FixedValueMark, "")
-	product, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: j.workflow.Project, EnvName: envName})
-	if err != nil {
-		return resp, fmt.Errorf("env %s not exists", envName)
-	}
-
 	project, err := templaterepo.NewProductColl().Find(j.workflow.Project)
 	if err != nil {
 		return resp, fmt.Errorf("failed to find project %s, err: %v", j.workflow.Project, err)
 	}
 
-	productServiceMap := product.GetServiceMap()
-
 	// get deploy info from previous build job
 	if j.spec.Source == config.SourceFromJob {
 		// adapt to the front end, use the direct quoted job name
@@ -853,6 +853,38 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 	}
 	timeout := templateProduct.Timeout * 60
 
+	// the services are deployed to the fan-out envs simultaneously, one job task per service per env
+	for _, envName := range j.spec.TargetEnvs() {
+		envJobs, err := j.toEnvJobs(taskID, envName, envName != strings.ReplaceAll(j.spec.Env, setting.FixedValueMark, ""), project, serviceMap, timeout)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, envJobs...)
+	}
+
+	j.job.Spec = j.spec
+	return resp, nil
+}
+
+// toEnvJobs generates the job tasks deploying the services to the env, the names and keys of the job tasks of a fan-out
+// env are suffixed with the env name to tell them from the ones of the default env. The configs and variables of the
+// services are resolved against the default env, so only the images are deployed to the fan-out envs.
+func (j *DeployJob) toEnvJobs(taskID int64, envName string, fanOut bool, project *templatemodels.Product, serviceMap map[string]*commonmodels.DeployServiceInfo, timeout int) ([]*commonmodels.JobTask, error) {
+	resp := []*commonmodels.JobTask{}
+	product, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: j.workflow.Project, EnvName: envName})
+	if err != nil {
+		return resp, fmt.Errorf("env %s not exists", envName)
+	}
+	if fanOut && product.Production != j.spec.Production {
+		return resp, fmt.Errorf("fan-out env %s is not of the same kind as the env of the job", envName)
+	}
+	productServiceMap := product.GetServiceMap()
+
+	deployContents := j.spec.DeployContents
+	if fanOut {
+		deployContents = []config.DeployContent{config.DeployImage}
+	}
+
 	if j.spec.DeployType == setting.K8SDeployType {
 		for _, svc := range j.spec.Services {
 			serviceName := svc.ServiceName
@@ -864,8 +896,12 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 				CreateEnvType:      project.ProductFeature.CreateEnvType,
 				ClusterID:          product.ClusterID,
 				Production:         j.spec.Production,
-				DeployContents:     j.spec.DeployContents,
+				DeployContents:     deployContents,
 				Timeout:            timeout,
+				BakeTime:           j.spec.BakeTime,
+				StatefulSetRollout: j.spec.StatefulSetRollout,
+				GitOpsExport:       j.spec.GitOpsExport,
+				ContractCheck:      j.spec.ContractCheck,
 			}
 
 			for _, module := range svc.Modules {
@@ -882,10 +918,10 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 				})
 			}
 			if !project.IsHostProduct() {
-				jobTaskSpec.DeployContents = j.spec.DeployContents
+				jobTaskSpec.DeployContents = deployContents
 				jobTaskSpec.Production = j.spec.Production
 				service := serviceMap[serviceName]
-				if service != nil {
+				if service != nil && !fanOut {
 					jobTaskSpec.UpdateConfig = service.UpdateConfig
 					jobTaskSpec.VariableConfigs = service.VariableConfigs
 					jobTaskSpec.VariableKVs = service.VariableKVs
@@ -914,7 +950,7 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 					jobTaskSpec.VariableKVs = filteredKV
 				}
 				// if only deploy images, clear keyvals
-				if onlyDeployImage(j.spec.DeployContents) {
+				if onlyDeployImage(deployContents) {
 					jobTaskSpec.VariableConfigs = []*commonmodels.DeployVariableConfig{}
 					jobTaskSpec.VariableKVs = []*commontypes.RenderVariableKV{}
 				}
@@ -925,10 +961,12 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 				JobInfo: map[string]string{
 					JobNameKey:     j.job.Name,
 					"service_name": serviceName,
+					"env_name":     envName,
 				},
 				JobType:     string(config.JobZadigDeploy),
 				Spec:        jobTaskSpec,
 				ErrorPolicy: j.job.ErrorPolicy,
+				NotifyCtls:  j.job.NotifyCtls,
 			}
 			if jobTaskSpec.CreateEnvType == "system" {
 				var updateRevision bool
@@ -986,6 +1024,7 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 				log.Infof("DeployJob ToJobs %d: workflow %s service %s, module %s, image %s",
 					taskID, j.workflow.Name, serviceName, image.ServiceModule, image.Image)
 			}
+			j.setFanOutJobName(jobTask, serviceName, envName, fanOut)
 			resp = append(resp, jobTask)
 		}
 	}
@@ -1010,18 +1049,20 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 			jobTaskSpec := &commonmodels.JobTaskHelmDeploySpec{
 				Env:                envName,
 				ServiceName:        svc.ServiceName,
-				DeployContents:     j.spec.DeployContents,
+				DeployContents:     deployContents,
 				SkipCheckRunStatus: j.spec.SkipCheckRunStatus,
 				ServiceType:        setting.HelmDeployType,
 				ClusterID:          product.ClusterID,
 				ReleaseName:        releaseName,
 				Timeout:            timeout,
 				IsProduction:       j.spec.Production,
+				GitOpsExport:       j.spec.GitOpsExport,
+				ContractCheck:      j.spec.ContractCheck,
 			}
 
 			for _, module := range svc.Modules {
 				service := serviceMap[svc.ServiceName]
-				if service != nil {
+				if service != nil && !fanOut {
 					jobTaskSpec.UpdateConfig = service.UpdateConfig
 					jobTaskSpec.KeyVals = service.KeyVals
 					jobTaskSpec.VariableYaml = service.VariableYaml
@@ -1039,18 +1080,28 @@ func (j *DeployJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
 				JobInfo: map[string]string{
 					JobNameKey:     j.job.Name,
 					"service_name": svc.ServiceName,
+					"env_name":     envName,
 				},
-				JobType: string(config.JobZadigHelmDeploy),
-				Spec:    jobTaskSpec,
+				JobType:    string(config.JobZadigHelmDeploy),
+				Spec:       jobTaskSpec,
+				NotifyCtls: j.job.NotifyCtls,
 			}
+			j.setFanOutJobName(jobTask, svc.ServiceName, envName, fanOut)
 			resp = append(resp, jobTask)
 		}
 	}
 
-	j.job.Spec = j.spec
 	return resp, nil
 }
 
+func (j *DeployJob) setFanOutJobName(jobTask *commonmodels.JobTask, serviceName, envName string, fanOut bool) {
+	if !fanOut {
+		return
+	}
+	jobTask.Name = jobNameFormat(serviceName + "-" + envName + "-" + j.job.Name)
+	jobTask.Key = strings.Join([]string{j.job.Name, serviceName, envName}, ".")
+}
+
 func onlyDeployImage(deployContents []config.DeployContent) bool {
 	return slices.Contains(deployContents, config.DeployImage) && len(deployContents) == 1
 }
@@ -1078,6 +1129,33 @@ func (j *DeployJob) LintJob() error {
 			}
 		}
 	}
+	if j.spec.BakeTime < 0 {
+		return fmt.Errorf("bake time of job %s can not be negative", j.job.Name)
+	}
+	if err := kube.ValidateStatefulSetRollout(j.spec.StatefulSetRollout, j.spec.Production); err != nil {
+		return fmt.Errorf("job %s: %v", j.job.Name, err)
+	}
+	if err := j.lintFanOutEnvs(); err != nil {
+		return fmt.Errorf("job %s: %v", j.job.Name, err)
+	}
+	if j.spec.GitOpsExport != nil {
+		// the manifests of different envs would be committed to the same files
+		if len(j.spec.FanOutEnvs) > 0 {
+			return fmt.Errorf("job %s: fan-out envs are not supported in gitops export mode", j.job.Name)
+		}
+		if err := gitops.ValidateExportConfig(j.spec.GitOpsExport); err != nil {
+			return fmt.Errorf("job %s: %v", j.job.Name, err)
+		}
+		// the services are synced by Argo CD or Flux, zadig can not verify or roll them back
+		if j.spec.BakeTime > 0 {
+			return fmt.Errorf("job %s: bake time is not supported in gitops export mode", j.job.Name)
+		}
+	}
+	if j.spec.ContractCheck != nil {
+		if _, err := commonrepo.NewContractRegistryColl().GetByID(context.TODO(), j.spec.ContractCheck.RegistryID); err != nil {
+			return fmt.Errorf("job %s: contract registry %s not found", j.job.Name, j.spec.ContractCheck.RegistryID)
+		}
+	}
 	if j.spec.Source != config.SourceFromJob {
 		return nil
 	}
@@ -1089,6 +1167,34 @@ func (j *DeployJob) LintJob() error {
 	return nil
 }
 
+// lintFanOutEnvs checks the fan-out envs exist and are of the same kind as the default env, envs selected at runtime
+// are checked when the task is created.
+func (j *DeployJob) lintFanOutEnvs() error {
+	envs := sets.NewString()
+	for _, env := range j.spec.FanOutEnvs {
+		env = strings.ReplaceAll(env, setting.FixedValueMark, "")
+		if env == "" {
+			continue
+		}
+		if envs.Has(env) {
+			return fmt.Errorf("fan-out env %s is duplicated", env)
+		}
+		if !slices.Contains(j.spec.DeployContents, config.DeployImage) {
+			return fmt.Errorf("only images are deployed to fan-out env %s, deploy contents must contain image", env)
+		}
+		envs.Insert(env)
+
+		product, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: j.workflow.Project, EnvName: env})
+		if err != nil {
+			return fmt.Errorf("fan-out env %s not exists", env)
+		}
+		if product.Production != j.spec.Production {
+			return fmt.Errorf("fan-out env %s is not of the same kind as the env of the job", env)
+		}
+	}
+	return nil
+}
+
 func (j *DeployJob) GetOutPuts(log *zap.SugaredLogger) []string {
 	resp := []string{}
 
@@ -209,6 +209,8 @@ func (j *ImageDistributeJob) UpdateWithLatestSetting() error {
 	j.spec.StrategyID = latestSpec.StrategyID
 	j.spec.EnableTargetImageTagRule = latestSpec.EnableTargetImageTagRule
 	j.spec.TargetImageTagRule = latestSpec.TargetImageTagRule
+	j.spec.DistributeMethod = latestSpec.DistributeMethod
+	j.spec.ReplicationRegistryName = latestSpec.ReplicationRegistryName
 	j.job.Spec = j.spec
 	return nil
 }
@@ -276,9 +278,15 @@ func (j *ImageDistributeJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, erro
 		}
 	}
 
+	if j.spec.DistributeMethod == step.DistributeMethodHarborReplication && sourceReg.RegProvider != config.RegistryProviderHarbor {
+		return resp, fmt.Errorf("harbor replication requires the source registry to be harbor, got %s", sourceReg.RegProvider)
+	}
+
 	stepSpec := &step.StepImageDistributeSpec{
-		SourceRegistry: getRegistry(sourceReg),
-		TargetRegistry: getRegistry(targetReg),
+		SourceRegistry:          getRegistry(sourceReg),
+		TargetRegistry:          getRegistry(targetReg),
+		Method:                  j.spec.DistributeMethod,
+		ReplicationRegistryName: j.spec.ReplicationRegistryName,
 	}
 	for _, target := range j.spec.Targets {
 		// for other job refer current latest image.
@@ -332,6 +340,19 @@ func (j *ImageDistributeJob) LintJob() error {
 	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
 		return err
 	}
+	switch j.spec.DistributeMethod {
+	case "", step.DistributeMethodImagePush:
+	case step.DistributeMethodHarborReplication:
+		if j.spec.ReplicationRegistryName == "" {
+			return fmt.Errorf("replication registry name is required by harbor replication in job %s", j.job.Name)
+		}
+		// harbor replication keeps the tags of the source images
+		if j.spec.EnableTargetImageTagRule {
+			return fmt.Errorf("target image tag rule is not supported by harbor replication in job %s", j.job.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported distribute method %s in job %s", j.spec.DistributeMethod, j.job.Name)
+	}
 	if j.spec.Source != config.SourceFromJob {
 		return nil
 	}
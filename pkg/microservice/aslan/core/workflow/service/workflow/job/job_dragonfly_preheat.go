@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/tool/dragonfly"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+type DragonflyPreheatJob struct {
+	job      *commonmodels.Job
+	workflow *commonmodels.WorkflowV4
+	spec     *commonmodels.DragonflyPreheatJobSpec
+}
+
+func (j *DragonflyPreheatJob) Instantiate() error {
+	j.spec = &commonmodels.DragonflyPreheatJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *DragonflyPreheatJob) SetPreset() error {
+	j.spec = &commonmodels.DragonflyPreheatJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *DragonflyPreheatJob) SetOptions() error {
+	return nil
+}
+
+func (j *DragonflyPreheatJob) ClearSelectionField() error {
+	return nil
+}
+
+func (j *DragonflyPreheatJob) UpdateWithLatestSetting() error {
+	j.spec = &commonmodels.DragonflyPreheatJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	latestWorkflow, err := mongodb.NewWorkflowV4Coll().Find(j.workflow.Name)
+	if err != nil {
+		log.Errorf("Failed to find original workflow to set options, error: %s", err)
+		return err
+	}
+
+	latestSpec := new(commonmodels.DragonflyPreheatJobSpec)
+	found := false
+	for _, stage := range latestWorkflow.Stages {
+		if !found {
+			for _, job := range stage.Jobs {
+				if job.Name == j.job.Name && job.JobType == j.job.JobType {
+					if err := commonmodels.IToi(job.Spec, latestSpec); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+		} else {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("failed to find the original workflow: %s", j.workflow.Name)
+	}
+
+	// images are allowed to be changed by user, others use the latest config
+	j.spec.ClusterID = latestSpec.ClusterID
+	j.spec.Scope = latestSpec.Scope
+	j.spec.Timeout = latestSpec.Timeout
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *DragonflyPreheatJob) MergeArgs(args *commonmodels.Job) error {
+	if j.job.Name == args.Name && j.job.JobType == args.JobType {
+		j.spec = &commonmodels.DragonflyPreheatJobSpec{}
+		if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+			return err
+		}
+		argsSpec := &commonmodels.DragonflyPreheatJobSpec{}
+		if err := commonmodels.IToi(args.Spec, argsSpec); err != nil {
+			return err
+		}
+		j.spec.Images = argsSpec.Images
+		j.job.Spec = j.spec
+	}
+	return nil
+}
+
+func (j *DragonflyPreheatJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
+	j.spec = &commonmodels.DragonflyPreheatJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return nil, err
+	}
+	j.job.Spec = j.spec
+
+	cluster, err := mongodb.NewK8SClusterColl().Get(j.spec.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cluster %s, error: %s", j.spec.ClusterID, err)
+	}
+
+	images := make([]*commonmodels.DragonflyPreheatImage, 0)
+	for _, image := range j.spec.Images {
+		image = strings.TrimSpace(image)
+		if image == "" {
+			continue
+		}
+		images = append(images, &commonmodels.DragonflyPreheatImage{
+			Image:  image,
+			Status: config.StatusPrepare,
+		})
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no image to be preheated")
+	}
+
+	scope := j.spec.Scope
+	if scope == "" {
+		scope = dragonfly.PreheatScopeSingleSeedPeer
+	}
+
+	jobTask := &commonmodels.JobTask{
+		Name: j.job.Name,
+		JobInfo: map[string]string{
+			JobNameKey: j.job.Name,
+		},
+		Key:     j.job.Name,
+		JobType: string(config.JobDragonflyPreheat),
+		Spec: &commonmodels.JobTaskDragonflyPreheatSpec{
+			ClusterID:   j.spec.ClusterID,
+			ClusterName: cluster.Name,
+			Scope:       scope,
+			Timeout:     j.spec.Timeout,
+			Images:      images,
+		},
+		Timeout:     j.spec.Timeout,
+		ErrorPolicy: j.job.ErrorPolicy,
+	}
+	return []*commonmodels.JobTask{jobTask}, nil
+}
+
+func (j *DragonflyPreheatJob) LintJob() error {
+	j.spec = &commonmodels.DragonflyPreheatJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	if j.spec.ClusterID == "" {
+		return fmt.Errorf("cluster is not set")
+	}
+	cluster, err := mongodb.NewK8SClusterColl().Get(j.spec.ClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to find cluster %s, error: %s", j.spec.ClusterID, err)
+	}
+	if cluster.DragonflyConfig == nil || !cluster.DragonflyConfig.Enabled {
+		return fmt.Errorf("dragonfly is not enabled in cluster %s", cluster.Name)
+	}
+	if cluster.DragonflyConfig.ManagerAddress == "" {
+		return fmt.Errorf("dragonfly manager address of cluster %s is not configured", cluster.Name)
+	}
+
+	switch j.spec.Scope {
+	case "", dragonfly.PreheatScopeSingleSeedPeer, dragonfly.PreheatScopeAllSeedPeers, dragonfly.PreheatScopeAllPeers:
+	default:
+		return fmt.Errorf("invalid preheat scope: %s", j.spec.Scope)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/gitops"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+type FluxImageUpdateJob struct {
+	job      *commonmodels.Job
+	workflow *commonmodels.WorkflowV4
+	spec     *commonmodels.FluxImageUpdateJobSpec
+}
+
+func (j *FluxImageUpdateJob) Instantiate() error {
+	j.spec = &commonmodels.FluxImageUpdateJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *FluxImageUpdateJob) SetPreset() error {
+	j.spec = &commonmodels.FluxImageUpdateJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *FluxImageUpdateJob) SetOptions() error {
+	return nil
+}
+
+func (j *FluxImageUpdateJob) ClearSelectionField() error {
+	return nil
+}
+
+func (j *FluxImageUpdateJob) UpdateWithLatestSetting() error {
+	j.spec = &commonmodels.FluxImageUpdateJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	latestWorkflow, err := mongodb.NewWorkflowV4Coll().Find(j.workflow.Name)
+	if err != nil {
+		log.Errorf("Failed to find original workflow to set options, error: %s", err)
+		return err
+	}
+
+	latestSpec := new(commonmodels.FluxImageUpdateJobSpec)
+	found := false
+	for _, stage := range latestWorkflow.Stages {
+		if !found {
+			for _, job := range stage.Jobs {
+				if job.Name == j.job.Name && job.JobType == j.job.JobType {
+					if err := commonmodels.IToi(job.Spec, latestSpec); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+		} else {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("failed to find the original workflow: %s", j.workflow.Name)
+	}
+
+	// tags of the targets are allowed to be changed by user, others use the latest config
+	userTags := make(map[string]string)
+	for _, target := range j.spec.Targets {
+		userTags[fluxTargetKey(target)] = target.Tag
+	}
+	for _, target := range latestSpec.Targets {
+		if tag, ok := userTags[fluxTargetKey(target)]; ok {
+			target.Tag = tag
+		}
+	}
+
+	j.spec = latestSpec
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *FluxImageUpdateJob) MergeArgs(args *commonmodels.Job) error {
+	if j.job.Name == args.Name && j.job.JobType == args.JobType {
+		j.spec = &commonmodels.FluxImageUpdateJobSpec{}
+		if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+			return err
+		}
+		argsSpec := &commonmodels.FluxImageUpdateJobSpec{}
+		if err := commonmodels.IToi(args.Spec, argsSpec); err != nil {
+			return err
+		}
+		j.spec.Targets = argsSpec.Targets
+		j.job.Spec = j.spec
+	}
+	return nil
+}
+
+func (j *FluxImageUpdateJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
+	j.spec = &commonmodels.FluxImageUpdateJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return nil, err
+	}
+	j.job.Spec = j.spec
+
+	targets := make([]*commonmodels.FluxImageUpdateTarget, 0)
+	for _, target := range j.spec.Targets {
+		targets = append(targets, &commonmodels.FluxImageUpdateTarget{
+			Type:      target.Type,
+			FilePath:  target.FilePath,
+			ImageName: target.ImageName,
+			ValuePath: target.ValuePath,
+			Tag:       strings.TrimSpace(target.Tag),
+		})
+	}
+
+	jobTask := &commonmodels.JobTask{
+		Name: j.job.Name,
+		JobInfo: map[string]string{
+			JobNameKey: j.job.Name,
+		},
+		Key:     j.job.Name,
+		JobType: string(config.JobFluxImageUpdate),
+		Spec: &commonmodels.JobTaskFluxImageUpdateSpec{
+			CodehostID:    j.spec.CodehostID,
+			RepoOwner:     j.spec.RepoOwner,
+			RepoNamespace: j.spec.RepoNamespace,
+			RepoName:      j.spec.RepoName,
+			Branch:        j.spec.Branch,
+			CreatePR:      j.spec.CreatePR,
+			Env:           j.spec.Env,
+			Targets:       targets,
+		},
+		ErrorPolicy: j.job.ErrorPolicy,
+	}
+	return []*commonmodels.JobTask{jobTask}, nil
+}
+
+func (j *FluxImageUpdateJob) LintJob() error {
+	j.spec = &commonmodels.FluxImageUpdateJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	if err := gitops.ValidateRepo(j.spec.CodehostID, j.spec.GetRepoNamespace(), j.spec.RepoName, j.spec.Branch); err != nil {
+		return err
+	}
+
+	if len(j.spec.Targets) == 0 {
+		return fmt.Errorf("no target is set")
+	}
+	for _, target := range j.spec.Targets {
+		if target.FilePath == "" {
+			return fmt.Errorf("file path of the target is empty")
+		}
+		switch target.Type {
+		case config.FluxImageUpdateTypeKustomize:
+			if target.ImageName == "" {
+				return fmt.Errorf("image name of the target in %s is empty", target.FilePath)
+			}
+		case config.FluxImageUpdateTypeValue:
+			if target.ValuePath == "" {
+				return fmt.Errorf("value path of the target in %s is empty", target.FilePath)
+			}
+		default:
+			return fmt.Errorf("invalid flux image update type: %s", target.Type)
+		}
+	}
+	return nil
+}
+
+func fluxTargetKey(target *commonmodels.FluxImageUpdateTarget) string {
+	return fmt.Sprintf("%s/%s/%s/%s", target.Type, target.FilePath, target.ImageName, target.ValuePath)
+}
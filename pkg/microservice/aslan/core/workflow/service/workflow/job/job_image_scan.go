@@ -0,0 +1,331 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/types/job"
+)
+
+const ImageScanTimeout int64 = 30
+
+type ImageScanJob struct {
+	job      *commonmodels.Job
+	workflow *commonmodels.WorkflowV4
+	spec     *commonmodels.ZadigImageScanJobSpec
+}
+
+func (j *ImageScanJob) Instantiate() error {
+	j.spec = &commonmodels.ZadigImageScanJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ImageScanJob) SetPreset() error {
+	j.spec = &commonmodels.ZadigImageScanJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	if j.spec.Source == config.SourceFromJob {
+		j.spec.OriginJobName = j.spec.JobName
+		targets, err := j.getOriginReferredJobTargets(j.spec.JobName)
+		if err != nil {
+			return fmt.Errorf("failed to get referred job info for image scan job: %s, error: %s", j.job.Name, err)
+		}
+		j.spec.Targets = targets
+	}
+
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ImageScanJob) SetOptions() error {
+	return nil
+}
+
+func (j *ImageScanJob) ClearSelectionField() error {
+	j.spec = &commonmodels.ZadigImageScanJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	j.spec.Targets = make([]*commonmodels.ImageScanTarget, 0)
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ImageScanJob) MergeArgs(args *commonmodels.Job) error {
+	if j.job.Name == args.Name && j.job.JobType == args.JobType {
+		j.spec = &commonmodels.ZadigImageScanJobSpec{}
+		if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+			return err
+		}
+		argsSpec := &commonmodels.ZadigImageScanJobSpec{}
+		if err := commonmodels.IToi(args.Spec, argsSpec); err != nil {
+			return err
+		}
+		j.spec.Targets = argsSpec.Targets
+		j.job.Spec = j.spec
+	}
+	return nil
+}
+
+func (j *ImageScanJob) UpdateWithLatestSetting() error {
+	j.spec = &commonmodels.ZadigImageScanJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	latestWorkflow, err := commonrepo.NewWorkflowV4Coll().Find(j.workflow.Name)
+	if err != nil {
+		log.Errorf("Failed to find original workflow to set options, error: %s", err)
+		return err
+	}
+
+	latestSpec := new(commonmodels.ZadigImageScanJobSpec)
+	found := false
+	for _, stage := range latestWorkflow.Stages {
+		if !found {
+			for _, job := range stage.Jobs {
+				if job.Name == j.job.Name && job.JobType == j.job.JobType {
+					if err := commonmodels.IToi(job.Spec, latestSpec); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+		} else {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("failed to find the original workflow: %s", j.workflow.Name)
+	}
+
+	// the targets input at runtime are meaningless if the source has been changed to fromjob.
+	if j.spec.Source != latestSpec.Source {
+		j.spec.Targets = make([]*commonmodels.ImageScanTarget, 0)
+	}
+	j.spec.Source = latestSpec.Source
+	j.spec.JobName = latestSpec.JobName
+	j.spec.OriginJobName = latestSpec.OriginJobName
+	j.spec.Scanner = latestSpec.Scanner
+	j.spec.ScannerImage = latestSpec.ScannerImage
+	j.spec.IgnoreUnfixed = latestSpec.IgnoreUnfixed
+	j.spec.Thresholds = latestSpec.Thresholds
+	j.spec.Timeout = latestSpec.Timeout
+	j.spec.ClusterID = latestSpec.ClusterID
+	j.spec.StrategyID = latestSpec.StrategyID
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ImageScanJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
+	logger := log.SugaredLogger()
+	resp := []*commonmodels.JobTask{}
+
+	j.spec = &commonmodels.ZadigImageScanJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return resp, err
+	}
+
+	if j.spec.Source == config.SourceFromJob {
+		targets, err := j.getOriginReferredJobTargets(j.spec.JobName)
+		if err != nil {
+			return resp, fmt.Errorf("failed to get referred job info for image scan job: %s, error: %s", j.job.Name, err)
+		}
+		j.spec.Targets = targets
+	}
+
+	registries, err := commonservice.ListRegistryNamespaces("", true, logger)
+	if err != nil {
+		return resp, err
+	}
+
+	timeout := j.spec.Timeout
+	if timeout <= 0 {
+		timeout = ImageScanTimeout
+	}
+	for _, target := range j.spec.Targets {
+		jobTaskSpec := &commonmodels.JobTaskImageScanSpec{
+			Properties: commonmodels.JobProperties{
+				Timeout:         timeout,
+				ResourceRequest: setting.MinRequest,
+				ClusterID:       j.spec.ClusterID,
+				StrategyID:      j.spec.StrategyID,
+				Registries:      registries,
+			},
+			ServiceName:   target.ServiceName,
+			ServiceModule: target.ServiceModule,
+			Image:         target.Image,
+			Scanner:       j.spec.Scanner,
+			ScannerImage:  j.spec.ScannerImage,
+			IgnoreUnfixed: j.spec.IgnoreUnfixed,
+			Thresholds:    j.spec.Thresholds,
+		}
+		jobTask := &commonmodels.JobTask{
+			Name: jobNameFormat(target.ServiceName + "-" + target.ServiceModule + "-" + j.job.Name),
+			Key:  strings.Join([]string{j.job.Name, target.ServiceName, target.ServiceModule}, "."),
+			JobInfo: map[string]string{
+				"service_name":   target.ServiceName,
+				"service_module": target.ServiceModule,
+				JobNameKey:       j.job.Name,
+			},
+			JobType:     string(config.JobZadigImageScan),
+			Spec:        jobTaskSpec,
+			Timeout:     timeout,
+			ErrorPolicy: j.job.ErrorPolicy,
+		}
+		resp = append(resp, jobTask)
+	}
+
+	j.job.Spec = j.spec
+	return resp, nil
+}
+
+func (j *ImageScanJob) LintJob() error {
+	j.spec = &commonmodels.ZadigImageScanJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	switch j.spec.Scanner {
+	case config.ImageScannerTrivy, config.ImageScannerGrype:
+	default:
+		return fmt.Errorf("unsupported image scanner: %s", j.spec.Scanner)
+	}
+
+	severities := sets.NewString(config.ImageScanSeverities...)
+	for _, threshold := range j.spec.Thresholds {
+		if !severities.Has(threshold.Severity) {
+			return fmt.Errorf("invalid severity %s in thresholds, it should be one of %s", threshold.Severity, strings.Join(config.ImageScanSeverities, ", "))
+		}
+		if threshold.Max < 0 {
+			return fmt.Errorf("threshold of severity %s can not be negative", threshold.Severity)
+		}
+	}
+
+	if j.spec.Source != config.SourceFromJob {
+		return nil
+	}
+	jobRankMap := getJobRankMap(j.workflow.Stages)
+	referredJobRank, ok := jobRankMap[j.spec.JobName]
+	if !ok || referredJobRank >= jobRankMap[j.job.Name] {
+		return fmt.Errorf("can not quote job %s in job %s", j.spec.JobName, j.job.Name)
+	}
+	return nil
+}
+
+// GetOutPuts returns the count of vulnerabilities of each severity for every scanned image.
+func (j *ImageScanJob) GetOutPuts(log *zap.SugaredLogger) []string {
+	resp := []string{}
+	j.spec = &commonmodels.ZadigImageScanJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return resp
+	}
+
+	targets := j.spec.Targets
+	if j.spec.Source == config.SourceFromJob {
+		referredTargets, err := j.getOriginReferredJobTargets(j.spec.JobName)
+		if err != nil {
+			log.Errorf("failed to get referred job info for image scan job: %s, error: %s", j.job.Name, err)
+			return resp
+		}
+		targets = referredTargets
+	}
+
+	outputs := make([]*commonmodels.Output, 0, len(config.ImageScanSeverities))
+	for _, severity := range config.ImageScanSeverities {
+		outputs = append(outputs, &commonmodels.Output{Name: severity})
+	}
+	for _, target := range targets {
+		jobKey := strings.Join([]string{j.job.Name, target.ServiceName, target.ServiceModule}, ".")
+		resp = append(resp, getOutputKey(jobKey, outputs)...)
+	}
+	return resp
+}
+
+// getOriginReferredJobTargets gets the services from the origin build job, and the images from the referred build or
+// distribute job, the images are rendered when the referred job finished.
+func (j *ImageScanJob) getOriginReferredJobTargets(imageReferredJob string) ([]*commonmodels.ImageScanTarget, error) {
+	serviceReferredJob := getOriginJobName(j.workflow, imageReferredJob)
+	targets := []*commonmodels.ImageScanTarget{}
+	found := false
+serviceLoop:
+	for _, stage := range j.workflow.Stages {
+		for _, job := range stage.Jobs {
+			if job.Name != serviceReferredJob {
+				continue
+			}
+			switch job.JobType {
+			case config.JobZadigBuild:
+				buildSpec := &commonmodels.ZadigBuildJobSpec{}
+				if err := commonmodels.IToi(job.Spec, buildSpec); err != nil {
+					return nil, fmt.Errorf("failed to decode build job spec, error: %s", err)
+				}
+				for _, build := range buildSpec.ServiceAndBuilds {
+					targets = append(targets, &commonmodels.ImageScanTarget{
+						ServiceName:   build.ServiceName,
+						ServiceModule: build.ServiceModule,
+					})
+				}
+			case config.JobZadigDistributeImage:
+				distributeSpec := &commonmodels.ZadigDistributeImageJobSpec{}
+				if err := commonmodels.IToi(job.Spec, distributeSpec); err != nil {
+					return nil, fmt.Errorf("failed to decode distribute job spec, error: %s", err)
+				}
+				for _, distribute := range distributeSpec.Targets {
+					targets = append(targets, &commonmodels.ImageScanTarget{
+						ServiceName:   distribute.ServiceName,
+						ServiceModule: distribute.ServiceModule,
+					})
+				}
+			default:
+				return nil, fmt.Errorf("referred job %s is neither a build job nor a distribute job", serviceReferredJob)
+			}
+			found = true
+			break serviceLoop
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("referred service job %s not found", serviceReferredJob)
+	}
+
+	// both the build job and the distribute job output the image of each module with the same key
+	for _, target := range targets {
+		target.Image = job.GetJobOutputKey(fmt.Sprintf("%s.%s.%s", imageReferredJob, target.ServiceName, target.ServiceModule), IMAGEKEY)
+	}
+	return targets, nil
+}
@@ -18,10 +18,14 @@ package job
 
 import (
 	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
 
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
 	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
 	"github.com/koderover/zadig/v2/pkg/setting"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
@@ -238,7 +242,61 @@ func (j *K8sPacthJob) LintJob() error {
 		return e.ErrLicenseInvalid.AddDesc("")
 	}
 
-	return nil
+	j.spec = &commonmodels.K8sPatchJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	return lintPatchItems(j.spec)
+}
+
+// lintPatchItems checks the patch contents against the schema of the target cluster, patches still containing
+// params to be filled at runtime and json patches are skipped.
+func lintPatchItems(spec *commonmodels.K8sPatchJobSpec) error {
+	if spec.ClusterID == "" || strings.Contains(spec.ClusterID, "{{") {
+		return nil
+	}
+
+	manifests := make([]string, 0)
+	for _, patch := range spec.PatchItems {
+		if patch.PatchStrategy == "json" {
+			continue
+		}
+		content := renderString(patch.PatchContent, setting.RenderValueTemplate, patch.Params)
+		if strings.Contains(content, "{{") {
+			continue
+		}
+		resource := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(content), &resource); err != nil {
+			return fmt.Errorf("invalid patch content of %s/%s: %s", patch.ResourceKind, patch.ResourceName, err)
+		}
+		apiVersion := patch.ResourceVersion
+		if patch.ResourceGroup != "" {
+			apiVersion = fmt.Sprintf("%s/%s", patch.ResourceGroup, patch.ResourceVersion)
+		}
+		resource["apiVersion"] = apiVersion
+		resource["kind"] = patch.ResourceKind
+		metadata, ok := resource["metadata"].(map[string]interface{})
+		if !ok {
+			metadata = map[string]interface{}{}
+		}
+		metadata["name"] = patch.ResourceName
+		resource["metadata"] = metadata
+		manifest, err := yaml.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, string(manifest))
+	}
+	if len(manifests) == 0 {
+		return nil
+	}
+
+	findings, err := kube.LintManifests([]string{spec.ClusterID}, manifests, true)
+	if err != nil {
+		log.Warnf("failed to lint patch contents, err: %s", err)
+		return nil
+	}
+	return kube.ShouldBlockManifests(findings)
 }
 
 func patchJobToTaskJob(job *commonmodels.K8sPatchJobSpec) *commonmodels.JobTasK8sPatchSpec {
@@ -0,0 +1,426 @@
+/*
+Copyright 2022 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+type JobReferenceReason string
+
+const (
+	// JobReferenceNotFound means the referenced job is deleted or renamed
+	JobReferenceNotFound JobReferenceReason = "not_found"
+	// JobReferenceInvalidType means the referenced job can not be used as the source of the job
+	JobReferenceInvalidType JobReferenceReason = "invalid_type"
+	// JobReferenceNotUpstream means the referenced job is not in a previous position of the job
+	JobReferenceNotUpstream JobReferenceReason = "not_upstream"
+)
+
+// JobReferenceIssue is a broken reference from a job to another job of the same workflow
+type JobReferenceIssue struct {
+	JobName    string             `json:"job_name"`
+	JobType    config.JobType     `json:"job_type"`
+	Field      string             `json:"field"`
+	RefJobName string             `json:"ref_job_name"`
+	Reason     JobReferenceReason `json:"reason"`
+	// Suggestions are the jobs which can be referenced instead, the most similar one comes first
+	Suggestions []string `json:"suggestions"`
+}
+
+func (i *JobReferenceIssue) Error() string {
+	var msg string
+	switch i.Reason {
+	case JobReferenceNotFound:
+		msg = fmt.Sprintf("job [%s] refers to job [%s] in %s which does not exist, it may have been deleted or renamed", i.JobName, i.RefJobName, i.Field)
+	case JobReferenceInvalidType:
+		msg = fmt.Sprintf("job [%s] refers to job [%s] in %s whose type can not be used as its source", i.JobName, i.RefJobName, i.Field)
+	default:
+		msg = fmt.Sprintf("job [%s] refers to job [%s] in %s which is not executed before it", i.JobName, i.RefJobName, i.Field)
+	}
+	if len(i.Suggestions) > 0 {
+		msg += fmt.Sprintf(", available jobs: %s", strings.Join(i.Suggestions, ", "))
+	}
+	return msg
+}
+
+// jobReference describes the field of a job spec which refers to another job
+type jobReference struct {
+	field   string
+	allowed []config.JobType
+	// get returns the referenced job name, empty means the job does not refer to any job
+	get func(job *commonmodels.Job) (string, error)
+	set func(job *commonmodels.Job, name string) error
+}
+
+var (
+	serviceSourceJobTypes = []config.JobType{config.JobZadigBuild, config.JobZadigDistributeImage, config.JobZadigDeploy}
+	testingSourceJobTypes = []config.JobType{config.JobZadigBuild, config.JobZadigDistributeImage, config.JobZadigDeploy, config.JobZadigScanning}
+)
+
+var jobReferences = map[config.JobType]*jobReference{
+	config.JobZadigDeploy: {
+		field:   "job_name",
+		allowed: serviceSourceJobTypes,
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.ZadigDeployJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Source != config.SourceFromJob {
+				return "", err
+			}
+			return spec.JobName, nil
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.ZadigDeployJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.JobName, spec.OriginJobName = name, name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobZadigVMDeploy: {
+		field:   "job_name",
+		allowed: []config.JobType{config.JobZadigBuild},
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.ZadigVMDeployJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Source != config.SourceFromJob {
+				return "", err
+			}
+			return spec.JobName, nil
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.ZadigVMDeployJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.JobName, spec.OriginJobName = name, name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobZadigDistributeImage: {
+		field:   "job_name",
+		allowed: serviceSourceJobTypes,
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.ZadigDistributeImageJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Source != config.SourceFromJob {
+				return "", err
+			}
+			return spec.JobName, nil
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.ZadigDistributeImageJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.JobName = name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobZadigTesting: {
+		field:   "job_name",
+		allowed: testingSourceJobTypes,
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.ZadigTestingJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Source != config.SourceFromJob {
+				return "", err
+			}
+			return spec.JobName, nil
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.ZadigTestingJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.JobName, spec.OriginJobName = name, name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobZadigScanning: {
+		field:   "job_name",
+		allowed: testingSourceJobTypes,
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.ZadigScanningJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Source != config.SourceFromJob {
+				return "", err
+			}
+			return spec.JobName, nil
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.ZadigScanningJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.JobName, spec.OriginJobName = name, name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobZadigImageScan: {
+		field:   "job_name",
+		allowed: []config.JobType{config.JobZadigBuild, config.JobZadigDistributeImage},
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.ZadigImageScanJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Source != config.SourceFromJob {
+				return "", err
+			}
+			return spec.JobName, nil
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.ZadigImageScanJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.JobName, spec.OriginJobName = name, name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobWorkflowTrigger: {
+		field:   "source_job_name",
+		allowed: serviceSourceJobTypes,
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.WorkflowTriggerJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil || spec.Source != config.TriggerWorkflowSourceFromJob {
+				return "", err
+			}
+			return spec.SourceJobName, nil
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.WorkflowTriggerJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.SourceJobName = name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobK8sBlueGreenRelease: {
+		field:   "from_job",
+		allowed: []config.JobType{config.JobK8sBlueGreenDeploy},
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.BlueGreenReleaseV2JobSpec{}
+			err := commonmodels.IToi(job.Spec, spec)
+			return spec.FromJob, err
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.BlueGreenReleaseV2JobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.FromJob = name
+			job.Spec = spec
+			return nil
+		},
+	},
+	config.JobK8sCanaryRelease: {
+		field:   "from_job",
+		allowed: []config.JobType{config.JobK8sCanaryDeploy},
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.CanaryReleaseJobSpec{}
+			err := commonmodels.IToi(job.Spec, spec)
+			return spec.FromJob, err
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.CanaryReleaseJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.FromJob = name
+			job.Spec = spec
+			return nil
+		},
+	},
+	// the first gray release job does not refer to any job
+	config.JobK8sGrayRelease: {
+		field:   "from_job",
+		allowed: []config.JobType{config.JobK8sGrayRelease},
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.GrayReleaseJobSpec{}
+			err := commonmodels.IToi(job.Spec, spec)
+			return spec.FromJob, err
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.GrayReleaseJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.FromJob = name
+			job.Spec = spec
+			return nil
+		},
+	},
+	// the first istio release job does not refer to any job
+	config.JobIstioRelease: {
+		field:   "from_job",
+		allowed: []config.JobType{config.JobIstioRelease},
+		get: func(job *commonmodels.Job) (string, error) {
+			spec := &commonmodels.IstioJobSpec{}
+			err := commonmodels.IToi(job.Spec, spec)
+			return spec.FromJob, err
+		},
+		set: func(job *commonmodels.Job, name string) error {
+			spec := &commonmodels.IstioJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			spec.FromJob = name
+			job.Spec = spec
+			return nil
+		},
+	},
+}
+
+// CheckJobReferences checks that every job referring to another job (source=fromjob, from_job and so on)
+// refers to an existing job of an allowed type executed before it.
+func CheckJobReferences(workflow *commonmodels.WorkflowV4) ([]*JobReferenceIssue, error) {
+	jobRankMap := getJobRankMap(workflow.Stages)
+	jobTypeMap := make(map[string]config.JobType)
+	for _, stage := range workflow.Stages {
+		for _, job := range stage.Jobs {
+			jobTypeMap[job.Name] = job.JobType
+		}
+	}
+
+	issues := make([]*JobReferenceIssue, 0)
+	for _, stage := range workflow.Stages {
+		for _, job := range stage.Jobs {
+			ref, ok := jobReferences[job.JobType]
+			if !ok {
+				continue
+			}
+			refJobName, err := ref.get(job)
+			if err != nil {
+				return nil, warpJobError(job.Name, fmt.Errorf("failed to decode job spec: %v", err))
+			}
+			if refJobName == "" {
+				continue
+			}
+
+			issue := &JobReferenceIssue{
+				JobName:    job.Name,
+				JobType:    job.JobType,
+				Field:      ref.field,
+				RefJobName: refJobName,
+			}
+			refJobType, exists := jobTypeMap[refJobName]
+			switch {
+			case !exists:
+				issue.Reason = JobReferenceNotFound
+			case !containsJobType(ref.allowed, refJobType):
+				issue.Reason = JobReferenceInvalidType
+			case jobRankMap[refJobName] >= jobRankMap[job.Name]:
+				issue.Reason = JobReferenceNotUpstream
+			default:
+				continue
+			}
+			issue.Suggestions = suggestReferencedJobs(workflow, job.Name, refJobName, ref.allowed, jobRankMap)
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// RepairJobReferences points the broken references to the only available job,
+// the issues with zero or more than one suggestion are returned as unresolved.
+func RepairJobReferences(workflow *commonmodels.WorkflowV4) (repaired, unresolved []*JobReferenceIssue, err error) {
+	issues, err := CheckJobReferences(workflow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repaired, unresolved = make([]*JobReferenceIssue, 0), make([]*JobReferenceIssue, 0)
+	issueMap := make(map[string]*JobReferenceIssue)
+	for _, issue := range issues {
+		if len(issue.Suggestions) == 1 {
+			issueMap[issue.JobName] = issue
+		} else {
+			unresolved = append(unresolved, issue)
+		}
+	}
+
+	for _, stage := range workflow.Stages {
+		for _, job := range stage.Jobs {
+			issue, ok := issueMap[job.Name]
+			if !ok {
+				continue
+			}
+			if err := jobReferences[job.JobType].set(job, issue.Suggestions[0]); err != nil {
+				return nil, nil, warpJobError(job.Name, fmt.Errorf("failed to update job spec: %v", err))
+			}
+			repaired = append(repaired, issue)
+		}
+	}
+	return repaired, unresolved, nil
+}
+
+// suggestReferencedJobs lists the upstream jobs of allowed types, sorted by the similarity of their names to refJobName
+func suggestReferencedJobs(workflow *commonmodels.WorkflowV4, jobName, refJobName string, allowed []config.JobType, jobRankMap map[string]int) []string {
+	suggestions := make([]string, 0)
+	for _, stage := range workflow.Stages {
+		for _, job := range stage.Jobs {
+			if job.Name == jobName || !containsJobType(allowed, job.JobType) || jobRankMap[job.Name] >= jobRankMap[jobName] {
+				continue
+			}
+			suggestions = append(suggestions, job.Name)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return editDistance(suggestions[i], refJobName) < editDistance(suggestions[j], refJobName)
+	})
+	return suggestions
+}
+
+func containsJobType(jobTypes []config.JobType, jobType config.JobType) bool {
+	for _, t := range jobTypes {
+		if t == jobType {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance returns the levenshtein distance of a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/kube"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+)
+
+type ZadigRollbackJob struct {
+	job      *commonmodels.Job
+	workflow *commonmodels.WorkflowV4
+	spec     *commonmodels.ZadigRollbackJobSpec
+}
+
+func (j *ZadigRollbackJob) Instantiate() error {
+	j.spec = &commonmodels.ZadigRollbackJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ZadigRollbackJob) SetPreset() error {
+	j.spec = &commonmodels.ZadigRollbackJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ZadigRollbackJob) SetOptions() error {
+	return nil
+}
+
+func (j *ZadigRollbackJob) ClearSelectionField() error {
+	return nil
+}
+
+func (j *ZadigRollbackJob) UpdateWithLatestSetting() error {
+	j.spec = &commonmodels.ZadigRollbackJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	latestWorkflow, err := mongodb.NewWorkflowV4Coll().Find(j.workflow.Name)
+	if err != nil {
+		log.Errorf("Failed to find original workflow to set options, error: %s", err)
+		return err
+	}
+
+	latestSpec := new(commonmodels.ZadigRollbackJobSpec)
+	found := false
+	for _, stage := range latestWorkflow.Stages {
+		if !found {
+			for _, job := range stage.Jobs {
+				if job.Name == j.job.Name && job.JobType == j.job.JobType {
+					if err := commonmodels.IToi(job.Spec, latestSpec); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+		} else {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("failed to find the original workflow: %s", j.workflow.Name)
+	}
+
+	// the env and production flag use the latest config, the rollback target is chosen by user
+	latestSpec.Source = j.spec.Source
+	latestSpec.WorkflowName = j.spec.WorkflowName
+	latestSpec.TaskID = j.spec.TaskID
+	latestSpec.SnapshotID = j.spec.SnapshotID
+	latestSpec.Services = j.spec.Services
+
+	j.spec = latestSpec
+	j.job.Spec = j.spec
+	return nil
+}
+
+func (j *ZadigRollbackJob) MergeArgs(args *commonmodels.Job) error {
+	if j.job.Name == args.Name && j.job.JobType == args.JobType {
+		j.spec = &commonmodels.ZadigRollbackJobSpec{}
+		if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+			return err
+		}
+		argsSpec := &commonmodels.ZadigRollbackJobSpec{}
+		if err := commonmodels.IToi(args.Spec, argsSpec); err != nil {
+			return err
+		}
+		j.spec.Env = argsSpec.Env
+		j.spec.Source = argsSpec.Source
+		j.spec.WorkflowName = argsSpec.WorkflowName
+		j.spec.TaskID = argsSpec.TaskID
+		j.spec.SnapshotID = argsSpec.SnapshotID
+		j.spec.Services = argsSpec.Services
+		j.job.Spec = j.spec
+	}
+	return nil
+}
+
+func (j *ZadigRollbackJob) ToJobs(taskID int64) ([]*commonmodels.JobTask, error) {
+	j.spec = &commonmodels.ZadigRollbackJobSpec{}
+	if err := commonmodels.IToi(j.job.Spec, j.spec); err != nil {
+		return nil, err
+	}
+	j.job.Spec = j.spec
+
+	env, err := mongodb.NewProductColl().Find(&mongodb.ProductFindOptions{
+		Name:       j.workflow.Project,
+		EnvName:    j.spec.Env,
+		Production: &j.spec.Production,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find env %s of project %s, error: %v", j.spec.Env, j.workflow.Project, err)
+	}
+
+	// resolve the versions when the task is created, so that an invalid rollback target fails fast
+	versions, err := kube.FindRollbackServiceVersions(j.rollbackArgs())
+	if err != nil {
+		return nil, err
+	}
+	services := make([]*commonmodels.RollbackServiceTarget, 0, len(versions))
+	for _, version := range versions {
+		services = append(services, &commonmodels.RollbackServiceTarget{
+			ServiceName: kube.GetRollbackServiceName(version),
+			Revision:    version.Revision,
+			Images:      kube.GetRollbackServiceImages(version),
+			Status:      config.StatusPrepare,
+		})
+	}
+
+	jobTask := &commonmodels.JobTask{
+		Name: j.job.Name,
+		JobInfo: map[string]string{
+			JobNameKey: j.job.Name,
+		},
+		Key:     j.job.Name,
+		JobType: string(config.JobZadigRollback),
+		Spec: &commonmodels.JobTaskZadigRollbackSpec{
+			Env:          j.spec.Env,
+			Namespace:    env.Namespace,
+			Production:   j.spec.Production,
+			Source:       j.spec.Source,
+			WorkflowName: j.spec.WorkflowName,
+			TaskID:       j.spec.TaskID,
+			SnapshotID:   j.spec.SnapshotID,
+			Services:     services,
+		},
+		ErrorPolicy: j.job.ErrorPolicy,
+	}
+	return []*commonmodels.JobTask{jobTask}, nil
+}
+
+func (j *ZadigRollbackJob) rollbackArgs() *kube.EnvRollbackArgs {
+	return &kube.EnvRollbackArgs{
+		ProjectName:  j.workflow.Project,
+		EnvName:      j.spec.Env,
+		Production:   j.spec.Production,
+		Source:       j.spec.Source,
+		WorkflowName: j.spec.WorkflowName,
+		TaskID:       j.spec.TaskID,
+		SnapshotID:   j.spec.SnapshotID,
+		Services:     j.spec.Services,
+	}
+}
+
+func (j *ZadigRollbackJob) LintJob() error {
+	j.spec = &commonmodels.ZadigRollbackJobSpec{}
+	if err := commonmodels.IToiYaml(j.job.Spec, j.spec); err != nil {
+		return err
+	}
+
+	switch j.spec.Source {
+	case config.RollbackSourceWorkflowTask, config.RollbackSourceEnvSnapshot:
+	default:
+		return fmt.Errorf("invalid rollback source: %s", j.spec.Source)
+	}
+	return nil
+}
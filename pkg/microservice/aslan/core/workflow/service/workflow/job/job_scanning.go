@@ -19,6 +19,7 @@ package job
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/koderover/zadig/v2/pkg/setting"
@@ -393,6 +394,7 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 		Infrastructure: scanningInfo.Infrastructure,
 		VMLabels:       scanningInfo.VMLabels,
 		ErrorPolicy:    j.job.ErrorPolicy,
+		NotifyCtls:     j.job.NotifyCtls,
 	}
 	envs := getScanningJobVariables(scanning.Repos, taskID, j.workflow.Project, j.workflow.Name, j.workflow.DisplayName, jobTask.Infrastructure, scanningType, serviceName, serviceModule, scanning.Name)
 	envs = append(envs, scanningInfo.Envs...)
@@ -495,11 +497,12 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 	}
 
 	// init git clone step
+	repos := renderRepos(scanning.Repos, scanningInfo.Repos, jobTaskSpec.Properties.Envs)
 	gitStep := &commonmodels.StepTask{
 		Name:     scanning.Name + "-git",
 		JobName:  jobTask.Name,
 		StepType: config.StepGit,
-		Spec:     step.StepGitSpec{Repos: renderRepos(scanning.Repos, scanningInfo.Repos, jobTaskSpec.Properties.Envs)},
+		Spec:     step.StepGitSpec{Repos: repos},
 	}
 	jobTaskSpec.Steps = append(jobTaskSpec.Steps, gitStep)
 	repoName := ""
@@ -556,7 +559,15 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 		}
 
 		projectKey := renderEnv(sonar.GetSonarProjectKeyFromConfig(scanningInfo.Parameter), jobTaskSpec.Properties.Envs)
-		resultAddr, err := sonar.GetSonarAddressWithProjectKey(sonarInfo.ServerAddress, projectKey)
+		parameter := scanningInfo.Parameter
+		pullRequest := getSonarPullRequest(repos)
+		var resultAddr string
+		if pullRequest != nil {
+			parameter = sonar.SetPullRequestParameters(parameter, pullRequest.ID, pullRequest.Branch, pullRequest.Base)
+			resultAddr, err = sonar.GetSonarAddressWithPullRequest(sonarInfo.ServerAddress, projectKey, pullRequest.ID)
+		} else {
+			resultAddr, err = sonar.GetSonarAddressWithProjectKey(sonarInfo.ServerAddress, projectKey)
+		}
 		if err != nil {
 			log.Errorf("failed to get sonar address with project key, error: %s", err)
 		}
@@ -582,7 +593,7 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 				JobName: jobTask.Name,
 			}
 			if scanningInfo.ScriptType == types.ScriptTypeShell || scanningInfo.ScriptType == "" {
-				sonarConfig := fmt.Sprintf("sonar.login=%s\nsonar.host.url=%s\n%s", sonarInfo.Token, sonarInfo.ServerAddress, scanningInfo.Parameter)
+				sonarConfig := fmt.Sprintf("sonar.login=%s\nsonar.host.url=%s\n%s", sonarInfo.Token, sonarInfo.ServerAddress, parameter)
 				sonarConfig = strings.ReplaceAll(sonarConfig, "$branch", branch)
 				sonarScript := fmt.Sprintf("set -e\ncd %s\ncat > sonar-project.properties << EOF\n%s\nEOF\nsonar-scanner", repoName, renderEnv(sonarConfig, jobTaskSpec.Properties.Envs))
 
@@ -596,7 +607,7 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 				sonarScript := fmt.Sprintf("@echo off\nsetlocal enabledelayedexpansion\ncd %s\n\n", repoName)
 				sonarScript += "(\n"
 
-				sonarConfig := fmt.Sprintf("sonar.login=%s\nsonar.host.url=%s\n%s", sonarInfo.Token, sonarInfo.ServerAddress, scanningInfo.Parameter)
+				sonarConfig := fmt.Sprintf("sonar.login=%s\nsonar.host.url=%s\n%s", sonarInfo.Token, sonarInfo.ServerAddress, parameter)
 				sonarConfig = strings.ReplaceAll(sonarConfig, "$branch", branch)
 				sonarConfig = renderEnv(sonarConfig, jobTaskSpec.Properties.Envs)
 				sonarConfigArr := strings.Split(sonarConfig, "\n")
@@ -615,7 +626,7 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 				sonarScript := fmt.Sprintf("Set-StrictMode -Version Latest\nSet-Location -Path \"%s\"\n", repoName)
 				sonarScript += "@\"\n"
 
-				sonarConfig := fmt.Sprintf("sonar.login=%s\nsonar.host.url=%s\n%s", sonarInfo.Token, sonarInfo.ServerAddress, scanningInfo.Parameter)
+				sonarConfig := fmt.Sprintf("sonar.login=%s\nsonar.host.url=%s\n%s", sonarInfo.Token, sonarInfo.ServerAddress, parameter)
 				sonarConfig = strings.ReplaceAll(sonarConfig, "$branch", branch)
 				sonarConfig = renderEnv(sonarConfig, jobTaskSpec.Properties.Envs)
 				sonarConfigArr := strings.Split(sonarConfig, "\n")
@@ -648,6 +659,8 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 				SonarToken:       sonarInfo.Token,
 				SonarServer:      sonarInfo.ServerAddress,
 				CheckQualityGate: scanningInfo.CheckQualityGate,
+				PullRequest:      pullRequest,
+				ScanningName:     scanning.Name,
 			},
 		}
 		jobTaskSpec.Steps = append(jobTaskSpec.Steps, sonarGetMetricsStep)
@@ -668,6 +681,12 @@ func (j *ScanningJob) toJobTask(scanning *commonmodels.ScanningModule, taskID in
 			}
 			jobTaskSpec.Steps = append(jobTaskSpec.Steps, sonarChekStep)
 		}
+	} else if scanningInfo.ScannerType == types.ScanningTypeSemgrep || scanningInfo.ScannerType == types.ScanningTypeCheckmarx {
+		scannerSteps, err := j.getScannerSteps(scanning.Name, scanningInfo, jobTask, jobTaskSpec, repoName, branch, taskID)
+		if err != nil {
+			return nil, err
+		}
+		jobTaskSpec.Steps = append(jobTaskSpec.Steps, scannerSteps...)
 	} else {
 		scriptStep := &commonmodels.StepTask{
 			JobName: jobTask.Name,
@@ -823,6 +842,7 @@ func fillScanningDetail(moduleScanning *commonmodels.Scanning) error {
 	moduleScanning.EnableScanner = templateInfo.EnableScanner
 	moduleScanning.ImageID = templateInfo.ImageID
 	moduleScanning.SonarID = templateInfo.SonarID
+	moduleScanning.ScannerIntegrationID = templateInfo.ScannerIntegrationID
 	moduleScanning.Installs = templateInfo.Installs
 	moduleScanning.Parameter = templateInfo.Parameter
 	moduleScanning.Envs = commonservice.MergeBuildEnvs(templateInfo.Envs, moduleScanning.Envs)
@@ -834,6 +854,150 @@ func fillScanningDetail(moduleScanning *commonmodels.Scanning) error {
 	return nil
 }
 
+// getSonarPullRequest returns the pull request of the primary scanning repo, nil is returned if the scanning is not
+// running for a pull request.
+func getSonarPullRequest(repos []*types.Repository) *step.SonarPullRequest {
+	if len(repos) == 0 {
+		return nil
+	}
+	repo := repos[0]
+	prID := repo.PR
+	if len(repo.PRs) > 0 {
+		prID = repo.PRs[len(repo.PRs)-1]
+	}
+	if prID <= 0 {
+		return nil
+	}
+
+	namespace := repo.RepoNamespace
+	if namespace == "" {
+		namespace = repo.RepoOwner
+	}
+	return &step.SonarPullRequest{
+		CodehostID:    repo.CodehostID,
+		RepoNamespace: namespace,
+		RepoName:      repo.RepoName,
+		ID:            prID,
+		// the source branch is not kept in the repo info, the pull request id is used to name the analysis instead
+		Branch: fmt.Sprintf("pr-%d", prID),
+		Base:   repo.Branch,
+	}
+}
+
+// getScannerSteps generates the steps of semgrep/checkmarx scanning, the scanner writes a json report
+// into the repo which is uploaded by the scanner report step and parsed when the step is done.
+func (j *ScanningJob) getScannerSteps(name string, scanningInfo *commonmodels.Scanning, jobTask *commonmodels.JobTask, jobTaskSpec *commonmodels.JobTaskFreestyleSpec, repoName, branch string, taskID int64) ([]*commonmodels.StepTask, error) {
+	resp := make([]*commonmodels.StepTask, 0)
+
+	integration, err := commonrepo.NewScannerIntegrationColl().GetByID(context.TODO(), scanningInfo.ScannerIntegrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s integration information to create scanning task, error: %s", scanningInfo.ScannerType, err)
+	}
+	if integration.Type != scanningInfo.ScannerType {
+		return nil, fmt.Errorf("scanner integration %s is not of type %s", integration.Name, scanningInfo.ScannerType)
+	}
+
+	var command string
+	switch scanningInfo.ScannerType {
+	case types.ScanningTypeSemgrep:
+		if integration.Token != "" {
+			jobTaskSpec.Properties.Envs = append(jobTaskSpec.Properties.Envs, &commonmodels.KeyVal{
+				Key:          "SEMGREP_APP_TOKEN",
+				Value:        integration.Token,
+				IsCredential: true,
+			})
+			if integration.ServerAddress != "" {
+				jobTaskSpec.Properties.Envs = append(jobTaskSpec.Properties.Envs, &commonmodels.KeyVal{
+					Key:   "SEMGREP_APP_URL",
+					Value: integration.ServerAddress,
+				})
+			}
+			command = fmt.Sprintf("semgrep ci --json --output %s %s", setting.ScannerReportFileName, scanningInfo.Parameter)
+		} else {
+			parameter := scanningInfo.Parameter
+			if parameter == "" {
+				parameter = "--config auto"
+			}
+			command = fmt.Sprintf("semgrep scan --json --output %s %s", setting.ScannerReportFileName, parameter)
+		}
+	case types.ScanningTypeCheckmarx:
+		// the checkmarx cli reads the server information and api key from these envs
+		jobTaskSpec.Properties.Envs = append(jobTaskSpec.Properties.Envs,
+			&commonmodels.KeyVal{Key: "CX_BASE_URI", Value: integration.ServerAddress},
+			&commonmodels.KeyVal{Key: "CX_BASE_AUTH_URI", Value: integration.AuthAddress},
+			&commonmodels.KeyVal{Key: "CX_TENANT", Value: integration.Tenant},
+			&commonmodels.KeyVal{Key: "CX_APIKEY", Value: integration.Token, IsCredential: true},
+		)
+		command = fmt.Sprintf("cx scan create --project-name \"%s-%s\" --branch \"%s\" -s . --report-format json --output-path . --output-name %s %s",
+			scanningInfo.ProjectName, scanningInfo.Name, branch, strings.TrimSuffix(setting.ScannerReportFileName, ".json"), scanningInfo.Parameter)
+	}
+	command = renderEnv(strings.TrimSpace(command), jobTaskSpec.Properties.Envs)
+
+	scriptStep := &commonmodels.StepTask{
+		JobName: jobTask.Name,
+	}
+	scannerScriptStep := &commonmodels.StepTask{
+		JobName: jobTask.Name,
+	}
+	if scanningInfo.ScriptType == types.ScriptTypeShell || scanningInfo.ScriptType == "" {
+		scriptStep.Name = name + "-shell"
+		scriptStep.StepType = config.StepShell
+		scriptStep.Spec = &step.StepShellSpec{
+			Scripts: append(strings.Split(replaceWrapLine(scanningInfo.Script), "\n"), outputScript(scanningInfo.Outputs, jobTask.Infrastructure)...),
+		}
+		scannerScriptStep.Name = fmt.Sprintf("%s-%s-shell", name, scanningInfo.ScannerType)
+		scannerScriptStep.StepType = config.StepShell
+		scannerScriptStep.Spec = &step.StepShellSpec{
+			Scripts:     []string{"set -e", fmt.Sprintf("cd %s", repoName), command},
+			SkipPrepare: true,
+		}
+	} else if scanningInfo.ScriptType == types.ScriptTypeBatchFile {
+		scriptStep.Name = name + "-batchfile"
+		scriptStep.StepType = config.StepBatchFile
+		scriptStep.Spec = &step.StepBatchFileSpec{
+			Scripts: append(strings.Split(replaceWrapLine(scanningInfo.Script), "\n"), outputScript(scanningInfo.Outputs, jobTask.Infrastructure)...),
+		}
+		scannerScriptStep.Name = fmt.Sprintf("%s-%s-batchfile", name, scanningInfo.ScannerType)
+		scannerScriptStep.StepType = config.StepBatchFile
+		scannerScriptStep.Spec = &step.StepBatchFileSpec{
+			Scripts:     []string{"@echo off", fmt.Sprintf("cd %s", repoName), command},
+			SkipPrepare: true,
+		}
+	} else if scanningInfo.ScriptType == types.ScriptTypePowerShell {
+		scriptStep.Name = name + "-powershell"
+		scriptStep.StepType = config.StepPowerShell
+		scriptStep.Spec = &step.StepPowerShellSpec{
+			Scripts: append(strings.Split(replaceWrapLine(scanningInfo.Script), "\n"), outputScript(scanningInfo.Outputs, jobTask.Infrastructure)...),
+		}
+		scannerScriptStep.Name = fmt.Sprintf("%s-%s-powershell", name, scanningInfo.ScannerType)
+		scannerScriptStep.StepType = config.StepPowerShell
+		scannerScriptStep.Spec = &step.StepPowerShellSpec{
+			Scripts:     []string{fmt.Sprintf("Set-Location -Path \"%s\"", repoName), command},
+			SkipPrepare: true,
+		}
+	}
+	resp = append(resp, scriptStep)
+	// when the scanner is not enabled, the user script is expected to generate the report
+	if scanningInfo.EnableScanner {
+		resp = append(resp, scannerScriptStep)
+	}
+
+	resp = append(resp, &commonmodels.StepTask{
+		Name:      fmt.Sprintf("%s-%s-report", name, scanningInfo.ScannerType),
+		JobName:   jobTask.Name,
+		JobKey:    jobTask.Key,
+		StepType:  config.StepScannerReport,
+		Onfailure: true,
+		Spec: &step.StepScannerReportSpec{
+			ScannerType: scanningInfo.ScannerType,
+			ReportFile:  path.Join(repoName, setting.ScannerReportFileName),
+			S3DestDir:   path.Join(j.workflow.Name, fmt.Sprint(taskID), jobTask.Name, "scanner"),
+			FileName:    setting.ScannerReportFileName,
+		},
+	})
+	return resp, nil
+}
+
 func getScanningJobCacheObjectPath(workflowName, scanningName string) string {
 	return fmt.Sprintf("%s/cache/%s", workflowName, scanningName)
 }
@@ -533,6 +533,9 @@ func (j *TestingJob) toJobtask(testing *commonmodels.TestModule, defaultS3 *comm
 		}
 		jobTaskSpec.Steps = append(jobTaskSpec.Steps, archiveStep)
 	}
+	if reportsStep := htmlReportsStep(testingInfo.HTMLReports, j.workflow.Name, taskID, jobTask.Name, defaultS3); reportsStep != nil {
+		jobTaskSpec.Steps = append(jobTaskSpec.Steps, reportsStep)
+	}
 
 	destDir := "/tmp"
 	if testingInfo.ScriptType == types.ScriptTypeBatchFile {
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+type VariableResolution string
+
+const (
+	// VariableResolvedAtCreation means the variable is rendered when the task is created
+	VariableResolvedAtCreation VariableResolution = "creation"
+	// VariableResolvedAtRuntime means the variable is an output of a previous job and rendered when the job starts
+	VariableResolvedAtRuntime VariableResolution = "runtime"
+	// VariableConverted means the variable is converted to the output of the referred job when the task is created
+	VariableConverted VariableResolution = "converted"
+	// VariableUnresolved means nothing provides the variable, it will be kept as it is at runtime
+	VariableUnresolved VariableResolution = "unresolved"
+)
+
+var variableExpressionRegex = regexp.MustCompile(`{{\.[^{}\s]+}}`)
+
+// jobImageOutputRegex matches the image output of a build like job, the IMAGES of the job is joined by them after the stage is done
+var jobImageOutputRegex = regexp.MustCompile(`^{{\.job\.([a-zA-Z0-9_-]+)\.[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]+\.output\.IMAGE}}$`)
+
+type VariableExplanation struct {
+	Expression string             `json:"expression"`
+	Resolution VariableResolution `json:"resolution"`
+	// Value is the simulated value of the variable resolved at creation
+	Value string `json:"value,omitempty"`
+	// Source is where the variable comes from, like workflow, workflow params or job name
+	Source string `json:"source,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type JobVariableExplanation struct {
+	JobName   string                 `json:"job_name"`
+	JobType   config.JobType         `json:"job_type"`
+	Variables []*VariableExplanation `json:"variables"`
+}
+
+// ExplainWorkflowVariables simulates the variable rendering of a task created by the workflow and explains how
+// every templated expression in the job specs will be resolved. The workflow should be instantiated already.
+func ExplainWorkflowVariables(workflow *commonmodels.WorkflowV4, creator, account string, log *zap.SugaredLogger) ([]*JobVariableExplanation, error) {
+	defaultParams, err := getWorkflowDefaultParams(workflow, 0, creator, account)
+	if err != nil {
+		return nil, fmt.Errorf("get workflow default params error: %v", err)
+	}
+	stageParams, err := getWorkflowStageParams(workflow, 0, creator)
+	if err != nil {
+		return nil, fmt.Errorf("get workflow stage params error: %v", err)
+	}
+
+	creationValues := make(map[string]*VariableExplanation)
+	for _, param := range defaultParams {
+		expression := fmt.Sprintf(setting.RenderValueTemplate, param.Name)
+		explanation := &VariableExplanation{Expression: expression, Resolution: VariableResolvedAtCreation, Value: param.Value, Source: "workflow"}
+		switch param.Name {
+		case "workflow.task.id", "workflow.task.timestamp":
+			explanation.Value = ""
+			explanation.Reason = "assigned when the task is created"
+		}
+		if strings.HasPrefix(param.Name, "workflow.params.") {
+			explanation.Source = "workflow params"
+		}
+		creationValues[expression] = explanation
+	}
+	for _, param := range stageParams {
+		expression := fmt.Sprintf(setting.RenderValueTemplate, param.Name)
+		explanation := &VariableExplanation{Expression: expression, Resolution: VariableResolvedAtCreation, Value: param.Value, Source: strings.Split(param.Name, ".")[1]}
+		if strings.HasSuffix(param.Name, ".COMMITID") && param.Value == "" {
+			explanation.Reason = "the latest commit of the branch is fetched when the task is created"
+		}
+		creationValues[expression] = explanation
+	}
+
+	jobRankMap := getJobRankMap(workflow.Stages)
+	resp := make([]*JobVariableExplanation, 0)
+	for _, stage := range workflow.Stages {
+		for _, j := range stage.Jobs {
+			if JobSkiped(j) {
+				continue
+			}
+			expressions, err := findVariableExpressions(j.Spec)
+			if err != nil {
+				return nil, fmt.Errorf("find variables of job %s error: %v", j.Name, err)
+			}
+
+			runtimeOutputs := make(map[string]string)
+			for _, output := range GetWorkflowOutputs(workflow, j.Name, log) {
+				sourceJobName := strings.Split(output, ".")[2]
+				if isJobSkipped(workflow, sourceJobName) {
+					continue
+				}
+				runtimeOutputs[output] = sourceJobName
+				if list := jobImageOutputRegex.FindStringSubmatch(output); len(list) > 0 {
+					runtimeOutputs[fmt.Sprintf("{{.job.%s.IMAGES}}", list[1])] = list[1]
+				}
+			}
+
+			explanation := &JobVariableExplanation{JobName: j.Name, JobType: j.JobType, Variables: make([]*VariableExplanation, 0)}
+			for _, expression := range expressions {
+				explanation.Variables = append(explanation.Variables, explainVariable(workflow, j, expression, creationValues, runtimeOutputs, jobRankMap))
+			}
+			resp = append(resp, explanation)
+		}
+	}
+	return resp, nil
+}
+
+func explainVariable(workflow *commonmodels.WorkflowV4, currentJob *commonmodels.Job, expression string, creationValues map[string]*VariableExplanation, runtimeOutputs map[string]string, jobRankMap map[string]int) *VariableExplanation {
+	if explanation, ok := creationValues[expression]; ok {
+		resp := *explanation
+		return &resp
+	}
+	if source, ok := runtimeOutputs[expression]; ok {
+		return &VariableExplanation{Expression: expression, Resolution: VariableResolvedAtRuntime, Source: source, Reason: fmt.Sprintf("output of job %s", source)}
+	}
+	switch expression {
+	case WorkflowInputImageTagVariable:
+		return &VariableExplanation{Expression: expression, Resolution: VariableConverted, Reason: "replaced by the image tag input of the task"}
+	case PreBuildImageTagVariable, PreJobImageTagVariable:
+		return &VariableExplanation{Expression: expression, Resolution: VariableConverted, Reason: "replaced by the image tag output of the referred job when the task is created"}
+	}
+
+	resp := &VariableExplanation{Expression: expression, Resolution: VariableUnresolved}
+	path := strings.Split(strings.TrimSuffix(strings.TrimPrefix(expression, "{{."), "}}"), ".")
+	switch {
+	case len(path) > 2 && path[0] == "workflow" && path[1] == "params":
+		resp.Reason = fmt.Sprintf("workflow parameter %s is not defined", strings.Join(path[2:], "."))
+	case len(path) > 1 && path[0] == "job":
+		rank, ok := jobRankMap[path[1]]
+		switch {
+		case !ok:
+			resp.Reason = fmt.Sprintf("job %s does not exist", path[1])
+		case rank >= jobRankMap[currentJob.Name]:
+			resp.Reason = fmt.Sprintf("job %s is not executed before the current job", path[1])
+		case isJobSkipped(workflow, path[1]):
+			resp.Reason = fmt.Sprintf("job %s is skipped", path[1])
+		default:
+			resp.Reason = fmt.Sprintf("job %s does not provide this variable", path[1])
+		}
+		resp.Source = path[1]
+	default:
+		resp.Reason = "unknown variable"
+	}
+	return resp
+}
+
+func isJobSkipped(workflow *commonmodels.WorkflowV4, jobName string) bool {
+	for _, stage := range workflow.Stages {
+		for _, j := range stage.Jobs {
+			if j.Name == jobName {
+				return JobSkiped(j)
+			}
+		}
+	}
+	return false
+}
+
+func findVariableExpressions(spec interface{}) ([]string, error) {
+	bf := bytes.NewBuffer([]byte{})
+	jsonEncoder := json.NewEncoder(bf)
+	jsonEncoder.SetEscapeHTML(false)
+	if err := jsonEncoder.Encode(spec); err != nil {
+		return nil, err
+	}
+
+	resp := make([]string, 0)
+	found := make(map[string]bool)
+	for _, expression := range variableExpressionRegex.FindAllString(bf.String(), -1) {
+		if found[expression] {
+			continue
+		}
+		found[expression] = true
+		resp = append(resp, expression)
+	}
+	sort.Strings(resp)
+	return resp, nil
+}
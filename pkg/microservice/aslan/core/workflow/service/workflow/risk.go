@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
+	jobctl "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow/job"
+)
+
+const (
+	defaultHighRiskScore    = 60
+	mediumRiskScore         = 30
+	defaultWorkStartHour    = 9
+	defaultWorkEndHour      = 19
+	riskHistoryTaskCount    = 20
+	highRiskApprovalJob     = "high-risk-approval"
+	highRiskApprovalStage   = "高风险审批"
+	riskFactorDiffSize      = "diff_size"
+	riskFactorServices      = "services"
+	riskFactorEnv           = "env"
+	riskFactorTimeOfDay     = "time_of_day"
+	riskFactorFailureRate   = "failure_rate"
+	maxDiffSizeRiskScore    = 25
+	maxServicesRiskScore    = 20
+	maxEnvRiskScore         = 30
+	maxTimeOfDayRiskScore   = 10
+	maxFailureRateRiskScore = 15
+)
+
+// computeTaskRisk scores the change risk of the task from the size of the diff, the services and envs it deploys to,
+// the time it's created and the recent failure rate of the workflow.
+func computeTaskRisk(workflow *commonmodels.WorkflowV4, task *commonmodels.WorkflowTask, now time.Time, log *zap.SugaredLogger) *commonmodels.TaskRisk {
+	risk := &commonmodels.TaskRisk{Factors: make([]*commonmodels.RiskFactor, 0)}
+
+	changedFiles := 0
+	if workflow.HookPayload != nil {
+		changedFiles = workflow.HookPayload.ChangedFiles
+	}
+	risk.Factors = append(risk.Factors, diffSizeRiskFactor(changedFiles))
+
+	services := sets.NewString()
+	production, deploy := false, false
+	for _, stage := range task.Stages {
+		for _, job := range stage.Jobs {
+			switch spec := job.Spec.(type) {
+			case *commonmodels.JobTaskDeploySpec:
+				deploy = true
+				production = production || spec.Production
+				services.Insert(spec.ServiceName)
+			case *commonmodels.JobTaskHelmDeploySpec:
+				deploy = true
+				production = production || spec.IsProduction
+				services.Insert(spec.ServiceName)
+			}
+		}
+	}
+	risk.Factors = append(risk.Factors, &commonmodels.RiskFactor{
+		Name:     riskFactorServices,
+		Score:    min(services.Len()*4, maxServicesRiskScore),
+		MaxScore: maxServicesRiskScore,
+		Detail:   fmt.Sprintf("%d services deployed", services.Len()),
+	})
+	risk.Factors = append(risk.Factors, envRiskFactor(deploy, production))
+	risk.Factors = append(risk.Factors, timeOfDayRiskFactor(workflow.RiskPolicy, now))
+
+	statuses, err := commonrepo.NewworkflowTaskv4Coll().ListRecentFinishedStatuses(workflow.Name, riskHistoryTaskCount)
+	if err != nil {
+		log.Warnf("failed to list recent tasks of workflow %s for risk scoring, error: %s", workflow.Name, err)
+	}
+	risk.Factors = append(risk.Factors, failureRateRiskFactor(statuses))
+
+	for _, factor := range risk.Factors {
+		risk.Score += factor.Score
+	}
+	risk.Level = taskRiskLevel(risk.Score, workflow.RiskPolicy)
+	return risk
+}
+
+func diffSizeRiskFactor(changedFiles int) *commonmodels.RiskFactor {
+	factor := &commonmodels.RiskFactor{
+		Name:     riskFactorDiffSize,
+		MaxScore: maxDiffSizeRiskScore,
+		Detail:   fmt.Sprintf("%d files changed", changedFiles),
+	}
+	switch {
+	case changedFiles == 0:
+		factor.Detail = "unknown, the task is not created by a code change"
+	case changedFiles <= 10:
+		factor.Score = 5
+	case changedFiles <= 50:
+		factor.Score = 15
+	default:
+		factor.Score = maxDiffSizeRiskScore
+	}
+	return factor
+}
+
+func envRiskFactor(deploy, production bool) *commonmodels.RiskFactor {
+	factor := &commonmodels.RiskFactor{
+		Name:     riskFactorEnv,
+		MaxScore: maxEnvRiskScore,
+		Detail:   "no env is deployed",
+	}
+	if production {
+		factor.Score = maxEnvRiskScore
+		factor.Detail = "production envs are deployed"
+	} else if deploy {
+		factor.Score = 10
+		factor.Detail = "test envs are deployed"
+	}
+	return factor
+}
+
+func timeOfDayRiskFactor(policy *commonmodels.WorkflowRiskPolicy, now time.Time) *commonmodels.RiskFactor {
+	start, end := defaultWorkStartHour, defaultWorkEndHour
+	if policy != nil && (policy.WorkStartHour != 0 || policy.WorkEndHour != 0) {
+		start, end = policy.WorkStartHour, policy.WorkEndHour
+	}
+
+	factor := &commonmodels.RiskFactor{
+		Name:     riskFactorTimeOfDay,
+		MaxScore: maxTimeOfDayRiskScore,
+		Detail:   "in working hours",
+	}
+	if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
+		factor.Score = maxTimeOfDayRiskScore
+		factor.Detail = "on weekends"
+	} else if now.Hour() < start || now.Hour() >= end {
+		factor.Score = maxTimeOfDayRiskScore
+		factor.Detail = fmt.Sprintf("out of working hours %d:00-%d:00", start, end)
+	}
+	return factor
+}
+
+func failureRateRiskFactor(statuses []config.Status) *commonmodels.RiskFactor {
+	factor := &commonmodels.RiskFactor{
+		Name:     riskFactorFailureRate,
+		MaxScore: maxFailureRateRiskScore,
+		Detail:   "no finished task yet",
+	}
+	if len(statuses) == 0 {
+		return factor
+	}
+
+	failed := 0
+	for _, status := range statuses {
+		if status == config.StatusFailed || status == config.StatusTimeout {
+			failed++
+		}
+	}
+	factor.Score = failed * maxFailureRateRiskScore / len(statuses)
+	factor.Detail = fmt.Sprintf("%d of the latest %d tasks failed", failed, len(statuses))
+	return factor
+}
+
+func taskRiskLevel(score int, policy *commonmodels.WorkflowRiskPolicy) commonmodels.TaskRiskLevel {
+	highRiskScore := defaultHighRiskScore
+	if policy != nil && policy.HighRiskScore > 0 {
+		highRiskScore = policy.HighRiskScore
+	}
+	switch {
+	case score >= highRiskScore:
+		return commonmodels.TaskRiskLevelHigh
+	case score >= mediumRiskScore:
+		return commonmodels.TaskRiskLevelMedium
+	default:
+		return commonmodels.TaskRiskLevelLow
+	}
+}
+
+// escalateHighRiskTask adds the high risk approval of the workflow as the first stage of the task if the task is high
+// risk, so that it can't be executed before it's approved.
+func escalateHighRiskTask(policy *commonmodels.WorkflowRiskPolicy, task *commonmodels.WorkflowTask) {
+	if policy == nil || !policy.Enabled || policy.HighRiskApproval == nil || task.Risk == nil || task.Risk.Level != commonmodels.TaskRiskLevelHigh {
+		return
+	}
+
+	approval := *policy.HighRiskApproval
+	approval.ApproveUsers, _ = util.GeneFlatUsers(approval.ApproveUsers)
+	approval.QuorumRules = util.GeneQuorumRules(approval.QuorumRules)
+	approval.Steps = util.GeneNativeApprovalSteps(approval.Steps)
+
+	task.Stages = append([]*commonmodels.StageTask{{
+		Name: highRiskApprovalStage,
+		Jobs: []*commonmodels.JobTask{{
+			Name: highRiskApprovalJob,
+			JobInfo: map[string]string{
+				jobctl.JobNameKey: highRiskApprovalJob,
+			},
+			Key:     highRiskApprovalJob,
+			JobType: string(config.JobApproval),
+			Spec: &commonmodels.JobTaskApprovalSpec{
+				Timeout:        int64(approval.Timeout),
+				Type:           config.NativeApproval,
+				Description:    fmt.Sprintf("high risk task, risk score %d", task.Risk.Score),
+				NativeApproval: &approval,
+			},
+			Timeout: int64(approval.Timeout),
+		}},
+	}}, task.Stages...)
+	task.Risk.Escalated = true
+}
+
+// lintWorkflowRiskPolicy checks the high risk approval of the workflow risk policy
+func lintWorkflowRiskPolicy(policy *commonmodels.WorkflowRiskPolicy) error {
+	if policy == nil || !policy.Enabled {
+		return nil
+	}
+	if policy.HighRiskScore < 0 || policy.HighRiskScore > 100 {
+		return fmt.Errorf("high risk score should be between 0 and 100")
+	}
+	if policy.WorkStartHour < 0 || policy.WorkEndHour > 24 || policy.WorkStartHour > policy.WorkEndHour {
+		return fmt.Errorf("invalid working hours %d-%d", policy.WorkStartHour, policy.WorkEndHour)
+	}
+	if policy.HighRiskApproval == nil {
+		return fmt.Errorf("high risk approval is required")
+	}
+	allApproveUsers, _ := util.GeneFlatUsers(policy.HighRiskApproval.ApproveUsers)
+	if len(allApproveUsers) < policy.HighRiskApproval.NeededApprovers {
+		return fmt.Errorf("all approve users of the high risk approval should not less than needed approvers")
+	}
+	return util.LintNativeApprovalQuorum(policy.HighRiskApproval)
+}
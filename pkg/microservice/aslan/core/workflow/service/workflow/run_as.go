@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	systemmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/repository/models"
+	systemmongodb "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	"github.com/koderover/zadig/v2/pkg/shared/client/user"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+// automatedTaskCreators are the trigger names used when a task is created without a human user
+var automatedTaskCreators = sets.NewString(
+	setting.CronTaskCreator,
+	setting.WebhookTaskCreator,
+	setting.JiraHookTaskCreator,
+	setting.MeegoHookTaskCreator,
+	setting.GeneralHookTaskCreator,
+	setting.RegistryHookTaskCreator,
+	setting.WorkflowTriggerTaskCreator,
+)
+
+// getRunAsServiceAccount returns the service account the task created by the trigger runs as,
+// nil is returned if the task is created by a user or the workflow has no run_as configured.
+func getRunAsServiceAccount(workflow *commonmodels.WorkflowV4, triggerName string) (*commonmodels.ProjectServiceAccount, error) {
+	if workflow.RunAs == "" || !automatedTaskCreators.Has(triggerName) {
+		return nil, nil
+	}
+
+	sa, err := commonrepo.NewProjectServiceAccountColl().Find(workflow.Project, workflow.RunAs)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, e.ErrCreateTask.AddDesc(fmt.Sprintf("service account %s of workflow %s not found", workflow.RunAs, workflow.Name))
+		}
+		return nil, e.ErrCreateTask.AddErr(fmt.Errorf("failed to find service account %s, error: %v", workflow.RunAs, err))
+	}
+	if sa.Disabled {
+		return nil, e.ErrCreateTask.AddDesc(fmt.Sprintf("service account %s is disabled", sa.Name))
+	}
+	if !sa.CanRunWorkflow(workflow.Name) {
+		return nil, e.ErrCreateTask.AddDesc(fmt.Sprintf("service account %s is not allowed to run workflow %s", sa.Name, workflow.Name))
+	}
+	return sa, nil
+}
+
+// validateWorkflowRunAs makes sure the run_as service account of the workflow exists in the project.
+func validateWorkflowRunAs(workflow *commonmodels.WorkflowV4) error {
+	if workflow.RunAs == "" {
+		return nil
+	}
+	if _, err := commonrepo.NewProjectServiceAccountColl().Find(workflow.Project, workflow.RunAs); err != nil {
+		return e.ErrUpsertWorkflow.AddDesc(fmt.Sprintf("service account %s not found in project %s", workflow.RunAs, workflow.Project))
+	}
+	return nil
+}
+
+// CheckWorkflowRunAsPermission allows only the project admins to set or change the run_as of the workflow, since the
+// tasks triggered automatically run with the permissions of the service account.
+func CheckWorkflowRunAsPermission(resources *user.AuthorizedResources, workflow *commonmodels.WorkflowV4) error {
+	if workflow.RunAs == "" || resources.IsSystemAdmin {
+		return nil
+	}
+	if authInfo, ok := resources.ProjectAuthInfo[workflow.Project]; ok && authInfo.IsProjectAdmin {
+		return nil
+	}
+	if existed, err := commonrepo.NewWorkflowV4Coll().Find(workflow.Name); err == nil && existed.Project == workflow.Project && existed.RunAs == workflow.RunAs {
+		return nil
+	}
+	return e.ErrForbidden.AddDesc(fmt.Sprintf("only the project admin is able to set the run_as of workflow %s", workflow.Name))
+}
+
+// checkServiceAccountPermission rejects the task if any of its deploy jobs targets an env the service account can not deploy to.
+func checkServiceAccountPermission(sa *commonmodels.ProjectServiceAccount, workflowTask *commonmodels.WorkflowTask) error {
+	for _, stage := range workflowTask.Stages {
+		for _, job := range stage.Jobs {
+			var envName string
+			var production bool
+			switch config.JobType(job.JobType) {
+			case config.JobZadigDeploy:
+				spec := &commonmodels.JobTaskDeploySpec{}
+				if err := commonmodels.IToi(job.Spec, spec); err != nil {
+					return e.ErrCreateTask.AddErr(err)
+				}
+				envName, production = spec.Env, spec.Production
+			case config.JobZadigHelmDeploy:
+				spec := &commonmodels.JobTaskHelmDeploySpec{}
+				if err := commonmodels.IToi(job.Spec, spec); err != nil {
+					return e.ErrCreateTask.AddErr(err)
+				}
+				envName, production = spec.Env, spec.IsProduction
+			default:
+				continue
+			}
+			if envName == "" || sa.CanDeployEnv(envName, production) {
+				continue
+			}
+			return e.ErrCreateTask.AddDesc(fmt.Sprintf("job %s can not be created: service account %s is not allowed to deploy to env %s", job.Name, sa.Name, envName))
+		}
+	}
+	return nil
+}
+
+// insertRunAsOperationLog records the task created as the service account so that it can be told apart from the tasks of users.
+func insertRunAsOperationLog(sa *commonmodels.ProjectServiceAccount, triggerName string, workflowTask *commonmodels.WorkflowTask, log *zap.SugaredLogger) {
+	err := systemmongodb.NewOperationLogColl().Insert(&systemmodels.OperationLog{
+		Username:    fmt.Sprintf("%s(%s)", sa.Name, triggerName),
+		ProductName: workflowTask.ProjectName,
+		Method:      "新建",
+		Function:    "工作流任务-服务账号",
+		Scene:       setting.OperationSceneWorkflow,
+		Targets:     []string{workflowTask.WorkflowName},
+		Name:        fmt.Sprintf("%s#%d", workflowTask.WorkflowName, workflowTask.TaskID),
+		Status:      http.StatusOK,
+		CreatedAt:   time.Now().Unix(),
+	})
+	if err != nil {
+		log.Errorf("failed to insert run as operation log of workflow %s, error: %s", workflowTask.WorkflowName, err)
+	}
+}
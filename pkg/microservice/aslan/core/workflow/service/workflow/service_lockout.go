@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/repository"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+type lockoutKey struct {
+	serviceName string
+	production  bool
+}
+
+// checkServiceLockouts rejects the task if any of its build or deploy jobs works on a locked out service.
+func checkServiceLockouts(workflowTask *commonmodels.WorkflowTask) error {
+	lockouts := make(map[lockoutKey]*commonmodels.ServiceLockout)
+	getLockout := func(serviceName string, production bool) *commonmodels.ServiceLockout {
+		key := lockoutKey{serviceName: serviceName, production: production}
+		if lockout, ok := lockouts[key]; ok {
+			return lockout
+		}
+		var lockout *commonmodels.ServiceLockout
+		svc, err := repository.QueryTemplateService(&commonrepo.ServiceFindOption{
+			ProductName: workflowTask.ProjectName,
+			ServiceName: serviceName,
+		}, production)
+		if err == nil {
+			lockout = svc.Lockout
+		}
+		lockouts[key] = lockout
+		return lockout
+	}
+
+	now := time.Now()
+	for _, stage := range workflowTask.Stages {
+		for _, job := range stage.Jobs {
+			var serviceName string
+			// builds are blocked by the lockouts of both the testing and the production service
+			productions := []bool{false}
+			switch config.JobType(job.JobType) {
+			case config.JobZadigBuild:
+				serviceName = jobInfoServiceName(job)
+				productions = []bool{false, true}
+			case config.JobZadigVMDeploy:
+				serviceName = jobInfoServiceName(job)
+			case config.JobZadigDeploy:
+				spec := &commonmodels.JobTaskDeploySpec{}
+				if err := commonmodels.IToi(job.Spec, spec); err != nil {
+					return e.ErrCreateTask.AddErr(err)
+				}
+				serviceName, productions = spec.ServiceName, []bool{spec.Production}
+			case config.JobZadigHelmDeploy:
+				spec := &commonmodels.JobTaskHelmDeploySpec{}
+				if err := commonmodels.IToi(job.Spec, spec); err != nil {
+					return e.ErrCreateTask.AddErr(err)
+				}
+				serviceName, productions = spec.ServiceName, []bool{spec.IsProduction}
+			default:
+				continue
+			}
+			if serviceName == "" {
+				continue
+			}
+
+			for _, production := range productions {
+				lockout := getLockout(serviceName, production)
+				if !lockout.Active(now) {
+					continue
+				}
+				until := "it is unlocked"
+				if lockout.ExpireTime > 0 {
+					until = time.Unix(lockout.ExpireTime, 0).Format("2006-01-02 15:04:05")
+				}
+				return e.ErrCreateTask.AddDesc(fmt.Sprintf("job %s can not be created: service %s is locked out by %s until %s, reason: %s",
+					job.Name, serviceName, lockout.LockedBy, until, lockout.Reason))
+			}
+		}
+	}
+	return nil
+}
+
+// jobInfoServiceName reads the service name from the job info of a job task that is just built.
+func jobInfoServiceName(job *commonmodels.JobTask) string {
+	jobInfo, ok := job.JobInfo.(map[string]string)
+	if !ok {
+		return ""
+	}
+	return jobInfo["service_name"]
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/tool/pdf"
+	"github.com/koderover/zadig/v2/pkg/tool/workwx"
+)
+
+type TaskReportFormat string
+
+const (
+	TaskReportFormatHTML TaskReportFormat = "html"
+	TaskReportFormatPDF  TaskReportFormat = "pdf"
+)
+
+type WorkflowTaskReport struct {
+	ProjectName         string                `json:"project_name"`
+	WorkflowName        string                `json:"workflow_name"`
+	WorkflowDisplayName string                `json:"workflow_display_name"`
+	TaskID              int64                 `json:"task_id"`
+	TaskName            string                `json:"task_name,omitempty"`
+	Status              config.Status         `json:"status"`
+	Creator             string                `json:"creator"`
+	Revoker             string                `json:"revoker,omitempty"`
+	Remark              string                `json:"remark,omitempty"`
+	Error               string                `json:"error,omitempty"`
+	CreateTime          int64                 `json:"create_time"`
+	StartTime           int64                 `json:"start_time"`
+	EndTime             int64                 `json:"end_time"`
+	Duration            int64                 `json:"duration"`
+	Params              []*commonmodels.Param `json:"params"`
+	Stages              []*TaskReportStage    `json:"stages"`
+	GenerateTime        int64                 `json:"generate_time"`
+}
+
+type TaskReportStage struct {
+	Name      string           `json:"name"`
+	Status    config.Status    `json:"status"`
+	StartTime int64            `json:"start_time"`
+	EndTime   int64            `json:"end_time"`
+	Duration  int64            `json:"duration"`
+	Executor  string           `json:"executor,omitempty"`
+	Jobs      []*TaskReportJob `json:"jobs"`
+}
+
+type TaskReportJob struct {
+	Name        string                  `json:"name"`
+	JobType     string                  `json:"job_type"`
+	Status      config.Status           `json:"status"`
+	StartTime   int64                   `json:"start_time"`
+	EndTime     int64                   `json:"end_time"`
+	Duration    int64                   `json:"duration"`
+	Error       string                  `json:"error,omitempty"`
+	Artifacts   []string                `json:"artifacts,omitempty"`
+	Approvals   []*TaskReportApproval   `json:"approvals,omitempty"`
+	TestResults []*TaskReportTestResult `json:"test_results,omitempty"`
+}
+
+type TaskReportApproval struct {
+	Approver string `json:"approver"`
+	Result   string `json:"result"`
+	Comment  string `json:"comment,omitempty"`
+	Time     int64  `json:"time,omitempty"`
+}
+
+type TaskReportTestResult struct {
+	TestName string  `json:"test_name"`
+	Total    int     `json:"total"`
+	Success  int     `json:"success"`
+	Failed   int     `json:"failed"`
+	Skipped  int     `json:"skipped"`
+	Error    int     `json:"error"`
+	Duration float64 `json:"duration"`
+}
+
+// GetWorkflowTaskReport collects the full execution record of a workflow task,
+// including the timeline of stages and jobs, the approvals, the artifacts and the test summaries.
+func GetWorkflowTaskReport(workflowName string, taskID int64, logger *zap.SugaredLogger) (*WorkflowTaskReport, error) {
+	task, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+	if err != nil {
+		return nil, e.ErrGetTaskReport.AddDesc(fmt.Sprintf("failed to find task %d of workflow %s: %s", taskID, workflowName, err))
+	}
+	preview, err := GetWorkflowTaskV4(workflowName, taskID, logger)
+	if err != nil {
+		return nil, e.ErrGetTaskReport.AddErr(err)
+	}
+
+	resp := &WorkflowTaskReport{
+		ProjectName:         task.ProjectName,
+		WorkflowName:        task.WorkflowName,
+		WorkflowDisplayName: task.WorkflowDisplayName,
+		TaskID:              task.TaskID,
+		TaskName:            task.TaskName,
+		Status:              task.Status,
+		Creator:             task.TaskCreator,
+		Revoker:             task.TaskRevoker,
+		Remark:              task.Remark,
+		Error:               task.Error,
+		CreateTime:          task.CreateTime,
+		StartTime:           task.StartTime,
+		EndTime:             task.EndTime,
+		Duration:            reportDuration(task.StartTime, task.EndTime),
+		Params:              task.Params,
+		Stages:              make([]*TaskReportStage, 0),
+		GenerateTime:        time.Now().Unix(),
+	}
+
+	for i, stage := range task.Stages {
+		reportStage := &TaskReportStage{
+			Name:      stage.Name,
+			Status:    stage.Status,
+			StartTime: stage.StartTime,
+			EndTime:   stage.EndTime,
+			Duration:  reportDuration(stage.StartTime, stage.EndTime),
+			Jobs:      make([]*TaskReportJob, 0),
+		}
+		if stage.ManualExec != nil && stage.ManualExec.Enabled {
+			reportStage.Executor = stage.ManualExec.ManualExectorName
+		}
+
+		var previewJobs []*JobTaskPreview
+		if i < len(preview.Stages) {
+			previewJobs = preview.Stages[i].Jobs
+		}
+		for _, job := range stage.Jobs {
+			reportJob := &TaskReportJob{
+				Name:      job.Name,
+				JobType:   job.JobType,
+				Status:    job.Status,
+				StartTime: job.StartTime,
+				EndTime:   job.EndTime,
+				Duration:  reportDuration(job.StartTime, job.EndTime),
+				Error:     job.Error,
+			}
+
+			switch job.JobType {
+			case string(config.JobApproval):
+				reportJob.Approvals = getTaskReportApprovals(job)
+			case string(config.JobZadigBuild), string(config.JobFreestyle):
+				for _, previewJob := range previewJobs {
+					if previewJob.Name != job.Name {
+						continue
+					}
+					spec := new(ZadigBuildJobSpec)
+					if err := commonmodels.IToi(previewJob.Spec, spec); err != nil {
+						logger.Warnf("failed to decode spec of job %s: %s", job.Name, err)
+						break
+					}
+					if spec.Image != "" {
+						reportJob.Artifacts = append(reportJob.Artifacts, spec.Image)
+					}
+					if spec.Package != "" {
+						reportJob.Artifacts = append(reportJob.Artifacts, spec.Package)
+					}
+				}
+			case string(config.JobZadigTesting):
+				reportJob.TestResults = getTaskReportTestResults(workflowName, job.Name, taskID, logger)
+			}
+			reportStage.Jobs = append(reportStage.Jobs, reportJob)
+		}
+		resp.Stages = append(resp.Stages, reportStage)
+	}
+	return resp, nil
+}
+
+// RenderWorkflowTaskReport renders the task report in the given format, the content type of the result is also returned
+func RenderWorkflowTaskReport(workflowName string, taskID int64, format TaskReportFormat, logger *zap.SugaredLogger) ([]byte, string, error) {
+	report, err := GetWorkflowTaskReport(workflowName, taskID, logger)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case TaskReportFormatPDF:
+		return renderTaskReportPDF(report), "application/pdf", nil
+	case TaskReportFormatHTML, "":
+		buf := new(bytes.Buffer)
+		if err := taskReportHTMLTemplate.Execute(buf, report); err != nil {
+			return nil, "", e.ErrGetTaskReport.AddErr(err)
+		}
+		return buf.Bytes(), "text/html; charset=utf-8", nil
+	default:
+		return nil, "", e.ErrInvalidParam.AddDesc(fmt.Sprintf("unsupported report format: %s", format))
+	}
+}
+
+func getTaskReportApprovals(job *commonmodels.JobTask) []*TaskReportApproval {
+	resp := make([]*TaskReportApproval, 0)
+	spec := new(commonmodels.JobTaskApprovalSpec)
+	if err := commonmodels.IToi(job.Spec, spec); err != nil {
+		return resp
+	}
+
+	switch spec.Type {
+	case config.NativeApproval:
+		if spec.NativeApproval == nil {
+			break
+		}
+		for _, user := range spec.NativeApproval.ApproveUsers {
+			if user.RejectOrApprove == "" {
+				continue
+			}
+			resp = append(resp, &TaskReportApproval{Approver: user.UserName, Result: string(user.RejectOrApprove), Comment: user.Comment, Time: user.OperationTime})
+		}
+	case config.LarkApproval:
+		if spec.LarkApproval == nil {
+			break
+		}
+		for _, node := range spec.LarkApproval.ApprovalNodes {
+			for _, user := range node.ApproveUsers {
+				if user.RejectOrApprove == "" {
+					continue
+				}
+				resp = append(resp, &TaskReportApproval{Approver: user.Name, Result: string(user.RejectOrApprove), Comment: user.Comment, Time: user.OperationTime})
+			}
+		}
+	case config.DingTalkApproval:
+		if spec.DingTalkApproval == nil {
+			break
+		}
+		for _, node := range spec.DingTalkApproval.ApprovalNodes {
+			for _, user := range node.ApproveUsers {
+				if user.RejectOrApprove == "" {
+					continue
+				}
+				resp = append(resp, &TaskReportApproval{Approver: user.Name, Result: string(user.RejectOrApprove), Comment: user.Comment, Time: user.OperationTime})
+			}
+		}
+	case config.WorkWXApproval:
+		if spec.WorkWXApproval == nil {
+			break
+		}
+		for _, node := range spec.WorkWXApproval.ApprovalNodeDetails {
+			for _, subNode := range node.SubNodes {
+				var result config.ApproveOrReject
+				switch subNode.Status {
+				case workwx.ApprovalSubNodeStatusApproved, workwx.ApprovalSubNodeStatusApprovedAndAddApprover:
+					result = config.Approve
+				case workwx.ApprovalSubNodeStatusRejected:
+					result = config.Reject
+				default:
+					continue
+				}
+				resp = append(resp, &TaskReportApproval{Approver: subNode.UserInfo.UserID, Result: string(result), Comment: subNode.Speech, Time: subNode.Timestamp})
+			}
+		}
+	}
+	return resp
+}
+
+func getTaskReportTestResults(workflowName, jobName string, taskID int64, logger *zap.SugaredLogger) []*TaskReportTestResult {
+	resp := make([]*TaskReportTestResult, 0)
+	reports, err := commonrepo.NewCustomWorkflowTestReportColl().ListByWorkflow(workflowName, jobName, taskID)
+	if err != nil {
+		logger.Warnf("failed to list test reports of job %s: %s", jobName, err)
+		return resp
+	}
+	for _, report := range reports {
+		resp = append(resp, &TaskReportTestResult{
+			TestName: report.ZadigTestName,
+			Total:    report.TestCaseNum,
+			Success:  report.SuccessCaseNum,
+			Failed:   report.FailedCaseNum,
+			Skipped:  report.SkipCaseNum,
+			Error:    report.ErrorCaseNum,
+			Duration: report.TestTime,
+		})
+	}
+	return resp
+}
+
+func reportDuration(startTime, endTime int64) int64 {
+	if startTime == 0 || endTime == 0 || endTime < startTime {
+		return 0
+	}
+	return endTime - startTime
+}
+
+func formatReportTime(timestamp int64) string {
+	if timestamp == 0 {
+		return "-"
+	}
+	return time.Unix(timestamp, 0).Format("2006-01-02 15:04:05")
+}
+
+func formatReportDuration(seconds int64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func renderTaskReportPDF(report *WorkflowTaskReport) []byte {
+	doc := pdf.NewTextDocument()
+	title := fmt.Sprintf("%s #%d", report.WorkflowDisplayName, report.TaskID)
+	if report.TaskName != "" {
+		title += " " + report.TaskName
+	}
+	doc.AddTitle(title)
+	doc.AddText(fmt.Sprintf("项目: %s    工作流: %s    状态: %s", report.ProjectName, report.WorkflowName, report.Status))
+	doc.AddText(fmt.Sprintf("执行人: %s    开始时间: %s    结束时间: %s    持续时间: %s", report.Creator, formatReportTime(report.StartTime), formatReportTime(report.EndTime), formatReportDuration(report.Duration)))
+	if report.Remark != "" {
+		doc.AddText(fmt.Sprintf("备注: %s", report.Remark))
+	}
+	if report.Error != "" {
+		doc.AddText(fmt.Sprintf("错误: %s", report.Error))
+	}
+	if len(report.Params) > 0 {
+		doc.AddHeading("参数")
+		for _, param := range report.Params {
+			value := param.Value
+			if param.IsCredential {
+				value = "******"
+			}
+			doc.AddItem(fmt.Sprintf("%s = %s", param.Name, value))
+		}
+	}
+
+	for _, stage := range report.Stages {
+		doc.AddHeading(fmt.Sprintf("阶段 %s  [%s]  %s ~ %s  (%s)", stage.Name, stage.Status, formatReportTime(stage.StartTime), formatReportTime(stage.EndTime), formatReportDuration(stage.Duration)))
+		if stage.Executor != "" {
+			doc.AddText(fmt.Sprintf("手动执行人: %s", stage.Executor))
+		}
+		for _, job := range stage.Jobs {
+			doc.AddText(fmt.Sprintf("任务 %s (%s)  [%s]  %s ~ %s  (%s)", job.Name, job.JobType, job.Status, formatReportTime(job.StartTime), formatReportTime(job.EndTime), formatReportDuration(job.Duration)))
+			if job.Error != "" {
+				doc.AddItem(fmt.Sprintf("错误: %s", job.Error))
+			}
+			for _, artifact := range job.Artifacts {
+				doc.AddItem(fmt.Sprintf("制品: %s", artifact))
+			}
+			for _, approval := range job.Approvals {
+				doc.AddItem(fmt.Sprintf("审批: %s %s %s %s", approval.Approver, approval.Result, formatReportTime(approval.Time), approval.Comment))
+			}
+			for _, result := range job.TestResults {
+				doc.AddItem(fmt.Sprintf("测试 %s: 总数 %d, 成功 %d, 失败 %d, 跳过 %d, 错误 %d, 耗时 %.2fs", result.TestName, result.Total, result.Success, result.Failed, result.Skipped, result.Error, result.Duration))
+			}
+		}
+	}
+
+	doc.AddText("")
+	doc.AddText(fmt.Sprintf("报告生成时间: %s", formatReportTime(report.GenerateTime)))
+	return doc.Bytes()
+}
+
+var taskReportHTMLTemplate = template.Must(template.New("task_report").Funcs(template.FuncMap{
+	"formatTime":     formatReportTime,
+	"formatDuration": formatReportDuration,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.WorkflowDisplayName}} #{{.TaskID}}</title>
+<style>
+body { font-family: -apple-system, "PingFang SC", "Microsoft YaHei", sans-serif; font-size: 13px; color: #121212; margin: 32px; }
+h1 { font-size: 22px; }
+h2 { font-size: 16px; margin-top: 24px; border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { border: 1px solid #ddd; padding: 6px 8px; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+.sub { color: #666; font-size: 12px; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.WorkflowDisplayName}} #{{.TaskID}}{{if .TaskName}} {{.TaskName}}{{end}}</h1>
+<table>
+<tr><th>项目</th><td>{{.ProjectName}}</td><th>工作流</th><td>{{.WorkflowName}}</td></tr>
+<tr><th>状态</th><td>{{.Status}}</td><th>执行人</th><td>{{.Creator}}</td></tr>
+<tr><th>开始时间</th><td>{{formatTime .StartTime}}</td><th>结束时间</th><td>{{formatTime .EndTime}}</td></tr>
+<tr><th>持续时间</th><td>{{formatDuration .Duration}}</td><th>取消人</th><td>{{.Revoker}}</td></tr>
+{{- if .Remark}}<tr><th>备注</th><td colspan="3">{{.Remark}}</td></tr>{{end}}
+{{- if .Error}}<tr><th>错误</th><td colspan="3">{{.Error}}</td></tr>{{end}}
+</table>
+{{- if .Params}}
+<h2>参数</h2>
+<table>
+<tr><th>名称</th><th>值</th></tr>
+{{- range .Params}}
+<tr><td>{{.Name}}</td><td>{{if .IsCredential}}******{{else}}{{.Value}}{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range .Stages}}
+<h2>阶段 {{.Name}} <span class="sub">[{{.Status}}] {{formatTime .StartTime}} ~ {{formatTime .EndTime}} ({{formatDuration .Duration}}){{if .Executor}} 手动执行人: {{.Executor}}{{end}}</span></h2>
+<table>
+<tr><th>任务</th><th>类型</th><th>状态</th><th>开始时间</th><th>结束时间</th><th>持续时间</th><th>详情</th></tr>
+{{- range .Jobs}}
+<tr>
+<td>{{.Name}}</td><td>{{.JobType}}</td><td>{{.Status}}</td><td>{{formatTime .StartTime}}</td><td>{{formatTime .EndTime}}</td><td>{{formatDuration .Duration}}</td>
+<td>
+{{- if .Error}}<div>错误: {{.Error}}</div>{{end}}
+{{- range .Artifacts}}<div>制品: {{.}}</div>{{end}}
+{{- range .Approvals}}<div>审批: {{.Approver}} {{.Result}} {{formatTime .Time}} {{.Comment}}</div>{{end}}
+{{- range .TestResults}}<div>测试 {{.TestName}}: 总数 {{.Total}}, 成功 {{.Success}}, 失败 {{.Failed}}, 跳过 {{.Skipped}}, 错误 {{.Error}}, 耗时 {{printf "%.2f" .Duration}}s</div>{{end}}
+</td>
+</tr>
+{{- end}}
+</table>
+{{- end}}
+<p class="sub">报告生成时间: {{formatTime .GenerateTime}}</p>
+</body>
+</html>
+`))
@@ -2282,8 +2282,8 @@ func BuildModuleToSubTasks(args *commonmodels.BuildModuleArgs, log *zap.SugaredL
 	if module.PostBuild != nil && module.PostBuild.DockerBuild != nil {
 		dockerTemplateContent := ""
 		if module.PostBuild.DockerBuild.TemplateID != "" {
-			if dockerfileDetail, err := templ.GetDockerfileTemplateDetail(module.PostBuild.DockerBuild.TemplateID, log); err == nil {
-				dockerTemplateContent = dockerfileDetail.Content
+			if content, err := templ.GetDockerfileTemplateContent(module.PostBuild.DockerBuild.TemplateID, module.PostBuild.DockerBuild.TemplateVersion, log); err == nil {
+				dockerTemplateContent = content
 			}
 		}
 		build.JobCtx.DockerBuildCtx = &taskmodels.DockerBuildCtx{
@@ -17,6 +17,7 @@ limitations under the License.
 package workflow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -26,6 +27,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	config2 "github.com/koderover/zadig/v2/pkg/config"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
@@ -33,6 +35,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	approvalservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/approval"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/dingtalk"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/instantmessage"
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/lark"
@@ -68,6 +71,9 @@ type CreateTaskV4Resp struct {
 	TaskID       int64  `json:"task_id"`
 }
 
+// maxTaskNameLength is the max length of the display name of a workflow task
+const maxTaskNameLength = 64
+
 type WorkflowTaskPreview struct {
 	TaskID              int64                 `bson:"task_id"                   json:"task_id"`
 	WorkflowName        string                `bson:"workflow_name"             json:"workflow_key"`
@@ -75,6 +81,7 @@ type WorkflowTaskPreview struct {
 	Params              []*commonmodels.Param `bson:"params"                    json:"params"`
 	Status              config.Status         `bson:"status"                    json:"status,omitempty"`
 	Remark              string                `bson:"remark"                    json:"remark"`
+	TaskName            string                `bson:"task_name"                 json:"task_name,omitempty"`
 	TaskCreator         string                `bson:"task_creator"              json:"task_creator,omitempty"`
 	TaskRevoker         string                `bson:"task_revoker,omitempty"    json:"task_revoker,omitempty"`
 	CreateTime          int64                 `bson:"create_time"               json:"create_time,omitempty"`
@@ -85,6 +92,8 @@ type WorkflowTaskPreview struct {
 	Error               string                `bson:"error,omitempty"           json:"error,omitempty"`
 	IsRestart           bool                  `bson:"is_restart"                json:"is_restart"`
 	Debug               bool                  `bson:"debug"                     json:"debug"`
+	// Risk is the change risk of the task, it's empty for tasks created before risk scoring is supported
+	Risk *commonmodels.TaskRisk `bson:"risk,omitempty" json:"risk,omitempty"`
 }
 
 type StageTaskPreview struct {
@@ -96,6 +105,15 @@ type StageTaskPreview struct {
 	ManualExec *commonmodels.ManualExec `bson:"manual_exec"      json:"manual_exec"`
 	Jobs       []*JobTaskPreview        `bson:"jobs"          json:"jobs"`
 	Error      string                   `bson:"error" json:"error""`
+	PauseAfter *commonmodels.StagePause `bson:"pause_after"   json:"pause_after,omitempty"`
+	// DeployEnvs is the aggregated status of each env of the deploy jobs fanned out to multiple envs
+	DeployEnvs []*DeployEnvPreview `bson:"deploy_envs"   json:"deploy_envs,omitempty"`
+}
+
+type DeployEnvPreview struct {
+	JobName string        `bson:"job_name" json:"job_name"`
+	EnvName string        `bson:"env_name" json:"env_name"`
+	Status  config.Status `bson:"status"   json:"status"`
 }
 
 type JobTaskPreview struct {
@@ -147,6 +165,8 @@ type ZadigScanningJobSpec struct {
 	SonarMetrics  *step.SonarMetrics  `bson:"sonar_metrics"   json:"sonar_metrics"`
 	LinkURL       string              `bson:"link_url"        json:"link_url"`
 	ScanningName  string              `bson:"scanning_name"   json:"scanning_name"`
+	// ScannerResult is the parsed report of semgrep and checkmarx scanning
+	ScannerResult *step.ScannerResult `bson:"scanner_result,omitempty" json:"scanner_result,omitempty"`
 }
 
 type ZadigDeployJobPreviewSpec struct {
@@ -391,10 +411,18 @@ func CreateWorkflowTaskV4(args *CreateWorkflowTaskV4Args, workflow *commonmodels
 		ProjectName:  workflow.Project,
 		WorkflowName: workflow.Name,
 	}
+	if err := checkJobReferences(workflow); err != nil {
+		log.Errorf("check job references of workflow %s failed: %v", workflow.Name, err)
+		return resp, e.ErrCreateTask.AddErr(err)
+	}
 	if err := LintWorkflowV4(workflow, log); err != nil {
 		return resp, err
 	}
+	if err := service.CheckSandboxTaskQuota(workflow.Project); err != nil {
+		return resp, err
+	}
 
+	var runAs *commonmodels.ProjectServiceAccount
 	if args.Type == config.WorkflowTaskTypeWorkflow || args.Type == "" {
 		orignalWorkflow, err := commonrepo.NewWorkflowV4Coll().Find(workflow.Name)
 		if err != nil {
@@ -403,6 +431,12 @@ func CreateWorkflowTaskV4(args *CreateWorkflowTaskV4Args, workflow *commonmodels
 		if orignalWorkflow.Disabled {
 			return resp, e.ErrCreateTask.AddDesc("workflow is disabled")
 		}
+		// the run as setting is read from the saved workflow, the args of crons and webhooks may be stale
+		runAs, err = getRunAsServiceAccount(orignalWorkflow, args.Name)
+		if err != nil {
+			log.Errorf("cannot create workflow %s, error: %v", workflow.Name, err)
+			return resp, err
+		}
 	} else {
 		if workflow.Disabled {
 			return resp, e.ErrCreateTask.AddDesc("workflow is disabled")
@@ -441,6 +475,7 @@ func CreateWorkflowTaskV4(args *CreateWorkflowTaskV4Args, workflow *commonmodels
 	originTaskArgs.MeegoHookCtls = nil
 	originTaskArgs.JiraHookCtls = nil
 	originTaskArgs.GeneralHookCtls = nil
+	originTaskArgs.RegistryHookCtls = nil
 	workflowTask.OriginWorkflowArgs = originTaskArgs
 	nextTaskID, err := commonrepo.NewCounterColl().GetNextSeq(fmt.Sprintf(setting.WorkflowTaskV4Fmt, workflow.Name))
 	if err != nil {
@@ -473,6 +508,10 @@ func CreateWorkflowTaskV4(args *CreateWorkflowTaskV4Args, workflow *commonmodels
 	workflowTask.ShareStorages = workflow.ShareStorages
 	workflowTask.IsDebug = workflow.Debug
 	workflowTask.Remark = workflow.Remark
+	workflowTask.TaskName = strings.TrimSpace(workflow.TaskName)
+	if utf8.RuneCountInString(workflowTask.TaskName) > maxTaskNameLength {
+		return resp, e.ErrCreateTask.AddDesc(fmt.Sprintf("task name should be no longer than %d characters", maxTaskNameLength))
+	}
 	// set workflow params repo info, like commitid, branch etc.
 	setZadigParamRepos(workflow, log)
 	for _, stage := range workflow.Stages {
@@ -481,6 +520,9 @@ func CreateWorkflowTaskV4(args *CreateWorkflowTaskV4Args, workflow *commonmodels
 			Parallel:   stage.Parallel,
 			ManualExec: stage.ManualExec,
 		}
+		if stage.PauseAfter != nil && stage.PauseAfter.Enabled {
+			stageTask.PauseAfter = &commonmodels.StagePause{Enabled: true, Timeout: stage.PauseAfter.Timeout}
+		}
 		for _, job := range stage.Jobs {
 			if jobctl.JobSkiped(job) {
 				continue
@@ -548,10 +590,27 @@ func CreateWorkflowTaskV4(args *CreateWorkflowTaskV4Args, workflow *commonmodels
 		return resp, err
 	}
 
+	if err := checkServiceLockouts(workflowTask); err != nil {
+		log.Errorf("cannot create workflow %s, error: %v", workflow.Name, err)
+		return resp, err
+	}
+
+	if runAs != nil {
+		if err := checkServiceAccountPermission(runAs, workflowTask); err != nil {
+			log.Errorf("cannot create workflow %s, error: %v", workflow.Name, err)
+			return resp, err
+		}
+		workflowTask.RunAs = runAs.Name
+	}
+
+	workflowTask.Risk = computeTaskRisk(workflow, workflowTask, time.Now(), log)
+	escalateHighRiskTask(workflow.RiskPolicy, workflowTask)
+
 	workflow.HookCtls = nil
 	workflow.JiraHookCtls = nil
 	workflow.MeegoHookCtls = nil
 	workflow.GeneralHookCtls = nil
+	workflow.RegistryHookCtls = nil
 	workflowTask.WorkflowArgs = workflow
 	workflowTask.Status = config.StatusCreated
 	workflowTask.StartTime = time.Now().Unix()
@@ -574,6 +633,9 @@ func CreateWorkflowTaskV4(args *CreateWorkflowTaskV4Args, workflow *commonmodels
 		log.Errorf("create workflow task error: %v", err)
 		return resp, e.ErrCreateTask.AddDesc(err.Error())
 	}
+	if runAs != nil {
+		insertRunAsOperationLog(runAs, args.Name, workflowTask, log)
+	}
 	// Updating the comment in the git repository, this will not cause the function to return error if this function call fails
 	if err := scmnotify.NewService().UpdateWebhookCommentForWorkflowV4(workflowTask, log); err != nil {
 		log.Warnf("Failed to update comment for custom workflow %s, taskID: %d the error is: %s", workflowTask.WorkflowName, workflowTask.TaskID, err)
@@ -637,24 +699,9 @@ func RetryWorkflowTaskV4(workflowName string, taskID int64, logger *zap.SugaredL
 		return errors.New("工作流任务数据异常, 无法重试")
 	}
 
-	jobTaskMap := make(map[string]*commonmodels.JobTask)
-	for _, stage := range task.WorkflowArgs.Stages {
-		for _, job := range stage.Jobs {
-			if job.Skipped {
-				continue
-			}
-			jobCtl, err := jobctl.InitJobCtl(job, task.WorkflowArgs)
-			if err != nil {
-				return errors.Errorf("init jobCtl %s error: %s", job.Name, err)
-			}
-			jobTasks, err := jobCtl.ToJobs(taskID)
-			if err != nil {
-				return errors.Errorf("job %s toJobs error: %s", job.Name, err)
-			}
-			for _, jobTask := range jobTasks {
-				jobTaskMap[jobTask.Key] = jobTask
-			}
-		}
+	jobTaskMap, err := genOriginJobTaskMap(task)
+	if err != nil {
+		return err
 	}
 
 	for _, stage := range task.Stages {
@@ -684,6 +731,7 @@ func RetryWorkflowTaskV4(workflowName string, taskID int64, logger *zap.SugaredL
 
 	task.Status = config.StatusCreated
 	task.StartTime = time.Now().Unix()
+	task.RetryJobKeys = nil
 	if err := instantmessage.NewWeChatClient().SendWorkflowTaskNotifications(task); err != nil {
 		log.Errorf("send workflow task notification failed, error: %v", err)
 	}
@@ -696,6 +744,152 @@ func RetryWorkflowTaskV4(workflowName string, taskID int64, logger *zap.SugaredL
 	return nil
 }
 
+// RetryWorkflowTaskJobV4 retries a failed job of the task identified by its name, all the failed job tasks of the job are
+// retried, or by the key of a single job task. The passed jobs are kept with their outputs. The other failed jobs in the
+// same stage are retried as well since the stage can't pass otherwise. If downstream is set, the jobs in the following
+// stages are run after the stage too, otherwise the task ends after the stage and the following stages keep their
+// previous status.
+func RetryWorkflowTaskJobV4(workflowName string, taskID int64, jobName string, downstream bool, logger *zap.SugaredLogger) error {
+	task, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+	if err != nil {
+		logger.Errorf("find workflowTaskV4 error: %s", err)
+		return e.ErrGetTask.AddErr(err)
+	}
+	switch task.Status {
+	case config.StatusFailed, config.StatusTimeout, config.StatusCancelled, config.StatusReject:
+	default:
+		return errors.New("工作流任务状态无法重试")
+	}
+
+	if task.OriginWorkflowArgs == nil || task.OriginWorkflowArgs.Stages == nil {
+		return errors.New("工作流任务数据异常, 无法重试")
+	}
+
+	isFailed := func(jobTask *commonmodels.JobTask) bool {
+		switch jobTask.Status {
+		case config.StatusFailed, config.StatusTimeout, config.StatusCancelled, config.StatusReject:
+			return true
+		}
+		return false
+	}
+
+	targetStage := -1
+	found := false
+	for i, stage := range task.Stages {
+		for _, jobTask := range stage.Jobs {
+			if jobTask.Name != jobName && jobTask.Key != jobName {
+				continue
+			}
+			found = true
+			if isFailed(jobTask) {
+				targetStage = i
+			}
+		}
+		if found {
+			break
+		}
+		switch stage.Status {
+		case config.StatusPassed, config.StatusSkipped, config.StatusUnstable:
+		default:
+			return errors.Errorf("previous stage %s status is not passed", stage.Name)
+		}
+	}
+	if !found {
+		return errors.Errorf("job %s not found in workflow task %s/%d", jobName, workflowName, taskID)
+	}
+	if targetStage < 0 {
+		return errors.Errorf("job %s is not failed", jobName)
+	}
+
+	jobTaskMap, err := genOriginJobTaskMap(task)
+	if err != nil {
+		return err
+	}
+	resetJob := func(jobTask *commonmodels.JobTask) error {
+		t, ok := jobTaskMap[jobTask.Key]
+		if !ok {
+			return errors.Errorf("failed to get jobTask %s origin spec", jobTask.Name)
+		}
+		jobTask.Status = ""
+		jobTask.StartTime = 0
+		jobTask.EndTime = 0
+		jobTask.Error = ""
+		jobTask.Spec = t.Spec
+		return nil
+	}
+
+	retryJobKeys := make([]string, 0)
+	for _, jobTask := range task.Stages[targetStage].Jobs {
+		if !isFailed(jobTask) {
+			continue
+		}
+		if err := resetJob(jobTask); err != nil {
+			return err
+		}
+		retryJobKeys = append(retryJobKeys, jobTask.Key)
+	}
+	for i := targetStage; i < len(task.Stages); i++ {
+		stage := task.Stages[i]
+		if i > targetStage {
+			if !downstream {
+				break
+			}
+			for _, jobTask := range stage.Jobs {
+				if jobTask.Status == config.StatusPassed {
+					continue
+				}
+				if err := resetJob(jobTask); err != nil {
+					return err
+				}
+				retryJobKeys = append(retryJobKeys, jobTask.Key)
+			}
+		}
+		stage.Status = ""
+		stage.StartTime = 0
+		stage.EndTime = 0
+		stage.Error = ""
+	}
+
+	task.Status = config.StatusCreated
+	task.StartTime = time.Now().Unix()
+	task.RetryJobKeys = retryJobKeys
+	if err := instantmessage.NewWeChatClient().SendWorkflowTaskNotifications(task); err != nil {
+		log.Errorf("send workflow task notification failed, error: %v", err)
+	}
+
+	if err := workflowcontroller.UpdateTask(task); err != nil {
+		log.Errorf("retry workflow task job error: %v", err)
+		return e.ErrCreateTask.AddDesc(fmt.Sprintf("重试工作流任务失败: %s", err.Error()))
+	}
+
+	return nil
+}
+
+// genOriginJobTaskMap generates the job tasks from the workflow args of the task, the specs of the retried jobs are
+// reset with them.
+func genOriginJobTaskMap(task *commonmodels.WorkflowTask) (map[string]*commonmodels.JobTask, error) {
+	jobTaskMap := make(map[string]*commonmodels.JobTask)
+	for _, stage := range task.WorkflowArgs.Stages {
+		for _, job := range stage.Jobs {
+			if job.Skipped {
+				continue
+			}
+			jobCtl, err := jobctl.InitJobCtl(job, task.WorkflowArgs)
+			if err != nil {
+				return nil, errors.Errorf("init jobCtl %s error: %s", job.Name, err)
+			}
+			jobTasks, err := jobCtl.ToJobs(task.TaskID)
+			if err != nil {
+				return nil, errors.Errorf("job %s toJobs error: %s", job.Name, err)
+			}
+			for _, jobTask := range jobTasks {
+				jobTaskMap[jobTask.Key] = jobTask
+			}
+		}
+	}
+	return jobTaskMap, nil
+}
+
 type ManualExecWorkflowTaskV4Request struct {
 	Jobs []*commonmodels.Job `json:"jobs"`
 }
@@ -842,6 +1036,30 @@ func ManualExecWorkflowTaskV4(workflowName string, taskID int64, stageName strin
 	return nil
 }
 
+// ResumeWorkflowTaskV4Stage resumes the task which is paused after the given stage.
+func ResumeWorkflowTaskV4Stage(workflowName string, taskID int64, stageName, userID, userName string, logger *zap.SugaredLogger) error {
+	task, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+	if err != nil {
+		logger.Errorf("find workflowTaskV4 error: %s", err)
+		return e.ErrGetTask.AddErr(err)
+	}
+
+	for _, stage := range task.Stages {
+		if stage.Name != stageName {
+			continue
+		}
+		if stage.PauseAfter == nil || !stage.PauseAfter.Waiting {
+			return e.ErrInvalidParam.AddDesc(fmt.Sprintf("stage %s is not paused", stageName))
+		}
+		if err := workflowcontroller.ResumeStage(workflowName, stageName, taskID, userID, userName); err != nil {
+			logger.Errorf("resume stage %s of workflow task %s/%d error: %s", stageName, workflowName, taskID, err)
+			return e.ErrResumeWorkflowStage.AddErr(err)
+		}
+		return nil
+	}
+	return e.ErrInvalidParam.AddDesc(fmt.Sprintf("stage %s not found in workflow task %s/%d", stageName, workflowName, taskID))
+}
+
 func SetWorkflowTaskV4Breakpoint(workflowName, jobName string, taskID int64, set bool, position string, logger *zap.SugaredLogger) error {
 	event := &workflowcontroller.WorkflowDebugEvent{
 		EventType: workflowcontroller.WorkflowDebugEventSetBreakPoint,
@@ -894,6 +1112,10 @@ type TaskHistoryFilter struct {
 	QueryType    string `json:"queryType"    form:"queryType"`
 	Filters      string `json:"filters" form:"filters"`
 	JobName      string `json:"jobName" form:"jobName"`
+	// LabelSelector filters the tasks by labels, e.g. "release=true"
+	LabelSelector string `json:"labelSelector" form:"labelSelector"`
+	// Keyword searches the tasks by task id, task name or remark
+	Keyword string `json:"keyword" form:"keyword"`
 }
 
 func ListWorkflowTaskV4ByFilter(filter *TaskHistoryFilter, filterList []string, logger *zap.SugaredLogger) ([]*commonmodels.WorkflowTaskPreview, int64, error) {
@@ -931,6 +1153,15 @@ func ListWorkflowTaskV4ByFilter(filter *TaskHistoryFilter, filterList []string,
 			ProjectName:  filter.ProjectName,
 		}
 	}
+	listTaskOpt.Keyword = strings.TrimSpace(filter.Keyword)
+	if filter.LabelSelector != "" {
+		if err := setTaskLabelFilter(listTaskOpt, filter.LabelSelector); err != nil {
+			return nil, 0, err
+		}
+		if listTaskOpt.TaskIDs != nil && len(listTaskOpt.TaskIDs) == 0 {
+			return make([]*commonmodels.WorkflowTaskPreview, 0), 0, nil
+		}
+	}
 	tasks, total, err := commonrepo.NewworkflowTaskv4Coll().ListByFilter(listTaskOpt, filter.PageNum, filter.PageSize)
 	if err != nil {
 		logger.Errorf("list workflowTaskV4 error: %s", err)
@@ -946,6 +1177,7 @@ func ListWorkflowTaskV4ByFilter(filter *TaskHistoryFilter, filterList []string,
 			WorkflowName:        task.WorkflowName,
 			WorkflowDisplayName: task.WorkflowDisplayName,
 			Remark:              task.Remark,
+			TaskName:            task.TaskName,
 			Status:              task.Status,
 			CreateTime:          task.CreateTime,
 			StartTime:           task.StartTime,
@@ -1069,6 +1301,7 @@ func ListWorkflowTaskV4ByFilter(filter *TaskHistoryFilter, filterList []string,
 					stagePreview.StartTime = stage.StartTime
 					stagePreview.EndTime = stage.EndTime
 					stagePreview.ManualExec = stage.ManualExec
+					stagePreview.PauseAfter = stage.PauseAfter
 					stagePreview.Parallel = stage.Parallel
 					stagePreview.Error = stage.Error
 					break
@@ -1114,6 +1347,86 @@ func CancelWorkflowTaskV4(userName, workflowName string, taskID int64, logger *z
 	return nil
 }
 
+// ListWorkflowTaskImageScanResults returns the vulnerability counts of the images scanned by the image scan jobs of the task.
+func ListWorkflowTaskImageScanResults(workflowName string, taskID int64, logger *zap.SugaredLogger) ([]*commonmodels.ImageScanResult, error) {
+	results, err := commonrepo.NewImageScanResultColl().List(&commonrepo.ImageScanResultListOption{
+		WorkflowName: workflowName,
+		TaskID:       taskID,
+	})
+	if err != nil {
+		logger.Errorf("failed to list image scan results of workflow %s task %d, error: %s", workflowName, taskID, err)
+		return nil, e.ErrGetTask.AddErr(err)
+	}
+	return results, nil
+}
+
+type JobOutputFilter struct {
+	ProjectName   string `json:"projectName"    form:"projectName"`
+	WorkflowName  string `json:"workflowName"   form:"workflowName"`
+	TaskID        int64  `json:"taskID"         form:"taskID"`
+	JobName       string `json:"jobName"        form:"jobName"`
+	ServiceName   string `json:"serviceName"    form:"serviceName"`
+	ServiceModule string `json:"serviceModule"  form:"serviceModule"`
+	OutputName    string `json:"outputName"     form:"outputName"`
+	StartTime     int64  `json:"startTime"      form:"startTime"`
+	EndTime       int64  `json:"endTime"        form:"endTime"`
+	PageNum       int64  `json:"pageNum"        form:"pageNum,default=1"`
+	PageSize      int64  `json:"pageSize"       form:"pageSize,default=50"`
+}
+
+type JobOutputListResp struct {
+	Outputs []*commonmodels.JobOutput `json:"outputs"`
+	Total   int64                     `json:"total"`
+}
+
+// ListJobOutputs returns the historical outputs of the jobs, e.g. the IMAGE built for a service in a task. The outputs
+// are saved when the tasks finish, so they are kept after the tasks are moved into the cold storage.
+func ListJobOutputs(filter *JobOutputFilter, logger *zap.SugaredLogger) (*JobOutputListResp, error) {
+	outputs, total, err := commonrepo.NewJobOutputColl().List(context.TODO(), &commonrepo.JobOutputListOption{
+		ProjectName:   filter.ProjectName,
+		WorkflowName:  filter.WorkflowName,
+		TaskID:        filter.TaskID,
+		JobName:       filter.JobName,
+		ServiceName:   filter.ServiceName,
+		ServiceModule: filter.ServiceModule,
+		Name:          filter.OutputName,
+		StartTime:     filter.StartTime,
+		EndTime:       filter.EndTime,
+		PageNum:       filter.PageNum,
+		PageSize:      filter.PageSize,
+	})
+	if err != nil {
+		logger.Errorf("failed to list job outputs of project %s, error: %s", filter.ProjectName, err)
+		return nil, e.ErrListJobOutputs.AddErr(err)
+	}
+	return &JobOutputListResp{Outputs: outputs, Total: total}, nil
+}
+
+// ListWorkflowTaskNotifications returns the delivery status of the notifications sent for the task.
+func ListWorkflowTaskNotifications(workflowName string, taskID int64, logger *zap.SugaredLogger) ([]*commonmodels.NotificationDelivery, error) {
+	deliveries, err := instantmessage.ListNotificationDeliveries(workflowName, taskID)
+	if err != nil {
+		logger.Errorf("failed to list notification deliveries of workflow %s task %d, error: %s", workflowName, taskID, err)
+		return nil, e.ErrGetTask.AddErr(err)
+	}
+	return deliveries, nil
+}
+
+// DraftWorkflowTaskReleaseNotes drafts the release notes from the commits and service changes of the task with the default llm.
+func DraftWorkflowTaskReleaseNotes(workflowName string, taskID int64, logger *zap.SugaredLogger) (*service.ReleaseNotesDraft, error) {
+	task, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
+	if err != nil {
+		logger.Errorf("find workflowTaskV4 error: %s", err)
+		return nil, e.ErrDraftReleaseNotes.AddErr(err)
+	}
+
+	return service.DraftReleaseNotes(&service.ReleaseNotesSource{
+		Title:       fmt.Sprintf("%s #%d", task.WorkflowDisplayName, task.TaskID),
+		Description: task.Remark,
+		Tasks:       []*commonmodels.WorkflowTask{task},
+	}, logger)
+}
+
 func GetWorkflowTaskV4(workflowName string, taskID int64, logger *zap.SugaredLogger) (*WorkflowTaskPreview, error) {
 	task, err := commonrepo.NewworkflowTaskv4Coll().Find(workflowName, taskID)
 	if err != nil {
@@ -1126,6 +1439,7 @@ func GetWorkflowTaskV4(workflowName string, taskID int64, logger *zap.SugaredLog
 		WorkflowDisplayName: task.WorkflowDisplayName,
 		ProjectName:         task.ProjectName,
 		Remark:              task.Remark,
+		TaskName:            task.TaskName,
 		Status:              task.Status,
 		Params:              task.Params,
 		TaskCreator:         task.TaskCreator,
@@ -1136,6 +1450,7 @@ func GetWorkflowTaskV4(workflowName string, taskID int64, logger *zap.SugaredLog
 		Error:               task.Error,
 		IsRestart:           task.IsRestart,
 		Debug:               task.IsDebug,
+		Risk:                task.Risk,
 	}
 	timeNow := time.Now().Unix()
 	for _, stage := range task.Stages {
@@ -1148,21 +1463,98 @@ func GetWorkflowTaskV4(workflowName string, taskID int64, logger *zap.SugaredLog
 			ManualExec: stage.ManualExec,
 			Jobs:       jobsToJobPreviews(stage.Jobs, task.GlobalContext, timeNow, task.ProjectName),
 			Error:      stage.Error,
+			PauseAfter: stage.PauseAfter,
+			DeployEnvs: deployEnvsToPreviews(stage.Jobs),
 		})
 	}
 	return resp, nil
 }
 
+// deployEnvsToPreviews aggregates the status of the job tasks of the deploy jobs by env, a deploy job is only included
+// if it is fanned out to more than one env.
+func deployEnvsToPreviews(jobs []*commonmodels.JobTask) []*DeployEnvPreview {
+	resp := []*DeployEnvPreview{}
+	envPreviews := make(map[string]*DeployEnvPreview)
+	jobEnvs := make(map[string]sets.String)
+	for _, job := range jobs {
+		if job.JobType != string(config.JobZadigDeploy) && job.JobType != string(config.JobZadigHelmDeploy) {
+			continue
+		}
+		jobInfo := map[string]string{}
+		if err := commonmodels.IToi(job.JobInfo, &jobInfo); err != nil {
+			continue
+		}
+		jobName, envName := jobInfo[jobctl.JobNameKey], jobInfo["env_name"]
+		if jobName == "" || envName == "" {
+			continue
+		}
+		if _, ok := jobEnvs[jobName]; !ok {
+			jobEnvs[jobName] = sets.NewString()
+		}
+		jobEnvs[jobName].Insert(envName)
+
+		key := jobName + "/" + envName
+		preview, ok := envPreviews[key]
+		if !ok {
+			preview = &DeployEnvPreview{JobName: jobName, EnvName: envName, Status: job.Status}
+			envPreviews[key] = preview
+			resp = append(resp, preview)
+			continue
+		}
+		preview.Status = aggregateDeployEnvStatus(preview.Status, job.Status)
+	}
+
+	filtered := []*DeployEnvPreview{}
+	for _, preview := range resp {
+		if jobEnvs[preview.JobName].Len() > 1 {
+			filtered = append(filtered, preview)
+		}
+	}
+	return filtered
+}
+
+// aggregateDeployEnvStatus returns the status of an env given the status of two of its job tasks, a failure outranks
+// unfinished job tasks, which outrank the passed ones.
+func aggregateDeployEnvStatus(a, b config.Status) config.Status {
+	rank := func(status config.Status) int {
+		switch status {
+		case config.StatusCancelled:
+			return 7
+		case config.StatusTimeout:
+			return 6
+		case config.StatusFailed:
+			return 5
+		case config.StatusReject:
+			return 4
+		case config.StatusPassed:
+			return 1
+		case config.StatusUnstable:
+			return 2
+		case config.StatusSkipped:
+			return 0
+		default:
+			// job tasks that are not finished yet
+			return 3
+		}
+	}
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
 func ApproveStage(workflowName, jobName, userName, userID, comment string, taskID int64, approve bool, logger *zap.SugaredLogger) error {
 	if workflowName == "" || jobName == "" || taskID == 0 {
 		errMsg := fmt.Sprintf("can not find approved workflow: %s, taskID: %d,jobName: %s", workflowName, taskID, jobName)
 		logger.Error(errMsg)
 		return e.ErrApproveTask.AddDesc(errMsg)
 	}
-	if err := workflowcontroller.ApproveStage(workflowName, jobName, userName, userID, comment, taskID, approve); err != nil {
+	approval, err := workflowcontroller.ApproveStage(workflowName, jobName, userName, userID, comment, taskID, approve)
+	if err != nil {
 		logger.Error(err)
 		return e.ErrApproveTask.AddErr(err)
 	}
+	service.RecordWorkflowApproval(workflowName, jobName, taskID, userID, userName, comment, approve, approvalservice.OnBehalfOf(approval, userID))
 	return nil
 }
 
@@ -1395,6 +1787,12 @@ func jobsToJobPreviews(jobs []*commonmodels.JobTask, context map[string]string,
 					spec.SonarMetrics = stepSpec.SonarMetrics
 					continue
 				}
+				if step.StepType == config.StepScannerReport {
+					stepSpec := &stepspec.StepScannerReportSpec{}
+					commonmodels.IToi(step.Spec, &stepSpec)
+					spec.ScannerResult = stepSpec.ScannerResult
+					continue
+				}
 			}
 			for _, arg := range taskJobSpec.Properties.Envs {
 				if arg.Key == "SONAR_LINK" {
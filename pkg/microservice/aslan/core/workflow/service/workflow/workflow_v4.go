@@ -95,6 +95,9 @@ func CreateWorkflowV4(user string, workflow *commonmodels.WorkflowV4, logger *za
 	if err := LintWorkflowV4(workflow, logger); err != nil {
 		return err
 	}
+	if err := validateWorkflowRunAs(workflow); err != nil {
+		return err
+	}
 	// lark approval different node type need different approval definition
 	// check whether lark approvals in workflow need to create lark approval definition
 	if err := createLarkApprovalDefinition(workflow); err != nil {
@@ -260,6 +263,9 @@ func UpdateWorkflowV4(name, user string, inputWorkflow *commonmodels.WorkflowV4,
 	if err := LintWorkflowV4(inputWorkflow, logger); err != nil {
 		return err
 	}
+	if err := validateWorkflowRunAs(inputWorkflow); err != nil {
+		return err
+	}
 
 	inputWorkflow.UpdatedBy = user
 	inputWorkflow.UpdateTime = time.Now().Unix()
@@ -268,7 +274,9 @@ func UpdateWorkflowV4(name, user string, inputWorkflow *commonmodels.WorkflowV4,
 	inputWorkflow.JiraHookCtls = workflow.JiraHookCtls
 	inputWorkflow.GeneralHookCtls = workflow.GeneralHookCtls
 	inputWorkflow.MeegoHookCtls = workflow.MeegoHookCtls
+	inputWorkflow.RegistryHookCtls = workflow.RegistryHookCtls
 	inputWorkflow.CustomField = workflow.CustomField
+	inputWorkflow.Owner = workflow.Owner
 
 	for _, stage := range inputWorkflow.Stages {
 		for _, job := range stage.Jobs {
@@ -706,6 +714,7 @@ func clearWorkflowV4Triggers(workflow *commonmodels.WorkflowV4) {
 	workflow.MeegoHookCtls = nil
 	workflow.GeneralHookCtls = nil
 	workflow.JiraHookCtls = nil
+	workflow.RegistryHookCtls = nil
 }
 
 func ensureWorkflowV4Resp(encryptedKey string, workflow *commonmodels.WorkflowV4, logger *zap.SugaredLogger) error {
@@ -979,6 +988,10 @@ func LintWorkflowV4(workflow *commonmodels.WorkflowV4, logger *zap.SugaredLogger
 			return e.ErrUpsertWorkflow.AddDesc("common workflow only support k8s and helm project")
 		}
 	}
+	if err := lintWorkflowRiskPolicy(workflow.RiskPolicy); err != nil {
+		logger.Errorf("invalid risk policy: %s", err)
+		return e.ErrUpsertWorkflow.AddErr(err)
+	}
 	stageNameMap := make(map[string]bool)
 	jobNameMap := make(map[string]string)
 
@@ -1017,9 +1030,116 @@ func LintWorkflowV4(workflow *commonmodels.WorkflowV4, logger *zap.SugaredLogger
 			}
 		}
 	}
+
+	if err := checkJobReferences(workflow); err != nil {
+		logger.Errorf("check job references of workflow %s failed: %v", workflow.Name, err)
+		return e.ErrUpsertWorkflow.AddErr(err)
+	}
 	return nil
 }
 
+// checkJobReferences returns an error listing all the broken job references of the workflow
+func checkJobReferences(workflow *commonmodels.WorkflowV4) error {
+	issues, err := jobctl.CheckJobReferences(workflow)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		msgs = append(msgs, issue.Error())
+	}
+	return fmt.Errorf("invalid job references: %s", strings.Join(msgs, "; "))
+}
+
+type JobReferenceCheckResp struct {
+	Issues []*jobctl.JobReferenceIssue `json:"issues"`
+}
+
+type JobReferenceRepairResp struct {
+	Repaired   []*jobctl.JobReferenceIssue `json:"repaired"`
+	Unresolved []*jobctl.JobReferenceIssue `json:"unresolved"`
+}
+
+// CheckWorkflowV4JobReferences checks the job references of the given workflow, the workflow is not required to be saved
+func CheckWorkflowV4JobReferences(workflow *commonmodels.WorkflowV4, logger *zap.SugaredLogger) (*JobReferenceCheckResp, error) {
+	issues, err := jobctl.CheckJobReferences(workflow)
+	if err != nil {
+		logger.Errorf("failed to check job references of workflow %s, error: %v", workflow.Name, err)
+		return nil, e.ErrCheckJobReferences.AddErr(err)
+	}
+	return &JobReferenceCheckResp{Issues: issues}, nil
+}
+
+// RepairWorkflowV4JobReferences points the broken job references of the saved workflow to the only available job and saves it,
+// the references with no or several candidates are left to the user.
+func RepairWorkflowV4JobReferences(name, user string, logger *zap.SugaredLogger) (*JobReferenceRepairResp, error) {
+	workflow, err := commonrepo.NewWorkflowV4Coll().Find(name)
+	if err != nil {
+		logger.Errorf("failed to find workflow %s, error: %v", name, err)
+		return nil, e.ErrFindWorkflow.AddErr(err)
+	}
+
+	repaired, unresolved, err := jobctl.RepairJobReferences(workflow)
+	if err != nil {
+		logger.Errorf("failed to repair job references of workflow %s, error: %v", name, err)
+		return nil, e.ErrRepairJobReferences.AddErr(err)
+	}
+
+	if len(repaired) > 0 {
+		workflow.UpdatedBy = user
+		workflow.UpdateTime = time.Now().Unix()
+		if err := commonrepo.NewWorkflowV4Coll().Update(workflow.ID.Hex(), workflow); err != nil {
+			logger.Errorf("failed to update workflow %s, error: %v", name, err)
+			return nil, e.ErrRepairJobReferences.AddErr(err)
+		}
+	}
+	return &JobReferenceRepairResp{Repaired: repaired, Unresolved: unresolved}, nil
+}
+
+type WorkflowVariableExplainResp struct {
+	Jobs []*jobctl.JobVariableExplanation `json:"jobs"`
+	// Unresolved is the number of the variables which will be kept as they are at runtime
+	Unresolved int `json:"unresolved"`
+}
+
+// ExplainWorkflowV4Variables simulates the variable rendering of a task created with the given workflow args,
+// nothing is saved and no task is created.
+func ExplainWorkflowV4Variables(args *commonmodels.WorkflowV4, creator, account string, logger *zap.SugaredLogger) (*WorkflowVariableExplainResp, error) {
+	workflow := &commonmodels.WorkflowV4{}
+	if err := commonmodels.IToi(args, workflow); err != nil {
+		logger.Errorf("failed to copy workflow %s args, error: %v", args.Name, err)
+		return nil, e.ErrExplainWorkflowVariables.AddErr(err)
+	}
+	if err := jobctl.InstantiateWorkflow(workflow); err != nil {
+		logger.Errorf("failed to instantiate workflow %s, error: %v", workflow.Name, err)
+		return nil, e.ErrExplainWorkflowVariables.AddErr(err)
+	}
+	if err := jobctl.RemoveFixedValueMarks(workflow); err != nil {
+		logger.Errorf("failed to remove fixed value marks of workflow %s, error: %v", workflow.Name, err)
+		return nil, e.ErrExplainWorkflowVariables.AddErr(err)
+	}
+
+	jobs, err := jobctl.ExplainWorkflowVariables(workflow, creator, account, logger)
+	if err != nil {
+		logger.Errorf("failed to explain variables of workflow %s, error: %v", workflow.Name, err)
+		return nil, e.ErrExplainWorkflowVariables.AddErr(err)
+	}
+
+	resp := &WorkflowVariableExplainResp{Jobs: jobs}
+	for _, job := range jobs {
+		for _, variable := range job.Variables {
+			if variable.Resolution == jobctl.VariableUnresolved {
+				resp.Unresolved++
+			}
+		}
+	}
+	return resp, nil
+}
+
 func createLarkApprovalDefinition(workflow *commonmodels.WorkflowV4) error {
 	for _, stage := range workflow.Stages {
 		for _, job := range stage.Jobs {
@@ -1330,6 +1450,9 @@ func CreateGeneralHookForWorkflowV4(workflowName string, arg *models.GeneralHook
 		logger.Errorf(err.Error())
 		return e.ErrCreateGeneralHook.AddErr(err)
 	}
+	if err := validateGeneralHookParamMappings(arg); err != nil {
+		return e.ErrCreateGeneralHook.AddErr(err)
+	}
 	workflow.GeneralHookCtls = append(workflow.GeneralHookCtls, arg)
 	if err := commonrepo.NewWorkflowV4Coll().Update(workflow.ID.Hex(), workflow); err != nil {
 		errMsg := fmt.Sprintf("failed to create general hook for workflow %s, the error is: %v", workflowName, err)
@@ -1415,6 +1538,9 @@ func UpdateGeneralHookForWorkflowV4(workflowName string, arg *models.GeneralHook
 		logger.Errorf("instantiate hook args error: %s", err)
 		return e.ErrUpdateGeneralHook.AddErr(err)
 	}
+	if err := validateGeneralHookParamMappings(arg); err != nil {
+		return e.ErrUpdateGeneralHook.AddErr(err)
+	}
 
 	workflow, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
 	if err != nil {
@@ -1468,7 +1594,7 @@ func DeleteGeneralHookForWorkflowV4(workflowName, hookName string, logger *zap.S
 	return nil
 }
 
-func GeneralHookEventHandler(workflowName, hookName string, logger *zap.SugaredLogger) error {
+func GeneralHookEventHandler(workflowName, hookName string, payload []byte, logger *zap.SugaredLogger) error {
 	workflowInfo, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to find WorkflowV4: %s, the error is: %v", workflowName, err)
@@ -1492,6 +1618,11 @@ func GeneralHookEventHandler(workflowName, hookName string, logger *zap.SugaredL
 		logger.Error(errMsg)
 		return errors.New(errMsg)
 	}
+	if err := applyGeneralHookParamMappings(generalHook, payload); err != nil {
+		errMsg := fmt.Sprintf("HandleGeneralHookEvent: failed to extract params from payload: %s", err)
+		logger.Error(errMsg)
+		return e.ErrInvalidParam.AddDesc(errMsg)
+	}
 	_, err = CreateWorkflowTaskV4ByBuildInTrigger(setting.GeneralHookTaskCreator, generalHook.WorkflowArg, logger)
 	if err != nil {
 		errMsg := fmt.Sprintf("HandleGeneralHookEvent: failed to create workflow task: %s", err)
@@ -1863,6 +1994,9 @@ func BulkCopyWorkflowV4(args BulkCopyWorkflowArgs, username string, log *zap.Sug
 }
 
 func CreateCronForWorkflowV4(workflowName string, input *commonmodels.Cronjob, logger *zap.SugaredLogger) error {
+	if err := validateCronSchedule(input); err != nil {
+		return e.ErrUpsertCronjob.AddErr(err)
+	}
 	if err := jobctl.InstantiateWorkflow(input.WorkflowV4Args); err != nil {
 		logger.Errorf("instantiate hook args error: %s", err)
 		return e.ErrUpsertCronjob.AddErr(err)
@@ -1903,6 +2037,9 @@ func CreateCronForWorkflowV4(workflowName string, input *commonmodels.Cronjob, l
 }
 
 func UpdateCronForWorkflowV4(input *commonmodels.Cronjob, logger *zap.SugaredLogger) error {
+	if err := validateCronSchedule(input); err != nil {
+		return e.ErrUpsertCronjob.AddErr(err)
+	}
 	if err := jobctl.InstantiateWorkflow(input.WorkflowV4Args); err != nil {
 		logger.Errorf("instantiate hook args error: %s", err)
 		return e.ErrUpsertCronjob.AddErr(err)
@@ -2067,6 +2204,8 @@ func cronJobToSchedule(input *commonmodels.Cronjob) *commonmodels.Schedule {
 		Type:           config.ScheduleType(input.JobType),
 		Cron:           input.Cron,
 		Enabled:        input.Enabled,
+		Timezone:       input.Timezone,
+		Jitter:         input.Jitter,
 	}
 }
 
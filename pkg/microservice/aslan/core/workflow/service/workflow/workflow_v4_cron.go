@@ -0,0 +1,349 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models/msg_queue"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	commonservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+)
+
+const (
+	maxCronJitterSeconds  = 3600
+	defaultCronFireCount  = 5
+	maxCronFireTimesCount = 100
+)
+
+type CronFireTimes struct {
+	CronID   string  `json:"cron_id"`
+	Enabled  bool    `json:"enabled"`
+	Cron     string  `json:"cron"`
+	Timezone string  `json:"timezone"`
+	Jitter   int64   `json:"jitter"`
+	Times    []int64 `json:"times"`
+}
+
+func validateCronSchedule(input *commonmodels.Cronjob) error {
+	if input.Jitter < 0 || input.Jitter > maxCronJitterSeconds {
+		return fmt.Errorf("jitter must be between 0 and %d seconds", maxCronJitterSeconds)
+	}
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %s: %s", input.Timezone, err)
+		}
+	}
+	_, err := parseCronSchedule(input)
+	return err
+}
+
+// cronSpec converts the cron job to the standard cron spec without seconds field, it is the same as the one the cron
+// service schedules.
+func cronSpec(input *commonmodels.Cronjob) (string, error) {
+	switch input.JobType {
+	case setting.CrontabCronjob:
+		return input.Cron, nil
+	case setting.FixedGapCronjob:
+		switch input.Frequency {
+		case setting.FrequencyMinutes:
+			return fmt.Sprintf("*/%d * * * *", input.Number), nil
+		case setting.FrequencyHours:
+			return fmt.Sprintf("0 */%d * * *", input.Number), nil
+		}
+		return "", fmt.Errorf("unsupported gap frequency: %s", input.Frequency)
+	case setting.FixedDayTimeCronjob:
+		timeString := strings.Split(input.Time, ":")
+		if len(timeString) != 2 {
+			return "", fmt.Errorf("invalid time: %s", input.Time)
+		}
+		weekdays := map[string]int{
+			setting.FrequencySunday:    0,
+			setting.FrequencyMondy:     1,
+			setting.FrequencyTuesday:   2,
+			setting.FrequencyWednesday: 3,
+			setting.FrequencyThursday:  4,
+			setting.FrequencyFriday:    5,
+			setting.FrequencySaturday:  6,
+		}
+		if input.Frequency == setting.FrequencyDay {
+			return fmt.Sprintf("%s %s */1 * *", timeString[1], timeString[0]), nil
+		}
+		if weekday, ok := weekdays[input.Frequency]; ok {
+			return fmt.Sprintf("%s %s * * %d", timeString[1], timeString[0], weekday), nil
+		}
+		return "", fmt.Errorf("unsupported timing frequency: %s", input.Frequency)
+	}
+	return "", fmt.Errorf("unsupported cron type: %s", input.JobType)
+}
+
+func parseCronSchedule(input *commonmodels.Cronjob) (cron.Schedule, error) {
+	spec, err := cronSpec(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.Timezone != "" {
+		spec = fmt.Sprintf("CRON_TZ=%s %s", input.Timezone, spec)
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron %s: %s", spec, err)
+	}
+	return schedule, nil
+}
+
+// ListCronFireTimesForWorkflowV4 lists the next fire times of the crons of the workflow, the jitter is not included.
+func ListCronFireTimesForWorkflowV4(workflowName string, count int, logger *zap.SugaredLogger) ([]*CronFireTimes, error) {
+	if count <= 0 {
+		count = defaultCronFireCount
+	}
+	if count > maxCronFireTimesCount {
+		count = maxCronFireTimesCount
+	}
+
+	crons, err := ListCronForWorkflowV4(workflowName, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*CronFireTimes, 0)
+	now := time.Now()
+	for _, cronjob := range crons {
+		fireTimes := &CronFireTimes{
+			CronID:   cronjob.ID.Hex(),
+			Enabled:  cronjob.Enabled,
+			Timezone: cronjob.Timezone,
+			Jitter:   cronjob.Jitter,
+			Times:    make([]int64, 0),
+		}
+		fireTimes.Cron, err = cronSpec(cronjob)
+		if err != nil {
+			logger.Warnf("failed to get spec of cron %s, error: %s", cronjob.ID.Hex(), err)
+			continue
+		}
+		schedule, err := parseCronSchedule(cronjob)
+		if err != nil {
+			logger.Warnf("failed to parse cron %s, error: %s", cronjob.ID.Hex(), err)
+			continue
+		}
+
+		// the paused crons are listed without fire times
+		if cronjob.Enabled {
+			next := now
+			for i := 0; i < count; i++ {
+				next = schedule.Next(next)
+				if next.IsZero() {
+					break
+				}
+				fireTimes.Times = append(fireTimes.Times, next.Unix())
+			}
+		}
+		resp = append(resp, fireTimes)
+	}
+	return resp, nil
+}
+
+// SetCronEnabledForWorkflowV4 pauses or resumes a single cron of the workflow.
+func SetCronEnabledForWorkflowV4(workflowName, cronID string, enabled bool, logger *zap.SugaredLogger) error {
+	id, err := primitive.ObjectIDFromHex(cronID)
+	if err != nil {
+		return e.ErrUpsertCronjob.AddErr(err)
+	}
+	cronjob, err := commonrepo.NewCronjobColl().GetByID(id)
+	if err != nil {
+		logger.Errorf("failed to find cron %s, error: %s", cronID, err)
+		return e.ErrUpsertCronjob.AddErr(err)
+	}
+	if cronjob.Name != workflowName || cronjob.Type != setting.WorkflowV4Cronjob {
+		return e.ErrUpsertCronjob.AddDesc(fmt.Sprintf("cron %s doesn't belong to workflow %s", cronID, workflowName))
+	}
+	if cronjob.Enabled == enabled {
+		return nil
+	}
+
+	cronjob.Enabled = enabled
+	cronjob.BulkPaused = false
+	return setCronjobsEnabled(workflowName, []*commonmodels.Cronjob{cronjob}, enabled, logger)
+}
+
+type BulkCronItem struct {
+	WorkflowName string `json:"workflow_name"`
+	CronID       string `json:"cron_id"`
+}
+
+type BulkSetCronsResp struct {
+	Crons []*BulkCronItem `json:"crons"`
+}
+
+type UpcomingCronRun struct {
+	WorkflowName        string `json:"workflow_name"`
+	WorkflowDisplayName string `json:"workflow_display_name"`
+	CronID              string `json:"cron_id"`
+	Time                int64  `json:"time"`
+}
+
+// SetAllCronsEnabledForWorkflowV4 pauses all the enabled crons of the workflow, or resumes the crons paused by it.
+func SetAllCronsEnabledForWorkflowV4(workflowName string, enabled bool, logger *zap.SugaredLogger) (*BulkSetCronsResp, error) {
+	items, err := bulkSetCronsEnabled(workflowName, enabled, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &BulkSetCronsResp{Crons: items}, nil
+}
+
+// SetAllCronsEnabledForProject pauses all the enabled crons of the workflows in the project, or resumes the crons
+// paused by it, e.g. during the incident freeze.
+func SetAllCronsEnabledForProject(projectName string, enabled bool, logger *zap.SugaredLogger) (*BulkSetCronsResp, error) {
+	workflows, err := commonrepo.NewWorkflowV4Coll().ListByProjectNames([]string{projectName})
+	if err != nil {
+		logger.Errorf("failed to list workflows of project %s, error: %s", projectName, err)
+		return nil, e.ErrUpsertCronjob.AddErr(err)
+	}
+
+	resp := &BulkSetCronsResp{Crons: make([]*BulkCronItem, 0)}
+	for _, workflow := range workflows {
+		items, err := bulkSetCronsEnabled(workflow.Name, enabled, logger)
+		if err != nil {
+			return nil, err
+		}
+		resp.Crons = append(resp.Crons, items...)
+	}
+	return resp, nil
+}
+
+func bulkSetCronsEnabled(workflowName string, enabled bool, logger *zap.SugaredLogger) ([]*BulkCronItem, error) {
+	crons, err := commonrepo.NewCronjobColl().List(&commonrepo.ListCronjobParam{
+		ParentName: workflowName,
+		ParentType: setting.WorkflowV4Cronjob,
+	})
+	if err != nil {
+		logger.Errorf("failed to list crons of workflow %s, error: %s", workflowName, err)
+		return nil, e.ErrUpsertCronjob.AddErr(err)
+	}
+
+	changed := make([]*commonmodels.Cronjob, 0)
+	items := make([]*BulkCronItem, 0)
+	for _, cronjob := range crons {
+		// the crons disabled by the users are not resumed
+		if enabled && (cronjob.Enabled || !cronjob.BulkPaused) {
+			continue
+		}
+		if !enabled && !cronjob.Enabled {
+			continue
+		}
+
+		cronjob.Enabled = enabled
+		cronjob.BulkPaused = !enabled
+		changed = append(changed, cronjob)
+		items = append(items, &BulkCronItem{
+			WorkflowName: workflowName,
+			CronID:       cronjob.ID.Hex(),
+		})
+	}
+	if len(changed) == 0 {
+		return items, nil
+	}
+
+	return items, setCronjobsEnabled(workflowName, changed, enabled, logger)
+}
+
+// setCronjobsEnabled saves the crons of the workflow and notifies the cron service to schedule or stop them.
+func setCronjobsEnabled(workflowName string, cronjobs []*commonmodels.Cronjob, enabled bool, logger *zap.SugaredLogger) error {
+	payload := &commonservice.CronjobPayload{
+		Name:    workflowName,
+		JobType: setting.WorkflowV4Cronjob,
+		Action:  setting.TypeEnableCronjob,
+	}
+	for _, cronjob := range cronjobs {
+		if err := commonrepo.NewCronjobColl().Update(cronjob); err != nil {
+			logger.Errorf("failed to update cron %s, error: %s", cronjob.ID.Hex(), err)
+			return e.ErrUpsertCronjob.AddErr(err)
+		}
+		if enabled {
+			payload.JobList = append(payload.JobList, cronJobToSchedule(cronjob))
+		} else {
+			payload.DeleteList = append(payload.DeleteList, cronjob.ID.Hex())
+		}
+	}
+
+	pl, _ := json.Marshal(payload)
+	if err := commonrepo.NewMsgQueueCommonColl().Create(&msg_queue.MsgQueueCommon{
+		Payload:   string(pl),
+		QueueType: setting.TopicCronjob,
+	}); err != nil {
+		logger.Errorf("Failed to publish cron to MsgQueueCommon, the error is: %v", err)
+		return e.ErrUpsertCronjob.AddDesc(err.Error())
+	}
+	return nil
+}
+
+// ListUpcomingCronRunsForProject lists the next scheduled runs of the enabled workflows in the project ordered by time,
+// the jitter is not included.
+func ListUpcomingCronRunsForProject(projectName string, count int, logger *zap.SugaredLogger) ([]*UpcomingCronRun, error) {
+	if count <= 0 {
+		count = defaultCronFireCount
+	}
+	if count > maxCronFireTimesCount {
+		count = maxCronFireTimesCount
+	}
+
+	workflows, err := commonrepo.NewWorkflowV4Coll().ListByProjectNames([]string{projectName})
+	if err != nil {
+		logger.Errorf("failed to list workflows of project %s, error: %s", projectName, err)
+		return nil, e.ErrGetCronjob.AddErr(err)
+	}
+
+	resp := make([]*UpcomingCronRun, 0)
+	for _, workflow := range workflows {
+		if workflow.Disabled {
+			continue
+		}
+		fireTimes, err := ListCronFireTimesForWorkflowV4(workflow.Name, count, logger)
+		if err != nil {
+			return nil, err
+		}
+		for _, cronFireTimes := range fireTimes {
+			for _, fireTime := range cronFireTimes.Times {
+				resp = append(resp, &UpcomingCronRun{
+					WorkflowName:        workflow.Name,
+					WorkflowDisplayName: workflow.DisplayName,
+					CronID:              cronFireTimes.CronID,
+					Time:                fireTime,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].Time < resp[j].Time
+	})
+	if len(resp) > count {
+		resp = resp[:count]
+	}
+	return resp, nil
+}
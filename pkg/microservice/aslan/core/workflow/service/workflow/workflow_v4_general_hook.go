@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+)
+
+// parseGeneralHookJSONPath accepts both the standard $.a.b syntax and the kubernetes {.a.b} template syntax
+func parseGeneralHookJSONPath(name, path string) (*jsonpath.JSONPath, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("json path cannot be empty")
+	}
+	if !strings.HasPrefix(path, "{") {
+		path = fmt.Sprintf("{%s}", path)
+	}
+
+	parser := jsonpath.New(name).AllowMissingKeys(true)
+	if err := parser.Parse(path); err != nil {
+		return nil, fmt.Errorf("invalid json path %s: %s", path, err)
+	}
+	return parser, nil
+}
+
+func validateGeneralHookParamMappings(hook *commonmodels.GeneralHook) error {
+	if len(hook.ParamMappings) == 0 {
+		return nil
+	}
+	if hook.WorkflowArg == nil {
+		return fmt.Errorf("workflow args of the hook cannot be empty")
+	}
+
+	params := make(map[string]bool)
+	for _, param := range hook.WorkflowArg.Params {
+		params[param.Name] = true
+	}
+	mapped := make(map[string]bool)
+	for _, mapping := range hook.ParamMappings {
+		if !params[mapping.Param] {
+			return fmt.Errorf("param %s is not defined in the workflow", mapping.Param)
+		}
+		if mapped[mapping.Param] {
+			return fmt.Errorf("param %s is mapped more than once", mapping.Param)
+		}
+		mapped[mapping.Param] = true
+		if _, err := parseGeneralHookJSONPath(mapping.Param, mapping.JSONPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyGeneralHookParamMappings sets the workflow params of the hook with the values extracted from the payload.
+// A single string result is used as it is, other results are encoded as json.
+func applyGeneralHookParamMappings(hook *commonmodels.GeneralHook, payload []byte) error {
+	if len(hook.ParamMappings) == 0 {
+		return nil
+	}
+
+	var data interface{}
+	if len(bytes.TrimSpace(payload)) > 0 {
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return fmt.Errorf("payload is not a valid json: %s", err)
+		}
+	}
+
+	values := make(map[string]string)
+	for _, mapping := range hook.ParamMappings {
+		value, found, err := extractGeneralHookParam(mapping, data)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if mapping.Required {
+				return fmt.Errorf("nothing is extracted for required param %s by json path %s", mapping.Param, mapping.JSONPath)
+			}
+			continue
+		}
+		values[mapping.Param] = value
+	}
+
+	for _, param := range hook.WorkflowArg.Params {
+		if value, ok := values[param.Name]; ok {
+			param.Value = value
+		}
+	}
+	return nil
+}
+
+func extractGeneralHookParam(mapping *commonmodels.GeneralHookParamMapping, data interface{}) (string, bool, error) {
+	if data == nil {
+		return "", false, nil
+	}
+	parser, err := parseGeneralHookJSONPath(mapping.Param, mapping.JSONPath)
+	if err != nil {
+		return "", false, err
+	}
+	results, err := parser.FindResults(data)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to evaluate json path %s: %s", mapping.JSONPath, err)
+	}
+
+	values := make([]interface{}, 0)
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+
+	switch len(values) {
+	case 0:
+		return "", false, nil
+	case 1:
+		if str, ok := values[0].(string); ok {
+			return str, true, nil
+		}
+		if values[0] == nil {
+			return "", false, nil
+		}
+		encoded, err := json.Marshal(values[0])
+		if err != nil {
+			return "", false, err
+		}
+		return string(encoded), true, nil
+	default:
+		encoded, err := json.Marshal(values)
+		if err != nil {
+			return "", false, err
+		}
+		return string(encoded), true, nil
+	}
+}
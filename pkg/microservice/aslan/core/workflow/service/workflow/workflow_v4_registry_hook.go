@@ -0,0 +1,414 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/config"
+	commonmodels "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/models"
+	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
+	jobctl "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/service/workflow/job"
+	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
+	"github.com/koderover/zadig/v2/pkg/util"
+)
+
+// RegistryPushedImage is an image tag parsed from a registry webhook payload
+type RegistryPushedImage struct {
+	// Repo is the repository path without the registry host, e.g. library/nginx
+	Repo string `json:"repo"`
+	// Name is the last element of the repository path, which is the image name used by service modules
+	Name  string `json:"name"`
+	Tag   string `json:"tag"`
+	Image string `json:"image"`
+}
+
+type harborWebhookPayload struct {
+	Type      string `json:"type"`
+	EventData struct {
+		Resources []struct {
+			Tag         string `json:"tag"`
+			ResourceURL string `json:"resource_url"`
+		} `json:"resources"`
+		Repository struct {
+			Name         string `json:"name"`
+			Namespace    string `json:"namespace"`
+			RepoFullName string `json:"repo_full_name"`
+		} `json:"repository"`
+	} `json:"event_data"`
+}
+
+type acrWebhookPayload struct {
+	PushData struct {
+		Tag string `json:"tag"`
+	} `json:"push_data"`
+	Repository struct {
+		Name         string `json:"name"`
+		Namespace    string `json:"namespace"`
+		Region       string `json:"region"`
+		RepoFullName string `json:"repo_full_name"`
+	} `json:"repository"`
+}
+
+// ecrWebhookPayload is the ECR image action event delivered by EventBridge
+type ecrWebhookPayload struct {
+	DetailType string `json:"detail-type"`
+	Account    string `json:"account"`
+	Region     string `json:"region"`
+	Detail     struct {
+		Result         string `json:"result"`
+		ActionType     string `json:"action-type"`
+		RepositoryName string `json:"repository-name"`
+		ImageTag       string `json:"image-tag"`
+	} `json:"detail"`
+}
+
+func parseRegistryWebhook(provider config.RegistryHookProvider, body []byte) ([]*RegistryPushedImage, error) {
+	resp := make([]*RegistryPushedImage, 0)
+	switch provider {
+	case config.RegistryHookProviderHarbor:
+		payload := new(harborWebhookPayload)
+		if err := json.Unmarshal(body, payload); err != nil {
+			return nil, fmt.Errorf("invalid harbor webhook payload: %s", err)
+		}
+		// harbor 1.x sends pushImage and harbor 2.x sends PUSH_ARTIFACT
+		if payload.Type != "PUSH_ARTIFACT" && payload.Type != "pushImage" {
+			return resp, nil
+		}
+		repo := payload.EventData.Repository.RepoFullName
+		if repo == "" {
+			repo = fmt.Sprintf("%s/%s", payload.EventData.Repository.Namespace, payload.EventData.Repository.Name)
+		}
+		for _, resource := range payload.EventData.Resources {
+			if resource.Tag == "" {
+				continue
+			}
+			resp = append(resp, &RegistryPushedImage{
+				Repo:  repo,
+				Name:  payload.EventData.Repository.Name,
+				Tag:   resource.Tag,
+				Image: resource.ResourceURL,
+			})
+		}
+	case config.RegistryHookProviderACR:
+		payload := new(acrWebhookPayload)
+		if err := json.Unmarshal(body, payload); err != nil {
+			return nil, fmt.Errorf("invalid acr webhook payload: %s", err)
+		}
+		if payload.PushData.Tag == "" {
+			return resp, nil
+		}
+		resp = append(resp, &RegistryPushedImage{
+			Repo:  payload.Repository.RepoFullName,
+			Name:  payload.Repository.Name,
+			Tag:   payload.PushData.Tag,
+			Image: fmt.Sprintf("registry.%s.aliyuncs.com/%s:%s", payload.Repository.Region, payload.Repository.RepoFullName, payload.PushData.Tag),
+		})
+	case config.RegistryHookProviderECR:
+		payload := new(ecrWebhookPayload)
+		if err := json.Unmarshal(body, payload); err != nil {
+			return nil, fmt.Errorf("invalid ecr webhook payload: %s", err)
+		}
+		if payload.DetailType != "ECR Image Action" || payload.Detail.ActionType != "PUSH" ||
+			payload.Detail.Result != "SUCCESS" || payload.Detail.ImageTag == "" {
+			return resp, nil
+		}
+		nameArr := strings.Split(payload.Detail.RepositoryName, "/")
+		resp = append(resp, &RegistryPushedImage{
+			Repo:  payload.Detail.RepositoryName,
+			Name:  nameArr[len(nameArr)-1],
+			Tag:   payload.Detail.ImageTag,
+			Image: fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s:%s", payload.Account, payload.Region, payload.Detail.RepositoryName, payload.Detail.ImageTag),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported registry hook provider: %s", provider)
+	}
+
+	for _, image := range resp {
+		if image.Image == "" {
+			image.Image = fmt.Sprintf("%s:%s", image.Repo, image.Tag)
+		}
+	}
+	return resp, nil
+}
+
+func validateRegistryHook(hook *commonmodels.RegistryHook) error {
+	if err := validateHookNames([]string{hook.Name}); err != nil {
+		return err
+	}
+	switch hook.Provider {
+	case config.RegistryHookProviderHarbor, config.RegistryHookProviderACR, config.RegistryHookProviderECR:
+	default:
+		return fmt.Errorf("unsupported registry hook provider: %s", hook.Provider)
+	}
+	if _, err := regexp.Compile(hook.RepoFilter); err != nil {
+		return fmt.Errorf("invalid repo filter: %s", err)
+	}
+	if _, err := regexp.Compile(hook.TagFilter); err != nil {
+		return fmt.Errorf("invalid tag filter: %s", err)
+	}
+	return nil
+}
+
+func CreateRegistryHookForWorkflowV4(workflowName string, arg *commonmodels.RegistryHook, logger *zap.SugaredLogger) error {
+	if err := validateRegistryHook(arg); err != nil {
+		return e.ErrCreateRegistryHook.AddErr(err)
+	}
+	if err := jobctl.InstantiateWorkflow(arg.WorkflowArg); err != nil {
+		logger.Errorf("instantiate hook args error: %s", err)
+		return e.ErrCreateRegistryHook.AddErr(err)
+	}
+
+	workflow, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
+	if err != nil {
+		logger.Errorf("Failed to find WorkflowV4: %s, the error is: %v", workflowName, err)
+		return e.ErrCreateRegistryHook.AddErr(err)
+	}
+	for _, hook := range workflow.RegistryHookCtls {
+		if hook.Name == arg.Name {
+			return e.ErrCreateRegistryHook.AddDesc(fmt.Sprintf("registry hook %s already exists", arg.Name))
+		}
+	}
+	workflow.RegistryHookCtls = append(workflow.RegistryHookCtls, arg)
+	if err := commonrepo.NewWorkflowV4Coll().Update(workflow.ID.Hex(), workflow); err != nil {
+		logger.Errorf("failed to create registry hook for workflow %s, the error is: %v", workflowName, err)
+		return e.ErrCreateRegistryHook.AddErr(err)
+	}
+	return nil
+}
+
+func ListRegistryHookForWorkflowV4(workflowName string, logger *zap.SugaredLogger) ([]*commonmodels.RegistryHook, error) {
+	workflow, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
+	if err != nil {
+		logger.Errorf("Failed to find WorkflowV4: %s, the error is: %v", workflowName, err)
+		return nil, e.ErrListRegistryHook.AddErr(err)
+	}
+	if workflow.RegistryHookCtls == nil {
+		return []*commonmodels.RegistryHook{}, nil
+	}
+	return workflow.RegistryHookCtls, nil
+}
+
+func UpdateRegistryHookForWorkflowV4(workflowName string, arg *commonmodels.RegistryHook, logger *zap.SugaredLogger) error {
+	if err := validateRegistryHook(arg); err != nil {
+		return e.ErrUpdateRegistryHook.AddErr(err)
+	}
+	if err := jobctl.InstantiateWorkflow(arg.WorkflowArg); err != nil {
+		logger.Errorf("instantiate hook args error: %s", err)
+		return e.ErrUpdateRegistryHook.AddErr(err)
+	}
+
+	workflow, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
+	if err != nil {
+		logger.Errorf("Failed to find WorkflowV4: %s, the error is: %v", workflowName, err)
+		return e.ErrUpdateRegistryHook.AddErr(err)
+	}
+	updated := false
+	for i, hook := range workflow.RegistryHookCtls {
+		if hook.Name == arg.Name {
+			workflow.RegistryHookCtls[i] = arg
+			updated = true
+		}
+	}
+	if !updated {
+		return e.ErrUpdateRegistryHook.AddDesc(fmt.Sprintf("failed to find registry hook %s", arg.Name))
+	}
+	if err := commonrepo.NewWorkflowV4Coll().Update(workflow.ID.Hex(), workflow); err != nil {
+		logger.Errorf("failed to update registry hook for workflow %s, the error is: %v", workflowName, err)
+		return e.ErrUpdateRegistryHook.AddErr(err)
+	}
+	return nil
+}
+
+func DeleteRegistryHookForWorkflowV4(workflowName, hookName string, logger *zap.SugaredLogger) error {
+	workflow, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
+	if err != nil {
+		logger.Errorf("Failed to find WorkflowV4: %s, the error is: %v", workflowName, err)
+		return e.ErrDeleteRegistryHook.AddErr(err)
+	}
+	var list []*commonmodels.RegistryHook
+	for _, ctl := range workflow.RegistryHookCtls {
+		if ctl.Name == hookName {
+			continue
+		}
+		list = append(list, ctl)
+	}
+	if len(list) == len(workflow.RegistryHookCtls) {
+		return e.ErrDeleteRegistryHook.AddDesc(fmt.Sprintf("registry hook %s not found", hookName))
+	}
+	workflow.RegistryHookCtls = list
+	if err := commonrepo.NewWorkflowV4Coll().Update(workflow.ID.Hex(), workflow); err != nil {
+		logger.Errorf("failed to delete registry hook for workflow %s, the error is: %v", workflowName, err)
+		return e.ErrDeleteRegistryHook.AddErr(err)
+	}
+	return nil
+}
+
+// RegistryHookEventHandler parses the registry webhook payload and triggers the workflow for the pushed images,
+// the targets of the runtime deploy jobs are replaced with the service modules which use the pushed images.
+func RegistryHookEventHandler(workflowName, hookName, token string, body []byte, logger *zap.SugaredLogger) (*CreateTaskV4Resp, error) {
+	workflowInfo, err := commonrepo.NewWorkflowV4Coll().Find(workflowName)
+	if err != nil {
+		logger.Errorf("Failed to find WorkflowV4: %s, the error is: %v", workflowName, err)
+		return nil, e.ErrTriggerRegistryHook.AddErr(err)
+	}
+	var registryHook *commonmodels.RegistryHook
+	for _, hook := range workflowInfo.RegistryHookCtls {
+		if hook.Name == hookName {
+			registryHook = hook
+			break
+		}
+	}
+	if registryHook == nil {
+		return nil, e.ErrTriggerRegistryHook.AddDesc(fmt.Sprintf("failed to find registry hook %s", hookName))
+	}
+	if registryHook.Secret != "" && subtle.ConstantTimeCompare([]byte(registryHook.Secret), []byte(token)) != 1 {
+		return nil, e.ErrUnauthorized.AddDesc("invalid registry hook secret")
+	}
+	if !registryHook.Enabled {
+		return nil, e.ErrTriggerRegistryHook.AddDesc(fmt.Sprintf("registry hook %s is not enabled", hookName))
+	}
+
+	images, err := parseRegistryWebhook(registryHook.Provider, body)
+	if err != nil {
+		return nil, e.ErrTriggerRegistryHook.AddErr(err)
+	}
+	images, err = filterRegistryPushedImages(registryHook, images)
+	if err != nil {
+		return nil, e.ErrTriggerRegistryHook.AddErr(err)
+	}
+	if len(images) == 0 {
+		logger.Infof("RegistryHookEventHandler: no pushed image matches registry hook %s of workflow %s, skip", hookName, workflowName)
+		return nil, nil
+	}
+
+	if err := fillDeployJobsWithPushedImages(registryHook.WorkflowArg, images); err != nil {
+		return nil, e.ErrTriggerRegistryHook.AddErr(err)
+	}
+
+	resp, err := CreateWorkflowTaskV4ByBuildInTrigger(setting.RegistryHookTaskCreator, registryHook.WorkflowArg, logger)
+	if err != nil {
+		logger.Errorf("RegistryHookEventHandler: failed to create workflow task: %s", err)
+		return nil, err
+	}
+	logger.Infof("RegistryHookEventHandler: workflow-%s hook-%s create workflow task success", workflowName, hookName)
+	return resp, nil
+}
+
+func filterRegistryPushedImages(hook *commonmodels.RegistryHook, images []*RegistryPushedImage) ([]*RegistryPushedImage, error) {
+	repoFilter, err := regexp.Compile(hook.RepoFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repo filter: %s", err)
+	}
+	tagFilter, err := regexp.Compile(hook.TagFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag filter: %s", err)
+	}
+
+	resp := make([]*RegistryPushedImage, 0)
+	for _, image := range images {
+		if repoFilter.MatchString(image.Repo) && tagFilter.MatchString(image.Tag) {
+			resp = append(resp, image)
+		}
+	}
+	return resp, nil
+}
+
+// fillDeployJobsWithPushedImages replaces the targets of the runtime deploy jobs with the service modules
+// in the job's env whose image name is the same as a pushed image.
+func fillDeployJobsWithPushedImages(workflow *commonmodels.WorkflowV4, images []*RegistryPushedImage) error {
+	imageMap := make(map[string]*RegistryPushedImage)
+	for _, image := range images {
+		imageMap[image.Name] = image
+	}
+
+	matched := false
+	for _, stage := range workflow.Stages {
+		for _, job := range stage.Jobs {
+			if job.JobType != config.JobZadigDeploy {
+				continue
+			}
+			spec := &commonmodels.ZadigDeployJobSpec{}
+			if err := commonmodels.IToi(job.Spec, spec); err != nil {
+				return err
+			}
+			if spec.Source != config.SourceRuntime {
+				continue
+			}
+
+			envName := strings.ReplaceAll(spec.Env, setting.FixedValueMark, "")
+			production := spec.Production
+			env, err := commonrepo.NewProductColl().Find(&commonrepo.ProductFindOptions{Name: workflow.Project, EnvName: envName, Production: &production})
+			if err != nil {
+				return fmt.Errorf("failed to find env %s of job %s, error: %s", envName, job.Name, err)
+			}
+
+			configuredServices := make(map[string]*commonmodels.DeployServiceInfo)
+			for _, svc := range spec.Services {
+				configuredServices[svc.ServiceName] = svc
+			}
+
+			// follow the service orchestration of the env so that the deploy order is stable
+			services := make([]*commonmodels.DeployServiceInfo, 0)
+			for _, service := range env.GetSvcList() {
+				serviceName := service.ServiceName
+				modules := make([]*commonmodels.DeployModuleInfo, 0)
+				for _, container := range service.Containers {
+					imageName := util.GetImageNameFromContainerInfo(container.ImageName, container.Name)
+					image, ok := imageMap[imageName]
+					if !ok {
+						continue
+					}
+					modules = append(modules, &commonmodels.DeployModuleInfo{
+						ServiceModule: container.Name,
+						Image:         image.Image,
+						ImageName:     imageName,
+					})
+				}
+				if len(modules) == 0 {
+					continue
+				}
+
+				deployService := &commonmodels.DeployServiceInfo{ServiceName: serviceName}
+				if configured, ok := configuredServices[serviceName]; ok {
+					deployService = configured
+				}
+				deployService.Modules = modules
+				services = append(services, deployService)
+			}
+			if len(services) == 0 {
+				continue
+			}
+
+			spec.Services = services
+			job.Spec = spec
+			matched = true
+		}
+	}
+
+	if !matched {
+		return fmt.Errorf("no service module in the deploy jobs uses the pushed images")
+	}
+	return nil
+}
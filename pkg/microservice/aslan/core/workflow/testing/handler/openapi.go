@@ -22,11 +22,21 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/yaml"
+
 	testingservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/workflow/testing/service"
 	internalhandler "github.com/koderover/zadig/v2/pkg/shared/handler"
 	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 )
 
+// @Summary OpenAPI Create Scanning Module
+// @Description OpenAPI Create Scanning Module
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	testingservice.OpenAPICreateScanningReq	true	"body"
+// @Success 200
+// @Router /openapi/quality/codescan [post]
 func OpenAPICreateScanningModule(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -66,6 +76,17 @@ func OpenAPICreateScanningModule(c *gin.Context) {
 	ctx.Err = testingservice.OpenAPICreateScanningModule(ctx.UserName, args, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Scanning Task
+// @Description OpenAPI Create Scanning Task
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	scanName	path	string	true	"scan name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	testingservice.OpenAPICreateScanningTaskReq	true	"body"
+// @Success 200 	{object} 	testingservice.OpenAPICreateScanningTaskResp
+// @Router /openapi/quality/codescan/{scanName}/task [post]
+// @Router /openapi/scanning/{scanName}/task [post]
 func OpenAPICreateScanningTask(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -114,6 +135,17 @@ func OpenAPICreateScanningTask(c *gin.Context) {
 	ctx.Err = err
 }
 
+// @Summary OpenAPI Get Scanning Task Detail
+// @Description OpenAPI Get Scanning Task Detail
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	scanName	path	string	true	"scan name"
+// @Param 	taskID	path	string	true	"task id"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	testingservice.OpenAPIScanTaskDetail
+// @Router /openapi/quality/codescan/{scanName}/task/{taskID} [get]
+// @Router /openapi/scanning/{scanName}/task/{taskID} [get]
 func OpenAPIGetScanningTaskDetail(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -133,6 +165,14 @@ func OpenAPIGetScanningTaskDetail(c *gin.Context) {
 	ctx.Resp, ctx.Err = testingservice.OpenAPIGetScanningTaskDetail(taskID, projectKey, scanName, ctx.Logger)
 }
 
+// @Summary OpenAPI Create Test Task
+// @Description OpenAPI Create Test Task
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	body	body	testingservice.OpenAPICreateTestTaskReq	true	"body"
+// @Success 200 	{object} 	testingservice.OpenAPICreateTestTaskResp
+// @Router /openapi/quality/testing/task [post]
 func OpenAPICreateTestTask(c *gin.Context) {
 	ctx, err := internalhandler.NewContextWithAuthorization(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -180,6 +220,16 @@ func OpenAPICreateTestTask(c *gin.Context) {
 	ctx.Err = err
 }
 
+// @Summary OpenAPI Get Test Task Result
+// @Description OpenAPI Get Test Task Result
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	testName	path	string	true	"test name"
+// @Param 	taskID	path	string	true	"task id"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	testingservice.OpenAPITestTaskDetail
+// @Router /openapi/quality/testing/{testName}/task/{taskID} [get]
 func OpenAPIGetTestTaskResult(c *gin.Context) {
 	ctx := internalhandler.NewContext(c)
 	defer func() { internalhandler.JSONResponse(c, ctx) }()
@@ -198,3 +248,289 @@ func OpenAPIGetTestTaskResult(c *gin.Context) {
 
 	ctx.Resp, ctx.Err = testingservice.OpenAPIGetTestTaskResult(taskID, projectKey, testName, ctx.Logger)
 }
+
+// bindOpenAPIScanningYaml parses the scanning definition in the request body, both yaml and json bodies are accepted
+func bindOpenAPIScanningYaml(c *gin.Context, args *testingservice.OpenAPICreateScanningReq) ([]byte, error) {
+	data, err := c.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(data, args); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// @Summary OpenAPI Create Scanning Module From Yaml
+// @Description OpenAPI Create Scanning Module From Yaml
+// @Tags 	OpenAPI
+// @Accept 	application/x-yaml
+// @Produce json
+// @Param 	body	body	testingservice.OpenAPICreateScanningReq	true	"body"
+// @Success 200
+// @Router /openapi/scanning [post]
+func OpenAPICreateScanningModuleFromYaml(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	args := new(testingservice.OpenAPICreateScanningReq)
+	data, err := bindOpenAPIScanningYaml(c, args)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+	if isValid, err := args.Validate(); !isValid {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, args.ProjectName, "OpenAPI"+"新增", "代码扫描", args.Name, string(data), ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[args.ProjectName]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[args.ProjectName].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[args.ProjectName].Scanning.Create {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = testingservice.OpenAPICreateScanningModule(ctx.UserName, args, ctx.Logger)
+}
+
+// @Summary OpenAPI Update Scanning Module
+// @Description OpenAPI Update Scanning Module
+// @Tags 	OpenAPI
+// @Accept 	application/x-yaml
+// @Produce json
+// @Param 	scanName	path	string	true	"scan name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Param 	body	body	testingservice.OpenAPICreateScanningReq	true	"body"
+// @Success 200
+// @Router /openapi/scanning/{scanName} [put]
+func OpenAPIUpdateScanningModule(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	scanName := c.Param("scanName")
+	args := new(testingservice.OpenAPICreateScanningReq)
+	data, err := bindOpenAPIScanningYaml(c, args)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+	if args.Name == "" {
+		args.Name = scanName
+	}
+	if args.ProjectName == "" {
+		args.ProjectName = c.Query("projectKey")
+	}
+	if isValid, err := args.Validate(); !isValid {
+		ctx.Err = e.ErrInvalidParam.AddDesc(err.Error())
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, args.ProjectName, "OpenAPI"+"更新", "代码扫描", scanName, string(data), ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[args.ProjectName]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[args.ProjectName].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[args.ProjectName].Scanning.Edit {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = testingservice.OpenAPIUpdateScanningModule(ctx.UserName, scanName, args, ctx.Logger)
+}
+
+// @Summary OpenAPI List Scanning Modules
+// @Description OpenAPI List Scanning Modules
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	testingservice.OpenAPIListScanningResp
+// @Router /openapi/scanning [get]
+func OpenAPIListScanningModules(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectKey")
+	if projectKey == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("projectKey cannot be empty")
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].Scanning.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = testingservice.OpenAPIListScanningModules(projectKey, ctx.Logger)
+}
+
+// @Summary OpenAPI Get Scanning Module
+// @Description OpenAPI Get Scanning Module
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	scanName	path	string	true	"scan name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	testingservice.OpenAPIScanningDetail
+// @Router /openapi/scanning/{scanName} [get]
+func OpenAPIGetScanningModule(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectKey")
+	scanName := c.Param("scanName")
+	if projectKey == "" || scanName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid params")
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].Scanning.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = testingservice.OpenAPIGetScanningModule(projectKey, scanName, ctx.Logger)
+}
+
+// @Summary OpenAPI Delete Scanning Module
+// @Description OpenAPI Delete Scanning Module
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	scanName	path	string	true	"scan name"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200
+// @Router /openapi/scanning/{scanName} [delete]
+func OpenAPIDeleteScanningModule(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	projectKey := c.Query("projectKey")
+	scanName := c.Param("scanName")
+	if projectKey == "" || scanName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid params")
+		return
+	}
+	internalhandler.InsertOperationLog(c, ctx.UserName, projectKey, "OpenAPI"+"删除", "代码扫描", scanName, "", ctx.Logger)
+
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].Scanning.Delete {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Err = testingservice.OpenAPIDeleteScanningModule(projectKey, scanName, ctx.Logger)
+}
+
+// @Summary OpenAPI Get Scanning Task Result
+// @Description OpenAPI Get Scanning Task Result
+// @Tags 	OpenAPI
+// @Accept 	json
+// @Produce json
+// @Param 	scanName	path	string	true	"scan name"
+// @Param 	taskID	path	string	true	"task id"
+// @Param 	projectKey	query	string	true	"project key"
+// @Success 200 	{object} 	testingservice.OpenAPIScanTaskResult
+// @Router /openapi/scanning/{scanName}/task/{taskID}/result [get]
+func OpenAPIGetScanningTaskResult(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	scanName := c.Param("scanName")
+	projectKey := c.Query("projectKey")
+	taskID, err := strconv.ParseInt(c.Param("taskID"), 10, 64)
+	if err != nil {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid param taskID")
+		return
+	}
+	if taskID == 0 || projectKey == "" || scanName == "" {
+		ctx.Err = e.ErrInvalidParam.AddDesc("invalid params")
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		if _, ok := ctx.Resources.ProjectAuthInfo[projectKey]; !ok {
+			ctx.UnAuthorized = true
+			return
+		}
+
+		if !ctx.Resources.ProjectAuthInfo[projectKey].IsProjectAdmin &&
+			!ctx.Resources.ProjectAuthInfo[projectKey].Scanning.View {
+			ctx.UnAuthorized = true
+			return
+		}
+	}
+
+	ctx.Resp, ctx.Err = testingservice.OpenAPIGetScanningTaskResult(taskID, projectKey, scanName, ctx.Logger)
+}
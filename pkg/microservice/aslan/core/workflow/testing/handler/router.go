@@ -145,3 +145,16 @@ func (*QualityRouter) Inject(router *gin.RouterGroup) {
 		test.GET("/:testName/task/:taskID", OpenAPIGetTestTaskResult)
 	}
 }
+
+type ScanningOpenAPIRouter struct{}
+
+func (*ScanningOpenAPIRouter) Inject(router *gin.RouterGroup) {
+	router.GET("", OpenAPIListScanningModules)
+	router.POST("", OpenAPICreateScanningModuleFromYaml)
+	router.GET("/:scanName", OpenAPIGetScanningModule)
+	router.PUT("/:scanName", OpenAPIUpdateScanningModule)
+	router.DELETE("/:scanName", OpenAPIDeleteScanningModule)
+	router.POST("/:scanName/task", OpenAPICreateScanningTask)
+	router.GET("/:scanName/task/:taskID", OpenAPIGetScanningTaskDetail)
+	router.GET("/:scanName/task/:taskID/result", OpenAPIGetScanningTaskResult)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	commonrepo "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/repository/mongodb"
 	"github.com/koderover/zadig/v2/pkg/setting"
+	e "github.com/koderover/zadig/v2/pkg/tool/errors"
 	openapitool "github.com/koderover/zadig/v2/pkg/tool/openapi"
 	"github.com/koderover/zadig/v2/pkg/types"
 )
@@ -62,8 +63,8 @@ func OpenAPICreateScanningTask(username, account, userID string, args *OpenAPICr
 					CodehostID:    dbRepo.CodehostID,
 					PRs:           repo.PRs,
 				})
+				break
 			}
-			break
 		}
 	}
 
@@ -73,6 +74,120 @@ func OpenAPICreateScanningTask(username, account, userID string, args *OpenAPICr
 	}, "", log)
 }
 
+func OpenAPIUpdateScanningModule(username, scanName string, args *OpenAPICreateScanningReq, log *zap.SugaredLogger) error {
+	if args.Name != scanName {
+		return e.ErrUpdateScanningModule.AddDesc("scanning name cannot be changed")
+	}
+	scan, err := mongodb.NewScanningColl().Find(args.ProjectName, scanName)
+	if err != nil {
+		log.Errorf("failed to find scanning module, err: %s", err)
+		return e.ErrUpdateScanningModule.AddErr(err)
+	}
+
+	scanning, err := generateScanningModuleFromOpenAPIInput(args, log)
+	if err != nil {
+		log.Errorf("failed to generate scanning module from input, err: %s", err)
+		return e.ErrUpdateScanningModule.AddErr(err)
+	}
+	// keep the key/values defined by the user in the scanning module
+	scanning.Envs = scan.Envs
+	scanning.TemplateID = scan.TemplateID
+
+	return UpdateScanningModule(scan.ID.Hex(), username, scanning, log)
+}
+
+func OpenAPIListScanningModules(projectName string, log *zap.SugaredLogger) (*OpenAPIListScanningResp, error) {
+	scannings, total, err := ListScanningModule(projectName, log)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &OpenAPIListScanningResp{
+		Total:     total,
+		Scannings: make([]*OpenAPIScanningBrief, 0),
+	}
+	for _, scanning := range scannings {
+		brief := &OpenAPIScanningBrief{
+			Name:        scanning.Name,
+			Description: scanning.Description,
+			CreatedAt:   scanning.CreatedAt,
+			UpdatedAt:   scanning.UpdatedAt,
+		}
+		if scanning.Statistics != nil {
+			brief.TimesRun = scanning.Statistics.TimesRun
+			brief.AverageRuntime = scanning.Statistics.AverageRuntime
+		}
+		resp.Scannings = append(resp.Scannings, brief)
+	}
+	return resp, nil
+}
+
+func OpenAPIGetScanningModule(projectName, scanName string, log *zap.SugaredLogger) (*OpenAPIScanningDetail, error) {
+	scan, err := mongodb.NewScanningColl().Find(projectName, scanName)
+	if err != nil {
+		log.Errorf("OpenAPI: failed to find scanning module:%s in project:%s, err: %s", scanName, projectName, err)
+		return nil, err
+	}
+
+	resp := &OpenAPIScanningDetail{
+		Name:              scan.Name,
+		ProjectName:       scan.ProjectName,
+		Description:       scan.Description,
+		ScannerType:       scan.ScannerType,
+		Addons:            scan.Installs,
+		SonarParameter:    scan.Parameter,
+		Script:            scan.Script,
+		EnableQualityGate: scan.CheckQualityGate,
+		KeyVals:           scan.Envs,
+		UpdatedBy:         scan.UpdatedBy,
+		CreatedAt:         scan.CreatedAt,
+		UpdatedAt:         scan.UpdatedAt,
+		RepoInfo:          make([]*OpenAPIScanRepoBrief, 0),
+	}
+
+	if scan.ImageID != "" {
+		image, err := mongodb.NewBasicImageColl().Find(scan.ImageID)
+		if err != nil {
+			log.Warnf("OpenAPI: failed to find image %s of scanning %s, err: %s", scan.ImageID, scanName, err)
+		} else {
+			resp.ImageName = image.Label
+		}
+	}
+	if scan.ScannerType == "sonarQube" && scan.SonarID != "" {
+		sonarInfo, err := mongodb.NewSonarIntegrationColl().GetByID(context.TODO(), scan.SonarID)
+		if err != nil {
+			log.Warnf("OpenAPI: failed to find sonar integration %s of scanning %s, err: %s", scan.SonarID, scanName, err)
+		} else {
+			resp.SonarSystem = sonarInfo.SystemIdentity
+		}
+	}
+	for _, repo := range scan.Repos {
+		resp.RepoInfo = append(resp.RepoInfo, &OpenAPIScanRepoBrief{
+			RepoName:     repo.RepoName,
+			RepoOwner:    repo.RepoOwner,
+			Source:       repo.Source,
+			Address:      repo.Address,
+			Branch:       repo.Branch,
+			RemoteName:   repo.RemoteName,
+			Hidden:       repo.Hidden,
+			CheckoutPath: repo.CheckoutPath,
+			SubModules:   repo.SubModules,
+		})
+	}
+
+	return resp, nil
+}
+
+func OpenAPIDeleteScanningModule(projectName, scanName string, log *zap.SugaredLogger) error {
+	scan, err := mongodb.NewScanningColl().Find(projectName, scanName)
+	if err != nil {
+		log.Errorf("OpenAPI: failed to find scanning module:%s in project:%s, err: %s", scanName, projectName, err)
+		return err
+	}
+
+	return DeleteScanningModuleByID(scan.ID.Hex(), log)
+}
+
 func generateScanningModuleFromOpenAPIInput(req *OpenAPICreateScanningReq, log *zap.SugaredLogger) (*Scanning, error) {
 	ret := &Scanning{
 		Name:             req.Name,
@@ -108,6 +223,7 @@ func generateScanningModuleFromOpenAPIInput(req *OpenAPICreateScanningReq, log *
 	imageInfo, err := mongodb.NewBasicImageColl().FindByImageName(req.ImageName)
 	if err != nil {
 		log.Errorf("failed to find the image name by tag")
+		return nil, fmt.Errorf("failed to find image: %s", req.ImageName)
 	}
 	ret.ImageID = imageInfo.ID.Hex()
 
@@ -239,3 +355,39 @@ func OpenAPIGetScanningTaskDetail(taskID int64, productName, scanName string, lo
 
 	return resp, nil
 }
+
+func OpenAPIGetScanningTaskResult(taskID int64, productName, scanName string, logger *zap.SugaredLogger) (*OpenAPIScanTaskResult, error) {
+	scan, err := mongodb.NewScanningColl().Find(productName, scanName)
+	if err != nil {
+		logger.Errorf("OpenAPI: failed to find scanning module:%s in project:%s, err: %s", scanName, productName, err)
+		return nil, err
+	}
+	detail, err := GetScanningTaskInfo(scan.ID.Hex(), taskID, logger)
+	if err != nil {
+		logger.Errorf("OpenAPI: failed to get scanning task:%d detail, err: %s", taskID, err)
+		return nil, err
+	}
+
+	resp := &OpenAPIScanTaskResult{
+		ScanName:    scanName,
+		TaskID:      taskID,
+		ScannerType: scan.ScannerType,
+		Status:      strings.ToLower(detail.Status),
+		ResultLink:  detail.ResultLink,
+		Scanner:     detail.ScannerResult,
+	}
+	if detail.SonarMetrics != nil {
+		resp.Sonar = &OpenAPISonarResult{
+			Ncloc:           detail.SonarMetrics.Ncloc,
+			Bugs:            detail.SonarMetrics.Bugs,
+			Vulnerabilities: detail.SonarMetrics.Vulnerabilities,
+			CodeSmells:      detail.SonarMetrics.CodeSmells,
+			Coverage:        detail.SonarMetrics.Coverage,
+		}
+		if scan.CheckQualityGate {
+			resp.QualityGate = string(detail.SonarMetrics.QualityGateStatus)
+		}
+	}
+
+	return resp, nil
+}
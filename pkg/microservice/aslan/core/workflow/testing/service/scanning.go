@@ -267,6 +267,7 @@ func CreateScanningTask(id string, req []*ScanningRepoInfo, notificationID, user
 		scanningInfo.EnableScanner = templateInfo.EnableScanner
 		scanningInfo.ImageID = templateInfo.ImageID
 		scanningInfo.SonarID = templateInfo.SonarID
+		scanningInfo.ScannerIntegrationID = templateInfo.ScannerIntegrationID
 		scanningInfo.Installs = templateInfo.Installs
 		scanningInfo.Parameter = templateInfo.Parameter
 		scanningInfo.Envs = templateInfo.Envs
@@ -589,6 +590,7 @@ func GetScanningTaskInfo(scanningID string, taskID int64, log *zap.SugaredLogger
 	}
 
 	sonarMetrics := &stepspec.SonarMetrics{}
+	var scannerResult *stepspec.ScannerResult
 	if scanningInfo.ScannerType == "sonarQube" {
 		sonarInfo, err := commonrepo.NewSonarIntegrationColl().GetByID(context.TODO(), scanningInfo.SonarID)
 		if err != nil {
@@ -612,6 +614,14 @@ func GetScanningTaskInfo(scanningID string, taskID int64, log *zap.SugaredLogger
 		}
 	} else {
 		sonarMetrics = nil
+		for _, step := range jobTaskSpec.Steps {
+			if step.StepType == config.StepScannerReport {
+				stepSpec := &stepspec.StepScannerReportSpec{}
+				commonmodels.IToi(step.Spec, &stepSpec)
+				scannerResult = stepSpec.ScannerResult
+				break
+			}
+		}
 	}
 
 	repoInfo := spec.Scannings[0].Repos
@@ -623,13 +633,14 @@ func GetScanningTaskInfo(scanningID string, taskID int64, log *zap.SugaredLogger
 	}
 
 	return &ScanningTaskDetail{
-		Creator:      workflowTask.TaskCreator,
-		Status:       string(workflowTask.Status),
-		CreateTime:   workflowTask.CreateTime,
-		EndTime:      workflowTask.EndTime,
-		RepoInfo:     repoInfo,
-		SonarMetrics: sonarMetrics,
-		ResultLink:   resultAddr,
+		Creator:       workflowTask.TaskCreator,
+		Status:        string(workflowTask.Status),
+		CreateTime:    workflowTask.CreateTime,
+		EndTime:       workflowTask.EndTime,
+		RepoInfo:      repoInfo,
+		SonarMetrics:  sonarMetrics,
+		ScannerResult: scannerResult,
+		ResultLink:    resultAddr,
 	}, nil
 }
 
@@ -55,6 +55,9 @@ func CreateTesting(username string, testing *commonmodels.Testing, log *zap.Suga
 	if err := commonutil.CheckDefineResourceParam(testing.PreTest.ResReq, testing.PreTest.ResReqSpec); err != nil {
 		return e.ErrCreateTestModule.AddDesc(err.Error())
 	}
+	if err := commonutil.CheckHTMLReports(testing.HTMLReports); err != nil {
+		return e.ErrCreateTestModule.AddDesc(err.Error())
+	}
 	err := HandleCronjob(testing, log)
 	if err != nil {
 		return e.ErrCreateTestModule.AddErr(err)
@@ -118,6 +121,9 @@ func UpdateTesting(username string, testing *commonmodels.Testing, log *zap.Suga
 	if err := commonutil.CheckDefineResourceParam(testing.PreTest.ResReq, testing.PreTest.ResReqSpec); err != nil {
 		return e.ErrUpdateTestModule.AddDesc(err.Error())
 	}
+	if err := commonutil.CheckHTMLReports(testing.HTMLReports); err != nil {
+		return e.ErrUpdateTestModule.AddDesc(err.Error())
+	}
 	err := HandleCronjob(testing, log)
 	if err != nil {
 		return e.ErrUpdateTestModule.AddErr(err)
@@ -41,6 +41,8 @@ type Scanning struct {
 	Repos          []*types.Repository  `json:"repos"`
 	// Parameter is for sonarQube type only
 	Parameter string `json:"parameter"`
+	// ScannerIntegrationID is for semgrep and checkmarx type only
+	ScannerIntegrationID string `json:"scanner_integration_id"`
 	// Envs is the user defined key/values
 	Envs             []*commonmodels.KeyVal                `json:"envs"`
 	ScriptType       types.ScriptType                      `json:"script_type"`
@@ -176,30 +178,33 @@ type ScanningTaskDetail struct {
 	RepoInfo     []*types.Repository `json:"repo_info"`
 	SonarMetrics *step.SonarMetrics  `json:"sonar_metrics"`
 	ResultLink   string              `json:"result_link,omitempty"`
+	// ScannerResult is the parsed report of semgrep and checkmarx type
+	ScannerResult *step.ScannerResult `json:"scanner_result,omitempty"`
 }
 
 func ConvertToDBScanningModule(args *Scanning) *commonmodels.Scanning {
 	// ID is omitted since they are of different type and there will be no use of it
 	return &commonmodels.Scanning{
-		Name:             args.Name,
-		ProjectName:      args.ProjectName,
-		Description:      args.Description,
-		ScannerType:      args.ScannerType,
-		EnableScanner:    args.EnableScanner,
-		ImageID:          args.ImageID,
-		Infrastructure:   args.Infrastructure,
-		VMLabels:         args.VMLabels,
-		SonarID:          args.SonarID,
-		Repos:            args.Repos,
-		Parameter:        args.Parameter,
-		ScriptType:       args.ScriptType,
-		Script:           args.Script,
-		AdvancedSetting:  args.AdvancedSetting,
-		Installs:         args.Installs,
-		CheckQualityGate: args.CheckQualityGate,
-		Outputs:          args.Outputs,
-		Envs:             args.Envs,
-		TemplateID:       args.TemplateID,
+		Name:                 args.Name,
+		ProjectName:          args.ProjectName,
+		Description:          args.Description,
+		ScannerType:          args.ScannerType,
+		EnableScanner:        args.EnableScanner,
+		ImageID:              args.ImageID,
+		Infrastructure:       args.Infrastructure,
+		VMLabels:             args.VMLabels,
+		SonarID:              args.SonarID,
+		ScannerIntegrationID: args.ScannerIntegrationID,
+		Repos:                args.Repos,
+		Parameter:            args.Parameter,
+		ScriptType:           args.ScriptType,
+		Script:               args.Script,
+		AdvancedSetting:      args.AdvancedSetting,
+		Installs:             args.Installs,
+		CheckQualityGate:     args.CheckQualityGate,
+		Outputs:              args.Outputs,
+		Envs:                 args.Envs,
+		TemplateID:           args.TemplateID,
 	}
 }
 
@@ -208,26 +213,27 @@ func ConvertDBScanningModule(scanning *commonmodels.Scanning) *Scanning {
 		repo.RepoNamespace = repo.GetRepoNamespace()
 	}
 	return &Scanning{
-		ID:               scanning.ID.Hex(),
-		Name:             scanning.Name,
-		ProjectName:      scanning.ProjectName,
-		Description:      scanning.Description,
-		ScannerType:      scanning.ScannerType,
-		EnableScanner:    scanning.EnableScanner,
-		ImageID:          scanning.ImageID,
-		SonarID:          scanning.SonarID,
-		Infrastructure:   scanning.Infrastructure,
-		VMLabels:         scanning.VMLabels,
-		Repos:            scanning.Repos,
-		Parameter:        scanning.Parameter,
-		ScriptType:       scanning.ScriptType,
-		Script:           scanning.Script,
-		AdvancedSetting:  scanning.AdvancedSetting,
-		Installs:         scanning.Installs,
-		CheckQualityGate: scanning.CheckQualityGate,
-		Outputs:          scanning.Outputs,
-		Envs:             scanning.Envs,
-		TemplateID:       scanning.TemplateID,
+		ID:                   scanning.ID.Hex(),
+		Name:                 scanning.Name,
+		ProjectName:          scanning.ProjectName,
+		Description:          scanning.Description,
+		ScannerType:          scanning.ScannerType,
+		EnableScanner:        scanning.EnableScanner,
+		ImageID:              scanning.ImageID,
+		SonarID:              scanning.SonarID,
+		ScannerIntegrationID: scanning.ScannerIntegrationID,
+		Infrastructure:       scanning.Infrastructure,
+		VMLabels:             scanning.VMLabels,
+		Repos:                scanning.Repos,
+		Parameter:            scanning.Parameter,
+		ScriptType:           scanning.ScriptType,
+		Script:               scanning.Script,
+		AdvancedSetting:      scanning.AdvancedSetting,
+		Installs:             scanning.Installs,
+		CheckQualityGate:     scanning.CheckQualityGate,
+		Outputs:              scanning.Outputs,
+		Envs:                 scanning.Envs,
+		TemplateID:           scanning.TemplateID,
 	}
 }
 
@@ -262,6 +268,58 @@ type OpenAPIScanTaskDetail struct {
 	RepoInfo   []*OpenAPIScanRepoBrief `json:"repo_info"`
 }
 
+type OpenAPIScanTaskResult struct {
+	ScanName    string `json:"scan_name"`
+	TaskID      int64  `json:"task_id"`
+	ScannerType string `json:"scanner_type"`
+	Status      string `json:"status"`
+	ResultLink  string `json:"result_link"`
+	// QualityGate is the sonar quality gate status, empty if the quality gate is not checked
+	QualityGate string              `json:"quality_gate,omitempty"`
+	Sonar       *OpenAPISonarResult `json:"sonar,omitempty"`
+	Scanner     *step.ScannerResult `json:"scanner,omitempty"`
+}
+
+type OpenAPISonarResult struct {
+	Ncloc           string `json:"ncloc"`
+	Bugs            string `json:"bugs"`
+	Vulnerabilities string `json:"vulnerabilities"`
+	CodeSmells      string `json:"code_smells"`
+	Coverage        string `json:"coverage"`
+}
+
+type OpenAPIListScanningResp struct {
+	Total     int64                   `json:"total"`
+	Scannings []*OpenAPIScanningBrief `json:"scannings"`
+}
+
+type OpenAPIScanningBrief struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	TimesRun       int64  `json:"times_run"`
+	AverageRuntime int64  `json:"average_runtime"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
+
+type OpenAPIScanningDetail struct {
+	Name              string                  `json:"name"`
+	ProjectName       string                  `json:"project_key"`
+	Description       string                  `json:"description"`
+	ScannerType       string                  `json:"scanner_type"`
+	ImageName         string                  `json:"image_name"`
+	SonarSystem       string                  `json:"sonar_system,omitempty"`
+	RepoInfo          []*OpenAPIScanRepoBrief `json:"repo_info"`
+	Addons            []*commonmodels.Item    `json:"addons"`
+	SonarParameter    string                  `json:"sonar_parameter,omitempty"`
+	Script            string                  `json:"script"`
+	EnableQualityGate bool                    `json:"enable_quality_gate"`
+	KeyVals           []*commonmodels.KeyVal  `json:"key_vals"`
+	UpdatedBy         string                  `json:"updated_by"`
+	CreatedAt         int64                   `json:"created_at"`
+	UpdatedAt         int64                   `json:"updated_at"`
+}
+
 type OpenAPIScanRepoBrief struct {
 	RepoOwner    string `json:"repo_owner"`
 	Source       string `json:"source"`
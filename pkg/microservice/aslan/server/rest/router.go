@@ -38,6 +38,7 @@ import (
 	clusterservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/multicluster/service"
 	projecthandler "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/project/handler"
 	releaseplanhandler "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/release_plan/handler"
+	resourcehandler "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/resource/handler"
 	servicehandler "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/service/handler"
 	stathandler "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/stat/handler"
 	systemhandler "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/handler"
@@ -97,6 +98,8 @@ func (s *engine) injectRouterGroup(router *gin.RouterGroup) {
 			s.HandleContext(c)
 		})
 		public.GET("/health", commonhandler.Health)
+		public.GET("/healthz", commonhandler.Healthz)
+		public.GET("/readyz", commonhandler.Readyz)
 		public.POST("/callback", commonhandler.HandleCallback)
 	}
 
@@ -107,12 +110,14 @@ func (s *engine) injectRouterGroup(router *gin.RouterGroup) {
 		"/openapi/workflows":    new(workflowhandler.OpenAPIRouter),
 		"/openapi/environments": new(environmenthandler.OpenAPIRouter),
 		"/openapi/quality":      new(testinghandler.QualityRouter),
+		"/openapi/scanning":     new(testinghandler.ScanningOpenAPIRouter),
 		"/openapi/build":        new(buildhandler.OpenAPIRouter),
 		"/openapi/service":      new(servicehandler.OpenAPIRouter),
 		"/openapi/release_plan": new(releaseplanhandler.OpenAPIRouter),
 		"/openapi/delivery":     new(deliveryhandler.OpenAPIRouter),
 		"/openapi/cluster":      new(multiclusterhandler.OpenAPIRouter),
 		"/openapi/logs":         new(loghandler.OpenAPIRouter),
+		"/openapi/resources":    new(resourcehandler.OpenAPIRouter),
 	} {
 		r.Inject(router.Group(name))
 	}
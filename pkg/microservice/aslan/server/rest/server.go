@@ -21,7 +21,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/koderover/zadig/v2/pkg/config"
+	systemservice "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/system/service"
 	ginmiddleware "github.com/koderover/zadig/v2/pkg/middleware/gin"
+	"github.com/koderover/zadig/v2/pkg/tool/apispec"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
 )
 
@@ -38,6 +40,7 @@ func NewEngine() *engine {
 
 	s.injectMiddlewares()
 	s.injectRouters()
+	s.initOpenAPISpec()
 
 	return s
 }
@@ -77,3 +80,32 @@ func (s *engine) injectRouters() {
 
 	s.Engine = g
 }
+
+// initOpenAPISpec generates the OpenAPI spec from the registered routes, it must be called after the routers are injected.
+func (s *engine) initOpenAPISpec() {
+	systemservice.InitOpenAPISpec(s.routes())
+}
+
+func (s *engine) routes() []apispec.Route {
+	routes := make([]apispec.Route, 0)
+	for _, route := range s.Routes() {
+		routes = append(routes, apispec.Route{
+			Method:  route.Method,
+			Path:    route.Path,
+			Handler: route.Handler,
+		})
+	}
+	return routes
+}
+
+// Routes returns the routes of aslan without starting the server, it's used to generate the OpenAPI spec offline.
+func Routes() []apispec.Route {
+	s := &engine{mode: gin.TestMode}
+
+	gin.SetMode(s.mode)
+
+	s.injectMiddlewares()
+	s.injectRouters()
+
+	return s.routes()
+}
@@ -67,6 +67,17 @@ func (c *Client) TriggerCleanProducts(log *zap.SugaredLogger) error {
 	return err
 }
 
+// TriggerCleanSandboxProjects triggers the deletion of expired sandbox projects
+func (c *Client) TriggerCleanSandboxProjects(log *zap.SugaredLogger) error {
+	url := fmt.Sprintf("%s/project/sandbox/cron/clean", c.APIBase)
+	log.Info("start clean expired sandbox projects..")
+	err := c.sendRequest(url)
+	if err != nil {
+		log.Errorf("trigger clean sandbox projects error :%s", err)
+	}
+	return err
+}
+
 // TriggerCleanCIResources trigger clean CollaborationInstance Resources
 func (c *Client) TriggerCleanCIResources(log *zap.SugaredLogger) error {
 	url := fmt.Sprintf("%s/collaboration/collaborations/cron/clean", c.APIBase)
@@ -44,6 +44,8 @@ type Cronjob struct {
 	ReleasePlanArgs *ReleasePlanArgs  `json:"release_plan_args,omitempty"`
 	JobType         string            `json:"job_type"`
 	Enabled         bool              `json:"enabled"`
+	Timezone        string            `json:"timezone,omitempty"`
+	Jitter          int64             `json:"jitter,omitempty"`
 }
 
 // param type: cronjob的执行内容类型
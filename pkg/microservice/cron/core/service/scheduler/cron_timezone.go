@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/rfyiamcool/cronlib"
+	"github.com/robfig/cron/v3"
+)
+
+// everyMinuteSpec fires at the beginning of every minute, the crons with timezone are checked against it since cronlib
+// only evaluates the spec in the local timezone.
+const everyMinuteSpec = "0 * * * * *"
+
+var timezoneCronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// newJobModel creates the cronlib job of the spec with seconds field, which is evaluated in the given timezone and
+// delayed by a random duration up to jitter seconds.
+func newJobModel(spec, timezone string, jitter int64, f func()) (*cronlib.JobModel, error) {
+	do := f
+	if jitter > 0 {
+		do = func() {
+			time.AfterFunc(time.Duration(rand.Int63n(jitter+1))*time.Second, f)
+		}
+	}
+
+	if timezone == "" {
+		return cronlib.NewJobModel(spec, do)
+	}
+
+	schedule, err := timezoneCronParser.Parse(fmt.Sprintf("CRON_TZ=%s %s", timezone, spec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cron %s in timezone %s: %s", spec, timezone, err)
+	}
+	return cronlib.NewJobModel(everyMinuteSpec, func() {
+		now := time.Now().Truncate(time.Minute)
+		if schedule.Next(now.Add(-time.Second)).Equal(now) {
+			do()
+		}
+	})
+}
@@ -286,7 +286,7 @@ func (h *CronjobHandler) registerWorkFlowV4Job(name, schedule string, job *servi
 	if job.WorkflowV4Args == nil {
 		return nil
 	}
-	scheduleJob, err := cronlib.NewJobModel(schedule, func() {
+	scheduleJob, err := newJobModel(schedule, job.Timezone, job.Jitter, func() {
 		if err := h.aslanCli.ScheduleCall(fmt.Sprintf("workflow/v4/workflowtask/trigger?triggerName=%s", setting.CronTaskCreator), job.WorkflowV4Args, log.SugaredLogger()); err != nil {
 			log.Errorf("[%s]RunScheduledTask err: %v", name, err)
 		}
@@ -415,7 +415,7 @@ func registerCronjob(job *service.Cronjob, client *client.Client, scheduler *cro
 		} else {
 			cron, _ = convertCronString(job.JobType, job.Time, job.Frequency, job.Number)
 		}
-		scheduleJob, err := cronlib.NewJobModel(cron, func() {
+		scheduleJob, err := newJobModel(cron, job.Timezone, job.Jitter, func() {
 			if err := client.ScheduleCall(fmt.Sprintf("workflow/v4/workflowtask/trigger?triggerName=%s", setting.CronTaskCreator), job.WorkflowV4Args, log.SugaredLogger()); err != nil {
 				log.Errorf("[%s]RunScheduledTask err: %v", job.Name, err)
 			}
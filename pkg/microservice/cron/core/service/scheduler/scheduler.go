@@ -127,6 +127,8 @@ const (
 
 	CleanCIResourcesScheduler = "CleanCIResourcesScheduler"
 
+	CleanSandboxProjectsScheduler = "CleanSandboxProjectsScheduler"
+
 	InitStatScheduler = "InitStatScheduler"
 
 	InitOperationStatScheduler = "InitOperationStatScheduler"
@@ -211,6 +213,8 @@ func (c *CronClient) Init() {
 	c.InitCleanProductScheduler()
 	// clean collaboration instance resource every 5 minutes
 	c.InitCleanCIResourcesScheduler()
+	// delete expired sandbox projects every hour
+	c.InitCleanSandboxProjectsScheduler()
 	// 定时初始化构建数据
 	c.InitBuildStatScheduler()
 	// 定时初始化健康检查
@@ -250,6 +254,15 @@ func (c *CronClient) InitCleanCIResourcesScheduler() {
 	c.Schedulers[CleanCIResourcesScheduler].Start()
 }
 
+func (c *CronClient) InitCleanSandboxProjectsScheduler() {
+
+	c.Schedulers[CleanSandboxProjectsScheduler] = gocron.NewScheduler()
+
+	c.Schedulers[CleanSandboxProjectsScheduler].Every(1).Hour().Do(c.AslanCli.TriggerCleanSandboxProjects, c.log)
+
+	c.Schedulers[CleanSandboxProjectsScheduler].Start()
+}
+
 func (c *CronClient) InitJobScheduler() {
 
 	c.Schedulers[UpsertWorkflowScheduler] = gocron.NewScheduler()
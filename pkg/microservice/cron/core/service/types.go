@@ -94,7 +94,9 @@ type Schedule struct {
 	Cron            string             `bson:"cron"                          json:"cron"`
 	IsModified      bool               `bson:"-"                             json:"-"`
 	// 自由编排工作流的开关是放在schedule里面的
-	Enabled bool `bson:"enabled"                       json:"enabled"`
+	Enabled  bool   `bson:"enabled"                       json:"enabled"`
+	Timezone string `bson:"timezone,omitempty"            json:"timezone,omitempty"`
+	Jitter   int64  `bson:"jitter,omitempty"              json:"jitter,omitempty"`
 }
 
 // Validate validate schedule setting
@@ -68,6 +68,11 @@ func RunStep(ctx context.Context, step *meta.Step, workspace, paths string, envs
 		if err != nil {
 			return err
 		}
+	case "artifact_upload":
+		stepInstance, err = NewArtifactUploadStep(step.Spec, workspace, envs, secretEnvs)
+		if err != nil {
+			return err
+		}
 	case "download_archive":
 		stepInstance, err = NewDownloadArchiveStep(step.Spec, workspace, envs, secretEnvs)
 		if err != nil {
@@ -93,6 +98,11 @@ func RunStep(ctx context.Context, step *meta.Step, workspace, paths string, envs
 		if err != nil {
 			return err
 		}
+	case "scanner_report":
+		stepInstance, err = NewScannerReportStep(step.Spec, workspace, envs, secretEnvs)
+		if err != nil {
+			return err
+		}
 	case "distribute_image":
 		stepInstance, err = NewDistributeImageStep(step.Spec, workspace, envs, secretEnvs)
 		if err != nil {
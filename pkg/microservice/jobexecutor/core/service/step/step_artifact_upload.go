@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package step
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/koderover/zadig/v2/pkg/tool/artifactrepo"
+	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/types/step"
+)
+
+type ArtifactUploadStep struct {
+	spec       *step.StepArtifactUploadSpec
+	envs       []string
+	secretEnvs []string
+	workspace  string
+}
+
+func NewArtifactUploadStep(spec interface{}, workspace string, envs, secretEnvs []string) (*ArtifactUploadStep, error) {
+	artifactUploadStep := &ArtifactUploadStep{workspace: workspace, envs: envs, secretEnvs: secretEnvs}
+	yamlBytes, err := yaml.Marshal(spec)
+	if err != nil {
+		return artifactUploadStep, fmt.Errorf("marshal spec %+v failed", spec)
+	}
+	if err := yaml.Unmarshal(yamlBytes, &artifactUploadStep.spec); err != nil {
+		return artifactUploadStep, fmt.Errorf("unmarshal spec %s to artifact upload spec failed", yamlBytes)
+	}
+	return artifactUploadStep, nil
+}
+
+func (s *ArtifactUploadStep) Run(ctx context.Context) error {
+	start := time.Now()
+	defer func() {
+		log.Infof("Artifact upload ended. Duration: %.2f seconds", time.Since(start).Seconds())
+	}()
+
+	client, err := artifactrepo.NewClient(s.spec.Type, s.spec.Address, s.spec.Repository, s.spec.Username, s.spec.Password, s.spec.Token)
+	if err != nil {
+		return fmt.Errorf("failed to create artifact repository client, err: %s", err)
+	}
+
+	envmaps := makeEnvMap(s.envs, s.secretEnvs)
+	localPath := replaceEnvWithValue(path.Join(s.workspace, s.spec.FilePath), envmaps)
+	destPath := path.Join(s.spec.DestDir, s.spec.FileName)
+	properties := make(map[string]string)
+	for key, value := range s.spec.Properties {
+		properties[key] = replaceEnvWithValue(value, envmaps)
+	}
+
+	log.Infof("Start uploading %s to %s.", localPath, client.FileURL(destPath))
+	return client.Upload(localPath, destPath, properties)
+}
@@ -20,9 +20,14 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -30,11 +35,22 @@ import (
 
 	"github.com/koderover/zadig/v2/pkg/setting"
 	"github.com/koderover/zadig/v2/pkg/tool/log"
+	"github.com/koderover/zadig/v2/pkg/types"
+	"github.com/koderover/zadig/v2/pkg/types/job"
 	"github.com/koderover/zadig/v2/pkg/types/step"
 	"github.com/koderover/zadig/v2/pkg/util/fs"
 )
 
-const dockerExe = "docker"
+const (
+	dockerExe = "docker"
+	// kanikoExe is the path of the executor in the kaniko images, the executor in PATH is used if it doesn't exist
+	kanikoExe = "/kaniko/executor"
+	// buildKitDaemonlessExe starts a rootless buildkitd for the build if BUILDKIT_HOST is not set
+	buildKitDaemonlessExe = "buildctl-daemonless.sh"
+	// buildxBuilderName is the buildx builder with the docker-container driver, the default docker driver
+	// can't build multi-platform images
+	buildxBuilderName = "zadig-multi-arch"
+)
 
 type DockerBuildStep struct {
 	spec       *step.StepDockerBuildSpec
@@ -67,12 +83,54 @@ func (s *DockerBuildStep) Run(ctx context.Context) error {
 	s.spec.DockerFile = replaceEnvWithValue(s.spec.DockerFile, envMap)
 	s.spec.BuildArgs = replaceEnvWithValue(s.spec.BuildArgs, envMap)
 
-	if err := s.dockerLogin(); err != nil {
-		return err
+	switch s.spec.GetBackend() {
+	case types.DockerBuildBackendKaniko, types.DockerBuildBackendBuildKit:
+		if err := s.writeRegistryAuth(); err != nil {
+			return err
+		}
+	default:
+		if err := s.dockerLogin(); err != nil {
+			return err
+		}
 	}
 	return s.runDockerBuild()
 }
 
+// dockerConfigDir is where the registry credential is saved for kaniko and buildkit since there is no docker login,
+// both of them read the credential from DOCKER_CONFIG.
+func (s *DockerBuildStep) dockerConfigDir() string {
+	return filepath.Join(os.TempDir(), "zadig-docker-config")
+}
+
+func (s *DockerBuildStep) writeRegistryAuth() error {
+	if s.spec.DockerRegistry == nil || s.spec.DockerRegistry.UserName == "" {
+		return nil
+	}
+
+	host := s.spec.DockerRegistry.Host
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(s.spec.DockerRegistry.UserName + ":" + s.spec.DockerRegistry.Password))
+	config, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			host: map[string]string{"auth": auth},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal docker config: %s", err)
+	}
+
+	if err := os.MkdirAll(s.dockerConfigDir(), 0700); err != nil {
+		return fmt.Errorf("failed to create docker config dir: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dockerConfigDir(), "config.json"), config, 0600); err != nil {
+		return fmt.Errorf("failed to write docker config: %s", err)
+	}
+	log.Infof("Registry credential of %s is prepared for %s.", host, s.spec.GetBackend())
+	return nil
+}
+
 func (s DockerBuildStep) dockerLogin() error {
 	if s.spec.DockerRegistry == nil {
 		return nil
@@ -132,9 +190,12 @@ func (s *DockerBuildStep) runDockerBuild() error {
 		setProxy(s.spec)
 	}
 
-	log.Infof("Running Docker Build.")
+	log.Infof("Running Docker Build with %s.", s.spec.GetBackend())
 	startTimeDockerBuild := time.Now()
 	envs := s.envs
+	if s.spec.GetBackend() != types.DockerBuildBackendDocker {
+		envs = append(envs, fmt.Sprintf("DOCKER_CONFIG=%s", s.dockerConfigDir()))
+	}
 	for _, c := range s.dockerCommands() {
 
 		cmdOutReader, err := c.StdoutPipe()
@@ -169,6 +230,13 @@ func (s *DockerBuildStep) runDockerBuild() error {
 	}
 	log.Infof("Docker build ended. Duration: %.2f seconds.", time.Since(startTimeDockerBuild).Seconds())
 
+	if s.spec.IsMultiArch() {
+		platforms := strings.Join(s.spec.Platforms, ",")
+		if err := os.WriteFile(filepath.Join(job.JobOutputDir, setting.BuildJobOutputKeyImagePlatforms), []byte(platforms), 0644); err != nil {
+			return fmt.Errorf("failed to write the platforms of the image to job output: %s", err)
+		}
+		log.Infof("Image %s is published for platforms: %s.", s.spec.ImageName, platforms)
+	}
 	return nil
 }
 
@@ -178,6 +246,25 @@ func (s *DockerBuildStep) dockerCommands() []*exec.Cmd {
 		s.spec.WorkDir = "."
 	}
 
+	switch s.spec.GetBackend() {
+	case types.DockerBuildBackendKaniko:
+		return append(cmds, kanikoBuildCmd(s.spec.GetDockerFile(), s.spec.ImageName, s.spec.WorkDir, s.spec.BuildArgs))
+	case types.DockerBuildBackendBuildKit:
+		_, useDaemon := makeEnvMap(s.envs, s.secretEnvs)["BUILDKIT_HOST"]
+		return append(cmds, buildKitBuildCmd(s.spec.GetDockerFile(), s.spec.ImageName, s.spec.WorkDir, s.spec.BuildArgs, s.spec.Platforms, s.spec.IgnoreCache, useDaemon))
+	}
+
+	if s.spec.IsMultiArch() {
+		if s.spec.GetMultiArchStrategy() == types.MultiArchStrategyManifest {
+			return s.manifestCommands()
+		}
+		return append(
+			cmds,
+			buildxCreateCmd(),
+			buildxBuildCmd(s.spec.GetDockerFile(), s.spec.ImageName, s.spec.WorkDir, s.spec.BuildArgs, s.spec.Platforms, s.spec.IgnoreCache),
+		)
+	}
+
 	cmds = append(
 		cmds,
 		dockerBuildCmd(
@@ -192,6 +279,53 @@ func (s *DockerBuildStep) dockerCommands() []*exec.Cmd {
 	return cmds
 }
 
+// manifestCommands builds and pushes the image of each platform, then assembles them into the manifest list of the image.
+func (s *DockerBuildStep) manifestCommands() []*exec.Cmd {
+	cmds := make([]*exec.Cmd, 0)
+	platformImages := make([]string, 0, len(s.spec.Platforms))
+	for _, platform := range s.spec.Platforms {
+		platformImage := platformImageName(s.spec.ImageName, platform)
+		platformImages = append(platformImages, platformImage)
+
+		buildCmd := dockerBuildCmd(s.spec.GetDockerFile(), platformImage, s.spec.WorkDir, s.spec.BuildArgs+" --platform "+platform, s.spec.IgnoreCache)
+		cmds = append(cmds, buildCmd, dockerPush(platformImage))
+	}
+
+	manifestCommand := fmt.Sprintf("docker manifest create --amend %s %s && docker manifest push --purge %s",
+		s.spec.ImageName, strings.Join(platformImages, " "), s.spec.ImageName)
+	return append(cmds, exec.Command("sh", "-c", manifestCommand))
+}
+
+// platformImageName returns the image of the platform by adding the architecture to the tag, e.g.
+// the image of linux/arm64/v8 for repo/app:v1 is repo/app:v1-arm64-v8.
+func platformImageName(image, platform string) string {
+	suffix := strings.ReplaceAll(strings.TrimPrefix(platform, "linux/"), "/", "-")
+	if strings.LastIndex(image, ":") > strings.LastIndex(image, "/") {
+		return image + "-" + suffix
+	}
+	return image + ":" + suffix
+}
+
+// buildxCreateCmd creates the builder for multi-platform build if it doesn't exist and uses it.
+func buildxCreateCmd() *exec.Cmd {
+	buildxCommand := fmt.Sprintf("docker buildx use %s 2>/dev/null || docker buildx create --name %s --driver docker-container --use",
+		buildxBuilderName, buildxBuilderName)
+	return exec.Command("sh", "-c", buildxCommand)
+}
+
+// buildxBuildCmd builds the image for all the platforms and pushes the manifest list in one command.
+func buildxBuildCmd(dockerfile, fullImage, ctx, buildArgs string, platforms []string, ignoreCache bool) *exec.Cmd {
+	buildxCommand := fmt.Sprintf("docker buildx build --platform %s --push", strings.Join(platforms, ","))
+	if ignoreCache {
+		buildxCommand += " --no-cache"
+	}
+	for _, val := range strings.Fields(buildArgs) {
+		buildxCommand = buildxCommand + " " + val
+	}
+	buildxCommand = buildxCommand + " -t " + fullImage + " -f " + dockerfile + " " + ctx
+	return exec.Command("sh", "-c", buildxCommand)
+}
+
 func dockerBuildCmd(dockerfile, fullImage, ctx, buildArgs string, ignoreCache bool) *exec.Cmd {
 	args := []string{"-c"}
 	dockerCommand := "docker build --rm=true"
@@ -212,6 +346,61 @@ func dockerBuildCmd(dockerfile, fullImage, ctx, buildArgs string, ignoreCache bo
 	return exec.Command("sh", args...)
 }
 
+// kanikoBuildCmd builds and pushes the image in one command, the docker build args are compatible with kaniko.
+func kanikoBuildCmd(dockerfile, fullImage, ctx, buildArgs string) *exec.Cmd {
+	executor := kanikoExe
+	if _, err := os.Stat(executor); err != nil {
+		executor = "executor"
+	}
+
+	kanikoCommand := fmt.Sprintf("%s --context %s --dockerfile %s --destination %s", executor, ctx, dockerfile, fullImage)
+	for _, val := range strings.Fields(buildArgs) {
+		kanikoCommand = kanikoCommand + " " + val
+	}
+	return exec.Command("sh", "-c", kanikoCommand)
+}
+
+// buildKitBuildCmd builds and pushes the image by buildctl, the buildkitd in BUILDKIT_HOST is used if useDaemon is true.
+// A manifest list is pushed if there are multiple platforms.
+func buildKitBuildCmd(dockerfile, fullImage, ctx, buildArgs string, platforms []string, ignoreCache, useDaemon bool) *exec.Cmd {
+	buildctl := buildKitDaemonlessExe
+	if useDaemon {
+		buildctl = "buildctl"
+	}
+
+	buildKitCommand := fmt.Sprintf("%s build --frontend dockerfile.v0 --local context=%s --local dockerfile=%s --opt filename=%s --output type=image,name=%s,push=true",
+		buildctl, ctx, filepath.Dir(dockerfile), filepath.Base(dockerfile), fullImage)
+	if ignoreCache {
+		buildKitCommand += " --no-cache"
+	}
+	if len(platforms) > 0 {
+		buildKitCommand += " --opt platform=" + strings.Join(platforms, ",")
+	}
+	for _, arg := range buildKitBuildArgs(buildArgs) {
+		buildKitCommand = buildKitCommand + " --opt build-arg:" + arg
+	}
+	return exec.Command("sh", "-c", buildKitCommand)
+}
+
+// buildKitBuildArgs extracts the KEY=VALUE of the --build-arg flags in the docker build args,
+// the other docker build flags are not supported by buildctl and are ignored.
+func buildKitBuildArgs(buildArgs string) []string {
+	resp := make([]string, 0)
+	fields := strings.Fields(buildArgs)
+	for i := 0; i < len(fields); i++ {
+		switch {
+		case fields[i] == "--build-arg" && i+1 < len(fields):
+			i++
+			resp = append(resp, fields[i])
+		case strings.HasPrefix(fields[i], "--build-arg="):
+			resp = append(resp, strings.TrimPrefix(fields[i], "--build-arg="))
+		default:
+			log.Warnf("docker build arg %s is not supported by buildkit, ignored", fields[i])
+		}
+	}
+	return resp
+}
+
 func dockerPush(fullImage string) *exec.Cmd {
 	args := []string{"-c"}
 	dockerPushCommand := "docker push " + fullImage
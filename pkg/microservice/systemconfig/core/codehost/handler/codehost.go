@@ -23,6 +23,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	commonutil "github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/util"
 	"github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/service"
@@ -101,7 +102,30 @@ func DeleteSystemCodeHost(c *gin.Context) {
 		ctx.Err = err
 		return
 	}
-	ctx.Err = service.DeleteCodeHost(id, ctx.Logger)
+	ctx.Err = service.DeleteCodeHost(id, c.Query("force") == "true", ctx.Logger)
+}
+
+func GetSystemCodeHostReferences(c *gin.Context) {
+	ctx, err := internalhandler.NewContextWithAuthorization(c)
+	defer func() { internalhandler.JSONResponse(c, ctx) }()
+
+	if err != nil {
+		ctx.Err = fmt.Errorf("authorization Info Generation failed: err %s", err)
+		ctx.UnAuthorized = true
+		return
+	}
+
+	if !ctx.Resources.IsSystemAdmin {
+		ctx.UnAuthorized = true
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		ctx.Err = err
+		return
+	}
+	ctx.Resp, ctx.Err = integration.GetCodehostReferences(id)
 }
 
 func GetSystemCodeHost(c *gin.Context) {
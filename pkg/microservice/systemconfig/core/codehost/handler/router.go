@@ -29,6 +29,7 @@ func (*Router) Inject(router *gin.RouterGroup) {
 		codehost.GET("", ListSystemCodeHost)
 		codehost.GET("/internal", ListCodeHostInternal)
 		codehost.DELETE("/:id", DeleteSystemCodeHost)
+		codehost.GET("/:id/references", GetSystemCodeHostReferences)
 		codehost.POST("", CreateSystemCodeHost)
 		codehost.PATCH("/:id", UpdateSystemCodeHost)
 		codehost.GET("/:id", GetSystemCodeHost)
@@ -29,6 +29,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/koderover/zadig/v2/pkg/config"
+	"github.com/koderover/zadig/v2/pkg/microservice/aslan/core/common/service/integration"
 	"github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/internal/oauth"
 	"github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/repository/models"
 	"github.com/koderover/zadig/v2/pkg/microservice/systemconfig/core/codehost/repository/mongodb"
@@ -130,7 +131,15 @@ func SystemList(encryptedKey, address, owner, source string, log *zap.SugaredLog
 	return EncypteCodeHost(encryptedKey, codeHosts, log)
 }
 
-func DeleteCodeHost(id int, _ *zap.SugaredLogger) error {
+func DeleteCodeHost(id int, force bool, log *zap.SugaredLogger) error {
+	references, err := integration.GetCodehostReferences(id)
+	if err != nil {
+		log.Errorf("failed to get references of codehost %d, error: %s", id, err)
+		return err
+	}
+	if err := references.CheckDeletion(force); err != nil {
+		return err
+	}
 	return mongodb.NewCodehostColl().DeleteSystemCodeHostByID(id)
 }
 
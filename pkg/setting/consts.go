@@ -316,6 +316,8 @@ const (
 	BuildOSSCacheFileName    = "zadig-build-cache.tar.gz"
 	ScanningOSSCacheFileName = "zadig-scanning-cache.tar.gz"
 	TestingOSSCacheFileName  = "zadig-testing-cache.tar.gz"
+
+	ScannerReportFileName = "zadig-scanner-report.json"
 )
 
 const (
@@ -478,6 +480,8 @@ const (
 	MeegoHookTaskCreator = "meego_hook"
 	// GeneralHookTaskCreator ...
 	GeneralHookTaskCreator = "general_hook"
+	// RegistryHookTaskCreator ...
+	RegistryHookTaskCreator = "registry_hook"
 	// CronTaskCreator ...
 	CronTaskCreator = "timer"
 	// DefaultTaskRevoker ...
@@ -811,9 +815,10 @@ const (
 
 // Project Management types
 const (
-	PMJira  = "jira"
-	PMLark  = "lark"
-	PMMeego = "meego"
+	PMJira       = "jira"
+	PMLark       = "lark"
+	PMMeego      = "meego"
+	PMServiceNow = "servicenow"
 )
 
 // Workflow variable source type
@@ -923,6 +928,8 @@ const (
 const (
 	WorkflowScanningJobOutputKey        = "SonarCETaskID"
 	WorkflowScanningJobOutputKeyProject = "SonarProjectKey"
+	// BuildJobOutputKeyImagePlatforms is the comma separated platforms of the multi-arch image published by the build job
+	BuildJobOutputKeyImagePlatforms = "IMAGE_PLATFORMS"
 )
 
 type NotifyWebHookType string
@@ -933,6 +940,7 @@ const (
 	NotifyWebHookTypeWechatWork NotifyWebHookType = "wechat"
 	NotifyWebHookTypeMail       NotifyWebHookType = "mail"
 	NotifyWebHookTypeWebook     NotifyWebHookType = "webhook"
+	NotifyWebHookTypeTelegram   NotifyWebHookType = "telegram"
 )
 
 const (
@@ -48,3 +48,37 @@ func (c *Client) DeleteAllProjectRoles(namespace string) error {
 	_, err := c.Post(url, httpclient.SetQueryParams(query))
 	return err
 }
+
+type RoleBinding struct {
+	BindingType string                `json:"binding_type"`
+	UserInfo    *RoleBindingUserInfo  `json:"user_info,omitempty"`
+	GroupInfo   *RoleBindingGroupInfo `json:"group_info,omitempty"`
+	Roles       []string              `json:"roles"`
+}
+
+type RoleBindingUserInfo struct {
+	UID      string `json:"uid"`
+	Account  string `json:"account"`
+	Username string `json:"username"`
+}
+
+type RoleBindingGroupInfo struct {
+	GID  string `json:"group_id"`
+	Name string `json:"name"`
+}
+
+func (c *Client) ListRoleBindings(namespace string) ([]*RoleBinding, error) {
+	url := "/policy/role-bindings"
+
+	query := map[string]string{
+		"namespace": namespace,
+	}
+
+	resp := make([]*RoleBinding, 0)
+
+	_, err := c.Get(url, httpclient.SetQueryParams(query), httpclient.SetResult(&resp))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
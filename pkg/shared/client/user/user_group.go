@@ -45,3 +45,15 @@ func (c *Client) GetUserGroupsByUid(uid string) (*types.ListUserGroupResp, error
 	_, err := c.Get(url, httpclient.SetQueryParams(queries), httpclient.SetResult(resp))
 	return resp, err
 }
+
+func (c *Client) ListUserGroups(pageNum, pageSize int) (*types.ListUserGroupResp, error) {
+	url := "/user-group"
+	resp := &types.ListUserGroupResp{}
+	queries := map[string]string{
+		"page_num":  fmt.Sprintf("%d", pageNum),
+		"page_size": fmt.Sprintf("%d", pageSize),
+	}
+
+	_, err := c.Get(url, httpclient.SetQueryParams(queries), httpclient.SetResult(resp))
+	return resp, err
+}
@@ -58,6 +58,16 @@ func (w *job) Complete() bool {
 	return false
 }
 
+func (w *job) Failed() bool {
+	for _, c := range w.Status.Conditions {
+		if c.Type == batchv1.JobFailed {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
 func (w *job) Resource() *resource.Job {
 	return &resource.Job{
 		Name:       w.Name,
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acr
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+const apiVersion = "2018-12-01"
+
+// Client calls the RPC style open api of Alibaba Cloud Container Registry Enterprise Edition
+type Client struct {
+	*req.Client
+	Region          string
+	AccessKeyID     string
+	AccessKeySecret string
+}
+
+func NewClient(region, accessKeyID, accessKeySecret string) *Client {
+	return &Client{
+		Client:          req.C().SetTimeout(30 * time.Second),
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		AccessKeySecret: accessKeySecret,
+	}
+}
+
+// AuthorizationToken is the temporary credential of docker login
+type AuthorizationToken struct {
+	TempUsername       string `json:"TempUsername"`
+	AuthorizationToken string `json:"AuthorizationToken"`
+	// ExpireTime is the unix timestamp in milliseconds
+	ExpireTime int64  `json:"ExpireTime"`
+	IsSuccess  bool   `json:"IsSuccess"`
+	Code       string `json:"Code"`
+	Message    string `json:"Message"`
+}
+
+// GetAuthorizationToken requests a temporary docker login credential of the enterprise instance
+func (c *Client) GetAuthorizationToken(instanceID string) (*AuthorizationToken, error) {
+	token := &AuthorizationToken{}
+	if err := c.call("GetAuthorizationToken", map[string]string{"InstanceId": instanceID}, token); err != nil {
+		return nil, err
+	}
+	if !token.IsSuccess {
+		return nil, errors.Errorf("failed to get authorization token, code: %s, message: %s", token.Code, token.Message)
+	}
+	return token, nil
+}
+
+func (c *Client) call(action string, params map[string]string, result interface{}) error {
+	query := map[string]string{
+		"Action":           action,
+		"Version":          apiVersion,
+		"Format":           "JSON",
+		"RegionId":         c.Region,
+		"AccessKeyId":      c.AccessKeyID,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   uuid.NewString(),
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+	query["Signature"] = sign("GET", query, c.AccessKeySecret)
+
+	values := url.Values{}
+	for k, v := range query {
+		values.Set(k, v)
+	}
+	resp, err := c.R().SetSuccessResult(result).Get(fmt.Sprintf("https://cr.%s.aliyuncs.com/?%s", c.Region, values.Encode()))
+	if err != nil {
+		return errors.Wrapf(err, "failed to call %s", action)
+	}
+	if !resp.IsSuccessState() {
+		return errors.Errorf("failed to call %s, status code: %d, body: %s", action, resp.GetStatusCode(), resp.String())
+	}
+	return nil
+}
+
+// sign computes the signature of the RPC style request, see
+// https://help.aliyun.com/document_detail/315526.html
+func sign(method string, query map[string]string, secret string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, percentEncode(k)+"="+percentEncode(query[k]))
+	}
+	stringToSign := method + "&" + percentEncode("/") + "&" + percentEncode(strings.Join(pairs, "&"))
+
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func percentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// the example in the signature document of the Alibaba Cloud RPC api
+	query := map[string]string{
+		"AccessKeyId":      "testid",
+		"Action":           "DescribeRegions",
+		"Format":           "XML",
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureNonce":   "3ee8c1b8-83d3-44af-a94f-4e0ad82fd6cf",
+		"SignatureVersion": "1.0",
+		"Timestamp":        "2016-02-23T12:46:24Z",
+		"Version":          "2014-05-26",
+	}
+	assert.Equal(t, "OLeaidS1JvxuMvnyHOwuJ+uX5qY=", sign("GET", query, "testsecret"))
+}
+
+func TestPercentEncode(t *testing.T) {
+	assert.Equal(t, "a%20b%2Ac~d%2F", percentEncode("a b*c~d/"))
+}
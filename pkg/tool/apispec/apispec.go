@@ -0,0 +1,505 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apispec generates OpenAPI 3.0 documents from the swagger 2.0 documents generated by swag
+// and the routes registered in gin, so that the routes without annotations are still documented.
+package apispec
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	Version = "3.0.3"
+
+	swaggerDefinitionPrefix = "#/definitions/"
+	schemaPrefix            = "#/components/schemas/"
+	bearerAuth              = "BearerAuth"
+)
+
+var (
+	ginParamRegexp  = regexp.MustCompile(`[:*]([^/]+)`)
+	pathParamRegexp = regexp.MustCompile(`\{[^/]+}`)
+)
+
+// Route is a route registered in the http server.
+type Route struct {
+	Method string
+	Path   string
+	// Handler is the full name of the handler function, for example: github.com/foo/handler.ListFoo
+	Handler string
+}
+
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       map[string]interface{}           `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components *Components                      `json:"components,omitempty"`
+	Security   []map[string][]string            `json:"security,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]interface{}     `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string                 `json:"name"`
+	In          string                 `json:"in"`
+	Description string                 `json:"description,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema interface{} `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type swaggerDocument struct {
+	Info        map[string]interface{}                  `json:"info"`
+	Consumes    []string                                `json:"consumes"`
+	Produces    []string                                `json:"produces"`
+	Paths       map[string]map[string]*swaggerOperation `json:"paths"`
+	Definitions map[string]interface{}                  `json:"definitions"`
+}
+
+type swaggerOperation struct {
+	Tags        []string                    `json:"tags"`
+	Summary     string                      `json:"summary"`
+	Description string                      `json:"description"`
+	OperationID string                      `json:"operationId"`
+	Consumes    []string                    `json:"consumes"`
+	Produces    []string                    `json:"produces"`
+	Parameters  []*swaggerParameter         `json:"parameters"`
+	Responses   map[string]*swaggerResponse `json:"responses"`
+}
+
+type swaggerParameter struct {
+	Name             string        `json:"name"`
+	In               string        `json:"in"`
+	Description      string        `json:"description"`
+	Required         bool          `json:"required"`
+	Type             string        `json:"type"`
+	Format           string        `json:"format"`
+	Items            interface{}   `json:"items"`
+	Enum             []interface{} `json:"enum"`
+	Default          interface{}   `json:"default"`
+	CollectionFormat string        `json:"collectionFormat"`
+	Schema           interface{}   `json:"schema"`
+}
+
+type swaggerResponse struct {
+	Description string      `json:"description"`
+	Schema      interface{} `json:"schema"`
+}
+
+// Generate builds an OpenAPI 3.0 document of the routes with the given path prefix. The operations are taken from
+// the swagger 2.0 document, an error is returned if any of the routes has no swag annotations.
+func Generate(swaggerDoc string, routes []Route, prefix, version string) (*Document, error) {
+	swagger := &swaggerDocument{}
+	if err := json.Unmarshal([]byte(swaggerDoc), swagger); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger document: %v", err)
+	}
+
+	// the path parameter names in the annotations may differ from the ones in the routes
+	annotatedPaths := make(map[string]string)
+	for path := range swagger.Paths {
+		annotatedPaths[normalizePath(path)] = path
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    map[string]interface{}{"version": version},
+		Paths:   make(map[string]map[string]*Operation),
+		Components: &Components{
+			Schemas: make(map[string]interface{}),
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer"},
+			},
+		},
+		Security: []map[string][]string{{bearerAuth: {}}},
+	}
+	for k, v := range swagger.Info {
+		if k != "version" {
+			doc.Info[k] = v
+		}
+	}
+
+	refs := make(map[string]struct{})
+	operationIDs := make(map[string]int)
+	undocumented := make([]string, 0)
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix) {
+			continue
+		}
+		method := strings.ToLower(route.Method)
+		path := ginParamRegexp.ReplaceAllString(route.Path, "{$1}")
+
+		var operation *Operation
+		if annotatedPath, ok := annotatedPaths[normalizePath(path)]; ok {
+			if swaggerOp, ok := swagger.Paths[annotatedPath][method]; ok {
+				path = annotatedPath
+				operation = convertOperation(swaggerOp, swagger, refs)
+			}
+		}
+		if operation == nil {
+			undocumented = append(undocumented, fmt.Sprintf("%s %s (%s)", route.Method, route.Path, handlerName(route.Handler)))
+			continue
+		}
+		// swag doesn't generate the operation id unless @ID is annotated, the handler name is used instead,
+		// the ids must be unique since they are used as the method names of the generated clients
+		if operation.OperationID == "" {
+			operation.OperationID = handlerName(route.Handler)
+		}
+		if operation.OperationID != "" {
+			operationIDs[operation.OperationID]++
+			if count := operationIDs[operation.OperationID]; count > 1 {
+				operation.OperationID = fmt.Sprintf("%s%d", operation.OperationID, count)
+			}
+		}
+
+		if _, ok := doc.Paths[path]; !ok {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+		doc.Paths[path][method] = operation
+	}
+	if len(undocumented) > 0 {
+		return nil, fmt.Errorf("routes without swag annotations: %s", strings.Join(undocumented, ", "))
+	}
+
+	// add the definitions referenced by the operations and the definitions themselves
+	pending := make([]string, 0, len(refs))
+	for name := range refs {
+		pending = append(pending, name)
+	}
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if _, ok := doc.Components.Schemas[name]; ok {
+			continue
+		}
+		definition, ok := swagger.Definitions[name]
+		if !ok {
+			continue
+		}
+		nested := make(map[string]struct{})
+		doc.Components.Schemas[name] = convertSchema(definition, nested)
+		for ref := range nested {
+			pending = append(pending, ref)
+		}
+	}
+
+	return doc, nil
+}
+
+func normalizePath(path string) string {
+	return pathParamRegexp.ReplaceAllString(path, "{}")
+}
+
+func convertOperation(swaggerOp *swaggerOperation, swagger *swaggerDocument, refs map[string]struct{}) *Operation {
+	consumes := swaggerOp.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+	if len(consumes) == 0 {
+		consumes = []string{"application/json"}
+	}
+	produces := swaggerOp.Produces
+	if len(produces) == 0 {
+		produces = swagger.Produces
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+
+	operation := &Operation{
+		Tags:        swaggerOp.Tags,
+		Summary:     swaggerOp.Summary,
+		Description: swaggerOp.Description,
+		OperationID: swaggerOp.OperationID,
+		Responses:   make(map[string]*Response),
+	}
+
+	var formSchema map[string]interface{}
+	for _, param := range swaggerOp.Parameters {
+		switch param.In {
+		case "body":
+			operation.RequestBody = &RequestBody{
+				Description: param.Description,
+				Required:    param.Required,
+				Content:     make(map[string]*MediaType),
+			}
+			schema := convertSchema(param.Schema, refs)
+			for _, mime := range consumes {
+				operation.RequestBody.Content[mime] = &MediaType{Schema: schema}
+			}
+		case "formData":
+			if formSchema == nil {
+				formSchema = map[string]interface{}{
+					"type":       "object",
+					"properties": make(map[string]interface{}),
+				}
+			}
+			formSchema["properties"].(map[string]interface{})[param.Name] = parameterSchema(param, refs)
+			if param.Required {
+				required, _ := formSchema["required"].([]string)
+				formSchema["required"] = append(required, param.Name)
+			}
+		default:
+			operation.Parameters = append(operation.Parameters, &Parameter{
+				Name:        param.Name,
+				In:          param.In,
+				Description: param.Description,
+				// path parameters are always required in OpenAPI 3.0
+				Required: param.Required || param.In == "path",
+				Schema:   parameterSchema(param, refs),
+			})
+		}
+	}
+	if formSchema != nil {
+		mime := "application/x-www-form-urlencoded"
+		for _, consume := range consumes {
+			if consume == "multipart/form-data" {
+				mime = consume
+			}
+		}
+		operation.RequestBody = &RequestBody{
+			Content: map[string]*MediaType{mime: {Schema: formSchema}},
+		}
+	}
+
+	for code, swaggerResp := range swaggerOp.Responses {
+		resp := &Response{Description: swaggerResp.Description}
+		if resp.Description == "" {
+			resp.Description = code
+		}
+		if swaggerResp.Schema != nil {
+			schema := convertSchema(swaggerResp.Schema, refs)
+			resp.Content = make(map[string]*MediaType)
+			for _, mime := range produces {
+				resp.Content[mime] = &MediaType{Schema: schema}
+			}
+		}
+		operation.Responses[code] = resp
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["200"] = &Response{Description: "OK"}
+	}
+	return operation
+}
+
+func parameterSchema(param *swaggerParameter, refs map[string]struct{}) map[string]interface{} {
+	schema := make(map[string]interface{})
+	if param.Type != "" {
+		schema["type"] = param.Type
+	}
+	if param.Format != "" {
+		schema["format"] = param.Format
+	}
+	if param.Items != nil {
+		schema["items"] = convertSchema(param.Items, refs)
+	}
+	if len(param.Enum) > 0 {
+		schema["enum"] = param.Enum
+	}
+	if param.Default != nil {
+		schema["default"] = param.Default
+	}
+	if param.Type == "file" {
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	}
+	if len(schema) == 0 {
+		schema["type"] = "string"
+	}
+	return schema
+}
+
+// convertSchema rewrites the swagger definition references to the OpenAPI 3.0 component references,
+// and records the referenced definition names in refs.
+func convertSchema(schema interface{}, refs map[string]struct{}) interface{} {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		resp := make(map[string]interface{}, len(s))
+		for k, v := range s {
+			if ref, ok := v.(string); ok && k == "$ref" && strings.HasPrefix(ref, swaggerDefinitionPrefix) {
+				name := strings.TrimPrefix(ref, swaggerDefinitionPrefix)
+				refs[name] = struct{}{}
+				resp[k] = schemaPrefix + name
+				continue
+			}
+			resp[k] = convertSchema(v, refs)
+		}
+		return resp
+	case []interface{}:
+		resp := make([]interface{}, 0, len(s))
+		for _, v := range s {
+			resp = append(resp, convertSchema(v, refs))
+		}
+		return resp
+	default:
+		return s
+	}
+}
+
+// handlerName returns the function name of the handler, for example: ListFoo for github.com/foo/handler.ListFoo.
+func handlerName(handler string) string {
+	if idx := strings.LastIndex(handler, "."); idx >= 0 {
+		return strings.TrimSuffix(handler[idx+1:], "-fm")
+	}
+	return handler
+}
+
+// Diff is the difference of the operations between two documents, the operations are in the form of "METHOD path".
+type Diff struct {
+	Base    string   `json:"base"`
+	Target  string   `json:"target"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// Compare returns the operations added, removed or changed in target compared with base.
+func Compare(base, target *Document) (*Diff, error) {
+	baseOps, err := operations(base)
+	if err != nil {
+		return nil, err
+	}
+	targetOps, err := operations(target)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{
+		Base:    documentVersion(base),
+		Target:  documentVersion(target),
+		Added:   make([]string, 0),
+		Removed: make([]string, 0),
+		Changed: make([]string, 0),
+	}
+	for key, op := range targetOps {
+		baseOp, ok := baseOps[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, key)
+		case baseOp != op:
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+	for key := range baseOps {
+		if _, ok := targetOps[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+// operations returns the serialized operations and the schemas they reference, so that the change of
+// a referenced schema is regarded as a change of the operation.
+func operations(doc *Document) (map[string]string, error) {
+	resp := make(map[string]string)
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			refs := make(map[string]struct{})
+			raw, err := json.Marshal(op)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal operation %s %s: %v", method, path, err)
+			}
+			var decoded interface{}
+			if err := json.Unmarshal(raw, &decoded); err != nil {
+				return nil, fmt.Errorf("failed to decode operation %s %s: %v", method, path, err)
+			}
+			collectRefs(decoded, doc, refs)
+
+			schemas := make(map[string]interface{}, len(refs))
+			if doc.Components != nil {
+				for name := range refs {
+					schemas[name] = doc.Components.Schemas[name]
+				}
+			}
+			content, err := json.Marshal(map[string]interface{}{"operation": decoded, "schemas": schemas})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal operation %s %s: %v", method, path, err)
+			}
+			resp[fmt.Sprintf("%s %s", strings.ToUpper(method), path)] = string(content)
+		}
+	}
+	return resp, nil
+}
+
+func collectRefs(schema interface{}, doc *Document, refs map[string]struct{}) {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		for k, v := range s {
+			if ref, ok := v.(string); ok && k == "$ref" && strings.HasPrefix(ref, schemaPrefix) {
+				name := strings.TrimPrefix(ref, schemaPrefix)
+				if _, ok := refs[name]; ok {
+					continue
+				}
+				refs[name] = struct{}{}
+				if doc.Components != nil {
+					collectRefs(doc.Components.Schemas[name], doc, refs)
+				}
+				continue
+			}
+			collectRefs(v, doc, refs)
+		}
+	case []interface{}:
+		for _, v := range s {
+			collectRefs(v, doc, refs)
+		}
+	}
+}
+
+func documentVersion(doc *Document) string {
+	if version, ok := doc.Info["version"].(string); ok {
+		return version
+	}
+	return ""
+}
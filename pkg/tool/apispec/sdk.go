@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apispec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	nonAlphanumericRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	// the regexps split the words in order: HTTPServer -> HTTP_Server, FooBar -> Foo_Bar, V4List -> V4_List,
+	// the digits followed by an upper case letter are kept together, for example: K8S
+	acronymBoundaryRegexp = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+	wordBoundaryRegexp    = regexp.MustCompile(`([a-z])([A-Z])`)
+	digitBoundaryRegexp   = regexp.MustCompile(`([0-9])([A-Z][a-z])`)
+	initialisms           = map[string]string{
+		"id": "ID", "ids": "IDs", "url": "URL", "api": "API", "http": "HTTP", "ip": "IP", "json": "JSON",
+		"yaml": "YAML", "uid": "UID", "cpu": "CPU", "sql": "SQL", "uri": "URI", "ssh": "SSH", "tls": "TLS",
+	}
+)
+
+// sdkOperation is an operation of the OpenAPI document prepared for the client generators.
+type sdkOperation struct {
+	// Name is the exported method name, it's converted to snake case for python
+	Name        string
+	Method      string
+	Path        string
+	Summary     string
+	PathParams  []string
+	QueryParams []*Parameter
+	HasBody     bool
+	BodySchema  interface{}
+	RespSchema  interface{}
+}
+
+// sdkOperations returns the operations sorted by path and method.
+func sdkOperations(doc *Document) []*sdkOperation {
+	resp := make([]*sdkOperation, 0)
+	names := make(map[string]int)
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		methods := make([]string, 0, len(doc.Paths[path]))
+		for method := range doc.Paths[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			op := doc.Paths[path][method]
+			sdkOp := &sdkOperation{
+				Name:    operationName(op.OperationID, method, path),
+				Method:  strings.ToUpper(method),
+				Path:    path,
+				Summary: strings.Join(strings.Fields(op.Summary), " "),
+			}
+			names[sdkOp.Name]++
+			if count := names[sdkOp.Name]; count > 1 {
+				sdkOp.Name = fmt.Sprintf("%s%d", sdkOp.Name, count)
+			}
+
+			for _, param := range op.Parameters {
+				if param.In == "query" {
+					sdkOp.QueryParams = append(sdkOp.QueryParams, param)
+				}
+			}
+			// the path parameters are taken from the path since the annotations may not declare all of them
+			for _, match := range pathParamRegexp.FindAllString(path, -1) {
+				sdkOp.PathParams = append(sdkOp.PathParams, strings.Trim(match, "{}"))
+			}
+
+			if op.RequestBody != nil {
+				sdkOp.HasBody = true
+				if media, ok := op.RequestBody.Content["application/json"]; ok {
+					sdkOp.BodySchema = media.Schema
+				}
+			}
+			for _, code := range []string{"200", "201"} {
+				if r, ok := op.Responses[code]; ok && r.Content != nil {
+					if media, ok := r.Content["application/json"]; ok {
+						sdkOp.RespSchema = media.Schema
+						break
+					}
+				}
+			}
+			resp = append(resp, sdkOp)
+		}
+	}
+	return resp
+}
+
+// operationName converts the operation id to an exported name, the OpenAPI prefix of the handler names is removed.
+func operationName(operationID, method, path string) string {
+	name := exportedName(operationID)
+	if trimmed := strings.TrimPrefix(name, "OpenAPI"); trimmed != name && trimmed != "" && unicode.IsUpper(rune(trimmed[0])) {
+		name = trimmed
+	}
+	if name == "" {
+		name = exportedName(method + " " + path)
+	}
+	return name
+}
+
+// exportedName converts the name to the exported Go identifier, for example: project_key -> ProjectKey.
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, word := range nonAlphanumericRegexp.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok && strings.ToLower(word) == word {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	resp := b.String()
+	if resp != "" && unicode.IsDigit(rune(resp[0])) {
+		resp = "F" + resp
+	}
+	return resp
+}
+
+// snakeName converts the name to snake case, for example: GetWorkflowV4List -> get_workflow_v4_list.
+func snakeName(name string) string {
+	name = strings.ReplaceAll(name, "IDs", "Ids")
+	name = acronymBoundaryRegexp.ReplaceAllString(name, "${1}_${2}")
+	name = wordBoundaryRegexp.ReplaceAllString(name, "${1}_${2}")
+	name = digitBoundaryRegexp.ReplaceAllString(name, "${1}_${2}")
+	name = nonAlphanumericRegexp.ReplaceAllString(name, "_")
+	return strings.Trim(strings.ToLower(name), "_")
+}
+
+// schemaName returns the name of the schema referenced by the $ref of the schema.
+func schemaName(schema interface{}) (string, bool) {
+	m, ok := schema.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	if ref, ok := m["$ref"].(string); ok && strings.HasPrefix(ref, schemaPrefix) {
+		return strings.TrimPrefix(ref, schemaPrefix), true
+	}
+	// swag wraps the referenced schema in allOf if the field has a description
+	if allOf, ok := m["allOf"].([]interface{}); ok && len(allOf) == 1 {
+		return schemaName(allOf[0])
+	}
+	return "", false
+}
+
+// typeNames maps the schema names to the type names, the package prefixes like "models." are removed
+// if the names are still unique.
+func typeNames(schemas map[string]interface{}) map[string]string {
+	resp := make(map[string]string, len(schemas))
+	// the types declared by the client itself
+	count := map[string]int{"Client": 1, "Error": 1}
+	for name := range schemas {
+		count[exportedName(shortSchemaName(name))]++
+	}
+	for name := range schemas {
+		short := exportedName(shortSchemaName(name))
+		if count[short] == 1 {
+			resp[name] = short
+		} else {
+			resp[name] = exportedName(name)
+		}
+	}
+	return resp
+}
+
+func shortSchemaName(name string) string {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	resp := make([]string, 0, len(m))
+	for k := range m {
+		resp = append(resp, k)
+	}
+	sort.Strings(resp)
+	return resp
+}
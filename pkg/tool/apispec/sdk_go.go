@@ -0,0 +1,309 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apispec
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const goClientTemplate = `// Code generated by apispec. DO NOT EDIT.
+
+// Package {{.Package}} is the client of the Zadig OpenAPI {{.Version}}.
+package {{.Package}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the Zadig OpenAPI with an API token.
+type Client struct {
+	// Address is the address of Zadig, for example: https://zadig.example.com
+	Address    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+func NewClient(address, token string) *Client {
+	return &Client{
+		Address:    strings.TrimSuffix(address, "/"),
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Error is returned if the status code of the response is not 2xx.
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("zadig openapi returns %d: %s", e.StatusCode, e.Body)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	u := c.Address + path
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &Error{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+{{range .Types}}
+{{.}}
+{{end}}
+{{range .Operations}}
+// {{.Name}}{{if .Summary}} {{.Summary}}{{end}}
+//
+// {{.Method}} {{.Path}}{{range .QueryParams}}
+//   - query {{.Name}}{{if .Required}} (required){{end}}{{if .Description}}: {{.Description}}{{end}}{{end}}
+func (c *Client) {{.Name}}(ctx context.Context{{range .PathArgs}}, {{.}} string{{end}}, query url.Values{{if .HasBody}}, body {{.BodyType}}{{end}}) ({{.RespType}}, error) {
+	var resp {{.RespType}}
+	err := c.do(ctx, "{{.Method}}", {{.PathExpr}}, query, {{if .HasBody}}body{{else}}nil{{end}}, &resp)
+	return resp, err
+}
+{{end}}`
+
+type goOperation struct {
+	*sdkOperation
+	PathArgs []string
+	PathExpr string
+	BodyType string
+	RespType string
+}
+
+type goGenerator struct {
+	schemas   map[string]interface{}
+	typeNames map[string]string
+}
+
+// GenerateGoClient generates the Go client of the operations in the document.
+func GenerateGoClient(doc *Document, pkg string) ([]byte, error) {
+	g := &goGenerator{schemas: map[string]interface{}{}}
+	if doc.Components != nil && doc.Components.Schemas != nil {
+		g.schemas = doc.Components.Schemas
+	}
+	g.typeNames = typeNames(g.schemas)
+
+	types := make([]string, 0, len(g.schemas))
+	for _, name := range sortedKeys(g.schemas) {
+		types = append(types, g.typeDecl(name, g.schemas[name]))
+	}
+
+	operations := make([]*goOperation, 0)
+	for _, op := range sdkOperations(doc) {
+		goOp := &goOperation{sdkOperation: op, BodyType: "interface{}", RespType: "json.RawMessage"}
+		args := make(map[string]string)
+		for _, param := range op.PathParams {
+			arg := goArgName(param)
+			args[param] = arg
+			goOp.PathArgs = append(goOp.PathArgs, arg)
+		}
+		goOp.PathExpr = goPathExpr(op.Path, args)
+		if op.BodySchema != nil {
+			goOp.BodyType = g.goType(op.BodySchema)
+		}
+		if op.RespSchema != nil {
+			goOp.RespType = g.goType(op.RespSchema)
+		}
+		operations = append(operations, goOp)
+	}
+
+	tmpl, err := template.New("client").Parse(goClientTemplate)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, map[string]interface{}{
+		"Package":    pkg,
+		"Version":    documentVersion(doc),
+		"Types":      types,
+		"Operations": operations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render go client: %v", err)
+	}
+	resp, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format go client: %v", err)
+	}
+	return resp, nil
+}
+
+func (g *goGenerator) typeDecl(name string, schema interface{}) string {
+	typeName := g.typeNames[name]
+	m, _ := schema.(map[string]interface{})
+	properties, _ := m["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return fmt.Sprintf("type %s %s", typeName, g.goValueType(schema))
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "type %s struct {\n", typeName)
+	fields := make(map[string]int)
+	for _, key := range sortedKeys(properties) {
+		field := exportedName(key)
+		if field == "" {
+			field = "Field"
+		}
+		fields[field]++
+		if fields[field] > 1 {
+			field = fmt.Sprintf("%s%d", field, fields[field])
+		}
+		if p, ok := properties[key].(map[string]interface{}); ok {
+			if desc, ok := p["description"].(string); ok && desc != "" {
+				fmt.Fprintf(b, "\t// %s\n", strings.Join(strings.Fields(desc), " "))
+			}
+		}
+		fmt.Fprintf(b, "\t%s %s `json:%s`\n", field, g.goType(properties[key]), strconv.Quote(key+",omitempty"))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// goType returns the Go type of the schema, the referenced structs are used as pointers.
+func (g *goGenerator) goType(schema interface{}) string {
+	if name, ok := schemaName(schema); ok {
+		if typeName, ok := g.typeNames[name]; ok {
+			m, _ := g.schemas[name].(map[string]interface{})
+			if properties, _ := m["properties"].(map[string]interface{}); len(properties) > 0 {
+				return "*" + typeName
+			}
+			return typeName
+		}
+		return "json.RawMessage"
+	}
+	return g.goValueType(schema)
+}
+
+func (g *goGenerator) goValueType(schema interface{}) string {
+	m, ok := schema.(map[string]interface{})
+	if !ok {
+		return "interface{}"
+	}
+	switch m["type"] {
+	case "string":
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(m["items"])
+	case "object":
+		if additional, ok := m["additionalProperties"].(map[string]interface{}); ok {
+			return "map[string]" + g.goType(additional)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+func goArgName(param string) string {
+	name := exportedName(param)
+	if name == "" {
+		return "param"
+	}
+	// lower the leading initialism as a whole, for example: ID -> id, URLPath -> urlPath
+	upper := 0
+	for upper < len(name) && unicode.IsUpper(rune(name[upper])) {
+		upper++
+	}
+	switch {
+	case upper == len(name):
+		name = strings.ToLower(name)
+	case upper > 1:
+		name = strings.ToLower(name[:upper-1]) + name[upper-1:]
+	default:
+		name = strings.ToLower(name[:1]) + name[1:]
+	}
+	switch {
+	case token.IsKeyword(name), name == "c", name == "ctx", name == "query", name == "body", name == "resp", name == "err":
+		return name + "Param"
+	}
+	return name
+}
+
+// goPathExpr returns the Go expression of the path with the parameters escaped.
+func goPathExpr(path string, args map[string]string) string {
+	parts := make([]string, 0)
+	last := 0
+	for _, loc := range pathParamRegexp.FindAllStringIndex(path, -1) {
+		if loc[0] > last {
+			parts = append(parts, strconv.Quote(path[last:loc[0]]))
+		}
+		parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", args[strings.Trim(path[loc[0]:loc[1]], "{}")]))
+		last = loc[1]
+	}
+	if last < len(path) {
+		parts = append(parts, strconv.Quote(path[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apispec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const pythonClientTemplate = `# Code generated by apispec. DO NOT EDIT.
+"""Client of the Zadig OpenAPI {{.Version}}."""
+
+from urllib.parse import quote
+
+import requests
+
+
+class ZadigError(Exception):
+    """Raised if the status code of the response is not 2xx."""
+
+    def __init__(self, status_code, body):
+        super().__init__("zadig openapi returns %d: %s" % (status_code, body))
+        self.status_code = status_code
+        self.body = body
+
+
+class Client:
+    """Calls the Zadig OpenAPI with an API token.
+
+    :param address: the address of Zadig, for example: https://zadig.example.com
+    :param token: the API token of the user
+    """
+
+    def __init__(self, address, token, session=None, timeout=60):
+        self.address = address.rstrip("/")
+        self.token = token
+        self.session = session or requests.Session()
+        self.timeout = timeout
+
+    def _request(self, method, path, query=None, body=None):
+        headers = {"Authorization": "Bearer " + self.token}
+        resp = self.session.request(
+            method,
+            self.address + path,
+            params=query,
+            json=body,
+            headers=headers,
+            timeout=self.timeout,
+        )
+        if resp.status_code < 200 or resp.status_code >= 300:
+            raise ZadigError(resp.status_code, resp.text)
+        if not resp.content:
+            return None
+        try:
+            return resp.json()
+        except ValueError:
+            return resp.text
+{{range .Operations}}
+    def {{.Name}}(self{{range .PathArgs}}, {{.}}{{end}}, query=None{{if .HasBody}}, body=None{{end}}):
+        """{{if .Summary}}{{.Summary}}{{else}}{{.Method}} {{.Path}}{{end}}
+
+        {{.Method}} {{.Path}}{{if .QueryParams}}
+
+        Query parameters:{{range .QueryParams}}
+            {{.Name}}{{if .Required}} (required){{end}}{{if .Description}}: {{doc .Description}}{{end}}{{end}}{{end}}
+        """
+        return self._request("{{.Method}}", {{.PathExpr}}, query{{if .HasBody}}, body{{end}})
+{{end}}`
+
+var pythonKeywords = map[string]struct{}{
+	"and": {}, "as": {}, "assert": {}, "async": {}, "await": {}, "break": {}, "class": {}, "continue": {},
+	"def": {}, "del": {}, "elif": {}, "else": {}, "except": {}, "finally": {}, "for": {}, "from": {},
+	"global": {}, "if": {}, "import": {}, "in": {}, "is": {}, "lambda": {}, "nonlocal": {}, "not": {},
+	"or": {}, "pass": {}, "raise": {}, "return": {}, "try": {}, "while": {}, "with": {}, "yield": {},
+	"self": {}, "query": {}, "body": {}, "None": {}, "True": {}, "False": {},
+}
+
+type pythonOperation struct {
+	*sdkOperation
+	PathArgs []string
+	PathExpr string
+}
+
+// GeneratePythonClient generates the python client of the operations in the document, the requests and
+// the responses are plain dicts.
+func GeneratePythonClient(doc *Document) ([]byte, error) {
+	operations := make([]*pythonOperation, 0)
+	for _, op := range sdkOperations(doc) {
+		pyOp := &pythonOperation{sdkOperation: op}
+		pyOp.Name = snakeName(op.Name)
+		pyOp.Summary = pythonDocString(op.Summary)
+
+		args := make([]string, 0, len(op.PathParams))
+		for _, param := range op.PathParams {
+			arg := snakeName(param)
+			if _, ok := pythonKeywords[arg]; ok || arg == "" {
+				arg = arg + "_"
+			}
+			pyOp.PathArgs = append(pyOp.PathArgs, arg)
+			args = append(args, fmt.Sprintf("quote(str(%s), safe=\"\")", arg))
+		}
+		format := pathParamRegexp.ReplaceAllString(strings.ReplaceAll(op.Path, "%", "%%"), "%s")
+		if len(args) == 0 {
+			pyOp.PathExpr = fmt.Sprintf("%q", format)
+		} else {
+			pyOp.PathExpr = fmt.Sprintf("%q %% (%s,)", format, strings.Join(args, ", "))
+		}
+		operations = append(operations, pyOp)
+	}
+
+	tmpl, err := template.New("client").Funcs(template.FuncMap{"doc": pythonDocString}).Parse(pythonClientTemplate)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, map[string]interface{}{
+		"Version":    documentVersion(doc),
+		"Operations": operations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render python client: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func pythonDocString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"""`, `\"\"\"`)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	SyncStatusSynced    = "Synced"
+	SyncStatusOutOfSync = "OutOfSync"
+
+	HealthStatusHealthy     = "Healthy"
+	HealthStatusProgressing = "Progressing"
+	HealthStatusDegraded    = "Degraded"
+	HealthStatusSuspended   = "Suspended"
+	HealthStatusMissing     = "Missing"
+	HealthStatusUnknown     = "Unknown"
+
+	OperationPhaseRunning     = "Running"
+	OperationPhaseTerminating = "Terminating"
+	OperationPhaseSucceeded   = "Succeeded"
+	OperationPhaseFailed      = "Failed"
+	OperationPhaseError       = "Error"
+)
+
+// Application only contains the fields of the argo cd application used by zadig
+type Application struct {
+	Metadata  ApplicationMeta   `json:"metadata"`
+	Operation *Operation        `json:"operation,omitempty"`
+	Status    ApplicationStatus `json:"status"`
+}
+
+type ApplicationMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type Operation struct {
+	Sync *SyncOperation `json:"sync,omitempty"`
+}
+
+type SyncOperation struct {
+	Revision string `json:"revision,omitempty"`
+	Prune    bool   `json:"prune,omitempty"`
+}
+
+type ApplicationStatus struct {
+	Sync           SyncStatus      `json:"sync"`
+	Health         HealthStatus    `json:"health"`
+	OperationState *OperationState `json:"operationState,omitempty"`
+	History        []*HistoryEntry `json:"history,omitempty"`
+}
+
+type SyncStatus struct {
+	Status   string `json:"status"`
+	Revision string `json:"revision"`
+}
+
+type HealthStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type OperationState struct {
+	Phase      string     `json:"phase"`
+	Message    string     `json:"message"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type HistoryEntry struct {
+	ID         int64     `json:"id"`
+	Revision   string    `json:"revision"`
+	DeployedAt time.Time `json:"deployedAt"`
+}
+
+// LatestHistoryID returns the id of the latest deployment of the application, 0 means it has never been deployed
+func (a *Application) LatestHistoryID() int64 {
+	var id int64
+	for _, history := range a.Status.History {
+		if history.ID > id {
+			id = history.ID
+		}
+	}
+	return id
+}
+
+type SyncStrategy struct {
+	Hook *SyncStrategyHook `json:"hook,omitempty"`
+}
+
+type SyncStrategyHook struct {
+	Force bool `json:"force,omitempty"`
+}
+
+type SyncOptions struct {
+	Items []string `json:"items,omitempty"`
+}
+
+type SyncRequest struct {
+	Revision    string        `json:"revision,omitempty"`
+	Prune       bool          `json:"prune"`
+	DryRun      bool          `json:"dryRun"`
+	Strategy    *SyncStrategy `json:"strategy,omitempty"`
+	SyncOptions *SyncOptions  `json:"syncOptions,omitempty"`
+}
+
+type RollbackRequest struct {
+	ID    int64 `json:"id"`
+	Prune bool  `json:"prune"`
+}
+
+// GetApplication gets the application with the refreshed status
+// api reference: https://argo-cd.readthedocs.io/en/stable/developer-guide/api-docs/
+func (c *Client) GetApplication(name string) (*Application, error) {
+	resp := new(Application)
+	_, err := c.R().SetSuccessResult(resp).Get(fmt.Sprintf("/api/v1/applications/%s", name))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SyncApplication triggers a sync operation of the application, an error is returned if another operation is in progress
+func (c *Client) SyncApplication(name string, args *SyncRequest) (*Application, error) {
+	resp := new(Application)
+	_, err := c.R().SetBody(args).SetSuccessResult(resp).Post(fmt.Sprintf("/api/v1/applications/%s/sync", name))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RollbackApplication rolls the application back to the deployment of the history id,
+// argo cd refuses to roll back applications with automated sync enabled.
+func (c *Client) RollbackApplication(name string, args *RollbackRequest) (*Application, error) {
+	resp := new(Application)
+	_, err := c.R().SetBody(args).SetSuccessResult(resp).Post(fmt.Sprintf("/api/v1/applications/%s/rollback", name))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
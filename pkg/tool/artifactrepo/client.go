@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package artifactrepo pushes files to and pulls files from nexus raw repositories and artifactory generic repositories.
+package artifactrepo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TypeNexus       = "nexus"
+	TypeArtifactory = "artifactory"
+)
+
+type Client struct {
+	Type       string
+	Address    string
+	Repository string
+	Username   string
+	Password   string
+	Token      string
+
+	httpClient *http.Client
+}
+
+func NewClient(repoType, address, repository, username, password, token string) (*Client, error) {
+	if repoType != TypeNexus && repoType != TypeArtifactory {
+		return nil, fmt.Errorf("unsupported artifact repository type: %s", repoType)
+	}
+	if address == "" || repository == "" {
+		return nil, errors.New("address and repository can not be empty")
+	}
+	return &Client{
+		Type:       repoType,
+		Address:    strings.TrimSuffix(address, "/"),
+		Repository: strings.Trim(repository, "/"),
+		Username:   username,
+		Password:   password,
+		Token:      token,
+		// uploads of large packages may take a long time, the timeout only limits the response header
+		httpClient: &http.Client{Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ResponseHeaderTimeout: 10 * time.Minute,
+		}},
+	}, nil
+}
+
+// FileURL returns the url of the file in the repository
+func (c *Client) FileURL(filePath string) string {
+	filePath = strings.TrimPrefix(filePath, "/")
+	if c.Type == TypeNexus {
+		return fmt.Sprintf("%s/repository/%s/%s", c.Address, c.Repository, filePath)
+	}
+	return fmt.Sprintf("%s/%s/%s", c.Address, c.Repository, filePath)
+}
+
+func (c *Client) newRequest(method, reqURL string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	} else if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("unexpected status code %d from %s, body: %s", resp.StatusCode, req.URL.Redacted(), string(body))
+	}
+	return resp, nil
+}
+
+// Upload pushes the local file to the path of the repository. The properties are attached to the file as matrix
+// params for artifactory, nexus raw repositories do not support metadata so they are ignored.
+func (c *Client) Upload(localPath, filePath string, properties map[string]string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	reqURL := c.FileURL(filePath)
+	if c.Type == TypeArtifactory && len(properties) > 0 {
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		matrix := ""
+		for _, key := range keys {
+			matrix += fmt.Sprintf(";%s=%s", url.PathEscape(key), url.PathEscape(properties[key]))
+		}
+		reqURL += matrix
+	}
+
+	req, err := c.newRequest(http.MethodPut, reqURL, file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload %s", localPath)
+	}
+	return resp.Body.Close()
+}
+
+// Download returns the content of the file in the repository, the caller should close it
+func (c *Client) Download(filePath string) (io.ReadCloser, int64, error) {
+	req, err := c.newRequest(http.MethodGet, c.FileURL(filePath), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to download %s", filePath)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// Validate checks the address, credentials and the existence of the repository
+func (c *Client) Validate() error {
+	reqURL := fmt.Sprintf("%s/api/repositories/%s", c.Address, c.Repository)
+	if c.Type == TypeNexus {
+		reqURL = fmt.Sprintf("%s/service/rest/v1/repositories", c.Address)
+	}
+	req, err := c.newRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if c.Type == TypeArtifactory {
+		return nil
+	}
+	repos := make([]struct {
+		Name string `json:"name"`
+	}, 0)
+	if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
+		return errors.Wrap(err, "failed to decode nexus repositories")
+	}
+	for _, repo := range repos {
+		if repo.Name == c.Repository {
+			return nil
+		}
+	}
+	return fmt.Errorf("repository %s not found", c.Repository)
+}
@@ -87,6 +87,10 @@ func (c *RedisCache) Exists(key string) (bool, error) {
 	}
 }
 
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.redisClient.Ping(ctx).Err()
+}
+
 func (c *RedisCache) GetString(key string) (string, error) {
 	return c.redisClient.Get(context.TODO(), key).Result()
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkmarx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+// clientID is the public client used by the checkmarx one cli to exchange api keys
+const clientID = "ast-app"
+
+type Client struct {
+	*req.Client
+	BaseURL string
+	AuthURL string
+	Tenant  string
+	APIKey  string
+}
+
+// NewClient creates a checkmarx one client, authURL is the IAM address and falls back to the server address
+func NewClient(url, authURL, tenant, apiKey string) *Client {
+	if authURL == "" {
+		authURL = url
+	}
+	return &Client{
+		Client: req.C().
+			SetBaseURL(url).
+			OnAfterResponse(func(client *req.Client, resp *req.Response) error {
+				if resp.Err != nil {
+					resp.Err = errors.Wrapf(resp.Err, "body: %s", resp.String())
+					return nil
+				}
+				if !resp.IsSuccessState() {
+					resp.Err = errors.Errorf("unexpected status code %d, body: %s", resp.GetStatusCode(), resp.String())
+					return nil
+				}
+				return nil
+			}),
+		BaseURL: url,
+		AuthURL: strings.TrimSuffix(authURL, "/"),
+		Tenant:  tenant,
+		APIKey:  apiKey,
+	}
+}
+
+type tokenResp struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (c *Client) GetAccessToken() (string, error) {
+	resp := new(tokenResp)
+	_, err := c.R().
+		SetFormData(map[string]string{
+			"grant_type":    "refresh_token",
+			"client_id":     clientID,
+			"refresh_token": c.APIKey,
+		}).
+		SetSuccessResult(resp).
+		Post(fmt.Sprintf("%s/auth/realms/%s/protocol/openid-connect/token", c.AuthURL, c.Tenant))
+	if err != nil {
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
+
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ListProjectsResp struct {
+	TotalCount int        `json:"totalCount"`
+	Projects   []*Project `json:"projects"`
+}
+
+func (c *Client) ListProjects(limit int) (*ListProjectsResp, error) {
+	token, err := c.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp := new(ListProjectsResp)
+	_, err = c.R().
+		SetBearerAuthToken(token).
+		SetQueryParam("limit", fmt.Sprintf("%d", limit)).
+		SetSuccessResult(resp).
+		Get("/api/projects")
+	return resp, err
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkmarx
+
+import "encoding/json"
+
+// Report is the json output of `cx scan create --report-format json`
+type Report struct {
+	ScanID     string    `json:"scanID"`
+	TotalCount int       `json:"totalCount"`
+	Results    []*Result `json:"results"`
+}
+
+type Result struct {
+	Type        string     `json:"type"`
+	ID          string     `json:"id"`
+	Severity    string     `json:"severity"`
+	Status      string     `json:"status"`
+	Description string     `json:"description"`
+	Data        ResultData `json:"data"`
+}
+
+type ResultData struct {
+	QueryName string `json:"queryName"`
+	// Filename and Line are set for kics results
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	// Nodes are set for sast results, the first node is where the flow starts
+	Nodes []*Node `json:"nodes"`
+}
+
+type Node struct {
+	FileName string `json:"fileName"`
+	Line     int    `json:"line"`
+}
+
+func ParseReport(data []byte) (*Report, error) {
+	report := new(Report)
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Location returns the file and line a result points to
+func (r *Result) Location() (string, int) {
+	if len(r.Data.Nodes) > 0 {
+		return r.Data.Nodes[0].FileName, r.Data.Nodes[0].Line
+	}
+	return r.Data.Filename, r.Data.Line
+}
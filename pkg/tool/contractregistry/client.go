@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contractregistry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+// Client is the client of a schema/contract registry which tells whether a provider version is compatible with the
+// consumers' contracts, e.g. a protobuf or OpenAPI contract store fronted by a compatibility api.
+type Client struct {
+	*req.Client
+	BaseURL string
+}
+
+func NewClient(url, token string, insecureSkipVerify bool) *Client {
+	client := req.C().
+		SetBaseURL(url).
+		SetCommonContentType("application/json").
+		OnAfterResponse(func(client *req.Client, resp *req.Response) error {
+			if resp.Err != nil {
+				resp.Err = errors.Wrapf(resp.Err, "body: %s", resp.String())
+				return nil
+			}
+			if !resp.IsSuccessState() {
+				resp.Err = errors.Errorf("unexpected status code %d, body: %s", resp.GetStatusCode(), resp.String())
+				return nil
+			}
+			return nil
+		})
+	if token != "" {
+		client.SetCommonBearerAuthToken(token)
+	}
+	if insecureSkipVerify {
+		client.EnableInsecureSkipVerify()
+	}
+	return &Client{
+		Client:  client,
+		BaseURL: url,
+	}
+}
+
+// Participant is a service version taking part in a contract
+type Participant struct {
+	Service string   `json:"service"`
+	Version string   `json:"version"`
+	Images  []string `json:"images"`
+}
+
+type CompatibilityRequest struct {
+	Project    string         `json:"project"`
+	Env        string         `json:"env"`
+	Production bool           `json:"production"`
+	Provider   *Participant   `json:"provider"`
+	Consumers  []*Participant `json:"consumers"`
+}
+
+type Incompatibility struct {
+	Consumer string `json:"consumer"`
+	Contract string `json:"contract"`
+	Message  string `json:"message"`
+}
+
+type CompatibilityResult struct {
+	Compatible        bool               `json:"compatible"`
+	Incompatibilities []*Incompatibility `json:"incompatibilities"`
+}
+
+// CheckCompatibility asks the registry whether the provider version is compatible with the consumer versions
+func (c *Client) CheckCompatibility(args *CompatibilityRequest) (*CompatibilityResult, error) {
+	resp := new(CompatibilityResult)
+	_, err := c.R().SetBody(args).SetSuccessResult(resp).Post("/api/v1/compatibility/check")
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Validate checks whether the registry is reachable with the token
+func (c *Client) Validate() error {
+	_, err := c.R().Get("/api/v1/health")
+	return err
+}
+
+// Report formats the incompatibilities one per line
+func (r *CompatibilityResult) Report() string {
+	if r.Compatible {
+		return "compatible"
+	}
+	lines := make([]string, 0, len(r.Incompatibilities))
+	for _, item := range r.Incompatibilities {
+		line := fmt.Sprintf("consumer %s", item.Consumer)
+		if item.Contract != "" {
+			line += fmt.Sprintf(" contract %s", item.Contract)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", line, item.Message))
+	}
+	if len(lines) == 0 {
+		return "incompatible, no details given by the registry"
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contractregistry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/compatibility/check" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		args := new(CompatibilityRequest)
+		if err := json.NewDecoder(r.Body).Decode(args); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := &CompatibilityResult{Compatible: len(args.Consumers) == 0}
+		for _, consumer := range args.Consumers {
+			resp.Incompatibilities = append(resp.Incompatibilities, &Incompatibility{
+				Consumer: consumer.Service,
+				Contract: "order.proto",
+				Message:  "field 3 removed",
+			})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", false)
+	result, err := client.CheckCompatibility(&CompatibilityRequest{
+		Provider:  &Participant{Service: "order", Version: "v2"},
+		Consumers: []*Participant{{Service: "cart", Version: "v1"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Compatible {
+		t.Fatalf("expected incompatible result")
+	}
+	if got, want := result.Report(), "consumer cart contract order.proto: field 3 removed"; got != want {
+		t.Errorf("unexpected report %q, want %q", got, want)
+	}
+
+	if _, err := NewClient(server.URL, "wrong", false).CheckCompatibility(&CompatibilityRequest{}); err == nil {
+		t.Errorf("expected error for rejected token")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2024 The KodeRover Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dragonfly
+
+import (
+	"fmt"
+	"strings"
+
+	ref "github.com/containers/image/docker/reference"
+)
+
+const dockerHubRegistry = "docker.io"
+
+// MirrorImage replaces the registry of the image with the dragonfly mirror configured for it.
+// mirrors is a map from registry address to the mirror address, images of other registries are returned as is.
+func MirrorImage(image string, mirrors map[string]string) string {
+	if len(mirrors) == 0 {
+		return image
+	}
+	named, err := ref.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	domain := ref.Domain(named)
+	mirror, ok := mirrors[domain]
+	if !ok && domain == dockerHubRegistry {
+		mirror, ok = mirrors["index.docker.io"]
+	}
+	if !ok || mirror == "" {
+		return image
+	}
+
+	mirrored := fmt.Sprintf("%s/%s", strings.TrimSuffix(mirror, "/"), ref.Path(named))
+	if tagged, ok := named.(ref.Tagged); ok {
+		mirrored = fmt.Sprintf("%s:%s", mirrored, tagged.Tag())
+	}
+	if digested, ok := named.(ref.Digested); ok {
+		mirrored = fmt.Sprintf("%s@%s", mirrored, digested.Digest())
+	}
+	return mirrored
+}
+
+// UnmirrorImage is the reverse of MirrorImage, it restores the image pulled through the dragonfly mirror to the
+// image of the original registry so it can be compared with the images saved in zadig. Images of docker hub are
+// returned in the familiar form, e.g. nginx:latest.
+func UnmirrorImage(image string, mirrors map[string]string) string {
+	for registry, mirror := range mirrors {
+		if mirror == "" {
+			continue
+		}
+		prefix := strings.TrimSuffix(mirror, "/") + "/"
+		if !strings.HasPrefix(image, prefix) {
+			continue
+		}
+
+		original := fmt.Sprintf("%s/%s", registry, strings.TrimPrefix(image, prefix))
+		if registry != dockerHubRegistry && registry != "index.docker.io" {
+			return original
+		}
+		named, err := ref.ParseNormalizedNamed(original)
+		if err != nil {
+			return original
+		}
+		return ref.FamiliarString(named)
+	}
+	return image
+}
+
+// ManifestURL returns the manifest url of the image which is used as the preheat url,
+// scheme is the scheme of the registry, https will be used if it is empty.
+func ManifestURL(image, scheme string) (string, error) {
+	named, err := ref.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse image %s, error: %s", image, err)
+	}
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	domain := ref.Domain(named)
+	if domain == dockerHubRegistry {
+		domain = "index.docker.io"
+	}
+
+	reference := "latest"
+	if tagged, ok := named.(ref.Tagged); ok {
+		reference = tagged.Tag()
+	}
+	if digested, ok := named.(ref.Digested); ok {
+		reference = digested.Digest().String()
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, domain, ref.Path(named), reference), nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2024 The KodeRover Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dragonfly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorImage(t *testing.T) {
+	mirrors := map[string]string{
+		"harbor.example.com": "127.0.0.1:65001",
+		"docker.io":          "127.0.0.1:65002/",
+	}
+
+	assert.Equal(t, "127.0.0.1:65001/zadig/aslan:1.0.0", MirrorImage("harbor.example.com/zadig/aslan:1.0.0", mirrors))
+	assert.Equal(t, "127.0.0.1:65002/library/nginx:latest", MirrorImage("nginx:latest", mirrors))
+	assert.Equal(t, "ccr.ccs.tencentyun.com/koderover/aslan:1.0.0", MirrorImage("ccr.ccs.tencentyun.com/koderover/aslan:1.0.0", mirrors))
+	assert.Equal(t, "harbor.example.com/zadig/aslan:1.0.0", MirrorImage("harbor.example.com/zadig/aslan:1.0.0", nil))
+}
+
+func TestUnmirrorImage(t *testing.T) {
+	mirrors := map[string]string{
+		"harbor.example.com": "127.0.0.1:65001",
+		"docker.io":          "127.0.0.1:65002/",
+	}
+
+	assert.Equal(t, "harbor.example.com/zadig/aslan:1.0.0", UnmirrorImage("127.0.0.1:65001/zadig/aslan:1.0.0", mirrors))
+	assert.Equal(t, "nginx:latest", UnmirrorImage("127.0.0.1:65002/library/nginx:latest", mirrors))
+	assert.Equal(t, "koderover/aslan:1.0.0", UnmirrorImage("127.0.0.1:65002/koderover/aslan:1.0.0", mirrors))
+	assert.Equal(t, "ccr.ccs.tencentyun.com/koderover/aslan:1.0.0", UnmirrorImage("ccr.ccs.tencentyun.com/koderover/aslan:1.0.0", mirrors))
+
+	for _, image := range []string{"harbor.example.com/zadig/aslan:1.0.0", "nginx:latest"} {
+		assert.Equal(t, image, UnmirrorImage(MirrorImage(image, mirrors), mirrors))
+	}
+}
+
+func TestManifestURL(t *testing.T) {
+	url, err := ManifestURL("nginx", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://index.docker.io/v2/library/nginx/manifests/latest", url)
+
+	url, err = ManifestURL("harbor.example.com/zadig/aslan:1.0.0", "http")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://harbor.example.com/v2/zadig/aslan/manifests/1.0.0", url)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 The KodeRover Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dragonfly
+
+import "fmt"
+
+const (
+	JobTypePreheat = "preheat"
+
+	PreheatTypeImage = "image"
+
+	PreheatScopeSingleSeedPeer = "single_seed_peer"
+	PreheatScopeAllSeedPeers   = "all_seed_peers"
+	PreheatScopeAllPeers       = "all_peers"
+
+	JobStatePending = "PENDING"
+	JobStateSuccess = "SUCCESS"
+	JobStateFailure = "FAILURE"
+)
+
+type PreheatArgs struct {
+	Type     string `json:"type"`
+	URL      string `json:"url"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+}
+
+type CreatePreheatJobRequest struct {
+	Type string       `json:"type"`
+	Args *PreheatArgs `json:"args"`
+}
+
+type Job struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	State     string `json:"state"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// CreatePreheatJob creates a preheat job in dragonfly manager
+// api reference: https://d7y.io/docs/next/advanced-guides/open-api/preheat/
+func (c *Client) CreatePreheatJob(args *PreheatArgs) (*Job, error) {
+	resp := new(Job)
+	_, err := c.R().SetBody(&CreatePreheatJobRequest{
+		Type: JobTypePreheat,
+		Args: args,
+	}).SetSuccessResult(resp).Post("/oapi/v1/jobs")
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) GetJob(id int64) (*Job, error) {
+	resp := new(Job)
+	_, err := c.R().SetSuccessResult(resp).Get(fmt.Sprintf("/oapi/v1/jobs/%d", id))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -29,6 +29,10 @@ var (
 	ErrForbidden = NewHTTPError(403, "Forbidden")
 	// ErrNotFound ...
 	ErrNotFound = NewHTTPError(404, "Request Not Found")
+	// ErrConflict ...
+	ErrConflict = NewHTTPError(409, "Conflict")
+	// ErrPreconditionFailed ...
+	ErrPreconditionFailed = NewHTTPError(412, "Precondition Failed")
 	// ErrInternalError ...
 	ErrInternalError = NewHTTPError(500, "Internal Error")
 
@@ -325,6 +329,8 @@ var (
 	ErrGetDebugShell = NewHTTPError(6172, "获取调试 Shell 失败")
 
 	ErrEnableDebug = NewHTTPError(6173, "开启工作流任务调试失败")
+
+	ErrResumeWorkflowStage = NewHTTPError(6174, "继续执行工作流任务失败")
 	//-----------------------------------------------------------------------------------------------
 	// Keystore APIs Range: 6180 - 6189
 	//-----------------------------------------------------------------------------------------------
@@ -628,6 +634,7 @@ var (
 	ErrCreateActivity       = NewHTTPError(6664, "添加交付事件失败")
 	ErrFindActivities       = NewHTTPError(6665, "获取交付事件列表失败")
 	ErrCreateArtifactFailed = NewHTTPError(6666, "该交付物已经存在")
+	ErrTraceImage           = NewHTTPError(6667, "追溯镜像来源失败")
 
 	//-----------------------------------------------------------------------------------------------
 	// basicImage APIs Range: 6670 - 6679
@@ -851,6 +858,15 @@ var (
 	ErrUpdateGeneralHook = NewHTTPError(6973, "更新 general hook 失败")
 	ErrDeleteGeneralHook = NewHTTPError(6974, "删除 general hook 失败")
 
+	//-----------------------------------------------------------------------------------------------
+	// registry hook releated Error Range: 6975 - 6979
+	//-----------------------------------------------------------------------------------------------
+	ErrListRegistryHook    = NewHTTPError(6975, "列出镜像仓库 hook 失败")
+	ErrCreateRegistryHook  = NewHTTPError(6976, "创建镜像仓库 hook 失败")
+	ErrUpdateRegistryHook  = NewHTTPError(6977, "更新镜像仓库 hook 失败")
+	ErrDeleteRegistryHook  = NewHTTPError(6978, "删除镜像仓库 hook 失败")
+	ErrTriggerRegistryHook = NewHTTPError(6979, "触发镜像仓库 hook 失败")
+
 	//-----------------------------------------------------------------------------------------------
 	// meego hook releated Error Range: 6980 - 6989
 	//-----------------------------------------------------------------------------------------------
@@ -883,6 +899,9 @@ var (
 	ErrUpdateLLMIntegration = NewHTTPError(7012, "更新llm集成失败")
 	ErrDeleteLLMIntegration = NewHTTPError(7013, "删除llm集成失败")
 	ErrGetLLMIntegration    = NewHTTPError(7014, "获取llm集成详情失败")
+	ErrAnalyzeWorkflowTask  = NewHTTPError(7015, "AI分析工作流任务失败")
+	ErrDraftReleaseNotes    = NewHTTPError(7016, "AI生成发布说明失败")
+	ErrAIQuery              = NewHTTPError(7017, "AI查询失败")
 
 	//-----------------------------------------------------------------------------------------------
 	// observability integration Error Range: 7020 - 7029
@@ -937,4 +956,330 @@ var (
 	ErrGetReleasePlanTemplate    = NewHTTPError(7073, "获取发布计划模板失败")
 	ErrDeleteReleasePlanTemplate = NewHTTPError(7074, "删除发布计划模板失败")
 	ErrLintReleasePlanTemplate   = NewHTTPError(7075, "检查发布计划模板失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// cert expiry monitor releated errors: 7080 - 7089
+	//-----------------------------------------------------------------------------------------------
+	ErrListCertExpiry          = NewHTTPError(7080, "获取证书过期信息失败")
+	ErrGetCertExpiryMonitor    = NewHTTPError(7081, "获取证书过期监控配置失败")
+	ErrUpdateCertExpiryMonitor = NewHTTPError(7082, "更新证书过期监控配置失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// hostname management releated errors: 7090 - 7099
+	//-----------------------------------------------------------------------------------------------
+	ErrGetHostnamePolicy    = NewHTTPError(7090, "获取域名分配规则失败")
+	ErrUpdateHostnamePolicy = NewHTTPError(7091, "更新域名分配规则失败")
+	ErrListHostnames        = NewHTTPError(7092, "列出项目域名失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// template impact releated errors: 7100 - 7109
+	//-----------------------------------------------------------------------------------------------
+	ErrGetTemplateImpact    = NewHTTPError(7100, "获取模板变更影响范围失败")
+	ErrNotifyTemplateImpact = NewHTTPError(7101, "发送模板变更影响通知失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// workflow task report releated errors: 7110 - 7119
+	//-----------------------------------------------------------------------------------------------
+	ErrGetTaskReport = NewHTTPError(7110, "生成工作流任务报告失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// saved dashboard releated errors: 7120 - 7129
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateDashboard  = NewHTTPError(7120, "创建仪表盘失败")
+	ErrUpdateDashboard  = NewHTTPError(7121, "更新仪表盘失败")
+	ErrDeleteDashboard  = NewHTTPError(7122, "删除仪表盘失败")
+	ErrGetDashboard     = NewHTTPError(7123, "获取仪表盘失败")
+	ErrListDashboards   = NewHTTPError(7124, "列出仪表盘失败")
+	ErrGetDashboardData = NewHTTPError(7125, "获取仪表盘数据失败")
+	ErrShareDashboard   = NewHTTPError(7126, "分享仪表盘失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// env snapshot releated errors: 7130 - 7139
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateEnvSnapshot  = NewHTTPError(7130, "创建环境快照失败")
+	ErrListEnvSnapshots   = NewHTTPError(7131, "列出环境快照失败")
+	ErrDeleteEnvSnapshot  = NewHTTPError(7132, "删除环境快照失败")
+	ErrRestoreEnvSnapshot = NewHTTPError(7133, "恢复环境快照失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// favorite and recent item releated errors: 7140 - 7149
+	//-----------------------------------------------------------------------------------------------
+	ErrListFavorites    = NewHTTPError(7140, "列出收藏失败")
+	ErrListRecentItems  = NewHTTPError(7141, "列出最近访问失败")
+	ErrRecordRecentItem = NewHTTPError(7142, "记录最近访问失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// workflow job reference releated errors: 7150 - 7159
+	//-----------------------------------------------------------------------------------------------
+	ErrCheckJobReferences  = NewHTTPError(7150, "检查任务引用失败")
+	ErrRepairJobReferences = NewHTTPError(7151, "修复任务引用失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// workflow variable explain releated errors: 7160 - 7169
+	//-----------------------------------------------------------------------------------------------
+	ErrExplainWorkflowVariables = NewHTTPError(7160, "解析工作流变量失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// env rollback releated errors: 7170 - 7179
+	//-----------------------------------------------------------------------------------------------
+	ErrRollbackEnvServices    = NewHTTPError(7170, "回滚环境服务失败")
+	ErrListEnvRollbackRecords = NewHTTPError(7171, "获取环境回滚记录失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// statefulSet rollout releated errors: 7180 - 7189
+	//-----------------------------------------------------------------------------------------------
+	ErrUpdateStatefulSetRollout = NewHTTPError(7180, "更新 StatefulSet 发布策略失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// build cache releated errors: 7190 - 7199
+	//-----------------------------------------------------------------------------------------------
+	ErrListBuildCache  = NewHTTPError(7190, "获取构建缓存列表失败")
+	ErrPruneBuildCache = NewHTTPError(7191, "清理构建缓存失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// first deploy hook releated errors: 7200 - 7209
+	//-----------------------------------------------------------------------------------------------
+	ErrListFirstDeployHookRecords = NewHTTPError(7200, "获取服务首次部署钩子记录失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// openapi spec releated errors: 7210 - 7219
+	//-----------------------------------------------------------------------------------------------
+	ErrGetOpenAPISpec  = NewHTTPError(7210, "获取 OpenAPI 文档失败")
+	ErrDiffOpenAPISpec = NewHTTPError(7211, "对比 OpenAPI 文档失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// resource api releated errors: 7220 - 7229
+	//-----------------------------------------------------------------------------------------------
+	ErrGetResource = NewHTTPError(7220, "获取资源失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// label releated errors: 7230 - 7239
+	//-----------------------------------------------------------------------------------------------
+	ErrInvalidLabel    = NewHTTPError(7230, "标签不符合规则")
+	ErrListLabels      = NewHTTPError(7231, "获取标签失败")
+	ErrListLabelRules  = NewHTTPError(7232, "获取标签规则失败")
+	ErrUpdateLabelRule = NewHTTPError(7233, "更新标签规则失败")
+	ErrDeleteLabelRule = NewHTTPError(7234, "删除标签规则失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// resource owner releated errors: 7240 - 7249
+	//-----------------------------------------------------------------------------------------------
+	ErrInvalidOwner        = NewHTTPError(7240, "负责人无效")
+	ErrListStaleOwners     = NewHTTPError(7241, "获取负责人失效的资源失败")
+	ErrUpdateResourceOwner = NewHTTPError(7242, "更新资源负责人失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// user offboarding releated errors: 7250 - 7259
+	//-----------------------------------------------------------------------------------------------
+	ErrGetOffboardingReport       = NewHTTPError(7250, "获取用户交接信息失败")
+	ErrTransferUserResources      = NewHTTPError(7251, "交接用户资源失败")
+	ErrListUserOffboardingRecords = NewHTTPError(7252, "获取用户交接记录失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// scanner integration releated errors: 7260 - 7269
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateScannerIntegration   = NewHTTPError(7260, "创建 代码扫描工具 集成失败")
+	ErrListScannerIntegration     = NewHTTPError(7261, "获取 代码扫描工具 集成列表失败")
+	ErrUpdateScannerIntegration   = NewHTTPError(7262, "更新 代码扫描工具 集成失败")
+	ErrDeleteScannerIntegration   = NewHTTPError(7263, "删除 代码扫描工具 集成失败")
+	ErrValidateScannerIntegration = NewHTTPError(7264, "代码扫描工具 集成校验失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// approval record releated errors: 7270 - 7279
+	//-----------------------------------------------------------------------------------------------
+	ErrListApprovalRecords   = NewHTTPError(7270, "获取审批记录失败")
+	ErrExportApprovalRecords = NewHTTPError(7271, "导出审批记录失败")
+	ErrVerifyApprovalRecords = NewHTTPError(7272, "校验审批记录失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// env ttl releated errors: 7280 - 7289
+	//-----------------------------------------------------------------------------------------------
+	ErrUpdateEnvTTL           = NewHTTPError(7280, "更新环境有效期失败")
+	ErrRequestEnvTTLExtension = NewHTTPError(7281, "申请环境延期失败")
+	ErrReviewEnvTTLExtension  = NewHTTPError(7282, "审批环境延期失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// integration deletion releated errors: 7290 - 7299
+	//-----------------------------------------------------------------------------------------------
+	ErrIntegrationInUse         = NewHTTPError(7290, "集成正在被使用, 无法删除")
+	ErrGetIntegrationReferences = NewHTTPError(7291, "获取集成引用信息失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// sandbox project releated errors: 7300 - 7309
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateSandboxProject = NewHTTPError(7300, "创建沙箱项目失败")
+	ErrSandboxExpired       = NewHTTPError(7301, "沙箱项目已过期")
+	ErrSandboxQuotaExceeded = NewHTTPError(7302, "沙箱项目配额不足")
+	ErrCleanSandboxProjects = NewHTTPError(7303, "清理过期沙箱项目失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// env drift detection releated errors: 7310 - 7319
+	//-----------------------------------------------------------------------------------------------
+	ErrDetectEnvDrift        = NewHTTPError(7310, "检测环境配置漂移失败")
+	ErrListEnvDriftRecords   = NewHTTPError(7311, "获取环境配置漂移记录失败")
+	ErrGetEnvDriftMonitor    = NewHTTPError(7312, "获取环境配置漂移检测配置失败")
+	ErrUpdateEnvDriftMonitor = NewHTTPError(7313, "更新环境配置漂移检测配置失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// env resource quota releated errors: 7320 - 7329
+	//-----------------------------------------------------------------------------------------------
+	ErrGetEnvQuota    = NewHTTPError(7320, "获取环境资源配额失败")
+	ErrUpdateEnvQuota = NewHTTPError(7321, "更新环境资源配额失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// env cost releated errors: 7330 - 7339
+	//-----------------------------------------------------------------------------------------------
+	ErrGetEnvCostReport   = NewHTTPError(7330, "获取环境成本报表失败")
+	ErrGetEnvCostPrice    = NewHTTPError(7331, "获取环境成本单价失败")
+	ErrUpdateEnvCostPrice = NewHTTPError(7332, "更新环境成本单价失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// helm release history releated errors: 7340 - 7349
+	//-----------------------------------------------------------------------------------------------
+	ErrListHelmReleaseHistory = NewHTTPError(7340, "获取helm release历史版本失败")
+	ErrRollbackHelmRelease    = NewHTTPError(7341, "回滚helm release失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// mongo index audit releated errors: 7350 - 7359
+	//-----------------------------------------------------------------------------------------------
+	ErrAuditMongoIndex = NewHTTPError(7350, "检查数据库索引失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// workflow task cold storage releated errors: 7360 - 7369
+	//-----------------------------------------------------------------------------------------------
+	ErrListColdWorkflowTask  = NewHTTPError(7360, "获取冷存储工作流任务失败")
+	ErrRehydrateWorkflowTask = NewHTTPError(7361, "恢复冷存储工作流任务失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// helm values schema releated errors: 7370 - 7379
+	//-----------------------------------------------------------------------------------------------
+	ErrHelmValuesSchemaViolation = NewHTTPError(7370, "helm values 不符合 values schema")
+	ErrGetHelmValuesSchema       = NewHTTPError(7371, "获取helm values schema失败")
+	ErrUpdateHelmValuesSchema    = NewHTTPError(7372, "更新helm values schema失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// secret provider releated errors: 7380 - 7389
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateSecretProvider   = NewHTTPError(7380, "创建 密钥管理 集成失败")
+	ErrListSecretProvider     = NewHTTPError(7381, "获取 密钥管理 集成列表失败")
+	ErrUpdateSecretProvider   = NewHTTPError(7382, "更新 密钥管理 集成失败")
+	ErrDeleteSecretProvider   = NewHTTPError(7383, "删除 密钥管理 集成失败")
+	ErrValidateSecretProvider = NewHTTPError(7384, "密钥管理 集成校验失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// sops releated errors: 7390 - 7399
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateSopsKey = NewHTTPError(7390, "创建 sops 密钥失败")
+	ErrListSopsKey   = NewHTTPError(7391, "获取 sops 密钥列表失败")
+	ErrUpdateSopsKey = NewHTTPError(7392, "更新 sops 密钥失败")
+	ErrDeleteSopsKey = NewHTTPError(7393, "删除 sops 密钥失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// dockerfile template releated errors: 7400 - 7409
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateDockerfileTemplate       = NewHTTPError(7400, "创建 Dockerfile 模板失败")
+	ErrUpdateDockerfileTemplate       = NewHTTPError(7401, "更新 Dockerfile 模板失败")
+	ErrListDockerfileTemplateVersion  = NewHTTPError(7402, "获取 Dockerfile 模板版本列表失败")
+	ErrDiffDockerfileTemplateVersions = NewHTTPError(7403, "对比 Dockerfile 模板版本失败")
+	ErrLintDockerfileTemplate         = NewHTTPError(7404, "Dockerfile 模板检查失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// artifact repository releated errors: 7410 - 7419
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateArtifactRepository   = NewHTTPError(7410, "创建 制品仓库 集成失败")
+	ErrListArtifactRepository     = NewHTTPError(7411, "获取 制品仓库 集成列表失败")
+	ErrUpdateArtifactRepository   = NewHTTPError(7412, "更新 制品仓库 集成失败")
+	ErrDeleteArtifactRepository   = NewHTTPError(7413, "删除 制品仓库 集成失败")
+	ErrValidateArtifactRepository = NewHTTPError(7414, "制品仓库 集成校验失败")
+	ErrListBuildArtifact          = NewHTTPError(7415, "获取 构建制品列表失败")
+	ErrDownloadBuildArtifact      = NewHTTPError(7416, "下载 构建制品失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// argo cd releated errors: 7420 - 7429
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateArgoCD   = NewHTTPError(7420, "创建 Argo CD 集成失败")
+	ErrListArgoCD     = NewHTTPError(7421, "获取 Argo CD 集成列表失败")
+	ErrUpdateArgoCD   = NewHTTPError(7422, "更新 Argo CD 集成失败")
+	ErrDeleteArgoCD   = NewHTTPError(7423, "删除 Argo CD 集成失败")
+	ErrValidateArgoCD = NewHTTPError(7424, "Argo CD 集成校验失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// job output releated errors: 7430 - 7439
+	//-----------------------------------------------------------------------------------------------
+	ErrListJobOutputs = NewHTTPError(7430, "获取任务输出变量失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// deploy ledger releated errors: 7440 - 7449
+	//-----------------------------------------------------------------------------------------------
+	ErrListDeployLedger   = NewHTTPError(7440, "获取环境部署记录失败")
+	ErrExportDeployLedger = NewHTTPError(7441, "导出环境部署记录失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// html report releated errors: 7450 - 7459
+	//-----------------------------------------------------------------------------------------------
+	ErrListHTMLReports   = NewHTTPError(7450, "获取 HTML 报告列表失败")
+	ErrGetHTMLReportFile = NewHTTPError(7451, "获取 HTML 报告文件失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// image retention policy releated errors: 7460 - 7469
+	//-----------------------------------------------------------------------------------------------
+	ErrListImageRetentionPolicy   = NewHTTPError(7460, "获取镜像清理策略列表失败")
+	ErrGetImageRetentionPolicy    = NewHTTPError(7461, "获取镜像清理策略失败")
+	ErrCreateImageRetentionPolicy = NewHTTPError(7462, "创建镜像清理策略失败")
+	ErrUpdateImageRetentionPolicy = NewHTTPError(7463, "更新镜像清理策略失败")
+	ErrDeleteImageRetentionPolicy = NewHTTPError(7464, "删除镜像清理策略失败")
+	ErrPreviewImageRetention      = NewHTTPError(7465, "预览镜像清理结果失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// registry credential releated errors: 7470 - 7479
+	//-----------------------------------------------------------------------------------------------
+	ErrCheckRegistryCredential = NewHTTPError(7470, "检查镜像仓库凭证失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// project resource usage releated errors: 7480 - 7489
+	//-----------------------------------------------------------------------------------------------
+	ErrGetProjectResourceUsage    = NewHTTPError(7480, "获取项目资源用量失败")
+	ErrExportProjectResourceUsage = NewHTTPError(7481, "导出项目资源用量失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// project service account releated errors: 7490 - 7499
+	//-----------------------------------------------------------------------------------------------
+	ErrListServiceAccount   = NewHTTPError(7490, "获取服务账号列表失败")
+	ErrCreateServiceAccount = NewHTTPError(7491, "创建服务账号失败")
+	ErrUpdateServiceAccount = NewHTTPError(7492, "更新服务账号失败")
+	ErrDeleteServiceAccount = NewHTTPError(7493, "删除服务账号失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// contract registry releated errors: 7500 - 7509
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateContractRegistry   = NewHTTPError(7500, "创建契约注册中心集成失败")
+	ErrListContractRegistry     = NewHTTPError(7501, "获取契约注册中心集成列表失败")
+	ErrUpdateContractRegistry   = NewHTTPError(7502, "更新契约注册中心集成失败")
+	ErrDeleteContractRegistry   = NewHTTPError(7503, "删除契约注册中心集成失败")
+	ErrValidateContractRegistry = NewHTTPError(7504, "契约注册中心集成校验失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// project bundle releated errors: 7510 - 7519
+	//-----------------------------------------------------------------------------------------------
+	ErrExportProjectBundle = NewHTTPError(7510, "导出项目模板失败")
+	ErrImportProjectBundle = NewHTTPError(7511, "导入项目模板失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// approval delegation releated errors: 7520 - 7529
+	//-----------------------------------------------------------------------------------------------
+	ErrCreateApprovalDelegation = NewHTTPError(7520, "创建审批委托失败")
+	ErrListApprovalDelegations  = NewHTTPError(7521, "获取审批委托列表失败")
+	ErrDeleteApprovalDelegation = NewHTTPError(7522, "删除审批委托失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// unmanaged resources releated errors: 7530 - 7539
+	//-----------------------------------------------------------------------------------------------
+	ErrListUnmanagedResources  = NewHTTPError(7530, "获取环境外部资源失败")
+	ErrAdoptUnmanagedResources = NewHTTPError(7531, "纳管环境外部资源失败")
+
+	//-----------------------------------------------------------------------------------------------
+	// helm release import releated errors: 7540 - 7549
+	//-----------------------------------------------------------------------------------------------
+	ErrListImportableHelmReleases = NewHTTPError(7540, "获取可导入的 Helm Release 失败")
+	ErrImportHelmReleases         = NewHTTPError(7541, "导入 Helm Release 失败")
 )
@@ -15,6 +15,7 @@ package github
 
 import (
 	"context"
+	"sort"
 
 	"github.com/google/go-github/v35/github"
 )
@@ -27,3 +28,62 @@ func (c *Client) GetTree(ctx context.Context, owner string, repo string, sha str
 
 	return nil, err
 }
+
+// CommitFiles commits the files to the branch in a single commit and returns the sha of the commit,
+// the branch is created from the base branch if it does not exist.
+func (c *Client) CommitFiles(ctx context.Context, owner, repo, branch, baseBranch, message string, files map[string]string) (string, error) {
+	ref, res, err := c.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+	if err = wrapError(res, err); err != nil {
+		baseRef, res, err := c.Git.GetRef(ctx, owner, repo, "refs/heads/"+baseBranch)
+		if err = wrapError(res, err); err != nil {
+			return "", err
+		}
+		ref, res, err = c.Git.CreateRef(ctx, owner, repo, &github.Reference{
+			Ref:    github.String("refs/heads/" + branch),
+			Object: &github.GitObject{SHA: baseRef.Object.SHA},
+		})
+		if err = wrapError(res, err); err != nil {
+			return "", err
+		}
+	}
+
+	parent, res, err := c.Git.GetCommit(ctx, owner, repo, ref.Object.GetSHA())
+	if err = wrapError(res, err); err != nil {
+		return "", err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	entries := make([]*github.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entries = append(entries, &github.TreeEntry{
+			Path:    github.String(path),
+			Mode:    github.String("100644"),
+			Type:    github.String("blob"),
+			Content: github.String(files[path]),
+		})
+	}
+	tree, res, err := c.Git.CreateTree(ctx, owner, repo, parent.GetTree().GetSHA(), entries)
+	if err = wrapError(res, err); err != nil {
+		return "", err
+	}
+
+	commit, res, err := c.Git.CreateCommit(ctx, owner, repo, &github.Commit{
+		Message: github.String(message),
+		Tree:    tree,
+		Parents: []*github.Commit{parent},
+	})
+	if err = wrapError(res, err); err != nil {
+		return "", err
+	}
+
+	ref.Object.SHA = commit.SHA
+	_, res, err = c.Git.UpdateRef(ctx, owner, repo, ref, false)
+	if err = wrapError(res, err); err != nil {
+		return "", err
+	}
+	return commit.GetSHA(), nil
+}
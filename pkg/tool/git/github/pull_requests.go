@@ -33,6 +33,20 @@ func (c *Client) GetPullRequest(ctx context.Context, owner string, repo string,
 	return nil, err
 }
 
+func (c *Client) CreatePullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*github.PullRequest, error) {
+	pr, err := wrap(c.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.String(title),
+		Body:  github.String(body),
+		Head:  github.String(head),
+		Base:  github.String(base),
+	}))
+	if p, ok := pr.(*github.PullRequest); ok {
+		return p, err
+	}
+
+	return nil, err
+}
+
 func (c *Client) ListPullRequests(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error) {
 	prs, err := wrap(c.PullRequests.List(ctx, owner, repo, opts))
 	if p, ok := prs.([]*github.PullRequest); ok {
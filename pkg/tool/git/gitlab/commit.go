@@ -17,6 +17,8 @@ limitations under the License.
 package gitlab
 
 import (
+	"sort"
+
 	"github.com/xanzy/go-gitlab"
 )
 
@@ -79,3 +81,45 @@ func (c *Client) ListCommits(owner, repo, branch string, opts *ListOptions) ([]*
 
 	return cs, nil
 }
+
+// CommitFiles commits the files to the branch in a single commit,
+// the branch is created from the start branch if it does not exist.
+func (c *Client) CommitFiles(owner, repo, branch, startBranch, message string, files map[string]string) (*gitlab.Commit, error) {
+	pid := generateProjectName(owner, repo)
+	opts := &gitlab.CreateCommitOptions{
+		Branch:        &branch,
+		CommitMessage: &message,
+	}
+	ref := branch
+	if _, _, err := c.Branches.GetBranch(pid, branch); err != nil {
+		opts.StartBranch = &startBranch
+		ref = startBranch
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		action := gitlab.FileCreate
+		if _, _, err := c.RepositoryFiles.GetFileMetaData(pid, path, &gitlab.GetFileMetaDataOptions{Ref: &ref}); err == nil {
+			action = gitlab.FileUpdate
+		}
+		filePath, content := path, files[path]
+		opts.Actions = append(opts.Actions, &gitlab.CommitActionOptions{
+			Action:   &action,
+			FilePath: &filePath,
+			Content:  &content,
+		})
+	}
+
+	commit, err := wrap(c.Commits.CreateCommit(pid, opts))
+	if err != nil {
+		return nil, err
+	}
+	if ct, ok := commit.(*gitlab.Commit); ok {
+		return ct, nil
+	}
+	return nil, err
+}
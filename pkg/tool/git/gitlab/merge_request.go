@@ -18,6 +18,22 @@ package gitlab
 
 import "github.com/xanzy/go-gitlab"
 
+func (c *Client) CreateMergeRequest(owner, repo, sourceBranch, targetBranch, title, description string) (*gitlab.MergeRequest, error) {
+	mr, err := wrap(c.MergeRequests.CreateMergeRequest(generateProjectName(owner, repo), &gitlab.CreateMergeRequestOptions{
+		Title:        &title,
+		Description:  &description,
+		SourceBranch: &sourceBranch,
+		TargetBranch: &targetBranch,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := mr.(*gitlab.MergeRequest); ok {
+		return m, nil
+	}
+	return nil, err
+}
+
 func (c *Client) ListOpenedProjectMergeRequests(owner, repo, targetBranch, key string, opts *ListOptions) ([]*gitlab.MergeRequest, error) {
 	mergeRequests, err := wrap(paginated(func(o *gitlab.ListOptions) ([]interface{}, *gitlab.Response, error) {
 		state := "opened"
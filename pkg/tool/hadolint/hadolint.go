@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hadolint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultBinary = "hadolint"
+
+	lintTimeout = 30 * time.Second
+)
+
+// Issue is a single item of the json output of `hadolint --format json`
+type Issue struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Code    string `json:"code"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// Lint runs hadolint against the given dockerfile content and returns the issues found
+func Lint(content string) ([]*Issue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lintTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, DefaultBinary, "--format", "json", "--no-fail", "-")
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to run hadolint, stderr: %s", stderr.String())
+	}
+
+	issues := make([]*Issue, 0)
+	if stdout.Len() == 0 {
+		return issues, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse hadolint output: %s", stdout.String())
+	}
+	return issues, nil
+}
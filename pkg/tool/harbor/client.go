@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harbor
+
+import (
+	"path"
+	"strconv"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+// Client is the client of the harbor v2.0 api
+type Client struct {
+	*req.Client
+	BaseURL string
+}
+
+// NewClient creates the harbor client, the tls verification is skipped if tlsEnabled is false.
+func NewClient(url, username, password string, tlsEnabled bool, tlsCert string) *Client {
+	client := req.C().
+		SetBaseURL(url).
+		SetCommonBasicAuth(username, password).
+		SetCommonContentType("application/json").
+		OnAfterResponse(func(client *req.Client, resp *req.Response) error {
+			if resp.Err != nil {
+				resp.Err = errors.Wrapf(resp.Err, "body: %s", resp.String())
+				return nil
+			}
+			if !resp.IsSuccessState() {
+				resp.Err = errors.Errorf("unexpected status code %d, body: %s", resp.GetStatusCode(), resp.String())
+				return nil
+			}
+			return nil
+		})
+	if !tlsEnabled {
+		client.EnableInsecureSkipVerify()
+	} else if tlsCert != "" {
+		client.SetRootCertFromString(tlsCert)
+	}
+
+	return &Client{
+		Client:  client,
+		BaseURL: url,
+	}
+}
+
+// createdID parses the id of the created resource from the location header, e.g. /api/v2.0/replication/policies/1
+func createdID(resp *req.Response) (int64, error) {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return 0, errors.New("location header is missing in the response")
+	}
+	id, err := strconv.ParseInt(path.Base(location), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid location header %s", location)
+	}
+	return id, nil
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harbor
+
+import (
+	"fmt"
+
+	ref "github.com/containers/image/docker/reference"
+)
+
+// RepositoryAndTag returns the repository in harbor and the tag of the image,
+// e.g. harbor.example.com/zadig/aslan:1.0.0 returns zadig/aslan and 1.0.0
+func RepositoryAndTag(image string) (string, string, error) {
+	named, err := ref.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse image %s, error: %s", image, err)
+	}
+	tagged, ok := named.(ref.Tagged)
+	if !ok {
+		return "", "", fmt.Errorf("image %s has no tag", image)
+	}
+	return ref.Path(named), tagged.Tag(), nil
+}
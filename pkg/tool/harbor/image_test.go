@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harbor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryAndTag(t *testing.T) {
+	repo, tag, err := RepositoryAndTag("harbor.example.com/zadig/aslan:1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "zadig/aslan", repo)
+	assert.Equal(t, "1.0.0", tag)
+
+	repo, tag, err = RepositoryAndTag("10.0.0.1:8443/zadig/sub/aslan:20240601000000-1-main")
+	assert.NoError(t, err)
+	assert.Equal(t, "zadig/sub/aslan", repo)
+	assert.Equal(t, "20240601000000-1-main", tag)
+
+	_, _, err = RepositoryAndTag("harbor.example.com/zadig/aslan")
+	assert.Error(t, err)
+}
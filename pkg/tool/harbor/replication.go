@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harbor
+
+import (
+	"fmt"
+)
+
+const (
+	FilterTypeName = "name"
+	FilterTypeTag  = "tag"
+
+	TriggerTypeManual = "manual"
+
+	// FlattenAll flattens all the levels of the source repository, e.g. a/b/c is replicated to <dest_namespace>/c
+	FlattenAll = -1
+
+	ExecutionStatusInProgress = "InProgress"
+	ExecutionStatusSucceed    = "Succeed"
+	ExecutionStatusFailed     = "Failed"
+	ExecutionStatusStopped    = "Stopped"
+)
+
+type Registry struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+type ReplicationFilter struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type ReplicationTrigger struct {
+	Type string `json:"type"`
+}
+
+type ReplicationPolicy struct {
+	ID            int64     `json:"id,omitempty"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	SrcRegistry   *Registry `json:"src_registry,omitempty"`
+	DestRegistry  *Registry `json:"dest_registry,omitempty"`
+	DestNamespace string    `json:"dest_namespace"`
+	// DestNamespaceReplaceCount is the levels of the source repository replaced by DestNamespace
+	DestNamespaceReplaceCount int                  `json:"dest_namespace_replace_count"`
+	Filters                   []*ReplicationFilter `json:"filters"`
+	Trigger                   *ReplicationTrigger  `json:"trigger"`
+	Override                  bool                 `json:"override"`
+	Enabled                   bool                 `json:"enabled"`
+}
+
+type ReplicationExecution struct {
+	ID         int64  `json:"id"`
+	PolicyID   int64  `json:"policy_id"`
+	Status     string `json:"status"`
+	StatusText string `json:"status_text"`
+	Total      int    `json:"total"`
+	Failed     int    `json:"failed"`
+	Succeed    int    `json:"succeed"`
+	InProgress int    `json:"in_progress"`
+	Stopped    int    `json:"stopped"`
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+}
+
+type startReplicationRequest struct {
+	PolicyID int64 `json:"policy_id"`
+}
+
+// GetRegistryByName gets the registry endpoint configured in harbor by its name
+func (c *Client) GetRegistryByName(name string) (*Registry, error) {
+	resp := make([]*Registry, 0)
+	_, err := c.R().SetQueryParam("q", fmt.Sprintf("name=%s", name)).SetSuccessResult(&resp).Get("/api/v2.0/registries")
+	if err != nil {
+		return nil, err
+	}
+	for _, registry := range resp {
+		if registry.Name == name {
+			return registry, nil
+		}
+	}
+	return nil, fmt.Errorf("registry endpoint %s not found in harbor", name)
+}
+
+// GetReplicationPolicyByName returns nil if the policy is not found
+func (c *Client) GetReplicationPolicyByName(name string) (*ReplicationPolicy, error) {
+	resp := make([]*ReplicationPolicy, 0)
+	_, err := c.R().SetQueryParam("name", name).SetSuccessResult(&resp).Get("/api/v2.0/replication/policies")
+	if err != nil {
+		return nil, err
+	}
+	// the name query is a fuzzy match
+	for _, policy := range resp {
+		if policy.Name == name {
+			return policy, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) CreateReplicationPolicy(policy *ReplicationPolicy) (int64, error) {
+	resp, err := c.R().SetBody(policy).Post("/api/v2.0/replication/policies")
+	if err != nil {
+		return 0, err
+	}
+	return createdID(resp)
+}
+
+func (c *Client) UpdateReplicationPolicy(id int64, policy *ReplicationPolicy) error {
+	_, err := c.R().SetBody(policy).Put(fmt.Sprintf("/api/v2.0/replication/policies/%d", id))
+	return err
+}
+
+// StartReplication triggers the replication policy and returns the id of the execution
+func (c *Client) StartReplication(policyID int64) (int64, error) {
+	resp, err := c.R().SetBody(&startReplicationRequest{PolicyID: policyID}).Post("/api/v2.0/replication/executions")
+	if err != nil {
+		return 0, err
+	}
+	return createdID(resp)
+}
+
+func (c *Client) GetReplicationExecution(id int64) (*ReplicationExecution, error) {
+	resp := new(ReplicationExecution)
+	_, err := c.R().SetSuccessResult(resp).Get(fmt.Sprintf("/api/v2.0/replication/executions/%d", id))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -31,6 +31,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	cm "github.com/chartmuseum/helm-push/pkg/chartmuseum"
 	hc "github.com/mittwald/go-helm-client"
@@ -447,6 +448,18 @@ func (hClient *HelmClient) ensureUpgrade(maxHistoryCount int, releaseName string
 	return updater.DeleteSecretWithName(hClient.Namespace, secretName, hClient.kubeClient)
 }
 
+// RollbackReleaseToRevision rolls back the release to the given revision and returns the new release created by the rollback
+func (hClient *HelmClient) RollbackReleaseToRevision(releaseName string, revision int, timeout time.Duration) (*release.Release, error) {
+	client := action.NewRollback(hClient.ActionConfig)
+	client.Version = revision
+	client.Timeout = timeout
+	client.MaxHistory = 10
+	if err := client.Run(releaseName); err != nil {
+		return nil, err
+	}
+	return hClient.GetRelease(releaseName)
+}
+
 // getChart returns a chart matching the provided chart name and options.
 func (hClient *HelmClient) getChart(chartName string, chartPathOptions *action.ChartPathOptions) (*chart.Chart, string, error) {
 	chartPath, err := chartPathOptions.LocateChart(chartName, hClient.HelmClient.Settings)
@@ -77,6 +77,58 @@ func (s *IssueService) GetByKeyOrID(keyOrID, fields string) (*Issue, error) {
 	return issue, nil
 }
 
+// CreateIssueArgs is the args to create an issue, the issue type is matched by name
+type CreateIssueArgs struct {
+	ProjectKey  string
+	IssueType   string
+	Summary     string
+	Description string
+}
+
+// Create https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/issue-createIssue
+func (s *IssueService) Create(args *CreateIssueArgs) (*Issue, error) {
+	url := s.client.Host + "/rest/api/2/issue"
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": args.ProjectKey},
+			"issuetype":   map[string]string{"name": args.IssueType},
+			"summary":     args.Summary,
+			"description": args.Description,
+		},
+	}
+	resp, err := s.client.R().SetBodyJsonMarshal(body).Post(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetStatusCode()/100 != 2 {
+		return nil, errors.Errorf("get unexpected status code %d, body: %s", resp.GetStatusCode(), resp.String())
+	}
+	issue := &Issue{}
+	if err = resp.UnmarshalJson(issue); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	return issue, nil
+}
+
+// UpdateSummary updates the summary and description of the issue
+func (s *IssueService) UpdateSummary(key, summary, description string) error {
+	url := s.client.Host + "/rest/api/2/issue/" + key
+	body := map[string]interface{}{
+		"fields": map[string]string{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+	resp, err := s.client.R().SetBodyJsonMarshal(body).Put(url)
+	if err != nil {
+		return err
+	}
+	if resp.GetStatusCode()/100 != 2 {
+		return errors.Errorf("get unexpected status code %d, body: %s", resp.GetStatusCode(), resp.String())
+	}
+	return nil
+}
+
 type IssueTypeDefinition struct {
 	Self     string `json:"self"`
 	ID       string `json:"id"`
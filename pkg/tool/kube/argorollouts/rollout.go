@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argorollouts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+)
+
+var RolloutGVK = schema.GroupVersionKind{
+	Group:   "argoproj.io",
+	Version: "v1alpha1",
+	Kind:    "Rollout",
+}
+
+// rollout phases defined by argo rollouts
+const (
+	PhaseHealthy     = "Healthy"
+	PhaseProgressing = "Progressing"
+	PhasePaused      = "Paused"
+	PhaseDegraded    = "Degraded"
+)
+
+type RolloutStatus struct {
+	Phase   string `json:"phase"`
+	Message string `json:"message"`
+	// Observed is false if the controller has not handled the latest spec of the rollout yet
+	Observed         bool  `json:"observed"`
+	Paused           bool  `json:"paused"`
+	Aborted          bool  `json:"aborted"`
+	CurrentStepIndex int64 `json:"current_step_index"`
+	StepCount        int64 `json:"step_count"`
+}
+
+func GetRollout(namespace, name string, cl client.Client) (*unstructured.Unstructured, bool, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(RolloutGVK)
+	found, err := getter.GetResourceInCache(namespace, name, u, cl)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	return u, true, nil
+}
+
+// SetImage updates the images of the containers of the rollout, the key of images is the container name
+func SetImage(namespace, name string, images map[string]string, cl client.Client) error {
+	rollout, found, err := GetRollout(namespace, name, cl)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("rollout %s/%s not found", namespace, name)
+	}
+
+	if err := setContainerImages(rollout, images); err != nil {
+		return err
+	}
+	return cl.Update(context.TODO(), rollout)
+}
+
+// Promote resumes a paused rollout or skips the current canary step, all the remaining steps are skipped if full is true.
+// It behaves the same as `kubectl argo rollouts promote`.
+func Promote(namespace, name string, full bool, cl client.Client) error {
+	rollout, found, err := GetRollout(namespace, name, cl)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("rollout %s/%s not found", namespace, name)
+	}
+
+	if full {
+		return patchStatus(rollout, map[string]interface{}{"promoteFull": true}, cl)
+	}
+
+	status := GetRolloutStatus(rollout)
+	if status.Paused {
+		if err := patchSpec(rollout, map[string]interface{}{"paused": false}, cl); err != nil {
+			return err
+		}
+		return patchStatus(rollout, map[string]interface{}{"pauseConditions": nil}, cl)
+	}
+	if status.StepCount > 0 && status.CurrentStepIndex < status.StepCount {
+		return patchStatus(rollout, map[string]interface{}{"currentStepIndex": status.CurrentStepIndex + 1}, cl)
+	}
+	return nil
+}
+
+// Abort stops the progress of the rollout and scales the stable version back up
+func Abort(namespace, name string, cl client.Client) error {
+	rollout, found, err := GetRollout(namespace, name, cl)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("rollout %s/%s not found", namespace, name)
+	}
+	return patchStatus(rollout, map[string]interface{}{"abort": true}, cl)
+}
+
+// GetRolloutStatus parses the status of the rollout
+func GetRolloutStatus(rollout *unstructured.Unstructured) *RolloutStatus {
+	status := &RolloutStatus{}
+	status.Phase, _, _ = unstructured.NestedString(rollout.Object, "status", "phase")
+	status.Message, _, _ = unstructured.NestedString(rollout.Object, "status", "message")
+	status.Aborted, _, _ = unstructured.NestedBool(rollout.Object, "status", "abort")
+	status.CurrentStepIndex, _, _ = unstructured.NestedInt64(rollout.Object, "status", "currentStepIndex")
+
+	// the observed generation of rollout is a string
+	observedGeneration, _, _ := unstructured.NestedFieldNoCopy(rollout.Object, "status", "observedGeneration")
+	status.Observed = fmt.Sprint(observedGeneration) == strconv.FormatInt(rollout.GetGeneration(), 10)
+
+	specPaused, _, _ := unstructured.NestedBool(rollout.Object, "spec", "paused")
+	pauseConditions, _, _ := unstructured.NestedSlice(rollout.Object, "status", "pauseConditions")
+	status.Paused = specPaused || len(pauseConditions) > 0
+
+	steps, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "strategy", "canary", "steps")
+	status.StepCount = int64(len(steps))
+	return status
+}
+
+func setContainerImages(rollout *unstructured.Unstructured, images map[string]string) error {
+	matched := make(map[string]bool)
+	for _, field := range []string{"containers", "initContainers"} {
+		path := []string{"spec", "template", "spec", field}
+		containers, found, err := unstructured.NestedSlice(rollout.Object, path...)
+		if err != nil {
+			return fmt.Errorf("failed to get %s of rollout %s: %v", field, rollout.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := container["name"].(string)
+			if image, ok := images[name]; ok {
+				container["image"] = image
+				matched[name] = true
+			}
+		}
+		if err := unstructured.SetNestedSlice(rollout.Object, containers, path...); err != nil {
+			return fmt.Errorf("failed to set %s of rollout %s: %v", field, rollout.GetName(), err)
+		}
+	}
+
+	for name := range images {
+		if !matched[name] {
+			return fmt.Errorf("container %s not found in rollout %s", name, rollout.GetName())
+		}
+	}
+	return nil
+}
+
+func patchSpec(rollout *unstructured.Unstructured, spec map[string]interface{}, cl client.Client) error {
+	data, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return err
+	}
+	return cl.Patch(context.TODO(), rollout, client.RawPatch(types.MergePatchType, data))
+}
+
+func patchStatus(rollout *unstructured.Unstructured, status map[string]interface{}, cl client.Client) error {
+	data, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+	return cl.Status().Patch(context.TODO(), rollout, client.RawPatch(types.MergePatchType, data))
+}
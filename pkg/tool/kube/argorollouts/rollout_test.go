@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argorollouts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newRollout() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"name":       "demo",
+			"generation": int64(3),
+		},
+		"spec": map[string]interface{}{
+			"strategy": map[string]interface{}{
+				"canary": map[string]interface{}{
+					"steps": []interface{}{
+						map[string]interface{}{"setWeight": int64(20)},
+						map[string]interface{}{"pause": map[string]interface{}{}},
+					},
+				},
+			},
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "app:v1"},
+					},
+					"initContainers": []interface{}{
+						map[string]interface{}{"name": "init", "image": "init:v1"},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"phase":              PhasePaused,
+			"message":            "CanaryPauseStep",
+			"observedGeneration": "3",
+			"currentStepIndex":   int64(1),
+			"pauseConditions": []interface{}{
+				map[string]interface{}{"reason": "CanaryPauseStep"},
+			},
+		},
+	}}
+}
+
+func TestGetRolloutStatus(t *testing.T) {
+	ast := require.New(t)
+
+	status := GetRolloutStatus(newRollout())
+	ast.Equal(PhasePaused, status.Phase)
+	ast.Equal("CanaryPauseStep", status.Message)
+	ast.True(status.Observed)
+	ast.True(status.Paused)
+	ast.False(status.Aborted)
+	ast.Equal(int64(1), status.CurrentStepIndex)
+	ast.Equal(int64(2), status.StepCount)
+
+	rollout := newRollout()
+	rollout.SetGeneration(4)
+	ast.False(GetRolloutStatus(rollout).Observed)
+}
+
+func TestSetContainerImages(t *testing.T) {
+	ast := require.New(t)
+
+	rollout := newRollout()
+	ast.NoError(setContainerImages(rollout, map[string]string{"app": "app:v2", "init": "init:v2"}))
+
+	containers, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "template", "spec", "containers")
+	ast.Equal("app:v2", containers[0].(map[string]interface{})["image"])
+	initContainers, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "template", "spec", "initContainers")
+	ast.Equal("init:v2", initContainers[0].(map[string]interface{})["image"])
+
+	ast.Error(setContainerImages(newRollout(), map[string]string{"sidecar": "sidecar:v1"}))
+}
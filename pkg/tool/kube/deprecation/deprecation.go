@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/yaml"
+)
+
+type Status string
+
+const (
+	StatusDeprecated Status = "deprecated"
+	StatusRemoved    Status = "removed"
+)
+
+// API is a group version kind deprecated in DeprecatedIn and no longer served since RemovedIn
+type API struct {
+	APIVersion   string `json:"api_version"`
+	Kind         string `json:"kind"`
+	DeprecatedIn string `json:"deprecated_in"`
+	RemovedIn    string `json:"removed_in"`
+	Replacement  string `json:"replacement"`
+}
+
+// APIs is the list of the deprecated APIs, refer to https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var APIs = []*API{
+	{APIVersion: "extensions/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.10", RemovedIn: "1.16", Replacement: "policy/v1beta1"},
+	{APIVersion: "apps/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta1", Kind: "StatefulSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "StatefulSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "DaemonSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "ReplicaSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1"},
+
+	{APIVersion: "extensions/v1beta1", Kind: "Ingress", DeprecatedIn: "1.14", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kind: "IngressClass", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "MutatingWebhookConfiguration", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "admissionregistration.k8s.io/v1"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "ValidatingWebhookConfiguration", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "admissionregistration.k8s.io/v1"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "apiextensions.k8s.io/v1"},
+	{APIVersion: "apiregistration.k8s.io/v1beta1", Kind: "APIService", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "apiregistration.k8s.io/v1"},
+	{APIVersion: "certificates.k8s.io/v1beta1", Kind: "CertificateSigningRequest", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "certificates.k8s.io/v1"},
+	{APIVersion: "coordination.k8s.io/v1beta1", Kind: "Lease", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "coordination.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", DeprecatedIn: "1.14", RemovedIn: "1.22", Replacement: "scheduling.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIDriver", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSINode", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "VolumeAttachment", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+
+	{APIVersion: "batch/v1beta1", Kind: "CronJob", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "batch/v1"},
+	{APIVersion: "discovery.k8s.io/v1beta1", Kind: "EndpointSlice", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "discovery.k8s.io/v1"},
+	{APIVersion: "events.k8s.io/v1beta1", Kind: "Event", DeprecatedIn: "1.19", RemovedIn: "1.25", Replacement: "events.k8s.io/v1"},
+	{APIVersion: "autoscaling/v2beta1", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.22", RemovedIn: "1.25", Replacement: "autoscaling/v2"},
+	{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "policy/v1"},
+	{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: ""},
+	{APIVersion: "node.k8s.io/v1beta1", Kind: "RuntimeClass", DeprecatedIn: "1.20", RemovedIn: "1.25", Replacement: "node.k8s.io/v1"},
+
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "FlowSchema", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "autoscaling/v2"},
+
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIStorageCapacity", DeprecatedIn: "1.24", RemovedIn: "1.27", Replacement: "storage.k8s.io/v1"},
+
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "FlowSchema", DeprecatedIn: "1.26", RemovedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.26", RemovedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "FlowSchema", DeprecatedIn: "1.29", RemovedIn: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.29", RemovedIn: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// Find returns the deprecation of the api version and kind, nil is returned if it is not deprecated
+func Find(apiVersion, kind string) *API {
+	for _, api := range APIs {
+		if api.APIVersion == apiVersion && api.Kind == kind {
+			return api
+		}
+	}
+	return nil
+}
+
+// StatusIn returns the status of the api in the k8s version, empty is returned if the api is still fully supported
+func (a *API) StatusIn(v *version.Version) Status {
+	if v.AtLeast(version.MustParseGeneric(a.RemovedIn)) {
+		return StatusRemoved
+	}
+	if v.AtLeast(version.MustParseGeneric(a.DeprecatedIn)) {
+		return StatusDeprecated
+	}
+	return ""
+}
+
+// RemovedIn returns the apis removed in the minor version of v
+func RemovedIn(v *version.Version) []*API {
+	ret := make([]*API, 0)
+	for _, api := range APIs {
+		removed := version.MustParseGeneric(api.RemovedIn)
+		if removed.Major() == v.Major() && removed.Minor() == v.Minor() {
+			ret = append(ret, api)
+		}
+	}
+	return ret
+}
+
+// LatestRemoval returns the latest k8s version in which some of the apis are removed
+func LatestRemoval() *version.Version {
+	var latest *version.Version
+	for _, api := range APIs {
+		removed := version.MustParseGeneric(api.RemovedIn)
+		if latest == nil || latest.LessThan(removed) {
+			latest = removed
+		}
+	}
+	return latest
+}
+
+// ParseVersion parses the k8s version like v1.25.3-eks-123 to the minor version 1.25
+func ParseVersion(v string) (*version.Version, error) {
+	parsed, err := version.ParseGeneric(strings.TrimSpace(v))
+	if err != nil {
+		return nil, fmt.Errorf("invalid k8s version %s: %s", v, err)
+	}
+	return majorMinor(parsed.Major(), parsed.Minor()), nil
+}
+
+// NextMinor returns the next minor versions of v
+func NextMinor(v *version.Version, count int) []*version.Version {
+	ret := make([]*version.Version, 0, count)
+	for i := 1; i <= count; i++ {
+		ret = append(ret, majorMinor(v.Major(), v.Minor()+uint(i)))
+	}
+	return ret
+}
+
+func majorMinor(major, minor uint) *version.Version {
+	return version.MustParseGeneric(fmt.Sprintf("%d.%d", major, minor))
+}
+
+// Finding is a resource in the manifests using a deprecated api
+type Finding struct {
+	*API   `json:",inline"`
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+}
+
+type objectMeta struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+}
+
+// the service templates may contain go template syntax and can not be always unmarshalled,
+// so the api version and kind are matched by the top level fields
+var (
+	apiVersionRegex = regexp.MustCompile(`(?m)^apiVersion:\s*["']?([\w./-]+)["']?\s*$`)
+	kindRegex       = regexp.MustCompile(`(?m)^kind:\s*["']?(\w+)["']?\s*$`)
+)
+
+// Scan checks the apis used by the manifests against the target k8s version
+func Scan(manifests []string, target *version.Version) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, manifest := range manifests {
+		if strings.TrimSpace(manifest) == "" {
+			continue
+		}
+		apiVersionMatch := apiVersionRegex.FindStringSubmatch(manifest)
+		kindMatch := kindRegex.FindStringSubmatch(manifest)
+		if apiVersionMatch == nil || kindMatch == nil {
+			continue
+		}
+		api := Find(apiVersionMatch[1], kindMatch[1])
+		if api == nil {
+			continue
+		}
+		meta := &objectMeta{}
+		_ = yaml.Unmarshal([]byte(manifest), meta)
+		status := api.StatusIn(target)
+		if status == "" {
+			continue
+		}
+		findings = append(findings, &Finding{
+			API:    api,
+			Name:   meta.Metadata.Name,
+			Status: status,
+		})
+	}
+	return findings
+}
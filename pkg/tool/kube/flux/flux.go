@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package flux reads the reconcile status of the fluxcd HelmRelease and Kustomization objects and edits the
+// manifests in the flux source repositories.
+package flux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
+)
+
+const (
+	KindHelmRelease   = "HelmRelease"
+	KindKustomization = "Kustomization"
+
+	// ReconcileRequestAnnotation asks the flux controllers to reconcile the object out of its interval
+	ReconcileRequestAnnotation = "reconcile.fluxcd.io/requestedAt"
+)
+
+// the served versions are tried in order so that both the current and the older flux releases are supported
+var kindVersions = map[string][]schema.GroupVersion{
+	KindHelmRelease: {
+		{Group: "helm.toolkit.fluxcd.io", Version: "v2"},
+		{Group: "helm.toolkit.fluxcd.io", Version: "v2beta2"},
+		{Group: "helm.toolkit.fluxcd.io", Version: "v2beta1"},
+	},
+	KindKustomization: {
+		{Group: "kustomize.toolkit.fluxcd.io", Version: "v1"},
+		{Group: "kustomize.toolkit.fluxcd.io", Version: "v1beta2"},
+	},
+}
+
+type ResourceStatus struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Found     bool   `json:"found"`
+	Suspended bool   `json:"suspended"`
+	// Ready is the status of the Ready condition: True, False or Unknown
+	Ready   string `json:"ready"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// Observed is false if the controller has not handled the latest spec of the object yet
+	Observed              bool   `json:"observed"`
+	LastAppliedRevision   string `json:"last_applied_revision"`
+	LastAttemptedRevision string `json:"last_attempted_revision"`
+	LastReconcileTime     string `json:"last_reconcile_time"`
+	Error                 string `json:"error,omitempty"`
+}
+
+func ValidateKind(kind string) error {
+	if _, ok := kindVersions[kind]; !ok {
+		return fmt.Errorf("unsupported flux kind %s, only %s and %s are supported", kind, KindHelmRelease, KindKustomization)
+	}
+	return nil
+}
+
+// GetResource gets the object with the first served version of the kind
+func GetResource(kind, namespace, name string, cl client.Client) (*unstructured.Unstructured, bool, error) {
+	versions, ok := kindVersions[kind]
+	if !ok {
+		return nil, false, ValidateKind(kind)
+	}
+
+	var lastErr error
+	for _, gv := range versions {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gv.WithKind(kind))
+		found, err := getter.GetResourceInCache(namespace, name, u, cl)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				lastErr = err
+				continue
+			}
+			return nil, false, err
+		}
+		return u, found, nil
+	}
+	return nil, false, fmt.Errorf("%s is not served by the cluster, is flux installed? error: %v", kind, lastErr)
+}
+
+// GetResourceStatus returns the reconcile status of the object, a missing object is reported with Found false
+func GetResourceStatus(kind, namespace, name string, cl client.Client) (*ResourceStatus, error) {
+	status := &ResourceStatus{
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+	}
+	obj, found, err := GetResource(kind, namespace, name, cl)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return status, nil
+	}
+
+	status.Found = true
+	status.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	status.Observed = observedGeneration == obj.GetGeneration()
+	status.LastAppliedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+	status.LastAttemptedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAttemptedRevision")
+	status.LastReconcileTime, _, _ = unstructured.NestedString(obj.Object, "status", "lastHandledReconcileAt")
+
+	status.Ready = "Unknown"
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		status.Ready, _ = condition["status"].(string)
+		status.Reason, _ = condition["reason"].(string)
+		status.Message, _ = condition["message"].(string)
+		if t, _ := condition["lastTransitionTime"].(string); t != "" && status.LastReconcileTime == "" {
+			status.LastReconcileTime = t
+		}
+	}
+	return status, nil
+}
+
+// RequestReconcile annotates the object so that the flux controller reconciles it immediately, it behaves the same as
+// `flux reconcile`.
+func RequestReconcile(kind, namespace, name string, cl client.Client) error {
+	obj, found, err := GetResource(kind, namespace, name, cl)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s %s/%s not found", kind, namespace, name)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				ReconcileRequestAnnotation: time.Now().Format(time.RFC3339Nano),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return cl.Patch(context.TODO(), obj, client.RawPatch(types.MergePatchType, data))
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetKustomizationImage sets the tag of the image in the images of the kustomization.yaml, the image is appended
+// if it's not there yet. It returns false if the content is not changed.
+func SetKustomizationImage(content []byte, imageName, newTag string) ([]byte, bool, error) {
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(docs) == 0 || docs[0].Kind != yaml.DocumentNode || len(docs[0].Content) == 0 || docs[0].Content[0].Kind != yaml.MappingNode {
+		return nil, false, fmt.Errorf("invalid kustomization file")
+	}
+
+	root := docs[0].Content[0]
+	images := mappingValue(root, "images")
+	if images == nil {
+		images = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, scalarNode("images"), images)
+	}
+	if images.Kind != yaml.SequenceNode {
+		return nil, false, fmt.Errorf("images of the kustomization file is not a list")
+	}
+
+	for _, image := range images.Content {
+		name := mappingValue(image, "name")
+		if name == nil || name.Value != imageName {
+			continue
+		}
+		tag := mappingValue(image, "newTag")
+		if tag != nil && tag.Value == newTag {
+			return content, false, nil
+		}
+		if tag == nil {
+			image.Content = append(image.Content, scalarNode("newTag"), scalarNode(newTag))
+		} else {
+			tag.Value = newTag
+			tag.Tag = "!!str"
+			tag.Style = 0
+		}
+		resp, err := encodeDocuments(docs)
+		return resp, true, err
+	}
+
+	images.Content = append(images.Content, &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			scalarNode("name"), scalarNode(imageName),
+			scalarNode("newTag"), scalarNode(newTag),
+		},
+	})
+	resp, err := encodeDocuments(docs)
+	return resp, true, err
+}
+
+// SetValue sets the string value of the dot separated path, e.g. spec.values.image.tag of a HelmRelease. The first
+// document which has the path is changed if the file contains multiple documents. It returns false if the content
+// is not changed.
+func SetValue(content []byte, path, value string) ([]byte, bool, error) {
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return nil, false, err
+	}
+	keys := strings.Split(strings.Trim(path, "."), ".")
+
+	for _, doc := range docs {
+		if len(doc.Content) == 0 {
+			continue
+		}
+		node := doc.Content[0]
+		for _, key := range keys {
+			node = mappingValue(node, key)
+			if node == nil {
+				break
+			}
+		}
+		if node == nil {
+			continue
+		}
+		if node.Kind != yaml.ScalarNode {
+			return nil, false, fmt.Errorf("%s is not a scalar value", path)
+		}
+		if node.Value == value {
+			return content, false, nil
+		}
+		node.Value = value
+		node.Tag = "!!str"
+		resp, err := encodeDocuments(docs)
+		return resp, true, err
+	}
+	return nil, false, fmt.Errorf("path %s not found", path)
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func decodeDocuments(content []byte) ([]*yaml.Node, error) {
+	docs := make([]*yaml.Node, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		doc := &yaml.Node{}
+		if err := decoder.Decode(doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode yaml: %v", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func encodeDocuments(docs []*yaml.Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode yaml: %v", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
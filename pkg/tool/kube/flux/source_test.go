@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const kustomization = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - deployment.yaml
+images:
+  - name: koderover/app
+    newTag: v1 # updated by zadig
+`
+
+func TestSetKustomizationImage(t *testing.T) {
+	content, changed, err := SetKustomizationImage([]byte(kustomization), "koderover/app", "v2")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Contains(t, string(content), "newTag: v2 # updated by zadig")
+	require.Contains(t, string(content), "- deployment.yaml")
+
+	_, changed, err = SetKustomizationImage(content, "koderover/app", "v2")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	content, changed, err = SetKustomizationImage(content, "koderover/worker", "1.0")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Contains(t, string(content), "- name: koderover/worker\n    newTag: \"1.0\"")
+}
+
+const helmRelease = `apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: podinfo
+---
+apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: podinfo
+spec:
+  values:
+    image:
+      tag: 6.5.0
+`
+
+func TestSetValue(t *testing.T) {
+	content, changed, err := SetValue([]byte(helmRelease), "spec.values.image.tag", "6.6.0")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Contains(t, string(content), "kind: HelmRepository")
+	require.Contains(t, string(content), "tag: 6.6.0")
+
+	_, changed, err = SetValue(content, "spec.values.image.tag", "6.6.0")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	_, _, err = SetValue(content, "spec.values.image.repository", "podinfo")
+	require.Error(t, err)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koderover/zadig/v2/pkg/setting"
+)
+
+// generationMap stores a change counter for each informer key, the counter is increased each time
+// one of the watched workloads in the namespace is added, updated or deleted.
+var generationMap sync.Map
+
+// Generation returns the change counter of the given namespace. Caches built on top of the informer
+// can record the generation when computing a value and treat the value as stale once it changes.
+func Generation(clusterID, namespace string) uint64 {
+	if clusterID == "" {
+		clusterID = setting.LocalClusterID
+	}
+
+	counter, ok := generationMap.Load(generateInformerKey(clusterID, namespace))
+	if !ok {
+		return 0
+	}
+	return atomic.LoadUint64(counter.(*uint64))
+}
+
+func increaseGeneration(key string) {
+	counter, _ := generationMap.LoadOrStore(key, new(uint64))
+	atomic.AddUint64(counter.(*uint64), 1)
+}
+
+// newGenerationHandler returns an event handler increasing the generation of the informer key.
+// Periodic resyncs deliver updates with an unchanged resource version and are ignored.
+func newGenerationHandler(key string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			increaseGeneration(key)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldMeta, err := meta.Accessor(oldObj)
+			if err != nil {
+				increaseGeneration(key)
+				return
+			}
+			newMeta, err := meta.Accessor(newObj)
+			if err != nil {
+				increaseGeneration(key)
+				return
+			}
+			if oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+				return
+			}
+			increaseGeneration(key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			increaseGeneration(key)
+		},
+	}
+}
@@ -128,6 +128,15 @@ func NewInformer(clusterID, namespace string, cls *kubernetes.Clientset) (inform
 	informerFactory.Core().V1().Pods().Lister()
 	informerFactory.Core().V1().ConfigMaps().Lister()
 	informerFactory.Batch().V1().Jobs().Lister()
+
+	// changes of the workloads, pods, services and ingresses affect the computed service status,
+	// so they increase the generation of the namespace
+	generationHandler := newGenerationHandler(generateInformerKey(clusterID, namespace))
+	informerFactory.Apps().V1().Deployments().Informer().AddEventHandler(generationHandler)
+	informerFactory.Apps().V1().StatefulSets().Informer().AddEventHandler(generationHandler)
+	informerFactory.Core().V1().Services().Informer().AddEventHandler(generationHandler)
+	informerFactory.Core().V1().Pods().Informer().AddEventHandler(generationHandler)
+	informerFactory.Batch().V1().Jobs().Informer().AddEventHandler(generationHandler)
 	versionInfo, err := cls.Discovery().ServerVersion()
 	if err != nil {
 		return nil, err
@@ -135,15 +144,19 @@ func NewInformer(clusterID, namespace string, cls *kubernetes.Clientset) (inform
 	// if less than v1.22.0, then we look for the extensions/v1beta1 ingress
 	if kubeclient.VersionLessThan122(versionInfo) {
 		informerFactory.Extensions().V1beta1().Ingresses().Lister()
+		informerFactory.Extensions().V1beta1().Ingresses().Informer().AddEventHandler(generationHandler)
 	} else {
 		// otherwise above resource is deprecated, we watch for the k8s.networking.io/v1 ingress
 		informerFactory.Networking().V1().Ingresses().Lister()
+		informerFactory.Networking().V1().Ingresses().Informer().AddEventHandler(generationHandler)
 	}
 
 	if kubeclient.VersionLessThan121(versionInfo) {
 		informerFactory.Batch().V1beta1().CronJobs().Lister()
+		informerFactory.Batch().V1beta1().CronJobs().Informer().AddEventHandler(generationHandler)
 	} else {
 		informerFactory.Batch().V1().CronJobs().Lister()
+		informerFactory.Batch().V1().CronJobs().Informer().AddEventHandler(generationHandler)
 	}
 
 	informerMapInstance.Set(clusterID, namespace, informerFactory)
@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	KubeScoreBinary = "kube-score"
+	PolarisBinary   = "polaris"
+
+	checkTimeout = 60 * time.Second
+)
+
+// kube-score grades, checks with a grade higher than gradeWarning are passed
+const (
+	gradeCritical = 1
+	gradeWarning  = 5
+)
+
+type kubeScoreObject struct {
+	ObjectName string `json:"object_name"`
+	TypeMeta   struct {
+		Kind string `json:"kind"`
+	} `json:"type_meta"`
+	Checks []*kubeScoreCheck `json:"checks"`
+}
+
+type kubeScoreCheck struct {
+	Check struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"check"`
+	Grade    int  `json:"grade"`
+	Skipped  bool `json:"skipped"`
+	Comments []struct {
+		Path        string `json:"path"`
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+	} `json:"comments"`
+}
+
+// KubeScore runs `kube-score score` against the manifests and converts the failed checks into findings
+func KubeScore(manifests []string) ([]*Finding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, KubeScoreBinary, "score", "--output-format", "json", "-")
+	cmd.Stdin = strings.NewReader(strings.Join(manifests, "\n---\n"))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// kube-score exits with a non-zero code when any check fails, the output is checked instead
+	if err := cmd.Run(); err != nil && stdout.Len() == 0 {
+		return nil, errors.Wrapf(err, "failed to run kube-score, stderr: %s", stderr.String())
+	}
+
+	objects := make([]*kubeScoreObject, 0)
+	if err := json.Unmarshal(stdout.Bytes(), &objects); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse kube-score output: %s", stdout.String())
+	}
+
+	findings := make([]*Finding, 0)
+	for _, object := range objects {
+		for _, check := range object.Checks {
+			if check.Skipped || check.Grade > gradeWarning {
+				continue
+			}
+			level := LevelWarning
+			if check.Grade <= gradeCritical {
+				level = LevelError
+			}
+			messages := make([]string, 0)
+			for _, comment := range check.Comments {
+				msg := comment.Summary
+				if comment.Path != "" {
+					msg = fmt.Sprintf("(%s) %s", comment.Path, msg)
+				}
+				messages = append(messages, msg)
+			}
+			if len(messages) == 0 {
+				messages = append(messages, check.Check.Name)
+			}
+			findings = append(findings, &Finding{
+				Source:  SourceKubeScore,
+				Level:   level,
+				Kind:    object.TypeMeta.Kind,
+				Name:    object.ObjectName,
+				Rule:    check.Check.ID,
+				Message: strings.Join(messages, "; "),
+			})
+		}
+	}
+	return findings, nil
+}
+
+type polarisReport struct {
+	Results []*polarisResult `json:"Results"`
+}
+
+type polarisResult struct {
+	Name      string                         `json:"Name"`
+	Kind      string                         `json:"Kind"`
+	Results   map[string]*polarisCheckResult `json:"Results"`
+	PodResult *struct {
+		Results          map[string]*polarisCheckResult `json:"Results"`
+		ContainerResults []*struct {
+			Name    string                         `json:"Name"`
+			Results map[string]*polarisCheckResult `json:"Results"`
+		} `json:"ContainerResults"`
+	} `json:"PodResult"`
+}
+
+type polarisCheckResult struct {
+	ID       string `json:"ID"`
+	Message  string `json:"Message"`
+	Success  bool   `json:"Success"`
+	Severity string `json:"Severity"`
+}
+
+// Polaris runs `polaris audit` against the manifests and converts the failed checks into findings
+func Polaris(manifests []string) ([]*Finding, error) {
+	dir, err := os.MkdirTemp("", "polaris-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// polaris audits the files in a directory only
+	if err := os.WriteFile(filepath.Join(dir, "manifests.yaml"), []byte(strings.Join(manifests, "\n---\n")), 0644); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, PolarisBinary, "audit", "--audit-path", dir, "--format", "json")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to run polaris, stderr: %s", stderr.String())
+	}
+
+	report := &polarisReport{}
+	if err := json.Unmarshal(stdout.Bytes(), report); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse polaris output: %s", stdout.String())
+	}
+
+	findings := make([]*Finding, 0)
+	appendResults := func(result *polarisResult, prefix string, checks map[string]*polarisCheckResult) {
+		for _, check := range checks {
+			if check.Success || check.Severity == "ignore" {
+				continue
+			}
+			level := LevelWarning
+			if check.Severity == "danger" {
+				level = LevelError
+			}
+			findings = append(findings, &Finding{
+				Source:  SourcePolaris,
+				Level:   level,
+				Kind:    result.Kind,
+				Name:    result.Name,
+				Rule:    check.ID,
+				Message: prefix + check.Message,
+			})
+		}
+	}
+	for _, result := range report.Results {
+		appendResults(result, "", result.Results)
+		if result.PodResult == nil {
+			continue
+		}
+		appendResults(result, "", result.PodResult.Results)
+		for _, container := range result.PodResult.ContainerResults {
+			appendResults(result, fmt.Sprintf("(container %s) ", container.Name), container.Results)
+		}
+	}
+	return findings, nil
+}
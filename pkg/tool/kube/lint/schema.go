@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/kubectl/pkg/util/openapi"
+	"k8s.io/kubectl/pkg/util/openapi/validation"
+	"sigs.k8s.io/yaml"
+)
+
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelInfo    Level = "info"
+)
+
+type Source string
+
+const (
+	SourceSchema    Source = "schema"
+	SourceKubeScore Source = "kube-score"
+	SourcePolaris   Source = "polaris"
+)
+
+// Finding is a single problem found in the manifests
+type Finding struct {
+	Source  Source `json:"source"`
+	Level   Level  `json:"level"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+	// ClusterID is the cluster whose api schema is used, it is only set for schema findings
+	ClusterID string `json:"cluster_id,omitempty"`
+}
+
+func HasError(findings []*Finding) bool {
+	for _, finding := range findings {
+		if finding.Level == LevelError {
+			return true
+		}
+	}
+	return false
+}
+
+type objectMeta struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+}
+
+type SchemaValidator struct {
+	validator *validation.SchemaValidation
+}
+
+// NewSchemaValidator creates a validator with the openapi schema served by the cluster
+func NewSchemaValidator(client discovery.OpenAPISchemaInterface) (*SchemaValidator, error) {
+	resources, err := openapi.NewOpenAPIParser(client).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get openapi schema, err: %s", err)
+	}
+	return &SchemaValidator{validator: validation.NewSchemaValidation(resources)}, nil
+}
+
+// Validate validates the manifests against the schema, resources whose kind is unknown to the cluster are skipped.
+// When partial is true, errors about missing required fields are ignored so that patches can be validated as well.
+func (v *SchemaValidator) Validate(manifests []string, partial bool) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, manifest := range manifests {
+		if strings.TrimSpace(manifest) == "" {
+			continue
+		}
+		meta := &objectMeta{}
+		_ = yaml.Unmarshal([]byte(manifest), meta)
+
+		err := v.validator.ValidateBytes([]byte(manifest))
+		if err == nil {
+			continue
+		}
+		errs := []error{err}
+		if agg, ok := err.(utilerrors.Aggregate); ok {
+			errs = agg.Errors()
+		}
+		for _, e := range errs {
+			if partial && strings.Contains(e.Error(), "missing required field") {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Source:  SourceSchema,
+				Level:   LevelError,
+				Kind:    meta.Kind,
+				Name:    meta.Metadata.Name,
+				Message: e.Error(),
+			})
+		}
+	}
+	return findings
+}
@@ -89,3 +89,29 @@ func UpdateJobImage(ns, name, container, image string, cl client.Client) error {
 	patchBytes := []byte(fmt.Sprintf(`{"spec":{"template":{"spec":{"containers":[{"name":"%s","image":"%s"}]}}}}`, container, image))
 	return PatchJob(ns, name, patchBytes, cl)
 }
+
+// RecreateJob deletes the job and creates it again from its spec, since the pod template of a job is immutable.
+// Fields generated by the job controller are dropped so that the new job gets its own selector.
+func RecreateJob(job *batchv1.Job, cl client.Client) error {
+	copied := job.DeepCopy()
+	newJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   copied.Namespace,
+			Name:        copied.Name,
+			Labels:      copied.Labels,
+			Annotations: copied.Annotations,
+		},
+		Spec: copied.Spec,
+	}
+	newJob.Spec.Selector = nil
+	newJob.Spec.ManualSelector = nil
+	for _, key := range []string{"controller-uid", "job-name", "batch.kubernetes.io/controller-uid", "batch.kubernetes.io/job-name"} {
+		delete(newJob.Labels, key)
+		delete(newJob.Spec.Template.Labels, key)
+	}
+
+	if err := DeleteJobAndWait(job.Namespace, job.Name, cl); err != nil {
+		return err
+	}
+	return CreateJob(newJob, cl)
+}
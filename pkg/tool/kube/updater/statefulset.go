@@ -23,11 +23,14 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koderover/zadig/v2/pkg/tool/kube/getter"
 )
 
 func PatchStatefulSet(ns, name string, patchBytes []byte, cl client.Client) error {
@@ -82,3 +85,44 @@ func ScaleStatefulSet(ns, name string, replicas int, cl client.Client) error {
 func CreateOrPatchStatefulSet(sts *appsv1.StatefulSet, cl client.Client) error {
 	return createOrPatchObject(sts, cl)
 }
+
+func UpdateStatefulSetPartition(ns, name string, partition int32, cl client.Client) error {
+	patchBytes := []byte(fmt.Sprintf(`{"spec":{"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"partition":%d}}}}`, partition))
+	return PatchStatefulSet(ns, name, patchBytes, cl)
+}
+
+func UpdateStatefulSetPVCRetentionPolicy(ns, name string, policy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy, cl client.Client) error {
+	patchBytes := []byte(fmt.Sprintf(`{"spec":{"persistentVolumeClaimRetentionPolicy":{"whenDeleted":"%s","whenScaled":"%s"}}}`, policy.WhenDeleted, policy.WhenScaled))
+	return PatchStatefulSet(ns, name, patchBytes, cl)
+}
+
+// RecreateStatefulSetOrphan recreates the statefulSet with the given spec while keeping its pods and pvcs,
+// it is the only way to change immutable fields such as podManagementPolicy.
+// The orphaned pods are adopted by the new statefulSet since the selector is not changed.
+func RecreateStatefulSetOrphan(sts *appsv1.StatefulSet, cl client.Client) error {
+	copied := sts.DeepCopy()
+	newSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   copied.Namespace,
+			Name:        copied.Name,
+			Labels:      copied.Labels,
+			Annotations: copied.Annotations,
+		},
+		Spec: copied.Spec,
+	}
+
+	if err := deleteObject(sts, cl, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil {
+		return err
+	}
+	err := wait.PollImmediate(time.Second, 60*time.Second, func() (bool, error) {
+		found, err := getter.GetResourceInCache(sts.Namespace, sts.Name, &appsv1.StatefulSet{}, cl)
+		if err != nil {
+			return false, err
+		}
+		return !found, nil
+	})
+	if err != nil {
+		return err
+	}
+	return createObject(newSts, cl)
+}
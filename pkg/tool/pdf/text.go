@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pdf renders plain text documents to PDF without any external dependency.
+// The text is drawn with the Adobe standard CJK font STSong-Light which is provided by the PDF readers,
+// so both latin and chinese characters are supported without embedding a font.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	marginLeft   = 50.0
+	marginTop    = 60.0
+	marginBottom = 50.0
+)
+
+type lineStyle struct {
+	fontSize   float64
+	lineHeight float64
+	indent     float64
+}
+
+var (
+	titleStyle   = lineStyle{fontSize: 18, lineHeight: 28}
+	headingStyle = lineStyle{fontSize: 13, lineHeight: 22}
+	textStyle    = lineStyle{fontSize: 10, lineHeight: 15}
+	itemStyle    = lineStyle{fontSize: 10, lineHeight: 15, indent: 15}
+)
+
+type line struct {
+	text  string
+	style lineStyle
+}
+
+// TextDocument is a simple flow document made of titles, headings and text lines
+type TextDocument struct {
+	lines []*line
+}
+
+func NewTextDocument() *TextDocument {
+	return &TextDocument{lines: make([]*line, 0)}
+}
+
+func (d *TextDocument) AddTitle(text string) {
+	d.addLine(text, titleStyle)
+}
+
+func (d *TextDocument) AddHeading(text string) {
+	d.addLine("", textStyle)
+	d.addLine(text, headingStyle)
+}
+
+func (d *TextDocument) AddText(text string) {
+	d.addLine(text, textStyle)
+}
+
+// AddItem adds an indented text line
+func (d *TextDocument) AddItem(text string) {
+	d.addLine(text, itemStyle)
+}
+
+func (d *TextDocument) addLine(text string, style lineStyle) {
+	maxWidth := pageWidth - 2*marginLeft - style.indent
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, wrapped := range wrapText(paragraph, style.fontSize, maxWidth) {
+			d.lines = append(d.lines, &line{text: wrapped, style: style})
+		}
+	}
+}
+
+// Bytes renders the document to PDF
+func (d *TextDocument) Bytes() []byte {
+	pages := d.paginate()
+
+	// object numbers: 1 catalog, 2 pages, 3 font, 4 descendant font, 5 font descriptor,
+	// then a page object and a content object for each page
+	objects := make([]string, 0)
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 6+2*i))
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>",
+		"<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+	)
+	for i, content := range pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 7+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, 0, len(objects))
+	for i, obj := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// paginate lays the lines out and returns the content stream of each page
+func (d *TextDocument) paginate() []string {
+	pages := make([]string, 0)
+	content := new(strings.Builder)
+	y := pageHeight - marginTop
+	for _, l := range d.lines {
+		if y-l.style.lineHeight < marginBottom {
+			pages = append(pages, content.String())
+			content.Reset()
+			y = pageHeight - marginTop
+		}
+		y -= l.style.lineHeight
+		if l.text == "" {
+			continue
+		}
+		fmt.Fprintf(content, "BT /F1 %.0f Tf %.2f %.2f Td <%s> Tj ET\n", l.style.fontSize, marginLeft+l.style.indent, y, encodeText(l.text))
+	}
+	return append(pages, content.String())
+}
+
+// encodeText encodes the text to UCS-2 hex string used by the UniGB-UCS2-H encoding
+func encodeText(text string) string {
+	sb := new(strings.Builder)
+	for _, r := range text {
+		if r > 0xFFFF || r < 0x20 {
+			r = '?'
+		}
+		for _, u := range utf16.Encode([]rune{r}) {
+			fmt.Fprintf(sb, "%04X", u)
+		}
+	}
+	return sb.String()
+}
+
+func runeWidth(r rune, fontSize float64) float64 {
+	if r < 0x80 {
+		return fontSize / 2
+	}
+	return fontSize
+}
+
+func wrapText(text string, fontSize, maxWidth float64) []string {
+	resp := make([]string, 0)
+	current := make([]rune, 0)
+	width := 0.0
+	for _, r := range text {
+		w := runeWidth(r, fontSize)
+		if width+w > maxWidth && len(current) > 0 {
+			resp = append(resp, string(current))
+			current = current[:0]
+			width = 0
+		}
+		current = append(current, r)
+		width += w
+	}
+	return append(resp, string(current))
+}
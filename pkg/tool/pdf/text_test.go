@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextDocument_Bytes(t *testing.T) {
+	ast := require.New(t)
+
+	doc := NewTextDocument()
+	doc.AddTitle("工作流任务报告")
+	for i := 0; i < 60; i++ {
+		doc.AddItem("build job succeeded")
+	}
+	data := doc.Bytes()
+
+	ast.True(bytes.HasPrefix(data, []byte("%PDF-1.4")))
+	ast.True(bytes.HasSuffix(data, []byte("%%EOF\n")))
+	ast.Contains(string(data), "/Count 2")
+	ast.Contains(string(data), "<5DE54F5C6D414EFB52A162A5544A>")
+}
+
+func TestWrapText(t *testing.T) {
+	ast := require.New(t)
+
+	lines := wrapText(strings.Repeat("a", 30), 10, 100)
+	ast.Equal([]string{strings.Repeat("a", 20), strings.Repeat("a", 10)}, lines)
+
+	lines = wrapText("中文中文中文中文中文中文", 10, 100)
+	ast.Equal([]string{"中文中文中文中文中文", "中文"}, lines)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/fs"
 	"mime"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -275,6 +276,41 @@ func (c *Client) GetFile(bucketName, objectKey string, option *DownloadOption) (
 	return nil, err
 }
 
+// StatObject returns the metadata of the object, found is false if the object doesn't exist.
+func (c *Client) StatObject(bucketName, objectKey string) (*s3.HeadObjectOutput, bool, error) {
+	output, err := c.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		if e, ok := err.(awserr.RequestFailure); ok && e.StatusCode() == http.StatusNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return output, true, nil
+}
+
+// ListFilesWithSize returns the sizes of all the objects with given prefix, keyed by the object keys
+func (c *Client) ListFilesWithSize(bucketName, prefix string) (map[string]int64, error) {
+	ret := make(map[string]int64)
+	input := &s3.ListObjectsInput{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	}
+	err := c.ListObjectsPages(input, func(output *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, item := range output.Contents {
+			ret[aws.StringValue(item.Key)] = aws.Int64Value(item.Size)
+		}
+		return true
+	})
+	if err != nil {
+		log.Errorf("bucket [%s] listing objects with prefix [%v] failed, error: %v", bucketName, prefix, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
 // ListFiles with given prefix
 func (c *Client) ListFiles(bucketName, prefix string, recursive bool) ([]string, error) {
 	ret := make([]string, 0)
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// AWSSecretsManagerProvider reads the secrets from AWS Secrets Manager, the path is the name or arn of the secret.
+type AWSSecretsManagerProvider struct {
+	region    string
+	accessKey string
+	secretKey string
+}
+
+func NewAWSSecretsManagerProvider(region, accessKey, secretKey string) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+	}
+}
+
+func (p *AWSSecretsManagerProvider) client() (*secretsmanager.SecretsManager, error) {
+	config := &aws.Config{
+		Region: aws.String(p.region),
+	}
+	// use the credentials of the pod, e.g. IRSA, if the access key is not provided
+	if p.accessKey != "" {
+		config.Credentials = credentials.NewStaticCredentials(p.accessKey, p.secretKey, "")
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return secretsmanager.New(sess), nil
+}
+
+func (p *AWSSecretsManagerProvider) GetSecret(path, key string) (string, error) {
+	svc, err := p.client()
+	if err != nil {
+		return "", err
+	}
+	out, err := svc.GetSecretValue(&secretsmanager.GetSecretValueInput{SecretId: aws.String(path)})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret %s is binary, only string secrets are supported", path)
+	}
+	if key == "" {
+		return *out.SecretString, nil
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(*out.SecretString), &data); err != nil {
+		return "", fmt.Errorf("secret %s is not a key/value secret: %s", path, err)
+	}
+	return pickSecretKey(data, path, key)
+}
+
+// Validate checks the credentials by listing the secrets.
+func (p *AWSSecretsManagerProvider) Validate() error {
+	svc, err := p.client()
+	if err != nil {
+		return err
+	}
+	_, err = svc.ListSecrets(&secretsmanager.ListSecretsInput{MaxResults: aws.Int64(1)})
+	return err
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koderover/zadig/v2/pkg/tool/crypto"
+)
+
+const (
+	ProviderTypeVault             = "vault"
+	ProviderTypeAWSSecretsManager = "aws_secrets_manager"
+
+	// ReferencePrefix starts the value of a credential variable that references an external secret store,
+	// the format is secret://<provider name>/<path>#<key>, the key is optional.
+	ReferencePrefix = "secret://"
+	// sealedPrefix starts the value resolved from an external secret store and encrypted by the aes key of zadig.
+	sealedPrefix = "sealed://"
+)
+
+// Provider reads a secret from an external secret store.
+type Provider interface {
+	// GetSecret returns the value of the key in the secret of the path,
+	// the whole secret is returned if the key is empty and the secret is not a key/value map.
+	GetSecret(path, key string) (string, error)
+}
+
+type Reference struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+func (r *Reference) String() string {
+	s := fmt.Sprintf("%s%s/%s", ReferencePrefix, r.Provider, r.Path)
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// IsReference tells if the value references an external secret store.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+func ParseReference(value string) (*Reference, error) {
+	if !IsReference(value) {
+		return nil, fmt.Errorf("%s is not a secret reference", value)
+	}
+	ref := &Reference{}
+	s := strings.TrimPrefix(value, ReferencePrefix)
+	if i := strings.LastIndex(s, "#"); i >= 0 {
+		ref.Key = s[i+1:]
+		s = s[:i]
+	}
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || strings.Trim(parts[1], "/") == "" {
+		return nil, fmt.Errorf("invalid secret reference %s, the format is %s<provider>/<path>#<key>", value, ReferencePrefix)
+	}
+	ref.Provider = parts[0]
+	ref.Path = strings.Trim(parts[1], "/")
+	return ref, nil
+}
+
+// Seal encrypts the resolved secret so that it is not saved in plaintext with the task.
+func Seal(value string) (string, error) {
+	encrypted, err := crypto.AesEncrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + encrypted, nil
+}
+
+// Unseal decrypts the value sealed by Seal, other values are returned as they are.
+func Unseal(value string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return value, nil
+	}
+	return crypto.AesDecrypt(strings.TrimPrefix(value, sealedPrefix))
+}
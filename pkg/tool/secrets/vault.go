@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+const defaultVaultMountPath = "secret"
+
+// VaultProvider reads the secrets from the KV version 2 secrets engine of HashiCorp Vault.
+type VaultProvider struct {
+	client    *req.Client
+	mountPath string
+}
+
+func NewVaultProvider(address, token, namespace, mountPath string) *VaultProvider {
+	if mountPath == "" {
+		mountPath = defaultVaultMountPath
+	}
+	client := req.C().
+		SetBaseURL(strings.TrimSuffix(address, "/")).
+		SetCommonHeader("X-Vault-Token", token).
+		OnAfterResponse(func(client *req.Client, resp *req.Response) error {
+			if resp.Err != nil {
+				resp.Err = errors.Wrapf(resp.Err, "body: %s", resp.String())
+				return nil
+			}
+			if !resp.IsSuccessState() {
+				resp.Err = errors.Errorf("unexpected status code %d, body: %s", resp.GetStatusCode(), resp.String())
+				return nil
+			}
+			return nil
+		})
+	if namespace != "" {
+		client.SetCommonHeader("X-Vault-Namespace", namespace)
+	}
+	return &VaultProvider{
+		client:    client,
+		mountPath: strings.Trim(mountPath, "/"),
+	}
+}
+
+type vaultKVResp struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+func (p *VaultProvider) GetSecret(path, key string) (string, error) {
+	resp := new(vaultKVResp)
+	_, err := p.client.R().SetSuccessResult(resp).Get(fmt.Sprintf("/v1/%s/data/%s", p.mountPath, strings.Trim(path, "/")))
+	if err != nil {
+		return "", err
+	}
+	return pickSecretKey(resp.Data.Data, path, key)
+}
+
+// Validate checks the token by looking up itself.
+func (p *VaultProvider) Validate() error {
+	_, err := p.client.R().Get("/v1/auth/token/lookup-self")
+	return err
+}
+
+func pickSecretKey(data map[string]interface{}, path, key string) (string, error) {
+	if key == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("secret %s has %d keys, the key must be specified", path, len(data))
+		}
+		for k := range data {
+			key = k
+		}
+	}
+	v, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s", key, path)
+	}
+	switch value := v.(type) {
+	case string:
+		return value, nil
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
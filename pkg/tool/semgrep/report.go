@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semgrep
+
+import "encoding/json"
+
+// Report is the json output of `semgrep scan --json`
+type Report struct {
+	Results []*Result      `json:"results"`
+	Errors  []*ReportError `json:"errors"`
+}
+
+type Result struct {
+	CheckID string   `json:"check_id"`
+	Path    string   `json:"path"`
+	Start   Position `json:"start"`
+	End     Position `json:"end"`
+	Extra   Extra    `json:"extra"`
+}
+
+type Position struct {
+	Line int `json:"line"`
+	Col  int `json:"col"`
+}
+
+type Extra struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+type ReportError struct {
+	Level   string `json:"level"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func ParseReport(data []byte) (*Report, error) {
+	report := new(Report)
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
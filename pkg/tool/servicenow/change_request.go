@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicenow
+
+import (
+	"fmt"
+	"strings"
+)
+
+const changeRequestTable = "/api/now/table/change_request"
+
+// approval values of change requests
+const (
+	ApprovalRequested    = "requested"
+	ApprovalApproved     = "approved"
+	ApprovalRejected     = "rejected"
+	ApprovalNotRequested = "not requested"
+)
+
+type ChangeRequest struct {
+	SysID            string `json:"sys_id,omitempty"`
+	Number           string `json:"number,omitempty"`
+	ShortDescription string `json:"short_description,omitempty"`
+	Description      string `json:"description,omitempty"`
+	Type             string `json:"type,omitempty"`
+	AssignmentGroup  string `json:"assignment_group,omitempty"`
+	State            string `json:"state,omitempty"`
+	Approval         string `json:"approval,omitempty"`
+	WorkNotes        string `json:"work_notes,omitempty"`
+}
+
+type changeRequestResp struct {
+	Result *ChangeRequest `json:"result"`
+}
+
+func (c *Client) CreateChangeRequest(change *ChangeRequest) (*ChangeRequest, error) {
+	resp := new(changeRequestResp)
+	_, err := c.R().SetBodyJsonMarshal(change).SetSuccessResult(resp).Post(changeRequestTable)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, fmt.Errorf("empty change request in response")
+	}
+	return resp.Result, nil
+}
+
+func (c *Client) GetChangeRequest(sysID string) (*ChangeRequest, error) {
+	resp := new(changeRequestResp)
+	_, err := c.R().SetPathParam("sysID", sysID).
+		SetQueryParam("sysparm_fields", "sys_id,number,short_description,state,approval").
+		SetSuccessResult(resp).Get(changeRequestTable + "/{sysID}")
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, fmt.Errorf("change request %s not found", sysID)
+	}
+	return resp.Result, nil
+}
+
+// UpdateChangeRequest updates the non-empty fields of the change request
+func (c *Client) UpdateChangeRequest(sysID string, change *ChangeRequest) (*ChangeRequest, error) {
+	resp := new(changeRequestResp)
+	_, err := c.R().SetPathParam("sysID", sysID).SetBodyJsonMarshal(change).
+		SetSuccessResult(resp).Patch(changeRequestTable + "/{sysID}")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+func (c *Client) AddWorkNote(sysID, note string) error {
+	_, err := c.UpdateChangeRequest(sysID, &ChangeRequest{WorkNotes: note})
+	return err
+}
+
+// IsApproved returns true if the change request is approved
+func (c *ChangeRequest) IsApproved() bool {
+	return strings.EqualFold(c.Approval, ApprovalApproved)
+}
+
+// IsRejected returns true if the change request is rejected
+func (c *ChangeRequest) IsRejected() bool {
+	return strings.EqualFold(c.Approval, ApprovalRejected)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicenow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChangeRequest(t *testing.T) {
+	changes := map[string]*ChangeRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		change := new(ChangeRequest)
+		switch r.Method {
+		case http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(change)
+			change.SysID, change.Number, change.Approval = "abc", "CHG0001", ApprovalRequested
+			changes[change.SysID] = change
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			change = changes[r.URL.Path[len(changeRequestTable)+1:]]
+			if change == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		case http.MethodPatch:
+			change = changes[r.URL.Path[len(changeRequestTable)+1:]]
+			_ = json.NewDecoder(r.Body).Decode(change)
+		}
+		_ = json.NewEncoder(w).Encode(changeRequestResp{Result: change})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "admin", "secret")
+	change, err := client.CreateChangeRequest(&ChangeRequest{ShortDescription: "release"})
+	if err != nil {
+		t.Fatalf("create change request: %v", err)
+	}
+	if change.SysID != "abc" || change.Number != "CHG0001" || change.IsApproved() {
+		t.Fatalf("unexpected change request %+v", change)
+	}
+
+	changes["abc"].Approval = ApprovalApproved
+	change, err = client.GetChangeRequest("abc")
+	if err != nil {
+		t.Fatalf("get change request: %v", err)
+	}
+	if !change.IsApproved() || change.IsRejected() {
+		t.Fatalf("change request should be approved, got %s", change.Approval)
+	}
+
+	if err := client.AddWorkNote("abc", "done"); err != nil {
+		t.Fatalf("add work note: %v", err)
+	}
+	if changes["abc"].WorkNotes != "done" {
+		t.Fatalf("work note not added")
+	}
+
+	if _, err := client.GetChangeRequest("missing"); err == nil {
+		t.Fatalf("expect error for missing change request")
+	}
+	if _, err := NewClient(server.URL, "admin", "wrong").GetChangeRequest("abc"); err == nil {
+		t.Fatalf("expect error for wrong password")
+	}
+}
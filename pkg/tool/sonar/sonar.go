@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -109,10 +110,19 @@ func (c *Client) GetCETaskInfo(taskID string) (*CETaskInfo, error) {
 	return res, nil
 }
 
-func (c *Client) GetComponentMeasures(componentKey string) (*MeasuresComponentResponse, error) {
+// GetComponentMeasures returns the measures of the component, the measures of the pull request analysis are returned
+// if the pullRequest is not empty.
+func (c *Client) GetComponentMeasures(componentKey, pullRequest string) (*MeasuresComponentResponse, error) {
 	url := "/api/measures/component"
 	resp := &MeasuresComponentResponse{}
-	if _, err := c.Client.Get(url, httpclient.SetQueryParam("component", componentKey), httpclient.SetQueryParam("metricKeys", "ncloc,bugs,vulnerabilities,code_smells,coverage"), httpclient.SetResult(resp)); err != nil {
+	queries := map[string]string{
+		"component":  componentKey,
+		"metricKeys": "ncloc,bugs,vulnerabilities,code_smells,coverage",
+	}
+	if pullRequest != "" {
+		queries["pullRequest"] = pullRequest
+	}
+	if _, err := c.Client.Get(url, httpclient.SetQueryParams(queries), httpclient.SetResult(resp)); err != nil {
 		return nil, fmt.Errorf("search sonar component measures: component %s, error: %v", componentKey, err)
 	}
 	return resp, nil
@@ -253,3 +263,40 @@ func GetSonarAddressWithProjectKey(baseAddr, projectKey string) (string, error)
 	u.RawQuery = url.Values{"id": {projectKey}}.Encode()
 	return u.String(), nil
 }
+
+// GetSonarAddressWithPullRequest return the address of the pull request analysis of the project
+func GetSonarAddressWithPullRequest(baseAddr, projectKey string, pullRequest int) (string, error) {
+	if projectKey == "" {
+		return baseAddr, nil
+	}
+	u, err := url.Parse(baseAddr)
+	if err != nil {
+		return baseAddr, fmt.Errorf("failed to parse sonar server address, error: %s", err)
+	}
+	u = u.JoinPath("dashboard")
+	u.RawQuery = url.Values{"id": {projectKey}, "pullRequest": {strconv.Itoa(pullRequest)}}.Encode()
+	return u.String(), nil
+}
+
+// SetPullRequestParameters adds the sonar.pullrequest.* parameters to the scanner config and removes the
+// sonar.branch.* parameters since sonar does not allow both of them in one analysis.
+// The config is returned as it is if the pull request parameters are configured by the user.
+func SetPullRequestParameters(config string, pullRequest int, branch, base string) string {
+	if getKeyValue(config, "sonar.pullrequest.key") != "" {
+		return config
+	}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(config, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "sonar.branch.") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines,
+		fmt.Sprintf("sonar.pullrequest.key=%d", pullRequest),
+		fmt.Sprintf("sonar.pullrequest.branch=%s", branch),
+		fmt.Sprintf("sonar.pullrequest.base=%s", base),
+	)
+	return strings.Join(lines, "\n")
+}
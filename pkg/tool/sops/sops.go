@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sops decrypts the yaml files encrypted by mozilla sops (https://github.com/getsops/sops)
+// with the age identities or the aws kms keys. The keys are passed in instead of being read from the
+// environment, and the message authentication code of the file is always verified.
+package sops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	gosops "github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	sopsage "github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/keyservice"
+	sopskms "github.com/getsops/sops/v3/kms"
+	sopsyaml "github.com/getsops/sops/v3/stores/yaml"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
+)
+
+const metadataKey = "sops"
+
+// Keys are the keys used to decrypt the data key of the file, the ones matching the metadata of the file are tried in order.
+type Keys struct {
+	// AgeIdentities are the age secret keys, e.g. AGE-SECRET-KEY-1...
+	AgeIdentities []string
+	KMS           []*KMSCredential
+}
+
+// KMSCredential is the aws credential used to call kms, the credentials of the pod are used if the access key is empty.
+type KMSCredential struct {
+	AccessKey string
+	SecretKey string
+}
+
+// CheckAgeIdentity checks if the identity is a valid age secret key.
+func CheckAgeIdentity(identity string) error {
+	if _, err := age.ParseX25519Identity(strings.TrimSpace(identity)); err != nil {
+		return fmt.Errorf("invalid age identity: %s", err)
+	}
+	return nil
+}
+
+// IsEncrypted tells if the yaml has the sops metadata.
+func IsEncrypted(content string) bool {
+	if !strings.Contains(content, metadataKey+":") {
+		return false
+	}
+	holder := struct {
+		Metadata map[string]interface{} `yaml:"sops"`
+	}{}
+	if err := yaml.Unmarshal([]byte(content), &holder); err != nil {
+		return false
+	}
+	return holder.Metadata != nil
+}
+
+// Decrypt returns the yaml with the values decrypted and the sops metadata removed.
+func Decrypt(content string, keys *Keys) (string, error) {
+	store := &sopsyaml.Store{}
+	tree, err := store.LoadEncryptedFile([]byte(content))
+	if err == gosops.MetadataNotFound {
+		return content, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load sops file: %s", err)
+	}
+
+	client, err := newKeyClient(keys)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := tree.Metadata.GetDataKeyWithKeyServices([]keyservice.KeyServiceClient{client})
+	if err != nil {
+		// the error of sops only counts the failed key groups, the reasons are kept by the client
+		if len(client.errs) > 0 {
+			return "", fmt.Errorf("failed to decrypt the sops data key: %s", strings.Join(client.errs, "; "))
+		}
+		return "", fmt.Errorf("failed to decrypt the sops data key: %s", err)
+	}
+
+	cipher := aes.NewCipher()
+	computedMac, err := tree.Decrypt(dataKey, cipher)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the values: %s", err)
+	}
+	fileMac, err := cipher.Decrypt(tree.Metadata.MessageAuthenticationCode, dataKey, tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the mac: %s", err)
+	}
+	if fileMac != computedMac {
+		return "", fmt.Errorf("mac mismatch, the file has been modified after it was encrypted")
+	}
+
+	plain, err := store.EmitPlainFile(tree.Branches)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// keyClient is a local key service decrypting the data key with the given keys only,
+// the default key service of sops reads the keys from the environment of the process.
+type keyClient struct {
+	identities sopsage.ParsedIdentities
+	kms        []*sopskms.CredentialsProvider
+	errs       []string
+}
+
+func newKeyClient(keys *Keys) (*keyClient, error) {
+	client := &keyClient{}
+	if keys == nil {
+		return client, nil
+	}
+	for _, identity := range keys.AgeIdentities {
+		if err := client.identities.Import(identity); err != nil {
+			return nil, err
+		}
+	}
+	for _, cred := range keys.KMS {
+		if cred.AccessKey == "" {
+			// nil provider falls back to the default credential chain of the pod
+			client.kms = append(client.kms, sopskms.NewCredentialsProvider(nil))
+			continue
+		}
+		client.kms = append(client.kms, sopskms.NewCredentialsProvider(credentials.NewStaticCredentialsProvider(cred.AccessKey, cred.SecretKey, "")))
+	}
+	return client, nil
+}
+
+func (c *keyClient) Encrypt(ctx context.Context, req *keyservice.EncryptRequest, opts ...grpc.CallOption) (*keyservice.EncryptResponse, error) {
+	return nil, fmt.Errorf("encryption is not supported")
+}
+
+func (c *keyClient) Decrypt(ctx context.Context, req *keyservice.DecryptRequest, opts ...grpc.CallOption) (*keyservice.DecryptResponse, error) {
+	plain, err := c.decrypt(req)
+	if err != nil {
+		c.errs = append(c.errs, err.Error())
+		return nil, err
+	}
+	return &keyservice.DecryptResponse{Plaintext: plain}, nil
+}
+
+func (c *keyClient) decrypt(req *keyservice.DecryptRequest) ([]byte, error) {
+	switch k := req.Key.KeyType.(type) {
+	case *keyservice.Key_AgeKey:
+		if len(c.identities) == 0 {
+			return nil, fmt.Errorf("age recipient %s: no age identity is configured", k.AgeKey.Recipient)
+		}
+		key := &sopsage.MasterKey{
+			Recipient:    k.AgeKey.Recipient,
+			EncryptedKey: string(req.Ciphertext),
+		}
+		c.identities.ApplyToMasterKey(key)
+		plain, err := key.Decrypt()
+		if err != nil {
+			return nil, fmt.Errorf("age recipient %s: %s", k.AgeKey.Recipient, err)
+		}
+		return plain, nil
+	case *keyservice.Key_KmsKey:
+		if len(c.kms) == 0 {
+			return nil, fmt.Errorf("kms %s: no aws kms credential is configured", k.KmsKey.Arn)
+		}
+		encryptionContext := make(map[string]*string)
+		for name, v := range k.KmsKey.Context {
+			value := v
+			encryptionContext[name] = &value
+		}
+		errs := make([]string, 0)
+		for _, provider := range c.kms {
+			key := &sopskms.MasterKey{
+				Arn:               k.KmsKey.Arn,
+				Role:              k.KmsKey.Role,
+				EncryptedKey:      string(req.Ciphertext),
+				EncryptionContext: encryptionContext,
+			}
+			provider.ApplyToMasterKey(key)
+			plain, err := key.Decrypt()
+			if err == nil {
+				return plain, nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return nil, fmt.Errorf("kms %s: %s", k.KmsKey.Arn, strings.Join(errs, "; "))
+	default:
+		return nil, fmt.Errorf("unsupported key type, only age and aws kms are supported")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// the fixtures are encrypted by `sops --encrypt --age <recipient> --encrypted-regex '^(password|port|ssl|hosts)$'`,
+// values.kms.yaml carries the same values with the data key encrypted by a kms key instead.
+const testAgeIdentity = "AGE-SECRET-KEY-1RVP2FGL05HCA057MLZZDV4HUE8CUF8GPNWXVD5QLGX5QG0JSXSJQHWUUCD"
+
+func readFixture(t *testing.T, name string) string {
+	content, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestIsEncrypted(t *testing.T) {
+	assert.True(t, IsEncrypted(readFixture(t, "values.age.yaml")))
+	assert.True(t, IsEncrypted(readFixture(t, "values.kms.yaml")))
+	assert.False(t, IsEncrypted("image:\n  tag: 1.0.0\n"))
+	assert.False(t, IsEncrypted("sops: enabled\n"))
+	assert.False(t, IsEncrypted("sops: [\n"))
+}
+
+func TestDecryptAge(t *testing.T) {
+	plain, err := Decrypt(readFixture(t, "values.age.yaml"), &Keys{AgeIdentities: []string{testAgeIdentity}})
+	assert.NoError(t, err)
+
+	values := map[string]interface{}{}
+	assert.NoError(t, yaml.Unmarshal([]byte(plain), &values))
+	assert.NotContains(t, values, "sops")
+	assert.Equal(t, map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "koderover/aslan",
+			"tag":        "1.0.0",
+		},
+		"database": map[string]interface{}{
+			"password": "s3cr3t",
+			"port":     3306,
+			"ssl":      true,
+		},
+		"hosts": []interface{}{"a.example.com", "b.example.com"},
+	}, values)
+}
+
+func TestDecryptAgeWrongIdentity(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	assert.NoError(t, err)
+
+	_, err = Decrypt(readFixture(t, "values.age.yaml"), &Keys{AgeIdentities: []string{identity.String()}})
+	assert.Error(t, err)
+
+	_, err = Decrypt(readFixture(t, "values.age.yaml"), nil)
+	assert.Error(t, err)
+}
+
+func TestDecryptMacMismatch(t *testing.T) {
+	content := strings.Replace(readFixture(t, "values.age.yaml"), "tag: 1.0.0", "tag: 2.0.0", 1)
+
+	_, err := Decrypt(content, &Keys{AgeIdentities: []string{testAgeIdentity}})
+	assert.ErrorContains(t, err, "mac mismatch")
+}
+
+func TestDecryptKMSWithoutCredential(t *testing.T) {
+	_, err := Decrypt(readFixture(t, "values.kms.yaml"), &Keys{AgeIdentities: []string{testAgeIdentity}})
+	assert.ErrorContains(t, err, "no aws kms credential is configured")
+}
+
+func TestDecryptPlainValues(t *testing.T) {
+	plain, err := Decrypt("image:\n  tag: 1.0.0\n", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "image:\n  tag: 1.0.0\n", plain)
+}
+
+func TestCheckAgeIdentity(t *testing.T) {
+	assert.NoError(t, CheckAgeIdentity(testAgeIdentity))
+	assert.Error(t, CheckAgeIdentity("age1d8nmmkkhaucq6vq6ewrz2l4cjw5hlf27eadh9e0lka6lj4ec7v9skxwp3n"))
+	assert.Error(t, CheckAgeIdentity("AGE-SECRET-KEY-1INVALID"))
+}
@@ -30,3 +30,44 @@ const (
 	Str    JenkinsParamType = "string"
 	Choice JenkinsParamType = "choice"
 )
+
+// DockerBuildBackend is the tool used to build and push the image in the docker build step.
+type DockerBuildBackend string
+
+const (
+	// DockerBuildBackendDocker uses the docker daemon, which is the default backend
+	DockerBuildBackendDocker DockerBuildBackend = "docker"
+	// DockerBuildBackendKaniko uses the kaniko executor, no docker daemon or privilege is required
+	DockerBuildBackendKaniko DockerBuildBackend = "kaniko"
+	// DockerBuildBackendBuildKit uses buildctl, the buildkitd in BUILDKIT_HOST is used if set, otherwise a rootless
+	// buildkitd is started in the job by buildctl-daemonless.sh
+	DockerBuildBackendBuildKit DockerBuildBackend = "buildkit"
+)
+
+func (b DockerBuildBackend) Valid() bool {
+	switch b {
+	case "", DockerBuildBackendDocker, DockerBuildBackendKaniko, DockerBuildBackendBuildKit:
+		return true
+	}
+	return false
+}
+
+// MultiArchStrategy is how the docker backend builds the image for multiple platforms.
+type MultiArchStrategy string
+
+const (
+	// MultiArchStrategyBuildx builds all the platforms in one docker buildx command, which relies on qemu
+	// to build the platforms other than the one of the node
+	MultiArchStrategyBuildx MultiArchStrategy = "buildx"
+	// MultiArchStrategyManifest builds and pushes an image for each platform and assembles them into a manifest list
+	// in the end, the per-platform images are tagged with the architecture as the suffix
+	MultiArchStrategyManifest MultiArchStrategy = "manifest"
+)
+
+func (s MultiArchStrategy) Valid() bool {
+	switch s {
+	case "", MultiArchStrategyBuildx, MultiArchStrategyManifest:
+		return true
+	}
+	return false
+}
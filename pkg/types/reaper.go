@@ -25,5 +25,7 @@ const (
 )
 
 const (
-	ScanningTypeSonar = "sonarQube"
+	ScanningTypeSonar     = "sonarQube"
+	ScanningTypeSemgrep   = "semgrep"
+	ScanningTypeCheckmarx = "checkmarx"
 )
@@ -35,4 +35,6 @@ type Upload struct {
 	FilePath            string `bson:"file_path"                             json:"file_path"                                 yaml:"file_path"`
 	AbsFilePath         string `bson:"abs_file_path"                         json:"aabs_file_pathk"                           yaml:"abs_file_path"`
 	DestinationPath     string `bson:"dest_path"                             json:"dest_path"                                 yaml:"dest_path"`
+	// IndexFile is the entry page if the upload is a html report
+	IndexFile string `bson:"index_file,omitempty"                  json:"index_file,omitempty"                      yaml:"index_file,omitempty"`
 }
@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package step
+
+import "github.com/koderover/zadig/v2/pkg/types"
+
+// StepArtifactUploadSpec pushes a file of the workspace to a nexus/artifactory repository
+type StepArtifactUploadSpec struct {
+	RepositoryID  string              `bson:"repository_id"              json:"repository_id"                     yaml:"repository_id"`
+	Type          string              `bson:"type"                       json:"type"                              yaml:"type"`
+	Address       string              `bson:"address"                    json:"address"                           yaml:"address"`
+	Repository    string              `bson:"repository"                 json:"repository"                        yaml:"repository"`
+	Username      string              `bson:"username"                   json:"username"                          yaml:"username"`
+	Password      string              `bson:"password"                   json:"password"                          yaml:"password"`
+	Token         string              `bson:"token"                      json:"token"                             yaml:"token"`
+	FilePath      string              `bson:"file_path"                  json:"file_path"                         yaml:"file_path"`
+	FileName      string              `bson:"file_name"                  json:"file_name"                         yaml:"file_name"`
+	DestDir       string              `bson:"dest_dir"                   json:"dest_dir"                          yaml:"dest_dir"`
+	ServiceName   string              `bson:"service_name"               json:"service_name"                      yaml:"service_name"`
+	ServiceModule string              `bson:"service_module"             json:"service_module"                    yaml:"service_module"`
+	Version       string              `bson:"version"                    json:"version"                           yaml:"version"`
+	Properties    map[string]string   `bson:"properties"                 json:"properties"                        yaml:"properties"`
+	Repos         []*types.Repository `bson:"repos"                      json:"repos"                             yaml:"repos"`
+}
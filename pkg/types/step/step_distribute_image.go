@@ -21,10 +21,20 @@ import (
 	"strings"
 )
 
+const (
+	// DistributeMethodImagePush pulls and pushes the images by docker in the job pod, it is the default method
+	DistributeMethodImagePush = "image_push"
+	// DistributeMethodHarborReplication triggers the replication of the source harbor to push the images to the target registry
+	DistributeMethodHarborReplication = "harbor_replication"
+)
+
 type StepImageDistributeSpec struct {
 	SourceRegistry   *RegistryNamespace      `bson:"source_registry"                json:"source_registry"               yaml:"source_registry"`
 	TargetRegistry   *RegistryNamespace      `bson:"target_registry"                json:"target_registry"               yaml:"target_registry"`
 	DistributeTarget []*DistributeTaskTarget `bson:"distribute_target"              json:"distribute_target"             yaml:"distribute_target"`
+	Method           string                  `bson:"method,omitempty"               json:"method,omitempty"              yaml:"method,omitempty"`
+	// ReplicationRegistryName is the name of the registry endpoint of the target registry configured in the source harbor
+	ReplicationRegistryName string `bson:"replication_registry_name,omitempty" json:"replication_registry_name,omitempty" yaml:"replication_registry_name,omitempty"`
 }
 
 type DistributeTaskTarget struct {
@@ -34,6 +44,11 @@ type DistributeTaskTarget struct {
 	ServiceName   string `bson:"service_name"       yaml:"service_name"     json:"service_name"`
 	ServiceModule string `bson:"service_module"     yaml:"service_module"   json:"service_module"`
 	UpdateTag     bool   `bson:"update_tag"         yaml:"update_tag"       json:"update_tag"`
+	// the fields below are only used by the harbor replication
+	ReplicationPolicyID    int64  `bson:"replication_policy_id,omitempty"    yaml:"replication_policy_id,omitempty"    json:"replication_policy_id,omitempty"`
+	ReplicationExecutionID int64  `bson:"replication_execution_id,omitempty" yaml:"replication_execution_id,omitempty" json:"replication_execution_id,omitempty"`
+	ReplicationStatus      string `bson:"replication_status,omitempty"       yaml:"replication_status,omitempty"       json:"replication_status,omitempty"`
+	ReplicationStatusText  string `bson:"replication_status_text,omitempty"  yaml:"replication_status_text,omitempty"  json:"replication_status_text,omitempty"`
 }
 
 type RegistryNamespace struct {
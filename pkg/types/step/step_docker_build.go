@@ -24,18 +24,21 @@ import (
 )
 
 type StepDockerBuildSpec struct {
-	Source                string              `bson:"source"                              json:"source"                                 yaml:"source"`
-	WorkDir               string              `bson:"work_dir"                            json:"work_dir"                               yaml:"work_dir"`
-	RegistryHost          string              `bson:"registry_host"                       json:"registry_host"                          yaml:"registry_host"`
-	DockerFile            string              `bson:"docker_file"                         json:"docker_file"                            yaml:"docker_file"`
-	ImageName             string              `bson:"image_name"                          json:"image_name"                             yaml:"image_name"`
-	BuildArgs             string              `bson:"build_args"                          json:"build_args"                             yaml:"build_args"`
-	ImageReleaseTag       string              `bson:"image_release_tag"                   json:"image_release_tag"                      yaml:"image_release_tag"`
-	DockerTemplateContent string              `bson:"docker_template_content"             json:"docker_template_content"                yaml:"docker_template_content"`
-	Proxy                 *Proxy              `bson:"proxy"                               json:"proxy"                                  yaml:"proxy"`
-	IgnoreCache           bool                `bson:"ignore_cache"                        json:"ignore_cache"                           yaml:"ignore_cache"`
-	DockerRegistry        *DockerRegistry     `bson:"docker_registry"                     json:"docker_registry"                        yaml:"docker_registry"`
-	Repos                 []*types.Repository `bson:"repos"                               json:"repos"`
+	Source                string                   `bson:"source"                              json:"source"                                 yaml:"source"`
+	WorkDir               string                   `bson:"work_dir"                            json:"work_dir"                               yaml:"work_dir"`
+	RegistryHost          string                   `bson:"registry_host"                       json:"registry_host"                          yaml:"registry_host"`
+	DockerFile            string                   `bson:"docker_file"                         json:"docker_file"                            yaml:"docker_file"`
+	ImageName             string                   `bson:"image_name"                          json:"image_name"                             yaml:"image_name"`
+	BuildArgs             string                   `bson:"build_args"                          json:"build_args"                             yaml:"build_args"`
+	ImageReleaseTag       string                   `bson:"image_release_tag"                   json:"image_release_tag"                      yaml:"image_release_tag"`
+	DockerTemplateContent string                   `bson:"docker_template_content"             json:"docker_template_content"                yaml:"docker_template_content"`
+	Proxy                 *Proxy                   `bson:"proxy"                               json:"proxy"                                  yaml:"proxy"`
+	IgnoreCache           bool                     `bson:"ignore_cache"                        json:"ignore_cache"                           yaml:"ignore_cache"`
+	DockerRegistry        *DockerRegistry          `bson:"docker_registry"                     json:"docker_registry"                        yaml:"docker_registry"`
+	Backend               types.DockerBuildBackend `bson:"backend"                             json:"backend"                                yaml:"backend"`
+	Platforms             []string                 `bson:"platforms"                           json:"platforms"                              yaml:"platforms"`
+	MultiArchStrategy     types.MultiArchStrategy  `bson:"multi_arch_strategy"                 json:"multi_arch_strategy"                    yaml:"multi_arch_strategy"`
+	Repos                 []*types.Repository      `bson:"repos"                               json:"repos"`
 }
 
 type DockerRegistry struct {
@@ -56,3 +59,24 @@ func (s *StepDockerBuildSpec) GetDockerFile() string {
 	}
 	return s.DockerFile
 }
+
+// IsMultiArch returns whether the image is built for the given platforms instead of the platform of the node.
+func (s *StepDockerBuildSpec) IsMultiArch() bool {
+	return len(s.Platforms) > 0
+}
+
+// GetMultiArchStrategy returns the multi-arch strategy of the docker backend, buildx is used by default.
+func (s *StepDockerBuildSpec) GetMultiArchStrategy() types.MultiArchStrategy {
+	if s.MultiArchStrategy == "" {
+		return types.MultiArchStrategyBuildx
+	}
+	return s.MultiArchStrategy
+}
+
+// GetBackend returns the build backend, docker is used by default.
+func (s *StepDockerBuildSpec) GetBackend() types.DockerBuildBackend {
+	if s.Backend == "" {
+		return types.DockerBuildBackendDocker
+	}
+	return s.Backend
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The KodeRover Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package step
+
+type StepScannerReportSpec struct {
+	ScannerType string `bson:"scanner_type"  json:"scanner_type"  yaml:"scanner_type"`
+	// ReportFile is the path of the scanner report relative to the workspace
+	ReportFile string `bson:"report_file"   json:"report_file"   yaml:"report_file"`
+	S3DestDir  string `bson:"s3_dest_dir"   json:"s3_dest_dir"   yaml:"s3_dest_dir"`
+	FileName   string `bson:"file_name"     json:"file_name"     yaml:"file_name"`
+	S3Storage  *S3    `bson:"s3_storage"    json:"s3_storage"    yaml:"s3_storage"`
+	// ScannerResult is parsed from the uploaded report after the step finishes
+	ScannerResult *ScannerResult `bson:"scanner_result" json:"scanner_result" yaml:"scanner_result"`
+}
+
+type ScannerResult struct {
+	Total    int               `bson:"total"    json:"total"    yaml:"total"`
+	Critical int               `bson:"critical" json:"critical" yaml:"critical"`
+	High     int               `bson:"high"     json:"high"     yaml:"high"`
+	Medium   int               `bson:"medium"   json:"medium"   yaml:"medium"`
+	Low      int               `bson:"low"      json:"low"      yaml:"low"`
+	Info     int               `bson:"info"     json:"info"     yaml:"info"`
+	Findings []*ScannerFinding `bson:"findings" json:"findings" yaml:"findings"`
+}
+
+type ScannerFinding struct {
+	RuleID   string `bson:"rule_id"  json:"rule_id"  yaml:"rule_id"`
+	Severity string `bson:"severity" json:"severity" yaml:"severity"`
+	Message  string `bson:"message"  json:"message"  yaml:"message"`
+	File     string `bson:"file"     json:"file"     yaml:"file"`
+	Line     int    `bson:"line"     json:"line"     yaml:"line"`
+}
+
+const (
+	ScannerSeverityCritical = "critical"
+	ScannerSeverityHigh     = "high"
+	ScannerSeverityMedium   = "medium"
+	ScannerSeverityLow      = "low"
+	ScannerSeverityInfo     = "info"
+)
+
+// AddFinding counts the finding by its severity and keeps at most maxFindings of them
+func (r *ScannerResult) AddFinding(finding *ScannerFinding, maxFindings int) {
+	r.Total++
+	switch finding.Severity {
+	case ScannerSeverityCritical:
+		r.Critical++
+	case ScannerSeverityHigh:
+		r.High++
+	case ScannerSeverityMedium:
+		r.Medium++
+	case ScannerSeverityLow:
+		r.Low++
+	default:
+		r.Info++
+	}
+	if len(r.Findings) < maxFindings {
+		r.Findings = append(r.Findings, finding)
+	}
+}
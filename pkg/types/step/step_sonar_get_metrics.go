@@ -26,6 +26,19 @@ type StepSonarGetMetricsSpec struct {
 	CheckDir         string        `bson:"check_dir"          json:"check_dir"          yaml:"check_dir"`
 	CheckQualityGate bool          `bson:"check_quality_gate" json:"check_quality_gate" yaml:"check_quality_gate"`
 	SonarMetrics     *SonarMetrics `bson:"sonar_metrics"      json:"sonar_metrics"      yaml:"sonar_metrics"`
+	// PullRequest is set when the scanning runs for a pull request, the quality gate result is commented back to it
+	PullRequest *SonarPullRequest `bson:"pull_request,omitempty" json:"pull_request,omitempty" yaml:"pull_request,omitempty"`
+	// ScanningName is used to save the metrics for the scanning trend
+	ScanningName string `bson:"scanning_name" json:"scanning_name" yaml:"scanning_name"`
+}
+
+type SonarPullRequest struct {
+	CodehostID    int    `bson:"codehost_id"    json:"codehost_id"    yaml:"codehost_id"`
+	RepoNamespace string `bson:"repo_namespace" json:"repo_namespace" yaml:"repo_namespace"`
+	RepoName      string `bson:"repo_name"      json:"repo_name"      yaml:"repo_name"`
+	ID            int    `bson:"id"             json:"id"             yaml:"id"`
+	Branch        string `bson:"branch"         json:"branch"         yaml:"branch"`
+	Base          string `bson:"base"           json:"base"           yaml:"base"`
 }
 
 type SonarMetrics struct {
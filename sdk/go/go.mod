@@ -0,0 +1,3 @@
+module github.com/koderover/zadig/sdk/go
+
+go 1.21
@@ -0,0 +1,3498 @@
+// Code generated by apispec. DO NOT EDIT.
+
+// Package zadig is the client of the Zadig OpenAPI dev.
+package zadig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the Zadig OpenAPI with an API token.
+type Client struct {
+	// Address is the address of Zadig, for example: https://zadig.example.com
+	Address    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+func NewClient(address, token string) *Client {
+	return &Client{
+		Address:    strings.TrimSuffix(address, "/"),
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Error is returned if the status code of the response is not 2xx.
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("zadig openapi returns %d: %s", e.StatusCode, e.Body)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	u := c.Address + path
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &Error{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+type Components struct {
+	Schemas         map[string]interface{}     `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type Diff struct {
+	Added   []string `json:"added,omitempty"`
+	Base    string   `json:"base,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Target  string   `json:"target,omitempty"`
+}
+
+type Document struct {
+	Components *Components                      `json:"components,omitempty"`
+	Info       map[string]interface{}           `json:"info,omitempty"`
+	Openapi    string                           `json:"openapi,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths,omitempty"`
+	Security   []map[string][]string            `json:"security,omitempty"`
+}
+
+type MediaType struct {
+	Schema interface{} `json:"schema,omitempty"`
+}
+
+type Operation struct {
+	Description string               `json:"description,omitempty"`
+	OperationId string               `json:"operationId,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+}
+
+type Parameter struct {
+	Description string                 `json:"description,omitempty"`
+	In          string                 `json:"in,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+}
+
+type Response struct {
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Description string                `json:"description,omitempty"`
+}
+
+type SecurityScheme struct {
+	Scheme string `json:"scheme,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+type ApprovalDelegateType string
+
+type ConfigApprovalType string
+
+type ApproveOrReject string
+
+type CommonEnvCfgType string
+
+type HookEventType string
+
+type ConfigJobErrorPolicy string
+
+type JobRetryBackoff string
+
+type JobRunPolicy string
+
+type JobType string
+
+type ReleasePlanJobStatus string
+
+type ReleasePlanJobType string
+
+type ReleasePlanStatus string
+
+type WorkflowParamType string
+
+type Status string
+
+type ServiceModule struct {
+	ServiceModule string `json:"service_module,omitempty"`
+	ServiceName   string `json:"service_name,omitempty"`
+}
+
+type Workflow struct {
+	DisplayName string `json:"display_name,omitempty"`
+	Name        string `json:"name,omitempty"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	// Yaml is the definition of the custom workflow, the same as the one used in the workflow editor
+	YAML string `json:"yaml,omitempty"`
+}
+
+type DailyTestStat struct {
+	Date         string `json:"date,omitempty"`
+	FailedCount  int64  `json:"failed_count,omitempty"`
+	SuccessCount int64  `json:"success_count,omitempty"`
+	TimeoutCount int64  `json:"timeout_count,omitempty"`
+}
+
+type OpenAPIGetServiceResponse struct {
+	CronJobs         []*CronJob  `json:"cron_jobs,omitempty"`
+	EnvName          string      `json:"env_name,omitempty"`
+	GroupName        string      `json:"group_name,omitempty"`
+	Ingress          []*Ingress  `json:"ingress,omitempty"`
+	Namespace        string      `json:"namespace,omitempty"`
+	ProductName      string      `json:"product_name,omitempty"`
+	Scales           []*Workload `json:"scales,omitempty"`
+	ServiceEndpoints []*Service  `json:"service_endpoints,omitempty"`
+	ServiceName      string      `json:"service_name,omitempty"`
+}
+
+type OpenAPIListKubeEventResponse struct {
+	// The number of times this event has occurred.
+	Count int64 `json:"count,omitempty"`
+	// The time at which the event was first recorded. (Time of server receipt is in TypeMeta.)
+	FirstSeen int64 `json:"first_seen,omitempty"`
+	// The time at which the most recent occurrence of this event was recorded.
+	LastSeen int64 `json:"last_seen,omitempty"`
+	// A human-readable description of the status of this operation.
+	Message string `json:"message,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	// Type of this event (Normal, Warning), new types could be added in the future
+	Type string `json:"type,omitempty"`
+}
+
+type OpenAPISetPortalServiceRequest struct {
+	Host         string `json:"host,omitempty"`
+	PortNumber   int64  `json:"port_number,omitempty"`
+	PortProtocol string `json:"port_protocol,omitempty"`
+}
+
+type OpenAPIShareEnvReadyChecks struct {
+	NamespaceHasIstioLabel  bool `json:"namespace_has_istio_label,omitempty"`
+	PodsHaveIstioProxy      bool `json:"pods_have_istio_proxy,omitempty"`
+	VirtualserviceDeployed  bool `json:"virtualservice_deployed,omitempty"`
+	WorkloadsHaveK8sService bool `json:"workloads_have_k8s_service,omitempty"`
+	WorkloadsReady          bool `json:"workloads_ready,omitempty"`
+}
+
+type OpenAPIShareEnvReadyResponse struct {
+	Checks  *OpenAPIShareEnvReadyChecks `json:"checks,omitempty"`
+	IsReady bool                        `json:"is_ready,omitempty"`
+}
+
+type OpenAPITestStatResp struct {
+	AverageRuntime int64            `json:"average_runtime,omitempty"`
+	CaseCount      int64            `json:"case_count,omitempty"`
+	Data           []*DailyTestStat `json:"data,omitempty"`
+	ExecCount      int64            `json:"exec_count,omitempty"`
+	SuccessCount   int64            `json:"success_count,omitempty"`
+}
+
+type OpenAPIUpdateContainerImageArgs struct {
+	ContainerName string `json:"container_name,omitempty"`
+	EnvName       string `json:"env_name,omitempty"`
+	Image         string `json:"image,omitempty"`
+	Name          string `json:"name,omitempty"`
+	ProductName   string `json:"product_name,omitempty"`
+	ServiceName   string `json:"service_name,omitempty"`
+	Type          string `json:"type,omitempty"`
+}
+
+type GetworkflowTaskReq struct {
+	TaskID      int64  `json:"task_id,omitempty"`
+	WorkflowKey string `json:"workflow_key,omitempty"`
+}
+
+type ProjectResp struct {
+	Projects []string `json:"projects,omitempty"`
+	Total    int64    `json:"total,omitempty"`
+}
+
+type ApproveType string
+
+type Approval struct {
+	Description      string             `json:"description,omitempty"`
+	DingtalkApproval *DingTalkApproval  `json:"dingtalk_approval,omitempty"`
+	Enabled          bool               `json:"enabled,omitempty"`
+	EndTime          int64              `json:"end_time,omitempty"`
+	LarkApproval     *LarkApproval      `json:"lark_approval,omitempty"`
+	NativeApproval   *NativeApproval    `json:"native_approval,omitempty"`
+	StartTime        int64              `json:"start_time,omitempty"`
+	Status           Status             `json:"status,omitempty"`
+	Type             ConfigApprovalType `json:"type,omitempty"`
+	WorkwxApproval   *WorkWXApproval    `json:"workwx_approval,omitempty"`
+}
+
+type ApprovalDelegate struct {
+	DelegationID string `json:"delegation_id,omitempty"`
+	ID           string `json:"id,omitempty"`
+	// MemberIDs are the users who can approve on behalf of the original approver
+	MemberIDs []string `json:"member_ids,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	// OperatorID and OperatorName are the user who made the decision on behalf of the original approver
+	OperatorID   string               `json:"operator_id,omitempty"`
+	OperatorName string               `json:"operator_name,omitempty"`
+	RerouteTime  int64                `json:"reroute_time,omitempty"`
+	Type         ApprovalDelegateType `json:"type,omitempty"`
+}
+
+type ApprovalQuorumRule struct {
+	// Approved is the number of the members who have approved so far
+	Approved  int64  `json:"approved,omitempty"`
+	GroupID   string `json:"group_id,omitempty"`
+	GroupName string `json:"group_name,omitempty"`
+	// MemberIDs are resolved from the group when the approval starts
+	MemberIDs    []string `json:"member_ids,omitempty"`
+	MinApprovers int64    `json:"min_approvers,omitempty"`
+}
+
+type ModelsContainer struct {
+	Image     string         `json:"image,omitempty"`
+	ImagePath *ImagePathSpec `json:"imagePath,omitempty"`
+	ImageName string         `json:"image_name,omitempty"`
+	Name      string         `json:"name,omitempty"`
+}
+
+type CreateFromRepo struct {
+	GitRepoConfig *TemplateGitRepoConfig `json:"git_repo_config,omitempty"`
+	LoadPath      string                 `json:"load_path,omitempty"`
+}
+
+type DingTalkApproval struct {
+	// ID: dintalk im app mongodb id
+	ApprovalID    string                  `json:"approval_id,omitempty"`
+	ApprovalNodes []*DingTalkApprovalNode `json:"approval_nodes,omitempty"`
+	// DefaultApprovalInitiator if not set, use workflow task creator as approval initiator
+	DefaultApprovalInitiator *DingTalkApprovalUser `json:"default_approval_initiator,omitempty"`
+	// InstanceCode: dingtalk approval instance code
+	InstanceCode string `json:"instance_code,omitempty"`
+	Timeout      int64  `json:"timeout,omitempty"`
+}
+
+type DingTalkApprovalNode struct {
+	ApproveUsers    []*DingTalkApprovalUser `json:"approve_users,omitempty"`
+	RejectOrApprove ApproveOrReject         `json:"reject_or_approve,omitempty"`
+	Type            string                  `json:"type,omitempty"`
+}
+
+type DingTalkApprovalUser struct {
+	Avatar          string          `json:"avatar,omitempty"`
+	Comment         string          `json:"comment,omitempty"`
+	ID              string          `json:"id,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	OperationTime   int64           `json:"operation_time,omitempty"`
+	RejectOrApprove ApproveOrReject `json:"reject_or_approve,omitempty"`
+}
+
+type DockerBuild struct {
+	// Backend is the tool to build the image, docker is used if not set
+	Backend DockerBuildBackend `json:"backend,omitempty"`
+	// BuildArgs docker build args
+	BuildArgs string `json:"build_args,omitempty"`
+	// DockerFile name, default is Dockerfile
+	DockerFile string `json:"docker_file,omitempty"`
+	// MultiArchStrategy is used by the docker backend when platforms are set, buildx is used if not set
+	MultiArchStrategy MultiArchStrategy `json:"multi_arch_strategy,omitempty"`
+	// Platforms are the platforms of the multi-arch image like linux/amd64 and linux/arm64, the image is built for the platform of the node if not set
+	Platforms []string `json:"platforms,omitempty"`
+	// Source whether dockerfile comes from template or existing file
+	Source string `json:"source,omitempty"`
+	// TemplateId is the id of the template dockerfile
+	TemplateID string `json:"template_id,omitempty"`
+	// TemplateName is the name of the template dockerfile
+	TemplateName string `json:"template_name,omitempty"`
+	// TemplateVersion pins the dockerfile template to a specific version, 0 means always using the latest one
+	TemplateVersion int64 `json:"template_version,omitempty"`
+	// WorkDir docker run path
+	WorkDir string `json:"work_dir,omitempty"`
+}
+
+type ModelsError struct {
+	Message string `json:"message,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type Failure struct {
+	Message string `json:"message,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type FileArchive struct {
+	// ArtifactRepositoryID is the nexus/artifactory repository the package is pushed to, besides the object storage
+	ArtifactRepositoryID string `json:"artifact_repository_id,omitempty"`
+	FileLocation         string `json:"file_location,omitempty"`
+}
+
+type ImagePathSpec struct {
+	Image     string `json:"image,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Repo      string `json:"repo,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+}
+
+type Item struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type Job struct {
+	ErrorPolicy *ModelsJobErrorPolicy `json:"error_policy,omitempty"`
+	Name        string                `json:"name,omitempty"`
+	// NotifyCtls are sent when the job finishes, in addition to the workflow notifications, only build, deploy and scanning jobs support it.
+	NotifyCtls []*NotifyCtl `json:"notify_ctls,omitempty"`
+	// RetryPolicy retries the failed job automatically before the error policy is applied.
+	RetryPolicy    *JobRetryPolicy          `json:"retry_policy,omitempty"`
+	RunPolicy      JobRunPolicy             `json:"run_policy,omitempty"`
+	ServiceModules []*WorkflowServiceModule `json:"service_modules,omitempty"`
+	// only for webhook workflow args to skip some tasks.
+	Skipped bool        `json:"skipped,omitempty"`
+	Spec    interface{} `json:"spec,omitempty"`
+	// Timeout is the maximum minutes of every run of the job, 0 means the job is only limited by its own spec.
+	Timeout int64   `json:"timeout,omitempty"`
+	Type    JobType `json:"type,omitempty"`
+}
+
+type ModelsJobErrorPolicy struct {
+	ApprovalUsers []*User              `json:"approval_users,omitempty"`
+	MaximumRetry  int64                `json:"maximum_retry,omitempty"`
+	Policy        ConfigJobErrorPolicy `json:"policy,omitempty"`
+}
+
+type JobRetryPolicy struct {
+	Backoff JobRetryBackoff `json:"backoff,omitempty"`
+	// Interval is the seconds to wait before the first retry, it is doubled for every retry with exponential backoff.
+	Interval int64 `json:"interval,omitempty"`
+	// MaxAttempts is the maximum number of retries after the first run.
+	MaxAttempts int64 `json:"max_attempts,omitempty"`
+	// RetryOn is the job statuses to retry on, failed and timeout are retried if it is empty.
+	RetryOn []Status `json:"retry_on,omitempty"`
+}
+
+type KeyVal struct {
+	ChoiceOption []string             `json:"choice_option,omitempty"`
+	IsCredential bool                 `json:"is_credential,omitempty"`
+	Key          string               `json:"key,omitempty"`
+	Type         ParameterSettingType `json:"type,omitempty"`
+	Value        string               `json:"value,omitempty"`
+}
+
+type LarkApproval struct {
+	// ID: lark im app mongodb id
+	ApprovalID    string              `json:"approval_id,omitempty"`
+	ApprovalNodes []*LarkApprovalNode `json:"approval_nodes,omitempty"`
+	// Deprecated: use ApprovalNodes instead
+	ApproveUsers []*LarkApprovalUser `json:"approve_users,omitempty"`
+	// DefaultApprovalInitiator if not set, use workflow task creator as approval initiator
+	DefaultApprovalInitiator *LarkApprovalUser `json:"default_approval_initiator,omitempty"`
+	// InstanceCode: lark approval instance code
+	InstanceCode string `json:"instance_code,omitempty"`
+	Timeout      int64  `json:"timeout,omitempty"`
+}
+
+type LarkApprovalNode struct {
+	ApproveUsers    []*LarkApprovalUser `json:"approve_users,omitempty"`
+	RejectOrApprove ApproveOrReject     `json:"reject_or_approve,omitempty"`
+	Type            ApproveType         `json:"type,omitempty"`
+}
+
+type LarkApprovalUser struct {
+	Avatar          string          `json:"avatar,omitempty"`
+	Comment         string          `json:"comment,omitempty"`
+	ID              string          `json:"id,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	OperationTime   int64           `json:"operation_time,omitempty"`
+	RejectOrApprove ApproveOrReject `json:"reject_or_approve,omitempty"`
+}
+
+type ManualExec struct {
+	Enabled           bool    `json:"enabled,omitempty"`
+	Excuted           bool    `json:"excuted,omitempty"`
+	ManualExecUsers   []*User `json:"manual_exec_users,omitempty"`
+	ManualExectorID   string  `json:"manual_exector_id,omitempty"`
+	ManualExectorName string  `json:"manual_exector_name,omitempty"`
+	ModifyParams      bool    `json:"modify_params,omitempty"`
+}
+
+type NativeApproval struct {
+	ApproveUsers []*User `json:"approve_users,omitempty"`
+	// CurrentStep is the index of the step waiting for approval, it equals len(Steps) after all the steps are passed
+	CurrentStep      int64   `json:"current_step,omitempty"`
+	FlatApproveUsers []*User `json:"flat_approve_users,omitempty"`
+	// InstanceCode: native approval instance code, save for working after restart aslan
+	InstanceCode    string `json:"instance_code,omitempty"`
+	NeededApprovers int64  `json:"needed_approvers,omitempty"`
+	// QuorumRules must all be met besides NeededApprovers to pass the approval, e.g. at least one approver from the security group
+	QuorumRules     []*ApprovalQuorumRule `json:"quorum_rules,omitempty"`
+	RejectOrApprove ApproveOrReject       `json:"reject_or_approve,omitempty"`
+	// Steps is an ordered approval chain, a step is open for approval only after all the previous steps are passed. ApproveUsers, NeededApprovers and QuorumRules above are ignored if steps are set.
+	Steps   []*NativeApprovalStep `json:"steps,omitempty"`
+	Timeout int64                 `json:"timeout,omitempty"`
+}
+
+type NativeApprovalStep struct {
+	ApproveUsers    []*User               `json:"approve_users,omitempty"`
+	Name            string                `json:"name,omitempty"`
+	NeededApprovers int64                 `json:"needed_approvers,omitempty"`
+	QuorumRules     []*ApprovalQuorumRule `json:"quorum_rules,omitempty"`
+	RejectOrApprove ApproveOrReject       `json:"reject_or_approve,omitempty"`
+}
+
+type NotifyCtl struct {
+	AtMobiles        []string          `json:"at_mobiles,omitempty"`
+	DingdingWebhook  string            `json:"dingding_webhook,omitempty"`
+	Enabled          bool              `json:"enabled,omitempty"`
+	FeishuWebhook    string            `json:"feishu_webhook,omitempty"`
+	IsAtAll          bool              `json:"is_at_all,omitempty"`
+	LarkUserIDs      []string          `json:"lark_user_ids,omitempty"`
+	MailUsers        []*User           `json:"mail_users,omitempty"`
+	NotifyType       []string          `json:"notify_type,omitempty"`
+	TelegramBotToken string            `json:"telegram_bot_token,omitempty"`
+	TelegramChatID   string            `json:"telegram_chat_id,omitempty"`
+	WeChatWebHook    string            `json:"weChat_webHook,omitempty"`
+	WebhookNotify    *WebhookNotify    `json:"webhook_notify,omitempty"`
+	WebhookType      NotifyWebHookType `json:"webhook_type,omitempty"`
+	WechatUserIDs    []string          `json:"wechat_user_ids,omitempty"`
+}
+
+type ObjectStorageUpload struct {
+	Enabled         bool                       `json:"enabled,omitempty"`
+	ObjectStorageID string                     `json:"object_storage_id,omitempty"`
+	UploadDetail    []*ObjectStoragePathDetail `json:"upload_detail,omitempty"`
+}
+
+type OpenAPISpecSnapshot struct {
+	CreateTime int64 `json:"create_time,omitempty"`
+	// Digest is the sha256 of the spec, it changes if the routes or the annotations change in the same version
+	Digest     string `json:"digest,omitempty"`
+	ID         string `json:"id,omitempty"`
+	UpdateTime int64  `json:"update_time,omitempty"`
+	Version    string `json:"version,omitempty"`
+}
+
+type Output struct {
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type Param struct {
+	ChoiceOption []string    `json:"choice_option,omitempty"`
+	Default      string      `json:"default,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	IsCredential bool        `json:"is_credential,omitempty"`
+	Name         string      `json:"name,omitempty"`
+	Repo         *Repository `json:"repo,omitempty"`
+	Source       string      `json:"source,omitempty"`
+	// support string/text/choice/repo type
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+type ParameterSettingType string
+
+type PostBuild struct {
+	DockerBuild         *DockerBuild         `json:"docker_build,omitempty"`
+	FileArchive         *FileArchive         `json:"file_archive,omitempty"`
+	ObjectStorageUpload *ObjectStorageUpload `json:"object_storage_upload,omitempty"`
+	Scripts             string               `json:"scripts,omitempty"`
+}
+
+type ReleaseJob struct {
+	// DependsOn are the ids of the jobs in the same plan which should be finished before this job starts
+	DependsOn    []string `json:"depends_on,omitempty"`
+	ExecutedBy   string   `json:"executed_by,omitempty"`
+	ExecutedTime int64    `json:"executed_time,omitempty"`
+	ID           string   `json:"id,omitempty"`
+	// ReleasePlan can return to PlanningStatus when some release jobs have been executed So we need to record the last status of the release job
+	LastStatus     ReleasePlanJobStatus `json:"last_status,omitempty"`
+	Name           string               `json:"name,omitempty"`
+	PlannedEndTime int64                `json:"planned_end_time,omitempty"`
+	// PlannedStartTime and PlannedEndTime are the planned time window of the job, 0 if not planned
+	PlannedStartTime int64                `json:"planned_start_time,omitempty"`
+	Spec             interface{}          `json:"spec,omitempty"`
+	Status           ReleasePlanJobStatus `json:"status,omitempty"`
+	Type             ReleasePlanJobType   `json:"type,omitempty"`
+	// Updated is used to indicate whether the release job has been updated
+	Updated bool `json:"updated,omitempty"`
+}
+
+type ReleasePlan struct {
+	Approval              *Approval                         `json:"approval,omitempty"`
+	ApprovalTime          int64                             `json:"approval_time,omitempty"`
+	CreateTime            int64                             `json:"create_time,omitempty"`
+	CreatedBy             string                            `json:"created_by,omitempty"`
+	Description           string                            `json:"description,omitempty"`
+	EndTime               int64                             `json:"end_time,omitempty"`
+	ExecutingTime         int64                             `json:"executing_time,omitempty"`
+	ID                    string                            `json:"id,omitempty"`
+	Index                 int64                             `json:"index,omitempty"`
+	JiraSprintAssociation *ReleasePlanJiraSprintAssociation `json:"jira_sprint_association,omitempty"`
+	Jobs                  []*ReleaseJob                     `json:"jobs,omitempty"`
+	Manager               string                            `json:"manager,omitempty"`
+	// ManagerID is the user id of the manager
+	ManagerID           string            `json:"manager_id,omitempty"`
+	Name                string            `json:"name,omitempty"`
+	PlanningTime        int64             `json:"planning_time,omitempty"`
+	ScheduleExecuteTime int64             `json:"schedule_execute_time,omitempty"`
+	StartTime           int64             `json:"start_time,omitempty"`
+	Status              ReleasePlanStatus `json:"status,omitempty"`
+	SuccessTime         int64             `json:"success_time,omitempty"`
+	UpdateTime          int64             `json:"update_time,omitempty"`
+	UpdatedBy           string            `json:"updated_by,omitempty"`
+}
+
+type ReleasePlanJiraSprint struct {
+	BoardID     int64  `json:"board_id,omitempty"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+	SprintID    int64  `json:"sprint_id,omitempty"`
+	SprintName  string `json:"sprint_name,omitempty"`
+}
+
+type ReleasePlanJiraSprintAssociation struct {
+	JiraID  string                   `json:"jira_id,omitempty"`
+	Sprints []*ReleasePlanJiraSprint `json:"sprints,omitempty"`
+}
+
+type RiskFactor struct {
+	Detail   string `json:"detail,omitempty"`
+	MaxScore int64  `json:"max_score,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Score    int64  `json:"score,omitempty"`
+}
+
+type ServiceKeyVal struct {
+	ChoiceOption []string             `json:"choice_option,omitempty"`
+	IsCredential bool                 `json:"is_credential,omitempty"`
+	Key          string               `json:"key,omitempty"`
+	Type         ParameterSettingType `json:"type,omitempty"`
+	Value        interface{}          `json:"value,omitempty"`
+}
+
+type ShareStorage struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type StagePause struct {
+	Enabled       bool   `json:"enabled,omitempty"`
+	ResumeTime    int64  `json:"resume_time,omitempty"`
+	Resumed       bool   `json:"resumed,omitempty"`
+	ResumedByID   string `json:"resumed_by_id,omitempty"`
+	ResumedByName string `json:"resumed_by_name,omitempty"`
+	// Timeout is in minutes, the task times out if it's not resumed in time, 0 means the default 60 minutes
+	Timeout int64 `json:"timeout,omitempty"`
+	Waiting bool  `json:"waiting,omitempty"`
+}
+
+type TaskRisk struct {
+	// Escalated is true if the task is routed to the high risk approval of the workflow
+	Escalated bool          `json:"escalated,omitempty"`
+	Factors   []*RiskFactor `json:"factors,omitempty"`
+	Level     TaskRiskLevel `json:"level,omitempty"`
+	Score     int64         `json:"score,omitempty"`
+}
+
+type TaskRiskLevel string
+
+type User struct {
+	Comment string `json:"comment,omitempty"`
+	// DelegatedTo is set when the pending approval of the user is rerouted to the delegate of the user
+	DelegatedTo     *ApprovalDelegate `json:"delegated_to,omitempty"`
+	GroupID         string            `json:"group_id,omitempty"`
+	GroupName       string            `json:"group_name,omitempty"`
+	OperationTime   int64             `json:"operation_time,omitempty"`
+	RejectOrApprove ApproveOrReject   `json:"reject_or_approve,omitempty"`
+	Type            string            `json:"type,omitempty"`
+	UserID          string            `json:"user_id,omitempty"`
+	UserName        string            `json:"user_name,omitempty"`
+}
+
+type WebhookNotify struct {
+	Address string `json:"address,omitempty"`
+	Token   string `json:"token,omitempty"`
+}
+
+type WeeklyDeployStat struct {
+	CreateTime int64  `json:"create_time,omitempty"`
+	Date       string `json:"date,omitempty"`
+	Failed     int64  `json:"failed,omitempty"`
+	Production bool   `json:"production,omitempty"`
+	ProjectKey string `json:"project_key,omitempty"`
+	Success    int64  `json:"success,omitempty"`
+	Timeout    int64  `json:"timeout,omitempty"`
+	UpdateTime int64  `json:"update_time,omitempty"`
+}
+
+type WorkWXApproval struct {
+	// ID: workwx im app mongodb id
+	ApprovalID          string          `json:"approval_id,omitempty"`
+	ApprovalNodeDetails []*ApprovalNode `json:"approval_node_details,omitempty"`
+	ApprovalNodes       []*ApprovalNode `json:"approval_nodes,omitempty"`
+	CreatorUser         *ApprovalUser   `json:"creator_user,omitempty"`
+	InstanceID          string          `json:"instance_id,omitempty"`
+	Timeout             int64           `json:"timeout,omitempty"`
+}
+
+type WorkflowServiceModule struct {
+	CodeInfo      []*Repository `json:"code_info,omitempty"`
+	ServiceModule string        `json:"service_module,omitempty"`
+	ServiceName   string        `json:"service_name,omitempty"`
+}
+
+type Backend struct {
+	ServiceName string `json:"service_name,omitempty"`
+	ServicePort string `json:"service_port,omitempty"`
+}
+
+type ResourceContainer struct {
+	// Time at which the container last terminated
+	FinishedAt int64  `json:"finished_at,omitempty"`
+	Image      string `json:"image,omitempty"`
+	// Message regarding the last termination of the container
+	Message string           `json:"message,omitempty"`
+	Name    string           `json:"name,omitempty"`
+	Ports   []*ContainerPort `json:"ports,omitempty"`
+	Ready   bool             `json:"ready,omitempty"`
+	// reason from the last termination of the container
+	Reason       string `json:"reason,omitempty"`
+	RestartCount int64  `json:"restart_count,omitempty"`
+	// Time at which previous execution of the container started
+	StartedAt int64  `json:"started_at,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+type ContainerImage struct {
+	Image     string `json:"image,omitempty"`
+	ImageName string `json:"image_name,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+type ContainerPort struct {
+	// Number of port to expose on the pod's IP address. This must be a valid port number, 0 < x < 65536.
+	ContainerPort int64 `json:"containerPort,omitempty"`
+	// What host IP to bind the external port to. +optional
+	HostIP string `json:"hostIP,omitempty"`
+	// Number of port to expose on the host. If specified, this must be a valid port number, 0 < x < 65536. If HostNetwork is specified, this must match ContainerPort. Most containers do not need this. +optional
+	HostPort int64 `json:"hostPort,omitempty"`
+	// If specified, this must be an IANA_SVC_NAME and unique within the pod. Each named port in a pod must have a unique name. Name for the port that can be referred to by services. +optional
+	Name string `json:"name,omitempty"`
+	// Protocol for port. Must be UDP, TCP, or SCTP. Defaults to "TCP". +optional +default="TCP"
+	Protocol Protocol `json:"protocol,omitempty"`
+}
+
+type CronJob struct {
+	Active       int64             `json:"active,omitempty"`
+	CreateTime   int64             `json:"create_time,omitempty"`
+	Images       []*ContainerImage `json:"images,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	LastSchedule string            `json:"last_schedule,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Pods         []*Pod            `json:"pods,omitempty"`
+	Schedule     string            `json:"schedule,omitempty"`
+	Suspend      bool              `json:"suspend,omitempty"`
+}
+
+type HostInfo struct {
+	Backend []*Backend `json:"backend,omitempty"`
+	Host    string     `json:"host,omitempty"`
+}
+
+type Ingress struct {
+	Age      string            `json:"age,omitempty"`
+	HostInfo []*HostInfo       `json:"host_info,omitempty"`
+	Ips      []string          `json:"ips,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Name     string            `json:"name,omitempty"`
+}
+
+type Pod struct {
+	Age                  string               `json:"age,omitempty"`
+	Containers           []*ResourceContainer `json:"containers,omitempty"`
+	ContainersMessage    string               `json:"containers_message,omitempty"`
+	ContainersReady      bool                 `json:"containers_ready,omitempty"`
+	Createtime           int64                `json:"createtime,omitempty"`
+	EnableDebugContainer bool                 `json:"enable_debug_container,omitempty"`
+	HostIP               string               `json:"host_ip,omitempty"`
+	IP                   string               `json:"ip,omitempty"`
+	Kind                 string               `json:"kind,omitempty"`
+	Labels               map[string]string    `json:"labels,omitempty"`
+	Name                 string               `json:"name,omitempty"`
+	NodeName             string               `json:"node_name,omitempty"`
+	PodReady             bool                 `json:"pod_ready,omitempty"`
+	Status               string               `json:"status,omitempty"`
+}
+
+type Protocol string
+
+type Service struct {
+	Age       string            `json:"age,omitempty"`
+	Endpoints []*ServicePort    `json:"endpoints,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Name      string            `json:"name,omitempty"`
+}
+
+type ServicePort struct {
+	NodePort    int64  `json:"node_port,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	ServicePort int64  `json:"service_port,omitempty"`
+}
+
+type Workload struct {
+	Images           []*ContainerImage `json:"images,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	Pods             []*Pod            `json:"pods,omitempty"`
+	Replicas         int64             `json:"replicas,omitempty"`
+	Type             string            `json:"type,omitempty"`
+	ZadigxReleaseTag string            `json:"zadigx_release_tag,omitempty"`
+	// ZadigXReleaseType represent the release type of workload created by zadigx when it is not empty frontend should limit or allow some operations on these workloads
+	ZadigxReleaseType string `json:"zadigx_release_type,omitempty"`
+}
+
+type CfgRepoInfo struct {
+	GitRepoConfig *ServiceGitRepoConfig `json:"git_repo_config,omitempty"`
+	LoadPath      string                `json:"load_path,omitempty"`
+}
+
+type Cluster struct {
+	ClusterID   string `json:"cluster_id,omitempty"`
+	Description string `json:"description,omitempty"`
+	// KubeConfig is write only, it's never returned
+	KubeConfig   string   `json:"kube_config,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Production   bool     `json:"production,omitempty"`
+	ProjectNames []string `json:"project_names,omitempty"`
+	Provider     int64    `json:"provider,omitempty"`
+	Type         string   `json:"type,omitempty"`
+}
+
+type ContainerBrief struct {
+	Image     string `json:"image,omitempty"`
+	ImageName string `json:"image_name,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+type DailyStat struct {
+	Date         string `json:"date,omitempty"`
+	FailCount    int64  `json:"fail_count,omitempty"`
+	SuccessCount int64  `json:"success_count,omitempty"`
+	Total        int64  `json:"total,omitempty"`
+}
+
+type DeployDashboard struct {
+	Data    []*WeeklyDeployStat `json:"data,omitempty"`
+	Success int64               `json:"success,omitempty"`
+	Total   int64               `json:"total,omitempty"`
+}
+
+type EnvBasicInfoArgs struct {
+	EnvName    string `json:"env_name,omitempty"`
+	RegistryID string `json:"registry_id,omitempty"`
+}
+
+type EnvCfgArgs struct {
+	AutoSync         bool                   `json:"auto_sync,omitempty"`
+	CommonEnvCfgType string                 `json:"common_env_cfg_type,omitempty"`
+	GitRepoConfig    *TemplateGitRepoConfig `json:"git_repo_config,omitempty"`
+	Name             string                 `json:"name,omitempty"`
+	YAMLData         string                 `json:"yaml_data,omitempty"`
+}
+
+type EnvDefinition struct {
+	ClusterName string `json:"cluster_name,omitempty"`
+	EnvKey      string `json:"env_key,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+}
+
+type Environment struct {
+	// Alias can only be set for production environments
+	Alias      string `json:"alias,omitempty"`
+	ClusterID  string `json:"cluster_id,omitempty"`
+	EnvName    string `json:"env_name,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Production bool   `json:"production,omitempty"`
+	ProjectKey string `json:"project_key,omitempty"`
+	RegistryID string `json:"registry_id,omitempty"`
+}
+
+type ServiceGitRepoConfig struct {
+	Branch      string   `json:"branch,omitempty"`
+	CodehostKey string   `json:"codehost_key,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Repo        string   `json:"repo,omitempty"`
+	ValuesPaths []string `json:"values_paths,omitempty"`
+}
+
+type KVPair struct {
+	Key   string      `json:"key,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type OpenAPIApplyYamlServiceReq struct {
+	EnvKey      string               `json:"env_key,omitempty"`
+	ServiceList []*YamlServiceWithKV `json:"service_list,omitempty"`
+}
+
+type OpenAPIBuildBrief struct {
+	Name           string           `json:"name,omitempty"`
+	ProjectKey     string           `json:"project_key,omitempty"`
+	Source         string           `json:"source,omitempty"`
+	TargetServices []*ServiceModule `json:"target_services,omitempty"`
+	UpdateBy       string           `json:"update_by,omitempty"`
+	UpdateTime     int64            `json:"update_time,omitempty"`
+}
+
+type OpenAPIBuildCreationFromTemplateReq struct {
+	Name           string                     `json:"name,omitempty"`
+	ProjectKey     string                     `json:"project_key,omitempty"`
+	TargetServices []*OpenAPIServiceBuildArgs `json:"target_services,omitempty"`
+	TemplateName   string                     `json:"template_name,omitempty"`
+}
+
+type OpenAPIBuildDetailResp struct {
+	AdvancedSettings *OpenAPIAdvancedSetting `json:"advanced_settings,omitempty"`
+	BuildEnv         *OpenAPIBuildEnv        `json:"build_env,omitempty"`
+	BuildScript      string                  `json:"build_script,omitempty"`
+	Name             string                  `json:"name,omitempty"`
+	Outputs          []*Output               `json:"outputs,omitempty"`
+	Parameters       []*ServiceKeyVal        `json:"parameters,omitempty"`
+	PostBuild        *PostBuild              `json:"post_build,omitempty"`
+	ProjectKey       string                  `json:"project_key,omitempty"`
+	Repos            []*OpenAPIRepo          `json:"repos,omitempty"`
+	Source           string                  `json:"source,omitempty"`
+	TargetServices   []*ServiceModule        `json:"target_services,omitempty"`
+	TemplateName     string                  `json:"template_name,omitempty"`
+	UpdateBy         string                  `json:"update_by,omitempty"`
+	UpdateTime       int64                   `json:"update_time,omitempty"`
+}
+
+type OpenAPIBuildEnv struct {
+	BasicImageID    string  `json:"basic_image_id,omitempty"`
+	BasicImageLabel string  `json:"basic_image_label,omitempty"`
+	Installs        []*Item `json:"installs,omitempty"`
+}
+
+type OpenAPIBuildListResp struct {
+	Builds []*OpenAPIBuildBrief `json:"builds,omitempty"`
+	Total  int64                `json:"total,omitempty"`
+}
+
+type OpenAPICluster struct {
+	ClusterID    string   `json:"cluster_id,omitempty"`
+	CreatedBy    string   `json:"created_by,omitempty"`
+	CreatedTime  int64    `json:"created_time,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Local        bool     `json:"local,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Production   bool     `json:"production,omitempty"`
+	ProjectNames []string `json:"project_names,omitempty"`
+	Provider     int64    `json:"provider,omitempty"`
+	ProviderName string   `json:"provider_name,omitempty"`
+	Status       string   `json:"status,omitempty"`
+	Type         string   `json:"type,omitempty"`
+}
+
+type OpenAPICreateClusterRequest struct {
+	Description  string   `json:"description,omitempty"`
+	KubeConfig   string   `json:"kube_config,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Production   bool     `json:"production,omitempty"`
+	ProjectNames []string `json:"project_names,omitempty"`
+	Provider     int64    `json:"provider,omitempty"`
+	Type         string   `json:"type,omitempty"`
+}
+
+type OpenAPICreateClusterResponse struct {
+	AgentCmd string          `json:"agent_cmd,omitempty"`
+	Cluster  *OpenAPICluster `json:"cluster,omitempty"`
+}
+
+type OpenAPICreateEnvArgs struct {
+	ChartValues     []*ProductHelmServiceCreationInfo `json:"chart_values,omitempty"`
+	ClusterID       string                            `json:"cluster_id,omitempty"`
+	EnvConfigs      []*EnvCfgArgs                     `json:"env_configs,omitempty"`
+	EnvKey          string                            `json:"env_key,omitempty"`
+	EnvName         string                            `json:"env_name,omitempty"`
+	GlobalVariables []*GlobalVariableKV               `json:"global_variables,omitempty"`
+	Namespace       string                            `json:"namespace,omitempty"`
+	Production      bool                              `json:"production,omitempty"`
+	ProjectKey      string                            `json:"project_key,omitempty"`
+	RegistryID      string                            `json:"registry_id,omitempty"`
+	Services        []*OpenAPICreateServiceArgs       `json:"services,omitempty"`
+}
+
+type OpenAPICreateHelmDeliveryVersionChartData struct {
+	ImageDatas  []*OpenAPIDeliveryVersionImageData `json:"image_datas,omitempty"`
+	ServiceName string                             `json:"service_name,omitempty"`
+	Version     string                             `json:"version,omitempty"`
+}
+
+type OpenAPICreateHelmDeliveryVersionRequest struct {
+	ChartDatas      []*OpenAPICreateHelmDeliveryVersionChartData `json:"chart_datas,omitempty"`
+	ChartRepoName   string                                       `json:"chart_repo_name,omitempty"`
+	Desc            string                                       `json:"desc,omitempty"`
+	EnvName         string                                       `json:"env_name,omitempty"`
+	ImageRegistryID string                                       `json:"image_registry_id,omitempty"`
+	Labels          []string                                     `json:"labels,omitempty"`
+	Production      bool                                         `json:"production,omitempty"`
+	ProjectKey      string                                       `json:"project_key,omitempty"`
+	Retry           bool                                         `json:"retry,omitempty"`
+	VersionName     string                                       `json:"version_name,omitempty"`
+}
+
+type OpenAPICreateK8SDeliveryVersionRequest struct {
+	Desc            string                                     `json:"desc,omitempty"`
+	EnvName         string                                     `json:"env_name,omitempty"`
+	ImageRegistryID string                                     `json:"image_registry_id,omitempty"`
+	Labels          []string                                   `json:"labels,omitempty"`
+	Production      bool                                       `json:"production,omitempty"`
+	ProjectKey      string                                     `json:"project_key,omitempty"`
+	Retry           bool                                       `json:"retry,omitempty"`
+	VersionName     string                                     `json:"version_name,omitempty"`
+	YAMLDatas       []*OpenAPICreateK8SDeliveryVersionYamlData `json:"yaml_datas,omitempty"`
+}
+
+type OpenAPICreateK8SDeliveryVersionYamlData struct {
+	ImageDatas  []*OpenAPIDeliveryVersionImageData `json:"image_datas,omitempty"`
+	ServiceName string                             `json:"service_name,omitempty"`
+}
+
+type OpenAPICreateProductReq struct {
+	Description string `json:"description,omitempty"`
+	IsPublic    bool   `json:"is_public,omitempty"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+	ProjectType string `json:"project_type,omitempty"`
+}
+
+type OpenAPICreateRegistryReq struct {
+	AccessKey string `json:"access_key,omitempty"`
+	Address   string `json:"address,omitempty"`
+	EnableTLS bool   `json:"enable_tls,omitempty"`
+	IsDefault bool   `json:"is_default,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Provider  string `json:"provider,omitempty"`
+	// Optional field below
+	Region    string `json:"region,omitempty"`
+	SecretKey string `json:"secret_key,omitempty"`
+	TLSCert   string `json:"tls_cert,omitempty"`
+}
+
+type OpenAPICreateReleasePlanArgs struct {
+	Approval            *Approval `json:"approval,omitempty"`
+	Description         string    `json:"description,omitempty"`
+	EndTime             int64     `json:"end_time,omitempty"`
+	Manager             string    `json:"manager,omitempty"`
+	ManagerIdentityType string    `json:"manager_identity_type,omitempty"`
+	Name                string    `json:"name,omitempty"`
+	StartTime           int64     `json:"start_time,omitempty"`
+}
+
+type OpenAPICreateScanningReq struct {
+	// FIMXE: currently only one sonar system is required, so we just fill in the default sonar ID.
+	Addons            []*Item                 `json:"addons,omitempty"`
+	AdvancedSettings  *OpenAPIAdvancedSetting `json:"advanced_settings,omitempty"`
+	Description       string                  `json:"description,omitempty"`
+	EnableQualityGate bool                    `json:"enable_quality_gate,omitempty"`
+	ImageName         string                  `json:"image_name,omitempty"`
+	Name              string                  `json:"name,omitempty"`
+	PrelaunchScript   string                  `json:"prelaunch_script,omitempty"`
+	ProjectKey        string                  `json:"project_key,omitempty"`
+	RepoInfo          []*OpenAPIRepoInput     `json:"repo_info,omitempty"`
+	ScannerType       string                  `json:"scanner_type,omitempty"`
+	Script            string                  `json:"script,omitempty"`
+	SonarParameter    string                  `json:"sonar_parameter,omitempty"`
+	SonarSystem       string                  `json:"sonar_system,omitempty"`
+}
+
+type OpenAPICreateScanningTaskReq struct {
+	ProjectName string              `json:"projectName,omitempty"`
+	ScanName    string              `json:"scanName,omitempty"`
+	ScanKvs     []*KeyVal           `json:"scan_kvs,omitempty"`
+	ScanRepos   []*ScanningRepoInfo `json:"scan_repos,omitempty"`
+}
+
+type OpenAPICreateScanningTaskResp struct {
+	TaskID int64 `json:"task_id,omitempty"`
+}
+
+type OpenAPICreateServiceArgs struct {
+	Containers     []*ModelsContainer  `json:"containers,omitempty"`
+	DeployStrategy string              `json:"deploy_strategy,omitempty"`
+	ServiceName    string              `json:"service_name,omitempty"`
+	Status         string              `json:"status,omitempty"`
+	Type           string              `json:"type,omitempty"`
+	VariableKvs    []*RenderVariableKV `json:"variable_kvs,omitempty"`
+}
+
+type OpenAPICreateTestTaskReq struct {
+	ProjectKey string `json:"project_key,omitempty"`
+	TestName   string `json:"test_name,omitempty"`
+}
+
+type OpenAPICreateTestTaskResp struct {
+	TaskID int64 `json:"task_id,omitempty"`
+}
+
+type OpenAPICreateYamlServiceReq struct {
+	Production   bool                 `json:"production,omitempty"`
+	ServiceName  string               `json:"service_name,omitempty"`
+	VariableYAML []*ServiceVariableKV `json:"variable_yaml,omitempty"`
+	YAML         string               `json:"yaml,omitempty"`
+}
+
+type OpenAPIDeleteYamlServiceFromEnvReq struct {
+	EnvKey       string   `json:"env_key,omitempty"`
+	ServiceNames []string `json:"service_names,omitempty"`
+}
+
+type OpenAPIDeliveryVersionImageData struct {
+	ContainerName    string `json:"container_name,omitempty"`
+	DisableImageDist bool   `json:"disable_image_dist,omitempty"`
+	ImageName        string `json:"image_name,omitempty"`
+	ImageTag         string `json:"image_tag,omitempty"`
+}
+
+type OpenAPIEnvCfgArgs struct {
+	AutoSync             bool             `json:"auto_sync,omitempty"`
+	CommonEnvCfgType     CommonEnvCfgType `json:"common_env_cfg_type,omitempty"`
+	CreatedBy            string           `json:"created_by,omitempty"`
+	CreatedTime          int64            `json:"created_time,omitempty"`
+	EnvKey               string           `json:"env_key,omitempty"`
+	Name                 string           `json:"name,omitempty"`
+	ProductName          string           `json:"product_name,omitempty"`
+	RestartAssociatedSvc bool             `json:"restart_associated_svc,omitempty"`
+	ServiceName          string           `json:"service_name,omitempty"`
+	Services             []string         `json:"services,omitempty"`
+	SourceDetail         *CreateFromRepo  `json:"source_detail,omitempty"`
+	UpdateBy             string           `json:"update_by,omitempty"`
+	UpdateTime           int64            `json:"update_time,omitempty"`
+	YAMLData             string           `json:"yaml_data,omitempty"`
+}
+
+type OpenAPIEnvCfgBrief struct {
+	CommonEnvCfgType CommonEnvCfgType `json:"common_env_cfg_type,omitempty"`
+	EnvName          string           `json:"env_name,omitempty"`
+	Name             string           `json:"name,omitempty"`
+	ProjectName      string           `json:"project_name,omitempty"`
+	UpdateBy         string           `json:"update_by,omitempty"`
+	UpdateTime       int64            `json:"update_time,omitempty"`
+}
+
+type OpenAPIEnvCfgConfigMapDetail struct {
+	CmData           map[string]string `json:"cm_data,omitempty"`
+	CommonEnvCfgType CommonEnvCfgType  `json:"common_env_cfg_type,omitempty"`
+	CreatedBy        string            `json:"created_by,omitempty"`
+	CreatedTime      int64             `json:"created_time,omitempty"`
+	EnvKey           string            `json:"env_key,omitempty"`
+	Immutable        bool              `json:"immutable,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	ProjectKey       string            `json:"project_key,omitempty"`
+	Services         []string          `json:"services,omitempty"`
+	SourceDetail     *CfgRepoInfo      `json:"source_detail,omitempty"`
+	UpdateBy         string            `json:"update_by,omitempty"`
+	UpdateTime       int64             `json:"update_time,omitempty"`
+	YAMLData         string            `json:"yaml_data,omitempty"`
+}
+
+type OpenAPIEnvCfgDetail struct {
+	ConfigMapDetail *OpenAPIEnvCfgConfigMapDetail `json:"configMap_detail,omitempty"`
+	IngressDetail   *OpenAPIEnvCfgIngressDetail   `json:"ingress_detail,omitempty"`
+	PvcDetail       *OpenAPIEnvCfgPvcDetail       `json:"pvc_detail,omitempty"`
+	SecretDetail    *OpenAPIEnvCfgSecretDetail    `json:"secret_detail,omitempty"`
+}
+
+type OpenAPIEnvCfgIngressDetail struct {
+	Address          string           `json:"address,omitempty"`
+	CommonEnvCfgType CommonEnvCfgType `json:"common_env_cfg_type,omitempty"`
+	CreatedBy        string           `json:"created_by,omitempty"`
+	CreatedTime      int64            `json:"created_time,omitempty"`
+	EnvKey           string           `json:"env_key,omitempty"`
+	ErrorReason      string           `json:"error_reason,omitempty"`
+	HostInfo         string           `json:"host_info,omitempty"`
+	Name             string           `json:"name,omitempty"`
+	Ports            string           `json:"ports,omitempty"`
+	ProjectKey       string           `json:"project_key,omitempty"`
+	Services         []string         `json:"services,omitempty"`
+	SourceDetail     *CfgRepoInfo     `json:"source_detail,omitempty"`
+	UpdateBy         string           `json:"update_by,omitempty"`
+	UpdateTime       int64            `json:"update_time,omitempty"`
+	YAMLData         string           `json:"yaml_data,omitempty"`
+}
+
+type OpenAPIEnvCfgPvcDetail struct {
+	AccessModes      string           `json:"access_modes,omitempty"`
+	Capacity         string           `json:"capacity,omitempty"`
+	CommonEnvCfgType CommonEnvCfgType `json:"common_env_cfg_type,omitempty"`
+	CreatedBy        string           `json:"created_by,omitempty"`
+	CreatedTime      int64            `json:"created_time,omitempty"`
+	EnvKey           string           `json:"env_key,omitempty"`
+	Name             string           `json:"name,omitempty"`
+	ProjectKey       string           `json:"project_key,omitempty"`
+	Services         []string         `json:"services,omitempty"`
+	SourceDetail     *CfgRepoInfo     `json:"source_detail,omitempty"`
+	Status           string           `json:"status,omitempty"`
+	StorageClass     string           `json:"storage_class,omitempty"`
+	UpdateBy         string           `json:"update_by,omitempty"`
+	UpdateTime       int64            `json:"update_time,omitempty"`
+	Volume           string           `json:"volume,omitempty"`
+	YAMLData         string           `json:"yaml_data,omitempty"`
+}
+
+type OpenAPIEnvCfgSecretDetail struct {
+	CommonEnvCfgType CommonEnvCfgType `json:"common_env_cfg_type,omitempty"`
+	CreatedBy        string           `json:"created_by,omitempty"`
+	CreatedTime      int64            `json:"created_time,omitempty"`
+	EnvKey           string           `json:"env_key,omitempty"`
+	Name             string           `json:"name,omitempty"`
+	ProjectKey       string           `json:"project_key,omitempty"`
+	SecretType       string           `json:"secret_type,omitempty"`
+	Services         []string         `json:"services,omitempty"`
+	SourceDetail     *CfgRepoInfo     `json:"source_detail,omitempty"`
+	UpdateBy         string           `json:"update_by,omitempty"`
+	UpdateTime       int64            `json:"update_time,omitempty"`
+	YAMLData         string           `json:"yaml_data,omitempty"`
+}
+
+type OpenAPIEnvDetail struct {
+	ChartValues     []*ProductHelmServiceCreationInfo `json:"chart_values,omitempty"`
+	ClusterID       string                            `json:"cluster_id,omitempty"`
+	EnvKey          string                            `json:"env_key,omitempty"`
+	EnvName         string                            `json:"env_name,omitempty"`
+	GlobalVariables []*GlobalVariableKV               `json:"global_variables,omitempty"`
+	Namespace       string                            `json:"namespace,omitempty"`
+	ProjectKey      string                            `json:"project_key,omitempty"`
+	RegistryID      string                            `json:"registry_id,omitempty"`
+	Services        []*OpenAPIServiceDetail           `json:"services,omitempty"`
+	Status          string                            `json:"status,omitempty"`
+	UpdateBy        string                            `json:"update_by,omitempty"`
+	UpdateTime      int64                             `json:"update_time,omitempty"`
+}
+
+type OpenAPIEnvGlobalVariables struct {
+	GlobalVariables []*GlobalVariableKV `json:"global_variables,omitempty"`
+}
+
+type OpenAPIGetYamlServiceResp struct {
+	Containers         []*ModelsContainer   `json:"containers,omitempty"`
+	CreatedBy          string               `json:"created_by,omitempty"`
+	CreatedTime        int64                `json:"created_time,omitempty"`
+	ServiceName        string               `json:"service_name,omitempty"`
+	ServiceVariableKvs []*ServiceVariableKV `json:"service_variable_kvs,omitempty"`
+	Source             string               `json:"source,omitempty"`
+	TemplateName       string               `json:"template_name,omitempty"`
+	Type               string               `json:"type,omitempty"`
+	YAML               string               `json:"yaml,omitempty"`
+}
+
+type OpenAPIInitializeProjectReq struct {
+	Description string               `json:"description,omitempty"`
+	EnvList     []*EnvDefinition     `json:"env_list,omitempty"`
+	IsPublic    bool                 `json:"is_public,omitempty"`
+	ProjectKey  string               `json:"project_key,omitempty"`
+	ProjectName string               `json:"project_name,omitempty"`
+	ServiceList []*ServiceDefinition `json:"service_list,omitempty"`
+}
+
+type OpenAPIListEnvBrief struct {
+	ClusterID  string `json:"cluster_id,omitempty"`
+	EnvName    string `json:"envName,omitempty"`
+	EnvKey     string `json:"env_key,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Production bool   `json:"production,omitempty"`
+	RegistryID string `json:"registry_id,omitempty"`
+	Status     string `json:"status,omitempty"`
+	UpdateBy   string `json:"update_by,omitempty"`
+	UpdateTime int64  `json:"update_time,omitempty"`
+}
+
+type OpenAPIListReleasePlanInfo struct {
+	CreateTime  int64  `json:"create_time,omitempty"`
+	CreatedBy   string `json:"created_by,omitempty"`
+	Description string `json:"description,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Index       int64  `json:"index,omitempty"`
+	Manager     string `json:"manager,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type OpenAPIListReleasePlanResp struct {
+	List  []*OpenAPIListReleasePlanInfo `json:"list,omitempty"`
+	Total int64                         `json:"total,omitempty"`
+}
+
+type OpenAPIListScanningResp struct {
+	Scannings []*OpenAPIScanningBrief `json:"scannings,omitempty"`
+	Total     int64                   `json:"total,omitempty"`
+}
+
+type OpenAPILoadServiceFromYamlTemplateReq struct {
+	AutoSync     bool        `json:"auto_sync,omitempty"`
+	Production   bool        `json:"production,omitempty"`
+	ProjectKey   string      `json:"project_key,omitempty"`
+	ServiceName  string      `json:"service_name,omitempty"`
+	TemplateName string      `json:"template_name,omitempty"`
+	VariableYAML []*KeyValue `json:"variable_yaml,omitempty"`
+}
+
+type OpenAPIProjectDetailResp struct {
+	CreateTime  int64  `json:"create_time,omitempty"`
+	CreatedBy   string `json:"created_by,omitempty"`
+	DeployType  string `json:"deploy_type,omitempty"`
+	Desc        string `json:"desc,omitempty"`
+	IsPublic    bool   `json:"is_public,omitempty"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+}
+
+type OpenAPIRegistry struct {
+	Address    string `json:"address,omitempty"`
+	IsDefault  bool   `json:"is_default,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Provider   string `json:"provider,omitempty"`
+	Region     string `json:"region,omitempty"`
+	RegistryID string `json:"registry_id,omitempty"`
+}
+
+type OpenAPIRepo struct {
+	Branch       string `json:"branch,omitempty"`
+	CheckoutPath string `json:"checkout_path,omitempty"`
+	Hidden       bool   `json:"hidden,omitempty"`
+	RemoteName   string `json:"remote_name,omitempty"`
+	RepoName     string `json:"repo_name,omitempty"`
+	RepoOwner    string `json:"repo_owner,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Submodules   bool   `json:"submodules,omitempty"`
+}
+
+type OpenAPIScaleServiceReq struct {
+	EnvKey         string `json:"env_key,omitempty"`
+	ProjectKey     string `json:"project_key,omitempty"`
+	TargetReplicas int64  `json:"target_replicas,omitempty"`
+	WorkloadName   string `json:"workload_name,omitempty"`
+	WorkloadType   string `json:"workload_type,omitempty"`
+}
+
+type OpenAPIScanRepoBrief struct {
+	Address      string `json:"address,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	CheckoutPath string `json:"checkout_path,omitempty"`
+	Hidden       bool   `json:"hidden,omitempty"`
+	RemoteName   string `json:"remote_name,omitempty"`
+	RepoName     string `json:"repo_name,omitempty"`
+	RepoOwner    string `json:"repo_owner,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Submodules   bool   `json:"submodules,omitempty"`
+}
+
+type OpenAPIScanTaskDetail struct {
+	CreateTime int64                   `json:"create_time,omitempty"`
+	Creator    string                  `json:"creator,omitempty"`
+	EndTime    int64                   `json:"end_time,omitempty"`
+	RepoInfo   []*OpenAPIScanRepoBrief `json:"repo_info,omitempty"`
+	ResultLink string                  `json:"result_link,omitempty"`
+	ScanName   string                  `json:"scan_name,omitempty"`
+	Status     string                  `json:"status,omitempty"`
+	TaskID     int64                   `json:"task_id,omitempty"`
+}
+
+type OpenAPIScanTaskResult struct {
+	// QualityGate is the sonar quality gate status, empty if the quality gate is not checked
+	QualityGate string              `json:"quality_gate,omitempty"`
+	ResultLink  string              `json:"result_link,omitempty"`
+	ScanName    string              `json:"scan_name,omitempty"`
+	Scanner     *ScannerResult      `json:"scanner,omitempty"`
+	ScannerType string              `json:"scanner_type,omitempty"`
+	Sonar       *OpenAPISonarResult `json:"sonar,omitempty"`
+	Status      string              `json:"status,omitempty"`
+	TaskID      int64               `json:"task_id,omitempty"`
+}
+
+type OpenAPIScanningBrief struct {
+	AverageRuntime int64  `json:"average_runtime,omitempty"`
+	CreatedAt      int64  `json:"created_at,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Name           string `json:"name,omitempty"`
+	TimesRun       int64  `json:"times_run,omitempty"`
+	UpdatedAt      int64  `json:"updated_at,omitempty"`
+}
+
+type OpenAPIScanningDetail struct {
+	Addons            []*Item                 `json:"addons,omitempty"`
+	CreatedAt         int64                   `json:"created_at,omitempty"`
+	Description       string                  `json:"description,omitempty"`
+	EnableQualityGate bool                    `json:"enable_quality_gate,omitempty"`
+	ImageName         string                  `json:"image_name,omitempty"`
+	KeyVals           []*KeyVal               `json:"key_vals,omitempty"`
+	Name              string                  `json:"name,omitempty"`
+	ProjectKey        string                  `json:"project_key,omitempty"`
+	RepoInfo          []*OpenAPIScanRepoBrief `json:"repo_info,omitempty"`
+	ScannerType       string                  `json:"scanner_type,omitempty"`
+	Script            string                  `json:"script,omitempty"`
+	SonarParameter    string                  `json:"sonar_parameter,omitempty"`
+	SonarSystem       string                  `json:"sonar_system,omitempty"`
+	UpdatedAt         int64                   `json:"updated_at,omitempty"`
+	UpdatedBy         string                  `json:"updated_by,omitempty"`
+}
+
+type OpenAPIServiceBrief struct {
+	Containers  []*ContainerBrief `json:"containers,omitempty"`
+	ServiceName string            `json:"service_name,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	Type        string            `json:"type,omitempty"`
+}
+
+type OpenAPIServiceDetail struct {
+	Containers  []*ModelsContainer   `json:"containers,omitempty"`
+	ServiceName string               `json:"service_name,omitempty"`
+	Status      string               `json:"status,omitempty"`
+	Type        string               `json:"type,omitempty"`
+	VariableKvs []*ServiceVariableKV `json:"variable_kvs,omitempty"`
+}
+
+type OpenAPIServiceVariablesReq struct {
+	ServiceList []*ServiceVariable `json:"service_list,omitempty"`
+}
+
+type OpenAPISonarResult struct {
+	Bugs            string `json:"bugs,omitempty"`
+	CodeSmells      string `json:"code_smells,omitempty"`
+	Coverage        string `json:"coverage,omitempty"`
+	Ncloc           string `json:"ncloc,omitempty"`
+	Vulnerabilities string `json:"vulnerabilities,omitempty"`
+}
+
+type OpenAPIStatV2 struct {
+	DailyStat    []*DailyStat `json:"daily_stat,omitempty"`
+	SuccessCount int64        `json:"success_count,omitempty"`
+	Total        int64        `json:"total,omitempty"`
+}
+
+type OpenAPITestCase struct {
+	Error   *ModelsError `json:"error,omitempty"`
+	Failure *Failure     `json:"failure,omitempty"`
+	Name    string       `json:"name,omitempty"`
+	Time    float64      `json:"time,omitempty"`
+}
+
+type OpenAPITestReport struct {
+	ErrorTotal   int64              `json:"error_total,omitempty"`
+	FailureTotal int64              `json:"failure_total,omitempty"`
+	SkipedTotal  int64              `json:"skiped_total,omitempty"`
+	SuccessTotal int64              `json:"success_total,omitempty"`
+	TestCases    []*OpenAPITestCase `json:"test_cases,omitempty"`
+	TestTotal    int64              `json:"test_total,omitempty"`
+	Time         float64            `json:"time,omitempty"`
+}
+
+type OpenAPITestTaskDetail struct {
+	CreateTime int64              `json:"create_time,omitempty"`
+	Creator    string             `json:"creator,omitempty"`
+	EndTime    int64              `json:"end_time,omitempty"`
+	StartTime  int64              `json:"start_time,omitempty"`
+	Status     string             `json:"status,omitempty"`
+	TaskID     int64              `json:"task_id,omitempty"`
+	TestName   string             `json:"test_name,omitempty"`
+	TestReport *OpenAPITestReport `json:"test_report,omitempty"`
+}
+
+type OpenAPIUpdateServiceConfigArgs struct {
+	ProjectName string `json:"project_name,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Type        string `json:"type,omitempty"`
+	YAML        string `json:"yaml,omitempty"`
+}
+
+type OpenAPIUpdateServiceVariableRequest struct {
+	ServiceVariableKvs []*ServiceVariableKV `json:"service_variable_kvs,omitempty"`
+}
+
+type Overview struct {
+	ArtifactCount int64 `json:"artifact_count,omitempty"`
+	ClusterCount  int64 `json:"cluster_count,omitempty"`
+	EnvCount      int64 `json:"env_count,omitempty"`
+	ProjectCount  int64 `json:"project_count,omitempty"`
+	ServiceCount  int64 `json:"service_count,omitempty"`
+	WorkflowCount int64 `json:"workflow_count,omitempty"`
+}
+
+type ProductHelmServiceCreationInfo struct {
+	ChartVersion   string          `json:"chartVersion,omitempty"`
+	ChartName      string          `json:"chart_name,omitempty"`
+	ChartRepo      string          `json:"chart_repo,omitempty"`
+	DeployStrategy string          `json:"deploy_strategy,omitempty"`
+	EnvName        string          `json:"envName,omitempty"`
+	IsChartDeploy  bool            `json:"is_chart_deploy,omitempty"`
+	OverrideValues []*KVPair       `json:"overrideValues,omitempty"`
+	OverrideYaml   string          `json:"overrideYaml,omitempty"`
+	ReleaseName    string          `json:"release_name,omitempty"`
+	ServiceName    string          `json:"serviceName,omitempty"`
+	ValuesData     *ValuesDataArgs `json:"valuesData,omitempty"`
+	VariableYAML   string          `json:"variable_yaml,omitempty"`
+	YAMLData       *CustomYaml     `json:"yaml_data,omitempty"`
+}
+
+type Project struct {
+	Description string `json:"description,omitempty"`
+	IsPublic    bool   `json:"is_public,omitempty"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+	ProjectType string `json:"project_type,omitempty"`
+}
+
+type Registry struct {
+	AccessKey  string `json:"access_key,omitempty"`
+	Address    string `json:"address,omitempty"`
+	EnableTLS  bool   `json:"enable_tls,omitempty"`
+	IsDefault  bool   `json:"is_default,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Provider   string `json:"provider,omitempty"`
+	Region     string `json:"region,omitempty"`
+	RegistryID string `json:"registry_id,omitempty"`
+	// SecretKey is write only, it's never returned
+	SecretKey string `json:"secret_key,omitempty"`
+	TLSCert   string `json:"tls_cert,omitempty"`
+}
+
+type RepoConfig struct {
+	Branch      string   `json:"branch,omitempty"`
+	CodehostID  int64    `json:"codehostID,omitempty"`
+	Namespace   string   `json:"namespace,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Repo        string   `json:"repo,omitempty"`
+	ValuesPaths []string `json:"valuesPaths,omitempty"`
+}
+
+type ScanningRepoInfo struct {
+	Branch        string  `json:"branch,omitempty"`
+	CodehostID    int64   `json:"codehost_id,omitempty"`
+	Pr            int64   `json:"pr,omitempty"`
+	Prs           []int64 `json:"prs,omitempty"`
+	RepoName      string  `json:"repo_name,omitempty"`
+	RepoNamespace string  `json:"repo_namespace,omitempty"`
+	RepoOwner     string  `json:"repo_owner,omitempty"`
+	Source        string  `json:"source,omitempty"`
+	Tag           string  `json:"tag,omitempty"`
+}
+
+type ServiceDefinition struct {
+	AutoSync     bool        `json:"auto_sync,omitempty"`
+	ServiceName  string      `json:"service_name,omitempty"`
+	Source       string      `json:"source,omitempty"`
+	TemplateName string      `json:"template_name,omitempty"`
+	ValuesYAML   string      `json:"values_yaml,omitempty"`
+	VariableYAML []*KeyValue `json:"variable_yaml,omitempty"`
+	YAML         string      `json:"yaml,omitempty"`
+}
+
+type ServiceVariable struct {
+	ServiceName string              `json:"service_name,omitempty"`
+	VariableKvs []*RenderVariableKV `json:"variable_kvs,omitempty"`
+}
+
+type ValuesDataArgs struct {
+	AutoSync      bool        `json:"autoSync,omitempty"`
+	GitRepoConfig *RepoConfig `json:"gitRepoConfig,omitempty"`
+	SourceID      string      `json:"source_id,omitempty"`
+	YamlSource    string      `json:"yamlSource,omitempty"`
+}
+
+type YamlServiceWithKV struct {
+	ServiceName string              `json:"service_name,omitempty"`
+	VariableKvs []*RenderVariableKV `json:"variable_kvs,omitempty"`
+}
+
+type DashboardBuild struct {
+	Data    []*DashboardBuildDaily `json:"data,omitempty"`
+	Success int64                  `json:"success,omitempty"`
+	Total   int64                  `json:"total,omitempty"`
+}
+
+type DashboardBuildDaily struct {
+	Date    string `json:"date,omitempty"`
+	Failure int64  `json:"failure,omitempty"`
+	Success int64  `json:"success,omitempty"`
+	Total   int64  `json:"total,omitempty"`
+}
+
+type NotifyWebHookType string
+
+type RequestSpec struct {
+	CPULimit int64 `json:"cpu_limit,omitempty"`
+	CPUReq   int64 `json:"cpu_req,omitempty"`
+	// gpu request, eg: "nvidia.com/gpu: 1"
+	GpuLimit    string `json:"gpu_limit,omitempty"`
+	MemoryLimit int64  `json:"memory_limit,omitempty"`
+	MemoryReq   int64  `json:"memory_req,omitempty"`
+}
+
+type ScannerFinding struct {
+	File     string `json:"file,omitempty"`
+	Line     int64  `json:"line,omitempty"`
+	Message  string `json:"message,omitempty"`
+	RuleID   string `json:"rule_id,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+type ScannerResult struct {
+	Critical int64             `json:"critical,omitempty"`
+	Findings []*ScannerFinding `json:"findings,omitempty"`
+	High     int64             `json:"high,omitempty"`
+	Info     int64             `json:"info,omitempty"`
+	Low      int64             `json:"low,omitempty"`
+	Medium   int64             `json:"medium,omitempty"`
+	Total    int64             `json:"total,omitempty"`
+}
+
+type CustomYaml struct {
+	AutoSync          bool                `json:"auto_sync,omitempty"`
+	RenderVariableKvs []*RenderVariableKV `json:"render_variable_kvs,omitempty"`
+	Source            string              `json:"source,omitempty"`
+	SourceDetail      interface{}         `json:"source_detail,omitempty"`
+	SourceID          string              `json:"source_id,omitempty"`
+	YAMLContent       string              `json:"yaml_content,omitempty"`
+}
+
+type TemplateGitRepoConfig struct {
+	Branch     string `json:"branch,omitempty"`
+	CodehostID int64  `json:"codehost_id,omitempty"`
+	// records the actual namespace of repo, used to generate correct project name
+	Namespace   string   `json:"namespace,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Repo        string   `json:"repo,omitempty"`
+	ValuesPaths []string `json:"values_paths,omitempty"`
+}
+
+type AuthType string
+
+type DockerBuildBackend string
+
+type GlobalVariableKV struct {
+	Desc            string                `json:"desc,omitempty"`
+	Key             string                `json:"key,omitempty"`
+	Options         []string              `json:"options,omitempty"`
+	RelatedServices []string              `json:"related_services,omitempty"`
+	Type            ServiceVariableKVType `json:"type,omitempty"`
+	Value           interface{}           `json:"value,omitempty"`
+}
+
+type GlobalVariables struct {
+	ProductionVariables []*ServiceVariableKV `json:"production_variables,omitempty"`
+	Variables           []*ServiceVariableKV `json:"variables,omitempty"`
+}
+
+type KV struct {
+	IsCredential bool   `json:"is_credential,omitempty"`
+	Key          string `json:"key,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Value        string `json:"value,omitempty"`
+}
+
+type MultiArchStrategy string
+
+type ObjectStoragePathDetail struct {
+	AbsFilePath string `json:"abs_file_path,omitempty"`
+	DestPath    string `json:"dest_path,omitempty"`
+	FilePath    string `json:"file_path,omitempty"`
+}
+
+type OpenAPIAdvancedSetting struct {
+	// Cache settings is for build only for now, remove this line if there are further changes
+	CacheSetting        *OpenAPICacheSetting   `json:"cache_setting,omitempty"`
+	ClusterName         string                 `json:"cluster_name,omitempty"`
+	ResourceSpec        *RequestSpec           `json:"resource_spec,omitempty"`
+	StrategyName        string                 `json:"strategy_name,omitempty"`
+	Timeout             int64                  `json:"timeout,omitempty"`
+	UseHostDockerDaemon bool                   `json:"use_host_docker_daemon,omitempty"`
+	Webhooks            *OpenAPIWebhookSetting `json:"webhooks,omitempty"`
+}
+
+type OpenAPICacheSetting struct {
+	CacheDir string `json:"cache_dir,omitempty"`
+	Enabled  bool   `json:"enabled,omitempty"`
+}
+
+type OpenAPIRepoInput struct {
+	Branch        string  `json:"branch,omitempty"`
+	CheckoutPath  string  `json:"checkout_path,omitempty"`
+	CodehostName  string  `json:"codehost_name,omitempty"`
+	Pr            int64   `json:"pr,omitempty"`
+	Prs           []int64 `json:"prs,omitempty"`
+	RemoteName    string  `json:"remote_name,omitempty"`
+	RepoName      string  `json:"repo_name,omitempty"`
+	RepoNamespace string  `json:"repo_namespace,omitempty"`
+	Submodules    bool    `json:"submodules,omitempty"`
+}
+
+type OpenAPIServiceBuildArgs struct {
+	Inputs        []*KV               `json:"inputs,omitempty"`
+	RepoInfo      []*OpenAPIRepoInput `json:"repo_info,omitempty"`
+	ServiceModule string              `json:"service_module,omitempty"`
+	ServiceName   string              `json:"service_name,omitempty"`
+}
+
+type OpenAPIWebhookConfigDetail struct {
+	Branch        string          `json:"branch,omitempty"`
+	CodehostName  string          `json:"codehost_name,omitempty"`
+	Events        []HookEventType `json:"events,omitempty"`
+	MatchFolders  []string        `json:"match_folders,omitempty"`
+	RepoName      string          `json:"repo_name,omitempty"`
+	RepoNamespace string          `json:"repo_namespace,omitempty"`
+}
+
+type OpenAPIWebhookSetting struct {
+	Enabled  bool                          `json:"enabled,omitempty"`
+	HookList []*OpenAPIWebhookConfigDetail `json:"hook_list,omitempty"`
+}
+
+type RenderVariableKV struct {
+	Desc              string                `json:"desc,omitempty"`
+	Key               string                `json:"key,omitempty"`
+	Options           []string              `json:"options,omitempty"`
+	Type              ServiceVariableKVType `json:"type,omitempty"`
+	UseGlobalVariable bool                  `json:"use_global_variable,omitempty"`
+	Value             interface{}           `json:"value,omitempty"`
+}
+
+type RepoSource string
+
+type Repository struct {
+	Address string `json:"address,omitempty"`
+	// The address of the code base input of the other type
+	AuthType      AuthType `json:"auth_type,omitempty"`
+	AuthorName    string   `json:"author_name,omitempty"`
+	Branch        string   `json:"branch,omitempty"`
+	CheckoutPath  string   `json:"checkout_path,omitempty"`
+	CheckoutRef   string   `json:"checkout_ref,omitempty"`
+	CodehostID    int64    `json:"codehost_id,omitempty"`
+	CommitID      string   `json:"commit_id,omitempty"`
+	CommitMessage string   `json:"commit_message,omitempty"`
+	// EnableCommit marks if the pull uses a commit instead of branch/pr
+	EnableCommit bool `json:"enable_commit,omitempty"`
+	// Now EnableProxy is not something we store. We decide this on runtime
+	EnableProxy bool `json:"enable_proxy,omitempty"`
+	// FilterRegexp is the regular expression filter for the branches and tags
+	FilterRegexp string `json:"filter_regexp,omitempty"`
+	// Hidden defines whether the frontend needs to hide this repo
+	Hidden bool `json:"hidden,omitempty"`
+	// IsPrimary used to generated image and package name, each build has one primary repo
+	IsPrimary bool   `json:"is_primary,omitempty"`
+	JobName   string `json:"job_name,omitempty"`
+	// add
+	OauthToken         string  `json:"oauth_token,omitempty"`
+	ParamName          string  `json:"param_name,omitempty"`
+	Password           string  `json:"password,omitempty"`
+	Pr                 int64   `json:"pr,omitempty"`
+	PrivateAccessToken string  `json:"private_access_token,omitempty"`
+	Prs                []int64 `json:"prs,omitempty"`
+	RemoteName         string  `json:"remote_name,omitempty"`
+	RepoIndex          int64   `json:"repo_index,omitempty"`
+	RepoName           string  `json:"repo_name,omitempty"`
+	RepoNamespace      string  `json:"repo_namespace,omitempty"`
+	RepoOwner          string  `json:"repo_owner,omitempty"`
+	ServiceModule      string  `json:"service_module,omitempty"`
+	ServiceName        string  `json:"service_name,omitempty"`
+	Source             string  `json:"source,omitempty"`
+	// repo can come from params or other job, introduced in 1.3.1
+	SourceFrom   RepoSource `json:"source_from,omitempty"`
+	SSHKey       string     `json:"ssh_key,omitempty"`
+	SubmissionID string     `json:"submission_id,omitempty"`
+	Submodules   bool       `json:"submodules,omitempty"`
+	Tag          string     `json:"tag,omitempty"`
+	// UseDefault defines if the repo can be configured in start pipeline task page
+	UseDefault bool `json:"use_default,omitempty"`
+	// username/password authorization
+	Username string `json:"username,omitempty"`
+}
+
+type ServiceVariableKV struct {
+	Desc    string                `json:"desc,omitempty"`
+	Key     string                `json:"key,omitempty"`
+	Options []string              `json:"options,omitempty"`
+	Type    ServiceVariableKVType `json:"type,omitempty"`
+	Value   interface{}           `json:"value,omitempty"`
+}
+
+type ServiceVariableKVType string
+
+type KeyValue struct {
+	Key   string      `json:"key,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type CreateCustomTaskJobInput struct {
+	JobName    string      `json:"job_name,omitempty"`
+	JobType    JobType     `json:"job_type,omitempty"`
+	Parameters interface{} `json:"parameters,omitempty"`
+}
+
+type CreateCustomTaskParam struct {
+	Name  string                   `json:"name,omitempty"`
+	Repo  *CreateCustomTaskRepoArg `json:"repo,omitempty"`
+	Type  WorkflowParamType        `json:"type,omitempty"`
+	Value string                   `json:"value,omitempty"`
+}
+
+type CreateCustomTaskRepoArg struct {
+	Branch        string  `json:"branch,omitempty"`
+	CodehostName  string  `json:"codehost_name,omitempty"`
+	Prs           []int64 `json:"prs,omitempty"`
+	RepoName      string  `json:"repo_name,omitempty"`
+	RepoNamespace string  `json:"repo_namespace,omitempty"`
+}
+
+type CreateProductTaskJobInput struct {
+	Build     *WorkflowBuildArg  `json:"build,omitempty"`
+	Deploy    *WorkflowDeployArg `json:"deploy,omitempty"`
+	TargetEnv string             `json:"target_env,omitempty"`
+}
+
+type CreateTaskResp struct {
+	PipelineName string `json:"pipeline_name,omitempty"`
+	ProjectName  string `json:"project_name,omitempty"`
+	TaskID       int64  `json:"task_id,omitempty"`
+}
+
+type CreateTaskV4Resp struct {
+	ProjectName  string `json:"project_name,omitempty"`
+	TaskID       int64  `json:"task_id,omitempty"`
+	WorkflowName string `json:"workflow_name,omitempty"`
+}
+
+type DeployEnvPreview struct {
+	EnvName string `json:"env_name,omitempty"`
+	JobName string `json:"job_name,omitempty"`
+	Status  Status `json:"status,omitempty"`
+}
+
+type JobTaskPreview struct {
+	BreakpointAfter      bool                  `json:"breakpoint_after,omitempty"`
+	BreakpointBefore     bool                  `json:"breakpoint_before,omitempty"`
+	CostSeconds          int64                 `json:"cost_seconds,omitempty"`
+	EndTime              int64                 `json:"end_time,omitempty"`
+	Error                string                `json:"error,omitempty"`
+	ErrorHandlerUserID   string                `json:"error_handler_user_id,omitempty"`
+	ErrorHandlerUsername string                `json:"error_handler_username,omitempty"`
+	ErrorPolicy          *ModelsJobErrorPolicy `json:"error_policy,omitempty"`
+	// JobInfo contains the fields that make up the job task name, for frontend display
+	JobInfo    interface{} `json:"job_info,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	RetryCount int64       `json:"retry_count,omitempty"`
+	Spec       interface{} `json:"spec,omitempty"`
+	StartTime  int64       `json:"start_time,omitempty"`
+	Status     Status      `json:"status,omitempty"`
+	Type       string      `json:"type,omitempty"`
+}
+
+type OpenAPIApproveRequest struct {
+	Approve     bool   `json:"approve,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	StageName   string `json:"stage_name,omitempty"`
+	TaskID      int64  `json:"task_id,omitempty"`
+	WorkflowKey string `json:"workflow_key,omitempty"`
+}
+
+type OpenAPICreateCustomWorkflowTaskArgs struct {
+	Inputs      []*CreateCustomTaskJobInput `json:"inputs,omitempty"`
+	Parameters  []*CreateCustomTaskParam    `json:"parameters,omitempty"`
+	ProjectKey  string                      `json:"project_key,omitempty"`
+	WorkflowKey string                      `json:"workflow_key,omitempty"`
+}
+
+type OpenAPICreateProductWorkflowTaskArgs struct {
+	Input       *CreateProductTaskJobInput `json:"input,omitempty"`
+	ProjectKey  string                     `json:"project_key,omitempty"`
+	WorkflowKey string                     `json:"workflow_key,omitempty"`
+}
+
+type OpenAPICreateWorkflowViewReq struct {
+	Name         string                       `json:"name,omitempty"`
+	ProjectKey   string                       `json:"project_key,omitempty"`
+	WorkflowList []*OpenAPIWorkflowViewDetail `json:"workflow_list,omitempty"`
+}
+
+type OpenAPIProductWorkflowTaskBrief struct {
+	CreateTime  int64  `json:"create_time,omitempty"`
+	EndTime     int64  `json:"end_time,omitempty"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	StartTime   int64  `json:"start_time,omitempty"`
+	Status      Status `json:"status,omitempty"`
+	TaskCreator string `json:"task_creator,omitempty"`
+	TaskID      int64  `json:"task_id,omitempty"`
+	WorkflowKey string `json:"workflow_key,omitempty"`
+}
+
+type OpenAPIProductWorkflowTaskDetail struct {
+	CreateTime   int64  `json:"create_time,omitempty"`
+	EndTime      int64  `json:"end_time,omitempty"`
+	ProjectKey   string `json:"project_key,omitempty"`
+	StartTime    int64  `json:"start_time,omitempty"`
+	Status       Status `json:"status,omitempty"`
+	TaskCreator  string `json:"task_creator,omitempty"`
+	TaskID       int64  `json:"task_id,omitempty"`
+	WorkflowKey  string `json:"workflow_key,omitempty"`
+	WorkflowName string `json:"workflow_name,omitempty"`
+}
+
+type OpenAPIStage struct {
+	Jobs     []*Job `json:"jobs,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Parallel bool   `json:"parallel,omitempty"`
+}
+
+type OpenAPIWorkflowListResp struct {
+	Workflows []*WorkflowBrief `json:"workflows,omitempty"`
+}
+
+type OpenAPIWorkflowV4Detail struct {
+	ConcurrencyLimit int64           `json:"concurrency_limit,omitempty"`
+	CreateTime       int64           `json:"create_time,omitempty"`
+	CreatedBy        string          `json:"created_by,omitempty"`
+	Description      string          `json:"description,omitempty"`
+	NotifyCtls       []*NotifyCtl    `json:"notify_ctls,omitempty"`
+	Params           []*Param        `json:"params,omitempty"`
+	ProjectKey       string          `json:"project_key,omitempty"`
+	ShareStorages    []*ShareStorage `json:"share_storages,omitempty"`
+	Stages           []*OpenAPIStage `json:"stages,omitempty"`
+	UpdateTime       int64           `json:"update_time,omitempty"`
+	UpdatedBy        string          `json:"updated_by,omitempty"`
+	WorkflowKey      string          `json:"workflow_key,omitempty"`
+	WorkflowName     string          `json:"workflow_name,omitempty"`
+}
+
+type OpenAPIWorkflowV4Task struct {
+	CreateTime   int64           `json:"create_time,omitempty"`
+	EndTime      int64           `json:"end_time,omitempty"`
+	ProjectKey   string          `json:"project_key,omitempty"`
+	Stages       []*OpenAPIStage `json:"stages,omitempty"`
+	StartTime    int64           `json:"start_time,omitempty"`
+	Status       Status          `json:"status,omitempty"`
+	TaskCreator  string          `json:"task_creator,omitempty"`
+	TaskID       int64           `json:"task_id,omitempty"`
+	WorkflowKey  string          `json:"workflow_key,omitempty"`
+	WorkflowName string          `json:"workflow_name,omitempty"`
+}
+
+type OpenAPIWorkflowV4TaskListResp struct {
+	Total         int64                    `json:"total,omitempty"`
+	WorkflowTasks []*OpenAPIWorkflowV4Task `json:"workflow_tasks,omitempty"`
+}
+
+type OpenAPIWorkflowViewBrief struct {
+	Name       string          `json:"name,omitempty"`
+	ProjectKey string          `json:"project_key,omitempty"`
+	UpdateBy   string          `json:"update_by,omitempty"`
+	UpdateTime int64           `json:"update_time,omitempty"`
+	Workflows  []*ViewWorkflow `json:"workflows,omitempty"`
+}
+
+type OpenAPIWorkflowViewDetail struct {
+	Enabled      bool   `json:"enabled,omitempty"`
+	WorkflowKey  string `json:"workflow_key,omitempty"`
+	WorkflowName string `json:"workflow_name,omitempty"`
+	WorkflowType string `json:"workflow_type,omitempty"`
+}
+
+type ServiceDeployArgs struct {
+	ImageName     string `json:"image_name,omitempty"`
+	ServiceModule string `json:"service_module,omitempty"`
+	ServiceName   string `json:"service_name,omitempty"`
+}
+
+type StageTaskPreview struct {
+	// DeployEnvs is the aggregated status of each env of the deploy jobs fanned out to multiple envs
+	DeployEnvs []*DeployEnvPreview `json:"deploy_envs,omitempty"`
+	EndTime    int64               `json:"end_time,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	Jobs       []*JobTaskPreview   `json:"jobs,omitempty"`
+	ManualExec *ManualExec         `json:"manual_exec,omitempty"`
+	Name       string              `json:"name,omitempty"`
+	Parallel   bool                `json:"parallel,omitempty"`
+	PauseAfter *StagePause         `json:"pause_after,omitempty"`
+	StartTime  int64               `json:"start_time,omitempty"`
+	Status     Status              `json:"status,omitempty"`
+}
+
+type ViewWorkflow struct {
+	WorkflowKey  string `json:"workflow_key,omitempty"`
+	WorkflowType string `json:"workflow_type,omitempty"`
+}
+
+type WorkflowBrief struct {
+	Type         string `json:"type,omitempty"`
+	UpdateBy     string `json:"update_by,omitempty"`
+	UpdateTime   int64  `json:"update_time,omitempty"`
+	WorkflowKey  string `json:"workflow_key,omitempty"`
+	WorkflowName string `json:"workflow_name,omitempty"`
+}
+
+type WorkflowBuildArg struct {
+	Enabled     bool                       `json:"enabled,omitempty"`
+	ServiceList []*OpenAPIServiceBuildArgs `json:"service_list,omitempty"`
+}
+
+type WorkflowDeployArg struct {
+	Enabled     bool                 `json:"enabled,omitempty"`
+	ServiceList []*ServiceDeployArgs `json:"service_list,omitempty"`
+	Source      string               `json:"source,omitempty"`
+}
+
+type WorkflowTaskPreview struct {
+	CreateTime int64    `json:"create_time,omitempty"`
+	Debug      bool     `json:"debug,omitempty"`
+	EndTime    int64    `json:"end_time,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	IsRestart  bool     `json:"is_restart,omitempty"`
+	Params     []*Param `json:"params,omitempty"`
+	ProjectKey string   `json:"project_key,omitempty"`
+	Remark     string   `json:"remark,omitempty"`
+	// Risk is the change risk of the task, it's empty for tasks created before risk scoring is supported
+	Risk         *TaskRisk           `json:"risk,omitempty"`
+	Stages       []*StageTaskPreview `json:"stages,omitempty"`
+	StartTime    int64               `json:"start_time,omitempty"`
+	Status       Status              `json:"status,omitempty"`
+	TaskCreator  string              `json:"task_creator,omitempty"`
+	TaskID       int64               `json:"task_id,omitempty"`
+	TaskName     string              `json:"task_name,omitempty"`
+	TaskRevoker  string              `json:"task_revoker,omitempty"`
+	WorkflowKey  string              `json:"workflow_key,omitempty"`
+	WorkflowName string              `json:"workflow_name,omitempty"`
+}
+
+type ApprovalNode struct {
+	ApvRel   ApprovalRel        `json:"apv_rel,omitempty"`
+	Status   ApprovalNodeStatus `json:"status,omitempty"`
+	SubNodes []*ApprovalSubNode `json:"sub_nodes,omitempty"`
+	Type     WorkwxApprovalType `json:"type,omitempty"`
+	Userid   []string           `json:"userid,omitempty"`
+	Users    []*ApprovalUser    `json:"users,omitempty"`
+}
+
+type ApprovalNodeStatus int64
+
+type ApprovalRel int64
+
+type ApprovalSubNode struct {
+	Speech    string                 `json:"speech,omitempty"`
+	Status    ApprovalSubNodeStatus  `json:"status,omitempty"`
+	Timestamp int64                  `json:"timestamp,omitempty"`
+	UserInfo  map[string]interface{} `json:"user_info,omitempty"`
+}
+
+type ApprovalSubNodeStatus int64
+
+type WorkwxApprovalType int64
+
+type ApprovalUser struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// DeleteBuildModule OpenAPI Delete Build Module
+//
+// DELETE /openapi/build
+//   - query name: name
+//   - query projectKey (required): project key
+func (c *Client) DeleteBuildModule(ctx context.Context, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/build", query, nil, &resp)
+	return resp, err
+}
+
+// ListBuildModules OpenAPI List Build Modules
+//
+// GET /openapi/build
+//   - query projectKey (required): project key
+//   - query pageNum: page num
+//   - query pageSize: page size
+func (c *Client) ListBuildModules(ctx context.Context, query url.Values) (*OpenAPIBuildListResp, error) {
+	var resp *OpenAPIBuildListResp
+	err := c.do(ctx, "GET", "/openapi/build", query, nil, &resp)
+	return resp, err
+}
+
+// CreateBuildModule OpenAPI Create Build Module
+//
+// POST /openapi/build
+//   - query source: source
+func (c *Client) CreateBuildModule(ctx context.Context, query url.Values, body *OpenAPIBuildCreationFromTemplateReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/build", query, body, &resp)
+	return resp, err
+}
+
+// GetBuildModule OpenAPI Get Build Module
+//
+// GET /openapi/build/{name}/detail
+//   - query projectKey (required): project key
+func (c *Client) GetBuildModule(ctx context.Context, name string, query url.Values) (*OpenAPIBuildDetailResp, error) {
+	var resp *OpenAPIBuildDetailResp
+	err := c.do(ctx, "GET", "/openapi/build/"+url.PathEscape(name)+"/detail", query, nil, &resp)
+	return resp, err
+}
+
+// CheckIstiod OpenAPI Check Istiod
+//
+// GET /openapi/cluster/istio/check/{id}
+func (c *Client) CheckIstiod(ctx context.Context, id string, query url.Values) (bool, error) {
+	var resp bool
+	err := c.do(ctx, "GET", "/openapi/cluster/istio/check/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// ListDeliveryVersion OpenAPI List Delivery Version
+//
+// GET /openapi/delivery/releases
+//   - query projectKey (required): project key
+//   - query pageNum (required): page num
+//   - query pageSize (required): page size
+func (c *Client) ListDeliveryVersion(ctx context.Context, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "GET", "/openapi/delivery/releases", query, nil, &resp)
+	return resp, err
+}
+
+// CreateHelmDeliveryVersion OpenAPI Create Helm Delivery Version
+//
+// POST /openapi/delivery/releases/helm
+func (c *Client) CreateHelmDeliveryVersion(ctx context.Context, query url.Values, body *OpenAPICreateHelmDeliveryVersionRequest) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/delivery/releases/helm", query, body, &resp)
+	return resp, err
+}
+
+// CreateK8SDeliveryVersion OpenAPI Create K8S Delivery Version
+//
+// POST /openapi/delivery/releases/k8s
+func (c *Client) CreateK8SDeliveryVersion(ctx context.Context, query url.Values, body *OpenAPICreateK8SDeliveryVersionRequest) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/delivery/releases/k8s", query, body, &resp)
+	return resp, err
+}
+
+// DeleteDeliveryVersion OpenAPI Delete Delivery Version
+//
+// DELETE /openapi/delivery/releases/{id}
+//   - query projectKey (required): project key
+func (c *Client) DeleteDeliveryVersion(ctx context.Context, id string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/delivery/releases/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// GetDeliveryVersion OpenAPI Get Delivery Version
+//
+// GET /openapi/delivery/releases/{id}
+//   - query projectKey (required): project key
+func (c *Client) GetDeliveryVersion(ctx context.Context, id string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "GET", "/openapi/delivery/releases/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// ListEnvs OpenAPI List Envs
+//
+// GET /openapi/environments
+//   - query projectKey (required): project key
+func (c *Client) ListEnvs(ctx context.Context, query url.Values) ([]*OpenAPIListEnvBrief, error) {
+	var resp []*OpenAPIListEnvBrief
+	err := c.do(ctx, "GET", "/openapi/environments", query, nil, &resp)
+	return resp, err
+}
+
+// CreateK8sEnv OpenAPI Create K8s Env
+//
+// POST /openapi/environments
+//   - query projectKey (required): project key
+func (c *Client) CreateK8sEnv(ctx context.Context, query url.Values, body *OpenAPICreateEnvArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments", query, body, &resp)
+	return resp, err
+}
+
+// UpdateCommonEnvCfg OpenAPI Update Common Env Cfg
+//
+// PUT /openapi/environments/envcfgs
+//   - query projectKey (required): project key
+func (c *Client) UpdateCommonEnvCfg(ctx context.Context, query url.Values, body *OpenAPIEnvCfgArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/envcfgs", query, body, &resp)
+	return resp, err
+}
+
+// UpdateCronJobContainerImage OpenAPI Update Cron Job Container Image
+//
+// POST /openapi/environments/image/cronjob/{envName}
+func (c *Client) UpdateCronJobContainerImage(ctx context.Context, envName string, query url.Values, body *OpenAPIUpdateContainerImageArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/image/cronjob/"+url.PathEscape(envName), query, body, &resp)
+	return resp, err
+}
+
+// UpdateDeploymentContainerImage OpenAPI Update Deployment Container Image
+//
+// POST /openapi/environments/image/deployment/{envName}
+func (c *Client) UpdateDeploymentContainerImage(ctx context.Context, envName string, query url.Values, body *OpenAPIUpdateContainerImageArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/image/deployment/"+url.PathEscape(envName), query, body, &resp)
+	return resp, err
+}
+
+// UpdateStatefulSetContainerImage OpenAPI Update Stateful Set Container Image
+//
+// POST /openapi/environments/image/statefulset/{envName}
+func (c *Client) UpdateStatefulSetContainerImage(ctx context.Context, envName string, query url.Values, body *OpenAPIUpdateContainerImageArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/image/statefulset/"+url.PathEscape(envName), query, body, &resp)
+	return resp, err
+}
+
+// ListKubeEvents OpenAPI List Kube Events
+//
+// GET /openapi/environments/kube/events
+//   - query envName: env name
+//   - query projectKey (required): project key
+//   - query name: name
+//   - query type: type
+func (c *Client) ListKubeEvents(ctx context.Context, query url.Values) ([]*OpenAPIListKubeEventResponse, error) {
+	var resp []*OpenAPIListKubeEventResponse
+	err := c.do(ctx, "GET", "/openapi/environments/kube/events", query, nil, &resp)
+	return resp, err
+}
+
+// ListProductionEnvs OpenAPI List Production Envs
+//
+// GET /openapi/environments/production
+//   - query projectKey (required): project key
+func (c *Client) ListProductionEnvs(ctx context.Context, query url.Values) ([]*OpenAPIListEnvBrief, error) {
+	var resp []*OpenAPIListEnvBrief
+	err := c.do(ctx, "GET", "/openapi/environments/production", query, nil, &resp)
+	return resp, err
+}
+
+// CreateProductionEnv OpenAPI Create Production Env
+//
+// POST /openapi/environments/production
+//   - query projectKey (required): project key
+func (c *Client) CreateProductionEnv(ctx context.Context, query url.Values, body *OpenAPICreateEnvArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/production", query, body, &resp)
+	return resp, err
+}
+
+// UpdateProductionCommonEnvCfg OpenAPI Update Production Common Env Cfg
+//
+// PUT /openapi/environments/production/envcfgs
+//   - query projectKey (required): project key
+func (c *Client) UpdateProductionCommonEnvCfg(ctx context.Context, query url.Values, body *OpenAPIEnvCfgArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/production/envcfgs", query, body, &resp)
+	return resp, err
+}
+
+// DeleteProductionYamlServiceFromEnv OpenAPI Delete Production Yaml Service From Env
+//
+// DELETE /openapi/environments/production/service/yaml
+//   - query projectKey (required): project key
+func (c *Client) DeleteProductionYamlServiceFromEnv(ctx context.Context, query url.Values, body *OpenAPIDeleteYamlServiceFromEnvReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/environments/production/service/yaml", query, body, &resp)
+	return resp, err
+}
+
+// ApplyProductionYamlService OpenAPI Apply Production Yaml Service
+//
+// POST /openapi/environments/production/service/yaml
+//   - query projectKey (required): project key
+func (c *Client) ApplyProductionYamlService(ctx context.Context, query url.Values, body *OpenAPIApplyYamlServiceReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/production/service/yaml", query, body, &resp)
+	return resp, err
+}
+
+// DeleteProductionEnv OpenAPI Delete Production Env
+//
+// DELETE /openapi/environments/production/{name}
+//   - query projectKey (required): project key
+func (c *Client) DeleteProductionEnv(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/environments/production/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// GetProductionEnvDetail OpenAPI Get Production Env Detail
+//
+// GET /openapi/environments/production/{name}
+//   - query projectKey (required): project key
+func (c *Client) GetProductionEnvDetail(ctx context.Context, name string, query url.Values) (*OpenAPIEnvDetail, error) {
+	var resp *OpenAPIEnvDetail
+	err := c.do(ctx, "GET", "/openapi/environments/production/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateProductionEnvBasicInfo OpenAPI Update Production Env Basic Info
+//
+// PUT /openapi/environments/production/{name}
+//   - query projectKey (required): project key
+func (c *Client) UpdateProductionEnvBasicInfo(ctx context.Context, name string, query url.Values, body *EnvBasicInfoArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/production/"+url.PathEscape(name), query, body, &resp)
+	return resp, err
+}
+
+// DeleteProductionEnvCommonEnvCfg OpenAPI Delete Production Env Common Env Cfg
+//
+// DELETE /openapi/environments/production/{name}/envcfg/{cfgName}
+//   - query projectKey (required): project key
+//   - query type: type
+func (c *Client) DeleteProductionEnvCommonEnvCfg(ctx context.Context, name string, cfgName string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/environments/production/"+url.PathEscape(name)+"/envcfg/"+url.PathEscape(cfgName), query, nil, &resp)
+	return resp, err
+}
+
+// GetProductionCommonEnvCfg OpenAPI Get Production Common Env Cfg
+//
+// GET /openapi/environments/production/{name}/envcfg/{cfgName}
+//   - query projectKey (required): project key
+//   - query type: type
+func (c *Client) GetProductionCommonEnvCfg(ctx context.Context, name string, cfgName string, query url.Values) (*OpenAPIEnvCfgDetail, error) {
+	var resp *OpenAPIEnvCfgDetail
+	err := c.do(ctx, "GET", "/openapi/environments/production/"+url.PathEscape(name)+"/envcfg/"+url.PathEscape(cfgName), query, nil, &resp)
+	return resp, err
+}
+
+// ListProductionCommonEnvCfg OpenAPI List Production Common Env Cfg
+//
+// GET /openapi/environments/production/{name}/envcfgs
+//   - query projectKey (required): project key
+//   - query type: type
+func (c *Client) ListProductionCommonEnvCfg(ctx context.Context, name string, query url.Values) ([]*OpenAPIEnvCfgBrief, error) {
+	var resp []*OpenAPIEnvCfgBrief
+	err := c.do(ctx, "GET", "/openapi/environments/production/"+url.PathEscape(name)+"/envcfgs", query, nil, &resp)
+	return resp, err
+}
+
+// CreateCommonEnvCfg OpenAPI Create Common Env Cfg
+//
+// POST /openapi/environments/production/{name}/envcfgs
+//   - query projectKey (required): project key
+func (c *Client) CreateCommonEnvCfg(ctx context.Context, name string, query url.Values, body *OpenAPIEnvCfgArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/production/"+url.PathEscape(name)+"/envcfgs", query, body, &resp)
+	return resp, err
+}
+
+// ProductionRestartService OpenAPI Production Restart Service
+//
+// POST /openapi/environments/production/{name}/service/{serviceName}/restart
+//   - query projectKey (required): project key
+func (c *Client) ProductionRestartService(ctx context.Context, name string, serviceName string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/production/"+url.PathEscape(name)+"/service/"+url.PathEscape(serviceName)+"/restart", query, nil, &resp)
+	return resp, err
+}
+
+// UpdateProductionYamlServices OpenAPI Update Production Yaml Services
+//
+// PUT /openapi/environments/production/{name}/services
+//   - query projectKey (required): project key
+func (c *Client) UpdateProductionYamlServices(ctx context.Context, name string, query url.Values, body *OpenAPIServiceVariablesReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/production/"+url.PathEscape(name)+"/services", query, body, &resp)
+	return resp, err
+}
+
+// GetProductionService OpenAPI Get Production Service
+//
+// GET /openapi/environments/production/{name}/services/{serviceName}
+//   - query projectName: project name
+//   - query workLoadType: work load type
+func (c *Client) GetProductionService(ctx context.Context, name string, serviceName string, query url.Values) (*OpenAPIGetServiceResponse, error) {
+	var resp *OpenAPIGetServiceResponse
+	err := c.do(ctx, "GET", "/openapi/environments/production/"+url.PathEscape(name)+"/services/"+url.PathEscape(serviceName), query, nil, &resp)
+	return resp, err
+}
+
+// GetProductionEnvGlobalVariables OpenAPI Get Production Env Global Variables
+//
+// GET /openapi/environments/production/{name}/variable
+//   - query projectKey (required): project key
+func (c *Client) GetProductionEnvGlobalVariables(ctx context.Context, name string, query url.Values) ([]*GlobalVariableKV, error) {
+	var resp []*GlobalVariableKV
+	err := c.do(ctx, "GET", "/openapi/environments/production/"+url.PathEscape(name)+"/variable", query, nil, &resp)
+	return resp, err
+}
+
+// UpdateProductionGlobalVariables OpenAPI Update Production K8S Environment Global Variables
+//
+// PUT /openapi/environments/production/{name}/variable
+//   - query projectKey (required): project key
+func (c *Client) UpdateProductionGlobalVariables(ctx context.Context, name string, query url.Values, body *OpenAPIEnvGlobalVariables) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/production/"+url.PathEscape(name)+"/variable", query, body, &resp)
+	return resp, err
+}
+
+// ScaleWorkloads OpenAPI Scale Workloads
+//
+// POST /openapi/environments/scale
+func (c *Client) ScaleWorkloads(ctx context.Context, query url.Values, body *OpenAPIScaleServiceReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/scale", query, body, &resp)
+	return resp, err
+}
+
+// DeleteYamlServiceFromEnv OpenAPI Delete Yaml Service From Env
+//
+// DELETE /openapi/environments/service/yaml
+//   - query projectKey (required): project key
+func (c *Client) DeleteYamlServiceFromEnv(ctx context.Context, query url.Values, body *OpenAPIDeleteYamlServiceFromEnvReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/environments/service/yaml", query, body, &resp)
+	return resp, err
+}
+
+// ApplyYamlService OpenAPI Apply Yaml Service
+//
+// POST /openapi/environments/service/yaml
+//   - query projectKey (required): project key
+func (c *Client) ApplyYamlService(ctx context.Context, query url.Values, body *OpenAPIApplyYamlServiceReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/service/yaml", query, body, &resp)
+	return resp, err
+}
+
+// DeleteEnv OpenAPI Delete Env
+//
+// DELETE /openapi/environments/{name}
+//   - query projectKey (required): project key
+//   - query isDelete: is delete
+func (c *Client) DeleteEnv(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/environments/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// GetEnvDetail OpenAPI Get Env Detail
+//
+// GET /openapi/environments/{name}
+//   - query projectKey (required): project key
+func (c *Client) GetEnvDetail(ctx context.Context, name string, query url.Values) (*OpenAPIEnvDetail, error) {
+	var resp *OpenAPIEnvDetail
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateEnvBasicInfo OpenAPI Update Env Basic Info
+//
+// PUT /openapi/environments/{name}
+//   - query projectKey (required): project key
+func (c *Client) UpdateEnvBasicInfo(ctx context.Context, name string, query url.Values, body *EnvBasicInfoArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/"+url.PathEscape(name), query, body, &resp)
+	return resp, err
+}
+
+// CheckShareEnvReady OpenAPI Check Share Env Ready
+//
+// GET /openapi/environments/{name}/check/sharenv/{op}/ready
+//   - query projectKey (required): project key
+func (c *Client) CheckShareEnvReady(ctx context.Context, name string, op string, query url.Values) (*OpenAPIShareEnvReadyResponse, error) {
+	var resp *OpenAPIShareEnvReadyResponse
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name)+"/check/sharenv/"+url.PathEscape(op)+"/ready", query, nil, &resp)
+	return resp, err
+}
+
+// CheckWorkloadsK8sServices OpenAPI Check Workloads K8s Services
+//
+// GET /openapi/environments/{name}/check/workloads/k8services
+//   - query projectKey (required): project key
+func (c *Client) CheckWorkloadsK8sServices(ctx context.Context, name string, query url.Values) ([]string, error) {
+	var resp []string
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name)+"/check/workloads/k8services", query, nil, &resp)
+	return resp, err
+}
+
+// DeleteCommonEnvCfg OpenAPI Delete Common Env Cfg
+//
+// DELETE /openapi/environments/{name}/envcfg/{cfgName}
+//   - query projectKey (required): project key
+//   - query type: type
+func (c *Client) DeleteCommonEnvCfg(ctx context.Context, name string, cfgName string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/environments/"+url.PathEscape(name)+"/envcfg/"+url.PathEscape(cfgName), query, nil, &resp)
+	return resp, err
+}
+
+// GetCommonEnvCfg OpenAPI Get Common Env Cfg
+//
+// GET /openapi/environments/{name}/envcfg/{cfgName}
+//   - query projectKey (required): project key
+//   - query type: type
+func (c *Client) GetCommonEnvCfg(ctx context.Context, name string, cfgName string, query url.Values) (*OpenAPIEnvCfgDetail, error) {
+	var resp *OpenAPIEnvCfgDetail
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name)+"/envcfg/"+url.PathEscape(cfgName), query, nil, &resp)
+	return resp, err
+}
+
+// ListCommonEnvCfg OpenAPI List Common Env Cfg
+//
+// GET /openapi/environments/{name}/envcfgs
+//   - query projectKey (required): project key
+//   - query type: type
+func (c *Client) ListCommonEnvCfg(ctx context.Context, name string, query url.Values) ([]*OpenAPIEnvCfgBrief, error) {
+	var resp []*OpenAPIEnvCfgBrief
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name)+"/envcfgs", query, nil, &resp)
+	return resp, err
+}
+
+// CreateCommonEnvCfg2 OpenAPI Create Common Env Cfg
+//
+// POST /openapi/environments/{name}/envcfgs
+//   - query projectKey (required): project key
+func (c *Client) CreateCommonEnvCfg2(ctx context.Context, name string, query url.Values, body *OpenAPIEnvCfgArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/"+url.PathEscape(name)+"/envcfgs", query, body, &resp)
+	return resp, err
+}
+
+// RestartService OpenAPI Restart Service
+//
+// POST /openapi/environments/{name}/service/{serviceName}/restart
+//   - query projectKey (required): project key
+func (c *Client) RestartService(ctx context.Context, name string, serviceName string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/"+url.PathEscape(name)+"/service/"+url.PathEscape(serviceName)+"/restart", query, nil, &resp)
+	return resp, err
+}
+
+// UpdateYamlServices OpenAPI Update Yaml Services
+//
+// PUT /openapi/environments/{name}/services
+//   - query projectKey (required): project key
+func (c *Client) UpdateYamlServices(ctx context.Context, name string, query url.Values, body *OpenAPIServiceVariablesReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/"+url.PathEscape(name)+"/services", query, body, &resp)
+	return resp, err
+}
+
+// GetService OpenAPI Get Service
+//
+// GET /openapi/environments/{name}/services/{serviceName}
+//   - query projectName: project name
+//   - query workLoadType: work load type
+func (c *Client) GetService(ctx context.Context, name string, serviceName string, query url.Values) (*OpenAPIGetServiceResponse, error) {
+	var resp *OpenAPIGetServiceResponse
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name)+"/services/"+url.PathEscape(serviceName), query, nil, &resp)
+	return resp, err
+}
+
+// DsiableBaseEnv OpenAPI Disable Base Env
+//
+// DELETE /openapi/environments/{name}/share/enable
+//   - query projectKey (required): project key
+func (c *Client) DsiableBaseEnv(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/environments/"+url.PathEscape(name)+"/share/enable", query, nil, &resp)
+	return resp, err
+}
+
+// EnableBaseEnv OpenAPI Enable Base Env
+//
+// POST /openapi/environments/{name}/share/enable
+//   - query projectKey (required): project key
+func (c *Client) EnableBaseEnv(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/"+url.PathEscape(name)+"/share/enable", query, nil, &resp)
+	return resp, err
+}
+
+// GetPortalService OpenAPI Get Portal Service
+//
+// GET /openapi/environments/{name}/share/portal/{serviceName}
+//   - query projectKey (required): project key
+func (c *Client) GetPortalService(ctx context.Context, name string, serviceName string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name)+"/share/portal/"+url.PathEscape(serviceName), query, nil, &resp)
+	return resp, err
+}
+
+// SetPortalService OpenAPI Set Portal Service
+//
+// POST /openapi/environments/{name}/share/portal/{serviceName}
+//   - query projectKey (required): project key
+func (c *Client) SetPortalService(ctx context.Context, name string, serviceName string, query url.Values, body []*OpenAPISetPortalServiceRequest) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/environments/"+url.PathEscape(name)+"/share/portal/"+url.PathEscape(serviceName), query, body, &resp)
+	return resp, err
+}
+
+// GetEnvGlobalVariables OpenAPI Get Env Global Variables
+//
+// GET /openapi/environments/{name}/variable
+//   - query projectKey (required): project key
+func (c *Client) GetEnvGlobalVariables(ctx context.Context, name string, query url.Values) ([]*GlobalVariableKV, error) {
+	var resp []*GlobalVariableKV
+	err := c.do(ctx, "GET", "/openapi/environments/"+url.PathEscape(name)+"/variable", query, nil, &resp)
+	return resp, err
+}
+
+// UpdateGlobalVariables OpenAPI Update K8S Environment Global Variables
+//
+// PUT /openapi/environments/{name}/variable
+//   - query projectKey (required): project key
+func (c *Client) UpdateGlobalVariables(ctx context.Context, name string, query url.Values, body *OpenAPIEnvGlobalVariables) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/environments/"+url.PathEscape(name)+"/variable", query, body, &resp)
+	return resp, err
+}
+
+// GetContainerLogsSSE OpenAPI Get Container Logs SSE
+//
+// GET /openapi/logs/sse/pods/{podName}/containers/{containerName}
+//   - query tails: tails
+//   - query envName: env name
+//   - query projectKey (required): project key
+func (c *Client) GetContainerLogsSSE(ctx context.Context, podName string, containerName string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "GET", "/openapi/logs/sse/pods/"+url.PathEscape(podName)+"/containers/"+url.PathEscape(containerName), query, nil, &resp)
+	return resp, err
+}
+
+// DeleteProject OpenAPI Delete Project
+//
+// DELETE /openapi/projects/project
+//   - query projectKey (required): project key
+//   - query isDelete: is delete
+func (c *Client) DeleteProject(ctx context.Context, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/projects/project", query, nil, &resp)
+	return resp, err
+}
+
+// ListProject OpenAPI List Project
+//
+// GET /openapi/projects/project
+//   - query pageSize: page size
+//   - query pageNum: page num
+func (c *Client) ListProject(ctx context.Context, query url.Values) (*ProjectResp, error) {
+	var resp *ProjectResp
+	err := c.do(ctx, "GET", "/openapi/projects/project", query, nil, &resp)
+	return resp, err
+}
+
+// CreateProductTemplate OpenAPI Create Product Template
+//
+// POST /openapi/projects/project
+func (c *Client) CreateProductTemplate(ctx context.Context, query url.Values, body *OpenAPICreateProductReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/projects/project", query, body, &resp)
+	return resp, err
+}
+
+// GetProjectDetail OpenAPI Get Project Detail
+//
+// GET /openapi/projects/project/detail
+//   - query projectKey (required): project key
+func (c *Client) GetProjectDetail(ctx context.Context, query url.Values) (*OpenAPIProjectDetailResp, error) {
+	var resp *OpenAPIProjectDetailResp
+	err := c.do(ctx, "GET", "/openapi/projects/project/detail", query, nil, &resp)
+	return resp, err
+}
+
+// GetGlobalVariables OpenAPI Get Global Variables
+//
+// GET /openapi/projects/project/globalVariable
+//   - query projectKey (required): project key
+func (c *Client) GetGlobalVariables(ctx context.Context, query url.Values) (*GlobalVariables, error) {
+	var resp *GlobalVariables
+	err := c.do(ctx, "GET", "/openapi/projects/project/globalVariable", query, nil, &resp)
+	return resp, err
+}
+
+// InitializeHelmProject OpenAPI Initialize Helm Project
+//
+// POST /openapi/projects/project/init/helm
+func (c *Client) InitializeHelmProject(ctx context.Context, query url.Values, body *OpenAPIInitializeProjectReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/projects/project/init/helm", query, body, &resp)
+	return resp, err
+}
+
+// InitializeYamlProject OpenAPI Initialize Yaml Project
+//
+// POST /openapi/projects/project/init/yaml
+func (c *Client) InitializeYamlProject(ctx context.Context, query url.Values, body *OpenAPIInitializeProjectReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/projects/project/init/yaml", query, body, &resp)
+	return resp, err
+}
+
+// CreateScanningModule OpenAPI Create Scanning Module
+//
+// POST /openapi/quality/codescan
+func (c *Client) CreateScanningModule(ctx context.Context, query url.Values, body *OpenAPICreateScanningReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/quality/codescan", query, body, &resp)
+	return resp, err
+}
+
+// CreateScanningTask2 OpenAPI Create Scanning Task
+//
+// POST /openapi/quality/codescan/{scanName}/task
+//   - query projectKey (required): project key
+func (c *Client) CreateScanningTask2(ctx context.Context, scanName string, query url.Values, body *OpenAPICreateScanningTaskReq) (*OpenAPICreateScanningTaskResp, error) {
+	var resp *OpenAPICreateScanningTaskResp
+	err := c.do(ctx, "POST", "/openapi/quality/codescan/"+url.PathEscape(scanName)+"/task", query, body, &resp)
+	return resp, err
+}
+
+// GetScanningTaskDetail2 OpenAPI Get Scanning Task Detail
+//
+// GET /openapi/quality/codescan/{scanName}/task/{taskID}
+//   - query projectKey (required): project key
+func (c *Client) GetScanningTaskDetail2(ctx context.Context, scanName string, taskID string, query url.Values) (*OpenAPIScanTaskDetail, error) {
+	var resp *OpenAPIScanTaskDetail
+	err := c.do(ctx, "GET", "/openapi/quality/codescan/"+url.PathEscape(scanName)+"/task/"+url.PathEscape(taskID), query, nil, &resp)
+	return resp, err
+}
+
+// CreateTestTask OpenAPI Create Test Task
+//
+// POST /openapi/quality/testing/task
+func (c *Client) CreateTestTask(ctx context.Context, query url.Values, body *OpenAPICreateTestTaskReq) (*OpenAPICreateTestTaskResp, error) {
+	var resp *OpenAPICreateTestTaskResp
+	err := c.do(ctx, "POST", "/openapi/quality/testing/task", query, body, &resp)
+	return resp, err
+}
+
+// GetTestTaskResult OpenAPI Get Test Task Result
+//
+// GET /openapi/quality/testing/{testName}/task/{taskID}
+//   - query projectKey (required): project key
+func (c *Client) GetTestTaskResult(ctx context.Context, testName string, taskID string, query url.Values) (*OpenAPITestTaskDetail, error) {
+	var resp *OpenAPITestTaskDetail
+	err := c.do(ctx, "GET", "/openapi/quality/testing/"+url.PathEscape(testName)+"/task/"+url.PathEscape(taskID), query, nil, &resp)
+	return resp, err
+}
+
+// ListReleasePlans OpenAPI List Release Plans
+//
+// GET /openapi/release_plan/v1
+//   - query pageNum (required): page num
+//   - query pageSize (required): page size
+func (c *Client) ListReleasePlans(ctx context.Context, query url.Values) (*OpenAPIListReleasePlanResp, error) {
+	var resp *OpenAPIListReleasePlanResp
+	err := c.do(ctx, "GET", "/openapi/release_plan/v1", query, nil, &resp)
+	return resp, err
+}
+
+// CreateReleasePlan OpenAPI Create Release Plan
+//
+// POST /openapi/release_plan/v1
+func (c *Client) CreateReleasePlan(ctx context.Context, query url.Values, body *OpenAPICreateReleasePlanArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/release_plan/v1", query, body, &resp)
+	return resp, err
+}
+
+// GetReleasePlan OpenAPI Get Release Plan
+//
+// GET /openapi/release_plan/v1/{id}
+func (c *Client) GetReleasePlan(ctx context.Context, id string, query url.Values) (*ReleasePlan, error) {
+	var resp *ReleasePlan
+	err := c.do(ctx, "GET", "/openapi/release_plan/v1/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// CreateClusterResource OpenAPI Create Cluster Resource
+//
+// POST /openapi/resources/clusters
+func (c *Client) CreateClusterResource(ctx context.Context, query url.Values, body *Cluster) (*Cluster, error) {
+	var resp *Cluster
+	err := c.do(ctx, "POST", "/openapi/resources/clusters", query, body, &resp)
+	return resp, err
+}
+
+// DeleteClusterResource OpenAPI Delete Cluster Resource
+//
+// DELETE /openapi/resources/clusters/{id}
+func (c *Client) DeleteClusterResource(ctx context.Context, id string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/resources/clusters/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// GetClusterResource OpenAPI Get Cluster Resource
+//
+// GET /openapi/resources/clusters/{id}
+func (c *Client) GetClusterResource(ctx context.Context, id string, query url.Values) (*Cluster, error) {
+	var resp *Cluster
+	err := c.do(ctx, "GET", "/openapi/resources/clusters/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateClusterResource OpenAPI Update Cluster Resource
+//
+// PUT /openapi/resources/clusters/{id}
+func (c *Client) UpdateClusterResource(ctx context.Context, id string, query url.Values, body *Cluster) (*Cluster, error) {
+	var resp *Cluster
+	err := c.do(ctx, "PUT", "/openapi/resources/clusters/"+url.PathEscape(id), query, body, &resp)
+	return resp, err
+}
+
+// CreateProjectResource OpenAPI Create Project Resource
+//
+// POST /openapi/resources/projects
+func (c *Client) CreateProjectResource(ctx context.Context, query url.Values, body *Project) (*Project, error) {
+	var resp *Project
+	err := c.do(ctx, "POST", "/openapi/resources/projects", query, body, &resp)
+	return resp, err
+}
+
+// DeleteProjectResource OpenAPI Delete Project Resource
+//
+// DELETE /openapi/resources/projects/{key}
+//   - query isDelete: whether to delete the resources of the environments
+func (c *Client) DeleteProjectResource(ctx context.Context, key string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/resources/projects/"+url.PathEscape(key), query, nil, &resp)
+	return resp, err
+}
+
+// GetProjectResource OpenAPI Get Project Resource
+//
+// GET /openapi/resources/projects/{key}
+func (c *Client) GetProjectResource(ctx context.Context, key string, query url.Values) (*Project, error) {
+	var resp *Project
+	err := c.do(ctx, "GET", "/openapi/resources/projects/"+url.PathEscape(key), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateProjectResource OpenAPI Update Project Resource
+//
+// PUT /openapi/resources/projects/{key}
+func (c *Client) UpdateProjectResource(ctx context.Context, key string, query url.Values, body *Project) (*Project, error) {
+	var resp *Project
+	err := c.do(ctx, "PUT", "/openapi/resources/projects/"+url.PathEscape(key), query, body, &resp)
+	return resp, err
+}
+
+// CreateEnvironmentResource OpenAPI Create Environment Resource
+//
+// POST /openapi/resources/projects/{key}/environments
+func (c *Client) CreateEnvironmentResource(ctx context.Context, key string, query url.Values, body *Environment) (*Environment, error) {
+	var resp *Environment
+	err := c.do(ctx, "POST", "/openapi/resources/projects/"+url.PathEscape(key)+"/environments", query, body, &resp)
+	return resp, err
+}
+
+// DeleteEnvironmentResource OpenAPI Delete Environment Resource
+//
+// DELETE /openapi/resources/projects/{key}/environments/{name}
+//   - query isDelete: whether to delete the namespace of the environment
+func (c *Client) DeleteEnvironmentResource(ctx context.Context, key string, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/resources/projects/"+url.PathEscape(key)+"/environments/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// GetEnvironmentResource OpenAPI Get Environment Resource
+//
+// GET /openapi/resources/projects/{key}/environments/{name}
+func (c *Client) GetEnvironmentResource(ctx context.Context, key string, name string, query url.Values) (*Environment, error) {
+	var resp *Environment
+	err := c.do(ctx, "GET", "/openapi/resources/projects/"+url.PathEscape(key)+"/environments/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateEnvironmentResource OpenAPI Update Environment Resource
+//
+// PUT /openapi/resources/projects/{key}/environments/{name}
+func (c *Client) UpdateEnvironmentResource(ctx context.Context, key string, name string, query url.Values, body *Environment) (*Environment, error) {
+	var resp *Environment
+	err := c.do(ctx, "PUT", "/openapi/resources/projects/"+url.PathEscape(key)+"/environments/"+url.PathEscape(name), query, body, &resp)
+	return resp, err
+}
+
+// CreateRegistryResource OpenAPI Create Registry Resource
+//
+// POST /openapi/resources/registries
+func (c *Client) CreateRegistryResource(ctx context.Context, query url.Values, body *Registry) (*Registry, error) {
+	var resp *Registry
+	err := c.do(ctx, "POST", "/openapi/resources/registries", query, body, &resp)
+	return resp, err
+}
+
+// DeleteRegistryResource OpenAPI Delete Registry Resource
+//
+// DELETE /openapi/resources/registries/{id}
+func (c *Client) DeleteRegistryResource(ctx context.Context, id string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/resources/registries/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// GetRegistryResource OpenAPI Get Registry Resource
+//
+// GET /openapi/resources/registries/{id}
+func (c *Client) GetRegistryResource(ctx context.Context, id string, query url.Values) (*Registry, error) {
+	var resp *Registry
+	err := c.do(ctx, "GET", "/openapi/resources/registries/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateRegistryResource OpenAPI Update Registry Resource
+//
+// PUT /openapi/resources/registries/{id}
+func (c *Client) UpdateRegistryResource(ctx context.Context, id string, query url.Values, body *Registry) (*Registry, error) {
+	var resp *Registry
+	err := c.do(ctx, "PUT", "/openapi/resources/registries/"+url.PathEscape(id), query, body, &resp)
+	return resp, err
+}
+
+// CreateWorkflowResource OpenAPI Create Workflow Resource
+//
+// POST /openapi/resources/workflows
+func (c *Client) CreateWorkflowResource(ctx context.Context, query url.Values, body *Workflow) (*Workflow, error) {
+	var resp *Workflow
+	err := c.do(ctx, "POST", "/openapi/resources/workflows", query, body, &resp)
+	return resp, err
+}
+
+// DeleteWorkflowResource OpenAPI Delete Workflow Resource
+//
+// DELETE /openapi/resources/workflows/{name}
+func (c *Client) DeleteWorkflowResource(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/resources/workflows/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// GetWorkflowResource OpenAPI Get Workflow Resource
+//
+// GET /openapi/resources/workflows/{name}
+func (c *Client) GetWorkflowResource(ctx context.Context, name string, query url.Values) (*Workflow, error) {
+	var resp *Workflow
+	err := c.do(ctx, "GET", "/openapi/resources/workflows/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateWorkflowResource OpenAPI Update Workflow Resource
+//
+// PUT /openapi/resources/workflows/{name}
+func (c *Client) UpdateWorkflowResource(ctx context.Context, name string, query url.Values, body *Workflow) (*Workflow, error) {
+	var resp *Workflow
+	err := c.do(ctx, "PUT", "/openapi/resources/workflows/"+url.PathEscape(name), query, body, &resp)
+	return resp, err
+}
+
+// ListScanningModules OpenAPI List Scanning Modules
+//
+// GET /openapi/scanning
+//   - query projectKey (required): project key
+func (c *Client) ListScanningModules(ctx context.Context, query url.Values) (*OpenAPIListScanningResp, error) {
+	var resp *OpenAPIListScanningResp
+	err := c.do(ctx, "GET", "/openapi/scanning", query, nil, &resp)
+	return resp, err
+}
+
+// CreateScanningModuleFromYaml OpenAPI Create Scanning Module From Yaml
+//
+// POST /openapi/scanning
+func (c *Client) CreateScanningModuleFromYaml(ctx context.Context, query url.Values, body interface{}) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/scanning", query, body, &resp)
+	return resp, err
+}
+
+// DeleteScanningModule OpenAPI Delete Scanning Module
+//
+// DELETE /openapi/scanning/{scanName}
+//   - query projectKey (required): project key
+func (c *Client) DeleteScanningModule(ctx context.Context, scanName string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/scanning/"+url.PathEscape(scanName), query, nil, &resp)
+	return resp, err
+}
+
+// GetScanningModule OpenAPI Get Scanning Module
+//
+// GET /openapi/scanning/{scanName}
+//   - query projectKey (required): project key
+func (c *Client) GetScanningModule(ctx context.Context, scanName string, query url.Values) (*OpenAPIScanningDetail, error) {
+	var resp *OpenAPIScanningDetail
+	err := c.do(ctx, "GET", "/openapi/scanning/"+url.PathEscape(scanName), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateScanningModule OpenAPI Update Scanning Module
+//
+// PUT /openapi/scanning/{scanName}
+//   - query projectKey (required): project key
+func (c *Client) UpdateScanningModule(ctx context.Context, scanName string, query url.Values, body interface{}) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/scanning/"+url.PathEscape(scanName), query, body, &resp)
+	return resp, err
+}
+
+// CreateScanningTask OpenAPI Create Scanning Task
+//
+// POST /openapi/scanning/{scanName}/task
+//   - query projectKey (required): project key
+func (c *Client) CreateScanningTask(ctx context.Context, scanName string, query url.Values, body *OpenAPICreateScanningTaskReq) (*OpenAPICreateScanningTaskResp, error) {
+	var resp *OpenAPICreateScanningTaskResp
+	err := c.do(ctx, "POST", "/openapi/scanning/"+url.PathEscape(scanName)+"/task", query, body, &resp)
+	return resp, err
+}
+
+// GetScanningTaskDetail OpenAPI Get Scanning Task Detail
+//
+// GET /openapi/scanning/{scanName}/task/{taskID}
+//   - query projectKey (required): project key
+func (c *Client) GetScanningTaskDetail(ctx context.Context, scanName string, taskID string, query url.Values) (*OpenAPIScanTaskDetail, error) {
+	var resp *OpenAPIScanTaskDetail
+	err := c.do(ctx, "GET", "/openapi/scanning/"+url.PathEscape(scanName)+"/task/"+url.PathEscape(taskID), query, nil, &resp)
+	return resp, err
+}
+
+// GetScanningTaskResult OpenAPI Get Scanning Task Result
+//
+// GET /openapi/scanning/{scanName}/task/{taskID}/result
+//   - query projectKey (required): project key
+func (c *Client) GetScanningTaskResult(ctx context.Context, scanName string, taskID string, query url.Values) (*OpenAPIScanTaskResult, error) {
+	var resp *OpenAPIScanTaskResult
+	err := c.do(ctx, "GET", "/openapi/scanning/"+url.PathEscape(scanName)+"/task/"+url.PathEscape(taskID)+"/result", query, nil, &resp)
+	return resp, err
+}
+
+// LoadServiceFromYamlTemplateOpenAPI OpenAPI Load Service From Yaml Template
+//
+// POST /openapi/service/template/load/yaml
+func (c *Client) LoadServiceFromYamlTemplateOpenAPI(ctx context.Context, query url.Values, body *OpenAPILoadServiceFromYamlTemplateReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/service/template/load/yaml", query, body, &resp)
+	return resp, err
+}
+
+// LoadProductionServiceFromYamlTemplateOpenAPI OpenAPI Load Production Service From Yaml Template
+//
+// POST /openapi/service/template/production/load/yaml
+func (c *Client) LoadProductionServiceFromYamlTemplateOpenAPI(ctx context.Context, query url.Values, body *OpenAPILoadServiceFromYamlTemplateReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/service/template/production/load/yaml", query, body, &resp)
+	return resp, err
+}
+
+// CreateRawProductionYamlServicesOpenAPI OpenAPI Create Raw Production Yaml Services
+//
+// POST /openapi/service/yaml/production/raw
+//   - query projectKey (required): project key
+func (c *Client) CreateRawProductionYamlServicesOpenAPI(ctx context.Context, query url.Values, body *OpenAPICreateYamlServiceReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/service/yaml/production/raw", query, body, &resp)
+	return resp, err
+}
+
+// ListProductionYamlServicesOpenAPI OpenAPI List Production Yaml Services
+//
+// GET /openapi/service/yaml/production/services
+//   - query projectKey (required): project key
+func (c *Client) ListProductionYamlServicesOpenAPI(ctx context.Context, query url.Values) ([]*OpenAPIServiceBrief, error) {
+	var resp []*OpenAPIServiceBrief
+	err := c.do(ctx, "GET", "/openapi/service/yaml/production/services", query, nil, &resp)
+	return resp, err
+}
+
+// DeleteProductionServicesOpenAPI OpenAPI Delete Production Services
+//
+// DELETE /openapi/service/yaml/production/{name}
+//   - query projectKey (required): project key
+func (c *Client) DeleteProductionServicesOpenAPI(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/service/yaml/production/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// GetProductionYamlServiceOpenAPI OpenAPI Get Production Yaml Service
+//
+// GET /openapi/service/yaml/production/{name}
+//   - query projectKey (required): project key
+func (c *Client) GetProductionYamlServiceOpenAPI(ctx context.Context, name string, query url.Values) (*OpenAPIGetYamlServiceResp, error) {
+	var resp *OpenAPIGetYamlServiceResp
+	err := c.do(ctx, "GET", "/openapi/service/yaml/production/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateProductionServiceConfigOpenAPI OpenAPI Update Production Service Config
+//
+// PUT /openapi/service/yaml/production/{name}
+//   - query projectKey (required): project key
+func (c *Client) UpdateProductionServiceConfigOpenAPI(ctx context.Context, name string, query url.Values, body *OpenAPIUpdateServiceConfigArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/service/yaml/production/"+url.PathEscape(name), query, body, &resp)
+	return resp, err
+}
+
+// UpdateProductionServiceVariableOpenAPI OpenAPI Update Production Service Variable
+//
+// PUT /openapi/service/yaml/production/{name}/variable
+//   - query projectKey (required): project key
+func (c *Client) UpdateProductionServiceVariableOpenAPI(ctx context.Context, name string, query url.Values, body *OpenAPIUpdateServiceVariableRequest) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/service/yaml/production/"+url.PathEscape(name)+"/variable", query, body, &resp)
+	return resp, err
+}
+
+// CreateRawYamlServicesOpenAPI OpenAPI Create Raw Yaml Services
+//
+// POST /openapi/service/yaml/raw
+//   - query projectKey (required): project key
+func (c *Client) CreateRawYamlServicesOpenAPI(ctx context.Context, query url.Values, body *OpenAPICreateYamlServiceReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/service/yaml/raw", query, body, &resp)
+	return resp, err
+}
+
+// ListYamlServicesOpenAPI OpenAPI List Yaml Services
+//
+// GET /openapi/service/yaml/services
+//   - query projectKey (required): project key
+func (c *Client) ListYamlServicesOpenAPI(ctx context.Context, query url.Values) ([]*OpenAPIServiceBrief, error) {
+	var resp []*OpenAPIServiceBrief
+	err := c.do(ctx, "GET", "/openapi/service/yaml/services", query, nil, &resp)
+	return resp, err
+}
+
+// DeleteYamlServicesOpenAPI OpenAPI Delete Yaml Services
+//
+// DELETE /openapi/service/yaml/{name}
+//   - query projectKey (required): project key
+func (c *Client) DeleteYamlServicesOpenAPI(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/service/yaml/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// GetYamlServiceOpenAPI OpenAPI Get Yaml Service
+//
+// GET /openapi/service/yaml/{name}
+//   - query projectKey (required): project key
+func (c *Client) GetYamlServiceOpenAPI(ctx context.Context, name string, query url.Values) (*OpenAPIGetYamlServiceResp, error) {
+	var resp *OpenAPIGetYamlServiceResp
+	err := c.do(ctx, "GET", "/openapi/service/yaml/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateServiceConfigOpenAPI OpenAPI Update Service Config
+//
+// PUT /openapi/service/yaml/{name}
+//   - query projectKey (required): project key
+func (c *Client) UpdateServiceConfigOpenAPI(ctx context.Context, name string, query url.Values, body *OpenAPIUpdateServiceConfigArgs) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/service/yaml/"+url.PathEscape(name), query, body, &resp)
+	return resp, err
+}
+
+// UpdateServiceVariableOpenAPI OpenAPI Update Service Variable
+//
+// PUT /openapi/service/yaml/{name}/variable
+//   - query projectKey (required): project key
+func (c *Client) UpdateServiceVariableOpenAPI(ctx context.Context, name string, query url.Values, body *OpenAPIUpdateServiceVariableRequest) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/service/yaml/"+url.PathEscape(name)+"/variable", query, body, &resp)
+	return resp, err
+}
+
+// GetBuildStatForOpenAPI OpenAPI Get Build Stat
+//
+// GET /openapi/statistics/build
+//   - query startDate: start date
+//   - query endDate: end date
+//   - query projectKey (required): project key
+func (c *Client) GetBuildStatForOpenAPI(ctx context.Context, query url.Values) (*DashboardBuild, error) {
+	var resp *DashboardBuild
+	err := c.do(ctx, "GET", "/openapi/statistics/build", query, nil, &resp)
+	return resp, err
+}
+
+// GetDeployStatsOpenAPI OpenAPI Get Deploy Stats
+//
+// GET /openapi/statistics/deploy
+//   - query startDate: start date
+//   - query endDate: end date
+//   - query projectKey (required): project key
+func (c *Client) GetDeployStatsOpenAPI(ctx context.Context, query url.Values) (*DeployDashboard, error) {
+	var resp *DeployDashboard
+	err := c.do(ctx, "GET", "/openapi/statistics/deploy", query, nil, &resp)
+	return resp, err
+}
+
+// GetOverviewStat OpenAPI Get Overview Stat
+//
+// GET /openapi/statistics/overview
+func (c *Client) GetOverviewStat(ctx context.Context, query url.Values) (*Overview, error) {
+	var resp *Overview
+	err := c.do(ctx, "GET", "/openapi/statistics/overview", query, nil, &resp)
+	return resp, err
+}
+
+// GetTestStatOpenAPI OpenAPI Get Test Stat
+//
+// GET /openapi/statistics/test
+//   - query startDate: start date
+//   - query endDate: end date
+//   - query projectKey (required): project key
+func (c *Client) GetTestStatOpenAPI(ctx context.Context, query url.Values) (*OpenAPITestStatResp, error) {
+	var resp *OpenAPITestStatResp
+	err := c.do(ctx, "GET", "/openapi/statistics/test", query, nil, &resp)
+	return resp, err
+}
+
+// GetReleaseStatOpenAPI OpenAPI Get Release Stat
+//
+// GET /openapi/statistics/v2/release
+//   - query startTime: start time
+//   - query endTime: end time
+//   - query projectKey (required): project key
+func (c *Client) GetReleaseStatOpenAPI(ctx context.Context, query url.Values) (*OpenAPIStatV2, error) {
+	var resp *OpenAPIStatV2
+	err := c.do(ctx, "GET", "/openapi/statistics/v2/release", query, nil, &resp)
+	return resp, err
+}
+
+// GetSpec Get OpenAPI Spec
+//
+// GET /openapi/system/apispec
+//   - query version: zadig version, the running version is used if not specified
+func (c *Client) GetSpec(ctx context.Context, query url.Values) (*Document, error) {
+	var resp *Document
+	err := c.do(ctx, "GET", "/openapi/system/apispec", query, nil, &resp)
+	return resp, err
+}
+
+// DiffSpec Diff OpenAPI Spec
+//
+// GET /openapi/system/apispec/diff
+//   - query base (required): base version
+//   - query target: target version, the running version is used if not specified
+func (c *Client) DiffSpec(ctx context.Context, query url.Values) (*Diff, error) {
+	var resp *Diff
+	err := c.do(ctx, "GET", "/openapi/system/apispec/diff", query, nil, &resp)
+	return resp, err
+}
+
+// ListSpecVersions List OpenAPI Spec Versions
+//
+// GET /openapi/system/apispec/versions
+func (c *Client) ListSpecVersions(ctx context.Context, query url.Values) ([]*OpenAPISpecSnapshot, error) {
+	var resp []*OpenAPISpecSnapshot
+	err := c.do(ctx, "GET", "/openapi/system/apispec/versions", query, nil, &resp)
+	return resp, err
+}
+
+// ListCluster OpenAPI List Cluster
+//
+// GET /openapi/system/cluster
+//   - query projectName: project name
+func (c *Client) ListCluster(ctx context.Context, query url.Values) ([]*OpenAPICluster, error) {
+	var resp []*OpenAPICluster
+	err := c.do(ctx, "GET", "/openapi/system/cluster", query, nil, &resp)
+	return resp, err
+}
+
+// CreateCluster OpenAPI Create Cluster
+//
+// POST /openapi/system/cluster
+func (c *Client) CreateCluster(ctx context.Context, query url.Values, body *OpenAPICreateClusterRequest) (*OpenAPICreateClusterResponse, error) {
+	var resp *OpenAPICreateClusterResponse
+	err := c.do(ctx, "POST", "/openapi/system/cluster", query, body, &resp)
+	return resp, err
+}
+
+// DeleteCluster OpenAPI Delete Cluster
+//
+// DELETE /openapi/system/cluster/{id}
+func (c *Client) DeleteCluster(ctx context.Context, id string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/system/cluster/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateCluster OpenAPI Update Cluster
+//
+// PUT /openapi/system/cluster/{id}
+func (c *Client) UpdateCluster(ctx context.Context, id string, query url.Values, body *OpenAPICluster) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/system/cluster/"+url.PathEscape(id), query, body, &resp)
+	return resp, err
+}
+
+// ListRegistry OpenAPI List Registry
+//
+// GET /openapi/system/registry
+func (c *Client) ListRegistry(ctx context.Context, query url.Values) ([]*OpenAPIRegistry, error) {
+	var resp []*OpenAPIRegistry
+	err := c.do(ctx, "GET", "/openapi/system/registry", query, nil, &resp)
+	return resp, err
+}
+
+// CreateRegistry OpenAPI Create Registry
+//
+// POST /openapi/system/registry
+func (c *Client) CreateRegistry(ctx context.Context, query url.Values, body *OpenAPICreateRegistryReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/system/registry", query, body, &resp)
+	return resp, err
+}
+
+// GetRegistry OpenAPI Get Registry
+//
+// GET /openapi/system/registry/{id}
+func (c *Client) GetRegistry(ctx context.Context, id string, query url.Values) (*OpenAPIRegistry, error) {
+	var resp *OpenAPIRegistry
+	err := c.do(ctx, "GET", "/openapi/system/registry/"+url.PathEscape(id), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateRegistry OpenAPI Update Registry
+//
+// PUT /openapi/system/registry/{id}
+func (c *Client) UpdateRegistry(ctx context.Context, id string, query url.Values, body *OpenAPIRegistry) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/system/registry/"+url.PathEscape(id), query, body, &resp)
+	return resp, err
+}
+
+// GetWorkflowV4List OpenAPI Get Workflow V4 List
+//
+// GET /openapi/workflows
+//   - query projectKey (required): project key
+//   - query viewName: view name
+func (c *Client) GetWorkflowV4List(ctx context.Context, query url.Values) (*OpenAPIWorkflowListResp, error) {
+	var resp *OpenAPIWorkflowListResp
+	err := c.do(ctx, "GET", "/openapi/workflows", query, nil, &resp)
+	return resp, err
+}
+
+// DeleteCustomWorkflowV4 OpenAPI Delete Custom Workflow V4
+//
+// DELETE /openapi/workflows/custom
+//   - query workflowKey: workflow key
+//   - query projectKey (required): project key
+func (c *Client) DeleteCustomWorkflowV4(ctx context.Context, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/workflows/custom", query, nil, &resp)
+	return resp, err
+}
+
+// CancelWorkflowTaskV4 OpenAPI Cancel Workflow Task V4
+//
+// DELETE /openapi/workflows/custom/task
+func (c *Client) CancelWorkflowTaskV4(ctx context.Context, query url.Values, body *GetworkflowTaskReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/workflows/custom/task", query, body, &resp)
+	return resp, err
+}
+
+// GetWorkflowTaskV4 OpenAPI Get Workflow Task V4
+//
+// GET /openapi/workflows/custom/task
+//   - query taskId: task id
+//   - query workflowKey: workflow key
+func (c *Client) GetWorkflowTaskV4(ctx context.Context, query url.Values) (*WorkflowTaskPreview, error) {
+	var resp *WorkflowTaskPreview
+	err := c.do(ctx, "GET", "/openapi/workflows/custom/task", query, nil, &resp)
+	return resp, err
+}
+
+// CreateCustomWorkflowTask OpenAPI Create Custom Workflow Task
+//
+// POST /openapi/workflows/custom/task
+func (c *Client) CreateCustomWorkflowTask(ctx context.Context, query url.Values, body *OpenAPICreateCustomWorkflowTaskArgs) (*CreateTaskV4Resp, error) {
+	var resp *CreateTaskV4Resp
+	err := c.do(ctx, "POST", "/openapi/workflows/custom/task", query, body, &resp)
+	return resp, err
+}
+
+// ApproveStage OpenAPI Approve Stage
+//
+// POST /openapi/workflows/custom/task/approve
+func (c *Client) ApproveStage(ctx context.Context, query url.Values, body *OpenAPIApproveRequest) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/workflows/custom/task/approve", query, body, &resp)
+	return resp, err
+}
+
+// GetCustomWorkflowV4 OpenAPI Get Custom Workflow V4
+//
+// GET /openapi/workflows/custom/{name}/detail
+//   - query projectKey (required): project key
+func (c *Client) GetCustomWorkflowV4(ctx context.Context, name string, query url.Values) (*OpenAPIWorkflowV4Detail, error) {
+	var resp *OpenAPIWorkflowV4Detail
+	err := c.do(ctx, "GET", "/openapi/workflows/custom/"+url.PathEscape(name)+"/detail", query, nil, &resp)
+	return resp, err
+}
+
+// RetryCustomWorkflowTaskV4 OpenAPI Retry Custom Workflow Task V4
+//
+// POST /openapi/workflows/custom/{name}/task/{taskID}
+//   - query projectKey (required): project key
+func (c *Client) RetryCustomWorkflowTaskV4(ctx context.Context, name string, taskID string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/workflows/custom/"+url.PathEscape(name)+"/task/"+url.PathEscape(taskID), query, nil, &resp)
+	return resp, err
+}
+
+// GetCustomWorkflowTaskV4 OpenAPI Get Custom Workflow Task V4
+//
+// GET /openapi/workflows/custom/{name}/tasks
+//   - query projectKey (required): project key
+//   - query pageNum: page num
+//   - query pageSize: page size
+func (c *Client) GetCustomWorkflowTaskV4(ctx context.Context, name string, query url.Values) (*OpenAPIWorkflowV4TaskListResp, error) {
+	var resp *OpenAPIWorkflowV4TaskListResp
+	err := c.do(ctx, "GET", "/openapi/workflows/custom/"+url.PathEscape(name)+"/tasks", query, nil, &resp)
+	return resp, err
+}
+
+// DeleteProductWorkflowV4 OpenAPI Delete Product Workflow V4
+//
+// DELETE /openapi/workflows/product
+//   - query workflowKey: workflow key
+//   - query projectKey (required): project key
+func (c *Client) DeleteProductWorkflowV4(ctx context.Context, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/workflows/product", query, nil, &resp)
+	return resp, err
+}
+
+// CreateProductWorkflowTask OpenAPI Create Product Workflow Task
+//
+// POST /openapi/workflows/product/task
+func (c *Client) CreateProductWorkflowTask(ctx context.Context, query url.Values, body *OpenAPICreateProductWorkflowTaskArgs) (*CreateTaskResp, error) {
+	var resp *CreateTaskResp
+	err := c.do(ctx, "POST", "/openapi/workflows/product/task", query, body, &resp)
+	return resp, err
+}
+
+// GetProductWorkflowTaskV4 OpenAPI Get Product Workflow Task V4
+//
+// GET /openapi/workflows/product/{name}/task/{taskID}
+//   - query projectKey (required): project key
+func (c *Client) GetProductWorkflowTaskV4(ctx context.Context, name string, taskID string, query url.Values) (*OpenAPIProductWorkflowTaskDetail, error) {
+	var resp *OpenAPIProductWorkflowTaskDetail
+	err := c.do(ctx, "GET", "/openapi/workflows/product/"+url.PathEscape(name)+"/task/"+url.PathEscape(taskID), query, nil, &resp)
+	return resp, err
+}
+
+// GetProductWorkflowTasksV4 OpenAPI Get Product Workflow Tasks V4
+//
+// GET /openapi/workflows/product/{name}/tasks
+//   - query projectKey (required): project key
+//   - query pageNum: page num
+//   - query pageSize: page size
+func (c *Client) GetProductWorkflowTasksV4(ctx context.Context, name string, query url.Values) ([]*OpenAPIProductWorkflowTaskBrief, error) {
+	var resp []*OpenAPIProductWorkflowTaskBrief
+	err := c.do(ctx, "GET", "/openapi/workflows/product/"+url.PathEscape(name)+"/tasks", query, nil, &resp)
+	return resp, err
+}
+
+// GetWorkflowViews OpenAPI Get Workflow Views
+//
+// GET /openapi/workflows/view
+//   - query projectKey (required): project key
+func (c *Client) GetWorkflowViews(ctx context.Context, query url.Values) ([]*OpenAPIWorkflowViewBrief, error) {
+	var resp []*OpenAPIWorkflowViewBrief
+	err := c.do(ctx, "GET", "/openapi/workflows/view", query, nil, &resp)
+	return resp, err
+}
+
+// CreateWorkflowView OpenAPI Create Workflow View
+//
+// POST /openapi/workflows/view
+func (c *Client) CreateWorkflowView(ctx context.Context, query url.Values, body *OpenAPICreateWorkflowViewReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "POST", "/openapi/workflows/view", query, body, &resp)
+	return resp, err
+}
+
+// DeleteWorkflowView OpenAPI Delete Workflow View
+//
+// DELETE /openapi/workflows/view/{name}
+//   - query projectKey (required): project key
+func (c *Client) DeleteWorkflowView(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "DELETE", "/openapi/workflows/view/"+url.PathEscape(name), query, nil, &resp)
+	return resp, err
+}
+
+// UpdateWorkflowView OpenAPI Update Workflow View
+//
+// PUT /openapi/workflows/view/{name}
+//   - query projectKey (required): project key
+func (c *Client) UpdateWorkflowView(ctx context.Context, name string, query url.Values, body *OpenAPICreateWorkflowViewReq) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.do(ctx, "PUT", "/openapi/workflows/view/"+url.PathEscape(name), query, body, &resp)
+	return resp, err
+}
@@ -0,0 +1,3 @@
+from .client import Client, ZadigError
+
+__all__ = ["Client", "ZadigError"]
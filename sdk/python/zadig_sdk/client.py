@@ -0,0 +1,1400 @@
+# Code generated by apispec. DO NOT EDIT.
+"""Client of the Zadig OpenAPI dev."""
+
+from urllib.parse import quote
+
+import requests
+
+
+class ZadigError(Exception):
+    """Raised if the status code of the response is not 2xx."""
+
+    def __init__(self, status_code, body):
+        super().__init__("zadig openapi returns %d: %s" % (status_code, body))
+        self.status_code = status_code
+        self.body = body
+
+
+class Client:
+    """Calls the Zadig OpenAPI with an API token.
+
+    :param address: the address of Zadig, for example: https://zadig.example.com
+    :param token: the API token of the user
+    """
+
+    def __init__(self, address, token, session=None, timeout=60):
+        self.address = address.rstrip("/")
+        self.token = token
+        self.session = session or requests.Session()
+        self.timeout = timeout
+
+    def _request(self, method, path, query=None, body=None):
+        headers = {"Authorization": "Bearer " + self.token}
+        resp = self.session.request(
+            method,
+            self.address + path,
+            params=query,
+            json=body,
+            headers=headers,
+            timeout=self.timeout,
+        )
+        if resp.status_code < 200 or resp.status_code >= 300:
+            raise ZadigError(resp.status_code, resp.text)
+        if not resp.content:
+            return None
+        try:
+            return resp.json()
+        except ValueError:
+            return resp.text
+
+    def delete_build_module(self, query=None):
+        """OpenAPI Delete Build Module
+
+        DELETE /openapi/build
+
+        Query parameters:
+            name: name
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/build", query)
+
+    def list_build_modules(self, query=None):
+        """OpenAPI List Build Modules
+
+        GET /openapi/build
+
+        Query parameters:
+            projectKey (required): project key
+            pageNum: page num
+            pageSize: page size
+        """
+        return self._request("GET", "/openapi/build", query)
+
+    def create_build_module(self, query=None, body=None):
+        """OpenAPI Create Build Module
+
+        POST /openapi/build
+
+        Query parameters:
+            source: source
+        """
+        return self._request("POST", "/openapi/build", query, body)
+
+    def get_build_module(self, name, query=None):
+        """OpenAPI Get Build Module
+
+        GET /openapi/build/{name}/detail
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/build/%s/detail" % (quote(str(name), safe=""),), query)
+
+    def check_istiod(self, id, query=None):
+        """OpenAPI Check Istiod
+
+        GET /openapi/cluster/istio/check/{id}
+        """
+        return self._request("GET", "/openapi/cluster/istio/check/%s" % (quote(str(id), safe=""),), query)
+
+    def list_delivery_version(self, query=None):
+        """OpenAPI List Delivery Version
+
+        GET /openapi/delivery/releases
+
+        Query parameters:
+            projectKey (required): project key
+            pageNum (required): page num
+            pageSize (required): page size
+        """
+        return self._request("GET", "/openapi/delivery/releases", query)
+
+    def create_helm_delivery_version(self, query=None, body=None):
+        """OpenAPI Create Helm Delivery Version
+
+        POST /openapi/delivery/releases/helm
+        """
+        return self._request("POST", "/openapi/delivery/releases/helm", query, body)
+
+    def create_k8s_delivery_version(self, query=None, body=None):
+        """OpenAPI Create K8S Delivery Version
+
+        POST /openapi/delivery/releases/k8s
+        """
+        return self._request("POST", "/openapi/delivery/releases/k8s", query, body)
+
+    def delete_delivery_version(self, id, query=None):
+        """OpenAPI Delete Delivery Version
+
+        DELETE /openapi/delivery/releases/{id}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/delivery/releases/%s" % (quote(str(id), safe=""),), query)
+
+    def get_delivery_version(self, id, query=None):
+        """OpenAPI Get Delivery Version
+
+        GET /openapi/delivery/releases/{id}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/delivery/releases/%s" % (quote(str(id), safe=""),), query)
+
+    def list_envs(self, query=None):
+        """OpenAPI List Envs
+
+        GET /openapi/environments
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments", query)
+
+    def create_k8s_env(self, query=None, body=None):
+        """OpenAPI Create K8s Env
+
+        POST /openapi/environments
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments", query, body)
+
+    def update_common_env_cfg(self, query=None, body=None):
+        """OpenAPI Update Common Env Cfg
+
+        PUT /openapi/environments/envcfgs
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/envcfgs", query, body)
+
+    def update_cron_job_container_image(self, env_name, query=None, body=None):
+        """OpenAPI Update Cron Job Container Image
+
+        POST /openapi/environments/image/cronjob/{envName}
+        """
+        return self._request("POST", "/openapi/environments/image/cronjob/%s" % (quote(str(env_name), safe=""),), query, body)
+
+    def update_deployment_container_image(self, env_name, query=None, body=None):
+        """OpenAPI Update Deployment Container Image
+
+        POST /openapi/environments/image/deployment/{envName}
+        """
+        return self._request("POST", "/openapi/environments/image/deployment/%s" % (quote(str(env_name), safe=""),), query, body)
+
+    def update_stateful_set_container_image(self, env_name, query=None, body=None):
+        """OpenAPI Update Stateful Set Container Image
+
+        POST /openapi/environments/image/statefulset/{envName}
+        """
+        return self._request("POST", "/openapi/environments/image/statefulset/%s" % (quote(str(env_name), safe=""),), query, body)
+
+    def list_kube_events(self, query=None):
+        """OpenAPI List Kube Events
+
+        GET /openapi/environments/kube/events
+
+        Query parameters:
+            envName: env name
+            projectKey (required): project key
+            name: name
+            type: type
+        """
+        return self._request("GET", "/openapi/environments/kube/events", query)
+
+    def list_production_envs(self, query=None):
+        """OpenAPI List Production Envs
+
+        GET /openapi/environments/production
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/production", query)
+
+    def create_production_env(self, query=None, body=None):
+        """OpenAPI Create Production Env
+
+        POST /openapi/environments/production
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/production", query, body)
+
+    def update_production_common_env_cfg(self, query=None, body=None):
+        """OpenAPI Update Production Common Env Cfg
+
+        PUT /openapi/environments/production/envcfgs
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/production/envcfgs", query, body)
+
+    def delete_production_yaml_service_from_env(self, query=None, body=None):
+        """OpenAPI Delete Production Yaml Service From Env
+
+        DELETE /openapi/environments/production/service/yaml
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/environments/production/service/yaml", query, body)
+
+    def apply_production_yaml_service(self, query=None, body=None):
+        """OpenAPI Apply Production Yaml Service
+
+        POST /openapi/environments/production/service/yaml
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/production/service/yaml", query, body)
+
+    def delete_production_env(self, name, query=None):
+        """OpenAPI Delete Production Env
+
+        DELETE /openapi/environments/production/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/environments/production/%s" % (quote(str(name), safe=""),), query)
+
+    def get_production_env_detail(self, name, query=None):
+        """OpenAPI Get Production Env Detail
+
+        GET /openapi/environments/production/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/production/%s" % (quote(str(name), safe=""),), query)
+
+    def update_production_env_basic_info(self, name, query=None, body=None):
+        """OpenAPI Update Production Env Basic Info
+
+        PUT /openapi/environments/production/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/production/%s" % (quote(str(name), safe=""),), query, body)
+
+    def delete_production_env_common_env_cfg(self, name, cfg_name, query=None):
+        """OpenAPI Delete Production Env Common Env Cfg
+
+        DELETE /openapi/environments/production/{name}/envcfg/{cfgName}
+
+        Query parameters:
+            projectKey (required): project key
+            type: type
+        """
+        return self._request("DELETE", "/openapi/environments/production/%s/envcfg/%s" % (quote(str(name), safe=""), quote(str(cfg_name), safe=""),), query)
+
+    def get_production_common_env_cfg(self, name, cfg_name, query=None):
+        """OpenAPI Get Production Common Env Cfg
+
+        GET /openapi/environments/production/{name}/envcfg/{cfgName}
+
+        Query parameters:
+            projectKey (required): project key
+            type: type
+        """
+        return self._request("GET", "/openapi/environments/production/%s/envcfg/%s" % (quote(str(name), safe=""), quote(str(cfg_name), safe=""),), query)
+
+    def list_production_common_env_cfg(self, name, query=None):
+        """OpenAPI List Production Common Env Cfg
+
+        GET /openapi/environments/production/{name}/envcfgs
+
+        Query parameters:
+            projectKey (required): project key
+            type: type
+        """
+        return self._request("GET", "/openapi/environments/production/%s/envcfgs" % (quote(str(name), safe=""),), query)
+
+    def create_common_env_cfg(self, name, query=None, body=None):
+        """OpenAPI Create Common Env Cfg
+
+        POST /openapi/environments/production/{name}/envcfgs
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/production/%s/envcfgs" % (quote(str(name), safe=""),), query, body)
+
+    def production_restart_service(self, name, service_name, query=None):
+        """OpenAPI Production Restart Service
+
+        POST /openapi/environments/production/{name}/service/{serviceName}/restart
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/production/%s/service/%s/restart" % (quote(str(name), safe=""), quote(str(service_name), safe=""),), query)
+
+    def update_production_yaml_services(self, name, query=None, body=None):
+        """OpenAPI Update Production Yaml Services
+
+        PUT /openapi/environments/production/{name}/services
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/production/%s/services" % (quote(str(name), safe=""),), query, body)
+
+    def get_production_service(self, name, service_name, query=None):
+        """OpenAPI Get Production Service
+
+        GET /openapi/environments/production/{name}/services/{serviceName}
+
+        Query parameters:
+            projectName: project name
+            workLoadType: work load type
+        """
+        return self._request("GET", "/openapi/environments/production/%s/services/%s" % (quote(str(name), safe=""), quote(str(service_name), safe=""),), query)
+
+    def get_production_env_global_variables(self, name, query=None):
+        """OpenAPI Get Production Env Global Variables
+
+        GET /openapi/environments/production/{name}/variable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/production/%s/variable" % (quote(str(name), safe=""),), query)
+
+    def update_production_global_variables(self, name, query=None, body=None):
+        """OpenAPI Update Production K8S Environment Global Variables
+
+        PUT /openapi/environments/production/{name}/variable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/production/%s/variable" % (quote(str(name), safe=""),), query, body)
+
+    def scale_workloads(self, query=None, body=None):
+        """OpenAPI Scale Workloads
+
+        POST /openapi/environments/scale
+        """
+        return self._request("POST", "/openapi/environments/scale", query, body)
+
+    def delete_yaml_service_from_env(self, query=None, body=None):
+        """OpenAPI Delete Yaml Service From Env
+
+        DELETE /openapi/environments/service/yaml
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/environments/service/yaml", query, body)
+
+    def apply_yaml_service(self, query=None, body=None):
+        """OpenAPI Apply Yaml Service
+
+        POST /openapi/environments/service/yaml
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/service/yaml", query, body)
+
+    def delete_env(self, name, query=None):
+        """OpenAPI Delete Env
+
+        DELETE /openapi/environments/{name}
+
+        Query parameters:
+            projectKey (required): project key
+            isDelete: is delete
+        """
+        return self._request("DELETE", "/openapi/environments/%s" % (quote(str(name), safe=""),), query)
+
+    def get_env_detail(self, name, query=None):
+        """OpenAPI Get Env Detail
+
+        GET /openapi/environments/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/%s" % (quote(str(name), safe=""),), query)
+
+    def update_env_basic_info(self, name, query=None, body=None):
+        """OpenAPI Update Env Basic Info
+
+        PUT /openapi/environments/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/%s" % (quote(str(name), safe=""),), query, body)
+
+    def check_share_env_ready(self, name, op, query=None):
+        """OpenAPI Check Share Env Ready
+
+        GET /openapi/environments/{name}/check/sharenv/{op}/ready
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/%s/check/sharenv/%s/ready" % (quote(str(name), safe=""), quote(str(op), safe=""),), query)
+
+    def check_workloads_k8s_services(self, name, query=None):
+        """OpenAPI Check Workloads K8s Services
+
+        GET /openapi/environments/{name}/check/workloads/k8services
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/%s/check/workloads/k8services" % (quote(str(name), safe=""),), query)
+
+    def delete_common_env_cfg(self, name, cfg_name, query=None):
+        """OpenAPI Delete Common Env Cfg
+
+        DELETE /openapi/environments/{name}/envcfg/{cfgName}
+
+        Query parameters:
+            projectKey (required): project key
+            type: type
+        """
+        return self._request("DELETE", "/openapi/environments/%s/envcfg/%s" % (quote(str(name), safe=""), quote(str(cfg_name), safe=""),), query)
+
+    def get_common_env_cfg(self, name, cfg_name, query=None):
+        """OpenAPI Get Common Env Cfg
+
+        GET /openapi/environments/{name}/envcfg/{cfgName}
+
+        Query parameters:
+            projectKey (required): project key
+            type: type
+        """
+        return self._request("GET", "/openapi/environments/%s/envcfg/%s" % (quote(str(name), safe=""), quote(str(cfg_name), safe=""),), query)
+
+    def list_common_env_cfg(self, name, query=None):
+        """OpenAPI List Common Env Cfg
+
+        GET /openapi/environments/{name}/envcfgs
+
+        Query parameters:
+            projectKey (required): project key
+            type: type
+        """
+        return self._request("GET", "/openapi/environments/%s/envcfgs" % (quote(str(name), safe=""),), query)
+
+    def create_common_env_cfg2(self, name, query=None, body=None):
+        """OpenAPI Create Common Env Cfg
+
+        POST /openapi/environments/{name}/envcfgs
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/%s/envcfgs" % (quote(str(name), safe=""),), query, body)
+
+    def restart_service(self, name, service_name, query=None):
+        """OpenAPI Restart Service
+
+        POST /openapi/environments/{name}/service/{serviceName}/restart
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/%s/service/%s/restart" % (quote(str(name), safe=""), quote(str(service_name), safe=""),), query)
+
+    def update_yaml_services(self, name, query=None, body=None):
+        """OpenAPI Update Yaml Services
+
+        PUT /openapi/environments/{name}/services
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/%s/services" % (quote(str(name), safe=""),), query, body)
+
+    def get_service(self, name, service_name, query=None):
+        """OpenAPI Get Service
+
+        GET /openapi/environments/{name}/services/{serviceName}
+
+        Query parameters:
+            projectName: project name
+            workLoadType: work load type
+        """
+        return self._request("GET", "/openapi/environments/%s/services/%s" % (quote(str(name), safe=""), quote(str(service_name), safe=""),), query)
+
+    def dsiable_base_env(self, name, query=None):
+        """OpenAPI Disable Base Env
+
+        DELETE /openapi/environments/{name}/share/enable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/environments/%s/share/enable" % (quote(str(name), safe=""),), query)
+
+    def enable_base_env(self, name, query=None):
+        """OpenAPI Enable Base Env
+
+        POST /openapi/environments/{name}/share/enable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/%s/share/enable" % (quote(str(name), safe=""),), query)
+
+    def get_portal_service(self, name, service_name, query=None):
+        """OpenAPI Get Portal Service
+
+        GET /openapi/environments/{name}/share/portal/{serviceName}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/%s/share/portal/%s" % (quote(str(name), safe=""), quote(str(service_name), safe=""),), query)
+
+    def set_portal_service(self, name, service_name, query=None, body=None):
+        """OpenAPI Set Portal Service
+
+        POST /openapi/environments/{name}/share/portal/{serviceName}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/environments/%s/share/portal/%s" % (quote(str(name), safe=""), quote(str(service_name), safe=""),), query, body)
+
+    def get_env_global_variables(self, name, query=None):
+        """OpenAPI Get Env Global Variables
+
+        GET /openapi/environments/{name}/variable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/environments/%s/variable" % (quote(str(name), safe=""),), query)
+
+    def update_global_variables(self, name, query=None, body=None):
+        """OpenAPI Update K8S Environment Global Variables
+
+        PUT /openapi/environments/{name}/variable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/environments/%s/variable" % (quote(str(name), safe=""),), query, body)
+
+    def get_container_logs_sse(self, pod_name, container_name, query=None):
+        """OpenAPI Get Container Logs SSE
+
+        GET /openapi/logs/sse/pods/{podName}/containers/{containerName}
+
+        Query parameters:
+            tails: tails
+            envName: env name
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/logs/sse/pods/%s/containers/%s" % (quote(str(pod_name), safe=""), quote(str(container_name), safe=""),), query)
+
+    def delete_project(self, query=None):
+        """OpenAPI Delete Project
+
+        DELETE /openapi/projects/project
+
+        Query parameters:
+            projectKey (required): project key
+            isDelete: is delete
+        """
+        return self._request("DELETE", "/openapi/projects/project", query)
+
+    def list_project(self, query=None):
+        """OpenAPI List Project
+
+        GET /openapi/projects/project
+
+        Query parameters:
+            pageSize: page size
+            pageNum: page num
+        """
+        return self._request("GET", "/openapi/projects/project", query)
+
+    def create_product_template(self, query=None, body=None):
+        """OpenAPI Create Product Template
+
+        POST /openapi/projects/project
+        """
+        return self._request("POST", "/openapi/projects/project", query, body)
+
+    def get_project_detail(self, query=None):
+        """OpenAPI Get Project Detail
+
+        GET /openapi/projects/project/detail
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/projects/project/detail", query)
+
+    def get_global_variables(self, query=None):
+        """OpenAPI Get Global Variables
+
+        GET /openapi/projects/project/globalVariable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/projects/project/globalVariable", query)
+
+    def initialize_helm_project(self, query=None, body=None):
+        """OpenAPI Initialize Helm Project
+
+        POST /openapi/projects/project/init/helm
+        """
+        return self._request("POST", "/openapi/projects/project/init/helm", query, body)
+
+    def initialize_yaml_project(self, query=None, body=None):
+        """OpenAPI Initialize Yaml Project
+
+        POST /openapi/projects/project/init/yaml
+        """
+        return self._request("POST", "/openapi/projects/project/init/yaml", query, body)
+
+    def create_scanning_module(self, query=None, body=None):
+        """OpenAPI Create Scanning Module
+
+        POST /openapi/quality/codescan
+        """
+        return self._request("POST", "/openapi/quality/codescan", query, body)
+
+    def create_scanning_task2(self, scan_name, query=None, body=None):
+        """OpenAPI Create Scanning Task
+
+        POST /openapi/quality/codescan/{scanName}/task
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/quality/codescan/%s/task" % (quote(str(scan_name), safe=""),), query, body)
+
+    def get_scanning_task_detail2(self, scan_name, task_id, query=None):
+        """OpenAPI Get Scanning Task Detail
+
+        GET /openapi/quality/codescan/{scanName}/task/{taskID}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/quality/codescan/%s/task/%s" % (quote(str(scan_name), safe=""), quote(str(task_id), safe=""),), query)
+
+    def create_test_task(self, query=None, body=None):
+        """OpenAPI Create Test Task
+
+        POST /openapi/quality/testing/task
+        """
+        return self._request("POST", "/openapi/quality/testing/task", query, body)
+
+    def get_test_task_result(self, test_name, task_id, query=None):
+        """OpenAPI Get Test Task Result
+
+        GET /openapi/quality/testing/{testName}/task/{taskID}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/quality/testing/%s/task/%s" % (quote(str(test_name), safe=""), quote(str(task_id), safe=""),), query)
+
+    def list_release_plans(self, query=None):
+        """OpenAPI List Release Plans
+
+        GET /openapi/release_plan/v1
+
+        Query parameters:
+            pageNum (required): page num
+            pageSize (required): page size
+        """
+        return self._request("GET", "/openapi/release_plan/v1", query)
+
+    def create_release_plan(self, query=None, body=None):
+        """OpenAPI Create Release Plan
+
+        POST /openapi/release_plan/v1
+        """
+        return self._request("POST", "/openapi/release_plan/v1", query, body)
+
+    def get_release_plan(self, id, query=None):
+        """OpenAPI Get Release Plan
+
+        GET /openapi/release_plan/v1/{id}
+        """
+        return self._request("GET", "/openapi/release_plan/v1/%s" % (quote(str(id), safe=""),), query)
+
+    def create_cluster_resource(self, query=None, body=None):
+        """OpenAPI Create Cluster Resource
+
+        POST /openapi/resources/clusters
+        """
+        return self._request("POST", "/openapi/resources/clusters", query, body)
+
+    def delete_cluster_resource(self, id, query=None):
+        """OpenAPI Delete Cluster Resource
+
+        DELETE /openapi/resources/clusters/{id}
+        """
+        return self._request("DELETE", "/openapi/resources/clusters/%s" % (quote(str(id), safe=""),), query)
+
+    def get_cluster_resource(self, id, query=None):
+        """OpenAPI Get Cluster Resource
+
+        GET /openapi/resources/clusters/{id}
+        """
+        return self._request("GET", "/openapi/resources/clusters/%s" % (quote(str(id), safe=""),), query)
+
+    def update_cluster_resource(self, id, query=None, body=None):
+        """OpenAPI Update Cluster Resource
+
+        PUT /openapi/resources/clusters/{id}
+        """
+        return self._request("PUT", "/openapi/resources/clusters/%s" % (quote(str(id), safe=""),), query, body)
+
+    def create_project_resource(self, query=None, body=None):
+        """OpenAPI Create Project Resource
+
+        POST /openapi/resources/projects
+        """
+        return self._request("POST", "/openapi/resources/projects", query, body)
+
+    def delete_project_resource(self, key, query=None):
+        """OpenAPI Delete Project Resource
+
+        DELETE /openapi/resources/projects/{key}
+
+        Query parameters:
+            isDelete: whether to delete the resources of the environments
+        """
+        return self._request("DELETE", "/openapi/resources/projects/%s" % (quote(str(key), safe=""),), query)
+
+    def get_project_resource(self, key, query=None):
+        """OpenAPI Get Project Resource
+
+        GET /openapi/resources/projects/{key}
+        """
+        return self._request("GET", "/openapi/resources/projects/%s" % (quote(str(key), safe=""),), query)
+
+    def update_project_resource(self, key, query=None, body=None):
+        """OpenAPI Update Project Resource
+
+        PUT /openapi/resources/projects/{key}
+        """
+        return self._request("PUT", "/openapi/resources/projects/%s" % (quote(str(key), safe=""),), query, body)
+
+    def create_environment_resource(self, key, query=None, body=None):
+        """OpenAPI Create Environment Resource
+
+        POST /openapi/resources/projects/{key}/environments
+        """
+        return self._request("POST", "/openapi/resources/projects/%s/environments" % (quote(str(key), safe=""),), query, body)
+
+    def delete_environment_resource(self, key, name, query=None):
+        """OpenAPI Delete Environment Resource
+
+        DELETE /openapi/resources/projects/{key}/environments/{name}
+
+        Query parameters:
+            isDelete: whether to delete the namespace of the environment
+        """
+        return self._request("DELETE", "/openapi/resources/projects/%s/environments/%s" % (quote(str(key), safe=""), quote(str(name), safe=""),), query)
+
+    def get_environment_resource(self, key, name, query=None):
+        """OpenAPI Get Environment Resource
+
+        GET /openapi/resources/projects/{key}/environments/{name}
+        """
+        return self._request("GET", "/openapi/resources/projects/%s/environments/%s" % (quote(str(key), safe=""), quote(str(name), safe=""),), query)
+
+    def update_environment_resource(self, key, name, query=None, body=None):
+        """OpenAPI Update Environment Resource
+
+        PUT /openapi/resources/projects/{key}/environments/{name}
+        """
+        return self._request("PUT", "/openapi/resources/projects/%s/environments/%s" % (quote(str(key), safe=""), quote(str(name), safe=""),), query, body)
+
+    def create_registry_resource(self, query=None, body=None):
+        """OpenAPI Create Registry Resource
+
+        POST /openapi/resources/registries
+        """
+        return self._request("POST", "/openapi/resources/registries", query, body)
+
+    def delete_registry_resource(self, id, query=None):
+        """OpenAPI Delete Registry Resource
+
+        DELETE /openapi/resources/registries/{id}
+        """
+        return self._request("DELETE", "/openapi/resources/registries/%s" % (quote(str(id), safe=""),), query)
+
+    def get_registry_resource(self, id, query=None):
+        """OpenAPI Get Registry Resource
+
+        GET /openapi/resources/registries/{id}
+        """
+        return self._request("GET", "/openapi/resources/registries/%s" % (quote(str(id), safe=""),), query)
+
+    def update_registry_resource(self, id, query=None, body=None):
+        """OpenAPI Update Registry Resource
+
+        PUT /openapi/resources/registries/{id}
+        """
+        return self._request("PUT", "/openapi/resources/registries/%s" % (quote(str(id), safe=""),), query, body)
+
+    def create_workflow_resource(self, query=None, body=None):
+        """OpenAPI Create Workflow Resource
+
+        POST /openapi/resources/workflows
+        """
+        return self._request("POST", "/openapi/resources/workflows", query, body)
+
+    def delete_workflow_resource(self, name, query=None):
+        """OpenAPI Delete Workflow Resource
+
+        DELETE /openapi/resources/workflows/{name}
+        """
+        return self._request("DELETE", "/openapi/resources/workflows/%s" % (quote(str(name), safe=""),), query)
+
+    def get_workflow_resource(self, name, query=None):
+        """OpenAPI Get Workflow Resource
+
+        GET /openapi/resources/workflows/{name}
+        """
+        return self._request("GET", "/openapi/resources/workflows/%s" % (quote(str(name), safe=""),), query)
+
+    def update_workflow_resource(self, name, query=None, body=None):
+        """OpenAPI Update Workflow Resource
+
+        PUT /openapi/resources/workflows/{name}
+        """
+        return self._request("PUT", "/openapi/resources/workflows/%s" % (quote(str(name), safe=""),), query, body)
+
+    def list_scanning_modules(self, query=None):
+        """OpenAPI List Scanning Modules
+
+        GET /openapi/scanning
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/scanning", query)
+
+    def create_scanning_module_from_yaml(self, query=None, body=None):
+        """OpenAPI Create Scanning Module From Yaml
+
+        POST /openapi/scanning
+        """
+        return self._request("POST", "/openapi/scanning", query, body)
+
+    def delete_scanning_module(self, scan_name, query=None):
+        """OpenAPI Delete Scanning Module
+
+        DELETE /openapi/scanning/{scanName}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/scanning/%s" % (quote(str(scan_name), safe=""),), query)
+
+    def get_scanning_module(self, scan_name, query=None):
+        """OpenAPI Get Scanning Module
+
+        GET /openapi/scanning/{scanName}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/scanning/%s" % (quote(str(scan_name), safe=""),), query)
+
+    def update_scanning_module(self, scan_name, query=None, body=None):
+        """OpenAPI Update Scanning Module
+
+        PUT /openapi/scanning/{scanName}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/scanning/%s" % (quote(str(scan_name), safe=""),), query, body)
+
+    def create_scanning_task(self, scan_name, query=None, body=None):
+        """OpenAPI Create Scanning Task
+
+        POST /openapi/scanning/{scanName}/task
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/scanning/%s/task" % (quote(str(scan_name), safe=""),), query, body)
+
+    def get_scanning_task_detail(self, scan_name, task_id, query=None):
+        """OpenAPI Get Scanning Task Detail
+
+        GET /openapi/scanning/{scanName}/task/{taskID}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/scanning/%s/task/%s" % (quote(str(scan_name), safe=""), quote(str(task_id), safe=""),), query)
+
+    def get_scanning_task_result(self, scan_name, task_id, query=None):
+        """OpenAPI Get Scanning Task Result
+
+        GET /openapi/scanning/{scanName}/task/{taskID}/result
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/scanning/%s/task/%s/result" % (quote(str(scan_name), safe=""), quote(str(task_id), safe=""),), query)
+
+    def load_service_from_yaml_template_open_api(self, query=None, body=None):
+        """OpenAPI Load Service From Yaml Template
+
+        POST /openapi/service/template/load/yaml
+        """
+        return self._request("POST", "/openapi/service/template/load/yaml", query, body)
+
+    def load_production_service_from_yaml_template_open_api(self, query=None, body=None):
+        """OpenAPI Load Production Service From Yaml Template
+
+        POST /openapi/service/template/production/load/yaml
+        """
+        return self._request("POST", "/openapi/service/template/production/load/yaml", query, body)
+
+    def create_raw_production_yaml_services_open_api(self, query=None, body=None):
+        """OpenAPI Create Raw Production Yaml Services
+
+        POST /openapi/service/yaml/production/raw
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/service/yaml/production/raw", query, body)
+
+    def list_production_yaml_services_open_api(self, query=None):
+        """OpenAPI List Production Yaml Services
+
+        GET /openapi/service/yaml/production/services
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/service/yaml/production/services", query)
+
+    def delete_production_services_open_api(self, name, query=None):
+        """OpenAPI Delete Production Services
+
+        DELETE /openapi/service/yaml/production/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/service/yaml/production/%s" % (quote(str(name), safe=""),), query)
+
+    def get_production_yaml_service_open_api(self, name, query=None):
+        """OpenAPI Get Production Yaml Service
+
+        GET /openapi/service/yaml/production/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/service/yaml/production/%s" % (quote(str(name), safe=""),), query)
+
+    def update_production_service_config_open_api(self, name, query=None, body=None):
+        """OpenAPI Update Production Service Config
+
+        PUT /openapi/service/yaml/production/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/service/yaml/production/%s" % (quote(str(name), safe=""),), query, body)
+
+    def update_production_service_variable_open_api(self, name, query=None, body=None):
+        """OpenAPI Update Production Service Variable
+
+        PUT /openapi/service/yaml/production/{name}/variable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/service/yaml/production/%s/variable" % (quote(str(name), safe=""),), query, body)
+
+    def create_raw_yaml_services_open_api(self, query=None, body=None):
+        """OpenAPI Create Raw Yaml Services
+
+        POST /openapi/service/yaml/raw
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/service/yaml/raw", query, body)
+
+    def list_yaml_services_open_api(self, query=None):
+        """OpenAPI List Yaml Services
+
+        GET /openapi/service/yaml/services
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/service/yaml/services", query)
+
+    def delete_yaml_services_open_api(self, name, query=None):
+        """OpenAPI Delete Yaml Services
+
+        DELETE /openapi/service/yaml/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/service/yaml/%s" % (quote(str(name), safe=""),), query)
+
+    def get_yaml_service_open_api(self, name, query=None):
+        """OpenAPI Get Yaml Service
+
+        GET /openapi/service/yaml/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/service/yaml/%s" % (quote(str(name), safe=""),), query)
+
+    def update_service_config_open_api(self, name, query=None, body=None):
+        """OpenAPI Update Service Config
+
+        PUT /openapi/service/yaml/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/service/yaml/%s" % (quote(str(name), safe=""),), query, body)
+
+    def update_service_variable_open_api(self, name, query=None, body=None):
+        """OpenAPI Update Service Variable
+
+        PUT /openapi/service/yaml/{name}/variable
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/service/yaml/%s/variable" % (quote(str(name), safe=""),), query, body)
+
+    def get_build_stat_for_open_api(self, query=None):
+        """OpenAPI Get Build Stat
+
+        GET /openapi/statistics/build
+
+        Query parameters:
+            startDate: start date
+            endDate: end date
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/statistics/build", query)
+
+    def get_deploy_stats_open_api(self, query=None):
+        """OpenAPI Get Deploy Stats
+
+        GET /openapi/statistics/deploy
+
+        Query parameters:
+            startDate: start date
+            endDate: end date
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/statistics/deploy", query)
+
+    def get_overview_stat(self, query=None):
+        """OpenAPI Get Overview Stat
+
+        GET /openapi/statistics/overview
+        """
+        return self._request("GET", "/openapi/statistics/overview", query)
+
+    def get_test_stat_open_api(self, query=None):
+        """OpenAPI Get Test Stat
+
+        GET /openapi/statistics/test
+
+        Query parameters:
+            startDate: start date
+            endDate: end date
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/statistics/test", query)
+
+    def get_release_stat_open_api(self, query=None):
+        """OpenAPI Get Release Stat
+
+        GET /openapi/statistics/v2/release
+
+        Query parameters:
+            startTime: start time
+            endTime: end time
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/statistics/v2/release", query)
+
+    def get_spec(self, query=None):
+        """Get OpenAPI Spec
+
+        GET /openapi/system/apispec
+
+        Query parameters:
+            version: zadig version, the running version is used if not specified
+        """
+        return self._request("GET", "/openapi/system/apispec", query)
+
+    def diff_spec(self, query=None):
+        """Diff OpenAPI Spec
+
+        GET /openapi/system/apispec/diff
+
+        Query parameters:
+            base (required): base version
+            target: target version, the running version is used if not specified
+        """
+        return self._request("GET", "/openapi/system/apispec/diff", query)
+
+    def list_spec_versions(self, query=None):
+        """List OpenAPI Spec Versions
+
+        GET /openapi/system/apispec/versions
+        """
+        return self._request("GET", "/openapi/system/apispec/versions", query)
+
+    def list_cluster(self, query=None):
+        """OpenAPI List Cluster
+
+        GET /openapi/system/cluster
+
+        Query parameters:
+            projectName: project name
+        """
+        return self._request("GET", "/openapi/system/cluster", query)
+
+    def create_cluster(self, query=None, body=None):
+        """OpenAPI Create Cluster
+
+        POST /openapi/system/cluster
+        """
+        return self._request("POST", "/openapi/system/cluster", query, body)
+
+    def delete_cluster(self, id, query=None):
+        """OpenAPI Delete Cluster
+
+        DELETE /openapi/system/cluster/{id}
+        """
+        return self._request("DELETE", "/openapi/system/cluster/%s" % (quote(str(id), safe=""),), query)
+
+    def update_cluster(self, id, query=None, body=None):
+        """OpenAPI Update Cluster
+
+        PUT /openapi/system/cluster/{id}
+        """
+        return self._request("PUT", "/openapi/system/cluster/%s" % (quote(str(id), safe=""),), query, body)
+
+    def list_registry(self, query=None):
+        """OpenAPI List Registry
+
+        GET /openapi/system/registry
+        """
+        return self._request("GET", "/openapi/system/registry", query)
+
+    def create_registry(self, query=None, body=None):
+        """OpenAPI Create Registry
+
+        POST /openapi/system/registry
+        """
+        return self._request("POST", "/openapi/system/registry", query, body)
+
+    def get_registry(self, id, query=None):
+        """OpenAPI Get Registry
+
+        GET /openapi/system/registry/{id}
+        """
+        return self._request("GET", "/openapi/system/registry/%s" % (quote(str(id), safe=""),), query)
+
+    def update_registry(self, id, query=None, body=None):
+        """OpenAPI Update Registry
+
+        PUT /openapi/system/registry/{id}
+        """
+        return self._request("PUT", "/openapi/system/registry/%s" % (quote(str(id), safe=""),), query, body)
+
+    def get_workflow_v4_list(self, query=None):
+        """OpenAPI Get Workflow V4 List
+
+        GET /openapi/workflows
+
+        Query parameters:
+            projectKey (required): project key
+            viewName: view name
+        """
+        return self._request("GET", "/openapi/workflows", query)
+
+    def delete_custom_workflow_v4(self, query=None):
+        """OpenAPI Delete Custom Workflow V4
+
+        DELETE /openapi/workflows/custom
+
+        Query parameters:
+            workflowKey: workflow key
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/workflows/custom", query)
+
+    def cancel_workflow_task_v4(self, query=None, body=None):
+        """OpenAPI Cancel Workflow Task V4
+
+        DELETE /openapi/workflows/custom/task
+        """
+        return self._request("DELETE", "/openapi/workflows/custom/task", query, body)
+
+    def get_workflow_task_v4(self, query=None):
+        """OpenAPI Get Workflow Task V4
+
+        GET /openapi/workflows/custom/task
+
+        Query parameters:
+            taskId: task id
+            workflowKey: workflow key
+        """
+        return self._request("GET", "/openapi/workflows/custom/task", query)
+
+    def create_custom_workflow_task(self, query=None, body=None):
+        """OpenAPI Create Custom Workflow Task
+
+        POST /openapi/workflows/custom/task
+        """
+        return self._request("POST", "/openapi/workflows/custom/task", query, body)
+
+    def approve_stage(self, query=None, body=None):
+        """OpenAPI Approve Stage
+
+        POST /openapi/workflows/custom/task/approve
+        """
+        return self._request("POST", "/openapi/workflows/custom/task/approve", query, body)
+
+    def get_custom_workflow_v4(self, name, query=None):
+        """OpenAPI Get Custom Workflow V4
+
+        GET /openapi/workflows/custom/{name}/detail
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/workflows/custom/%s/detail" % (quote(str(name), safe=""),), query)
+
+    def retry_custom_workflow_task_v4(self, name, task_id, query=None):
+        """OpenAPI Retry Custom Workflow Task V4
+
+        POST /openapi/workflows/custom/{name}/task/{taskID}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("POST", "/openapi/workflows/custom/%s/task/%s" % (quote(str(name), safe=""), quote(str(task_id), safe=""),), query)
+
+    def get_custom_workflow_task_v4(self, name, query=None):
+        """OpenAPI Get Custom Workflow Task V4
+
+        GET /openapi/workflows/custom/{name}/tasks
+
+        Query parameters:
+            projectKey (required): project key
+            pageNum: page num
+            pageSize: page size
+        """
+        return self._request("GET", "/openapi/workflows/custom/%s/tasks" % (quote(str(name), safe=""),), query)
+
+    def delete_product_workflow_v4(self, query=None):
+        """OpenAPI Delete Product Workflow V4
+
+        DELETE /openapi/workflows/product
+
+        Query parameters:
+            workflowKey: workflow key
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/workflows/product", query)
+
+    def create_product_workflow_task(self, query=None, body=None):
+        """OpenAPI Create Product Workflow Task
+
+        POST /openapi/workflows/product/task
+        """
+        return self._request("POST", "/openapi/workflows/product/task", query, body)
+
+    def get_product_workflow_task_v4(self, name, task_id, query=None):
+        """OpenAPI Get Product Workflow Task V4
+
+        GET /openapi/workflows/product/{name}/task/{taskID}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/workflows/product/%s/task/%s" % (quote(str(name), safe=""), quote(str(task_id), safe=""),), query)
+
+    def get_product_workflow_tasks_v4(self, name, query=None):
+        """OpenAPI Get Product Workflow Tasks V4
+
+        GET /openapi/workflows/product/{name}/tasks
+
+        Query parameters:
+            projectKey (required): project key
+            pageNum: page num
+            pageSize: page size
+        """
+        return self._request("GET", "/openapi/workflows/product/%s/tasks" % (quote(str(name), safe=""),), query)
+
+    def get_workflow_views(self, query=None):
+        """OpenAPI Get Workflow Views
+
+        GET /openapi/workflows/view
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("GET", "/openapi/workflows/view", query)
+
+    def create_workflow_view(self, query=None, body=None):
+        """OpenAPI Create Workflow View
+
+        POST /openapi/workflows/view
+        """
+        return self._request("POST", "/openapi/workflows/view", query, body)
+
+    def delete_workflow_view(self, name, query=None):
+        """OpenAPI Delete Workflow View
+
+        DELETE /openapi/workflows/view/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("DELETE", "/openapi/workflows/view/%s" % (quote(str(name), safe=""),), query)
+
+    def update_workflow_view(self, name, query=None, body=None):
+        """OpenAPI Update Workflow View
+
+        PUT /openapi/workflows/view/{name}
+
+        Query parameters:
+            projectKey (required): project key
+        """
+        return self._request("PUT", "/openapi/workflows/view/%s" % (quote(str(name), safe=""),), query, body)